@@ -284,6 +284,7 @@ type Seat struct {
 	Status     string
 	Price      float64
 	Position   *Position
+	RowLabel   string
 }
 
 type Position struct {
@@ -316,6 +317,16 @@ type HoldSeatsResponse struct {
 	Pricing   *PriceBreakdown
 }
 
+type ReleaseSeatsRequest struct {
+	HoldId     string
+	ShowtimeId string
+}
+
+type ReleaseSeatsResponse struct {
+	Success       bool
+	ReleasedSeats int32
+}
+
 type ConfirmBookingRequest struct {
 	HoldId        string
 	PromoCode     *string
@@ -340,8 +351,11 @@ type GetBookingResponse struct {
 }
 
 type ListUserBookingsRequest struct {
-	Page  int32
-	Limit int32
+	Page     int32
+	Limit    int32
+	Status   *string
+	DateFrom *string
+	DateTo   *string
 }
 
 type ListUserBookingsResponse struct {
@@ -375,6 +389,8 @@ type Booking struct {
 	BookedAt         string
 	Showtime         *Showtime
 	Seats            []*BookedSeat
+	CanCancel        bool
+	RefundPercent    float64
 }
 
 type BookedSeat struct {
@@ -10,13 +10,27 @@ import (
 	"os/signal"
 	"syscall"
 
+	// Registers the generated API spec served under /api/v1/docs
+	_ "cinemaos-backend/docs"
+
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
 
+// @title CinemaOS API
+// @version 1.0
+// @description Cinema booking platform: movies, cinemas, showtimes, seat holds, bookings and payments.
+// @BasePath /api/v1
+//
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login, sent as "Bearer <token>"
 func main() {
 	var configPath string
+	var disableScheduler bool
 	flag.StringVar(&configPath, "config", "", "path to config file")
+	flag.BoolVar(&disableScheduler, "disable-scheduler", false, "do not run periodic background jobs on this instance")
 	flag.Parse()
 
 	// Load .env file if it exists
@@ -48,8 +62,31 @@ func main() {
 				app.Logger.Error("Failed to shutdown tracer", zap.Error(err))
 			}
 		}
+		if app.Metrics != nil {
+			if err := app.Metrics.Shutdown(context.Background()); err != nil {
+				app.Logger.Error("Failed to shutdown metrics", zap.Error(err))
+			}
+		}
 	}()
 
+	// Start background workers
+	app.Dispatcher.Start()
+	app.EventBus.Start()
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	if disableScheduler {
+		close(jobsDone)
+		app.Logger.Info("Background job scheduler disabled")
+	} else {
+		go func() {
+			defer close(jobsDone)
+			app.Jobs.Run(workerCtx)
+		}()
+	}
+	// The server listens while caches warm; /health/ready reports 503 until done
+	go app.Warmup.Run(workerCtx)
+	go app.SeatFeed.Run(workerCtx)
+
 	// Start server
 	go func() {
 		if err := app.Server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -71,5 +108,23 @@ func main() {
 		app.Logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	stopWorkers()
+	// Let running jobs finish before the database and Redis are closed
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		app.Logger.Warn("Background jobs still running at shutdown timeout")
+	}
+	if err := app.EventBus.Stop(app.Config.Server.ShutdownTimeout); err != nil {
+		app.Logger.Error("Failed to stop event bus", zap.Error(err))
+	}
+	if err := app.Dispatcher.Stop(app.Config.Server.ShutdownTimeout); err != nil {
+		app.Logger.Error("Failed to drain background jobs", zap.Error(err))
+	}
+	// Analytics writes run on the dispatcher, so the sink closes after it stops
+	if err := app.Analytics.Close(); err != nil {
+		app.Logger.Error("Failed to flush analytics", zap.Error(err))
+	}
+
 	app.Logger.Info("Server exited properly")
 }
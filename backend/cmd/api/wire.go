@@ -6,8 +6,15 @@ package main
 import (
 	"cinemaos-backend/internal/app/postgres"
 	"cinemaos-backend/internal/app/redis"
+	"cinemaos-backend/internal/app/seatfeed"
+	"cinemaos-backend/internal/app/warmup"
 	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+	"cinemaos-backend/internal/pkg/scheduler"
 	"cinemaos-backend/internal/pkg/tracer"
 	"cinemaos-backend/internal/provider"
 	httpserver "cinemaos-backend/internal/server"
@@ -22,7 +29,14 @@ type Application struct {
 	DB          *postgres.Database
 	RedisClient *redis.Client
 	Tracer      *tracer.Tracer
+	Metrics     *metrics.Metrics
 	Config      *config.Config
+	Dispatcher  *async.Dispatcher
+	EventBus    *eventbus.Bus
+	Jobs        *scheduler.Runner
+	Analytics   *analytics.Tracker
+	Warmup      *warmup.Service
+	SeatFeed    *seatfeed.Service
 }
 
 // InitializeApplication wires up all dependencies using Wire
@@ -34,27 +48,96 @@ func InitializeApplication(configPath string) (*Application, error) {
 		// Infrastructure
 		provider.ProvideLogger,
 		provider.ProvideTracer,
+		provider.ProvideMetrics,
 		provider.ProvideDatabase,
 		provider.ProvideRedis,
 		provider.ProvideValidator,
+		provider.ProvideEmailSender,
+		provider.ProvideAsyncDispatcher,
+		provider.ProvideEmailClient,
+		provider.ProvideEventBus,
+		provider.ProvideShadowReader,
+		provider.ProvideAnalyticsTracker,
 
 		// Repositories
 		provider.ProvideUserRepository,
 		provider.ProvideRefreshTokenRepository,
 		provider.ProvidePasswordResetTokenRepository,
+		provider.ProvideEmailVerificationTokenRepository,
+		provider.ProvideCachedMovieRepository,
 		provider.ProvideMovieRepository,
+		provider.ProvideMovieMediaRepository,
+		provider.ProvideReviewRepository,
 		provider.ProvideCinemaRepository,
 		provider.ProvideScreenRepository,
 		provider.ProvideSeatRepository,
 		provider.ProvideShowtimeRepository,
+		provider.ProvideAssistiveDeviceRepository,
+		provider.ProvideSeatTypeRuleRepository,
+		provider.ProvideSeatHoldRepository,
+		provider.ProvideSeatUpdateFeed,
+		provider.ProvideGuestLookupRepository,
+		provider.ProvideBookingRepository,
+		provider.ProvideBookingSeatRepository,
+		provider.ProvidePaymentRepository,
+		provider.ProvideTransactor,
+		provider.ProvideOutboxRepository,
+		provider.ProvidePromoCodeRepository,
+		provider.ProvideGroupCheckoutRepository,
+		provider.ProvideGroupBookingRepository,
+		provider.ProvideGroupBookingLock,
+		provider.ProvideWebhookEventRepository,
+		provider.ProvideChangeRecordRepository,
+		provider.ProvideTicketCheckInRepository,
+		provider.ProvideFeaturedSlotRepository,
+		provider.ProvideHoldRecoveryRepository,
+		provider.ProvideDailyReportRepository,
+		provider.ProvideDemandRepository,
+		provider.ProvideCinemaStaffRepository,
+		provider.ProvideWaitlistRepository,
+		provider.ProvideLoyaltyRepository,
+		provider.ProvideUserPreferencesRepository,
+		provider.ProvidePricingRuleRepository,
+		provider.ProvidePricingRuleCache,
+		provider.ProvideBookingAnalyticsCache,
+		provider.ProvideJobStore,
 
 		// Services
 		provider.ProvideJWTManager,
 		provider.ProvidePasswordManager,
+		provider.ProvideGoogleVerifier,
+		provider.ProvideStorageClient,
 		provider.ProvideAuthService,
+		provider.ProvideChangeLogService,
+		provider.ProvideTMDBService,
+		provider.ProvidePopularityCalculator,
 		provider.ProvideMovieService,
+		provider.ProvideCurationService,
 		provider.ProvideCinemaService,
 		provider.ProvideShowtimeService,
+		provider.ProvidePricingEngine,
+		provider.ProvidePricingService,
+		provider.ProvidePromoService,
+		provider.ProvideOutbox,
+		provider.ProvideOutboxRelay,
+		provider.ProvideBookingService,
+		provider.ProvideConfirmationService,
+		provider.ProvideWaitlistService,
+		provider.ProvideGroupCheckoutService,
+		provider.ProvideGroupBookingService,
+		provider.ProvideHoldRecoveryService,
+		provider.ProvideGuestLookupService,
+		provider.ProvidePaymentGateway,
+		provider.ProvidePaymentCheckout,
+		provider.ProvidePaymentService,
+		provider.ProvideDailyReportService,
+		provider.ProvideDemandService,
+		provider.ProvideLoyaltyService,
+		provider.ProvidePreferencesService,
+		provider.ProvideAdminAnalyticsService,
+		provider.ProvideSeatFeedService,
+		provider.ProvideWarmupService,
+		provider.ProvideJobRunner,
 
 		// Handlers
 		provider.ProvideAuthHandler,
@@ -62,9 +145,31 @@ func InitializeApplication(configPath string) (*Application, error) {
 		provider.ProvideMovieHandler,
 		provider.ProvideCinemaHandler,
 		provider.ProvideShowtimeHandler,
+		provider.ProvideBookingHandler,
+		provider.ProvideGroupCheckoutHandler,
+		provider.ProvideGroupBookingHandler,
+		provider.ProvideHoldRecoveryHandler,
+		provider.ProvidePaymentHandler,
+		provider.ProvideAdminHandler,
+		provider.ProvideChangeLogHandler,
+		provider.ProvideCurationHandler,
+		provider.ProvideGuestLookupHandler,
+		provider.ProvideDailyReportHandler,
+		provider.ProvideAnalyticsHandler,
+		provider.ProvideDemandHandler,
+		provider.ProvideWaitlistHandler,
+		provider.ProvidePricingHandler,
+		provider.ProvidePromoHandler,
+		provider.ProvideSeatUpdateHandler,
+		provider.ProvideLoyaltyHandler,
+		provider.ProvidePreferencesHandler,
 
 		// Middleware
 		provider.ProvideAuthMiddleware,
+		provider.ProvideCinemaAccessMiddleware,
+		provider.ProvideRateLimiter,
+		provider.ProvideUserRateLimiter,
+		provider.ProvideIdempotency,
 
 		// Server
 		provider.ProvideRouter,
@@ -9,8 +9,15 @@ package main
 import (
 	"cinemaos-backend/internal/app/postgres"
 	"cinemaos-backend/internal/app/redis"
+	"cinemaos-backend/internal/app/seatfeed"
+	"cinemaos-backend/internal/app/warmup"
 	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+	"cinemaos-backend/internal/pkg/scheduler"
 	"cinemaos-backend/internal/pkg/tracer"
 	"cinemaos-backend/internal/provider"
 	"cinemaos-backend/internal/server"
@@ -29,7 +36,6 @@ func InitializeApplication(configPath string) (*Application, error) {
 		return nil, err
 	}
 	jwtManager := provider.ProvideJWTManager(config)
-	authMiddleware := provider.ProvideAuthMiddleware(jwtManager, logger)
 	database, err := provider.ProvideDatabase(config, logger)
 	if err != nil {
 		return nil, err
@@ -38,26 +44,127 @@ func InitializeApplication(configPath string) (*Application, error) {
 	refreshTokenRepository := provider.ProvideRefreshTokenRepository(database)
 	passwordResetTokenRepository := provider.ProvidePasswordResetTokenRepository(database)
 	passwordManager := provider.ProvidePasswordManager()
-	service := provider.ProvideAuthService(userRepository, refreshTokenRepository, passwordResetTokenRepository, jwtManager, passwordManager, logger, config)
+	reader := provider.ProvideShadowReader(config, logger)
+	bookingRepository := provider.ProvideBookingRepository(database, reader)
+	emailVerificationTokenRepository := provider.ProvideEmailVerificationTokenRepository(database)
+	emailSender := provider.ProvideEmailSender(config, logger)
+	dispatcher := provider.ProvideAsyncDispatcher(emailSender, logger)
+	emailClient := provider.ProvideEmailClient(dispatcher, config)
+	googleVerifier := provider.ProvideGoogleVerifier(config, logger)
+	storageClient, err := provider.ProvideStorageClient(config, logger)
+	if err != nil {
+		return nil, err
+	}
+	service := provider.ProvideAuthService(userRepository, refreshTokenRepository, passwordResetTokenRepository, emailVerificationTokenRepository, bookingRepository, jwtManager, passwordManager, googleVerifier, storageClient, emailClient, logger, config)
 	validator := provider.ProvideValidator()
 	authHandler := provider.ProvideAuthHandler(service, validator)
 	client, err := provider.ProvideRedis(config, logger)
 	if err != nil {
 		return nil, err
 	}
-	healthHandler := provider.ProvideHealthHandler(config, database, client)
-	movieRepository := provider.ProvideMovieRepository(database)
-	movieService := provider.ProvideMovieService(movieRepository, logger)
-	showtimeRepository := provider.ProvideShowtimeRepository(database)
+	userRateLimiter := provider.ProvideUserRateLimiter(config, client, logger)
+	authMiddleware := provider.ProvideAuthMiddleware(jwtManager, userRateLimiter, logger)
+	cachedMovieRepository := provider.ProvideCachedMovieRepository(database, client, config)
+	movieRepository := provider.ProvideMovieRepository(cachedMovieRepository)
+	changeRecordRepository := provider.ProvideChangeRecordRepository(database)
+	ticketCheckInRepository := provider.ProvideTicketCheckInRepository(database)
+	changelogService := provider.ProvideChangeLogService(changeRecordRepository, logger)
+	movieMediaRepository := provider.ProvideMovieMediaRepository(database)
+	reviewRepository := provider.ProvideReviewRepository(database)
+	tmdbService := provider.ProvideTMDBService(config, logger)
+	popularityCalculator := provider.ProvidePopularityCalculator(movieRepository, config, logger)
+	showtimeRepository := provider.ProvideShowtimeRepository(database, client, config)
+	bus := provider.ProvideEventBus(config, logger)
+	userPreferencesRepository := provider.ProvideUserPreferencesRepository(database)
+	movieService := provider.ProvideMovieService(movieRepository, movieMediaRepository, reviewRepository, bookingRepository, showtimeRepository, userPreferencesRepository, changelogService, tmdbService, popularityCalculator, bus, logger)
 	cinemaRepository := provider.ProvideCinemaRepository(database)
 	screenRepository := provider.ProvideScreenRepository(database)
-	showtimeService := provider.ProvideShowtimeService(showtimeRepository, movieRepository, cinemaRepository, screenRepository, logger)
+	seatRepository := provider.ProvideSeatRepository(database, client, config)
+	seatHoldRepository := provider.ProvideSeatHoldRepository(client, database)
+	seatTypeRuleRepository := provider.ProvideSeatTypeRuleRepository(database)
+	showtimeService := provider.ProvideShowtimeService(showtimeRepository, movieRepository, cinemaRepository, screenRepository, seatRepository, seatHoldRepository, seatTypeRuleRepository, changelogService, bus, logger, config)
 	movieHandler := provider.ProvideMovieHandler(movieService, showtimeService, validator)
-	seatRepository := provider.ProvideSeatRepository(database)
-	cinemaService := provider.ProvideCinemaService(cinemaRepository, screenRepository, seatRepository, logger)
+	assistiveDeviceRepository := provider.ProvideAssistiveDeviceRepository(database)
+	cinemaService := provider.ProvideCinemaService(cinemaRepository, screenRepository, seatRepository, assistiveDeviceRepository, logger)
 	cinemaHandler := provider.ProvideCinemaHandler(cinemaService, validator)
-	showtimeHandler := provider.ProvideShowtimeHandler(showtimeService, validator)
-	engine := provider.ProvideRouter(config, logger, authMiddleware, authHandler, healthHandler, movieHandler, cinemaHandler, showtimeHandler)
+	cinemaStaffRepository := provider.ProvideCinemaStaffRepository(database)
+	cinemaAccessMiddleware := provider.ProvideCinemaAccessMiddleware(cinemaStaffRepository, showtimeRepository, logger)
+	showtimeHandler := provider.ProvideShowtimeHandler(showtimeService, cinemaAccessMiddleware, validator)
+	bookingSeatRepository := provider.ProvideBookingSeatRepository(database, reader)
+	tracker, err := provider.ProvideAnalyticsTracker(config, dispatcher, logger)
+	if err != nil {
+		return nil, err
+	}
+	groupCheckoutRepository := provider.ProvideGroupCheckoutRepository(database)
+	paymentRepository := provider.ProvidePaymentRepository(database)
+	transactor := provider.ProvideTransactor(database)
+	outboxRepository := provider.ProvideOutboxRepository(database)
+	promoCodeRepository := provider.ProvidePromoCodeRepository(database)
+	paymentStarter := provider.ProvidePaymentCheckout(paymentRepository, logger, config)
+	pricingRuleRepository := provider.ProvidePricingRuleRepository(database)
+	pricingRuleCache := provider.ProvidePricingRuleCache(client)
+	ruleBasedEngine := provider.ProvidePricingEngine(pricingRuleRepository, pricingRuleCache, logger, config)
+	seatUpdateFeed := provider.ProvideSeatUpdateFeed(client)
+	metricsMetrics, err := provider.ProvideMetrics(config, database, client, seatHoldRepository, cachedMovieRepository)
+	if err != nil {
+		return nil, err
+	}
+	outbox := provider.ProvideOutbox(outboxRepository)
+	bookingService := provider.ProvideBookingService(seatHoldRepository, showtimeRepository, seatRepository, bookingRepository, bookingSeatRepository, groupCheckoutRepository, assistiveDeviceRepository, seatTypeRuleRepository, promoCodeRepository, cinemaStaffRepository, ticketCheckInRepository, seatUpdateFeed, ruleBasedEngine, paymentStarter, tracker, metricsMetrics, transactor, outbox, logger, config)
+	confirmationService := provider.ProvideConfirmationService(bookingRepository, userRepository, seatRepository, dispatcher, emailClient, tracker, bus, logger, config)
+	bookingHandler := provider.ProvideBookingHandler(bookingService, confirmationService, validator)
+	groupcheckoutService := provider.ProvideGroupCheckoutService(groupCheckoutRepository, seatHoldRepository, bookingRepository, paymentRepository, userRepository, assistiveDeviceRepository, transactor, outbox, dispatcher, bus, logger, config)
+	groupCheckoutHandler := provider.ProvideGroupCheckoutHandler(groupcheckoutService, validator)
+	groupBookingRepository := provider.ProvideGroupBookingRepository(database)
+	groupBookingLock := provider.ProvideGroupBookingLock(client)
+	groupbookingService := provider.ProvideGroupBookingService(groupBookingRepository, groupBookingLock, showtimeRepository, seatHoldRepository, userRepository, bookingService, dispatcher, logger, config)
+	groupBookingHandler := provider.ProvideGroupBookingHandler(groupbookingService, validator)
+	holdRecoveryRepository := provider.ProvideHoldRecoveryRepository(database)
+	holdrecoveryService := provider.ProvideHoldRecoveryService(seatHoldRepository, holdRecoveryRepository, showtimeRepository, bookingRepository, groupCheckoutRepository, userRepository, bookingService, dispatcher, bus, logger, config)
+	holdRecoveryHandler := provider.ProvideHoldRecoveryHandler(holdrecoveryService, validator)
+	webhookEventRepository := provider.ProvideWebhookEventRepository(database)
+	gateway := provider.ProvidePaymentGateway(config)
+	service2 := provider.ProvidePaymentService(webhookEventRepository, paymentRepository, bookingRepository, userRepository, groupcheckoutService, bookingService, gateway, changelogService, dispatcher, transactor, outbox, tracker, logger, config)
+	paymentHandler := provider.ProvidePaymentHandler(service2, validator)
+	store := provider.ProvideJobStore(database)
+	dailyReportRepository := provider.ProvideDailyReportRepository(database)
+	dailyreportService := provider.ProvideDailyReportService(dailyReportRepository, bookingRepository, cinemaRepository, cinemaStaffRepository, dispatcher, logger, config)
+	demandRepository := provider.ProvideDemandRepository(database)
+	demandService := provider.ProvideDemandService(demandRepository, logger, config)
+	loyaltyRepository := provider.ProvideLoyaltyRepository(database)
+	loyaltyService := provider.ProvideLoyaltyService(loyaltyRepository, bus, logger, config)
+	relay := provider.ProvideOutboxRelay(outboxRepository, bus, logger, config)
+	runner := provider.ProvideJobRunner(store, groupcheckoutService, holdrecoveryService, service2, bookingService, dailyreportService, demandService, loyaltyService, popularityCalculator, relay, logger, config)
+	bookingAnalyticsCache := provider.ProvideBookingAnalyticsCache(client)
+	adminanalyticsService := provider.ProvideAdminAnalyticsService(bookingRepository, cinemaStaffRepository, bookingAnalyticsCache, logger, config)
+	adminHandler := provider.ProvideAdminHandler(reader, runner, adminanalyticsService, validator)
+	changeLogHandler := provider.ProvideChangeLogHandler(changelogService, validator)
+	featuredSlotRepository := provider.ProvideFeaturedSlotRepository(database)
+	curationService := provider.ProvideCurationService(featuredSlotRepository, movieRepository, logger, config)
+	curationHandler := provider.ProvideCurationHandler(curationService, validator)
+	guestLookupRepository := provider.ProvideGuestLookupRepository(client)
+	guestlookupService := provider.ProvideGuestLookupService(bookingRepository, guestLookupRepository, dispatcher, logger, config)
+	guestLookupHandler := provider.ProvideGuestLookupHandler(guestlookupService, validator)
+	dailyReportHandler := provider.ProvideDailyReportHandler(dailyreportService, validator)
+	analyticsHandler := provider.ProvideAnalyticsHandler(tracker, validator)
+	demandHandler := provider.ProvideDemandHandler(demandService, validator)
+	waitlistRepository := provider.ProvideWaitlistRepository(database)
+	waitlistService := provider.ProvideWaitlistService(waitlistRepository, showtimeRepository, userRepository, bookingService, dispatcher, bus, logger, config)
+	waitlistHandler := provider.ProvideWaitlistHandler(waitlistService, validator)
+	pricingService := provider.ProvidePricingService(pricingRuleRepository, cinemaRepository, ruleBasedEngine, logger)
+	pricingHandler := provider.ProvidePricingHandler(pricingService, validator)
+	promoService := provider.ProvidePromoService(promoCodeRepository, logger)
+	promoHandler := provider.ProvidePromoHandler(promoService, validator)
+	seatfeedService := provider.ProvideSeatFeedService(seatUpdateFeed, logger)
+	seatUpdateHandler := provider.ProvideSeatUpdateHandler(seatfeedService, showtimeService, config)
+	loyaltyHandler := provider.ProvideLoyaltyHandler(loyaltyService)
+	preferencesService := provider.ProvidePreferencesService(userPreferencesRepository, logger)
+	preferencesHandler := provider.ProvidePreferencesHandler(preferencesService, validator)
+	warmupService := provider.ProvideWarmupService(showtimeRepository, bookingService, confirmationService, curationService, ruleBasedEngine, logger, config)
+	healthHandler := provider.ProvideHealthHandler(config, database, client, reader, warmupService)
+	rateLimiter := provider.ProvideRateLimiter(config, client, logger)
+	idempotency := provider.ProvideIdempotency(config, client, logger)
+	engine := provider.ProvideRouter(config, logger, metricsMetrics, authMiddleware, cinemaAccessMiddleware, rateLimiter, idempotency, authHandler, healthHandler, movieHandler, cinemaHandler, showtimeHandler, bookingHandler, groupCheckoutHandler, groupBookingHandler, holdRecoveryHandler, paymentHandler, adminHandler, changeLogHandler, curationHandler, guestLookupHandler, dailyReportHandler, analyticsHandler, demandHandler, waitlistHandler, pricingHandler, promoHandler, seatUpdateHandler, loyaltyHandler, preferencesHandler)
 	server := provider.ProvideHTTPServer(config, engine, logger)
 	tracer, err := provider.ProvideTracer(config)
 	if err != nil {
@@ -69,7 +176,14 @@ func InitializeApplication(configPath string) (*Application, error) {
 		DB:          database,
 		RedisClient: client,
 		Tracer:      tracer,
+		Metrics:     metricsMetrics,
 		Config:      config,
+		Dispatcher:  dispatcher,
+		EventBus:    bus,
+		Jobs:        runner,
+		Analytics:   tracker,
+		Warmup:      warmupService,
+		SeatFeed:    seatfeedService,
 	}
 	return application, nil
 }
@@ -83,5 +197,12 @@ type Application struct {
 	DB          *postgres.Database
 	RedisClient *redis.Client
 	Tracer      *tracer.Tracer
+	Metrics     *metrics.Metrics
 	Config      *config.Config
+	Dispatcher  *async.Dispatcher
+	EventBus    *eventbus.Bus
+	Jobs        *scheduler.Runner
+	Analytics   *analytics.Tracker
+	Warmup      *warmup.Service
+	SeatFeed    *seatfeed.Service
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/swaggo/swag"
+	"github.com/swaggo/swag/gen"
+)
+
+var (
+	flags  = flag.NewFlagSet("swag", flag.ExitOnError)
+	output = flags.String("output", "docs", "directory the spec is written to")
+	quiet  = flags.Bool("quiet", false, "do not log the files parsed")
+)
+
+// Generates the API spec from the handler annotations, the same as
+// `swag init -g cmd/api/main.go --parseInternal` without installing the swag
+// CLI. Run from the backend directory (make docs).
+func main() {
+	flags.Parse(os.Args[1:])
+
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	if *quiet {
+		logger.SetOutput(io.Discard)
+	}
+
+	if err := generate(".", *output, logger); err != nil {
+		log.Fatalf("swag: %v", err)
+	}
+}
+
+// generate writes the spec of the module at dir to output
+func generate(dir, output string, logger *log.Logger) error {
+	return gen.New().Build(&gen.Config{
+		SearchDir:          dir,
+		MainAPIFile:        "cmd/api/main.go",
+		OutputDir:          output,
+		OutputTypes:        []string{"go", "json", "yaml"},
+		PropNamingStrategy: swag.CamelCase,
+		ParseInternal:      true,
+		ParseDependency:    1,
+		ParseGoList:        true,
+		ParseDepth:         100,
+		PackageName:        "docs",
+		LeftTemplateDelim:  "{{",
+		RightTemplateDelim: "}}",
+		CollectionFormat:   "csv",
+		Debugger:           logger,
+	})
+}
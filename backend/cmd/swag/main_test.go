@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestDocsAreUpToDate regenerates the spec and compares it with the one
+// committed in docs/, so a handler annotation changed without make docs
+// fails the build
+func TestDocsAreUpToDate(t *testing.T) {
+	output := t.TempDir()
+	if err := generate("../..", output, log.New(io.Discard, "", 0)); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	for _, name := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+		want, err := os.ReadFile(filepath.Join("../../docs", name))
+		if err != nil {
+			t.Fatalf("read committed spec: %v", err)
+		}
+		got, err := os.ReadFile(filepath.Join(output, name))
+		if err != nil {
+			t.Fatalf("read generated spec: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("docs/%s is out of date; run make docs", name)
+		}
+	}
+}
+
+func TestSpecDocumentsAuthAndErrors(t *testing.T) {
+	data, err := os.ReadFile("../../docs/swagger.json")
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	var spec struct {
+		BasePath            string `json:"basePath"`
+		SecurityDefinitions map[string]struct {
+			Type string `json:"type"`
+			In   string `json:"in"`
+			Name string `json:"name"`
+		} `json:"securityDefinitions"`
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+
+	if spec.BasePath != "/api/v1" {
+		t.Errorf("basePath = %q, want /api/v1", spec.BasePath)
+	}
+	if bearer := spec.SecurityDefinitions["BearerAuth"]; bearer.In != "header" || bearer.Name != "Authorization" {
+		t.Errorf("BearerAuth = %+v, want an Authorization header", bearer)
+	}
+
+	errorResponse, ok := spec.Definitions["cinemaos-backend_internal_pkg_response.ErrorResponse"]
+	if !ok {
+		t.Fatal("ErrorResponse is not documented")
+	}
+	codes := errorResponse.Properties["code"].Enum
+	for _, code := range []string{"VALIDATION_ERROR", "NOT_FOUND", "SEAT_NOT_AVAILABLE", "SHOWTIME_FULL"} {
+		if !slices.Contains(codes, code) {
+			t.Errorf("error codes %v do not include %s", codes, code)
+		}
+	}
+}
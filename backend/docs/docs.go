@@ -0,0 +1,11498 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/analytics/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get bookings, revenue, cancellations and occupancy by day, week or month, with the top movies and revenue by screen type. Managers must pass one of their cinemas. Send Accept: text/csv to export one section as CSV. Reports are cached for a few minutes.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get booking analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "date_from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "date_to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "time_series",
+                            "top_movies",
+                            "screen_types"
+                        ],
+                        "type": "string",
+                        "description": "Section picks the table of a CSV export",
+                        "name": "section",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "how many top movies",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_adminanalytics.BookingAnalyticsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find bookings by reference, customer email (guest or account), showtime, status, payment status and booked_at range, with the movie, showtime, seats and payment of each. Managers and staff must pass one of their cinemas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Search bookings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "booked_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "booked_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "name": "booking_reference",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "CONFIRMED",
+                            "COMPLETED",
+                            "CANCELLED",
+                            "REFUNDED",
+                            "EXPIRED"
+                        ],
+                        "type": "string",
+                        "name": "booking_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "description": "guest or account email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "PAID",
+                            "FAILED",
+                            "REFUNDED",
+                            "CANCELLED"
+                        ],
+                        "type": "string",
+                        "name": "payment_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "showtime_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "booked_at",
+                            "-booked_at"
+                        ],
+                        "type": "string",
+                        "description": "-booked_at is newest first",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_booking.StaffBookingResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/bookings/check-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admit the holder of a scanned e-ticket, or of a booking reference typed in with the showtime being admitted. The booking must be for that showtime, when given, and confirmed and paid for a show today, within the check-in window around its start; it is then marked COMPLETED. A ticket scanned a second time fails with ALREADY_CHECKED_IN and the time of the first scan. Managers and staff can only check in tickets for their cinemas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Check in a ticket",
+                "parameters": [
+                    {
+                        "description": "Scanned QR code or booking reference",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CheckInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.StaffBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/bookings/{id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes made to a booking by background jobs such as payment reconciliation, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List booking changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "exclude_system",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_changelog.ChangeRecordResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cinemas/{id}/daily-reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List a cinema's end-of-day reports, newest business date first. Managers see only their assigned cinemas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List daily reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_dailyreport.DailyReportResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Build a cinema's report for a date again after late corrections, replacing the saved report and emailing the cinema's managers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Regenerate a daily report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Business date",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_dailyreport.RegenerateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_dailyreport.DailyReportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/demand-stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export confirmed bookings rolled up by movie, cinema, day of week, time bucket and screen type, as JSON or CSV",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export showtime demand stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "movie_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_demand.ExportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/featured-slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List homepage slots, including scheduled and expired ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List featured slots",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "name": "active_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "HERO",
+                            "FEATURED"
+                        ],
+                        "type": "string",
+                        "name": "position",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "MOVIE",
+                            "BANNER"
+                        ],
+                        "type": "string",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_curation.SlotResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Feature a movie or a promotional banner on the homepage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create featured slot",
+                "parameters": [
+                    {
+                        "description": "Slot",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.CreateSlotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.SlotResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/featured-slots/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get featured slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.SlotResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update featured slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.UpdateSlotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.SlotResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete featured slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the health and last runs of every scheduled background job across all instances",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List background jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_pkg_scheduler.JobStatus"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List movies including inactive ones, and deleted ones with include_deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List movies for admins",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "is_coming_soon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "is_now_showing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search matches titles, descriptions, directors and cast, best matches\nfirst; quotes, \"or\" and a leading \"-\" work as in web searches",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the field-level changes made to a movie, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List movie changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "exclude_system",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_changelog.ChangeRecordResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/recalculate-popularity": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recompute a movie's popularity score now rather than on the next hourly run",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Recalculate movie popularity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bring back a deleted movie",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Restore movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/shadow-reads": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get shadow-read sampling settings and per-comparison mismatch counters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get shadow-read settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_handler.ShadowReadsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change shadow-read sampling at runtime; the change is not persisted across restarts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update shadow-read settings",
+                "parameters": [
+                    {
+                        "description": "Shadow-read settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateShadowReadsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_handler.ShadowReadsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/showtimes/{id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the field-level changes made to a showtime, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List showtime changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "exclude_system",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_changelog.ChangeRecordResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stats/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get total, confirmed and cancelled bookings, tickets sold, gross revenue, refunds, revenue less refunds and the average ticket price, with the same by day and the 10 movies with the most revenue. Revenue counts paid and refunded bookings less their refunds. Managers must pass one of their cinemas. Stats are cached for a few minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get booking stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "date_from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "date_to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_adminanalytics.BookingStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List received payment webhook events, e.g. the failed ones awaiting replay",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List webhook events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "payment_reference",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "RECEIVED",
+                            "PROCESSED",
+                            "FAILED",
+                            "IGNORED",
+                            "REJECTED"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_payment.WebhookEventResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Process several stored webhook events again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replay webhook events",
+                "parameters": [
+                    {
+                        "description": "Webhook event IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_payment.ReplayBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_payment.ReplayBatchResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Process a stored webhook event again. Already processed events are left unchanged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replay webhook event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_payment.WebhookEventResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/events": {
+            "post": {
+                "description": "Record a batch of booking funnel events observed by the client. Properties must be scalar values; string values that look like email addresses are dropped. Events are sampled and written asynchronously.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Record client funnel events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client-generated visitor UUID",
+                        "name": "X-Anonymous-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TrackEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_handler.TrackEventsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/change-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change password for authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Send password reset email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "Email address",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/google": {
+            "post": {
+                "description": "Sign in with the ID token from Google Sign-In. A new Google account is registered as a customer; an existing user with the same verified email is linked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login with Google",
+                "parameters": [
+                    {
+                        "description": "Google ID token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.GoogleLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout user",
+                "parameters": [
+                    {
+                        "description": "Refresh token to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-others": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every refresh token of the user except the current session's, identified by the refresh token in the body or else by the access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout other sessions",
+                "parameters": [
+                    {
+                        "description": "Refresh token of the current session",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.LogoutOthersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.LogoutOthersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get profile of authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update authenticated user's profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "description": "Profile updates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me/avatar": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the authenticated user's avatar with a JPEG or PNG image of at most 5MB, sent as the \"avatar\" field of a multipart form",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Upload avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "JPEG or PNG image, at most 5MB",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's favorite genres, cinemas and formats and notification settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_preferences.PreferencesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save the current user's preferences. Lists and settings left out keep their current value; an empty list clears it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_preferences.UpdatePreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_preferences.PreferencesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. The presented token is revoked; presenting it again revokes every session of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.TokenRefreshResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Reset password using token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the user's unexpired sessions, newest first, marking the current one and those that were logged out",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_auth.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Verify an email address using the token from the verification email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "description": "Verification token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_auth.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Email the current user a new link to verify their email address",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Send verification email",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the current user's bookings, newest first, with whether each can be cancelled now and the refund percentage that would apply. status narrows them to upcoming (confirmed, show not yet started), past (completed, or confirmed and the show has started) or cancelled bookings, judged in each cinema's timezone; date_from and date_to bound the show date. Pages are linked by meta.next_cursor; passing page instead returns numbered pages with totals.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "List my bookings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upcoming, past or cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First show date (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last show date (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, for numbered pages",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_booking.BookingSummaryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/claim": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start adding a guest booking to the signed-in account. A code is emailed to the booking's guest email and the booking is moved by the verify endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Claim a guest booking",
+                "parameters": [
+                    {
+                        "description": "Booking reference",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.ClaimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.ClaimResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/claim/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add the guest booking to the signed-in account once the code emailed by the claim is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Verify a guest booking claim",
+                "parameters": [
+                    {
+                        "description": "Booking reference and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.ClaimVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.GuestBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn a seat hold into a booking pending payment, at the prices quoted by the hold. Loyalty points in redeem_points are taken off the seats at $0.01 each. A retry sent with the same X-Idempotency-Key gets the first successful response back instead of a second booking. When the payment cannot be started the booking is still returned, with payment_error instead of checkout_url, and the payment is retried with POST /bookings/{id}/payment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Confirm booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request with",
+                        "name": "X-Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Hold to confirm",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ConfirmBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ConfirmBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/hold": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Temporarily hold seats for a showtime",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Hold seats",
+                "parameters": [
+                    {
+                        "description": "Seats to hold",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldSeatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/promo-code": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Price a seat hold with a promo code before confirming it. An INVALID_PROMO_CODE error says why the code does not apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Validate a promo code",
+                "parameters": [
+                    {
+                        "description": "Hold and promo code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ValidatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.PromoQuoteResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/release": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give up a seat hold owned by the current user so its seats are available again. Releasing a hold that has already expired or been released succeeds without freeing any seats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Release seat hold",
+                "parameters": [
+                    {
+                        "description": "Hold to release",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ReleaseHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ReleaseHoldResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a booking for its owner or an admin. Guests without an account pass the booking reference and email instead of signing in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Booking reference, for guest bookings",
+                        "name": "booking_reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest email, for guest bookings",
+                        "name": "email",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.BookingDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a booking and release its seats. Paid bookings are refunded under the cancellation policy and cannot be cancelled close to the showtime. Guests without an account pass the booking reference and email in the body.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Cancel booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Guest proof and reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CancelBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CancelBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/payment": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start the payment of a pending booking again when confirming it returned a payment_error instead of a checkout URL. Only possible while the booking has not expired and no checkout is open for it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Retry booking payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ConfirmBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/seatmap.png": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the auditorium with the booking's seats highlighted, for email clients without SVG support",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get booking seat plan as PNG",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/seatmap.svg": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the auditorium with the booking's seats highlighted, for printed tickets",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get booking seat plan as SVG",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/ticket": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the confirmed booking's e-ticket as a PDF with a QR code of the booking reference",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Download booking e-ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas": {
+            "get": {
+                "description": "List cinemas with filters and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "List cinemas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "description": "Query searches the name, city and address, best match first",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "description": "older name for q",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new cinema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Create cinema",
+                "parameters": [
+                    {
+                        "description": "Cinema details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CreateCinemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/nearby": {
+            "get": {
+                "description": "List active cinemas within a radius of a point, nearest first, with their distance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Find nearby cinemas",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 0,
+                        "type": "number",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/search": {
+            "get": {
+                "description": "Search active cinemas by name, city and address, best match first. A single word matches anywhere in the text; several words match whole words, the last as a prefix.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Search cinemas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "description": "Query searches the name, city and address, best match first",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "description": "older name for q",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}": {
+            "get": {
+                "description": "Get a cinema by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get cinema by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing cinema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Update cinema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cinema updates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.UpdateCinemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CinemaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/pricing-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List a cinema's pricing rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "List pricing rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.PricingRuleResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a rule that adjusts a cinema's seat prices by seat type, time of day, day of week or occupancy, optionally only on one screen type or within a validity window. Of the matching rules of each type only those with the highest priority apply. Percentage modifiers compound on the base price by seat type, then flat amounts are added.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Create pricing rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pricing rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.CreatePricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.PricingRuleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/pricing-rules/{ruleId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get one of a cinema's pricing rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get pricing rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pricing rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.PricingRuleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a pricing rule's modifier, condition, screen type, priority or validity window. Omitted fields are kept; the clear_* flags remove optional ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Update pricing rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pricing rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.UpdatePricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_pricing.PricingRuleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove one of a cinema's pricing rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Delete pricing rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pricing rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/screens": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new screen to a cinema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Add screen to cinema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Screen details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.CreateScreenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.ScreenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/screens/{screenId}/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a screen's stock of assistive listening and audio description devices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get screen assistive devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Screen ID",
+                        "name": "screenId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.ScreenDevicesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a screen's stock of assistive devices; omitted device types have no stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Update screen assistive devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Screen ID",
+                        "name": "screenId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Device stock",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.UpdateScreenDevicesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.ScreenDevicesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/seats/{seatId}/companion": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Designate the free companion seat for a wheelchair seat",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Link wheelchair companion seat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wheelchair seat ID",
+                        "name": "seatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Companion seat",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.LinkCompanionSeatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-bookings": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Open a group booking for a showtime and get the link participants join with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-bookings"
+                ],
+                "summary": "Open a group booking",
+                "parameters": [
+                    {
+                        "description": "Group booking",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.CreateGroupBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.GroupBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-bookings/{token}": {
+            "get": {
+                "description": "Get the group booking behind a share link with the seats picked so far",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-bookings"
+                ],
+                "summary": "Get group booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.GroupBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-bookings/{token}/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Book the seats of everyone who has joined as one booking, paid for by the organizer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-bookings"
+                ],
+                "summary": "Finalize a group booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.FinalizeGroupBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-bookings/{token}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Join a group booking with your own seats, which are held for the group until it is finalized",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-bookings"
+                ],
+                "summary": "Join a group booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seats to join with",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.JoinGroupBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.JoinGroupBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-checkouts/shares/{token}": {
+            "get": {
+                "description": "Get the share an invitee was asked to pay",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-checkouts"
+                ],
+                "summary": "Get share by pay link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pay link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.SharePaymentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-checkouts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a group checkout organized by the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-checkouts"
+                ],
+                "summary": "Get group checkout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group checkout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.GroupCheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-checkouts/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pay the remainder or release the unpaid seats after the share window lapsed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-checkouts"
+                ],
+                "summary": "Resolve unpaid shares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group checkout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organizer decision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.ResolveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.GroupCheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest-bookings/lookup": {
+            "post": {
+                "description": "Find a guest booking by reference and email. When email verification is enabled a code is emailed to the guest and the booking is returned by the verify endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-bookings"
+                ],
+                "summary": "Look up a guest booking",
+                "parameters": [
+                    {
+                        "description": "Reference and email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.LookupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.LookupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest-bookings/lookup/verify": {
+            "post": {
+                "description": "Return the guest booking once the code emailed by the lookup is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-bookings"
+                ],
+                "summary": "Verify a guest booking lookup",
+                "parameters": [
+                    {
+                        "description": "Reference, email and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.VerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.LookupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Basic health check endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Kubernetes liveness probe",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Health check with dependency status. The server only listens once the database and Redis connections were tried, so this is unreachable rather than ready while they are retried at startup; a server that gave up on Redis reports \"degraded\", keeps seat holds in the database and stays ready.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Detailed health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/recover": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hold seats comparable to an expired hold using the token from the \"your seats were released\" email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holds"
+                ],
+                "summary": "Hold seats again from a recovery email",
+                "parameters": [
+                    {
+                        "description": "Recovery token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_holdrecovery.ReholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_holdrecovery.ReholdResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a seat hold owned by the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get seat hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}/extend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extend a seat hold owned by the current user by the hold time, up to the maximum hold lifetime. A hold already at that lifetime fails with HOLD_EXTENSION_LIMIT_REACHED.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Extend seat hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Showtime the hold is for",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ExtendHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}/split": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign the held seats to invitees who each pay their own share",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "group-checkouts"
+                ],
+                "summary": "Split a hold between several payers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share assignments",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.SplitHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.GroupCheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/home": {
+            "get": {
+                "description": "Get the active hero and featured slots with the now-showing and coming-soon rows. Responses carry an ETag and may be cached briefly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "home"
+                ],
+                "summary": "Get homepage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_curation.HomeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    }
+                }
+            }
+        },
+        "/info": {
+            "get": {
+                "description": "Get application information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Application info",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's loyalty points balance, what it is worth and when it expires without further activity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loyalty"
+                ],
+                "summary": "Get loyalty balance",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_loyalty.BalanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the points the current user earned, redeemed, got back or lost to expiry, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loyalty"
+                ],
+                "summary": "List loyalty transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_loyalty.TransactionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies": {
+            "get": {
+                "description": "List movies with filters and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "List movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "is_coming_soon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "is_now_showing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search matches titles, descriptions, directors and cast, best matches\nfirst; quotes, \"or\" and a leading \"-\" work as in web searches",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include, e.g. id,title,poster_url,genres",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Create movie",
+                "parameters": [
+                    {
+                        "description": "Movie details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.CreateMovieRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/coming-soon": {
+            "get": {
+                "description": "Get upcoming movies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get coming soon movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/now-showing": {
+            "get": {
+                "description": "Get movies currently showing. For a signed-in user with saved preferences, movies in their favorite genres come first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get now showing movies",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only movies matching the user's favorite genres, formats or cinemas",
+                        "name": "personalized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "description": "Get a movie by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get movie by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Update movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie updates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.UpdateMovieRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete a movie. A movie with upcoming scheduled showtimes is only deleted with force, which cancels them.",
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Delete movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cancel the movie's upcoming showtimes",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.DeleteMovieResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/media": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every asset of a movie's media gallery in order, published or not",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "List movie media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MediaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a trailer, teaser, still or alternative poster at the end of a movie's media gallery. Video links are normalized to their canonical YouTube or Vimeo URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Add movie media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media asset",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.CreateMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MediaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/media/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put a movie's media gallery in order. The list must name every asset of the movie exactly once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Reorder movie media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media IDs in order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.ReorderMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MediaResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/media/{mediaId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a media asset's URL, title or published flag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Update movie media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Media ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media updates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.UpdateMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MediaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an asset from a movie's media gallery",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Delete movie media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Media ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews": {
+            "get": {
+                "description": "List a movie's reviews, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "List movie reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_movie.ReviewResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rate a movie from 1 to 5 stars. Requires a confirmed or completed booking for the movie; each customer can review a movie once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Review a movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_movie.ReviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews/{reviewId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a review. Customers can remove their own reviews; admins can remove any.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Delete a movie review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/showtimes": {
+            "get": {
+                "description": "Get the upcoming showtimes of a movie grouped by cinema, then by date. Pages count cinemas; showtimes that have already started are left out. Listings are cached briefly, so seat counts may lag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get movie showtimes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cinema ID",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cinema city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First show date (YYYY-MM-DD), defaults to today",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last show date (YYYY-MM-DD), defaults to a week after date_from",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cinemas per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_showtime.CinemaShowtimesResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Receive a payment gateway event, including the Stripe checkout.session.* and payment_intent.* events. A paid checkout session confirms its booking; an expired or declined one releases the seats. The event is stored before it is processed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Payment gateway webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the body",
+                        "name": "X-Webhook-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stripe's signature, when Stripe sends the event",
+                        "name": "Stripe-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_payment.WebhookAckResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List promo codes, newest first, or look up a single code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "List promo codes",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "name": "active_only",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "description": "looks up a single code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_promo.PromoCodeResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a percentage or fixed-amount promo code. Codes are stored uppercase and must be unique, deleted codes included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Create promo code",
+                "parameters": [
+                    {
+                        "description": "Promo code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.CreatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.PromoCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Get promo code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.PromoCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a promo code's discount, limits or validity. Bookings already made keep their discount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Update promo code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.UpdatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.PromoCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Delete promo code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a promo code's redemptions, its paid bookings and the discount given, and optionally one user's uses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Get promo code stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "also count this user's uses",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_promo.PromoCodeStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/showtimes/{id}/check-seats": {
+            "post": {
+                "description": "Advisory check of whether seats are available, held or booked, to re-validate a selection just before holding it. Nothing is reserved. When some seats are gone a replacement selection of the same seat types may be suggested.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Probe seat availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seats to check",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CheckSeatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CheckSeatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/showtimes/{id}/seats": {
+            "get": {
+                "description": "Get seats and their availability for a showtime. Before sales open only the sales state is returned unless a valid pre-sale code is supplied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get showtime seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pre-sale access code",
+                        "name": "presale_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Access code of a private showtime",
+                        "name": "access_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.SeatMapResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/showtimes/{id}/waitlist": {
+            "post": {
+                "description": "Wait for seats at a sold-out showtime. When a cancellation releases enough seats they are held for signed-in customers and the customer is emailed. Guests without an account give an email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "showtimes"
+                ],
+                "summary": "Join showtime waitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seats wanted",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_waitlist.JoinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_waitlist.PositionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/showtimes/{id}/waitlist/position": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's place on a showtime's waitlist",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "showtimes"
+                ],
+                "summary": "Get waitlist position",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_waitlist.PositionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/check-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admit the holder of a scanned e-ticket, or of a booking reference typed in with the showtime being admitted. The booking must be for that showtime, when given, and confirmed and paid for a show today, within the check-in window around its start; it is then marked COMPLETED. A ticket scanned a second time fails with ALREADY_CHECKED_IN and the time of the first scan. Managers and staff can only check in tickets for their cinemas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Check in a ticket",
+                "parameters": [
+                    {
+                        "description": "Scanned QR code or booking reference",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.CheckInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.StaffBookingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/showtimes/{id}/check-ins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the bookings of a showtime checked in at the door, the latest first. Managers and staff can only list showtimes at their cinemas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List check-ins of a showtime",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Showtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/cinemaos-backend_internal_app_booking.StaffBookingResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "cinemaos-backend_internal_app_adminanalytics.BookingAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "screen_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_repository.ScreenTypeRevenue"
+                    }
+                },
+                "time_series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_repository.TimeBucket"
+                    }
+                },
+                "top_movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_repository.MovieBookingStats"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_adminanalytics.BookingStatsResponse": {
+            "type": "object",
+            "properties": {
+                "average_ticket_price": {
+                    "description": "gross revenue per ticket sold",
+                    "type": "number"
+                },
+                "cancelled_bookings": {
+                    "type": "integer"
+                },
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "confirmed_bookings": {
+                    "type": "integer"
+                },
+                "daily": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_repository.DailyBookingStats"
+                    }
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "gross_revenue": {
+                    "description": "paid for tickets, before refunds",
+                    "type": "number"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "tickets_sold": {
+                    "description": "of paid and refunded bookings",
+                    "type": "integer"
+                },
+                "top_movies": {
+                    "description": "by revenue less refunds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_repository.MovieBookingStats"
+                    }
+                },
+                "total_bookings": {
+                    "type": "integer"
+                },
+                "total_revenue": {
+                    "description": "gross revenue less refunds",
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_auth.UserResponse"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.GoogleLoginRequest": {
+            "type": "object",
+            "required": [
+                "id_token"
+            ],
+            "properties": {
+                "id_token": {
+                    "description": "from Google Sign-In",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.LogoutOthersRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.LogoutOthersResponse": {
+            "type": "object",
+            "properties": {
+                "revoked_sessions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "hold_recovery_emails": {
+                    "description": "HoldRecoveryEmails turns emails about expired seat holds on or off",
+                    "type": "boolean"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "password": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "signed in",
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "revoked": {
+                    "description": "logged out",
+                    "type": "boolean"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.TokenRefreshResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "replaces the presented token, which is now revoked",
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "hold_recovery_emails": {
+                    "description": "HoldRecoveryEmails turns emails about expired seat holds on or off",
+                    "type": "boolean"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.UserResponse": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "hold_recovery_emails": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_auth.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.BookingCustomerResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "empty for guests",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.BookingDetailResponse": {
+            "type": "object",
+            "properties": {
+                "booked_at": {
+                    "type": "string"
+                },
+                "booking_reference": {
+                    "type": "string"
+                },
+                "booking_status": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "movie_title": {
+                    "type": "string"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "pay_by": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "points_discount": {
+                    "type": "number"
+                },
+                "refund_amount": {
+                    "type": "number"
+                },
+                "screen_name": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "ticket_url": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.BookingPaymentSummary": {
+            "type": "object",
+            "properties": {
+                "card_last_four": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "gateway": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "points_discount": {
+                    "description": "PointsDiscount is what redeemed loyalty points took off",
+                    "type": "number"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.BookingSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "booked_at": {
+                    "type": "string"
+                },
+                "booking_reference": {
+                    "type": "string"
+                },
+                "booking_status": {
+                    "type": "string"
+                },
+                "can_cancel": {
+                    "description": "booking history only: whether it can be cancelled now",
+                    "type": "boolean"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "refund_percent": {
+                    "description": "booking history only: share of a paid booking refunded if cancelled now",
+                    "type": "number"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ticket_url": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.CancelBookingRequest": {
+            "type": "object",
+            "properties": {
+                "booking_reference": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "email": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.CancelBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booked_at": {
+                    "type": "string"
+                },
+                "booking_reference": {
+                    "type": "string"
+                },
+                "booking_status": {
+                    "type": "string"
+                },
+                "can_cancel": {
+                    "description": "booking history only: whether it can be cancelled now",
+                    "type": "boolean"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "type": "number"
+                },
+                "refund_percent": {
+                    "description": "booking history only: share of a paid booking refunded if cancelled now",
+                    "type": "number"
+                },
+                "refund_policy": {
+                    "description": "only for paid bookings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cinemaos-backend_internal_app_booking.RefundPolicyResponse"
+                        }
+                    ]
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ticket_url": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.CheckInRequest": {
+            "type": "object",
+            "properties": {
+                "booking_reference": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "payload": {
+                    "description": "the QR code's content",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "showtime_id": {
+                    "description": "the show being admitted; the ticket must be for it",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.CheckSeatsRequest": {
+            "type": "object",
+            "required": [
+                "seat_ids"
+            ],
+            "properties": {
+                "seat_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.CheckSeatsResponse": {
+            "type": "object",
+            "properties": {
+                "all_available": {
+                    "type": "boolean"
+                },
+                "alternatives": {
+                    "description": "Alternatives is a suggested replacement selection of the same seat\ntypes, given when some seats are gone and one can be found",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_booking.SeatStatusResponse"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ConfirmBookingRequest": {
+            "type": "object",
+            "required": [
+                "hold_id"
+            ],
+            "properties": {
+                "hold_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "CREDIT_CARD",
+                        "DEBIT_CARD",
+                        "PAYPAL",
+                        "APPLE_PAY",
+                        "GOOGLE_PAY"
+                    ]
+                },
+                "promo_code": {
+                    "description": "PromoCode is re-checked and redeemed when the booking is created",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "redeem_points": {
+                    "description": "RedeemPoints is how many loyalty points to take off the seats; no more\nthan the seats cost are used",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ConfirmBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "booking_reference": {
+                    "type": "string"
+                },
+                "booking_status": {
+                    "type": "string"
+                },
+                "checkout_url": {
+                    "description": "CheckoutURL is the payment page the customer is redirected to",
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "pay_by": {
+                    "description": "PayBy is when the booking expires unless paid",
+                    "type": "string"
+                },
+                "payment_error": {
+                    "description": "PaymentError says why there is no checkout URL; the payment is\nretried with POST /bookings/{id}/payment",
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "points_discount": {
+                    "type": "number"
+                },
+                "points_redeemed": {
+                    "description": "PointsRedeemed is how many loyalty points were taken off the seats,\nworth PointsDiscount",
+                    "type": "integer"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.DeviceRequest": {
+            "type": "object",
+            "required": [
+                "device_type",
+                "quantity"
+            ],
+            "properties": {
+                "device_type": {
+                    "type": "string",
+                    "enum": [
+                        "ASSISTED_LISTENING",
+                        "AUDIO_DESCRIPTION"
+                    ]
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ExtendHoldRequest": {
+            "type": "object",
+            "properties": {
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.HeldSeatResponse": {
+            "type": "object",
+            "properties": {
+                "fare": {
+                    "description": "COMPANION seats are free",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "seat_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "seat_label": {
+                    "type": "string"
+                },
+                "seat_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.HoldResponse": {
+            "type": "object",
+            "properties": {
+                "assistive_devices": {
+                    "description": "AssistiveDevices are reserved when the booking is confirmed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_entity.DeviceRequest"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "description": "booking fee, shown as its own line",
+                    "type": "number"
+                },
+                "hold_id": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HeldSeatResponse"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.HoldSeatsRequest": {
+            "type": "object",
+            "required": [
+                "seat_ids",
+                "showtime_id"
+            ],
+            "properties": {
+                "access_code": {
+                    "description": "required for private showtimes",
+                    "type": "string"
+                },
+                "assistive_devices": {
+                    "description": "AssistiveDevices are reserved from the screen's stock with the booking",
+                    "type": "array",
+                    "maxItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_booking.DeviceRequest"
+                    }
+                },
+                "presale_code": {
+                    "description": "unlocks seats before sales open",
+                    "type": "string"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.PromoQuoteResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.RefundPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "applies_until": {
+                    "description": "the next window, if any, starts here",
+                    "type": "string"
+                },
+                "refund_percent": {
+                    "type": "number"
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ReleaseHoldRequest": {
+            "type": "object",
+            "required": [
+                "hold_id",
+                "showtime_id"
+            ],
+            "properties": {
+                "hold_id": {
+                    "type": "string"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ReleaseHoldResponse": {
+            "type": "object",
+            "properties": {
+                "hold_id": {
+                    "type": "string"
+                },
+                "released_seats": {
+                    "description": "0 when the hold had already ended",
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.SeatMapResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "description": "Capacity is the number of seats that can be sold after capacity\noverrides; Available is what is left of it, held seats included",
+                    "type": "integer"
+                },
+                "sales_open_at": {
+                    "type": "string"
+                },
+                "sales_state": {
+                    "type": "string"
+                },
+                "seat_types": {
+                    "description": "SeatTypes lists the seat types with online sales rules",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_booking.SeatTypeInventoryResponse"
+                    }
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_booking.SeatMapSeatResponse"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "sold_out": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.SeatMapSeatResponse": {
+            "type": "object",
+            "properties": {
+                "companion_seat_id": {
+                    "description": "CompanionSeatID links a wheelchair seat to its free companion seat",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "fare": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "row_label": {
+                    "type": "string"
+                },
+                "seat_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "seat_label": {
+                    "type": "string"
+                },
+                "seat_number": {
+                    "type": "integer"
+                },
+                "seat_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "x_position": {
+                    "type": "number"
+                },
+                "y_position": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.SeatStatusResponse": {
+            "type": "object",
+            "properties": {
+                "seat_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "description": "AVAILABLE, LOCKED or BOOKED",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.SeatTypeInventoryResponse": {
+            "type": "object",
+            "properties": {
+                "box_office_only": {
+                    "type": "boolean"
+                },
+                "on_sale": {
+                    "type": "boolean"
+                },
+                "online_sales_cap": {
+                    "type": "integer"
+                },
+                "remaining_online": {
+                    "description": "RemainingOnline is left out for types without a cap",
+                    "type": "integer"
+                },
+                "sales_open_at": {
+                    "type": "string"
+                },
+                "seat_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.StaffBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booked_at": {
+                    "type": "string"
+                },
+                "booking_reference": {
+                    "type": "string"
+                },
+                "booking_status": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "checked_in_at": {
+                    "type": "string"
+                },
+                "checked_in_by": {
+                    "description": "staff user who scanned the ticket",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "customer": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_booking.BookingCustomerResponse"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "movie_title": {
+                    "type": "string"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "payment": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_booking.BookingPaymentSummary"
+                },
+                "sales_channel": {
+                    "type": "string"
+                },
+                "screen_name": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_booking.ValidatePromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "hold_id",
+                "promo_code"
+            ],
+            "properties": {
+                "hold_id": {
+                    "type": "string"
+                },
+                "promo_code": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_changelog.ChangeRecordResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_changes.Actor"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_pkg_changes.Change"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.CinemaResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "booking_fee": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.BookingFee"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "companion_policy_enabled": {
+                    "type": "boolean"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "description": "set on nearby searches",
+                    "type": "number"
+                },
+                "email": {
+                    "description": "Changed to pointer",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "Changed to pointer",
+                    "type": "string"
+                },
+                "screens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.ScreenResponse"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "Changed to pointer",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "description": "Changed to pointer",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.CreateCinemaRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "city",
+                "country",
+                "email",
+                "name",
+                "slug",
+                "state",
+                "zip_code"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 5
+                },
+                "city": {
+                    "type": "string"
+                },
+                "companion_policy_enabled": {
+                    "description": "free companion seat with a wheelchair space",
+                    "type": "boolean"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "state": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "defaults to UTC",
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.CreateScreenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "seating_capacity",
+                "type"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "seating_capacity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "type": {
+                    "description": "STANDARD, IMAX, 3D",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.LinkCompanionSeatRequest": {
+            "type": "object",
+            "properties": {
+                "companion_seat_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.ScreenDeviceRequest": {
+            "type": "object",
+            "required": [
+                "device_type"
+            ],
+            "properties": {
+                "device_type": {
+                    "type": "string",
+                    "enum": [
+                        "ASSISTED_LISTENING",
+                        "AUDIO_DESCRIPTION"
+                    ]
+                },
+                "total_count": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.ScreenDevicesResponse": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_entity.ScreenDevice"
+                    }
+                },
+                "screen_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.ScreenResponse": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "seating_capacity": {
+                    "type": "integer"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.SeatResponse"
+                    }
+                },
+                "type": {
+                    "description": "Restored",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.SeatResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "price_multiplier": {
+                    "type": "number"
+                },
+                "row_name": {
+                    "type": "string"
+                },
+                "screen_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "seat_number": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.UpdateCinemaRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 5
+                },
+                "city": {
+                    "type": "string"
+                },
+                "clear_fee_cap": {
+                    "type": "boolean"
+                },
+                "companion_policy_enabled": {
+                    "type": "boolean"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "fee_cap": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "fee_online_only": {
+                    "description": "waive the fee for walk-in sales",
+                    "type": "boolean"
+                },
+                "fee_refundable": {
+                    "description": "refund the fee with the tickets",
+                    "type": "boolean"
+                },
+                "fee_type": {
+                    "description": "Booking fee; omitted fields are left unchanged. fee_amount is a\npercent for PERCENTAGE fees and a flat amount otherwise.",
+                    "type": "string",
+                    "enum": [
+                        "NONE",
+                        "PER_TICKET",
+                        "PER_ORDER",
+                        "PERCENTAGE"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_cinema.UpdateScreenDevicesRequest": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "maxItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_cinema.ScreenDeviceRequest"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.CreateSlotRequest": {
+            "type": "object",
+            "required": [
+                "position",
+                "type"
+            ],
+            "properties": {
+                "active_from": {
+                    "type": "string"
+                },
+                "active_until": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "image_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "link_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "movie_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "position": {
+                    "type": "string",
+                    "enum": [
+                        "HERO",
+                        "FEATURED"
+                    ]
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "MOVIE",
+                        "BANNER"
+                    ]
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.HomeResponse": {
+            "type": "object",
+            "properties": {
+                "coming_soon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_curation.MovieSummary"
+                    }
+                },
+                "featured": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_curation.HomeSlot"
+                    }
+                },
+                "hero": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_curation.HomeSlot"
+                    }
+                },
+                "now_showing": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_curation.MovieSummary"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.HomeSlot": {
+            "type": "object",
+            "properties": {
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "link_url": {
+                    "type": "string"
+                },
+                "movie": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_curation.MovieSummary"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.MovieSummary": {
+            "type": "object",
+            "properties": {
+                "backdrop_url": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trailer_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.SlotResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "shown on the homepage right now",
+                    "type": "boolean"
+                },
+                "active_from": {
+                    "type": "string"
+                },
+                "active_until": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "link_url": {
+                    "type": "string"
+                },
+                "movie": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_curation.MovieSummary"
+                },
+                "movie_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_curation.UpdateSlotRequest": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "type": "string"
+                },
+                "active_until": {
+                    "type": "string"
+                },
+                "clear_active_from": {
+                    "type": "boolean"
+                },
+                "clear_active_until": {
+                    "type": "boolean"
+                },
+                "headline": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "image_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "link_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "movie_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "position": {
+                    "type": "string",
+                    "enum": [
+                        "HERO",
+                        "FEATURED"
+                    ]
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "MOVIE",
+                        "BANNER"
+                    ]
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_dailyreport.DailyReportResponse": {
+            "type": "object",
+            "properties": {
+                "business_date": {
+                    "type": "string"
+                },
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "data": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.DailyReportData"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_dailyreport.RegenerateRequest": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "description": "business date in the cinema's timezone",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_demand.DemandStatResponse": {
+            "type": "object",
+            "properties": {
+                "avg_lead_hours": {
+                    "type": "number"
+                },
+                "bookings": {
+                    "type": "integer"
+                },
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "day_of_week": {
+                    "description": "ISO, Monday is 1",
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "screen_type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.ScreenType"
+                },
+                "tickets": {
+                    "type": "integer"
+                },
+                "time_bucket": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.TimeBucket"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_demand.ExportResponse": {
+            "type": "object",
+            "properties": {
+                "stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_demand.DemandStatResponse"
+                    }
+                },
+                "watermark": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.BookingDevice": {
+            "type": "object",
+            "properties": {
+                "device_type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.DeviceType"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.BookingFee": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "flat amount, or percent for PERCENTAGE",
+                    "type": "number"
+                },
+                "cap": {
+                    "description": "upper bound of a PERCENTAGE fee",
+                    "type": "number"
+                },
+                "online_only": {
+                    "description": "waived for walk-in sales",
+                    "type": "boolean"
+                },
+                "refundable": {
+                    "description": "refunded together with the tickets",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.FeeType"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.DailyReportData": {
+            "type": "object",
+            "properties": {
+                "admissions": {
+                    "type": "integer"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "refunds": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.RefundTotals"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "revenue_by_method": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_entity.PaymentMethodTotal"
+                    }
+                },
+                "showtimes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_entity.ShowtimeSales"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "top_movie": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.MovieSales"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.DeviceRequest": {
+            "type": "object",
+            "properties": {
+                "device_type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.DeviceType"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.DeviceType": {
+            "type": "string",
+            "enum": [
+                "ASSISTED_LISTENING",
+                "AUDIO_DESCRIPTION"
+            ],
+            "x-enum-comments": {
+                "DeviceAssistedListening": "hearing assistance headset",
+                "DeviceAudioDescription": "descriptive audio receiver"
+            },
+            "x-enum-descriptions": [
+                "hearing assistance headset",
+                "descriptive audio receiver"
+            ],
+            "x-enum-varnames": [
+                "DeviceAssistedListening",
+                "DeviceAudioDescription"
+            ]
+        },
+        "cinemaos-backend_internal_app_entity.FeeType": {
+            "type": "string",
+            "enum": [
+                "NONE",
+                "PER_TICKET",
+                "PER_ORDER",
+                "PERCENTAGE"
+            ],
+            "x-enum-varnames": [
+                "FeeNone",
+                "FeePerTicket",
+                "FeePerOrder",
+                "FeePercent"
+            ]
+        },
+        "cinemaos-backend_internal_app_entity.MovieSales": {
+            "type": "object",
+            "properties": {
+                "admissions": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.PaymentMethodTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.RefundTotals": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.ScreenDevice": {
+            "type": "object",
+            "properties": {
+                "device_type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.DeviceType"
+                },
+                "screen_id": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.ScreenType": {
+            "type": "string",
+            "enum": [
+                "STANDARD",
+                "IMAX",
+                "4DX",
+                "DOLBY",
+                "VIP"
+            ],
+            "x-enum-varnames": [
+                "ScreenStandard",
+                "ScreenIMAX",
+                "Screen4DX",
+                "ScreenDolby",
+                "ScreenVIP"
+            ]
+        },
+        "cinemaos-backend_internal_app_entity.ShowtimeSales": {
+            "type": "object",
+            "properties": {
+                "admissions": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "movie_title": {
+                    "type": "string"
+                },
+                "occupancy": {
+                    "description": "admissions over capacity, 0 to 1",
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "screen_name": {
+                    "type": "string"
+                },
+                "showtime_id": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_entity.TimeBucket": {
+            "type": "string",
+            "enum": [
+                "MORNING",
+                "AFTERNOON",
+                "EVENING",
+                "LATE"
+            ],
+            "x-enum-comments": {
+                "TimeBucketAfternoon": "12:00 to 17:00",
+                "TimeBucketEvening": "17:00 to 21:00",
+                "TimeBucketLate": "from 21:00",
+                "TimeBucketMorning": "before 12:00"
+            },
+            "x-enum-descriptions": [
+                "before 12:00",
+                "12:00 to 17:00",
+                "17:00 to 21:00",
+                "from 21:00"
+            ],
+            "x-enum-varnames": [
+                "TimeBucketMorning",
+                "TimeBucketAfternoon",
+                "TimeBucketEvening",
+                "TimeBucketLate"
+            ]
+        },
+        "cinemaos-backend_internal_app_groupbooking.CreateGroupBookingRequest": {
+            "type": "object",
+            "required": [
+                "max_participants",
+                "showtime_id"
+            ],
+            "properties": {
+                "access_code": {
+                    "description": "required for private showtimes",
+                    "type": "string"
+                },
+                "max_participants": {
+                    "type": "integer",
+                    "maximum": 20,
+                    "minimum": 2
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupbooking.FinalizeGroupBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booking": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_booking.ConfirmBookingResponse"
+                },
+                "group": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.GroupBookingResponse"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupbooking.GroupBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "current_participants": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "max_participants": {
+                    "type": "integer"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.ParticipantResponse"
+                    }
+                },
+                "seat_ids": {
+                    "description": "every participant's seats",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "share_token": {
+                    "description": "ShareToken and ShareURL are only returned to the organizer when the\ngroup is created; the token is not stored",
+                    "type": "string"
+                },
+                "share_url": {
+                    "type": "string"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupbooking.JoinGroupBookingRequest": {
+            "type": "object",
+            "required": [
+                "seat_ids"
+            ],
+            "properties": {
+                "access_code": {
+                    "description": "required for private showtimes",
+                    "type": "string"
+                },
+                "presale_code": {
+                    "description": "unlocks seats before sales open",
+                    "type": "string"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupbooking.JoinGroupBookingResponse": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_groupbooking.GroupBookingResponse"
+                },
+                "hold": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldResponse"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupbooking.ParticipantResponse": {
+            "type": "object",
+            "properties": {
+                "joined_at": {
+                    "type": "string"
+                },
+                "organizer": {
+                    "type": "boolean"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.GroupCheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "group_reference": {
+                    "type": "string"
+                },
+                "hold_expires_at": {
+                    "type": "string"
+                },
+                "hold_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "paid_amount": {
+                    "type": "number"
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.ShareResponse"
+                    }
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.ResolveRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "PAY_REMAINDER",
+                        "RELEASE_UNPAID"
+                    ]
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.ShareAssignment": {
+            "type": "object",
+            "required": [
+                "email",
+                "seat_ids"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.SharePaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "type": "number"
+                },
+                "group_reference": {
+                    "type": "string"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "share_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.ShareResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "booking fee included",
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "description": "this share's part of the booking fee",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "seat_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_groupcheckout.SplitHoldRequest": {
+            "type": "object",
+            "required": [
+                "shares"
+            ],
+            "properties": {
+                "shares": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_groupcheckout.ShareAssignment"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.ClaimRequest": {
+            "type": "object",
+            "required": [
+                "reference"
+            ],
+            "properties": {
+                "reference": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.ClaimResponse": {
+            "type": "object",
+            "properties": {
+                "code_expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "sent_to": {
+                    "description": "masked guest email",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.ClaimVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "reference"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.GuestBookingResponse": {
+            "type": "object",
+            "properties": {
+                "assistive_devices": {
+                    "description": "AssistiveDevices are handed out by staff at check-in",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_entity.BookingDevice"
+                    }
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "guest_name": {
+                    "type": "string"
+                },
+                "movie_title": {
+                    "type": "string"
+                },
+                "num_tickets": {
+                    "type": "integer"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "screen_name": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.LookupRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "reference"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.LookupResponse": {
+            "type": "object",
+            "properties": {
+                "booking": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_guestlookup.GuestBookingResponse"
+                },
+                "code_expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "verification_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_guestlookup.VerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email",
+                "reference"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_holdrecovery.ReholdRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_holdrecovery.ReholdResponse": {
+            "type": "object",
+            "properties": {
+                "hold": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_booking.HoldResponse"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_loyalty.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is when the balance expires unless points are earned or\nredeemed before then",
+                    "type": "string"
+                },
+                "last_activity_at": {
+                    "type": "string"
+                },
+                "points_balance": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Value is what the balance takes off a booking when redeemed",
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_loyalty.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "points": {
+                    "description": "credits positive, debits negative",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "EARN, REDEEM, EXPIRE, REFUND or REVERSE",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.CreateMediaRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "url"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "TRAILER",
+                        "TEASER",
+                        "STILL",
+                        "POSTER_ALT"
+                    ]
+                },
+                "published": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.CreateMovieRequest": {
+            "type": "object",
+            "required": [
+                "format",
+                "slug"
+            ],
+            "properties": {
+                "backdrop_url": {
+                    "type": "string"
+                },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "STANDARD",
+                        "3D",
+                        "IMAX",
+                        "4DX",
+                        "DOLBY"
+                    ]
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imdb_rating": {
+                    "type": "number"
+                },
+                "is_coming_soon": {
+                    "type": "boolean"
+                },
+                "is_now_showing": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "original_title": {
+                    "type": "string"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tmdb_id": {
+                    "description": "TMDBId fills title, description, genres, cast, director, images,\nduration and release date from TMDB where left blank",
+                    "type": "integer"
+                },
+                "trailer_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.DeleteMovieResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled_showtimes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.MediaResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "published": {
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.MovieResponse": {
+            "type": "object",
+            "properties": {
+                "backdrop_url": {
+                    "type": "string"
+                },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "set in admin listings of deleted movies",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "imdb_rating": {
+                    "type": "number"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_coming_soon": {
+                    "type": "boolean"
+                },
+                "is_now_showing": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "media": {
+                    "description": "Media is the published media gallery in order, on movie details only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_movie.MediaResponse"
+                    }
+                },
+                "original_title": {
+                    "type": "string"
+                },
+                "popularity_score": {
+                    "type": "number"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tmdb_id": {
+                    "type": "integer"
+                },
+                "trailer_url": {
+                    "type": "string"
+                },
+                "user_rating": {
+                    "description": "average review rating, 1-5",
+                    "type": "number"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.ReorderMediaRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "movie_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.UpdateMediaRequest": {
+            "type": "object",
+            "properties": {
+                "published": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_movie.UpdateMovieRequest": {
+            "type": "object",
+            "properties": {
+                "backdrop_url": {
+                    "type": "string"
+                },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "STANDARD",
+                        "3D",
+                        "IMAX",
+                        "4DX",
+                        "DOLBY"
+                    ]
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imdb_rating": {
+                    "type": "number"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_coming_soon": {
+                    "type": "boolean"
+                },
+                "is_now_showing": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "original_title": {
+                    "type": "string"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trailer_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_payment.ReplayBatchRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_payment.ReplayBatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_payment.ReplayResult"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_payment.ReplayResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_payment.WebhookAckResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_payment.WebhookEventResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "booking_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "processed_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "signature_valid": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_preferences.PreferencesResponse": {
+            "type": "object",
+            "properties": {
+                "favorite_cinema_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "favorite_genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notification_email": {
+                    "type": "boolean"
+                },
+                "notification_push": {
+                    "type": "boolean"
+                },
+                "preferred_formats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "description": "unset until first saved",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_preferences.UpdatePreferencesRequest": {
+            "type": "object",
+            "required": [
+                "favorite_cinema_ids",
+                "favorite_genres"
+            ],
+            "properties": {
+                "favorite_cinema_ids": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "favorite_genres": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notification_email": {
+                    "type": "boolean"
+                },
+                "notification_push": {
+                    "type": "boolean"
+                },
+                "preferred_formats": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_pricing.CreatePricingRuleRequest": {
+            "type": "object",
+            "required": [
+                "condition_json",
+                "modifier_type",
+                "rule_type"
+            ],
+            "properties": {
+                "condition_json": {
+                    "description": "ConditionJSON is when the rule applies, e.g. {\"seat_types\":[\"VIP\"]},\n{\"from\":\"18:00\",\"to\":\"23:00\"}, {\"days\":[\"SAT\",\"SUN\"]} or\n{\"min_occupancy\":80}",
+                    "type": "object"
+                },
+                "modifier_type": {
+                    "type": "string",
+                    "enum": [
+                        "FLAT",
+                        "PERCENTAGE"
+                    ]
+                },
+                "modifier_value": {
+                    "description": "ModifierValue is an amount for FLAT and a percent for PERCENTAGE;\nnegative values are discounts",
+                    "type": "number",
+                    "maximum": 1000,
+                    "minimum": -1000
+                },
+                "priority": {
+                    "description": "Priority picks, among matching rules of the same type, the ones applied",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": -1000
+                },
+                "rule_type": {
+                    "type": "string",
+                    "enum": [
+                        "SEAT_TYPE",
+                        "TIME_OF_DAY",
+                        "DAY_OF_WEEK",
+                        "OCCUPANCY"
+                    ]
+                },
+                "screen_type": {
+                    "description": "ScreenType limits the rule to screens of one type",
+                    "type": "string",
+                    "enum": [
+                        "STANDARD",
+                        "IMAX",
+                        "4DX",
+                        "DOLBY",
+                        "VIP"
+                    ]
+                },
+                "valid_from": {
+                    "description": "ValidFrom and ValidUntil limit the rule to showtimes starting within them",
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_pricing.PricingRuleResponse": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "condition_json": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "modifier_type": {
+                    "type": "string"
+                },
+                "modifier_value": {
+                    "type": "number"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "rule_type": {
+                    "type": "string"
+                },
+                "screen_type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_pricing.UpdatePricingRuleRequest": {
+            "type": "object",
+            "properties": {
+                "clear_screen_type": {
+                    "type": "boolean"
+                },
+                "clear_valid_from": {
+                    "type": "boolean"
+                },
+                "clear_valid_until": {
+                    "type": "boolean"
+                },
+                "condition_json": {
+                    "type": "object"
+                },
+                "modifier_type": {
+                    "type": "string",
+                    "enum": [
+                        "FLAT",
+                        "PERCENTAGE"
+                    ]
+                },
+                "modifier_value": {
+                    "type": "number",
+                    "maximum": 1000,
+                    "minimum": -1000
+                },
+                "priority": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": -1000
+                },
+                "screen_type": {
+                    "type": "string",
+                    "enum": [
+                        "STANDARD",
+                        "IMAX",
+                        "4DX",
+                        "DOLBY",
+                        "VIP"
+                    ]
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_promo.CreatePromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "discount_type",
+                "valid_from",
+                "valid_until"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "discount_type": {
+                    "type": "string",
+                    "enum": [
+                        "PERCENTAGE",
+                        "FIXED"
+                    ]
+                },
+                "discount_value": {
+                    "description": "DiscountValue is a percent for PERCENTAGE and an amount for FIXED",
+                    "type": "number"
+                },
+                "is_active": {
+                    "description": "defaults to true",
+                    "type": "boolean"
+                },
+                "max_discount": {
+                    "type": "number"
+                },
+                "min_purchase": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "usage_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "usage_limit_per_user": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_promo.PromoCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_discount": {
+                    "type": "number"
+                },
+                "min_purchase": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usage_count": {
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "usage_limit_per_user": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "can be applied right now",
+                    "type": "boolean"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_promo.PromoCodeStatsResponse": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "description": "Bookings and TotalDiscount cover confirmed and completed bookings",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "promo_code_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "total_discount": {
+                    "type": "number"
+                },
+                "usage_count": {
+                    "description": "UsageCount counts the code's redemptions, pending bookings included",
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_promo.UpdatePromoCodeRequest": {
+            "type": "object",
+            "properties": {
+                "clear_max_discount": {
+                    "type": "boolean"
+                },
+                "clear_min_purchase": {
+                    "type": "boolean"
+                },
+                "clear_usage_limit": {
+                    "type": "boolean"
+                },
+                "clear_usage_limit_per_user": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "discount_type": {
+                    "type": "string",
+                    "enum": [
+                        "PERCENTAGE",
+                        "FIXED"
+                    ]
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_discount": {
+                    "type": "number"
+                },
+                "min_purchase": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "usage_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "usage_limit_per_user": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_repository.DailyBookingStats": {
+            "type": "object",
+            "properties": {
+                "cancelled_bookings": {
+                    "type": "integer"
+                },
+                "confirmed_bookings": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "description": "less refunds",
+                    "type": "number"
+                },
+                "total_bookings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_repository.MovieBookingStats": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "tickets": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_repository.ScreenTypeRevenue": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "screen_type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_entity.ScreenType"
+                },
+                "tickets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_repository.TimeBucket": {
+            "type": "object",
+            "properties": {
+                "cancelled_bookings": {
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "start of the bucket",
+                    "type": "string"
+                },
+                "occupancy_rate": {
+                    "description": "percent of seats sold for the bucket's showtimes",
+                    "type": "number"
+                },
+                "revenue": {
+                    "description": "of paid bookings",
+                    "type": "number"
+                },
+                "total_bookings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_showtime.CinemaShowtimesResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cinema_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_showtime.ShowtimeDateResponse"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_showtime.MovieShowtimeResponse": {
+            "type": "object",
+            "properties": {
+                "available_percent": {
+                    "description": "AvailablePercent is the share of the capacity still available, 0-100",
+                    "type": "integer"
+                },
+                "available_seats": {
+                    "description": "net of seats held for checkout",
+                    "type": "integer"
+                },
+                "end_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "screen type, e.g. IMAX",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "price_tier": {
+                    "type": "string"
+                },
+                "sales_state": {
+                    "type": "string"
+                },
+                "screen_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "screen_name": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_showtime.ShowtimeDateResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "showtimes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_showtime.MovieShowtimeResponse"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_waitlist.JoinRequest": {
+            "type": "object",
+            "required": [
+                "seat_count"
+            ],
+            "properties": {
+                "access_code": {
+                    "description": "required for private showtimes",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email is where guests without an account are notified",
+                    "type": "string"
+                },
+                "seat_count": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_waitlist.PositionResponse": {
+            "type": "object",
+            "properties": {
+                "entry_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "notified_at": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Position counts from 1 at the front of the queue; it is left out\nonce the entry is no longer waiting",
+                    "type": "integer"
+                },
+                "seat_count": {
+                    "type": "integer"
+                },
+                "showtime_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_warmup.State": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "RUNNING",
+                "DONE",
+                "BUDGET_EXCEEDED",
+                "SKIPPED"
+            ],
+            "x-enum-comments": {
+                "StateTimedOut": "items still running are no longer waited for"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "items still running are no longer waited for",
+                ""
+            ],
+            "x-enum-varnames": [
+                "StatePending",
+                "StateRunning",
+                "StateDone",
+                "StateTimedOut",
+                "StateSkipped"
+            ]
+        },
+        "cinemaos-backend_internal_app_warmup.Status": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_warmup.State"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_app_warmup.TaskResult"
+                    }
+                }
+            }
+        },
+        "cinemaos-backend_internal_app_warmup.TaskResult": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "boolean"
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "warmed": {
+                    "description": "entries loaded",
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_changes.Actor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_changes.ActorType"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_changes.ActorType": {
+            "type": "string",
+            "enum": [
+                "USER",
+                "SYSTEM"
+            ],
+            "x-enum-comments": {
+                "ActorSystem": "background jobs and other unattended callers"
+            },
+            "x-enum-descriptions": [
+                "",
+                "background jobs and other unattended callers"
+            ],
+            "x-enum-varnames": [
+                "ActorUser",
+                "ActorSystem"
+            ]
+        },
+        "cinemaos-backend_internal_pkg_changes.Change": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {}
+                },
+                "field": {
+                    "type": "string"
+                },
+                "new": {},
+                "old": {},
+                "removed": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_response.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "INTERNAL_ERROR",
+                        "VALIDATION_ERROR",
+                        "NOT_FOUND",
+                        "CONFLICT",
+                        "BAD_REQUEST",
+                        "UNAUTHORIZED",
+                        "FORBIDDEN",
+                        "TOO_MANY_REQUESTS",
+                        "FAILED_PRECONDITION",
+                        "TIMEOUT",
+                        "INVALID_CREDENTIALS",
+                        "TOKEN_EXPIRED",
+                        "TOKEN_INVALID",
+                        "EMAIL_NOT_VERIFIED",
+                        "ACCOUNT_DISABLED",
+                        "USER_NOT_FOUND",
+                        "MOVIE_NOT_FOUND",
+                        "BOOKING_NOT_FOUND",
+                        "SHOWTIME_NOT_FOUND",
+                        "CINEMA_NOT_FOUND",
+                        "SEAT_NOT_AVAILABLE",
+                        "EMAIL_ALREADY_EXISTS",
+                        "BOOKING_EXPIRED",
+                        "PAYMENT_FAILED",
+                        "INVALID_PROMO_CODE",
+                        "SEATS_ALREADY_BOOKED",
+                        "SALES_NOT_OPEN",
+                        "SALES_CLOSED",
+                        "SHOWTIME_FULL",
+                        "INVALID_STATUS_TRANSITION",
+                        "DEVICE_UNAVAILABLE",
+                        "SEAT_TYPE_NOT_ON_SALE",
+                        "SEAT_TYPE_ONLINE_CAP_REACHED",
+                        "INSUFFICIENT_LOYALTY_POINTS",
+                        "INVALID_TICKET",
+                        "ALREADY_CHECKED_IN",
+                        "VERSION_CONFLICT"
+                    ]
+                },
+                "details": {
+                    "type": "object"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_response.Meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "v2 cursor pagination",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_response.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.ErrorResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_pkg_response.Meta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_scheduler.JobStatus": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "health": {
+                    "type": "string"
+                },
+                "interval_ms": {
+                    "type": "integer"
+                },
+                "last_duration_ms": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_finished_at": {
+                    "type": "string"
+                },
+                "last_started_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "runs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemaos-backend_internal_pkg_shadow.Stats": {
+            "type": "object",
+            "properties": {
+                "compared": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "mismatched": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.CheckStatus": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.ClientEventRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "enum": [
+                        "showtime_viewed",
+                        "seat_selected",
+                        "seat_deselected",
+                        "checkout_started",
+                        "checkout_abandoned"
+                    ]
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "showtime_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/internal_handler.CheckStatus"
+                    }
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "shadow_reads": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_pkg_shadow.Stats"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "warmup": {
+                    "$ref": "#/definitions/cinemaos-backend_internal_app_warmup.Status"
+                }
+            }
+        },
+        "internal_handler.ShadowReadsResponse": {
+            "type": "object",
+            "properties": {
+                "comparisons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_in_flight": {
+                    "type": "integer"
+                },
+                "sample_rate": {
+                    "type": "number"
+                },
+                "stats": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/cinemaos-backend_internal_pkg_shadow.Stats"
+                    }
+                },
+                "timeout_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.TrackEventsRequest": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.ClientEventRequest"
+                    }
+                }
+            }
+        },
+        "internal_handler.TrackEventsResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.UpdateShadowReadsRequest": {
+            "type": "object",
+            "properties": {
+                "comparisons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "sample_rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "timeout_ms": {
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 1
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access token from /auth/login, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/api/v1",
+	Schemes:          []string{},
+	Title:            "CinemaOS API",
+	Description:      "Cinema booking platform: movies, cinemas, showtimes, seat holds, bookings and payments.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,47 @@
+package adminanalytics
+
+import (
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// BookingAnalyticsParams represents query parameters for booking analytics.
+// Dates are inclusive; managers must name one of their cinemas.
+type BookingAnalyticsParams struct {
+	CinemaID string `form:"cinema_id" validate:"omitempty,uuid"`
+	DateFrom string `form:"date_from" validate:"required,datetime=2006-01-02"`
+	DateTo   string `form:"date_to" validate:"required,datetime=2006-01-02"`
+	GroupBy  string `form:"group_by,default=day" validate:"oneof=day week month"`
+	Top      int    `form:"top,default=10" validate:"min=1,max=50"` // how many top movies
+	// Section picks the table of a CSV export
+	Section string `form:"section,default=time_series" validate:"oneof=time_series top_movies screen_types"`
+}
+
+// BookingAnalyticsResponse represents a booking analytics report
+type BookingAnalyticsResponse struct {
+	CinemaID    *uuid.UUID                     `json:"cinema_id,omitempty" swaggertype:"string" format:"uuid"`
+	DateFrom    string                         `json:"date_from"`
+	DateTo      string                         `json:"date_to"`
+	GroupBy     string                         `json:"group_by"`
+	TimeSeries  []repository.TimeBucket        `json:"time_series"`
+	TopMovies   []repository.MovieBookingStats `json:"top_movies"`
+	ScreenTypes []repository.ScreenTypeRevenue `json:"screen_types"`
+}
+
+// BookingStatsParams represents query parameters for booking stats. Dates
+// are inclusive; managers must name one of their cinemas.
+type BookingStatsParams struct {
+	CinemaID string `form:"cinema_id" validate:"omitempty,uuid"`
+	DateFrom string `form:"date_from" validate:"required,datetime=2006-01-02"`
+	DateTo   string `form:"date_to" validate:"required,datetime=2006-01-02"`
+}
+
+// BookingStatsResponse represents the booking stats of a cinema, or of
+// every cinema, over a date range
+type BookingStatsResponse struct {
+	CinemaID *uuid.UUID `json:"cinema_id,omitempty" swaggertype:"string" format:"uuid"`
+	DateFrom string     `json:"date_from"`
+	DateTo   string     `json:"date_to"`
+	repository.BookingStats
+}
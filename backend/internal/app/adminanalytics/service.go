@@ -0,0 +1,242 @@
+package adminanalytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxRangeDays bounds a report's date range so one request cannot scan
+	// years of bookings
+	maxRangeDays = 366
+	// statsTopMovies is how many movies booking stats break down
+	statsTopMovies = 10
+)
+
+// Service reports bookings and revenue to admins and cinema managers
+type Service struct {
+	bookingRepo repository.BookingRepository
+	staffRepo   repository.CinemaStaffRepository
+	cache       repository.BookingAnalyticsCache
+	cfg         config.ReportsConfig
+	logger      *logger.Logger
+}
+
+// NewService creates a new admin analytics service
+func NewService(
+	bookingRepo repository.BookingRepository,
+	staffRepo repository.CinemaStaffRepository,
+	cache repository.BookingAnalyticsCache,
+	cfg config.ReportsConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		bookingRepo: bookingRepo,
+		staffRepo:   staffRepo,
+		cache:       cache,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// BookingAnalytics returns bookings over time, the top movies and revenue
+// by screen type for the params. Admins may report on every cinema at once;
+// managers only on a cinema they are assigned to. Reports are cached per
+// query, so they may lag new bookings by the cache TTL.
+func (s *Service) BookingAnalytics(ctx context.Context, viewerID uuid.UUID, role string, params BookingAnalyticsParams) (*BookingAnalyticsResponse, error) {
+	from, to, cinemaID, err := s.scope(ctx, viewerID, role, params.CinemaID, params.DateFrom, params.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.report(ctx, cinemaID, from, to, params)
+	if err != nil {
+		return nil, err
+	}
+	return &BookingAnalyticsResponse{
+		CinemaID:    cinemaID,
+		DateFrom:    params.DateFrom,
+		DateTo:      params.DateTo,
+		GroupBy:     params.GroupBy,
+		TimeSeries:  report.TimeSeries,
+		TopMovies:   report.TopMovies,
+		ScreenTypes: report.ScreenTypes,
+	}, nil
+}
+
+// BookingStats returns booking totals, revenue net of refunds, the same by
+// day and the top movies by revenue for the params. Access is checked like
+// BookingAnalytics, and stats are cached per query for a few minutes.
+func (s *Service) BookingStats(ctx context.Context, viewerID uuid.UUID, role string, params BookingStatsParams) (*BookingStatsResponse, error) {
+	from, to, cinemaID, err := s.scope(ctx, viewerID, role, params.CinemaID, params.DateFrom, params.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fingerprint(cinemaID, from, to, "day", statsTopMovies)
+	stats, ok, err := s.cache.GetStats(ctx, key)
+	if err != nil || !ok {
+		if stats, err = s.bookingRepo.GetBookingStats(ctx, cinemaID, from, to, statsTopMovies); err != nil {
+			return nil, err
+		}
+		if err := s.cache.SetStats(ctx, key, stats, s.cfg.StatsCacheTTL); err != nil {
+			s.logger.WithContext(ctx).Debug("failed to cache booking stats", zap.Error(err))
+		}
+	}
+
+	return &BookingStatsResponse{
+		CinemaID:     cinemaID,
+		DateFrom:     params.DateFrom,
+		DateTo:       params.DateTo,
+		BookingStats: *stats,
+	}, nil
+}
+
+// scope parses the cinema and the inclusive dates of a report into the
+// half-open range queried, and checks the viewer may see it
+func (s *Service) scope(ctx context.Context, viewerID uuid.UUID, role, cinema, dateFrom, dateTo string) (time.Time, time.Time, *uuid.UUID, error) {
+	from, err := time.Parse("2006-01-02", dateFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, apperrors.ErrValidation("date_from must be a date like 2026-01-31")
+	}
+	to, err := time.Parse("2006-01-02", dateTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, apperrors.ErrValidation("date_to must be a date like 2026-01-31")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, nil, apperrors.ErrValidation("date_from must not be after date_to")
+	}
+	if to.Sub(from) >= maxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, nil, apperrors.ErrValidation(fmt.Sprintf("the date range cannot exceed %d days", maxRangeDays))
+	}
+	to = to.AddDate(0, 0, 1)
+
+	var cinemaID *uuid.UUID
+	if cinema != "" {
+		id, err := uuid.Parse(cinema)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, apperrors.ErrValidation("invalid cinema_id")
+		}
+		cinemaID = &id
+	}
+	if err := s.checkAccess(ctx, viewerID, role, cinemaID); err != nil {
+		return time.Time{}, time.Time{}, nil, err
+	}
+	return from, to, cinemaID, nil
+}
+
+// checkAccess lets admins see every cinema and managers their own
+func (s *Service) checkAccess(ctx context.Context, viewerID uuid.UUID, role string, cinemaID *uuid.UUID) error {
+	if entity.Role(role) != entity.RoleManager {
+		return nil
+	}
+	if cinemaID == nil {
+		return apperrors.ErrValidation("cinema_id is required for managers")
+	}
+	assigned, err := s.staffRepo.IsAssigned(ctx, *cinemaID, viewerID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return apperrors.ErrForbidden("you are not assigned to this cinema")
+	}
+	return nil
+}
+
+// report returns the cached report for the query or builds it. Cache
+// errors only cost the queries.
+func (s *Service) report(ctx context.Context, cinemaID *uuid.UUID, from, to time.Time, params BookingAnalyticsParams) (*repository.BookingAnalytics, error) {
+	key := fingerprint(cinemaID, from, to, params.GroupBy, params.Top)
+	report, ok, err := s.cache.Get(ctx, key)
+	if err == nil && ok {
+		return report, nil
+	}
+
+	report = &repository.BookingAnalytics{}
+	if report.TimeSeries, err = s.bookingRepo.GetBookingTimeSeries(ctx, cinemaID, from, to, params.GroupBy); err != nil {
+		return nil, err
+	}
+	if report.TopMovies, err = s.bookingRepo.GetTopMovies(ctx, cinemaID, params.Top, from, to); err != nil {
+		return nil, err
+	}
+	if report.ScreenTypes, err = s.bookingRepo.GetRevenueByScreenType(ctx, cinemaID, from, to); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Set(ctx, key, report, s.cfg.AnalyticsCacheTTL); err != nil {
+		s.logger.WithContext(ctx).Debug("failed to cache booking analytics", zap.Error(err))
+	}
+	return report, nil
+}
+
+// fingerprint identifies a report's query for caching; reports of
+// different kinds are cached under different prefixes
+func fingerprint(cinemaID *uuid.UUID, from, to time.Time, groupBy string, top int) string {
+	cinema := "all"
+	if cinemaID != nil {
+		cinema = cinemaID.String()
+	}
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%s|%s|%s|%d",
+		cinema, from.Format(time.DateOnly), to.Format(time.DateOnly), groupBy, top))
+	return hex.EncodeToString(sum[:16])
+}
+
+// WriteCSV writes one section of a report as CSV with a header row
+func WriteCSV(w io.Writer, report *BookingAnalyticsResponse, section string) error {
+	var rows [][]string
+	switch section {
+	case "top_movies":
+		rows = append(rows, []string{"movie_id", "title", "bookings", "tickets", "revenue"})
+		for _, m := range report.TopMovies {
+			rows = append(rows, []string{
+				m.MovieID.String(),
+				m.Title,
+				strconv.FormatInt(m.Bookings, 10),
+				strconv.FormatInt(m.Tickets, 10),
+				strconv.FormatFloat(m.Revenue, 'f', 2, 64),
+			})
+		}
+	case "screen_types":
+		rows = append(rows, []string{"screen_type", "bookings", "tickets", "revenue"})
+		for _, st := range report.ScreenTypes {
+			rows = append(rows, []string{
+				string(st.ScreenType),
+				strconv.FormatInt(st.Bookings, 10),
+				strconv.FormatInt(st.Tickets, 10),
+				strconv.FormatFloat(st.Revenue, 'f', 2, 64),
+			})
+		}
+	default:
+		rows = append(rows, []string{"date", "total_bookings", "revenue", "cancelled_bookings", "occupancy_rate"})
+		for _, b := range report.TimeSeries {
+			rows = append(rows, []string{
+				b.Date.Format(time.DateOnly),
+				strconv.FormatInt(b.TotalBookings, 10),
+				strconv.FormatFloat(b.Revenue, 'f', 2, 64),
+				strconv.FormatInt(b.CancelledBookings, 10),
+				strconv.FormatFloat(b.OccupancyRate, 'f', 2, 64),
+			})
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
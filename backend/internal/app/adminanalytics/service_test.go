@@ -0,0 +1,350 @@
+package adminanalytics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// stubReports returns a fixed report and records the queries it is asked
+type stubReports struct {
+	repository.BookingRepository
+	queries  int
+	cinemaID *uuid.UUID
+	from, to time.Time
+}
+
+func (s *stubReports) GetBookingTimeSeries(_ context.Context, cinemaID *uuid.UUID, from, to time.Time, _ string) ([]repository.TimeBucket, error) {
+	s.queries++
+	s.cinemaID, s.from, s.to = cinemaID, from, to
+	return []repository.TimeBucket{{Date: from, TotalBookings: 3, Revenue: 45, OccupancyRate: 12.5}}, nil
+}
+
+func (s *stubReports) GetTopMovies(context.Context, *uuid.UUID, int, time.Time, time.Time) ([]repository.MovieBookingStats, error) {
+	return []repository.MovieBookingStats{{MovieID: uuid.New(), Title: "Dune, Part Two", Bookings: 2, Tickets: 4, Revenue: 40}}, nil
+}
+
+func (s *stubReports) GetRevenueByScreenType(context.Context, *uuid.UUID, time.Time, time.Time) ([]repository.ScreenTypeRevenue, error) {
+	return []repository.ScreenTypeRevenue{{ScreenType: entity.ScreenIMAX, Bookings: 2, Tickets: 4, Revenue: 40}}, nil
+}
+
+// memStaff assigns staff to cinemas
+type memStaff struct {
+	repository.CinemaStaffRepository
+	assigned map[uuid.UUID]uuid.UUID // user to cinema
+}
+
+func (m *memStaff) IsAssigned(_ context.Context, cinemaID, userID uuid.UUID) (bool, error) {
+	return m.assigned[userID] == cinemaID, nil
+}
+
+// memCache caches reports in memory, or fails every call when broken
+type memCache struct {
+	repository.BookingAnalyticsCache
+	reports map[string]*repository.BookingAnalytics
+	broken  bool
+}
+
+func (m *memCache) Get(_ context.Context, key string) (*repository.BookingAnalytics, bool, error) {
+	if m.broken {
+		return nil, false, errors.New("cache down")
+	}
+	report, ok := m.reports[key]
+	return report, ok, nil
+}
+
+func (m *memCache) Set(_ context.Context, key string, report *repository.BookingAnalytics, _ time.Duration) error {
+	if m.broken {
+		return errors.New("cache down")
+	}
+	m.reports[key] = report
+	return nil
+}
+
+type analyticsFixture struct {
+	svc     *Service
+	reports *stubReports
+	cache   *memCache
+	cinema  uuid.UUID
+	manager uuid.UUID
+}
+
+func newAnalyticsFixture() *analyticsFixture {
+	f := &analyticsFixture{
+		reports: &stubReports{},
+		cache:   &memCache{reports: make(map[string]*repository.BookingAnalytics)},
+		cinema:  uuid.New(),
+		manager: uuid.New(),
+	}
+	staff := &memStaff{assigned: map[uuid.UUID]uuid.UUID{f.manager: f.cinema}}
+	f.svc = NewService(f.reports, staff, f.cache, config.ReportsConfig{AnalyticsCacheTTL: time.Minute},
+		&logger.Logger{Logger: zap.NewNop()})
+	return f
+}
+
+func params(cinemaID, from, to string) BookingAnalyticsParams {
+	return BookingAnalyticsParams{CinemaID: cinemaID, DateFrom: from, DateTo: to, GroupBy: "day", Top: 10}
+}
+
+func TestBookingAnalyticsValidation(t *testing.T) {
+	f := newAnalyticsFixture()
+	tests := []struct {
+		name   string
+		params BookingAnalyticsParams
+	}{
+		{"bad date_from", params("", "2026-02-30", "2026-03-01")},
+		{"bad date_to", params("", "2026-03-01", "tomorrow")},
+		{"reversed", params("", "2026-03-02", "2026-03-01")},
+		{"367 days", params("", "2025-01-01", "2026-01-02")},
+		{"bad cinema", params("cinema-1", "2026-03-01", "2026-03-02")},
+	}
+	for _, tt := range tests {
+		if _, err := f.svc.BookingAnalytics(context.Background(), uuid.New(), string(entity.RoleAdmin), tt.params); !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%s: err = %v, want a validation error", tt.name, err)
+		}
+	}
+
+	// A leap year's full 366 days is allowed
+	if _, err := f.svc.BookingAnalytics(context.Background(), uuid.New(), string(entity.RoleAdmin), params("", "2028-01-01", "2028-12-31")); err != nil {
+		t.Errorf("366 days: %v", err)
+	}
+}
+
+func TestBookingAnalyticsAccess(t *testing.T) {
+	f := newAnalyticsFixture()
+	manager := string(entity.RoleManager)
+	tests := []struct {
+		name   string
+		viewer uuid.UUID
+		role   string
+		cinema string
+		want   apperrors.ErrorCode // empty when allowed
+	}{
+		{"admin over every cinema", uuid.New(), string(entity.RoleAdmin), "", ""},
+		{"manager of the cinema", f.manager, manager, f.cinema.String(), ""},
+		{"manager without a cinema", f.manager, manager, "", apperrors.CodeValidation},
+		{"manager of another cinema", f.manager, manager, uuid.NewString(), apperrors.CodeForbidden},
+		{"unassigned manager", uuid.New(), manager, f.cinema.String(), apperrors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		_, err := f.svc.BookingAnalytics(context.Background(), tt.viewer, tt.role, params(tt.cinema, "2026-03-01", "2026-03-07"))
+		if tt.want == "" && err != nil || tt.want != "" && !apperrors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBookingAnalyticsIsCachedPerQuery(t *testing.T) {
+	ctx := context.Background()
+	f := newAnalyticsFixture()
+	admin := string(entity.RoleAdmin)
+
+	report, err := f.svc.BookingAnalytics(ctx, uuid.New(), admin, params(f.cinema.String(), "2026-03-01", "2026-03-07"))
+	if err != nil {
+		t.Fatalf("BookingAnalytics: %v", err)
+	}
+	// date_to is inclusive, so the query ends the day after
+	if *f.reports.cinemaID != f.cinema || !f.reports.from.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) ||
+		!f.reports.to.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("queried cinema %v from %v to %v", f.reports.cinemaID, f.reports.from, f.reports.to)
+	}
+	if len(report.TimeSeries) != 1 || len(report.TopMovies) != 1 || len(report.ScreenTypes) != 1 {
+		t.Errorf("report = %+v", report)
+	}
+
+	if _, err := f.svc.BookingAnalytics(ctx, uuid.New(), admin, params(f.cinema.String(), "2026-03-01", "2026-03-07")); err != nil {
+		t.Fatalf("BookingAnalytics: %v", err)
+	}
+	if f.reports.queries != 1 {
+		t.Errorf("the same query ran %d times, want once", f.reports.queries)
+	}
+
+	weekly := params(f.cinema.String(), "2026-03-01", "2026-03-07")
+	weekly.GroupBy = "week"
+	if _, err := f.svc.BookingAnalytics(ctx, uuid.New(), admin, weekly); err != nil {
+		t.Fatalf("BookingAnalytics: %v", err)
+	}
+	if f.reports.queries != 2 {
+		t.Errorf("another grouping ran %d queries, want 2", f.reports.queries)
+	}
+
+	// Without the cache every request queries the database
+	f.cache.broken = true
+	for range 2 {
+		if _, err := f.svc.BookingAnalytics(ctx, uuid.New(), admin, weekly); err != nil {
+			t.Fatalf("BookingAnalytics without the cache: %v", err)
+		}
+	}
+	if f.reports.queries != 4 {
+		t.Errorf("%d queries, want 4", f.reports.queries)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	f := newAnalyticsFixture()
+	report, err := f.svc.BookingAnalytics(context.Background(), uuid.New(), string(entity.RoleAdmin), params("", "2026-03-01", "2026-03-07"))
+	if err != nil {
+		t.Fatalf("BookingAnalytics: %v", err)
+	}
+
+	tests := []struct {
+		section string
+		want    string
+	}{
+		{"time_series", "date,total_bookings,revenue,cancelled_bookings,occupancy_rate\n2026-03-01,3,45.00,0,12.50\n"},
+		{"top_movies", ",\"Dune, Part Two\",2,4,40.00\n"},
+		{"screen_types", "screen_type,bookings,tickets,revenue\nIMAX,2,4,40.00\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteCSV(&b, report, tt.section); err != nil {
+			t.Fatalf("WriteCSV(%s): %v", tt.section, err)
+		}
+		if !strings.Contains(b.String(), tt.want) {
+			t.Errorf("%s:\n%s\nwant it to contain %q", tt.section, b.String(), tt.want)
+		}
+	}
+}
+
+// statsBookings serves fixed stats and records the queried range
+type statsBookings struct {
+	repository.BookingRepository
+	queries  int
+	cinemaID *uuid.UUID
+	from, to time.Time
+}
+
+func (m *statsBookings) GetBookingStats(_ context.Context, cinemaID *uuid.UUID, from, to time.Time, top int) (*repository.BookingStats, error) {
+	m.queries++
+	m.cinemaID, m.from, m.to = cinemaID, from, to
+	return &repository.BookingStats{TotalBookings: 3, TotalRevenue: 25}, nil
+}
+
+// memStatsCache keeps booking stats in memory; down makes every call fail
+// the way an unreachable Redis does
+type memStatsCache struct {
+	repository.BookingAnalyticsCache
+	stats map[string]*repository.BookingStats
+	down  bool
+}
+
+func (m *memStatsCache) GetStats(_ context.Context, key string) (*repository.BookingStats, bool, error) {
+	if m.down {
+		return nil, false, errors.New("cache is down")
+	}
+	stats, ok := m.stats[key]
+	return stats, ok, nil
+}
+
+func (m *memStatsCache) SetStats(_ context.Context, key string, stats *repository.BookingStats, _ time.Duration) error {
+	if m.down {
+		return errors.New("cache is down")
+	}
+	m.stats[key] = stats
+	return nil
+}
+
+// assignedStaff assigns one manager to one cinema
+type assignedStaff struct {
+	repository.CinemaStaffRepository
+	cinemaID, managerID uuid.UUID
+}
+
+func (m *assignedStaff) IsAssigned(_ context.Context, cinemaID, userID uuid.UUID) (bool, error) {
+	return cinemaID == m.cinemaID && userID == m.managerID, nil
+}
+
+type statsFixture struct {
+	svc      *Service
+	bookings *statsBookings
+	cache    *memStatsCache
+	staff    *assignedStaff
+}
+
+func newStatsFixture() *statsFixture {
+	f := &statsFixture{
+		bookings: &statsBookings{},
+		cache:    &memStatsCache{stats: make(map[string]*repository.BookingStats)},
+		staff:    &assignedStaff{cinemaID: uuid.New(), managerID: uuid.New()},
+	}
+	f.svc = NewService(f.bookings, f.staff, f.cache, config.ReportsConfig{StatsCacheTTL: time.Minute},
+		&logger.Logger{Logger: zap.NewNop()})
+	return f
+}
+
+func TestBookingStats(t *testing.T) {
+	ctx := context.Background()
+	f := newStatsFixture()
+	params := BookingStatsParams{CinemaID: f.staff.cinemaID.String(), DateFrom: "2026-10-01", DateTo: "2026-10-07"}
+
+	res, err := f.svc.BookingStats(ctx, f.staff.managerID, string(entity.RoleManager), params)
+	if err != nil {
+		t.Fatalf("BookingStats: %v", err)
+	}
+	if res.TotalBookings != 3 || res.TotalRevenue != 25 || *res.CinemaID != f.staff.cinemaID || res.DateTo != "2026-10-07" {
+		t.Errorf("response = %+v", res)
+	}
+	// The inclusive dates are queried as a half-open range
+	if !f.bookings.from.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || !f.bookings.to.Equal(time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("queried [%s, %s)", f.bookings.from, f.bookings.to)
+	}
+
+	// The same query is served from the cache; another one is not
+	if _, err := f.svc.BookingStats(ctx, uuid.New(), string(entity.RoleAdmin), params); err != nil {
+		t.Fatalf("BookingStats: %v", err)
+	}
+	if _, err := f.svc.BookingStats(ctx, uuid.New(), string(entity.RoleAdmin), BookingStatsParams{DateFrom: "2026-10-01", DateTo: "2026-10-07"}); err != nil {
+		t.Fatalf("BookingStats: %v", err)
+	}
+	if f.bookings.queries != 2 || f.bookings.cinemaID != nil {
+		t.Errorf("%d queries, last for cinema %v, want 2 with the last for every cinema", f.bookings.queries, f.bookings.cinemaID)
+	}
+
+	// Without the cache every request queries
+	f.cache.down = true
+	if _, err := f.svc.BookingStats(ctx, uuid.New(), string(entity.RoleAdmin), params); err != nil {
+		t.Fatalf("BookingStats with the cache down: %v", err)
+	}
+	if f.bookings.queries != 3 {
+		t.Errorf("%d queries, want 3 with the cache down", f.bookings.queries)
+	}
+}
+
+func TestBookingStatsRejects(t *testing.T) {
+	f := newStatsFixture()
+	manager := f.staff.managerID
+	cinema := f.staff.cinemaID.String()
+
+	tests := []struct {
+		name   string
+		viewer uuid.UUID
+		role   entity.Role
+		params BookingStatsParams
+		code   apperrors.ErrorCode
+	}{
+		{"bad date", manager, entity.RoleManager, BookingStatsParams{CinemaID: cinema, DateFrom: "2026-13-01", DateTo: "2026-10-07"}, apperrors.CodeValidation},
+		{"inverted range", manager, entity.RoleManager, BookingStatsParams{CinemaID: cinema, DateFrom: "2026-10-07", DateTo: "2026-10-01"}, apperrors.CodeValidation},
+		{"range too long", uuid.New(), entity.RoleAdmin, BookingStatsParams{DateFrom: "2025-01-01", DateTo: "2026-10-07"}, apperrors.CodeValidation},
+		{"manager without a cinema", manager, entity.RoleManager, BookingStatsParams{DateFrom: "2026-10-01", DateTo: "2026-10-07"}, apperrors.CodeValidation},
+		{"manager of another cinema", manager, entity.RoleManager, BookingStatsParams{CinemaID: uuid.NewString(), DateFrom: "2026-10-01", DateTo: "2026-10-07"}, apperrors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		if _, err := f.svc.BookingStats(context.Background(), tt.viewer, string(tt.role), tt.params); !apperrors.Is(err, tt.code) {
+			t.Errorf("%s: BookingStats = %v, want %s", tt.name, err, tt.code)
+		}
+	}
+	if f.bookings.queries != 0 {
+		t.Errorf("%d queries for rejected requests", f.bookings.queries)
+	}
+}
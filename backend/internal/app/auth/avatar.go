@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxAvatarSize is the largest avatar image accepted, in bytes
+const MaxAvatarSize = 5 << 20
+
+// avatarExtensions are the accepted avatar image types, by the content type
+// sniffed from the file, and the extension they are stored with
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// UpdateAvatar stores a JPEG or PNG image of at most MaxAvatarSize as the
+// user's avatar. The type is judged from the content, not the file name. An
+// avatar uploaded before is deleted once the new one is saved.
+func (s *Service) UpdateAvatar(ctx context.Context, userID uuid.UUID, file io.Reader) (*UserResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	if s.avatars == nil {
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition, "avatar uploads are not enabled")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxAvatarSize+1))
+	if err != nil {
+		return nil, apperrors.ErrBadRequest("failed to read avatar")
+	}
+	if len(data) > MaxAvatarSize {
+		return nil, apperrors.ErrValidation(fmt.Sprintf("avatar must be at most %dMB", MaxAvatarSize>>20))
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, apperrors.ErrValidation("avatar must be a JPEG or PNG image")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("avatars/%s/%s%s", user.ID, uuid.New(), ext)
+	url, err := s.avatars.Upload(ctx, key, contentType, bytes.NewReader(data))
+	if err != nil {
+		log.Error("failed to upload avatar", zap.String("key", key), zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to upload avatar")
+	}
+
+	previous := user.AvatarKey
+	user.AvatarURL = &url
+	user.AvatarKey = &key
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Error("failed to update user", zap.Error(err))
+		s.deleteAvatar(ctx, key)
+		return nil, err
+	}
+	if previous != nil && *previous != key {
+		s.deleteAvatar(ctx, *previous)
+	}
+
+	log.Info("avatar updated", zap.String("key", key))
+	return toUserResponse(user), nil
+}
+
+// deleteAvatar removes an avatar that is no longer used. A failure leaves
+// an orphaned file behind and is only logged.
+func (s *Service) deleteAvatar(ctx context.Context, key string) {
+	if err := s.avatars.Delete(ctx, key); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to delete avatar", zap.String("key", key), zap.Error(err))
+	}
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// memAvatars is a StorageClient kept in memory
+type memAvatars struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	failing error
+}
+
+func (m *memAvatars) Upload(_ context.Context, key, _ string, reader io.Reader) (string, error) {
+	if m.failing != nil {
+		return "", m.failing
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = data
+	return "https://cdn.example.com/" + key, nil
+}
+
+func (m *memAvatars) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+var (
+	pngImage  = append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), make([]byte, 64)...)
+	jpegImage = append([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), make([]byte, 64)...)
+)
+
+func TestUpdateAvatar(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID := uuid.MustParse(f.register(t, "avatar@example.com").User.ID)
+
+	first, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(pngImage))
+	if err != nil {
+		t.Fatalf("UpdateAvatar: %v", err)
+	}
+	prefix := "https://cdn.example.com/avatars/" + userID.String() + "/"
+	if first.AvatarURL == nil || !strings.HasPrefix(*first.AvatarURL, prefix) || !strings.HasSuffix(*first.AvatarURL, ".png") {
+		t.Fatalf("avatar URL = %v, want a .png under %s", first.AvatarURL, prefix)
+	}
+
+	// The type follows the content, and the replaced upload is deleted
+	second, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(jpegImage))
+	if err != nil {
+		t.Fatalf("UpdateAvatar: %v", err)
+	}
+	if !strings.HasSuffix(*second.AvatarURL, ".jpg") {
+		t.Errorf("avatar URL = %s, want a .jpg", *second.AvatarURL)
+	}
+	if len(f.avatars.objects) != 1 {
+		t.Errorf("%d objects stored, want only the current avatar", len(f.avatars.objects))
+	}
+	user, _ := f.users.GetByID(ctx, userID)
+	if user.AvatarKey == nil || f.avatars.objects[*user.AvatarKey] == nil {
+		t.Errorf("avatar key %v does not name the stored avatar", user.AvatarKey)
+	}
+}
+
+func TestUpdateAvatarKeepsExternalAvatars(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID := uuid.MustParse(f.register(t, "google@example.com").User.ID)
+
+	// A Google profile picture has a URL but no key of ours
+	user, _ := f.users.GetByID(ctx, userID)
+	picture := "https://lh3.googleusercontent.com/a/photo"
+	user.AvatarURL = &picture
+	f.users.Update(ctx, user)
+	f.avatars.objects["elsewhere"] = []byte("kept")
+
+	if _, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(pngImage)); err != nil {
+		t.Fatalf("UpdateAvatar: %v", err)
+	}
+	if len(f.avatars.objects) != 2 {
+		t.Errorf("%d objects stored, want nothing deleted", len(f.avatars.objects))
+	}
+}
+
+func TestUpdateAvatarRejected(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID := uuid.MustParse(f.register(t, "reject@example.com").User.ID)
+
+	tests := []struct {
+		name  string
+		image []byte
+		code  apperrors.ErrorCode
+	}{
+		{"GIF", []byte("GIF89a\x01\x00\x01\x00"), apperrors.CodeValidation},
+		{"text named .png", []byte("hello, world"), apperrors.CodeValidation},
+		{"over 5MB", append(bytes.Clone(pngImage), make([]byte, MaxAvatarSize)...), apperrors.CodeValidation},
+	}
+	for _, tt := range tests {
+		if _, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(tt.image)); !apperrors.Is(err, tt.code) {
+			t.Errorf("%s: UpdateAvatar = %v, want %s", tt.name, err, tt.code)
+		}
+	}
+	if len(f.avatars.objects) != 0 {
+		t.Errorf("%d objects stored for rejected images", len(f.avatars.objects))
+	}
+
+	f.avatars.failing = errors.New("bucket unreachable")
+	if _, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(pngImage)); !apperrors.Is(err, apperrors.CodeInternal) {
+		t.Errorf("with the bucket down: %v, want %s", err, apperrors.CodeInternal)
+	}
+	if user, _ := f.users.GetByID(ctx, userID); user.AvatarURL != nil {
+		t.Errorf("avatar URL set to %s by a failed upload", *user.AvatarURL)
+	}
+
+	f.svc.avatars = nil
+	if _, err := f.svc.UpdateAvatar(ctx, userID, bytes.NewReader(pngImage)); !apperrors.Is(err, apperrors.CodeFailedPrecondition) {
+		t.Errorf("without a bucket: %v, want %s", err, apperrors.CodeFailedPrecondition)
+	}
+}
@@ -11,6 +11,8 @@ type RegisterRequest struct {
 	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
 	LastName  string `json:"last_name" validate:"required,min=2,max=50"`
 	Phone     string `json:"phone,omitempty" validate:"omitempty,phone"`
+	// HoldRecoveryEmails turns emails about expired seat holds on or off
+	HoldRecoveryEmails *bool `json:"hold_recovery_emails,omitempty"`
 }
 
 // LoginRequest is the input for user login
@@ -19,11 +21,22 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// GoogleLoginRequest is the input for signing in with Google
+type GoogleLoginRequest struct {
+	IDToken string `json:"id_token" validate:"required"` // from Google Sign-In
+}
+
 // RefreshTokenRequest is the input for token refresh
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+// LogoutOthersRequest is the input for logging out all other sessions. The
+// refresh token is optional when the access token identifies the session.
+type LogoutOthersRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
 // ForgotPasswordRequest is the input for password reset request
 type ForgotPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
@@ -51,36 +64,60 @@ type UpdateProfileRequest struct {
 	FirstName string `json:"first_name,omitempty" validate:"omitempty,min=2,max=50"`
 	LastName  string `json:"last_name,omitempty" validate:"omitempty,min=2,max=50"`
 	Phone     string `json:"phone,omitempty" validate:"omitempty,phone"`
+	// HoldRecoveryEmails turns emails about expired seat holds on or off
+	HoldRecoveryEmails *bool `json:"hold_recovery_emails,omitempty"`
 }
 
 // AuthResponse is the response for successful authentication
 type AuthResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
-	ExpiresIn    int64        `json:"expires_in"` // seconds
-	TokenType    string       `json:"token_type"`
-	User         UserResponse `json:"user"`
+	AccessToken      string       `json:"access_token"`
+	RefreshToken     string       `json:"refresh_token"`
+	ExpiresIn        int64        `json:"expires_in"`         // seconds
+	RefreshExpiresIn int64        `json:"refresh_expires_in"` // seconds
+	TokenType        string       `json:"token_type"`
+	User             UserResponse `json:"user"`
 }
 
 // UserResponse is the user data in responses
 type UserResponse struct {
-	ID            string     `json:"id"`
-	Email         string     `json:"email"`
-	FirstName     string     `json:"first_name"`
-	LastName      string     `json:"last_name"`
-	FullName      string     `json:"full_name"`
-	Phone         *string    `json:"phone,omitempty"`
-	Role          string     `json:"role"`
-	EmailVerified bool       `json:"email_verified"`
-	CreatedAt     time.Time  `json:"created_at"`
-	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
+	ID                 string     `json:"id"`
+	Email              string     `json:"email"`
+	FirstName          string     `json:"first_name"`
+	LastName           string     `json:"last_name"`
+	FullName           string     `json:"full_name"`
+	Phone              *string    `json:"phone,omitempty"`
+	AvatarURL          *string    `json:"avatar_url,omitempty"`
+	Role               string     `json:"role"`
+	EmailVerified      bool       `json:"email_verified"`
+	HoldRecoveryEmails bool       `json:"hold_recovery_emails"`
+	CreatedAt          time.Time  `json:"created_at"`
+	LastLoginAt        *time.Time `json:"last_login_at,omitempty"`
 }
 
 // TokenRefreshResponse is the response for token refresh
 type TokenRefreshResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int64  `json:"expires_in"`
-	TokenType   string `json:"token_type"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"` // replaces the presented token, which is now revoked
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+	TokenType        string `json:"token_type"`
+}
+
+// LogoutOthersResponse is the response for logging out other sessions
+type LogoutOthersResponse struct {
+	RevokedSessions int64 `json:"revoked_sessions"`
+}
+
+// SessionResponse is a signed-in session, one per refresh token
+type SessionResponse struct {
+	ID        string     `json:"id"`
+	Current   bool       `json:"current"`
+	Revoked   bool       `json:"revoked"` // logged out
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	UserAgent *string    `json:"user_agent,omitempty"`
+	IPAddress *string    `json:"ip_address,omitempty"`
+	CreatedAt time.Time  `json:"created_at"` // signed in
+	ExpiresAt time.Time  `json:"expires_at"`
 }
 
 // MessageResponse is a simple message response
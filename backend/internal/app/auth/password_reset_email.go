@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/pkg/mailer"
+
+	"go.uber.org/zap"
+)
+
+// sendPasswordReset queues the email with the reset link for the raw
+// token. Delivery happens in the background; a failure to queue is only
+// logged so the response does not reveal whether the account exists.
+func (s *Service) sendPasswordReset(ctx context.Context, user *entity.User, token string) {
+	if err := s.emails.SendPasswordReset(ctx, mailer.PasswordReset{
+		To:     user.Email,
+		Name:   user.FirstName,
+		Token:  token,
+		Expiry: s.jwtManager.GetResetTokenExpiry(),
+	}); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to queue password reset email",
+			zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+}
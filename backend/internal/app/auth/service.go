@@ -2,6 +2,10 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"cinemaos-backend/internal/app/entity"
@@ -9,20 +13,42 @@ import (
 	"cinemaos-backend/internal/app/authinfra"
 	apperrors "cinemaos-backend/internal/pkg/errors"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+	"cinemaos-backend/internal/pkg/oauth"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// GoogleVerifier verifies the ID tokens of Google Sign-In
+type GoogleVerifier interface {
+	Verify(ctx context.Context, idToken string) (*oauth.GoogleProfile, error)
+}
+
+// StorageClient stores uploaded files
+type StorageClient interface {
+	// Upload stores the content under key and returns the URL it is served at
+	Upload(ctx context.Context, key, contentType string, reader io.Reader) (url string, err error)
+
+	// Delete removes the file stored under key
+	Delete(ctx context.Context, key string) error
+}
+
 // Service handles authentication business logic
 type Service struct {
 	userRepo       repository.UserRepository
 	refreshRepo    repository.RefreshTokenRepository
 	resetTokenRepo repository.PasswordResetTokenRepository
+	verifyRepo     repository.EmailVerificationTokenRepository
+	bookingRepo    repository.BookingRepository
 	jwtManager     *authinfra.JWTManager
 	passwordMgr    *authinfra.PasswordManager
+	google         GoogleVerifier // nil when Google sign-in is off
+	avatars        StorageClient  // nil when avatar uploads are off
+	emails         mailer.EmailClient
 	logger         *logger.Logger
-	frontendURL    string
+	// keepSession ends only the other sessions on a password change
+	keepSession bool
 }
 
 // NewService creates a new auth service
@@ -30,19 +56,29 @@ func NewService(
 	userRepo repository.UserRepository,
 	refreshRepo repository.RefreshTokenRepository,
 	resetTokenRepo repository.PasswordResetTokenRepository,
+	verifyRepo repository.EmailVerificationTokenRepository,
+	bookingRepo repository.BookingRepository,
 	jwtManager *authinfra.JWTManager,
 	passwordMgr *authinfra.PasswordManager,
+	google GoogleVerifier,
+	avatars StorageClient,
+	emails mailer.EmailClient,
 	logger *logger.Logger,
-	frontendURL string,
+	keepSessionOnPasswordChange bool,
 ) *Service {
 	return &Service{
 		userRepo:       userRepo,
 		refreshRepo:    refreshRepo,
 		resetTokenRepo: resetTokenRepo,
+		verifyRepo:     verifyRepo,
+		bookingRepo:    bookingRepo,
 		jwtManager:     jwtManager,
 		passwordMgr:    passwordMgr,
+		google:         google,
+		avatars:        avatars,
+		emails:         emails,
 		logger:         logger,
-		frontendURL:    frontendURL,
+		keepSession:    keepSessionOnPasswordChange,
 	}
 }
 
@@ -89,11 +125,37 @@ func (s *Service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 	}
 
 	log.Info("user registered successfully")
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Warn("failed to send verification email", zap.Error(err))
+	}
+	s.claimGuestBookings(ctx, user)
 
 	// Generate tokens
 	return s.generateAuthResponse(ctx, user)
 }
 
+// claimGuestBookings attaches the guest bookings made with the user's email
+// to the account. Only a verified email proves the bookings are the user's,
+// so nothing is claimed before verification; a failure is logged and does
+// not fail the sign-in.
+func (s *Service) claimGuestBookings(ctx context.Context, user *entity.User) {
+	if !user.EmailVerified {
+		return
+	}
+
+	claimed, err := s.bookingRepo.ClaimGuestBookings(ctx, user.ID, user.Email)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("failed to claim guest bookings", zap.Error(err))
+		return
+	}
+	if claimed > 0 {
+		s.logger.WithContext(ctx).Info("guest bookings claimed",
+			zap.String("user_id", user.ID.String()),
+			zap.Int64("bookings", claimed),
+		)
+	}
+}
+
 // Login authenticates a user
 func (s *Service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, error) {
 	log := s.logger.WithContext(ctx)
@@ -120,15 +182,133 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, e
 
 	// Update last login
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		log.Warn("failed to update last login")
+		log.Warn("failed to update last login", zap.Error(err))
 	}
 
 	log.Info("user logged in successfully")
+	s.claimGuestBookings(ctx, user)
 
 	// Generate tokens
 	return s.generateAuthResponse(ctx, user)
 }
 
+// LoginWithGoogle signs a user in with a Google ID token. The Google
+// account is matched by its ID, then by its verified email, which links the
+// account to an existing user; otherwise a user is created with a random
+// password, so they can only sign in with Google until they reset it. An
+// existing user whose email was never verified loses their password and
+// sessions when the account is linked.
+func (s *Service) LoginWithGoogle(ctx context.Context, idToken string) (*AuthResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	if s.google == nil {
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition, "Google sign-in is not enabled")
+	}
+
+	profile, err := s.google.Verify(ctx, idToken)
+	if err != nil {
+		if errors.Is(err, oauth.ErrInvalidToken) {
+			return nil, apperrors.ErrTokenInvalid()
+		}
+		log.Error("failed to verify Google ID token", zap.Error(err))
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to verify Google ID token")
+	}
+	// An unverified email could belong to someone else's account
+	if !profile.EmailVerified {
+		return nil, apperrors.New(apperrors.CodeEmailNotVerified, "Google account email is not verified")
+	}
+
+	user, err := s.userRepo.GetByGoogleID(ctx, profile.Subject)
+	if apperrors.Is(err, apperrors.CodeUserNotFound) {
+		user, err = s.userRepo.GetByEmail(ctx, profile.Email)
+		switch {
+		case err == nil:
+			if !user.EmailVerified {
+				// Whoever registered the email never proved it was theirs, and
+				// Google says it belongs to this person. Their password and
+				// sessions must not outlive the link, or a pre-registered
+				// account would let them into the owner's account.
+				if err := s.takeOverUnverified(ctx, user); err != nil {
+					return nil, err
+				}
+				log.Warn("unverified account taken over by its Google owner", zap.String("user_id", user.ID.String()))
+			}
+			user.GoogleID = &profile.Subject
+			user.EmailVerified = true
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return nil, err
+			}
+			log.Info("Google account linked", zap.String("user_id", user.ID.String()))
+		case apperrors.Is(err, apperrors.CodeUserNotFound):
+			user, err = s.createGoogleUser(ctx, profile)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, apperrors.ErrAccountDisabled()
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		log.Warn("failed to update last login", zap.Error(err))
+	}
+
+	log.Info("user logged in with Google")
+	s.claimGuestBookings(ctx, user)
+
+	return s.generateAuthResponse(ctx, user)
+}
+
+// takeOverUnverified ends the sessions of an account whose email was never
+// verified and replaces its password with a random one, before the account
+// is linked to the Google identity that owns the email
+func (s *Service) takeOverUnverified(ctx context.Context, user *entity.User) error {
+	if err := s.refreshRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	passwordHash, err := s.passwordMgr.HashPassword(uuid.New().String())
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = passwordHash
+	return nil
+}
+
+// createGoogleUser registers a verified Google account as a customer
+func (s *Service) createGoogleUser(ctx context.Context, profile *oauth.GoogleProfile) (*entity.User, error) {
+	passwordHash, err := s.passwordMgr.HashPassword(uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, lastName := profile.GivenName, profile.FamilyName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(profile.Email, "@")
+	}
+
+	user := &entity.User{
+		Email:         profile.Email,
+		PasswordHash:  passwordHash,
+		FirstName:     firstName,
+		LastName:      lastName,
+		Role:          entity.RoleCustomer,
+		EmailVerified: true,
+		IsActive:      true,
+		GoogleID:      &profile.Subject,
+	}
+	if profile.Picture != "" {
+		user.AvatarURL = &profile.Picture
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("user registered with Google", zap.String("user_id", user.ID.String()))
+	return user, nil
+}
+
 // RefreshToken refreshes an access token
 func (s *Service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*TokenRefreshResponse, error) {
 	log := s.logger.WithContext(ctx)
@@ -146,6 +326,12 @@ func (s *Service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*T
 		return nil, err
 	}
 
+	// A token whose family already moved past it was copied; either use
+	// may be the attacker's, so every session ends
+	if storedToken.Revoked && storedToken.ReplacedByID != nil {
+		return nil, s.revokeOnReuse(ctx, storedToken)
+	}
+
 	// Verify token is valid
 	if !storedToken.IsValid() {
 		return nil, apperrors.ErrTokenExpired()
@@ -162,20 +348,62 @@ func (s *Service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*T
 		return nil, apperrors.ErrAccountDisabled()
 	}
 
-	// Generate new access token
-	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, string(user.Role))
+	// Each refresh token is used once; its successor starts a new session
+	// row on the same device
+	sessionID := uuid.New()
+	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, sessionID, user.Email, string(user.Role))
 	if err != nil {
 		log.Error("failed to generate access token", zap.Error(err))
 		return nil, apperrors.ErrInternal("failed to generate token")
 	}
+	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, sessionID, user.Email, string(user.Role))
+	if err != nil {
+		log.Error("failed to generate refresh token", zap.Error(err))
+		return nil, apperrors.ErrInternal("failed to generate token")
+	}
+
+	next := &entity.RefreshToken{
+		ID:              sessionID,
+		UserID:          user.ID,
+		TokenHash:       authinfra.HashToken(refreshToken),
+		ExpiresAt:       time.Now().Add(s.jwtManager.GetRefreshTokenExpiry()),
+		UserAgent:       storedToken.UserAgent,
+		IPAddress:       storedToken.IPAddress,
+		FamilyID:        storedToken.FamilyID,
+		ParentTokenHash: &storedToken.TokenHash,
+	}
+	rotated, err := s.refreshRepo.Rotate(ctx, storedToken.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another refresh with the same token won the race
+		return nil, s.revokeOnReuse(ctx, storedToken)
+	}
 
 	return &TokenRefreshResponse{
-		AccessToken: accessToken,
-		ExpiresIn:   int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
-		TokenType:   "Bearer",
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
+		RefreshExpiresIn: int64(s.jwtManager.GetRefreshTokenExpiry().Seconds()),
+		TokenType:        "Bearer",
 	}, nil
 }
 
+// revokeOnReuse ends all of a user's sessions after a refresh token was used
+// twice, since either use may be an attacker's
+func (s *Service) revokeOnReuse(ctx context.Context, token *entity.RefreshToken) error {
+	s.logger.WithContext(ctx).Warn("refresh token reused, revoking all sessions",
+		zap.String("user_id", token.UserID.String()),
+		zap.String("token_id", token.ID.String()),
+		zap.String("family_id", token.FamilyID.String()),
+	)
+	if err := s.refreshRepo.RevokeAllForUser(ctx, token.UserID); err != nil {
+		return err
+	}
+	return apperrors.ErrTokenInvalid()
+}
+
 // Logout revokes a refresh token
 func (s *Service) Logout(ctx context.Context, refreshToken string) error {
 	tokenHash := authinfra.HashToken(refreshToken)
@@ -194,6 +422,84 @@ func (s *Service) LogoutAll(ctx context.Context, userID uuid.UUID) error {
 	return s.refreshRepo.RevokeAllForUser(ctx, userID)
 }
 
+// LogoutOthers revokes all of a user's sessions except the current one,
+// identified by the presented refresh token or else by the session of the
+// access token
+func (s *Service) LogoutOthers(ctx context.Context, userID, sessionID uuid.UUID, req LogoutOthersRequest) (*LogoutOthersResponse, error) {
+	current, err := s.currentSession(ctx, userID, sessionID, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.refreshRepo.RevokeAllForUserExcept(ctx, userID, current)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("other sessions logged out", zap.Int64("revoked_sessions", revoked))
+	return &LogoutOthersResponse{RevokedSessions: revoked}, nil
+}
+
+// ListSessions returns a user's unexpired sessions, newest first, marking
+// the current one and those that were logged out. A session is a login: its
+// refresh tokens share a family, and only the latest one, which no refresh
+// replaced, stands for it.
+func (s *Service) ListSessions(ctx context.Context, userID, sessionID uuid.UUID) ([]SessionResponse, error) {
+	tokens, err := s.refreshRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+
+	// The session started with the first token of its family
+	started := make(map[uuid.UUID]time.Time, len(tokens))
+	for _, token := range tokens {
+		started[token.FamilyID] = token.CreatedAt
+	}
+
+	sessions := make([]SessionResponse, 0, len(tokens))
+	for _, token := range tokens {
+		if token.ReplacedByID != nil || token.IsExpired() {
+			continue
+		}
+		session := toSessionResponse(token, token.ID == sessionID)
+		session.CreatedAt = started[token.FamilyID]
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// currentSession returns the ID of the caller's session. A refresh token
+// must be the user's and still valid; a session from the access token must
+// not have been logged out.
+func (s *Service) currentSession(ctx context.Context, userID, sessionID uuid.UUID, refreshToken string) (uuid.UUID, error) {
+	if refreshToken != "" {
+		stored, err := s.refreshRepo.GetByTokenHash(ctx, authinfra.HashToken(refreshToken))
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if stored.UserID != userID || !stored.IsValid() {
+			return uuid.Nil, apperrors.ErrTokenInvalid()
+		}
+		return stored.ID, nil
+	}
+
+	if sessionID == uuid.Nil {
+		return uuid.Nil, apperrors.ErrBadRequest("refresh_token is required to identify the current session")
+	}
+
+	tokens, err := s.refreshRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, token := range tokens {
+		if token.ID == sessionID && token.IsValid() {
+			return sessionID, nil
+		}
+	}
+	return uuid.Nil, apperrors.ErrTokenExpired()
+}
+
 // ForgotPassword initiates password reset
 func (s *Service) ForgotPassword(ctx context.Context, req ForgotPasswordRequest) error {
 	log := s.logger.WithContext(ctx)
@@ -233,9 +539,7 @@ func (s *Service) ForgotPassword(ctx context.Context, req ForgotPasswordRequest)
 		return err
 	}
 
-	// TODO: Send email with reset link
-	// resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, token)
-	// emailService.SendPasswordResetEmail(user.Email, user.FirstName, resetLink)
+	s.sendPasswordReset(ctx, user, token)
 
 	log.Info("password reset token generated")
 	return nil
@@ -283,8 +587,10 @@ func (s *Service) ResetPassword(ctx context.Context, req ResetPasswordRequest) e
 	return nil
 }
 
-// ChangePassword changes password for authenticated user
-func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, req ChangePasswordRequest) error {
+// ChangePassword changes password for authenticated user. Other sessions
+// are logged out; the current one, identified by sessionID, stays signed in
+// if so configured and known.
+func (s *Service) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, req ChangePasswordRequest) error {
 	log := s.logger.WithContext(ctx)
 
 	// Get user
@@ -310,8 +616,12 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, req Chan
 		return err
 	}
 
-	// Revoke all refresh tokens for security
-	if err := s.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
+	// Revoke refresh tokens for security
+	if s.keepSession && sessionID != uuid.Nil {
+		if _, err := s.refreshRepo.RevokeAllForUserExcept(ctx, userID, sessionID); err != nil {
+			log.Warn("failed to revoke refresh tokens")
+		}
+	} else if err := s.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
 		log.Warn("failed to revoke refresh tokens")
 	}
 
@@ -347,6 +657,9 @@ func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, req Updat
 	if req.Phone != "" {
 		user.Phone = &req.Phone
 	}
+	if req.HoldRecoveryEmails != nil {
+		user.HoldRecoveryEmails = *req.HoldRecoveryEmails
+	}
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		log.Error("failed to update user", zap.Error(err))
@@ -359,23 +672,29 @@ func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, req Updat
 
 // generateAuthResponse generates auth response with tokens
 func (s *Service) generateAuthResponse(ctx context.Context, user *entity.User) (*AuthResponse, error) {
+	// The refresh token's row ID identifies the session in both tokens
+	sessionID := uuid.New()
+
 	// Generate access token
-	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, string(user.Role))
+	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, sessionID, user.Email, string(user.Role))
 	if err != nil {
 		return nil, apperrors.ErrInternal("failed to generate access token")
 	}
 
 	// Generate refresh token
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, user.Email, string(user.Role))
+	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, sessionID, user.Email, string(user.Role))
 	if err != nil {
 		return nil, apperrors.ErrInternal("failed to generate refresh token")
 	}
 
 	// Store refresh token
+	// A login starts a new token family
 	tokenEntity := &entity.RefreshToken{
+		ID:        sessionID,
 		UserID:    user.ID,
 		TokenHash: authinfra.HashToken(refreshToken),
 		ExpiresAt: time.Now().Add(s.jwtManager.GetRefreshTokenExpiry()),
+		FamilyID:  sessionID,
 	}
 
 	if err := s.refreshRepo.Create(ctx, tokenEntity); err != nil {
@@ -383,26 +702,43 @@ func (s *Service) generateAuthResponse(ctx context.Context, user *entity.User) (
 	}
 
 	return &AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
-		TokenType:    "Bearer",
-		User:         *toUserResponse(user),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
+		RefreshExpiresIn: int64(s.jwtManager.GetRefreshTokenExpiry().Seconds()),
+		TokenType:        "Bearer",
+		User:             *toUserResponse(user),
 	}, nil
 }
 
+// toSessionResponse converts a refresh token to a session response
+func toSessionResponse(token *entity.RefreshToken, current bool) SessionResponse {
+	return SessionResponse{
+		ID:        token.ID.String(),
+		Current:   current,
+		Revoked:   token.Revoked,
+		RevokedAt: token.RevokedAt,
+		UserAgent: token.UserAgent,
+		IPAddress: token.IPAddress,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}
+}
+
 // toUserResponse converts entity to response DTO
 func toUserResponse(user *entity.User) *UserResponse {
 	return &UserResponse{
-		ID:            user.ID.String(),
-		Email:         user.Email,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		FullName:      user.FullName(),
-		Phone:         user.Phone,
-		Role:          string(user.Role),
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
-		LastLoginAt:   user.LastLoginAt,
+		ID:                 user.ID.String(),
+		Email:              user.Email,
+		FirstName:          user.FirstName,
+		LastName:           user.LastName,
+		FullName:           user.FullName(),
+		Phone:              user.Phone,
+		AvatarURL:          user.AvatarURL,
+		Role:               string(user.Role),
+		EmailVerified:      user.EmailVerified,
+		HoldRecoveryEmails: user.HoldRecoveryEmails,
+		CreatedAt:          user.CreatedAt,
+		LastLoginAt:        user.LastLoginAt,
 	}
 }
@@ -0,0 +1,1098 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+	"cinemaos-backend/internal/pkg/oauth"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memUsers is a UserRepository kept in memory
+type memUsers struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*entity.User
+	// raceEmail makes Create lose a concurrent registration for the address
+	raceEmail string
+}
+
+func (m *memUsers) Create(_ context.Context, user *entity.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if user.Email == m.raceEmail {
+		return apperrors.ErrEmailExists()
+	}
+	user.ID = uuid.New()
+	copied := *user
+	m.users[user.ID] = &copied
+	return nil
+}
+
+func (m *memUsers) EmailExists(_ context.Context, email string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memUsers) GetByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, apperrors.New(apperrors.CodeUserNotFound, "user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (m *memUsers) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.New(apperrors.CodeUserNotFound, "user not found")
+}
+
+func (m *memUsers) GetByGoogleID(_ context.Context, googleID string) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.GoogleID != nil && *user.GoogleID == googleID {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.New(apperrors.CodeUserNotFound, "user not found")
+}
+
+func (m *memUsers) Update(_ context.Context, user *entity.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *user
+	m.users[user.ID] = &copied
+	return nil
+}
+
+func (m *memUsers) UpdateLastLogin(context.Context, uuid.UUID) error { return nil }
+
+func (m *memUsers) UpdatePassword(_ context.Context, id uuid.UUID, passwordHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[id].PasswordHash = passwordHash
+	return nil
+}
+
+// memRefreshTokens is a RefreshTokenRepository kept in memory
+type memRefreshTokens struct {
+	repository.RefreshTokenRepository
+
+	mu     sync.Mutex
+	tokens map[uuid.UUID]*entity.RefreshToken
+}
+
+func (m *memRefreshTokens) Create(_ context.Context, token *entity.RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	token.CreatedAt = time.Now()
+	copied := *token
+	m.tokens[token.ID] = &copied
+	return nil
+}
+
+func (m *memRefreshTokens) GetByTokenHash(_ context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrTokenInvalid()
+}
+
+func (m *memRefreshTokens) GetByUserID(_ context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error) {
+	var tokens []*entity.RefreshToken
+	for _, token := range m.all() {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *memRefreshTokens) Revoke(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.tokens[id].Revoked = true
+	m.tokens[id].RevokedAt = &now
+	return nil
+}
+
+func (m *memRefreshTokens) RevokeAllForUser(_ context.Context, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.UserID == userID && !token.Revoked {
+			token.Revoked = true
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memRefreshTokens) RevokeAllForUserExcept(_ context.Context, userID, keepID uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var revoked int64
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.ID != keepID && !token.Revoked {
+			token.Revoked = true
+			token.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (m *memRefreshTokens) Rotate(_ context.Context, id uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token := m.tokens[id]
+	if token.Revoked {
+		return false, nil
+	}
+	now := time.Now()
+	token.Revoked = true
+	token.RevokedAt = &now
+	token.ReplacedByID = &next.ID
+	next.CreatedAt = now
+	copied := *next
+	m.tokens[next.ID] = &copied
+	return true, nil
+}
+
+func (m *memRefreshTokens) all() []*entity.RefreshToken {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var tokens []*entity.RefreshToken
+	for _, token := range m.tokens {
+		copied := *token
+		tokens = append(tokens, &copied)
+	}
+	return tokens
+}
+
+// memVerifyTokens is an EmailVerificationTokenRepository kept in memory
+type memVerifyTokens struct {
+	repository.EmailVerificationTokenRepository
+
+	users  *memUsers
+	tokens []*entity.EmailVerificationToken
+}
+
+func (m *memVerifyTokens) Create(_ context.Context, token *entity.EmailVerificationToken) error {
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memVerifyTokens) GetByTokenHash(_ context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrTokenInvalid()
+}
+
+func (m *memVerifyTokens) GetLatestByUserID(_ context.Context, userID uuid.UUID) (*entity.EmailVerificationToken, error) {
+	for i := len(m.tokens) - 1; i >= 0; i-- {
+		if m.tokens[i].UserID == userID && !m.tokens[i].Used {
+			return m.tokens[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memVerifyTokens) Redeem(_ context.Context, id uuid.UUID) error {
+	for _, token := range m.tokens {
+		if token.ID == id && token.IsValid() {
+			now := time.Now()
+			token.Used = true
+			token.UsedAt = &now
+			m.users.users[token.UserID].EmailVerified = true
+			return nil
+		}
+	}
+	return apperrors.ErrTokenExpired()
+}
+
+func (m *memVerifyTokens) InvalidateAllForUser(_ context.Context, userID uuid.UUID) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			token.Used = true
+		}
+	}
+	return nil
+}
+
+// memResetTokens is a PasswordResetTokenRepository kept in memory
+type memResetTokens struct {
+	repository.PasswordResetTokenRepository
+	tokens []*entity.PasswordResetToken
+}
+
+func (m *memResetTokens) Create(_ context.Context, token *entity.PasswordResetToken) error {
+	token.ID = uuid.New()
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memResetTokens) GetByTokenHash(_ context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, apperrors.ErrTokenInvalid()
+}
+
+func (m *memResetTokens) MarkUsed(_ context.Context, id uuid.UUID) error {
+	for _, token := range m.tokens {
+		if token.ID == id {
+			token.Used = true
+		}
+	}
+	return nil
+}
+
+func (m *memResetTokens) InvalidateAllForUser(_ context.Context, userID uuid.UUID) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			token.Used = true
+		}
+	}
+	return nil
+}
+
+// fakeSender hands the emails it is given to the test
+type fakeSender struct {
+	sent chan async.EmailPayload
+	err  error
+}
+
+func (f *fakeSender) Send(_ context.Context, email async.EmailPayload) error {
+	f.sent <- email
+	return f.err
+}
+
+// withSender starts a dispatcher delivering the fixture's emails to a fake
+// sender
+func (f *authFixture) withSender(t *testing.T, err error) *fakeSender {
+	t.Helper()
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 10), err: err}
+	dispatcher := async.NewDispatcher(1, 10, sender, &logger.Logger{Logger: zap.NewNop()})
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(time.Second) })
+	f.svc.emails = mailer.NewClient(dispatcher, "https://cinema.example.com")
+	return sender
+}
+
+// memGuestBookings holds bookings for the guest booking claim at sign-in
+type memGuestBookings struct {
+	repository.BookingRepository
+	bookings []*entity.Booking
+}
+
+func (m *memGuestBookings) ClaimGuestBookings(_ context.Context, userID uuid.UUID, email string) (int64, error) {
+	var claimed int64
+	for _, booking := range m.bookings {
+		if booking.UserID == nil && booking.GuestEmail != "" && strings.EqualFold(booking.GuestEmail, email) {
+			booking.UserID = &userID
+			claimed++
+		}
+	}
+	return claimed, nil
+}
+
+// fakeGoogle accepts the ID tokens it has profiles for
+type fakeGoogle struct {
+	profiles map[string]*oauth.GoogleProfile
+}
+
+func (g *fakeGoogle) Verify(_ context.Context, idToken string) (*oauth.GoogleProfile, error) {
+	profile, ok := g.profiles[idToken]
+	if !ok {
+		return nil, oauth.ErrInvalidToken
+	}
+	copied := *profile
+	return &copied, nil
+}
+
+type authFixture struct {
+	svc      *Service
+	jwt      *authinfra.JWTManager
+	users    *memUsers
+	tokens   *memRefreshTokens
+	verify   *memVerifyTokens
+	resets   *memResetTokens
+	bookings *memGuestBookings
+	google   *fakeGoogle
+	avatars  *memAvatars
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	f := &authFixture{
+		users:    &memUsers{users: make(map[uuid.UUID]*entity.User)},
+		tokens:   &memRefreshTokens{tokens: make(map[uuid.UUID]*entity.RefreshToken)},
+		resets:   &memResetTokens{},
+		bookings: &memGuestBookings{},
+		google:   &fakeGoogle{profiles: make(map[string]*oauth.GoogleProfile)},
+		avatars:  &memAvatars{objects: make(map[string][]byte)},
+	}
+	f.verify = &memVerifyTokens{users: f.users}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	f.jwt = authinfra.NewJWTManager(config.JWTConfig{
+		AccessSecret:       "access-secret",
+		RefreshSecret:      "refresh-secret",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 72 * time.Hour,
+		ResetTokenExpiry:   time.Hour,
+		VerifyTokenExpiry:  24 * time.Hour,
+		Issuer:             "cinemaos-test",
+	})
+	// The dispatcher is not started: emails are not sent, and the tests
+	// stand in the tokens they would carry
+	f.svc = NewService(f.users, f.tokens, f.resets, f.verify, f.bookings, f.jwt, authinfra.NewPasswordManager(),
+		f.google, f.avatars, mailer.NewClient(async.NewDispatcher(1, 10, nil, log), "https://cinema.example.com"), log, true)
+	return f
+}
+
+func (f *authFixture) register(t *testing.T, email string) *AuthResponse {
+	t.Helper()
+	res, err := f.svc.Register(context.Background(), RegisterRequest{
+		Email: email, Password: "correct horse battery", FirstName: "Test", LastName: "User",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return res
+}
+
+// sqlDigest is what the plaintext-token migration stores:
+// encode(sha256(convert_to(token, 'UTF8')), 'hex')
+func sqlDigest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRefreshTokensAreStoredHashed(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	res := f.register(t, "user@example.com")
+
+	if res.ExpiresIn != 900 || res.RefreshExpiresIn != 72*3600 {
+		t.Errorf("expires_in = %d, refresh_expires_in = %d, want the configured expiries", res.ExpiresIn, res.RefreshExpiresIn)
+	}
+
+	stored := f.tokens.all()
+	if len(stored) != 1 {
+		t.Fatalf("stored %d refresh tokens, want 1", len(stored))
+	}
+	if stored[0].TokenHash == res.RefreshToken || stored[0].TokenHash != sqlDigest(res.RefreshToken) {
+		t.Fatalf("stored %q, want the SHA-256 of the token", stored[0].TokenHash)
+	}
+
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if err := f.svc.Logout(ctx, res.RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err == nil {
+		t.Error("a revoked token was refreshed")
+	}
+}
+
+func TestPlaintextTokenRowsAfterMigration(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	res := f.register(t, "legacy@example.com")
+
+	// A row written by a path that stored the raw token
+	legacy := f.tokens.all()[0]
+	f.tokens.tokens[legacy.ID].TokenHash = res.RefreshToken
+
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err == nil {
+		t.Fatal("a plaintext row matched before the migration")
+	}
+
+	// The migration's UPDATE, row by row
+	hashed := regexp.MustCompile(`^[0-9a-f]{64}$`)
+	for _, token := range f.tokens.tokens {
+		if !hashed.MatchString(token.TokenHash) {
+			token.TokenHash = sqlDigest(token.TokenHash)
+		}
+	}
+
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err != nil {
+		t.Fatalf("RefreshToken after the migration: %v", err)
+	}
+	if migrated := f.tokens.tokens[legacy.ID]; !migrated.Revoked || migrated.ReplacedByID == nil {
+		t.Error("the refresh did not rotate the migrated row")
+	}
+}
+
+func TestRegisterLosingARaceReturnsEmailExists(t *testing.T) {
+	f := newAuthFixture(t)
+	f.users.raceEmail = "race@example.com"
+
+	_, err := f.svc.Register(context.Background(), RegisterRequest{
+		Email: "race@example.com", Password: "correct horse battery", FirstName: "Test", LastName: "User",
+	})
+	if !apperrors.Is(err, apperrors.CodeEmailAlreadyExists) {
+		t.Fatalf("Register = %v, want EMAIL_EXISTS", err)
+	}
+	if len(f.tokens.all()) != 0 {
+		t.Error("tokens issued for a registration that lost the race")
+	}
+}
+
+func TestVerifiedSignInClaimsGuestBookings(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	other := uuid.New()
+	guest := &entity.Booking{ID: uuid.New(), GuestEmail: "Fan@Example.com"}
+	stranger := &entity.Booking{ID: uuid.New(), GuestEmail: "someone@example.com"}
+	taken := &entity.Booking{ID: uuid.New(), GuestEmail: "fan@example.com", UserID: &other}
+	f.bookings.bookings = []*entity.Booking{guest, stranger, taken}
+
+	// Registering someone else's address claims nothing
+	res := f.register(t, "fan@example.com")
+	if guest.UserID != nil {
+		t.Fatal("a booking was claimed by an unverified registration")
+	}
+	login := func() {
+		t.Helper()
+		if _, err := f.svc.Login(ctx, LoginRequest{Email: "fan@example.com", Password: "correct horse battery"}); err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+	}
+	login()
+	if guest.UserID != nil {
+		t.Fatal("a booking was claimed at sign-in before the email was verified")
+	}
+
+	userID := uuid.MustParse(res.User.ID)
+	f.users.users[userID].EmailVerified = true
+	login()
+	if guest.UserID == nil || *guest.UserID != userID {
+		t.Fatalf("guest booking user = %v, want %s", guest.UserID, userID)
+	}
+	if guest.GuestEmail != "Fan@Example.com" {
+		t.Error("the guest fields were not kept")
+	}
+	if stranger.UserID != nil || *taken.UserID != other {
+		t.Error("a booking of another email or account was claimed")
+	}
+}
+
+// signIn starts three sessions for one user, the first by registering
+func (f *authFixture) signIn(t *testing.T, email string) (uuid.UUID, []*AuthResponse) {
+	t.Helper()
+	first := f.register(t, email)
+	sessions := []*AuthResponse{first}
+	for range 2 {
+		res, err := f.svc.Login(context.Background(), LoginRequest{Email: email, Password: "correct horse battery"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		sessions = append(sessions, res)
+	}
+	return uuid.MustParse(first.User.ID), sessions
+}
+
+// sessionOf returns the session ID the access token carries
+func (f *authFixture) sessionOf(t *testing.T, res *AuthResponse) uuid.UUID {
+	t.Helper()
+	claims, err := f.jwt.ValidateAccessToken(res.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		t.Fatalf("sid %q: %v", claims.SessionID, err)
+	}
+	return sessionID
+}
+
+func TestLogoutOthers(t *testing.T) {
+	tests := []struct {
+		name string
+		// byRefreshToken identifies the session by the refresh token in the
+		// body rather than by the access token's sid
+		byRefreshToken bool
+	}{
+		{"by refresh token", true},
+		{"by access token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newAuthFixture(t)
+			userID, sessions := f.signIn(t, "fan@example.com")
+			caller := sessions[1]
+
+			var req LogoutOthersRequest
+			if tt.byRefreshToken {
+				req.RefreshToken = caller.RefreshToken
+			}
+			res, err := f.svc.LogoutOthers(ctx, userID, f.sessionOf(t, caller), req)
+			if err != nil {
+				t.Fatalf("LogoutOthers: %v", err)
+			}
+			if res.RevokedSessions != 2 {
+				t.Errorf("revoked %d sessions, want 2", res.RevokedSessions)
+			}
+
+			listed, err := f.svc.ListSessions(ctx, userID, f.sessionOf(t, caller))
+			if err != nil {
+				t.Fatalf("ListSessions: %v", err)
+			}
+			if len(listed) != 3 {
+				t.Fatalf("listed %d sessions, want 3", len(listed))
+			}
+			for _, session := range listed {
+				current := session.ID == f.sessionOf(t, caller).String()
+				if session.Current != current || session.Revoked == current {
+					t.Errorf("session %s: current %v, revoked %v", session.ID, session.Current, session.Revoked)
+				}
+			}
+
+			if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: caller.RefreshToken}); err != nil {
+				t.Errorf("the caller's refresh token was rejected: %v", err)
+			}
+			for _, i := range []int{0, 2} {
+				if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: sessions[i].RefreshToken}); err == nil {
+					t.Errorf("session %d can still refresh", i)
+				}
+			}
+		})
+	}
+}
+
+func TestListSessionsShowsEachLoginOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID, sessions := f.signIn(t, "fan@example.com")
+
+	// The first device refreshes twice: its rotated tokens are not
+	// sessions of their own, and were not logged out
+	refreshToken := sessions[0].RefreshToken
+	var latest *TokenRefreshResponse
+	for range 2 {
+		res, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: refreshToken})
+		if err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		latest, refreshToken = res, res.RefreshToken
+	}
+	if err := f.svc.Logout(ctx, sessions[2].RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	current := f.sessionOf(t, &AuthResponse{AccessToken: latest.AccessToken})
+	listed, err := f.svc.ListSessions(ctx, userID, current)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(listed) != 3 {
+		t.Fatalf("listed %d sessions, want one per login", len(listed))
+	}
+	loggedOut := f.sessionOf(t, sessions[2]).String()
+	for _, session := range listed {
+		if session.Current != (session.ID == current.String()) || session.Revoked != (session.ID == loggedOut) {
+			t.Errorf("session %s: current %v, revoked %v", session.ID, session.Current, session.Revoked)
+		}
+		if session.Current && !session.CreatedAt.Equal(f.tokens.tokens[f.sessionOf(t, sessions[0])].CreatedAt) {
+			t.Errorf("refreshed session created at %v, want the sign-in time", session.CreatedAt)
+		}
+	}
+}
+
+func TestLogoutOthersRejectsAForeignOrEndedSession(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID, sessions := f.signIn(t, "fan@example.com")
+	otherID, others := f.signIn(t, "other@example.com")
+
+	// Another user's refresh token does not identify the caller's session
+	_, err := f.svc.LogoutOthers(ctx, userID, uuid.Nil, LogoutOthersRequest{RefreshToken: others[0].RefreshToken})
+	if !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Errorf("foreign refresh token: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+
+	// Neither token identifies a session
+	_, err = f.svc.LogoutOthers(ctx, userID, uuid.Nil, LogoutOthersRequest{})
+	if !apperrors.Is(err, apperrors.CodeBadRequest) {
+		t.Errorf("no session: %v, want %s", err, apperrors.CodeBadRequest)
+	}
+
+	// A session that was logged out cannot end the others
+	if err := f.svc.Logout(ctx, sessions[0].RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	_, err = f.svc.LogoutOthers(ctx, userID, f.sessionOf(t, sessions[0]), LogoutOthersRequest{})
+	if !apperrors.Is(err, apperrors.CodeTokenExpired) {
+		t.Errorf("ended session: %v, want %s", err, apperrors.CodeTokenExpired)
+	}
+	for _, token := range f.tokens.all() {
+		if token.Revoked && token.UserID == otherID {
+			t.Error("the other user's sessions were touched")
+		}
+	}
+}
+
+func TestChangePasswordKeepsTheCurrentSession(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID, sessions := f.signIn(t, "fan@example.com")
+
+	err := f.svc.ChangePassword(ctx, userID, f.sessionOf(t, sessions[2]), ChangePasswordRequest{
+		CurrentPassword: "correct horse battery", NewPassword: "battery staple horse",
+	})
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	current, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: sessions[2].RefreshToken})
+	if err != nil {
+		t.Fatalf("the current session was logged out: %v", err)
+	}
+	for _, i := range []int{0, 1} {
+		if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: sessions[i].RefreshToken}); err == nil {
+			t.Errorf("session %d survived the password change", i)
+		}
+	}
+
+	// Without a session every one is revoked
+	if err := f.svc.ChangePassword(ctx, userID, uuid.Nil, ChangePasswordRequest{
+		CurrentPassword: "battery staple horse", NewPassword: "correct horse battery",
+	}); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: current.RefreshToken}); err == nil {
+		t.Error("a session survived a password change without a known session")
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	guest := &entity.Booking{ID: uuid.New(), GuestEmail: "fan@example.com"}
+	f.bookings.bookings = []*entity.Booking{guest}
+	userID := uuid.MustParse(f.register(t, "fan@example.com").User.ID)
+
+	// Registration issued a token even though the email was not queued
+	if len(f.verify.tokens) != 1 {
+		t.Fatalf("%d verification tokens after registering, want 1", len(f.verify.tokens))
+	}
+	f.verify.tokens[0].TokenHash = authinfra.HashToken("emailed-token")
+
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailRequest{Token: "wrong-token"}); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Errorf("wrong token: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailRequest{Token: "emailed-token"}); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if !f.users.users[userID].EmailVerified {
+		t.Error("the email is not verified")
+	}
+	if guest.UserID == nil || *guest.UserID != userID {
+		t.Errorf("guest booking user = %v, want %s", guest.UserID, userID)
+	}
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailRequest{Token: "emailed-token"}); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Errorf("reused token: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+
+	if err := f.svc.SendVerificationEmail(ctx, userID); !apperrors.Is(err, apperrors.CodeBadRequest) {
+		t.Errorf("resend after verifying: %v, want %s", err, apperrors.CodeBadRequest)
+	}
+}
+
+func TestSendVerificationEmailIsThrottled(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	userID := uuid.MustParse(f.register(t, "fan@example.com").User.ID)
+
+	if err := f.svc.SendVerificationEmail(ctx, userID); !apperrors.Is(err, apperrors.CodeTooManyRequests) {
+		t.Errorf("resend right after registering: %v, want %s", err, apperrors.CodeTooManyRequests)
+	}
+
+	// Once the cooldown passed a new link is sent and the old one stops working
+	first := f.verify.tokens[0]
+	first.CreatedAt = first.CreatedAt.Add(-verificationResendCooldown)
+	if err := f.svc.SendVerificationEmail(ctx, userID); !apperrors.Is(err, apperrors.CodeInternal) {
+		t.Errorf("resend: %v, want %s as the email cannot be queued", err, apperrors.CodeInternal)
+	}
+	if len(f.verify.tokens) != 2 || !first.Used {
+		t.Errorf("%d tokens, first used %v: the old link still works", len(f.verify.tokens), first.Used)
+	}
+
+	// An unused link past its expiry has expired; the superseded one is
+	// invalid
+	first.TokenHash = authinfra.HashToken("first-token")
+	second := f.verify.tokens[1]
+	second.TokenHash = authinfra.HashToken("second-token")
+	second.ExpiresAt = time.Now().Add(-time.Second)
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailRequest{Token: "second-token"}); !apperrors.Is(err, apperrors.CodeTokenExpired) {
+		t.Errorf("expired token: %v, want %s", err, apperrors.CodeTokenExpired)
+	}
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailRequest{Token: "first-token"}); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Errorf("superseded token: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+}
+
+func TestRefreshRotatesTheToken(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	res := f.register(t, "fan@example.com")
+
+	rotated, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if rotated.RefreshToken == "" || rotated.RefreshToken == res.RefreshToken {
+		t.Fatalf("refresh token = %q, want a new one", rotated.RefreshToken)
+	}
+
+	old, _ := f.tokens.GetByTokenHash(ctx, authinfra.HashToken(res.RefreshToken))
+	next, err := f.tokens.GetByTokenHash(ctx, authinfra.HashToken(rotated.RefreshToken))
+	if err != nil {
+		t.Fatalf("the new refresh token was not stored: %v", err)
+	}
+	if !old.Revoked || old.ReplacedByID == nil || *old.ReplacedByID != next.ID {
+		t.Errorf("old token revoked %v, replaced by %v, want revoked for %s", old.Revoked, old.ReplacedByID, next.ID)
+	}
+
+	// The new token rotates in turn
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: rotated.RefreshToken}); err != nil {
+		t.Errorf("the rotated token was rejected: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesEverySession(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	_, sessions := f.signIn(t, "fan@example.com")
+
+	rotated, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: sessions[0].RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	// The rotated-away token comes back: whoever holds it may be an attacker
+	_, err = f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: sessions[0].RefreshToken})
+	if !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Fatalf("reuse: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+	for _, token := range f.tokens.all() {
+		if !token.Revoked {
+			t.Errorf("token %s survived the reuse", token.ID)
+		}
+	}
+	for _, refreshToken := range []string{rotated.RefreshToken, sessions[1].RefreshToken} {
+		if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: refreshToken}); err == nil {
+			t.Error("a session can still refresh after the reuse")
+		}
+	}
+}
+
+func TestRefreshTokenFamily(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	_, sessions := f.signIn(t, "fan@example.com")
+
+	stored := func(refreshToken string) *entity.RefreshToken {
+		t.Helper()
+		token, err := f.tokens.GetByTokenHash(ctx, authinfra.HashToken(refreshToken))
+		if err != nil {
+			t.Fatalf("GetByTokenHash: %v", err)
+		}
+		return token
+	}
+
+	chain := []string{sessions[0].RefreshToken}
+	for range 2 {
+		next, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: chain[len(chain)-1]})
+		if err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		chain = append(chain, next.RefreshToken)
+	}
+
+	first := stored(chain[0])
+	if first.ParentTokenHash != nil {
+		t.Errorf("the login token has a parent")
+	}
+	for i := 1; i < len(chain); i++ {
+		token := stored(chain[i])
+		if token.FamilyID != first.FamilyID {
+			t.Errorf("rotation %d left the family: %s, want %s", i, token.FamilyID, first.FamilyID)
+		}
+		if parent := stored(chain[i-1]); token.ParentTokenHash == nil || *token.ParentTokenHash != parent.TokenHash {
+			t.Errorf("rotation %d does not point at the token it replaced", i)
+		}
+	}
+	if other := stored(sessions[1].RefreshToken); other.FamilyID == first.FamilyID {
+		t.Error("a second login joined the first login's family")
+	}
+
+	// A token from the middle of the chain comes back
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: chain[1]}); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Fatalf("reuse: %v, want %s", err, apperrors.CodeTokenInvalid)
+	}
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: chain[2]}); err == nil {
+		t.Error("the family's latest token still refreshes after the reuse")
+	}
+}
+
+func TestConcurrentRefreshesWithOneToken(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	res := f.register(t, "fan@example.com")
+
+	const refreshes = 8
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	for range refreshes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := succeeded.Load(); got != 1 {
+		t.Errorf("%d refreshes succeeded, want exactly 1", got)
+	}
+	// The losers count as reuse, which ends the winner's session as well
+	for _, token := range f.tokens.all() {
+		if !token.Revoked {
+			t.Errorf("token %s survived the concurrent reuse", token.ID)
+		}
+	}
+}
+
+func TestForgotPasswordEmailsTheResetLink(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	res := f.register(t, "fan@example.com")
+	sender := f.withSender(t, nil)
+
+	if err := f.svc.ForgotPassword(ctx, ForgotPasswordRequest{Email: "fan@example.com"}); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+
+	var email async.EmailPayload
+	select {
+	case email = <-sender.sent:
+	case <-time.After(time.Second):
+		t.Fatal("no email was sent")
+	}
+	if !slices.Equal(email.To, []string{"fan@example.com"}) || !email.IsHTML {
+		t.Errorf("email to %v, html %v", email.To, email.IsHTML)
+	}
+
+	// The link carries the raw token; only its hash is stored
+	match := regexp.MustCompile(`https://cinema\.example\.com/reset-password\?token=([A-Za-z0-9_=-]+)`).FindStringSubmatch(email.Body)
+	if match == nil {
+		t.Fatalf("no reset link in %s", email.Body)
+	}
+	token := match[1]
+	if len(f.resets.tokens) != 1 || f.resets.tokens[0].TokenHash != authinfra.HashToken(token) {
+		t.Fatalf("the link's token %q is not the stored one", token)
+	}
+	if strings.Contains(email.Body, f.resets.tokens[0].TokenHash) {
+		t.Error("the email carries the token hash")
+	}
+
+	if err := f.svc.ResetPassword(ctx, ResetPasswordRequest{Token: token, NewPassword: "battery staple horse"}); err != nil {
+		t.Fatalf("ResetPassword with the emailed token: %v", err)
+	}
+	if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: res.RefreshToken}); err == nil {
+		t.Error("a session survived the password reset")
+	}
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	f.register(t, "fan@example.com")
+	sender := f.withSender(t, errors.New("smtp: connection refused"))
+
+	// A failed delivery and an unknown address answer like a sent email
+	for _, email := range []string{"fan@example.com", "nobody@example.com"} {
+		if err := f.svc.ForgotPassword(ctx, ForgotPasswordRequest{Email: email}); err != nil {
+			t.Errorf("%s: %v", email, err)
+		}
+	}
+	select {
+	case email := <-sender.sent:
+		if email.To[0] != "fan@example.com" {
+			t.Errorf("an email went to %v", email.To)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no email was attempted")
+	}
+	select {
+	case email := <-sender.sent:
+		t.Errorf("an email went to %v", email.To)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLoginWithGoogle(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t)
+	f.google.profiles["new-fan"] = &oauth.GoogleProfile{Subject: "g-1", Email: "new@example.com", EmailVerified: true, GivenName: "New", FamilyName: "Fan"}
+	f.google.profiles["member"] = &oauth.GoogleProfile{Subject: "g-2", Email: "member@example.com", EmailVerified: true}
+	f.google.profiles["unverified"] = &oauth.GoogleProfile{Subject: "g-3", Email: "unverified@example.com"}
+
+	t.Run("new account", func(t *testing.T) {
+		res, err := f.svc.LoginWithGoogle(ctx, "new-fan")
+		if err != nil {
+			t.Fatalf("LoginWithGoogle: %v", err)
+		}
+		user := f.users.users[uuid.MustParse(res.User.ID)]
+		if user.GoogleID == nil || *user.GoogleID != "g-1" || !user.EmailVerified || user.FirstName != "New" {
+			t.Errorf("created user = %+v", user)
+		}
+
+		again, err := f.svc.LoginWithGoogle(ctx, "new-fan")
+		if err != nil {
+			t.Fatalf("second sign-in: %v", err)
+		}
+		if again.User.ID != res.User.ID || len(f.users.users) != 1 {
+			t.Errorf("the second sign-in did not find the linked user")
+		}
+	})
+
+	t.Run("links the account with the same email", func(t *testing.T) {
+		member := f.register(t, "member@example.com")
+		memberID := uuid.MustParse(member.User.ID)
+		f.users.users[memberID].EmailVerified = true
+
+		res, err := f.svc.LoginWithGoogle(ctx, "member")
+		if err != nil {
+			t.Fatalf("LoginWithGoogle: %v", err)
+		}
+		if res.User.ID != member.User.ID {
+			t.Fatalf("signed in as %s, want the existing user %s", res.User.ID, member.User.ID)
+		}
+		if linked := f.users.users[memberID].GoogleID; linked == nil || *linked != "g-2" {
+			t.Errorf("google_id = %v, want g-2", linked)
+		}
+	})
+
+	t.Run("takes over an unverified account with the same email", func(t *testing.T) {
+		// Someone registered the owner's email first and booked as a guest
+		// with it, hoping the owner's bookings would follow
+		squatter := f.register(t, "owner@example.com")
+		squatterID := uuid.MustParse(squatter.User.ID)
+		f.bookings.bookings = append(f.bookings.bookings, &entity.Booking{GuestEmail: "owner@example.com"})
+		f.google.profiles["owner"] = &oauth.GoogleProfile{Subject: "g-4", Email: "owner@example.com", EmailVerified: true}
+
+		res, err := f.svc.LoginWithGoogle(ctx, "owner")
+		if err != nil {
+			t.Fatalf("LoginWithGoogle: %v", err)
+		}
+		if res.User.ID != squatter.User.ID {
+			t.Fatalf("signed in as %s, want the existing user %s", res.User.ID, squatter.User.ID)
+		}
+		if user := f.users.users[squatterID]; user.GoogleID == nil || !user.EmailVerified {
+			t.Errorf("account not linked: %+v", user)
+		}
+		if _, err := f.svc.Login(ctx, LoginRequest{Email: "owner@example.com", Password: "correct horse battery"}); !apperrors.Is(err, apperrors.CodeInvalidCredentials) {
+			t.Errorf("the earlier password still signs in: %v", err)
+		}
+		if _, err := f.svc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: squatter.RefreshToken}); err == nil {
+			t.Errorf("the earlier session still refreshes")
+		}
+		for _, token := range f.tokens.all() {
+			if token.UserID == squatterID && token.Revoked == (token.TokenHash == authinfra.HashToken(res.RefreshToken)) {
+				t.Errorf("session %s revoked = %t", token.ID, token.Revoked)
+			}
+		}
+		// The guest bookings go to the account only now that Google has
+		// proved the email, and the squatter can no longer sign in to it
+		if claimed := f.bookings.bookings[len(f.bookings.bookings)-1].UserID; claimed == nil || *claimed != squatterID {
+			t.Errorf("guest booking claimed by %v, want the linked account", claimed)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		if _, err := f.svc.LoginWithGoogle(ctx, "unverified"); !apperrors.Is(err, apperrors.CodeEmailNotVerified) {
+			t.Errorf("unverified Google email: %v, want %s", err, apperrors.CodeEmailNotVerified)
+		}
+		if _, err := f.svc.LoginWithGoogle(ctx, "forged"); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+			t.Errorf("invalid token: %v, want %s", err, apperrors.CodeTokenInvalid)
+		}
+
+		res, _ := f.svc.LoginWithGoogle(ctx, "new-fan")
+		f.users.users[uuid.MustParse(res.User.ID)].IsActive = false
+		if _, err := f.svc.LoginWithGoogle(ctx, "new-fan"); !apperrors.Is(err, apperrors.CodeAccountDisabled) {
+			t.Errorf("disabled account: %v, want %s", err, apperrors.CodeAccountDisabled)
+		}
+
+		f.svc.google = nil
+		if _, err := f.svc.LoginWithGoogle(ctx, "member"); !apperrors.Is(err, apperrors.CodeFailedPrecondition) {
+			t.Errorf("Google sign-in off: %v, want %s", err, apperrors.CodeFailedPrecondition)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/mailer"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// verificationResendCooldown is how long a user waits before another
+// verification email is sent
+const verificationResendCooldown = time.Minute
+
+// SendVerificationEmail emails the user a new verification link. Links sent
+// before stop working.
+func (s *Service) SendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return apperrors.ErrBadRequest("email is already verified")
+	}
+
+	latest, err := s.verifyRepo.GetLatestByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < verificationResendCooldown {
+		return apperrors.New(apperrors.CodeTooManyRequests, "a verification email was just sent, try again in a minute")
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// VerifyEmail marks the email of the token's user verified. Guest bookings
+// made with the email are then attached to the account.
+func (s *Service) VerifyEmail(ctx context.Context, req VerifyEmailRequest) error {
+	log := s.logger.WithContext(ctx)
+
+	token, err := s.verifyRepo.GetByTokenHash(ctx, authinfra.HashToken(req.Token))
+	if err != nil {
+		return err
+	}
+	// A used or superseded link is invalid; an unused one may have expired
+	if token.Used {
+		return apperrors.ErrTokenInvalid()
+	}
+	if !token.IsValid() {
+		return apperrors.ErrTokenExpired()
+	}
+
+	if err := s.verifyRepo.Redeem(ctx, token.ID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		log.Warn("failed to load user after email verification", zap.Error(err))
+		return nil
+	}
+
+	log.Info("email verified", zap.String("user_id", user.ID.String()))
+	s.claimGuestBookings(ctx, user)
+	return nil
+}
+
+// sendVerification replaces the user's verification tokens with a new one
+// and queues the email with its link
+func (s *Service) sendVerification(ctx context.Context, user *entity.User) error {
+	if err := s.verifyRepo.InvalidateAllForUser(ctx, user.ID); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to invalidate existing verification tokens")
+	}
+
+	token, err := authinfra.GenerateRandomToken(32)
+	if err != nil {
+		return apperrors.ErrInternal("failed to generate token")
+	}
+
+	verifyToken := &entity.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: authinfra.HashToken(token),
+		ExpiresAt: time.Now().Add(s.jwtManager.GetVerifyTokenExpiry()),
+	}
+	if err := s.verifyRepo.Create(ctx, verifyToken); err != nil {
+		return err
+	}
+
+	if err := s.emails.SendEmailVerification(ctx, mailer.EmailVerification{
+		To:     user.Email,
+		Name:   user.FirstName,
+		Token:  token,
+		Expiry: s.jwtManager.GetVerifyTokenExpiry(),
+	}); err != nil {
+		return apperrors.ErrInternal("failed to queue verification email")
+	}
+	return nil
+}
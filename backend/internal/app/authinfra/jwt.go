@@ -32,6 +32,9 @@ type Claims struct {
 	Email  string    `json:"email"`
 	Role   string    `json:"role"`
 	Type   TokenType `json:"type"`
+	// SessionID is the ID of the refresh token the session started with;
+	// access tokens carry it so the current session is known from either
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -59,13 +62,14 @@ func NewJWTManager(cfg config.JWTConfig) *JWTManager {
 	}
 }
 
-// GenerateAccessToken generates an access token
-func (m *JWTManager) GenerateAccessToken(userID uuid.UUID, email, role string) (string, error) {
+// GenerateAccessToken generates an access token for a session
+func (m *JWTManager) GenerateAccessToken(userID, sessionID uuid.UUID, email, role string) (string, error) {
 	claims := Claims{
-		UserID: userID.String(),
-		Email:  email,
-		Role:   role,
-		Type:   TokenTypeAccess,
+		UserID:    userID.String(),
+		Email:     email,
+		Role:      role,
+		Type:      TokenTypeAccess,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -79,13 +83,14 @@ func (m *JWTManager) GenerateAccessToken(userID uuid.UUID, email, role string) (
 	return token.SignedString(m.accessSecret)
 }
 
-// GenerateRefreshToken generates a refresh token
-func (m *JWTManager) GenerateRefreshToken(userID uuid.UUID, email, role string) (string, error) {
+// GenerateRefreshToken generates a refresh token for a session
+func (m *JWTManager) GenerateRefreshToken(userID, sessionID uuid.UUID, email, role string) (string, error) {
 	claims := Claims{
-		UserID: userID.String(),
-		Email:  email,
-		Role:   role,
-		Type:   TokenTypeRefresh,
+		UserID:    userID.String(),
+		Email:     email,
+		Role:      role,
+		Type:      TokenTypeRefresh,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -169,6 +174,11 @@ func (m *JWTManager) GetResetTokenExpiry() time.Duration {
 	return m.resetTokenExpiry
 }
 
+// GetVerifyTokenExpiry returns email verification token expiry duration
+func (m *JWTManager) GetVerifyTokenExpiry() time.Duration {
+	return m.verifyTokenExpiry
+}
+
 // PasswordManager handles password operations
 type PasswordManager struct {
 	cost int
@@ -222,3 +232,10 @@ func GeneratePaymentReference() string {
 	random, _ := GenerateRandomToken(4)
 	return "PAY" + string(rune(timestamp)) + random[:6]
 }
+
+// GenerateGroupReference generates a unique reference for a group checkout
+func GenerateGroupReference() string {
+	date := time.Now().Format("20060102")
+	random, _ := GenerateRandomToken(4)
+	return "GRP" + date + random[:6]
+}
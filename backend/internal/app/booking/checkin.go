@@ -0,0 +1,196 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/ticket"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CheckIn admits the holder of an e-ticket scanned at the door. The QR
+// payload must carry a booking reference signed with the ticket key, or
+// the reference is typed in along with the show being admitted. The
+// booking must be for that show, when given, and confirmed and paid for a
+// show starting today within the check-in window. The booking is then
+// COMPLETED. Admins may check in tickets at every cinema; managers and
+// staff at a cinema they are assigned to. Every scan is recorded, whether
+// or not it let the customer in.
+func (s *Service) CheckIn(ctx context.Context, staffID uuid.UUID, role string, req CheckInRequest) (*StaffBookingResponse, error) {
+	attempt := &entity.TicketCheckIn{StaffUserID: staffID}
+	booking, err := s.checkIn(ctx, staffID, role, req, attempt)
+	if err != nil {
+		attempt.Message = err.Error()
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			attempt.Message = appErr.Message
+		}
+	}
+	s.recordCheckIn(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toStaffBooking(booking)
+	return &res, nil
+}
+
+// checkIn runs the checks of CheckIn and fills in the attempt to record
+func (s *Service) checkIn(ctx context.Context, staffID uuid.UUID, role string, req CheckInRequest, attempt *entity.TicketCheckIn) (*entity.Booking, error) {
+	reference := strings.TrimSpace(req.BookingReference)
+	attempt.BookingReference = reference
+	if req.Payload != "" {
+		var ok bool
+		reference, ok = ticket.Verify(req.Payload, s.ticketCfg.SigningSecret)
+		attempt.BookingReference = reference
+		if !ok {
+			attempt.Result = entity.CheckInInvalidTicket
+			return nil, apperrors.New(apperrors.CodeInvalidTicket, "ticket is not valid")
+		}
+	}
+	if reference == "" {
+		// An empty reference would not filter the search at all
+		attempt.Result = entity.CheckInNotFound
+		return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+
+	bookings, _, err := s.bookingRepo.Search(ctx, repository.BookingFilter{Reference: reference}, 0, 1)
+	if err != nil {
+		attempt.Result = entity.CheckInError
+		return nil, err
+	}
+	if len(bookings) == 0 {
+		attempt.Result = entity.CheckInNotFound
+		return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+	booking := bookings[0]
+	attempt.BookingID = &booking.ID
+
+	cinemaID := booking.Showtime.CinemaID
+	if err := s.checkCinemaAccess(ctx, staffID, role, &cinemaID); err != nil {
+		attempt.Result = entity.CheckInError
+		if apperrors.Is(err, apperrors.CodeForbidden) {
+			attempt.Result = entity.CheckInForbidden
+		}
+		return nil, err
+	}
+
+	if req.ShowtimeID != nil && booking.ShowtimeID != *req.ShowtimeID {
+		attempt.Result = entity.CheckInWrongShowtime
+		loc := booking.Showtime.Cinema.Location()
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition,
+			fmt.Sprintf("ticket is for %s on %s", booking.Showtime.Movie.Title,
+				booking.Showtime.StartsAt(loc).Format("Mon, 02 Jan 2006 15:04")))
+	}
+	if booking.CheckedInAt != nil {
+		attempt.Result = entity.CheckInAlreadyCheckedIn
+		return nil, alreadyCheckedIn(booking)
+	}
+	if booking.BookingStatus != entity.BookingConfirmed || !booking.IsPaid() {
+		attempt.Result = entity.CheckInNotConfirmed
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition,
+			fmt.Sprintf("booking is %s with payment %s; only confirmed, paid bookings can be checked in", booking.BookingStatus, booking.PaymentStatus))
+	}
+
+	// The show date and check-in window are judged in the cinema's time zone
+	loc := booking.Showtime.Cinema.Location()
+	now := time.Now().In(loc)
+	startsAt := booking.Showtime.StartsAt(loc)
+	if startsAt.Format(time.DateOnly) != now.Format(time.DateOnly) {
+		attempt.Result = entity.CheckInWrongDay
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition,
+			"ticket is for the show on "+startsAt.Format("Mon, 02 Jan 2006 15:04"))
+	}
+	if opens := startsAt.Add(-s.ticketCfg.CheckInOpensBefore); now.Before(opens) {
+		attempt.Result = entity.CheckInOutsideWindow
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition, "check-in for this show opens at "+opens.Format("15:04"))
+	}
+	if closes := startsAt.Add(s.ticketCfg.CheckInClosesAfter); now.After(closes) {
+		attempt.Result = entity.CheckInOutsideWindow
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition, "check-in for this show closed at "+closes.Format("15:04"))
+	}
+
+	if err := s.bookingRepo.CheckIn(ctx, booking.ID, staffID, now); err != nil {
+		if !apperrors.Is(err, apperrors.CodeInvalidStatus) {
+			attempt.Result = entity.CheckInError
+			return nil, err
+		}
+		// The booking changed since it was read; most likely the same
+		// ticket was scanned at another door a moment ago
+		current, getErr := s.bookingRepo.GetByID(ctx, booking.ID)
+		if getErr == nil && current.CheckedInAt != nil {
+			current.Showtime = booking.Showtime
+			attempt.Result = entity.CheckInAlreadyCheckedIn
+			return nil, alreadyCheckedIn(current)
+		}
+		attempt.Result = entity.CheckInNotConfirmed
+		return nil, err
+	}
+
+	booking.BookingStatus = entity.BookingCompleted
+	booking.CheckedInAt = &now
+	booking.CheckedInBy = &staffID
+	attempt.Result = entity.CheckInAccepted
+
+	s.logger.WithContext(ctx).Info("ticket checked in",
+		zap.String("booking_id", booking.ID.String()),
+		zap.String("booking_reference", booking.BookingReference),
+		zap.String("staff_user_id", staffID.String()),
+	)
+	return booking, nil
+}
+
+// ListCheckIns lists the bookings of a showtime checked in at the door, the
+// latest first, for staff of its cinema
+func (s *Service) ListCheckIns(ctx context.Context, viewerID uuid.UUID, role string, showtimeID uuid.UUID, page, limit int) ([]StaffBookingResponse, int64, error) {
+	showtime, err := s.showtimeRepo.GetByID(ctx, showtimeID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.checkCinemaAccess(ctx, viewerID, role, &showtime.CinemaID); err != nil {
+		return nil, 0, err
+	}
+
+	filter := repository.BookingFilter{ShowtimeID: &showtimeID, CheckedIn: true}
+	bookings, total, err := s.bookingRepo.Search(ctx, filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]StaffBookingResponse, 0, len(bookings))
+	for _, b := range bookings {
+		responses = append(responses, toStaffBooking(b))
+	}
+	return responses, total, nil
+}
+
+// recordCheckIn adds the scan to the audit log. A failure to record it
+// does not undo the check-in.
+func (s *Service) recordCheckIn(ctx context.Context, attempt *entity.TicketCheckIn) {
+	if err := s.checkInRepo.Create(ctx, attempt); err != nil {
+		s.logger.WithContext(ctx).Error("failed to record ticket check-in",
+			zap.String("booking_reference", attempt.BookingReference),
+			zap.String("result", string(attempt.Result)),
+			zap.Error(err),
+		)
+	}
+}
+
+// alreadyCheckedIn reports a second scan of a ticket with when, and by
+// whom, it was first checked in, so staff can spot copied tickets
+func alreadyCheckedIn(booking *entity.Booking) error {
+	at := booking.CheckedInAt.In(booking.Showtime.Cinema.Location())
+	return apperrors.New(apperrors.CodeAlreadyCheckedIn, "ticket was already checked in at "+at.Format("15:04")).
+		WithDetails(map[string]any{
+			"checked_in_at": at,
+			"checked_in_by": booking.CheckedInBy,
+		})
+}
@@ -0,0 +1,304 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/ticket"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const testTicketSecret = "ticket-secret"
+
+// memTickets keeps bookings by reference; scannedElsewhere checks a
+// booking in from another door just before CheckIn writes
+type memTickets struct {
+	repository.BookingRepository
+	bookings         map[string]*entity.Booking
+	scannedElsewhere bool
+}
+
+func (m *memTickets) Search(_ context.Context, filter repository.BookingFilter, _, _ int) ([]*entity.Booking, int64, error) {
+	if filter.CheckedIn {
+		var checkedIn []*entity.Booking
+		for _, booking := range m.bookings {
+			if booking.CheckedInAt != nil && booking.ShowtimeID == *filter.ShowtimeID {
+				checkedIn = append(checkedIn, booking)
+			}
+		}
+		return checkedIn, int64(len(checkedIn)), nil
+	}
+	booking, ok := m.bookings[filter.Reference]
+	if !ok {
+		return nil, 0, nil
+	}
+	copied := *booking
+	return []*entity.Booking{&copied}, 1, nil
+}
+
+func (m *memTickets) GetByID(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	for _, booking := range m.bookings {
+		if booking.ID == id {
+			copied := *booking
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+}
+
+func (m *memTickets) CheckIn(_ context.Context, id, staffID uuid.UUID, at time.Time) error {
+	for _, booking := range m.bookings {
+		if booking.ID != id {
+			continue
+		}
+		if m.scannedElsewhere {
+			other, earlier := uuid.New(), at.Add(-time.Second)
+			booking.CheckedInAt, booking.CheckedInBy = &earlier, &other
+		}
+		if booking.CheckedInAt != nil || booking.BookingStatus != entity.BookingConfirmed {
+			return apperrors.New(apperrors.CodeInvalidStatus, "booking can no longer be checked in")
+		}
+		booking.BookingStatus = entity.BookingCompleted
+		booking.CheckedInAt, booking.CheckedInBy = &at, &staffID
+	}
+	return nil
+}
+
+// memCheckIns records the scans
+type memCheckIns struct {
+	scans []*entity.TicketCheckIn
+}
+
+func (m *memCheckIns) Create(_ context.Context, checkIn *entity.TicketCheckIn) error {
+	m.scans = append(m.scans, checkIn)
+	return nil
+}
+
+// noonZone returns a fixed-offset zone where it is around noon, so a show a
+// few hours away is still today there
+func noonZone() string {
+	offset := 12 - time.Now().UTC().Hour()
+	// The sign of Etc/GMT zones is inverted: Etc/GMT-3 is three hours ahead
+	return fmt.Sprintf("Etc/GMT%+d", -offset)
+}
+
+type checkInFixture struct {
+	svc      *Service
+	bookings *memTickets
+	scans    *memCheckIns
+	cinema   uuid.UUID
+	showtime uuid.UUID
+	staff    uuid.UUID
+}
+
+func newCheckInFixture() *checkInFixture {
+	f := &checkInFixture{
+		bookings: &memTickets{bookings: map[string]*entity.Booking{}},
+		scans:    &memCheckIns{},
+		cinema:   uuid.New(),
+		showtime: uuid.New(),
+		staff:    uuid.New(),
+	}
+	staff := &memStaff{assigned: map[uuid.UUID]uuid.UUID{f.staff: f.cinema}}
+	showtimes := &stubShowtimes{showtime: &entity.Showtime{ID: f.showtime, CinemaID: f.cinema}}
+	f.svc = NewService(nil, showtimes, nil, f.bookings, nil, nil, nil, nil, nil, staff, f.scans, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{}, config.TicketConfig{SigningSecret: testTicketSecret, CheckInOpensBefore: time.Hour, CheckInClosesAfter: 30 * time.Minute},
+		&logger.Logger{Logger: zap.NewNop()})
+	return f
+}
+
+// ticket adds a confirmed, paid booking for a show starting after d and
+// returns its QR payload
+func (f *checkInFixture) ticket(reference string, d time.Duration) (*entity.Booking, string) {
+	loc, _ := time.LoadLocation(noonZone())
+	start := time.Now().In(loc).Add(d)
+	f.bookings.bookings[reference] = &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: reference,
+		BookingStatus:    entity.BookingConfirmed,
+		PaymentStatus:    entity.PaymentPaid,
+		ShowtimeID:       f.showtime,
+		Showtime: entity.Showtime{
+			ID:        f.showtime,
+			CinemaID:  f.cinema,
+			ShowDate:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+			StartTime: start.Format("15:04"),
+			Cinema:    entity.Cinema{Timezone: loc.String()},
+		},
+	}
+	return f.bookings.bookings[reference], ticket.Sign(reference, testTicketSecret)
+}
+
+func (f *checkInFixture) lastResult() entity.CheckInResult {
+	return f.scans.scans[len(f.scans.scans)-1].Result
+}
+
+func TestCheckIn(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckInFixture()
+	booking, payload := f.ticket("BK-DOOR01", 20*time.Minute)
+
+	res, err := f.svc.CheckIn(ctx, f.staff, "STAFF", CheckInRequest{Payload: payload})
+	if err != nil {
+		t.Fatalf("CheckIn: %v", err)
+	}
+	if res.BookingStatus != string(entity.BookingCompleted) || res.CheckedInBy == nil || *res.CheckedInBy != f.staff {
+		t.Errorf("checked in as %s by %v, want COMPLETED by the staff user", res.BookingStatus, res.CheckedInBy)
+	}
+	if booking.CheckedInAt == nil || f.lastResult() != entity.CheckInAccepted {
+		t.Errorf("check-in stored %v, recorded %s", booking.CheckedInAt, f.lastResult())
+	}
+
+	// A second scan says when and by whom the ticket was first scanned
+	_, err = f.svc.CheckIn(ctx, uuid.New(), "ADMIN", CheckInRequest{Payload: payload})
+	var appErr *apperrors.AppError
+	if !apperrors.Is(err, apperrors.CodeAlreadyCheckedIn) || !errors.As(err, &appErr) {
+		t.Fatalf("second scan: %v, want %s", err, apperrors.CodeAlreadyCheckedIn)
+	}
+	if details, _ := appErr.Details.(map[string]any); details["checked_in_by"] != booking.CheckedInBy {
+		t.Errorf("details = %v, want the first scan's staff user", appErr.Details)
+	}
+	if len(f.scans.scans) != 2 || f.lastResult() != entity.CheckInAlreadyCheckedIn {
+		t.Errorf("%d scans recorded, last %s", len(f.scans.scans), f.lastResult())
+	}
+}
+
+func TestCheckInRejected(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckInFixture()
+	_, onTime := f.ticket("BK-ONTIME", 20*time.Minute)
+	_, early := f.ticket("BK-EARLY1", 2*time.Hour)
+	_, late := f.ticket("BK-LATE01", -45*time.Minute)
+	tomorrow, tomorrowPayload := f.ticket("BK-TMRW01", 20*time.Minute)
+	tomorrow.Showtime.ShowDate = tomorrow.Showtime.ShowDate.AddDate(0, 0, 1)
+	unpaid, unpaidPayload := f.ticket("BK-UNPAID", 20*time.Minute)
+	unpaid.BookingStatus, unpaid.PaymentStatus = entity.BookingPending, entity.PaymentPending
+
+	tests := []struct {
+		name    string
+		staff   uuid.UUID
+		role    string
+		payload string
+		code    apperrors.ErrorCode
+		result  entity.CheckInResult
+	}{
+		{"forged", f.staff, "STAFF", "BK-ONTIME." + "00112233445566778899aabbccddeeff", apperrors.CodeInvalidTicket, entity.CheckInInvalidTicket},
+		{"unknown reference", f.staff, "STAFF", ticket.Sign("BK-NOSUCH", testTicketSecret), apperrors.CodeBookingNotFound, entity.CheckInNotFound},
+		{"another cinema's staff", uuid.New(), "STAFF", onTime, apperrors.CodeForbidden, entity.CheckInForbidden},
+		{"too early", f.staff, "STAFF", early, apperrors.CodeFailedPrecondition, entity.CheckInOutsideWindow},
+		{"too late", f.staff, "STAFF", late, apperrors.CodeFailedPrecondition, entity.CheckInOutsideWindow},
+		{"another day", f.staff, "STAFF", tomorrowPayload, apperrors.CodeFailedPrecondition, entity.CheckInWrongDay},
+		{"not paid", f.staff, "STAFF", unpaidPayload, apperrors.CodeFailedPrecondition, entity.CheckInNotConfirmed},
+	}
+	for _, tt := range tests {
+		_, err := f.svc.CheckIn(ctx, tt.staff, tt.role, CheckInRequest{Payload: tt.payload})
+		if !apperrors.Is(err, tt.code) {
+			t.Errorf("%s: %v, want %s", tt.name, err, tt.code)
+			continue
+		}
+		if got := f.lastResult(); got != tt.result {
+			t.Errorf("%s: recorded %s, want %s", tt.name, got, tt.result)
+		}
+	}
+	if len(f.scans.scans) != len(tests) {
+		t.Errorf("%d scans recorded, want %d", len(f.scans.scans), len(tests))
+	}
+}
+
+func TestCheckInRace(t *testing.T) {
+	f := newCheckInFixture()
+	_, payload := f.ticket("BK-RACE01", 20*time.Minute)
+	f.bookings.scannedElsewhere = true
+
+	_, err := f.svc.CheckIn(context.Background(), f.staff, "STAFF", CheckInRequest{Payload: payload})
+	if !apperrors.Is(err, apperrors.CodeAlreadyCheckedIn) {
+		t.Fatalf("CheckIn = %v, want %s", err, apperrors.CodeAlreadyCheckedIn)
+	}
+	if f.lastResult() != entity.CheckInAlreadyCheckedIn {
+		t.Errorf("recorded %s", f.lastResult())
+	}
+}
+
+func TestCheckInByReference(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckInFixture()
+	booking, _ := f.ticket("BK-TYPED1", 20*time.Minute)
+	booking.Showtime.Movie.Title = "Dune: Part Two"
+	other := uuid.New()
+
+	// A ticket for another show says which one it is for
+	_, err := f.svc.CheckIn(ctx, f.staff, "STAFF", CheckInRequest{BookingReference: "BK-TYPED1", ShowtimeID: &other})
+	if !apperrors.Is(err, apperrors.CodeFailedPrecondition) || !strings.Contains(err.Error(), "Dune: Part Two") {
+		t.Fatalf("CheckIn for another show = %v", err)
+	}
+	if f.lastResult() != entity.CheckInWrongShowtime || booking.CheckedInAt != nil {
+		t.Errorf("recorded %s, checked in %v", f.lastResult(), booking.CheckedInAt)
+	}
+
+	res, err := f.svc.CheckIn(ctx, f.staff, "STAFF", CheckInRequest{BookingReference: " BK-TYPED1 ", ShowtimeID: &f.showtime})
+	if err != nil {
+		t.Fatalf("CheckIn by reference: %v", err)
+	}
+	if res.BookingStatus != string(entity.BookingCompleted) || f.lastResult() != entity.CheckInAccepted {
+		t.Errorf("checked in as %s, recorded %s", res.BookingStatus, f.lastResult())
+	}
+	if reference := f.scans.scans[len(f.scans.scans)-1].BookingReference; reference != "BK-TYPED1" {
+		t.Errorf("recorded reference %q", reference)
+	}
+}
+
+func TestCheckInRequestValidation(t *testing.T) {
+	v := validator.New()
+	showtimeID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   CheckInRequest
+		valid bool
+	}{
+		{"payload", CheckInRequest{Payload: "BK-1.sig"}, true},
+		{"payload for a show", CheckInRequest{Payload: "BK-1.sig", ShowtimeID: &showtimeID}, true},
+		{"reference for a show", CheckInRequest{BookingReference: "BK-1", ShowtimeID: &showtimeID}, true},
+		{"reference alone", CheckInRequest{BookingReference: "BK-1"}, false},
+		{"both", CheckInRequest{Payload: "BK-1.sig", BookingReference: "BK-1", ShowtimeID: &showtimeID}, false},
+		{"neither", CheckInRequest{ShowtimeID: &showtimeID}, false},
+	}
+	for _, tt := range tests {
+		if errs := v.Validate(tt.req); (errs == nil) != tt.valid {
+			t.Errorf("%s: errors %v, want valid %v", tt.name, errs, tt.valid)
+		}
+	}
+}
+
+func TestListCheckIns(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckInFixture()
+	_, first := f.ticket("BK-LIST01", 20*time.Minute)
+	f.ticket("BK-LIST02", 20*time.Minute)
+	if _, err := f.svc.CheckIn(ctx, f.staff, "STAFF", CheckInRequest{Payload: first}); err != nil {
+		t.Fatalf("CheckIn: %v", err)
+	}
+
+	res, total, err := f.svc.ListCheckIns(ctx, f.staff, "STAFF", f.showtime, 1, 20)
+	if err != nil {
+		t.Fatalf("ListCheckIns: %v", err)
+	}
+	if total != 1 || len(res) != 1 || res[0].BookingReference != "BK-LIST01" {
+		t.Errorf("listed %d of %d check-ins: %+v", len(res), total, res)
+	}
+	if _, _, err := f.svc.ListCheckIns(ctx, uuid.New(), "STAFF", f.showtime, 1, 20); !apperrors.Is(err, apperrors.CodeForbidden) {
+		t.Errorf("ListCheckIns by another cinema's staff = %v, want forbidden", err)
+	}
+}
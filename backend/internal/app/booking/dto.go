@@ -0,0 +1,596 @@
+package booking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/pkg/apiversion"
+
+	"github.com/google/uuid"
+)
+
+// HoldSeatsRequest represents request to temporarily hold seats
+type HoldSeatsRequest struct {
+	ShowtimeID  uuid.UUID   `json:"showtime_id" validate:"required" swaggertype:"string" format:"uuid"`
+	SeatIDs     []uuid.UUID `json:"seat_ids" validate:"required,min=1,dive,required" swaggertype:"array,string" format:"uuid"`
+	PresaleCode string      `json:"presale_code,omitempty"` // unlocks seats before sales open
+	AccessCode  string      `json:"access_code,omitempty"`  // required for private showtimes
+	// AssistiveDevices are reserved from the screen's stock with the booking
+	AssistiveDevices []DeviceRequest `json:"assistive_devices,omitempty" validate:"omitempty,max=2,dive"`
+}
+
+// DeviceRequest asks for assistive devices of one type
+type DeviceRequest struct {
+	DeviceType string `json:"device_type" validate:"required,oneof=ASSISTED_LISTENING AUDIO_DESCRIPTION"`
+	Quantity   int    `json:"quantity" validate:"required,min=1"`
+}
+
+// ConfirmBookingRequest turns a hold into a booking
+type ConfirmBookingRequest struct {
+	HoldID        string `json:"hold_id" validate:"required"`
+	PaymentMethod string `json:"payment_method,omitempty" validate:"omitempty,oneof=CREDIT_CARD DEBIT_CARD PAYPAL APPLE_PAY GOOGLE_PAY"`
+	// PromoCode is re-checked and redeemed when the booking is created
+	PromoCode string `json:"promo_code,omitempty" validate:"omitempty,max=50"`
+	// RedeemPoints is how many loyalty points to take off the seats; no more
+	// than the seats cost are used
+	RedeemPoints int `json:"redeem_points,omitempty" validate:"omitempty,min=0"`
+}
+
+// ValidatePromoCodeRequest checks a promo code against a hold before
+// confirming it
+type ValidatePromoCodeRequest struct {
+	HoldID    string `json:"hold_id" validate:"required"`
+	PromoCode string `json:"promo_code" validate:"required,max=50"`
+}
+
+// PromoQuoteResponse is what a hold costs with a promo code applied
+type PromoQuoteResponse struct {
+	PromoCode string  `json:"promo_code"`
+	Subtotal  float64 `json:"subtotal"`
+	Discount  float64 `json:"discount"`
+	Fee       float64 `json:"fee"`
+	Total     float64 `json:"total"`
+}
+
+// ConfirmBookingResponse is a booking created from a hold, pending payment
+type ConfirmBookingResponse struct {
+	BookingID        uuid.UUID `json:"booking_id" swaggertype:"string" format:"uuid"`
+	BookingReference string    `json:"booking_reference"`
+	ShowtimeID       uuid.UUID `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	NumTickets       int       `json:"num_tickets"`
+	BookingStatus    string    `json:"booking_status"`
+	PaymentStatus    string    `json:"payment_status"`
+	Subtotal         float64   `json:"subtotal"`
+	Discount         float64   `json:"discount,omitempty"`
+	Fee              float64   `json:"fee"`
+	Total            float64   `json:"total"`
+	// PointsRedeemed is how many loyalty points were taken off the seats,
+	// worth PointsDiscount
+	PointsRedeemed int     `json:"points_redeemed,omitempty"`
+	PointsDiscount float64 `json:"points_discount,omitempty"`
+	// PayBy is when the booking expires unless paid
+	PayBy *time.Time `json:"pay_by,omitempty"`
+	// CheckoutURL is the payment page the customer is redirected to
+	CheckoutURL string `json:"checkout_url,omitempty"`
+	// PaymentError says why there is no checkout URL; the payment is
+	// retried with POST /bookings/{id}/payment
+	PaymentError string `json:"payment_error,omitempty"`
+}
+
+// CheckSeatsRequest lists seats to probe before holding them
+type CheckSeatsRequest struct {
+	SeatIDs []uuid.UUID `json:"seat_ids" validate:"required,min=1,max=50,dive,required" swaggertype:"array,string" format:"uuid"`
+}
+
+// SeatStatusResponse is the probed status of one seat
+type SeatStatusResponse struct {
+	SeatID uuid.UUID `json:"seat_id" swaggertype:"string" format:"uuid"`
+	Status string    `json:"status"` // AVAILABLE, LOCKED or BOOKED
+}
+
+// CheckSeatsResponse is an advisory snapshot of seat availability. Nothing
+// is reserved; a hold can still fail for seats reported available.
+type CheckSeatsResponse struct {
+	ShowtimeID   uuid.UUID            `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Seats        []SeatStatusResponse `json:"seats"`
+	AllAvailable bool                 `json:"all_available"`
+	// Alternatives is a suggested replacement selection of the same seat
+	// types, given when some seats are gone and one can be found
+	Alternatives []uuid.UUID `json:"alternatives,omitempty" swaggertype:"array,string" format:"uuid"`
+}
+
+// Seat map seat statuses
+const (
+	SeatStatusAvailable = "AVAILABLE"
+	SeatStatusLocked    = "LOCKED"
+	SeatStatusBooked    = "BOOKED"
+	// SeatStatusUnavailable marks companion seats with no linked wheelchair seat
+	SeatStatusUnavailable = "UNAVAILABLE"
+	// SeatStatusBlocked marks seats left empty by a distancing pattern
+	SeatStatusBlocked = "BLOCKED"
+	// SeatStatusNotOnSale marks seats of a type whose online sales open later
+	SeatStatusNotOnSale = "NOT_ON_SALE"
+	// SeatStatusBoxOfficeOnly marks seats of a type whose online sales cap is
+	// reached; they can still be sold at the box office
+	SeatStatusBoxOfficeOnly = "BOX_OFFICE_ONLY"
+)
+
+// SeatMapSeatResponse represents a seat on a showtime's seat map
+type SeatMapSeatResponse struct {
+	SeatID     uuid.UUID `json:"seat_id" swaggertype:"string" format:"uuid"`
+	SeatLabel  string    `json:"seat_label"`
+	RowLabel   string    `json:"row_label"`
+	SeatNumber int       `json:"seat_number"`
+	SeatType   string    `json:"seat_type"`
+	XPosition  float64   `json:"x_position"`
+	YPosition  float64   `json:"y_position"`
+	Status     string    `json:"status"`
+	Price      float64   `json:"price"`
+	Fare       string    `json:"fare"`
+	// CompanionSeatID links a wheelchair seat to its free companion seat
+	CompanionSeatID *uuid.UUID `json:"companion_seat_id,omitempty" swaggertype:"string" format:"uuid"`
+}
+
+// SeatMapResponse represents seat availability for a showtime. Seats are
+// omitted while sales are not open.
+type SeatMapResponse struct {
+	ShowtimeID  uuid.UUID  `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	SalesState  string     `json:"sales_state"`
+	SalesOpenAt *time.Time `json:"sales_open_at,omitempty"`
+	// Capacity is the number of seats that can be sold after capacity
+	// overrides; Available is what is left of it, held seats included
+	Capacity  int                   `json:"capacity"`
+	Available int                   `json:"available"`
+	SoldOut   bool                  `json:"sold_out"`
+	Seats     []SeatMapSeatResponse `json:"seats,omitempty"`
+	// SeatTypes lists the seat types with online sales rules
+	SeatTypes []SeatTypeInventoryResponse `json:"seat_types,omitempty"`
+}
+
+// SeatTypeInventoryResponse is the online inventory of a seat type with
+// sales rules
+type SeatTypeInventoryResponse struct {
+	SeatType       string     `json:"seat_type"`
+	OnSale         bool       `json:"on_sale"`
+	SalesOpenAt    *time.Time `json:"sales_open_at,omitempty"`
+	OnlineSalesCap *int       `json:"online_sales_cap,omitempty"`
+	// RemainingOnline is left out for types without a cap
+	RemainingOnline *int `json:"remaining_online,omitempty"`
+	BoxOfficeOnly   bool `json:"box_office_only"`
+}
+
+func toSeatTypeInventory(rules []*entity.SeatTypeRule, remaining map[entity.SeatType]int, now time.Time) []SeatTypeInventoryResponse {
+	inventory := make([]SeatTypeInventoryResponse, 0, len(rules))
+	for _, rule := range rules {
+		item := SeatTypeInventoryResponse{
+			SeatType:       string(rule.SeatType),
+			OnSale:         rule.OnSaleAt(now),
+			SalesOpenAt:    rule.SalesOpenAt,
+			OnlineSalesCap: rule.OnlineSalesCap,
+		}
+		if left, ok := remaining[rule.SeatType]; ok {
+			item.RemainingOnline = &left
+			item.BoxOfficeOnly = left == 0
+		}
+		inventory = append(inventory, item)
+	}
+	return inventory
+}
+
+// HeldSeatResponse represents a held seat in responses
+type HeldSeatResponse struct {
+	SeatID    uuid.UUID `json:"seat_id" swaggertype:"string" format:"uuid"`
+	SeatLabel string    `json:"seat_label"`
+	SeatType  string    `json:"seat_type"`
+	Price     float64   `json:"price"`
+	Fare      string    `json:"fare"` // COMPANION seats are free
+}
+
+// ReleaseHoldRequest represents a request to give up a seat hold
+type ReleaseHoldRequest struct {
+	HoldID     string    `json:"hold_id" validate:"required"`
+	ShowtimeID uuid.UUID `json:"showtime_id" validate:"required" swaggertype:"string" format:"uuid"`
+}
+
+// ExtendHoldRequest represents a request to extend a seat hold. The
+// showtime is optional; when given, the hold must be for it.
+type ExtendHoldRequest struct {
+	ShowtimeID *uuid.UUID `json:"showtime_id" swaggertype:"string" format:"uuid"`
+}
+
+// ReleaseHoldResponse reports how many seats a release freed
+type ReleaseHoldResponse struct {
+	HoldID        string `json:"hold_id"`
+	ReleasedSeats int    `json:"released_seats"` // 0 when the hold had already ended
+}
+
+// HoldResponse represents a seat hold in responses
+type HoldResponse struct {
+	HoldID     string             `json:"hold_id"`
+	ShowtimeID uuid.UUID          `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Seats      []HeldSeatResponse `json:"seats"`
+	Subtotal   float64            `json:"subtotal"`
+	Fee        float64            `json:"fee"` // booking fee, shown as its own line
+	Total      float64            `json:"total"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	// AssistiveDevices are reserved when the booking is confirmed
+	AssistiveDevices []entity.DeviceRequest `json:"assistive_devices,omitempty"`
+}
+
+// HeldSeatResponseV2 is the v2 shape of a held seat, with money in cents
+type HeldSeatResponseV2 struct {
+	SeatID     uuid.UUID `json:"seat_id" swaggertype:"string" format:"uuid"`
+	SeatLabel  string    `json:"seat_label"`
+	SeatType   string    `json:"seat_type"`
+	PriceCents int64     `json:"price_cents"`
+	Fare       string    `json:"fare"`
+}
+
+// HoldResponseV2 is the v2 shape of a seat hold, with money in cents
+type HoldResponseV2 struct {
+	HoldID           string                 `json:"hold_id"`
+	ShowtimeID       uuid.UUID              `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Seats            []HeldSeatResponseV2   `json:"seats"`
+	SubtotalCents    int64                  `json:"subtotal_cents"`
+	FeeCents         int64                  `json:"fee_cents"`
+	TotalCents       int64                  `json:"total_cents"`
+	ExpiresAt        time.Time              `json:"expires_at"`
+	AssistiveDevices []entity.DeviceRequest `json:"assistive_devices,omitempty"`
+}
+
+// ForVersion returns the hold shape for the given API version
+func (r *HoldResponse) ForVersion(v apiversion.Version) any {
+	if v == apiversion.V1 {
+		return r
+	}
+
+	seats := make([]HeldSeatResponseV2, len(r.Seats))
+	for i, seat := range r.Seats {
+		seats[i] = HeldSeatResponseV2{
+			SeatID:     seat.SeatID,
+			SeatLabel:  seat.SeatLabel,
+			SeatType:   seat.SeatType,
+			PriceCents: apiversion.Cents(seat.Price),
+			Fare:       seat.Fare,
+		}
+	}
+
+	return &HoldResponseV2{
+		HoldID:           r.HoldID,
+		ShowtimeID:       r.ShowtimeID,
+		Seats:            seats,
+		SubtotalCents:    apiversion.Cents(r.Subtotal),
+		FeeCents:         apiversion.Cents(r.Fee),
+		TotalCents:       apiversion.Cents(r.Total),
+		ExpiresAt:        r.ExpiresAt,
+		AssistiveDevices: r.AssistiveDevices,
+	}
+}
+
+// SeatMapSeatResponseV2 is the v2 shape of a seat map seat, with money in cents
+type SeatMapSeatResponseV2 struct {
+	SeatID          uuid.UUID  `json:"seat_id" swaggertype:"string" format:"uuid"`
+	SeatLabel       string     `json:"seat_label"`
+	RowLabel        string     `json:"row_label"`
+	SeatNumber      int        `json:"seat_number"`
+	SeatType        string     `json:"seat_type"`
+	XPosition       float64    `json:"x_position"`
+	YPosition       float64    `json:"y_position"`
+	Status          string     `json:"status"`
+	PriceCents      int64      `json:"price_cents"`
+	Fare            string     `json:"fare"`
+	CompanionSeatID *uuid.UUID `json:"companion_seat_id,omitempty" swaggertype:"string" format:"uuid"`
+}
+
+// SeatMapResponseV2 is the v2 shape of a seat map
+type SeatMapResponseV2 struct {
+	ShowtimeID  uuid.UUID               `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	SalesState  string                  `json:"sales_state"`
+	SalesOpenAt *time.Time              `json:"sales_open_at,omitempty"`
+	Capacity    int                     `json:"capacity"`
+	Available   int                     `json:"available"`
+	SoldOut     bool                    `json:"sold_out"`
+	Seats       []SeatMapSeatResponseV2 `json:"seats,omitempty"`
+}
+
+// ForVersion returns the seat map shape for the given API version
+func (r *SeatMapResponse) ForVersion(v apiversion.Version) any {
+	if v == apiversion.V1 {
+		return r
+	}
+
+	var seats []SeatMapSeatResponseV2
+	for _, seat := range r.Seats {
+		seats = append(seats, SeatMapSeatResponseV2{
+			SeatID:          seat.SeatID,
+			SeatLabel:       seat.SeatLabel,
+			RowLabel:        seat.RowLabel,
+			SeatNumber:      seat.SeatNumber,
+			SeatType:        seat.SeatType,
+			XPosition:       seat.XPosition,
+			YPosition:       seat.YPosition,
+			Status:          seat.Status,
+			PriceCents:      apiversion.Cents(seat.Price),
+			Fare:            seat.Fare,
+			CompanionSeatID: seat.CompanionSeatID,
+		})
+	}
+
+	return &SeatMapResponseV2{
+		ShowtimeID:  r.ShowtimeID,
+		SalesState:  r.SalesState,
+		SalesOpenAt: r.SalesOpenAt,
+		Capacity:    r.Capacity,
+		Available:   r.Available,
+		SoldOut:     r.SoldOut,
+		Seats:       seats,
+	}
+}
+
+// BookingSummaryResponse is a booking in the customer's booking history
+type BookingSummaryResponse struct {
+	ID               uuid.UUID  `json:"id" swaggertype:"string" format:"uuid"`
+	BookingReference string     `json:"booking_reference"`
+	ShowtimeID       uuid.UUID  `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	NumTickets       int        `json:"num_tickets"`
+	BookingStatus    string     `json:"booking_status"`
+	PaymentStatus    string     `json:"payment_status"`
+	Total            float64    `json:"total"`
+	BookedAt         time.Time  `json:"booked_at"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	TicketURL        *string    `json:"ticket_url,omitempty"`
+	CanCancel        *bool      `json:"can_cancel,omitempty"`     // booking history only: whether it can be cancelled now
+	RefundPercent    *float64   `json:"refund_percent,omitempty"` // booking history only: share of a paid booking refunded if cancelled now
+}
+
+// BookingHistoryParams filters the signed-in user's booking history
+type BookingHistoryParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=upcoming past cancelled"`
+	DateFrom string `form:"date_from" validate:"omitempty,datetime=2006-01-02"` // first show date
+	DateTo   string `form:"date_to" validate:"omitempty,datetime=2006-01-02"`   // last show date
+}
+
+func toBookingSummary(b *entity.Booking) BookingSummaryResponse {
+	return BookingSummaryResponse{
+		ID:               b.ID,
+		BookingReference: b.BookingReference,
+		ShowtimeID:       b.ShowtimeID,
+		NumTickets:       b.NumTickets,
+		BookingStatus:    string(b.BookingStatus),
+		PaymentStatus:    string(b.PaymentStatus),
+		Total:            b.FinalAmount,
+		BookedAt:         b.BookedAt,
+		ConfirmedAt:      b.ConfirmedAt,
+		TicketURL:        b.TicketURL,
+	}
+}
+
+// CancelBookingRequest represents a request to cancel a booking. Guests
+// without an account prove ownership with the reference and email.
+type CancelBookingRequest struct {
+	BookingReference string `json:"booking_reference" validate:"omitempty,max=32"`
+	Email            string `json:"email" validate:"omitempty,email"`
+	Reason           string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// CancelBookingResponse is a cancelled booking and what was refunded
+type CancelBookingResponse struct {
+	BookingSummaryResponse
+	RefundAmount float64               `json:"refund_amount"`
+	RefundPolicy *RefundPolicyResponse `json:"refund_policy,omitempty"` // only for paid bookings
+}
+
+// RefundPolicyResponse is the cancellation policy window that set the refund
+type RefundPolicyResponse struct {
+	Window        string    `json:"window"`
+	RefundPercent float64   `json:"refund_percent"`
+	AppliesUntil  time.Time `json:"applies_until"` // the next window, if any, starts here
+}
+
+// BookingDetailResponse is a booking as shown to its owner
+type BookingDetailResponse struct {
+	ID               uuid.UUID  `json:"id" swaggertype:"string" format:"uuid"`
+	BookingReference string     `json:"booking_reference"`
+	BookingStatus    string     `json:"booking_status"`
+	PaymentStatus    string     `json:"payment_status"`
+	ShowtimeID       uuid.UUID  `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	MovieTitle       string     `json:"movie_title"`
+	CinemaName       string     `json:"cinema_name"`
+	ScreenName       string     `json:"screen_name"`
+	StartsAt         time.Time  `json:"starts_at"`
+	Seats            []string   `json:"seats"`
+	NumTickets       int        `json:"num_tickets"`
+	Subtotal         float64    `json:"subtotal"`
+	Discount         float64    `json:"discount,omitempty"`
+	PointsDiscount   float64    `json:"points_discount,omitempty"`
+	Fee              float64    `json:"fee"`
+	Tax              float64    `json:"tax"`
+	Total            float64    `json:"total"`
+	BookedAt         time.Time  `json:"booked_at"`
+	PayBy            *time.Time `json:"pay_by,omitempty"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	RefundAmount     *float64   `json:"refund_amount,omitempty"`
+	TicketURL        *string    `json:"ticket_url,omitempty"`
+}
+
+// seatLabels returns the labels of a booking's seats, e.g. "F12"
+func seatLabels(b *entity.Booking) []string {
+	seats := make([]string, 0, len(b.BookingSeats))
+	for _, bs := range b.BookingSeats {
+		seats = append(seats, fmt.Sprintf("%s%d", bs.Seat.RowLabel, bs.Seat.SeatNumber))
+	}
+	return seats
+}
+
+func toBookingDetail(b *entity.Booking) *BookingDetailResponse {
+	showtime := b.Showtime
+	seats := seatLabels(b)
+
+	var payBy *time.Time
+	if b.BookingStatus == entity.BookingPending {
+		payBy = b.ExpiresAt
+	}
+
+	return &BookingDetailResponse{
+		ID:               b.ID,
+		BookingReference: b.BookingReference,
+		BookingStatus:    string(b.BookingStatus),
+		PaymentStatus:    string(b.PaymentStatus),
+		ShowtimeID:       b.ShowtimeID,
+		MovieTitle:       showtime.Movie.Title,
+		CinemaName:       showtime.Cinema.Name,
+		ScreenName:       showtime.Screen.Name,
+		StartsAt:         showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:            seats,
+		NumTickets:       b.NumTickets,
+		Subtotal:         b.SubtotalAmount,
+		Discount:         b.DiscountAmount,
+		PointsDiscount:   b.PointsDiscountAmount,
+		Fee:              b.FeeAmount,
+		Tax:              b.TaxAmount,
+		Total:            b.FinalAmount,
+		BookedAt:         b.BookedAt,
+		PayBy:            payBy,
+		ConfirmedAt:      b.ConfirmedAt,
+		CancelledAt:      b.CancelledAt,
+		RefundAmount:     b.RefundAmount,
+		TicketURL:        b.TicketURL,
+	}
+}
+
+// CheckInRequest is a ticket scanned at the door, or its booking reference
+// typed in when the QR code cannot be read. A typed reference is not
+// signed, so it is only accepted for the show being admitted.
+type CheckInRequest struct {
+	Payload          string     `json:"payload" validate:"required_without=BookingReference,excluded_with=BookingReference,max=128"` // the QR code's content
+	BookingReference string     `json:"booking_reference" validate:"max=32"`
+	ShowtimeID       *uuid.UUID `json:"showtime_id" validate:"required_with=BookingReference" swaggertype:"string" format:"uuid"` // the show being admitted; the ticket must be for it
+}
+
+// SearchBookingsParams filters the staff booking search. Managers and
+// staff must pass one of their cinemas.
+type SearchBookingsParams struct {
+	Page             int        `form:"page,default=1"`
+	Limit            int        `form:"limit,default=20"`
+	CinemaID         string     `form:"cinema_id" validate:"omitempty,uuid"`
+	BookingReference string     `form:"booking_reference" validate:"omitempty,max=32"`
+	Email            string     `form:"email" validate:"omitempty,max=255"` // guest or account email
+	ShowtimeID       string     `form:"showtime_id" validate:"omitempty,uuid"`
+	BookingStatus    string     `form:"booking_status" validate:"omitempty,oneof=PENDING CONFIRMED COMPLETED CANCELLED REFUNDED EXPIRED"`
+	PaymentStatus    string     `form:"payment_status" validate:"omitempty,oneof=PENDING PAID FAILED REFUNDED CANCELLED"`
+	BookedFrom       *time.Time `form:"booked_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	BookedTo         *time.Time `form:"booked_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort             string     `form:"sort,default=-booked_at" validate:"oneof=booked_at -booked_at"` // -booked_at is newest first
+}
+
+// StaffBookingResponse is a booking as shown to cinema staff, with what
+// the box office needs to serve the customer
+type StaffBookingResponse struct {
+	ID               uuid.UUID               `json:"id" swaggertype:"string" format:"uuid"`
+	BookingReference string                  `json:"booking_reference"`
+	BookingStatus    string                  `json:"booking_status"`
+	SalesChannel     string                  `json:"sales_channel"`
+	Customer         BookingCustomerResponse `json:"customer"`
+	ShowtimeID       uuid.UUID               `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	MovieTitle       string                  `json:"movie_title"`
+	CinemaName       string                  `json:"cinema_name"`
+	ScreenName       string                  `json:"screen_name"`
+	StartsAt         time.Time               `json:"starts_at"`
+	Seats            []string                `json:"seats"`
+	NumTickets       int                     `json:"num_tickets"`
+	Payment          BookingPaymentSummary   `json:"payment"`
+	BookedAt         time.Time               `json:"booked_at"`
+	ConfirmedAt      *time.Time              `json:"confirmed_at,omitempty"`
+	CancelledAt      *time.Time              `json:"cancelled_at,omitempty"`
+	CheckedInAt      *time.Time              `json:"checked_in_at,omitempty"`
+	CheckedInBy      *uuid.UUID              `json:"checked_in_by,omitempty" swaggertype:"string" format:"uuid"` // staff user who scanned the ticket
+}
+
+// BookingCustomerResponse is who made a booking: an account, or a guest
+type BookingCustomerResponse struct {
+	UserID *uuid.UUID `json:"user_id,omitempty" swaggertype:"string" format:"uuid"` // empty for guests
+	Name   string     `json:"name"`
+	Email  string     `json:"email"`
+	Phone  *string    `json:"phone,omitempty"`
+}
+
+// BookingPaymentSummary is what a booking costs and how it was paid. The
+// gateway fields come from the booking's latest payment, if any.
+type BookingPaymentSummary struct {
+	Status   string  `json:"status"`
+	Method   *string `json:"method,omitempty"`
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount,omitempty"`
+	// PointsDiscount is what redeemed loyalty points took off
+	PointsDiscount float64    `json:"points_discount,omitempty"`
+	Fee            float64    `json:"fee"`
+	Total          float64    `json:"total"`
+	PromoCode      *string    `json:"promo_code,omitempty"`
+	RefundAmount   *float64   `json:"refund_amount,omitempty"`
+	Reference      string     `json:"payment_reference,omitempty"`
+	Gateway        string     `json:"gateway,omitempty"`
+	CardLastFour   *string    `json:"card_last_four,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+}
+
+func toStaffBooking(b *entity.Booking) StaffBookingResponse {
+	showtime := b.Showtime
+
+	customer := BookingCustomerResponse{
+		UserID: b.UserID,
+		Name:   b.GuestName,
+		Email:  b.GuestEmail,
+		Phone:  b.GuestPhone,
+	}
+	if b.User != nil {
+		customer.Name = strings.TrimSpace(b.User.FirstName + " " + b.User.LastName)
+		customer.Email = b.User.Email
+		customer.Phone = b.User.Phone
+	}
+
+	payment := BookingPaymentSummary{
+		Status:         string(b.PaymentStatus),
+		Subtotal:       b.SubtotalAmount,
+		Discount:       b.DiscountAmount,
+		PointsDiscount: b.PointsDiscountAmount,
+		Fee:            b.FeeAmount,
+		Total:          b.FinalAmount,
+		PromoCode:      b.PromoCode,
+		RefundAmount:   b.RefundAmount,
+	}
+	if b.PaymentMethod != nil {
+		method := string(*b.PaymentMethod)
+		payment.Method = &method
+	}
+	if n := len(b.Payments); n > 0 {
+		latest := b.Payments[n-1]
+		payment.Reference = latest.PaymentReference
+		payment.Gateway = latest.PaymentGateway
+		payment.CardLastFour = latest.CardLastFour
+		payment.PaidAt = latest.PaidAt
+	}
+
+	return StaffBookingResponse{
+		ID:               b.ID,
+		BookingReference: b.BookingReference,
+		BookingStatus:    string(b.BookingStatus),
+		SalesChannel:     string(b.SalesChannel),
+		Customer:         customer,
+		ShowtimeID:       b.ShowtimeID,
+		MovieTitle:       showtime.Movie.Title,
+		CinemaName:       showtime.Cinema.Name,
+		ScreenName:       showtime.Screen.Name,
+		StartsAt:         showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:            seatLabels(b),
+		NumTickets:       b.NumTickets,
+		Payment:          payment,
+		BookedAt:         b.BookedAt,
+		ConfirmedAt:      b.ConfirmedAt,
+		CancelledAt:      b.CancelledAt,
+		CheckedInAt:      b.CheckedInAt,
+		CheckedInBy:      b.CheckedInBy,
+	}
+}
@@ -0,0 +1,114 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// HoldSeatsForGroup holds a participant's seats for a group booking. The
+// hold is checked like any other but can only be booked by finalizing the
+// group.
+func (s *Service) HoldSeatsForGroup(ctx context.Context, userID, groupBookingID uuid.UUID, req HoldSeatsRequest) (*entity.SeatHold, error) {
+	return s.holdSeats(ctx, userID, req, &groupBookingID)
+}
+
+// ReleaseGroupHold frees the seats of a group booking hold that could not
+// be added to the group
+func (s *Service) ReleaseGroupHold(ctx context.Context, hold *entity.SeatHold) {
+	released, err := s.holdRepo.Release(ctx, hold)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to release group booking hold", zap.String("hold_id", hold.ID), zap.Error(err))
+		return
+	}
+	if released > 0 {
+		s.publishReleased(ctx, hold.ShowtimeID, hold.SeatIDs())
+	}
+}
+
+// BookGroup turns the holds pooled by a group booking into one pending
+// booking for the organizer. Seats, prices and fees are taken from each
+// hold as quoted; the booking is due before the first hold would have
+// expired.
+func (s *Service) BookGroup(ctx context.Context, organizerID uuid.UUID, holds []*entity.SeatHold) (*ConfirmBookingResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	if len(holds) == 0 {
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "no seats are held for the group any more")
+	}
+
+	var seats []*entity.BookingSeat
+	var devices []entity.DeviceRequest
+	var subtotal, fee float64
+	payBy := holds[0].ExpiresAt
+	for _, hold := range holds {
+		if hold.ShowtimeID != holds[0].ShowtimeID {
+			return nil, apperrors.ErrBadRequest("group holds are for different showtimes")
+		}
+		for _, held := range hold.Seats {
+			seats = append(seats, &entity.BookingSeat{SeatID: held.SeatID, Price: held.Price, Fare: held.Fare})
+		}
+		devices = append(devices, hold.Devices...)
+		subtotal += hold.Subtotal
+		fee += hold.Fee
+		if hold.ExpiresAt.Before(payBy) {
+			payBy = hold.ExpiresAt
+		}
+	}
+	subtotal, fee = entity.RoundCents(subtotal), entity.RoundCents(fee)
+
+	now := time.Now()
+	booking := &entity.Booking{
+		BookingReference: authinfra.GenerateBookingReference(),
+		UserID:           &organizerID,
+		ShowtimeID:       holds[0].ShowtimeID,
+		NumTickets:       len(seats),
+		SubtotalAmount:   subtotal,
+		FeeAmount:        fee,
+		FinalAmount:      entity.RoundCents(subtotal + fee),
+		BookingStatus:    entity.BookingPending,
+		PaymentStatus:    entity.PaymentPending,
+		SalesChannel:     entity.ChannelOnline,
+		BookedAt:         now,
+		ExpiresAt:        &payBy,
+	}
+
+	if err := s.createWithSeats(ctx, booking, seats); err != nil {
+		log.Warn("failed to book group holds", zap.Int("holds", len(holds)), zap.Error(err))
+		return nil, err
+	}
+	s.metrics.BookingCreated(ctx)
+
+	if len(devices) > 0 {
+		if err := s.deviceRepo.Reserve(ctx, booking, devices); err != nil {
+			log.Warn("failed to reserve assistive devices, staff follow-up required",
+				zap.String("booking_reference", booking.BookingReference),
+				zap.Error(err),
+			)
+		}
+	}
+
+	for _, hold := range holds {
+		s.publishSeats(ctx, hold.ShowtimeID, hold.SeatIDs(), SeatStatusBooked)
+		// The seats are booked now; a hold left behind only expires on its own
+		if err := s.holdRepo.Delete(ctx, hold); err != nil {
+			log.Warn("failed to release hold", zap.String("hold_id", hold.ID), zap.Error(err))
+		}
+	}
+
+	log.Info("booking created from group holds",
+		zap.String("booking_reference", booking.BookingReference),
+		zap.Int("holds", len(holds)),
+		zap.Int("seats", booking.NumTickets),
+	)
+
+	res := toConfirmBookingResponse(booking)
+	s.startPayment(ctx, booking, res)
+	return res, nil
+}
@@ -0,0 +1,188 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memCreated records the bookings created with their seats. The first
+// conflicts writes lose the race for the showtime's version.
+type memCreated struct {
+	repository.BookingRepository
+	created   []*entity.Booking
+	seats     [][]*entity.BookingSeat
+	conflicts int
+	attempts  int
+}
+
+func (m *memCreated) CreateWithSeats(_ context.Context, booking *entity.Booking, seats []*entity.BookingSeat) error {
+	m.attempts++
+	if m.attempts <= m.conflicts {
+		return apperrors.New(apperrors.CodeVersionConflict, "showtime seats changed concurrently")
+	}
+	booking.ID = uuid.New()
+	m.created = append(m.created, booking)
+	m.seats = append(m.seats, seats)
+	return nil
+}
+
+// memDeletedHolds records the holds deleted once booked
+type memDeletedHolds struct {
+	repository.SeatHoldRepository
+	deleted []string
+}
+
+func (m *memDeletedHolds) Delete(_ context.Context, hold *entity.SeatHold) error {
+	m.deleted = append(m.deleted, hold.ID)
+	return nil
+}
+
+// flakyPayments fails the first failures payments it is asked to start
+type flakyPayments struct {
+	failures int
+	started  []string
+}
+
+func (p *flakyPayments) StartPayment(_ context.Context, booking *entity.Booking) (string, error) {
+	if p.failures > 0 {
+		p.failures--
+		return "", errors.New("gateway unavailable")
+	}
+	p.started = append(p.started, booking.BookingReference)
+	return "https://checkout.test/" + booking.BookingReference, nil
+}
+
+func TestBookGroup(t *testing.T) {
+	ctx := context.Background()
+	bookings, holds, feed, created := &memCreated{}, &memDeletedHolds{}, &memSeatFeed{}, &memOutbox{}
+	m, err := metrics.New(metrics.Config{ServiceName: "booking-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	svc := NewService(holds, nil, nil, bookings, nil, nil, nil, nil, nil, nil, nil, feed, nil, nil, nil, m, inlineTx{}, outbox.New(created),
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	showtimeID, organizerID := uuid.New(), uuid.New()
+	soonest := time.Now().Add(5 * time.Minute)
+	hold := func(id string, expiresAt time.Time, prices ...float64) *entity.SeatHold {
+		h := &entity.SeatHold{ID: id, ShowtimeID: showtimeID, UserID: uuid.New(), ExpiresAt: expiresAt, Fee: 0.75 * float64(len(prices))}
+		for _, price := range prices {
+			h.Seats = append(h.Seats, entity.HeldSeat{SeatID: uuid.New(), Price: price})
+			h.Subtotal += price
+		}
+		return h
+	}
+	pooled := []*entity.SeatHold{
+		hold("hold-1", soonest.Add(time.Minute), 12.5, 12.5),
+		hold("hold-2", soonest, 9.1),
+	}
+
+	res, err := svc.BookGroup(ctx, organizerID, pooled)
+	if err != nil {
+		t.Fatalf("BookGroup: %v", err)
+	}
+	if len(bookings.created) != 1 {
+		t.Fatalf("%d bookings created, want one for the group", len(bookings.created))
+	}
+	booking := bookings.created[0]
+	if *booking.UserID != organizerID || booking.NumTickets != 3 || len(bookings.seats[0]) != 3 {
+		t.Errorf("booking for %v with %d tickets, want the organizer's with 3", *booking.UserID, booking.NumTickets)
+	}
+	if res.Subtotal != 34.1 || res.Fee != 2.25 || res.Total != 36.35 {
+		t.Errorf("priced %v + %v = %v, want 34.1 + 2.25 = 36.35", res.Subtotal, res.Fee, res.Total)
+	}
+	if res.PayBy == nil || !res.PayBy.Equal(soonest) {
+		t.Errorf("pay by %v, want the first hold's expiry %v", res.PayBy, soonest)
+	}
+	if len(holds.deleted) != 2 {
+		t.Errorf("%d holds deleted, want both", len(holds.deleted))
+	}
+	// BookingCreated goes out through the outbox with the booking
+	if got := created.decoded(t, events.BookingCreatedEvent); len(got) != 1 || got[0].(events.BookingCreated).BookingID != booking.ID {
+		t.Errorf("outbox holds %v, want BookingCreated for the booking", got)
+	}
+	if got := feed.published[showtimeID]; len(got) != 3 || got[0].Status != SeatStatusBooked {
+		t.Errorf("published %+v, want 3 booked seats", got)
+	}
+
+	// Holds for another showtime are never pooled
+	stray := hold("hold-3", soonest, 10)
+	stray.ShowtimeID = uuid.New()
+	if _, err := svc.BookGroup(ctx, organizerID, []*entity.SeatHold{pooled[0], stray}); !apperrors.Is(err, apperrors.CodeBadRequest) {
+		t.Errorf("mixed showtimes: %v, want %s", err, apperrors.CodeBadRequest)
+	}
+	if _, err := svc.BookGroup(ctx, organizerID, nil); !apperrors.Is(err, apperrors.CodeBookingExpired) {
+		t.Errorf("no holds: %v, want %s", err, apperrors.CodeBookingExpired)
+	}
+}
+
+func TestBookGroupRetriesVersionConflicts(t *testing.T) {
+	m, err := metrics.New(metrics.Config{ServiceName: "booking-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	book := func(conflicts int) (*memCreated, error) {
+		bookings := &memCreated{conflicts: conflicts}
+		svc := NewService(&memDeletedHolds{}, nil, nil, bookings, nil, nil, nil, nil, nil, nil, nil, &memSeatFeed{}, nil, nil, nil, m, inlineTx{}, outbox.New(&memOutbox{}),
+			config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+		hold := &entity.SeatHold{ID: "hold-1", ShowtimeID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute),
+			Seats: []entity.HeldSeat{{SeatID: uuid.New(), Price: 10}}, Subtotal: 10}
+		_, err := svc.BookGroup(context.Background(), uuid.New(), []*entity.SeatHold{hold})
+		return bookings, err
+	}
+
+	bookings, err := book(bookingVersionRetries - 1)
+	if err != nil || len(bookings.created) != 1 {
+		t.Errorf("after %d lost races: %v with %d bookings, want the booking", bookingVersionRetries-1, err, len(bookings.created))
+	}
+	bookings, err = book(bookingVersionRetries)
+	if !apperrors.Is(err, apperrors.CodeVersionConflict) || bookings.attempts != bookingVersionRetries {
+		t.Errorf("after %d attempts: %v, want %s", bookings.attempts, err, apperrors.CodeVersionConflict)
+	}
+}
+
+func TestBookGroupKeepsTheBookingWhenPaymentFails(t *testing.T) {
+	m, err := metrics.New(metrics.Config{ServiceName: "booking-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	bookings, holds, payments := &memCreated{}, &memDeletedHolds{}, &flakyPayments{failures: 1}
+	svc := NewService(holds, nil, nil, bookings, nil, nil, nil, nil, nil, nil, nil, &memSeatFeed{}, nil, payments, nil, m, inlineTx{}, outbox.New(&memOutbox{}),
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	hold := &entity.SeatHold{ID: "hold-1", ShowtimeID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute),
+		Seats: []entity.HeldSeat{{SeatID: uuid.New(), Price: 10}}, Subtotal: 10}
+
+	res, err := svc.BookGroup(context.Background(), uuid.New(), []*entity.SeatHold{hold})
+	if err != nil {
+		t.Fatalf("BookGroup with the gateway down: %v", err)
+	}
+	if len(bookings.created) != 1 || res.BookingReference != bookings.created[0].BookingReference {
+		t.Fatalf("%d bookings created, want the pending booking returned", len(bookings.created))
+	}
+	if res.CheckoutURL != "" || res.PaymentError == "" {
+		t.Errorf("checkout URL %q, payment error %q, want only the error", res.CheckoutURL, res.PaymentError)
+	}
+
+	// Once the gateway answers the response carries the checkout page
+	res, err = svc.BookGroup(context.Background(), uuid.New(), []*entity.SeatHold{hold})
+	if err != nil {
+		t.Fatalf("BookGroup: %v", err)
+	}
+	if res.CheckoutURL != "https://checkout.test/"+res.BookingReference || res.PaymentError != "" {
+		t.Errorf("checkout URL %q, payment error %q", res.CheckoutURL, res.PaymentError)
+	}
+}
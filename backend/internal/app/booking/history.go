@@ -0,0 +1,178 @@
+package booking
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ListUserBookings returns a page of the user's bookings matching params,
+// newest first, starting after cursor, and the cursor of the next page. An
+// empty cursor starts at the newest booking; the next cursor is empty on
+// the last page.
+func (s *Service) ListUserBookings(ctx context.Context, userID uuid.UUID, params BookingHistoryParams, cursor string, limit int) ([]BookingSummaryResponse, string, error) {
+	filter, err := historyFilter(userID, params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var after *repository.BookingCursor
+	if cursor != "" {
+		decoded, ok := decodeBookingCursor(cursor)
+		if !ok {
+			return nil, "", apperrors.ErrBadRequest("invalid cursor")
+		}
+		after = &decoded
+	}
+
+	// One extra row tells whether another page follows
+	bookings, err := s.bookingRepo.ListAfter(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(bookings) > limit {
+		bookings = bookings[:limit]
+		last := bookings[limit-1]
+		next = encodeBookingCursor(repository.BookingCursor{BookedAt: last.BookedAt, ID: last.ID})
+	}
+
+	responses, err := s.historySummaries(ctx, bookings)
+	if err != nil {
+		return nil, "", err
+	}
+	return responses, next, nil
+}
+
+// ListUserBookingsPage returns a numbered page of the user's bookings
+// matching params, newest first, with their total. Kept for clients that
+// page by number.
+func (s *Service) ListUserBookingsPage(ctx context.Context, userID uuid.UUID, params BookingHistoryParams, page, limit int) ([]BookingSummaryResponse, int64, error) {
+	filter, err := historyFilter(userID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bookings, total, err := s.bookingRepo.List(ctx, filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses, err := s.historySummaries(ctx, bookings)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// historyFilter turns the booking history params into a filter on the
+// user's bookings
+func historyFilter(userID uuid.UUID, params BookingHistoryParams) (repository.BookingFilter, error) {
+	filter := repository.BookingFilter{UserID: &userID, Period: repository.BookingPeriod(params.Status)}
+	if params.DateFrom != "" {
+		from, err := time.Parse(time.DateOnly, params.DateFrom)
+		if err != nil {
+			return filter, apperrors.ErrValidation("date_from must be a date like 2026-01-31")
+		}
+		filter.ShowDateFrom = &from
+	}
+	if params.DateTo != "" {
+		to, err := time.Parse(time.DateOnly, params.DateTo)
+		if err != nil {
+			return filter, apperrors.ErrValidation("date_to must be a date like 2026-01-31")
+		}
+		filter.ShowDateTo = &to
+	}
+	if filter.ShowDateFrom != nil && filter.ShowDateTo != nil && filter.ShowDateTo.Before(*filter.ShowDateFrom) {
+		return filter, apperrors.ErrValidation("date_from must not be after date_to")
+	}
+	return filter, nil
+}
+
+// historySummaries summarises bookings for their owner with whether each
+// can be cancelled now and what share a cancellation would refund
+func (s *Service) historySummaries(ctx context.Context, bookings []*entity.Booking) ([]BookingSummaryResponse, error) {
+	ids := make([]uuid.UUID, 0, len(bookings))
+	for _, b := range bookings {
+		if b.CanCancel() && b.IsPaid() {
+			ids = append(ids, b.ShowtimeID)
+		}
+	}
+	showtimes, err := s.showtimeRepo.GetWithCinemas(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entity.Showtime, len(showtimes))
+	for _, st := range showtimes {
+		byID[st.ID] = st
+	}
+
+	now := time.Now()
+	responses := make([]BookingSummaryResponse, 0, len(bookings))
+	for _, b := range bookings {
+		summary := toBookingSummary(b)
+		canCancel, refundPercent := s.cancellationTerms(b, byID[b.ShowtimeID], now)
+		summary.CanCancel = &canCancel
+		summary.RefundPercent = &refundPercent
+		responses = append(responses, summary)
+	}
+	return responses, nil
+}
+
+// cancellationTerms reports whether a booking could be cancelled at now and
+// the refund percentage that would apply, by the rules of CancelBooking.
+// Unpaid bookings can be cancelled with nothing to refund; paid ones while a
+// window of the cancellation policy still applies to their showtime.
+func (s *Service) cancellationTerms(b *entity.Booking, showtime *entity.Showtime, now time.Time) (bool, float64) {
+	if !b.CanCancel() {
+		return false, 0
+	}
+	if !b.IsPaid() {
+		return true, 0
+	}
+	if showtime == nil {
+		return false, 0
+	}
+	window, ok := s.cancellationWindow(showtime.StartsAt(showtime.Cinema.Location()), now)
+	if !ok {
+		return false, 0
+	}
+	return true, window.RefundPercent
+}
+
+// encodeBookingCursor encodes the position as "booked_at:id", booked_at in
+// RFC 3339 with nanoseconds
+func encodeBookingCursor(c repository.BookingCursor) string {
+	raw := c.BookedAt.UTC().Format(time.RFC3339Nano) + ":" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeBookingCursor(cursor string) (repository.BookingCursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return repository.BookingCursor{}, false
+	}
+
+	// The time has colons of its own; the ID has none
+	sep := strings.LastIndex(string(raw), ":")
+	if sep < 0 {
+		return repository.BookingCursor{}, false
+	}
+	bookedAt, err := time.Parse(time.RFC3339Nano, string(raw[:sep]))
+	if err != nil {
+		return repository.BookingCursor{}, false
+	}
+	id, err := uuid.Parse(string(raw[sep+1:]))
+	if err != nil {
+		return repository.BookingCursor{}, false
+	}
+	return repository.BookingCursor{BookedAt: bookedAt, ID: id}, true
+}
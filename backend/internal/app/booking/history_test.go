@@ -0,0 +1,233 @@
+package booking
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memHistory is a user's booking history kept newest first, as the
+// repository orders it
+type memHistory struct {
+	repository.BookingRepository
+	bookings []*entity.Booking
+	filter   repository.BookingFilter // the last one listed with
+}
+
+func newMemHistory(userID uuid.UUID, n int) *memHistory {
+	m := &memHistory{}
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := range n {
+		// Pairs of bookings share a booked_at
+		m.bookings = append(m.bookings, &entity.Booking{ID: uuid.New(), UserID: &userID, BookedAt: base.Add(time.Duration(i/2) * time.Hour)})
+	}
+	slices.SortFunc(m.bookings, func(a, b *entity.Booking) int {
+		if c := b.BookedAt.Compare(a.BookedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(b.ID[:], a.ID[:])
+	})
+	return m
+}
+
+func (m *memHistory) List(_ context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	m.filter = filter
+	end := min(offset+limit, len(m.bookings))
+	return m.bookings[min(offset, end):end], int64(len(m.bookings)), nil
+}
+
+func (m *memHistory) ListAfter(_ context.Context, filter repository.BookingFilter, after *repository.BookingCursor, limit int) ([]*entity.Booking, error) {
+	m.filter = filter
+	start := 0
+	if after != nil {
+		// The first booking below (booked_at, id)
+		start = slices.IndexFunc(m.bookings, func(b *entity.Booking) bool {
+			if c := b.BookedAt.Compare(after.BookedAt); c != 0 {
+				return c < 0
+			}
+			return slices.Compare(b.ID[:], after.ID[:]) < 0
+		})
+		if start < 0 {
+			return nil, nil
+		}
+	}
+	return m.bookings[start:min(start+limit, len(m.bookings))], nil
+}
+
+// memShowtimeCinemas serves showtimes with their cinema
+type memShowtimeCinemas struct {
+	repository.ShowtimeRepository
+	showtimes map[uuid.UUID]*entity.Showtime
+}
+
+func (m *memShowtimeCinemas) GetWithCinemas(_ context.Context, ids []uuid.UUID) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	for _, id := range ids {
+		if showtime, ok := m.showtimes[id]; ok {
+			showtimes = append(showtimes, showtime)
+		}
+	}
+	return showtimes, nil
+}
+
+func newHistoryService(history *memHistory) *Service {
+	return newHistoryServiceWith(history, &memShowtimeCinemas{}, config.BookingConfig{})
+}
+
+func newHistoryServiceWith(history *memHistory, showtimes *memShowtimeCinemas, cfg config.BookingConfig) *Service {
+	return NewService(nil, showtimes, nil, history, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		cfg, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestListUserBookingsCursorMatchesOffset(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	svc := newHistoryService(newMemHistory(userID, 11))
+
+	ids := func(bookings []BookingSummaryResponse) []uuid.UUID {
+		var ids []uuid.UUID
+		for _, b := range bookings {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	cursor := ""
+	for page := 1; page <= 3; page++ {
+		byOffset, total, err := svc.ListUserBookingsPage(ctx, userID, BookingHistoryParams{}, page, 4)
+		if err != nil {
+			t.Fatalf("ListUserBookingsPage: %v", err)
+		}
+		if total != 11 {
+			t.Errorf("total = %d, want 11", total)
+		}
+		byCursor, next, err := svc.ListUserBookings(ctx, userID, BookingHistoryParams{}, cursor, 4)
+		if err != nil {
+			t.Fatalf("ListUserBookings: %v", err)
+		}
+		if !slices.Equal(ids(byCursor), ids(byOffset)) {
+			t.Fatalf("page %d: cursor %v, offset %v", page, ids(byCursor), ids(byOffset))
+		}
+		if (next == "") != (page == 3) {
+			t.Errorf("page %d: next cursor %q", page, next)
+		}
+		cursor = next
+	}
+}
+
+func TestBookingCursorEncoding(t *testing.T) {
+	want := repository.BookingCursor{
+		BookedAt: time.Date(2026, 10, 16, 9, 30, 15, 123456789, time.FixedZone("ICT", 7*3600)),
+		ID:       uuid.New(),
+	}
+	got, ok := decodeBookingCursor(encodeBookingCursor(want))
+	if !ok || !got.BookedAt.Equal(want.BookedAt) || got.ID != want.ID {
+		t.Errorf("round trip = %+v, %v, want %+v", got, ok, want)
+	}
+
+	svc := newHistoryService(newMemHistory(uuid.New(), 1))
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeBookingCursor(want)[:10]} {
+		if _, _, err := svc.ListUserBookings(context.Background(), uuid.New(), BookingHistoryParams{}, cursor, 4); !apperrors.Is(err, apperrors.CodeBadRequest) {
+			t.Errorf("cursor %q: %v, want %s", cursor, err, apperrors.CodeBadRequest)
+		}
+	}
+}
+
+func TestBookingHistoryFilters(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	history := newMemHistory(userID, 1)
+	svc := newHistoryService(history)
+
+	params := BookingHistoryParams{Status: "upcoming", DateFrom: "2026-10-01", DateTo: "2026-10-31"}
+	for name, list := range map[string]func() error{
+		"numbered": func() error { _, _, err := svc.ListUserBookingsPage(ctx, userID, params, 1, 20); return err },
+		"cursor":   func() error { _, _, err := svc.ListUserBookings(ctx, userID, params, "", 20); return err },
+	} {
+		history.filter = repository.BookingFilter{}
+		if err := list(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		f := history.filter
+		if *f.UserID != userID || f.Period != repository.BookingsUpcoming ||
+			!f.ShowDateFrom.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || !f.ShowDateTo.Equal(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s: listed with %+v", name, f)
+		}
+	}
+
+	for _, bad := range []BookingHistoryParams{
+		{DateFrom: "2026-10-32"},
+		{DateTo: "31/10/2026"},
+		{DateFrom: "2026-10-31", DateTo: "2026-10-01"},
+	} {
+		if _, _, err := svc.ListUserBookingsPage(ctx, userID, bad, 1, 20); !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%+v: %v, want %s", bad, err, apperrors.CodeValidation)
+		}
+	}
+}
+
+func TestBookingHistoryCancellationTerms(t *testing.T) {
+	userID := uuid.New()
+	soon, later := uuid.New(), uuid.New()
+	// Shows in a cinema seven hours ahead of UTC, so the start is judged
+	// in its timezone
+	loc := time.FixedZone("ICT", 7*3600)
+	startingIn := func(id uuid.UUID, d time.Duration) *entity.Showtime {
+		start := time.Now().In(loc).Add(d).Truncate(time.Minute)
+		return &entity.Showtime{
+			ID:        id,
+			ShowDate:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+			StartTime: start.Format("15:04"),
+			Cinema:    entity.Cinema{Timezone: "Asia/Ho_Chi_Minh"},
+		}
+	}
+	showtimes := &memShowtimeCinemas{showtimes: map[uuid.UUID]*entity.Showtime{
+		soon:  startingIn(soon, 90*time.Minute),
+		later: startingIn(later, 72*time.Hour),
+	}}
+	booking := func(status entity.BookingStatus, payment entity.PaymentStatus, showtimeID uuid.UUID) *entity.Booking {
+		return &entity.Booking{ID: uuid.New(), UserID: &userID, ShowtimeID: showtimeID, BookingStatus: status, PaymentStatus: payment, BookedAt: time.Now()}
+	}
+	history := &memHistory{bookings: []*entity.Booking{
+		booking(entity.BookingPending, entity.PaymentPending, soon),
+		booking(entity.BookingConfirmed, entity.PaymentPaid, later),
+		booking(entity.BookingConfirmed, entity.PaymentPaid, soon),
+		booking(entity.BookingConfirmed, entity.PaymentPaid, uuid.New()),
+		booking(entity.BookingCancelled, entity.PaymentRefunded, later),
+	}}
+	svc := newHistoryServiceWith(history, showtimes, config.BookingConfig{CancellationWindows: []config.CancellationWindow{
+		{Before: 48 * time.Hour, RefundPercent: 100},
+		{Before: 2 * time.Hour, RefundPercent: 50},
+	}})
+
+	res, _, err := svc.ListUserBookingsPage(context.Background(), userID, BookingHistoryParams{}, 1, 20)
+	if err != nil {
+		t.Fatalf("ListUserBookingsPage: %v", err)
+	}
+	want := []struct {
+		name      string
+		canCancel bool
+		refund    float64
+	}{
+		{"unpaid", true, 0},
+		{"paid, days ahead", true, 100},
+		{"paid, inside the last window", false, 0},
+		{"paid, showtime not found", false, 0},
+		{"cancelled", false, 0},
+	}
+	for i, w := range want {
+		if *res[i].CanCancel != w.canCancel || *res[i].RefundPercent != w.refund {
+			t.Errorf("%s: can cancel %v with %v%% back, want %v with %v%%", w.name, *res[i].CanCancel, *res[i].RefundPercent, w.canCancel, w.refund)
+		}
+	}
+}
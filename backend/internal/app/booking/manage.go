@@ -0,0 +1,146 @@
+package booking
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/guestlookup"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Viewer is who is asking for a booking: a signed-in user, possibly an
+// admin or manager, or a guest proving ownership with the booking
+// reference and the email the booking was made with
+type Viewer struct {
+	UserID    *uuid.UUID
+	Admin     bool
+	Reference string
+	Email     string
+}
+
+// errBookingNotFound is returned both for missing bookings and for
+// bookings the viewer may not see, so the response does not tell whether
+// a booking ID exists
+func errBookingNotFound() error {
+	return apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+}
+
+// canAccess reports whether the viewer owns the booking or is an admin.
+// Guest proof is only accepted for bookings that do not belong to an
+// account; the booking ID it comes with keeps the reference and email from
+// being guessed the way the throttled guest lookup has to guard against.
+func (v Viewer) canAccess(booking *entity.Booking) bool {
+	switch {
+	case v.Admin:
+		return true
+	case booking.UserID != nil:
+		return v.UserID != nil && *booking.UserID == *v.UserID
+	case v.Reference == "" || v.Email == "":
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(v.Reference), booking.BookingReference) &&
+		guestlookup.EmailMatches(booking.GuestEmail, v.Email)
+}
+
+// GetBooking returns a booking to its owner or an admin
+func (s *Service) GetBooking(ctx context.Context, viewer Viewer, id uuid.UUID) (*BookingDetailResponse, error) {
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.canAccess(booking) {
+		return nil, errBookingNotFound()
+	}
+	return toBookingDetail(booking), nil
+}
+
+// CancelBooking cancels a booking for its owner or an admin and releases
+// its seats. Paid bookings are refunded under the cancellation policy and
+// cannot be cancelled once the showtime is closer than every window of it;
+// unpaid bookings have nothing to refund and can always be cancelled.
+func (s *Service) CancelBooking(ctx context.Context, viewer Viewer, id uuid.UUID, req CancelBookingRequest) (*CancelBookingResponse, error) {
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.canAccess(booking) {
+		return nil, errBookingNotFound()
+	}
+
+	var refund float64
+	var policy *RefundPolicyResponse
+	if booking.IsPaid() {
+		showtime := &booking.Showtime
+		startsAt := showtime.StartsAt(showtime.Cinema.Location())
+		window, ok := s.cancellationWindow(startsAt, time.Now())
+		if !ok {
+			return nil, apperrors.New(apperrors.CodeFailedPrecondition,
+				"paid bookings can no longer be cancelled this close to the showtime")
+		}
+		refund = entity.RoundCents(booking.RefundableAmount(showtime.Cinema.BookingFee()) * window.RefundPercent / 100)
+		policy = &RefundPolicyResponse{
+			Window:        window.Name,
+			RefundPercent: window.RefundPercent,
+			AppliesUntil:  startsAt.Add(-window.Before),
+		}
+	}
+
+	// The cancellation's side effects are published only once it commits
+	var cancelled *entity.Booking
+	err = s.tx.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if cancelled, err = s.bookingRepo.Cancel(ctx, id, refund); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, events.BookingCancelled{
+			BookingID:        cancelled.ID,
+			BookingReference: cancelled.BookingReference,
+			UserID:           cancelled.UserID,
+			ShowtimeID:       cancelled.ShowtimeID,
+			SeatIDs:          bookedSeatIDs(cancelled),
+			RefundAmount:     refund,
+			Reason:           req.Reason,
+			CancelledAt:      *cancelled.CancelledAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The cached booked seats still list the released seats
+	if err := s.holdRepo.InvalidateBookedSeats(ctx, cancelled.ShowtimeID); err != nil {
+		s.logger.Warn("failed to invalidate booked seats", zap.String("showtime_id", cancelled.ShowtimeID.String()), zap.Error(err))
+	}
+
+	s.publishReleased(ctx, cancelled.ShowtimeID, bookedSeatIDs(cancelled))
+	s.metrics.BookingCancelled(ctx)
+
+	return &CancelBookingResponse{
+		BookingSummaryResponse: toBookingSummary(cancelled),
+		RefundAmount:           refund,
+		RefundPolicy:           policy,
+	}, nil
+}
+
+// cancellationWindow returns the window of the cancellation policy that
+// applies to a cancellation at now: the one with the longest notice that
+// the showtime is still ahead by. It returns false when no window applies
+// any longer.
+func (s *Service) cancellationWindow(startsAt, now time.Time) (config.CancellationWindow, bool) {
+	notice := startsAt.Sub(now)
+	var applied config.CancellationWindow
+	found := false
+	for _, w := range s.cfg.CancellationWindows {
+		if notice >= w.Before && (!found || w.Before > applied.Before) {
+			applied, found = w, true
+		}
+	}
+	return applied, found
+}
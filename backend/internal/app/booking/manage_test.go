@@ -0,0 +1,393 @@
+package booking
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memBookings keeps bookings by ID
+type memBookings struct {
+	repository.BookingRepository
+	bookings map[uuid.UUID]*entity.Booking
+}
+
+func (m *memBookings) GetByID(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	booking, ok := m.bookings[id]
+	if !ok {
+		return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+	copied := *booking
+	return &copied, nil
+}
+
+func (m *memBookings) GetByIDWithDetails(ctx context.Context, id uuid.UUID) (*entity.Booking, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m *memBookings) Cancel(_ context.Context, id uuid.UUID, refund float64) (*entity.Booking, error) {
+	booking := m.bookings[id]
+	if !booking.CanCancel() {
+		return nil, apperrors.New(apperrors.CodeInvalidStatus, "booking cannot be cancelled")
+	}
+	now := time.Now()
+	booking.BookingStatus = entity.BookingCancelled
+	booking.CancelledAt = &now
+	if refund > 0 {
+		booking.PaymentStatus = entity.PaymentRefunded
+		booking.RefundAmount = &refund
+	}
+	copied := *booking
+	return &copied, nil
+}
+
+type manageFixture struct {
+	svc       *Service
+	bookings  *memBookings
+	holds     *memProbeHolds
+	feed      *memSeatFeed
+	metrics   *metrics.Metrics
+	owner     uuid.UUID
+	owned     *entity.Booking // belongs to owner
+	guest     *entity.Booking // made without an account
+	outbox    *memOutbox
+}
+
+func newManageFixture(t *testing.T) *manageFixture {
+	t.Helper()
+	f := &manageFixture{owner: uuid.New(), holds: &memProbeHolds{}, feed: &memSeatFeed{}, outbox: &memOutbox{}}
+	f.owned = &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK-OWNED1",
+		UserID:           &f.owner,
+		ShowtimeID:       uuid.New(),
+		BookingStatus:    entity.BookingPending,
+		PaymentStatus:    entity.PaymentPending,
+		BookingSeats:     []entity.BookingSeat{{SeatID: uuid.New()}, {SeatID: uuid.New()}},
+	}
+	f.guest = &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK-GUEST1",
+		ShowtimeID:       uuid.New(),
+		GuestEmail:       "guest@example.com",
+		BookingStatus:    entity.BookingPending,
+		PaymentStatus:    entity.PaymentPending,
+	}
+	f.bookings = &memBookings{bookings: map[uuid.UUID]*entity.Booking{f.owned.ID: f.owned, f.guest.ID: f.guest}}
+
+	m, err := metrics.New(metrics.Config{Enabled: true, ServiceName: "booking-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	f.metrics = m
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	f.svc = NewService(f.holds, nil, nil, f.bookings, &memBookedSeats{}, nil, nil, nil, nil, nil, nil, f.feed, nil, nil, nil, f.metrics, inlineTx{}, outbox.New(f.outbox),
+		config.BookingConfig{CancellationWindows: []config.CancellationWindow{
+			{Name: "full_refund", Before: 24 * time.Hour, RefundPercent: 100},
+			{Name: "partial_refund", Before: 2 * time.Hour, RefundPercent: 50},
+		}}, config.TicketConfig{}, log)
+	return f
+}
+
+// startsIn moves the owned booking's showtime to start after d, in a cinema
+// on UTC
+func (f *manageFixture) startsIn(d time.Duration) time.Time {
+	start := time.Now().UTC().Add(d).Truncate(time.Minute)
+	f.owned.Showtime = entity.Showtime{
+		ShowDate:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		StartTime: start.Format("15:04"),
+		Cinema:    entity.Cinema{Timezone: "UTC"},
+	}
+	return start
+}
+
+// cancelledEvents returns the BookingCancelled events in the outbox
+func (f *manageFixture) cancelledEvents(t *testing.T) []events.BookingCancelled {
+	t.Helper()
+	var cancelled []events.BookingCancelled
+	for _, event := range f.outbox.decoded(t, events.BookingCancelledEvent) {
+		cancelled = append(cancelled, event.(events.BookingCancelled))
+	}
+	return cancelled
+}
+
+func TestGetBookingAccess(t *testing.T) {
+	f := newManageFixture(t)
+	owner, stranger := f.owner, uuid.New()
+
+	tests := []struct {
+		name    string
+		viewer  Viewer
+		booking *entity.Booking
+		allowed bool
+	}{
+		{"owner", Viewer{UserID: &owner}, f.owned, true},
+		{"other user", Viewer{UserID: &stranger}, f.owned, false},
+		{"anonymous", Viewer{}, f.owned, false},
+		{"admin", Viewer{UserID: &stranger, Admin: true}, f.owned, true},
+		{"admin, guest booking", Viewer{UserID: &stranger, Admin: true}, f.guest, true},
+		{"guest reference and email", Viewer{Reference: "bk-guest1 ", Email: "Guest@Example.com"}, f.guest, true},
+		{"guest, wrong email", Viewer{Reference: "BK-GUEST1", Email: "other@example.com"}, f.guest, false},
+		{"guest, wrong reference", Viewer{Reference: "BK-OWNED1", Email: "guest@example.com"}, f.guest, false},
+		{"guest, no email", Viewer{Reference: "BK-GUEST1"}, f.guest, false},
+		{"signed-in user, guest booking", Viewer{UserID: &stranger}, f.guest, false},
+		// An account booking cannot be opened with guest proof
+		{"guest proof, account booking", Viewer{Reference: "BK-OWNED1", Email: "guest@example.com"}, f.owned, false},
+	}
+	for _, tt := range tests {
+		res, err := f.svc.GetBooking(context.Background(), tt.viewer, tt.booking.ID)
+		if tt.allowed {
+			if err != nil {
+				t.Errorf("%s: %v, want the booking", tt.name, err)
+			} else if res.BookingReference != tt.booking.BookingReference {
+				t.Errorf("%s: got %s", tt.name, res.BookingReference)
+			}
+			continue
+		}
+		if !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+			t.Errorf("%s: %v, want %s", tt.name, err, apperrors.CodeBookingNotFound)
+		}
+	}
+
+	// A missing booking looks the same as one the viewer may not see
+	if _, err := f.svc.GetBooking(context.Background(), Viewer{Admin: true}, uuid.New()); !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		t.Errorf("missing booking: %v", err)
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner", func(t *testing.T) {
+		f := newManageFixture(t)
+		res, err := f.svc.CancelBooking(ctx, Viewer{UserID: &f.owner}, f.owned.ID, CancelBookingRequest{Reason: "plans changed"})
+		if err != nil {
+			t.Fatalf("CancelBooking: %v", err)
+		}
+		if res.BookingStatus != string(entity.BookingCancelled) {
+			t.Errorf("status = %s", res.BookingStatus)
+		}
+		if f.holds.version != 1 {
+			t.Error("the booked seat cache was not invalidated")
+		}
+		got := f.cancelledEvents(t)
+		if len(got) != 1 || got[0].BookingID != f.owned.ID || got[0].Reason != "plans changed" {
+			t.Errorf("events = %+v", got)
+		}
+		statuses := f.feed.statuses(f.owned.ShowtimeID)
+		for _, seat := range f.owned.BookingSeats {
+			if statuses[seat.SeatID] != SeatStatusAvailable {
+				t.Errorf("seat %s published as %q, want %s", seat.SeatID, statuses[seat.SeatID], SeatStatusAvailable)
+			}
+		}
+		w := httptest.NewRecorder()
+		f.metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if !strings.Contains(w.Body.String(), "booking_cancelled_total 1") {
+			t.Error("the cancellation was not counted")
+		}
+	})
+
+	t.Run("seat taken again before the update", func(t *testing.T) {
+		f := newManageFixture(t)
+		// Someone holds a released seat before its status is read back
+		taken := f.owned.BookingSeats[0].SeatID
+		f.holds.held = entity.UUIDList{taken}
+		if _, err := f.svc.CancelBooking(ctx, Viewer{UserID: &f.owner}, f.owned.ID, CancelBookingRequest{}); err != nil {
+			t.Fatalf("CancelBooking: %v", err)
+		}
+		statuses := f.feed.statuses(f.owned.ShowtimeID)
+		if statuses[taken] != SeatStatusLocked || statuses[f.owned.BookingSeats[1].SeatID] != SeatStatusAvailable {
+			t.Errorf("published %v, want the taken seat LOCKED and the other AVAILABLE", statuses)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		f := newManageFixture(t)
+		stranger := uuid.New()
+		_, err := f.svc.CancelBooking(ctx, Viewer{UserID: &stranger}, f.owned.ID, CancelBookingRequest{})
+		if !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+			t.Fatalf("CancelBooking = %v, want %s", err, apperrors.CodeBookingNotFound)
+		}
+		if f.owned.BookingStatus != entity.BookingPending {
+			t.Error("the booking was cancelled")
+		}
+		if len(f.feed.published) != 0 {
+			t.Errorf("seat updates published: %v", f.feed.published)
+		}
+		if got := f.cancelledEvents(t); len(got) != 0 {
+			t.Errorf("events = %+v", got)
+		}
+	})
+
+	t.Run("guest reference", func(t *testing.T) {
+		f := newManageFixture(t)
+		viewer := Viewer{Reference: f.guest.BookingReference, Email: f.guest.GuestEmail}
+		if _, err := f.svc.CancelBooking(ctx, viewer, f.guest.ID, CancelBookingRequest{}); err != nil {
+			t.Fatalf("CancelBooking: %v", err)
+		}
+		if f.guest.BookingStatus != entity.BookingCancelled {
+			t.Errorf("status = %s", f.guest.BookingStatus)
+		}
+
+		viewer.Email = "other@example.com"
+		if _, err := f.svc.CancelBooking(ctx, viewer, f.guest.ID, CancelBookingRequest{}); !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+			t.Errorf("wrong email: %v", err)
+		}
+	})
+
+	t.Run("already cancelled", func(t *testing.T) {
+		f := newManageFixture(t)
+		f.owned.BookingStatus = entity.BookingCancelled
+		_, err := f.svc.CancelBooking(ctx, Viewer{UserID: &f.owner}, f.owned.ID, CancelBookingRequest{})
+		if !apperrors.Is(err, apperrors.CodeInvalidStatus) {
+			t.Errorf("CancelBooking = %v, want %s", err, apperrors.CodeInvalidStatus)
+		}
+	})
+}
+
+func TestCancelPaidBookingRefund(t *testing.T) {
+	tests := []struct {
+		name          string
+		startsIn      time.Duration
+		feeRefundable bool
+		want          float64 // refund, or -1 when the cancellation is refused
+		window        string
+	}{
+		{"two days ahead", 48 * time.Hour, false, 20, "full_refund"},
+		{"two days ahead, fee refunded", 48 * time.Hour, true, 21.5, "full_refund"},
+		{"ten hours ahead", 10 * time.Hour, false, 10, "partial_refund"},
+		{"an hour ahead", time.Hour, false, -1, ""},
+		{"after the start", -time.Hour, false, -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newManageFixture(t)
+			f.owned.BookingStatus = entity.BookingConfirmed
+			f.owned.PaymentStatus = entity.PaymentPaid
+			f.owned.FinalAmount, f.owned.FeeAmount = 21.5, 1.5
+			start := f.startsIn(tt.startsIn)
+			f.owned.Showtime.Cinema.FeeRefundable = tt.feeRefundable
+
+			res, err := f.svc.CancelBooking(context.Background(), Viewer{UserID: &f.owner}, f.owned.ID, CancelBookingRequest{})
+			if tt.want < 0 {
+				if !apperrors.Is(err, apperrors.CodeFailedPrecondition) {
+					t.Fatalf("CancelBooking = %v, want %s", err, apperrors.CodeFailedPrecondition)
+				}
+				if f.owned.BookingStatus != entity.BookingConfirmed {
+					t.Errorf("status = %s, want the booking kept", f.owned.BookingStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CancelBooking: %v", err)
+			}
+			if res.RefundAmount != tt.want || res.RefundPolicy == nil || res.RefundPolicy.Window != tt.window {
+				t.Fatalf("refund = %v under %+v, want %v under %s", res.RefundAmount, res.RefundPolicy, tt.want, tt.window)
+			}
+			if f.owned.RefundAmount == nil || *f.owned.RefundAmount != tt.want || f.owned.PaymentStatus != entity.PaymentRefunded {
+				t.Errorf("stored refund = %v, payment %s", f.owned.RefundAmount, f.owned.PaymentStatus)
+			}
+			if until := start.Add(-24 * time.Hour); tt.window == "full_refund" && !res.RefundPolicy.AppliesUntil.Equal(until) {
+				t.Errorf("applies until %s, want %s", res.RefundPolicy.AppliesUntil, until)
+			}
+			if got := f.cancelledEvents(t); len(got) != 1 || got[0].RefundAmount != tt.want {
+				t.Errorf("events = %+v", got)
+			}
+		})
+	}
+
+	// Unpaid bookings have nothing to refund and can be cancelled up to
+	// the start
+	f := newManageFixture(t)
+	f.startsIn(time.Hour)
+	res, err := f.svc.CancelBooking(context.Background(), Viewer{UserID: &f.owner}, f.owned.ID, CancelBookingRequest{})
+	if err != nil {
+		t.Fatalf("unpaid: %v", err)
+	}
+	if res.RefundAmount != 0 || res.RefundPolicy != nil {
+		t.Errorf("unpaid: refund %v under %+v", res.RefundAmount, res.RefundPolicy)
+	}
+}
+
+func TestRetryPayment(t *testing.T) {
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Minute)
+	upcoming := time.Now().Add(10 * time.Minute)
+
+	tests := []struct {
+		name     string
+		payments *flakyPayments
+		user     func(f *manageFixture) uuid.UUID
+		booking  func(f *manageFixture) *entity.Booking
+		code     apperrors.ErrorCode
+	}{
+		{"pending booking", &flakyPayments{}, ownerOf, ownedExpiring(upcoming), ""},
+		{"without a gateway", nil, ownerOf, ownedExpiring(upcoming), apperrors.CodeFailedPrecondition},
+		{"another user's booking", &flakyPayments{}, func(*manageFixture) uuid.UUID { return uuid.New() }, ownedExpiring(upcoming), apperrors.CodeBookingNotFound},
+		{"guest booking", &flakyPayments{}, ownerOf, func(f *manageFixture) *entity.Booking { return f.guest }, apperrors.CodeBookingNotFound},
+		{"paid booking", &flakyPayments{}, ownerOf, func(f *manageFixture) *entity.Booking {
+			f.owned.BookingStatus, f.owned.PaymentStatus = entity.BookingConfirmed, entity.PaymentPaid
+			return f.owned
+		}, apperrors.CodeConflict},
+		{"expired booking", &flakyPayments{}, ownerOf, ownedExpiring(expired), apperrors.CodeBookingExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newManageFixture(t)
+			if tt.payments != nil {
+				f.svc.payments = tt.payments
+			}
+			booking := tt.booking(f)
+
+			res, err := f.svc.RetryPayment(ctx, tt.user(f), booking.ID)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("RetryPayment: %v", err)
+				}
+				if res.CheckoutURL != "https://checkout.test/"+booking.BookingReference || res.BookingID != booking.ID {
+					t.Errorf("response = %+v", res)
+				}
+				return
+			}
+			if !apperrors.Is(err, tt.code) {
+				t.Errorf("RetryPayment = %v, want %s", err, tt.code)
+			}
+			if tt.payments != nil && len(tt.payments.started) != 0 {
+				t.Errorf("payment started for %v", tt.payments.started)
+			}
+		})
+	}
+
+	f := newManageFixture(t)
+	f.svc.payments = &flakyPayments{failures: 1}
+	booking := ownedExpiring(upcoming)(f)
+	if _, err := f.svc.RetryPayment(ctx, f.owner, booking.ID); err == nil {
+		t.Error("RetryPayment succeeded with the gateway down")
+	}
+}
+
+func ownerOf(f *manageFixture) uuid.UUID { return f.owner }
+
+// ownedExpiring returns the owned pending booking, expiring at expiresAt
+func ownedExpiring(expiresAt time.Time) func(f *manageFixture) *entity.Booking {
+	return func(f *manageFixture) *entity.Booking {
+		f.owned.ExpiresAt = &expiresAt
+		return f.owned
+	}
+}
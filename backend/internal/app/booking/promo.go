@@ -0,0 +1,61 @@
+package booking
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ValidatePromoCode prices the user's hold with a promo code, so the
+// checkout can show the discount or why the code does not apply before the
+// booking is confirmed
+func (s *Service) ValidatePromoCode(ctx context.Context, userID uuid.UUID, req ValidatePromoCodeRequest) (*PromoQuoteResponse, error) {
+	hold, err := s.holdRepo.GetByID(ctx, req.HoldID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingExpired, "seat hold not found or expired, hold the seats again")
+		}
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+
+	promo, err := s.checkPromoCode(ctx, userID, req.PromoCode, hold.Subtotal)
+	if err != nil {
+		return nil, err
+	}
+
+	price := hold.Price(promo)
+	return &PromoQuoteResponse{
+		PromoCode: promo.Code,
+		Subtotal:  price.Subtotal,
+		Discount:  price.Discount,
+		Fee:       price.Fee,
+		Total:     price.Total,
+	}, nil
+}
+
+// checkPromoCode returns the promo code if the user can apply it to an
+// order of subtotal, or an INVALID_PROMO_CODE error saying why not
+func (s *Service) checkPromoCode(ctx context.Context, userID uuid.UUID, code string, subtotal float64) (*entity.PromoCode, error) {
+	promo, err := s.promoRepo.GetByCode(ctx, code)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return nil, apperrors.New(apperrors.CodeInvalidPromoCode, "promo code not found")
+		}
+		return nil, err
+	}
+
+	uses, err := s.promoRepo.GetUserUsageCount(ctx, promo.ID, &userID, "")
+	if err != nil {
+		return nil, err
+	}
+	if reason := promo.Rejection(subtotal, uses); reason != "" {
+		return nil, apperrors.New(apperrors.CodeInvalidPromoCode, reason)
+	}
+	return promo, nil
+}
@@ -0,0 +1,105 @@
+package booking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memHold holds a single seat hold
+type memHold struct {
+	repository.SeatHoldRepository
+	hold *entity.SeatHold
+}
+
+func (m *memHold) GetByID(_ context.Context, id string) (*entity.SeatHold, error) {
+	if m.hold == nil || m.hold.ID != id {
+		return nil, apperrors.ErrNotFound("seat hold")
+	}
+	return m.hold, nil
+}
+
+// memPromos knows promo codes and how often each user used them
+type memPromos struct {
+	repository.PromoCodeRepository
+	codes map[string]*entity.PromoCode
+	uses  map[uuid.UUID]int
+}
+
+func (m *memPromos) GetByCode(_ context.Context, code string) (*entity.PromoCode, error) {
+	if promo, ok := m.codes[code]; ok {
+		return promo, nil
+	}
+	return nil, apperrors.ErrNotFound("promo code")
+}
+
+func (m *memPromos) GetUserUsageCount(_ context.Context, _ uuid.UUID, userID *uuid.UUID, _ string) (int, error) {
+	return m.uses[*userID], nil
+}
+
+func TestValidatePromoCode(t *testing.T) {
+	ctx := context.Background()
+	userID, regular := uuid.New(), uuid.New()
+	once := 1
+	hold := &entity.SeatHold{
+		ID:        "hold-1",
+		UserID:    userID,
+		Seats:     []entity.HeldSeat{{SeatID: uuid.New(), Price: 10}, {SeatID: uuid.New(), Price: 10}},
+		FeePolicy: entity.BookingFee{Type: entity.FeePercent, Amount: 10},
+	}
+	now := time.Now()
+	promos := &memPromos{
+		codes: map[string]*entity.PromoCode{
+			"HALF": {ID: uuid.New(), Code: "HALF", DiscountType: "PERCENTAGE", DiscountValue: 50,
+				UsageLimitPerUser: &once, IsActive: true, ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+		},
+		uses: map[uuid.UUID]int{regular: 1},
+	}
+	svc := NewService(&memHold{hold: hold}, nil, nil, nil, nil, nil, nil, nil, promos, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	quote, err := svc.ValidatePromoCode(ctx, userID, ValidatePromoCodeRequest{HoldID: "hold-1", PromoCode: "HALF"})
+	if err != nil {
+		t.Fatalf("ValidatePromoCode: %v", err)
+	}
+	// The fee is charged on the discounted subtotal
+	if quote.Subtotal != 20 || quote.Discount != 10 || quote.Fee != 1 || quote.Total != 11 {
+		t.Errorf("quote = %+v, want 20 - 10 + 1 = 11", quote)
+	}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		holdID   string
+		code     string
+		wantCode apperrors.ErrorCode
+		wantMsg  string
+	}{
+		{"unknown code", userID, "hold-1", "NOPE", apperrors.CodeInvalidPromoCode, "not found"},
+		{"used by this user", regular, "hold-1", "HALF", apperrors.CodeForbidden, ""},
+		{"expired hold", userID, "hold-2", "HALF", apperrors.CodeBookingExpired, ""},
+	}
+	for _, tt := range tests {
+		_, err := svc.ValidatePromoCode(ctx, tt.userID, ValidatePromoCodeRequest{HoldID: tt.holdID, PromoCode: tt.code})
+		if !apperrors.Is(err, tt.wantCode) || !strings.Contains(err.Error(), tt.wantMsg) {
+			t.Errorf("%s: %v, want %s", tt.name, err, tt.wantCode)
+		}
+	}
+
+	// The per-user limit is checked for the hold's owner
+	hold.UserID = regular
+	_, err = svc.ValidatePromoCode(ctx, regular, ValidatePromoCodeRequest{HoldID: "hold-1", PromoCode: "HALF"})
+	if !apperrors.Is(err, apperrors.CodeInvalidPromoCode) || !strings.Contains(err.Error(), "maximum number of times") {
+		t.Errorf("second use: %v, want the per-user limit", err)
+	}
+}
@@ -0,0 +1,63 @@
+package booking
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+func TestCheckSalesWindow(t *testing.T) {
+	open := time.Date(2026, 10, 9, 3, 0, 0, 0, time.UTC)
+	hash := authinfra.HashToken("EARLYBIRD")
+
+	// 20:00 in Saigon, 13:00 UTC
+	showtime := &entity.Showtime{
+		ShowDate:        time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		StartTime:       "20:00",
+		SalesOpenAt:     &open,
+		PresaleCodeHash: &hash,
+		Cinema:          entity.Cinema{Timezone: "Asia/Ho_Chi_Minh"},
+	}
+	start := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		code string
+		now  time.Time
+		want apperrors.ErrorCode // empty when the sale is allowed
+	}{
+		{"before opening", "", open.Add(-time.Second), apperrors.CodeSalesNotOpen},
+		{"before opening with the code", "EARLYBIRD", open.Add(-time.Second), ""},
+		{"before opening with a wrong code", "LATEBIRD", open.Add(-time.Second), apperrors.CodeSalesNotOpen},
+		{"at opening", "", open, ""},
+		{"just before the start", "", start.Add(-time.Second), ""},
+		{"at the start", "", start, apperrors.CodeSalesClosed},
+		{"at the start with the code", "EARLYBIRD", start, apperrors.CodeSalesClosed},
+	}
+	for _, tt := range tests {
+		err := checkSalesWindow(showtime, tt.code, tt.now)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: %v, want the sale allowed", tt.name, err)
+			}
+			continue
+		}
+		if !apperrors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %s", tt.name, err, tt.want)
+		}
+	}
+
+	err := checkSalesWindow(showtime, "", open.Add(-time.Second))
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("error %v is not an AppError", err)
+	}
+	if details, ok := appErr.Details.(map[string]time.Time); !ok || !details["sales_open_at"].Equal(open) {
+		t.Errorf("details = %v, want the open time", appErr.Details)
+	}
+}
@@ -0,0 +1,85 @@
+package booking
+
+import (
+	"context"
+	"strings"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// SearchBookings finds bookings for cinema staff, e.g. to look up a
+// customer's booking at the box office. Admins may search every cinema;
+// managers and staff only a cinema they are assigned to.
+func (s *Service) SearchBookings(ctx context.Context, viewerID uuid.UUID, role string, params SearchBookingsParams) ([]StaffBookingResponse, int64, error) {
+	filter := repository.BookingFilter{
+		Reference:   strings.TrimSpace(params.BookingReference),
+		Email:       strings.TrimSpace(params.Email),
+		DateFrom:    params.BookedFrom,
+		DateTo:      params.BookedTo,
+		OldestFirst: params.Sort == "booked_at",
+	}
+	if params.CinemaID != "" {
+		id, err := uuid.Parse(params.CinemaID)
+		if err != nil {
+			return nil, 0, apperrors.ErrValidation("invalid cinema_id")
+		}
+		filter.CinemaID = &id
+	}
+	if params.ShowtimeID != "" {
+		id, err := uuid.Parse(params.ShowtimeID)
+		if err != nil {
+			return nil, 0, apperrors.ErrValidation("invalid showtime_id")
+		}
+		filter.ShowtimeID = &id
+	}
+	if params.BookingStatus != "" {
+		status := entity.BookingStatus(params.BookingStatus)
+		filter.BookingStatus = &status
+	}
+	if params.PaymentStatus != "" {
+		status := entity.PaymentStatus(params.PaymentStatus)
+		filter.PaymentStatus = &status
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return nil, 0, apperrors.ErrValidation("booked_from must not be after booked_to")
+	}
+
+	if err := s.checkCinemaAccess(ctx, viewerID, role, filter.CinemaID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	bookings, total, err := s.bookingRepo.Search(ctx, filter, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]StaffBookingResponse, 0, len(bookings))
+	for _, b := range bookings {
+		responses = append(responses, toStaffBooking(b))
+	}
+	return responses, total, nil
+}
+
+// checkCinemaAccess lets admins see every cinema and managers and staff
+// their own
+func (s *Service) checkCinemaAccess(ctx context.Context, viewerID uuid.UUID, role string, cinemaID *uuid.UUID) error {
+	if entity.Role(role) == entity.RoleAdmin {
+		return nil
+	}
+	if cinemaID == nil {
+		return apperrors.ErrValidation("cinema_id is required for managers and staff")
+	}
+	assigned, err := s.staffRepo.IsAssigned(ctx, *cinemaID, viewerID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return apperrors.ErrForbidden("you are not assigned to this cinema")
+	}
+	return nil
+}
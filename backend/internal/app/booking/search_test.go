@@ -0,0 +1,141 @@
+package booking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memSearch returns its bookings for every search and keeps the last filter
+type memSearch struct {
+	repository.BookingRepository
+	bookings []*entity.Booking
+	filter   repository.BookingFilter
+	offset   int
+}
+
+func (m *memSearch) Search(_ context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	m.filter, m.offset = filter, offset
+	return m.bookings, int64(len(m.bookings)), nil
+}
+
+// memStaff assigns users to cinemas
+type memStaff struct {
+	repository.CinemaStaffRepository
+	assigned map[uuid.UUID]uuid.UUID // user to cinema
+}
+
+func (m *memStaff) IsAssigned(_ context.Context, cinemaID, userID uuid.UUID) (bool, error) {
+	return m.assigned[userID] == cinemaID, nil
+}
+
+func TestSearchBookingsAccess(t *testing.T) {
+	ctx := context.Background()
+	cinemaID, otherCinema := uuid.New(), uuid.New()
+	staffID, adminID := uuid.New(), uuid.New()
+	svc := NewService(nil, nil, nil, &memSearch{}, nil, nil, nil, nil, nil, &memStaff{assigned: map[uuid.UUID]uuid.UUID{staffID: cinemaID}}, nil,
+		nil, nil, nil, nil, nil, nil, nil, config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	tests := []struct {
+		name     string
+		viewer   uuid.UUID
+		role     string
+		cinemaID string
+		want     apperrors.ErrorCode // empty when allowed
+	}{
+		{"admin, every cinema", adminID, "ADMIN", "", ""},
+		{"admin, any cinema", adminID, "ADMIN", otherCinema.String(), ""},
+		{"staff, own cinema", staffID, "STAFF", cinemaID.String(), ""},
+		{"staff, no cinema", staffID, "STAFF", "", apperrors.CodeValidation},
+		{"staff, another cinema", staffID, "STAFF", otherCinema.String(), apperrors.CodeForbidden},
+		{"manager not assigned", uuid.New(), "MANAGER", cinemaID.String(), apperrors.CodeForbidden},
+		{"malformed cinema", adminID, "ADMIN", "nope", apperrors.CodeValidation},
+	}
+	for _, tt := range tests {
+		_, _, err := svc.SearchBookings(ctx, tt.viewer, tt.role, SearchBookingsParams{Page: 1, Limit: 20, CinemaID: tt.cinemaID})
+		if tt.want == "" && err != nil {
+			t.Errorf("%s: %v, want allowed", tt.name, err)
+		}
+		if tt.want != "" && !apperrors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSearchBookingsFilter(t *testing.T) {
+	repo := &memSearch{}
+	svc := NewService(nil, nil, nil, repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	_, _, err := svc.SearchBookings(context.Background(), uuid.New(), "ADMIN", SearchBookingsParams{
+		Page: 3, Limit: 20, BookingReference: " BK-ABC123 ", Email: " Fan@Example.com ",
+		BookingStatus: "CONFIRMED", BookedFrom: &from, BookedTo: &to, Sort: "booked_at",
+	})
+	if err != nil {
+		t.Fatalf("SearchBookings: %v", err)
+	}
+	f := repo.filter
+	if f.Reference != "BK-ABC123" || f.Email != "Fan@Example.com" || !f.OldestFirst ||
+		f.BookingStatus == nil || *f.BookingStatus != entity.BookingConfirmed || f.PaymentStatus != nil {
+		t.Errorf("filter = %+v", f)
+	}
+	if repo.offset != 40 {
+		t.Errorf("offset = %d, want 40 on page 3", repo.offset)
+	}
+
+	_, _, err = svc.SearchBookings(context.Background(), uuid.New(), "ADMIN", SearchBookingsParams{Page: 1, Limit: 20, BookedFrom: &to, BookedTo: &from})
+	if !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("inverted range: %v, want %s", err, apperrors.CodeValidation)
+	}
+}
+
+func TestToStaffBooking(t *testing.T) {
+	userID := uuid.New()
+	paidAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	reference := "pi_2"
+	showtime := entity.Showtime{
+		ShowDate: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), StartTime: "19:30",
+		Movie: entity.Movie{Title: "Dune"}, Cinema: entity.Cinema{Name: "Downtown", Timezone: "Asia/Ho_Chi_Minh"},
+		Screen: entity.Screen{Name: "IMAX 1"},
+	}
+	b := &entity.Booking{
+		BookingReference: "BK-1", UserID: &userID, GuestEmail: "stale@example.com",
+		User:     &entity.User{FirstName: "Film", LastName: "Fan", Email: "fan@example.com"},
+		Showtime: showtime, NumTickets: 2, PaymentStatus: entity.PaymentPaid, FinalAmount: 24,
+		BookingSeats: []entity.BookingSeat{{Seat: entity.Seat{RowLabel: "F", SeatNumber: 7}}, {Seat: entity.Seat{RowLabel: "F", SeatNumber: 8}}},
+		Payments:     []entity.Payment{{PaymentReference: "pi_1"}, {PaymentReference: reference, PaidAt: &paidAt}},
+	}
+
+	got := toStaffBooking(b)
+	if got.Customer.Name != "Film Fan" || got.Customer.Email != "fan@example.com" {
+		t.Errorf("customer = %+v, want the account's name and email", got.Customer)
+	}
+	if got.MovieTitle != "Dune" || got.CinemaName != "Downtown" || got.ScreenName != "IMAX 1" {
+		t.Errorf("showtime = %q at %q in %q", got.MovieTitle, got.CinemaName, got.ScreenName)
+	}
+	if want := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC); !got.StartsAt.Equal(want) {
+		t.Errorf("starts at %v, want %v in the cinema's timezone", got.StartsAt, want)
+	}
+	if len(got.Seats) != 2 || got.Seats[0] != "F7" || got.Seats[1] != "F8" {
+		t.Errorf("seats = %v, want [F7 F8]", got.Seats)
+	}
+	if got.Payment.Reference != reference || got.Payment.PaidAt == nil || got.Payment.Total != 24 {
+		t.Errorf("payment = %+v, want the latest payment", got.Payment)
+	}
+
+	guest := toStaffBooking(&entity.Booking{GuestName: "Walk In", GuestEmail: "walk@example.com", Showtime: showtime})
+	if guest.Customer.UserID != nil || guest.Customer.Name != "Walk In" || guest.Customer.Email != "walk@example.com" {
+		t.Errorf("guest customer = %+v", guest.Customer)
+	}
+}
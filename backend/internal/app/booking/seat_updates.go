@@ -0,0 +1,65 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// publishSeats tells the clients watching a showtime that seats changed
+// status. Updates are best effort; a client that misses one still sees the
+// seat taken when it tries to hold it.
+func (s *Service) publishSeats(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID, status string) {
+	if len(seatIDs) == 0 {
+		return
+	}
+	now := time.Now()
+	updates := make([]repository.SeatUpdate, 0, len(seatIDs))
+	for _, seatID := range seatIDs {
+		updates = append(updates, repository.SeatUpdate{SeatID: seatID, Status: status, UpdatedAt: now})
+	}
+	s.sendSeatUpdates(ctx, showtimeID, updates)
+}
+
+// publishReleased publishes the status of seats a hold or booking gave up.
+// The status is read back rather than assumed AVAILABLE, since another
+// user may already have taken a seat.
+func (s *Service) publishReleased(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) {
+	if len(seatIDs) == 0 {
+		return
+	}
+	statuses, err := s.seatStatuses(ctx, showtimeID, seatIDs)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to read released seats",
+			zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	updates := make([]repository.SeatUpdate, 0, len(statuses))
+	for _, seat := range statuses {
+		updates = append(updates, repository.SeatUpdate{SeatID: seat.SeatID, Status: seat.Status, UpdatedAt: now})
+	}
+	s.sendSeatUpdates(ctx, showtimeID, updates)
+}
+
+func (s *Service) sendSeatUpdates(ctx context.Context, showtimeID uuid.UUID, updates []repository.SeatUpdate) {
+	if err := s.seatUpdates.Publish(ctx, showtimeID, updates); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to publish seat updates",
+			zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+	}
+}
+
+// bookedSeatIDs returns the IDs of the seats of a booking
+func bookedSeatIDs(booking *entity.Booking) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(booking.BookingSeats))
+	for _, seat := range booking.BookingSeats {
+		ids = append(ids, seat.SeatID)
+	}
+	return ids
+}
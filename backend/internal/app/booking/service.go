@@ -0,0 +1,1342 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/pricing"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PaymentStarter starts the gateway payment of a pending booking and
+// returns the URL of the checkout page the customer pays on
+type PaymentStarter interface {
+	StartPayment(ctx context.Context, booking *entity.Booking) (string, error)
+}
+
+// Service handles seat holds and bookings
+type Service struct {
+	holdRepo        repository.SeatHoldRepository
+	showtimeRepo    repository.ShowtimeRepository
+	seatRepo        repository.SeatRepository
+	bookingRepo     repository.BookingRepository
+	bookingSeatRepo repository.BookingSeatRepository
+	groupRepo       repository.GroupCheckoutRepository
+	deviceRepo      repository.AssistiveDeviceRepository
+	ruleRepo        repository.SeatTypeRuleRepository
+	promoRepo       repository.PromoCodeRepository
+	staffRepo       repository.CinemaStaffRepository
+	checkInRepo     repository.TicketCheckInRepository
+	seatUpdates     repository.SeatUpdateFeed
+	pricer          pricing.Engine
+	payments        PaymentStarter // nil when no gateway is configured
+	tracker         *analytics.Tracker
+	metrics         *metrics.Metrics
+	tx              repository.Transactor
+	outbox          *outbox.Outbox
+	cfg             config.BookingConfig
+	ticketCfg       config.TicketConfig
+	logger          *logger.Logger
+}
+
+// NewService creates a new booking service
+func NewService(
+	holdRepo repository.SeatHoldRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	seatRepo repository.SeatRepository,
+	bookingRepo repository.BookingRepository,
+	bookingSeatRepo repository.BookingSeatRepository,
+	groupRepo repository.GroupCheckoutRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
+	ruleRepo repository.SeatTypeRuleRepository,
+	promoRepo repository.PromoCodeRepository,
+	staffRepo repository.CinemaStaffRepository,
+	checkInRepo repository.TicketCheckInRepository,
+	seatUpdates repository.SeatUpdateFeed,
+	pricer pricing.Engine,
+	payments PaymentStarter,
+	tracker *analytics.Tracker,
+	metrics *metrics.Metrics,
+	tx repository.Transactor,
+	outbox *outbox.Outbox,
+	cfg config.BookingConfig,
+	ticketCfg config.TicketConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		holdRepo:        holdRepo,
+		showtimeRepo:    showtimeRepo,
+		seatRepo:        seatRepo,
+		bookingRepo:     bookingRepo,
+		bookingSeatRepo: bookingSeatRepo,
+		groupRepo:       groupRepo,
+		deviceRepo:      deviceRepo,
+		ruleRepo:        ruleRepo,
+		promoRepo:       promoRepo,
+		staffRepo:       staffRepo,
+		checkInRepo:     checkInRepo,
+		seatUpdates:     seatUpdates,
+		pricer:          pricer,
+		payments:        payments,
+		tracker:         tracker,
+		metrics:         metrics,
+		tx:              tx,
+		outbox:          outbox,
+		cfg:             cfg,
+		ticketCfg:       ticketCfg,
+		logger:          logger,
+	}
+}
+
+// HoldSeats locks the requested seats for the user until the hold expires
+func (s *Service) HoldSeats(ctx context.Context, userID uuid.UUID, req HoldSeatsRequest) (*HoldResponse, error) {
+	hold, err := s.holdSeats(ctx, userID, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ToHoldResponse(hold), nil
+}
+
+// holdSeats holds seats for the user, optionally on behalf of a group
+// booking the user joined
+func (s *Service) holdSeats(ctx context.Context, userID uuid.UUID, req HoldSeatsRequest, groupBookingID *uuid.UUID) (*entity.SeatHold, error) {
+	log := s.logger.WithContext(ctx)
+
+	if s.cfg.MaxSeatsPerHold > 0 && len(req.SeatIDs) > s.cfg.MaxSeatsPerHold {
+		return nil, apperrors.ErrBadRequest(fmt.Sprintf("cannot hold more than %d seats", s.cfg.MaxSeatsPerHold))
+	}
+
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, req.ShowtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !showtime.CanAccess(hashCode(req.AccessCode)) {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	if showtime.Status != entity.ShowtimeScheduled || showtime.MovieUnavailable() || showtime.ScreenUnavailable() {
+		return nil, apperrors.ErrBadRequest("showtime is not open for booking")
+	}
+	if err := checkSalesWindow(showtime, req.PresaleCode, time.Now()); err != nil {
+		return nil, err
+	}
+	if showtime.IsFull() {
+		// The client offers the showtime's waitlist instead
+		return nil, apperrors.New(apperrors.CodeShowtimeFull, "showtime is sold out").
+			WithDetails(map[string]bool{"waitlist": true})
+	}
+
+	seats, err := s.seatRepo.GetByIDs(ctx, req.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked against Postgres before any seat is locked, so a rejected
+	// request leaves no partial hold behind
+	booked, err := s.bookingSeatRepo.GetBookedSeatIDs(ctx, showtime.ID)
+	if err != nil {
+		return nil, err
+	}
+	bookedSet := entity.UUIDList(booked)
+	if err := checkSeatsBookable(showtime, req.SeatIDs, seats, bookedSet); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	hold := &entity.SeatHold{
+		ID:             uuid.New().String(),
+		ShowtimeID:     showtime.ID,
+		UserID:         userID,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.cfg.HoldTTL),
+		GroupBookingID: groupBookingID,
+	}
+
+	if showtime.HasCapacityOverride() {
+		if err := s.checkCapacity(ctx, showtime, seats, bookedSet); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkSeatTypes(ctx, showtime, seats); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.pricer.Quote(ctx, showtime)
+	if err != nil {
+		return nil, err
+	}
+	held, err := priceSeats(showtime, quote, seats)
+	if err != nil {
+		return nil, err
+	}
+	hold.Seats = held
+	hold.FeePolicy = showtime.Cinema.BookingFee()
+	hold.Reprice()
+
+	if len(req.AssistiveDevices) > 0 {
+		if hold.Devices, err = s.checkDevices(ctx, showtime, len(seats), req.AssistiveDevices); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.holdRepo.Create(ctx, hold); err != nil {
+		log.Warn("failed to hold seats", zap.String("showtime_id", showtime.ID.String()), zap.Error(err))
+		return nil, err
+	}
+
+	s.publishSeats(ctx, showtime.ID, hold.SeatIDs(), SeatStatusLocked)
+	s.metrics.SeatsHeld(ctx, len(hold.Seats))
+
+	log.Info("seats held",
+		zap.String("hold_id", hold.ID),
+		zap.String("showtime_id", showtime.ID.String()),
+		zap.Int("seats", len(hold.Seats)),
+	)
+	s.tracker.Track(ctx, analytics.Event{
+		Name:       analytics.EventSeatsHeld,
+		UserID:     &userID,
+		ShowtimeID: &showtime.ID,
+		MovieID:    &showtime.MovieID,
+		Properties: map[string]any{
+			"hold_id":  hold.ID,
+			"seats":    len(hold.Seats),
+			"subtotal": hold.Subtotal,
+			"fee":      hold.Fee,
+			"devices":  len(hold.Devices),
+		},
+	})
+
+	return hold, nil
+}
+
+// checkSeatsBookable rejects a selection with seats that do not exist, are
+// not active seats of the showtime's screen, or are booked by a pending or
+// confirmed booking. The error lists every offending seat ID so the
+// frontend can mark them.
+func checkSeatsBookable(showtime *entity.Showtime, requested []uuid.UUID, seats []*entity.Seat, booked entity.UUIDList) error {
+	found := make(map[uuid.UUID]bool, len(seats))
+	var invalid, taken []uuid.UUID
+	for _, seat := range seats {
+		found[seat.ID] = true
+		switch {
+		case seat.ScreenID != showtime.ScreenID || !seat.IsActive:
+			invalid = append(invalid, seat.ID)
+		case booked.Contains(seat.ID):
+			taken = append(taken, seat.ID)
+		}
+	}
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, id := range requested {
+		if seen[id] {
+			return apperrors.ErrBadRequest("seat " + id.String() + " is listed more than once")
+		}
+		seen[id] = true
+		if !found[id] {
+			invalid = append(invalid, id)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return apperrors.ErrBadRequest("one or more seats do not belong to this showtime").
+			WithDetails(map[string]any{"seat_ids": invalid})
+	}
+	if len(taken) > 0 {
+		return apperrors.New(apperrors.CodeSeatsAlreadyBooked, "one or more seats are already booked").
+			WithDetails(map[string]any{"seat_ids": taken})
+	}
+	return nil
+}
+
+// GetHold returns a hold owned by the user
+func (s *Service) GetHold(ctx context.Context, userID uuid.UUID, holdID string) (*HoldResponse, error) {
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+	return ToHoldResponse(hold), nil
+}
+
+// ExtendHold gives the user more time to check out: the hold and its seat
+// locks expire hold_ttl from now, but never later than hold_max_lifetime
+// after the hold was made. Once there, extending fails with
+// CodeHoldExtensionLimit so clients can tell the user the hold will lapse.
+func (s *Service) ExtendHold(ctx context.Context, userID uuid.UUID, holdID string, req ExtendHoldRequest) (*HoldResponse, error) {
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingExpired, "seat hold not found or expired, hold the seats again")
+		}
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+	if req.ShowtimeID != nil && hold.ShowtimeID != *req.ShowtimeID {
+		return nil, apperrors.ErrBadRequest("hold is for another showtime")
+	}
+	if hold.GroupBookingID != nil {
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is part of a group booking")
+	}
+
+	group, err := s.groupRepo.GetActiveByHoldID(ctx, hold.ID)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		// Share deadlines were set from the hold's expiry
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is being paid as a group checkout")
+	}
+
+	expiresAt := time.Now().Add(s.cfg.HoldTTL)
+	if s.cfg.HoldMaxLifetime > 0 {
+		if latest := hold.CreatedAt.Add(s.cfg.HoldMaxLifetime); expiresAt.After(latest) {
+			expiresAt = latest
+		}
+	}
+	if !expiresAt.After(hold.ExpiresAt) {
+		return nil, apperrors.New(apperrors.CodeHoldExtensionLimit, "Your hold can no longer be extended")
+	}
+
+	if err := s.holdRepo.Extend(ctx, hold, expiresAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("seat hold extended",
+		zap.String("hold_id", hold.ID),
+		zap.Time("expires_at", hold.ExpiresAt),
+	)
+	return ToHoldResponse(hold), nil
+}
+
+// ReleaseHold frees the seats of a hold the user abandoned, e.g. to pick
+// other seats, instead of leaving them locked until the hold expires.
+// Releasing a hold that has already ended frees nothing and succeeds, so
+// clients can retry freely.
+func (s *Service) ReleaseHold(ctx context.Context, userID uuid.UUID, req ReleaseHoldRequest) (*ReleaseHoldResponse, error) {
+	res := &ReleaseHoldResponse{HoldID: req.HoldID}
+
+	hold, err := s.holdRepo.GetByID(ctx, req.HoldID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+	if hold.ShowtimeID != req.ShowtimeID {
+		return nil, apperrors.ErrBadRequest("hold is for another showtime")
+	}
+	if hold.GroupBookingID != nil {
+		// The group counts on these seats
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is part of a group booking")
+	}
+
+	group, err := s.groupRepo.GetActiveByHoldID(ctx, hold.ID)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		// Invitees may be paying for these seats
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is being paid as a group checkout")
+	}
+
+	if res.ReleasedSeats, err = s.holdRepo.Release(ctx, hold); err != nil {
+		return nil, err
+	}
+	if res.ReleasedSeats > 0 {
+		s.publishReleased(ctx, hold.ShowtimeID, hold.SeatIDs())
+	}
+
+	s.logger.WithContext(ctx).Info("seat hold released",
+		zap.String("hold_id", hold.ID),
+		zap.String("showtime_id", hold.ShowtimeID.String()),
+		zap.Int("released_seats", res.ReleasedSeats),
+	)
+	return res, nil
+}
+
+// ConfirmBooking turns the user's hold into a pending booking awaiting
+// payment. The seats, prices and fee are taken from the hold as quoted;
+// the booking, its seats and the showtime's available seats are written
+// in one transaction before the hold's locks are released.
+func (s *Service) ConfirmBooking(ctx context.Context, userID uuid.UUID, req ConfirmBookingRequest) (*ConfirmBookingResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	hold, err := s.holdRepo.GetByID(ctx, req.HoldID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingExpired, "seat hold not found or expired, hold the seats again")
+		}
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+	if hold.IsExpired() {
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "seat hold has expired, hold the seats again")
+	}
+	if len(hold.Seats) == 0 {
+		return nil, apperrors.ErrBadRequest("hold has no seats")
+	}
+	if hold.GroupBookingID != nil {
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is part of a group booking")
+	}
+
+	group, err := s.groupRepo.GetActiveByHoldID(ctx, hold.ID)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		return nil, apperrors.New(apperrors.CodeConflict, "hold is being paid as a group checkout")
+	}
+
+	// The code is checked here for a clear error before anything is written,
+	// and again when its use is counted with the booking
+	var promo *entity.PromoCode
+	if req.PromoCode != "" {
+		if promo, err = s.checkPromoCode(ctx, userID, req.PromoCode, hold.Subtotal); err != nil {
+			return nil, err
+		}
+	}
+	price, points := hold.Price(promo).RedeemPoints(req.RedeemPoints)
+
+	seats := make([]*entity.BookingSeat, 0, len(hold.Seats))
+	for _, held := range hold.Seats {
+		seats = append(seats, &entity.BookingSeat{SeatID: held.SeatID, Price: held.Price, Fare: held.Fare})
+	}
+
+	now := time.Now()
+	booking := &entity.Booking{
+		BookingReference:     authinfra.GenerateBookingReference(),
+		UserID:               &userID,
+		ShowtimeID:           hold.ShowtimeID,
+		NumTickets:           len(seats),
+		SubtotalAmount:       price.Subtotal,
+		DiscountAmount:       price.Discount,
+		FeeAmount:            price.Fee,
+		FinalAmount:          price.Total,
+		BookingStatus:        entity.BookingPending,
+		PaymentStatus:        entity.PaymentPending,
+		SalesChannel:         entity.ChannelOnline,
+		BookedAt:             now,
+		PointsRedeemed:       points,
+		PointsDiscountAmount: price.PointsDiscount,
+		// Payment must arrive before the seats would have been released
+		ExpiresAt: &hold.ExpiresAt,
+	}
+	if req.PaymentMethod != "" {
+		method := entity.PaymentMethod(req.PaymentMethod)
+		booking.PaymentMethod = &method
+	}
+	if promo != nil {
+		booking.PromoCode = &promo.Code
+		booking.PromoCodeID = &promo.ID
+	}
+
+	if err := s.createWithSeats(ctx, booking, seats); err != nil {
+		log.Warn("failed to confirm hold", zap.String("hold_id", hold.ID), zap.Error(err))
+		return nil, err
+	}
+	s.publishSeats(ctx, hold.ShowtimeID, hold.SeatIDs(), SeatStatusBooked)
+	s.metrics.BookingCreated(ctx)
+
+	if len(hold.Devices) > 0 {
+		if err := s.deviceRepo.Reserve(ctx, booking, hold.Devices); err != nil {
+			log.Warn("failed to reserve assistive devices, staff follow-up required",
+				zap.String("booking_reference", booking.BookingReference),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// The seats are booked now; a hold left behind only expires on its own
+	if err := s.holdRepo.Delete(ctx, hold); err != nil {
+		log.Warn("failed to release hold", zap.String("hold_id", hold.ID), zap.Error(err))
+	}
+
+	log.Info("booking created from hold",
+		zap.String("hold_id", hold.ID),
+		zap.String("booking_reference", booking.BookingReference),
+		zap.Int("seats", booking.NumTickets),
+	)
+
+	res := toConfirmBookingResponse(booking)
+	s.startPayment(ctx, booking, res)
+	return res, nil
+}
+
+// startPayment adds the checkout URL of a new booking to res. The booking
+// is already written, so a payment that cannot be started is reported in
+// res rather than failing the request; the customer retries it with
+// RetryPayment before the booking expires.
+func (s *Service) startPayment(ctx context.Context, booking *entity.Booking, res *ConfirmBookingResponse) {
+	if s.payments == nil {
+		return
+	}
+	checkoutURL, err := s.payments.StartPayment(ctx, booking)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to start payment of new booking",
+			zap.String("booking_reference", booking.BookingReference),
+			zap.Error(err),
+		)
+		res.PaymentError = "payment could not be started, retry it before the booking expires"
+		return
+	}
+	res.CheckoutURL = checkoutURL
+}
+
+// RetryPayment starts the payment of the user's pending booking again,
+// after starting it when the booking was confirmed failed
+func (s *Service) RetryPayment(ctx context.Context, userID, id uuid.UUID) (*ConfirmBookingResponse, error) {
+	if s.payments == nil {
+		return nil, apperrors.New(apperrors.CodeFailedPrecondition, "online payments are not available")
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID == nil || *booking.UserID != userID {
+		return nil, errBookingNotFound()
+	}
+	if booking.BookingStatus != entity.BookingPending || booking.PaymentStatus != entity.PaymentPending {
+		return nil, apperrors.New(apperrors.CodeConflict, "booking is not awaiting payment")
+	}
+	if booking.IsExpired() {
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "booking has expired, hold the seats again")
+	}
+
+	checkoutURL, err := s.payments.StartPayment(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+	res := toConfirmBookingResponse(booking)
+	res.CheckoutURL = checkoutURL
+	return res, nil
+}
+
+const (
+	// bookingVersionRetries bounds how often a booking is written again
+	// after another booking changed the showtime's seat count first
+	bookingVersionRetries = 3
+	// bookingVersionBackoff is waited before writing the booking again, so
+	// the bookings racing for a showtime do not collide again at once
+	bookingVersionBackoff = 10 * time.Millisecond
+)
+
+// createWithSeats stores the booking and its seats with its BookingCreated
+// event in the outbox, retrying when it lost the race for the showtime's
+// version. Nothing is written by a lost attempt, so retrying is safe; the
+// seats themselves are already held, and every attempt reads the
+// showtime's current version.
+func (s *Service) createWithSeats(ctx context.Context, booking *entity.Booking, seats []*entity.BookingSeat) error {
+	return RetryOnVersionConflict(ctx, s.logger, booking.ShowtimeID, func(ctx context.Context) error {
+		return s.tx.InTransaction(ctx, func(ctx context.Context) error {
+			if err := s.bookingRepo.CreateWithSeats(ctx, booking, seats); err != nil {
+				return err
+			}
+			return s.outbox.Add(ctx, events.BookingCreated{
+				BookingID:        booking.ID,
+				BookingReference: booking.BookingReference,
+				UserID:           booking.UserID,
+				ShowtimeID:       booking.ShowtimeID,
+				NumTickets:       booking.NumTickets,
+				FinalAmount:      booking.FinalAmount,
+				BookedAt:         booking.BookedAt,
+			})
+		})
+	})
+}
+
+// RetryOnVersionConflict runs fn, which writes a booking for showtimeID,
+// up to bookingVersionRetries times while it fails with a showtime version
+// conflict, waiting bookingVersionBackoff between attempts. fn must leave
+// nothing behind when it fails, e.g. by running in its own transaction.
+func RetryOnVersionConflict(ctx context.Context, log *logger.Logger, showtimeID uuid.UUID, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= bookingVersionRetries; attempt++ {
+		err = fn(ctx)
+		if !apperrors.Is(err, apperrors.CodeVersionConflict) || attempt == bookingVersionRetries {
+			return err
+		}
+		log.WithContext(ctx).Debug("showtime version conflict, retrying booking",
+			zap.String("showtime_id", showtimeID.String()),
+			zap.Int("attempt", attempt),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(bookingVersionBackoff):
+		}
+	}
+	return err
+}
+
+// CheckSeats reports whether seats are available, held or booked without
+// reserving them, so a selection can be re-checked just before the hold.
+// Locks and cached booked seats are read in one Redis round trip; Postgres
+// is only read when the booked seats are not cached, or to suggest
+// alternatives once some seats are gone. Seats are not validated against
+// the showtime.
+func (s *Service) CheckSeats(ctx context.Context, showtimeID uuid.UUID, req CheckSeatsRequest) (*CheckSeatsResponse, error) {
+	statuses, err := s.seatStatuses(ctx, showtimeID, req.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CheckSeatsResponse{
+		ShowtimeID:   showtimeID,
+		Seats:        statuses,
+		AllAvailable: true,
+	}
+	for _, seat := range statuses {
+		if seat.Status != SeatStatusAvailable {
+			res.AllAvailable = false
+		}
+	}
+
+	if !res.AllAvailable {
+		res.Alternatives = s.suggestAlternatives(ctx, showtimeID, req.SeatIDs)
+	}
+	return res, nil
+}
+
+// seatStatuses reads whether seats are available, held or booked
+func (s *Service) seatStatuses(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) ([]SeatStatusResponse, error) {
+	booked, held, err := s.probeSeats(ctx, showtimeID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bookedSet, heldSet := entity.UUIDList(booked), entity.UUIDList(held)
+	statuses := make([]SeatStatusResponse, 0, len(seatIDs))
+	for _, seatID := range seatIDs {
+		status := SeatStatusAvailable
+		switch {
+		case bookedSet.Contains(seatID):
+			status = SeatStatusBooked
+		case heldSet.Contains(seatID):
+			status = SeatStatusLocked
+		}
+		statuses = append(statuses, SeatStatusResponse{SeatID: seatID, Status: status})
+	}
+	return statuses, nil
+}
+
+// probeSeats returns the booked seats of a showtime and which of the seats
+// are held, with one Redis round trip. The booked seats come from their
+// cache, or on a miss from one query whose result is then cached.
+func (s *Service) probeSeats(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) (booked, held []uuid.UUID, err error) {
+	probe, err := s.holdRepo.ProbeSeats(ctx, showtimeID, seatIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	booked = probe.Booked
+	if booked == nil {
+		if booked, err = s.bookingSeatRepo.GetBookedSeatIDs(ctx, showtimeID); err != nil {
+			return nil, nil, err
+		}
+		// Cached at the version read before the query, so a booking made
+		// meanwhile cannot leave its seats out of the cache
+		if err := s.holdRepo.CacheBookedSeats(ctx, showtimeID, probe.Version, booked, s.cfg.BookedCacheTTL); err != nil {
+			s.logger.WithContext(ctx).Warn("failed to cache booked seats",
+				zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+		}
+	}
+	return booked, probe.Held, nil
+}
+
+// suggestAlternatives suggests a full selection of the same seat types.
+// Suggestions are a courtesy, so failures only leave them out.
+func (s *Service) suggestAlternatives(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) []uuid.UUID {
+	seats, err := s.seatRepo.GetByIDs(ctx, seatIDs)
+	if err != nil || len(seats) == 0 {
+		return nil
+	}
+
+	seatTypes := make([]entity.SeatType, 0, len(seats))
+	for _, seat := range seats {
+		seatTypes = append(seatTypes, seat.SeatType)
+	}
+	suggested, err := s.SuggestSeats(ctx, showtimeID, seatTypes)
+	if err != nil {
+		s.logger.WithContext(ctx).Debug("no alternative seats suggested",
+			zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+		return nil
+	}
+	return suggested
+}
+
+// GetSeatMap returns the seats of a showtime with their availability. While
+// sales are not open only the sales state is returned, unless a valid
+// pre-sale code is supplied. Private showtimes require their access code.
+func (s *Service) GetSeatMap(ctx context.Context, showtimeID uuid.UUID, presaleCode, accessCode string) (*SeatMapResponse, error) {
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !showtime.CanAccess(hashCode(accessCode)) {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+
+	state := showtime.SalesStateAt(time.Now(), showtime.Cinema.Location())
+	res := &SeatMapResponse{
+		ShowtimeID:  showtime.ID,
+		SalesState:  string(state),
+		SalesOpenAt: showtime.SalesOpenAt,
+		Capacity:    showtime.EffectiveCapacity(),
+		Available:   showtime.AvailableSeats,
+		SoldOut:     showtime.AvailableSeats == 0,
+	}
+	s.tracker.Track(ctx, analytics.Event{
+		Name:       analytics.EventSeatMapViewed,
+		ShowtimeID: &showtime.ID,
+		MovieID:    &showtime.MovieID,
+		Properties: map[string]any{
+			"sales_state": string(state),
+			"available":   showtime.AvailableSeats,
+		},
+	})
+
+	if state == entity.SalesNotOpen && showtime.PresaleCodeMatches(hashCode(presaleCode)) {
+		state = entity.SalesOnSale
+	}
+	if state != entity.SalesOnSale {
+		return res, nil
+	}
+
+	seats, err := s.seatRepo.GetByScreenID(ctx, showtime.ScreenID)
+	if err != nil {
+		return nil, err
+	}
+	// The frontend lays the map out in response order
+	entity.SortSeats(seats)
+
+	seatIDs := make([]uuid.UUID, 0, len(seats))
+	for _, seat := range seats {
+		seatIDs = append(seatIDs, seat.ID)
+	}
+	booked, held, err := s.probeSeats(ctx, showtime.ID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocked entity.UUIDList
+	if showtime.Distancing {
+		blocked = entity.CheckerboardBlocked(seats)
+	}
+
+	bookedSet, heldSet := entity.UUIDList(booked), entity.UUIDList(held)
+	statuses := make(map[uuid.UUID]string, len(seats))
+	taken := 0
+	for _, seat := range seats {
+		switch {
+		case bookedSet.Contains(seat.ID):
+			statuses[seat.ID] = SeatStatusBooked
+			taken++
+		case heldSet.Contains(seat.ID):
+			statuses[seat.ID] = SeatStatusLocked
+			taken++
+		case blocked.Contains(seat.ID):
+			statuses[seat.ID] = SeatStatusBlocked
+		default:
+			statuses[seat.ID] = SeatStatusAvailable
+		}
+	}
+
+	// Once the effective capacity is reached the seats left over cannot be sold
+	res.Available = max(min(res.Capacity-taken, showtime.AvailableSeats), 0)
+	res.SoldOut = res.Available == 0
+	if res.SoldOut {
+		for id, status := range statuses {
+			if status == SeatStatusAvailable {
+				statuses[id] = SeatStatusUnavailable
+			}
+		}
+	}
+
+	rules, err := s.ruleRepo.ListByShowtime(ctx, showtime.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		indexed := entity.NewSeatTypeRules(rules)
+		remaining, err := s.onlineRemaining(ctx, showtime.ID, indexed, seats, heldSet)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		for _, seat := range seats {
+			rule, ok := indexed[seat.SeatType]
+			if !ok || statuses[seat.ID] != SeatStatusAvailable {
+				continue
+			}
+			if !rule.OnSaleAt(now) {
+				statuses[seat.ID] = SeatStatusNotOnSale
+			} else if left, capped := remaining[seat.SeatType]; capped && left == 0 {
+				statuses[seat.ID] = SeatStatusBoxOfficeOnly
+			}
+		}
+		res.SeatTypes = toSeatTypeInventory(rules, remaining, now)
+	}
+
+	quote, err := s.pricer.Quote(ctx, showtime)
+	if err != nil {
+		return nil, err
+	}
+
+	companionPolicy := showtime.Cinema.CompanionPolicyEnabled
+	companionOf := make(map[uuid.UUID]uuid.UUID)
+	for _, seat := range seats {
+		if seat.SeatType == entity.SeatWheelchair && seat.CompanionSeatID != nil {
+			companionOf[*seat.CompanionSeatID] = seat.ID
+		}
+	}
+
+	for _, seat := range seats {
+		if !seat.IsActive {
+			continue
+		}
+
+		item := SeatMapSeatResponse{
+			SeatID:          seat.ID,
+			SeatLabel:       fmt.Sprintf("%s%d", seat.RowLabel, seat.SeatNumber),
+			RowLabel:        seat.RowLabel,
+			SeatNumber:      seat.SeatNumber,
+			SeatType:        string(seat.SeatType),
+			XPosition:       seat.XPosition,
+			YPosition:       seat.YPosition,
+			Status:          statuses[seat.ID],
+			Price:           quote.Price(seat.SeatType),
+			Fare:            string(entity.FareStandard),
+			CompanionSeatID: seat.CompanionSeatID,
+		}
+
+		if companionPolicy && seat.SeatType == entity.SeatCompanion {
+			item.Price = 0
+			item.Fare = string(entity.FareCompanion)
+			// A companion seat cannot be taken without its wheelchair seat
+			if wheelchairID, ok := companionOf[seat.ID]; !ok {
+				item.Status = SeatStatusUnavailable
+			} else if item.Status == SeatStatusAvailable {
+				item.Status = statuses[wheelchairID]
+			}
+		}
+
+		res.Seats = append(res.Seats, item)
+	}
+
+	return res, nil
+}
+
+// SuggestSeats picks available seats matching the given seat types, one
+// seat per entry, preferring seats of a type side by side in one row. It
+// returns nil when the showtime has no comparable availability or is not on
+// sale. Companion seats are never suggested since they depend on a paired
+// wheelchair seat.
+func (s *Service) SuggestSeats(ctx context.Context, showtimeID uuid.UUID, seatTypes []entity.SeatType) ([]uuid.UUID, error) {
+	needed := make(map[string]int)
+	var order []string
+	for _, seatType := range seatTypes {
+		if seatType == entity.SeatCompanion {
+			return nil, nil
+		}
+		if needed[string(seatType)] == 0 {
+			order = append(order, string(seatType))
+		}
+		needed[string(seatType)]++
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	seatMap, err := s.GetSeatMap(ctx, showtimeID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if seatMap.Available < len(seatTypes) {
+		return nil, nil
+	}
+
+	available := make(map[string][]SeatMapSeatResponse)
+	for _, seat := range seatMap.Seats {
+		if seat.Status == SeatStatusAvailable {
+			available[seat.SeatType] = append(available[seat.SeatType], seat)
+		}
+	}
+
+	suggested := make([]uuid.UUID, 0, len(seatTypes))
+	for _, seatType := range order {
+		picked := pickSeats(available[seatType], needed[seatType])
+		if picked == nil {
+			return nil, nil
+		}
+		suggested = append(suggested, picked...)
+	}
+	return suggested, nil
+}
+
+// pickSeats returns n seats from candidates, ordered by row and number,
+// taking the first run of adjacent seats in one row if there is one
+func pickSeats(candidates []SeatMapSeatResponse, n int) []uuid.UUID {
+	if len(candidates) < n {
+		return nil
+	}
+
+	for start := 0; start+n <= len(candidates); start++ {
+		first := candidates[start]
+		adjacent := true
+		for i := 1; i < n; i++ {
+			seat := candidates[start+i]
+			if seat.RowLabel != first.RowLabel || seat.SeatNumber != first.SeatNumber+i {
+				adjacent = false
+				break
+			}
+		}
+		if adjacent {
+			return seatIDsOf(candidates[start : start+n])
+		}
+	}
+	return seatIDsOf(candidates[:n])
+}
+
+func seatIDsOf(seats []SeatMapSeatResponse) []uuid.UUID {
+	ids := make([]uuid.UUID, len(seats))
+	for i, seat := range seats {
+		ids[i] = seat.SeatID
+	}
+	return ids
+}
+
+// checkCapacity enforces a showtime's capacity overrides on a hold: seats
+// blocked by distancing cannot be taken, and booked plus held seats may not
+// exceed the effective capacity
+func (s *Service) checkCapacity(ctx context.Context, showtime *entity.Showtime, seats []*entity.Seat, booked entity.UUIDList) error {
+	screenSeats, err := s.seatRepo.GetByScreenID(ctx, showtime.ScreenID)
+	if err != nil {
+		return err
+	}
+
+	if showtime.Distancing {
+		blocked := entity.CheckerboardBlocked(screenSeats)
+		for _, seat := range seats {
+			if blocked.Contains(seat.ID) {
+				return apperrors.New(apperrors.CodeSeatNotAvailable,
+					fmt.Sprintf("seat %s%d is blocked for distancing", seat.RowLabel, seat.SeatNumber))
+			}
+		}
+	}
+
+	seatIDs := make([]uuid.UUID, 0, len(screenSeats))
+	for _, seat := range screenSeats {
+		seatIDs = append(seatIDs, seat.ID)
+	}
+	held, err := s.holdRepo.GetHeldSeatIDs(ctx, showtime.ID, seatIDs)
+	if err != nil {
+		return err
+	}
+
+	heldSet := entity.UUIDList(held)
+	taken := 0
+	for _, id := range seatIDs {
+		if booked.Contains(id) || heldSet.Contains(id) {
+			taken++
+		}
+	}
+
+	remaining := max(min(showtime.EffectiveCapacity()-taken, showtime.AvailableSeats), 0)
+	if len(seats) > remaining {
+		return apperrors.New(apperrors.CodeShowtimeFull, "not enough seats left within the showtime's capacity").
+			WithDetails(map[string]int{"capacity": showtime.EffectiveCapacity(), "remaining": remaining})
+	}
+	return nil
+}
+
+// checkSeatTypes enforces the showtime's per-seat-type rules on a hold:
+// seat types not on sale online yet cannot be held, and capped types cannot
+// be held beyond what is left of their online sales cap. Booking creation
+// checks the caps again against Postgres.
+func (s *Service) checkSeatTypes(ctx context.Context, showtime *entity.Showtime, seats []*entity.Seat) error {
+	rules, err := s.ruleRepo.ListByShowtime(ctx, showtime.ID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	indexed := entity.NewSeatTypeRules(rules)
+
+	now := time.Now()
+	requested := make(map[entity.SeatType]int)
+	for _, seat := range seats {
+		rule, ok := indexed[seat.SeatType]
+		if !ok {
+			continue
+		}
+		if !rule.OnSaleAt(now) {
+			return apperrors.New(apperrors.CodeSeatTypeNotOnSale,
+				fmt.Sprintf("%s seats for this showtime are not on sale yet", seat.SeatType)).
+				WithDetails(map[string]any{"seat_type": seat.SeatType, "sales_open_at": rule.SalesOpenAt})
+		}
+		if rule.OnlineSalesCap != nil {
+			requested[seat.SeatType]++
+		}
+	}
+	if len(requested) == 0 {
+		return nil
+	}
+
+	screenSeats, err := s.seatRepo.GetByScreenID(ctx, showtime.ScreenID)
+	if err != nil {
+		return err
+	}
+	seatIDs := make([]uuid.UUID, 0, len(screenSeats))
+	for _, seat := range screenSeats {
+		seatIDs = append(seatIDs, seat.ID)
+	}
+	held, err := s.holdRepo.GetHeldSeatIDs(ctx, showtime.ID, seatIDs)
+	if err != nil {
+		return err
+	}
+
+	remaining, err := s.onlineRemaining(ctx, showtime.ID, indexed, screenSeats, entity.UUIDList(held))
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if n := requested[rule.SeatType]; n > remaining[rule.SeatType] {
+			return apperrors.New(apperrors.CodeSeatTypeCapReached,
+				fmt.Sprintf("online sales of %s seats are capped at %d", rule.SeatType, *rule.OnlineSalesCap)).
+				WithDetails(map[string]any{
+					"seat_type":        rule.SeatType,
+					"online_sales_cap": *rule.OnlineSalesCap,
+					"remaining":        remaining[rule.SeatType],
+				})
+		}
+	}
+	return nil
+}
+
+// WarmSeatCaches loads a showtime's booked seats, and its online sales per
+// seat type if any type is capped, into the cache ahead of traffic
+func (s *Service) WarmSeatCaches(ctx context.Context, showtimeID uuid.UUID) error {
+	probe, err := s.holdRepo.ProbeSeats(ctx, showtimeID, nil)
+	if err != nil {
+		return err
+	}
+	if probe.Booked == nil {
+		booked, err := s.bookingSeatRepo.GetBookedSeatIDs(ctx, showtimeID)
+		if err != nil {
+			return err
+		}
+		if err := s.holdRepo.CacheBookedSeats(ctx, showtimeID, probe.Version, booked, s.cfg.BookedCacheTTL); err != nil {
+			return err
+		}
+	}
+
+	rules, err := s.ruleRepo.ListByShowtime(ctx, showtimeID)
+	if err != nil {
+		return err
+	}
+	if len(entity.NewSeatTypeRules(rules).Capped()) == 0 {
+		return nil
+	}
+	_, err = s.onlineSold(ctx, showtimeID)
+	return err
+}
+
+// onlineRemaining returns how many seats of each capped type can still be
+// sold online: the cap less the seats sold online and the seats held
+func (s *Service) onlineRemaining(ctx context.Context, showtimeID uuid.UUID, rules entity.SeatTypeRules, seats []*entity.Seat, held entity.UUIDList) (map[entity.SeatType]int, error) {
+	capped := rules.Capped()
+	if len(capped) == 0 {
+		return nil, nil
+	}
+
+	sold, err := s.onlineSold(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, seat := range seats {
+		if held.Contains(seat.ID) {
+			sold[seat.SeatType]++
+		}
+	}
+
+	remaining := make(map[entity.SeatType]int, len(capped))
+	for _, seatType := range capped {
+		remaining[seatType], _ = rules[seatType].Remaining(sold[seatType])
+	}
+	return remaining, nil
+}
+
+// onlineSold returns the seats sold online per seat type. Counts are cached
+// with the booked seats and counted in Postgres on a miss.
+func (s *Service) onlineSold(ctx context.Context, showtimeID uuid.UUID) (map[entity.SeatType]int, error) {
+	sold, version, err := s.holdRepo.GetOnlineSold(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if sold != nil {
+		return sold, nil
+	}
+
+	if sold, err = s.ruleRepo.CountOnlineSold(ctx, showtimeID); err != nil {
+		return nil, err
+	}
+	// Cached at the version read before the count, like the booked seats
+	if err := s.holdRepo.CacheOnlineSold(ctx, showtimeID, version, sold, s.cfg.BookedCacheTTL); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to cache seat type sales",
+			zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+	}
+	return sold, nil
+}
+
+// checkDevices validates a hold's assistive device requests against the
+// screen's stock left after confirmed bookings. Stock is checked again when
+// the booking is confirmed, since the hold does not reserve it.
+func (s *Service) checkDevices(ctx context.Context, showtime *entity.Showtime, tickets int, reqs []DeviceRequest) ([]entity.DeviceRequest, error) {
+	requests := make([]entity.DeviceRequest, 0, len(reqs))
+	seen := make(map[entity.DeviceType]bool, len(reqs))
+	for _, req := range reqs {
+		deviceType := entity.DeviceType(req.DeviceType)
+		if seen[deviceType] {
+			return nil, apperrors.ErrBadRequest("device type " + req.DeviceType + " is requested more than once")
+		}
+		if req.Quantity > tickets {
+			return nil, apperrors.ErrBadRequest("cannot request more devices than tickets")
+		}
+		seen[deviceType] = true
+		requests = append(requests, entity.DeviceRequest{DeviceType: deviceType, Quantity: req.Quantity})
+	}
+
+	stock, err := s.deviceRepo.ListByScreen(ctx, showtime.ScreenID)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.deviceRepo.Reserved(ctx, showtime.ID)
+	if err != nil {
+		return nil, err
+	}
+	if shortages := entity.CheckDevices(requests, stock, reserved); len(shortages) > 0 {
+		return nil, apperrors.New(apperrors.CodeDeviceUnavailable, "not enough assistive devices available").
+			WithDetails(shortages)
+	}
+	return requests, nil
+}
+
+// checkSalesWindow returns an error unless tickets for the showtime can be
+// sold at now. A valid pre-sale code bypasses the open time, not the close.
+func checkSalesWindow(showtime *entity.Showtime, presaleCode string, now time.Time) error {
+	switch showtime.SalesStateAt(now, showtime.Cinema.Location()) {
+	case entity.SalesClosed:
+		return apperrors.New(apperrors.CodeSalesClosed, "ticket sales for this showtime have closed")
+	case entity.SalesNotOpen:
+		if showtime.PresaleCodeMatches(hashCode(presaleCode)) {
+			return nil
+		}
+		return apperrors.New(apperrors.CodeSalesNotOpen, "ticket sales for this showtime have not opened yet").
+			WithDetails(map[string]time.Time{"sales_open_at": *showtime.SalesOpenAt})
+	}
+	return nil
+}
+
+func hashCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	return authinfra.HashToken(code)
+}
+
+// priceSeats prices each seat from the showtime's quote. When the cinema's
+// companion policy is enabled a COMPANION seat is free but only together
+// with the wheelchair seat it is linked to.
+func priceSeats(showtime *entity.Showtime, quote *pricing.Quote, seats []*entity.Seat) ([]entity.HeldSeat, error) {
+	companionPolicy := showtime.Cinema.CompanionPolicyEnabled
+
+	// companion seat ID -> wheelchair seat ID, for wheelchair seats in this request
+	pairs := make(map[uuid.UUID]uuid.UUID)
+	for _, seat := range seats {
+		if seat.SeatType == entity.SeatWheelchair && seat.CompanionSeatID != nil {
+			pairs[*seat.CompanionSeatID] = seat.ID
+		}
+	}
+
+	held := make([]entity.HeldSeat, 0, len(seats))
+	for _, seat := range seats {
+		item := entity.HeldSeat{
+			SeatID:    seat.ID,
+			SeatLabel: fmt.Sprintf("%s%d", seat.RowLabel, seat.SeatNumber),
+			SeatType:  seat.SeatType,
+			Price:     quote.Price(seat.SeatType),
+			Fare:      entity.FareStandard,
+		}
+
+		if companionPolicy && seat.SeatType == entity.SeatCompanion {
+			wheelchairID, ok := pairs[seat.ID]
+			if !ok {
+				return nil, apperrors.ErrBadRequest("companion seat " + item.SeatLabel + " can only be booked together with its wheelchair seat")
+			}
+			item.Price = 0
+			item.Fare = entity.FareCompanion
+			item.PairedSeatID = &wheelchairID
+		}
+
+		held = append(held, item)
+	}
+
+	return held, nil
+}
+
+// ToHoldResponse converts a hold to its response form
+func ToHoldResponse(hold *entity.SeatHold) *HoldResponse {
+	seats := make([]HeldSeatResponse, 0, len(hold.Seats))
+	for _, seat := range hold.Seats {
+		fare := seat.Fare
+		if fare == "" {
+			fare = entity.FareStandard
+		}
+		seats = append(seats, HeldSeatResponse{
+			SeatID:    seat.SeatID,
+			SeatLabel: seat.SeatLabel,
+			SeatType:  string(seat.SeatType),
+			Price:     seat.Price,
+			Fare:      string(fare),
+		})
+	}
+
+	return &HoldResponse{
+		HoldID:           hold.ID,
+		ShowtimeID:       hold.ShowtimeID,
+		Seats:            seats,
+		Subtotal:         hold.Subtotal,
+		Fee:              hold.Fee,
+		Total:            hold.Total(),
+		ExpiresAt:        hold.ExpiresAt,
+		AssistiveDevices: hold.Devices,
+	}
+}
+
+func toConfirmBookingResponse(booking *entity.Booking) *ConfirmBookingResponse {
+	return &ConfirmBookingResponse{
+		BookingID:        booking.ID,
+		BookingReference: booking.BookingReference,
+		ShowtimeID:       booking.ShowtimeID,
+		NumTickets:       booking.NumTickets,
+		BookingStatus:    string(booking.BookingStatus),
+		PaymentStatus:    string(booking.PaymentStatus),
+		Subtotal:         booking.SubtotalAmount,
+		Discount:         booking.DiscountAmount,
+		Fee:              booking.FeeAmount,
+		Total:            booking.FinalAmount,
+		PointsRedeemed:   booking.PointsRedeemed,
+		PointsDiscount:   booking.PointsDiscountAmount,
+		PayBy:            booking.ExpiresAt,
+	}
+}
+
+// pendingExpiryBatchSize caps the bookings expired per transaction
+const pendingExpiryBatchSize = 200
+
+// ExpirePendingBookings expires unpaid bookings past their payment deadline
+// and releases their seats
+func (s *Service) ExpirePendingBookings(ctx context.Context) error {
+	now := time.Now()
+	expired := 0
+	released := make(map[uuid.UUID][]uuid.UUID) // seats by showtime
+	for {
+		bookings, err := s.bookingRepo.ExpirePending(ctx, now, pendingExpiryBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, booking := range bookings {
+			released[booking.ShowtimeID] = append(released[booking.ShowtimeID], bookedSeatIDs(booking)...)
+		}
+		expired += len(bookings)
+		if len(bookings) < pendingExpiryBatchSize {
+			break
+		}
+	}
+	if expired == 0 {
+		return nil
+	}
+
+	// The seat locks went with the hold when the booking was made; the
+	// cached booked seats still list the released seats
+	for showtimeID, seatIDs := range released {
+		if err := s.holdRepo.InvalidateBookedSeats(ctx, showtimeID); err != nil {
+			s.logger.Warn("failed to invalidate booked seats", zap.String("showtime_id", showtimeID.String()), zap.Error(err))
+		}
+		s.publishReleased(ctx, showtimeID, seatIDs)
+	}
+
+	s.logger.Info("expired unpaid bookings",
+		zap.Int("bookings", expired),
+		zap.Int("showtimes", len(released)),
+	)
+	return nil
+}
+
+// ReleaseUnpaidBooking expires a pending booking whose checkout closed
+// unpaid, so its seats go back on sale without waiting for the payment
+// deadline. A booking that is no longer pending is left as it is.
+func (s *Service) ReleaseUnpaidBooking(ctx context.Context, bookingID uuid.UUID) error {
+	booking, err := s.bookingRepo.Expire(ctx, bookingID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeInvalidStatus) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.holdRepo.InvalidateBookedSeats(ctx, booking.ShowtimeID); err != nil {
+		s.logger.Warn("failed to invalidate booked seats", zap.String("showtime_id", booking.ShowtimeID.String()), zap.Error(err))
+	}
+	s.publishReleased(ctx, booking.ShowtimeID, bookedSeatIDs(booking))
+
+	s.logger.WithContext(ctx).Info("released seats of unpaid booking",
+		zap.String("booking_reference", booking.BookingReference),
+		zap.Int("seats", booking.NumTickets),
+	)
+	return nil
+}
+
+// heldCountBatchSize caps the expired holds taken out of the held counts per call
+const heldCountBatchSize = 500
+
+// ExpireHeldCounts takes holds that have expired out of the per-showtime
+// held counts used by the availability summary, and tells the clients
+// watching their showtimes that the seats were released
+func (s *Service) ExpireHeldCounts(ctx context.Context) error {
+	now := time.Now()
+	for {
+		n, holds, err := s.holdRepo.ExpireHeldCounts(ctx, now, heldCountBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			s.publishReleased(ctx, hold.ShowtimeID, hold.SeatIDs())
+		}
+		if n < heldCountBatchSize {
+			return nil
+		}
+	}
+}
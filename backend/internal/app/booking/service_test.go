@@ -0,0 +1,673 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/pricing"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// stubShowtimes serves a single showtime
+type stubShowtimes struct {
+	repository.ShowtimeRepository
+	showtime *entity.Showtime
+}
+
+func (s *stubShowtimes) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	if s.showtime.ID != id {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	copied := *s.showtime
+	return &copied, nil
+}
+
+func (s *stubShowtimes) GetByID(ctx context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	return s.GetByIDWithDetails(ctx, id)
+}
+
+// inlineTx runs the work as it comes; the fakes it touches do not roll back
+type inlineTx struct{}
+
+func (inlineTx) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// memOutbox records the events added to the outbox
+type memOutbox struct {
+	repository.OutboxRepository
+	mu     sync.Mutex
+	events []*entity.OutboxEvent
+}
+
+func (m *memOutbox) Add(_ context.Context, events []*entity.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	return nil
+}
+
+// decoded returns the recorded events named name
+func (m *memOutbox) decoded(t *testing.T, name string) []eventbus.Event {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var decoded []eventbus.Event
+	for _, record := range m.events {
+		if record.EventName != name {
+			continue
+		}
+		event, err := events.Decode(record.EventName, []byte(record.Payload))
+		if err != nil {
+			t.Fatalf("decode %s: %v", record.EventName, err)
+		}
+		decoded = append(decoded, event)
+	}
+	return decoded
+}
+
+func TestHoldRefusedOnceTheMovieIsPulled(t *testing.T) {
+	showtime := &entity.Showtime{
+		ID:        uuid.New(),
+		ShowDate:  time.Now().AddDate(0, 0, 7),
+		StartTime: "20:00",
+		Status:    entity.ShowtimeScheduled,
+		Movie:     entity.Movie{ID: uuid.New(), Title: "Dune: Part Two"},
+		Screen:    entity.Screen{ID: uuid.New(), Name: "Screen 1", IsActive: true},
+	}
+	svc := NewService(nil, &stubShowtimes{showtime: showtime}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	for _, pull := range []func(){
+		func() { showtime.Movie.IsActive = false },
+		func() { showtime.Movie.IsActive = true; showtime.Screen.IsActive = false },
+	} {
+		pull()
+		_, err := svc.HoldSeats(context.Background(), uuid.New(), HoldSeatsRequest{
+			ShowtimeID: showtime.ID,
+			SeatIDs:    []uuid.UUID{uuid.New()},
+		})
+		if !apperrors.Is(err, apperrors.CodeBadRequest) {
+			t.Errorf("HoldSeats = %v, want the showtime closed for booking", err)
+		}
+	}
+}
+
+func TestHoldOnASoldOutShowtimeOffersTheWaitlist(t *testing.T) {
+	showtime := &entity.Showtime{
+		ID:             uuid.New(),
+		ShowDate:       time.Now().AddDate(0, 0, 7),
+		StartTime:      "20:00",
+		Status:         entity.ShowtimeScheduled,
+		TotalSeats:     100,
+		AvailableSeats: 0,
+		Movie:          entity.Movie{ID: uuid.New(), Title: "Dune: Part Two", IsActive: true},
+		Screen:         entity.Screen{ID: uuid.New(), Name: "Screen 1", IsActive: true},
+	}
+	svc := NewService(nil, &stubShowtimes{showtime: showtime}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	_, err := svc.HoldSeats(context.Background(), uuid.New(), HoldSeatsRequest{
+		ShowtimeID: showtime.ID,
+		SeatIDs:    []uuid.UUID{uuid.New()},
+	})
+	if !apperrors.Is(err, apperrors.CodeShowtimeFull) {
+		t.Fatalf("HoldSeats = %v, want %s", err, apperrors.CodeShowtimeFull)
+	}
+	if details, _ := err.(*apperrors.AppError).Details.(map[string]bool); !details["waitlist"] {
+		t.Errorf("details = %v, want the waitlist offered", err.(*apperrors.AppError).Details)
+	}
+}
+
+// memProbeHolds keeps seat locks and a versioned booked seat cache, the way
+// the Redis repository does
+type memProbeHolds struct {
+	repository.SeatHoldRepository
+	held    entity.UUIDList
+	booked  []uuid.UUID // nil while the cache is cold
+	version int64
+}
+
+func (m *memProbeHolds) ProbeSeats(_ context.Context, _ uuid.UUID, seatIDs []uuid.UUID) (*entity.SeatProbe, error) {
+	probe := &entity.SeatProbe{Held: []uuid.UUID{}, Booked: m.booked, Version: m.version}
+	for _, id := range seatIDs {
+		if m.held.Contains(id) {
+			probe.Held = append(probe.Held, id)
+		}
+	}
+	return probe, nil
+}
+
+func (m *memProbeHolds) CacheBookedSeats(_ context.Context, _ uuid.UUID, version int64, seatIDs []uuid.UUID, _ time.Duration) error {
+	if version == m.version {
+		m.booked = append([]uuid.UUID{}, seatIDs...)
+	}
+	return nil
+}
+
+func (m *memProbeHolds) InvalidateBookedSeats(context.Context, uuid.UUID) error {
+	m.version++
+	m.booked = nil
+	return nil
+}
+
+func (m *memProbeHolds) GetHeldSeatIDs(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) ([]uuid.UUID, error) {
+	probe, _ := m.ProbeSeats(ctx, showtimeID, seatIDs)
+	return probe.Held, nil
+}
+
+// memBookedSeats counts reads of the booked seats. onRead runs after the
+// seats are read, to land a booking while the caller is still working.
+type memBookedSeats struct {
+	repository.BookingSeatRepository
+	booked []uuid.UUID
+	reads  int
+	onRead func()
+}
+
+func (m *memBookedSeats) GetBookedSeatIDs(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	m.reads++
+	booked := append([]uuid.UUID{}, m.booked...)
+	if m.onRead != nil {
+		m.onRead()
+		m.onRead = nil
+	}
+	return booked, nil
+}
+
+type memSeats struct {
+	repository.SeatRepository
+	seats []*entity.Seat
+}
+
+func (m *memSeats) GetByScreenID(context.Context, uuid.UUID) ([]*entity.Seat, error) {
+	return m.seats, nil
+}
+
+func (m *memSeats) GetByIDs(_ context.Context, ids []uuid.UUID) ([]*entity.Seat, error) {
+	var seats []*entity.Seat
+	for _, seat := range m.seats {
+		if entity.UUIDList(ids).Contains(seat.ID) {
+			seats = append(seats, seat)
+		}
+	}
+	return seats, nil
+}
+
+// noRules is a showtime without seat type rules
+type noRules struct {
+	repository.SeatTypeRuleRepository
+}
+
+func (noRules) ListByShowtime(context.Context, uuid.UUID) ([]*entity.SeatTypeRule, error) {
+	return nil, nil
+}
+
+// noPricing prices every seat at zero
+type noPricing struct{}
+
+func (noPricing) Quote(context.Context, *entity.Showtime) (*pricing.Quote, error) {
+	return &pricing.Quote{}, nil
+}
+
+// memSeatFeed records the seat updates published per showtime
+type memSeatFeed struct {
+	repository.SeatUpdateFeed
+	published map[uuid.UUID][]repository.SeatUpdate
+}
+
+func (m *memSeatFeed) Publish(_ context.Context, showtimeID uuid.UUID, updates []repository.SeatUpdate) error {
+	if m.published == nil {
+		m.published = make(map[uuid.UUID][]repository.SeatUpdate)
+	}
+	m.published[showtimeID] = append(m.published[showtimeID], updates...)
+	return nil
+}
+
+// statuses returns the last published status of each seat of a showtime
+func (m *memSeatFeed) statuses(showtimeID uuid.UUID) map[uuid.UUID]string {
+	statuses := make(map[uuid.UUID]string)
+	for _, update := range m.published[showtimeID] {
+		statuses[update.SeatID] = update.Status
+	}
+	return statuses
+}
+
+type probeFixture struct {
+	svc      *Service
+	holds    *memProbeHolds
+	bookings *memBookedSeats
+	showtime *entity.Showtime
+	seats    []*entity.Seat // A1-A4
+}
+
+func newProbeFixture() *probeFixture {
+	screenID := uuid.New()
+	f := &probeFixture{
+		holds:    &memProbeHolds{},
+		bookings: &memBookedSeats{},
+	}
+	for i := 1; i <= 4; i++ {
+		f.seats = append(f.seats, &entity.Seat{ID: uuid.New(), ScreenID: screenID, RowLabel: "A",
+			SeatNumber: i, SeatType: entity.SeatStandard, IsActive: true})
+	}
+	f.showtime = &entity.Showtime{
+		ID:             uuid.New(),
+		ScreenID:       screenID,
+		ShowDate:       time.Now().AddDate(0, 0, 7),
+		StartTime:      "20:00",
+		Status:         entity.ShowtimeScheduled,
+		TotalSeats:     4,
+		AvailableSeats: 4,
+	}
+	f.svc = NewService(f.holds, &stubShowtimes{showtime: f.showtime}, &memSeats{seats: f.seats}, nil, f.bookings, nil, nil, noRules{}, nil, nil, nil, nil, noPricing{}, nil, nil, nil, nil, nil,
+		config.BookingConfig{BookedCacheTTL: time.Minute}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	return f
+}
+
+// book records seats as booked the way a confirmed booking does: the rows
+// are written, then the hold is deleted, which invalidates the cache
+func (f *probeFixture) book(seatIDs ...uuid.UUID) {
+	f.bookings.booked = append(f.bookings.booked, seatIDs...)
+	f.holds.InvalidateBookedSeats(context.Background(), f.showtime.ID)
+}
+
+func (f *probeFixture) check(t *testing.T, seatIDs ...uuid.UUID) *CheckSeatsResponse {
+	t.Helper()
+	res, err := f.svc.CheckSeats(context.Background(), f.showtime.ID, CheckSeatsRequest{SeatIDs: seatIDs})
+	if err != nil {
+		t.Fatalf("CheckSeats: %v", err)
+	}
+	return res
+}
+
+func TestCheckSeatsStatuses(t *testing.T) {
+	f := newProbeFixture()
+	a1, a2, a3 := f.seats[0].ID, f.seats[1].ID, f.seats[2].ID
+	f.bookings.booked = []uuid.UUID{a1}
+	f.holds.held = entity.UUIDList{a1, a2}
+
+	res := f.check(t, a1, a2, a3)
+	want := []string{SeatStatusBooked, SeatStatusLocked, SeatStatusAvailable}
+	for i, seat := range res.Seats {
+		if seat.Status != want[i] {
+			t.Errorf("seat %d: status = %s, want %s", i, seat.Status, want[i])
+		}
+	}
+	if res.AllAvailable {
+		t.Error("AllAvailable is set with seats taken")
+	}
+}
+
+func TestCheckSeatsUsesTheWarmCache(t *testing.T) {
+	f := newProbeFixture()
+	a1 := f.seats[0].ID
+
+	if res := f.check(t, a1); !res.AllAvailable {
+		t.Fatalf("seats = %+v, want available", res.Seats)
+	}
+	if f.bookings.reads != 1 {
+		t.Fatalf("cold cache: %d reads, want 1", f.bookings.reads)
+	}
+	f.check(t, a1)
+	if f.bookings.reads != 1 {
+		t.Errorf("warm cache: %d reads, want no more", f.bookings.reads)
+	}
+}
+
+func TestCheckSeatsNeverServesStaleAvailability(t *testing.T) {
+	f := newProbeFixture()
+	a1 := f.seats[0].ID
+
+	t.Run("booking after the cache was filled", func(t *testing.T) {
+		f.check(t, a1)
+		f.book(a1)
+
+		if res := f.check(t, a1); res.Seats[0].Status != SeatStatusBooked {
+			t.Errorf("status = %s after the booking, want BOOKED", res.Seats[0].Status)
+		}
+	})
+
+	t.Run("booking during the fallback read", func(t *testing.T) {
+		a2 := f.seats[1].ID
+		f.holds.InvalidateBookedSeats(context.Background(), f.showtime.ID)
+		f.bookings.onRead = func() { f.book(a2) }
+
+		// The probe read the seats before the booking landed; that answer
+		// is only as stale as the request itself, but must not be cached
+		f.check(t, a2)
+		if f.holds.booked != nil {
+			t.Fatalf("the stale read was cached: %v", f.holds.booked)
+		}
+		if res := f.check(t, a2); res.Seats[0].Status != SeatStatusBooked {
+			t.Errorf("status = %s on the next probe, want BOOKED", res.Seats[0].Status)
+		}
+	})
+}
+
+func TestCheckSeatsSuggestsAlternatives(t *testing.T) {
+	f := newProbeFixture()
+	a1, a2 := f.seats[0].ID, f.seats[1].ID
+	f.book(a1)
+
+	res := f.check(t, a1, a2)
+	if res.AllAvailable {
+		t.Fatal("AllAvailable is set with a booked seat")
+	}
+	want := []uuid.UUID{f.seats[1].ID, f.seats[2].ID}
+	if len(res.Alternatives) != 2 || res.Alternatives[0] != want[0] || res.Alternatives[1] != want[1] {
+		t.Errorf("alternatives = %v, want A2-A3 %v", res.Alternatives, want)
+	}
+
+	// Nothing is suggested while the selection is available
+	if res := f.check(t, a2); res.Alternatives != nil {
+		t.Errorf("alternatives = %v for an available selection", res.Alternatives)
+	}
+}
+
+// releasableHold is a single hold that can be released once
+type releasableHold struct {
+	memHold
+	released int
+}
+
+func (m *releasableHold) Release(_ context.Context, hold *entity.SeatHold) (int, error) {
+	m.hold = nil
+	m.released++
+	return len(hold.Seats), nil
+}
+
+func (m *releasableHold) ProbeSeats(context.Context, uuid.UUID, []uuid.UUID) (*entity.SeatProbe, error) {
+	return &entity.SeatProbe{Booked: []uuid.UUID{}}, nil
+}
+
+// stubGroups knows the hold, if any, that a group checkout is paying for
+type stubGroups struct {
+	repository.GroupCheckoutRepository
+	holdID string
+}
+
+func (s *stubGroups) GetActiveByHoldID(_ context.Context, holdID string) (*entity.GroupCheckout, error) {
+	if holdID != s.holdID {
+		return nil, nil
+	}
+	return &entity.GroupCheckout{HoldID: holdID}, nil
+}
+
+func TestReleaseHold(t *testing.T) {
+	ctx := context.Background()
+	userID, showtimeID := uuid.New(), uuid.New()
+	newHold := func() *entity.SeatHold {
+		return &entity.SeatHold{
+			ID: "hold-1", UserID: userID, ShowtimeID: showtimeID,
+			Seats: []entity.HeldSeat{{SeatID: uuid.New()}, {SeatID: uuid.New()}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		req      ReleaseHoldRequest
+		grouped  bool
+		want     apperrors.ErrorCode // empty on success
+		released int
+	}{
+		{"owner", userID, ReleaseHoldRequest{HoldID: "hold-1", ShowtimeID: showtimeID}, false, "", 2},
+		{"already ended", userID, ReleaseHoldRequest{HoldID: "hold-2", ShowtimeID: showtimeID}, false, "", 0},
+		{"another user", uuid.New(), ReleaseHoldRequest{HoldID: "hold-1", ShowtimeID: showtimeID}, false, apperrors.CodeForbidden, 0},
+		{"another showtime", userID, ReleaseHoldRequest{HoldID: "hold-1", ShowtimeID: uuid.New()}, false, apperrors.CodeBadRequest, 0},
+		{"group checkout", userID, ReleaseHoldRequest{HoldID: "hold-1", ShowtimeID: showtimeID}, true, apperrors.CodeConflict, 0},
+	}
+	for _, tt := range tests {
+		holds := &releasableHold{memHold: memHold{hold: newHold()}}
+		groups := &stubGroups{}
+		if tt.grouped {
+			groups.holdID = "hold-1"
+		}
+		feed := &memSeatFeed{}
+		svc := &Service{holdRepo: holds, groupRepo: groups, seatUpdates: feed, logger: &logger.Logger{Logger: zap.NewNop()}}
+
+		res, err := svc.ReleaseHold(ctx, tt.userID, tt.req)
+		if tt.want != "" {
+			if !apperrors.Is(err, tt.want) {
+				t.Errorf("%s: ReleaseHold = %v, want %s", tt.name, err, tt.want)
+			}
+			if holds.released != 0 {
+				t.Errorf("%s: hold released", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: ReleaseHold: %v", tt.name, err)
+		}
+		if res.ReleasedSeats != tt.released {
+			t.Errorf("%s: released %d seats, want %d", tt.name, res.ReleasedSeats, tt.released)
+		}
+		if statuses := feed.statuses(showtimeID); len(statuses) != tt.released {
+			t.Errorf("%s: published %v, want the %d released seats", tt.name, statuses, tt.released)
+		} else {
+			for seatID, status := range statuses {
+				if status != SeatStatusAvailable {
+					t.Errorf("%s: seat %s published as %s", tt.name, seatID, status)
+				}
+			}
+		}
+	}
+}
+
+func TestGetSeatMapReadsThroughTheBookedSeatCache(t *testing.T) {
+	f := newProbeFixture()
+	a1, a2 := f.seats[0].ID, f.seats[1].ID
+	f.bookings.booked = []uuid.UUID{a1}
+	f.holds.held = entity.UUIDList{a2}
+
+	for range 2 {
+		res, err := f.svc.GetSeatMap(context.Background(), f.showtime.ID, "", "")
+		if err != nil {
+			t.Fatalf("GetSeatMap: %v", err)
+		}
+		want := []string{SeatStatusBooked, SeatStatusLocked, SeatStatusAvailable, SeatStatusAvailable}
+		for i, seat := range res.Seats {
+			if seat.Status != want[i] {
+				t.Errorf("%s: status = %s, want %s", seat.SeatLabel, seat.Status, want[i])
+			}
+		}
+		if res.Available != 2 {
+			t.Errorf("available = %d, want 2", res.Available)
+		}
+	}
+	if f.bookings.reads != 1 {
+		t.Errorf("%d booked seat queries for two seat maps, want 1", f.bookings.reads)
+	}
+
+	f.book(f.seats[2].ID)
+	res, _ := f.svc.GetSeatMap(context.Background(), f.showtime.ID, "", "")
+	if res.Seats[2].Status != SeatStatusBooked {
+		t.Errorf("status = %s after the booking, want BOOKED", res.Seats[2].Status)
+	}
+}
+
+// versionedSeats guards a showtime's seat count by its version the way the
+// Postgres decrement does. Each write reads the version, yields, then
+// writes only if the version has not moved.
+type versionedSeats struct {
+	repository.BookingRepository
+	mu        sync.Mutex
+	available int
+	version   int64
+	attempts  int
+}
+
+func (v *versionedSeats) CreateWithSeats(_ context.Context, _ *entity.Booking, seats []*entity.BookingSeat) error {
+	v.mu.Lock()
+	v.attempts++
+	read := v.version
+	v.mu.Unlock()
+
+	runtime.Gosched()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	switch {
+	case v.version != read:
+		return apperrors.New(apperrors.CodeVersionConflict, "showtime seats changed concurrently")
+	case v.available < len(seats):
+		return apperrors.New(apperrors.CodeSeatNotAvailable, "not enough seats left")
+	}
+	v.available -= len(seats)
+	v.version++
+	return nil
+}
+
+func TestCreateWithSeatsRacingForTheLastSeat(t *testing.T) {
+	showtime := &versionedSeats{available: 1}
+	svc := NewService(nil, nil, nil, showtime, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, inlineTx{}, outbox.New(&memOutbox{}),
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+
+	const buyers = 50
+	errs := make(chan error, buyers)
+	var start sync.WaitGroup
+	start.Add(1)
+	for range buyers {
+		go func() {
+			start.Wait()
+			booking := &entity.Booking{ShowtimeID: uuid.New()}
+			errs <- svc.createWithSeats(context.Background(), booking, []*entity.BookingSeat{{SeatID: uuid.New()}})
+		}()
+	}
+	start.Done()
+
+	booked := 0
+	for range buyers {
+		err := <-errs
+		switch {
+		case err == nil:
+			booked++
+		case apperrors.Is(err, apperrors.CodeSeatNotAvailable), apperrors.Is(err, apperrors.CodeVersionConflict):
+		default:
+			t.Errorf("createWithSeats: %v", err)
+		}
+	}
+	if booked != 1 || showtime.available != 0 || showtime.version != 1 {
+		t.Errorf("%d bookings, %d seats left at version %d; want 1 booking, none left at version 1",
+			booked, showtime.available, showtime.version)
+	}
+	if showtime.attempts > buyers*bookingVersionRetries {
+		t.Errorf("%d attempts for %d buyers, want at most %d each", showtime.attempts, buyers, bookingVersionRetries)
+	}
+}
+
+func TestCreateWithSeatsBacksOff(t *testing.T) {
+	svc := NewService(nil, nil, nil, &memCreated{conflicts: 1}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, inlineTx{}, outbox.New(&memOutbox{}),
+		config.BookingConfig{}, config.TicketConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	booking := &entity.Booking{ShowtimeID: uuid.New()}
+
+	started := time.Now()
+	if err := svc.createWithSeats(context.Background(), booking, nil); err != nil {
+		t.Fatalf("createWithSeats: %v", err)
+	}
+	if waited := time.Since(started); waited < bookingVersionBackoff {
+		t.Errorf("retried after %s, want a pause of %s", waited, bookingVersionBackoff)
+	}
+
+	// A request cancelled during the pause stops retrying
+	svc.bookingRepo = &memCreated{conflicts: bookingVersionRetries}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := svc.createWithSeats(ctx, booking, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("createWithSeats = %v after the request was cancelled, want %v", err, context.Canceled)
+	}
+}
+
+// memExtendHolds serves one hold and extends it in place
+type memExtendHolds struct {
+	repository.SeatHoldRepository
+	hold *entity.SeatHold
+}
+
+func (m *memExtendHolds) GetByID(_ context.Context, id string) (*entity.SeatHold, error) {
+	if m.hold.ID != id {
+		return nil, apperrors.ErrNotFound("seat hold")
+	}
+	copied := *m.hold
+	return &copied, nil
+}
+
+func (m *memExtendHolds) Extend(_ context.Context, hold *entity.SeatHold, expiresAt time.Time) error {
+	hold.ExpiresAt = expiresAt
+	m.hold.ExpiresAt = expiresAt
+	return nil
+}
+
+// noGroupCheckouts has no group checkouts
+type noGroupCheckouts struct {
+	repository.GroupCheckoutRepository
+}
+
+func (noGroupCheckouts) GetActiveByHoldID(context.Context, string) (*entity.GroupCheckout, error) {
+	return nil, nil
+}
+
+func TestExtendHold(t *testing.T) {
+	ctx := context.Background()
+	userID, showtimeID := uuid.New(), uuid.New()
+	createdAt := time.Now().Add(-10 * time.Minute)
+	holds := &memExtendHolds{hold: &entity.SeatHold{
+		ID: "hold-1", ShowtimeID: showtimeID, UserID: userID,
+		CreatedAt: createdAt, ExpiresAt: time.Now().Add(time.Minute),
+	}}
+	svc := NewService(holds, nil, nil, nil, nil, noGroupCheckouts{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		config.BookingConfig{HoldTTL: 10 * time.Minute, HoldMaxLifetime: 15 * time.Minute}, config.TicketConfig{},
+		&logger.Logger{Logger: zap.NewNop()})
+
+	other := uuid.New()
+	rejects := []struct {
+		name   string
+		userID uuid.UUID
+		holdID string
+		req    ExtendHoldRequest
+		code   apperrors.ErrorCode
+	}{
+		{"expired hold", userID, "hold-2", ExtendHoldRequest{}, apperrors.CodeBookingExpired},
+		{"another user's hold", uuid.New(), "hold-1", ExtendHoldRequest{}, apperrors.CodeForbidden},
+		{"another showtime", userID, "hold-1", ExtendHoldRequest{ShowtimeID: &other}, apperrors.CodeBadRequest},
+	}
+	for _, tt := range rejects {
+		if _, err := svc.ExtendHold(ctx, tt.userID, tt.holdID, tt.req); !apperrors.Is(err, tt.code) {
+			t.Errorf("%s: ExtendHold = %v, want %s", tt.name, err, tt.code)
+		}
+	}
+
+	// The extension stops at the hold's longest lifetime
+	res, err := svc.ExtendHold(ctx, userID, "hold-1", ExtendHoldRequest{ShowtimeID: &showtimeID})
+	if err != nil {
+		t.Fatalf("ExtendHold: %v", err)
+	}
+	if latest := createdAt.Add(15 * time.Minute); !res.ExpiresAt.Equal(latest) {
+		t.Errorf("expires at %s, want the cap at %s", res.ExpiresAt, latest)
+	}
+
+	// Once there it fails with a code of its own
+	_, err = svc.ExtendHold(ctx, userID, "hold-1", ExtendHoldRequest{})
+	if !apperrors.Is(err, apperrors.CodeHoldExtensionLimit) {
+		t.Fatalf("ExtendHold at the cap = %v, want %s", err, apperrors.CodeHoldExtensionLimit)
+	}
+	if status := apperrors.GetHTTPStatus(err); status != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", status)
+	}
+}
@@ -0,0 +1,26 @@
+package changelog
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/pkg/changes"
+
+	"github.com/google/uuid"
+)
+
+// ChangeListParams represents query parameters for listing an entity's changes
+type ChangeListParams struct {
+	Page          int        `form:"page,default=1"`
+	Limit         int        `form:"limit,default=20"`
+	From          *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To            *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	ExcludeSystem bool       `form:"exclude_system"`
+}
+
+// ChangeRecordResponse is one entry of an entity's change timeline
+type ChangeRecordResponse struct {
+	ID        uuid.UUID        `json:"id" swaggertype:"string" format:"uuid"`
+	Actor     changes.Actor    `json:"actor"`
+	Changes   []changes.Change `json:"changes"`
+	ChangedAt time.Time        `json:"changed_at"`
+}
@@ -0,0 +1,85 @@
+package changelog
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/changes"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ignoredFields are bookkeeping fields that change on every update
+var ignoredFields = []string{"created_at", "updated_at"}
+
+// Service records and lists field-level change history of entities
+type Service struct {
+	changeRepo repository.ChangeRecordRepository
+	logger     *logger.Logger
+}
+
+// NewService creates a new change log service
+func NewService(changeRepo repository.ChangeRecordRepository, logger *logger.Logger) *Service {
+	return &Service{
+		changeRepo: changeRepo,
+		logger:     logger,
+	}
+}
+
+// Record stores the fields that differ between two versions of an entity,
+// attributed to the actor in ctx. Updates without visible changes are not
+// recorded. The entity is already saved, so a failure is logged rather
+// than returned.
+func (s *Service) Record(ctx context.Context, entityType entity.ChangeEntityType, id uuid.UUID, before, after any) {
+	diff := changes.Diff(before, after, ignoredFields...)
+	if len(diff) == 0 {
+		return
+	}
+
+	actor := changes.ActorFrom(ctx)
+	record := &entity.ChangeRecord{
+		EntityType: entityType,
+		EntityID:   id,
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		Changes:    diff,
+	}
+	if err := s.changeRepo.Create(ctx, record); err != nil {
+		s.logger.Error("failed to record changes",
+			zap.String("entity_type", string(entityType)),
+			zap.String("entity_id", id.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// List returns an entity's change timeline, newest first
+func (s *Service) List(ctx context.Context, entityType entity.ChangeEntityType, id uuid.UUID, params ChangeListParams) ([]*ChangeRecordResponse, int64, error) {
+	filter := repository.ChangeRecordFilter{
+		EntityType:    entityType,
+		EntityID:      id,
+		From:          params.From,
+		To:            params.To,
+		ExcludeSystem: params.ExcludeSystem,
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	records, total, err := s.changeRepo.List(ctx, filter, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*ChangeRecordResponse, len(records))
+	for i, record := range records {
+		responses[i] = &ChangeRecordResponse{
+			ID:        record.ID,
+			Actor:     changes.Actor{Type: record.ActorType, ID: record.ActorID},
+			Changes:   record.Changes,
+			ChangedAt: record.CreatedAt,
+		}
+	}
+	return responses, total, nil
+}
@@ -3,12 +3,14 @@ package cinema
 import (
 	"time"
 
+	"cinemaos-backend/internal/app/entity"
+
 	"github.com/google/uuid"
 )
 
 // CinemaResponse represents a cinema in responses
 type CinemaResponse struct {
-	ID        uuid.UUID `json:"id"`
+	ID        uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
 	Name      string    `json:"name"`
 	Slug      string    `json:"slug"`
 	Address   string    `json:"address"`
@@ -18,15 +20,66 @@ type CinemaResponse struct {
 	Country   string    `json:"country"`
 	Phone     *string   `json:"phone"`     // Changed to pointer
 	Email     *string   `json:"email"`     // Changed to pointer
+	Timezone  string    `json:"timezone"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	DistanceKm *float64 `json:"distance_km,omitempty"` // set on nearby searches
+	CompanionPolicyEnabled bool `json:"companion_policy_enabled"`
+	BookingFee entity.BookingFee `json:"booking_fee"`
 	Screens   []ScreenResponse `json:"screens,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// PublicCinemaResponse is the cinema shape served to customers and
+// anonymous callers, without record timestamps
+type PublicCinemaResponse struct {
+	ID                     uuid.UUID        `json:"id" swaggertype:"string" format:"uuid"`
+	Name                   string           `json:"name"`
+	Slug                   string           `json:"slug"`
+	Address                string           `json:"address"`
+	City                   string           `json:"city"`
+	State                  *string          `json:"state"`
+	ZipCode                *string          `json:"zip_code"`
+	Country                string           `json:"country"`
+	Phone                  *string          `json:"phone"`
+	Email                  *string          `json:"email"`
+	Timezone               string           `json:"timezone"`
+	Latitude               *float64         `json:"latitude,omitempty"`
+	Longitude              *float64         `json:"longitude,omitempty"`
+	DistanceKm             *float64         `json:"distance_km,omitempty"`
+	CompanionPolicyEnabled bool              `json:"companion_policy_enabled"`
+	BookingFee             entity.BookingFee `json:"booking_fee"`
+	Screens                []ScreenResponse  `json:"screens,omitempty"`
+}
+
+// Public returns the public variant of the cinema
+func (r *CinemaResponse) Public() any {
+	return &PublicCinemaResponse{
+		ID:                     r.ID,
+		Name:                   r.Name,
+		Slug:                   r.Slug,
+		Address:                r.Address,
+		City:                   r.City,
+		State:                  r.State,
+		ZipCode:                r.ZipCode,
+		Country:                r.Country,
+		Phone:                  r.Phone,
+		Email:                  r.Email,
+		Timezone:               r.Timezone,
+		Latitude:               r.Latitude,
+		Longitude:              r.Longitude,
+		DistanceKm:             r.DistanceKm,
+		CompanionPolicyEnabled: r.CompanionPolicyEnabled,
+		BookingFee:             r.BookingFee,
+		Screens:                r.Screens,
+	}
+}
+
 // ScreenResponse represents a screen in responses
 type ScreenResponse struct {
-	ID            uuid.UUID `json:"id"`
-	CinemaID      uuid.UUID `json:"cinema_id"`
+	ID            uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	CinemaID      uuid.UUID `json:"cinema_id" swaggertype:"string" format:"uuid"`
 	Name          string    `json:"name"`
 	ScreenType    string    `json:"type"` // Restored
 	SeatingCapacity int     `json:"seating_capacity"`
@@ -35,8 +88,8 @@ type ScreenResponse struct {
 
 // SeatResponse represents a seat in responses
 type SeatResponse struct {
-	ID         uuid.UUID `json:"id"`
-	ScreenID   uuid.UUID `json:"screen_id"`
+	ID         uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	ScreenID   uuid.UUID `json:"screen_id" swaggertype:"string" format:"uuid"`
 	RowName    string    `json:"row_name"`
 	SeatNumber int       `json:"seat_number"`
 	Type       string    `json:"type"`
@@ -55,6 +108,8 @@ type CreateCinemaRequest struct {
 	Country string `json:"country" validate:"required"`
 	Phone   string `json:"phone" validate:"omitempty,e164"`
 	Email   string `json:"email" validate:"required,email"`
+	Timezone string `json:"timezone" validate:"omitempty,timezone"` // defaults to UTC
+	CompanionPolicyEnabled bool `json:"companion_policy_enabled"` // free companion seat with a wheelchair space
 }
 
 // UpdateCinemaRequest represents request to update a cinema
@@ -67,6 +122,40 @@ type UpdateCinemaRequest struct {
 	Country string `json:"country"`
 	Phone   string `json:"phone" validate:"omitempty,e164"`
 	Email   string `json:"email" validate:"omitempty,email"`
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	CompanionPolicyEnabled *bool `json:"companion_policy_enabled,omitempty"`
+
+	// Booking fee; omitted fields are left unchanged. fee_amount is a
+	// percent for PERCENTAGE fees and a flat amount otherwise.
+	FeeType       string   `json:"fee_type,omitempty" validate:"omitempty,oneof=NONE PER_TICKET PER_ORDER PERCENTAGE"`
+	FeeAmount     *float64 `json:"fee_amount,omitempty" validate:"omitempty,min=0"`
+	FeeCap        *float64 `json:"fee_cap,omitempty" validate:"omitempty,min=0"`
+	ClearFeeCap   bool     `json:"clear_fee_cap,omitempty"`
+	FeeOnlineOnly *bool    `json:"fee_online_only,omitempty"` // waive the fee for walk-in sales
+	FeeRefundable *bool    `json:"fee_refundable,omitempty"`  // refund the fee with the tickets
+}
+
+// LinkCompanionSeatRequest links a wheelchair seat to its companion seat.
+// A null companion_seat_id removes the link.
+type LinkCompanionSeatRequest struct {
+	CompanionSeatID *uuid.UUID `json:"companion_seat_id" swaggertype:"string" format:"uuid"`
+}
+
+// ScreenDeviceRequest sets a screen's stock of one assistive device type
+type ScreenDeviceRequest struct {
+	DeviceType string `json:"device_type" validate:"required,oneof=ASSISTED_LISTENING AUDIO_DESCRIPTION"`
+	TotalCount int    `json:"total_count" validate:"min=0"`
+}
+
+// UpdateScreenDevicesRequest replaces a screen's assistive device stock
+type UpdateScreenDevicesRequest struct {
+	Devices []ScreenDeviceRequest `json:"devices" validate:"max=2,dive"`
+}
+
+// ScreenDevicesResponse represents a screen's assistive device stock
+type ScreenDevicesResponse struct {
+	ScreenID uuid.UUID              `json:"screen_id" swaggertype:"string" format:"uuid"`
+	Devices  []*entity.ScreenDevice `json:"devices"`
 }
 
 // CreateScreenRequest represents request to create a screen
@@ -89,10 +178,20 @@ type CreateSeatLayoutRequest struct {
 	StandardPrice float64 `json:"standard_price"`
 }
 
+// NearbyCinemaParams locates cinemas around a point
+type NearbyCinemaParams struct {
+	Lat      *float64 `form:"lat" validate:"required,min=-90,max=90"`
+	Lon      *float64 `form:"lon" validate:"required,min=-180,max=180"`
+	RadiusKm float64  `form:"radius_km,default=25" validate:"min=0,max=500"`
+	Limit    int      `form:"limit,default=20" validate:"min=1,max=100"`
+}
+
 // CinemaListParams represents query parameters for listing cinemas
 type CinemaListParams struct {
 	Page   int    `form:"page,default=1" validate:"min=1"`
 	Limit  int    `form:"limit,default=10" validate:"min=1,max=100"`
 	City   string `form:"city"`
-	Search string `form:"search"`
+	// Query searches the name, city and address, best match first
+	Query  string `form:"q" validate:"max=100"`
+	Search string `form:"search" validate:"max=100"` // older name for q
 }
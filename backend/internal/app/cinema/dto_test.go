@@ -0,0 +1,32 @@
+package cinema
+
+import (
+	"testing"
+
+	"cinemaos-backend/internal/pkg/validator"
+)
+
+func TestNearbyCinemaParamsValidation(t *testing.T) {
+	v := validator.New()
+	at := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name   string
+		params NearbyCinemaParams
+		valid  bool
+	}{
+		{"valid", NearbyCinemaParams{Lat: at(10.77), Lon: at(106.7), RadiusKm: 25, Limit: 20}, true},
+		{"edges", NearbyCinemaParams{Lat: at(-90), Lon: at(180), RadiusKm: 500, Limit: 1}, true},
+		{"equator and meridian", NearbyCinemaParams{Lat: at(0), Lon: at(0), RadiusKm: 0, Limit: 20}, true},
+		{"lat too large", NearbyCinemaParams{Lat: at(90.5), Lon: at(0), RadiusKm: 25, Limit: 20}, false},
+		{"lon too small", NearbyCinemaParams{Lat: at(0), Lon: at(-180.5), RadiusKm: 25, Limit: 20}, false},
+		{"radius too large", NearbyCinemaParams{Lat: at(0), Lon: at(0), RadiusKm: 501, Limit: 20}, false},
+		{"negative radius", NearbyCinemaParams{Lat: at(0), Lon: at(0), RadiusKm: -1, Limit: 20}, false},
+		{"lat missing", NearbyCinemaParams{Lon: at(0), RadiusKm: 25, Limit: 20}, false},
+	}
+	for _, tt := range tests {
+		if errs := v.Validate(tt.params); (errs == nil) != tt.valid {
+			t.Errorf("%s: errors %v, want valid %v", tt.name, errs, tt.valid)
+		}
+	}
+}
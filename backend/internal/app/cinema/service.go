@@ -2,9 +2,12 @@ package cinema
 
 import (
 	"context"
+	"math"
+	"strings"
 
 	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
 	"cinemaos-backend/internal/pkg/logger"
 
 	"github.com/google/uuid"
@@ -16,6 +19,7 @@ type Service struct {
 	cinemaRepo repository.CinemaRepository
 	screenRepo repository.ScreenRepository
 	seatRepo   repository.SeatRepository
+	deviceRepo repository.AssistiveDeviceRepository
 	logger     *logger.Logger
 }
 
@@ -24,12 +28,14 @@ func NewService(
 	cinemaRepo repository.CinemaRepository,
 	screenRepo repository.ScreenRepository,
 	seatRepo repository.SeatRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
 	logger *logger.Logger,
 ) *Service {
 	return &Service{
 		cinemaRepo: cinemaRepo,
 		screenRepo: screenRepo,
 		seatRepo:   seatRepo,
+		deviceRepo: deviceRepo,
 		logger:     logger,
 	}
 }
@@ -50,6 +56,11 @@ func (s *Service) Create(ctx context.Context, req CreateCinemaRequest) (*CinemaR
 		Country:    req.Country,
 		Phone:      &req.Phone,      // Assign address
 		Email:      &req.Email,      // Assign address
+		Timezone:   req.Timezone,
+		CompanionPolicyEnabled: req.CompanionPolicyEnabled,
+	}
+	if cinema.Timezone == "" {
+		cinema.Timezone = "UTC"
 	}
 
 	if err := s.cinemaRepo.Create(ctx, cinema); err != nil {
@@ -69,8 +80,140 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*CinemaResponse, e
 	return s.toCinemaResponse(cinema), nil
 }
 
+// Update updates a cinema
+func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateCinemaRequest) (*CinemaResponse, error) {
+	cinema, err := s.cinemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		cinema.Name = req.Name
+	}
+	if req.Address != "" {
+		cinema.Address = req.Address
+	}
+	if req.City != "" {
+		cinema.City = req.City
+	}
+	if req.State != "" {
+		cinema.State = &req.State
+	}
+	if req.ZipCode != "" {
+		cinema.PostalCode = &req.ZipCode
+	}
+	if req.Country != "" {
+		cinema.Country = req.Country
+	}
+	if req.Phone != "" {
+		cinema.Phone = &req.Phone
+	}
+	if req.Email != "" {
+		cinema.Email = &req.Email
+	}
+	if req.Timezone != "" {
+		cinema.Timezone = req.Timezone
+	}
+	if req.CompanionPolicyEnabled != nil {
+		cinema.CompanionPolicyEnabled = *req.CompanionPolicyEnabled
+	}
+	if req.FeeType != "" {
+		cinema.FeeType = entity.FeeType(req.FeeType)
+	}
+	if req.FeeAmount != nil {
+		cinema.FeeAmount = *req.FeeAmount
+	}
+	if req.ClearFeeCap {
+		cinema.FeeCap = nil
+	} else if req.FeeCap != nil {
+		cinema.FeeCap = req.FeeCap
+	}
+	if req.FeeOnlineOnly != nil {
+		cinema.FeeOnlineOnly = *req.FeeOnlineOnly
+	}
+	if req.FeeRefundable != nil {
+		cinema.FeeRefundable = *req.FeeRefundable
+	}
+	if cinema.FeeType == entity.FeePercent && cinema.FeeAmount > 100 {
+		return nil, apperrors.ErrBadRequest("percentage booking fee cannot exceed 100")
+	}
+
+	if err := s.cinemaRepo.Update(ctx, cinema); err != nil {
+		s.logger.Error("failed to update cinema", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toCinemaResponse(cinema), nil
+}
+
+// LinkCompanionSeat designates the companion seat for a wheelchair seat.
+// The companion seat is marked as a COMPANION seat.
+func (s *Service) LinkCompanionSeat(ctx context.Context, cinemaID, seatID uuid.UUID, req LinkCompanionSeatRequest) error {
+	ids := []uuid.UUID{seatID}
+	if req.CompanionSeatID != nil {
+		ids = append(ids, *req.CompanionSeatID)
+	}
+
+	seats, err := s.seatRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*entity.Seat, len(seats))
+	for _, seat := range seats {
+		byID[seat.ID] = seat
+	}
+
+	wheelchair, ok := byID[seatID]
+	if !ok {
+		return apperrors.ErrNotFound("seat")
+	}
+	if wheelchair.SeatType != entity.SeatWheelchair {
+		return apperrors.ErrBadRequest("only wheelchair seats can have a companion seat")
+	}
+
+	screen, err := s.screenRepo.GetByID(ctx, wheelchair.ScreenID)
+	if err != nil {
+		return err
+	}
+	if screen.CinemaID != cinemaID {
+		return apperrors.ErrNotFound("seat")
+	}
+
+	if req.CompanionSeatID == nil {
+		wheelchair.CompanionSeatID = nil
+		return s.seatRepo.Update(ctx, wheelchair)
+	}
+
+	companion, ok := byID[*req.CompanionSeatID]
+	if !ok {
+		return apperrors.ErrNotFound("companion seat")
+	}
+	if companion.ID == wheelchair.ID || companion.ScreenID != wheelchair.ScreenID {
+		return apperrors.ErrBadRequest("companion seat must be another seat on the same screen")
+	}
+	if companion.SeatType != entity.SeatStandard && companion.SeatType != entity.SeatCompanion {
+		return apperrors.ErrBadRequest("companion seat must be a standard seat")
+	}
+
+	companion.SeatType = entity.SeatCompanion
+	if err := s.seatRepo.Update(ctx, companion); err != nil {
+		return err
+	}
+
+	wheelchair.CompanionSeatID = &companion.ID
+	if err := s.seatRepo.Update(ctx, wheelchair); err != nil {
+		s.logger.Error("failed to link companion seat", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 // List lists cinemas
 func (s *Service) List(ctx context.Context, params CinemaListParams) ([]*CinemaResponse, int64, error) {
+	if params.Query != "" || params.Search != "" {
+		return s.Search(ctx, params)
+	}
+
 	offset := (params.Page - 1) * params.Limit
 	cinemas, total, err := s.cinemaRepo.List(ctx, params.City, offset, params.Limit)
 	if err != nil {
@@ -85,6 +228,50 @@ func (s *Service) List(ctx context.Context, params CinemaListParams) ([]*CinemaR
 	return responses, total, nil
 }
 
+// Search lists the active cinemas matching the query, optionally in one
+// city, best match first
+func (s *Service) Search(ctx context.Context, params CinemaListParams) ([]*CinemaResponse, int64, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		query = strings.TrimSpace(params.Search)
+	}
+	if query == "" {
+		return nil, 0, apperrors.ErrValidation("q is required")
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	cinemas, total, err := s.cinemaRepo.Search(ctx, query, params.City, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*CinemaResponse, 0, len(cinemas))
+	for _, c := range cinemas {
+		responses = append(responses, s.toCinemaResponse(c))
+	}
+	return responses, total, nil
+}
+
+// Nearby lists active cinemas within the radius of a point, nearest first
+func (s *Service) Nearby(ctx context.Context, params NearbyCinemaParams) ([]*CinemaResponse, error) {
+	lat, lon := *params.Lat, *params.Lon
+	cinemas, err := s.cinemaRepo.GetNearby(ctx, lat, lon, params.RadiusKm, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*CinemaResponse, 0, len(cinemas))
+	for _, c := range cinemas {
+		res := s.toCinemaResponse(c)
+		if distance, ok := c.DistanceKm(lat, lon); ok {
+			distance = math.Round(distance*100) / 100
+			res.DistanceKm = &distance
+		}
+		responses = append(responses, res)
+	}
+	return responses, nil
+}
+
 // AddScreen adds a screen to a cinema
 func (s *Service) AddScreen(ctx context.Context, cinemaID uuid.UUID, req CreateScreenRequest) (*ScreenResponse, error) {
 	// Verify cinema exists
@@ -137,6 +324,11 @@ func (s *Service) toCinemaResponse(c *entity.Cinema) *CinemaResponse {
 		Country:   c.Country,
 		Phone:     c.Phone,      // Pointer to pointer
 		Email:     c.Email,      // Pointer to pointer
+		Timezone:  c.Timezone,
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+		CompanionPolicyEnabled: c.CompanionPolicyEnabled,
+		BookingFee: c.BookingFee(),
 		Screens:   screens,
 		CreatedAt: c.CreatedAt,
 		UpdatedAt: c.UpdatedAt,
@@ -183,3 +375,60 @@ func (s *Service) GenerateSeatingLayout(ctx context.Context, screenID uuid.UUID,
 
 	return nil
 }
+
+// GetScreenDevices returns a screen's assistive device stock
+func (s *Service) GetScreenDevices(ctx context.Context, cinemaID, screenID uuid.UUID) (*ScreenDevicesResponse, error) {
+	if err := s.checkScreen(ctx, cinemaID, screenID); err != nil {
+		return nil, err
+	}
+
+	devices, err := s.deviceRepo.ListByScreen(ctx, screenID)
+	if err != nil {
+		return nil, err
+	}
+	return &ScreenDevicesResponse{ScreenID: screenID, Devices: devices}, nil
+}
+
+// UpdateScreenDevices replaces a screen's assistive device stock. Device
+// types left out have no stock. Reservations already made are kept even
+// if the new stock is lower.
+func (s *Service) UpdateScreenDevices(ctx context.Context, cinemaID, screenID uuid.UUID, req UpdateScreenDevicesRequest) (*ScreenDevicesResponse, error) {
+	if err := s.checkScreen(ctx, cinemaID, screenID); err != nil {
+		return nil, err
+	}
+
+	devices := make([]*entity.ScreenDevice, 0, len(req.Devices))
+	seen := make(map[entity.DeviceType]bool, len(req.Devices))
+	for _, d := range req.Devices {
+		deviceType := entity.DeviceType(d.DeviceType)
+		if seen[deviceType] {
+			return nil, apperrors.ErrBadRequest("device type " + d.DeviceType + " is listed more than once")
+		}
+		seen[deviceType] = true
+		if d.TotalCount > 0 {
+			devices = append(devices, &entity.ScreenDevice{DeviceType: deviceType, TotalCount: d.TotalCount})
+		}
+	}
+
+	if err := s.deviceRepo.SetInventory(ctx, screenID, devices); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("screen devices updated",
+		zap.String("screen_id", screenID.String()),
+		zap.Int("device_types", len(devices)),
+	)
+	return s.GetScreenDevices(ctx, cinemaID, screenID)
+}
+
+// checkScreen returns a not found error unless the screen belongs to the cinema
+func (s *Service) checkScreen(ctx context.Context, cinemaID, screenID uuid.UUID) error {
+	screen, err := s.screenRepo.GetByID(ctx, screenID)
+	if err != nil {
+		return err
+	}
+	if screen.CinemaID != cinemaID {
+		return apperrors.ErrNotFound("screen")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cinema
+
+import (
+	"context"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// searchedCinemas records the searches that reach the repository
+type searchedCinemas struct {
+	repository.CinemaRepository
+	query, city   string
+	offset, limit int
+}
+
+func (m *searchedCinemas) Search(_ context.Context, query, city string, offset, limit int) ([]*entity.Cinema, int64, error) {
+	m.query, m.city, m.offset, m.limit = query, city, offset, limit
+	return []*entity.Cinema{{ID: uuid.New(), Name: "Galaxy Nguyen Du", City: "Ho Chi Minh"}}, 1, nil
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := &searchedCinemas{}
+	svc := NewService(repo, nil, nil, nil, &logger.Logger{Logger: zap.NewNop()})
+
+	tests := []struct {
+		name   string
+		params CinemaListParams
+		want   string
+	}{
+		{"q", CinemaListParams{Page: 2, Limit: 5, City: "Hanoi", Query: " galaxy nguyen "}, "galaxy nguyen"},
+		{"the older search parameter", CinemaListParams{Page: 2, Limit: 5, City: "Hanoi", Search: "galaxy"}, "galaxy"},
+		{"q wins over search", CinemaListParams{Page: 2, Limit: 5, City: "Hanoi", Query: "lotte", Search: "galaxy"}, "lotte"},
+	}
+	for _, tt := range tests {
+		// GET /cinemas with a query goes through the search too
+		cinemas, total, err := svc.List(ctx, tt.params)
+		if err != nil || total != 1 || len(cinemas) != 1 {
+			t.Fatalf("%s: List = %d cinemas of %d, %v", tt.name, len(cinemas), total, err)
+		}
+		if repo.query != tt.want || repo.city != "Hanoi" || repo.offset != 5 || repo.limit != 5 {
+			t.Errorf("%s: searched %q in %q at %d+%d", tt.name, repo.query, repo.city, repo.offset, repo.limit)
+		}
+	}
+
+	if _, _, err := svc.Search(ctx, CinemaListParams{Page: 1, Limit: 10, Query: "  "}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("Search without a query = %v, want a validation error", err)
+	}
+}
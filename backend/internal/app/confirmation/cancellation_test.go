@@ -0,0 +1,163 @@
+package confirmation
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memBookings serves bookings with their details
+type memBookings struct {
+	repository.BookingRepository
+	bookings map[uuid.UUID]*entity.Booking
+}
+
+func (m *memBookings) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	booking, ok := m.bookings[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("booking")
+	}
+	return booking, nil
+}
+
+// memUsers serves users by ID
+type memUsers struct {
+	repository.UserRepository
+	users map[uuid.UUID]*entity.User
+}
+
+func (m *memUsers) GetByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, apperrors.New(apperrors.CodeUserNotFound, "user not found")
+	}
+	return user, nil
+}
+
+// fakeSender hands the emails it is given to the test
+type fakeSender struct {
+	sent chan async.EmailPayload
+}
+
+func (f *fakeSender) Send(_ context.Context, email async.EmailPayload) error {
+	f.sent <- email
+	return nil
+}
+
+func newCancelledBooking(userID *uuid.UUID, paid bool) *entity.Booking {
+	booking := &entity.Booking{
+		ID:               uuid.New(),
+		UserID:           userID,
+		BookingReference: "BK-20261016-WXYZ",
+		BookingStatus:    entity.BookingCancelled,
+		FinalAmount:      24,
+		Showtime: entity.Showtime{
+			MovieID:   uuid.New(),
+			ShowDate:  time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC),
+			StartTime: "19:30",
+			Movie:     entity.Movie{Title: "Arrival"},
+			Screen:    entity.Screen{Name: "Screen 2"},
+			Cinema:    entity.Cinema{Name: "Riverside", Timezone: "UTC"},
+		},
+		BookingSeats: []entity.BookingSeat{
+			{Seat: entity.Seat{RowLabel: "F", SeatNumber: 11}},
+			{Seat: entity.Seat{RowLabel: "F", SeatNumber: 12}},
+		},
+	}
+	if userID == nil {
+		booking.GuestEmail, booking.GuestName = "guest@example.com", "Gil"
+	}
+	if paid {
+		booking.PaymentStatus = entity.PaymentPaid
+	}
+	return booking
+}
+
+func TestCancellationEmail(t *testing.T) {
+	userID := uuid.New()
+	member := newCancelledBooking(&userID, true)
+	guest := newCancelledBooking(nil, true)
+	unpaid := newCancelledBooking(&userID, false)
+
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 10)}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	dispatcher := async.NewDispatcher(1, 10, sender, log)
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(time.Second) })
+	svc := NewService(
+		&memBookings{bookings: map[uuid.UUID]*entity.Booking{member.ID: member, guest.ID: guest, unpaid.ID: unpaid}},
+		&memUsers{users: map[uuid.UUID]*entity.User{userID: {ID: userID, Email: "ann@example.com", FirstName: "Ann"}}},
+		nil, dispatcher, mailer.NewClient(dispatcher, "https://cinema.example.com"), nil, log, "ticket-key")
+
+	tests := []struct {
+		name    string
+		booking *entity.Booking
+		event   events.BookingCancelled
+		to      string
+		want    []string
+		notWant []string
+	}{
+		{"refunded member", member, events.BookingCancelled{RefundAmount: 18, Reason: "Change of plans <3"},
+			"ann@example.com", []string{"Hi Ann", "BK-20261016-WXYZ", "Arrival", "Riverside, Screen 2", "Sat, 24 Oct 2026 19:30",
+				"Seats: F11, F12", "Reason: Change of plans &lt;3", "refund of <strong>18.00</strong>"}, nil},
+		{"guest past the refund window", guest, events.BookingCancelled{},
+			"guest@example.com", []string{"Hi Gil", "No refund applies"}, []string{"Reason:"}},
+		{"unpaid", unpaid, events.BookingCancelled{},
+			"ann@example.com", []string{"has been cancelled"}, []string{"refund"}},
+	}
+	for _, tt := range tests {
+		tt.event.BookingID = tt.booking.ID
+		if err := svc.onBookingCancelled(context.Background(), tt.event); err != nil {
+			t.Fatalf("%s: onBookingCancelled: %v", tt.name, err)
+		}
+		var email async.EmailPayload
+		select {
+		case email = <-sender.sent:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: no email sent", tt.name)
+		}
+
+		if len(email.To) != 1 || email.To[0] != tt.to || email.Subject != "Booking BK-20261016-WXYZ is cancelled" {
+			t.Errorf("%s: email to %v about %q", tt.name, email.To, email.Subject)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(email.Body, want) {
+				t.Errorf("%s: body is missing %q:\n%s", tt.name, want, email.Body)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(email.Body, notWant) {
+				t.Errorf("%s: body mentions %q:\n%s", tt.name, notWant, email.Body)
+			}
+		}
+	}
+}
+
+func TestCancellationEmailWithoutRecipient(t *testing.T) {
+	booking := newCancelledBooking(nil, false)
+	booking.GuestEmail = ""
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 1)}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	dispatcher := async.NewDispatcher(1, 10, sender, log)
+	svc := NewService(&memBookings{bookings: map[uuid.UUID]*entity.Booking{booking.ID: booking}}, &memUsers{},
+		nil, dispatcher, mailer.NewClient(dispatcher, "https://cinema.example.com"), nil, log, "ticket-key")
+
+	if err := svc.onBookingCancelled(context.Background(), events.BookingCancelled{BookingID: booking.ID}); err != nil {
+		t.Fatalf("onBookingCancelled: %v", err)
+	}
+	if n := svc.dispatcher.QueueSize(); n != 0 {
+		t.Errorf("%d emails queued with nobody to email", n)
+	}
+}
@@ -0,0 +1,375 @@
+package confirmation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+	"cinemaos-backend/internal/pkg/seatplan"
+	"cinemaos-backend/internal/pkg/ticket"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Seat plan image formats
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+const (
+	// layoutTTL is how long a screen's seat layout is cached
+	layoutTTL = 10 * time.Minute
+	// ticketPath is where a booking's e-ticket is downloaded
+	ticketPath = "/api/v1/bookings/%s/ticket"
+)
+
+type cachedLayout struct {
+	seats    []*entity.Seat
+	loadedAt time.Time
+}
+
+// Service renders booking seat plans and sends booking confirmation and
+// cancellation emails
+type Service struct {
+	bookingRepo repository.BookingRepository
+	userRepo    repository.UserRepository
+	seatRepo    repository.SeatRepository
+	dispatcher  *async.Dispatcher
+	emails      mailer.EmailClient
+	tracker     *analytics.Tracker
+	logger      *logger.Logger
+	ticketKey   string // signs the booking reference in the ticket's QR code
+
+	layouts   map[uuid.UUID]cachedLayout // screen ID -> seats
+	layoutsMu sync.Mutex
+}
+
+// NewService creates a new confirmation service
+func NewService(
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	seatRepo repository.SeatRepository,
+	dispatcher *async.Dispatcher,
+	emails mailer.EmailClient,
+	tracker *analytics.Tracker,
+	logger *logger.Logger,
+	ticketKey string,
+) *Service {
+	return &Service{
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+		seatRepo:    seatRepo,
+		dispatcher:  dispatcher,
+		emails:      emails,
+		tracker:     tracker,
+		logger:      logger,
+		ticketKey:   ticketKey,
+		layouts:     make(map[uuid.UUID]cachedLayout),
+	}
+}
+
+// SeatPlan renders the booking's auditorium with its seats highlighted.
+// Only the booking's owner and admins may see it.
+func (s *Service) SeatPlan(ctx context.Context, viewerID uuid.UUID, admin bool, bookingID uuid.UUID, format string) ([]byte, error) {
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && (booking.UserID == nil || *booking.UserID != viewerID) {
+		return nil, apperrors.ErrForbidden("booking belongs to another user")
+	}
+
+	layout, err := s.seatPlanLayout(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatSVG:
+		return seatplan.SVG(layout), nil
+	case FormatPNG:
+		img, err := seatplan.PNG(layout)
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to render seat plan")
+		}
+		return img, nil
+	}
+	return nil, apperrors.ErrBadRequest("unsupported seat plan format " + format)
+}
+
+// Ticket renders the booking's PDF e-ticket and returns it with the booking
+// reference. Only the booking's owner and admins may download it, and only
+// once the booking is confirmed.
+func (s *Service) Ticket(ctx context.Context, viewerID uuid.UUID, admin bool, bookingID uuid.UUID) ([]byte, string, error) {
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, bookingID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !admin && (booking.UserID == nil || *booking.UserID != viewerID) {
+		return nil, "", apperrors.ErrForbidden("booking belongs to another user")
+	}
+	if !booking.IsConfirmed() {
+		return nil, "", apperrors.ErrConflict("tickets are issued once the booking is confirmed")
+	}
+
+	pdf, err := s.renderTicket(booking)
+	if err != nil {
+		return nil, "", err
+	}
+	return pdf, booking.BookingReference, nil
+}
+
+// renderTicket renders a booking loaded with its details as a PDF e-ticket
+// whose QR code staff scan to check it in
+func (s *Service) renderTicket(booking *entity.Booking) ([]byte, error) {
+	showtime := booking.Showtime
+	pdf, err := ticket.Generate(ticket.Ticket{
+		Reference: booking.BookingReference,
+		Code:      ticket.Sign(booking.BookingReference, s.ticketKey),
+		Movie:     showtime.Movie.Title,
+		Cinema:    showtime.Cinema.Name,
+		Screen:    showtime.Screen.Name,
+		StartsAt:  showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:     seatLabels(booking),
+		Tickets:   booking.NumTickets,
+		Total:     booking.FinalAmount,
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to render ticket")
+	}
+	return pdf, nil
+}
+
+// seatPlanLayout builds the seat plan of the booking's screen with the
+// booked seats highlighted
+func (s *Service) seatPlanLayout(ctx context.Context, booking *entity.Booking) (seatplan.Layout, error) {
+	seats, err := s.screenLayout(ctx, booking.Showtime.ScreenID)
+	if err != nil {
+		return seatplan.Layout{}, err
+	}
+
+	booked := make(entity.UUIDList, 0, len(booking.BookingSeats))
+	for _, bs := range booking.BookingSeats {
+		booked = append(booked, bs.SeatID)
+	}
+
+	layout := seatplan.Layout{Seats: make([]seatplan.Seat, 0, len(seats))}
+	for _, seat := range seats {
+		if !seat.IsActive {
+			continue
+		}
+		layout.Seats = append(layout.Seats, seatplan.Seat{
+			Row:         seat.RowLabel,
+			Number:      seat.SeatNumber,
+			X:           seat.XPosition,
+			Y:           seat.YPosition,
+			Highlighted: booked.Contains(seat.ID),
+		})
+	}
+	return layout, nil
+}
+
+// screenLayout returns a screen's seats, cached for layoutTTL
+func (s *Service) screenLayout(ctx context.Context, screenID uuid.UUID) ([]*entity.Seat, error) {
+	s.layoutsMu.Lock()
+	cached, ok := s.layouts[screenID]
+	s.layoutsMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < layoutTTL {
+		return cached.seats, nil
+	}
+
+	seats, err := s.seatRepo.GetByScreenID(ctx, screenID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.layoutsMu.Lock()
+	s.layouts[screenID] = cachedLayout{seats: seats, loadedAt: time.Now()}
+	s.layoutsMu.Unlock()
+	return seats, nil
+}
+
+// WarmLayout loads a screen's seat layout into the cache ahead of traffic
+func (s *Service) WarmLayout(ctx context.Context, screenID uuid.UUID) error {
+	_, err := s.screenLayout(ctx, screenID)
+	return err
+}
+
+// RegisterSubscribers subscribes the service's side effects to domain events
+func (s *Service) RegisterSubscribers(bus *eventbus.Bus) {
+	bus.Subscribe(events.BookingConfirmedEvent, "confirmation.email", s.onBookingConfirmed)
+	bus.Subscribe(events.BookingConfirmedEvent, "confirmation.analytics", s.trackConfirmed)
+	bus.Subscribe(events.BookingConfirmedEvent, "confirmation.ticket", s.queueTicket)
+	bus.Subscribe(events.BookingCancelledEvent, "confirmation.cancellation_email", s.onBookingCancelled)
+}
+
+// queueTicket queues the e-ticket of a newly confirmed booking
+func (s *Service) queueTicket(ctx context.Context, event eventbus.Event) error {
+	confirmed := event.(events.BookingConfirmed)
+	if !s.dispatcher.SubmitTicket(func(ctx context.Context) error {
+		err := s.issueTicket(ctx, confirmed.BookingID)
+		if err != nil {
+			s.logger.WithContext(ctx).Error("failed to issue ticket",
+				zap.String("booking_id", confirmed.BookingID.String()), zap.Error(err))
+		}
+		return err
+	}) {
+		return fmt.Errorf("ticket queue full")
+	}
+	return nil
+}
+
+// issueTicket renders the booking's e-ticket and records its download URL.
+// There is no file storage, so the download renders the PDF again; issuing
+// it here surfaces bookings that cannot be ticketed before the customer
+// asks for one.
+func (s *Service) issueTicket(ctx context.Context, bookingID uuid.UUID) error {
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.renderTicket(booking); err != nil {
+		return err
+	}
+	return s.bookingRepo.SetTicketURL(ctx, bookingID, fmt.Sprintf(ticketPath, bookingID))
+}
+
+// trackConfirmed records the confirmation as the last step of the booking
+// funnel
+func (s *Service) trackConfirmed(ctx context.Context, event eventbus.Event) error {
+	confirmed := event.(events.BookingConfirmed)
+	s.tracker.Track(ctx, analytics.Event{
+		Name:       analytics.EventBookingConfirmed,
+		UserID:     confirmed.UserID,
+		ShowtimeID: &confirmed.ShowtimeID,
+		Properties: map[string]any{
+			"booking_id": confirmed.BookingID.String(),
+			"tickets":    confirmed.NumTickets,
+			"amount":     confirmed.FinalAmount,
+		},
+		Timestamp: confirmed.ConfirmedAt.UTC(),
+	})
+	return nil
+}
+
+// onBookingConfirmed emails the customer their booking with an inline seat plan
+func (s *Service) onBookingConfirmed(ctx context.Context, event eventbus.Event) error {
+	confirmed := event.(events.BookingConfirmed)
+
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, confirmed.BookingID)
+	if err != nil {
+		return err
+	}
+
+	to, name, err := s.recipient(ctx, booking)
+	if err != nil || to == "" {
+		return err
+	}
+
+	email := confirmationEmail(booking, to, name)
+	layout, err := s.seatPlanLayout(ctx, booking)
+	if err == nil {
+		email.SeatPlan, err = seatplan.PNG(layout)
+	}
+	if err != nil {
+		// The confirmation matters more than the picture
+		s.logger.WithContext(ctx).Warn("failed to render seat plan for confirmation email",
+			zap.String("booking_reference", booking.BookingReference), zap.Error(err))
+	}
+	return s.emails.SendBookingConfirmation(ctx, email)
+}
+
+// confirmationEmail fills the confirmation email from the booking
+func confirmationEmail(booking *entity.Booking, to, name string) mailer.BookingConfirmation {
+	showtime := booking.Showtime
+	devices := make([]string, 0, len(booking.Devices))
+	for _, d := range booking.Devices {
+		devices = append(devices, fmt.Sprintf("%d x %s", d.Quantity, d.DeviceType.Label()))
+	}
+	return mailer.BookingConfirmation{
+		To:             to,
+		Name:           name,
+		BookingID:      booking.ID,
+		Reference:      booking.BookingReference,
+		Movie:          showtime.Movie.Title,
+		Cinema:         showtime.Cinema.Name,
+		Screen:         showtime.Screen.Name,
+		StartsAt:       showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:          seatLabels(booking),
+		Devices:        devices,
+		Subtotal:       booking.SubtotalAmount,
+		Discount:       booking.DiscountAmount,
+		PointsRedeemed: booking.PointsRedeemed,
+		PointsDiscount: booking.PointsDiscountAmount,
+		Fee:            booking.FeeAmount,
+		Tax:            booking.TaxAmount,
+		Total:          booking.FinalAmount,
+	}
+}
+
+// onBookingCancelled emails the customer that their booking was cancelled,
+// with the refund if there is one
+func (s *Service) onBookingCancelled(ctx context.Context, event eventbus.Event) error {
+	cancelled := event.(events.BookingCancelled)
+
+	booking, err := s.bookingRepo.GetByIDWithDetails(ctx, cancelled.BookingID)
+	if err != nil {
+		return err
+	}
+
+	to, name, err := s.recipient(ctx, booking)
+	if err != nil || to == "" {
+		return err
+	}
+
+	showtime := booking.Showtime
+	return s.emails.SendBookingCancellation(ctx, mailer.BookingCancellation{
+		To:        to,
+		Name:      name,
+		MovieID:   showtime.MovieID,
+		Reference: booking.BookingReference,
+		Movie:     showtime.Movie.Title,
+		Cinema:    showtime.Cinema.Name,
+		Screen:    showtime.Screen.Name,
+		StartsAt:  showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:     seatLabels(booking),
+		Reason:    cancelled.Reason,
+		Refund:    cancelled.RefundAmount,
+		Paid:      booking.IsPaid() || booking.PaymentStatus == entity.PaymentRefunded,
+	})
+}
+
+// recipient returns the address and first name to email about a booking:
+// its user's, or the guest's for guest bookings. The address is empty when
+// there is nobody to email.
+func (s *Service) recipient(ctx context.Context, booking *entity.Booking) (string, string, error) {
+	if booking.UserID == nil {
+		return booking.GuestEmail, booking.GuestName, nil
+	}
+	user, err := s.userRepo.GetByID(ctx, *booking.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	return user.Email, user.FirstName, nil
+}
+
+// seatLabels returns the booked seats as row and number, such as "F12"
+func seatLabels(booking *entity.Booking) []string {
+	labels := make([]string, 0, len(booking.BookingSeats))
+	for _, bs := range booking.BookingSeats {
+		labels = append(labels, fmt.Sprintf("%s%d", bs.Seat.RowLabel, bs.Seat.SeatNumber))
+	}
+	return labels
+}
@@ -0,0 +1,60 @@
+package confirmation
+
+import (
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestConfirmationEmailForDeletedMovie(t *testing.T) {
+	booking := &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK-20261016-ABCD",
+		SubtotalAmount:   20,
+		FinalAmount:      20,
+		Showtime: entity.Showtime{
+			ShowDate:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			StartTime: "20:00",
+			Movie: entity.Movie{
+				ID:        uuid.New(),
+				Title:     "Dune: Part Two",
+				DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true},
+			},
+			Screen: entity.Screen{ID: uuid.New(), Name: "Screen 1"},
+			Cinema: entity.Cinema{Name: "Downtown"},
+		},
+	}
+
+	email := confirmationEmail(booking, "ann@example.com", "Ann")
+	if email.Movie != "Dune: Part Two" || email.Screen != "Screen 1" || email.Cinema != "Downtown" || email.Reference != "BK-20261016-ABCD" {
+		t.Errorf("confirmation email = %+v", email)
+	}
+}
+
+func TestConfirmationEmailListsThePointsDiscount(t *testing.T) {
+	promo := "AUTUMN10"
+	booking := &entity.Booking{
+		BookingReference:     "BK-20261016-EFGH",
+		SubtotalAmount:       38.97,
+		DiscountAmount:       5.85,
+		PointsRedeemed:       1000,
+		PointsDiscountAmount: 10,
+		FeeAmount:            4.5,
+		FinalAmount:          27.62,
+		PromoCode:            &promo,
+		Showtime: entity.Showtime{
+			ShowDate:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			StartTime: "20:00",
+			Movie:     entity.Movie{Title: "Dune: Part Two"},
+		},
+	}
+
+	email := confirmationEmail(booking, "ann@example.com", "Ann")
+	if email.Discount != 5.85 || email.PointsRedeemed != 1000 || email.PointsDiscount != 10 || email.Fee != 4.5 || email.Total != 27.62 {
+		t.Errorf("confirmation email amounts = %+v", email)
+	}
+}
@@ -0,0 +1,109 @@
+package confirmation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ticketBookings serves one booking and records the ticket URL set on it
+type ticketBookings struct {
+	repository.BookingRepository
+	booking   *entity.Booking
+	ticketURL string
+}
+
+func (m *ticketBookings) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	if id != m.booking.ID {
+		return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+	return m.booking, nil
+}
+
+func (m *ticketBookings) SetTicketURL(_ context.Context, id uuid.UUID, url string) error {
+	m.ticketURL = url
+	return nil
+}
+
+func newTicketService(status entity.BookingStatus) (*Service, *ticketBookings) {
+	owner := uuid.New()
+	bookings := &ticketBookings{booking: &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK20261016ABCD",
+		UserID:           &owner,
+		NumTickets:       2,
+		FinalAmount:      25.5,
+		BookingStatus:    status,
+		BookingSeats: []entity.BookingSeat{
+			{Seat: entity.Seat{RowLabel: "F", SeatNumber: 12}},
+			{Seat: entity.Seat{RowLabel: "F", SeatNumber: 13}},
+		},
+		Showtime: entity.Showtime{
+			ShowDate:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			StartTime: "20:00",
+			Movie:     entity.Movie{Title: "Dune: Part Two"},
+			Screen:    entity.Screen{Name: "Screen 1"},
+			Cinema:    entity.Cinema{Name: "Downtown"},
+		},
+	}}
+	return &Service{bookingRepo: bookings, logger: &logger.Logger{Logger: zap.NewNop()}}, bookings
+}
+
+func TestTicket(t *testing.T) {
+	ctx := context.Background()
+	svc, bookings := newTicketService(entity.BookingConfirmed)
+	owner := *bookings.booking.UserID
+
+	tests := []struct {
+		name   string
+		viewer uuid.UUID
+		admin  bool
+		code   apperrors.ErrorCode
+	}{
+		{"owner", owner, false, ""},
+		{"admin", uuid.New(), true, ""},
+		{"another user", uuid.New(), false, apperrors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		pdf, reference, err := svc.Ticket(ctx, tt.viewer, tt.admin, bookings.booking.ID)
+		if tt.code != "" {
+			if !apperrors.Is(err, tt.code) {
+				t.Errorf("%s: Ticket = %v, want %s", tt.name, err, tt.code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Ticket: %v", tt.name, err)
+		}
+		if reference != "BK20261016ABCD" || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+			t.Errorf("%s: %d bytes for %q", tt.name, len(pdf), reference)
+		}
+	}
+
+	pending, bookings := newTicketService(entity.BookingPending)
+	if _, _, err := pending.Ticket(ctx, *bookings.booking.UserID, false, bookings.booking.ID); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("pending booking: Ticket = %v, want a conflict", err)
+	}
+}
+
+func TestIssueTicket(t *testing.T) {
+	svc, bookings := newTicketService(entity.BookingConfirmed)
+	id := bookings.booking.ID
+
+	if err := svc.issueTicket(context.Background(), id); err != nil {
+		t.Fatalf("issueTicket: %v", err)
+	}
+	if want := fmt.Sprintf("/api/v1/bookings/%s/ticket", id); bookings.ticketURL != want {
+		t.Errorf("ticket URL = %q, want %q", bookings.ticketURL, want)
+	}
+}
@@ -0,0 +1,96 @@
+package curation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateSlotRequest represents a request to create a featured slot
+type CreateSlotRequest struct {
+	Position    string     `json:"position" validate:"required,oneof=HERO FEATURED"`
+	Type        string     `json:"type" validate:"required,oneof=MOVIE BANNER"`
+	MovieID     *uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	ImageURL    *string    `json:"image_url" validate:"omitempty,url,max=2048"`
+	Headline    *string    `json:"headline" validate:"omitempty,min=1,max=255"`
+	LinkURL     *string    `json:"link_url" validate:"omitempty,url,max=2048"`
+	ActiveFrom  *time.Time `json:"active_from"`
+	ActiveUntil *time.Time `json:"active_until"`
+	SortOrder   int        `json:"sort_order" validate:"min=0"`
+}
+
+// UpdateSlotRequest represents a request to update a featured slot.
+// Omitted fields keep their current value.
+type UpdateSlotRequest struct {
+	Position         string     `json:"position" validate:"omitempty,oneof=HERO FEATURED"`
+	Type             string     `json:"type" validate:"omitempty,oneof=MOVIE BANNER"`
+	MovieID          *uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	ImageURL         *string    `json:"image_url" validate:"omitempty,url,max=2048"`
+	Headline         *string    `json:"headline" validate:"omitempty,min=1,max=255"`
+	LinkURL          *string    `json:"link_url" validate:"omitempty,url,max=2048"`
+	ActiveFrom       *time.Time `json:"active_from"`
+	ActiveUntil      *time.Time `json:"active_until"`
+	ClearActiveFrom  bool       `json:"clear_active_from"`
+	ClearActiveUntil bool       `json:"clear_active_until"`
+	SortOrder        *int       `json:"sort_order" validate:"omitempty,min=0"`
+}
+
+// SlotListParams represents query parameters for listing featured slots
+type SlotListParams struct {
+	Page       int    `form:"page,default=1"`
+	Limit      int    `form:"limit,default=20"`
+	Position   string `form:"position" validate:"omitempty,oneof=HERO FEATURED"`
+	Type       string `form:"type" validate:"omitempty,oneof=MOVIE BANNER"`
+	ActiveOnly bool   `form:"active_only"`
+}
+
+// SlotResponse represents a featured slot in admin responses
+type SlotResponse struct {
+	ID          uuid.UUID     `json:"id" swaggertype:"string" format:"uuid"`
+	Position    string        `json:"position"`
+	Type        string        `json:"type"`
+	MovieID     *uuid.UUID    `json:"movie_id,omitempty" swaggertype:"string" format:"uuid"`
+	Movie       *MovieSummary `json:"movie,omitempty"`
+	ImageURL    *string       `json:"image_url,omitempty"`
+	Headline    *string       `json:"headline,omitempty"`
+	LinkURL     *string       `json:"link_url,omitempty"`
+	ActiveFrom  *time.Time    `json:"active_from,omitempty"`
+	ActiveUntil *time.Time    `json:"active_until,omitempty"`
+	SortOrder   int           `json:"sort_order"`
+	Active      bool          `json:"active"` // shown on the homepage right now
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// MovieSummary is the short form of a movie shown on the homepage
+type MovieSummary struct {
+	ID          uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Duration    int       `json:"duration"` // in minutes
+	ReleaseDate string    `json:"release_date"`
+	Rating      *string   `json:"rating,omitempty"`
+	Genres      []string  `json:"genres"`
+	Format      string    `json:"format"`
+	PosterURL   *string   `json:"poster_url,omitempty"`
+	BackdropURL *string   `json:"backdrop_url,omitempty"`
+	TrailerURL  *string   `json:"trailer_url,omitempty"`
+}
+
+// HomeSlot is a featured slot as shown on the homepage
+type HomeSlot struct {
+	ID       uuid.UUID     `json:"id" swaggertype:"string" format:"uuid"`
+	Type     string        `json:"type"`
+	Movie    *MovieSummary `json:"movie,omitempty"`
+	ImageURL *string       `json:"image_url,omitempty"`
+	Headline *string       `json:"headline,omitempty"`
+	LinkURL  *string       `json:"link_url,omitempty"`
+}
+
+// HomeResponse is everything the homepage shows
+type HomeResponse struct {
+	Hero       []*HomeSlot     `json:"hero"`
+	Featured   []*HomeSlot     `json:"featured"`
+	NowShowing []*MovieSummary `json:"now_showing"`
+	ComingSoon []*MovieSummary `json:"coming_soon"`
+}
@@ -0,0 +1,401 @@
+package curation
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service handles homepage curation
+type Service struct {
+	slotRepo  repository.FeaturedSlotRepository
+	movieRepo repository.MovieRepository
+	cfg       config.HomeConfig
+	logger    *logger.Logger
+
+	mu            sync.Mutex
+	home          *HomeResponse
+	homeExpiresAt time.Time
+}
+
+// NewService creates a new curation service
+func NewService(
+	slotRepo repository.FeaturedSlotRepository,
+	movieRepo repository.MovieRepository,
+	cfg config.HomeConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		slotRepo:  slotRepo,
+		movieRepo: movieRepo,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// CacheTTL returns how long the homepage may be cached
+func (s *Service) CacheTTL() time.Duration {
+	return s.cfg.CacheTTL
+}
+
+// GetHome returns the active featured slots and the now-showing and
+// coming-soon rows. The result is cached for CacheTTL, or until the first
+// slot in it expires if that is sooner, so expired slots drop out on their
+// own.
+func (s *Service) GetHome(ctx context.Context) (*HomeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.home != nil && now.Before(s.homeExpiresAt) {
+		return s.home, nil
+	}
+
+	home, expiresAt, err := s.buildHome(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	s.home = home
+	s.homeExpiresAt = expiresAt
+	return home, nil
+}
+
+func (s *Service) buildHome(ctx context.Context, now time.Time) (*HomeResponse, time.Time, error) {
+	expiresAt := now.Add(s.cfg.CacheTTL)
+
+	slots, err := s.slotRepo.ListActive(ctx, now)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	home := &HomeResponse{
+		Hero:       []*HomeSlot{},
+		Featured:   []*HomeSlot{},
+		NowShowing: []*MovieSummary{},
+		ComingSoon: []*MovieSummary{},
+	}
+	for _, slot := range slots {
+		// A movie taken off the catalogue leaves its slots empty
+		if slot.Type == entity.SlotMovie && (slot.Movie == nil || !slot.Movie.IsAvailable()) {
+			continue
+		}
+		if slot.ActiveUntil != nil && slot.ActiveUntil.Before(expiresAt) {
+			expiresAt = *slot.ActiveUntil
+		}
+
+		switch slot.Position {
+		case entity.PositionHero:
+			home.Hero = append(home.Hero, toHomeSlot(slot))
+		case entity.PositionFeatured:
+			home.Featured = append(home.Featured, toHomeSlot(slot))
+		}
+	}
+
+	nowShowing, _, err := s.movieRepo.GetNowShowing(ctx, nil, 0, s.cfg.RowSize)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for _, m := range nowShowing {
+		home.NowShowing = append(home.NowShowing, toMovieSummary(m))
+	}
+
+	comingSoon, _, err := s.movieRepo.GetComingSoon(ctx, 0, s.cfg.RowSize)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for _, m := range comingSoon {
+		home.ComingSoon = append(home.ComingSoon, toMovieSummary(m))
+	}
+
+	return home, expiresAt, nil
+}
+
+// invalidateHome drops the cached homepage after a curation change. Other
+// instances pick the change up when their cache expires.
+func (s *Service) invalidateHome() {
+	s.mu.Lock()
+	s.home = nil
+	s.mu.Unlock()
+}
+
+// CreateSlot creates a featured slot
+func (s *Service) CreateSlot(ctx context.Context, req CreateSlotRequest) (*SlotResponse, error) {
+	slot := &entity.FeaturedSlot{
+		Position:    entity.SlotPosition(req.Position),
+		Type:        entity.SlotType(req.Type),
+		MovieID:     req.MovieID,
+		ImageURL:    req.ImageURL,
+		Headline:    req.Headline,
+		LinkURL:     req.LinkURL,
+		ActiveFrom:  req.ActiveFrom,
+		ActiveUntil: req.ActiveUntil,
+		SortOrder:   req.SortOrder,
+	}
+
+	if slot.ActiveUntil != nil && !slot.ActiveUntil.After(time.Now()) {
+		return nil, apperrors.ErrValidation("active_until must be in the future")
+	}
+	if err := s.validateSlot(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	if err := s.slotRepo.Create(ctx, slot); err != nil {
+		return nil, err
+	}
+	s.invalidateHome()
+
+	s.logger.Info("featured slot created",
+		zap.String("slot_id", slot.ID.String()),
+		zap.String("position", string(slot.Position)),
+		zap.String("type", string(slot.Type)),
+	)
+
+	return s.GetSlot(ctx, slot.ID)
+}
+
+// GetSlot retrieves a featured slot
+func (s *Service) GetSlot(ctx context.Context, id uuid.UUID) (*SlotResponse, error) {
+	slot, err := s.slotRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSlotResponse(slot, time.Now()), nil
+}
+
+// UpdateSlot updates a featured slot
+func (s *Service) UpdateSlot(ctx context.Context, id uuid.UUID, req UpdateSlotRequest) (*SlotResponse, error) {
+	slot, err := s.slotRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Position != "" {
+		slot.Position = entity.SlotPosition(req.Position)
+	}
+	if req.Type != "" {
+		slot.Type = entity.SlotType(req.Type)
+	}
+	if req.MovieID != nil {
+		slot.MovieID = req.MovieID
+	}
+	if req.ImageURL != nil {
+		slot.ImageURL = req.ImageURL
+	}
+	if req.Headline != nil {
+		slot.Headline = req.Headline
+	}
+	if req.LinkURL != nil {
+		slot.LinkURL = req.LinkURL
+	}
+	if req.ClearActiveFrom {
+		slot.ActiveFrom = nil
+	}
+	if req.ActiveFrom != nil {
+		slot.ActiveFrom = req.ActiveFrom
+	}
+	if req.ClearActiveUntil {
+		slot.ActiveUntil = nil
+	}
+	if req.ActiveUntil != nil {
+		slot.ActiveUntil = req.ActiveUntil
+	}
+	if req.SortOrder != nil {
+		slot.SortOrder = *req.SortOrder
+	}
+
+	// Switching type drops the fields of the old type
+	switch slot.Type {
+	case entity.SlotMovie:
+		if req.Type != "" {
+			slot.ImageURL, slot.Headline, slot.LinkURL = nil, nil, nil
+		}
+	case entity.SlotBanner:
+		if req.Type != "" {
+			slot.MovieID = nil
+		}
+	}
+	slot.Movie = nil
+
+	if err := s.validateSlot(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	if err := s.slotRepo.Update(ctx, slot); err != nil {
+		return nil, err
+	}
+	s.invalidateHome()
+
+	return s.GetSlot(ctx, slot.ID)
+}
+
+// DeleteSlot removes a featured slot
+func (s *Service) DeleteSlot(ctx context.Context, id uuid.UUID) error {
+	if err := s.slotRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateHome()
+	return nil
+}
+
+// ListSlots lists featured slots for admins, including inactive ones
+func (s *Service) ListSlots(ctx context.Context, params SlotListParams) ([]*SlotResponse, int64, error) {
+	now := time.Now()
+	filter := repository.FeaturedSlotFilter{
+		Position: entity.SlotPosition(params.Position),
+		Type:     entity.SlotType(params.Type),
+	}
+	if params.ActiveOnly {
+		filter.ActiveAt = &now
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	slots, total, err := s.slotRepo.List(ctx, filter, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*SlotResponse, len(slots))
+	for i, slot := range slots {
+		responses[i] = toSlotResponse(slot, now)
+	}
+	return responses, total, nil
+}
+
+// validateSlot checks a slot's type-specific fields and active window,
+// normalizes its URLs and rejects overlaps with other slots
+func (s *Service) validateSlot(ctx context.Context, slot *entity.FeaturedSlot) error {
+	switch slot.Type {
+	case entity.SlotMovie:
+		if slot.MovieID == nil {
+			return apperrors.ErrValidation("movie_id is required for MOVIE slots")
+		}
+		if slot.ImageURL != nil || slot.Headline != nil || slot.LinkURL != nil {
+			return apperrors.ErrValidation("MOVIE slots take their image, headline and link from the movie")
+		}
+		movie, err := s.movieRepo.GetByID(ctx, *slot.MovieID)
+		if err != nil {
+			return err
+		}
+		if !movie.IsAvailable() {
+			return apperrors.ErrValidation("movie is not active")
+		}
+	case entity.SlotBanner:
+		if slot.MovieID != nil {
+			return apperrors.ErrValidation("movie_id is only allowed for MOVIE slots")
+		}
+		if slot.ImageURL == nil || slot.Headline == nil {
+			return apperrors.ErrValidation("image_url and headline are required for BANNER slots")
+		}
+		headline := strings.TrimSpace(*slot.Headline)
+		if headline == "" {
+			return apperrors.ErrValidation("headline must not be blank")
+		}
+		slot.Headline = &headline
+	}
+
+	for _, u := range []*string{slot.ImageURL, slot.LinkURL} {
+		if err := normalizeURL(u); err != nil {
+			return err
+		}
+	}
+
+	if slot.ActiveFrom != nil && slot.ActiveUntil != nil && !slot.ActiveFrom.Before(*slot.ActiveUntil) {
+		return apperrors.ErrValidation("active_from must be before active_until")
+	}
+
+	overlap, err := s.slotRepo.HasOverlap(ctx, slot)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return apperrors.ErrConflict("another slot has the same position and sort order during this active window")
+	}
+	return nil
+}
+
+// normalizeURL requires an absolute http(s) URL and lowercases its scheme
+// and host
+func normalizeURL(raw *string) error {
+	if raw == nil {
+		return nil
+	}
+	parsed, err := url.Parse(strings.TrimSpace(*raw))
+	if err != nil || parsed.Host == "" {
+		return apperrors.ErrValidation("invalid URL: " + *raw)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return apperrors.ErrValidation("URL must use http or https: " + *raw)
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	*raw = parsed.String()
+	return nil
+}
+
+func toSlotResponse(slot *entity.FeaturedSlot, now time.Time) *SlotResponse {
+	res := &SlotResponse{
+		ID:          slot.ID,
+		Position:    string(slot.Position),
+		Type:        string(slot.Type),
+		MovieID:     slot.MovieID,
+		ImageURL:    slot.ImageURL,
+		Headline:    slot.Headline,
+		LinkURL:     slot.LinkURL,
+		ActiveFrom:  slot.ActiveFrom,
+		ActiveUntil: slot.ActiveUntil,
+		SortOrder:   slot.SortOrder,
+		Active:      slot.IsActiveAt(now),
+		CreatedAt:   slot.CreatedAt,
+		UpdatedAt:   slot.UpdatedAt,
+	}
+	if slot.Movie != nil {
+		res.Movie = toMovieSummary(slot.Movie)
+		res.Active = res.Active && slot.Movie.IsAvailable()
+	}
+	return res
+}
+
+func toHomeSlot(slot *entity.FeaturedSlot) *HomeSlot {
+	res := &HomeSlot{
+		ID:       slot.ID,
+		Type:     string(slot.Type),
+		ImageURL: slot.ImageURL,
+		Headline: slot.Headline,
+		LinkURL:  slot.LinkURL,
+	}
+	if slot.Movie != nil {
+		res.Movie = toMovieSummary(slot.Movie)
+	}
+	return res
+}
+
+func toMovieSummary(movie *entity.Movie) *MovieSummary {
+	genres := []string(movie.Genres)
+	if genres == nil {
+		genres = []string{}
+	}
+	return &MovieSummary{
+		ID:          movie.ID,
+		Title:       movie.Title,
+		Slug:        movie.Slug,
+		Duration:    movie.Duration,
+		ReleaseDate: movie.ReleaseDate.Format("2006-01-02"),
+		Rating:      movie.Rating,
+		Genres:      genres,
+		Format:      string(movie.Format),
+		PosterURL:   movie.PosterURL,
+		BackdropURL: movie.BackdropURL,
+		TrailerURL:  movie.TrailerURL,
+	}
+}
@@ -0,0 +1,29 @@
+package dailyreport
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// ReportListParams represents query parameters for listing a cinema's reports
+type ReportListParams struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+// RegenerateRequest asks for a cinema's report for a date to be built again
+type RegenerateRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"` // business date in the cinema's timezone
+}
+
+// DailyReportResponse represents an end-of-day report in responses
+type DailyReportResponse struct {
+	ID           uuid.UUID              `json:"id" swaggertype:"string" format:"uuid"`
+	CinemaID     uuid.UUID              `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	BusinessDate string                 `json:"business_date"`
+	Data         entity.DailyReportData `json:"data"`
+	GeneratedAt  time.Time              `json:"generated_at"`
+}
@@ -0,0 +1,321 @@
+package dailyreport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// cinemaPageSize is how many cinemas are checked per query by the job
+const cinemaPageSize = 100
+
+// reportEmail renders a daily report for the cinema's managers
+var reportEmail = template.Must(template.New("daily_report").Funcs(template.FuncMap{
+	"money":   func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	"percent": func(ratio float64) string { return fmt.Sprintf("%.0f%%", ratio*100) },
+}).Parse(`<h2>{{.Data.CinemaName}}: {{.Date}}</h2>
+<p>Admissions: <strong>{{.Data.Admissions}}</strong><br>
+Revenue: <strong>{{money .Data.Revenue}}</strong><br>
+Refunds: {{.Data.Refunds.Count}} ({{money .Data.Refunds.Amount}})
+{{- with .Data.TopMovie}}<br>
+Top movie: {{.Title}} ({{.Admissions}} admissions, {{money .Revenue}}){{end}}</p>
+{{- if .Data.RevenueByMethod}}
+<h3>Revenue by payment method</h3>
+<table>
+<tr><th align="left">Method</th><th align="right">Payments</th><th align="right">Amount</th></tr>
+{{- range .Data.RevenueByMethod}}
+<tr><td>{{.Method}}</td><td align="right">{{.Payments}}</td><td align="right">{{money .Amount}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Data.Showtimes}}
+<h3>Showtimes</h3>
+<table>
+<tr><th align="left">Time</th><th align="left">Movie</th><th align="left">Screen</th><th align="right">Admissions</th><th align="right">Occupancy</th></tr>
+{{- range .Data.Showtimes}}
+<tr><td>{{.StartTime}}</td><td>{{.MovieTitle}}</td><td>{{.ScreenName}}</td><td align="right">{{.Admissions}}/{{.Capacity}}</td><td align="right">{{percent .Occupancy}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+`))
+
+// Service builds end-of-day operations reports for cinemas and emails them
+// to the cinemas' managers
+type Service struct {
+	reportRepo  repository.DailyReportRepository
+	bookingRepo repository.BookingRepository
+	cinemaRepo  repository.CinemaRepository
+	staffRepo   repository.CinemaStaffRepository
+	dispatcher  *async.Dispatcher
+	cfg         config.ReportsConfig
+	logger      *logger.Logger
+}
+
+// NewService creates a new daily report service
+func NewService(
+	reportRepo repository.DailyReportRepository,
+	bookingRepo repository.BookingRepository,
+	cinemaRepo repository.CinemaRepository,
+	staffRepo repository.CinemaStaffRepository,
+	dispatcher *async.Dispatcher,
+	cfg config.ReportsConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		reportRepo:  reportRepo,
+		bookingRepo: bookingRepo,
+		cinemaRepo:  cinemaRepo,
+		staffRepo:   staffRepo,
+		dispatcher:  dispatcher,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// GenerateDue reports every active cinema's latest business day once the
+// cinema has closed for it. Days already reported are skipped, so the job
+// can run as often as it likes.
+func (s *Service) GenerateDue(ctx context.Context) error {
+	log := s.logger.WithContext(ctx)
+	now := time.Now()
+
+	for offset := 0; ; offset += cinemaPageSize {
+		cinemas, _, err := s.cinemaRepo.List(ctx, "", offset, cinemaPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, cinema := range cinemas {
+			if !cinema.IsActive {
+				continue
+			}
+			date, ok := s.dueDate(cinema, now)
+			if !ok {
+				continue
+			}
+			exists, err := s.reportRepo.Exists(ctx, cinema.ID, date.Format("2006-01-02"))
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			if _, err := s.generate(ctx, cinema, date); err != nil {
+				// One cinema's failure should not hold up the others
+				log.Error("failed to generate daily report",
+					zap.String("cinema_id", cinema.ID.String()),
+					zap.String("business_date", date.Format("2006-01-02")),
+					zap.Error(err),
+				)
+			}
+		}
+
+		if len(cinemas) < cinemaPageSize {
+			return nil
+		}
+	}
+}
+
+// dueDate returns the latest business day the cinema has closed for, with
+// the configured grace period, looking back no further than yesterday
+func (s *Service) dueDate(cinema *entity.Cinema, now time.Time) (time.Time, bool) {
+	local := now.In(cinema.Location())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, date := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if !now.Before(cinema.ClosingTime(date).Add(s.cfg.CloseGrace)) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Regenerate builds a cinema's report for a date again, replacing the saved
+// report and emailing the managers. Managers may only regenerate reports of
+// cinemas they are assigned to.
+func (s *Service) Regenerate(ctx context.Context, viewerID uuid.UUID, role string, cinemaID uuid.UUID, req RegenerateRequest) (*DailyReportResponse, error) {
+	if err := s.checkAccess(ctx, viewerID, role, cinemaID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, apperrors.ErrBadRequest("invalid date format (use YYYY-MM-DD)")
+	}
+
+	cinema, err := s.cinemaRepo.GetByID(ctx, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	local := time.Now().In(cinema.Location())
+	if date.After(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)) {
+		return nil, apperrors.ErrBadRequest("cannot report a future date")
+	}
+
+	report, err := s.generate(ctx, cinema, date)
+	if err != nil {
+		return nil, err
+	}
+	return toDailyReportResponse(report), nil
+}
+
+// List returns a cinema's saved reports, newest first
+func (s *Service) List(ctx context.Context, viewerID uuid.UUID, role string, cinemaID uuid.UUID, params ReportListParams) ([]*DailyReportResponse, int64, error) {
+	if err := s.checkAccess(ctx, viewerID, role, cinemaID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	reports, total, err := s.reportRepo.ListByCinema(ctx, cinemaID, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*DailyReportResponse, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, toDailyReportResponse(report))
+	}
+	return result, total, nil
+}
+
+// checkAccess lets admins see every cinema and managers their own
+func (s *Service) checkAccess(ctx context.Context, viewerID uuid.UUID, role string, cinemaID uuid.UUID) error {
+	if entity.Role(role) != entity.RoleManager {
+		return nil
+	}
+	assigned, err := s.staffRepo.IsAssigned(ctx, cinemaID, viewerID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return apperrors.ErrForbidden("you are not assigned to this cinema")
+	}
+	return nil
+}
+
+// generate aggregates a cinema's business day, saves the report and emails
+// it to the cinema's managers
+func (s *Service) generate(ctx context.Context, cinema *entity.Cinema, date time.Time) (*entity.DailyReport, error) {
+	log := s.logger.WithContext(ctx)
+	day := date.Format("2006-01-02")
+	loc := cinema.Location()
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 1)
+
+	showtimes, err := s.bookingRepo.GetShowtimeSales(ctx, cinema.ID, day)
+	if err != nil {
+		return nil, err
+	}
+	byMethod, err := s.bookingRepo.GetPaymentTotals(ctx, cinema.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.bookingRepo.GetRefundTotals(ctx, cinema.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	data := entity.DailyReportData{
+		CinemaName:      cinema.Name,
+		Timezone:        loc.String(),
+		RevenueByMethod: byMethod,
+		Showtimes:       showtimes,
+		Refunds:         *refunds,
+		TopMovie:        entity.TopMovie(showtimes),
+	}
+	for i := range data.Showtimes {
+		st := &data.Showtimes[i]
+		st.Revenue = entity.RoundCents(st.Revenue)
+		if st.Capacity > 0 {
+			st.Occupancy = float64(st.Admissions) / float64(st.Capacity)
+		}
+		data.Admissions += st.Admissions
+	}
+	for i := range data.RevenueByMethod {
+		data.RevenueByMethod[i].Amount = entity.RoundCents(data.RevenueByMethod[i].Amount)
+		data.Revenue += data.RevenueByMethod[i].Amount
+	}
+	data.Revenue = entity.RoundCents(data.Revenue)
+	data.Refunds.Amount = entity.RoundCents(data.Refunds.Amount)
+
+	report := &entity.DailyReport{
+		CinemaID:     cinema.ID,
+		BusinessDate: date,
+		Data:         data,
+		GeneratedAt:  time.Now(),
+	}
+	if err := s.reportRepo.Upsert(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.send(ctx, report, day); err != nil {
+		// The report is saved and can be regenerated to resend it
+		log.Warn("failed to email daily report",
+			zap.String("cinema_id", cinema.ID.String()),
+			zap.String("business_date", day),
+			zap.Error(err),
+		)
+	}
+
+	log.Info("daily report generated",
+		zap.String("cinema_id", cinema.ID.String()),
+		zap.String("business_date", day),
+		zap.Int("admissions", data.Admissions),
+	)
+	return report, nil
+}
+
+// send emails the report to the cinema's managers
+func (s *Service) send(ctx context.Context, report *entity.DailyReport, day string) error {
+	managers, err := s.staffRepo.ListUsers(ctx, report.CinemaID, entity.RoleManager)
+	if err != nil {
+		return err
+	}
+	if len(managers) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := reportEmail.Execute(&body, struct {
+		Date string
+		Data entity.DailyReportData
+	}{day, report.Data}); err != nil {
+		return err
+	}
+
+	to := make([]string, 0, len(managers))
+	for _, manager := range managers {
+		to = append(to, manager.Email)
+	}
+	if !s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      to,
+		Subject: "Daily report for " + report.Data.CinemaName + ": " + day,
+		Body:    body.String(),
+		IsHTML:  true,
+	}) {
+		return fmt.Errorf("email queue full")
+	}
+	return nil
+}
+
+func toDailyReportResponse(report *entity.DailyReport) *DailyReportResponse {
+	return &DailyReportResponse{
+		ID:           report.ID,
+		CinemaID:     report.CinemaID,
+		BusinessDate: report.BusinessDate.Format("2006-01-02"),
+		Data:         report.Data,
+		GeneratedAt:  report.GeneratedAt,
+	}
+}
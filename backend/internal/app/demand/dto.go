@@ -0,0 +1,55 @@
+package demand
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// ExportParams represents query parameters for exporting demand stats
+type ExportParams struct {
+	CinemaID string `form:"cinema_id" validate:"omitempty,uuid"`
+	MovieID  string `form:"movie_id" validate:"omitempty,uuid"`
+	Format   string `form:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// DemandStatResponse represents a row of demand stats
+type DemandStatResponse struct {
+	MovieID      uuid.UUID         `json:"movie_id" swaggertype:"string" format:"uuid"`
+	CinemaID     uuid.UUID         `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	DayOfWeek    int               `json:"day_of_week"` // ISO, Monday is 1
+	TimeBucket   entity.TimeBucket `json:"time_bucket"`
+	ScreenType   entity.ScreenType `json:"screen_type"`
+	Bookings     int64             `json:"bookings"`
+	Tickets      int64             `json:"tickets"`
+	Revenue      float64           `json:"revenue"`
+	AvgLeadHours float64           `json:"avg_lead_hours"`
+}
+
+// ExportResponse holds demand stats with the time bookings are rolled up to
+type ExportResponse struct {
+	Watermark time.Time            `json:"watermark"`
+	Stats     []DemandStatResponse `json:"stats"`
+}
+
+// csvHeader is the header row of the CSV export
+var csvHeader = []string{
+	"movie_id", "cinema_id", "day_of_week", "time_bucket", "screen_type",
+	"bookings", "tickets", "revenue", "avg_lead_hours",
+}
+
+func toDemandStatResponse(s *entity.ShowtimeDemandStat) DemandStatResponse {
+	return DemandStatResponse{
+		MovieID:      s.MovieID,
+		CinemaID:     s.CinemaID,
+		DayOfWeek:    s.DayOfWeek,
+		TimeBucket:   s.TimeBucket,
+		ScreenType:   s.ScreenType,
+		Bookings:     s.Bookings,
+		Tickets:      s.Tickets,
+		Revenue:      s.Revenue,
+		AvgLeadHours: s.AvgLeadHours(),
+	}
+}
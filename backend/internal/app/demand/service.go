@@ -0,0 +1,115 @@
+package demand
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service rolls confirmed bookings up into showtime demand stats and
+// exports them for scheduling and offline analysis
+type Service struct {
+	demandRepo repository.DemandRepository
+	cfg        config.ReportsConfig
+	logger     *logger.Logger
+}
+
+// NewService creates a new demand service
+func NewService(demandRepo repository.DemandRepository, cfg config.ReportsConfig, logger *logger.Logger) *Service {
+	return &Service{
+		demandRepo: demandRepo,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// Rollup adds the bookings confirmed since the last run to the demand stats.
+// Bookings confirmed within the configured lag are left to the next run, so
+// transactions still committing are not skipped by the watermark.
+func (s *Service) Rollup(ctx context.Context) error {
+	upTo := time.Now().Add(-s.cfg.DemandLag)
+
+	added, err := s.demandRepo.Rollup(ctx, upTo)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		s.logger.WithContext(ctx).Info("showtime demand rolled up",
+			zap.Int64("bookings", added),
+			zap.Time("watermark", upTo),
+		)
+	}
+	return nil
+}
+
+// Export returns the demand stats matching the params
+func (s *Service) Export(ctx context.Context, params ExportParams) (*ExportResponse, error) {
+	var filter repository.DemandFilter
+	if params.CinemaID != "" {
+		id, err := uuid.Parse(params.CinemaID)
+		if err != nil {
+			return nil, apperrors.ErrValidation("invalid cinema_id")
+		}
+		filter.CinemaID = &id
+	}
+	if params.MovieID != "" {
+		id, err := uuid.Parse(params.MovieID)
+		if err != nil {
+			return nil, apperrors.ErrValidation("invalid movie_id")
+		}
+		filter.MovieID = &id
+	}
+
+	watermark, err := s.demandRepo.Watermark(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.demandRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ExportResponse{
+		Watermark: watermark,
+		Stats:     make([]DemandStatResponse, len(stats)),
+	}
+	for i, stat := range stats {
+		res.Stats[i] = toDemandStatResponse(stat)
+	}
+	return res, nil
+}
+
+// WriteCSV writes demand stats as CSV with a header row
+func WriteCSV(w io.Writer, stats []DemandStatResponse) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, s := range stats {
+		if err := cw.Write([]string{
+			s.MovieID.String(),
+			s.CinemaID.String(),
+			strconv.Itoa(s.DayOfWeek),
+			string(s.TimeBucket),
+			string(s.ScreenType),
+			strconv.FormatInt(s.Bookings, 10),
+			strconv.FormatInt(s.Tickets, 10),
+			strconv.FormatFloat(s.Revenue, 'f', 2, 64),
+			strconv.FormatFloat(s.AvgLeadHours, 'f', 1, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,88 @@
+package demand
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memDemand records rollups and serves fixed stats
+type memDemand struct {
+	repository.DemandRepository
+	upTo   time.Time
+	filter repository.DemandFilter
+	stats  []*entity.ShowtimeDemandStat
+}
+
+func (m *memDemand) Rollup(_ context.Context, upTo time.Time) (int64, error) {
+	m.upTo = upTo
+	return 0, nil
+}
+
+func (m *memDemand) Watermark(context.Context) (time.Time, error) {
+	return m.upTo, nil
+}
+
+func (m *memDemand) List(_ context.Context, filter repository.DemandFilter) ([]*entity.ShowtimeDemandStat, error) {
+	m.filter = filter
+	return m.stats, nil
+}
+
+func newTestService(repo *memDemand) *Service {
+	return NewService(repo, config.ReportsConfig{DemandLag: 10 * time.Minute}, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestRollupLeavesTheLagForTheNextRun(t *testing.T) {
+	repo := &memDemand{}
+	before := time.Now()
+	if err := newTestService(repo).Rollup(context.Background()); err != nil {
+		t.Fatalf("Rollup: %v", err)
+	}
+	if repo.upTo.Before(before.Add(-10*time.Minute)) || repo.upTo.After(time.Now().Add(-10*time.Minute)) {
+		t.Errorf("rolled up to %s, want 10m before now", repo.upTo)
+	}
+}
+
+func TestExport(t *testing.T) {
+	movieID, cinemaID := uuid.New(), uuid.New()
+	repo := &memDemand{stats: []*entity.ShowtimeDemandStat{{
+		MovieID: movieID, CinemaID: cinemaID, DayOfWeek: 5, TimeBucket: entity.TimeBucketEvening,
+		ScreenType: entity.ScreenIMAX, Bookings: 4, Tickets: 9, Revenue: 135.5, LeadHours: 90,
+	}}}
+	svc := newTestService(repo)
+
+	res, err := svc.Export(context.Background(), ExportParams{MovieID: movieID.String()})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if repo.filter.MovieID == nil || *repo.filter.MovieID != movieID || repo.filter.CinemaID != nil {
+		t.Errorf("filter = %+v, want the movie only", repo.filter)
+	}
+	if len(res.Stats) != 1 || res.Stats[0].AvgLeadHours != 22.5 {
+		t.Fatalf("stats = %+v", res.Stats)
+	}
+
+	if _, err := svc.Export(context.Background(), ExportParams{CinemaID: "not-a-uuid"}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("invalid cinema_id = %v, want a validation error", err)
+	}
+
+	var csv strings.Builder
+	if err := WriteCSV(&csv, res.Stats); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want := "movie_id,cinema_id,day_of_week,time_bucket,screen_type,bookings,tickets,revenue,avg_lead_hours\n" +
+		movieID.String() + "," + cinemaID.String() + ",5,EVENING,IMAX,4,9,135.50,22.5\n"
+	if csv.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", csv.String(), want)
+	}
+}
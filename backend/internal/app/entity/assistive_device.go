@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceType is a kind of assistive device a screen can lend out
+type DeviceType string
+
+const (
+	DeviceAssistedListening DeviceType = "ASSISTED_LISTENING" // hearing assistance headset
+	DeviceAudioDescription  DeviceType = "AUDIO_DESCRIPTION"  // descriptive audio receiver
+)
+
+// IsValid returns true if the device type is known
+func (t DeviceType) IsValid() bool {
+	switch t {
+	case DeviceAssistedListening, DeviceAudioDescription:
+		return true
+	}
+	return false
+}
+
+// Label returns the device type's customer-facing name
+func (t DeviceType) Label() string {
+	switch t {
+	case DeviceAssistedListening:
+		return "assisted listening headset"
+	case DeviceAudioDescription:
+		return "audio description receiver"
+	}
+	return string(t)
+}
+
+// ScreenDevice is a screen's stock of one assistive device type
+type ScreenDevice struct {
+	ScreenID   uuid.UUID  `gorm:"type:uuid;primary_key" json:"screen_id"`
+	DeviceType DeviceType `gorm:"type:varchar(30);primary_key" json:"device_type"`
+	TotalCount int        `gorm:"not null" json:"total_count"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for ScreenDevice
+func (ScreenDevice) TableName() string {
+	return "screen_devices"
+}
+
+// DeviceRequest asks for a number of devices of one type
+type DeviceRequest struct {
+	DeviceType DeviceType `json:"device_type"`
+	Quantity   int        `json:"quantity"`
+}
+
+// BookingDevice is an assistive device reservation made with a booking.
+// A reservation counts against the screen's stock while its booking is
+// confirmed, so cancelling the booking releases it.
+type BookingDevice struct {
+	BookingID  uuid.UUID  `gorm:"type:uuid;primary_key" json:"-"`
+	DeviceType DeviceType `gorm:"type:varchar(30);primary_key" json:"device_type"`
+	ShowtimeID uuid.UUID  `gorm:"type:uuid;not null" json:"-"`
+	Quantity   int        `gorm:"not null" json:"quantity"`
+	CreatedAt  time.Time  `json:"-"`
+}
+
+// TableName sets the table name for BookingDevice
+func (BookingDevice) TableName() string {
+	return "booking_devices"
+}
+
+// DeviceShortage reports a device type that cannot cover a request
+type DeviceShortage struct {
+	DeviceType DeviceType `json:"device_type"`
+	Requested  int        `json:"requested"`
+	Available  int        `json:"available"`
+}
+
+// CheckDevices compares device requests with the stock left after
+// reservations and returns every type that falls short
+func CheckDevices(requests []DeviceRequest, stock []*ScreenDevice, reserved map[DeviceType]int) []DeviceShortage {
+	total := make(map[DeviceType]int, len(stock))
+	for _, device := range stock {
+		total[device.DeviceType] = device.TotalCount
+	}
+
+	var shortages []DeviceShortage
+	for _, req := range requests {
+		available := max(total[req.DeviceType]-reserved[req.DeviceType], 0)
+		if req.Quantity > available {
+			shortages = append(shortages, DeviceShortage{
+				DeviceType: req.DeviceType,
+				Requested:  req.Quantity,
+				Available:  available,
+			})
+		}
+	}
+	return shortages
+}
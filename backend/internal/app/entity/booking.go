@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -61,22 +63,37 @@ type Booking struct {
 	SubtotalAmount float64 `gorm:"type:decimal(10,2);not null" json:"subtotal_amount"`
 	DiscountAmount float64 `gorm:"type:decimal(10,2);default:0" json:"discount_amount"`
 	TaxAmount      float64 `gorm:"type:decimal(10,2);default:0" json:"tax_amount"`
+	FeeAmount      float64 `gorm:"type:decimal(10,2);default:0" json:"fee_amount"` // booking fee, never discounted
 	FinalAmount    float64 `gorm:"type:decimal(10,2);not null" json:"final_amount"`
+	RefundAmount   *float64 `gorm:"type:decimal(10,2)" json:"refund_amount,omitempty"` // set when a cancellation is refunded
 	
 	// Promo
 	PromoCode   *string `json:"promo_code,omitempty"`
 	PromoCodeID *uuid.UUID `gorm:"type:uuid" json:"promo_code_id,omitempty"`
+
+	// PointsRedeemed is how many loyalty points were taken off the seats,
+	// worth PointsDiscountAmount; DiscountAmount is the promo discount alone
+	PointsRedeemed       int     `gorm:"default:0" json:"points_redeemed,omitempty"`
+	PointsDiscountAmount float64 `gorm:"type:decimal(10,2);default:0" json:"points_discount_amount,omitempty"`
 	
 	// Status
 	BookingStatus BookingStatus `gorm:"type:varchar(20);default:'PENDING'" json:"booking_status"`
 	PaymentStatus PaymentStatus `gorm:"type:varchar(20);default:'PENDING'" json:"payment_status"`
 	PaymentMethod *PaymentMethod `gorm:"type:varchar(20)" json:"payment_method,omitempty"`
+	SalesChannel  SalesChannel   `gorm:"type:varchar(20);not null;default:'ONLINE'" json:"sales_channel"` // walk-in sales bypass online caps
+
+	// TicketURL is where the PDF e-ticket is downloaded, set once the
+	// ticket has been issued after confirmation
+	TicketURL *string `json:"ticket_url,omitempty"`
 	
 	// Timestamps
 	BookedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"booked_at"`
 	ExpiresAt   *time.Time     `json:"expires_at,omitempty"`
 	ConfirmedAt *time.Time     `json:"confirmed_at,omitempty"`
 	CancelledAt *time.Time     `json:"cancelled_at,omitempty"`
+	ClaimedAt   *time.Time     `json:"claimed_at,omitempty"` // guest booking attached to an account
+	CheckedInAt *time.Time     `json:"checked_in_at,omitempty"` // ticket scanned at the door
+	CheckedInBy *uuid.UUID     `gorm:"type:uuid" json:"checked_in_by,omitempty"` // staff user who scanned it
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -86,6 +103,11 @@ type Booking struct {
 	Showtime     Showtime      `gorm:"foreignKey:ShowtimeID" json:"showtime,omitempty"`
 	BookingSeats []BookingSeat `gorm:"foreignKey:BookingID" json:"seats,omitempty"`
 	Payments     []Payment     `gorm:"foreignKey:BookingID" json:"payments,omitempty"`
+	Devices      []BookingDevice `gorm:"foreignKey:BookingID" json:"assistive_devices,omitempty"`
+
+	// Statuses as last read or written, for transition checks
+	loadedBookingStatus BookingStatus
+	loadedPaymentStatus PaymentStatus
 }
 
 // TableName sets the table name for Booking
@@ -111,6 +133,15 @@ func (b *Booking) IsExpired() bool {
 	return time.Now().After(*b.ExpiresAt) && b.BookingStatus == BookingPending
 }
 
+// RefundableAmount returns how much of the booking a refund returns. The
+// booking fee is kept unless the cinema's policy refunds it.
+func (b *Booking) RefundableAmount(fee BookingFee) float64 {
+	if fee.Refundable {
+		return b.FinalAmount
+	}
+	return RoundCents(max(b.FinalAmount-b.FeeAmount, 0))
+}
+
 // CanCancel returns true if the booking can be cancelled
 func (b *Booking) CanCancel() bool {
 	return b.BookingStatus == BookingPending || b.BookingStatus == BookingConfirmed
@@ -123,6 +154,7 @@ type BookingSeat struct {
 	SeatID     uuid.UUID      `gorm:"type:uuid;not null" json:"seat_id"`
 	ShowtimeID uuid.UUID      `gorm:"type:uuid;not null" json:"showtime_id"`
 	Price      float64        `gorm:"type:decimal(10,2);not null" json:"price"`
+	Fare       Fare           `gorm:"type:varchar(20);default:'STANDARD'" json:"fare"`
 	CreatedAt  time.Time      `json:"created_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 
@@ -154,12 +186,19 @@ type Payment struct {
 	PaidAt               *time.Time     `json:"paid_at,omitempty"`
 	RefundedAt           *time.Time     `json:"refunded_at,omitempty"`
 	RefundAmount         *float64       `gorm:"type:decimal(10,2)" json:"refund_amount,omitempty"`
+	// Gateway status polls made while the payment is pending without a
+	// webhook, and when the next one is due
+	ReconcileAttempts int        `gorm:"default:0" json:"-"`
+	NextReconcileAt   *time.Time `json:"-"`
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
 	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+
+	// Status as last read or written, for transition checks
+	loadedStatus PaymentStatus
 }
 
 // TableName sets the table name for Payment
@@ -229,3 +268,69 @@ func (p *PromoCode) CalculateDiscount(subtotal float64) float64 {
 
 	return discount
 }
+
+// Rejection explains why the promo code cannot be applied to an order of
+// subtotal by a user who has used it userUses times before. It is empty
+// when the code can be applied.
+func (p *PromoCode) Rejection(subtotal float64, userUses int) string {
+	now := time.Now()
+	switch {
+	case !p.IsActive:
+		return "promo code is not active"
+	case now.Before(p.ValidFrom):
+		return "promo code is not valid yet"
+	case !now.Before(p.ValidUntil):
+		return "promo code has expired"
+	case p.UsageLimit != nil && p.UsageCount >= *p.UsageLimit:
+		return "promo code has reached its usage limit"
+	case p.UsageLimitPerUser != nil && userUses >= *p.UsageLimitPerUser:
+		return "you have already used this promo code the maximum number of times"
+	case p.MinPurchase != nil && subtotal < *p.MinPurchase:
+		return fmt.Sprintf("promo code requires an order of at least %.2f", *p.MinPurchase)
+	}
+	return ""
+}
+
+// Validate checks the promo code's discount and validity window
+func (p *PromoCode) Validate() error {
+	switch p.DiscountType {
+	case "PERCENTAGE":
+		if p.DiscountValue <= 0 || p.DiscountValue > 100 {
+			return errors.New("a percentage discount must be greater than 0 and at most 100")
+		}
+	case "FIXED":
+		if p.DiscountValue <= 0 {
+			return errors.New("discount_value must be greater than 0")
+		}
+	default:
+		return fmt.Errorf("unknown discount type %q", p.DiscountType)
+	}
+
+	if p.MaxDiscount != nil && *p.MaxDiscount <= 0 {
+		return errors.New("max_discount must be greater than 0")
+	}
+	if p.MinPurchase != nil && *p.MinPurchase < 0 {
+		return errors.New("min_purchase must not be negative")
+	}
+	if !p.ValidFrom.Before(p.ValidUntil) {
+		return errors.New("valid_from must be before valid_until")
+	}
+	return nil
+}
+
+// PromoCodeRedemption records a booking's use of a promo code, counted
+// against the code's per-user limit. Guests are counted by email. The row
+// is deleted when the booking is released, giving the use back.
+type PromoCodeRedemption struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PromoCodeID uuid.UUID  `gorm:"type:uuid;not null" json:"promo_code_id"`
+	UserID      *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	GuestEmail  string     `json:"guest_email,omitempty"` // lower-cased; set for guest bookings
+	BookingID   uuid.UUID  `gorm:"type:uuid;uniqueIndex;not null" json:"booking_id"`
+	RedeemedAt  time.Time  `gorm:"not null" json:"redeemed_at"`
+}
+
+// TableName sets the table name for PromoCodeRedemption
+func (PromoCodeRedemption) TableName() string {
+	return "promo_code_redemptions"
+}
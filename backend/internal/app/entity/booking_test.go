@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromoCodeRejection(t *testing.T) {
+	now := time.Now()
+	one, two := 1, 2
+	minPurchase := 20.0
+	valid := func() PromoCode {
+		return PromoCode{IsActive: true, ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name     string
+		edit     func(p *PromoCode)
+		subtotal float64
+		userUses int
+		want     string // part of the reason, empty when the code applies
+	}{
+		{"valid", func(p *PromoCode) {}, 10, 0, ""},
+		{"inactive", func(p *PromoCode) { p.IsActive = false }, 10, 0, "not active"},
+		{"not yet valid", func(p *PromoCode) { p.ValidFrom = now.Add(time.Minute) }, 10, 0, "not valid yet"},
+		{"expired", func(p *PromoCode) { p.ValidUntil = now.Add(-time.Minute) }, 10, 0, "expired"},
+		{"used up", func(p *PromoCode) { p.UsageLimit, p.UsageCount = &two, 2 }, 10, 0, "usage limit"},
+		{"one use left", func(p *PromoCode) { p.UsageLimit, p.UsageCount = &two, 1 }, 10, 0, ""},
+		{"used by the user", func(p *PromoCode) { p.UsageLimitPerUser = &one }, 10, 1, "maximum number of times"},
+		{"first use by the user", func(p *PromoCode) { p.UsageLimitPerUser = &one }, 10, 0, ""},
+		{"below the minimum", func(p *PromoCode) { p.MinPurchase = &minPurchase }, 19.99, 0, "at least 20.00"},
+		{"at the minimum", func(p *PromoCode) { p.MinPurchase = &minPurchase }, 20, 0, ""},
+	}
+	for _, tt := range tests {
+		promo := valid()
+		tt.edit(&promo)
+		got := promo.Rejection(tt.subtotal, tt.userUses)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Rejection = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPromoCodeValidate(t *testing.T) {
+	now := time.Now()
+	zero, negative := 0.0, -1.0
+	valid := func() PromoCode {
+		return PromoCode{DiscountType: "PERCENTAGE", DiscountValue: 20, ValidFrom: now, ValidUntil: now.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name string
+		edit func(p *PromoCode)
+		want string // part of the error, empty when the code is valid
+	}{
+		{"valid", func(p *PromoCode) {}, ""},
+		{"whole price off", func(p *PromoCode) { p.DiscountValue = 100 }, ""},
+		{"over 100 percent", func(p *PromoCode) { p.DiscountValue = 100.5 }, "at most 100"},
+		{"no percentage", func(p *PromoCode) { p.DiscountValue = 0 }, "greater than 0"},
+		{"fixed over 100", func(p *PromoCode) { p.DiscountType, p.DiscountValue = "FIXED", 150 }, ""},
+		{"negative fixed", func(p *PromoCode) { p.DiscountType, p.DiscountValue = "FIXED", -5 }, "greater than 0"},
+		{"unknown type", func(p *PromoCode) { p.DiscountType = "BOGO" }, "unknown discount type"},
+		{"zero cap", func(p *PromoCode) { p.MaxDiscount = &zero }, "max_discount"},
+		{"negative minimum", func(p *PromoCode) { p.MinPurchase = &negative }, "min_purchase"},
+		{"zero minimum", func(p *PromoCode) { p.MinPurchase = &zero }, ""},
+		{"empty window", func(p *PromoCode) { p.ValidUntil = p.ValidFrom }, "before valid_until"},
+		{"reversed window", func(p *PromoCode) { p.ValidUntil = now.Add(-time.Hour) }, "before valid_until"},
+	}
+	for _, tt := range tests {
+		promo := valid()
+		tt.edit(&promo)
+		err := promo.Validate()
+		if (tt.want == "") != (err == nil) || err != nil && !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
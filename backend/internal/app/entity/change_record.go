@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/pkg/changes"
+
+	"github.com/google/uuid"
+)
+
+// ChangeEntityType names the kind of entity a change record belongs to
+type ChangeEntityType string
+
+const (
+	ChangeEntityMovie    ChangeEntityType = "MOVIE"
+	ChangeEntityShowtime ChangeEntityType = "SHOWTIME"
+	ChangeEntityBooking  ChangeEntityType = "BOOKING"
+)
+
+// ChangeRecord is one update of an entity with the fields it changed
+type ChangeRecord struct {
+	ID         uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntityType ChangeEntityType  `gorm:"type:varchar(30);not null" json:"entity_type"`
+	EntityID   uuid.UUID         `gorm:"type:uuid;not null" json:"entity_id"`
+	ActorType  changes.ActorType `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *uuid.UUID        `gorm:"type:uuid" json:"actor_id,omitempty"` // nil for system changes
+	Changes    ChangeList        `gorm:"type:jsonb;not null" json:"changes"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// TableName sets the table name for ChangeRecord
+func (ChangeRecord) TableName() string {
+	return "change_records"
+}
+
+// ChangeList is a list of field changes stored as a JSONB array
+type ChangeList []changes.Change
+
+// Value implements driver.Valuer
+func (l ChangeList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *ChangeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = ChangeList{}
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for ChangeList: %T", value)
+	}
+
+	return json.Unmarshal(data, l)
+}
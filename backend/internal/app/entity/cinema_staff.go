@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CinemaStaff assigns a staff member or manager to a cinema. The role is
+// the user's role at that cinema only; managers manage the cinemas they
+// are assigned to as MANAGER.
+type CinemaStaff struct {
+	CinemaID  uuid.UUID `gorm:"type:uuid;primary_key" json:"cinema_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	Role      Role      `gorm:"type:varchar(20);not null;default:'STAFF'" json:"role"` // MANAGER or STAFF
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName sets the table name for CinemaStaff
+func (CinemaStaff) TableName() string {
+	return "cinema_staff"
+}
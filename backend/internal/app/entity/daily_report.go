@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyReport is a cinema's end-of-day operations report. There is one per
+// cinema and business date; regenerating a report replaces it.
+type DailyReport struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CinemaID     uuid.UUID       `gorm:"type:uuid;not null" json:"cinema_id"`
+	BusinessDate time.Time       `gorm:"type:date;not null" json:"business_date"` // in the cinema's timezone
+	Data         DailyReportData `gorm:"type:jsonb;not null" json:"data"`
+	GeneratedAt  time.Time       `gorm:"not null" json:"generated_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// TableName sets the table name for DailyReport
+func (DailyReport) TableName() string {
+	return "daily_reports"
+}
+
+// DailyReportData holds a day's figures for a cinema
+type DailyReportData struct {
+	CinemaName      string               `json:"cinema_name"`
+	Timezone        string               `json:"timezone"`
+	Admissions      int                  `json:"admissions"`
+	Revenue         float64              `json:"revenue"`
+	RevenueByMethod []PaymentMethodTotal `json:"revenue_by_method"`
+	Showtimes       []ShowtimeSales      `json:"showtimes"`
+	Refunds         RefundTotals         `json:"refunds"`
+	TopMovie        *MovieSales          `json:"top_movie,omitempty"`
+}
+
+// Scan implements the sql.Scanner interface
+func (d *DailyReportData) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// Value implements the driver.Valuer interface
+func (d DailyReportData) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// PaymentMethodTotal is the money taken through one payment method
+type PaymentMethodTotal struct {
+	Method   string  `json:"method"`
+	Payments int     `json:"payments"`
+	Amount   float64 `json:"amount"`
+}
+
+// ShowtimeSales is the admissions and occupancy of one showtime
+type ShowtimeSales struct {
+	ShowtimeID uuid.UUID `json:"showtime_id"`
+	MovieID    uuid.UUID `json:"movie_id"`
+	MovieTitle string    `json:"movie_title"`
+	ScreenName string    `json:"screen_name"`
+	StartTime  string    `json:"start_time"`
+	Capacity   int       `json:"capacity"`
+	Admissions int       `json:"admissions"`
+	Revenue    float64   `json:"revenue"`
+	Occupancy  float64   `json:"occupancy"` // admissions over capacity, 0 to 1
+}
+
+// RefundTotals is the refunds issued over a period
+type RefundTotals struct {
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+// MovieSales is the admissions and revenue of one movie
+type MovieSales struct {
+	MovieID    uuid.UUID `json:"movie_id"`
+	Title      string    `json:"title"`
+	Admissions int       `json:"admissions"`
+	Revenue    float64   `json:"revenue"`
+}
+
+// TopMovie returns the movie with the most admissions across the showtimes,
+// by revenue on a tie, or nil when nothing was sold
+func TopMovie(showtimes []ShowtimeSales) *MovieSales {
+	byMovie := make(map[uuid.UUID]*MovieSales)
+	var order []uuid.UUID
+	for _, st := range showtimes {
+		m, ok := byMovie[st.MovieID]
+		if !ok {
+			m = &MovieSales{MovieID: st.MovieID, Title: st.MovieTitle}
+			byMovie[st.MovieID] = m
+			order = append(order, st.MovieID)
+		}
+		m.Admissions += st.Admissions
+		m.Revenue = RoundCents(m.Revenue + st.Revenue)
+	}
+
+	var top *MovieSales
+	for _, id := range order {
+		m := byMovie[id]
+		if m.Admissions == 0 {
+			continue
+		}
+		if top == nil || m.Admissions > top.Admissions ||
+			(m.Admissions == top.Admissions && m.Revenue > top.Revenue) {
+			top = m
+		}
+	}
+	return top
+}
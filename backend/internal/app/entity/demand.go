@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeBucket groups showtimes by the part of the day they start in
+type TimeBucket string
+
+const (
+	TimeBucketMorning   TimeBucket = "MORNING"   // before 12:00
+	TimeBucketAfternoon TimeBucket = "AFTERNOON" // 12:00 to 17:00
+	TimeBucketEvening   TimeBucket = "EVENING"   // 17:00 to 21:00
+	TimeBucketLate      TimeBucket = "LATE"      // from 21:00
+)
+
+// TimeBucketBounds are the start times, in HH:MM, before which a showtime
+// falls in each bucket. Showtimes starting later are LATE.
+var TimeBucketBounds = []struct {
+	Bucket TimeBucket
+	Before string
+}{
+	{TimeBucketMorning, "12:00"},
+	{TimeBucketAfternoon, "17:00"},
+	{TimeBucketEvening, "21:00"},
+}
+
+// TimeBucketOf returns the bucket of a showtime starting at the HH:MM time
+func TimeBucketOf(startTime string) TimeBucket {
+	for _, b := range TimeBucketBounds {
+		if startTime < b.Before {
+			return b.Bucket
+		}
+	}
+	return TimeBucketLate
+}
+
+// ShowtimeDemandStat holds the confirmed bookings of a movie at a cinema for
+// one day of the week, time bucket and screen type, rolled up from bookings
+// by the demand job. Day of week is ISO: Monday is 1, Sunday is 7.
+type ShowtimeDemandStat struct {
+	MovieID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"movie_id"`
+	CinemaID   uuid.UUID  `gorm:"type:uuid;primaryKey" json:"cinema_id"`
+	DayOfWeek  int        `gorm:"primaryKey" json:"day_of_week"`
+	TimeBucket TimeBucket `gorm:"type:varchar(20);primaryKey" json:"time_bucket"`
+	ScreenType ScreenType `gorm:"type:varchar(20);primaryKey" json:"screen_type"`
+	Bookings   int64      `gorm:"not null;default:0" json:"bookings"`
+	Tickets    int64      `gorm:"not null;default:0" json:"tickets"`
+	Revenue    float64    `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"`
+	// LeadHours sums the hours between booking and showtime start over all
+	// bookings; divide by Bookings for the average lead time
+	LeadHours float64   `gorm:"not null;default:0" json:"lead_hours"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for ShowtimeDemandStat
+func (ShowtimeDemandStat) TableName() string {
+	return "showtime_demand_stats"
+}
+
+// AvgLeadHours returns the average hours tickets were bought before the
+// showtime started
+func (s *ShowtimeDemandStat) AvgLeadHours() float64 {
+	if s.Bookings == 0 {
+		return 0
+	}
+	return s.LeadHours / float64(s.Bookings)
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "testing"
+
+func TestTimeBucketOf(t *testing.T) {
+	tests := []struct {
+		start string
+		want  TimeBucket
+	}{
+		{"09:00", TimeBucketMorning},
+		{"11:59", TimeBucketMorning},
+		{"12:00", TimeBucketAfternoon},
+		{"16:59:59", TimeBucketAfternoon},
+		{"17:00", TimeBucketEvening},
+		{"20:45", TimeBucketEvening},
+		{"21:00", TimeBucketLate},
+		{"23:30", TimeBucketLate},
+		{"00:15", TimeBucketMorning},
+	}
+	for _, tt := range tests {
+		if got := TimeBucketOf(tt.start); got != tt.want {
+			t.Errorf("TimeBucketOf(%q) = %s, want %s", tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestAvgLeadHours(t *testing.T) {
+	if got := (&ShowtimeDemandStat{Bookings: 4, LeadHours: 90}).AvgLeadHours(); got != 22.5 {
+		t.Errorf("AvgLeadHours = %v, want 22.5", got)
+	}
+	if got := (&ShowtimeDemandStat{}).AvgLeadHours(); got != 0 {
+		t.Errorf("AvgLeadHours without bookings = %v, want 0", got)
+	}
+}
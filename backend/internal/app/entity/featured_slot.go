@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SlotPosition is the homepage area a featured slot appears in
+type SlotPosition string
+
+const (
+	PositionHero     SlotPosition = "HERO"     // hero carousel
+	PositionFeatured SlotPosition = "FEATURED" // featured row
+)
+
+// SlotType says what a featured slot shows
+type SlotType string
+
+const (
+	SlotMovie  SlotType = "MOVIE"
+	SlotBanner SlotType = "BANNER"
+)
+
+// FeaturedSlot is a curated homepage entry: a movie, or a promotional banner
+// with its own image, headline and link. Slots are shown during their
+// active window, ordered by SortOrder within their position.
+type FeaturedSlot struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Position    SlotPosition   `gorm:"type:varchar(20);not null" json:"position"`
+	Type        SlotType       `gorm:"type:varchar(20);not null" json:"type"`
+	MovieID     *uuid.UUID     `gorm:"type:uuid" json:"movie_id,omitempty"`
+	ImageURL    *string        `json:"image_url,omitempty"`
+	Headline    *string        `json:"headline,omitempty"`
+	LinkURL     *string        `json:"link_url,omitempty"`
+	ActiveFrom  *time.Time     `json:"active_from,omitempty"`  // nil: active immediately
+	ActiveUntil *time.Time     `json:"active_until,omitempty"` // nil: active until removed
+	SortOrder   int            `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	Movie *Movie `gorm:"foreignKey:MovieID" json:"movie,omitempty"`
+}
+
+// TableName sets the table name for FeaturedSlot
+func (FeaturedSlot) TableName() string {
+	return "featured_slots"
+}
+
+// IsActiveAt returns true if the slot's active window contains t
+func (s *FeaturedSlot) IsActiveAt(t time.Time) bool {
+	if s.ActiveFrom != nil && t.Before(*s.ActiveFrom) {
+		return false
+	}
+	return s.ActiveUntil == nil || t.Before(*s.ActiveUntil)
+}
@@ -0,0 +1,117 @@
+package entity
+
+import "math"
+
+// FeeType selects how a cinema charges its booking fee
+type FeeType string
+
+const (
+	FeeNone      FeeType = "NONE"
+	FeePerTicket FeeType = "PER_TICKET"
+	FeePerOrder  FeeType = "PER_ORDER"
+	FeePercent   FeeType = "PERCENTAGE"
+)
+
+// SalesChannel is where a sale is made
+type SalesChannel string
+
+const (
+	ChannelOnline SalesChannel = "ONLINE"
+	ChannelWalkIn SalesChannel = "WALK_IN" // staff sales at the box office
+)
+
+// BookingFee is a cinema's convenience fee policy
+type BookingFee struct {
+	Type       FeeType  `json:"type"`
+	Amount     float64  `json:"amount"`        // flat amount, or percent for PERCENTAGE
+	Cap        *float64 `json:"cap,omitempty"` // upper bound of a PERCENTAGE fee
+	OnlineOnly bool     `json:"online_only"`   // waived for walk-in sales
+	Refundable bool     `json:"refundable"`    // refunded together with the tickets
+}
+
+// Calculate returns the fee for an order of tickets. Percentage fees apply
+// to the subtotal after discounts.
+func (f BookingFee) Calculate(subtotal float64, tickets int, channel SalesChannel) float64 {
+	if tickets <= 0 || (f.OnlineOnly && channel != ChannelOnline) {
+		return 0
+	}
+
+	var fee float64
+	switch f.Type {
+	case FeePerTicket:
+		fee = f.Amount * float64(tickets)
+	case FeePerOrder:
+		fee = f.Amount
+	case FeePercent:
+		fee = subtotal * f.Amount / 100
+		if f.Cap != nil && fee > *f.Cap {
+			fee = *f.Cap
+		}
+	}
+	return RoundCents(max(fee, 0))
+}
+
+// PriceBreakdown is the priced total of an order, line by line
+type PriceBreakdown struct {
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount"`
+	// PointsDiscount is what redeemed loyalty points took off the seats,
+	// kept apart from the promo discount in Discount
+	PointsDiscount float64 `json:"points_discount,omitempty"`
+	Fee            float64 `json:"fee"`
+	Total          float64 `json:"total"`
+}
+
+// PriceOrder prices an order of tickets. The promo discount applies to the
+// seats alone, then the booking fee is added on the discounted subtotal;
+// tax, once charged, follows the fee. Every line is rounded to the cent so
+// all callers agree on the total.
+func PriceOrder(subtotal float64, tickets int, promo *PromoCode, fee BookingFee, channel SalesChannel) PriceBreakdown {
+	b := PriceBreakdown{Subtotal: RoundCents(subtotal)}
+	if promo != nil {
+		b.Discount = RoundCents(min(promo.CalculateDiscount(b.Subtotal), b.Subtotal))
+	}
+	discounted := RoundCents(b.Subtotal - b.Discount)
+	b.Fee = fee.Calculate(discounted, tickets, channel)
+	b.Total = RoundCents(discounted + b.Fee)
+	return b
+}
+
+// AllocateFee splits a fee across parts in proportion to their amounts.
+// Rounding is cumulative, so the parts add up to the fee exactly.
+func AllocateFee(fee float64, amounts []float64) []float64 {
+	parts := make([]float64, len(amounts))
+
+	var total float64
+	for _, amount := range amounts {
+		total += amount
+	}
+
+	feeCents := toCents(fee)
+	var running float64
+	var allocated int64
+	for i, amount := range amounts {
+		running += amount
+		share := float64(i+1) / float64(len(amounts))
+		if total > 0 {
+			share = running / total
+		}
+		cents := int64(math.Round(float64(feeCents) * share))
+		parts[i] = fromCents(cents - allocated)
+		allocated = cents
+	}
+	return parts
+}
+
+// RoundCents rounds an amount to the nearest cent
+func RoundCents(amount float64) float64 {
+	return fromCents(toCents(amount))
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
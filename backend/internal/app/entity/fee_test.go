@@ -0,0 +1,148 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func activePromo(discountType string, value float64) *PromoCode {
+	now := time.Now()
+	return &PromoCode{
+		Code:          "TEST",
+		DiscountType:  discountType,
+		DiscountValue: value,
+		IsActive:      true,
+		ValidFrom:     now.Add(-time.Hour),
+		ValidUntil:    now.Add(time.Hour),
+	}
+}
+
+func TestPriceOrder(t *testing.T) {
+	feeCap := 2.0
+	tests := []struct {
+		name     string
+		subtotal float64
+		tickets  int
+		promo    *PromoCode
+		fee      BookingFee
+		channel  SalesChannel
+		want     PriceBreakdown
+	}{
+		{
+			name:     "per-ticket fee on a float subtotal",
+			subtotal: 12.99 * 3,
+			tickets:  3,
+			fee:      BookingFee{Type: FeePerTicket, Amount: 1.5},
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 38.97, Fee: 4.5, Total: 43.47},
+		},
+		{
+			name:     "percentage promo rounds half a cent up",
+			subtotal: 38.97,
+			tickets:  3,
+			promo:    activePromo("PERCENTAGE", 15), // 5.8455
+			fee:      BookingFee{Type: FeePerTicket, Amount: 1.5},
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 38.97, Discount: 5.85, Fee: 4.5, Total: 37.62},
+		},
+		{
+			name:     "percentage fee on the rounded discounted subtotal",
+			subtotal: 10.05,
+			tickets:  1,
+			promo:    activePromo("PERCENTAGE", 50),             // 5.025
+			fee:      BookingFee{Type: FeePercent, Amount: 2.5}, // 2.5% of 5.02
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 10.05, Discount: 5.03, Fee: 0.13, Total: 5.15},
+		},
+		{
+			name:     "percentage fee capped",
+			subtotal: 45,
+			tickets:  3,
+			fee:      BookingFee{Type: FeePercent, Amount: 10, Cap: &feeCap},
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 45, Fee: 2, Total: 47},
+		},
+		{
+			name:     "promo larger than the seats still pays the fee",
+			subtotal: 20,
+			tickets:  2,
+			promo:    activePromo("FIXED", 50),
+			fee:      BookingFee{Type: FeePerOrder, Amount: 1},
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 20, Discount: 20, Fee: 1, Total: 1},
+		},
+		{
+			name:     "online-only fee waived at the box office",
+			subtotal: 20,
+			tickets:  2,
+			fee:      BookingFee{Type: FeePerOrder, Amount: 1, OnlineOnly: true},
+			channel:  ChannelWalkIn,
+			want:     PriceBreakdown{Subtotal: 20, Total: 20},
+		},
+		{
+			name:     "no tickets, no fee",
+			subtotal: 8,
+			tickets:  0,
+			fee:      BookingFee{Type: FeePerOrder, Amount: 1},
+			channel:  ChannelOnline,
+			want:     PriceBreakdown{Subtotal: 8, Total: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PriceOrder(tt.subtotal, tt.tickets, tt.promo, tt.fee, tt.channel)
+			if got != tt.want {
+				t.Errorf("PriceOrder = %+v, want %+v", got, tt.want)
+			}
+			if lines := RoundCents(got.Subtotal - got.Discount - got.PointsDiscount + got.Fee); lines != got.Total {
+				t.Errorf("lines add up to %v, total is %v", lines, got.Total)
+			}
+		})
+	}
+}
+
+func TestAllocateFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		fee     float64
+		amounts []float64
+		want    []float64
+	}{
+		{"even split of an odd cent", 1, []float64{1, 1, 1}, []float64{0.33, 0.34, 0.33}},
+		{"proportional", 3, []float64{10, 20}, []float64{1, 2}},
+		{"nothing to weigh by", 0.1, []float64{0, 0, 0}, []float64{0.03, 0.04, 0.03}},
+		{"no fee", 0, []float64{5, 7}, []float64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AllocateFee(tt.fee, tt.amounts)
+			var sum float64
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("part %d = %v, want %v", i, got[i], tt.want[i])
+				}
+				sum += got[i]
+			}
+			if RoundCents(sum) != RoundCents(tt.fee) {
+				t.Errorf("parts add up to %v, want %v", sum, tt.fee)
+			}
+		})
+	}
+}
+
+func TestRefundableAmount(t *testing.T) {
+	booking := &Booking{FinalAmount: 43.47, FeeAmount: 4.5}
+
+	if got := booking.RefundableAmount(BookingFee{Refundable: true}); got != 43.47 {
+		t.Errorf("refundable fee: refund %v, want 43.47", got)
+	}
+	if got := booking.RefundableAmount(BookingFee{}); got != 38.97 {
+		t.Errorf("kept fee: refund %v, want 38.97", got)
+	}
+	free := &Booking{FinalAmount: 1, FeeAmount: 1}
+	if got := free.RefundableAmount(BookingFee{}); got != 0 {
+		t.Errorf("fee-only booking: refund %v, want 0", got)
+	}
+}
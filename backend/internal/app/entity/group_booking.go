@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupBookingStatus represents the state of a group booking session
+type GroupBookingStatus string
+
+const (
+	// GroupBookingOpen means participants can still join
+	GroupBookingOpen GroupBookingStatus = "OPEN"
+	// GroupBookingFinalized means the pooled seats were booked as one booking
+	GroupBookingFinalized GroupBookingStatus = "FINALIZED"
+	// GroupBookingExpired means the session ended without being finalized
+	GroupBookingExpired GroupBookingStatus = "EXPIRED"
+)
+
+// groupBookingTransitions lists the allowed state transitions
+var groupBookingTransitions = map[GroupBookingStatus][]GroupBookingStatus{
+	GroupBookingOpen: {GroupBookingFinalized, GroupBookingExpired},
+}
+
+// CanTransitionTo returns true if the status may move to next
+func (s GroupBookingStatus) CanTransitionTo(next GroupBookingStatus) bool {
+	for _, allowed := range groupBookingTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupBooking is a session in which friends each pick their own seats for
+// a showtime through a shared link. The seats are held per participant and
+// booked together as one booking for the organizer when the session is
+// finalized.
+type GroupBooking struct {
+	ID                  uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizerUserID     uuid.UUID          `gorm:"type:uuid;not null;index" json:"organizer_user_id"`
+	ShowtimeID          uuid.UUID          `gorm:"type:uuid;not null" json:"showtime_id"`
+	MaxParticipants     int                `gorm:"not null" json:"max_participants"`
+	CurrentParticipants int                `gorm:"not null;default:0" json:"current_participants"`
+	ShareTokenHash      string             `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt           time.Time          `gorm:"not null" json:"expires_at"`
+	Status              GroupBookingStatus `gorm:"type:varchar(20);default:'OPEN'" json:"status"`
+	BookingID           *uuid.UUID         `gorm:"type:uuid" json:"booking_id,omitempty"`
+	FinalizedAt         *time.Time         `json:"finalized_at,omitempty"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+
+	// Relations
+	Participants []GroupBookingParticipant `gorm:"foreignKey:GroupBookingID" json:"participants,omitempty"`
+}
+
+// TableName sets the table name for GroupBooking
+func (GroupBooking) TableName() string {
+	return "group_bookings"
+}
+
+// StatusAt returns the status as of now: an open session past its expiry
+// is reported as expired even before it is stored as such
+func (g *GroupBooking) StatusAt(now time.Time) GroupBookingStatus {
+	if g.Status == GroupBookingOpen && !now.Before(g.ExpiresAt) {
+		return GroupBookingExpired
+	}
+	return g.Status
+}
+
+// IsFull returns true if no more participants can join
+func (g *GroupBooking) IsFull() bool {
+	return g.CurrentParticipants >= g.MaxParticipants
+}
+
+// Participant returns the user's participation, if any
+func (g *GroupBooking) Participant(userID uuid.UUID) (*GroupBookingParticipant, bool) {
+	for i := range g.Participants {
+		if g.Participants[i].UserID == userID {
+			return &g.Participants[i], true
+		}
+	}
+	return nil, false
+}
+
+// PooledSeatIDs returns the seats picked by every participant
+func (g *GroupBooking) PooledSeatIDs() UUIDList {
+	var seats UUIDList
+	for _, p := range g.Participants {
+		seats = append(seats, p.SeatIDs...)
+	}
+	return seats
+}
+
+// GroupBookingParticipant is a user who joined a group booking with the
+// seats they hold for it
+type GroupBookingParticipant struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GroupBookingID uuid.UUID `gorm:"type:uuid;not null;index" json:"group_booking_id"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	HoldID         string    `gorm:"not null" json:"hold_id"`
+	SeatIDs        UUIDList  `gorm:"type:jsonb;not null" json:"seat_ids"`
+	JoinedAt       time.Time `gorm:"not null" json:"joined_at"`
+}
+
+// TableName sets the table name for GroupBookingParticipant
+func (GroupBookingParticipant) TableName() string {
+	return "group_booking_participants"
+}
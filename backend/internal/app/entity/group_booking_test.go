@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGroupBookingStatusAt(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		status GroupBookingStatus
+		expiry time.Time
+		want   GroupBookingStatus
+	}{
+		{"open", GroupBookingOpen, now.Add(time.Minute), GroupBookingOpen},
+		{"open past its expiry", GroupBookingOpen, now, GroupBookingExpired},
+		{"finalized past its expiry", GroupBookingFinalized, now.Add(-time.Minute), GroupBookingFinalized},
+	}
+	for _, tt := range tests {
+		g := &GroupBooking{Status: tt.status, ExpiresAt: tt.expiry}
+		if got := g.StatusAt(now); got != tt.want {
+			t.Errorf("%s: StatusAt = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if !GroupBookingOpen.CanTransitionTo(GroupBookingFinalized) || GroupBookingFinalized.CanTransitionTo(GroupBookingExpired) {
+		t.Error("only open groups may be finalized or expired")
+	}
+}
+
+func TestGroupBookingParticipants(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	seats := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	g := &GroupBooking{MaxParticipants: 3, CurrentParticipants: 2, Participants: []GroupBookingParticipant{
+		{UserID: alice, SeatIDs: UUIDList{seats[0], seats[1]}},
+		{UserID: bob, SeatIDs: UUIDList{seats[2]}},
+	}}
+
+	if g.IsFull() {
+		t.Error("2 of 3 participants is full")
+	}
+	if p, ok := g.Participant(bob); !ok || p.UserID != bob {
+		t.Errorf("Participant(bob) = %v, %v", p, ok)
+	}
+	if _, ok := g.Participant(uuid.New()); ok {
+		t.Error("a stranger is a participant")
+	}
+	if pooled := g.PooledSeatIDs(); len(pooled) != 3 || !pooled.Contains(seats[2]) {
+		t.Errorf("pooled seats = %v, want all 3", pooled)
+	}
+
+	g.CurrentParticipants++
+	if !g.IsFull() {
+		t.Error("3 of 3 participants is not full")
+	}
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupCheckoutStatus represents the state of a split-payment group checkout
+type GroupCheckoutStatus string
+
+const (
+	// GroupCheckoutOpen means shares are out for payment
+	GroupCheckoutOpen GroupCheckoutStatus = "OPEN"
+	// GroupCheckoutAwaitingOrganizer means the share window lapsed with unpaid shares
+	// and the organizer has to pay the remainder or release those seats
+	GroupCheckoutAwaitingOrganizer GroupCheckoutStatus = "AWAITING_ORGANIZER"
+	// GroupCheckoutCompleted means every remaining share is paid and the booking is confirmed
+	GroupCheckoutCompleted GroupCheckoutStatus = "COMPLETED"
+	// GroupCheckoutCancelled means nothing was paid and all seats were released
+	GroupCheckoutCancelled GroupCheckoutStatus = "CANCELLED"
+)
+
+// groupCheckoutTransitions lists the allowed state transitions
+var groupCheckoutTransitions = map[GroupCheckoutStatus][]GroupCheckoutStatus{
+	GroupCheckoutOpen:              {GroupCheckoutAwaitingOrganizer, GroupCheckoutCompleted, GroupCheckoutCancelled},
+	GroupCheckoutAwaitingOrganizer: {GroupCheckoutOpen, GroupCheckoutCompleted, GroupCheckoutCancelled},
+}
+
+// CanTransitionTo returns true if the status may move to next
+func (s GroupCheckoutStatus) CanTransitionTo(next GroupCheckoutStatus) bool {
+	for _, allowed := range groupCheckoutTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal returns true if no further transitions are possible
+func (s GroupCheckoutStatus) IsTerminal() bool {
+	return len(groupCheckoutTransitions[s]) == 0
+}
+
+// GroupCheckoutShareStatus represents the payment state of a single share
+type GroupCheckoutShareStatus string
+
+const (
+	ShareStatusPending  GroupCheckoutShareStatus = "PENDING"
+	ShareStatusPaid     GroupCheckoutShareStatus = "PAID"
+	ShareStatusExpired  GroupCheckoutShareStatus = "EXPIRED"
+	ShareStatusReleased GroupCheckoutShareStatus = "RELEASED"
+)
+
+// GroupCheckout splits a seat hold into shares paid by different people
+type GroupCheckout struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GroupReference string              `gorm:"uniqueIndex;not null" json:"group_reference"`
+	HoldID         string              `gorm:"not null;index" json:"hold_id"`
+	ShowtimeID     uuid.UUID           `gorm:"type:uuid;not null" json:"showtime_id"`
+	OrganizerID    uuid.UUID           `gorm:"type:uuid;not null" json:"organizer_id"`
+	OrganizerEmail string              `gorm:"not null" json:"organizer_email"`
+	Status         GroupCheckoutStatus `gorm:"type:varchar(20);default:'OPEN'" json:"status"`
+	TotalAmount    float64             `gorm:"type:decimal(10,2);not null" json:"total_amount"`
+	HoldExpiresAt  time.Time           `gorm:"not null" json:"hold_expires_at"`
+	BookingID      *uuid.UUID          `gorm:"type:uuid" json:"booking_id,omitempty"`
+	CompletedAt    *time.Time          `json:"completed_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+
+	// Relations
+	Shares []GroupCheckoutShare `gorm:"foreignKey:GroupCheckoutID" json:"shares,omitempty"`
+}
+
+// TableName sets the table name for GroupCheckout
+func (GroupCheckout) TableName() string {
+	return "group_checkouts"
+}
+
+// AllSharesSettled returns true if no share is still waiting for payment
+func (g *GroupCheckout) AllSharesSettled() bool {
+	for _, share := range g.Shares {
+		if share.Status == ShareStatusPending {
+			return false
+		}
+	}
+	return true
+}
+
+// PaidShares returns the shares that have been paid
+func (g *GroupCheckout) PaidShares() []GroupCheckoutShare {
+	var paid []GroupCheckoutShare
+	for _, share := range g.Shares {
+		if share.Status == ShareStatusPaid {
+			paid = append(paid, share)
+		}
+	}
+	return paid
+}
+
+// GroupCheckoutShare is the portion of a group checkout paid by one invitee.
+// It doubles as the payment intent for that portion.
+type GroupCheckoutShare struct {
+	ID                   uuid.UUID                `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GroupCheckoutID      uuid.UUID                `gorm:"type:uuid;not null;index" json:"group_checkout_id"`
+	InviteeEmail         string                   `gorm:"not null" json:"invitee_email"`
+	SeatIDs              UUIDList                 `gorm:"type:jsonb;not null" json:"seat_ids"`
+	Amount               float64                  `gorm:"type:decimal(10,2);not null" json:"amount"`      // booking fee included
+	FeeAmount            float64                  `gorm:"type:decimal(10,2);default:0" json:"fee_amount"` // this share's part of the booking fee
+	Status               GroupCheckoutShareStatus `gorm:"type:varchar(20);default:'PENDING'" json:"status"`
+	PaymentReference     string                   `gorm:"uniqueIndex;not null" json:"payment_reference"`
+	PayTokenHash         string                   `gorm:"uniqueIndex;not null" json:"-"`
+	GatewayTransactionID *string                  `json:"gateway_transaction_id,omitempty"`
+	ExpiresAt            time.Time                `gorm:"not null" json:"expires_at"`
+	PaidAt               *time.Time               `json:"paid_at,omitempty"`
+	CreatedAt            time.Time                `json:"created_at"`
+	UpdatedAt            time.Time                `json:"updated_at"`
+}
+
+// TableName sets the table name for GroupCheckoutShare
+func (GroupCheckoutShare) TableName() string {
+	return "group_checkout_shares"
+}
+
+// IsPayable returns true if the share can still be paid
+func (s *GroupCheckoutShare) IsPayable() bool {
+	return s.Status == ShareStatusPending && time.Now().Before(s.ExpiresAt)
+}
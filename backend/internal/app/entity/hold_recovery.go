@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// HoldRecovery records the "your seats were released" email sent after a
+// user's hold expired. There is at most one per user and showtime.
+type HoldRecovery struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	ShowtimeID uuid.UUID      `gorm:"type:uuid;not null" json:"showtime_id"`
+	HoldID     string         `gorm:"not null" json:"hold_id"` // the expired hold
+	TokenHash  string         `gorm:"uniqueIndex;not null" json:"-"`
+	SeatTypes  pq.StringArray `gorm:"type:text[]" json:"seat_types"` // seat types of the expired hold
+	// ReholdOffered is true if the email carried a re-hold link
+	ReholdOffered      bool       `gorm:"not null;default:false" json:"rehold_offered"`
+	SentAt             time.Time  `gorm:"not null" json:"sent_at"`
+	ReheldAt           *time.Time `json:"reheld_at,omitempty"`
+	ReholdHoldID       *string    `json:"rehold_hold_id,omitempty"`
+	ConvertedAt        *time.Time `json:"converted_at,omitempty"`
+	ConvertedBookingID *uuid.UUID `gorm:"type:uuid" json:"converted_booking_id,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name for HoldRecovery
+func (HoldRecovery) TableName() string {
+	return "hold_recoveries"
+}
+
+// IsConverted returns true if a booking followed the recovery email
+func (r *HoldRecovery) IsConverted() bool {
+	return r.ConvertedAt != nil
+}
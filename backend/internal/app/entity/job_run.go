@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// JobRun is the run history of a scheduled background job, shared by all
+// instances
+type JobRun struct {
+	Name           string     `gorm:"primaryKey" json:"name"`
+	Owner          string     `gorm:"not null" json:"owner"` // instance that last started the job
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastSuccessAt  *time.Time `json:"last_success_at,omitempty"`
+	LastError      *string    `gorm:"type:text" json:"last_error,omitempty"`
+	LastDurationMs int64      `gorm:"not null;default:0" json:"last_duration_ms"`
+	Runs           int64      `gorm:"not null;default:0" json:"runs"`
+	Failures       int64      `gorm:"not null;default:0" json:"failures"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for JobRun
+func (JobRun) TableName() string {
+	return "job_runs"
+}
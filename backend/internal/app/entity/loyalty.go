@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyTransactionType says why a loyalty balance changed
+type LoyaltyTransactionType string
+
+const (
+	// LoyaltyEarn credits points for a confirmed booking
+	LoyaltyEarn LoyaltyTransactionType = "EARN"
+	// LoyaltyRedeem debits points spent on a booking
+	LoyaltyRedeem LoyaltyTransactionType = "REDEEM"
+	// LoyaltyExpire debits the balance of an account left inactive
+	LoyaltyExpire LoyaltyTransactionType = "EXPIRE"
+	// LoyaltyRefund credits back points redeemed on a booking that was
+	// cancelled or never paid
+	LoyaltyRefund LoyaltyTransactionType = "REFUND"
+	// LoyaltyReverse debits points earned on a booking that was cancelled
+	LoyaltyReverse LoyaltyTransactionType = "REVERSE"
+)
+
+const (
+	// LoyaltyPointsPerDollar is how many points a dollar spent earns
+	LoyaltyPointsPerDollar = 10
+	// loyaltyPointCents is what a redeemed point is worth, in cents
+	loyaltyPointCents = 1
+)
+
+// LoyaltyAccount holds a user's loyalty points
+type LoyaltyAccount struct {
+	UserID         uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	PointsBalance  int       `gorm:"not null;default:0" json:"points_balance"`
+	LastActivityAt time.Time `gorm:"not null" json:"last_activity_at"` // last earn or redeem
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for LoyaltyAccount
+func (LoyaltyAccount) TableName() string {
+	return "loyalty_accounts"
+}
+
+// ExpiresAt returns when the balance expires unless the account is used
+func (a *LoyaltyAccount) ExpiresAt(inactivity time.Duration) time.Time {
+	return a.LastActivityAt.Add(inactivity)
+}
+
+// LoyaltyTransaction is one change of a loyalty balance. Points are signed:
+// credits are positive and debits negative.
+type LoyaltyTransaction struct {
+	ID          uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID              `gorm:"type:uuid;not null" json:"user_id"`
+	BookingID   *uuid.UUID             `gorm:"type:uuid" json:"booking_id,omitempty"`
+	Type        LoyaltyTransactionType `gorm:"type:varchar(20);not null" json:"type"`
+	Points      int                    `gorm:"not null" json:"points"`
+	Description string                 `gorm:"not null" json:"description"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// TableName sets the table name for LoyaltyTransaction
+func (LoyaltyTransaction) TableName() string {
+	return "loyalty_transactions"
+}
+
+// LoyaltyPointsEarned returns the points an amount spent earns, rounded down
+func LoyaltyPointsEarned(amount float64) int {
+	return int(max(toCents(amount), 0) * LoyaltyPointsPerDollar / 100)
+}
+
+// LoyaltyPointsValue returns what points are worth when redeemed
+func LoyaltyPointsValue(points int) float64 {
+	return fromCents(int64(points) * loyaltyPointCents)
+}
+
+// RedeemPoints takes loyalty points off the seats of a priced order, after
+// any promo discount; the booking fee is still paid. Their value goes to
+// PointsDiscount, leaving the promo discount as it was. It uses no more
+// points than the seats cost and returns the new breakdown with the points
+// used.
+func (b PriceBreakdown) RedeemPoints(points int) (PriceBreakdown, int) {
+	if points <= 0 {
+		return b, 0
+	}
+	seats := toCents(b.Subtotal - b.Discount - b.PointsDiscount)
+	used := min(points, int(seats/loyaltyPointCents))
+	value := LoyaltyPointsValue(used)
+
+	b.PointsDiscount = RoundCents(b.PointsDiscount + value)
+	b.Total = RoundCents(b.Total - value)
+	return b, used
+}
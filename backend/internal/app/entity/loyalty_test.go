@@ -0,0 +1,92 @@
+package entity
+
+import "testing"
+
+func TestRedeemPoints(t *testing.T) {
+	// 38.97 of seats, 5.85 promo discount, 4.50 fee
+	priced := PriceOrder(38.97, 3, activePromo("PERCENTAGE", 15), BookingFee{Type: FeePerTicket, Amount: 1.5}, ChannelOnline)
+
+	tests := []struct {
+		name     string
+		points   int
+		want     PriceBreakdown
+		wantUsed int
+	}{
+		{
+			name:     "no points",
+			points:   0,
+			want:     priced,
+			wantUsed: 0,
+		},
+		{
+			name:     "negative points are ignored",
+			points:   -5,
+			want:     priced,
+			wantUsed: 0,
+		},
+		{
+			name:     "points kept apart from the promo discount",
+			points:   1000,
+			want:     PriceBreakdown{Subtotal: 38.97, Discount: 5.85, PointsDiscount: 10, Fee: 4.5, Total: 27.62},
+			wantUsed: 1000,
+		},
+		{
+			name:     "one point is one cent",
+			points:   7,
+			want:     PriceBreakdown{Subtotal: 38.97, Discount: 5.85, PointsDiscount: 0.07, Fee: 4.5, Total: 37.55},
+			wantUsed: 7,
+		},
+		{
+			name:     "capped at the discounted seats, the fee is still paid",
+			points:   100000,
+			want:     PriceBreakdown{Subtotal: 38.97, Discount: 5.85, PointsDiscount: 33.12, Fee: 4.5, Total: 4.5},
+			wantUsed: 3312,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used := priced.RedeemPoints(tt.points)
+			if got != tt.want || used != tt.wantUsed {
+				t.Errorf("RedeemPoints(%d) = %+v, %d; want %+v, %d", tt.points, got, used, tt.want, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestRedeemPointsTwice(t *testing.T) {
+	priced := PriceOrder(20, 2, nil, BookingFee{Type: FeePerOrder, Amount: 1}, ChannelOnline)
+
+	once, first := priced.RedeemPoints(1500)
+	twice, second := once.RedeemPoints(1500)
+	if first != 1500 || second != 500 {
+		t.Errorf("used %d then %d points, want 1500 then 500", first, second)
+	}
+	want := PriceBreakdown{Subtotal: 20, PointsDiscount: 20, Fee: 1, Total: 1}
+	if twice != want {
+		t.Errorf("breakdown = %+v, want %+v", twice, want)
+	}
+}
+
+func TestLoyaltyPoints(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   int
+	}{
+		{0, 0},
+		{-10, 0},
+		{0.09, 0},
+		{0.1, 1},
+		{12.99, 129},
+		{43.47, 434},
+	}
+	for _, tt := range tests {
+		if got := LoyaltyPointsEarned(tt.amount); got != tt.want {
+			t.Errorf("LoyaltyPointsEarned(%v) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+
+	if got := LoyaltyPointsValue(3312); got != 33.12 {
+		t.Errorf("LoyaltyPointsValue(3312) = %v, want 33.12", got)
+	}
+}
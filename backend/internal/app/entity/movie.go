@@ -1,9 +1,13 @@
 package entity
 
 import (
+	"cmp"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -46,6 +50,8 @@ type Movie struct {
 	IsNowShowing    bool           `gorm:"default:false" json:"is_now_showing"`
 	IsComingSoon    bool           `gorm:"default:false" json:"is_coming_soon"`
 	PopularityScore float64        `gorm:"type:decimal(5,2);default:0" json:"popularity_score"`
+	UserRating      *float64       `gorm:"type:decimal(2,1)" json:"user_rating,omitempty"` // average review rating, 1-5
+	ReviewCount     int            `gorm:"default:0" json:"review_count"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
@@ -61,29 +67,41 @@ func (m *Movie) IsReleased() bool {
 	return time.Now().After(m.ReleaseDate)
 }
 
+// IsAvailable returns true if the movie is active and not deleted
+func (m *Movie) IsAvailable() bool {
+	return m.IsActive && !m.DeletedAt.Valid
+}
+
 // Cinema represents a cinema location
 type Cinema struct {
-	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name           string         `gorm:"not null" json:"name"`
-	Slug           string         `gorm:"uniqueIndex;not null" json:"slug"`
-	Description    *string        `gorm:"type:text" json:"description,omitempty"`
-	Address        string         `gorm:"not null" json:"address"`
-	City           string         `gorm:"not null" json:"city"`
-	State          *string        `json:"state,omitempty"`
-	PostalCode     *string        `json:"postal_code,omitempty"`
-	Country        string         `gorm:"not null" json:"country"`
-	Latitude       *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
-	Longitude      *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
-	Phone          *string        `json:"phone,omitempty"`
-	Email          *string        `json:"email,omitempty"`
-	Website        *string        `json:"website,omitempty"`
-	ImageURL       *string        `json:"image_url,omitempty"`
-	OperatingHours OperatingHours `gorm:"type:jsonb" json:"operating_hours,omitempty"`
-	Facilities     pq.StringArray `gorm:"type:text[]" json:"facilities,omitempty"`
-	IsActive       bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                     uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name                   string         `gorm:"not null" json:"name"`
+	Slug                   string         `gorm:"uniqueIndex;not null" json:"slug"`
+	Description            *string        `gorm:"type:text" json:"description,omitempty"`
+	Address                string         `gorm:"not null" json:"address"`
+	City                   string         `gorm:"not null" json:"city"`
+	State                  *string        `json:"state,omitempty"`
+	PostalCode             *string        `json:"postal_code,omitempty"`
+	Country                string         `gorm:"not null" json:"country"`
+	Latitude               *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude              *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	Phone                  *string        `json:"phone,omitempty"`
+	Email                  *string        `json:"email,omitempty"`
+	Website                *string        `json:"website,omitempty"`
+	ImageURL               *string        `json:"image_url,omitempty"`
+	OperatingHours         OperatingHours `gorm:"type:jsonb" json:"operating_hours,omitempty"`
+	Facilities             pq.StringArray `gorm:"type:text[]" json:"facilities,omitempty"`
+	IsActive               bool           `gorm:"default:true" json:"is_active"`
+	Timezone               string         `gorm:"type:varchar(64);default:'UTC'" json:"timezone"` // IANA name, e.g. Asia/Ho_Chi_Minh
+	CompanionPolicyEnabled bool           `gorm:"default:false" json:"companion_policy_enabled"`  // free companion seats for wheelchair users
+	FeeType                FeeType        `gorm:"type:varchar(20);default:'NONE'" json:"fee_type"`
+	FeeAmount              float64        `gorm:"type:decimal(10,2);default:0" json:"fee_amount"` // flat amount, or percent for PERCENTAGE
+	FeeCap                 *float64       `gorm:"type:decimal(10,2)" json:"fee_cap,omitempty"`
+	FeeOnlineOnly          bool           `gorm:"default:true" json:"fee_online_only"`
+	FeeRefundable          bool           `gorm:"default:false" json:"fee_refundable"`
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	Screens []Screen `gorm:"foreignKey:CinemaID" json:"screens,omitempty"`
@@ -94,6 +112,69 @@ func (Cinema) TableName() string {
 	return "cinemas"
 }
 
+// BookingFee returns the cinema's booking fee policy
+func (c *Cinema) BookingFee() BookingFee {
+	return BookingFee{
+		Type:       c.FeeType,
+		Amount:     c.FeeAmount,
+		Cap:        c.FeeCap,
+		OnlineOnly: c.FeeOnlineOnly,
+		Refundable: c.FeeRefundable,
+	}
+}
+
+// EarthRadiusKm is the mean radius used for great-circle distances
+const EarthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance from the cinema to a point,
+// or false when the cinema has no coordinates
+func (c *Cinema) DistanceKm(lat, lon float64) (float64, bool) {
+	if c.Latitude == nil || c.Longitude == nil {
+		return 0, false
+	}
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	lat1, lat2 := toRad(lat), toRad(*c.Latitude)
+	dLat, dLon := lat2-lat1, toRad(*c.Longitude-lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h))), true
+}
+
+// Location returns the cinema's time zone, falling back to UTC
+func (c *Cinema) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ClosingTime returns when the cinema closes on the given business date.
+// Operating hours are keyed by lowercase weekday name; closing times before
+// the opening time fall on the next day. Without hours for the day the
+// cinema is taken to close at midnight.
+func (c *Cinema) ClosingTime(date time.Time) time.Time {
+	loc := c.Location()
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	midnight := day.AddDate(0, 0, 1)
+
+	hours, ok := c.OperatingHours[strings.ToLower(day.Weekday().String())]
+	if !ok || hours.Closed {
+		return midnight
+	}
+	closeAt, err := time.ParseInLocation("15:04", hours.Close, loc)
+	if err != nil {
+		return midnight
+	}
+	closing := time.Date(day.Year(), day.Month(), day.Day(), closeAt.Hour(), closeAt.Minute(), 0, 0, loc)
+	if hours.Open != "" && hours.Close < hours.Open {
+		closing = closing.AddDate(0, 0, 1)
+	}
+	return closing
+}
+
 // OperatingHours represents operating hours for each day
 type OperatingHours map[string]DayHours
 
@@ -129,6 +210,9 @@ const (
 	ScreenVIP      ScreenType = "VIP"
 )
 
+// screenTypes lists the known screen types
+var screenTypes = []ScreenType{ScreenStandard, ScreenIMAX, Screen4DX, ScreenDolby, ScreenVIP}
+
 // SupportedFormats is a slice of MovieFormat
 type SupportedFormats []MovieFormat
 
@@ -173,6 +257,11 @@ func (Screen) TableName() string {
 	return "screens"
 }
 
+// IsAvailable returns true if the screen is active and not deleted
+func (s *Screen) IsAvailable() bool {
+	return s.IsActive && !s.DeletedAt.Valid
+}
+
 // SeatType represents seat types
 type SeatType string
 
@@ -183,6 +272,15 @@ const (
 	SeatWheelchair SeatType = "WHEELCHAIR"
 	SeatCouple     SeatType = "COUPLE"
 	SeatRecliner   SeatType = "RECLINER"
+	SeatCompanion  SeatType = "COMPANION" // designated companion seat next to a wheelchair space
+)
+
+// Fare describes how a booked seat is priced
+type Fare string
+
+const (
+	FareStandard  Fare = "STANDARD"
+	FareCompanion Fare = "COMPANION" // free wheelchair companion ticket
 )
 
 // SeatStatus represents seat availability status
@@ -197,15 +295,16 @@ const (
 
 // Seat represents a seat in a screen
 type Seat struct {
-	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ScreenID   uuid.UUID      `gorm:"type:uuid;not null" json:"screen_id"`
-	RowLabel   string         `gorm:"not null" json:"row_label"` // A, B, C...
-	SeatNumber int            `gorm:"not null" json:"seat_number"`
-	SeatType   SeatType       `gorm:"type:varchar(20);default:'STANDARD'" json:"seat_type"`
-	XPosition  float64        `gorm:"type:decimal(5,2)" json:"x_position"`
-	YPosition  float64        `gorm:"type:decimal(5,2)" json:"y_position"`
-	IsActive   bool           `gorm:"default:true" json:"is_active"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ScreenID        uuid.UUID      `gorm:"type:uuid;not null" json:"screen_id"`
+	RowLabel        string         `gorm:"not null" json:"row_label"` // A, B, C...
+	SeatNumber      int            `gorm:"not null" json:"seat_number"`
+	SeatType        SeatType       `gorm:"type:varchar(20);default:'STANDARD'" json:"seat_type"`
+	XPosition       float64        `gorm:"type:decimal(5,2)" json:"x_position"`
+	YPosition       float64        `gorm:"type:decimal(5,2)" json:"y_position"`
+	IsActive        bool           `gorm:"default:true" json:"is_active"`
+	CompanionSeatID *uuid.UUID     `gorm:"type:uuid" json:"companion_seat_id,omitempty"` // set on wheelchair seats
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	Screen Screen `gorm:"foreignKey:ScreenID" json:"-"`
@@ -220,3 +319,22 @@ func (Seat) TableName() string {
 func (s *Seat) SeatLabel() string {
 	return s.RowLabel + string(rune('0'+s.SeatNumber))
 }
+
+// CompareRowLabels orders row labels A..Z before AA..AZ, the way rows are
+// lettered past Z
+func CompareRowLabels(a, b string) int {
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// SortSeats orders seats by row, then by seat number
+func SortSeats(seats []*Seat) {
+	slices.SortStableFunc(seats, func(a, b *Seat) int {
+		if c := CompareRowLabels(a.RowLabel, b.RowLabel); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.SeatNumber, b.SeatNumber)
+	})
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaKind is the kind of a movie media asset
+type MediaKind string
+
+const (
+	MediaTrailer   MediaKind = "TRAILER"
+	MediaTeaser    MediaKind = "TEASER"
+	MediaStill     MediaKind = "STILL"
+	MediaPosterAlt MediaKind = "POSTER_ALT" // alternative poster artwork
+)
+
+// MaxMediaPerMovie caps the media assets of one movie
+const MaxMediaPerMovie = 50
+
+// IsVideo returns true for kinds hosted on a video platform
+func (k MediaKind) IsVideo() bool {
+	return k == MediaTrailer || k == MediaTeaser
+}
+
+// MovieMedia is a trailer, teaser or image of a movie's media gallery.
+// Assets are shown in sort order; unpublished assets are only visible to
+// admins.
+type MovieMedia struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MovieID   uuid.UUID `gorm:"type:uuid;not null" json:"movie_id"`
+	Kind      MediaKind `gorm:"type:varchar(20);not null" json:"kind"`
+	URL       string    `gorm:"not null" json:"url"`
+	Title     *string   `json:"title,omitempty"`
+	SortOrder int       `gorm:"not null;default:0" json:"sort_order"`
+	Published bool      `gorm:"not null;default:false" json:"published"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for MovieMedia
+func (MovieMedia) TableName() string {
+	return "movie_media"
+}
+
+// FirstPublishedTrailer returns the first published trailer of media in
+// sort order, or nil
+func FirstPublishedTrailer(media []*MovieMedia) *MovieMedia {
+	for _, m := range media {
+		if m.Kind == MediaTrailer && m.Published {
+			return m
+		}
+	}
+	return nil
+}
+
+var (
+	// ErrUnsupportedVideo is returned for video URLs not on YouTube or Vimeo
+	ErrUnsupportedVideo = errors.New("video must be a YouTube or Vimeo URL")
+	// ErrUnsupportedImage is returned for image URLs that are not an https
+	// JPEG, PNG or WebP file
+	ErrUnsupportedImage = errors.New("image must be an https URL to a .jpg, .jpeg, .png or .webp file")
+)
+
+var (
+	youtubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoID   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// imageExtensions are the image formats accepted for stills and posters
+var imageExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+// NormalizeMediaURL validates an asset URL for its kind. Video URLs in any
+// of the usual YouTube or Vimeo forms (watch, short, embed, player) are
+// rewritten to one canonical form, so the same video is stored once.
+func NormalizeMediaURL(kind MediaKind, raw string) (string, error) {
+	if kind.IsVideo() {
+		return normalizeVideoURL(raw)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", ErrUnsupportedImage
+	}
+	if !imageExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return "", ErrUnsupportedImage
+	}
+	return u.String(), nil
+}
+
+func normalizeVideoURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", ErrUnsupportedVideo
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch host {
+	case "youtube.com", "youtube-nocookie.com":
+		switch {
+		case u.Path == "/watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "v"):
+			id = segments[1]
+		}
+		if youtubeID.MatchString(id) {
+			return "https://www.youtube.com/watch?v=" + id, nil
+		}
+	case "youtu.be":
+		if len(segments) == 1 {
+			id = segments[0]
+		}
+		if youtubeID.MatchString(id) {
+			return "https://www.youtube.com/watch?v=" + id, nil
+		}
+	case "vimeo.com", "player.vimeo.com":
+		if len(segments) > 0 {
+			id = segments[len(segments)-1]
+		}
+		if vimeoID.MatchString(id) {
+			return "https://vimeo.com/" + id, nil
+		}
+	}
+	return "", ErrUnsupportedVideo
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeMediaURL(t *testing.T) {
+	const youtube = "https://www.youtube.com/watch?v=Way9Dexny3w"
+
+	tests := []struct {
+		kind MediaKind
+		raw  string
+		want string
+		err  error
+	}{
+		{MediaTrailer, "https://www.youtube.com/watch?v=Way9Dexny3w&t=42s", youtube, nil},
+		{MediaTrailer, "https://m.youtube.com/watch?v=Way9Dexny3w", youtube, nil},
+		{MediaTrailer, "https://youtu.be/Way9Dexny3w", youtube, nil},
+		{MediaTeaser, "https://www.youtube-nocookie.com/embed/Way9Dexny3w", youtube, nil},
+		{MediaTeaser, "https://youtube.com/shorts/Way9Dexny3w", youtube, nil},
+		{MediaTrailer, "http://vimeo.com/76979871", "https://vimeo.com/76979871", nil},
+		{MediaTrailer, "https://player.vimeo.com/video/76979871", "https://vimeo.com/76979871", nil},
+		{MediaTrailer, "https://www.youtube.com/watch?v=short", "", ErrUnsupportedVideo},
+		{MediaTrailer, "https://vimeo.com/channels/staffpicks", "", ErrUnsupportedVideo},
+		{MediaTrailer, "https://example.com/trailer.mp4", "", ErrUnsupportedVideo},
+		{MediaTrailer, "ftp://youtu.be/Way9Dexny3w", "", ErrUnsupportedVideo},
+		{MediaStill, "https://cdn.example.com/stills/01.JPG", "https://cdn.example.com/stills/01.JPG", nil},
+		{MediaPosterAlt, "https://cdn.example.com/posters/alt.webp?v=2", "https://cdn.example.com/posters/alt.webp?v=2", nil},
+		{MediaStill, "http://cdn.example.com/stills/01.jpg", "", ErrUnsupportedImage},
+		{MediaStill, "https://cdn.example.com/stills/01.gif", "", ErrUnsupportedImage},
+		{MediaStill, "https:///01.png", "", ErrUnsupportedImage},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeMediaURL(tt.kind, tt.raw)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("NormalizeMediaURL(%s, %q) = %q, %v, want %q, %v", tt.kind, tt.raw, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCinemaDistanceKm(t *testing.T) {
+	lat, lon := 10.7769, 106.7009 // Ho Chi Minh City
+	cinema := &Cinema{Latitude: &lat, Longitude: &lon}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     float64
+	}{
+		{"same point", lat, lon, 0},
+		{"Hanoi", 21.0285, 105.8542, 1143.5},
+		{"antipode", -lat, lon - 180, math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		got, ok := cinema.DistanceKm(tt.lat, tt.lon)
+		if !ok || math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("%s: DistanceKm = %.2f, %v, want %.1f", tt.name, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := (&Cinema{Latitude: &lat}).DistanceKm(lat, lon); ok {
+		t.Error("a cinema without a longitude has a distance")
+	}
+}
+
+func TestSortSeats(t *testing.T) {
+	seat := func(row string, number int) *Seat { return &Seat{RowLabel: row, SeatNumber: number} }
+	seats := []*Seat{seat("AB", 2), seat("B", 1), seat("AA", 1), seat("A", 10), seat("Z", 1), seat("A", 2), seat("AB", 1), seat("A", 1)}
+
+	SortSeats(seats)
+	var got []string
+	for _, s := range seats {
+		got = append(got, fmt.Sprintf("%s%d", s.RowLabel, s.SeatNumber))
+	}
+	want := []string{"A1", "A2", "A10", "B1", "Z1", "AA1", "AB1", "AB2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
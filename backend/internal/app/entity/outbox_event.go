@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// OutboxEvent is a domain event stored in the transaction that produced it,
+// waiting for the outbox relay to publish it
+type OutboxEvent struct {
+	ID          int64      `gorm:"primaryKey" json:"id"` // publication order
+	EventName   string     `gorm:"not null" json:"event_name"`
+	AggregateID string     `gorm:"not null" json:"aggregate_id"`
+	Payload     string     `gorm:"type:jsonb;not null" json:"payload"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"` // failed publication attempts
+	LastError   *string    `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// TableName sets the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "event_outbox"
+}
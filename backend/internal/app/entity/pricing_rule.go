@@ -0,0 +1,258 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRuleType is what a pricing rule matches on
+type PricingRuleType string
+
+const (
+	PricingRuleSeatType  PricingRuleType = "SEAT_TYPE"
+	PricingRuleTimeOfDay PricingRuleType = "TIME_OF_DAY"
+	PricingRuleDayOfWeek PricingRuleType = "DAY_OF_WEEK"
+	PricingRuleOccupancy PricingRuleType = "OCCUPANCY"
+)
+
+// PricingModifierType is how a pricing rule changes the price
+type PricingModifierType string
+
+const (
+	PricingModifierFlat       PricingModifierType = "FLAT"       // amount added, negative for a discount
+	PricingModifierPercentage PricingModifierType = "PERCENTAGE" // percent added, negative for a discount
+)
+
+// weekdays names the days of a DAY_OF_WEEK condition
+var weekdays = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+// PricingRule adjusts a cinema's seat prices when its condition matches,
+// e.g. +20% on Saturdays or -2.00 for matinees. A rule can be limited to
+// one screen type and to showtimes starting within a validity window.
+// When several rules of the same type apply to a seat, only those with the
+// highest priority are applied, so a specific rule can override a general
+// one.
+type PricingRule struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CinemaID      uuid.UUID           `gorm:"type:uuid;not null;index" json:"cinema_id"`
+	RuleType      PricingRuleType     `gorm:"type:varchar(20);not null" json:"rule_type"`
+	ModifierType  PricingModifierType `gorm:"type:varchar(20);not null" json:"modifier_type"`
+	ModifierValue float64             `gorm:"type:decimal(10,2);not null" json:"modifier_value"`
+	ConditionJSON string              `gorm:"column:condition_json;type:jsonb;not null;default:'{}'" json:"condition_json"`
+	ScreenType    *ScreenType         `gorm:"type:varchar(20)" json:"screen_type,omitempty"` // nil for every screen
+	Priority      int                 `gorm:"not null;default:0" json:"priority"`
+	ValidFrom     *time.Time          `json:"valid_from,omitempty"`  // showtimes starting at or after, nil for no start
+	ValidUntil    *time.Time          `json:"valid_until,omitempty"` // showtimes starting before, nil for no end
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// TableName sets the table name for PricingRule
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
+
+// PricingCondition is when a pricing rule applies. Only the fields of the
+// rule's type are used.
+type PricingCondition struct {
+	SeatTypes    []SeatType `json:"seat_types,omitempty"`    // SEAT_TYPE
+	From         string     `json:"from,omitempty"`          // TIME_OF_DAY start time, HH:MM, inclusive
+	To           string     `json:"to,omitempty"`            // TIME_OF_DAY start time, HH:MM, exclusive; before From wraps past midnight
+	Days         []string   `json:"days,omitempty"`          // DAY_OF_WEEK, MON..SUN
+	MinOccupancy *float64   `json:"min_occupancy,omitempty"` // OCCUPANCY percent sold, inclusive
+	MaxOccupancy *float64   `json:"max_occupancy,omitempty"` // OCCUPANCY percent sold, exclusive
+}
+
+// Condition parses the rule's condition
+func (r *PricingRule) Condition() (PricingCondition, error) {
+	var cond PricingCondition
+	if r.ConditionJSON == "" {
+		return cond, nil
+	}
+	if err := json.Unmarshal([]byte(r.ConditionJSON), &cond); err != nil {
+		return cond, fmt.Errorf("invalid condition: %w", err)
+	}
+	return cond, nil
+}
+
+// Validate checks the rule's modifier, scope and validity window, and that
+// its condition fits its type
+func (r *PricingRule) Validate() error {
+	if r.ScreenType != nil && !slices.Contains(screenTypes, *r.ScreenType) {
+		return fmt.Errorf("unknown screen type %q", *r.ScreenType)
+	}
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidFrom.Before(*r.ValidUntil) {
+		return errors.New("valid_from must be before valid_until")
+	}
+
+	switch r.ModifierType {
+	case PricingModifierFlat:
+	case PricingModifierPercentage:
+		if r.ModifierValue < -100 {
+			return errors.New("a percentage modifier cannot take off more than 100%")
+		}
+	default:
+		return fmt.Errorf("unknown modifier type %q", r.ModifierType)
+	}
+
+	cond, err := r.Condition()
+	if err != nil {
+		return err
+	}
+	switch r.RuleType {
+	case PricingRuleSeatType:
+		if len(cond.SeatTypes) == 0 {
+			return errors.New("seat_types is required")
+		}
+	case PricingRuleTimeOfDay:
+		if _, err := minuteOfDay(cond.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		if _, err := minuteOfDay(cond.To); err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		if cond.From == cond.To {
+			return errors.New("from and to must differ")
+		}
+	case PricingRuleDayOfWeek:
+		if len(cond.Days) == 0 {
+			return errors.New("days is required")
+		}
+		for _, day := range cond.Days {
+			if _, ok := weekdays[strings.ToUpper(day)]; !ok {
+				return fmt.Errorf("unknown day %q, expected MON..SUN", day)
+			}
+		}
+	case PricingRuleOccupancy:
+		if cond.MinOccupancy == nil && cond.MaxOccupancy == nil {
+			return errors.New("min_occupancy or max_occupancy is required")
+		}
+		if cond.MinOccupancy != nil && cond.MaxOccupancy != nil && *cond.MinOccupancy >= *cond.MaxOccupancy {
+			return errors.New("min_occupancy must be below max_occupancy")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.RuleType)
+	}
+	return nil
+}
+
+// AppliesTo reports whether the showtime is within the rule's screen type
+// and validity window. The showtime needs its screen and cinema loaded.
+func (r *PricingRule) AppliesTo(showtime *Showtime) bool {
+	if r.ScreenType != nil && showtime.Screen.ScreenType != *r.ScreenType {
+		return false
+	}
+	if r.ValidFrom == nil && r.ValidUntil == nil {
+		return true
+	}
+	startsAt := showtime.StartsAt(showtime.Cinema.Location())
+	if r.ValidFrom != nil && startsAt.Before(*r.ValidFrom) {
+		return false
+	}
+	return r.ValidUntil == nil || startsAt.Before(*r.ValidUntil)
+}
+
+// MatchesShowtime reports whether the condition holds for the showtime.
+// SEAT_TYPE conditions hold for every showtime; MatchesSeat decides them.
+func (c PricingCondition) MatchesShowtime(ruleType PricingRuleType, showtime *Showtime) bool {
+	switch ruleType {
+	case PricingRuleSeatType:
+		return true
+	case PricingRuleTimeOfDay:
+		// Showtime times are wall-clock times of the cinema
+		start := showtime.StartsAt(time.UTC)
+		minute := start.Hour()*60 + start.Minute()
+		from, err := minuteOfDay(c.From)
+		if err != nil {
+			return false
+		}
+		to, err := minuteOfDay(c.To)
+		if err != nil {
+			return false
+		}
+		if from < to {
+			return minute >= from && minute < to
+		}
+		return minute >= from || minute < to
+	case PricingRuleDayOfWeek:
+		weekday := showtime.ShowDate.Weekday()
+		return slices.ContainsFunc(c.Days, func(day string) bool {
+			d, ok := weekdays[strings.ToUpper(day)]
+			return ok && d == weekday
+		})
+	case PricingRuleOccupancy:
+		occupancy := showtime.OccupancyRate()
+		if c.MinOccupancy != nil && occupancy < *c.MinOccupancy {
+			return false
+		}
+		if c.MaxOccupancy != nil && occupancy >= *c.MaxOccupancy {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// MatchesSeat reports whether the condition holds for a seat type. Only
+// SEAT_TYPE conditions depend on the seat.
+func (c PricingCondition) MatchesSeat(ruleType PricingRuleType, seatType SeatType) bool {
+	return ruleType != PricingRuleSeatType || slices.Contains(c.SeatTypes, seatType)
+}
+
+// TopPriorityPricingRules keeps, of each rule type, only the rules with the
+// highest priority
+func TopPriorityPricingRules(rules []*PricingRule) []*PricingRule {
+	top := make(map[PricingRuleType]int)
+	for _, rule := range rules {
+		if p, ok := top[rule.RuleType]; !ok || rule.Priority > p {
+			top[rule.RuleType] = rule.Priority
+		}
+	}
+	kept := make([]*PricingRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Priority == top[rule.RuleType] {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// ApplyPricingRules adjusts a price by the rules that apply to it.
+// Percentage modifiers compound first, then flat amounts are added, so the
+// result does not depend on the order of the rules. Prices do not go below
+// zero.
+func ApplyPricingRules(price float64, rules []*PricingRule) float64 {
+	var flat float64
+	for _, rule := range rules {
+		switch rule.ModifierType {
+		case PricingModifierPercentage:
+			price *= 1 + rule.ModifierValue/100
+		case PricingModifierFlat:
+			flat += rule.ModifierValue
+		}
+	}
+	return RoundCents(max(price+flat, 0))
+}
+
+// minuteOfDay parses an HH:MM time into minutes since midnight
+func minuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
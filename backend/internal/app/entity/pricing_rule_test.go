@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPricingRuleValidate(t *testing.T) {
+	imax, hologram := ScreenIMAX, ScreenType("HOLOGRAM")
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	until := from.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name  string
+		rule  PricingRule
+		valid bool
+	}{
+		{"seat types", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ModifierValue: 2, ConditionJSON: `{"seat_types":["VIP"]}`}, true},
+		{"seat types missing", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ModifierValue: 2}, false},
+		{"time of day", PricingRule{RuleType: PricingRuleTimeOfDay, ModifierType: PricingModifierPercentage, ModifierValue: -20, ConditionJSON: `{"from":"10:00","to":"14:00"}`}, true},
+		{"time of day past midnight", PricingRule{RuleType: PricingRuleTimeOfDay, ModifierType: PricingModifierPercentage, ModifierValue: 10, ConditionJSON: `{"from":"22:00","to":"02:00"}`}, true},
+		{"time of day, bad time", PricingRule{RuleType: PricingRuleTimeOfDay, ModifierType: PricingModifierFlat, ConditionJSON: `{"from":"25:00","to":"02:00"}`}, false},
+		{"time of day, empty range", PricingRule{RuleType: PricingRuleTimeOfDay, ModifierType: PricingModifierFlat, ConditionJSON: `{"from":"10:00","to":"10:00"}`}, false},
+		{"days", PricingRule{RuleType: PricingRuleDayOfWeek, ModifierType: PricingModifierPercentage, ModifierValue: 20, ConditionJSON: `{"days":["sat","SUN"]}`}, true},
+		{"unknown day", PricingRule{RuleType: PricingRuleDayOfWeek, ModifierType: PricingModifierFlat, ConditionJSON: `{"days":["FUNDAY"]}`}, false},
+		{"occupancy", PricingRule{RuleType: PricingRuleOccupancy, ModifierType: PricingModifierPercentage, ModifierValue: 15, ConditionJSON: `{"min_occupancy":80}`}, true},
+		{"occupancy, inverted range", PricingRule{RuleType: PricingRuleOccupancy, ModifierType: PricingModifierFlat, ConditionJSON: `{"min_occupancy":80,"max_occupancy":20}`}, false},
+		{"occupancy, no bound", PricingRule{RuleType: PricingRuleOccupancy, ModifierType: PricingModifierFlat}, false},
+		{"more than 100% off", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierPercentage, ModifierValue: -150, ConditionJSON: `{"seat_types":["VIP"]}`}, false},
+		{"unknown modifier", PricingRule{RuleType: PricingRuleSeatType, ModifierType: "DOUBLE", ConditionJSON: `{"seat_types":["VIP"]}`}, false},
+		{"unknown rule type", PricingRule{RuleType: "WEATHER", ModifierType: PricingModifierFlat}, false},
+		{"malformed condition", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":`}, false},
+		{"screen type", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ScreenType: &imax}, true},
+		{"unknown screen type", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ScreenType: &hologram}, false},
+		{"validity window", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ValidFrom: &from, ValidUntil: &until}, true},
+		{"open-ended window", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ValidUntil: &until}, true},
+		{"empty window", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ValidFrom: &from, ValidUntil: &from}, false},
+		{"inverted window", PricingRule{RuleType: PricingRuleSeatType, ModifierType: PricingModifierFlat, ConditionJSON: `{"seat_types":["VIP"]}`, ValidFrom: &until, ValidUntil: &from}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestPricingConditionMatchesShowtime(t *testing.T) {
+	// A Saturday
+	at := func(startTime string) *Showtime {
+		return &Showtime{ShowDate: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), StartTime: startTime, TotalSeats: 100, AvailableSeats: 100}
+	}
+	sold := func(n int) *Showtime {
+		st := at("20:00")
+		st.AvailableSeats -= n
+		return st
+	}
+	minOcc, maxOcc := 50.0, 90.0
+
+	tests := []struct {
+		name     string
+		ruleType PricingRuleType
+		cond     PricingCondition
+		showtime *Showtime
+		want     bool
+	}{
+		{"matinee", PricingRuleTimeOfDay, PricingCondition{From: "10:00", To: "14:00"}, at("10:00"), true},
+		{"matinee, end excluded", PricingRuleTimeOfDay, PricingCondition{From: "10:00", To: "14:00"}, at("14:00"), false},
+		{"late night before midnight", PricingRuleTimeOfDay, PricingCondition{From: "22:00", To: "02:00"}, at("23:30"), true},
+		{"late night after midnight", PricingRuleTimeOfDay, PricingCondition{From: "22:00", To: "02:00"}, at("01:00"), true},
+		{"late night, evening", PricingRuleTimeOfDay, PricingCondition{From: "22:00", To: "02:00"}, at("20:00"), false},
+		{"weekend", PricingRuleDayOfWeek, PricingCondition{Days: []string{"sat", "SUN"}}, at("20:00"), true},
+		{"weekday", PricingRuleDayOfWeek, PricingCondition{Days: []string{"MON"}}, at("20:00"), false},
+		{"busy", PricingRuleOccupancy, PricingCondition{MinOccupancy: &minOcc, MaxOccupancy: &maxOcc}, sold(50), true},
+		{"quiet", PricingRuleOccupancy, PricingCondition{MinOccupancy: &minOcc}, sold(49), false},
+		{"full, max excluded", PricingRuleOccupancy, PricingCondition{MaxOccupancy: &maxOcc}, sold(90), false},
+		{"seat type holds for every showtime", PricingRuleSeatType, PricingCondition{SeatTypes: []SeatType{SeatVIP}}, at("20:00"), true},
+	}
+	for _, tt := range tests {
+		if got := tt.cond.MatchesShowtime(tt.ruleType, tt.showtime); got != tt.want {
+			t.Errorf("%s: MatchesShowtime = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPricingRules(t *testing.T) {
+	weekend := &PricingRule{ModifierType: PricingModifierPercentage, ModifierValue: 20}
+	matinee := &PricingRule{ModifierType: PricingModifierPercentage, ModifierValue: -50}
+	surcharge := &PricingRule{ModifierType: PricingModifierFlat, ModifierValue: 1.5}
+	voucher := &PricingRule{ModifierType: PricingModifierFlat, ModifierValue: -20}
+
+	tests := []struct {
+		name  string
+		rules []*PricingRule
+		want  float64
+	}{
+		{"no rules", nil, 10},
+		{"percentages compound", []*PricingRule{weekend, matinee}, 6},
+		{"flat after percentages", []*PricingRule{surcharge, weekend}, 13.5},
+		{"order does not matter", []*PricingRule{weekend, surcharge}, 13.5},
+		{"never below zero", []*PricingRule{voucher}, 0},
+	}
+	for _, tt := range tests {
+		if got := ApplyPricingRules(10, tt.rules); got != tt.want {
+			t.Errorf("%s: ApplyPricingRules = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPricingRuleAppliesTo(t *testing.T) {
+	imax := ScreenIMAX
+	saigon, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Midnight in Saigon is 17:00 UTC the day before
+	from := time.Date(2026, 10, 17, 0, 0, 0, 0, saigon)
+	until := from.AddDate(0, 0, 2)
+	show := func(screen ScreenType, day int, startTime string) *Showtime {
+		return &Showtime{ShowDate: time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC), StartTime: startTime,
+			Cinema: Cinema{Timezone: "Asia/Ho_Chi_Minh"}, Screen: Screen{ScreenType: screen}}
+	}
+
+	tests := []struct {
+		name     string
+		rule     PricingRule
+		showtime *Showtime
+		want     bool
+	}{
+		{"no scope", PricingRule{}, show(ScreenStandard, 1, "20:00"), true},
+		{"screen type", PricingRule{ScreenType: &imax}, show(ScreenIMAX, 1, "20:00"), true},
+		{"another screen type", PricingRule{ScreenType: &imax}, show(Screen4DX, 1, "20:00"), false},
+		{"window start is inclusive", PricingRule{ValidFrom: &from, ValidUntil: &until}, show(ScreenStandard, 17, "00:00"), true},
+		{"before the window, in the cinema's timezone", PricingRule{ValidFrom: &from}, show(ScreenStandard, 16, "23:30"), false},
+		{"window end is exclusive", PricingRule{ValidUntil: &until}, show(ScreenStandard, 19, "00:00"), false},
+		{"inside the window", PricingRule{ValidFrom: &from, ValidUntil: &until}, show(ScreenStandard, 18, "23:30"), true},
+		{"screen type outside the window", PricingRule{ScreenType: &imax, ValidUntil: &until}, show(ScreenIMAX, 20, "20:00"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.AppliesTo(tt.showtime); got != tt.want {
+			t.Errorf("%s: AppliesTo = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTopPriorityPricingRules(t *testing.T) {
+	generalSeat := &PricingRule{RuleType: PricingRuleSeatType, Priority: 0}
+	specificSeat := &PricingRule{RuleType: PricingRuleSeatType, Priority: 5}
+	alsoSpecificSeat := &PricingRule{RuleType: PricingRuleSeatType, Priority: 5}
+	weekend := &PricingRule{RuleType: PricingRuleDayOfWeek, Priority: -1}
+
+	got := TopPriorityPricingRules([]*PricingRule{generalSeat, specificSeat, weekend, alsoSpecificSeat})
+	want := []*PricingRule{specificSeat, weekend, alsoSpecificSeat}
+	if len(got) != len(want) {
+		t.Fatalf("kept %d rules, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if kept := TopPriorityPricingRules(nil); len(kept) != 0 {
+		t.Errorf("no rules: kept %d", len(kept))
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Review ratings run from MinReviewRating to MaxReviewRating stars
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+)
+
+// Review is a customer's rating of a movie. Only customers holding a
+// confirmed or completed booking for the movie can review it, once.
+type Review struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MovieID    uuid.UUID      `gorm:"type:uuid;not null" json:"movie_id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	Rating     int            `gorm:"not null" json:"rating"`
+	Title      *string        `json:"title,omitempty"`
+	Body       *string        `gorm:"type:text" json:"body,omitempty"`
+	IsVerified bool           `gorm:"default:false" json:"is_verified"` // the author bought a ticket
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName sets the table name for Review
+func (Review) TableName() string {
+	return "reviews"
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeldSeat is a seat inside a hold with the price quoted when it was held
+type HeldSeat struct {
+	SeatID    uuid.UUID `json:"seat_id"`
+	SeatLabel string    `json:"seat_label"`
+	SeatType  SeatType  `json:"seat_type"`
+	Price     float64   `json:"price"`
+	Fare      Fare      `json:"fare,omitempty"`
+	// PairedSeatID is the wheelchair seat a companion seat was held with
+	PairedSeatID *uuid.UUID `json:"paired_seat_id,omitempty"`
+}
+
+// SeatHold is a temporary reservation of seats for a showtime.
+// Holds live in Redis, or in the database while running without Redis, and
+// disappear when they expire.
+type SeatHold struct {
+	ID         string          `json:"id"`
+	ShowtimeID uuid.UUID       `json:"showtime_id"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Seats      []HeldSeat      `json:"seats"`
+	Subtotal   float64         `json:"subtotal"`
+	Fee        float64         `json:"fee"`
+	FeePolicy  BookingFee      `json:"fee_policy"`        // as quoted when the hold was made
+	Devices    []DeviceRequest `json:"devices,omitempty"` // assistive devices to reserve with the booking
+	CreatedAt  time.Time       `json:"created_at"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	// GroupBookingID is set on holds made by joining a group booking; such
+	// holds are only booked when the group is finalized
+	GroupBookingID *uuid.UUID `json:"group_booking_id,omitempty"`
+}
+
+// Reprice recomputes the subtotal and booking fee from the held seats.
+// Free companion tickets carry no per-ticket fee. Holds are made online, so
+// online-only fees apply.
+func (h *SeatHold) Reprice() {
+	price := h.Price(nil)
+	h.Subtotal, h.Fee = price.Subtotal, price.Fee
+}
+
+// Price prices the held seats with an optional promo code. The booking fee
+// is charged on the discounted subtotal.
+func (h *SeatHold) Price(promo *PromoCode) PriceBreakdown {
+	var subtotal float64
+	tickets := 0
+	for _, seat := range h.Seats {
+		subtotal += seat.Price
+		if seat.Fare != FareCompanion {
+			tickets++
+		}
+	}
+	return PriceOrder(subtotal, tickets, promo, h.FeePolicy, ChannelOnline)
+}
+
+// Total returns what the hold costs, booking fee included
+func (h *SeatHold) Total() float64 {
+	return RoundCents(h.Subtotal + h.Fee)
+}
+
+// SeatIDs returns the IDs of all held seats
+func (h *SeatHold) SeatIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(h.Seats))
+	for _, seat := range h.Seats {
+		ids = append(ids, seat.SeatID)
+	}
+	return ids
+}
+
+// FindSeat returns the held seat with the given ID
+func (h *SeatHold) FindSeat(seatID uuid.UUID) (HeldSeat, bool) {
+	for _, seat := range h.Seats {
+		if seat.SeatID == seatID {
+			return seat, true
+		}
+	}
+	return HeldSeat{}, false
+}
+
+// IsExpired returns true if the hold has expired
+func (h *SeatHold) IsExpired() bool {
+	return time.Now().After(h.ExpiresAt)
+}
+
+// TTL returns the remaining lifetime of the hold
+func (h *SeatHold) TTL() time.Duration {
+	return time.Until(h.ExpiresAt)
+}
+
+// SeatProbe is a snapshot of seat locks and cached booked seats for a
+// showtime. Booked is nil when the booked seats were not cached; Version
+// is the cache version the snapshot was read at.
+type SeatProbe struct {
+	Held    []uuid.UUID
+	Booked  []uuid.UUID
+	Version int64
+}
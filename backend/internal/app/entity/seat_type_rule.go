@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeatTypeRule restricts online sales of one seat type for a showtime, e.g.
+// VIP seats going on sale later than the rest, or recliners partly kept
+// back for the box office. Walk-in sales are not restricted.
+type SeatTypeRule struct {
+	ShowtimeID     uuid.UUID  `gorm:"type:uuid;primary_key" json:"showtime_id"`
+	SeatType       SeatType   `gorm:"type:varchar(20);primary_key" json:"seat_type"`
+	OnlineSalesCap *int       `json:"online_sales_cap,omitempty"` // nil: no cap
+	SalesOpenAt    *time.Time `json:"sales_open_at,omitempty"`    // nil: on sale with the showtime
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for SeatTypeRule
+func (SeatTypeRule) TableName() string {
+	return "showtime_seat_type_rules"
+}
+
+// OnSaleAt reports whether the seat type may be sold online at now. A
+// type's opening only delays sales; the showtime's own window still
+// applies.
+func (r *SeatTypeRule) OnSaleAt(now time.Time) bool {
+	return r.SalesOpenAt == nil || !now.Before(*r.SalesOpenAt)
+}
+
+// Remaining returns how many more seats of the type may be sold online
+// given the seats already sold online, and false when the type is not
+// capped
+func (r *SeatTypeRule) Remaining(sold int) (int, bool) {
+	if r.OnlineSalesCap == nil {
+		return 0, false
+	}
+	return max(*r.OnlineSalesCap-sold, 0), true
+}
+
+// SeatTypeRules indexes a showtime's rules by seat type
+type SeatTypeRules map[SeatType]*SeatTypeRule
+
+// NewSeatTypeRules indexes rules by seat type
+func NewSeatTypeRules(rules []*SeatTypeRule) SeatTypeRules {
+	indexed := make(SeatTypeRules, len(rules))
+	for _, rule := range rules {
+		indexed[rule.SeatType] = rule
+	}
+	return indexed
+}
+
+// Capped returns the seat types with an online sales cap
+func (r SeatTypeRules) Capped() []SeatType {
+	var types []SeatType
+	for seatType, rule := range r {
+		if rule.OnlineSalesCap != nil {
+			types = append(types, seatType)
+		}
+	}
+	return types
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"crypto/subtle"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,6 +29,27 @@ const (
 	PriceTierHoliday  PriceTier = "HOLIDAY"
 )
 
+// SalesState describes whether tickets for a showtime can be bought
+type SalesState string
+
+const (
+	SalesOnSale  SalesState = "ON_SALE"
+	SalesNotOpen SalesState = "SALES_NOT_OPEN"
+	SalesClosed  SalesState = "SALES_CLOSED"
+)
+
+// ShowtimeVisibility controls where a showtime can be found
+type ShowtimeVisibility string
+
+const (
+	// VisibilityPublic showtimes appear in listings
+	VisibilityPublic ShowtimeVisibility = "PUBLIC"
+	// VisibilityUnlisted showtimes are left out of listings but open to anyone with the link
+	VisibilityUnlisted ShowtimeVisibility = "UNLISTED"
+	// VisibilityPrivate showtimes are invite-only and require an access code
+	VisibilityPrivate ShowtimeVisibility = "PRIVATE"
+)
+
 // Showtime represents a movie showtime
 type Showtime struct {
 	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -42,7 +65,20 @@ type Showtime struct {
 	AvailableSeats  int            `gorm:"not null" json:"available_seats"`
 	IsAutoGenerated bool           `gorm:"default:false" json:"is_auto_generated"`
 	Status          ShowtimeStatus `gorm:"type:varchar(20);default:'SCHEDULED'" json:"status"`
-	Version         int            `gorm:"default:0" json:"-"` // For optimistic locking
+	SalesOpenAt     *time.Time     `json:"sales_open_at,omitempty"`  // nil: on sale immediately
+	SalesCloseAt    *time.Time     `json:"sales_close_at,omitempty"` // nil: closes at start time
+	PresaleCodeHash *string        `gorm:"type:varchar(64)" json:"-"`
+
+	// Capacity overrides. CapacityLimit caps bookable seats below TotalSeats;
+	// with Distancing a checkerboard of seats is blocked and BlockedSeats
+	// holds how many.
+	CapacityLimit  *int               `json:"capacity_limit,omitempty"`
+	Distancing     bool               `gorm:"default:false" json:"distancing"`
+	BlockedSeats   int                `gorm:"default:0" json:"blocked_seats"`
+	Visibility     ShowtimeVisibility `gorm:"type:varchar(20);default:'PUBLIC'" json:"visibility"`
+	AccessCodeHash *string            `gorm:"type:varchar(64)" json:"-"`
+
+	Version         int            `gorm:"not null;default:0" json:"-"` // bumped by every versioned write, for optimistic locking
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
@@ -68,10 +104,171 @@ func (s *Showtime) IsUpcoming() bool {
 	return s.Status == ShowtimeScheduled
 }
 
-// OccupancyRate returns the occupancy rate as a percentage
+// OccupancyRate returns the occupancy rate of the effective capacity as a percentage
 func (s *Showtime) OccupancyRate() float64 {
-	if s.TotalSeats == 0 {
+	capacity := s.EffectiveCapacity()
+	if capacity == 0 {
 		return 0
 	}
-	return float64(s.TotalSeats-s.AvailableSeats) / float64(s.TotalSeats) * 100
+	return float64(capacity-s.AvailableSeats) / float64(capacity) * 100
+}
+
+// EffectiveCapacity returns how many seats can be sold, after the capacity
+// limit and any distancing pattern
+func (s *Showtime) EffectiveCapacity() int {
+	capacity := s.TotalSeats - s.BlockedSeats
+	if s.CapacityLimit != nil && *s.CapacityLimit < capacity {
+		capacity = *s.CapacityLimit
+	}
+	return max(capacity, 0)
+}
+
+// ApplyCapacity recomputes AvailableSeats after the capacity overrides
+// changed, keeping seats already sold. Sales beyond a lowered capacity stand
+// but leave nothing available.
+func (s *Showtime) ApplyCapacity(previousCapacity int) {
+	sold := previousCapacity - s.AvailableSeats
+	s.AvailableSeats = max(s.EffectiveCapacity()-sold, 0)
+}
+
+// HasCapacityOverride returns true if fewer seats can be sold than the screen holds
+func (s *Showtime) HasCapacityOverride() bool {
+	return s.EffectiveCapacity() < s.TotalSeats
+}
+
+// IsListed returns true if the showtime appears in public listings
+func (s *Showtime) IsListed() bool {
+	return s.Visibility == "" || s.Visibility == VisibilityPublic
+}
+
+// MovieUnavailable returns true if the loaded movie has been deactivated or
+// deleted. It is false when the movie was not loaded.
+func (s *Showtime) MovieUnavailable() bool {
+	return s.Movie.ID != uuid.Nil && !s.Movie.IsAvailable()
+}
+
+// ScreenUnavailable returns true if the loaded screen has been deactivated
+// or deleted. It is false when the screen was not loaded.
+func (s *Showtime) ScreenUnavailable() bool {
+	return s.Screen.ID != uuid.Nil && !s.Screen.IsAvailable()
+}
+
+// AccessCodeMatches compares the hash of a supplied private access code in constant time
+func (s *Showtime) AccessCodeMatches(codeHash string) bool {
+	if s.AccessCodeHash == nil || *s.AccessCodeHash == "" || codeHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(*s.AccessCodeHash), []byte(codeHash)) == 1
+}
+
+// CanAccess returns true unless the showtime is private and the supplied
+// access code hash does not match
+func (s *Showtime) CanAccess(codeHash string) bool {
+	return s.Visibility != VisibilityPrivate || s.AccessCodeMatches(codeHash)
+}
+
+// CheckerboardBlocked returns the seats a distanced showtime leaves empty:
+// alternate seats in each row, offset by one on every other row, so no two
+// occupied seats are adjacent in a row or column. Rows are ordered by their
+// position on the seat map.
+func CheckerboardBlocked(seats []*Seat) UUIDList {
+	rowY := make(map[string]float64)
+	for _, seat := range seats {
+		if y, ok := rowY[seat.RowLabel]; !ok || seat.YPosition < y {
+			rowY[seat.RowLabel] = seat.YPosition
+		}
+	}
+
+	rows := make([]string, 0, len(rowY))
+	for label := range rowY {
+		rows = append(rows, label)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rowY[rows[i]] != rowY[rows[j]] {
+			return rowY[rows[i]] < rowY[rows[j]]
+		}
+		return rows[i] < rows[j]
+	})
+
+	rowIndex := make(map[string]int, len(rows))
+	for i, label := range rows {
+		rowIndex[label] = i
+	}
+
+	var blocked UUIDList
+	for _, seat := range seats {
+		if seat.IsActive && (rowIndex[seat.RowLabel]+seat.SeatNumber)%2 == 0 {
+			blocked = append(blocked, seat.ID)
+		}
+	}
+	return blocked
+}
+
+// StartsAt returns the start instant of the showtime in the given location
+func (s *Showtime) StartsAt(loc *time.Location) time.Time {
+	start := clockTime(s.StartTime)
+	return time.Date(s.ShowDate.Year(), s.ShowDate.Month(), s.ShowDate.Day(),
+		start.Hour(), start.Minute(), 0, 0, loc)
+}
+
+// EndsAt returns the end instant of the showtime in the given location.
+// Shows running past midnight end on the next day.
+func (s *Showtime) EndsAt(loc *time.Location) time.Time {
+	start := s.StartsAt(loc)
+	end := clockTime(s.EndTime)
+	endsAt := time.Date(start.Year(), start.Month(), start.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if !endsAt.After(start) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
+}
+
+// ClashesWith reports whether two showtimes on the same screen run within
+// gap of each other, leaving too little time to turn the screen around
+func (s *Showtime) ClashesWith(other *Showtime, gap time.Duration) bool {
+	return s.StartsAt(time.UTC).Before(other.EndsAt(time.UTC).Add(gap)) &&
+		other.StartsAt(time.UTC).Before(s.EndsAt(time.UTC).Add(gap))
+}
+
+// clockTime parses a time of day
+func clockTime(value string) time.Time {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		// Postgres TIME columns come back as HH:MM:SS
+		t, _ = time.Parse("15:04:05", value)
+	}
+	return t
+}
+
+// SalesCloseTime returns when ticket sales close
+func (s *Showtime) SalesCloseTime(loc *time.Location) time.Time {
+	if s.SalesCloseAt != nil {
+		return *s.SalesCloseAt
+	}
+	return s.StartsAt(loc)
+}
+
+// SalesStateAt returns the sales state at the given instant. Sales are open
+// on [SalesOpenAt, SalesCloseAt).
+func (s *Showtime) SalesStateAt(now time.Time, loc *time.Location) SalesState {
+	if !now.Before(s.SalesCloseTime(loc)) {
+		return SalesClosed
+	}
+	if s.SalesOpenAt != nil && now.Before(*s.SalesOpenAt) {
+		return SalesNotOpen
+	}
+	return SalesOnSale
+}
+
+// HasPresale returns true if a pre-sale access code is configured
+func (s *Showtime) HasPresale() bool {
+	return s.PresaleCodeHash != nil && *s.PresaleCodeHash != ""
+}
+
+// PresaleCodeMatches compares the hash of a supplied access code in constant time
+func (s *Showtime) PresaleCodeMatches(codeHash string) bool {
+	if !s.HasPresale() || codeHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(*s.PresaleCodeHash), []byte(codeHash)) == 1
 }
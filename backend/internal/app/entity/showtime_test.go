@@ -0,0 +1,189 @@
+package entity
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the tests must not depend on the host's zoneinfo
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestShowtimeStartsAtInCinemaTimezone(t *testing.T) {
+	saigon := mustLocation(t, "Asia/Ho_Chi_Minh")
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		startTime string
+		loc       *time.Location
+		want      time.Time
+	}{
+		{"UTC+7", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "20:00", saigon,
+			time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{"Postgres TIME format", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "20:00:00", saigon,
+			time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{"after midnight local", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), "00:30", saigon,
+			time.Date(2026, 10, 16, 17, 30, 0, 0, time.UTC)},
+		{"New York summer time", time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC), "19:00", newYork,
+			time.Date(2026, 10, 31, 23, 0, 0, 0, time.UTC)},
+		{"New York winter time", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), "19:00", newYork,
+			time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		st := &Showtime{ShowDate: tt.date, StartTime: tt.startTime}
+		if got := st.StartsAt(tt.loc); !got.Equal(tt.want) {
+			t.Errorf("%s: StartsAt = %s, want %s", tt.name, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestSalesStateBoundaries(t *testing.T) {
+	saigon := mustLocation(t, "Asia/Ho_Chi_Minh")
+
+	// 20:00 in Saigon is 13:00 UTC
+	start := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+	open := time.Date(2026, 10, 9, 10, 0, 0, 0, saigon)
+	close := time.Date(2026, 10, 16, 19, 30, 0, 0, saigon)
+
+	windowed := &Showtime{
+		ShowDate:     time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		StartTime:    "20:00",
+		SalesOpenAt:  &open,
+		SalesCloseAt: &close,
+	}
+	defaults := &Showtime{ShowDate: windowed.ShowDate, StartTime: windowed.StartTime}
+
+	tests := []struct {
+		name     string
+		showtime *Showtime
+		now      time.Time
+		want     SalesState
+	}{
+		{"just before opening", windowed, open.Add(-time.Nanosecond), SalesNotOpen},
+		{"at opening", windowed, open, SalesOnSale},
+		{"just before closing", windowed, close.Add(-time.Nanosecond), SalesOnSale},
+		{"at closing", windowed, close, SalesClosed},
+		{"opening in UTC terms", windowed, time.Date(2026, 10, 9, 3, 0, 0, 0, time.UTC), SalesOnSale},
+		{"no window, long before", defaults, start.Add(-30 * 24 * time.Hour), SalesOnSale},
+		{"no window, just before start", defaults, start.Add(-time.Nanosecond), SalesOnSale},
+		{"no window, at start", defaults, start, SalesClosed},
+		{"no window, start read as UTC", defaults, time.Date(2026, 10, 16, 19, 59, 0, 0, time.UTC), SalesClosed},
+	}
+	for _, tt := range tests {
+		if got := tt.showtime.SalesStateAt(tt.now, saigon); got != tt.want {
+			t.Errorf("%s: SalesStateAt(%s) = %s, want %s", tt.name, tt.now.UTC(), got, tt.want)
+		}
+	}
+}
+
+func TestCinemaLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{"Asia/Ho_Chi_Minh", "Asia/Ho_Chi_Minh"},
+		{"", "UTC"},
+		{"Mars/Olympus_Mons", "UTC"},
+	}
+	for _, tt := range tests {
+		c := &Cinema{Timezone: tt.timezone}
+		if got := c.Location().String(); got != tt.want {
+			t.Errorf("Location(%q) = %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+}
+
+func TestPresaleCodeMatches(t *testing.T) {
+	hash := "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+	st := &Showtime{PresaleCodeHash: &hash}
+
+	if !st.PresaleCodeMatches(hash) {
+		t.Error("the configured code does not match")
+	}
+	if st.PresaleCodeMatches("") || st.PresaleCodeMatches(hash[:63]+"9") {
+		t.Error("a wrong code matches")
+	}
+	if (&Showtime{}).PresaleCodeMatches("") {
+		t.Error("an empty code matches a showtime without pre-sale")
+	}
+}
+
+func TestRelationAvailability(t *testing.T) {
+	deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name              string
+		movie             Movie
+		screen            Screen
+		movieUnavailable  bool
+		screenUnavailable bool
+	}{
+		{"both active", Movie{ID: uuid.New(), IsActive: true}, Screen{ID: uuid.New(), IsActive: true}, false, false},
+		{"movie deactivated", Movie{ID: uuid.New()}, Screen{ID: uuid.New(), IsActive: true}, true, false},
+		{"movie deleted", Movie{ID: uuid.New(), IsActive: true, DeletedAt: deleted}, Screen{ID: uuid.New(), IsActive: true}, true, false},
+		{"screen deleted", Movie{ID: uuid.New(), IsActive: true}, Screen{ID: uuid.New(), IsActive: true, DeletedAt: deleted}, false, true},
+		{"relations not loaded", Movie{}, Screen{}, false, false},
+	}
+	for _, tt := range tests {
+		st := &Showtime{Movie: tt.movie, Screen: tt.screen}
+		if got := st.MovieUnavailable(); got != tt.movieUnavailable {
+			t.Errorf("%s: MovieUnavailable = %v", tt.name, got)
+		}
+		if got := st.ScreenUnavailable(); got != tt.screenUnavailable {
+			t.Errorf("%s: ScreenUnavailable = %v", tt.name, got)
+		}
+	}
+}
+
+func TestShowtimeClashesWith(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	show := func(date time.Time, start, end string) *Showtime {
+		return &Showtime{ShowDate: date, StartTime: start, EndTime: end}
+	}
+	evening := show(day, "19:00", "21:00")
+
+	tests := []struct {
+		name  string
+		other *Showtime
+		want  bool
+	}{
+		{"overlapping", show(day, "20:00", "22:00"), true},
+		{"inside", show(day, "19:30", "20:30"), true},
+		{"starts within the gap", show(day, "21:10", "23:00"), true},
+		{"starts after the gap", show(day, "21:15", "23:00"), false},
+		{"ends within the gap", show(day, "16:30", "18:50"), true},
+		{"ends before the gap", show(day, "16:30", "18:45"), false},
+		{"another day", show(day.AddDate(0, 0, 1), "19:00", "21:00"), false},
+		// A late show from the day before runs past midnight into this one
+		{"late show from the day before", show(day.AddDate(0, 0, -1), "23:30", "01:30"), false},
+		{"TIME columns with seconds", show(day, "20:00:00", "22:00:00"), true},
+	}
+	for _, tt := range tests {
+		if got := evening.ClashesWith(tt.other, 15*time.Minute); got != tt.want {
+			t.Errorf("%s: ClashesWith = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.other.ClashesWith(evening, 15*time.Minute); got != tt.want {
+			t.Errorf("%s, reversed: ClashesWith = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A show past midnight clashes with an early show the next morning
+	late := show(day, "23:00", "01:30")
+	if !late.ClashesWith(show(day.AddDate(0, 0, 1), "01:00", "03:00"), 15*time.Minute) {
+		t.Error("a show past midnight does not clash with one at 01:00 the next day")
+	}
+	if got := late.EndsAt(time.UTC); !got.Equal(time.Date(2026, 10, 17, 1, 30, 0, 0, time.UTC)) {
+		t.Errorf("EndsAt = %s, want 01:30 the next day", got)
+	}
+}
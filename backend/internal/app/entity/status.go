@@ -0,0 +1,221 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// bookingTransitions lists the statuses each booking status may move to.
+// Every BookingStatus must have an entry, terminal ones with none.
+var bookingTransitions = map[BookingStatus][]BookingStatus{
+	BookingPending:   {BookingConfirmed, BookingCancelled, BookingExpired},
+	BookingConfirmed: {BookingCompleted, BookingCancelled, BookingRefunded},
+	BookingCancelled: {BookingRefunded},
+	BookingCompleted: {},
+	BookingRefunded:  {},
+	BookingExpired:   {},
+}
+
+// paymentTransitions lists the statuses each payment status may move to.
+// A failed payment can still succeed when the gateway retries it.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentPending:   {PaymentPaid, PaymentFailed, PaymentCancelled},
+	PaymentFailed:    {PaymentPaid, PaymentCancelled},
+	PaymentPaid:      {PaymentRefunded},
+	PaymentRefunded:  {},
+	PaymentCancelled: {},
+}
+
+// BookingStatuses returns every booking status
+func BookingStatuses() []BookingStatus {
+	return []BookingStatus{BookingPending, BookingConfirmed, BookingCompleted, BookingCancelled, BookingRefunded, BookingExpired}
+}
+
+// PaymentStatuses returns every payment status
+func PaymentStatuses() []PaymentStatus {
+	return []PaymentStatus{PaymentPending, PaymentPaid, PaymentFailed, PaymentRefunded, PaymentCancelled}
+}
+
+// Valid returns true if s is a known booking status
+func (s BookingStatus) Valid() bool {
+	_, ok := bookingTransitions[s]
+	return ok
+}
+
+// CanTransitionTo returns true if a booking may move from s to next.
+// Staying in the same status is always allowed.
+func (s BookingStatus) CanTransitionTo(next BookingStatus) bool {
+	if s == next {
+		return s.Valid()
+	}
+	for _, allowed := range bookingTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// BookingStatusesBefore returns the statuses a booking may move to next
+// from, next itself included
+func BookingStatusesBefore(next BookingStatus) []BookingStatus {
+	var from []BookingStatus
+	for _, s := range BookingStatuses() {
+		if s.CanTransitionTo(next) {
+			from = append(from, s)
+		}
+	}
+	return from
+}
+
+// Valid returns true if s is a known payment status
+func (s PaymentStatus) Valid() bool {
+	_, ok := paymentTransitions[s]
+	return ok
+}
+
+// CanTransitionTo returns true if a payment may move from s to next.
+// Staying in the same status is always allowed.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	if s == next {
+		return s.Valid()
+	}
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentStatusesBefore returns the statuses a payment may move to next
+// from, next itself included
+func PaymentStatusesBefore(next PaymentStatus) []PaymentStatus {
+	var from []PaymentStatus
+	for _, s := range PaymentStatuses() {
+		if s.CanTransitionTo(next) {
+			from = append(from, s)
+		}
+	}
+	return from
+}
+
+// ParseBookingStatus parses a booking status from user input
+func ParseBookingStatus(value string) (BookingStatus, error) {
+	s := BookingStatus(strings.ToUpper(strings.TrimSpace(value)))
+	if !s.Valid() {
+		return "", &StatusError{Field: "booking_status", To: value, Allowed: statusNames(BookingStatuses())}
+	}
+	return s, nil
+}
+
+// ParsePaymentStatus parses a payment status from user input
+func ParsePaymentStatus(value string) (PaymentStatus, error) {
+	s := PaymentStatus(strings.ToUpper(strings.TrimSpace(value)))
+	if !s.Valid() {
+		return "", &StatusError{Field: "payment_status", To: value, Allowed: statusNames(PaymentStatuses())}
+	}
+	return s, nil
+}
+
+// StatusError reports an unknown status value or a status change that is
+// not allowed. From is empty for unknown values.
+type StatusError struct {
+	Field   string
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *StatusError) Error() string {
+	if e.From == "" {
+		return fmt.Sprintf("invalid %s %q: must be one of %s", e.Field, e.To, strings.Join(e.Allowed, ", "))
+	}
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("%s cannot change from %s", e.Field, e.From)
+	}
+	return fmt.Sprintf("%s cannot change from %s to %s: must be one of %s", e.Field, e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+func statusNames[S ~string](statuses []S) []string {
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// CheckBookingTransition returns a *StatusError unless to is a known
+// booking status reachable from from. An empty from only checks to.
+func CheckBookingTransition(from, to BookingStatus) error {
+	if !to.Valid() {
+		return &StatusError{Field: "booking_status", To: string(to), Allowed: statusNames(BookingStatuses())}
+	}
+	if from != "" && !from.CanTransitionTo(to) {
+		return &StatusError{Field: "booking_status", From: string(from), To: string(to), Allowed: statusNames(bookingTransitions[from])}
+	}
+	return nil
+}
+
+// CheckPaymentTransition returns a *StatusError unless to is a known
+// payment status reachable from from. An empty from only checks to.
+func CheckPaymentTransition(from, to PaymentStatus) error {
+	if !to.Valid() {
+		return &StatusError{Field: "payment_status", To: string(to), Allowed: statusNames(PaymentStatuses())}
+	}
+	if from != "" && !from.CanTransitionTo(to) {
+		return &StatusError{Field: "payment_status", From: string(from), To: string(to), Allowed: statusNames(paymentTransitions[from])}
+	}
+	return nil
+}
+
+// AfterFind remembers the statuses as loaded so BeforeSave can check the
+// transition
+func (b *Booking) AfterFind(tx *gorm.DB) error {
+	b.loadedBookingStatus = b.BookingStatus
+	b.loadedPaymentStatus = b.PaymentStatus
+	return nil
+}
+
+// BeforeSave rejects unknown statuses and, for bookings read from the
+// database, status changes outside the transition table
+func (b *Booking) BeforeSave(tx *gorm.DB) error {
+	// Empty statuses are left to the column defaults
+	if b.BookingStatus != "" {
+		if err := CheckBookingTransition(b.loadedBookingStatus, b.BookingStatus); err != nil {
+			return err
+		}
+	}
+	if b.PaymentStatus != "" {
+		return CheckPaymentTransition(b.loadedPaymentStatus, b.PaymentStatus)
+	}
+	return nil
+}
+
+// AfterSave makes the saved statuses the baseline for the next save
+func (b *Booking) AfterSave(tx *gorm.DB) error {
+	return b.AfterFind(tx)
+}
+
+// AfterFind remembers the status as loaded so BeforeSave can check the
+// transition
+func (p *Payment) AfterFind(tx *gorm.DB) error {
+	p.loadedStatus = p.PaymentStatus
+	return nil
+}
+
+// BeforeSave rejects unknown statuses and, for payments read from the
+// database, status changes outside the transition table
+func (p *Payment) BeforeSave(tx *gorm.DB) error {
+	if p.PaymentStatus == "" {
+		return nil
+	}
+	return CheckPaymentTransition(p.loadedStatus, p.PaymentStatus)
+}
+
+// AfterSave makes the saved status the baseline for the next save
+func (p *Payment) AfterSave(tx *gorm.DB) error {
+	return p.AfterFind(tx)
+}
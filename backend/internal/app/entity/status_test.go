@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestBookingStatusTransitions(t *testing.T) {
+	allowed := map[BookingStatus][]BookingStatus{
+		BookingPending:   {BookingPending, BookingConfirmed, BookingCancelled, BookingExpired},
+		BookingConfirmed: {BookingConfirmed, BookingCompleted, BookingCancelled, BookingRefunded},
+		BookingCompleted: {BookingCompleted},
+		BookingCancelled: {BookingCancelled, BookingRefunded},
+		BookingRefunded:  {BookingRefunded},
+		BookingExpired:   {BookingExpired},
+	}
+
+	for _, from := range BookingStatuses() {
+		for _, to := range BookingStatuses() {
+			want := slices.Contains(allowed[from], to)
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+
+			err := CheckBookingTransition(from, to)
+			if want != (err == nil) {
+				t.Errorf("%s -> %s: CheckBookingTransition = %v, want allowed %v", from, to, err, want)
+			}
+			var statusErr *StatusError
+			if err != nil && (!errors.As(err, &statusErr) || statusErr.From != string(from)) {
+				t.Errorf("%s -> %s: error %v does not name the status changed from", from, to, err)
+			}
+		}
+	}
+}
+
+func TestPaymentStatusTransitions(t *testing.T) {
+	allowed := map[PaymentStatus][]PaymentStatus{
+		PaymentPending:   {PaymentPending, PaymentPaid, PaymentFailed, PaymentCancelled},
+		PaymentPaid:      {PaymentPaid, PaymentRefunded},
+		PaymentFailed:    {PaymentFailed, PaymentPaid, PaymentCancelled},
+		PaymentRefunded:  {PaymentRefunded},
+		PaymentCancelled: {PaymentCancelled},
+	}
+
+	for _, from := range PaymentStatuses() {
+		for _, to := range PaymentStatuses() {
+			want := slices.Contains(allowed[from], to)
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+			if err := CheckPaymentTransition(from, to); want != (err == nil) {
+				t.Errorf("%s -> %s: CheckPaymentTransition = %v, want allowed %v", from, to, err, want)
+			}
+		}
+	}
+}
+
+func TestUnknownStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"unknown booking target", CheckBookingTransition(BookingPending, "SHIPPED")},
+		{"unknown booking target from nothing", CheckBookingTransition("", "SHIPPED")},
+		{"unknown payment target", CheckPaymentTransition(PaymentPending, "DISPUTED")},
+		{"unknown payment source", CheckPaymentTransition("DISPUTED", PaymentPaid)},
+	}
+	for _, tt := range tests {
+		var statusErr *StatusError
+		if !errors.As(tt.err, &statusErr) {
+			t.Errorf("%s: got %v, want a *StatusError", tt.name, tt.err)
+		}
+	}
+
+	if BookingStatus("SHIPPED").CanTransitionTo("SHIPPED") {
+		t.Error("an unknown booking status may stay as it is")
+	}
+	if err := CheckBookingTransition("", BookingConfirmed); err != nil {
+		t.Errorf("new booking as CONFIRMED: %v", err)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if s, err := ParseBookingStatus(" confirmed "); err != nil || s != BookingConfirmed {
+		t.Errorf("ParseBookingStatus = %q, %v", s, err)
+	}
+	if _, err := ParseBookingStatus("done"); err == nil {
+		t.Error("ParseBookingStatus accepted an unknown status")
+	}
+	if s, err := ParsePaymentStatus("Paid"); err != nil || s != PaymentPaid {
+		t.Errorf("ParsePaymentStatus = %q, %v", s, err)
+	}
+}
+
+// TestEveryStatusConstantIsListed fails when a status constant is added
+// without an entry in its transition table and status list
+func TestEveryStatusConstantIsListed(t *testing.T) {
+	constants := statusConstants(t, "booking.go")
+
+	booking := constants["BookingStatus"]
+	if len(booking) == 0 {
+		t.Fatal("no BookingStatus constants found")
+	}
+	for _, value := range booking {
+		s := BookingStatus(value)
+		if !s.Valid() {
+			t.Errorf("booking status %s has no transitions entry", s)
+		}
+		if !slices.Contains(BookingStatuses(), s) {
+			t.Errorf("booking status %s is missing from BookingStatuses", s)
+		}
+	}
+	if len(bookingTransitions) != len(booking) {
+		t.Errorf("bookingTransitions has %d statuses, %d are declared", len(bookingTransitions), len(booking))
+	}
+
+	payment := constants["PaymentStatus"]
+	if len(payment) == 0 {
+		t.Fatal("no PaymentStatus constants found")
+	}
+	for _, value := range payment {
+		s := PaymentStatus(value)
+		if !s.Valid() {
+			t.Errorf("payment status %s has no transitions entry", s)
+		}
+		if !slices.Contains(PaymentStatuses(), s) {
+			t.Errorf("payment status %s is missing from PaymentStatuses", s)
+		}
+	}
+	if len(paymentTransitions) != len(payment) {
+		t.Errorf("paymentTransitions has %d statuses, %d are declared", len(paymentTransitions), len(payment))
+	}
+}
+
+// statusConstants returns the string constants declared in file, keyed on
+// their type name
+func statusConstants(t *testing.T, file string) map[string][]string {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatalf("parse %s: %v", file, err)
+	}
+
+	constants := make(map[string][]string)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			typ, ok := value.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			for _, v := range value.Values {
+				lit, ok := v.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("unquote %s: %v", lit.Value, err)
+				}
+				constants[typ.Name] = append(constants[typ.Name], s)
+			}
+		}
+	}
+	return constants
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckInResult is the outcome of scanning a ticket at the door
+type CheckInResult string
+
+const (
+	CheckInAccepted         CheckInResult = "ACCEPTED"
+	CheckInInvalidTicket    CheckInResult = "INVALID_TICKET"     // the signature does not match
+	CheckInNotFound         CheckInResult = "NOT_FOUND"          // no booking has the reference
+	CheckInWrongShowtime    CheckInResult = "WRONG_SHOWTIME"     // the booking is for another show
+	CheckInForbidden        CheckInResult = "FORBIDDEN"          // the staff user is not assigned to the cinema
+	CheckInAlreadyCheckedIn CheckInResult = "ALREADY_CHECKED_IN" // the ticket was scanned before
+	CheckInNotConfirmed     CheckInResult = "NOT_CONFIRMED"      // the booking is not confirmed and paid
+	CheckInWrongDay         CheckInResult = "WRONG_DAY"          // the show is not today
+	CheckInOutsideWindow    CheckInResult = "OUTSIDE_WINDOW"     // too early or too late for the show
+	CheckInError            CheckInResult = "ERROR"
+)
+
+// TicketCheckIn records one scan of a ticket, whether or not it let the
+// customer in
+type TicketCheckIn struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookingReference string        `gorm:"type:varchar(128);not null" json:"booking_reference"` // as scanned or typed in
+	BookingID        *uuid.UUID    `gorm:"type:uuid" json:"booking_id,omitempty"`               // nil when no booking matched
+	StaffUserID      uuid.UUID     `gorm:"type:uuid;not null" json:"staff_user_id"`
+	Result           CheckInResult `gorm:"type:varchar(30);not null" json:"result"`
+	Message          string        `gorm:"type:text" json:"message,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+// TableName sets the table name for TicketCheckIn
+func (TicketCheckIn) TableName() string {
+	return "ticket_check_ins"
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUIDList is a list of UUIDs stored as a JSONB array
+type UUIDList []uuid.UUID
+
+// Value implements driver.Valuer
+func (l UUIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *UUIDList) Scan(value interface{}) error {
+	if value == nil {
+		*l = UUIDList{}
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for UUIDList: %T", value)
+	}
+
+	return json.Unmarshal(data, l)
+}
+
+// Contains returns true if the list contains the given ID
+func (l UUIDList) Contains(id uuid.UUID) bool {
+	for _, item := range l {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
@@ -19,20 +19,27 @@ const (
 
 // User represents a user in the system
 type User struct {
-	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Email         string         `gorm:"uniqueIndex;not null" json:"email"`
-	PasswordHash  string         `gorm:"not null" json:"-"`
-	FirstName     string         `gorm:"not null" json:"first_name"`
-	LastName      string         `gorm:"not null" json:"last_name"`
-	Phone         *string        `json:"phone"`
-	AvatarURL     *string        `json:"avatar_url"`
-	Role          Role           `gorm:"type:varchar(20);default:'CUSTOMER'" json:"role"`
-	EmailVerified bool           `gorm:"default:false" json:"email_verified"`
-	IsActive      bool           `gorm:"default:true" json:"is_active"`
-	LastLoginAt   *time.Time     `json:"last_login_at"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email         string    `gorm:"uniqueIndex;not null" json:"email"`
+	PasswordHash  string    `gorm:"not null" json:"-"`
+	FirstName     string    `gorm:"not null" json:"first_name"`
+	LastName      string    `gorm:"not null" json:"last_name"`
+	Phone         *string   `json:"phone"`
+	AvatarURL     *string   `json:"avatar_url"`
+	Role          Role      `gorm:"type:varchar(20);default:'CUSTOMER'" json:"role"`
+	EmailVerified bool      `gorm:"default:false" json:"email_verified"`
+	IsActive      bool      `gorm:"default:true" json:"is_active"`
+	// GoogleID is the Google account the user signs in with, if any
+	GoogleID *string `gorm:"uniqueIndex" json:"-"`
+	// AvatarKey is the storage key of an uploaded avatar, nil when the
+	// avatar is hosted elsewhere
+	AvatarKey *string `json:"-"`
+	// HoldRecoveryEmails opts the user into emails about expired seat holds
+	HoldRecoveryEmails bool           `gorm:"not null;default:true" json:"hold_recovery_emails"`
+	LastLoginAt        *time.Time     `json:"last_login_at"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName sets the table name for User
@@ -60,7 +67,13 @@ type RefreshToken struct {
 	RevokedAt *time.Time `json:"revoked_at,omitempty"`
 	UserAgent *string    `json:"user_agent,omitempty"`
 	IPAddress *string    `json:"ip_address,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
+	// The token issued when this one was rotated; set only on rotation
+	ReplacedByID *uuid.UUID `gorm:"type:uuid;column:replaced_by" json:"-"`
+	// FamilyID is shared by every token rotated from the same login
+	FamilyID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	// ParentTokenHash is the hash of the token this one replaced
+	ParentTokenHash *string   `json:"-"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // TableName sets the table name for RefreshToken
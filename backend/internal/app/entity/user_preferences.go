@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserPreferences holds what a user likes to watch and how they want to
+// hear from us. Users who never saved preferences get the defaults.
+type UserPreferences struct {
+	UserID            uuid.UUID      `gorm:"type:uuid;primary_key" json:"user_id"`
+	FavoriteGenres    pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"favorite_genres"`
+	FavoriteCinemaIDs pq.StringArray `gorm:"type:uuid[];not null;default:'{}'" json:"favorite_cinema_ids"`
+	PreferredFormats  pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"preferred_formats"`
+	NotificationEmail bool           `gorm:"not null" json:"notification_email"`
+	NotificationPush  bool           `gorm:"not null" json:"notification_push"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// TableName sets the table name for UserPreferences
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
+
+// DefaultUserPreferences returns the preferences of a user who has not
+// saved any: no favorites, and every notification on
+func DefaultUserPreferences(userID uuid.UUID) *UserPreferences {
+	return &UserPreferences{
+		UserID:            userID,
+		FavoriteGenres:    pq.StringArray{},
+		FavoriteCinemaIDs: pq.StringArray{},
+		PreferredFormats:  pq.StringArray{},
+		NotificationEmail: true,
+		NotificationPush:  true,
+	}
+}
+
+// CinemaIDs returns the favorite cinemas, skipping malformed IDs
+func (p *UserPreferences) CinemaIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(p.FavoriteCinemaIDs))
+	for _, raw := range p.FavoriteCinemaIDs {
+		if id, err := uuid.Parse(raw); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// HasFavorites returns true if the user picked any genre, cinema or format
+func (p *UserPreferences) HasFavorites() bool {
+	return len(p.FavoriteGenres) > 0 || len(p.FavoriteCinemaIDs) > 0 || len(p.PreferredFormats) > 0
+}
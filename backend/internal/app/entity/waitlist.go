@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStatus represents where a waitlist entry is in the queue
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting  WaitlistStatus = "WAITING"
+	WaitlistNotified WaitlistStatus = "NOTIFIED" // offered seats that opened up
+	WaitlistExpired  WaitlistStatus = "EXPIRED"  // the showtime went ahead or was cancelled first
+)
+
+// WaitlistEntry is a customer waiting for seats at a sold-out showtime.
+// Entries are served in Position order as cancellations release seats.
+type WaitlistEntry struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ShowtimeID uuid.UUID      `gorm:"type:uuid;not null" json:"showtime_id"`
+	UserID     *uuid.UUID     `gorm:"type:uuid" json:"user_id,omitempty"`
+	GuestEmail string         `json:"guest_email,omitempty"` // for guests without an account
+	SeatCount  int            `gorm:"not null" json:"seat_count"`
+	Position   int64          `gorm:"not null" json:"position"` // order of joining within the showtime
+	Status     WaitlistStatus `gorm:"type:varchar(20);not null;default:'WAITING'" json:"status"`
+	HoldID     *string        `json:"hold_id,omitempty"` // hold made for the customer when notified
+	NotifiedAt *time.Time     `json:"notified_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// TableName sets the table name for WaitlistEntry
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookStatus represents the processing state of a received webhook
+type WebhookStatus string
+
+const (
+	// WebhookReceived means the event is stored but has not been processed;
+	// a crash during processing leaves it here
+	WebhookReceived WebhookStatus = "RECEIVED"
+	// WebhookProcessed means the event was applied
+	WebhookProcessed WebhookStatus = "PROCESSED"
+	// WebhookFailed means processing returned an error; the event can be replayed
+	WebhookFailed WebhookStatus = "FAILED"
+	// WebhookIgnored means the event type needs no action
+	WebhookIgnored WebhookStatus = "IGNORED"
+	// WebhookRejected means the signature did not verify; the payload is never applied
+	WebhookRejected WebhookStatus = "REJECTED"
+)
+
+// IsReplayable returns true if processing the event again may change state
+func (s WebhookStatus) IsReplayable() bool {
+	return s == WebhookReceived || s == WebhookFailed
+}
+
+// WebhookEvent is a payment gateway callback as received, kept in the
+// webhook inbox so failed events can be inspected and replayed
+type WebhookEvent struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Provider         string        `gorm:"not null" json:"provider"`
+	EventID          string        `gorm:"not null" json:"event_id"` // gateway event ID, unique per provider
+	EventType        string        `gorm:"not null" json:"event_type"`
+	Payload          string        `gorm:"type:jsonb;not null" json:"payload"`
+	SignatureValid   bool          `gorm:"not null" json:"signature_valid"`
+	Status           WebhookStatus `gorm:"type:varchar(20);not null;default:'RECEIVED'" json:"status"`
+	Attempts         int           `gorm:"not null;default:0" json:"attempts"`
+	LastError        *string       `gorm:"type:text" json:"last_error,omitempty"`
+	PaymentReference *string       `json:"payment_reference,omitempty"`
+	BookingID        *uuid.UUID    `gorm:"type:uuid" json:"booking_id,omitempty"`
+	PaymentID        *uuid.UUID    `gorm:"type:uuid" json:"payment_id,omitempty"`
+	ReceivedAt       time.Time     `gorm:"not null" json:"received_at"`
+	ProcessedAt      *time.Time    `json:"processed_at,omitempty"`
+	AlertedAt        *time.Time    `json:"alerted_at,omitempty"` // set when the stale-event alert went out
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// TableName sets the table name for WebhookEvent
+func (WebhookEvent) TableName() string {
+	return "webhook_inbox"
+}
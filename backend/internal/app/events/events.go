@@ -0,0 +1,175 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/pkg/eventbus"
+
+	"github.com/google/uuid"
+)
+
+// Event names
+const (
+	BookingCreatedEvent         = "booking.created"
+	BookingConfirmedEvent       = "booking.confirmed"
+	BookingCancelledEvent       = "booking.cancelled"
+	ShowtimeCancelledEvent      = "showtime.cancelled"
+	GroupCheckoutCompletedEvent = "group_checkout.completed"
+	HoldExpiredEvent            = "hold.expired"
+	HoldRecoverySentEvent       = "hold.recovery_sent"
+	HoldRecoveryReheldEvent     = "hold.recovery_reheld"
+	HoldRecoveryConvertedEvent  = "hold.recovery_converted"
+)
+
+// BookingCreated is published when a booking is created, pending payment
+// unless it was paid for already
+type BookingCreated struct {
+	BookingID        uuid.UUID
+	BookingReference string
+	UserID           *uuid.UUID
+	ShowtimeID       uuid.UUID
+	NumTickets       int
+	FinalAmount      float64
+	BookedAt         time.Time
+}
+
+func (BookingCreated) EventName() string     { return BookingCreatedEvent }
+func (e BookingCreated) AggregateID() string { return e.BookingID.String() }
+
+// BookingConfirmed is published once a booking is paid and confirmed
+type BookingConfirmed struct {
+	BookingID        uuid.UUID
+	BookingReference string
+	UserID           *uuid.UUID
+	ShowtimeID       uuid.UUID
+	NumTickets       int
+	FinalAmount      float64
+	ConfirmedAt      time.Time
+}
+
+func (BookingConfirmed) EventName() string     { return BookingConfirmedEvent }
+func (e BookingConfirmed) AggregateID() string { return e.BookingID.String() }
+
+// BookingCancelled is published when a booking is cancelled
+type BookingCancelled struct {
+	BookingID        uuid.UUID
+	BookingReference string
+	UserID           *uuid.UUID
+	ShowtimeID       uuid.UUID
+	SeatIDs          []uuid.UUID // seats released back to the showtime
+	RefundAmount     float64     // refunded under the cancellation policy, 0 for unpaid bookings
+	Reason           string
+	CancelledAt      time.Time
+}
+
+func (BookingCancelled) EventName() string     { return BookingCancelledEvent }
+func (e BookingCancelled) AggregateID() string { return e.BookingID.String() }
+
+// ShowtimeCancelled is published when a showtime is cancelled
+type ShowtimeCancelled struct {
+	ShowtimeID  uuid.UUID
+	MovieID     uuid.UUID
+	CinemaID    uuid.UUID
+	CancelledAt time.Time
+}
+
+func (ShowtimeCancelled) EventName() string     { return ShowtimeCancelledEvent }
+func (e ShowtimeCancelled) AggregateID() string { return e.ShowtimeID.String() }
+
+// GroupCheckoutCompleted is published when every share of a split payment
+// is settled and the group booking has been created
+type GroupCheckoutCompleted struct {
+	GroupCheckoutID  uuid.UUID
+	GroupReference   string
+	BookingID        uuid.UUID
+	BookingReference string
+	NumTickets       int
+	OrganizerEmail   string
+	PayerEmails      []string // paying invitees other than the organizer
+}
+
+func (GroupCheckoutCompleted) EventName() string { return GroupCheckoutCompletedEvent }
+
+// AggregateID keys on the booking so group events order with booking events
+func (e GroupCheckoutCompleted) AggregateID() string { return e.BookingID.String() }
+
+// HoldExpired is published when a seat hold expired without being converted
+type HoldExpired struct {
+	HoldID     string
+	UserID     uuid.UUID
+	ShowtimeID uuid.UUID
+	NumSeats   int
+	ExpiredAt  time.Time
+}
+
+func (HoldExpired) EventName() string     { return HoldExpiredEvent }
+func (e HoldExpired) AggregateID() string { return e.HoldID }
+
+// HoldRecoverySent is published when a "your seats were released" email is
+// queued for an expired hold
+type HoldRecoverySent struct {
+	RecoveryID    uuid.UUID
+	HoldID        string
+	UserID        uuid.UUID
+	ShowtimeID    uuid.UUID
+	ReholdOffered bool
+	SentAt        time.Time
+}
+
+func (HoldRecoverySent) EventName() string     { return HoldRecoverySentEvent }
+func (e HoldRecoverySent) AggregateID() string { return e.RecoveryID.String() }
+
+// HoldRecoveryReheld is published when a user follows the re-hold link of a
+// recovery email and gets seats again
+type HoldRecoveryReheld struct {
+	RecoveryID uuid.UUID
+	HoldID     string // the new hold
+	UserID     uuid.UUID
+	ShowtimeID uuid.UUID
+	ReheldAt   time.Time
+}
+
+func (HoldRecoveryReheld) EventName() string     { return HoldRecoveryReheldEvent }
+func (e HoldRecoveryReheld) AggregateID() string { return e.RecoveryID.String() }
+
+// HoldRecoveryConverted is published when a user who received a recovery
+// email confirms a booking for the showtime
+type HoldRecoveryConverted struct {
+	RecoveryID  uuid.UUID
+	BookingID   uuid.UUID
+	UserID      uuid.UUID
+	ShowtimeID  uuid.UUID
+	Reheld      bool // the booking followed a re-hold from the email
+	ConvertedAt time.Time
+}
+
+func (HoldRecoveryConverted) EventName() string     { return HoldRecoveryConvertedEvent }
+func (e HoldRecoveryConverted) AggregateID() string { return e.RecoveryID.String() }
+
+// outboxEvents decodes the events published through the outbox, by name
+var outboxEvents = map[string]func(payload []byte) (eventbus.Event, error){
+	BookingCreatedEvent:         decode[BookingCreated],
+	BookingConfirmedEvent:       decode[BookingConfirmed],
+	BookingCancelledEvent:       decode[BookingCancelled],
+	GroupCheckoutCompletedEvent: decode[GroupCheckoutCompleted],
+}
+
+// Decode rebuilds an event stored in the outbox from its name and JSON
+// payload
+func Decode(name string, payload []byte) (eventbus.Event, error) {
+	decodeFn, ok := outboxEvents[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decodeFn(payload)
+}
+
+func decode[E eventbus.Event](payload []byte) (eventbus.Event, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,62 @@
+package groupbooking
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/app/booking"
+
+	"github.com/google/uuid"
+)
+
+// CreateGroupBookingRequest represents a request to open a group booking
+type CreateGroupBookingRequest struct {
+	ShowtimeID      uuid.UUID `json:"showtime_id" validate:"required" swaggertype:"string" format:"uuid"`
+	MaxParticipants int       `json:"max_participants" validate:"required,min=2,max=20"`
+	AccessCode      string    `json:"access_code,omitempty"` // required for private showtimes
+}
+
+// JoinGroupBookingRequest represents a participant joining with their seats
+type JoinGroupBookingRequest struct {
+	SeatIDs     []uuid.UUID `json:"seat_ids" validate:"required,min=1,dive,required" swaggertype:"array,string" format:"uuid"`
+	PresaleCode string      `json:"presale_code,omitempty"` // unlocks seats before sales open
+	AccessCode  string      `json:"access_code,omitempty"`  // required for private showtimes
+}
+
+// ParticipantResponse represents a participant in responses
+type ParticipantResponse struct {
+	SeatIDs   []uuid.UUID `json:"seat_ids" swaggertype:"array,string" format:"uuid"`
+	Organizer bool        `json:"organizer"`
+	JoinedAt  time.Time   `json:"joined_at"`
+}
+
+// GroupBookingResponse represents a group booking in responses
+type GroupBookingResponse struct {
+	ID                  uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	ShowtimeID          uuid.UUID `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	MaxParticipants     int       `json:"max_participants"`
+	CurrentParticipants int       `json:"current_participants"`
+	Status              string    `json:"status"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	// ShareToken and ShareURL are only returned to the organizer when the
+	// group is created; the token is not stored
+	ShareToken   string                `json:"share_token,omitempty"`
+	ShareURL     string                `json:"share_url,omitempty"`
+	SeatIDs      []uuid.UUID           `json:"seat_ids" swaggertype:"array,string" format:"uuid"` // every participant's seats
+	Participants []ParticipantResponse `json:"participants"`
+	BookingID    *uuid.UUID            `json:"booking_id,omitempty" swaggertype:"string" format:"uuid"`
+	FinalizedAt  *time.Time            `json:"finalized_at,omitempty"`
+}
+
+// JoinGroupBookingResponse is the group after joining and the
+// participant's hold on their seats
+type JoinGroupBookingResponse struct {
+	Group *GroupBookingResponse `json:"group"`
+	Hold  *booking.HoldResponse `json:"hold"`
+}
+
+// FinalizeGroupBookingResponse is the finalized group and the booking
+// covering its seats, awaiting the organizer's payment
+type FinalizeGroupBookingResponse struct {
+	Group   *GroupBookingResponse           `json:"group"`
+	Booking *booking.ConfirmBookingResponse `json:"booking"`
+}
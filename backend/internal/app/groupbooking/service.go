@@ -0,0 +1,362 @@
+package groupbooking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service runs group bookings: the organizer opens a session for a
+// showtime and shares its link, each participant joins by holding their own
+// seats, and the pooled seats are booked as one booking for the organizer
+// once everyone has joined or the organizer finalizes early. Joining and
+// finalizing take the group's lock, so two requests never change the same
+// group at once.
+type Service struct {
+	groupRepo    repository.GroupBookingRepository
+	locks        repository.GroupBookingLock
+	showtimeRepo repository.ShowtimeRepository
+	holdRepo     repository.SeatHoldRepository
+	userRepo     repository.UserRepository
+	bookings     *booking.Service
+	dispatcher   *async.Dispatcher
+	cfg          config.BookingConfig
+	logger       *logger.Logger
+	frontendURL  string
+}
+
+// NewService creates a new group booking service
+func NewService(
+	groupRepo repository.GroupBookingRepository,
+	locks repository.GroupBookingLock,
+	showtimeRepo repository.ShowtimeRepository,
+	holdRepo repository.SeatHoldRepository,
+	userRepo repository.UserRepository,
+	bookings *booking.Service,
+	dispatcher *async.Dispatcher,
+	cfg config.BookingConfig,
+	logger *logger.Logger,
+	frontendURL string,
+) *Service {
+	return &Service{
+		groupRepo:    groupRepo,
+		locks:        locks,
+		showtimeRepo: showtimeRepo,
+		holdRepo:     holdRepo,
+		userRepo:     userRepo,
+		bookings:     bookings,
+		dispatcher:   dispatcher,
+		cfg:          cfg,
+		logger:       logger,
+		frontendURL:  frontendURL,
+	}
+}
+
+// Create opens a group booking for a showtime. The group stays open for
+// one hold_ttl, so the holds of everyone who joined are still live when it
+// is finalized.
+func (s *Service) Create(ctx context.Context, organizerID uuid.UUID, req CreateGroupBookingRequest) (*GroupBookingResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, req.ShowtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !showtime.CanAccess(hashCode(req.AccessCode)) {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	if showtime.Status != entity.ShowtimeScheduled || showtime.MovieUnavailable() || showtime.ScreenUnavailable() {
+		return nil, apperrors.ErrBadRequest("showtime is not open for booking")
+	}
+	if showtime.IsFull() {
+		return nil, apperrors.New(apperrors.CodeShowtimeFull, "showtime is sold out")
+	}
+
+	token, err := authinfra.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("failed to generate share token", zap.Error(err))
+		return nil, apperrors.ErrInternal("failed to generate token")
+	}
+
+	group := &entity.GroupBooking{
+		OrganizerUserID: organizerID,
+		ShowtimeID:      showtime.ID,
+		MaxParticipants: req.MaxParticipants,
+		ShareTokenHash:  authinfra.HashToken(token),
+		ExpiresAt:       time.Now().Add(s.cfg.HoldTTL),
+		Status:          entity.GroupBookingOpen,
+	}
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		log.Error("failed to create group booking", zap.Error(err))
+		return nil, err
+	}
+
+	log.Info("group booking created",
+		zap.String("group_booking_id", group.ID.String()),
+		zap.String("showtime_id", showtime.ID.String()),
+		zap.Int("max_participants", group.MaxParticipants),
+	)
+
+	res := toGroupBookingResponse(group, time.Now())
+	res.ShareToken = token
+	res.ShareURL = s.shareLink(token)
+	return res, nil
+}
+
+// Get returns the group behind a share link
+func (s *Service) Get(ctx context.Context, token string) (*GroupBookingResponse, error) {
+	group, err := s.groupRepo.GetByTokenHash(ctx, authinfra.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	return toGroupBookingResponse(group, time.Now()), nil
+}
+
+// Join adds the user to the group with the seats they picked. The seats are
+// held for the user like any other hold, but can only be booked with the
+// group. The last participant to join finalizes the group.
+func (s *Service) Join(ctx context.Context, userID uuid.UUID, token string, req JoinGroupBookingRequest) (*JoinGroupBookingResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	release, err := s.locks.Acquire(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	group, err := s.openGroup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if group.IsFull() {
+		return nil, apperrors.ErrConflict("group booking is full")
+	}
+	if _, joined := group.Participant(userID); joined {
+		return nil, apperrors.ErrConflict("you have already joined this group booking")
+	}
+
+	hold, err := s.bookings.HoldSeatsForGroup(ctx, userID, group.ID, booking.HoldSeatsRequest{
+		ShowtimeID:  group.ShowtimeID,
+		SeatIDs:     req.SeatIDs,
+		PresaleCode: req.PresaleCode,
+		AccessCode:  req.AccessCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	participant := entity.GroupBookingParticipant{
+		GroupBookingID: group.ID,
+		UserID:         userID,
+		HoldID:         hold.ID,
+		SeatIDs:        entity.UUIDList(hold.SeatIDs()),
+		JoinedAt:       time.Now(),
+	}
+	if err := s.groupRepo.AddParticipant(ctx, &participant); err != nil {
+		s.bookings.ReleaseGroupHold(ctx, hold)
+		return nil, err
+	}
+	group.Participants = append(group.Participants, participant)
+	group.CurrentParticipants++
+
+	log.Info("joined group booking",
+		zap.String("group_booking_id", group.ID.String()),
+		zap.String("hold_id", hold.ID),
+		zap.Int("participants", group.CurrentParticipants),
+	)
+
+	if group.IsFull() {
+		// The participant has joined either way; if booking fails the
+		// organizer can still finalize while the group is open
+		if res, err := s.finalize(ctx, group); err != nil {
+			log.Warn("failed to finalize full group booking", zap.String("group_booking_id", group.ID.String()), zap.Error(err))
+		} else {
+			s.notifyOrganizerFinalized(ctx, group, res)
+		}
+	}
+
+	return &JoinGroupBookingResponse{
+		Group: toGroupBookingResponse(group, time.Now()),
+		Hold:  booking.ToHoldResponse(hold),
+	}, nil
+}
+
+// Finalize books the seats of everyone who has joined so far as one
+// booking for the organizer, who pays for it through the returned checkout
+func (s *Service) Finalize(ctx context.Context, organizerID uuid.UUID, token string) (*FinalizeGroupBookingResponse, error) {
+	release, err := s.locks.Acquire(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	group, err := s.openGroup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if group.OrganizerUserID != organizerID {
+		return nil, apperrors.ErrForbidden("only the organizer can finalize the group booking")
+	}
+
+	res, err := s.finalize(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	return &FinalizeGroupBookingResponse{
+		Group:   toGroupBookingResponse(group, time.Now()),
+		Booking: res,
+	}, nil
+}
+
+// openGroup loads a group that can still be joined or finalized. A group
+// found past its expiry is marked expired on the way.
+func (s *Service) openGroup(ctx context.Context, token string) (*entity.GroupBooking, error) {
+	group, err := s.groupRepo.GetByTokenHash(ctx, authinfra.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	switch group.StatusAt(time.Now()) {
+	case entity.GroupBookingOpen:
+		return group, nil
+	case entity.GroupBookingExpired:
+		if group.Status == entity.GroupBookingOpen {
+			if err := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupBookingExpired); err != nil {
+				s.logger.WithContext(ctx).Warn("failed to expire group booking", zap.String("group_booking_id", group.ID.String()), zap.Error(err))
+			}
+		}
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "group booking has expired")
+	default:
+		return nil, apperrors.ErrConflict("group booking has already been finalized")
+	}
+}
+
+// finalize books the participants' live holds as one booking and closes
+// the group. Participants whose hold has lapsed are left out. The caller
+// holds the group's lock.
+func (s *Service) finalize(ctx context.Context, group *entity.GroupBooking) (*booking.ConfirmBookingResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	holds := make([]*entity.SeatHold, 0, len(group.Participants))
+	for _, p := range group.Participants {
+		hold, err := s.holdRepo.GetByID(ctx, p.HoldID)
+		if err != nil {
+			if apperrors.Is(err, apperrors.CodeNotFound) {
+				log.Info("group booking hold lapsed", zap.String("group_booking_id", group.ID.String()), zap.String("hold_id", p.HoldID))
+				continue
+			}
+			return nil, err
+		}
+		if hold.IsExpired() || hold.GroupBookingID == nil || *hold.GroupBookingID != group.ID {
+			continue
+		}
+		holds = append(holds, hold)
+	}
+	if len(holds) == 0 {
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "no seats are held for the group any more")
+	}
+
+	res, err := s.bookings.BookGroup(ctx, group.OrganizerUserID, holds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupBookingFinalized); err != nil {
+		log.Error("failed to close finalized group booking", zap.String("group_booking_id", group.ID.String()), zap.Error(err))
+		return nil, err
+	}
+	now := time.Now()
+	group.Status = entity.GroupBookingFinalized
+	group.BookingID = &res.BookingID
+	group.FinalizedAt = &now
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		log.Error("failed to link booking to group booking", zap.Error(err))
+	}
+
+	log.Info("group booking finalized",
+		zap.String("group_booking_id", group.ID.String()),
+		zap.String("booking_reference", res.BookingReference),
+		zap.Int("holds", len(holds)),
+	)
+	return res, nil
+}
+
+func (s *Service) shareLink(token string) string {
+	return fmt.Sprintf("%s/group-bookings/join?token=%s", s.frontendURL, token)
+}
+
+// notifyOrganizerFinalized tells the organizer that everyone joined and
+// the group's booking is waiting for their payment
+func (s *Service) notifyOrganizerFinalized(ctx context.Context, group *entity.GroupBooking, res *booking.ConfirmBookingResponse) {
+	organizer, err := s.userRepo.GetByID(ctx, group.OrganizerUserID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to load group booking organizer", zap.Error(err))
+		return
+	}
+
+	payLink := res.CheckoutURL
+	if payLink == "" {
+		payLink = fmt.Sprintf("%s/bookings/%s", s.frontendURL, res.BookingID)
+	}
+	payBy := ""
+	if res.PayBy != nil {
+		payBy = "\nPay before: " + res.PayBy.Format(time.RFC1123)
+	}
+
+	s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{organizer.Email},
+		Subject: "Your group booking " + res.BookingReference + " is ready to pay",
+		Body: fmt.Sprintf(
+			"All %d participants have picked their seats.\n\nBooking: %s\nTickets: %d\nTotal: %.2f%s\n\n%s",
+			group.CurrentParticipants, res.BookingReference, res.NumTickets, res.Total, payBy, payLink,
+		),
+	})
+}
+
+func toGroupBookingResponse(group *entity.GroupBooking, now time.Time) *GroupBookingResponse {
+	participants := make([]ParticipantResponse, 0, len(group.Participants))
+	for _, p := range group.Participants {
+		participants = append(participants, ParticipantResponse{
+			SeatIDs:   p.SeatIDs,
+			Organizer: p.UserID == group.OrganizerUserID,
+			JoinedAt:  p.JoinedAt,
+		})
+	}
+
+	seatIDs := group.PooledSeatIDs()
+	if seatIDs == nil {
+		seatIDs = entity.UUIDList{}
+	}
+
+	return &GroupBookingResponse{
+		ID:                  group.ID,
+		ShowtimeID:          group.ShowtimeID,
+		MaxParticipants:     group.MaxParticipants,
+		CurrentParticipants: group.CurrentParticipants,
+		Status:              string(group.StatusAt(now)),
+		ExpiresAt:           group.ExpiresAt,
+		SeatIDs:             seatIDs,
+		Participants:        participants,
+		BookingID:           group.BookingID,
+		FinalizedAt:         group.FinalizedAt,
+	}
+}
+
+func hashCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	return authinfra.HashToken(code)
+}
@@ -0,0 +1,304 @@
+package groupbooking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memGroups keeps group bookings by share token hash
+type memGroups struct {
+	repository.GroupBookingRepository
+	groups map[string]*entity.GroupBooking
+}
+
+func (m *memGroups) Create(_ context.Context, group *entity.GroupBooking) error {
+	group.ID = uuid.New()
+	m.groups[group.ShareTokenHash] = group
+	return nil
+}
+
+func (m *memGroups) GetByTokenHash(_ context.Context, tokenHash string) (*entity.GroupBooking, error) {
+	group, ok := m.groups[tokenHash]
+	if !ok {
+		return nil, apperrors.ErrNotFound("group booking")
+	}
+	copied := *group
+	copied.Participants = append([]entity.GroupBookingParticipant{}, group.Participants...)
+	return &copied, nil
+}
+
+func (m *memGroups) TransitionStatus(_ context.Context, id uuid.UUID, from, to entity.GroupBookingStatus) error {
+	for _, group := range m.groups {
+		if group.ID == id {
+			if group.Status != from {
+				return apperrors.ErrConflict("group booking status changed")
+			}
+			group.Status = to
+		}
+	}
+	return nil
+}
+
+func (m *memGroups) Update(_ context.Context, group *entity.GroupBooking) error {
+	stored := m.groups[group.ShareTokenHash]
+	stored.BookingID, stored.FinalizedAt = group.BookingID, group.FinalizedAt
+	return nil
+}
+
+// memLocks counts the locks taken and released
+type memLocks struct {
+	acquired, released int
+}
+
+func (m *memLocks) Acquire(context.Context, string) (func(), error) {
+	m.acquired++
+	return func() { m.released++ }, nil
+}
+
+// memHolds keeps the participants' holds by ID
+type memHolds struct {
+	repository.SeatHoldRepository
+	holds map[string]*entity.SeatHold
+}
+
+func (m *memHolds) GetByID(_ context.Context, id string) (*entity.SeatHold, error) {
+	hold, ok := m.holds[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("hold")
+	}
+	return hold, nil
+}
+
+func (m *memHolds) Delete(_ context.Context, hold *entity.SeatHold) error {
+	delete(m.holds, hold.ID)
+	return nil
+}
+
+// memBookings records the bookings created
+type memBookings struct {
+	repository.BookingRepository
+	created []*entity.Booking
+}
+
+func (m *memBookings) CreateWithSeats(_ context.Context, booking *entity.Booking, _ []*entity.BookingSeat) error {
+	booking.ID = uuid.New()
+	m.created = append(m.created, booking)
+	return nil
+}
+
+type noSeatFeed struct {
+	repository.SeatUpdateFeed
+}
+
+func (noSeatFeed) Publish(context.Context, uuid.UUID, []repository.SeatUpdate) error { return nil }
+
+// inlineTx runs the work as it comes
+type inlineTx struct{}
+
+func (inlineTx) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// noOutbox drops the events added to the outbox
+type noOutbox struct {
+	repository.OutboxRepository
+}
+
+func (noOutbox) Add(context.Context, []*entity.OutboxEvent) error {
+	return nil
+}
+
+// memShowtimes serves one showtime
+type memShowtimes struct {
+	repository.ShowtimeRepository
+	showtime *entity.Showtime
+}
+
+func (m *memShowtimes) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	if id != m.showtime.ID {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	return m.showtime, nil
+}
+
+type groupFixture struct {
+	svc       *Service
+	groups    *memGroups
+	locks     *memLocks
+	holds     *memHolds
+	bookings  *memBookings
+	showtime  *entity.Showtime
+	organizer uuid.UUID
+}
+
+func newGroupFixture(t *testing.T) *groupFixture {
+	t.Helper()
+	f := &groupFixture{
+		groups:    &memGroups{groups: map[string]*entity.GroupBooking{}},
+		locks:     &memLocks{},
+		holds:     &memHolds{holds: map[string]*entity.SeatHold{}},
+		bookings:  &memBookings{},
+		showtime:  &entity.Showtime{ID: uuid.New(), Status: entity.ShowtimeScheduled, AvailableSeats: 50},
+		organizer: uuid.New(),
+	}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	m, err := metrics.New(metrics.Config{ServiceName: "groupbooking-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	cfg := config.BookingConfig{HoldTTL: 10 * time.Minute}
+	bookings := booking.NewService(f.holds, nil, nil, f.bookings, nil, nil, nil, nil, nil, nil, nil, noSeatFeed{}, nil, nil, nil, m, inlineTx{}, outbox.New(noOutbox{}), cfg, config.TicketConfig{}, log)
+	f.svc = NewService(f.groups, f.locks, &memShowtimes{showtime: f.showtime}, f.holds, nil, bookings, nil, cfg, log, "https://cinema.example.com")
+	return f
+}
+
+// open adds a group with the given participants, each holding one seat,
+// and returns its share token
+func (f *groupFixture) open(participants int, maxParticipants int) (string, *entity.GroupBooking) {
+	token := uuid.NewString()
+	group := &entity.GroupBooking{
+		ID:                  uuid.New(),
+		OrganizerUserID:     f.organizer,
+		ShowtimeID:          f.showtime.ID,
+		MaxParticipants:     maxParticipants,
+		CurrentParticipants: participants,
+		ShareTokenHash:      authinfra.HashToken(token),
+		ExpiresAt:           time.Now().Add(10 * time.Minute),
+		Status:              entity.GroupBookingOpen,
+	}
+	for i := range participants {
+		userID := f.organizer
+		if i > 0 {
+			userID = uuid.New()
+		}
+		hold := &entity.SeatHold{
+			ID: uuid.NewString(), ShowtimeID: f.showtime.ID, UserID: userID, ExpiresAt: group.ExpiresAt, GroupBookingID: &group.ID,
+			Seats: []entity.HeldSeat{{SeatID: uuid.New(), Price: 10}}, Subtotal: 10,
+		}
+		f.holds.holds[hold.ID] = hold
+		group.Participants = append(group.Participants, entity.GroupBookingParticipant{
+			GroupBookingID: group.ID, UserID: userID, HoldID: hold.ID, SeatIDs: entity.UUIDList(hold.SeatIDs()), JoinedAt: time.Now(),
+		})
+	}
+	f.groups.groups[group.ShareTokenHash] = group
+	return token, group
+}
+
+func TestCreateGroupBooking(t *testing.T) {
+	f := newGroupFixture(t)
+	res, err := f.svc.Create(context.Background(), f.organizer, CreateGroupBookingRequest{ShowtimeID: f.showtime.ID, MaxParticipants: 4})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if res.ShareToken == "" || !strings.HasSuffix(res.ShareURL, "token="+res.ShareToken) {
+		t.Errorf("share link %q does not carry the token", res.ShareURL)
+	}
+	// Only the hash of the token is stored
+	stored, ok := f.groups.groups[authinfra.HashToken(res.ShareToken)]
+	if !ok || stored.ShareTokenHash == res.ShareToken {
+		t.Fatal("the group is not found by the hash of its token")
+	}
+	if res.Status != string(entity.GroupBookingOpen) || time.Until(res.ExpiresAt) > 10*time.Minute {
+		t.Errorf("status %s until %v, want open for one hold TTL", res.Status, res.ExpiresAt)
+	}
+
+	f.showtime.AvailableSeats = 0
+	if _, err := f.svc.Create(context.Background(), f.organizer, CreateGroupBookingRequest{ShowtimeID: f.showtime.ID, MaxParticipants: 4}); !apperrors.Is(err, apperrors.CodeShowtimeFull) {
+		t.Errorf("sold out: %v, want %s", err, apperrors.CodeShowtimeFull)
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	ctx := context.Background()
+	f := newGroupFixture(t)
+	token, group := f.open(3, 4)
+
+	if _, err := f.svc.Finalize(ctx, group.Participants[1].UserID, token); !apperrors.Is(err, apperrors.CodeForbidden) {
+		t.Fatalf("finalized by a participant: %v, want %s", err, apperrors.CodeForbidden)
+	}
+
+	// One participant's hold lapsed and is left out
+	delete(f.holds.holds, group.Participants[2].HoldID)
+	res, err := f.svc.Finalize(ctx, f.organizer, token)
+	if err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if res.Booking.NumTickets != 2 || res.Booking.Total != 20 {
+		t.Errorf("booked %d tickets for %v, want the 2 live holds for 20", res.Booking.NumTickets, res.Booking.Total)
+	}
+	if len(f.bookings.created) != 1 || *f.bookings.created[0].UserID != f.organizer {
+		t.Fatalf("want one booking for the organizer, got %d", len(f.bookings.created))
+	}
+	if group.Status != entity.GroupBookingFinalized || group.BookingID == nil || *group.BookingID != res.Booking.BookingID {
+		t.Errorf("group %s linked to %v, want finalized with the booking", group.Status, group.BookingID)
+	}
+	if len(f.holds.holds) != 0 {
+		t.Errorf("%d holds left after booking", len(f.holds.holds))
+	}
+
+	if _, err := f.svc.Finalize(ctx, f.organizer, token); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("finalized twice: %v, want %s", err, apperrors.CodeConflict)
+	}
+	if f.locks.acquired != 3 || f.locks.released != 3 {
+		t.Errorf("locks taken %d, released %d, want 3 each", f.locks.acquired, f.locks.released)
+	}
+}
+
+func TestFinalizeWithoutLiveHolds(t *testing.T) {
+	f := newGroupFixture(t)
+	token, group := f.open(2, 4)
+	for _, p := range group.Participants {
+		f.holds.holds[p.HoldID].ExpiresAt = time.Now().Add(-time.Second)
+	}
+	if _, err := f.svc.Finalize(context.Background(), f.organizer, token); !apperrors.Is(err, apperrors.CodeBookingExpired) {
+		t.Errorf("Finalize = %v, want %s", err, apperrors.CodeBookingExpired)
+	}
+	if group.Status != entity.GroupBookingOpen || len(f.bookings.created) != 0 {
+		t.Error("the group was closed without a booking")
+	}
+}
+
+func TestJoinRejected(t *testing.T) {
+	ctx := context.Background()
+	f := newGroupFixture(t)
+	req := JoinGroupBookingRequest{SeatIDs: []uuid.UUID{uuid.New()}}
+
+	fullToken, _ := f.open(2, 2)
+	if _, err := f.svc.Join(ctx, uuid.New(), fullToken, req); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("joining a full group: %v, want %s", err, apperrors.CodeConflict)
+	}
+
+	token, _ := f.open(1, 4)
+	if _, err := f.svc.Join(ctx, f.organizer, token, req); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("joining twice: %v, want %s", err, apperrors.CodeConflict)
+	}
+
+	// A group found past its expiry is marked expired
+	expiredToken, expired := f.open(1, 4)
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := f.svc.Join(ctx, uuid.New(), expiredToken, req); !apperrors.Is(err, apperrors.CodeBookingExpired) {
+		t.Errorf("joining an expired group: %v, want %s", err, apperrors.CodeBookingExpired)
+	}
+	if expired.Status != entity.GroupBookingExpired {
+		t.Errorf("expired group stored as %s", expired.Status)
+	}
+
+	if f.locks.acquired != 3 || f.locks.released != 3 {
+		t.Errorf("locks taken %d, released %d, want 3 each", f.locks.acquired, f.locks.released)
+	}
+}
@@ -0,0 +1,70 @@
+package groupcheckout
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resolve actions available to the organizer once the share window lapses
+const (
+	ActionPayRemainder  = "PAY_REMAINDER"
+	ActionReleaseUnpaid = "RELEASE_UNPAID"
+)
+
+// ShareAssignment assigns some of the held seats to an invitee
+type ShareAssignment struct {
+	Email   string      `json:"email" validate:"required,email"`
+	SeatIDs []uuid.UUID `json:"seat_ids" validate:"required,min=1,dive,required" swaggertype:"array,string" format:"uuid"`
+}
+
+// SplitHoldRequest represents request to split a hold into paid shares
+type SplitHoldRequest struct {
+	Shares []ShareAssignment `json:"shares" validate:"required,min=2,dive"`
+}
+
+// ResolveRequest represents the organizer's decision after the share window lapsed
+type ResolveRequest struct {
+	Action string `json:"action" validate:"required,oneof=PAY_REMAINDER RELEASE_UNPAID"`
+}
+
+// ShareResponse represents a share in responses
+type ShareResponse struct {
+	ID               uuid.UUID   `json:"id" swaggertype:"string" format:"uuid"`
+	InviteeEmail     string      `json:"invitee_email"`
+	SeatIDs          []uuid.UUID `json:"seat_ids" swaggertype:"array,string" format:"uuid"`
+	Amount           float64     `json:"amount"`     // booking fee included
+	FeeAmount        float64     `json:"fee_amount"` // this share's part of the booking fee
+	Status           string      `json:"status"`
+	PaymentReference string      `json:"payment_reference"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+	PaidAt           *time.Time  `json:"paid_at,omitempty"`
+}
+
+// GroupCheckoutResponse represents a group checkout in responses
+type GroupCheckoutResponse struct {
+	ID             uuid.UUID       `json:"id" swaggertype:"string" format:"uuid"`
+	GroupReference string          `json:"group_reference"`
+	HoldID         string          `json:"hold_id"`
+	ShowtimeID     uuid.UUID       `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Status         string          `json:"status"`
+	TotalAmount    float64         `json:"total_amount"`
+	PaidAmount     float64         `json:"paid_amount"`
+	HoldExpiresAt  time.Time       `json:"hold_expires_at"`
+	BookingID      *uuid.UUID      `json:"booking_id,omitempty" swaggertype:"string" format:"uuid"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	Shares         []ShareResponse `json:"shares"`
+}
+
+// SharePaymentResponse is what an invitee sees when opening their pay link
+type SharePaymentResponse struct {
+	ShareID          uuid.UUID   `json:"share_id" swaggertype:"string" format:"uuid"`
+	GroupReference   string      `json:"group_reference"`
+	ShowtimeID       uuid.UUID   `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	SeatIDs          []uuid.UUID `json:"seat_ids" swaggertype:"array,string" format:"uuid"`
+	Amount           float64     `json:"amount"`
+	FeeAmount        float64     `json:"fee_amount"`
+	Status           string      `json:"status"`
+	PaymentReference string      `json:"payment_reference"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+}
@@ -0,0 +1,680 @@
+package groupcheckout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	bookingapp "cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// minShareWindow is the shortest payment window worth sending to invitees
+	minShareWindow = time.Minute
+	// sweepBatchSize limits how many group checkouts are processed per sweep
+	sweepBatchSize = 100
+	// sharePaymentGateway is recorded on payments created from paid shares
+	sharePaymentGateway = "group_checkout"
+)
+
+// Service handles split-payment group checkouts
+type Service struct {
+	groupRepo   repository.GroupCheckoutRepository
+	holdRepo    repository.SeatHoldRepository
+	bookingRepo repository.BookingRepository
+	paymentRepo repository.PaymentRepository
+	userRepo    repository.UserRepository
+	deviceRepo  repository.AssistiveDeviceRepository
+	tx          repository.Transactor
+	outbox      *outbox.Outbox
+	dispatcher  *async.Dispatcher
+	cfg         config.BookingConfig
+	logger      *logger.Logger
+	frontendURL string
+}
+
+// NewService creates a new group checkout service
+func NewService(
+	groupRepo repository.GroupCheckoutRepository,
+	holdRepo repository.SeatHoldRepository,
+	bookingRepo repository.BookingRepository,
+	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
+	tx repository.Transactor,
+	outbox *outbox.Outbox,
+	dispatcher *async.Dispatcher,
+	cfg config.BookingConfig,
+	logger *logger.Logger,
+	frontendURL string,
+) *Service {
+	return &Service{
+		groupRepo:   groupRepo,
+		holdRepo:    holdRepo,
+		bookingRepo: bookingRepo,
+		paymentRepo: paymentRepo,
+		userRepo:    userRepo,
+		deviceRepo:  deviceRepo,
+		tx:          tx,
+		outbox:      outbox,
+		dispatcher:  dispatcher,
+		cfg:         cfg,
+		logger:      logger,
+		frontendURL: frontendURL,
+	}
+}
+
+// Split turns the organizer's hold into one payable share per invitee
+func (s *Service) Split(ctx context.Context, organizerID uuid.UUID, holdID string, req SplitHoldRequest) (*GroupCheckoutResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != organizerID {
+		return nil, apperrors.ErrForbidden("hold belongs to another user")
+	}
+
+	existing, err := s.groupRepo.GetActiveByHoldID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.ErrConflict("hold already has an active group checkout")
+	}
+
+	organizer, err := s.userRepo.GetByID(ctx, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shares expire before the hold so the organizer still has time to react
+	shareExpiresAt := hold.ExpiresAt.Add(-s.cfg.SplitShareMargin)
+	if time.Until(shareExpiresAt) < minShareWindow {
+		return nil, apperrors.New(apperrors.CodeBookingExpired, "hold expires too soon to split payment")
+	}
+
+	group := &entity.GroupCheckout{
+		GroupReference: authinfra.GenerateGroupReference(),
+		HoldID:         hold.ID,
+		ShowtimeID:     hold.ShowtimeID,
+		OrganizerID:    organizer.ID,
+		OrganizerEmail: organizer.Email,
+		Status:         entity.GroupCheckoutOpen,
+		TotalAmount:    hold.Total(),
+		HoldExpiresAt:  hold.ExpiresAt,
+	}
+
+	assigned := make(map[uuid.UUID]bool, len(hold.Seats))
+	emails := make(map[string]bool, len(req.Shares))
+	tokens := make([]string, 0, len(req.Shares))
+	subtotals := make([]float64, 0, len(req.Shares))
+
+	for _, assignment := range req.Shares {
+		if emails[assignment.Email] {
+			return nil, apperrors.ErrBadRequest("each invitee can only have one share")
+		}
+		emails[assignment.Email] = true
+
+		shareSeats := entity.UUIDList(assignment.SeatIDs)
+
+		var amount float64
+		for _, seatID := range assignment.SeatIDs {
+			seat, ok := hold.FindSeat(seatID)
+			if !ok {
+				return nil, apperrors.ErrBadRequest("seat is not part of the hold")
+			}
+			if assigned[seatID] {
+				return nil, apperrors.ErrBadRequest("seat is assigned to more than one share")
+			}
+			// A free companion seat must be paid for together with its wheelchair seat
+			if seat.PairedSeatID != nil && !shareSeats.Contains(*seat.PairedSeatID) {
+				return nil, apperrors.ErrBadRequest("companion seat " + seat.SeatLabel + " must be in the same share as its wheelchair seat")
+			}
+			assigned[seatID] = true
+			amount += seat.Price
+		}
+
+		token, err := authinfra.GenerateRandomToken(32)
+		if err != nil {
+			log.Error("failed to generate pay link token", zap.Error(err))
+			return nil, apperrors.ErrInternal("failed to generate token")
+		}
+		tokens = append(tokens, token)
+
+		group.Shares = append(group.Shares, entity.GroupCheckoutShare{
+			InviteeEmail:     assignment.Email,
+			SeatIDs:          shareSeats,
+			Amount:           amount,
+			Status:           entity.ShareStatusPending,
+			PaymentReference: authinfra.GeneratePaymentReference(),
+			PayTokenHash:     authinfra.HashToken(token),
+			ExpiresAt:        shareExpiresAt,
+		})
+		subtotals = append(subtotals, amount)
+	}
+
+	if len(assigned) != len(hold.Seats) {
+		return nil, apperrors.ErrBadRequest("every held seat must be assigned to a share")
+	}
+
+	// Each share carries the booking fee in proportion to its seats' price
+	for i, fee := range entity.AllocateFee(hold.Fee, subtotals) {
+		share := &group.Shares[i]
+		share.FeeAmount = fee
+		share.Amount = entity.RoundCents(share.Amount + fee)
+	}
+
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		log.Error("failed to create group checkout", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range group.Shares {
+		s.sendPayLink(group, &group.Shares[i], tokens[i], organizer.FullName())
+	}
+
+	log.Info("group checkout created",
+		zap.String("group_reference", group.GroupReference),
+		zap.String("hold_id", hold.ID),
+		zap.Int("shares", len(group.Shares)),
+	)
+
+	return toGroupCheckoutResponse(group), nil
+}
+
+// GetByID returns a group checkout owned by the organizer
+func (s *Service) GetByID(ctx context.Context, organizerID, id uuid.UUID) (*GroupCheckoutResponse, error) {
+	group, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group.OrganizerID != organizerID {
+		return nil, apperrors.ErrForbidden("group checkout belongs to another user")
+	}
+	return toGroupCheckoutResponse(group), nil
+}
+
+// GetShareByToken returns the share behind an invitee's pay link
+func (s *Service) GetShareByToken(ctx context.Context, token string) (*SharePaymentResponse, error) {
+	share, err := s.groupRepo.GetShareByTokenHash(ctx, authinfra.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, share.GroupCheckoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SharePaymentResponse{
+		ShareID:          share.ID,
+		GroupReference:   group.GroupReference,
+		ShowtimeID:       group.ShowtimeID,
+		SeatIDs:          share.SeatIDs,
+		Amount:           share.Amount,
+		FeeAmount:        share.FeeAmount,
+		Status:           string(share.Status),
+		PaymentReference: share.PaymentReference,
+		ExpiresAt:        share.ExpiresAt,
+	}, nil
+}
+
+// MarkSharePaid records a successful payment for a share. It is driven by the
+// payment provider callback using the share's payment reference and is safe to
+// call more than once for the same payment.
+func (s *Service) MarkSharePaid(ctx context.Context, paymentReference, gatewayTransactionID string) error {
+	log := s.logger.WithContext(ctx)
+
+	share, err := s.groupRepo.GetShareByPaymentReference(ctx, paymentReference)
+	if err != nil {
+		return err
+	}
+	group, err := s.groupRepo.GetByID(ctx, share.GroupCheckoutID)
+	if err != nil {
+		return err
+	}
+
+	if share.Status == entity.ShareStatusPaid {
+		// A repeated callback finishes a checkout that was interrupted after
+		// the share was recorded
+		if group.Status == entity.GroupCheckoutOpen && group.AllSharesSettled() {
+			return s.complete(ctx, group)
+		}
+		return nil
+	}
+
+	if group.Status != entity.GroupCheckoutOpen || !share.IsPayable() {
+		log.Warn("payment received for a share that can no longer be paid, refund required",
+			zap.String("group_reference", group.GroupReference),
+			zap.String("payment_reference", paymentReference),
+			zap.String("share_status", string(share.Status)),
+		)
+		return apperrors.New(apperrors.CodeBookingExpired, "share payment window has closed")
+	}
+
+	if err := s.groupRepo.MarkSharePaid(ctx, share.ID, gatewayTransactionID, time.Now()); err != nil {
+		return err
+	}
+
+	log.Info("group checkout share paid",
+		zap.String("group_reference", group.GroupReference),
+		zap.String("payment_reference", paymentReference),
+	)
+
+	group, err = s.groupRepo.GetByID(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if !group.AllSharesSettled() {
+		return nil
+	}
+
+	return s.complete(ctx, group)
+}
+
+// Resolve applies the organizer's decision for a group whose share window lapsed
+func (s *Service) Resolve(ctx context.Context, organizerID, id uuid.UUID, req ResolveRequest) (*GroupCheckoutResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	group, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group.OrganizerID != organizerID {
+		return nil, apperrors.ErrForbidden("group checkout belongs to another user")
+	}
+	if group.Status != entity.GroupCheckoutAwaitingOrganizer {
+		return nil, apperrors.ErrConflict("group checkout is not waiting for the organizer")
+	}
+
+	hold, err := s.holdRepo.GetByID(ctx, group.HoldID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingExpired, "seat hold has expired")
+		}
+		return nil, err
+	}
+
+	var unpaidSeats []uuid.UUID
+	var unpaidAmount, unpaidFee float64
+	for _, share := range group.Shares {
+		if share.Status == entity.ShareStatusExpired {
+			unpaidSeats = append(unpaidSeats, share.SeatIDs...)
+			unpaidAmount += share.Amount
+			unpaidFee += share.FeeAmount
+		}
+	}
+
+	switch req.Action {
+	case ActionPayRemainder:
+		token, err := authinfra.GenerateRandomToken(32)
+		if err != nil {
+			log.Error("failed to generate pay link token", zap.Error(err))
+			return nil, apperrors.ErrInternal("failed to generate token")
+		}
+
+		share := &entity.GroupCheckoutShare{
+			GroupCheckoutID:  group.ID,
+			InviteeEmail:     group.OrganizerEmail,
+			SeatIDs:          entity.UUIDList(unpaidSeats),
+			Amount:           entity.RoundCents(unpaidAmount),
+			FeeAmount:        entity.RoundCents(unpaidFee),
+			Status:           entity.ShareStatusPending,
+			PaymentReference: authinfra.GeneratePaymentReference(),
+			PayTokenHash:     authinfra.HashToken(token),
+			ExpiresAt:        hold.ExpiresAt,
+		}
+		if err := s.groupRepo.CreateShare(ctx, share); err != nil {
+			return nil, err
+		}
+		if err := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupCheckoutOpen); err != nil {
+			return nil, err
+		}
+		s.sendPayLink(group, share, token, "")
+
+	case ActionReleaseUnpaid:
+		if err := s.holdRepo.ReleaseSeats(ctx, hold, unpaidSeats); err != nil {
+			return nil, err
+		}
+		if err := s.groupRepo.UpdateShareStatus(ctx, group.ID, entity.ShareStatusExpired, entity.ShareStatusReleased); err != nil {
+			return nil, err
+		}
+
+		group, err = s.groupRepo.GetByID(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(group.PaidShares()) == 0 {
+			if err := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupCheckoutCancelled); err != nil {
+				return nil, err
+			}
+			if err := s.holdRepo.Delete(ctx, hold); err != nil {
+				log.Warn("failed to release hold", zap.String("hold_id", hold.ID), zap.Error(err))
+			}
+		} else if err := s.complete(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("group checkout resolved",
+		zap.String("group_reference", group.GroupReference),
+		zap.String("action", req.Action),
+	)
+
+	return s.GetByID(ctx, organizerID, id)
+}
+
+// ExpireLapsed moves open groups with unpaid shares past their window to the
+// organizer and cancels groups the organizer never resolved before the hold expired
+func (s *Service) ExpireLapsed(ctx context.Context) error {
+	log := s.logger.WithContext(ctx)
+	now := time.Now()
+
+	lapsed, err := s.groupRepo.ListLapsed(ctx, now, sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, group := range lapsed {
+		if err := s.groupRepo.TransitionStatus(ctx, group.ID, entity.GroupCheckoutOpen, entity.GroupCheckoutAwaitingOrganizer); err != nil {
+			// Another worker or a late payment got there first
+			continue
+		}
+		if err := s.groupRepo.UpdateShareStatus(ctx, group.ID, entity.ShareStatusPending, entity.ShareStatusExpired); err != nil {
+			log.Error("failed to expire shares", zap.String("group_id", group.ID.String()), zap.Error(err))
+			continue
+		}
+		s.notifyOrganizerLapsed(group)
+	}
+
+	abandoned, err := s.groupRepo.ListAbandoned(ctx, now, sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, group := range abandoned {
+		if err := s.groupRepo.TransitionStatus(ctx, group.ID, entity.GroupCheckoutAwaitingOrganizer, entity.GroupCheckoutCancelled); err != nil {
+			continue
+		}
+		if paid := group.PaidShares(); len(paid) > 0 {
+			log.Warn("group checkout abandoned with paid shares, refund required",
+				zap.String("group_reference", group.GroupReference),
+				zap.Int("paid_shares", len(paid)),
+			)
+		}
+		s.notifyOrganizerCancelled(group)
+	}
+
+	if len(lapsed) > 0 || len(abandoned) > 0 {
+		log.Info("group checkout sweep finished",
+			zap.Int("lapsed", len(lapsed)),
+			zap.Int("abandoned", len(abandoned)),
+		)
+	}
+	return nil
+}
+
+// RegisterSubscribers subscribes the service's side effects to domain events
+func (s *Service) RegisterSubscribers(bus *eventbus.Bus) {
+	bus.Subscribe(events.GroupCheckoutCompletedEvent, "groupcheckout.completed_email", s.onCompleted)
+}
+
+// complete confirms a single booking for the organizer covering every paid share
+func (s *Service) complete(ctx context.Context, group *entity.GroupCheckout) error {
+	log := s.logger.WithContext(ctx)
+
+	hold, err := s.holdRepo.GetByID(ctx, group.HoldID)
+	if err != nil {
+		log.Error("hold lost before group checkout completed, refund required",
+			zap.String("group_reference", group.GroupReference),
+			zap.Error(err),
+		)
+		if terr := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupCheckoutCancelled); terr == nil {
+			s.notifyOrganizerCancelled(group)
+		}
+		return apperrors.New(apperrors.CodeBookingExpired, "seat hold expired before all shares were paid")
+	}
+
+	paid := group.PaidShares()
+	now := time.Now()
+
+	var seats []*entity.BookingSeat
+	var subtotal, fee float64
+	for _, share := range paid {
+		for _, seatID := range share.SeatIDs {
+			held, _ := hold.FindSeat(seatID)
+			seats = append(seats, &entity.BookingSeat{SeatID: seatID, Price: held.Price, Fare: held.Fare})
+			subtotal += held.Price
+		}
+		fee += share.FeeAmount
+	}
+	subtotal, fee = entity.RoundCents(subtotal), entity.RoundCents(fee)
+
+	booking := &entity.Booking{
+		BookingReference: authinfra.GenerateBookingReference(),
+		UserID:           &group.OrganizerID,
+		ShowtimeID:       group.ShowtimeID,
+		NumTickets:       len(seats),
+		SubtotalAmount:   subtotal,
+		FeeAmount:        fee,
+		FinalAmount:      entity.RoundCents(subtotal + fee),
+		BookingStatus:    entity.BookingConfirmed,
+		PaymentStatus:    entity.PaymentPaid,
+		SalesChannel:     entity.ChannelOnline,
+		BookedAt:         group.CreatedAt,
+		ConfirmedAt:      &now,
+	}
+
+	// The group is completed, its booking created, the shares recorded as
+	// the booking's payments and the events written to the outbox together.
+	// Every share is paid by now, so a lost race for the showtime's version
+	// is retried rather than failing the group. On failure the group keeps
+	// its status, so a repeated payment callback or the organizer can
+	// finish it.
+	err = bookingapp.RetryOnVersionConflict(ctx, s.logger, group.ShowtimeID, func(ctx context.Context) error {
+		return s.tx.InTransaction(ctx, func(ctx context.Context) error {
+			// Claim the completion so concurrent callbacks do not book twice
+			if err := s.groupRepo.TransitionStatus(ctx, group.ID, group.Status, entity.GroupCheckoutCompleted); err != nil {
+				return err
+			}
+
+			if err := s.bookingRepo.CreateWithSeats(ctx, booking, seats); err != nil {
+				return err
+			}
+
+			for _, share := range paid {
+				payment := &entity.Payment{
+					BookingID:            booking.ID,
+					PaymentReference:     share.PaymentReference,
+					PaymentGateway:       sharePaymentGateway,
+					GatewayTransactionID: share.GatewayTransactionID,
+					Amount:               share.Amount,
+					PaymentStatus:        entity.PaymentPaid,
+					PaidAt:               share.PaidAt,
+				}
+				if err := s.paymentRepo.Create(ctx, payment); err != nil {
+					return err
+				}
+			}
+
+			group.Status = entity.GroupCheckoutCompleted
+			group.BookingID = &booking.ID
+			group.CompletedAt = &now
+			if err := s.groupRepo.Update(ctx, group); err != nil {
+				return err
+			}
+
+			payers := make([]string, 0, len(paid))
+			for _, share := range paid {
+				if share.InviteeEmail != group.OrganizerEmail {
+					payers = append(payers, share.InviteeEmail)
+				}
+			}
+			return s.outbox.Add(ctx,
+				events.BookingCreated{
+					BookingID:        booking.ID,
+					BookingReference: booking.BookingReference,
+					UserID:           booking.UserID,
+					ShowtimeID:       booking.ShowtimeID,
+					NumTickets:       booking.NumTickets,
+					FinalAmount:      booking.FinalAmount,
+					BookedAt:         booking.BookedAt,
+				},
+				events.BookingConfirmed{
+					BookingID:        booking.ID,
+					BookingReference: booking.BookingReference,
+					UserID:           booking.UserID,
+					ShowtimeID:       booking.ShowtimeID,
+					NumTickets:       booking.NumTickets,
+					FinalAmount:      booking.FinalAmount,
+					ConfirmedAt:      now,
+				},
+				events.GroupCheckoutCompleted{
+					GroupCheckoutID:  group.ID,
+					GroupReference:   group.GroupReference,
+					BookingID:        booking.ID,
+					BookingReference: booking.BookingReference,
+					NumTickets:       booking.NumTickets,
+					OrganizerEmail:   group.OrganizerEmail,
+					PayerEmails:      payers,
+				},
+			)
+		})
+	})
+	if err != nil {
+		log.Error("failed to complete group checkout",
+			zap.String("group_reference", group.GroupReference),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if len(hold.Devices) > 0 {
+		// Devices were only checked when the seats were held; the paid
+		// booking stands even if the stock ran out since
+		if err := s.deviceRepo.Reserve(ctx, booking, hold.Devices); err != nil {
+			log.Warn("failed to reserve assistive devices, staff follow-up required",
+				zap.String("booking_reference", booking.BookingReference),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if err := s.holdRepo.Delete(ctx, hold); err != nil {
+		log.Warn("failed to release hold", zap.String("hold_id", hold.ID), zap.Error(err))
+	}
+
+	log.Info("group checkout completed",
+		zap.String("group_reference", group.GroupReference),
+		zap.String("booking_reference", booking.BookingReference),
+	)
+	return nil
+}
+
+func (s *Service) payLink(token string) string {
+	return fmt.Sprintf("%s/group-checkout/pay?token=%s", s.frontendURL, token)
+}
+
+func (s *Service) sendPayLink(group *entity.GroupCheckout, share *entity.GroupCheckoutShare, token, organizerName string) {
+	body := fmt.Sprintf(
+		"You have been invited to pay your share of group booking %s.\n\nSeats: %d\nAmount: %.2f\nPay before: %s\n\n%s",
+		group.GroupReference, len(share.SeatIDs), share.Amount, share.ExpiresAt.Format(time.RFC1123), s.payLink(token),
+	)
+	if organizerName != "" {
+		body = organizerName + " has split the cost of a movie booking with you.\n\n" + body
+	}
+
+	s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{share.InviteeEmail},
+		Subject: "Pay your share for booking " + group.GroupReference,
+		Body:    body,
+	})
+}
+
+func (s *Service) notifyOrganizerLapsed(group *entity.GroupCheckout) {
+	s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{group.OrganizerEmail},
+		Subject: "Some shares of " + group.GroupReference + " were not paid",
+		Body: fmt.Sprintf(
+			"Not everyone paid their share of group booking %s in time.\n\n"+
+				"You can pay the remainder yourself or release the unpaid seats before %s.\n\n%s/group-checkout/%s",
+			group.GroupReference, group.HoldExpiresAt.Format(time.RFC1123), s.frontendURL, group.ID,
+		),
+	})
+}
+
+func (s *Service) notifyOrganizerCancelled(group *entity.GroupCheckout) {
+	s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{group.OrganizerEmail},
+		Subject: "Group booking " + group.GroupReference + " was cancelled",
+		Body: fmt.Sprintf(
+			"The seat hold for group booking %s expired before checkout finished. Any payments already made will be refunded.",
+			group.GroupReference,
+		),
+	})
+}
+
+// onCompleted emails the organizer and every paying invitee
+func (s *Service) onCompleted(_ context.Context, event eventbus.Event) error {
+	completed := event.(events.GroupCheckoutCompleted)
+
+	sent := s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      append([]string{completed.OrganizerEmail}, completed.PayerEmails...),
+		Subject: "Group booking " + completed.GroupReference + " is confirmed",
+		Body: fmt.Sprintf(
+			"All shares have been paid. Booking %s is confirmed for %d seats.",
+			completed.BookingReference, completed.NumTickets,
+		),
+	})
+	if !sent {
+		return fmt.Errorf("email queue full")
+	}
+	return nil
+}
+
+func toGroupCheckoutResponse(group *entity.GroupCheckout) *GroupCheckoutResponse {
+	res := &GroupCheckoutResponse{
+		ID:             group.ID,
+		GroupReference: group.GroupReference,
+		HoldID:         group.HoldID,
+		ShowtimeID:     group.ShowtimeID,
+		Status:         string(group.Status),
+		TotalAmount:    group.TotalAmount,
+		HoldExpiresAt:  group.HoldExpiresAt,
+		BookingID:      group.BookingID,
+		CompletedAt:    group.CompletedAt,
+		Shares:         make([]ShareResponse, 0, len(group.Shares)),
+	}
+
+	for _, share := range group.Shares {
+		if share.Status == entity.ShareStatusPaid {
+			res.PaidAmount += share.Amount
+		}
+		res.Shares = append(res.Shares, ShareResponse{
+			ID:               share.ID,
+			InviteeEmail:     share.InviteeEmail,
+			SeatIDs:          share.SeatIDs,
+			Amount:           share.Amount,
+			FeeAmount:        share.FeeAmount,
+			Status:           string(share.Status),
+			PaymentReference: share.PaymentReference,
+			ExpiresAt:        share.ExpiresAt,
+			PaidAt:           share.PaidAt,
+		})
+	}
+
+	return res
+}
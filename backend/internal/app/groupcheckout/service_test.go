@@ -0,0 +1,770 @@
+package groupcheckout
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memGroups is a GroupCheckoutRepository kept in memory
+type memGroups struct {
+	mu     sync.Mutex
+	groups map[uuid.UUID]*entity.GroupCheckout
+}
+
+func newMemGroups() *memGroups {
+	return &memGroups{groups: make(map[uuid.UUID]*entity.GroupCheckout)}
+}
+
+func copyGroup(g *entity.GroupCheckout) *entity.GroupCheckout {
+	copied := *g
+	copied.Shares = append([]entity.GroupCheckoutShare(nil), g.Shares...)
+	return &copied
+}
+
+func (m *memGroups) Create(_ context.Context, group *entity.GroupCheckout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	group.ID = uuid.New()
+	group.CreatedAt = time.Now()
+	for i := range group.Shares {
+		group.Shares[i].ID = uuid.New()
+		group.Shares[i].GroupCheckoutID = group.ID
+	}
+	m.groups[group.ID] = copyGroup(group)
+	return nil
+}
+
+func (m *memGroups) GetByID(_ context.Context, id uuid.UUID) (*entity.GroupCheckout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.groups[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("group checkout")
+	}
+	return copyGroup(g), nil
+}
+
+func (m *memGroups) GetActiveByHoldID(_ context.Context, holdID string) (*entity.GroupCheckout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, g := range m.groups {
+		if g.HoldID == holdID && !g.Status.IsTerminal() {
+			return copyGroup(g), nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memGroups) ExistsForHold(_ context.Context, holdID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, g := range m.groups {
+		if g.HoldID == holdID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memGroups) Update(_ context.Context, group *entity.GroupCheckout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shares := m.groups[group.ID].Shares
+	m.groups[group.ID] = copyGroup(group)
+	m.groups[group.ID].Shares = shares
+	return nil
+}
+
+func (m *memGroups) TransitionStatus(_ context.Context, id uuid.UUID, from, to entity.GroupCheckoutStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.groups[id]
+	if g.Status != from || !from.CanTransitionTo(to) {
+		return apperrors.ErrConflict("group checkout status changed")
+	}
+	g.Status = to
+	return nil
+}
+
+func (m *memGroups) CreateShare(_ context.Context, share *entity.GroupCheckoutShare) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	share.ID = uuid.New()
+	g := m.groups[share.GroupCheckoutID]
+	g.Shares = append(g.Shares, *share)
+	return nil
+}
+
+func (m *memGroups) findShare(match func(*entity.GroupCheckoutShare) bool) (*entity.GroupCheckoutShare, error) {
+	for _, g := range m.groups {
+		for i := range g.Shares {
+			if match(&g.Shares[i]) {
+				share := g.Shares[i]
+				return &share, nil
+			}
+		}
+	}
+	return nil, apperrors.ErrNotFound("share")
+}
+
+func (m *memGroups) GetShareByTokenHash(_ context.Context, tokenHash string) (*entity.GroupCheckoutShare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.findShare(func(s *entity.GroupCheckoutShare) bool { return s.PayTokenHash == tokenHash })
+}
+
+func (m *memGroups) GetShareByPaymentReference(_ context.Context, reference string) (*entity.GroupCheckoutShare, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.findShare(func(s *entity.GroupCheckoutShare) bool { return s.PaymentReference == reference })
+}
+
+func (m *memGroups) MarkSharePaid(_ context.Context, id uuid.UUID, gatewayTransactionID string, paidAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, g := range m.groups {
+		for i := range g.Shares {
+			share := &g.Shares[i]
+			if share.ID != id {
+				continue
+			}
+			if share.Status != entity.ShareStatusPending {
+				return apperrors.ErrConflict("share is not pending")
+			}
+			share.Status = entity.ShareStatusPaid
+			share.GatewayTransactionID = &gatewayTransactionID
+			share.PaidAt = &paidAt
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound("share")
+}
+
+func (m *memGroups) UpdateShareStatus(_ context.Context, groupID uuid.UUID, from, to entity.GroupCheckoutShareStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.groups[groupID].Shares {
+		if share := &m.groups[groupID].Shares[i]; share.Status == from {
+			share.Status = to
+		}
+	}
+	return nil
+}
+
+func (m *memGroups) ListLapsed(_ context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var lapsed []*entity.GroupCheckout
+	for _, g := range m.groups {
+		if g.Status != entity.GroupCheckoutOpen {
+			continue
+		}
+		for _, share := range g.Shares {
+			if share.Status == entity.ShareStatusPending && share.ExpiresAt.Before(now) {
+				lapsed = append(lapsed, copyGroup(g))
+				break
+			}
+		}
+	}
+	return lapsed, nil
+}
+
+func (m *memGroups) ListAbandoned(_ context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var abandoned []*entity.GroupCheckout
+	for _, g := range m.groups {
+		if g.Status == entity.GroupCheckoutAwaitingOrganizer && g.HoldExpiresAt.Before(now) {
+			abandoned = append(abandoned, copyGroup(g))
+		}
+	}
+	return abandoned, nil
+}
+
+// lapse moves the payment window of the group's pending shares into the past
+func (m *memGroups) lapse(groupID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.groups[groupID].Shares {
+		if share := &m.groups[groupID].Shares[i]; share.Status == entity.ShareStatusPending {
+			share.ExpiresAt = time.Now().Add(-time.Second)
+		}
+	}
+}
+
+// memHolds is a SeatHoldRepository kept in memory
+type memHolds struct {
+	repository.SeatHoldRepository
+
+	mu    sync.Mutex
+	holds map[string]*entity.SeatHold
+}
+
+func (m *memHolds) Create(_ context.Context, hold *entity.SeatHold) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *hold
+	m.holds[hold.ID] = &copied
+	return nil
+}
+
+func (m *memHolds) GetByID(_ context.Context, id string) (*entity.SeatHold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hold, ok := m.holds[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("seat hold")
+	}
+	copied := *hold
+	copied.Seats = append([]entity.HeldSeat(nil), hold.Seats...)
+	return &copied, nil
+}
+
+func (m *memHolds) Update(ctx context.Context, hold *entity.SeatHold) error {
+	return m.Create(ctx, hold)
+}
+
+func (m *memHolds) ReleaseSeats(_ context.Context, hold *entity.SeatHold, seatIDs []uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.holds[hold.ID]
+	var kept []entity.HeldSeat
+	for _, seat := range stored.Seats {
+		released := false
+		for _, id := range seatIDs {
+			released = released || id == seat.SeatID
+		}
+		if !released {
+			kept = append(kept, seat)
+		}
+	}
+	stored.Seats = kept
+	return nil
+}
+
+func (m *memHolds) Delete(_ context.Context, hold *entity.SeatHold) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.holds, hold.ID)
+	return nil
+}
+
+// memBookings records the bookings the service creates
+type memBookings struct {
+	repository.BookingRepository
+
+	mu       sync.Mutex
+	bookings []*entity.Booking
+	seats    map[uuid.UUID][]*entity.BookingSeat
+}
+
+func (m *memBookings) CreateWithSeats(_ context.Context, booking *entity.Booking, seats []*entity.BookingSeat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	booking.ID = uuid.New()
+	m.bookings = append(m.bookings, booking)
+	m.seats[booking.ID] = seats
+	return nil
+}
+
+// memPayments records the payments the service creates
+type memPayments struct {
+	repository.PaymentRepository
+
+	mu       sync.Mutex
+	payments []*entity.Payment
+	failures int // Create calls left to fail
+}
+
+func (m *memPayments) Create(_ context.Context, payment *entity.Payment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		return apperrors.New(apperrors.CodeInternal, "failed to create payment")
+	}
+	m.payments = append(m.payments, payment)
+	return nil
+}
+
+// memOutbox keeps outbox events in memory for the relay to publish
+type memOutbox struct {
+	repository.OutboxRepository
+	events []*entity.OutboxEvent
+}
+
+func (m *memOutbox) Add(_ context.Context, events []*entity.OutboxEvent) error {
+	for _, event := range events {
+		event.ID = int64(len(m.events) + 1)
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+
+func (m *memOutbox) ListUnpublished(_ context.Context, _, limit int) ([]*entity.OutboxEvent, error) {
+	var pending []*entity.OutboxEvent
+	for _, event := range m.events {
+		if event.PublishedAt == nil && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memOutbox) MarkPublished(_ context.Context, id int64, at time.Time) error {
+	m.events[id-1].PublishedAt = &at
+	return nil
+}
+
+// memTx undoes what the group, booking, payment and outbox fakes stored
+// when the work fails, the way a database transaction rolls back
+type memTx struct {
+	groups   *memGroups
+	bookings *memBookings
+	payments *memPayments
+	outbox   *memOutbox
+}
+
+func (m *memTx) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.groups.mu.Lock()
+	groups := make(map[uuid.UUID]*entity.GroupCheckout, len(m.groups.groups))
+	for id, g := range m.groups.groups {
+		groups[id] = copyGroup(g)
+	}
+	m.groups.mu.Unlock()
+	bookings, payments, events := len(m.bookings.bookings), len(m.payments.payments), len(m.outbox.events)
+
+	if err := fn(ctx); err != nil {
+		m.groups.mu.Lock()
+		m.groups.groups = groups
+		m.groups.mu.Unlock()
+		m.bookings.bookings = m.bookings.bookings[:bookings]
+		m.payments.payments = m.payments.payments[:payments]
+		m.outbox.events = m.outbox.events[:events]
+		return err
+	}
+	return nil
+}
+
+type memUsers struct {
+	repository.UserRepository
+	users map[uuid.UUID]*entity.User
+}
+
+func (m *memUsers) GetByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("user")
+	}
+	return user, nil
+}
+
+type fixture struct {
+	svc       *Service
+	groups    *memGroups
+	holds     *memHolds
+	bookings  *memBookings
+	payments  *memPayments
+	outbox    *memOutbox
+	bus       *eventbus.Bus
+	organizer *entity.User
+	hold      *entity.SeatHold
+}
+
+// newFixture returns a service with an organizer holding four seats at
+// 10.00 each for the next 15 minutes
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	organizer := &entity.User{ID: uuid.New(), Email: "organizer@example.com", FirstName: "Olga", LastName: "Organizer"}
+
+	hold := &entity.SeatHold{
+		ID:         "hold-1",
+		ShowtimeID: uuid.New(),
+		UserID:     organizer.ID,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(15 * time.Minute),
+	}
+	for i := 0; i < 4; i++ {
+		hold.Seats = append(hold.Seats, entity.HeldSeat{SeatID: uuid.New(), SeatType: entity.SeatStandard, Price: 10})
+		hold.Subtotal += 10
+	}
+
+	f := &fixture{
+		groups:    newMemGroups(),
+		holds:     &memHolds{holds: make(map[string]*entity.SeatHold)},
+		bookings:  &memBookings{seats: make(map[uuid.UUID][]*entity.BookingSeat)},
+		payments:  &memPayments{},
+		outbox:    &memOutbox{},
+		bus:       eventbus.New(eventbus.Config{Lanes: 2, MaxAttempts: 2}, log),
+		organizer: organizer,
+		hold:      hold,
+	}
+	_ = f.holds.Create(context.Background(), hold)
+
+	f.svc = NewService(
+		f.groups, f.holds, f.bookings, f.payments,
+		&memUsers{users: map[uuid.UUID]*entity.User{organizer.ID: organizer}},
+		nil, // no hold in these tests reserves assistive devices
+		&memTx{groups: f.groups, bookings: f.bookings, payments: f.payments, outbox: f.outbox},
+		outbox.New(f.outbox),
+		async.NewDispatcher(1, 10, nil, log),
+		config.BookingConfig{SplitShareMargin: 2 * time.Minute},
+		log,
+		"https://cinema.example.com",
+	)
+	return f
+}
+
+// split gives the first two held seats to alice and the last two to bob
+func (f *fixture) split(t *testing.T) *GroupCheckoutResponse {
+	t.Helper()
+
+	seats := f.hold.SeatIDs()
+	res, err := f.svc.Split(context.Background(), f.organizer.ID, f.hold.ID, SplitHoldRequest{Shares: []ShareAssignment{
+		{Email: "alice@example.com", SeatIDs: seats[:2]},
+		{Email: "bob@example.com", SeatIDs: seats[2:]},
+	}})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	return res
+}
+
+func (f *fixture) group(t *testing.T, id uuid.UUID) *entity.GroupCheckout {
+	t.Helper()
+	group, err := f.groups.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return group
+}
+
+func shareStatuses(group *entity.GroupCheckout) map[string]entity.GroupCheckoutShareStatus {
+	statuses := make(map[string]entity.GroupCheckoutShareStatus)
+	for _, share := range group.Shares {
+		statuses[share.InviteeEmail] = share.Status
+	}
+	return statuses
+}
+
+func TestSplitValidatesAssignments(t *testing.T) {
+	f := newFixture(t)
+	seats := f.hold.SeatIDs()
+	stranger := uuid.New()
+
+	tests := []struct {
+		name   string
+		user   uuid.UUID
+		shares []ShareAssignment
+		code   apperrors.ErrorCode
+	}{
+		{"another user's hold", stranger, []ShareAssignment{
+			{Email: "a@example.com", SeatIDs: seats[:2]}, {Email: "b@example.com", SeatIDs: seats[2:]},
+		}, apperrors.CodeForbidden},
+		{"seat outside the hold", f.organizer.ID, []ShareAssignment{
+			{Email: "a@example.com", SeatIDs: seats[:2]}, {Email: "b@example.com", SeatIDs: []uuid.UUID{seats[2], seats[3], uuid.New()}},
+		}, apperrors.CodeBadRequest},
+		{"seat in two shares", f.organizer.ID, []ShareAssignment{
+			{Email: "a@example.com", SeatIDs: seats[:3]}, {Email: "b@example.com", SeatIDs: seats[2:]},
+		}, apperrors.CodeBadRequest},
+		{"invitee with two shares", f.organizer.ID, []ShareAssignment{
+			{Email: "a@example.com", SeatIDs: seats[:2]}, {Email: "a@example.com", SeatIDs: seats[2:]},
+		}, apperrors.CodeBadRequest},
+		{"seat left out", f.organizer.ID, []ShareAssignment{
+			{Email: "a@example.com", SeatIDs: seats[:2]}, {Email: "b@example.com", SeatIDs: seats[2:3]},
+		}, apperrors.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.svc.Split(context.Background(), tt.user, f.hold.ID, SplitHoldRequest{Shares: tt.shares})
+			if !apperrors.Is(err, tt.code) {
+				t.Errorf("Split = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestSplitSharesExpireBeforeTheHold(t *testing.T) {
+	f := newFixture(t)
+	res := f.split(t)
+
+	if res.Status != string(entity.GroupCheckoutOpen) || len(res.Shares) != 2 {
+		t.Fatalf("group = %s with %d shares", res.Status, len(res.Shares))
+	}
+	for _, share := range res.Shares {
+		if share.Amount != 20 {
+			t.Errorf("%s owes %.2f, want 20.00", share.InviteeEmail, share.Amount)
+		}
+		if want := f.hold.ExpiresAt.Add(-2 * time.Minute); !share.ExpiresAt.Equal(want) {
+			t.Errorf("%s share expires at %s, want %s", share.InviteeEmail, share.ExpiresAt, want)
+		}
+	}
+
+	if _, err := f.svc.Split(context.Background(), f.organizer.ID, f.hold.ID, SplitHoldRequest{}); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("second split = %v, want a conflict", err)
+	}
+}
+
+func TestAllSharesPaidConfirmsOneBooking(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	res := f.split(t)
+
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[0].PaymentReference, "txn-a"); err != nil {
+		t.Fatalf("MarkSharePaid: %v", err)
+	}
+	if len(f.bookings.bookings) != 0 {
+		t.Fatal("booking created before every share was paid")
+	}
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[1].PaymentReference, "txn-b"); err != nil {
+		t.Fatalf("MarkSharePaid: %v", err)
+	}
+	// The gateway may deliver the same payment twice
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[1].PaymentReference, "txn-b"); err != nil {
+		t.Fatalf("repeated MarkSharePaid: %v", err)
+	}
+
+	if len(f.bookings.bookings) != 1 {
+		t.Fatalf("created %d bookings, want 1", len(f.bookings.bookings))
+	}
+	booking := f.bookings.bookings[0]
+	if booking.NumTickets != 4 || booking.FinalAmount != 40 || booking.BookingStatus != entity.BookingConfirmed {
+		t.Errorf("booking = %d tickets, %.2f, %s", booking.NumTickets, booking.FinalAmount, booking.BookingStatus)
+	}
+	if len(f.payments.payments) != 2 {
+		t.Errorf("recorded %d payments, want one per share", len(f.payments.payments))
+	}
+
+	group := f.group(t, res.ID)
+	if group.Status != entity.GroupCheckoutCompleted || group.BookingID == nil || *group.BookingID != booking.ID {
+		t.Errorf("group = %s linked to %v", group.Status, group.BookingID)
+	}
+	if _, err := f.holds.GetByID(ctx, f.hold.ID); err == nil {
+		t.Error("hold kept after the group completed")
+	}
+}
+
+func TestFailedCompletionRollsBack(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	res := f.split(t)
+
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[0].PaymentReference, "txn-a"); err != nil {
+		t.Fatalf("MarkSharePaid: %v", err)
+	}
+	// Recording the second share's payment fails after the booking is
+	// written, so the whole completion is undone
+	f.payments.failures = 1
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[1].PaymentReference, "txn-b"); err == nil {
+		t.Fatal("MarkSharePaid succeeded with the completion failing")
+	}
+	group := f.group(t, res.ID)
+	if group.Status != entity.GroupCheckoutOpen || group.BookingID != nil {
+		t.Errorf("group = %s linked to %v after the failure, want OPEN without a booking", group.Status, group.BookingID)
+	}
+	if len(f.bookings.bookings) != 0 || len(f.payments.payments) != 0 || len(f.outbox.events) != 0 {
+		t.Errorf("%d bookings, %d payments and %d events kept after the failure",
+			len(f.bookings.bookings), len(f.payments.payments), len(f.outbox.events))
+	}
+
+	// The gateway redelivers the callback and the group completes
+	if err := f.svc.MarkSharePaid(ctx, res.Shares[1].PaymentReference, "txn-b"); err != nil {
+		t.Fatalf("redelivered MarkSharePaid: %v", err)
+	}
+	group = f.group(t, res.ID)
+	if group.Status != entity.GroupCheckoutCompleted || len(f.bookings.bookings) != 1 || len(f.payments.payments) != 2 {
+		t.Errorf("group = %s with %d bookings and %d payments, want COMPLETED with 1 and 2",
+			group.Status, len(f.bookings.bookings), len(f.payments.payments))
+	}
+}
+
+func TestFailingSubscriberDoesNotAffectTheBooking(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	var attempts int
+	delivered := make(chan events.BookingConfirmed, 1)
+	f.bus.Subscribe(events.BookingConfirmedEvent, "failing", func(context.Context, eventbus.Event) error {
+		attempts++
+		panic("subscriber bug")
+	})
+	f.bus.Subscribe(events.BookingConfirmedEvent, "healthy", func(_ context.Context, event eventbus.Event) error {
+		delivered <- event.(events.BookingConfirmed)
+		return nil
+	})
+	f.bus.Start()
+	t.Cleanup(func() { _ = f.bus.Stop(time.Second) })
+
+	res := f.split(t)
+	for i, share := range res.Shares {
+		if err := f.svc.MarkSharePaid(ctx, share.PaymentReference, fmt.Sprintf("txn-%d", i)); err != nil {
+			t.Fatalf("MarkSharePaid: %v", err)
+		}
+	}
+	relay := outbox.NewRelay(f.outbox, f.bus, outbox.RelayConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+
+	if len(f.bookings.bookings) != 1 || f.bookings.bookings[0].BookingStatus != entity.BookingConfirmed {
+		t.Fatal("booking not confirmed")
+	}
+	select {
+	case event := <-delivered:
+		if event.BookingID != f.bookings.bookings[0].ID {
+			t.Errorf("event for booking %s, want %s", event.BookingID, f.bookings.bookings[0].ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("healthy subscriber never received BookingConfirmed")
+	}
+	if attempts != 2 {
+		t.Errorf("failing subscriber attempted %d times, want 2", attempts)
+	}
+}
+
+func TestPartialPaymentExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lapsed shares go to the organizer", func(t *testing.T) {
+		f := newFixture(t)
+		res := f.split(t)
+		if err := f.svc.MarkSharePaid(ctx, res.Shares[0].PaymentReference, "txn-a"); err != nil {
+			t.Fatalf("MarkSharePaid: %v", err)
+		}
+
+		f.groups.lapse(res.ID)
+		if err := f.svc.ExpireLapsed(ctx); err != nil {
+			t.Fatalf("ExpireLapsed: %v", err)
+		}
+
+		group := f.group(t, res.ID)
+		if group.Status != entity.GroupCheckoutAwaitingOrganizer {
+			t.Fatalf("group = %s, want %s", group.Status, entity.GroupCheckoutAwaitingOrganizer)
+		}
+		statuses := shareStatuses(group)
+		if statuses["alice@example.com"] != entity.ShareStatusPaid || statuses["bob@example.com"] != entity.ShareStatusExpired {
+			t.Errorf("shares = %v", statuses)
+		}
+
+		// Bob pays after his window closed
+		err := f.svc.MarkSharePaid(ctx, res.Shares[1].PaymentReference, "txn-b")
+		if !apperrors.Is(err, apperrors.CodeBookingExpired) {
+			t.Errorf("late payment = %v, want %s", err, apperrors.CodeBookingExpired)
+		}
+		if len(f.bookings.bookings) != 0 {
+			t.Error("late payment created a booking")
+		}
+	})
+
+	t.Run("organizer releases the unpaid seats", func(t *testing.T) {
+		f := newFixture(t)
+		res := f.split(t)
+		_ = f.svc.MarkSharePaid(ctx, res.Shares[0].PaymentReference, "txn-a")
+		f.groups.lapse(res.ID)
+		_ = f.svc.ExpireLapsed(ctx)
+
+		out, err := f.svc.Resolve(ctx, f.organizer.ID, res.ID, ResolveRequest{Action: ActionReleaseUnpaid})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if out.Status != string(entity.GroupCheckoutCompleted) {
+			t.Errorf("group = %s, want %s", out.Status, entity.GroupCheckoutCompleted)
+		}
+		if len(f.bookings.bookings) != 1 {
+			t.Fatalf("created %d bookings, want 1", len(f.bookings.bookings))
+		}
+		booking := f.bookings.bookings[0]
+		if booking.NumTickets != 2 || booking.FinalAmount != 20 {
+			t.Errorf("booking = %d tickets for %.2f, want alice's 2 for 20.00", booking.NumTickets, booking.FinalAmount)
+		}
+		for _, seat := range f.bookings.seats[booking.ID] {
+			if seat.SeatID == res.Shares[1].SeatIDs[0] || seat.SeatID == res.Shares[1].SeatIDs[1] {
+				t.Errorf("booked bob's unpaid seat %s", seat.SeatID)
+			}
+		}
+		if statuses := shareStatuses(f.group(t, res.ID)); statuses["bob@example.com"] != entity.ShareStatusReleased {
+			t.Errorf("bob's share = %s, want %s", statuses["bob@example.com"], entity.ShareStatusReleased)
+		}
+	})
+
+	t.Run("organizer pays the remainder", func(t *testing.T) {
+		f := newFixture(t)
+		res := f.split(t)
+		_ = f.svc.MarkSharePaid(ctx, res.Shares[0].PaymentReference, "txn-a")
+		f.groups.lapse(res.ID)
+		_ = f.svc.ExpireLapsed(ctx)
+
+		out, err := f.svc.Resolve(ctx, f.organizer.ID, res.ID, ResolveRequest{Action: ActionPayRemainder})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if out.Status != string(entity.GroupCheckoutOpen) || len(out.Shares) != 3 {
+			t.Fatalf("group = %s with %d shares, want OPEN with the organizer's share", out.Status, len(out.Shares))
+		}
+		remainder := out.Shares[2]
+		if remainder.InviteeEmail != f.organizer.Email || remainder.Amount != 20 || len(remainder.SeatIDs) != 2 {
+			t.Errorf("remainder share = %+v", remainder)
+		}
+
+		if err := f.svc.MarkSharePaid(ctx, remainder.PaymentReference, "txn-o"); err != nil {
+			t.Fatalf("MarkSharePaid: %v", err)
+		}
+		if len(f.bookings.bookings) != 1 || f.bookings.bookings[0].NumTickets != 4 {
+			t.Fatalf("want one booking for all four seats, got %d bookings", len(f.bookings.bookings))
+		}
+	})
+
+	t.Run("nothing paid cancels the group", func(t *testing.T) {
+		f := newFixture(t)
+		res := f.split(t)
+		f.groups.lapse(res.ID)
+		_ = f.svc.ExpireLapsed(ctx)
+
+		out, err := f.svc.Resolve(ctx, f.organizer.ID, res.ID, ResolveRequest{Action: ActionReleaseUnpaid})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if out.Status != string(entity.GroupCheckoutCancelled) || len(f.bookings.bookings) != 0 {
+			t.Errorf("group = %s with %d bookings, want CANCELLED with none", out.Status, len(f.bookings.bookings))
+		}
+		if _, err := f.holds.GetByID(ctx, f.hold.ID); err == nil {
+			t.Error("hold kept after the group was cancelled")
+		}
+	})
+
+	t.Run("organizer never decides", func(t *testing.T) {
+		f := newFixture(t)
+		res := f.split(t)
+		f.groups.lapse(res.ID)
+		_ = f.svc.ExpireLapsed(ctx)
+
+		f.groups.mu.Lock()
+		f.groups.groups[res.ID].HoldExpiresAt = time.Now().Add(-time.Second)
+		f.groups.mu.Unlock()
+		if err := f.svc.ExpireLapsed(ctx); err != nil {
+			t.Fatalf("ExpireLapsed: %v", err)
+		}
+
+		if group := f.group(t, res.ID); group.Status != entity.GroupCheckoutCancelled {
+			t.Errorf("group = %s, want %s", group.Status, entity.GroupCheckoutCancelled)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package guestlookup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Claim starts moving a guest booking to the signed-in user's account by
+// emailing a code to the booking's guest email, so only whoever reads that
+// inbox can move it. References that are unknown or already belong to an
+// account fail alike and count against the throttle.
+func (s *Service) Claim(ctx context.Context, userID uuid.UUID, req ClaimRequest, clientIP string) (*ClaimResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if err := s.checkThrottle(ctx, reference, clientIP); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.claimable(ctx, reference, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return nil, apperrors.ErrInternal("failed to generate verification code")
+	}
+	if err := s.lookupRepo.SaveCode(ctx, claimKey(userID, reference), authinfra.HashToken(code), s.cfg.CodeTTL); err != nil {
+		return nil, err
+	}
+	s.sendClaimCode(booking, code)
+
+	return &ClaimResponse{
+		SentTo:        MaskEmail(booking.GuestEmail),
+		CodeExpiresIn: int64(s.cfg.CodeTTL.Seconds()),
+	}, nil
+}
+
+// VerifyClaim checks the code emailed by Claim and attaches the booking to
+// the user's account. The code only works for the user who asked for it.
+func (s *Service) VerifyClaim(ctx context.Context, userID uuid.UUID, req ClaimVerifyRequest, clientIP string) (*GuestBookingResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if err := s.checkThrottle(ctx, reference, clientIP); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.claimable(ctx, reference, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	key := claimKey(userID, reference)
+	codeHash, attempts, err := s.lookupRepo.UseCode(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !CodeMatches(codeHash, req.Code) || attempts > s.cfg.MaxCodeAttempts {
+		s.recordFailure(ctx, reference, clientIP)
+		return nil, apperrors.New(apperrors.CodeTokenInvalid, "invalid or expired verification code")
+	}
+
+	if err := s.bookingRepo.ClaimGuestBooking(ctx, booking.ID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.lookupRepo.DeleteCode(ctx, key); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to delete claim code", zap.Error(err))
+	}
+	s.resetFailures(ctx, reference)
+
+	s.logger.WithContext(ctx).Info("guest booking claimed",
+		zap.String("booking_reference", booking.BookingReference),
+		zap.String("user_id", userID.String()),
+	)
+
+	booking, err = s.bookingRepo.GetByIDWithDetails(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toGuestBookingResponse(booking), nil
+}
+
+// claimable returns the booking with the reference if it is a guest booking
+// not yet attached to an account
+func (s *Service) claimable(ctx context.Context, reference, clientIP string) (*entity.Booking, error) {
+	booking, err := s.bookingRepo.GetByReference(ctx, reference)
+	if err != nil && !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		return nil, err
+	}
+	if booking == nil || booking.UserID != nil || booking.GuestEmail == "" {
+		s.recordFailure(ctx, reference, clientIP)
+		return nil, errNotFound()
+	}
+	return booking, nil
+}
+
+func (s *Service) sendClaimCode(booking *entity.Booking, code string) {
+	body := fmt.Sprintf("Hi %s,\n\nYour code to add booking %s to your account is %s.\n\nIt expires in %d minutes. If you did not ask for it, do not share it with anyone and you can ignore this email.\n",
+		booking.GuestName, booking.BookingReference, code, int(s.cfg.CodeTTL.Minutes()))
+
+	if !s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{booking.GuestEmail},
+		Subject: "Confirm adding your booking to an account",
+		Body:    body,
+	}) {
+		s.logger.Warn("failed to queue booking claim code email",
+			zap.String("booking_reference", booking.BookingReference))
+	}
+}
+
+// MaskEmail hides most of an email's local part, e.g. j***@example.com
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// claimKey keeps claim codes apart from lookup codes and from other users'
+// claims of the same booking
+func claimKey(userID uuid.UUID, reference string) string {
+	return "claim:" + userID.String() + ":" + reference
+}
@@ -0,0 +1,91 @@
+package guestlookup
+
+import (
+	"context"
+	"testing"
+
+	"cinemaos-backend/internal/app/authinfra"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestClaimWithEmailedCode(t *testing.T) {
+	ctx := context.Background()
+	svc, lookups := newTestService(false)
+	booking := svc.bookingRepo.(*guestBookings).booking
+	userID := uuid.New()
+
+	res, err := svc.Claim(ctx, userID, ClaimRequest{Reference: testReference}, testIP)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if res.SentTo != "g***@example.com" {
+		t.Errorf("sent to %q, want the masked guest email", res.SentTo)
+	}
+	key := claimKey(userID, testReference)
+	if lookups.codes[key] == "" {
+		t.Fatal("no claim code saved")
+	}
+	if booking.UserID != nil {
+		t.Fatal("the booking moved before the code was confirmed")
+	}
+	// Stand in for the code in the email, which the test cannot read
+	lookups.codes[key] = authinfra.HashToken("123456")
+
+	verify := func(userID uuid.UUID, code string) error {
+		_, err := svc.VerifyClaim(ctx, userID, ClaimVerifyRequest{Reference: testReference, Code: code}, testIP)
+		return err
+	}
+	if err := verify(userID, "654321"); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Fatalf("wrong code = %v, want it rejected", err)
+	}
+	// The code is bound to the user who asked for it
+	if err := verify(uuid.New(), "123456"); !apperrors.Is(err, apperrors.CodeTokenInvalid) {
+		t.Fatalf("another user's code = %v, want it rejected", err)
+	}
+	if booking.UserID != nil {
+		t.Fatal("the booking moved on a rejected code")
+	}
+
+	if err := verify(userID, "123456"); err != nil {
+		t.Fatalf("VerifyClaim: %v", err)
+	}
+	if booking.UserID == nil || *booking.UserID != userID {
+		t.Fatalf("booking user = %v, want %s", booking.UserID, userID)
+	}
+	if booking.GuestEmail != testEmail {
+		t.Error("the guest fields were not kept")
+	}
+
+	// Once attached the booking cannot be claimed again
+	if _, err := svc.Claim(ctx, uuid.New(), ClaimRequest{Reference: testReference}, testIP); !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		t.Errorf("Claim of a claimed booking = %v, want not found", err)
+	}
+}
+
+func TestClaimUnknownReferenceCountsAsFailure(t *testing.T) {
+	svc, lookups := newTestService(false)
+
+	_, err := svc.Claim(context.Background(), uuid.New(), ClaimRequest{Reference: "BK-20261016-ZZZZ"}, testIP)
+	if !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		t.Fatalf("Claim = %v, want not found", err)
+	}
+	if lookups.failures[ipKey(testIP)] != 1 {
+		t.Errorf("failures = %v, want the attempt throttled like a lookup", lookups.failures)
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"guest@example.com": "g***@example.com",
+		"g@example.com":     "g***@example.com",
+		"@example.com":      "***",
+		"not-an-email":      "***",
+	}
+	for email, want := range tests {
+		if got := MaskEmail(email); got != want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package guestlookup
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+)
+
+// LookupRequest represents a guest's request to find their booking
+type LookupRequest struct {
+	Reference string `json:"reference" validate:"required,max=32"`
+	Email     string `json:"email" validate:"required,email"`
+}
+
+// VerifyRequest represents a guest's verification code for a lookup
+type VerifyRequest struct {
+	Reference string `json:"reference" validate:"required,max=32"`
+	Email     string `json:"email" validate:"required,email"`
+	Code      string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// LookupResponse is the outcome of a lookup. With email verification on,
+// the booking is left out until the emailed code is verified.
+type LookupResponse struct {
+	VerificationRequired bool                  `json:"verification_required"`
+	CodeExpiresIn        int64                 `json:"code_expires_in,omitempty"` // seconds
+	Booking              *GuestBookingResponse `json:"booking,omitempty"`
+}
+
+// GuestBookingResponse represents a booking as shown to its guest
+type GuestBookingResponse struct {
+	Reference     string    `json:"reference"`
+	Status        string    `json:"status"`
+	PaymentStatus string    `json:"payment_status"`
+	GuestName     string    `json:"guest_name"`
+	MovieTitle    string    `json:"movie_title"`
+	CinemaName    string    `json:"cinema_name"`
+	ScreenName    string    `json:"screen_name"`
+	StartsAt      time.Time `json:"starts_at"`
+	Seats         []string  `json:"seats"`
+	NumTickets    int       `json:"num_tickets"`
+	TotalAmount   float64   `json:"total_amount"`
+	// AssistiveDevices are handed out by staff at check-in
+	AssistiveDevices []entity.BookingDevice `json:"assistive_devices,omitempty"`
+}
+
+// ClaimRequest represents a signed-in user's request to add a guest
+// booking to their account
+type ClaimRequest struct {
+	Reference string `json:"reference" validate:"required,max=32"`
+}
+
+// ClaimVerifyRequest represents the code emailed to the guest for a claim
+type ClaimVerifyRequest struct {
+	Reference string `json:"reference" validate:"required,max=32"`
+	Code      string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ClaimResponse tells where the claim code was sent
+type ClaimResponse struct {
+	SentTo        string `json:"sent_to"`         // masked guest email
+	CodeExpiresIn int64  `json:"code_expires_in"` // seconds
+}
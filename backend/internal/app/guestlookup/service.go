@@ -0,0 +1,282 @@
+package guestlookup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Service lets guests without an account find their booking by reference
+// and email. Every failed attempt counts against both the reference and
+// the client IP, and wrong references and wrong emails fail alike.
+type Service struct {
+	bookingRepo repository.BookingRepository
+	lookupRepo  repository.GuestLookupRepository
+	dispatcher  *async.Dispatcher
+	cfg         config.GuestLookupConfig
+	logger      *logger.Logger
+}
+
+// NewService creates a new guest lookup service
+func NewService(
+	bookingRepo repository.BookingRepository,
+	lookupRepo repository.GuestLookupRepository,
+	dispatcher *async.Dispatcher,
+	cfg config.GuestLookupConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		bookingRepo: bookingRepo,
+		lookupRepo:  lookupRepo,
+		dispatcher:  dispatcher,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// errNotFound is returned for every lookup that does not match a booking,
+// whichever of the reference and the email was wrong
+func errNotFound() error {
+	return apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+}
+
+// Lookup finds a guest booking. With email verification on, a code is sent
+// to the guest's email instead and the booking is returned by Verify.
+func (s *Service) Lookup(ctx context.Context, req LookupRequest, clientIP string) (*LookupResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if err := s.checkThrottle(ctx, reference, clientIP); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.match(ctx, reference, req.Email, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.cfg.VerifyEmail {
+		s.resetFailures(ctx, reference)
+		return &LookupResponse{Booking: toGuestBookingResponse(booking)}, nil
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return nil, apperrors.ErrInternal("failed to generate verification code")
+	}
+	if err := s.lookupRepo.SaveCode(ctx, reference, authinfra.HashToken(code), s.cfg.CodeTTL); err != nil {
+		return nil, err
+	}
+	s.sendCode(booking, code)
+
+	return &LookupResponse{
+		VerificationRequired: true,
+		CodeExpiresIn:        int64(s.cfg.CodeTTL.Seconds()),
+	}, nil
+}
+
+// Verify checks the code emailed by Lookup and returns the booking. A code
+// is single-use and stops working after MaxCodeAttempts wrong guesses.
+func (s *Service) Verify(ctx context.Context, req VerifyRequest, clientIP string) (*LookupResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if err := s.checkThrottle(ctx, reference, clientIP); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.match(ctx, reference, req.Email, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	codeHash, attempts, err := s.lookupRepo.UseCode(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if !CodeMatches(codeHash, req.Code) || attempts > s.cfg.MaxCodeAttempts {
+		s.recordFailure(ctx, reference, clientIP)
+		return nil, apperrors.New(apperrors.CodeTokenInvalid, "invalid or expired verification code")
+	}
+
+	if err := s.lookupRepo.DeleteCode(ctx, reference); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to delete verification code", zap.Error(err))
+	}
+	s.resetFailures(ctx, reference)
+
+	return &LookupResponse{Booking: toGuestBookingResponse(booking)}, nil
+}
+
+// match returns the guest booking with the reference if the email matches
+// its guest email. The email is compared in constant time, and also when
+// no booking has the reference, so the response time does not tell which
+// of the two was wrong.
+func (s *Service) match(ctx context.Context, reference, email, clientIP string) (*entity.Booking, error) {
+	booking, err := s.bookingRepo.GetByReference(ctx, reference)
+	if err != nil && !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		return nil, err
+	}
+
+	var guestEmail string
+	if booking != nil {
+		guestEmail = booking.GuestEmail
+	}
+	if !EmailMatches(guestEmail, email) {
+		s.recordFailure(ctx, reference, clientIP)
+		return nil, errNotFound()
+	}
+
+	return s.bookingRepo.GetByIDWithDetails(ctx, booking.ID)
+}
+
+// checkThrottle rejects the lookup while the reference or the client IP is
+// blocked
+func (s *Service) checkThrottle(ctx context.Context, reference, clientIP string) error {
+	blocked, err := s.lookupRepo.BlockedFor(ctx, referenceKey(reference), ipKey(clientIP))
+	if err != nil {
+		return err
+	}
+	if blocked > 0 {
+		retryAfter := int64((blocked + time.Second - 1) / time.Second)
+		return apperrors.New(apperrors.CodeTooManyRequests, "too many failed lookups, try again later").
+			WithDetails(map[string]int64{"retry_after": retryAfter})
+	}
+	return nil
+}
+
+// recordFailure counts a failed lookup against the reference and the client
+// IP, blocking each once it is past its free attempts
+func (s *Service) recordFailure(ctx context.Context, reference, clientIP string) {
+	log := s.logger.WithContext(ctx)
+
+	for _, key := range []string{referenceKey(reference), ipKey(clientIP)} {
+		failures, err := s.lookupRepo.RecordFailure(ctx, key, s.cfg.FailureWindow)
+		if err != nil {
+			log.Error("failed to record guest lookup failure", zap.Error(err))
+			continue
+		}
+
+		delay := BlockDelay(failures, s.cfg.FreeAttempts, s.cfg.BaseDelay, s.cfg.MaxDelay)
+		if delay == 0 {
+			continue
+		}
+		if err := s.lookupRepo.Block(ctx, key, delay); err != nil {
+			log.Error("failed to block guest lookups", zap.Error(err))
+		}
+		log.Warn("repeated failed guest booking lookups",
+			zap.String("throttle_key", key),
+			zap.String("client_ip", clientIP),
+			zap.Int("failures", failures),
+			zap.Duration("blocked_for", delay),
+		)
+	}
+}
+
+func (s *Service) resetFailures(ctx context.Context, reference string) {
+	if err := s.lookupRepo.ResetFailures(ctx, referenceKey(reference)); err != nil {
+		s.logger.WithContext(ctx).Warn("failed to reset guest lookup failures", zap.Error(err))
+	}
+}
+
+func (s *Service) sendCode(booking *entity.Booking, code string) {
+	body := fmt.Sprintf("Hi %s,\n\nYour code to view booking %s is %s.\n\nIt expires in %d minutes. If you did not ask for it, you can ignore this email.\n",
+		booking.GuestName, booking.BookingReference, code, int(s.cfg.CodeTTL.Minutes()))
+
+	if !s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{booking.GuestEmail},
+		Subject: "Your booking verification code",
+		Body:    body,
+	}) {
+		s.logger.Warn("failed to queue guest lookup code email",
+			zap.String("booking_reference", booking.BookingReference))
+	}
+}
+
+// BlockDelay returns how long to block lookups after the given number of
+// failures: nothing within the free attempts, then baseDelay doubling with
+// each further failure up to maxDelay
+func BlockDelay(failures, freeAttempts int, baseDelay, maxDelay time.Duration) time.Duration {
+	over := failures - freeAttempts
+	if over <= 0 {
+		return 0
+	}
+
+	delay := baseDelay
+	for i := 1; i < over && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// EmailMatches compares a booking's guest email with a supplied one in
+// constant time. Case and surrounding spaces are ignored; an empty guest
+// email, as on bookings made with an account, never matches.
+func EmailMatches(guestEmail, supplied string) bool {
+	stored := sha256.Sum256([]byte(normalizeEmail(guestEmail)))
+	given := sha256.Sum256([]byte(normalizeEmail(supplied)))
+	equal := subtle.ConstantTimeCompare(stored[:], given[:]) == 1
+	return equal && guestEmail != ""
+}
+
+// CodeMatches compares a stored code hash with a supplied code in constant
+// time. An empty hash, for a missing or expired code, never matches.
+func CodeMatches(codeHash, code string) bool {
+	given := authinfra.HashToken(code)
+	equal := subtle.ConstantTimeCompare([]byte(codeHash), []byte(given)) == 1
+	return equal && codeHash != ""
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// generateCode returns a random 6-digit code
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func referenceKey(reference string) string {
+	return "ref:" + reference
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func toGuestBookingResponse(booking *entity.Booking) *GuestBookingResponse {
+	showtime := booking.Showtime
+	seats := make([]string, len(booking.BookingSeats))
+	for i, bs := range booking.BookingSeats {
+		seats[i] = bs.Seat.SeatLabel()
+	}
+
+	return &GuestBookingResponse{
+		Reference:        booking.BookingReference,
+		Status:           string(booking.BookingStatus),
+		PaymentStatus:    string(booking.PaymentStatus),
+		GuestName:        booking.GuestName,
+		MovieTitle:       showtime.Movie.Title,
+		CinemaName:       showtime.Cinema.Name,
+		ScreenName:       showtime.Screen.Name,
+		StartsAt:         showtime.StartsAt(showtime.Cinema.Location()),
+		Seats:            seats,
+		NumTickets:       booking.NumTickets,
+		TotalAmount:      booking.FinalAmount,
+		AssistiveDevices: booking.Devices,
+	}
+}
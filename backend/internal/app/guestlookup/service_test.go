@@ -0,0 +1,303 @@
+package guestlookup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memLookups is a GuestLookupRepository kept in memory. Windows never run
+// out; their expiry is covered by the Redis repository tests.
+type memLookups struct {
+	mu       sync.Mutex
+	failures map[string]int
+	blocks   map[string]time.Duration
+	codes    map[string]string
+	attempts map[string]int
+}
+
+func newMemLookups() *memLookups {
+	return &memLookups{
+		failures: make(map[string]int),
+		blocks:   make(map[string]time.Duration),
+		codes:    make(map[string]string),
+		attempts: make(map[string]int),
+	}
+}
+
+func (m *memLookups) BlockedFor(_ context.Context, keys ...string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var longest time.Duration
+	for _, key := range keys {
+		longest = max(longest, m.blocks[key])
+	}
+	return longest, nil
+}
+
+func (m *memLookups) RecordFailure(_ context.Context, key string, _ time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[key]++
+	return m.failures[key], nil
+}
+
+func (m *memLookups) Block(_ context.Context, key string, d time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocks[key] = d
+	return nil
+}
+
+func (m *memLookups) ResetFailures(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.failures, key)
+	return nil
+}
+
+func (m *memLookups) SaveCode(_ context.Context, reference, codeHash string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes[reference] = codeHash
+	m.attempts[reference] = 0
+	return nil
+}
+
+func (m *memLookups) UseCode(_ context.Context, reference string) (string, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash, ok := m.codes[reference]
+	if !ok {
+		return "", 0, nil
+	}
+	m.attempts[reference]++
+	return hash, m.attempts[reference], nil
+}
+
+func (m *memLookups) DeleteCode(_ context.Context, reference string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.codes, reference)
+	return nil
+}
+
+// guestBookings holds one guest booking
+type guestBookings struct {
+	repository.BookingRepository
+	booking *entity.Booking
+}
+
+func (g *guestBookings) GetByReference(_ context.Context, ref string) (*entity.Booking, error) {
+	if ref != g.booking.BookingReference {
+		return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+	return g.booking, nil
+}
+
+func (g *guestBookings) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	return g.booking, nil
+}
+
+func (g *guestBookings) ClaimGuestBooking(_ context.Context, id, userID uuid.UUID) error {
+	if g.booking.ID != id || g.booking.UserID != nil {
+		return apperrors.ErrConflict("booking already belongs to an account")
+	}
+	g.booking.UserID = &userID
+	return nil
+}
+
+const (
+	testReference = "BK-20261016-ABCD"
+	testEmail     = "guest@example.com"
+	testIP        = "203.0.113.7"
+)
+
+func newTestService(verifyEmail bool) (*Service, *memLookups) {
+	lookups := newMemLookups()
+	booking := &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: testReference,
+		GuestEmail:       testEmail,
+		GuestName:        "Gus",
+		BookingStatus:    entity.BookingConfirmed,
+		Showtime:         entity.Showtime{ShowDate: time.Now(), StartTime: "20:00", Movie: entity.Movie{Title: "Dune"}},
+	}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	svc := NewService(&guestBookings{booking: booking}, lookups, async.NewDispatcher(1, 10, nil, log), config.GuestLookupConfig{
+		VerifyEmail:     verifyEmail,
+		CodeTTL:         10 * time.Minute,
+		MaxCodeAttempts: 3,
+		FailureWindow:   time.Hour,
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        8 * time.Second,
+	}, log)
+	return svc, lookups
+}
+
+func TestEmailMatches(t *testing.T) {
+	tests := []struct {
+		stored, supplied string
+		want             bool
+	}{
+		{"guest@example.com", "guest@example.com", true},
+		{"guest@example.com", " Guest@Example.COM ", true},
+		{"guest@example.com", "guest@example.co", false},
+		{"guest@example.com", "guest@example.comm", false},
+		{"guest@example.com", "", false},
+		{"", "", false}, // bookings made with an account have no guest email
+	}
+	for _, tt := range tests {
+		if got := EmailMatches(tt.stored, tt.supplied); got != tt.want {
+			t.Errorf("EmailMatches(%q, %q) = %v, want %v", tt.stored, tt.supplied, got, tt.want)
+		}
+	}
+}
+
+func TestCodeMatches(t *testing.T) {
+	hash := authinfra.HashToken("042137")
+	if !CodeMatches(hash, "042137") {
+		t.Error("correct code rejected")
+	}
+	if CodeMatches(hash, "42137") || CodeMatches(hash, "042138") {
+		t.Error("wrong code accepted")
+	}
+	if CodeMatches("", "") {
+		t.Error("missing code matched an empty guess")
+	}
+}
+
+func TestBlockDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 0}, {3, 0}, {4, time.Second}, {5, 2 * time.Second}, {6, 4 * time.Second}, {7, 8 * time.Second}, {20, 8 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := BlockDelay(tt.failures, 3, time.Second, 8*time.Second); got != tt.want {
+			t.Errorf("BlockDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestWrongReferenceAndWrongEmailFailAlike(t *testing.T) {
+	ctx := context.Background()
+	svc, lookups := newTestService(false)
+
+	_, wrongRef := svc.Lookup(ctx, LookupRequest{Reference: "BK-20261016-ZZZZ", Email: testEmail}, testIP)
+	_, wrongEmail := svc.Lookup(ctx, LookupRequest{Reference: testReference, Email: "other@example.com"}, testIP)
+
+	var a, b *apperrors.AppError
+	if !errors.As(wrongRef, &a) || !errors.As(wrongEmail, &b) {
+		t.Fatalf("errors = %v, %v", wrongRef, wrongEmail)
+	}
+	if a.Code != b.Code || a.Message != b.Message || a.Code != apperrors.CodeBookingNotFound {
+		t.Errorf("wrong reference gives %s %q, wrong email gives %s %q", a.Code, a.Message, b.Code, b.Message)
+	}
+	if lookups.failures[ipKey(testIP)] != 2 || lookups.failures[referenceKey(testReference)] != 1 {
+		t.Errorf("failures = %v", lookups.failures)
+	}
+}
+
+func TestRepeatedFailuresEscalate(t *testing.T) {
+	ctx := context.Background()
+	svc, lookups := newTestService(false)
+
+	// Each attempt targets another reference, so only the IP adds up
+	for i, want := range []time.Duration{0, 0, 0, time.Second, 2 * time.Second, 4 * time.Second} {
+		lookups.blocks = make(map[string]time.Duration) // the block ran out
+		_, _ = svc.Lookup(ctx, LookupRequest{Reference: uuid.NewString(), Email: testEmail}, testIP)
+		if got := lookups.blocks[ipKey(testIP)]; got != want {
+			t.Errorf("after failure %d the IP is blocked for %s, want %s", i+1, got, want)
+		}
+	}
+
+	// While blocked even the right details are refused
+	_, err := svc.Lookup(ctx, LookupRequest{Reference: testReference, Email: testEmail}, testIP)
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.CodeTooManyRequests {
+		t.Fatalf("Lookup while blocked = %v, want TOO_MANY_REQUESTS", err)
+	}
+	if details, _ := appErr.Details.(map[string]int64); details["retry_after"] != 4 {
+		t.Errorf("retry_after = %v, want 4", appErr.Details)
+	}
+
+	// Another client is not affected
+	if _, err := svc.Lookup(ctx, LookupRequest{Reference: testReference, Email: testEmail}, "198.51.100.1"); err != nil {
+		t.Errorf("Lookup from another IP: %v", err)
+	}
+}
+
+func TestVerifiedCodeGatesDetails(t *testing.T) {
+	ctx := context.Background()
+	svc, lookups := newTestService(true)
+
+	res, err := svc.Lookup(ctx, LookupRequest{Reference: testReference, Email: testEmail}, testIP)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if !res.VerificationRequired || res.Booking != nil {
+		t.Fatalf("Lookup returned the booking before verification: %+v", res)
+	}
+	if lookups.codes[testReference] == "" {
+		t.Fatal("no code saved")
+	}
+	// Stand in for the code in the email, which the test cannot read
+	lookups.codes[testReference] = authinfra.HashToken("123456")
+
+	verify := func(code, email string) (*LookupResponse, error) {
+		return svc.Verify(ctx, VerifyRequest{Reference: testReference, Email: email, Code: code}, testIP)
+	}
+
+	if res, err := verify("654321", testEmail); err == nil || res != nil {
+		t.Fatal("wrong code accepted")
+	}
+	if _, err := verify("123456", "other@example.com"); !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		t.Fatalf("right code with a wrong email = %v, want not found", err)
+	}
+
+	res, err = verify("123456", testEmail)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if res.Booking == nil || res.Booking.Reference != testReference || res.Booking.MovieTitle != "Dune" {
+		t.Fatalf("Verify returned %+v", res.Booking)
+	}
+
+	if _, err := verify("123456", testEmail); err == nil {
+		t.Error("a used code was accepted again")
+	}
+}
+
+func TestCodeStopsWorkingAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	svc, lookups := newTestService(true)
+
+	if _, err := svc.Lookup(ctx, LookupRequest{Reference: testReference, Email: testEmail}, testIP); err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	lookups.codes[testReference] = authinfra.HashToken("123456")
+
+	for i := 0; i < 3; i++ {
+		_, _ = svc.Verify(ctx, VerifyRequest{Reference: testReference, Email: testEmail, Code: "000000"}, testIP)
+		lookups.blocks = make(map[string]time.Duration)
+	}
+	if _, err := svc.Verify(ctx, VerifyRequest{Reference: testReference, Email: testEmail, Code: "123456"}, testIP); err == nil {
+		t.Error("right code accepted after the attempts ran out")
+	}
+}
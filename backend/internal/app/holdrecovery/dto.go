@@ -0,0 +1,15 @@
+package holdrecovery
+
+import (
+	"cinemaos-backend/internal/app/booking"
+)
+
+// ReholdRequest represents a one-click re-hold from a recovery email
+type ReholdRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ReholdResponse is returned when seats are held again from a recovery email
+type ReholdResponse struct {
+	Hold *booking.HoldResponse `json:"hold"`
+}
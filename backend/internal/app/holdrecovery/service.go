@@ -0,0 +1,379 @@
+package holdrecovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// sweepBatchSize limits how many expired holds are processed per sweep
+	sweepBatchSize = 100
+	// bookingLookback limits how many of the user's bookings for the
+	// showtime are checked before sending
+	bookingLookback = 20
+)
+
+// Service sends "your seats were released" emails for expired holds and
+// lets users hold comparable seats again from the email
+type Service struct {
+	holdRepo       repository.SeatHoldRepository
+	recoveryRepo   repository.HoldRecoveryRepository
+	showtimeRepo   repository.ShowtimeRepository
+	bookingRepo    repository.BookingRepository
+	groupRepo      repository.GroupCheckoutRepository
+	userRepo       repository.UserRepository
+	bookingService *booking.Service
+	dispatcher     *async.Dispatcher
+	bus            *eventbus.Bus
+	cfg            config.BookingConfig
+	logger         *logger.Logger
+	frontendURL    string
+}
+
+// NewService creates a new hold recovery service
+func NewService(
+	holdRepo repository.SeatHoldRepository,
+	recoveryRepo repository.HoldRecoveryRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	bookingRepo repository.BookingRepository,
+	groupRepo repository.GroupCheckoutRepository,
+	userRepo repository.UserRepository,
+	bookingService *booking.Service,
+	dispatcher *async.Dispatcher,
+	bus *eventbus.Bus,
+	cfg config.BookingConfig,
+	logger *logger.Logger,
+	frontendURL string,
+) *Service {
+	return &Service{
+		holdRepo:       holdRepo,
+		recoveryRepo:   recoveryRepo,
+		showtimeRepo:   showtimeRepo,
+		bookingRepo:    bookingRepo,
+		groupRepo:      groupRepo,
+		userRepo:       userRepo,
+		bookingService: bookingService,
+		dispatcher:     dispatcher,
+		bus:            bus,
+		cfg:            cfg,
+		logger:         logger,
+		frontendURL:    frontendURL,
+	}
+}
+
+// SweepExpired claims holds that expired at least the recovery delay ago,
+// records their expiry and sends a recovery email where one is due
+func (s *Service) SweepExpired(ctx context.Context) error {
+	log := s.logger.WithContext(ctx)
+
+	holds, err := s.holdRepo.ClaimExpired(ctx, time.Now().Add(-s.cfg.RecoveryDelay), sweepBatchSize)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, hold := range holds {
+		s.bus.Publish(ctx, events.HoldExpired{
+			HoldID:     hold.ID,
+			UserID:     hold.UserID,
+			ShowtimeID: hold.ShowtimeID,
+			NumSeats:   len(hold.Seats),
+			ExpiredAt:  hold.ExpiresAt,
+		})
+
+		ok, err := s.recover(ctx, hold)
+		if err != nil {
+			log.Error("failed to send hold recovery email", zap.String("hold_id", hold.ID), zap.Error(err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	if len(holds) > 0 {
+		log.Info("hold expiry sweep finished",
+			zap.Int("expired", len(holds)),
+			zap.Int("recovery_emails", sent),
+		)
+	}
+	return nil
+}
+
+// recover sends the recovery email for an expired hold unless the user opted
+// out, the showtime can no longer be booked, the user booked it in the
+// meantime or already got an email for it. It returns true if an email was
+// queued.
+func (s *Service) recover(ctx context.Context, hold *entity.SeatHold) (bool, error) {
+	if hold.UserID == uuid.Nil || len(hold.Seats) == 0 {
+		return false, nil
+	}
+	// Seats picked for a group booking can only be booked with the group
+	if hold.GroupBookingID != nil {
+		return false, nil
+	}
+
+	// Group checkout organizers are told about lapsed holds by group checkout
+	grouped, err := s.groupRepo.ExistsForHold(ctx, hold.ID)
+	if err != nil || grouped {
+		return false, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, hold.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !user.IsActive || !user.HoldRecoveryEmails {
+		return false, nil
+	}
+
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, hold.ShowtimeID)
+	if err != nil {
+		return false, err
+	}
+	if showtime.Status != entity.ShowtimeScheduled || showtime.Visibility == entity.VisibilityPrivate {
+		return false, nil
+	}
+	if showtime.MovieUnavailable() || showtime.ScreenUnavailable() {
+		return false, nil
+	}
+	if showtime.SalesStateAt(time.Now(), showtime.Cinema.Location()) == entity.SalesClosed {
+		return false, nil
+	}
+
+	booked, err := s.bookedSince(ctx, hold)
+	if err != nil || booked {
+		return false, err
+	}
+
+	seatTypes := make([]entity.SeatType, len(hold.Seats))
+	for i, seat := range hold.Seats {
+		seatTypes[i] = seat.SeatType
+	}
+
+	suggested, err := s.bookingService.SuggestSeats(ctx, showtime.ID, seatTypes)
+	if err != nil {
+		// The email is still worth sending without the re-hold link
+		s.logger.WithContext(ctx).Warn("failed to suggest seats for hold recovery",
+			zap.String("hold_id", hold.ID), zap.Error(err))
+		suggested = nil
+	}
+
+	token, err := authinfra.GenerateRandomToken(32)
+	if err != nil {
+		return false, apperrors.ErrInternal("failed to generate token")
+	}
+
+	recovery := &entity.HoldRecovery{
+		UserID:        user.ID,
+		ShowtimeID:    showtime.ID,
+		HoldID:        hold.ID,
+		TokenHash:     authinfra.HashToken(token),
+		SeatTypes:     seatTypeNames(seatTypes),
+		ReholdOffered: len(suggested) > 0,
+		SentAt:        time.Now(),
+	}
+	created, err := s.recoveryRepo.Create(ctx, recovery)
+	if err != nil || !created {
+		return false, err
+	}
+
+	if !s.sendRecoveryEmail(user, showtime, recovery, token) {
+		return false, fmt.Errorf("email queue full")
+	}
+
+	s.bus.Publish(ctx, events.HoldRecoverySent{
+		RecoveryID:    recovery.ID,
+		HoldID:        hold.ID,
+		UserID:        user.ID,
+		ShowtimeID:    showtime.ID,
+		ReholdOffered: recovery.ReholdOffered,
+		SentAt:        recovery.SentAt,
+	})
+	return true, nil
+}
+
+// bookedSince returns true if the user has a live booking for the hold's
+// showtime made after the hold was created
+func (s *Service) bookedSince(ctx context.Context, hold *entity.SeatHold) (bool, error) {
+	bookings, _, err := s.bookingRepo.List(ctx, repository.BookingFilter{
+		UserID:     &hold.UserID,
+		ShowtimeID: &hold.ShowtimeID,
+		DateFrom:   &hold.CreatedAt,
+	}, 0, bookingLookback)
+	if err != nil {
+		return false, err
+	}
+
+	for _, b := range bookings {
+		switch b.BookingStatus {
+		case entity.BookingPending, entity.BookingConfirmed, entity.BookingCompleted:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Rehold holds seats comparable to the expired hold of a recovery email
+func (s *Service) Rehold(ctx context.Context, userID uuid.UUID, token string) (*ReholdResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	recovery, err := s.recoveryRepo.GetByTokenHash(ctx, authinfra.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if recovery.UserID != userID {
+		return nil, apperrors.ErrForbidden("recovery link belongs to another user")
+	}
+	if recovery.IsConverted() {
+		return nil, apperrors.ErrConflict("showtime has already been booked")
+	}
+	if recovery.ReheldAt != nil {
+		return nil, apperrors.ErrConflict("seats have already been held from this link")
+	}
+
+	seatTypes := make([]entity.SeatType, len(recovery.SeatTypes))
+	for i, name := range recovery.SeatTypes {
+		seatTypes[i] = entity.SeatType(name)
+	}
+
+	seatIDs, err := s.bookingService.SuggestSeats(ctx, recovery.ShowtimeID, seatTypes)
+	if err != nil {
+		return nil, err
+	}
+	if len(seatIDs) == 0 {
+		return nil, apperrors.New(apperrors.CodeSeatNotAvailable, "comparable seats are no longer available")
+	}
+
+	hold, err := s.bookingService.HoldSeats(ctx, userID, booking.HoldSeatsRequest{
+		ShowtimeID: recovery.ShowtimeID,
+		SeatIDs:    seatIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recovery.ReheldAt = &now
+	recovery.ReholdHoldID = &hold.HoldID
+	if err := s.recoveryRepo.Update(ctx, recovery); err != nil {
+		// The hold stands; only the analytics record is behind
+		log.Error("failed to record hold recovery re-hold", zap.String("recovery_id", recovery.ID.String()), zap.Error(err))
+	}
+
+	s.bus.Publish(ctx, events.HoldRecoveryReheld{
+		RecoveryID: recovery.ID,
+		HoldID:     hold.HoldID,
+		UserID:     userID,
+		ShowtimeID: recovery.ShowtimeID,
+		ReheldAt:   now,
+	})
+
+	log.Info("seats re-held from recovery email",
+		zap.String("recovery_id", recovery.ID.String()),
+		zap.String("hold_id", hold.HoldID),
+	)
+	return &ReholdResponse{Hold: hold}, nil
+}
+
+// RegisterSubscribers subscribes the service's side effects to domain events
+func (s *Service) RegisterSubscribers(bus *eventbus.Bus) {
+	bus.Subscribe(events.BookingConfirmedEvent, "holdrecovery.conversion", s.onBookingConfirmed)
+}
+
+// onBookingConfirmed marks the user's recovery email for the showtime as
+// converted
+func (s *Service) onBookingConfirmed(ctx context.Context, event eventbus.Event) error {
+	confirmed := event.(events.BookingConfirmed)
+	if confirmed.UserID == nil {
+		return nil
+	}
+
+	recovery, err := s.recoveryRepo.GetByUserAndShowtime(ctx, *confirmed.UserID, confirmed.ShowtimeID)
+	if err != nil {
+		return err
+	}
+	if recovery == nil || recovery.IsConverted() || confirmed.ConfirmedAt.Before(recovery.SentAt) {
+		return nil
+	}
+
+	recovery.ConvertedAt = &confirmed.ConfirmedAt
+	recovery.ConvertedBookingID = &confirmed.BookingID
+	if err := s.recoveryRepo.Update(ctx, recovery); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, events.HoldRecoveryConverted{
+		RecoveryID:  recovery.ID,
+		BookingID:   confirmed.BookingID,
+		UserID:      recovery.UserID,
+		ShowtimeID:  recovery.ShowtimeID,
+		Reheld:      recovery.ReheldAt != nil,
+		ConvertedAt: confirmed.ConfirmedAt,
+	})
+	return nil
+}
+
+func (s *Service) sendRecoveryEmail(user *entity.User, showtime *entity.Showtime, recovery *entity.HoldRecovery, token string) bool {
+	startsAt := showtime.StartsAt(showtime.Cinema.Location())
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\nThe seats you were holding for %s have been released.\n\n", user.FirstName, showtime.Movie.Title)
+	fmt.Fprintf(&body, "Cinema: %s\nShowtime: %s\nSeats: %s\n\n",
+		showtime.Cinema.Name, startsAt.Format("Mon, 02 Jan 2006 15:04"), describeSeatTypes(recovery.SeatTypes))
+	fmt.Fprintf(&body, "Pick your seats again: %s/showtimes/%s/seats?recovery=%s\n", s.frontendURL, showtime.ID, token)
+	if recovery.ReholdOffered {
+		fmt.Fprintf(&body, "\nOr hold comparable seats in one click: %s/holds/recover?token=%s\n", s.frontendURL, token)
+	}
+
+	return s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{user.Email},
+		Subject: "Your seats for " + showtime.Movie.Title + " were released",
+		Body:    body.String(),
+	})
+}
+
+func seatTypeNames(seatTypes []entity.SeatType) []string {
+	names := make([]string, len(seatTypes))
+	for i, seatType := range seatTypes {
+		names[i] = string(seatType)
+	}
+	return names
+}
+
+// describeSeatTypes summarizes seat types as e.g. "2 x STANDARD, 1 x VIP"
+func describeSeatTypes(seatTypes []string) string {
+	counts := make(map[string]int)
+	for _, seatType := range seatTypes {
+		counts[seatType]++
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%d x %s", counts[name], name)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,28 @@
+package loyalty
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BalanceResponse is a user's loyalty points balance
+type BalanceResponse struct {
+	PointsBalance int `json:"points_balance"`
+	// Value is what the balance takes off a booking when redeemed
+	Value          float64    `json:"value"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+	// ExpiresAt is when the balance expires unless points are earned or
+	// redeemed before then
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// TransactionResponse is one change of a loyalty balance
+type TransactionResponse struct {
+	ID          uuid.UUID  `json:"id" swaggertype:"string" format:"uuid"`
+	BookingID   *uuid.UUID `json:"booking_id,omitempty" swaggertype:"string" format:"uuid"`
+	Type        string     `json:"type"`   // EARN, REDEEM, EXPIRE, REFUND or REVERSE
+	Points      int        `json:"points"` // credits positive, debits negative
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
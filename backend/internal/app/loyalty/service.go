@@ -0,0 +1,135 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// expiryBatchSize caps the balances expired per transaction
+const expiryBatchSize = 200
+
+// Service credits loyalty points for confirmed bookings, shows users their
+// balance and expires the balances of inactive accounts. Points are
+// redeemed when a booking is confirmed from a hold.
+type Service struct {
+	repo   repository.LoyaltyRepository
+	cfg    config.LoyaltyConfig
+	logger *logger.Logger
+}
+
+// NewService creates a new loyalty service
+func NewService(repo repository.LoyaltyRepository, cfg config.LoyaltyConfig, logger *logger.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// GetBalance returns the user's points balance; a user who never earned
+// points has a balance of zero
+func (s *Service) GetBalance(ctx context.Context, userID uuid.UUID) (*BalanceResponse, error) {
+	account, err := s.repo.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return &BalanceResponse{}, nil
+	}
+
+	res := &BalanceResponse{
+		PointsBalance:  account.PointsBalance,
+		Value:          entity.LoyaltyPointsValue(account.PointsBalance),
+		LastActivityAt: &account.LastActivityAt,
+	}
+	if account.PointsBalance > 0 {
+		expiresAt := account.ExpiresAt(s.cfg.InactivityExpiry)
+		res.ExpiresAt = &expiresAt
+	}
+	return res, nil
+}
+
+// ListTransactions returns the user's loyalty transactions, newest first
+func (s *Service) ListTransactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]*TransactionResponse, int64, error) {
+	offset := (page - 1) * limit
+	transactions, total, err := s.repo.ListTransactions(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*TransactionResponse, len(transactions))
+	for i, t := range transactions {
+		responses[i] = &TransactionResponse{
+			ID:          t.ID,
+			BookingID:   t.BookingID,
+			Type:        string(t.Type),
+			Points:      t.Points,
+			Description: t.Description,
+			CreatedAt:   t.CreatedAt,
+		}
+	}
+	return responses, total, nil
+}
+
+// ExpireInactive empties the balances of accounts with no earn or redeem
+// within the inactivity period
+func (s *Service) ExpireInactive(ctx context.Context) error {
+	before := time.Now().Add(-s.cfg.InactivityExpiry)
+	expired := 0
+	for {
+		n, err := s.repo.ExpireInactive(ctx, before, expiryBatchSize)
+		if err != nil {
+			return err
+		}
+		expired += n
+		if n < expiryBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		s.logger.WithContext(ctx).Info("inactive loyalty balances expired", zap.Int("accounts", expired))
+	}
+	return nil
+}
+
+// RegisterSubscribers subscribes the service's side effects to domain events
+func (s *Service) RegisterSubscribers(bus *eventbus.Bus) {
+	bus.Subscribe(events.BookingConfirmedEvent, "loyalty.earn", s.onBookingConfirmed)
+}
+
+// onBookingConfirmed credits the points a confirmed booking earned. Guest
+// bookings earn nothing.
+func (s *Service) onBookingConfirmed(ctx context.Context, event eventbus.Event) error {
+	confirmed := event.(events.BookingConfirmed)
+	if confirmed.UserID == nil {
+		return nil
+	}
+
+	points := entity.LoyaltyPointsEarned(confirmed.FinalAmount)
+	if points == 0 {
+		return nil
+	}
+
+	added, err := s.repo.AddPoints(ctx, *confirmed.UserID, confirmed.BookingID, points, "Booking "+confirmed.BookingReference)
+	if err != nil {
+		return err
+	}
+	if added {
+		s.logger.WithContext(ctx).Info("loyalty points earned",
+			zap.String("booking_reference", confirmed.BookingReference),
+			zap.Int("points", points),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memLoyalty keeps balances in memory; earned remembers the bookings that
+// earned points, inactive how many accounts are left to expire
+type memLoyalty struct {
+	repository.LoyaltyRepository
+	accounts map[uuid.UUID]*entity.LoyaltyAccount
+	earned   map[uuid.UUID]bool
+	inactive int
+	sweeps   int
+}
+
+func newMemLoyalty() *memLoyalty {
+	return &memLoyalty{accounts: map[uuid.UUID]*entity.LoyaltyAccount{}, earned: map[uuid.UUID]bool{}}
+}
+
+func (m *memLoyalty) GetBalance(_ context.Context, userID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	return m.accounts[userID], nil
+}
+
+func (m *memLoyalty) AddPoints(_ context.Context, userID, bookingID uuid.UUID, points int, _ string) (bool, error) {
+	if m.earned[bookingID] {
+		return false, nil
+	}
+	m.earned[bookingID] = true
+	account, ok := m.accounts[userID]
+	if !ok {
+		account = &entity.LoyaltyAccount{UserID: userID}
+		m.accounts[userID] = account
+	}
+	account.PointsBalance += points
+	account.LastActivityAt = time.Now()
+	return true, nil
+}
+
+func (m *memLoyalty) ExpireInactive(_ context.Context, _ time.Time, limit int) (int, error) {
+	m.sweeps++
+	n := min(m.inactive, limit)
+	m.inactive -= n
+	return n, nil
+}
+
+func newTestService(repo *memLoyalty) *Service {
+	return NewService(repo, config.LoyaltyConfig{InactivityExpiry: 365 * 24 * time.Hour}, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestEarnPoints(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemLoyalty()
+	s := newTestService(repo)
+	userID := uuid.New()
+
+	confirmed := events.BookingConfirmed{BookingID: uuid.New(), BookingReference: "BK-1", UserID: &userID, FinalAmount: 43.47}
+	for range 2 {
+		if err := s.onBookingConfirmed(ctx, confirmed); err != nil {
+			t.Fatalf("onBookingConfirmed: %v", err)
+		}
+	}
+	// Guests and free bookings earn nothing
+	if err := s.onBookingConfirmed(ctx, events.BookingConfirmed{BookingID: uuid.New(), FinalAmount: 50}); err != nil {
+		t.Fatalf("guest booking: %v", err)
+	}
+	if err := s.onBookingConfirmed(ctx, events.BookingConfirmed{BookingID: uuid.New(), UserID: &userID}); err != nil {
+		t.Fatalf("free booking: %v", err)
+	}
+
+	balance, err := s.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if balance.PointsBalance != 434 || balance.Value != 4.34 {
+		t.Errorf("balance = %d points worth %v, want 434 worth 4.34", balance.PointsBalance, balance.Value)
+	}
+	if balance.ExpiresAt == nil || !balance.ExpiresAt.Equal(balance.LastActivityAt.Add(365*24*time.Hour)) {
+		t.Errorf("expires at %v, want a year after %v", balance.ExpiresAt, balance.LastActivityAt)
+	}
+	if len(repo.earned) != 1 {
+		t.Errorf("%d bookings earned points, want 1", len(repo.earned))
+	}
+}
+
+func TestBalanceWithoutAccount(t *testing.T) {
+	balance, err := newTestService(newMemLoyalty()).GetBalance(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if balance.PointsBalance != 0 || balance.ExpiresAt != nil || balance.LastActivityAt != nil {
+		t.Errorf("balance = %+v, want zero with no expiry", balance)
+	}
+}
+
+func TestExpireInactiveInBatches(t *testing.T) {
+	repo := newMemLoyalty()
+	repo.inactive = 2*expiryBatchSize + 1
+	if err := newTestService(repo).ExpireInactive(context.Background()); err != nil {
+		t.Fatalf("ExpireInactive: %v", err)
+	}
+	if repo.inactive != 0 || repo.sweeps != 3 {
+		t.Errorf("%d accounts left after %d batches, want 0 after 3", repo.inactive, repo.sweeps)
+	}
+}
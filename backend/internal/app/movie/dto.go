@@ -3,13 +3,15 @@ package movie
 import (
 	"time"
 
+	"cinemaos-backend/internal/app/entity"
+
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 // MovieResponse represents a movie in responses
 type MovieResponse struct {
-	ID              uuid.UUID      `json:"id"`
+	ID              uuid.UUID      `json:"id" swaggertype:"string" format:"uuid"`
 	TMDBId          *int           `json:"tmdb_id,omitempty"`
 	Title           string         `json:"title"`
 	OriginalTitle   *string        `json:"original_title,omitempty"`
@@ -19,6 +21,8 @@ type MovieResponse struct {
 	ReleaseDate     string         `json:"release_date"`
 	Rating          *string        `json:"rating,omitempty"`
 	ImdbRating      *float64       `json:"imdb_rating,omitempty"`
+	UserRating      *float64       `json:"user_rating,omitempty"` // average review rating, 1-5
+	ReviewCount     int            `json:"review_count"`
 	Language        *string        `json:"language,omitempty"`
 	Genres          pq.StringArray `json:"genres"`
 	Director        *string        `json:"director,omitempty"`
@@ -30,18 +34,81 @@ type MovieResponse struct {
 	IsNowShowing    bool           `json:"is_now_showing"`
 	IsComingSoon    bool           `json:"is_coming_soon"`
 	PopularityScore float64        `json:"popularity_score"`
+	IsActive        bool           `json:"is_active"`
 	CreatedAt       time.Time      `json:"created_at"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty"` // set in admin listings of deleted movies
+	// Media is the published media gallery in order, on movie details only
+	Media []MediaResponse `json:"media,omitempty"`
+}
+
+// PublicMovieResponse is the movie shape served to customers and
+// anonymous callers, without catalogue internals such as the TMDB ID and
+// popularity score
+type PublicMovieResponse struct {
+	ID            uuid.UUID      `json:"id" swaggertype:"string" format:"uuid"`
+	Title         string         `json:"title"`
+	OriginalTitle *string        `json:"original_title,omitempty"`
+	Slug          string         `json:"slug"`
+	Description   *string        `json:"description,omitempty"`
+	Duration      int            `json:"duration"` // in minutes
+	ReleaseDate   string         `json:"release_date"`
+	Rating        *string        `json:"rating,omitempty"`
+	ImdbRating    *float64       `json:"imdb_rating,omitempty"`
+	UserRating    *float64       `json:"user_rating,omitempty"` // average review rating, 1-5
+	ReviewCount   int            `json:"review_count"`
+	Language      *string        `json:"language,omitempty"`
+	Genres        pq.StringArray `json:"genres"`
+	Director      *string        `json:"director,omitempty"`
+	Cast          pq.StringArray `json:"cast"`
+	PosterURL     *string        `json:"poster_url,omitempty"`
+	BackdropURL   *string        `json:"backdrop_url,omitempty"`
+	TrailerURL    *string        `json:"trailer_url,omitempty"`
+	Format        string         `json:"format"`
+	IsNowShowing  bool           `json:"is_now_showing"`
+	IsComingSoon  bool           `json:"is_coming_soon"`
+	// Media is the published media gallery in order, on movie details only
+	Media []MediaResponse `json:"media,omitempty"`
+}
+
+// Public returns the public variant of the movie
+func (r *MovieResponse) Public() any {
+	return &PublicMovieResponse{
+		ID:            r.ID,
+		Title:         r.Title,
+		OriginalTitle: r.OriginalTitle,
+		Slug:          r.Slug,
+		Description:   r.Description,
+		Duration:      r.Duration,
+		ReleaseDate:   r.ReleaseDate,
+		Rating:        r.Rating,
+		ImdbRating:    r.ImdbRating,
+		UserRating:    r.UserRating,
+		ReviewCount:   r.ReviewCount,
+		Language:      r.Language,
+		Genres:        r.Genres,
+		Director:      r.Director,
+		Cast:          r.Cast,
+		PosterURL:     r.PosterURL,
+		BackdropURL:   r.BackdropURL,
+		TrailerURL:    r.TrailerURL,
+		Format:        r.Format,
+		IsNowShowing:  r.IsNowShowing,
+		IsComingSoon:  r.IsComingSoon,
+		Media:         r.Media,
+	}
 }
 
 // CreateMovieRequest input for creating a movie
 type CreateMovieRequest struct {
-	TMDBId        *int     `json:"tmdb_id,omitempty"`
-	Title         string   `json:"title" validate:"required"`
+	// TMDBId fills title, description, genres, cast, director, images,
+	// duration and release date from TMDB where left blank
+	TMDBId        *int     `json:"tmdb_id,omitempty" validate:"omitempty,gt=0"`
+	Title         string   `json:"title" validate:"required_without=TMDBId"`
 	OriginalTitle *string  `json:"original_title,omitempty"`
 	Slug          string   `json:"slug" validate:"required,slug"`
 	Description   *string  `json:"description,omitempty"`
-	Duration      int      `json:"duration" validate:"required,gt=0"`
-	ReleaseDate   string   `json:"release_date" validate:"required"` // YYYY-MM-DD
+	Duration      int      `json:"duration" validate:"gte=0,required_without=TMDBId"`
+	ReleaseDate   string   `json:"release_date" validate:"required_without=TMDBId"` // YYYY-MM-DD
 	Rating        *string  `json:"rating,omitempty"`
 	ImdbRating    *float64 `json:"imdb_rating,omitempty"`
 	Language      *string  `json:"language,omitempty"`
@@ -80,6 +147,8 @@ type UpdateMovieRequest struct {
 
 // MovieListParams params for listing movies
 type MovieListParams struct {
+	// Search matches titles, descriptions, directors and cast, best matches
+	// first; quotes, "or" and a leading "-" work as in web searches
 	Search       string `form:"search"`
 	Genre        string `form:"genre"`
 	Format       string `form:"format"`
@@ -88,3 +157,114 @@ type MovieListParams struct {
 	Page         int    `form:"page,default=1"`
 	Limit        int    `form:"limit,default=20"`
 }
+
+// AdminMovieListParams represents query parameters for the admin movie
+// listing, which can include inactive and deleted movies
+type AdminMovieListParams struct {
+	MovieListParams
+	IsActive       *bool `form:"is_active"`
+	IncludeDeleted bool  `form:"include_deleted"`
+}
+
+// NowShowingParams represents query parameters for the now-showing listing
+type NowShowingParams struct {
+	// Personalized lists only the movies matching the signed-in user's
+	// favorite genres, formats or cinemas
+	Personalized bool `form:"personalized"`
+}
+
+// DeleteMovieParams represents query parameters for deleting a movie
+type DeleteMovieParams struct {
+	// Force cancels the movie's upcoming showtimes instead of refusing
+	Force bool `form:"force"`
+}
+
+// DeleteMovieResponse reports what deleting a movie cancelled
+type DeleteMovieResponse struct {
+	CancelledShowtimes int `json:"cancelled_showtimes"`
+}
+
+// MediaResponse represents a movie media asset in responses
+type MediaResponse struct {
+	ID        uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	Kind      string    `json:"kind"`
+	URL       string    `json:"url"`
+	Title     *string   `json:"title,omitempty"`
+	SortOrder int       `json:"sort_order"`
+	Published bool      `json:"published"`
+}
+
+// CreateMediaRequest adds an asset to a movie's media gallery. Trailers and
+// teasers are YouTube or Vimeo links; stills and alternative posters are
+// https image URLs.
+type CreateMediaRequest struct {
+	Kind      string  `json:"kind" validate:"required,oneof=TRAILER TEASER STILL POSTER_ALT"`
+	URL       string  `json:"url" validate:"required,url,max=2048"`
+	Title     *string `json:"title,omitempty" validate:"omitempty,max=200"`
+	Published bool    `json:"published"`
+}
+
+// UpdateMediaRequest updates an asset. Omitted fields are left unchanged.
+type UpdateMediaRequest struct {
+	URL       *string `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Title     *string `json:"title,omitempty" validate:"omitempty,max=200"`
+	Published *bool   `json:"published,omitempty"`
+}
+
+// CreateReviewRequest rates a movie from 1 to 5 stars with an optional
+// written review
+type CreateReviewRequest struct {
+	Rating int     `json:"rating" validate:"required,min=1,max=5"`
+	Title  *string `json:"title,omitempty" validate:"omitempty,max=200"`
+	Body   *string `json:"body,omitempty" validate:"omitempty,max=5000"`
+}
+
+// ReviewResponse represents a movie review in responses. Authors are shown
+// by first name only.
+type ReviewResponse struct {
+	ID         uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	MovieID    uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	Author     string    `json:"author"`
+	Rating     int       `json:"rating"`
+	Title      *string   `json:"title,omitempty"`
+	Body       *string   `json:"body,omitempty"`
+	IsVerified bool      `json:"is_verified"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReorderMediaRequest lists every asset of a movie in the new order
+type ReorderMediaRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,max=50" swaggertype:"array,string" format:"uuid"`
+}
+
+func toMediaResponses(media []*entity.MovieMedia) []MediaResponse {
+	responses := make([]MediaResponse, 0, len(media))
+	for _, m := range media {
+		responses = append(responses, toMediaResponse(m))
+	}
+	return responses
+}
+
+func toMediaResponse(m *entity.MovieMedia) MediaResponse {
+	return MediaResponse{
+		ID:        m.ID,
+		Kind:      string(m.Kind),
+		URL:       m.URL,
+		Title:     m.Title,
+		SortOrder: m.SortOrder,
+		Published: m.Published,
+	}
+}
+
+func toReviewResponse(r *entity.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:         r.ID,
+		MovieID:    r.MovieID,
+		Author:     r.User.FirstName,
+		Rating:     r.Rating,
+		Title:      r.Title,
+		Body:       r.Body,
+		IsVerified: r.IsVerified,
+		CreatedAt:  r.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package movie
+
+import (
+	"slices"
+	"testing"
+
+	"cinemaos-backend/internal/pkg/response"
+)
+
+func TestPublicMovieHidesInternalFields(t *testing.T) {
+	full := response.JSONFields(MovieResponse{})
+	public := response.JSONFields(PublicMovieResponse{})
+
+	for _, internal := range []string{"tmdb_id", "popularity_score", "created_at"} {
+		if !slices.Contains(full, internal) {
+			t.Errorf("admin shape lost %s", internal)
+		}
+		if slices.Contains(public, internal) {
+			t.Errorf("public shape exposes %s", internal)
+		}
+	}
+	for _, field := range public {
+		if !slices.Contains(full, field) {
+			t.Errorf("public field %s is not in the admin shape", field)
+		}
+	}
+	if got := len(response.JSONFields((&MovieResponse{}).Public())); got != len(public) {
+		t.Errorf("Public() returns %d fields, want %d", got, len(public))
+	}
+}
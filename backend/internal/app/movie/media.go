@@ -0,0 +1,205 @@
+package movie
+
+import (
+	"context"
+	"fmt"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ListMedia returns every asset of a movie's media gallery in order,
+// published or not
+func (s *Service) ListMedia(ctx context.Context, movieID uuid.UUID) ([]MediaResponse, error) {
+	if _, err := s.movieRepo.GetByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	media, err := s.mediaRepo.ListByMovie(ctx, movieID, false)
+	if err != nil {
+		return nil, err
+	}
+	return toMediaResponses(media), nil
+}
+
+// CreateMedia adds an asset at the end of a movie's media gallery
+func (s *Service) CreateMedia(ctx context.Context, movieID uuid.UUID, req CreateMediaRequest) (*MediaResponse, error) {
+	if _, err := s.movieRepo.GetByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	count, err := s.mediaRepo.Count(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= entity.MaxMediaPerMovie {
+		return nil, apperrors.ErrValidation(fmt.Sprintf("a movie can have at most %d media assets", entity.MaxMediaPerMovie))
+	}
+
+	kind := entity.MediaKind(req.Kind)
+	mediaURL, err := entity.NormalizeMediaURL(kind, req.URL)
+	if err != nil {
+		return nil, apperrors.ErrValidation(err.Error())
+	}
+
+	media := &entity.MovieMedia{
+		MovieID:   movieID,
+		Kind:      kind,
+		URL:       mediaURL,
+		Title:     req.Title,
+		Published: req.Published,
+	}
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, err
+	}
+	if kind == entity.MediaTrailer {
+		if err := s.syncTrailerURL(ctx, movieID, true); err != nil {
+			return nil, err
+		}
+	}
+
+	res := toMediaResponse(media)
+	return &res, nil
+}
+
+// UpdateMedia changes an asset's URL, title or published flag
+func (s *Service) UpdateMedia(ctx context.Context, movieID, id uuid.UUID, req UpdateMediaRequest) (*MediaResponse, error) {
+	media, err := s.mediaRepo.GetByID(ctx, movieID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		mediaURL, err := entity.NormalizeMediaURL(media.Kind, *req.URL)
+		if err != nil {
+			return nil, apperrors.ErrValidation(err.Error())
+		}
+		media.URL = mediaURL
+	}
+	if req.Title != nil {
+		media.Title = req.Title
+	}
+	if req.Published != nil {
+		media.Published = *req.Published
+	}
+
+	if err := s.mediaRepo.Update(ctx, media); err != nil {
+		return nil, err
+	}
+	if media.Kind == entity.MediaTrailer {
+		if err := s.syncTrailerURL(ctx, movieID, true); err != nil {
+			return nil, err
+		}
+	}
+
+	res := toMediaResponse(media)
+	return &res, nil
+}
+
+// DeleteMedia removes an asset from a movie's media gallery
+func (s *Service) DeleteMedia(ctx context.Context, movieID, id uuid.UUID) error {
+	media, err := s.mediaRepo.GetByID(ctx, movieID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.mediaRepo.Delete(ctx, movieID, id); err != nil {
+		return err
+	}
+	if media.Kind == entity.MediaTrailer {
+		return s.syncTrailerURL(ctx, movieID, true)
+	}
+	return nil
+}
+
+// ReorderMedia puts a movie's media gallery in the given order. The list
+// must name every asset of the movie exactly once.
+func (s *Service) ReorderMedia(ctx context.Context, movieID uuid.UUID, req ReorderMediaRequest) ([]MediaResponse, error) {
+	media, err := s.mediaRepo.ListByMovie(ctx, movieID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uuid.UUID]bool, len(media))
+	for _, m := range media {
+		existing[m.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if !existing[id] {
+			return nil, apperrors.ErrValidation("media " + id.String() + " does not belong to this movie")
+		}
+		if seen[id] {
+			return nil, apperrors.ErrValidation("media " + id.String() + " is listed more than once")
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(existing) {
+		return nil, apperrors.ErrValidation("ids must list every media asset of the movie")
+	}
+
+	if err := s.mediaRepo.Reorder(ctx, movieID, req.IDs); err != nil {
+		return nil, err
+	}
+	if err := s.syncTrailerURL(ctx, movieID, false); err != nil {
+		return nil, err
+	}
+	return s.ListMedia(ctx, movieID)
+}
+
+// syncTrailerURL keeps the legacy trailer_url field on the first published
+// trailer of the gallery for older clients. Without one the field is
+// cleared only when a trailer was just changed, so a trailer_url set
+// directly on a movie without gallery trailers is left alone.
+func (s *Service) syncTrailerURL(ctx context.Context, movieID uuid.UUID, trailerChanged bool) error {
+	published, err := s.mediaRepo.ListByMovie(ctx, movieID, true)
+	if err != nil {
+		return err
+	}
+
+	var trailerURL *string
+	if trailer := entity.FirstPublishedTrailer(published); trailer != nil {
+		trailerURL = &trailer.URL
+	} else if !trailerChanged {
+		return nil
+	}
+
+	movie, err := s.movieRepo.GetByID(ctx, movieID)
+	if err != nil {
+		return err
+	}
+	if sameURL(movie.TrailerURL, trailerURL) {
+		return nil
+	}
+
+	before := *movie
+	movie.TrailerURL = trailerURL
+	if err := s.movieRepo.Update(ctx, movie); err != nil {
+		return err
+	}
+	s.changeLog.Record(ctx, entity.ChangeEntityMovie, movie.ID, before, *movie)
+
+	s.logger.Info("movie trailer_url synced from media gallery",
+		zap.String("movie_id", movieID.String()),
+		zap.Bool("cleared", trailerURL == nil),
+	)
+	return nil
+}
+
+// publishedMedia returns a movie's published gallery for its details
+func (s *Service) publishedMedia(ctx context.Context, movieID uuid.UUID) ([]MediaResponse, error) {
+	media, err := s.mediaRepo.ListByMovie(ctx, movieID, true)
+	if err != nil {
+		return nil, err
+	}
+	return toMediaResponses(media), nil
+}
+
+func sameURL(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,333 @@
+package movie
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memMovies holds a single movie
+type memMovies struct {
+	repository.MovieRepository
+	movie   *entity.Movie
+	updates int
+}
+
+func (m *memMovies) GetByID(_ context.Context, id uuid.UUID) (*entity.Movie, error) {
+	if m.movie.ID != id {
+		return nil, apperrors.ErrNotFound("movie")
+	}
+	copied := *m.movie
+	return &copied, nil
+}
+
+func (m *memMovies) Create(_ context.Context, movie *entity.Movie) error {
+	movie.ID = uuid.New()
+	copied := *movie
+	m.movie = &copied
+	return nil
+}
+
+func (m *memMovies) UpdateReviewStats(_ context.Context, id uuid.UUID, rating *float64, count int64) error {
+	m.movie.UserRating = rating
+	m.movie.ReviewCount = int(count)
+	return nil
+}
+
+func (m *memMovies) Update(_ context.Context, movie *entity.Movie) error {
+	copied := *movie
+	m.movie = &copied
+	m.updates++
+	return nil
+}
+
+// memMedia is a MovieMediaRepository kept in memory, in sort order
+type memMedia struct {
+	media []*entity.MovieMedia
+}
+
+func (m *memMedia) Create(_ context.Context, media *entity.MovieMedia) error {
+	media.ID = uuid.New()
+	media.SortOrder = len(m.media)
+	copied := *media
+	m.media = append(m.media, &copied)
+	return nil
+}
+
+func (m *memMedia) GetByID(_ context.Context, movieID, id uuid.UUID) (*entity.MovieMedia, error) {
+	for _, media := range m.media {
+		if media.MovieID == movieID && media.ID == id {
+			copied := *media
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("movie media")
+}
+
+func (m *memMedia) Update(_ context.Context, media *entity.MovieMedia) error {
+	for i, stored := range m.media {
+		if stored.ID == media.ID {
+			copied := *media
+			m.media[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (m *memMedia) Delete(_ context.Context, _, id uuid.UUID) error {
+	m.media = slices.DeleteFunc(m.media, func(media *entity.MovieMedia) bool { return media.ID == id })
+	return nil
+}
+
+func (m *memMedia) ListByMovie(_ context.Context, movieID uuid.UUID, publishedOnly bool) ([]*entity.MovieMedia, error) {
+	var media []*entity.MovieMedia
+	for _, stored := range m.media {
+		if stored.MovieID == movieID && (stored.Published || !publishedOnly) {
+			copied := *stored
+			media = append(media, &copied)
+		}
+	}
+	return media, nil
+}
+
+func (m *memMedia) Count(context.Context, uuid.UUID) (int64, error) {
+	return int64(len(m.media)), nil
+}
+
+func (m *memMedia) Reorder(_ context.Context, _ uuid.UUID, ids []uuid.UUID) error {
+	ordered := make([]*entity.MovieMedia, 0, len(ids))
+	for i, id := range ids {
+		for _, media := range m.media {
+			if media.ID == id {
+				media.SortOrder = i
+				ordered = append(ordered, media)
+			}
+		}
+	}
+	m.media = ordered
+	return nil
+}
+
+// memChanges counts recorded movie changes
+type memChanges struct {
+	repository.ChangeRecordRepository
+	records int
+}
+
+func (m *memChanges) Create(context.Context, *entity.ChangeRecord) error {
+	m.records++
+	return nil
+}
+
+type mediaFixture struct {
+	svc     *Service
+	movies  *memMovies
+	media   *memMedia
+	changes *memChanges
+}
+
+func newMediaFixture() *mediaFixture {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	f := &mediaFixture{
+		movies:  &memMovies{movie: &entity.Movie{ID: uuid.New(), Title: "Dune: Part Two", IsActive: true}},
+		media:   &memMedia{},
+		changes: &memChanges{},
+	}
+	f.svc = NewService(f.movies, f.media, nil, nil, nil, nil, changelog.NewService(f.changes, log), nil, nil, nil, log)
+	return f
+}
+
+func (f *mediaFixture) add(t *testing.T, kind entity.MediaKind, url string, published bool) uuid.UUID {
+	t.Helper()
+	res, err := f.svc.CreateMedia(context.Background(), f.movies.movie.ID, CreateMediaRequest{
+		Kind: string(kind), URL: url, Published: published,
+	})
+	if err != nil {
+		t.Fatalf("add %s %s: %v", kind, url, err)
+	}
+	return res.ID
+}
+
+func (f *mediaFixture) setPublished(t *testing.T, id uuid.UUID, published bool) {
+	t.Helper()
+	if _, err := f.svc.UpdateMedia(context.Background(), f.movies.movie.ID, id, UpdateMediaRequest{Published: &published}); err != nil {
+		t.Fatalf("update %s: %v", id, err)
+	}
+}
+
+func (f *mediaFixture) trailerURL() string {
+	if f.movies.movie.TrailerURL == nil {
+		return ""
+	}
+	return *f.movies.movie.TrailerURL
+}
+
+const (
+	trailerA = "https://www.youtube.com/watch?v=Way9Dexny3w"
+	trailerB = "https://vimeo.com/76979871"
+	still    = "https://cdn.example.com/stills/dune-01.jpg"
+)
+
+func TestMediaReorder(t *testing.T) {
+	ctx := context.Background()
+	f := newMediaFixture()
+	movieID := f.movies.movie.ID
+	a := f.add(t, entity.MediaTrailer, trailerA, true)
+	b := f.add(t, entity.MediaTrailer, trailerB, true)
+	c := f.add(t, entity.MediaStill, still, false)
+
+	media, err := f.svc.ReorderMedia(ctx, movieID, ReorderMediaRequest{IDs: []uuid.UUID{c, b, a}})
+	if err != nil {
+		t.Fatalf("ReorderMedia: %v", err)
+	}
+	var order []uuid.UUID
+	for i, m := range media {
+		order = append(order, m.ID)
+		if m.SortOrder != i {
+			t.Errorf("%s: sort order %d, want %d", m.ID, m.SortOrder, i)
+		}
+	}
+	if !slices.Equal(order, []uuid.UUID{c, b, a}) {
+		t.Errorf("order = %v, want %v", order, []uuid.UUID{c, b, a})
+	}
+
+	for name, ids := range map[string][]uuid.UUID{
+		"missing an asset": {a, b},
+		"listed twice":     {a, b, b},
+		"foreign asset":    {a, b, uuid.New()},
+	} {
+		if _, err := f.svc.ReorderMedia(ctx, movieID, ReorderMediaRequest{IDs: ids}); !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%s: ReorderMedia = %v, want a validation error", name, err)
+		}
+	}
+
+	// The public gallery is the published assets in the new order
+	details, err := f.svc.GetByID(ctx, movieID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if len(details.Media) != 2 || details.Media[0].ID != b || details.Media[1].ID != a {
+		t.Errorf("details media = %+v, want B then A", details.Media)
+	}
+}
+
+func TestMediaPublishToggle(t *testing.T) {
+	ctx := context.Background()
+	f := newMediaFixture()
+	id := f.add(t, entity.MediaStill, still, false)
+
+	published := func() int {
+		details, err := f.svc.GetByID(ctx, f.movies.movie.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		return len(details.Media)
+	}
+	if n := published(); n != 0 {
+		t.Fatalf("%d assets public before publishing", n)
+	}
+	f.setPublished(t, id, true)
+	if n := published(); n != 1 {
+		t.Fatalf("%d assets public after publishing, want 1", n)
+	}
+	f.setPublished(t, id, false)
+	if n := published(); n != 0 {
+		t.Errorf("%d assets public after unpublishing", n)
+	}
+
+	// Admins still see it
+	all, err := f.svc.ListMedia(ctx, f.movies.movie.ID)
+	if err != nil || len(all) != 1 {
+		t.Errorf("ListMedia = %v, %v, want the unpublished asset", all, err)
+	}
+}
+
+func TestMediaTrailerURLSync(t *testing.T) {
+	ctx := context.Background()
+	f := newMediaFixture()
+	movieID := f.movies.movie.ID
+
+	a := f.add(t, entity.MediaTrailer, "https://youtu.be/Way9Dexny3w", false)
+	if got := f.trailerURL(); got != "" {
+		t.Fatalf("trailer_url = %q with no published trailer", got)
+	}
+
+	f.setPublished(t, a, true)
+	if got := f.trailerURL(); got != trailerA {
+		t.Fatalf("trailer_url = %q, want the canonical %q", got, trailerA)
+	}
+
+	b := f.add(t, entity.MediaTrailer, trailerB, true)
+	if got := f.trailerURL(); got != trailerA {
+		t.Errorf("trailer_url = %q after adding a second trailer, want the first", got)
+	}
+	if _, err := f.svc.ReorderMedia(ctx, movieID, ReorderMediaRequest{IDs: []uuid.UUID{b, a}}); err != nil {
+		t.Fatalf("ReorderMedia: %v", err)
+	}
+	if got := f.trailerURL(); got != trailerB {
+		t.Errorf("trailer_url = %q after reordering, want %q", got, trailerB)
+	}
+
+	if err := f.svc.DeleteMedia(ctx, movieID, b); err != nil {
+		t.Fatalf("DeleteMedia: %v", err)
+	}
+	if got := f.trailerURL(); got != trailerA {
+		t.Errorf("trailer_url = %q after deleting the first trailer, want %q", got, trailerA)
+	}
+
+	f.setPublished(t, a, false)
+	if got := f.trailerURL(); got != "" {
+		t.Errorf("trailer_url = %q with every trailer unpublished, want it cleared", got)
+	}
+	if f.changes.records != f.movies.updates {
+		t.Errorf("%d trailer_url changes, %d recorded in the change history", f.movies.updates, f.changes.records)
+	}
+}
+
+func TestMediaKeepsADirectTrailerURL(t *testing.T) {
+	ctx := context.Background()
+	f := newMediaFixture()
+	direct := "https://example.com/trailer.mp4"
+	f.movies.movie.TrailerURL = &direct
+
+	// Galleries without trailers leave trailer_url alone
+	id := f.add(t, entity.MediaStill, still, true)
+	if _, err := f.svc.ReorderMedia(ctx, f.movies.movie.ID, ReorderMediaRequest{IDs: []uuid.UUID{id}}); err != nil {
+		t.Fatalf("ReorderMedia: %v", err)
+	}
+	if got := f.trailerURL(); got != direct {
+		t.Errorf("trailer_url = %q, want the direct one kept", got)
+	}
+	if f.movies.updates != 0 {
+		t.Errorf("movie updated %d times", f.movies.updates)
+	}
+}
+
+func TestMediaLimitAndValidation(t *testing.T) {
+	ctx := context.Background()
+	f := newMediaFixture()
+	movieID := f.movies.movie.ID
+
+	if _, err := f.svc.CreateMedia(ctx, movieID, CreateMediaRequest{Kind: "TRAILER", URL: "https://example.com/x.mp4"}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("non-YouTube trailer: %v, want a validation error", err)
+	}
+	if _, err := f.svc.CreateMedia(ctx, movieID, CreateMediaRequest{Kind: "STILL", URL: "https://cdn.example.com/x.gif"}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("GIF still: %v, want a validation error", err)
+	}
+
+	for i := 0; i < entity.MaxMediaPerMovie; i++ {
+		f.add(t, entity.MediaStill, still, false)
+	}
+	if _, err := f.svc.CreateMedia(ctx, movieID, CreateMediaRequest{Kind: "STILL", URL: still}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("asset %d: %v, want the limit enforced", entity.MaxMediaPerMovie+1, err)
+	}
+}
@@ -0,0 +1,116 @@
+package movie
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Popularity weights; they add up to one, so scores run from 0 to
+// maxPopularityScore
+const (
+	bookingsWeight = 0.5
+	revenueWeight  = 0.3
+	ratingWeight   = 0.2
+
+	maxPopularityScore = 10
+	maxReviewRating    = 5
+)
+
+// PopularityCalculator computes movie popularity scores from their recent
+// bookings, the revenue of those bookings and their review rating. Bookings
+// and revenue are scaled against the movie doing best on each, so scores
+// rank the movies against each other rather than against a fixed target.
+type PopularityCalculator struct {
+	movieRepo repository.MovieRepository
+	window    time.Duration
+	logger    *logger.Logger
+}
+
+// NewPopularityCalculator creates a popularity calculator counting the
+// bookings made over window
+func NewPopularityCalculator(movieRepo repository.MovieRepository, window time.Duration, logger *logger.Logger) *PopularityCalculator {
+	return &PopularityCalculator{
+		movieRepo: movieRepo,
+		window:    window,
+		logger:    logger,
+	}
+}
+
+// RecalculateAll recomputes the scores of the movies with a showtime in the
+// window and saves them together. Movies without one keep their last score.
+func (p *PopularityCalculator) RecalculateAll(ctx context.Context) error {
+	stats, err := p.movieRepo.ListPopularityStats(ctx, time.Now().Add(-p.window))
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+
+	scores := popularityScores(stats)
+	if err := p.movieRepo.UpdatePopularityScores(ctx, scores); err != nil {
+		return err
+	}
+	p.logger.WithContext(ctx).Info("movie popularity recalculated", zap.Int("movies", len(scores)))
+	return nil
+}
+
+// Recalculate recomputes and saves the score of one movie, scaled against
+// the movies with a showtime in the window, and returns it. A movie without
+// a showtime in the window is scored on its rating alone.
+func (p *PopularityCalculator) Recalculate(ctx context.Context, movie *entity.Movie) (float64, error) {
+	stats, err := p.movieRepo.ListPopularityStats(ctx, time.Now().Add(-p.window))
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	for _, s := range stats {
+		if s.MovieID == movie.ID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		stats = append(stats, repository.MoviePopularityStats{MovieID: movie.ID, Rating: movie.UserRating})
+	}
+
+	score := popularityScores(stats)[movie.ID]
+	if err := p.movieRepo.UpdatePopularityScore(ctx, movie.ID, score); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
+
+// popularityScores scores each movie in stats
+func popularityScores(stats []repository.MoviePopularityStats) map[uuid.UUID]float64 {
+	var topBookings int64
+	var topRevenue float64
+	for _, s := range stats {
+		topBookings = max(topBookings, s.Bookings)
+		topRevenue = max(topRevenue, s.Revenue)
+	}
+
+	scores := make(map[uuid.UUID]float64, len(stats))
+	for _, s := range stats {
+		var score float64
+		if topBookings > 0 {
+			score += bookingsWeight * float64(max(s.Bookings, 0)) / float64(topBookings)
+		}
+		if topRevenue > 0 {
+			score += revenueWeight * max(s.Revenue, 0) / topRevenue
+		}
+		if s.Rating != nil {
+			score += ratingWeight * min(max(*s.Rating, 0), maxReviewRating) / maxReviewRating
+		}
+		scores[s.MovieID] = entity.RoundCents(score * maxPopularityScore)
+	}
+	return scores
+}
@@ -0,0 +1,115 @@
+package movie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// popularMovies serves fixed popularity stats and records the saved scores
+type popularMovies struct {
+	repository.MovieRepository
+	stats  []repository.MoviePopularityStats
+	since  time.Time
+	scores map[uuid.UUID]float64
+}
+
+func (m *popularMovies) ListPopularityStats(_ context.Context, since time.Time) ([]repository.MoviePopularityStats, error) {
+	m.since = since
+	return m.stats, nil
+}
+
+func (m *popularMovies) UpdatePopularityScores(_ context.Context, scores map[uuid.UUID]float64) error {
+	m.scores = scores
+	return nil
+}
+
+func (m *popularMovies) UpdatePopularityScore(_ context.Context, id uuid.UUID, score float64) error {
+	m.scores = map[uuid.UUID]float64{id: score}
+	return nil
+}
+
+func rating(r float64) *float64 {
+	return &r
+}
+
+func TestPopularityScores(t *testing.T) {
+	top, half, unrated, refunded := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	scores := popularityScores([]repository.MoviePopularityStats{
+		{MovieID: top, Bookings: 40, Revenue: 800, Rating: rating(5)},
+		{MovieID: half, Bookings: 20, Revenue: 400, Rating: rating(2.5)},
+		{MovieID: unrated, Bookings: 10, Revenue: 200},
+		{MovieID: refunded, Bookings: 0, Revenue: -50, Rating: rating(7)},
+	})
+
+	tests := []struct {
+		name  string
+		id    uuid.UUID
+		score float64
+	}{
+		{"best on everything", top, 10},
+		{"half of the best", half, 5},
+		{"without reviews", unrated, 2},
+		// Net refunds count as no revenue and ratings are capped
+		{"refunded", refunded, 2},
+	}
+	for _, tt := range tests {
+		if got := scores[tt.id]; got != tt.score {
+			t.Errorf("%s: score = %v, want %v", tt.name, got, tt.score)
+		}
+	}
+
+	// Without any sales only ratings count
+	quiet := uuid.New()
+	if got := popularityScores([]repository.MoviePopularityStats{{MovieID: quiet, Rating: rating(4)}})[quiet]; got != 1.6 {
+		t.Errorf("score without sales = %v, want 1.6", got)
+	}
+}
+
+func TestRecalculatePopularity(t *testing.T) {
+	ctx := context.Background()
+	top, other := uuid.New(), uuid.New()
+	movies := &popularMovies{stats: []repository.MoviePopularityStats{
+		{MovieID: top, Bookings: 10, Revenue: 100},
+		{MovieID: other, Bookings: 5, Revenue: 50},
+	}}
+	calc := NewPopularityCalculator(movies, 7*24*time.Hour, &logger.Logger{Logger: zap.NewNop()})
+
+	if err := calc.RecalculateAll(ctx); err != nil {
+		t.Fatalf("RecalculateAll: %v", err)
+	}
+	if len(movies.scores) != 2 || movies.scores[top] != 8 || movies.scores[other] != 4 {
+		t.Errorf("scores = %v, want 8 and 4", movies.scores)
+	}
+	if since := time.Since(movies.since); since < 7*24*time.Hour || since > 7*24*time.Hour+time.Minute {
+		t.Errorf("stats counted since %s ago, want the window", since)
+	}
+
+	// One movie is scaled against the others
+	score, err := calc.Recalculate(ctx, &entity.Movie{ID: other})
+	if err != nil {
+		t.Fatalf("Recalculate: %v", err)
+	}
+	if score != 4 || len(movies.scores) != 1 || movies.scores[other] != 4 {
+		t.Errorf("score = %v, saved %v, want 4 for the movie alone", score, movies.scores)
+	}
+
+	// A movie without showtimes in the window is scored on its rating
+	quiet := &entity.Movie{ID: uuid.New(), UserRating: rating(5)}
+	if score, err := calc.Recalculate(ctx, quiet); err != nil || score != 2 {
+		t.Errorf("Recalculate without showtimes = %v, %v, want 2", score, err)
+	}
+
+	// Nothing to score saves nothing
+	movies.stats, movies.scores = nil, nil
+	if err := calc.RecalculateAll(ctx); err != nil || movies.scores != nil {
+		t.Errorf("RecalculateAll without stats = %v, saved %v", err, movies.scores)
+	}
+}
@@ -0,0 +1,114 @@
+package movie
+
+import (
+	"context"
+	"math"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AddReview rates a movie for a customer. Only customers with a confirmed
+// or completed booking for the movie may review it, and only once.
+func (s *Service) AddReview(ctx context.Context, userID, movieID uuid.UUID, req CreateReviewRequest) (*ReviewResponse, error) {
+	if _, err := s.movieRepo.GetByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	booked, err := s.bookingRepo.HasBookingForMovie(ctx, userID, movieID, entity.BookingConfirmed, entity.BookingCompleted)
+	if err != nil {
+		return nil, err
+	}
+	if !booked {
+		return nil, apperrors.ErrForbidden("only customers with a confirmed booking for this movie can review it")
+	}
+
+	existing, err := s.reviewRepo.GetByUserAndMovie(ctx, userID, movieID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.ErrConflict("you have already reviewed this movie")
+	}
+
+	review := &entity.Review{
+		MovieID:    movieID,
+		UserID:     userID,
+		Rating:     req.Rating,
+		Title:      req.Title,
+		Body:       req.Body,
+		IsVerified: true,
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	s.refreshReviewStats(ctx, movieID)
+
+	// Reload to pick up the author for the response
+	review, err = s.reviewRepo.GetByID(ctx, movieID, review.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := toReviewResponse(review)
+	return &res, nil
+}
+
+// ListReviews returns a movie's reviews, newest first
+func (s *Service) ListReviews(ctx context.Context, movieID uuid.UUID, page, limit int) ([]ReviewResponse, int64, error) {
+	if _, err := s.movieRepo.GetByID(ctx, movieID); err != nil {
+		return nil, 0, err
+	}
+
+	reviews, total, err := s.reviewRepo.GetByMovieID(ctx, movieID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]ReviewResponse, 0, len(reviews))
+	for _, r := range reviews {
+		responses = append(responses, toReviewResponse(r))
+	}
+	return responses, total, nil
+}
+
+// DeleteReview removes a review. Customers can remove their own reviews;
+// admins can remove any.
+func (s *Service) DeleteReview(ctx context.Context, userID uuid.UUID, isAdmin bool, movieID, id uuid.UUID) error {
+	review, err := s.reviewRepo.GetByID(ctx, movieID, id)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && review.UserID != userID {
+		return apperrors.ErrForbidden("you can only delete your own reviews")
+	}
+
+	if err := s.reviewRepo.Delete(ctx, movieID, id); err != nil {
+		return err
+	}
+	s.refreshReviewStats(ctx, movieID)
+	return nil
+}
+
+// refreshReviewStats recomputes a movie's average user rating and review
+// count. The review itself is already stored, so a failure is logged
+// rather than returned; the next review corrects the stats.
+func (s *Service) refreshReviewStats(ctx context.Context, movieID uuid.UUID) {
+	avg, count, err := s.reviewRepo.GetAverageRating(ctx, movieID)
+	if err == nil {
+		var rating *float64
+		if count > 0 {
+			rounded := math.Round(avg*10) / 10
+			rating = &rounded
+		}
+		err = s.movieRepo.UpdateReviewStats(ctx, movieID, rating, count)
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to refresh movie review stats",
+			zap.String("movie_id", movieID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,171 @@
+package movie
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memReviews is a ReviewRepository kept in memory
+type memReviews struct {
+	repository.ReviewRepository
+	reviews []*entity.Review
+}
+
+func (m *memReviews) Create(_ context.Context, review *entity.Review) error {
+	review.ID = uuid.New()
+	review.CreatedAt = time.Now()
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+func (m *memReviews) GetByID(_ context.Context, movieID, id uuid.UUID) (*entity.Review, error) {
+	for _, review := range m.reviews {
+		if review.ID == id && review.MovieID == movieID {
+			return review, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("review")
+}
+
+func (m *memReviews) GetByUserAndMovie(_ context.Context, userID, movieID uuid.UUID) (*entity.Review, error) {
+	for _, review := range m.reviews {
+		if review.UserID == userID && review.MovieID == movieID {
+			return review, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memReviews) GetAverageRating(_ context.Context, movieID uuid.UUID) (float64, int64, error) {
+	var sum, count int64
+	for _, review := range m.reviews {
+		if review.MovieID == movieID {
+			sum += int64(review.Rating)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return float64(sum) / float64(count), count, nil
+}
+
+func (m *memReviews) Delete(_ context.Context, movieID, id uuid.UUID) error {
+	m.reviews = slices.DeleteFunc(m.reviews, func(r *entity.Review) bool { return r.ID == id && r.MovieID == movieID })
+	return nil
+}
+
+// memBookers knows which users hold a confirmed booking for the movie
+type memBookers struct {
+	repository.BookingRepository
+	users map[uuid.UUID]bool
+}
+
+func (m *memBookers) HasBookingForMovie(_ context.Context, userID, _ uuid.UUID, statuses ...entity.BookingStatus) (bool, error) {
+	if !slices.Contains(statuses, entity.BookingConfirmed) {
+		return false, nil
+	}
+	return m.users[userID], nil
+}
+
+type reviewFixture struct {
+	svc     *Service
+	movies  *memMovies
+	reviews *memReviews
+	// users with a confirmed booking
+	alice, bob, carol uuid.UUID
+}
+
+func newReviewFixture() *reviewFixture {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	f := &reviewFixture{
+		movies:  &memMovies{movie: &entity.Movie{ID: uuid.New(), Title: "Dune: Part Two", IsActive: true}},
+		reviews: &memReviews{},
+		alice:   uuid.New(),
+		bob:     uuid.New(),
+		carol:   uuid.New(),
+	}
+	bookers := &memBookers{users: map[uuid.UUID]bool{f.alice: true, f.bob: true, f.carol: true}}
+	f.svc = NewService(f.movies, &memMedia{}, f.reviews, bookers, nil, nil, changelog.NewService(&memChanges{}, log), nil, nil, nil, log)
+	return f
+}
+
+func (f *reviewFixture) review(t *testing.T, userID uuid.UUID, rating int) *ReviewResponse {
+	t.Helper()
+	res, err := f.svc.AddReview(context.Background(), userID, f.movies.movie.ID, CreateReviewRequest{Rating: rating})
+	if err != nil {
+		t.Fatalf("AddReview: %v", err)
+	}
+	return res
+}
+
+func TestAddReview(t *testing.T) {
+	ctx := context.Background()
+	f := newReviewFixture()
+
+	_, err := f.svc.AddReview(ctx, uuid.New(), f.movies.movie.ID, CreateReviewRequest{Rating: 5})
+	if !apperrors.Is(err, apperrors.CodeForbidden) {
+		t.Errorf("review without a booking: %v, want %s", err, apperrors.CodeForbidden)
+	}
+
+	f.review(t, f.alice, 4)
+	if !f.reviews.reviews[0].IsVerified {
+		t.Error("the review is not marked verified")
+	}
+	_, err = f.svc.AddReview(ctx, f.alice, f.movies.movie.ID, CreateReviewRequest{Rating: 1})
+	if !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("second review: %v, want %s", err, apperrors.CodeConflict)
+	}
+
+	_, err = f.svc.AddReview(ctx, f.bob, uuid.New(), CreateReviewRequest{Rating: 5})
+	if !apperrors.Is(err, apperrors.CodeNotFound) {
+		t.Errorf("review of an unknown movie: %v, want %s", err, apperrors.CodeNotFound)
+	}
+}
+
+func TestReviewStats(t *testing.T) {
+	ctx := context.Background()
+	f := newReviewFixture()
+	movie := f.movies.movie
+
+	f.review(t, f.alice, 5)
+	f.review(t, f.bob, 4)
+	carol := f.review(t, f.carol, 4)
+	if movie.UserRating == nil || *movie.UserRating != 4.3 || movie.ReviewCount != 3 {
+		t.Fatalf("rating %v from %d reviews, want 4.3 from 3", movie.UserRating, movie.ReviewCount)
+	}
+	if movie.ImdbRating != nil {
+		t.Error("the IMDb rating was touched")
+	}
+
+	// Only the author or an admin removes a review
+	if err := f.svc.DeleteReview(ctx, f.alice, false, movie.ID, carol.ID); !apperrors.Is(err, apperrors.CodeForbidden) {
+		t.Errorf("delete someone else's review: %v, want %s", err, apperrors.CodeForbidden)
+	}
+	if err := f.svc.DeleteReview(ctx, f.carol, false, movie.ID, carol.ID); err != nil {
+		t.Fatalf("DeleteReview: %v", err)
+	}
+	if *movie.UserRating != 4.5 || movie.ReviewCount != 2 {
+		t.Errorf("rating %v from %d reviews, want 4.5 from 2", *movie.UserRating, movie.ReviewCount)
+	}
+
+	for _, review := range slices.Clone(f.reviews.reviews) {
+		if err := f.svc.DeleteReview(ctx, uuid.New(), true, movie.ID, review.ID); err != nil {
+			t.Fatalf("admin DeleteReview: %v", err)
+		}
+	}
+	if movie.UserRating != nil || movie.ReviewCount != 0 {
+		t.Errorf("rating %v from %d reviews, want none", movie.UserRating, movie.ReviewCount)
+	}
+}
@@ -2,33 +2,70 @@ package movie
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"cinemaos-backend/internal/app/changelog"
 	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
 	"cinemaos-backend/internal/app/repository"
 	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"go.uber.org/zap"
 )
 
 // Service handles movie business logic
 type Service struct {
-	movieRepo repository.MovieRepository
-	logger    *logger.Logger
+	movieRepo   repository.MovieRepository
+	mediaRepo   repository.MovieMediaRepository
+	reviewRepo  repository.ReviewRepository
+	bookingRepo  repository.BookingRepository
+	showtimeRepo repository.ShowtimeRepository
+	prefsRepo    repository.UserPreferencesRepository
+	changeLog    *changelog.Service
+	tmdb         *TMDBService // nil when TMDB lookups are off
+	popularity   *PopularityCalculator
+	bus          *eventbus.Bus
+	logger       *logger.Logger
 }
 
 // NewService creates a new movie service
-func NewService(movieRepo repository.MovieRepository, logger *logger.Logger) *Service {
+func NewService(movieRepo repository.MovieRepository, mediaRepo repository.MovieMediaRepository, reviewRepo repository.ReviewRepository, bookingRepo repository.BookingRepository, showtimeRepo repository.ShowtimeRepository, prefsRepo repository.UserPreferencesRepository, changeLog *changelog.Service, tmdb *TMDBService, popularity *PopularityCalculator, bus *eventbus.Bus, logger *logger.Logger) *Service {
 	return &Service{
-		movieRepo: movieRepo,
-		logger:    logger,
+		movieRepo:    movieRepo,
+		mediaRepo:    mediaRepo,
+		reviewRepo:   reviewRepo,
+		bookingRepo:  bookingRepo,
+		showtimeRepo: showtimeRepo,
+		prefsRepo:    prefsRepo,
+		changeLog:    changeLog,
+		tmdb:         tmdb,
+		popularity:   popularity,
+		bus:          bus,
+		logger:       logger,
 	}
 }
 
-// Create creates a new movie
+// Create creates a new movie. With a TMDB ID, blank fields are filled from
+// TMDB first; if TMDB cannot be reached the movie is still created when the
+// required fields were given.
 func (s *Service) Create(ctx context.Context, req CreateMovieRequest) (*MovieResponse, error) {
+	if req.TMDBId != nil && s.tmdb != nil {
+		if err := s.tmdb.Fill(ctx, &req); err != nil {
+			if apperrors.Is(err, apperrors.CodeValidation) {
+				return nil, err
+			}
+			s.logger.WithContext(ctx).Warn("tmdb lookup failed", zap.Int("tmdb_id", *req.TMDBId), zap.Error(err))
+		}
+	}
+	if req.Title == "" || req.Duration <= 0 || req.ReleaseDate == "" {
+		return nil, apperrors.ErrValidation("title, duration and release_date are required unless TMDB provides them")
+	}
+
 	// Parse release date
 	releaseDate, err := time.Parse("2006-01-02", req.ReleaseDate)
 	if err != nil {
@@ -65,22 +102,22 @@ func (s *Service) Create(ctx context.Context, req CreateMovieRequest) (*MovieRes
 	return s.toResponse(movie), nil
 }
 
-// GetByID retrieves a movie by ID
+// GetByID retrieves a movie by ID with its published media gallery
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*MovieResponse, error) {
 	movie, err := s.movieRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return s.toResponse(movie), nil
+	return s.toDetailResponse(ctx, movie)
 }
 
-// GetBySlug retrieves a movie by slug
+// GetBySlug retrieves a movie by slug with its published media gallery
 func (s *Service) GetBySlug(ctx context.Context, slug string) (*MovieResponse, error) {
 	movie, err := s.movieRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
-	return s.toResponse(movie), nil
+	return s.toDetailResponse(ctx, movie)
 }
 
 // Update updates a movie
@@ -89,6 +126,7 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateMovieReque
 	if err != nil {
 		return nil, err
 	}
+	before := *movie
 
 	if req.Title != "" {
 		movie.Title = req.Title
@@ -152,28 +190,114 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateMovieReque
 	if err := s.movieRepo.Update(ctx, movie); err != nil {
 		return nil, err
 	}
+	s.changeLog.Record(ctx, entity.ChangeEntityMovie, movie.ID, before, *movie)
 
 	return s.toResponse(movie), nil
 }
 
-// Delete deletes a movie
-func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.movieRepo.Delete(ctx, id)
+// Delete soft deletes a movie, which takes it out of every listing until it
+// is restored. A movie with scheduled showtimes still to come is only
+// deleted with force, which cancels those showtimes first.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID, force bool) (*DeleteMovieResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	if _, err := s.movieRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	upcoming, err := s.showtimeRepo.ListScheduledForMovie(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(upcoming) > 0 && !force {
+		return nil, apperrors.New(apperrors.CodeConflict, fmt.Sprintf(
+			"movie has %d upcoming scheduled showtimes; delete with force=true to cancel them", len(upcoming))).
+			WithDetails(map[string]int{"scheduled_showtimes": len(upcoming)})
+	}
+
+	// Cancelled one by one, like a manual cancellation, so every showtime's
+	// subscribers hear of it. A failure part way leaves the movie in place,
+	// and a retry only finds the showtimes not cancelled yet.
+	for _, showtime := range upcoming {
+		if err := s.showtimeRepo.UpdateStatus(ctx, showtime.ID, entity.ShowtimeCancelled); err != nil {
+			return nil, err
+		}
+		s.bus.Publish(ctx, events.ShowtimeCancelled{
+			ShowtimeID:  showtime.ID,
+			MovieID:     showtime.MovieID,
+			CinemaID:    showtime.CinemaID,
+			CancelledAt: time.Now(),
+		})
+	}
+
+	if err := s.movieRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	log.Info("movie deleted",
+		zap.String("movie_id", id.String()),
+		zap.Int("cancelled_showtimes", len(upcoming)))
+	return &DeleteMovieResponse{CancelledShowtimes: len(upcoming)}, nil
+}
+
+// Restore brings back a deleted movie. Showtimes cancelled when it was
+// deleted stay cancelled.
+func (s *Service) Restore(ctx context.Context, id uuid.UUID) (*MovieResponse, error) {
+	if err := s.movieRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	movie, err := s.movieRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("movie restored", zap.String("movie_id", id.String()))
+	return s.toResponse(movie), nil
+}
+
+// RecalculatePopularity recomputes a movie's popularity score now rather
+// than on the next scheduled run
+func (s *Service) RecalculatePopularity(ctx context.Context, id uuid.UUID) (*MovieResponse, error) {
+	movie, err := s.movieRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.popularity.Recalculate(ctx, movie)
+	if err != nil {
+		return nil, err
+	}
+	movie.PopularityScore = score
+	s.logger.WithContext(ctx).Info("movie popularity recalculated",
+		zap.String("movie_id", id.String()),
+		zap.Float64("popularity_score", score))
+	return s.toResponse(movie), nil
 }
 
 // List lists movies with filters
 func (s *Service) List(ctx context.Context, params MovieListParams) ([]*MovieResponse, int64, error) {
-	// Parse offset and limit
-	offset := (params.Page - 1) * params.Limit
-	limit := params.Limit
+	return s.list(ctx, movieFilter(params), params.Page, params.Limit)
+}
+
+// ListForAdmin lists movies with filters, including inactive movies and,
+// when asked, deleted ones
+func (s *Service) ListForAdmin(ctx context.Context, params AdminMovieListParams) ([]*MovieResponse, int64, error) {
+	filter := movieFilter(params.MovieListParams)
+	filter.IsActive = params.IsActive
+	filter.IncludeDeleted = params.IncludeDeleted
+	return s.list(ctx, filter, params.Page, params.Limit)
+}
 
-	filter := repository.MovieFilter{
+func movieFilter(params MovieListParams) repository.MovieFilter {
+	return repository.MovieFilter{
 		Search:       params.Search,
 		Genre:        params.Genre,
 		Format:       params.Format,
 		IsNowShowing: params.IsNowShowing,
 		IsComingSoon: params.IsComingSoon,
 	}
+}
+
+func (s *Service) list(ctx context.Context, filter repository.MovieFilter, page, limit int) ([]*MovieResponse, int64, error) {
+	offset := (page - 1) * limit
 
 	movies, total, err := s.movieRepo.List(ctx, filter, offset, limit)
 	if err != nil {
@@ -188,10 +312,25 @@ func (s *Service) List(ctx context.Context, params MovieListParams) ([]*MovieRes
 	return responses, total, nil
 }
 
-// GetNowShowing returns movies currently showing
-func (s *Service) GetNowShowing(ctx context.Context, page, limit int) ([]*MovieResponse, int64, error) {
+// GetNowShowing returns movies currently showing. For a signed-in user
+// with preferences, movies in their favorite genres come first, and
+// personalized lists only the movies matching a favorite genre, format or
+// cinema. Without preferences everyone gets the same listing.
+func (s *Service) GetNowShowing(ctx context.Context, userID *uuid.UUID, personalized bool, page, limit int) ([]*MovieResponse, int64, error) {
 	offset := (page - 1) * limit
-	movies, total, err := s.movieRepo.GetNowShowing(ctx, nil, offset, limit)
+	var movies []*entity.Movie
+	var total int64
+	var err error
+	if prefs := s.preferences(ctx, userID); prefs != nil && prefs.HasFavorites() {
+		movies, total, err = s.movieRepo.GetNowShowingFor(ctx, repository.NowShowingPreferences{
+			Genres:       prefs.FavoriteGenres,
+			Formats:      prefs.PreferredFormats,
+			CinemaIDs:    prefs.CinemaIDs(),
+			OnlyMatching: personalized,
+		}, offset, limit)
+	} else {
+		movies, total, err = s.movieRepo.GetNowShowing(ctx, nil, offset, limit)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -204,6 +343,21 @@ func (s *Service) GetNowShowing(ctx context.Context, page, limit int) ([]*MovieR
 	return responses, total, nil
 }
 
+// preferences returns the user's saved preferences, or nil for guests and
+// users without any. A failure to read them is logged and the listing is
+// not personalised.
+func (s *Service) preferences(ctx context.Context, userID *uuid.UUID) *entity.UserPreferences {
+	if userID == nil {
+		return nil
+	}
+	prefs, err := s.prefsRepo.GetByUserID(ctx, *userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to get user preferences", zap.Error(err))
+		return nil
+	}
+	return prefs
+}
+
 // GetComingSoon returns upcoming movies
 func (s *Service) GetComingSoon(ctx context.Context, page, limit int) ([]*MovieResponse, int64, error) {
 	offset := (page - 1) * limit
@@ -220,9 +374,21 @@ func (s *Service) GetComingSoon(ctx context.Context, page, limit int) ([]*MovieR
 	return responses, total, nil
 }
 
+// toDetailResponse converts a movie to its detail response, which adds the
+// published media gallery
+func (s *Service) toDetailResponse(ctx context.Context, movie *entity.Movie) (*MovieResponse, error) {
+	res := s.toResponse(movie)
+	media, err := s.publishedMedia(ctx, movie.ID)
+	if err != nil {
+		return nil, err
+	}
+	res.Media = media
+	return res, nil
+}
+
 // toResponse converts movie entity to response DTO
 func (s *Service) toResponse(movie *entity.Movie) *MovieResponse {
-	return &MovieResponse{
+	res := &MovieResponse{
 		ID:              movie.ID,
 		TMDBId:          movie.TMDBId,
 		Title:           movie.Title,
@@ -233,6 +399,8 @@ func (s *Service) toResponse(movie *entity.Movie) *MovieResponse {
 		ReleaseDate:     movie.ReleaseDate.Format("2006-01-02"),
 		Rating:          movie.Rating,
 		ImdbRating:      movie.ImdbRating,
+		UserRating:      movie.UserRating,
+		ReviewCount:     movie.ReviewCount,
 		Language:        movie.Language,
 		Genres:          movie.Genres,
 		Director:        movie.Director,
@@ -244,6 +412,11 @@ func (s *Service) toResponse(movie *entity.Movie) *MovieResponse {
 		IsNowShowing:    movie.IsNowShowing,
 		IsComingSoon:    movie.IsComingSoon,
 		PopularityScore: movie.PopularityScore,
+		IsActive:        movie.IsActive,
 		CreatedAt:       movie.CreatedAt,
 	}
+	if movie.DeletedAt.Valid {
+		res.DeletedAt = &movie.DeletedAt.Time
+	}
+	return res
 }
@@ -0,0 +1,253 @@
+package movie
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// deletableMovies adds soft deletion to memMovies
+type deletableMovies struct {
+	*memMovies
+}
+
+func (m deletableMovies) Delete(_ context.Context, id uuid.UUID) error {
+	if m.movie.ID != id || m.movie.DeletedAt.Valid {
+		return apperrors.ErrNotFound("movie")
+	}
+	m.movie.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (m deletableMovies) Restore(_ context.Context, id uuid.UUID) error {
+	if m.movie.ID != id {
+		return apperrors.ErrNotFound("movie")
+	}
+	m.movie.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+// memShowtimes holds the showtimes of one movie
+type memShowtimes struct {
+	repository.ShowtimeRepository
+	showtimes []*entity.Showtime
+}
+
+func (m *memShowtimes) ListScheduledForMovie(_ context.Context, movieID uuid.UUID, after time.Time) ([]*entity.Showtime, error) {
+	var scheduled []*entity.Showtime
+	for _, showtime := range m.showtimes {
+		if showtime.MovieID == movieID && showtime.Status == entity.ShowtimeScheduled && showtime.ShowDate.After(after) {
+			copied := *showtime
+			scheduled = append(scheduled, &copied)
+		}
+	}
+	return scheduled, nil
+}
+
+func (m *memShowtimes) UpdateStatus(_ context.Context, id uuid.UUID, status entity.ShowtimeStatus) error {
+	for _, showtime := range m.showtimes {
+		if showtime.ID == id {
+			showtime.Status = status
+		}
+	}
+	return nil
+}
+
+type deleteFixture struct {
+	svc       *Service
+	movies    deletableMovies
+	showtimes *memShowtimes
+	cancelled *atomic.Int32
+}
+
+func newDeleteFixture(t *testing.T) *deleteFixture {
+	t.Helper()
+	log := &logger.Logger{Logger: zap.NewNop()}
+	movie := &entity.Movie{ID: uuid.New(), Title: "Dune: Part Two", IsActive: true}
+	f := &deleteFixture{
+		movies: deletableMovies{&memMovies{movie: movie}},
+		showtimes: &memShowtimes{showtimes: []*entity.Showtime{
+			{ID: uuid.New(), MovieID: movie.ID, Status: entity.ShowtimeScheduled, ShowDate: time.Now().AddDate(0, 0, 1)},
+			{ID: uuid.New(), MovieID: movie.ID, Status: entity.ShowtimeScheduled, ShowDate: time.Now().AddDate(0, 0, 2)},
+			{ID: uuid.New(), MovieID: movie.ID, Status: entity.ShowtimeCompleted, ShowDate: time.Now().AddDate(0, 0, -1)},
+		}},
+		cancelled: &atomic.Int32{},
+	}
+
+	bus := eventbus.New(eventbus.Config{Lanes: 1}, log)
+	bus.Subscribe(events.ShowtimeCancelledEvent, "counter", func(context.Context, eventbus.Event) error {
+		f.cancelled.Add(1)
+		return nil
+	})
+	bus.Start()
+	t.Cleanup(func() { _ = bus.Stop(time.Second) })
+
+	f.svc = NewService(f.movies, &memMedia{}, nil, nil, f.showtimes, nil, changelog.NewService(&memChanges{}, log), nil, nil, bus, log)
+	return f
+}
+
+func (f *deleteFixture) waitCancelled(t *testing.T, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for f.cancelled.Load() < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := f.cancelled.Load(); got != want {
+		t.Errorf("ShowtimeCancelled published %d times, want %d", got, want)
+	}
+}
+
+func TestDeleteRefusesUpcomingShowtimes(t *testing.T) {
+	f := newDeleteFixture(t)
+
+	_, err := f.svc.Delete(context.Background(), f.movies.movie.ID, false)
+	if !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Fatalf("Delete = %v, want a conflict", err)
+	}
+	if f.movies.movie.DeletedAt.Valid {
+		t.Error("the movie was deleted")
+	}
+	for _, showtime := range f.showtimes.showtimes {
+		if showtime.Status == entity.ShowtimeCancelled {
+			t.Errorf("showtime %s was cancelled without force", showtime.ID)
+		}
+	}
+}
+
+func TestDeleteWithForce(t *testing.T) {
+	ctx := context.Background()
+	f := newDeleteFixture(t)
+	id := f.movies.movie.ID
+
+	res, err := f.svc.Delete(ctx, id, true)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if res.CancelledShowtimes != 2 {
+		t.Errorf("cancelled %d showtimes, want 2", res.CancelledShowtimes)
+	}
+	if !f.movies.movie.DeletedAt.Valid {
+		t.Error("the movie was not deleted")
+	}
+	want := []entity.ShowtimeStatus{entity.ShowtimeCancelled, entity.ShowtimeCancelled, entity.ShowtimeCompleted}
+	for i, showtime := range f.showtimes.showtimes {
+		if showtime.Status != want[i] {
+			t.Errorf("showtime %d = %s, want %s", i, showtime.Status, want[i])
+		}
+	}
+	f.waitCancelled(t, 2)
+
+	restored, err := f.svc.Restore(ctx, id)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.DeletedAt != nil || f.movies.movie.DeletedAt.Valid {
+		t.Error("the movie is still deleted")
+	}
+	// Restoring does not bring the cancelled showtimes back
+	if f.showtimes.showtimes[0].Status != entity.ShowtimeCancelled {
+		t.Errorf("showtime = %s after the restore, want CANCELLED", f.showtimes.showtimes[0].Status)
+	}
+}
+
+func TestDeleteWithoutUpcomingShowtimes(t *testing.T) {
+	f := newDeleteFixture(t)
+	f.showtimes.showtimes = f.showtimes.showtimes[2:]
+
+	res, err := f.svc.Delete(context.Background(), f.movies.movie.ID, false)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if res.CancelledShowtimes != 0 || !f.movies.movie.DeletedAt.Valid {
+		t.Errorf("cancelled %d showtimes, deleted %v", res.CancelledShowtimes, f.movies.movie.DeletedAt.Valid)
+	}
+	if _, err := f.svc.Delete(context.Background(), uuid.New(), true); !apperrors.Is(err, apperrors.CodeNotFound) {
+		t.Errorf("Delete of an unknown movie = %v, want not found", err)
+	}
+}
+
+// nowShowingMovies records which now-showing query the service runs
+type nowShowingMovies struct {
+	*memMovies
+	prefs *repository.NowShowingPreferences
+}
+
+func (m *nowShowingMovies) GetNowShowing(context.Context, *uuid.UUID, int, int) ([]*entity.Movie, int64, error) {
+	m.prefs = nil
+	return []*entity.Movie{m.movie}, 1, nil
+}
+
+func (m *nowShowingMovies) GetNowShowingFor(_ context.Context, prefs repository.NowShowingPreferences, _, _ int) ([]*entity.Movie, int64, error) {
+	m.prefs = &prefs
+	return []*entity.Movie{m.movie}, 1, nil
+}
+
+// fixedPreferences serves the preferences of one user
+type fixedPreferences struct {
+	repository.UserPreferencesRepository
+	prefs *entity.UserPreferences
+}
+
+func (m *fixedPreferences) GetByUserID(_ context.Context, userID uuid.UUID) (*entity.UserPreferences, error) {
+	if m.prefs == nil || m.prefs.UserID != userID {
+		return nil, nil
+	}
+	return m.prefs, nil
+}
+
+func TestGetNowShowingPersonalised(t *testing.T) {
+	ctx := context.Background()
+	log := &logger.Logger{Logger: zap.NewNop()}
+	cinemaID := uuid.New()
+	fan := entity.DefaultUserPreferences(uuid.New())
+	fan.FavoriteGenres = []string{"Sci-Fi"}
+	fan.FavoriteCinemaIDs = []string{cinemaID.String(), "not-a-uuid"}
+	undecided := entity.DefaultUserPreferences(uuid.New())
+
+	movies := &nowShowingMovies{memMovies: &memMovies{movie: &entity.Movie{ID: uuid.New(), Title: "Dune: Part Two"}}}
+	prefs := &fixedPreferences{}
+	svc := NewService(movies, &memMedia{}, nil, nil, nil, prefs, changelog.NewService(&memChanges{}, log), nil, nil, nil, log)
+
+	tests := []struct {
+		name         string
+		prefs        *entity.UserPreferences
+		userID       *uuid.UUID
+		personalized bool
+		want         *repository.NowShowingPreferences
+	}{
+		{"guest", nil, nil, true, nil},
+		{"without preferences", nil, &undecided.UserID, true, nil},
+		{"without favorites", undecided, &undecided.UserID, true, nil},
+		{"favorites first", fan, &fan.UserID, false,
+			&repository.NowShowingPreferences{Genres: []string{"Sci-Fi"}, Formats: []string{}, CinemaIDs: []uuid.UUID{cinemaID}}},
+		{"only matching", fan, &fan.UserID, true,
+			&repository.NowShowingPreferences{Genres: []string{"Sci-Fi"}, Formats: []string{}, CinemaIDs: []uuid.UUID{cinemaID}, OnlyMatching: true}},
+	}
+	for _, tt := range tests {
+		prefs.prefs = tt.prefs
+		res, total, err := svc.GetNowShowing(ctx, tt.userID, tt.personalized, 1, 20)
+		if err != nil {
+			t.Fatalf("%s: GetNowShowing: %v", tt.name, err)
+		}
+		if total != 1 || len(res) != 1 {
+			t.Errorf("%s: %d of %d movies, want 1", tt.name, len(res), total)
+		}
+		if !reflect.DeepEqual(movies.prefs, tt.want) {
+			t.Errorf("%s: queried with %+v, want %+v", tt.name, movies.prefs, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/tmdb"
+
+	"go.uber.org/zap"
+)
+
+// TMDBService fills movie metadata from TMDB
+type TMDBService struct {
+	client *tmdb.Client
+	logger *logger.Logger
+}
+
+// NewTMDBService creates a new TMDB metadata service
+func NewTMDBService(client *tmdb.Client, logger *logger.Logger) *TMDBService {
+	return &TMDBService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Fill fills the blank fields of a create request from the TMDB movie with
+// the request's TMDB ID. Fields the admin set are left alone.
+func (t *TMDBService) Fill(ctx context.Context, req *CreateMovieRequest) error {
+	movie, err := t.client.GetMovie(ctx, *req.TMDBId)
+	if err != nil {
+		if errors.Is(err, tmdb.ErrNotFound) {
+			return apperrors.ErrValidation(fmt.Sprintf("tmdb_id %d does not exist on TMDB", *req.TMDBId))
+		}
+		return err
+	}
+
+	var filled []string
+	fillString := func(field string, dst *string, value string) {
+		if *dst == "" && value != "" {
+			*dst = value
+			filled = append(filled, field)
+		}
+	}
+	fillOptional := func(field string, dst **string, value string) {
+		if (*dst == nil || **dst == "") && value != "" {
+			*dst = &value
+			filled = append(filled, field)
+		}
+	}
+	fillList := func(field string, dst *[]string, value []string) {
+		if len(*dst) == 0 && len(value) > 0 {
+			*dst = value
+			filled = append(filled, field)
+		}
+	}
+
+	fillString("title", &req.Title, movie.Title)
+	fillString("release_date", &req.ReleaseDate, movie.ReleaseDate)
+	if req.Duration == 0 && movie.Runtime > 0 {
+		req.Duration = movie.Runtime
+		filled = append(filled, "duration")
+	}
+	if movie.OriginalTitle != movie.Title {
+		fillOptional("original_title", &req.OriginalTitle, movie.OriginalTitle)
+	}
+	fillOptional("description", &req.Description, movie.Overview)
+	fillOptional("director", &req.Director, movie.Director)
+	fillOptional("poster_url", &req.PosterURL, movie.PosterURL)
+	fillOptional("backdrop_url", &req.BackdropURL, movie.BackdropURL)
+	fillList("genres", &req.Genres, movie.Genres)
+	fillList("cast", &req.Cast, movie.Cast)
+
+	t.logger.WithContext(ctx).Info("movie metadata filled from tmdb",
+		zap.Int("tmdb_id", *req.TMDBId),
+		zap.Strings("fields", filled),
+	)
+	return nil
+}
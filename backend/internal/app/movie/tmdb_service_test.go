@@ -0,0 +1,122 @@
+package movie
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/changelog"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/tmdb"
+
+	"go.uber.org/zap"
+)
+
+// tmdbFixture is a movie service whose TMDB lookups go to a test server
+// that knows a single movie, 693134
+func tmdbFixture(t *testing.T, status int) (*Service, *memMovies) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.URL.Path != "/movie/693134" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+			"id": 693134, "title": "Dune: Part Two", "original_title": "Dune: Part Two",
+			"overview": "Paul Atreides unites with Chani and the Fremen.",
+			"poster_path": "/poster.jpg", "runtime": 167, "release_date": "2024-02-27",
+			"genres": [{"name": "Science Fiction"}],
+			"credits": {"cast": [{"name": "Zendaya"}], "crew": [{"name": "Denis Villeneuve", "job": "Director"}]}
+		}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	client := tmdb.NewClient(tmdb.Config{
+		APIKey:       "key",
+		BaseURL:      srv.URL,
+		ImageBaseURL: "https://image.example.com",
+		Timeout:      time.Second,
+		Backoff:      time.Millisecond,
+	}, log)
+	movies := &memMovies{}
+	svc := NewService(movies, &memMedia{}, nil, nil, nil, nil, changelog.NewService(&memChanges{}, log), NewTMDBService(client, log), nil, nil, log)
+	return svc, movies
+}
+
+func TestCreateFillsBlankFieldsFromTMDB(t *testing.T) {
+	svc, movies := tmdbFixture(t, http.StatusOK)
+	id := 693134
+	director := "Someone Else"
+
+	_, err := svc.Create(context.Background(), CreateMovieRequest{TMDBId: &id, Director: &director})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	movie := movies.movie
+	if movie.Title != "Dune: Part Two" || movie.Duration != 167 || movie.ReleaseDate.Format("2006-01-02") != "2024-02-27" {
+		t.Errorf("movie = %+v", movie)
+	}
+	if movie.Description == nil || *movie.Description != "Paul Atreides unites with Chani and the Fremen." {
+		t.Errorf("description = %v", movie.Description)
+	}
+	if movie.PosterURL == nil || *movie.PosterURL != "https://image.example.com/poster.jpg" {
+		t.Errorf("poster = %v", movie.PosterURL)
+	}
+	if movie.OriginalTitle != nil {
+		t.Errorf("original title = %q, want none when it equals the title", *movie.OriginalTitle)
+	}
+	if !slices.Equal(movie.Genres, []string{"Science Fiction"}) || !slices.Equal(movie.Cast, []string{"Zendaya"}) {
+		t.Errorf("genres = %v, cast = %v", movie.Genres, movie.Cast)
+	}
+	if *movie.Director != "Someone Else" {
+		t.Errorf("director = %q, the admin's value was overwritten", *movie.Director)
+	}
+}
+
+func TestCreateWithTMDBFailures(t *testing.T) {
+	unknown, known := 1, 693134
+	complete := CreateMovieRequest{Title: "Dune", Duration: 155, ReleaseDate: "2021-10-22"}
+
+	tests := []struct {
+		name     string
+		status   int
+		tmdbID   *int
+		req      CreateMovieRequest
+		wantCode apperrors.ErrorCode // empty when the movie is created
+	}{
+		{"unknown id", http.StatusOK, &unknown, complete, apperrors.CodeValidation},
+		{"tmdb down, fields given", http.StatusServiceUnavailable, &known, complete, ""},
+		{"tmdb down, fields missing", http.StatusServiceUnavailable, &known, CreateMovieRequest{}, apperrors.CodeValidation},
+		{"bad key, fields given", http.StatusUnauthorized, &known, complete, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, movies := tmdbFixture(t, tt.status)
+			req := tt.req
+			req.TMDBId = tt.tmdbID
+
+			_, err := svc.Create(context.Background(), req)
+			if tt.wantCode == "" {
+				if err != nil || movies.movie == nil || movies.movie.Title != "Dune" {
+					t.Errorf("err = %v, movie = %+v, want the request's movie", err, movies.movie)
+				}
+				return
+			}
+			if !apperrors.Is(err, tt.wantCode) {
+				t.Errorf("err = %v, want %s", err, tt.wantCode)
+			}
+			if movies.movie != nil {
+				t.Error("the movie was created")
+			}
+		})
+	}
+}
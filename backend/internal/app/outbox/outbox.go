@@ -0,0 +1,151 @@
+// Package outbox publishes domain events only once the transaction that
+// produced them has committed. Services add events to the outbox inside
+// their transaction; the relay job delivers them to the event bus and
+// records each as published, so a crash between the commit and delivery
+// delays the events rather than losing them. Delivery is at least once.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// maxStoredError bounds the publication error kept on an event
+const maxStoredError = 1000
+
+// Outbox records domain events to publish
+type Outbox struct {
+	repo repository.OutboxRepository
+}
+
+// New creates a new outbox
+func New(repo repository.OutboxRepository) *Outbox {
+	return &Outbox{repo: repo}
+}
+
+// Add stores events for the relay to publish. Inside
+// Transactor.InTransaction they are published only if the transaction
+// commits.
+func (o *Outbox) Add(ctx context.Context, evts ...eventbus.Event) error {
+	records := make([]*entity.OutboxEvent, 0, len(evts))
+	for _, event := range evts {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode event "+event.EventName())
+		}
+		records = append(records, &entity.OutboxEvent{
+			EventName:   event.EventName(),
+			AggregateID: event.AggregateID(),
+			Payload:     string(payload),
+		})
+	}
+	return o.repo.Add(ctx, records)
+}
+
+// RelayConfig holds outbox relay settings
+type RelayConfig struct {
+	BatchSize   int           // events published per run
+	MaxAttempts int           // failed attempts after which an event is left for inspection
+	Retention   time.Duration // how long published events are kept
+}
+
+// Relay publishes committed outbox events to the event bus
+type Relay struct {
+	repo   repository.OutboxRepository
+	bus    *eventbus.Bus
+	cfg    RelayConfig
+	logger *logger.Logger
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(repo repository.OutboxRepository, bus *eventbus.Bus, cfg RelayConfig, logger *logger.Logger) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	return &Relay{
+		repo:   repo,
+		bus:    bus,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// PublishPending delivers unpublished events in the order they were
+// written, waiting for their subscribers. It stops at the first event the
+// bus cannot take, so later events for the same booking are not delivered
+// ahead of it, and prunes events published longer ago than the retention.
+func (r *Relay) PublishPending(ctx context.Context) error {
+	log := r.logger.WithContext(ctx)
+
+	pending, err := r.repo.ListUnpublished(ctx, r.cfg.MaxAttempts, r.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, record := range pending {
+		event, err := events.Decode(record.EventName, []byte(record.Payload))
+		if err != nil {
+			// Most likely written by a newer release; it is retried until
+			// one that knows it takes over or the attempts run out
+			log.Warn("cannot decode outbox event",
+				zap.Int64("outbox_id", record.ID),
+				zap.String("event", record.EventName),
+				zap.Error(err),
+			)
+			r.markFailed(ctx, record, err)
+			continue
+		}
+
+		if err := r.bus.Deliver(ctx, event); err != nil {
+			r.markFailed(ctx, record, err)
+			return err
+		}
+		if err := r.repo.MarkPublished(ctx, record.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if r.cfg.Retention > 0 {
+		pruned, err := r.repo.DeletePublishedBefore(ctx, time.Now().Add(-r.cfg.Retention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Debug("pruned published outbox events", zap.Int64("events", pruned))
+		}
+	}
+	return nil
+}
+
+func (r *Relay) markFailed(ctx context.Context, record *entity.OutboxEvent, cause error) {
+	reason := cause.Error()
+	if len(reason) > maxStoredError {
+		reason = reason[:maxStoredError]
+	}
+	if err := r.repo.MarkFailed(ctx, record.ID, reason); err != nil {
+		r.logger.WithContext(ctx).Error("failed to record outbox event failure",
+			zap.Int64("outbox_id", record.ID),
+			zap.Error(err),
+		)
+	}
+	if record.Attempts+1 >= r.cfg.MaxAttempts {
+		r.logger.WithContext(ctx).Error("outbox event gave up, manual follow-up required",
+			zap.Int64("outbox_id", record.ID),
+			zap.String("event", record.EventName),
+			zap.String("aggregate_id", record.AggregateID),
+			zap.Error(cause),
+		)
+	}
+}
@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memOutbox is an OutboxRepository kept in memory
+type memOutbox struct {
+	events []*entity.OutboxEvent
+	pruned time.Time
+}
+
+func (m *memOutbox) Add(_ context.Context, events []*entity.OutboxEvent) error {
+	for _, event := range events {
+		event.ID = int64(len(m.events) + 1)
+		event.CreatedAt = time.Now()
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+
+func (m *memOutbox) ListUnpublished(_ context.Context, maxAttempts, limit int) ([]*entity.OutboxEvent, error) {
+	var pending []*entity.OutboxEvent
+	for _, event := range m.events {
+		if event.PublishedAt == nil && event.Attempts < maxAttempts && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memOutbox) MarkPublished(_ context.Context, id int64, at time.Time) error {
+	m.events[id-1].PublishedAt = &at
+	return nil
+}
+
+func (m *memOutbox) MarkFailed(_ context.Context, id int64, reason string) error {
+	m.events[id-1].Attempts++
+	m.events[id-1].LastError = &reason
+	return nil
+}
+
+func (m *memOutbox) DeletePublishedBefore(_ context.Context, before time.Time) (int64, error) {
+	m.pruned = before
+	var kept []*entity.OutboxEvent
+	var pruned int64
+	for _, event := range m.events {
+		if event.PublishedAt != nil && event.PublishedAt.Before(before) {
+			pruned++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	m.events = kept
+	return pruned, nil
+}
+
+// published lists the IDs of the events marked published
+func (m *memOutbox) published() []int64 {
+	var ids []int64
+	for _, event := range m.events {
+		if event.PublishedAt != nil {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
+// receiver subscribes to the booking events and records them in order
+type receiver struct {
+	mu       sync.Mutex
+	received []eventbus.Event
+}
+
+func newBus(t *testing.T, r *receiver, start bool) *eventbus.Bus {
+	t.Helper()
+	bus := eventbus.New(eventbus.Config{Lanes: 1}, &logger.Logger{Logger: zap.NewNop()})
+	for _, name := range []string{events.BookingCreatedEvent, events.BookingConfirmedEvent} {
+		bus.Subscribe(name, "receiver", func(_ context.Context, event eventbus.Event) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.received = append(r.received, event)
+			return nil
+		})
+	}
+	if start {
+		bus.Start()
+		t.Cleanup(func() { _ = bus.Stop(time.Second) })
+	}
+	return bus
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := &memOutbox{}
+	bookingID := uuid.New()
+	if err := New(repo).Add(ctx,
+		events.BookingCreated{BookingID: bookingID, BookingReference: "BK-1"},
+		events.BookingConfirmed{BookingID: bookingID},
+	); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if len(repo.events) != 2 || repo.events[0].EventName != events.BookingCreatedEvent || repo.events[0].AggregateID != bookingID.String() {
+		t.Fatalf("stored %+v", repo.events)
+	}
+
+	r := &receiver{}
+	relay := NewRelay(repo, newBus(t, r, true), RelayConfig{}, &logger.Logger{Logger: zap.NewNop()})
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+
+	// Deliver waits for the subscribers, so both events arrived already
+	if len(r.received) != 2 {
+		t.Fatalf("received %d events, want 2", len(r.received))
+	}
+	created, ok := r.received[0].(events.BookingCreated)
+	if !ok || created.BookingID != bookingID || created.BookingReference != "BK-1" {
+		t.Errorf("first event = %#v, want the BookingCreated", r.received[0])
+	}
+	if _, ok := r.received[1].(events.BookingConfirmed); !ok {
+		t.Errorf("second event = %#v, want the BookingConfirmed", r.received[1])
+	}
+	if got := repo.published(); len(got) != 2 {
+		t.Errorf("published %v, want both events", got)
+	}
+
+	// Published events are not delivered again
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+	if len(r.received) != 2 {
+		t.Errorf("received %d events after a second run, want 2", len(r.received))
+	}
+}
+
+func TestRelayStopsAtTheFirstFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := &memOutbox{}
+	bookingID := uuid.New()
+	_ = New(repo).Add(ctx, events.BookingCreated{BookingID: bookingID}, events.BookingConfirmed{BookingID: bookingID})
+
+	// A bus that is not running refuses every delivery
+	r := &receiver{}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	if err := NewRelay(repo, newBus(t, r, false), RelayConfig{MaxAttempts: 2}, log).PublishPending(ctx); err == nil {
+		t.Fatal("PublishPending succeeded with the bus down")
+	}
+	first, second := repo.events[0], repo.events[1]
+	if first.Attempts != 1 || first.LastError == nil || first.PublishedAt != nil {
+		t.Errorf("first event = %d attempts, error %v", first.Attempts, first.LastError)
+	}
+	// The confirmation is not delivered ahead of the creation
+	if second.Attempts != 0 || second.PublishedAt != nil {
+		t.Errorf("second event = %d attempts, published %v", second.Attempts, second.PublishedAt)
+	}
+
+	// Once the bus is back the relay picks up where it stopped
+	if err := NewRelay(repo, newBus(t, r, true), RelayConfig{MaxAttempts: 2}, log).PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+	if len(r.received) != 2 || len(repo.published()) != 2 {
+		t.Errorf("received %d events, published %v", len(r.received), repo.published())
+	}
+}
+
+func TestRelaySkipsUndecodableEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := &memOutbox{}
+	_ = repo.Add(ctx, []*entity.OutboxEvent{{EventName: "booking.renamed", AggregateID: "1", Payload: "{}"}})
+	_ = New(repo).Add(ctx, events.BookingConfirmed{BookingID: uuid.New()})
+
+	r := &receiver{}
+	relay := NewRelay(repo, newBus(t, r, true), RelayConfig{MaxAttempts: 1}, &logger.Logger{Logger: zap.NewNop()})
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+	unknown := repo.events[0]
+	if unknown.Attempts != 1 || unknown.PublishedAt != nil {
+		t.Errorf("unknown event = %d attempts, published %v", unknown.Attempts, unknown.PublishedAt)
+	}
+	if len(r.received) != 1 || len(repo.published()) != 1 {
+		t.Errorf("received %d events, published %v, want the confirmation", len(r.received), repo.published())
+	}
+
+	// Out of attempts, the unknown event is no longer listed
+	pending, _ := repo.ListUnpublished(ctx, 1, 10)
+	if len(pending) != 0 {
+		t.Errorf("%d events still pending", len(pending))
+	}
+}
+
+func TestRelayPrunesPublishedEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := &memOutbox{}
+	_ = New(repo).Add(ctx, events.BookingConfirmed{BookingID: uuid.New()}, events.BookingConfirmed{BookingID: uuid.New()})
+	old := time.Now().Add(-48 * time.Hour)
+	repo.events[0].PublishedAt = &old
+
+	r := &receiver{}
+	relay := NewRelay(repo, newBus(t, r, true), RelayConfig{Retention: 24 * time.Hour}, &logger.Logger{Logger: zap.NewNop()})
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending: %v", err)
+	}
+	if len(repo.events) != 1 || repo.events[0].ID != 2 || repo.events[0].PublishedAt == nil {
+		t.Errorf("kept %+v, want only the event published just now", repo.events)
+	}
+	if since := time.Since(repo.pruned); since < 24*time.Hour || since > 25*time.Hour {
+		t.Errorf("pruned before %s, want a day ago", repo.pruned)
+	}
+}
@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/stripe"
+
+	"go.uber.org/zap"
+)
+
+// GatewayStripe names Stripe on payments it collects
+const GatewayStripe = "stripe"
+
+// minSessionLifetime is the shortest expiry Stripe accepts for a checkout
+// session
+const minSessionLifetime = 30*time.Minute + time.Minute
+
+// PaymentGateway creates the hosted checkout pages booking payments are
+// collected on. *stripe.Client implements it; tests can use a fake.
+type PaymentGateway interface {
+	CreateCheckoutSession(ctx context.Context, params stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error)
+}
+
+// Checkout starts booking payments as Stripe Checkout Sessions. The
+// customer pays on the session's hosted page; the outcome arrives as a
+// webhook carrying the payment reference in the session's metadata.
+type Checkout struct {
+	paymentRepo repository.PaymentRepository
+	gateway     PaymentGateway
+	cfg         config.PaymentConfig
+	logger      *logger.Logger
+}
+
+// NewCheckout creates a new Stripe checkout
+func NewCheckout(
+	paymentRepo repository.PaymentRepository,
+	gateway PaymentGateway,
+	cfg config.PaymentConfig,
+	logger *logger.Logger,
+) *Checkout {
+	return &Checkout{
+		paymentRepo: paymentRepo,
+		gateway:     gateway,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// StartPayment records a pending payment for a booking and creates its
+// checkout session, keyed on the booking reference so a retry cannot
+// create a second session. A booking whose earlier attempt failed is
+// started again on the same payment, so Stripe sees the same request; one
+// with an open session cannot be started again. It returns the URL of the
+// checkout page.
+func (c *Checkout) StartPayment(ctx context.Context, booking *entity.Booking) (string, error) {
+	log := c.logger.WithContext(ctx)
+
+	existing, err := c.paymentRepo.GetByBookingID(ctx, booking.ID)
+	if err != nil {
+		return "", err
+	}
+	var payment *entity.Payment
+	for _, p := range existing {
+		switch {
+		case p.PaymentStatus == entity.PaymentPaid:
+			return "", apperrors.New(apperrors.CodeConflict, "booking is already paid")
+		case p.PaymentStatus == entity.PaymentPending && p.GatewayTransactionID != nil:
+			return "", apperrors.New(apperrors.CodeConflict, "a checkout is already open for this booking")
+		case p.PaymentStatus == entity.PaymentFailed && p.GatewayTransactionID == nil:
+			payment = p
+		}
+	}
+
+	if payment != nil {
+		payment.PaymentStatus = entity.PaymentPending
+		payment.FailureReason = nil
+		if err := c.paymentRepo.Update(ctx, payment); err != nil {
+			return "", err
+		}
+	} else {
+		payment = &entity.Payment{
+			BookingID:        booking.ID,
+			PaymentReference: authinfra.GeneratePaymentReference(),
+			PaymentGateway:   GatewayStripe,
+			Amount:           booking.FinalAmount,
+			Currency:         strings.ToUpper(c.cfg.Currency),
+			PaymentStatus:    entity.PaymentPending,
+			PaymentMethod:    booking.PaymentMethod,
+		}
+		if err := c.paymentRepo.Create(ctx, payment); err != nil {
+			return "", err
+		}
+	}
+
+	session, err := c.gateway.CreateCheckoutSession(ctx, stripe.CheckoutSessionParams{
+		Amount:            int64(math.Round(booking.FinalAmount * 100)),
+		Currency:          strings.ToLower(c.cfg.Currency),
+		ProductName:       fmt.Sprintf("Booking %s (%d tickets)", booking.BookingReference, booking.NumTickets),
+		SuccessURL:        checkoutURL(c.cfg.CheckoutSuccessURL, booking),
+		CancelURL:         checkoutURL(c.cfg.CheckoutCancelURL, booking),
+		ExpiresAt:         sessionExpiry(booking, time.Now()),
+		ClientReferenceID: booking.ID.String(),
+		Metadata: map[string]string{
+			"payment_reference": payment.PaymentReference,
+			"booking_id":        booking.ID.String(),
+			"booking_reference": booking.BookingReference,
+		},
+		IdempotencyKey: booking.BookingReference,
+	})
+	if err != nil {
+		log.Error("failed to create checkout session",
+			zap.String("booking_reference", booking.BookingReference),
+			zap.String("payment_reference", payment.PaymentReference),
+			zap.Error(err),
+		)
+		reason := "checkout session could not be created"
+		payment.PaymentStatus = entity.PaymentFailed
+		payment.FailureReason = &reason
+		if err := c.paymentRepo.Update(ctx, payment); err != nil {
+			log.Warn("failed to record payment failure", zap.Error(err))
+		}
+		return "", apperrors.Wrap(err, apperrors.CodeInternal, "failed to start payment")
+	}
+
+	payment.GatewayTransactionID = &session.ID
+	if err := c.paymentRepo.Update(ctx, payment); err != nil {
+		return "", err
+	}
+
+	log.Info("checkout session created",
+		zap.String("booking_reference", booking.BookingReference),
+		zap.String("payment_reference", payment.PaymentReference),
+		zap.String("checkout_session", session.ID),
+	)
+	return session.URL, nil
+}
+
+// checkoutURL fills the booking reference into a success or cancel URL
+func checkoutURL(template string, booking *entity.Booking) string {
+	return strings.ReplaceAll(template, "{BOOKING_REFERENCE}", booking.BookingReference)
+}
+
+// sessionExpiry closes the checkout page when the booking's seats are
+// released. Stripe keeps a session open for at least 30 minutes, so a
+// booking expiring sooner is left to the pending sweep; a payment arriving
+// after that is logged for a refund.
+func sessionExpiry(booking *entity.Booking, now time.Time) time.Time {
+	if booking.ExpiresAt == nil {
+		return time.Time{}
+	}
+	if earliest := now.Add(minSessionLifetime); booking.ExpiresAt.Before(earliest) {
+		return earliest
+	}
+	return *booking.ExpiresAt
+}
@@ -0,0 +1,201 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/stripe"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// fakeGateway records the checkout sessions asked for and fails when err
+// is set
+type fakeGateway struct {
+	err    error
+	params []stripe.CheckoutSessionParams
+}
+
+func (g *fakeGateway) CreateCheckoutSession(_ context.Context, params stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error) {
+	g.params = append(g.params, params)
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &stripe.CheckoutSession{ID: "cs_test_" + params.IdempotencyKey, URL: "https://checkout.test/" + params.IdempotencyKey}, nil
+}
+
+// fakePayments keeps payments in memory. Methods the checkout does not
+// use panic through the nil embedded interface.
+type fakePayments struct {
+	repository.PaymentRepository
+	payments []*entity.Payment
+}
+
+func (r *fakePayments) Create(_ context.Context, payment *entity.Payment) error {
+	payment.ID = uuid.New()
+	r.payments = append(r.payments, payment)
+	return nil
+}
+
+func (r *fakePayments) Update(_ context.Context, payment *entity.Payment) error {
+	return nil
+}
+
+func (r *fakePayments) GetByBookingID(_ context.Context, bookingID uuid.UUID) ([]*entity.Payment, error) {
+	var found []*entity.Payment
+	for _, payment := range r.payments {
+		if payment.BookingID == bookingID {
+			found = append(found, payment)
+		}
+	}
+	return found, nil
+}
+
+func newTestCheckout(gateway PaymentGateway, payments *fakePayments) *Checkout {
+	cfg := config.PaymentConfig{
+		Currency:           "usd",
+		CheckoutSuccessURL: "https://cinema.test/bookings/{BOOKING_REFERENCE}/paid",
+		CheckoutCancelURL:  "https://cinema.test/bookings/{BOOKING_REFERENCE}",
+	}
+	return NewCheckout(payments, gateway, cfg, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func testBooking() *entity.Booking {
+	expires := time.Now().Add(time.Hour)
+	return &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK-TEST01",
+		NumTickets:       2,
+		FinalAmount:      25.49,
+		BookingStatus:    entity.BookingPending,
+		PaymentStatus:    entity.PaymentPending,
+		ExpiresAt:        &expires,
+	}
+}
+
+func TestStartPayment(t *testing.T) {
+	gateway := &fakeGateway{}
+	payments := &fakePayments{}
+	booking := testBooking()
+
+	url, err := newTestCheckout(gateway, payments).StartPayment(context.Background(), booking)
+	if err != nil {
+		t.Fatalf("StartPayment: %v", err)
+	}
+
+	if len(payments.payments) != 1 {
+		t.Fatalf("%d payments recorded, want 1", len(payments.payments))
+	}
+	payment := payments.payments[0]
+	if payment.PaymentStatus != entity.PaymentPending || payment.Currency != "USD" || payment.Amount != 25.49 {
+		t.Errorf("payment = %+v", payment)
+	}
+	if payment.GatewayTransactionID == nil || *payment.GatewayTransactionID != "cs_test_"+booking.BookingReference {
+		t.Errorf("payment not linked to its checkout session: %v", payment.GatewayTransactionID)
+	}
+
+	params := gateway.params[0]
+	if url != "https://checkout.test/"+booking.BookingReference {
+		t.Errorf("checkout URL = %q", url)
+	}
+	if params.Amount != 2549 || params.Currency != "usd" {
+		t.Errorf("charged %d %s, want 2549 usd", params.Amount, params.Currency)
+	}
+	if params.IdempotencyKey != booking.BookingReference || params.Metadata["payment_reference"] != payment.PaymentReference {
+		t.Errorf("session not keyed on the booking reference: %+v", params)
+	}
+	if params.SuccessURL != "https://cinema.test/bookings/BK-TEST01/paid" {
+		t.Errorf("success URL = %q", params.SuccessURL)
+	}
+	if !params.ExpiresAt.Equal(*booking.ExpiresAt) {
+		t.Errorf("session expires at %v, want the booking's %v", params.ExpiresAt, booking.ExpiresAt)
+	}
+}
+
+func TestStartPaymentRetry(t *testing.T) {
+	gateway := &fakeGateway{err: errors.New("stripe unavailable")}
+	payments := &fakePayments{}
+	checkout := newTestCheckout(gateway, payments)
+	booking := testBooking()
+
+	if _, err := checkout.StartPayment(context.Background(), booking); err == nil {
+		t.Fatal("StartPayment succeeded with the gateway down")
+	}
+	if status := payments.payments[0].PaymentStatus; status != entity.PaymentFailed {
+		t.Fatalf("failed attempt left the payment %s", status)
+	}
+
+	// A retry goes out on the same payment and key as the failed attempt,
+	// so Stripe never opens two sessions for one booking
+	gateway.err = nil
+	if _, err := checkout.StartPayment(context.Background(), booking); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(gateway.params) != 2 || gateway.params[1].IdempotencyKey != booking.BookingReference {
+		t.Errorf("retry keyed %q, want the booking reference", gateway.params[1].IdempotencyKey)
+	}
+	if gateway.params[1].Metadata["payment_reference"] != gateway.params[0].Metadata["payment_reference"] {
+		t.Error("retry sent another payment reference")
+	}
+	if len(payments.payments) != 1 {
+		t.Fatalf("%d payments recorded, want the failed one reused", len(payments.payments))
+	}
+	if payment := payments.payments[0]; payment.PaymentStatus != entity.PaymentPending || payment.FailureReason != nil {
+		t.Errorf("retried payment is %s (%v), want PENDING", payment.PaymentStatus, payment.FailureReason)
+	}
+}
+
+func TestStartPaymentRefuses(t *testing.T) {
+	session := "cs_test_open"
+	tests := []struct {
+		name    string
+		payment entity.Payment
+	}{
+		{"paid booking", entity.Payment{PaymentStatus: entity.PaymentPaid}},
+		{"open checkout", entity.Payment{PaymentStatus: entity.PaymentPending, GatewayTransactionID: &session}},
+	}
+	for _, tt := range tests {
+		gateway := &fakeGateway{}
+		booking := testBooking()
+		tt.payment.BookingID = booking.ID
+		payments := &fakePayments{payments: []*entity.Payment{&tt.payment}}
+
+		_, err := newTestCheckout(gateway, payments).StartPayment(context.Background(), booking)
+		if !apperrors.Is(err, apperrors.CodeConflict) {
+			t.Errorf("%s: StartPayment = %v, want a conflict", tt.name, err)
+		}
+		if len(gateway.params) != 0 || len(payments.payments) != 1 {
+			t.Errorf("%s: %d sessions and %d payments", tt.name, len(gateway.params), len(payments.payments))
+		}
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(10 * time.Minute)
+	later := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		expires *time.Time
+		want    time.Time
+	}{
+		{"no deadline keeps Stripe's default", nil, time.Time{}},
+		{"deadline after Stripe's minimum", &later, later},
+		{"deadline before Stripe's minimum", &soon, now.Add(minSessionLifetime)},
+	}
+	for _, tt := range tests {
+		got := sessionExpiry(&entity.Booking{ExpiresAt: tt.expires}, now)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
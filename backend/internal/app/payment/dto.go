@@ -0,0 +1,137 @@
+package payment
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Gateway event types handled by the webhook
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+
+	// Stripe payment intent events
+	EventIntentSucceeded = "payment_intent.succeeded"
+	EventIntentFailed    = "payment_intent.payment_failed"
+
+	// Stripe Checkout Session events. A completed session is only paid
+	// when its payment_status says so; delayed methods such as bank debits
+	// settle later with an async event.
+	EventSessionCompleted      = "checkout.session.completed"
+	EventSessionAsyncSucceeded = "checkout.session.async_payment_succeeded"
+	EventSessionAsyncFailed    = "checkout.session.async_payment_failed"
+	EventSessionExpired        = "checkout.session.expired"
+)
+
+// gatewayEvent is the webhook body sent by the payment gateway. Stripe
+// events carry the payment intent or checkout session as data.object
+// instead.
+type gatewayEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentReference string        `json:"payment_reference"`
+		TransactionID    string        `json:"transaction_id"`
+		FailureReason    string        `json:"failure_reason"`
+		Object           *stripeObject `json:"object"`
+	} `json:"data"`
+}
+
+// stripeObject is the part of a Stripe payment intent or checkout session
+// the webhook reads
+type stripeObject struct {
+	ID               string            `json:"id"`
+	Metadata         map[string]string `json:"metadata"`
+	PaymentStatus    string            `json:"payment_status"` // sessions only
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"` // intents only
+}
+
+// parseGatewayEvent reads a webhook body, taking the payment reference,
+// transaction ID and failure reason of a Stripe event from its object
+func parseGatewayEvent(body []byte) (gatewayEvent, error) {
+	var parsed gatewayEvent
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return parsed, err
+	}
+
+	if object := parsed.Data.Object; object != nil {
+		if parsed.Data.PaymentReference == "" {
+			parsed.Data.PaymentReference = object.Metadata["payment_reference"]
+		}
+		if parsed.Data.TransactionID == "" {
+			parsed.Data.TransactionID = object.ID
+		}
+		if parsed.Data.FailureReason == "" && object.LastPaymentError != nil {
+			parsed.Data.FailureReason = object.LastPaymentError.Message
+		}
+	}
+	return parsed, nil
+}
+
+// sessionPaid reports whether a checkout session event carries a session
+// with nothing left to pay
+func (e gatewayEvent) sessionPaid() bool {
+	if e.Data.Object == nil {
+		return false
+	}
+	status := e.Data.Object.PaymentStatus
+	return status == "paid" || status == "no_payment_required"
+}
+
+// WebhookAckResponse is returned to the gateway
+type WebhookAckResponse struct {
+	ID     uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	Status string    `json:"status"`
+}
+
+// WebhookEventListParams represents query parameters for listing webhook events
+type WebhookEventListParams struct {
+	Page             int        `form:"page,default=1"`
+	Limit            int        `form:"limit,default=20"`
+	Status           string     `form:"status" validate:"omitempty,oneof=RECEIVED PROCESSED FAILED IGNORED REJECTED"`
+	EventType        string     `form:"event_type"`
+	PaymentReference string     `form:"payment_reference"`
+	From             *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To               *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// WebhookEventResponse represents a stored webhook event in admin responses
+type WebhookEventResponse struct {
+	ID               uuid.UUID  `json:"id" swaggertype:"string" format:"uuid"`
+	Provider         string     `json:"provider"`
+	EventID          string     `json:"event_id"`
+	EventType        string     `json:"event_type"`
+	Payload          string     `json:"payload"`
+	SignatureValid   bool       `json:"signature_valid"`
+	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	LastError        *string    `json:"last_error,omitempty"`
+	PaymentReference *string    `json:"payment_reference,omitempty"`
+	BookingID        *uuid.UUID `json:"booking_id,omitempty" swaggertype:"string" format:"uuid"`
+	PaymentID        *uuid.UUID `json:"payment_id,omitempty" swaggertype:"string" format:"uuid"`
+	ReceivedAt       time.Time  `json:"received_at"`
+	ProcessedAt      *time.Time `json:"processed_at,omitempty"`
+}
+
+// ReplayBatchRequest represents a request to replay several webhook events
+type ReplayBatchRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=100,dive,required" swaggertype:"array,string" format:"uuid"`
+}
+
+// ReplayResult is the outcome of replaying one event in a batch
+type ReplayResult struct {
+	ID     uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	Status string    `json:"status,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ReplayBatchResponse summarizes a batch replay
+type ReplayBatchResponse struct {
+	Results   []ReplayResult `json:"results"`
+	Processed int            `json:"processed"`
+	Failed    int            `json:"failed"`
+}
@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GatewayState is a payment's state as reported by the gateway
+type GatewayState string
+
+const (
+	GatewaySucceeded  GatewayState = "succeeded"
+	GatewayFailed     GatewayState = "failed"
+	GatewayProcessing GatewayState = "processing" // not settled yet; ask again later
+)
+
+// GatewayStatus is the gateway's view of one payment
+type GatewayStatus struct {
+	State         GatewayState `json:"status"`
+	TransactionID string       `json:"transaction_id"`
+	FailureReason string       `json:"failure_reason"`
+}
+
+// Gateway looks up payments at the payment gateway, so payments whose
+// webhook never arrived can still be settled
+type Gateway interface {
+	PaymentStatus(ctx context.Context, paymentReference string) (*GatewayStatus, error)
+}
+
+// httpGateway queries the gateway's payment status API
+type httpGateway struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewHTTPGateway creates a gateway client for the status API at baseURL
+func NewHTTPGateway(baseURL, apiKey string, timeout time.Duration) Gateway {
+	return &httpGateway{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// PaymentStatus returns the gateway's status of a payment. States other
+// than succeeded and failed are reported as processing.
+func (g *httpGateway) PaymentStatus(ctx context.Context, paymentReference string) (*GatewayStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		g.baseURL+"/payments/"+url.PathEscape(paymentReference), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("payment gateway request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return nil, fmt.Errorf("payment gateway returned %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var status GatewayStatus
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid payment gateway response: %w", err)
+	}
+	switch status.State {
+	case GatewaySucceeded, GatewayFailed:
+	default:
+		status.State = GatewayProcessing
+	}
+	return &status, nil
+}
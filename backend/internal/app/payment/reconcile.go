@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// reconciliationTrail is what the booking change history shows of a
+// reconciliation
+type reconciliationTrail struct {
+	BookingStatus  entity.BookingStatus `json:"booking_status"`
+	PaymentStatus  entity.PaymentStatus `json:"payment_status"`
+	Reconciliation string               `json:"reconciliation,omitempty"`
+}
+
+// ReconcilePending polls the gateway for payments still pending well after
+// they were initiated, in case their webhook was lost. Payments the gateway
+// reports settled are applied as the webhook would have; others are polled
+// again with a doubling backoff until the attempts run out or the booking
+// expires. It does nothing when no gateway is configured.
+func (s *Service) ReconcilePending(ctx context.Context) error {
+	if s.gateway == nil {
+		return nil
+	}
+
+	payments, err := s.paymentRepo.ListReconcilable(ctx, time.Now().Add(-s.reconcileAfter()), s.reconcileMaxAttempts(), reconcileBatchSize)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, payment := range payments {
+		if err := s.reconcile(ctx, payment); err != nil {
+			failed++
+			s.logger.Warn("payment reconciliation failed",
+				zap.String("payment_reference", payment.PaymentReference),
+				zap.Int("attempt", payment.ReconcileAttempts),
+				zap.Error(err),
+			)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d pending payments could not be reconciled", failed, len(payments))
+	}
+	return nil
+}
+
+// reconcile polls the gateway once for a pending payment and applies the
+// outcome. The next poll is scheduled before the gateway is asked, so a
+// payment the gateway keeps erroring on backs off too.
+func (s *Service) reconcile(ctx context.Context, payment *entity.Payment) error {
+	attempts := payment.ReconcileAttempts + 1
+	next := time.Now().Add(s.reconcileBackoff(attempts))
+	if err := s.paymentRepo.ScheduleReconcile(ctx, payment.ID, attempts, next); err != nil {
+		return err
+	}
+	payment.ReconcileAttempts = attempts
+	payment.NextReconcileAt = &next
+	lastAttempt := attempts >= s.reconcileMaxAttempts()
+
+	booking, err := s.bookingRepo.GetByID(ctx, payment.BookingID)
+	if err != nil {
+		return err
+	}
+	before := reconciliationTrail{BookingStatus: booking.BookingStatus, PaymentStatus: booking.PaymentStatus}
+
+	status, err := s.gateway.PaymentStatus(ctx, payment.PaymentReference)
+	if err != nil {
+		if lastAttempt {
+			s.recordReconciliation(ctx, payment.BookingID, before,
+				fmt.Sprintf("gave up after %d gateway polls: %v", attempts, err))
+		}
+		return err
+	}
+
+	switch status.State {
+	case GatewaySucceeded:
+		if err := s.settleSucceeded(ctx, payment, status.TransactionID, sourceReconciliation); err != nil {
+			return err
+		}
+		s.recordReconciliation(ctx, payment.BookingID, before, "gateway reported the payment succeeded")
+	case GatewayFailed:
+		if err := s.settleFailed(ctx, payment, status.FailureReason); err != nil {
+			return err
+		}
+		note := "gateway reported the payment failed"
+		if status.FailureReason != "" {
+			note += ": " + status.FailureReason
+		}
+		s.recordReconciliation(ctx, payment.BookingID, before, note)
+	default:
+		if lastAttempt {
+			s.recordReconciliation(ctx, payment.BookingID, before,
+				fmt.Sprintf("gave up after %d gateway polls; payment still processing", attempts))
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("payment reconciled",
+		zap.String("payment_reference", payment.PaymentReference),
+		zap.String("gateway_status", string(status.State)),
+		zap.Int("attempt", attempts),
+	)
+	return nil
+}
+
+// recordReconciliation adds a reconciliation outcome to the booking's change
+// history, with the statuses it left the booking in
+func (s *Service) recordReconciliation(ctx context.Context, bookingID uuid.UUID, before reconciliationTrail, note string) {
+	after := before
+	if booking, err := s.bookingRepo.GetByID(ctx, bookingID); err == nil {
+		after.BookingStatus = booking.BookingStatus
+		after.PaymentStatus = booking.PaymentStatus
+	}
+	after.Reconciliation = note
+	s.changeLog.Record(ctx, entity.ChangeEntityBooking, bookingID, before, after)
+}
+
+func (s *Service) reconcileAfter() time.Duration {
+	if s.cfg.ReconcileAfter <= 0 {
+		return 5 * time.Minute
+	}
+	return s.cfg.ReconcileAfter
+}
+
+func (s *Service) reconcileMaxAttempts() int {
+	if s.cfg.ReconcileMaxAttempts <= 0 {
+		return 6
+	}
+	return s.cfg.ReconcileMaxAttempts
+}
+
+// reconcileBackoff is the wait after the given poll: the configured backoff,
+// doubled after each poll
+func (s *Service) reconcileBackoff(attempts int) time.Duration {
+	backoff := s.cfg.ReconcileBackoff
+	if backoff <= 0 {
+		backoff = time.Minute
+	}
+	return backoff << min(attempts-1, 10)
+}
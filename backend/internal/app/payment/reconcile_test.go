@@ -0,0 +1,264 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// stubGateway answers every status poll with the same status, or error
+type stubGateway struct {
+	status *GatewayStatus
+	err    error
+	polls  int
+}
+
+func (g *stubGateway) PaymentStatus(context.Context, string) (*GatewayStatus, error) {
+	g.polls++
+	if g.err != nil {
+		return nil, g.err
+	}
+	copied := *g.status
+	return &copied, nil
+}
+
+// memChanges keeps the booking change history
+type memChanges struct {
+	repository.ChangeRecordRepository
+	records []*entity.ChangeRecord
+}
+
+func (m *memChanges) Create(_ context.Context, record *entity.ChangeRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+// notes returns the reconciliation outcomes recorded, oldest first
+func (m *memChanges) notes() []string {
+	var notes []string
+	for _, record := range m.records {
+		for _, change := range record.Changes {
+			if change.Field == "reconciliation" {
+				notes = append(notes, change.New.(string))
+			}
+		}
+	}
+	return notes
+}
+
+func (m *memPayments) ListReconcilable(_ context.Context, _ time.Time, maxAttempts, _ int) ([]*entity.Payment, error) {
+	p := m.payment
+	if p.PaymentStatus != entity.PaymentPending || p.ReconcileAttempts >= maxAttempts ||
+		(p.NextReconcileAt != nil && p.NextReconcileAt.After(time.Now())) {
+		return nil, nil
+	}
+	copied := *p
+	return []*entity.Payment{&copied}, nil
+}
+
+func (m *memPayments) ScheduleReconcile(_ context.Context, _ uuid.UUID, attempts int, next time.Time) error {
+	m.payment.ReconcileAttempts = attempts
+	m.payment.NextReconcileAt = &next
+	return nil
+}
+
+// due makes the payment's next poll due now
+func (f *webhookFixture) due() {
+	f.payments.payment.NextReconcileAt = nil
+}
+
+func TestReconcileSucceeded(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	f.gateway.status = &GatewayStatus{State: GatewaySucceeded, TransactionID: "txn-1"}
+
+	if err := f.svc.ReconcilePending(ctx); err != nil {
+		t.Fatalf("ReconcilePending: %v", err)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingConfirmed || f.payments.payment.PaymentStatus != entity.PaymentPaid {
+		t.Fatalf("booking = %s, payment = %s, want CONFIRMED and PAID",
+			f.bookings.booking.BookingStatus, f.payments.payment.PaymentStatus)
+	}
+	if txID := f.payments.payment.GatewayTransactionID; txID == nil || *txID != "txn-1" {
+		t.Errorf("transaction ID = %v, want txn-1", txID)
+	}
+	if notes := f.changes.notes(); len(notes) != 1 || notes[0] != "gateway reported the payment succeeded" {
+		t.Errorf("change history = %q", notes)
+	}
+
+	// The webhook arriving late finds the work done
+	body := succeeded("evt_late")
+	if _, err := f.svc.ReceiveWebhook(ctx, sign(body), body); err != nil {
+		t.Fatalf("late webhook: %v", err)
+	}
+	if f.bookings.statusUpdates != 1 {
+		t.Errorf("booking status updated %d times, want 1", f.bookings.statusUpdates)
+	}
+	if got := f.outbox.count(events.BookingConfirmedEvent); got != 1 {
+		t.Errorf("BookingConfirmed published %d times, want 1", got)
+	}
+
+	// A settled payment is not polled again
+	f.due()
+	if err := f.svc.ReconcilePending(ctx); err != nil || f.gateway.polls != 1 {
+		t.Errorf("ReconcilePending = %v after %d polls, want no further poll", err, f.gateway.polls)
+	}
+}
+
+func TestReconcileAfterTheWebhook(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	f.gateway.status = &GatewayStatus{State: GatewaySucceeded, TransactionID: "txn-1"}
+
+	// The poll was already picked up when the webhook settled the payment
+	stale := *f.payments.payment
+	body := succeeded("evt_1")
+	if _, err := f.svc.ReceiveWebhook(ctx, sign(body), body); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+	if err := f.svc.reconcile(ctx, &stale); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if f.bookings.statusUpdates != 1 {
+		t.Errorf("booking status updated %d times, want 1", f.bookings.statusUpdates)
+	}
+}
+
+func TestReconcileFailed(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.gateway.status = &GatewayStatus{State: GatewayFailed, FailureReason: "card_declined"}
+
+	if err := f.svc.ReconcilePending(context.Background()); err != nil {
+		t.Fatalf("ReconcilePending: %v", err)
+	}
+	payment := f.payments.payment
+	if payment.PaymentStatus != entity.PaymentFailed || payment.FailureReason == nil || *payment.FailureReason != "card_declined" {
+		t.Errorf("payment = %s (%v), want FAILED with the gateway's reason", payment.PaymentStatus, payment.FailureReason)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingPending {
+		t.Errorf("booking = %s, want it left pending for expiry", f.bookings.booking.BookingStatus)
+	}
+	if notes := f.changes.notes(); len(notes) != 1 || notes[0] != "gateway reported the payment failed: card_declined" {
+		t.Errorf("change history = %q", notes)
+	}
+}
+
+func TestReconcileProcessingBacksOff(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	f.gateway.status = &GatewayStatus{State: GatewayProcessing}
+
+	var waits []time.Duration
+	for poll := 1; poll <= 3; poll++ {
+		before := time.Now()
+		if err := f.svc.ReconcilePending(ctx); err != nil {
+			t.Fatalf("poll %d: %v", poll, err)
+		}
+		if f.payments.payment.ReconcileAttempts != poll {
+			t.Fatalf("poll %d: %d attempts recorded", poll, f.payments.payment.ReconcileAttempts)
+		}
+		waits = append(waits, f.payments.payment.NextReconcileAt.Sub(before).Round(time.Minute))
+
+		// Not due yet
+		if err := f.svc.ReconcilePending(ctx); err != nil || f.gateway.polls != poll {
+			t.Fatalf("poll %d: polled %d times before the backoff passed", poll, f.gateway.polls)
+		}
+		f.due()
+	}
+	if want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}; !slices.Equal(waits, want) {
+		t.Errorf("backoff = %v, want %v", waits, want)
+	}
+
+	if f.payments.payment.PaymentStatus != entity.PaymentPending {
+		t.Errorf("payment = %s, want it still pending", f.payments.payment.PaymentStatus)
+	}
+	if notes := f.changes.notes(); len(notes) != 1 || notes[0] != "gave up after 3 gateway polls; payment still processing" {
+		t.Errorf("change history = %q, want only the final give-up", notes)
+	}
+
+	// The attempts are used up
+	if err := f.svc.ReconcilePending(ctx); err != nil || f.gateway.polls != 3 {
+		t.Errorf("polled %d times, want 3", f.gateway.polls)
+	}
+}
+
+func TestReconcileGatewayErrors(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.gateway.err = errors.New("connection refused")
+
+	if err := f.svc.ReconcilePending(context.Background()); err == nil {
+		t.Fatal("ReconcilePending succeeded with the gateway down")
+	}
+	// The failed poll still counts and backs off
+	if f.payments.payment.ReconcileAttempts != 1 || f.payments.payment.NextReconcileAt == nil {
+		t.Errorf("attempts = %d, next = %v", f.payments.payment.ReconcileAttempts, f.payments.payment.NextReconcileAt)
+	}
+}
+
+func TestReconcileOffWithoutGateway(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.svc.gateway = nil
+
+	if err := f.svc.ReconcilePending(context.Background()); err != nil {
+		t.Fatalf("ReconcilePending: %v", err)
+	}
+	if f.payments.payment.ReconcileAttempts != 0 {
+		t.Error("a payment was polled with reconciliation off")
+	}
+}
+
+func TestHTTPGatewayPaymentStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/payments/PAY-1":
+			w.Write([]byte(`{"status":"succeeded","transaction_id":"txn-1"}`))
+		case "/payments/PAY-2":
+			w.Write([]byte(`{"status":"failed","failure_reason":"card_declined"}`))
+		case "/payments/PAY-3":
+			w.Write([]byte(`{"status":"requires_action"}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	gateway := NewHTTPGateway(srv.URL+"/", "sk_test", time.Second)
+	tests := []struct {
+		ref  string
+		want GatewayStatus
+	}{
+		{"PAY-1", GatewayStatus{State: GatewaySucceeded, TransactionID: "txn-1"}},
+		{"PAY-2", GatewayStatus{State: GatewayFailed, FailureReason: "card_declined"}},
+		{"PAY-3", GatewayStatus{State: GatewayProcessing}},
+	}
+	for _, tt := range tests {
+		status, err := gateway.PaymentStatus(context.Background(), tt.ref)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.ref, err)
+		}
+		if *status != tt.want {
+			t.Errorf("%s: status = %+v, want %+v", tt.ref, *status, tt.want)
+		}
+	}
+
+	if _, err := gateway.PaymentStatus(context.Background(), "PAY-404"); err == nil {
+		t.Error("a 404 was not reported")
+	}
+	if _, err := NewHTTPGateway(srv.URL, "wrong", time.Second).PaymentStatus(context.Background(), "PAY-1"); err == nil {
+		t.Error("a rejected API key was not reported")
+	}
+}
@@ -0,0 +1,699 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// sweepBatchSize limits how many stale events are alerted on per sweep
+	sweepBatchSize = 100
+	// maxStoredError caps the processing error kept on an event
+	maxStoredError = 1000
+	// reconcileBatchSize limits how many pending payments are polled per run
+	reconcileBatchSize = 100
+	// stripeSignatureTolerance bounds the age of a Stripe signature, so a
+	// captured delivery cannot be replayed later
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// Sources that settle a payment, for logs
+const (
+	sourceWebhook        = "webhook"
+	sourceReconciliation = "reconciliation"
+)
+
+// SharePayments settles split-payment shares paid through the gateway
+type SharePayments interface {
+	MarkSharePaid(ctx context.Context, paymentReference, gatewayTransactionID string) error
+}
+
+// SeatReleaser releases the seats of a pending booking whose payment is not
+// going to arrive
+type SeatReleaser interface {
+	ReleaseUnpaidBooking(ctx context.Context, bookingID uuid.UUID) error
+}
+
+// Service receives payment gateway webhooks through the webhook inbox.
+// Every event is stored before it is processed so failures can be replayed.
+// Payments whose webhook never arrives are reconciled by polling the gateway.
+type Service struct {
+	webhookRepo repository.WebhookEventRepository
+	paymentRepo repository.PaymentRepository
+	bookingRepo repository.BookingRepository
+	userRepo    repository.UserRepository
+	shares      SharePayments
+	seats       SeatReleaser
+	gateway     Gateway // nil when reconciliation is off
+	changeLog   *changelog.Service
+	dispatcher  *async.Dispatcher
+	tx          repository.Transactor
+	outbox      *outbox.Outbox
+	tracker     *analytics.Tracker
+	cfg         config.PaymentConfig
+	logger      *logger.Logger
+}
+
+// NewService creates a new payment service
+func NewService(
+	webhookRepo repository.WebhookEventRepository,
+	paymentRepo repository.PaymentRepository,
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	shares SharePayments,
+	seats SeatReleaser,
+	gateway Gateway,
+	changeLog *changelog.Service,
+	dispatcher *async.Dispatcher,
+	tx repository.Transactor,
+	outbox *outbox.Outbox,
+	tracker *analytics.Tracker,
+	cfg config.PaymentConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		webhookRepo: webhookRepo,
+		paymentRepo: paymentRepo,
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+		shares:      shares,
+		seats:       seats,
+		gateway:     gateway,
+		changeLog:   changeLog,
+		dispatcher:  dispatcher,
+		tx:          tx,
+		outbox:      outbox,
+		tracker:     tracker,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// ReceiveWebhook stores a gateway callback in the inbox and processes it.
+// Redelivered events that were already handled are acknowledged without
+// being applied again.
+func (s *Service) ReceiveWebhook(ctx context.Context, signature string, body []byte) (*WebhookAckResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	valid := s.verifySignature(signature, body)
+
+	parsed, parseErr := parseGatewayEvent(body)
+
+	event := &entity.WebhookEvent{
+		Provider:       s.cfg.Provider,
+		EventID:        parsed.ID,
+		EventType:      parsed.Type,
+		Payload:        string(body),
+		SignatureValid: valid,
+		Status:         entity.WebhookReceived,
+		ReceivedAt:     time.Now(),
+	}
+	if parseErr != nil {
+		// Keep the raw body; jsonb only accepts valid JSON
+		raw, _ := json.Marshal(string(body))
+		event.Payload = string(raw)
+	}
+	if event.EventID == "" {
+		event.EventID = "unidentified:" + uuid.New().String()
+	}
+	if event.EventType == "" {
+		event.EventType = "unknown"
+	}
+	if parsed.Data.PaymentReference != "" {
+		ref := parsed.Data.PaymentReference
+		event.PaymentReference = &ref
+	}
+	if !valid {
+		// Unverified events never claim the gateway event ID, so a forged
+		// delivery cannot shadow the genuine one
+		event.EventID = "unverified:" + uuid.New().String()
+		event.Status = entity.WebhookRejected
+	}
+
+	stored, created, err := s.webhookRepo.Create(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		log.Warn("rejected webhook with invalid signature",
+			zap.String("event_id", event.EventID),
+			zap.String("event_type", event.EventType),
+		)
+		return nil, apperrors.ErrUnauthorized("invalid webhook signature")
+	}
+	if parseErr != nil {
+		_ = s.finish(ctx, stored, apperrors.ErrBadRequest("malformed webhook payload"))
+		return nil, apperrors.ErrBadRequest("malformed webhook payload")
+	}
+
+	if !created && !stored.Status.IsReplayable() {
+		log.Info("duplicate webhook acknowledged",
+			zap.String("event_id", stored.EventID),
+			zap.String("status", string(stored.Status)),
+		)
+		return &WebhookAckResponse{ID: stored.ID, Status: string(stored.Status)}, nil
+	}
+
+	// The gateway retries on an error response; the stored event also stays
+	// replayable from the admin API
+	if err := s.process(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &WebhookAckResponse{ID: stored.ID, Status: string(stored.Status)}, nil
+}
+
+// verifySignature checks the hex HMAC-SHA256 of the body, with or without
+// a "sha256=" prefix, or a Stripe-Signature header
+func (s *Service) verifySignature(signature string, body []byte) bool {
+	if s.cfg.WebhookSecret == "" || signature == "" {
+		return false
+	}
+	if strings.Contains(signature, "v1=") {
+		return s.verifyStripeSignature(signature, body)
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.cfg.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// verifyStripeSignature checks a "t=<unix>,v1=<hex>" header: the HMAC-SHA256
+// of "<t>.<body>", signed within stripeSignatureTolerance. Stripe sends
+// several v1 signatures while a secret is rolled; any one is enough.
+func (s *Service) verifyStripeSignature(header string, body []byte) bool {
+	var (
+		timestamp  string
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return false
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.cfg.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListWebhookEvents lists stored webhook events for operators
+func (s *Service) ListWebhookEvents(ctx context.Context, params WebhookEventListParams) ([]*WebhookEventResponse, int64, error) {
+	filter := repository.WebhookEventFilter{
+		EventType:        params.EventType,
+		PaymentReference: params.PaymentReference,
+		ReceivedFrom:     params.From,
+		ReceivedTo:       params.To,
+	}
+	if params.Status != "" {
+		filter.Statuses = []entity.WebhookStatus{entity.WebhookStatus(params.Status)}
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	stored, total, err := s.webhookRepo.List(ctx, filter, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*WebhookEventResponse, len(stored))
+	for i, event := range stored {
+		responses[i] = toWebhookEventResponse(event)
+	}
+	return responses, total, nil
+}
+
+// ReplayWebhookEvent processes a stored event again through the same code
+// path as live delivery. Events that were already applied are returned
+// unchanged.
+func (s *Service) ReplayWebhookEvent(ctx context.Context, id uuid.UUID) (*WebhookEventResponse, error) {
+	event, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status == entity.WebhookRejected {
+		return nil, apperrors.ErrConflict("webhook event failed signature verification and cannot be replayed")
+	}
+	if !event.Status.IsReplayable() {
+		return toWebhookEventResponse(event), nil
+	}
+
+	// A processing failure is recorded on the event and shown to the caller
+	_ = s.process(ctx, event)
+	return toWebhookEventResponse(event), nil
+}
+
+// ReplayWebhookEvents replays several stored events, continuing past failures
+func (s *Service) ReplayWebhookEvents(ctx context.Context, req ReplayBatchRequest) (*ReplayBatchResponse, error) {
+	res := &ReplayBatchResponse{Results: make([]ReplayResult, 0, len(req.IDs))}
+
+	for _, id := range req.IDs {
+		event, err := s.ReplayWebhookEvent(ctx, id)
+		if err != nil {
+			res.Failed++
+			res.Results = append(res.Results, ReplayResult{ID: id, Error: err.Error()})
+			continue
+		}
+
+		result := ReplayResult{ID: id, Status: event.Status}
+		if event.Status == string(entity.WebhookFailed) {
+			res.Failed++
+			if event.LastError != nil {
+				result.Error = *event.LastError
+			}
+		} else {
+			res.Processed++
+		}
+		res.Results = append(res.Results, result)
+	}
+
+	return res, nil
+}
+
+// process applies a stored event and records the outcome on it. A crash
+// before the outcome is saved leaves the event RECEIVED or FAILED, so it
+// is picked up by a replay.
+func (s *Service) process(ctx context.Context, event *entity.WebhookEvent) error {
+	if err := s.webhookRepo.IncrementAttempts(ctx, event.ID); err != nil {
+		return err
+	}
+	event.Attempts++
+
+	parsed, err := parseGatewayEvent([]byte(event.Payload))
+	if err != nil {
+		return s.finish(ctx, event, apperrors.ErrBadRequest("malformed webhook payload"))
+	}
+
+	var applyErr error
+	switch parsed.Type {
+	case EventIntentSucceeded, EventIntentFailed:
+		if parsed.Data.PaymentReference == "" {
+			ref, err := s.intentPaymentReference(ctx, parsed.Data.TransactionID)
+			if err != nil {
+				applyErr = err
+				break
+			}
+			if ref == "" {
+				// Intents created by a checkout session carry no payment
+				// reference; the session events settle those payments
+				event.Status = entity.WebhookIgnored
+				break
+			}
+			parsed.Data.PaymentReference = ref
+		}
+		if parsed.Type == EventIntentSucceeded {
+			applyErr = s.applyPaymentSucceeded(ctx, event, parsed)
+		} else {
+			applyErr = s.applyPaymentFailed(ctx, event, parsed)
+		}
+	case EventSessionCompleted:
+		if !parsed.sessionPaid() {
+			// A delayed payment method settles with an async event
+			event.Status = entity.WebhookIgnored
+			break
+		}
+		applyErr = s.applyPaymentSucceeded(ctx, event, parsed)
+	case EventPaymentSucceeded, EventSessionAsyncSucceeded:
+		applyErr = s.applyPaymentSucceeded(ctx, event, parsed)
+	case EventPaymentFailed:
+		applyErr = s.applyPaymentFailed(ctx, event, parsed)
+	case EventSessionExpired, EventSessionAsyncFailed:
+		applyErr = s.applyCheckoutClosed(ctx, event, parsed)
+	default:
+		event.Status = entity.WebhookIgnored
+	}
+
+	return s.finish(ctx, event, applyErr)
+}
+
+// intentPaymentReference returns the reference of the payment made with a
+// payment intent that carries none in its metadata, looked up by the
+// intent's ID. It is empty when no payment was recorded with the intent.
+func (s *Service) intentPaymentReference(ctx context.Context, intentID string) (string, error) {
+	if intentID == "" {
+		return "", nil
+	}
+	payment, err := s.paymentRepo.GetByGatewayTransactionID(ctx, intentID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return payment.PaymentReference, nil
+}
+
+// finish stores the processing outcome and returns the processing error
+func (s *Service) finish(ctx context.Context, event *entity.WebhookEvent, applyErr error) error {
+	log := s.logger.WithContext(ctx)
+	now := time.Now()
+
+	switch {
+	case applyErr != nil:
+		msg := applyErr.Error()
+		if len(msg) > maxStoredError {
+			msg = msg[:maxStoredError]
+		}
+		event.Status = entity.WebhookFailed
+		event.LastError = &msg
+	case event.Status != entity.WebhookIgnored:
+		event.Status = entity.WebhookProcessed
+		event.LastError = nil
+	}
+	if applyErr == nil {
+		event.ProcessedAt = &now
+	}
+
+	if err := s.webhookRepo.Update(ctx, event); err != nil {
+		return err
+	}
+
+	if applyErr != nil {
+		log.Error("webhook processing failed",
+			zap.String("webhook_id", event.ID.String()),
+			zap.String("event_id", event.EventID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(applyErr),
+		)
+	}
+	return applyErr
+}
+
+// applyPaymentSucceeded marks the payment and its booking paid. Each step
+// checks the current state first, so a partially applied event completes
+// on replay without repeating work.
+func (s *Service) applyPaymentSucceeded(ctx context.Context, event *entity.WebhookEvent, parsed gatewayEvent) error {
+	ref := parsed.Data.PaymentReference
+	if ref == "" {
+		return apperrors.ErrBadRequest("payment reference is required")
+	}
+
+	payment, err := s.paymentRepo.GetByReference(ctx, ref)
+	if err != nil {
+		if !apperrors.Is(err, apperrors.CodeNotFound) {
+			return err
+		}
+		// Not a booking payment; it may settle a group checkout share
+		if err := s.shares.MarkSharePaid(ctx, ref, parsed.Data.TransactionID); err != nil {
+			if apperrors.Is(err, apperrors.CodeNotFound) {
+				return apperrors.ErrNotFound("Payment reference " + ref)
+			}
+			return err
+		}
+		return nil
+	}
+
+	event.PaymentID = &payment.ID
+	event.BookingID = &payment.BookingID
+
+	return s.settleSucceeded(ctx, payment, parsed.Data.TransactionID, sourceWebhook)
+}
+
+// settleSucceeded marks a payment and its booking paid and confirms the
+// booking. It is shared by the webhook and reconciliation, whichever comes
+// first; the other finds the work done.
+func (s *Service) settleSucceeded(ctx context.Context, payment *entity.Payment, transactionID, source string) error {
+	log := s.logger.WithContext(ctx)
+	ref := payment.PaymentReference
+
+	settled := payment.PaymentStatus != entity.PaymentPaid
+	if settled {
+		now := time.Now()
+		payment.PaymentStatus = entity.PaymentPaid
+		payment.PaidAt = &now
+		if transactionID != "" {
+			payment.GatewayTransactionID = &transactionID
+		}
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return err
+		}
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, payment.BookingID)
+	if err != nil {
+		return err
+	}
+	if settled {
+		// Replays of an applied event are not counted again
+		s.trackPayment(ctx, analytics.EventPaymentSucceeded, booking, payment)
+	}
+
+	if booking.PaymentStatus != entity.PaymentPaid {
+		if err := s.bookingRepo.UpdatePaymentStatus(ctx, booking.ID, entity.PaymentPaid); err != nil {
+			return err
+		}
+	}
+
+	switch booking.BookingStatus {
+	case entity.BookingPending:
+		// The confirmation's side effects are published only once it commits
+		err := s.tx.InTransaction(ctx, func(ctx context.Context) error {
+			if err := s.bookingRepo.UpdateStatus(ctx, booking.ID, entity.BookingConfirmed); err != nil {
+				return err
+			}
+			return s.outbox.Add(ctx, events.BookingConfirmed{
+				BookingID:        booking.ID,
+				BookingReference: booking.BookingReference,
+				UserID:           booking.UserID,
+				ShowtimeID:       booking.ShowtimeID,
+				NumTickets:       booking.NumTickets,
+				FinalAmount:      booking.FinalAmount,
+				ConfirmedAt:      time.Now(),
+			})
+		})
+		if err != nil {
+			return err
+		}
+		log.Info("booking confirmed by payment",
+			zap.String("booking_reference", booking.BookingReference),
+			zap.String("payment_reference", ref),
+			zap.String("source", source),
+		)
+	case entity.BookingCancelled, entity.BookingExpired:
+		log.Warn("payment received for a booking that is no longer pending, refund required",
+			zap.String("booking_reference", booking.BookingReference),
+			zap.String("booking_status", string(booking.BookingStatus)),
+			zap.String("payment_reference", ref),
+		)
+	}
+
+	return nil
+}
+
+// applyPaymentFailed records a declined payment against a pending booking
+func (s *Service) applyPaymentFailed(ctx context.Context, event *entity.WebhookEvent, parsed gatewayEvent) error {
+	ref := parsed.Data.PaymentReference
+	if ref == "" {
+		return apperrors.ErrBadRequest("payment reference is required")
+	}
+
+	payment, err := s.paymentRepo.GetByReference(ctx, ref)
+	if err != nil {
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			// Declined share payments leave the share pending until it lapses
+			return nil
+		}
+		return err
+	}
+
+	event.PaymentID = &payment.ID
+	event.BookingID = &payment.BookingID
+
+	return s.settleFailed(ctx, payment, parsed.Data.FailureReason)
+}
+
+// settleFailed records a declined payment against its pending booking
+func (s *Service) settleFailed(ctx context.Context, payment *entity.Payment, reason string) error {
+	if payment.PaymentStatus != entity.PaymentPending {
+		return nil
+	}
+
+	payment.PaymentStatus = entity.PaymentFailed
+	if reason != "" {
+		payment.FailureReason = &reason
+	}
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, payment.BookingID)
+	if err != nil {
+		return err
+	}
+	s.trackPayment(ctx, analytics.EventPaymentFailed, booking, payment)
+	if booking.PaymentStatus == entity.PaymentPending {
+		return s.bookingRepo.UpdatePaymentStatus(ctx, booking.ID, entity.PaymentFailed)
+	}
+	return nil
+}
+
+// applyCheckoutClosed fails the payment of a checkout session that expired
+// or whose delayed payment was declined, and releases the booking's seats
+// instead of holding them until the payment deadline
+func (s *Service) applyCheckoutClosed(ctx context.Context, event *entity.WebhookEvent, parsed gatewayEvent) error {
+	ref := parsed.Data.PaymentReference
+	if ref == "" {
+		return apperrors.ErrBadRequest("payment reference is required")
+	}
+
+	payment, err := s.paymentRepo.GetByReference(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	event.PaymentID = &payment.ID
+	event.BookingID = &payment.BookingID
+
+	reason := parsed.Data.FailureReason
+	if reason == "" {
+		reason = "checkout session expired"
+		if parsed.Type == EventSessionAsyncFailed {
+			reason = "payment declined"
+		}
+	}
+	if err := s.settleFailed(ctx, payment, reason); err != nil {
+		return err
+	}
+	// A session closing after its payment was settled leaves the seats sold
+	if payment.PaymentStatus != entity.PaymentFailed {
+		return nil
+	}
+	return s.seats.ReleaseUnpaidBooking(ctx, payment.BookingID)
+}
+
+// trackPayment records a booking payment outcome in the funnel analytics
+func (s *Service) trackPayment(ctx context.Context, name string, booking *entity.Booking, payment *entity.Payment) {
+	props := map[string]any{
+		"booking_id": booking.ID.String(),
+		"payment_id": payment.ID.String(),
+		"amount":     payment.Amount,
+	}
+	if payment.PaymentMethod != nil {
+		props["method"] = string(*payment.PaymentMethod)
+	}
+	s.tracker.Track(ctx, analytics.Event{
+		Name:       name,
+		UserID:     booking.UserID,
+		ShowtimeID: &booking.ShowtimeID,
+		Properties: props,
+	})
+}
+
+// AlertStaleWebhooks notifies admins about events that are still
+// unprocessed after the alert threshold. Each event is alerted on once.
+func (s *Service) AlertStaleWebhooks(ctx context.Context) error {
+	alertAfter := s.cfg.WebhookAlertAfter
+	if alertAfter <= 0 {
+		alertAfter = 10 * time.Minute
+	}
+
+	stale, err := s.webhookRepo.ListStale(ctx, time.Now().Add(-alertAfter), sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(stale))
+	eventIDs := make([]string, len(stale))
+	for i, event := range stale {
+		ids[i] = event.ID
+		eventIDs[i] = event.ID.String()
+	}
+
+	s.logger.Error("payment webhooks left unprocessed",
+		zap.Int("count", len(stale)),
+		zap.Time("oldest_received_at", stale[0].ReceivedAt),
+		zap.Strings("webhook_ids", eventIDs),
+	)
+
+	admins, err := s.userRepo.ListByRoles(ctx, entity.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	for _, admin := range admins {
+		s.dispatcher.SubmitNotification(async.NotificationPayload{
+			UserID:  admin.ID,
+			Type:    "webhook_backlog",
+			Title:   "Payment webhooks need attention",
+			Message: fmt.Sprintf("%d payment webhook events have not been processed for over %s. Review and replay them from the webhook inbox.", len(stale), alertAfter),
+			Data: map[string]interface{}{
+				"webhook_ids":        eventIDs,
+				"oldest_received_at": stale[0].ReceivedAt,
+			},
+		})
+	}
+
+	return s.webhookRepo.MarkAlerted(ctx, ids, time.Now())
+}
+
+func toWebhookEventResponse(event *entity.WebhookEvent) *WebhookEventResponse {
+	return &WebhookEventResponse{
+		ID:               event.ID,
+		Provider:         event.Provider,
+		EventID:          event.EventID,
+		EventType:        event.EventType,
+		Payload:          event.Payload,
+		SignatureValid:   event.SignatureValid,
+		Status:           string(event.Status),
+		Attempts:         event.Attempts,
+		LastError:        event.LastError,
+		PaymentReference: event.PaymentReference,
+		BookingID:        event.BookingID,
+		PaymentID:        event.PaymentID,
+		ReceivedAt:       event.ReceivedAt,
+		ProcessedAt:      event.ProcessedAt,
+	}
+}
@@ -0,0 +1,393 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/outbox"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const testSecret = "whsec_test"
+
+// memWebhooks is a WebhookEventRepository kept in memory
+type memWebhooks struct {
+	repository.WebhookEventRepository
+
+	mu     sync.Mutex
+	events map[uuid.UUID]*entity.WebhookEvent
+}
+
+func (m *memWebhooks) Create(_ context.Context, event *entity.WebhookEvent) (*entity.WebhookEvent, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.events {
+		if stored.Provider == event.Provider && stored.EventID == event.EventID {
+			copied := *stored
+			return &copied, false, nil
+		}
+	}
+	event.ID = uuid.New()
+	copied := *event
+	m.events[event.ID] = &copied
+	return event, true, nil
+}
+
+func (m *memWebhooks) GetByID(_ context.Context, id uuid.UUID) (*entity.WebhookEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.events[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("webhook event")
+	}
+	copied := *stored
+	return &copied, nil
+}
+
+func (m *memWebhooks) IncrementAttempts(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[id].Attempts++
+	return nil
+}
+
+func (m *memWebhooks) Update(_ context.Context, event *entity.WebhookEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *event
+	m.events[event.ID] = &copied
+	return nil
+}
+
+func (m *memWebhooks) only(t *testing.T) *entity.WebhookEvent {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.events) != 1 {
+		t.Fatalf("inbox holds %d events, want 1", len(m.events))
+	}
+	for _, event := range m.events {
+		copied := *event
+		return &copied
+	}
+	return nil
+}
+
+// memPayments holds a single booking payment
+type memPayments struct {
+	repository.PaymentRepository
+	payment *entity.Payment
+}
+
+func (m *memPayments) GetByReference(_ context.Context, ref string) (*entity.Payment, error) {
+	if m.payment == nil || m.payment.PaymentReference != ref {
+		return nil, apperrors.ErrNotFound("payment")
+	}
+	copied := *m.payment
+	return &copied, nil
+}
+
+func (m *memPayments) GetByGatewayTransactionID(_ context.Context, transactionID string) (*entity.Payment, error) {
+	if m.payment == nil || m.payment.GatewayTransactionID == nil || *m.payment.GatewayTransactionID != transactionID {
+		return nil, apperrors.ErrNotFound("payment")
+	}
+	copied := *m.payment
+	return &copied, nil
+}
+
+func (m *memPayments) Update(_ context.Context, payment *entity.Payment) error {
+	copied := *payment
+	m.payment = &copied
+	return nil
+}
+
+// crashingBookings panics on the first status update to simulate the
+// process dying halfway through a webhook
+type crashingBookings struct {
+	repository.BookingRepository
+	booking       *entity.Booking
+	crash         bool
+	statusUpdates int
+}
+
+func (m *crashingBookings) GetByID(_ context.Context, id uuid.UUID) (*entity.Booking, error) {
+	if m.booking.ID != id {
+		return nil, apperrors.ErrNotFound("booking")
+	}
+	copied := *m.booking
+	return &copied, nil
+}
+
+func (m *crashingBookings) UpdatePaymentStatus(_ context.Context, _ uuid.UUID, status entity.PaymentStatus) error {
+	m.booking.PaymentStatus = status
+	return nil
+}
+
+func (m *crashingBookings) UpdateStatus(_ context.Context, _ uuid.UUID, status entity.BookingStatus) error {
+	if m.crash {
+		m.crash = false
+		panic("process killed")
+	}
+	m.statusUpdates++
+	m.booking.BookingStatus = status
+	return nil
+}
+
+// noShares stands in for group checkout; no test payment settles a share
+type noShares struct{}
+
+func (noShares) MarkSharePaid(context.Context, string, string) error {
+	return apperrors.ErrNotFound("share")
+}
+
+// inlineTx runs the work as it comes
+type inlineTx struct{}
+
+func (inlineTx) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// memOutbox records the events added to the outbox
+type memOutbox struct {
+	repository.OutboxRepository
+	events []*entity.OutboxEvent
+}
+
+func (m *memOutbox) Add(_ context.Context, events []*entity.OutboxEvent) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *memOutbox) count(name string) int {
+	var n int
+	for _, event := range m.events {
+		if event.EventName == name {
+			n++
+		}
+	}
+	return n
+}
+
+type webhookFixture struct {
+	svc      *Service
+	inbox    *memWebhooks
+	payments *memPayments
+	bookings *crashingBookings
+	gateway  *stubGateway
+	changes  *memChanges
+	outbox   *memOutbox
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	t.Helper()
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	booking := &entity.Booking{
+		ID:               uuid.New(),
+		BookingReference: "BK-TEST",
+		BookingStatus:    entity.BookingPending,
+		PaymentStatus:    entity.PaymentPending,
+	}
+
+	f := &webhookFixture{
+		inbox: &memWebhooks{events: make(map[uuid.UUID]*entity.WebhookEvent)},
+		payments: &memPayments{payment: &entity.Payment{
+			ID:               uuid.New(),
+			BookingID:        booking.ID,
+			PaymentReference: "PAY-TEST",
+			PaymentStatus:    entity.PaymentPending,
+		}},
+		bookings: &crashingBookings{booking: booking},
+		gateway:  &stubGateway{},
+		changes:  &memChanges{},
+		outbox:   &memOutbox{},
+	}
+
+	f.svc = NewService(f.inbox, f.payments, f.bookings, nil, noShares{}, nil,
+		f.gateway, changelog.NewService(f.changes, log),
+		async.NewDispatcher(1, 10, nil, log), inlineTx{}, outbox.New(f.outbox), nil,
+		config.PaymentConfig{
+			Provider:             "test",
+			WebhookSecret:        testSecret,
+			ReconcileBackoff:     time.Minute,
+			ReconcileMaxAttempts: 3,
+		}, log)
+	return f
+}
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func succeeded(eventID string) []byte {
+	return []byte(fmt.Sprintf(`{"id":%q,"type":"payment.succeeded","data":{"payment_reference":"PAY-TEST","transaction_id":"txn-1"}}`, eventID))
+}
+
+// receiveCrashing delivers a webhook whose processing dies midway
+func (f *webhookFixture) receiveCrashing(t *testing.T, body []byte) {
+	t.Helper()
+	f.bookings.crash = true
+	defer func() {
+		if recover() == nil {
+			t.Fatal("processing did not crash")
+		}
+	}()
+	_, _ = f.svc.ReceiveWebhook(context.Background(), sign(body), body)
+}
+
+func TestWebhookCrashLeavesEventReplayable(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.receiveCrashing(t, succeeded("evt_1"))
+
+	event := f.inbox.only(t)
+	if event.Status != entity.WebhookReceived || !event.Status.IsReplayable() {
+		t.Fatalf("after the crash the event is %s, want it replayable", event.Status)
+	}
+	if f.payments.payment.PaymentStatus != entity.PaymentPaid {
+		t.Fatal("the crash should come after the payment was recorded")
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingPending {
+		t.Fatalf("booking = %s, want it still pending", f.bookings.booking.BookingStatus)
+	}
+}
+
+func TestWebhookReplayCompletesBooking(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	f.receiveCrashing(t, succeeded("evt_1"))
+	id := f.inbox.only(t).ID
+
+	res, err := f.svc.ReplayWebhookEvent(ctx, id)
+	if err != nil {
+		t.Fatalf("ReplayWebhookEvent: %v", err)
+	}
+	if res.Status != string(entity.WebhookProcessed) {
+		t.Fatalf("replayed event is %s, want PROCESSED", res.Status)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingConfirmed || f.bookings.booking.PaymentStatus != entity.PaymentPaid {
+		t.Fatalf("booking = %s/%s, want CONFIRMED/PAID", f.bookings.booking.BookingStatus, f.bookings.booking.PaymentStatus)
+	}
+	if event := f.inbox.only(t); event.BookingID == nil || *event.BookingID != f.bookings.booking.ID {
+		t.Errorf("event not linked to the booking: %v", event.BookingID)
+	}
+
+	t.Run("double replay changes nothing", func(t *testing.T) {
+		before := f.inbox.only(t)
+
+		batch, err := f.svc.ReplayWebhookEvents(ctx, ReplayBatchRequest{IDs: []uuid.UUID{id, id}})
+		if err != nil {
+			t.Fatalf("ReplayWebhookEvents: %v", err)
+		}
+		if batch.Processed != 2 || batch.Failed != 0 {
+			t.Errorf("batch = %d processed, %d failed", batch.Processed, batch.Failed)
+		}
+
+		after := f.inbox.only(t)
+		if after.Attempts != before.Attempts || !after.ProcessedAt.Equal(*before.ProcessedAt) {
+			t.Errorf("replay touched a processed event: %d attempts, was %d", after.Attempts, before.Attempts)
+		}
+		if f.bookings.statusUpdates != 1 {
+			t.Errorf("booking status updated %d times, want 1", f.bookings.statusUpdates)
+		}
+		if got := f.outbox.count(events.BookingConfirmedEvent); got != 1 {
+			t.Errorf("BookingConfirmed published %d times, want 1", got)
+		}
+	})
+}
+
+func TestWebhookRedeliveryIsAcknowledged(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	body := succeeded("evt_1")
+
+	for i := 0; i < 2; i++ {
+		ack, err := f.svc.ReceiveWebhook(ctx, sign(body), body)
+		if err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+		if ack.Status != string(entity.WebhookProcessed) {
+			t.Errorf("delivery %d acknowledged as %s", i+1, ack.Status)
+		}
+	}
+	if event := f.inbox.only(t); event.Attempts != 1 {
+		t.Errorf("event processed %d times, want 1", event.Attempts)
+	}
+}
+
+func TestWebhookInvalidSignatureIsRejected(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	body := succeeded("evt_1")
+
+	if _, err := f.svc.ReceiveWebhook(ctx, sign([]byte("other")), body); !apperrors.Is(err, apperrors.CodeUnauthorized) {
+		t.Fatalf("ReceiveWebhook = %v, want unauthorized", err)
+	}
+	event := f.inbox.only(t)
+	if event.Status != entity.WebhookRejected || event.SignatureValid || event.EventID == "evt_1" {
+		t.Errorf("stored %s event %s, signature valid %v", event.Status, event.EventID, event.SignatureValid)
+	}
+	if _, err := f.svc.ReplayWebhookEvent(ctx, event.ID); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("replay of a rejected event = %v, want a conflict", err)
+	}
+
+	// The genuine delivery is not shadowed by the forged one
+	if _, err := f.svc.ReceiveWebhook(ctx, sign(body), body); err != nil {
+		t.Fatalf("genuine delivery: %v", err)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingConfirmed {
+		t.Errorf("booking = %s, want CONFIRMED", f.bookings.booking.BookingStatus)
+	}
+}
+
+func TestWebhookIntentMatchedByTransactionID(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t)
+	intentID := "pi_1"
+	f.payments.payment.GatewayTransactionID = &intentID
+
+	// Intents created outside a checkout session carry no payment reference
+	unknown := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_other","metadata":{}}}}`)
+	ack, err := f.svc.ReceiveWebhook(ctx, sign(unknown), unknown)
+	if err != nil {
+		t.Fatalf("unknown intent: %v", err)
+	}
+	if ack.Status != string(entity.WebhookIgnored) {
+		t.Errorf("unknown intent acknowledged as %s, want IGNORED", ack.Status)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingPending {
+		t.Fatalf("booking = %s after an unknown intent, want it still pending", f.bookings.booking.BookingStatus)
+	}
+
+	body := []byte(`{"id":"evt_2","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","metadata":{}}}}`)
+	ack, err = f.svc.ReceiveWebhook(ctx, sign(body), body)
+	if err != nil {
+		t.Fatalf("ReceiveWebhook: %v", err)
+	}
+	if ack.Status != string(entity.WebhookProcessed) {
+		t.Errorf("intent acknowledged as %s, want PROCESSED", ack.Status)
+	}
+	if f.payments.payment.PaymentStatus != entity.PaymentPaid {
+		t.Errorf("payment = %s, want PAID", f.payments.payment.PaymentStatus)
+	}
+	if f.bookings.booking.BookingStatus != entity.BookingConfirmed {
+		t.Errorf("booking = %s, want CONFIRMED", f.bookings.booking.BookingStatus)
+	}
+}
@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/config"
+)
+
+func hmacHex(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// stripeHeader signs body like Stripe does, once per secret
+func stripeHeader(signedAt time.Time, body string, secrets ...string) string {
+	t := fmt.Sprint(signedAt.Unix())
+	header := "t=" + t
+	for _, secret := range secrets {
+		header += ",v1=" + hmacHex(secret, t+"."+body)
+	}
+	return header
+}
+
+func TestVerifySignature(t *testing.T) {
+	const (
+		secret = "whsec_current"
+		old    = "whsec_previous"
+		body   = `{"id":"evt_1","type":"checkout.session.completed"}`
+	)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		body      string
+		want      bool
+	}{
+		{"plain hex", secret, hmacHex(secret, body), body, true},
+		{"plain hex with prefix", secret, "sha256=" + hmacHex(secret, body), body, true},
+		{"plain hex, wrong secret", secret, hmacHex(old, body), body, false},
+		{"plain hex, tampered body", secret, hmacHex(secret, body), body + " ", false},
+		{"not hex", secret, "sha256=zz", body, false},
+		{"no signature", secret, "", body, false},
+		{"no secret configured", "", hmacHex("", body), body, false},
+
+		{"stripe", secret, stripeHeader(now, body, secret), body, true},
+		{"stripe, spaces after commas", secret, fmt.Sprintf("t=%d, v1=%s", now.Unix(), hmacHex(secret, fmt.Sprint(now.Unix())+"."+body)), body, true},
+		{"stripe, tampered body", secret, stripeHeader(now, body, secret), `{"id":"evt_2"}`, false},
+		{"stripe, wrong secret", secret, stripeHeader(now, body, old), body, false},
+		{"stripe, stale timestamp", secret, stripeHeader(now.Add(-stripeSignatureTolerance-time.Minute), body, secret), body, false},
+		{"stripe, timestamp from the future", secret, stripeHeader(now.Add(stripeSignatureTolerance+time.Minute), body, secret), body, false},
+		{"stripe, timestamp within tolerance", secret, stripeHeader(now.Add(-stripeSignatureTolerance+time.Minute), body, secret), body, true},
+		{"stripe, rolled secret signs with both", secret, stripeHeader(now, body, old, secret), body, true},
+		{"stripe, rolled secret still configured as the old one", old, stripeHeader(now, body, old, secret), body, true},
+		{"stripe, rolled away from the configured secret", "whsec_other", stripeHeader(now, body, old, secret), body, false},
+		{"stripe, no timestamp", secret, "v1=" + hmacHex(secret, "."+body), body, false},
+		{"stripe, bad timestamp", secret, "t=soon,v1=" + hmacHex(secret, "soon."+body), body, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{cfg: config.PaymentConfig{WebhookSecret: tt.secret}}
+			if got := s.verifySignature(tt.signature, []byte(tt.body)); got != tt.want {
+				t.Errorf("verifySignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// assistiveDeviceRepository implements repository.AssistiveDeviceRepository
+type assistiveDeviceRepository struct {
+	db *Database
+}
+
+// NewAssistiveDeviceRepository creates a new assistive device repository
+func NewAssistiveDeviceRepository(db *Database) repository.AssistiveDeviceRepository {
+	return &assistiveDeviceRepository{db: db}
+}
+
+func (r *assistiveDeviceRepository) ListByScreen(ctx context.Context, screenID uuid.UUID) ([]*entity.ScreenDevice, error) {
+	var devices []*entity.ScreenDevice
+	if err := r.db.WithContext(ctx).
+		Where("screen_id = ?", screenID).
+		Order("device_type").
+		Find(&devices).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list screen devices")
+	}
+	return devices, nil
+}
+
+func (r *assistiveDeviceRepository) SetInventory(ctx context.Context, screenID uuid.UUID, devices []*entity.ScreenDevice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("screen_id = ?", screenID).Delete(&entity.ScreenDevice{}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update screen devices")
+		}
+		for _, device := range devices {
+			device.ScreenID = screenID
+		}
+		if len(devices) > 0 {
+			if err := tx.Create(&devices).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update screen devices")
+			}
+		}
+		return nil
+	})
+}
+
+func (r *assistiveDeviceRepository) Reserved(ctx context.Context, showtimeID uuid.UUID) (map[entity.DeviceType]int, error) {
+	return reservedDevices(r.db.WithContext(ctx), showtimeID)
+}
+
+func (r *assistiveDeviceRepository) Reserve(ctx context.Context, booking *entity.Booking, requests []entity.DeviceRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stock []*entity.ScreenDevice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("screen_id = (?)", tx.Session(&gorm.Session{NewDB: true}).
+				Model(&entity.Showtime{}).Select("screen_id").Where("id = ?", booking.ShowtimeID)).
+			Find(&stock).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock screen devices")
+		}
+
+		reserved, err := reservedDevices(tx, booking.ShowtimeID)
+		if err != nil {
+			return err
+		}
+		if shortages := entity.CheckDevices(requests, stock, reserved); len(shortages) > 0 {
+			return apperrors.New(apperrors.CodeDeviceUnavailable, "not enough assistive devices available").
+				WithDetails(shortages)
+		}
+
+		devices := make([]*entity.BookingDevice, 0, len(requests))
+		for _, req := range requests {
+			devices = append(devices, &entity.BookingDevice{
+				BookingID:  booking.ID,
+				DeviceType: req.DeviceType,
+				ShowtimeID: booking.ShowtimeID,
+				Quantity:   req.Quantity,
+			})
+		}
+		if err := tx.Create(&devices).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to reserve devices")
+		}
+		return nil
+	})
+}
+
+// reservedDevices sums the devices held by the showtime's confirmed bookings
+func reservedDevices(db *gorm.DB, showtimeID uuid.UUID) (map[entity.DeviceType]int, error) {
+	var rows []struct {
+		DeviceType entity.DeviceType
+		Total      int
+	}
+	err := db.Table("booking_devices").
+		Select("booking_devices.device_type, SUM(booking_devices.quantity) AS total").
+		Joins("JOIN bookings ON bookings.id = booking_devices.booking_id").
+		Where("booking_devices.showtime_id = ?", showtimeID).
+		Where("bookings.booking_status IN ? AND bookings.deleted_at IS NULL",
+			[]entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted}).
+		Group("booking_devices.device_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count reserved devices")
+	}
+
+	reserved := make(map[entity.DeviceType]int, len(rows))
+	for _, row := range rows {
+		reserved[row.DeviceType] = row.Total
+	}
+	return reserved, nil
+}
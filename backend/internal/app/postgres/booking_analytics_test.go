@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+)
+
+func TestBookingAnalyticsQueries(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	// Two days before the showtime, 10 tickets are bought and 2 more are
+	// cancelled; the day before, 5 more are bought
+	f.book(t, entity.BookingConfirmed, 10, 48, f.startsAt)
+	f.book(t, entity.BookingCancelled, 2, 47, f.startsAt)
+	f.book(t, entity.BookingConfirmed, 5, 24, f.startsAt)
+	from := time.Date(2030, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	series, err := repo.GetBookingTimeSeries(ctx, &f.cinema.ID, from, to, "day")
+	if err != nil {
+		t.Fatalf("GetBookingTimeSeries: %v", err)
+	}
+	if len(series) != 3 {
+		t.Fatalf("time series = %+v, want 3 days", series)
+	}
+	if series[0].TotalBookings != 2 || series[0].CancelledBookings != 1 || series[1].TotalBookings != 1 {
+		t.Errorf("bookings by day = %+v", series)
+	}
+	// The fixture marks every booking paid, the cancelled one included
+	if series[0].Revenue != 120 || series[1].Revenue != 50 {
+		t.Errorf("revenue by day = %v, %v, want 120, 50", series[0].Revenue, series[1].Revenue)
+	}
+	// The showtime plays on the third day with 17 of its 100 seats paid for
+	if series[2].TotalBookings != 0 || series[2].OccupancyRate != 17 {
+		t.Errorf("showtime day = %+v, want no bookings and 17%% occupancy", series[2])
+	}
+
+	if _, err := repo.GetBookingTimeSeries(ctx, nil, from, to, "hour"); err == nil {
+		t.Errorf("grouping by hour succeeded")
+	}
+
+	movies, err := repo.GetTopMovies(ctx, &f.cinema.ID, 5, from, to)
+	if err != nil {
+		t.Fatalf("GetTopMovies: %v", err)
+	}
+	if len(movies) != 1 || movies[0].MovieID != f.movie.ID || movies[0].Tickets != 17 {
+		t.Errorf("top movies = %+v", movies)
+	}
+
+	screens, err := repo.GetRevenueByScreenType(ctx, &f.cinema.ID, from, to)
+	if err != nil {
+		t.Fatalf("GetRevenueByScreenType: %v", err)
+	}
+	if len(screens) != 1 || screens[0].ScreenType != entity.ScreenIMAX || screens[0].Revenue != 170 {
+		t.Errorf("revenue by screen type = %+v", screens)
+	}
+}
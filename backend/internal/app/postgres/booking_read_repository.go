@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// The repositories in this file are rewritten read paths that are verified
+// against the current implementations with shadow reads before they serve
+// traffic. Writes are inherited unchanged.
+
+// singleQueryBookingRepository lists bookings and their total count in one
+// round trip using a window function instead of a separate COUNT query
+type singleQueryBookingRepository struct {
+	*bookingRepository
+}
+
+// NewSingleQueryBookingRepository creates a booking repository with the
+// single-query list implementation
+func NewSingleQueryBookingRepository(db *Database) repository.BookingRepository {
+	return &singleQueryBookingRepository{bookingRepository: &bookingRepository{db: db}}
+}
+
+type bookingWithTotal struct {
+	entity.Booking
+	TotalCount int64 `gorm:"column:total_count"`
+}
+
+func (r *singleQueryBookingRepository) List(ctx context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	var rows []bookingWithTotal
+
+	if err := validateBookingFilter(filter); err != nil {
+		return nil, 0, err
+	}
+
+	db := applyBookingFilter(r.db.WithContext(ctx).Model(&entity.Booking{}), filter)
+	if err := db.Select("bookings.*, COUNT(*) OVER() AS total_count").
+		Offset(offset).Limit(limit).Order(bookingOrder(filter)).
+		Find(&rows).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list bookings")
+	}
+
+	// A page past the end has no rows to carry the count
+	if len(rows) == 0 && offset > 0 {
+		var total int64
+		if err := applyBookingFilter(r.db.WithContext(ctx).Model(&entity.Booking{}), filter).Count(&total).Error; err != nil {
+			return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count bookings")
+		}
+		return []*entity.Booking{}, total, nil
+	}
+
+	bookings := make([]*entity.Booking, len(rows))
+	var total int64
+	for i := range rows {
+		bookings[i] = &rows[i].Booking
+		total = rows[i].TotalCount
+	}
+	return bookings, total, nil
+}
+
+func (r *singleQueryBookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Booking, int64, error) {
+	return r.List(ctx, repository.BookingFilter{UserID: &userID}, offset, limit)
+}
+
+// semiJoinBookingSeatRepository reads booked seats with an EXISTS semi-join
+// so a seat appears once regardless of how many bookings reference it
+type semiJoinBookingSeatRepository struct {
+	*bookingSeatRepository
+}
+
+// NewSemiJoinBookingSeatRepository creates a booking seat repository with
+// the semi-join booked seat implementation
+func NewSemiJoinBookingSeatRepository(db *Database) repository.BookingSeatRepository {
+	return &semiJoinBookingSeatRepository{bookingSeatRepository: &bookingSeatRepository{db: db}}
+}
+
+func (r *semiJoinBookingSeatRepository) GetBookedSeatIDs(ctx context.Context, showtimeID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&entity.BookingSeat{}).
+		Distinct("booking_seats.seat_id").
+		Where("booking_seats.showtime_id = ?", showtimeID).
+		Where("EXISTS (SELECT 1 FROM bookings WHERE bookings.id = booking_seats.booking_id AND bookings.booking_status IN ?)",
+			[]entity.BookingStatus{entity.BookingPending, entity.BookingConfirmed, entity.BookingCompleted}).
+		Pluck("booking_seats.seat_id", &ids).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booked seats")
+	}
+	return ids, nil
+}
@@ -0,0 +1,1061 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bookingRepository implements repository.BookingRepository
+type bookingRepository struct {
+	db *Database
+}
+
+// NewBookingRepository creates a new booking repository
+func NewBookingRepository(db *Database) repository.BookingRepository {
+	return &bookingRepository{db: db}
+}
+
+func (r *bookingRepository) Create(ctx context.Context, booking *entity.Booking) error {
+	if err := conn(ctx, r.db).Create(booking).Error; err != nil {
+		return wrapWriteError(err, "failed to create booking")
+	}
+	return nil
+}
+
+func (r *bookingRepository) CreateWithSeats(ctx context.Context, booking *entity.Booking, seats []*entity.BookingSeat) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Omit associations so the preloaded relations are not upserted
+		if err := tx.Omit(clause.Associations).Create(booking).Error; err != nil {
+			return wrapWriteError(err, "failed to create booking")
+		}
+		if booking.PromoCodeID != nil {
+			if err := redeemPromoCode(tx, booking); err != nil {
+				return err
+			}
+		}
+		if booking.PointsRedeemed > 0 {
+			if err := redeemLoyaltyPoints(tx, booking); err != nil {
+				return err
+			}
+		}
+
+		for _, seat := range seats {
+			seat.BookingID = booking.ID
+			seat.ShowtimeID = booking.ShowtimeID
+		}
+		if len(seats) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&seats).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeSeatsAlreadyBooked, "failed to reserve booking seats")
+			}
+		}
+
+		seatIDs := make([]uuid.UUID, 0, len(seats))
+		for _, seat := range seats {
+			seatIDs = append(seatIDs, seat.SeatID)
+		}
+		if err := checkOnlineCaps(tx, booking, seatIDs); err != nil {
+			return err
+		}
+
+		// A booking that committed since the version was read makes this
+		// one fail with a version conflict rather than oversell
+		var showtime entity.Showtime
+		if err := tx.Select("id", "version").First(&showtime, "id = ?", booking.ShowtimeID).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get showtime")
+		}
+		return decrementAvailableSeats(tx, booking.ShowtimeID, len(seats), showtime.Version)
+	})
+}
+
+func (r *bookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := conn(ctx, r.db).First(&booking, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking")
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) GetByIDWithDetails(ctx context.Context, id uuid.UUID) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := conn(ctx, r.db).
+		Preload("Showtime", unscoped).
+		Preload("Showtime.Movie", unscoped).
+		Preload("Showtime.Cinema", unscoped).
+		Preload("Showtime.Screen", unscoped).
+		Preload("BookingSeats").
+		Preload("BookingSeats.Seat", unscoped).
+		Preload("Payments").
+		Preload("Devices").
+		First(&booking, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking")
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) GetByReference(ctx context.Context, reference string) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := conn(ctx, r.db).First(&booking, "booking_reference = ?", reference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking")
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) Update(ctx context.Context, booking *entity.Booking) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(booking).Error; err != nil {
+		return wrapWriteError(err, "failed to update booking")
+	}
+	return nil
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	return r.list(conn(ctx, r.db), filter, offset, limit)
+}
+
+func (r *bookingRepository) Search(ctx context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	db := conn(ctx, r.db).
+		Preload("User", unscoped).
+		Preload("Showtime", unscoped).
+		Preload("Showtime.Movie", unscoped).
+		Preload("Showtime.Cinema", unscoped).
+		Preload("Showtime.Screen", unscoped).
+		Preload("BookingSeats").
+		Preload("BookingSeats.Seat", unscoped).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		})
+	return r.list(db, filter, offset, limit)
+}
+
+func (r *bookingRepository) list(db *gorm.DB, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	var bookings []*entity.Booking
+	var total int64
+
+	if err := validateBookingFilter(filter); err != nil {
+		return nil, 0, err
+	}
+
+	db = applyBookingFilter(db.Model(&entity.Booking{}), filter)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count bookings")
+	}
+
+	if err := db.Offset(offset).Limit(limit).Order(bookingOrder(filter)).Find(&bookings).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list bookings")
+	}
+
+	return bookings, total, nil
+}
+
+// validateBookingFilter rejects status filters that can never match
+func validateBookingFilter(filter repository.BookingFilter) error {
+	if filter.BookingStatus != nil {
+		if err := entity.CheckBookingTransition("", *filter.BookingStatus); err != nil {
+			return apperrors.ErrValidation(err.Error())
+		}
+	}
+	if filter.PaymentStatus != nil {
+		if err := entity.CheckPaymentTransition("", *filter.PaymentStatus); err != nil {
+			return apperrors.ErrValidation(err.Error())
+		}
+	}
+	return nil
+}
+
+// statusConflict turns a rejected status into a conflict error. A nil err
+// means the stored status changed between the update and the check.
+func statusConflict(err error) error {
+	var statusErr *entity.StatusError
+	if !errors.As(err, &statusErr) {
+		return apperrors.New(apperrors.CodeConflict, "status was changed concurrently")
+	}
+	return apperrors.New(apperrors.CodeInvalidStatus, statusErr.Error()).WithDetails(map[string]any{
+		"field":   statusErr.Field,
+		"from":    statusErr.From,
+		"to":      statusErr.To,
+		"allowed": statusErr.Allowed,
+	})
+}
+
+// wrapWriteError reports a status rejected by a BeforeSave hook as a
+// conflict and anything else as an internal error
+func wrapWriteError(err error, message string) error {
+	var statusErr *entity.StatusError
+	if errors.As(err, &statusErr) {
+		return statusConflict(err)
+	}
+	return apperrors.Wrap(err, apperrors.CodeInternal, message)
+}
+
+// applyBookingFilter adds the WHERE clauses for a booking filter
+func applyBookingFilter(db *gorm.DB, filter repository.BookingFilter) *gorm.DB {
+	if filter.UserID != nil {
+		db = db.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.ShowtimeID != nil {
+		db = db.Where("showtime_id = ?", *filter.ShowtimeID)
+	}
+	if filter.CinemaID != nil {
+		db = db.Where("showtime_id IN (SELECT id FROM showtimes WHERE cinema_id = ?)", *filter.CinemaID)
+	}
+	if filter.Reference != "" {
+		db = db.Where("booking_reference = ?", filter.Reference)
+	}
+	if filter.Email != "" {
+		// Each side is served by an index on the lowercased email
+		email := strings.ToLower(filter.Email)
+		db = db.Where("(LOWER(guest_email) = ? OR user_id IN (SELECT id FROM users WHERE LOWER(email) = ?))", email, email)
+	}
+	if filter.BookingStatus != nil {
+		db = db.Where("booking_status = ?", *filter.BookingStatus)
+	}
+	if filter.PaymentStatus != nil {
+		db = db.Where("payment_status = ?", *filter.PaymentStatus)
+	}
+	if filter.DateFrom != nil {
+		db = db.Where("booked_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		db = db.Where("booked_at <= ?", *filter.DateTo)
+	}
+	if filter.CheckedIn {
+		db = db.Where("checked_in_at IS NOT NULL")
+	}
+	switch filter.Period {
+	case repository.BookingsUpcoming:
+		db = db.Where("booking_status = ? AND showtime_id IN (?)", entity.BookingConfirmed, showtimesStarted(db, ">", time.Now()))
+	case repository.BookingsPast:
+		db = db.Where("(booking_status = ? OR (booking_status = ? AND showtime_id IN (?)))",
+			entity.BookingCompleted, entity.BookingConfirmed, showtimesStarted(db, "<=", time.Now()))
+	case repository.BookingsCancelled:
+		db = db.Where("booking_status IN ?", []entity.BookingStatus{entity.BookingCancelled, entity.BookingRefunded})
+	}
+	if filter.ShowDateFrom != nil {
+		db = db.Where("showtime_id IN (SELECT id FROM showtimes WHERE show_date >= ?::date)", filter.ShowDateFrom.Format(time.DateOnly))
+	}
+	if filter.ShowDateTo != nil {
+		db = db.Where("showtime_id IN (SELECT id FROM showtimes WHERE show_date <= ?::date)", filter.ShowDateTo.Format(time.DateOnly))
+	}
+	return db
+}
+
+// showtimesStarted is a subquery of the IDs of showtimes, deleted ones
+// included, whose start in their cinema's timezone compares to at by op
+func showtimesStarted(db *gorm.DB, op string, at time.Time) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Table("showtimes").
+		Select("showtimes.id").
+		Joins("JOIN cinemas ON cinemas.id = showtimes.cinema_id").
+		Where("(showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC') "+op+" ?", at)
+}
+
+// bookingOrder orders a booking list by booked_at, or check-ins by when
+// they were scanned, the ID breaking ties
+func bookingOrder(filter repository.BookingFilter) string {
+	if filter.CheckedIn {
+		return "checked_in_at DESC, id DESC"
+	}
+	if filter.OldestFirst {
+		return "booked_at ASC, id ASC"
+	}
+	return "booked_at DESC, id DESC"
+}
+
+func (r *bookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Booking, int64, error) {
+	return r.List(ctx, repository.BookingFilter{UserID: &userID}, offset, limit)
+}
+
+// ListAfter seeks past the cursor on (booked_at, id) instead of skipping
+// rows, so deep pages cost as much as the first
+func (r *bookingRepository) ListAfter(ctx context.Context, filter repository.BookingFilter, after *repository.BookingCursor, limit int) ([]*entity.Booking, error) {
+	db := applyBookingFilter(conn(ctx, r.db), filter)
+	if after != nil {
+		db = db.Where("(booked_at, id) < (?, ?)", after.BookedAt, after.ID)
+	}
+
+	var bookings []*entity.Booking
+	if err := db.Order("booked_at DESC, id DESC").Limit(limit).Find(&bookings).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list bookings")
+	}
+	return bookings, nil
+}
+
+// UpdateStatus moves a booking to status if the transition table allows it
+// from the stored status. The check is part of the UPDATE so a concurrent
+// change cannot slip through.
+func (r *bookingRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.BookingStatus) error {
+	if err := entity.CheckBookingTransition("", status); err != nil {
+		return statusConflict(err)
+	}
+
+	updates := map[string]any{"booking_status": status}
+	if status == entity.BookingConfirmed {
+		updates["confirmed_at"] = time.Now()
+	}
+	result := conn(ctx, r.db).Model(&entity.Booking{}).
+		Where("id = ? AND booking_status IN ?", id, entity.BookingStatusesBefore(status)).
+		Updates(updates)
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update booking status")
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return statusConflict(entity.CheckBookingTransition(current.BookingStatus, status))
+	}
+	return nil
+}
+
+// UpdatePaymentStatus moves a booking's payment status, with the same
+// transition check as UpdateStatus
+func (r *bookingRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status entity.PaymentStatus) error {
+	if err := entity.CheckPaymentTransition("", status); err != nil {
+		return statusConflict(err)
+	}
+
+	result := conn(ctx, r.db).Model(&entity.Booking{}).
+		Where("id = ? AND payment_status IN ?", id, entity.PaymentStatusesBefore(status)).
+		Update("payment_status", status)
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update payment status")
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return statusConflict(entity.CheckPaymentTransition(current.PaymentStatus, status))
+	}
+	return nil
+}
+
+func (r *bookingRepository) SetTicketURL(ctx context.Context, id uuid.UUID, url string) error {
+	result := conn(ctx, r.db).Model(&entity.Booking{}).Where("id = ?", id).Update("ticket_url", url)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to set ticket URL")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+	}
+	return nil
+}
+
+func (r *bookingRepository) CheckIn(ctx context.Context, id, staffID uuid.UUID, at time.Time) error {
+	// The conditions make a second scan of the same ticket a no-op, however
+	// close together the two scans are
+	result := conn(ctx, r.db).Model(&entity.Booking{}).
+		Where("id = ? AND booking_status = ? AND payment_status = ? AND checked_in_at IS NULL",
+			id, entity.BookingConfirmed, entity.PaymentPaid).
+		Updates(map[string]any{
+			"booking_status": entity.BookingCompleted,
+			"checked_in_at":  at,
+			"checked_in_by":  staffID,
+		})
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to check in booking")
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return apperrors.New(apperrors.CodeInvalidStatus, "booking can no longer be checked in")
+	}
+	return nil
+}
+
+func (r *bookingRepository) GetExpiredPendingBookings(ctx context.Context) ([]*entity.Booking, error) {
+	var bookings []*entity.Booking
+	if err := conn(ctx, r.db).
+		Where("booking_status = ? AND expires_at IS NOT NULL AND expires_at < ?", entity.BookingPending, time.Now()).
+		Find(&bookings).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get expired bookings")
+	}
+	return bookings, nil
+}
+
+func (r *bookingRepository) ExpirePending(ctx context.Context, before time.Time, limit int) ([]*entity.Booking, error) {
+	var bookings []*entity.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Rows another sweeper holds are skipped, so no booking's seats are
+		// released twice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("booking_status = ? AND expires_at IS NOT NULL AND expires_at < ?", entity.BookingPending, before).
+			Order("expires_at ASC").
+			Limit(limit).
+			Find(&bookings).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get expired bookings")
+		}
+
+		for _, booking := range bookings {
+			if err := tx.Model(&entity.Booking{}).
+				Where("id = ? AND booking_status = ?", booking.ID, entity.BookingPending).
+				Update("booking_status", entity.BookingExpired).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to expire booking")
+			}
+			booking.BookingStatus = entity.BookingExpired
+
+			if err := releaseBooking(tx, booking); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *bookingRepository) Expire(ctx context.Context, id uuid.UUID) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+			}
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking")
+		}
+		if err := entity.CheckBookingTransition(booking.BookingStatus, entity.BookingExpired); err != nil {
+			return statusConflict(err)
+		}
+
+		if err := tx.Model(&entity.Booking{}).Where("id = ?", id).
+			Update("booking_status", entity.BookingExpired).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to expire booking")
+		}
+		booking.BookingStatus = entity.BookingExpired
+
+		return releaseBooking(tx, &booking)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) Cancel(ctx context.Context, id uuid.UUID, refund float64) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.New(apperrors.CodeBookingNotFound, "booking not found")
+			}
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking")
+		}
+		if err := entity.CheckBookingTransition(booking.BookingStatus, entity.BookingCancelled); err != nil {
+			return statusConflict(err)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&entity.Booking{}).Where("id = ?", id).Updates(map[string]any{
+			"booking_status": entity.BookingCancelled,
+			"cancelled_at":   now,
+		}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to cancel booking")
+		}
+		booking.BookingStatus = entity.BookingCancelled
+		booking.CancelledAt = &now
+
+		if refund > 0 {
+			if err := refundBooking(tx, &booking, refund, now); err != nil {
+				return err
+			}
+		}
+		return releaseBooking(tx, &booking)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// refundBooking records a refund of a paid booking on the booking and its
+// paid payments. A booking paid in shares has the refund split across the
+// payments in proportion to what each paid.
+func refundBooking(tx *gorm.DB, booking *entity.Booking, amount float64, at time.Time) error {
+	if err := entity.CheckPaymentTransition(booking.PaymentStatus, entity.PaymentRefunded); err != nil {
+		return statusConflict(err)
+	}
+	if err := tx.Model(&entity.Booking{}).Where("id = ?", booking.ID).Updates(map[string]any{
+		"payment_status": entity.PaymentRefunded,
+		"refund_amount":  amount,
+	}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to refund booking")
+	}
+
+	var payments []entity.Payment
+	if err := tx.Where("booking_id = ? AND payment_status = ?", booking.ID, entity.PaymentPaid).
+		Order("created_at").
+		Find(&payments).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payments")
+	}
+	var paid float64
+	for _, p := range payments {
+		paid += p.Amount
+	}
+	remaining := amount
+	for i, p := range payments {
+		// The last payment takes what rounding left over
+		share := remaining
+		if i < len(payments)-1 && paid > 0 {
+			share = entity.RoundCents(amount * p.Amount / paid)
+		}
+		remaining = entity.RoundCents(remaining - share)
+
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", p.ID).Updates(map[string]any{
+			"payment_status": entity.PaymentRefunded,
+			"refunded_at":    at,
+			"refund_amount":  share,
+		}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to refund payment")
+		}
+	}
+	booking.PaymentStatus = entity.PaymentRefunded
+	booking.RefundAmount = &amount
+	return nil
+}
+
+// releaseBooking gives back what a booking that will not go ahead took: its
+// seats return to the showtime's available seats, the use of its promo
+// code is released and its loyalty points are undone. The released seats
+// are left in booking.BookingSeats.
+func releaseBooking(tx *gorm.DB, booking *entity.Booking) error {
+	if booking.PromoCodeID != nil {
+		if err := releasePromoCode(tx, booking); err != nil {
+			return err
+		}
+	}
+	if err := reverseLoyaltyPoints(tx, booking); err != nil {
+		return err
+	}
+
+	var released []entity.BookingSeat
+	seats := tx.Clauses(clause.Returning{}).Where("booking_id = ?", booking.ID).Delete(&released)
+	booking.BookingSeats = released
+	if seats.Error != nil {
+		return apperrors.Wrap(seats.Error, apperrors.CodeInternal, "failed to delete booking seats")
+	}
+	if seats.RowsAffected > 0 {
+		if err := tx.Model(&entity.Showtime{}).
+			Where("id = ?", booking.ShowtimeID).
+			UpdateColumns(map[string]any{
+				"available_seats": gorm.Expr("available_seats + ?", seats.RowsAffected),
+				"version":         gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update available seats")
+		}
+	}
+	return nil
+}
+
+// soldPaymentStatuses are the payment states of bookings that count as
+// sold in booking stats; refunds are taken off their revenue
+var soldPaymentStatuses = []entity.PaymentStatus{entity.PaymentPaid, entity.PaymentRefunded}
+
+func (r *bookingRepository) GetBookingStats(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time, top int) (*repository.BookingStats, error) {
+	confirmed := []entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted}
+	where, filterArgs := cinemaFilter(cinemaID, []any{dateFrom, dateTo})
+	from := `
+		FROM bookings
+		JOIN showtimes ON showtimes.id = bookings.showtime_id
+		WHERE bookings.booked_at >= ? AND bookings.booked_at < ?
+			AND bookings.deleted_at IS NULL` + where
+	db := conn(ctx, r.db)
+
+	var stats repository.BookingStats
+	err := db.Raw(`
+		SELECT COUNT(*) AS total_bookings,
+			COUNT(*) FILTER (WHERE bookings.booking_status IN ?) AS confirmed_bookings,
+			COUNT(*) FILTER (WHERE bookings.booking_status = ?) AS cancelled_bookings,
+			COALESCE(SUM(bookings.num_tickets) FILTER (WHERE bookings.payment_status IN ?), 0) AS tickets_sold,
+			COALESCE(SUM(bookings.final_amount) FILTER (WHERE bookings.payment_status IN ?), 0) AS gross_revenue,
+			COALESCE(SUM(bookings.refund_amount) FILTER (WHERE bookings.payment_status IN ?), 0) AS refunded_amount,
+			COALESCE(SUM(bookings.final_amount - COALESCE(bookings.refund_amount, 0))
+				FILTER (WHERE bookings.payment_status IN ?), 0) AS total_revenue,
+			COALESCE(ROUND(SUM(bookings.final_amount) FILTER (WHERE bookings.payment_status IN ?)
+				/ NULLIF(SUM(bookings.num_tickets) FILTER (WHERE bookings.payment_status IN ?), 0), 2), 0) AS average_ticket_price`+from,
+		append([]any{confirmed, entity.BookingCancelled, soldPaymentStatuses, soldPaymentStatuses, soldPaymentStatuses,
+			soldPaymentStatuses, soldPaymentStatuses, soldPaymentStatuses}, filterArgs...)...).Scan(&stats).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking stats")
+	}
+
+	err = db.Raw(`
+		SELECT date_trunc('day', bookings.booked_at) AS date,
+			COUNT(*) AS total_bookings,
+			COUNT(*) FILTER (WHERE bookings.booking_status IN ?) AS confirmed_bookings,
+			COUNT(*) FILTER (WHERE bookings.booking_status = ?) AS cancelled_bookings,
+			COALESCE(SUM(bookings.final_amount - COALESCE(bookings.refund_amount, 0))
+				FILTER (WHERE bookings.payment_status IN ?), 0) AS revenue`+from+`
+		GROUP BY 1
+		ORDER BY 1`,
+		append([]any{confirmed, entity.BookingCancelled, soldPaymentStatuses}, filterArgs...)...).Scan(&stats.Daily).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get daily booking stats")
+	}
+
+	err = db.Raw(`
+		SELECT movies.id AS movie_id, movies.title,
+			COUNT(*) AS bookings,
+			SUM(bookings.num_tickets) AS tickets,
+			SUM(bookings.final_amount - COALESCE(bookings.refund_amount, 0)) AS revenue
+		FROM bookings
+		JOIN showtimes ON showtimes.id = bookings.showtime_id
+		JOIN movies ON movies.id = showtimes.movie_id
+		WHERE bookings.payment_status IN ? AND bookings.booked_at >= ? AND bookings.booked_at < ?
+			AND bookings.deleted_at IS NULL`+where+`
+		GROUP BY movies.id, movies.title
+		ORDER BY revenue DESC, tickets DESC, movies.title
+		LIMIT ?`,
+		append(append([]any{soldPaymentStatuses}, filterArgs...), top)...).Scan(&stats.TopMovies).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get movie booking stats")
+	}
+
+	return &stats, nil
+}
+
+// timeSeriesUnits are the date_trunc units bookings can be grouped by
+var timeSeriesUnits = map[string]bool{"day": true, "week": true, "month": true}
+
+// cinemaFilter restricts a showtimes query to one cinema when cinemaID is set
+func cinemaFilter(cinemaID *uuid.UUID, args []any) (string, []any) {
+	if cinemaID == nil {
+		return "", args
+	}
+	return " AND showtimes.cinema_id = ?", append(args, *cinemaID)
+}
+
+func (r *bookingRepository) GetBookingTimeSeries(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time, groupBy string) ([]repository.TimeBucket, error) {
+	if !timeSeriesUnits[groupBy] {
+		return nil, apperrors.ErrBadRequest("group_by must be day, week or month")
+	}
+
+	salesArgs := []any{groupBy, entity.PaymentPaid, entity.BookingCancelled, dateFrom, dateTo}
+	salesCinema, salesArgs := cinemaFilter(cinemaID, salesArgs)
+	// Occupancy compares the paid tickets of the showtimes playing in a
+	// bucket with their seats, wherever the tickets were bought
+	seatsArgs := []any{groupBy, entity.PaymentPaid, dateFrom, dateTo, entity.ShowtimeCancelled}
+	seatsCinema, seatsArgs := cinemaFilter(cinemaID, seatsArgs)
+
+	var buckets []repository.TimeBucket
+	err := conn(ctx, r.db).Raw(`
+		WITH sales AS (
+			SELECT date_trunc(?, bookings.booked_at) AS bucket,
+				COUNT(*) AS total_bookings,
+				COALESCE(SUM(bookings.final_amount) FILTER (WHERE bookings.payment_status = ?), 0) AS revenue,
+				COUNT(*) FILTER (WHERE bookings.booking_status = ?) AS cancelled_bookings
+			FROM bookings
+			JOIN showtimes ON showtimes.id = bookings.showtime_id
+			WHERE bookings.booked_at >= ? AND bookings.booked_at < ?
+				AND bookings.deleted_at IS NULL`+salesCinema+`
+			GROUP BY 1
+		), seats AS (
+			SELECT date_trunc(?, showtimes.show_date) AS bucket,
+				SUM(showtimes.total_seats) AS total_seats,
+				COALESCE(SUM(sold.tickets), 0) AS sold_seats
+			FROM showtimes
+			LEFT JOIN (
+				SELECT showtime_id, SUM(num_tickets) AS tickets
+				FROM bookings
+				WHERE payment_status = ? AND deleted_at IS NULL
+				GROUP BY showtime_id
+			) sold ON sold.showtime_id = showtimes.id
+			WHERE showtimes.show_date >= ?::date AND showtimes.show_date < ?::date
+				AND showtimes.status <> ? AND showtimes.deleted_at IS NULL`+seatsCinema+`
+			GROUP BY 1
+		)
+		SELECT COALESCE(sales.bucket, seats.bucket) AS date,
+			COALESCE(sales.total_bookings, 0) AS total_bookings,
+			COALESCE(sales.revenue, 0) AS revenue,
+			COALESCE(sales.cancelled_bookings, 0) AS cancelled_bookings,
+			COALESCE(ROUND(100.0 * seats.sold_seats / NULLIF(seats.total_seats, 0), 2), 0) AS occupancy_rate
+		FROM sales
+		FULL OUTER JOIN seats ON seats.bucket = sales.bucket
+		ORDER BY 1`, append(salesArgs, seatsArgs...)...).Scan(&buckets).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking time series")
+	}
+	return buckets, nil
+}
+
+func (r *bookingRepository) GetTopMovies(ctx context.Context, cinemaID *uuid.UUID, limit int, dateFrom, dateTo time.Time) ([]repository.MovieBookingStats, error) {
+	args := []any{entity.PaymentPaid, dateFrom, dateTo}
+	where, args := cinemaFilter(cinemaID, args)
+
+	var movies []repository.MovieBookingStats
+	err := conn(ctx, r.db).Raw(`
+		SELECT movies.id AS movie_id, movies.title,
+			COUNT(*) AS bookings,
+			SUM(bookings.num_tickets) AS tickets,
+			SUM(bookings.final_amount) AS revenue
+		FROM bookings
+		JOIN showtimes ON showtimes.id = bookings.showtime_id
+		JOIN movies ON movies.id = showtimes.movie_id
+		WHERE bookings.payment_status = ? AND bookings.booked_at >= ? AND bookings.booked_at < ?
+			AND bookings.deleted_at IS NULL`+where+`
+		GROUP BY movies.id, movies.title
+		ORDER BY revenue DESC, tickets DESC, movies.title
+		LIMIT ?`, append(args, limit)...).Scan(&movies).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get top movies")
+	}
+	return movies, nil
+}
+
+func (r *bookingRepository) GetRevenueByScreenType(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time) ([]repository.ScreenTypeRevenue, error) {
+	args := []any{entity.PaymentPaid, dateFrom, dateTo}
+	where, args := cinemaFilter(cinemaID, args)
+
+	var revenue []repository.ScreenTypeRevenue
+	err := conn(ctx, r.db).Raw(`
+		SELECT screens.screen_type,
+			COUNT(*) AS bookings,
+			SUM(bookings.num_tickets) AS tickets,
+			SUM(bookings.final_amount) AS revenue
+		FROM bookings
+		JOIN showtimes ON showtimes.id = bookings.showtime_id
+		JOIN screens ON screens.id = showtimes.screen_id
+		WHERE bookings.payment_status = ? AND bookings.booked_at >= ? AND bookings.booked_at < ?
+			AND bookings.deleted_at IS NULL`+where+`
+		GROUP BY screens.screen_type
+		ORDER BY revenue DESC`, args...).Scan(&revenue).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get revenue by screen type")
+	}
+	return revenue, nil
+}
+
+// bookingSeatRepository implements repository.BookingSeatRepository
+type bookingSeatRepository struct {
+	db *Database
+}
+
+// NewBookingSeatRepository creates a new booking seat repository
+func NewBookingSeatRepository(db *Database) repository.BookingSeatRepository {
+	return &bookingSeatRepository{db: db}
+}
+
+func (r *bookingSeatRepository) CreateBatch(ctx context.Context, seats []*entity.BookingSeat) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).CreateInBatches(seats, 100).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create booking seats")
+	}
+	return nil
+}
+
+func (r *bookingSeatRepository) GetByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*entity.BookingSeat, error) {
+	var seats []*entity.BookingSeat
+	if err := conn(ctx, r.db).Preload("Seat").Where("booking_id = ?", bookingID).Find(&seats).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking seats")
+	}
+	return seats, nil
+}
+
+func (r *bookingSeatRepository) GetByShowtimeID(ctx context.Context, showtimeID uuid.UUID) ([]*entity.BookingSeat, error) {
+	var seats []*entity.BookingSeat
+	if err := conn(ctx, r.db).Where("showtime_id = ?", showtimeID).Find(&seats).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking seats")
+	}
+	return seats, nil
+}
+
+func (r *bookingSeatRepository) GetBookedSeatIDs(ctx context.Context, showtimeID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := conn(ctx, r.db).Model(&entity.BookingSeat{}).
+		Joins("JOIN bookings ON bookings.id = booking_seats.booking_id").
+		Where("booking_seats.showtime_id = ? AND bookings.booking_status IN ?", showtimeID,
+			[]entity.BookingStatus{entity.BookingPending, entity.BookingConfirmed, entity.BookingCompleted}).
+		Pluck("booking_seats.seat_id", &ids).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booked seats")
+	}
+	return ids, nil
+}
+
+func (r *bookingSeatRepository) Delete(ctx context.Context, bookingID uuid.UUID) error {
+	if err := conn(ctx, r.db).Where("booking_id = ?", bookingID).Delete(&entity.BookingSeat{}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete booking seats")
+	}
+	return nil
+}
+
+// paymentRepository implements repository.PaymentRepository
+type paymentRepository struct {
+	db *Database
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *Database) repository.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Create(payment).Error; err != nil {
+		return wrapWriteError(err, "failed to create payment")
+	}
+	return nil
+}
+
+func (r *paymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := conn(ctx, r.db).First(&payment, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "payment not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payment")
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByReference(ctx context.Context, reference string) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := conn(ctx, r.db).First(&payment, "payment_reference = ?", reference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "payment not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payment")
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByGatewayTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := conn(ctx, r.db).First(&payment, "gateway_transaction_id = ?", transactionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "payment not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payment")
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	if err := conn(ctx, r.db).Where("booking_id = ?", bookingID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payments")
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(payment).Error; err != nil {
+		return wrapWriteError(err, "failed to update payment")
+	}
+	return nil
+}
+
+// UpdateStatus moves a payment to status if the transition table allows it
+// from the stored status
+func (r *paymentRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.PaymentStatus) error {
+	if err := entity.CheckPaymentTransition("", status); err != nil {
+		return statusConflict(err)
+	}
+
+	result := conn(ctx, r.db).Model(&entity.Payment{}).
+		Where("id = ? AND payment_status IN ?", id, entity.PaymentStatusesBefore(status)).
+		Update("payment_status", status)
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update payment status")
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return statusConflict(entity.CheckPaymentTransition(current.PaymentStatus, status))
+	}
+	return nil
+}
+
+func (r *paymentRepository) ListReconcilable(ctx context.Context, initiatedBefore time.Time, maxAttempts, limit int) ([]*entity.Payment, error) {
+	now := time.Now()
+	var payments []*entity.Payment
+	err := conn(ctx, r.db).
+		Joins("JOIN bookings ON bookings.id = payments.booking_id AND bookings.deleted_at IS NULL").
+		Where("payments.payment_status = ? AND payments.created_at < ?", entity.PaymentPending, initiatedBefore).
+		Where("payments.reconcile_attempts < ?", maxAttempts).
+		Where("payments.next_reconcile_at IS NULL OR payments.next_reconcile_at <= ?", now).
+		Where("bookings.booking_status = ?", entity.BookingPending).
+		Where("bookings.expires_at IS NULL OR bookings.expires_at > ?", now).
+		Order("payments.created_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list reconcilable payments")
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) ScheduleReconcile(ctx context.Context, id uuid.UUID, attempts int, next time.Time) error {
+	err := conn(ctx, r.db).Model(&entity.Payment{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"reconcile_attempts": attempts,
+			"next_reconcile_at":  next,
+		}).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to schedule payment reconciliation")
+	}
+	return nil
+}
+
+func (r *bookingRepository) GetShowtimeSales(ctx context.Context, cinemaID uuid.UUID, showDate string) ([]entity.ShowtimeSales, error) {
+	var sales []entity.ShowtimeSales
+	err := conn(ctx, r.db).Table("showtimes").
+		Select(`showtimes.id AS showtime_id,
+			showtimes.movie_id,
+			movies.title AS movie_title,
+			screens.name AS screen_name,
+			to_char(showtimes.start_time, 'HH24:MI') AS start_time,
+			GREATEST(LEAST(showtimes.capacity_limit, showtimes.total_seats - showtimes.blocked_seats), 0) AS capacity,
+			COALESCE(SUM(bookings.num_tickets), 0) AS admissions,
+			COALESCE(SUM(bookings.final_amount), 0) AS revenue`).
+		Joins("JOIN movies ON movies.id = showtimes.movie_id").
+		Joins("JOIN screens ON screens.id = showtimes.screen_id").
+		Joins("LEFT JOIN bookings ON bookings.showtime_id = showtimes.id AND bookings.deleted_at IS NULL AND bookings.booking_status IN ?",
+			[]entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted}).
+		Where("showtimes.cinema_id = ? AND showtimes.show_date = ? AND showtimes.deleted_at IS NULL", cinemaID, showDate).
+		Where("showtimes.status <> ?", entity.ShowtimeCancelled).
+		Group("showtimes.id, movies.title, screens.name").
+		Order("showtimes.start_time, screens.name").
+		Scan(&sales).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get showtime sales")
+	}
+	return sales, nil
+}
+
+func (r *bookingRepository) GetPaymentTotals(ctx context.Context, cinemaID uuid.UUID, from, to time.Time) ([]entity.PaymentMethodTotal, error) {
+	var totals []entity.PaymentMethodTotal
+	err := conn(ctx, r.db).Table("payments").
+		Select(`COALESCE(payments.payment_method, bookings.payment_method, 'UNKNOWN') AS method,
+			COUNT(*) AS payments,
+			SUM(payments.amount) AS amount`).
+		Joins("JOIN bookings ON bookings.id = payments.booking_id").
+		Joins("JOIN showtimes ON showtimes.id = bookings.showtime_id").
+		Where("showtimes.cinema_id = ? AND payments.deleted_at IS NULL", cinemaID).
+		Where("payments.paid_at >= ? AND payments.paid_at < ?", from, to).
+		Where("payments.payment_status IN ?", []entity.PaymentStatus{entity.PaymentPaid, entity.PaymentRefunded}).
+		Group("1").
+		Order("amount DESC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get payment totals")
+	}
+	return totals, nil
+}
+
+func (r *bookingRepository) GetRefundTotals(ctx context.Context, cinemaID uuid.UUID, from, to time.Time) (*entity.RefundTotals, error) {
+	var totals entity.RefundTotals
+	err := conn(ctx, r.db).Table("payments").
+		Select("COUNT(*) AS count, COALESCE(SUM(COALESCE(payments.refund_amount, payments.amount)), 0) AS amount").
+		Joins("JOIN bookings ON bookings.id = payments.booking_id").
+		Joins("JOIN showtimes ON showtimes.id = bookings.showtime_id").
+		Where("showtimes.cinema_id = ? AND payments.deleted_at IS NULL", cinemaID).
+		Where("payments.refunded_at >= ? AND payments.refunded_at < ?", from, to).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get refund totals")
+	}
+	return &totals, nil
+}
+
+func (r *bookingRepository) ClaimGuestBookings(ctx context.Context, userID uuid.UUID, email string) (int64, error) {
+	var claimed int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Booking{}).
+			Where("user_id IS NULL AND guest_email <> '' AND LOWER(guest_email) = LOWER(?)", email).
+			Updates(map[string]any{"user_id": userID, "claimed_at": time.Now()})
+		if result.Error != nil {
+			return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to claim guest bookings")
+		}
+		claimed = result.RowsAffected
+		return claimPromoRedemptions(tx, userID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return claimed, nil
+}
+
+func (r *bookingRepository) ClaimGuestBooking(ctx context.Context, id, userID uuid.UUID) error {
+	var claimed bool
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Booking{}).
+			Where("id = ? AND user_id IS NULL AND guest_email <> ''", id).
+			Updates(map[string]any{"user_id": userID, "claimed_at": time.Now()})
+		if result.Error != nil {
+			return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to claim guest booking")
+		}
+		if claimed = result.RowsAffected > 0; !claimed {
+			return nil
+		}
+		return claimPromoRedemptions(tx, userID)
+	})
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return apperrors.New(apperrors.CodeConflict, "booking already belongs to an account")
+	}
+	return nil
+}
+
+// claimPromoRedemptions moves the promo code uses of the guest bookings the
+// user just claimed to the account, so they count against its per-user
+// limits. The guest email is kept, so they still count for it too.
+func claimPromoRedemptions(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Model(&entity.PromoCodeRedemption{}).
+		Where("user_id IS NULL AND booking_id IN (SELECT id FROM bookings WHERE user_id = ?)", userID).
+		Update("user_id", userID).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim promo code redemptions")
+	}
+	return nil
+}
+
+func (r *bookingRepository) HasBookingForMovie(ctx context.Context, userID, movieID uuid.UUID, statuses ...entity.BookingStatus) (bool, error) {
+	var exists bool
+	err := conn(ctx, r.db).Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM bookings
+			JOIN showtimes ON showtimes.id = bookings.showtime_id
+			WHERE bookings.user_id = ? AND showtimes.movie_id = ?
+				AND bookings.booking_status IN ? AND bookings.deleted_at IS NULL
+		)`, userID, movieID, statuses).Scan(&exists).Error
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check movie bookings")
+	}
+	return exists, nil
+}
@@ -0,0 +1,447 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestBookingHistoryCursorMatchesOffset(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	user := &entity.User{Email: "history-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "History", LastName: "Test", Role: entity.RoleCustomer, IsActive: true}
+	if err := f.db.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	// Pairs of bookings share a booked_at, so pages must break ties on id
+	base := time.Date(2029, 12, 20, 12, 0, 0, 0, time.UTC)
+	for i := range 13 {
+		booking := &entity.Booking{
+			BookingReference: "BK-HISTORY-" + uuid.NewString()[:8],
+			UserID:           &user.ID,
+			ShowtimeID:       f.showtime.ID,
+			NumTickets:       1,
+			SubtotalAmount:   10,
+			FinalAmount:      10,
+			BookingStatus:    entity.BookingConfirmed,
+			PaymentStatus:    entity.PaymentPaid,
+			SalesChannel:     entity.ChannelOnline,
+			BookedAt:         base.Add(time.Duration(i/2) * time.Hour),
+		}
+		if err := f.db.DB.Create(booking).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+	}
+
+	ids := func(bookings []*entity.Booking) []uuid.UUID {
+		var ids []uuid.UUID
+		for _, b := range bookings {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	const limit = 4
+	var after *repository.BookingCursor
+	for page := range 3 {
+		byOffset, total, err := repo.GetByUserID(ctx, user.ID, page*limit, limit)
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if total != 13 {
+			t.Errorf("total = %d, want 13", total)
+		}
+		byCursor, err := repo.ListAfter(ctx, repository.BookingFilter{UserID: &user.ID}, after, limit)
+		if err != nil {
+			t.Fatalf("ListAfter: %v", err)
+		}
+		if len(byCursor) != limit || !slices.Equal(ids(byCursor), ids(byOffset)) {
+			t.Fatalf("page %d: cursor %v, offset %v", page+1, ids(byCursor), ids(byOffset))
+		}
+		last := byCursor[len(byCursor)-1]
+		after = &repository.BookingCursor{BookedAt: last.BookedAt, ID: last.ID}
+	}
+
+	rest, err := repo.ListAfter(ctx, repository.BookingFilter{UserID: &user.ID}, after, limit)
+	if err != nil {
+		t.Fatalf("ListAfter: %v", err)
+	}
+	if len(rest) != 1 || !rest[0].BookedAt.Equal(base) {
+		t.Errorf("last page = %v, want the oldest booking", ids(rest))
+	}
+}
+
+func TestBookingHistoryPeriods(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	user := &entity.User{Email: "periods-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "History", LastName: "Test", Role: entity.RoleCustomer, IsActive: true}
+	if err := f.db.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	// A show that started an hour ago in the cinema's timezone
+	started := time.Now().UTC().Add(-time.Hour)
+	past := &entity.Showtime{
+		CinemaID: f.cinema.ID, ScreenID: f.showtime.ScreenID, MovieID: f.movie.ID,
+		ShowDate:  time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, time.UTC),
+		StartTime: started.Format("15:04"), EndTime: started.Add(2 * time.Hour).Format("15:04"),
+		Status: entity.ShowtimeScheduled, TotalSeats: 100, AvailableSeats: 100,
+	}
+	if err := f.db.DB.Create(past).Error; err != nil {
+		t.Fatalf("create showtime: %v", err)
+	}
+
+	byName := map[string]uuid.UUID{}
+	for _, b := range []struct {
+		name       string
+		showtimeID uuid.UUID
+		status     entity.BookingStatus
+	}{
+		{"upcoming", f.showtime.ID, entity.BookingConfirmed},
+		{"started", past.ID, entity.BookingConfirmed},
+		{"completed", past.ID, entity.BookingCompleted},
+		{"cancelled", f.showtime.ID, entity.BookingCancelled},
+		{"refunded", past.ID, entity.BookingRefunded},
+		{"pending", f.showtime.ID, entity.BookingPending},
+	} {
+		booking := &entity.Booking{
+			BookingReference: "BK-PERIOD-" + uuid.NewString()[:8], UserID: &user.ID, ShowtimeID: b.showtimeID,
+			NumTickets: 1, SubtotalAmount: 10, FinalAmount: 10, BookingStatus: b.status,
+			PaymentStatus: entity.PaymentPaid, SalesChannel: entity.ChannelOnline, BookedAt: time.Now(),
+		}
+		if err := f.db.DB.Create(booking).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+		byName[b.name] = booking.ID
+	}
+
+	showDate := f.showtime.ShowDate
+	tests := []struct {
+		name   string
+		filter repository.BookingFilter
+		want   []string
+	}{
+		{"everything", repository.BookingFilter{}, []string{"upcoming", "started", "completed", "cancelled", "refunded", "pending"}},
+		{"upcoming", repository.BookingFilter{Period: repository.BookingsUpcoming}, []string{"upcoming"}},
+		{"past", repository.BookingFilter{Period: repository.BookingsPast}, []string{"started", "completed"}},
+		{"cancelled", repository.BookingFilter{Period: repository.BookingsCancelled}, []string{"cancelled", "refunded"}},
+		{"show date", repository.BookingFilter{ShowDateFrom: &showDate, ShowDateTo: &showDate}, []string{"upcoming", "cancelled", "pending"}},
+	}
+	for _, tt := range tests {
+		tt.filter.UserID = &user.ID
+		paged, total, err := repo.List(ctx, tt.filter, 0, 20)
+		if err != nil {
+			t.Fatalf("%s: List: %v", tt.name, err)
+		}
+		seeked, err := repo.ListAfter(ctx, tt.filter, nil, 20)
+		if err != nil {
+			t.Fatalf("%s: ListAfter: %v", tt.name, err)
+		}
+		var want []uuid.UUID
+		for _, name := range tt.want {
+			want = append(want, byName[name])
+		}
+		for _, got := range [][]*entity.Booking{paged, seeked} {
+			var ids []uuid.UUID
+			for _, b := range got {
+				ids = append(ids, b.ID)
+			}
+			slices.SortFunc(ids, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+			slices.SortFunc(want, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+			if !slices.Equal(ids, want) {
+				t.Errorf("%s: listed %v, want %s", tt.name, ids, tt.want)
+			}
+		}
+		if total != int64(len(tt.want)) {
+			t.Errorf("%s: total = %d, want %d", tt.name, total, len(tt.want))
+		}
+	}
+}
+
+func TestExpirePending(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+	now := time.Now()
+	at := func(d time.Duration) *time.Time { v := now.Add(d); return &v }
+
+	tests := []struct {
+		name      string
+		status    entity.BookingStatus
+		expiresAt *time.Time
+		expired   bool
+	}{
+		{"pending, past its deadline", entity.BookingPending, at(-time.Minute), true},
+		{"pending, long past its deadline", entity.BookingPending, at(-24 * time.Hour), true},
+		{"pending, deadline not reached", entity.BookingPending, at(time.Minute), false},
+		{"pending, no deadline", entity.BookingPending, nil, false},
+		{"confirmed, past its deadline", entity.BookingConfirmed, at(-time.Minute), false},
+	}
+
+	const seatsPerBooking = 2
+	bookings := make([]*entity.Booking, len(tests))
+	for i, tt := range tests {
+		var seats []*entity.BookingSeat
+		for n := range seatsPerBooking {
+			seat := &entity.Seat{ScreenID: f.showtime.ScreenID, RowLabel: string(rune('A' + i)), SeatNumber: n + 1, SeatType: entity.SeatStandard, IsActive: true}
+			if err := f.db.DB.Create(seat).Error; err != nil {
+				t.Fatalf("create seat: %v", err)
+			}
+			seats = append(seats, &entity.BookingSeat{SeatID: seat.ID, Price: 10})
+		}
+		bookings[i] = &entity.Booking{
+			BookingReference: "BK-EXPIRE-" + uuid.NewString()[:8],
+			ShowtimeID:       f.showtime.ID,
+			NumTickets:       seatsPerBooking,
+			SubtotalAmount:   20,
+			FinalAmount:      20,
+			BookingStatus:    tt.status,
+			PaymentStatus:    entity.PaymentPending,
+			SalesChannel:     entity.ChannelOnline,
+			BookedAt:         now.Add(-time.Hour),
+			ExpiresAt:        tt.expiresAt,
+		}
+		if err := repo.CreateWithSeats(ctx, bookings[i], seats); err != nil {
+			t.Fatalf("%s: create booking: %v", tt.name, err)
+		}
+	}
+
+	swept, err := repo.ExpirePending(ctx, now, 100)
+	if err != nil {
+		t.Fatalf("ExpirePending: %v", err)
+	}
+	sweptIDs := make(entity.UUIDList, 0, len(swept))
+	for _, b := range swept {
+		sweptIDs = append(sweptIDs, b.ID)
+	}
+
+	held := 0
+	for i, tt := range tests {
+		var stored entity.Booking
+		if err := f.db.DB.First(&stored, "id = ?", bookings[i].ID).Error; err != nil {
+			t.Fatalf("%s: reload: %v", tt.name, err)
+		}
+		var seats int64
+		if err := f.db.DB.Model(&entity.BookingSeat{}).Where("booking_id = ?", stored.ID).Count(&seats).Error; err != nil {
+			t.Fatalf("%s: count seats: %v", tt.name, err)
+		}
+
+		wantStatus, wantSeats := tt.status, int64(seatsPerBooking)
+		if tt.expired {
+			wantStatus, wantSeats = entity.BookingExpired, 0
+		} else {
+			held += seatsPerBooking
+		}
+		if stored.BookingStatus != wantStatus {
+			t.Errorf("%s: status = %s, want %s", tt.name, stored.BookingStatus, wantStatus)
+		}
+		if seats != wantSeats {
+			t.Errorf("%s: %d booking seats, want %d", tt.name, seats, wantSeats)
+		}
+		if sweptIDs.Contains(stored.ID) != tt.expired {
+			t.Errorf("%s: returned by the sweep = %v", tt.name, !tt.expired)
+		}
+	}
+
+	var showtime entity.Showtime
+	if err := f.db.DB.First(&showtime, "id = ?", f.showtime.ID).Error; err != nil {
+		t.Fatalf("reload showtime: %v", err)
+	}
+	if want := f.showtime.TotalSeats - held; showtime.AvailableSeats != want {
+		t.Errorf("available seats = %d, want %d", showtime.AvailableSeats, want)
+	}
+
+	// A second sweep finds nothing left to expire
+	if again, err := repo.ExpirePending(ctx, now, 100); err != nil || len(again) != 0 {
+		t.Errorf("second sweep = %d bookings, %v, want none", len(again), err)
+	}
+}
+
+func TestSearchBookings(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	suffix := uuid.NewString()[:8]
+	user := &entity.User{Email: "Search-" + suffix + "@Example.com", PasswordHash: "x", FirstName: "Search", LastName: "Test", Role: entity.RoleCustomer, IsActive: true}
+	if err := f.db.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	booked := time.Date(2029, 12, 20, 10, 0, 0, 0, time.UTC)
+	var created []*entity.Booking
+	for i, b := range []*entity.Booking{
+		{UserID: &user.ID},
+		{GuestEmail: "search-" + suffix + "@example.com", GuestName: "Guest"},
+		{GuestEmail: "someone-else-" + suffix + "@example.com", GuestName: "Other"},
+	} {
+		b.BookingReference = "BK-SEARCH-" + uuid.NewString()[:8]
+		b.ShowtimeID = f.showtime.ID
+		b.NumTickets = 1
+		b.SubtotalAmount, b.FinalAmount = 10, 10
+		b.BookingStatus = entity.BookingConfirmed
+		b.PaymentStatus = entity.PaymentPaid
+		b.SalesChannel = entity.ChannelOnline
+		b.BookedAt = booked.Add(time.Duration(i) * time.Hour)
+		if err := f.db.DB.Create(b).Error; err != nil {
+			t.Fatalf("create booking %d: %v", i, err)
+		}
+		created = append(created, b)
+	}
+
+	search := func(filter repository.BookingFilter) []*entity.Booking {
+		t.Helper()
+		filter.CinemaID = &f.cinema.ID
+		bookings, total, err := repo.Search(ctx, filter, 0, 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if total != int64(len(bookings)) {
+			t.Errorf("total = %d for %d bookings", total, len(bookings))
+		}
+		return bookings
+	}
+
+	// The account's and the guest's bookings, newest first
+	byEmail := search(repository.BookingFilter{Email: "SEARCH-" + suffix + "@example.COM"})
+	if len(byEmail) != 2 || byEmail[0].ID != created[1].ID || byEmail[1].ID != created[0].ID {
+		t.Fatalf("email search found %d bookings, want the guest's then the account's", len(byEmail))
+	}
+	if byEmail[1].User == nil || byEmail[1].Showtime.Cinema.Name != "Demand Test" {
+		t.Errorf("the account and showtime were not loaded")
+	}
+
+	oldest := search(repository.BookingFilter{OldestFirst: true})
+	if len(oldest) != 3 || oldest[0].ID != created[0].ID {
+		t.Errorf("oldest first did not start with the first booking")
+	}
+	if got := search(repository.BookingFilter{Reference: created[2].BookingReference}); len(got) != 1 || got[0].ID != created[2].ID {
+		t.Errorf("reference search found %d bookings, want the one", len(got))
+	}
+
+	otherCinema := uuid.New()
+	if got, _, err := repo.Search(ctx, repository.BookingFilter{CinemaID: &otherCinema}, 0, 10); err != nil || len(got) != 0 {
+		t.Errorf("another cinema: %d bookings, %v, want none", len(got), err)
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	staff := &entity.User{Email: "door-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "Door", LastName: "Staff", Role: entity.RoleStaff, IsActive: true}
+	if err := f.db.DB.Create(staff).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	book := func(status entity.BookingStatus, payment entity.PaymentStatus) *entity.Booking {
+		t.Helper()
+		b := &entity.Booking{
+			BookingReference: "BK-DOOR-" + uuid.NewString()[:8], ShowtimeID: f.showtime.ID, NumTickets: 1,
+			SubtotalAmount: 10, FinalAmount: 10, BookingStatus: status, PaymentStatus: payment,
+			SalesChannel: entity.ChannelOnline, BookedAt: time.Now(),
+		}
+		if err := f.db.DB.Create(b).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+		return b
+	}
+
+	paid := book(entity.BookingConfirmed, entity.PaymentPaid)
+	at := time.Now().Truncate(time.Second)
+	if err := repo.CheckIn(ctx, paid.ID, staff.ID, at); err != nil {
+		t.Fatalf("CheckIn: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, paid.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.BookingStatus != entity.BookingCompleted || stored.CheckedInAt == nil || !stored.CheckedInAt.Equal(at) || *stored.CheckedInBy != staff.ID {
+		t.Errorf("stored %s checked in at %v by %v", stored.BookingStatus, stored.CheckedInAt, stored.CheckedInBy)
+	}
+
+	// A second scan and an unpaid booking change nothing
+	if err := repo.CheckIn(ctx, paid.ID, staff.ID, at.Add(time.Minute)); !apperrors.Is(err, apperrors.CodeInvalidStatus) {
+		t.Errorf("second check-in: %v, want %s", err, apperrors.CodeInvalidStatus)
+	}
+	unpaid := book(entity.BookingConfirmed, entity.PaymentPending)
+	if err := repo.CheckIn(ctx, unpaid.ID, staff.ID, at); !apperrors.Is(err, apperrors.CodeInvalidStatus) {
+		t.Errorf("unpaid check-in: %v, want %s", err, apperrors.CodeInvalidStatus)
+	}
+	if err := repo.CheckIn(ctx, uuid.New(), staff.ID, at); !apperrors.Is(err, apperrors.CodeBookingNotFound) {
+		t.Errorf("missing booking: %v, want %s", err, apperrors.CodeBookingNotFound)
+	}
+}
+
+func TestGetBookingStats(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewBookingRepository(f.db)
+
+	day := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	book := func(bookedAt time.Time, tickets int, amount float64, status entity.BookingStatus, payment entity.PaymentStatus, refund *float64) {
+		t.Helper()
+		b := &entity.Booking{
+			BookingReference: "BK-STATS-" + uuid.NewString()[:8], ShowtimeID: f.showtime.ID, NumTickets: tickets,
+			SubtotalAmount: amount, FinalAmount: amount, BookingStatus: status, PaymentStatus: payment,
+			RefundAmount: refund, SalesChannel: entity.ChannelOnline, BookedAt: bookedAt,
+		}
+		if err := f.db.DB.Create(b).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+	}
+	refund := 8.0
+	book(day.Add(9*time.Hour), 2, 20, entity.BookingConfirmed, entity.PaymentPaid, nil)
+	book(day.Add(10*time.Hour), 1, 10, entity.BookingCompleted, entity.PaymentPaid, nil)
+	book(day.Add(26*time.Hour), 1, 10, entity.BookingCancelled, entity.PaymentRefunded, &refund)
+	book(day.Add(27*time.Hour), 3, 30, entity.BookingPending, entity.PaymentPending, nil)
+	// The end of the range is excluded
+	book(day.AddDate(0, 0, 2), 5, 50, entity.BookingConfirmed, entity.PaymentPaid, nil)
+
+	stats, err := repo.GetBookingStats(ctx, &f.cinema.ID, day, day.AddDate(0, 0, 2), 10)
+	if err != nil {
+		t.Fatalf("GetBookingStats: %v", err)
+	}
+	want := repository.BookingStats{
+		TotalBookings: 4, ConfirmedBookings: 2, CancelledBookings: 1, TicketsSold: 4,
+		GrossRevenue: 40, RefundedAmount: 8, TotalRevenue: 32, AverageTicketPrice: 10,
+	}
+	got := *stats
+	got.Daily, got.TopMovies = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+
+	if len(stats.Daily) != 2 {
+		t.Fatalf("%d days, want 2", len(stats.Daily))
+	}
+	for i, want := range []repository.DailyBookingStats{
+		{Date: day, TotalBookings: 2, ConfirmedBookings: 2, Revenue: 30},
+		{Date: day.AddDate(0, 0, 1), TotalBookings: 2, CancelledBookings: 1, Revenue: 2},
+	} {
+		if got := stats.Daily[i]; !got.Date.Equal(want.Date) || got.TotalBookings != want.TotalBookings ||
+			got.ConfirmedBookings != want.ConfirmedBookings || got.CancelledBookings != want.CancelledBookings || got.Revenue != want.Revenue {
+			t.Errorf("day %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if len(stats.TopMovies) != 1 || stats.TopMovies[0].MovieID != f.movie.ID || stats.TopMovies[0].Revenue != 32 {
+		t.Errorf("top movies = %+v, want the fixture's movie with 32 revenue", stats.TopMovies)
+	}
+
+	// Another cinema has no bookings
+	other := uuid.New()
+	if stats, err := repo.GetBookingStats(ctx, &other, day, day.AddDate(0, 0, 2), 10); err != nil || stats.TotalBookings != 0 || len(stats.Daily) != 0 {
+		t.Errorf("another cinema: %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/changes"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+// changeRecordRepository implements repository.ChangeRecordRepository
+type changeRecordRepository struct {
+	db *Database
+}
+
+// NewChangeRecordRepository creates a new change record repository
+func NewChangeRecordRepository(db *Database) repository.ChangeRecordRepository {
+	return &changeRecordRepository{db: db}
+}
+
+func (r *changeRecordRepository) Create(ctx context.Context, record *entity.ChangeRecord) error {
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create change record")
+	}
+	return nil
+}
+
+func (r *changeRecordRepository) List(ctx context.Context, filter repository.ChangeRecordFilter, offset, limit int) ([]*entity.ChangeRecord, int64, error) {
+	var records []*entity.ChangeRecord
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.ChangeRecord{}).
+		Where("entity_type = ? AND entity_id = ?", filter.EntityType, filter.EntityID)
+
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at <= ?", *filter.To)
+	}
+	if filter.ExcludeSystem {
+		db = db.Where("actor_type <> ?", changes.ActorSystem)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count change records")
+	}
+
+	if err := db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&records).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list change records")
+	}
+
+	return records, total, nil
+}
@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"math"
+	"strings"
+	"unicode"
 
 	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/app/repository"
@@ -10,8 +13,25 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// kmPerDegreeLat is the length of a degree of latitude
+const kmPerDegreeLat = 111.045
+
+// distanceSQL is the great-circle distance in km from (?, ?) to a cinema,
+// by the haversine formula, which needs no PostGIS and is the one
+// entity.Cinema.DistanceKm uses, so the filter and the order agree with the
+// distance reported. The root is clamped so rounding cannot push asin out
+// of its domain. Takes lat, lat, lon.
+const distanceSQL = `(2 * 6371 * asin(LEAST(1, sqrt(
+	power(sin(radians(latitude - ?) / 2), 2) +
+	cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2)))))`
+
+// cinemaSearchVector is the document a cinema is searched by; it matches
+// the expression of idx_cinemas_search so the index is used
+const cinemaSearchVector = `to_tsvector('simple', name || ' ' || city || ' ' || address)`
+
 type cinemaRepository struct {
 	db *Database
 }
@@ -87,6 +107,61 @@ func (r *cinemaRepository) List(ctx context.Context, city string, offset, limit
 	return cinemas, total, nil
 }
 
+func (r *cinemaRepository) Search(ctx context.Context, query, city string, offset, limit int) ([]*entity.Cinema, int64, error) {
+	var cinemas []*entity.Cinema
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.Cinema{}).Where("is_active = ?", true)
+	if city != "" {
+		db = db.Where("LOWER(city) = LOWER(?)", city)
+	}
+
+	// A single word is matched anywhere in the text, so partial names
+	// match; several words are matched as whole words, the last one as a
+	// prefix, and ranked
+	query = strings.TrimSpace(query)
+	tsQuery := prefixTSQuery(query)
+	var order clause.Expr
+	if !strings.ContainsFunc(query, unicode.IsSpace) || tsQuery == "" {
+		pattern := "%" + escapeLike(query) + "%"
+		db = db.Where("name ILIKE ? OR city ILIKE ? OR address ILIKE ?", pattern, pattern, pattern)
+		order = gorm.Expr("name ILIKE ? DESC, name ASC", pattern)
+	} else {
+		db = db.Where(cinemaSearchVector+" @@ to_tsquery('simple', ?)", tsQuery)
+		order = gorm.Expr("ts_rank("+cinemaSearchVector+", to_tsquery('simple', ?)) DESC, name ASC", tsQuery)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count cinemas")
+	}
+
+	if err := db.Clauses(clause.OrderBy{Expression: order}).Offset(offset).Limit(limit).Find(&cinemas).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to search cinemas")
+	}
+
+	return cinemas, total, nil
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression requiring
+// every word, the last one as a prefix since it may still be typed.
+// Everything but letters and digits is dropped, so the text cannot inject
+// tsquery operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
 func (r *cinemaRepository) GetWithScreens(ctx context.Context, id uuid.UUID) (*entity.Cinema, error) {
 	var cinema entity.Cinema
 	err := r.db.WithContext(ctx).Preload("Screens").First(&cinema, "id = ?", id).Error
@@ -100,9 +175,29 @@ func (r *cinemaRepository) GetWithScreens(ctx context.Context, id uuid.UUID) (*e
 }
 
 func (r *cinemaRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entity.Cinema, error) {
-	// Simple placeholder implementation or raw SQL for PostGIS/haversine
-	// For now, returning empty list as this likely requires specific DB extensions or complex queries
-	return []*entity.Cinema{}, nil
+	// A bounding box narrows the rows through idx_cinemas_location before
+	// the exact distance is computed
+	dLat := radiusKm / kmPerDegreeLat
+	db := r.db.WithContext(ctx).
+		Where("is_active = ? AND latitude IS NOT NULL AND longitude IS NOT NULL", true).
+		Where("latitude BETWEEN ? AND ?", lat-dLat, lat+dLat)
+	if math.Abs(lat)+dLat < 89 {
+		dLon := radiusKm / (kmPerDegreeLat * math.Cos(lat*math.Pi/180))
+		// Near the antimeridian the box would wrap; the distance filter alone applies
+		if lon-dLon >= -180 && lon+dLon <= 180 {
+			db = db.Where("longitude BETWEEN ? AND ?", lon-dLon, lon+dLon)
+		}
+	}
+
+	var cinemas []*entity.Cinema
+	if err := db.
+		Where(distanceSQL+" <= ?", lat, lat, lon, radiusKm).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: distanceSQL + " ASC", Vars: []interface{}{lat, lat, lon}}}).
+		Limit(limit).
+		Find(&cinemas).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to find nearby cinemas")
+	}
+	return cinemas, nil
 }
 
 type screenRepository struct {
@@ -185,7 +280,7 @@ func (r *seatRepository) CreateBatch(ctx context.Context, seats []*entity.Seat)
 
 func (r *seatRepository) GetByScreenID(ctx context.Context, screenID uuid.UUID) ([]*entity.Seat, error) {
 	var seats []*entity.Seat
-	if err := r.db.WithContext(ctx).Where("screen_id = ?", screenID).Order("row_label, seat_number").Find(&seats).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("screen_id = ?", screenID).Order("length(row_label), row_label, seat_number").Find(&seats).Error; err != nil {
 		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get seats")
 	}
 	return seats, nil
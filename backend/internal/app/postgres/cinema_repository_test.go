@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestGetNearby(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	repo := NewCinemaRepository(db)
+
+	// Around a point in the South Atlantic, where no real cinema is
+	const lat, lon = -45.0, -30.0
+	seed := func(name string, lat, lon float64, active bool) {
+		t.Helper()
+		suffix := uuid.NewString()[:8]
+		cinema := &entity.Cinema{
+			Name: name, Slug: "nearby-" + suffix, Address: "1 Test St", City: "Test", Country: "VN",
+			Latitude: &lat, Longitude: &lon, IsActive: true, Timezone: "UTC",
+		}
+		if err := db.DB.Create(cinema).Error; err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if !active {
+			if err := db.DB.Model(cinema).Update("is_active", false).Error; err != nil {
+				t.Fatalf("deactivate %s: %v", name, err)
+			}
+		}
+	}
+	seed("20 km east", lat, lon+0.25, true)
+	seed("5 km south", lat-0.045, lon, true)
+	seed("100 km south", lat-0.9, lon, true)
+	seed("here, closed", lat, lon, false)
+	seed("here", lat, lon, true)
+
+	tests := []struct {
+		name     string
+		radiusKm float64
+		limit    int
+		want     []string
+	}{
+		{"within 50 km", 50, 10, []string{"here", "5 km south", "20 km east"}},
+		{"within 10 km", 10, 10, []string{"here", "5 km south"}},
+		{"limited", 500, 2, []string{"here", "5 km south"}},
+		{"within 150 km", 150, 10, []string{"here", "5 km south", "20 km east", "100 km south"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cinemas, err := repo.GetNearby(ctx, lat, lon, tt.radiusKm, tt.limit)
+			if err != nil {
+				t.Fatalf("GetNearby: %v", err)
+			}
+			var got []string
+			for _, cinema := range cinemas {
+				got = append(got, cinema.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceSQLMatchesDistanceKm(t *testing.T) {
+	db := newTestDatabase(t)
+
+	tests := []struct {
+		name             string
+		lat, lon         float64
+		fromLat, fromLon float64
+	}{
+		{"across the city", 10.7769, 106.7009, 10.8231, 106.6297},
+		{"a few metres", 21.0285, 105.8542, 21.0286, 105.8543},
+		{"across the date line", 64.0, 179.9, 64.0, -179.9},
+		{"antipodes", 0, 0, 0, 180},
+	}
+	for _, tt := range tests {
+		cinema := &entity.Cinema{
+			Name: tt.name, Slug: "distance-" + uuid.NewString()[:8], Address: "1 Test St", City: "Test", Country: "VN",
+			Latitude: &tt.lat, Longitude: &tt.lon, IsActive: true, Timezone: "UTC",
+		}
+		if err := db.DB.Create(cinema).Error; err != nil {
+			t.Fatalf("%s: create: %v", tt.name, err)
+		}
+		var got float64
+		if err := db.DB.Raw("SELECT "+distanceSQL+" FROM cinemas WHERE id = ?", tt.fromLat, tt.fromLat, tt.fromLon, cinema.ID).Scan(&got).Error; err != nil {
+			t.Fatalf("%s: distance: %v", tt.name, err)
+		}
+		want, _ := cinema.DistanceKm(tt.fromLat, tt.fromLon)
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s: SQL distance = %v km, DistanceKm = %v km", tt.name, got, want)
+		}
+	}
+}
+
+func TestSeatsByScreenInRowOrder(t *testing.T) {
+	f := newDemandFixture(t)
+	for _, row := range []string{"AA", "B", "A", "Z"} {
+		for _, number := range []int{10, 2} {
+			seat := &entity.Seat{ScreenID: f.showtime.ScreenID, RowLabel: row, SeatNumber: number, SeatType: entity.SeatStandard, IsActive: true}
+			if err := f.db.DB.Create(seat).Error; err != nil {
+				t.Fatalf("create seat: %v", err)
+			}
+		}
+	}
+
+	seats, err := NewSeatRepository(f.db).GetByScreenID(context.Background(), f.showtime.ScreenID)
+	if err != nil {
+		t.Fatalf("GetByScreenID: %v", err)
+	}
+	var got []string
+	for _, seat := range seats {
+		got = append(got, fmt.Sprintf("%s%d", seat.RowLabel, seat.SeatNumber))
+	}
+	want := []string{"A2", "A10", "B2", "B10", "Z2", "Z10", "AA2", "AA10"}
+	if !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestSearchCinemas(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	repo := NewCinemaRepository(db)
+
+	// A city of its own keeps the seeded cinemas out of the results
+	city := "Searchville " + uuid.NewString()[:8]
+	seed := func(name, address string, active bool) {
+		t.Helper()
+		cinema := &entity.Cinema{
+			Name: name, Slug: "search-" + uuid.NewString()[:8], Address: address, City: city, Country: "VN",
+			IsActive: true, Timezone: "UTC",
+		}
+		if err := db.DB.Create(cinema).Error; err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if !active {
+			if err := db.DB.Model(cinema).Update("is_active", false).Error; err != nil {
+				t.Fatalf("deactivate %s: %v", name, err)
+			}
+		}
+	}
+	seed("Galaxy Nguyen Du", "116 Nguyen Du", true)
+	seed("Riverside Screens", "2 Galaxy Road", true)
+	seed("Galaxy Closed", "9 Nguyen Du", false)
+	seed("Lotte 100%", "1 Le Loi", true)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"one word, name matches first", "galax", []string{"Galaxy Nguyen Du", "Riverside Screens"}},
+		{"several words, the last a prefix", "nguyen galaxy d", []string{"Galaxy Nguyen Du"}},
+		{"words in the address", "galaxy road", []string{"Riverside Screens"}},
+		{"wildcards are literal", "100%", []string{"Lotte 100%"}},
+		{"operators are dropped", "galaxy | !nguyen", []string{"Galaxy Nguyen Du"}},
+		{"no match", "cinestar", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cinemas, total, err := repo.Search(ctx, tt.query, strings.ToUpper(city), 0, 10)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []string
+			for _, cinema := range cinemas {
+				got = append(got, cinema.Name)
+			}
+			if !slices.Equal(got, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("got %v (%d in total), want %v", got, total, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"galaxy nguyen d", "galaxy & nguyen & d:*"},
+		{"  Hà   Nội ", "Hà & Nội:*"},
+		{"a & b | !c:*", "a & b & c:*"},
+		{"' () <->", ""},
+	}
+	for _, tt := range tests {
+		if got := prefixTSQuery(tt.text); got != tt.want {
+			t.Errorf("prefixTSQuery(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	if got := escapeLike(`50%_off\`); got != `50\%\_off\\` {
+		t.Errorf("escapeLike = %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// cinemaStaffRepository implements repository.CinemaStaffRepository
+type cinemaStaffRepository struct {
+	db *Database
+}
+
+// NewCinemaStaffRepository creates a new cinema staff repository
+func NewCinemaStaffRepository(db *Database) repository.CinemaStaffRepository {
+	return &cinemaStaffRepository{db: db}
+}
+
+func (r *cinemaStaffRepository) ListUsers(ctx context.Context, cinemaID uuid.UUID, roles ...entity.Role) ([]*entity.User, error) {
+	var users []*entity.User
+	db := r.db.WithContext(ctx).
+		Joins("JOIN cinema_staff ON cinema_staff.user_id = users.id").
+		Where("cinema_staff.cinema_id = ? AND users.is_active = ?", cinemaID, true)
+	if len(roles) > 0 {
+		db = db.Where("cinema_staff.role IN ?", roles)
+	}
+	if err := db.Order("users.email").Find(&users).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list cinema staff")
+	}
+	return users, nil
+}
+
+func (r *cinemaStaffRepository) IsAssigned(ctx context.Context, cinemaID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.CinemaStaff{}).
+		Where("cinema_id = ? AND user_id = ?", cinemaID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check cinema staff")
+	}
+	return count > 0, nil
+}
+
+func (r *cinemaStaffRepository) GetRole(ctx context.Context, cinemaID, userID uuid.UUID) (entity.Role, error) {
+	var staff []entity.CinemaStaff
+	err := r.db.WithContext(ctx).
+		Where("cinema_id = ? AND user_id = ?", cinemaID, userID).
+		Limit(1).
+		Find(&staff).Error
+	if err != nil {
+		return "", apperrors.Wrap(err, apperrors.CodeInternal, "failed to get cinema staff role")
+	}
+	if len(staff) == 0 {
+		return "", nil
+	}
+	return staff[0].Role, nil
+}
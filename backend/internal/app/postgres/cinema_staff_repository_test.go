@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+func TestCinemaStaffRoles(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewCinemaStaffRepository(f.db)
+
+	// The account role does not decide the role at the cinema
+	assign := func(accountRole, cinemaRole entity.Role) uuid.UUID {
+		t.Helper()
+		user := &entity.User{Email: "staff-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "Staff", LastName: "Test", Role: accountRole, IsActive: true}
+		if err := f.db.DB.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		if err := f.db.DB.Omit(clause.Associations).Create(&entity.CinemaStaff{CinemaID: f.cinema.ID, UserID: user.ID, Role: cinemaRole}).Error; err != nil {
+			t.Fatalf("assign user: %v", err)
+		}
+		return user.ID
+	}
+	manager := assign(entity.RoleStaff, entity.RoleManager)
+	staff := assign(entity.RoleManager, entity.RoleStaff)
+
+	for _, tt := range []struct {
+		user uuid.UUID
+		want entity.Role
+	}{
+		{manager, entity.RoleManager},
+		{staff, entity.RoleStaff},
+		{uuid.New(), ""},
+	} {
+		role, err := repo.GetRole(ctx, f.cinema.ID, tt.user)
+		if err != nil {
+			t.Fatalf("GetRole: %v", err)
+		}
+		if role != tt.want {
+			t.Errorf("role = %q, want %q", role, tt.want)
+		}
+	}
+
+	managers, err := repo.ListUsers(ctx, f.cinema.ID, entity.RoleManager)
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(managers) != 1 || managers[0].ID != manager {
+		t.Errorf("listed %d managers, want the one managing the cinema", len(managers))
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"cinemaos-backend/internal/config"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -15,14 +16,15 @@ import (
 
 // Database holds the database connection
 type Database struct {
-	DB     *gorm.DB
-	logger *logger.Logger
+	DB      *gorm.DB
+	logger  *logger.Logger
+	queries *gormLogger
 }
 
 // New creates a new database connection
 func New(cfg config.DatabaseConfig, log *logger.Logger) (*Database, error) {
-	// Configure GORM logger
-	logLevel := gormlogger.Silent
+	// Configure GORM logger; slow queries are always logged
+	logLevel := gormlogger.Warn
 	// if cfg.DebugLevel != " " {
 	// 	logLevel = gormlogger.Info
 	// }
@@ -30,8 +32,9 @@ func New(cfg config.DatabaseConfig, log *logger.Logger) (*Database, error) {
 		logLevel = gormlogger.Info
 	}
 
+	queries := newGormLogger(log, logLevel, cfg.SlowQueryThreshold)
 	gormConfig := &gorm.Config{
-		Logger: gormlogger.Default.LogMode(logLevel),
+		Logger: queries,
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -64,12 +67,18 @@ func New(cfg config.DatabaseConfig, log *logger.Logger) (*Database, error) {
 	defer cancel()
 
 	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	log.Info("Database connected successfully")
 
-	return &Database{DB: db, logger: log}, nil
+	return &Database{DB: db, logger: log, queries: queries}, nil
+}
+
+// RecordQueries records the duration of every query from now on in m
+func (d *Database) RecordQueries(m *metrics.Metrics) {
+	d.queries.metrics.Store(m)
 }
 
 // AutoMigrate is removed in favor of Goose migrations
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// dailyReportRepository implements repository.DailyReportRepository
+type dailyReportRepository struct {
+	db *Database
+}
+
+// NewDailyReportRepository creates a new daily report repository
+func NewDailyReportRepository(db *Database) repository.DailyReportRepository {
+	return &dailyReportRepository{db: db}
+}
+
+func (r *dailyReportRepository) Upsert(ctx context.Context, report *entity.DailyReport) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cinema_id"}, {Name: "business_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "generated_at", "updated_at"}),
+	}).Create(report).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to save daily report")
+	}
+	return nil
+}
+
+func (r *dailyReportRepository) Exists(ctx context.Context, cinemaID uuid.UUID, businessDate string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.DailyReport{}).
+		Where("cinema_id = ? AND business_date = ?", cinemaID, businessDate).
+		Count(&count).Error
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check daily report")
+	}
+	return count > 0, nil
+}
+
+func (r *dailyReportRepository) ListByCinema(ctx context.Context, cinemaID uuid.UUID, offset, limit int) ([]*entity.DailyReport, int64, error) {
+	var reports []*entity.DailyReport
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.DailyReport{}).Where("cinema_id = ?", cinemaID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count daily reports")
+	}
+	if err := db.Order("business_date DESC").Offset(offset).Limit(limit).Find(&reports).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list daily reports")
+	}
+	return reports, total, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestDatabase connects to the migrated database at TEST_DATABASE_URL.
+// Everything a test does runs in one transaction that is rolled back
+// afterwards, so tests leave no rows behind; repository transactions nest
+// as savepoints.
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	db := openTestDatabase(t)
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return &Database{DB: tx, logger: &logger.Logger{Logger: zap.NewNop()}}
+}
+
+// newCommittedTestDatabase connects like newTestDatabase but without the
+// enclosing transaction, for tests that race several connections. Such
+// tests delete the rows they create.
+func newCommittedTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	return &Database{DB: openTestDatabase(t), logger: &logger.Logger{Logger: zap.NewNop()}}
+}
+
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("connect to %s: %v", dsn, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// demandWatermark names the watermark row of the showtime demand rollup
+const demandWatermark = "showtime_demand"
+
+// demandRepository implements repository.DemandRepository
+type demandRepository struct {
+	db *Database
+}
+
+// NewDemandRepository creates a new demand repository
+func NewDemandRepository(db *Database) repository.DemandRepository {
+	return &demandRepository{db: db}
+}
+
+// demandBookings selects the confirmed bookings of a rollup window
+const demandBookings = `FROM bookings
+	JOIN showtimes ON showtimes.id = bookings.showtime_id
+	JOIN screens ON screens.id = showtimes.screen_id
+	JOIN cinemas ON cinemas.id = showtimes.cinema_id
+	WHERE bookings.confirmed_at > ? AND bookings.confirmed_at <= ?
+	AND bookings.deleted_at IS NULL AND bookings.booking_status IN ?`
+
+func (r *demandRepository) Rollup(ctx context.Context, upTo time.Time) (int64, error) {
+	var added int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the watermark makes concurrent runs wait and then find
+		// their window already rolled up
+		var from time.Time
+		if err := tx.Raw("SELECT watermark FROM demand_rollup_watermarks WHERE name = ? FOR UPDATE", demandWatermark).
+			Row().Scan(&from); err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to read demand watermark")
+		}
+		if !upTo.After(from) {
+			return nil
+		}
+
+		statuses := []entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted}
+		if err := tx.Raw("SELECT COUNT(*) "+demandBookings, from, upTo, statuses).Scan(&added).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to roll up demand")
+		}
+
+		if added > 0 {
+			bucket, args := timeBucketCase()
+			args = append(args, from, upTo, statuses)
+			err := tx.Exec(`INSERT INTO showtime_demand_stats
+				(movie_id, cinema_id, day_of_week, time_bucket, screen_type, bookings, tickets, revenue, lead_hours, updated_at)
+				SELECT showtimes.movie_id, showtimes.cinema_id,
+					EXTRACT(ISODOW FROM showtimes.show_date)::int,
+					`+bucket+`,
+					screens.screen_type,
+					COUNT(*),
+					SUM(bookings.num_tickets),
+					SUM(bookings.final_amount),
+					SUM(GREATEST(EXTRACT(EPOCH FROM
+						((showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC'))
+						- bookings.booked_at) / 3600, 0)),
+					NOW()
+				`+demandBookings+`
+				GROUP BY 1, 2, 3, 4, 5
+				ON CONFLICT (movie_id, cinema_id, day_of_week, time_bucket, screen_type) DO UPDATE SET
+					bookings = showtime_demand_stats.bookings + EXCLUDED.bookings,
+					tickets = showtime_demand_stats.tickets + EXCLUDED.tickets,
+					revenue = showtime_demand_stats.revenue + EXCLUDED.revenue,
+					lead_hours = showtime_demand_stats.lead_hours + EXCLUDED.lead_hours,
+					updated_at = EXCLUDED.updated_at`, args...).Error
+			if err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to roll up demand")
+			}
+		}
+
+		if err := tx.Exec("UPDATE demand_rollup_watermarks SET watermark = ?, updated_at = NOW() WHERE name = ?",
+			upTo, demandWatermark).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to move demand watermark")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// timeBucketCase returns a SQL CASE mapping a showtime's start time to its
+// entity.TimeBucket, with its arguments
+func timeBucketCase() (string, []any) {
+	var sb strings.Builder
+	var args []any
+	sb.WriteString("CASE")
+	for _, b := range entity.TimeBucketBounds {
+		sb.WriteString(" WHEN showtimes.start_time < ?::time THEN ?")
+		args = append(args, b.Before, string(b.Bucket))
+	}
+	sb.WriteString(" ELSE ? END")
+	args = append(args, string(entity.TimeBucketLate))
+	return sb.String(), args
+}
+
+func (r *demandRepository) Watermark(ctx context.Context) (time.Time, error) {
+	var mark time.Time
+	if err := r.db.WithContext(ctx).Raw("SELECT watermark FROM demand_rollup_watermarks WHERE name = ?", demandWatermark).
+		Row().Scan(&mark); err != nil {
+		return time.Time{}, apperrors.Wrap(err, apperrors.CodeInternal, "failed to read demand watermark")
+	}
+	return mark, nil
+}
+
+func (r *demandRepository) GetDemandProfile(ctx context.Context, movieID, cinemaID uuid.UUID) ([]*entity.ShowtimeDemandStat, error) {
+	return r.List(ctx, repository.DemandFilter{MovieID: &movieID, CinemaID: &cinemaID})
+}
+
+func (r *demandRepository) List(ctx context.Context, filter repository.DemandFilter) ([]*entity.ShowtimeDemandStat, error) {
+	db := r.db.WithContext(ctx).Model(&entity.ShowtimeDemandStat{})
+	if filter.CinemaID != nil {
+		db = db.Where("cinema_id = ?", *filter.CinemaID)
+	}
+	if filter.MovieID != nil {
+		db = db.Where("movie_id = ?", *filter.MovieID)
+	}
+
+	var stats []*entity.ShowtimeDemandStat
+	if err := db.Order("cinema_id, movie_id, day_of_week, time_bucket, screen_type").Find(&stats).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list demand stats")
+	}
+	return stats, nil
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// demandFixture is a showtime on Monday 2030-01-07 at 19:30 UTC in an IMAX
+// screen
+type demandFixture struct {
+	db       *Database
+	movie    *entity.Movie
+	cinema   *entity.Cinema
+	showtime *entity.Showtime
+	startsAt time.Time
+}
+
+func newDemandFixture(t *testing.T) *demandFixture {
+	t.Helper()
+	db := newTestDatabase(t)
+	suffix := uuid.NewString()[:8]
+
+	f := &demandFixture{
+		db:       db,
+		cinema:   &entity.Cinema{Name: "Demand Test", Slug: "demand-test-" + suffix, Address: "1 Test St", City: "Test", Country: "VN", IsActive: true, Timezone: "UTC"},
+		movie:    &entity.Movie{Title: "Demand Test", Slug: "demand-test-" + suffix, Duration: 120, ReleaseDate: time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC), IsActive: true},
+		startsAt: time.Date(2030, 1, 7, 19, 30, 0, 0, time.UTC),
+	}
+	if err := db.DB.Create(f.cinema).Error; err != nil {
+		t.Fatalf("create cinema: %v", err)
+	}
+	if err := db.DB.Create(f.movie).Error; err != nil {
+		t.Fatalf("create movie: %v", err)
+	}
+	screen := &entity.Screen{CinemaID: f.cinema.ID, Name: "IMAX 1", ScreenNumber: 1, Capacity: 100, ScreenType: entity.ScreenIMAX, Rows: 10, SeatsPerRow: 10, IsActive: true}
+	if err := db.DB.Create(screen).Error; err != nil {
+		t.Fatalf("create screen: %v", err)
+	}
+	f.showtime = &entity.Showtime{
+		CinemaID: f.cinema.ID, ScreenID: screen.ID, MovieID: f.movie.ID,
+		ShowDate: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), StartTime: "19:30", EndTime: "21:30",
+		Status: entity.ShowtimeScheduled, TotalSeats: 100, AvailableSeats: 100,
+	}
+	if err := db.DB.Create(f.showtime).Error; err != nil {
+		t.Fatalf("create showtime: %v", err)
+	}
+
+	// Only the bookings of this test fall in the windows it rolls up
+	if err := db.DB.Exec("UPDATE demand_rollup_watermarks SET watermark = ? WHERE name = ?",
+		time.Now().Add(-time.Hour), demandWatermark).Error; err != nil {
+		t.Fatalf("reset watermark: %v", err)
+	}
+	return f
+}
+
+// book adds a booking made leadHours before the showtime and confirmed at
+// confirmedAt
+func (f *demandFixture) book(t *testing.T, status entity.BookingStatus, tickets int, leadHours float64, confirmedAt time.Time) {
+	t.Helper()
+	booking := &entity.Booking{
+		BookingReference: "BK-DEMAND-" + uuid.NewString()[:8],
+		ShowtimeID:       f.showtime.ID,
+		NumTickets:       tickets,
+		SubtotalAmount:   float64(tickets) * 10,
+		FinalAmount:      float64(tickets) * 10,
+		BookingStatus:    status,
+		PaymentStatus:    entity.PaymentPaid,
+		SalesChannel:     entity.ChannelOnline,
+		BookedAt:         f.startsAt.Add(-time.Duration(leadHours * float64(time.Hour))),
+		ConfirmedAt:      &confirmedAt,
+	}
+	if err := f.db.DB.Create(booking).Error; err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+}
+
+func (f *demandFixture) profile(t *testing.T) *entity.ShowtimeDemandStat {
+	t.Helper()
+	stats, err := NewDemandRepository(f.db).GetDemandProfile(context.Background(), f.movie.ID, f.cinema.ID)
+	if err != nil {
+		t.Fatalf("GetDemandProfile: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("profile has %d rows, want 1", len(stats))
+	}
+	return stats[0]
+}
+
+func TestDemandRollupWatermark(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewDemandRepository(f.db)
+	now := time.Now()
+
+	f.book(t, entity.BookingConfirmed, 2, 48, now.Add(-30*time.Minute))
+	f.book(t, entity.BookingCompleted, 3, 24, now.Add(-20*time.Minute))
+	f.book(t, entity.BookingCancelled, 4, 24, now.Add(-15*time.Minute))
+	late := now.Add(-time.Minute)
+	f.book(t, entity.BookingConfirmed, 1, 6, late)
+
+	upTo := now.Add(-5 * time.Minute)
+	added, err := repo.Rollup(ctx, upTo)
+	if err != nil {
+		t.Fatalf("Rollup: %v", err)
+	}
+	if added != 2 {
+		t.Fatalf("rolled up %d bookings, want the 2 confirmed before %s", added, upTo)
+	}
+	stat := f.profile(t)
+	if stat.DayOfWeek != 1 || stat.TimeBucket != entity.TimeBucketEvening || stat.ScreenType != entity.ScreenIMAX {
+		t.Errorf("keyed %d/%s/%s, want Monday/EVENING/IMAX", stat.DayOfWeek, stat.TimeBucket, stat.ScreenType)
+	}
+	if stat.Bookings != 2 || stat.Tickets != 5 || stat.Revenue != 50 {
+		t.Errorf("stat = %d bookings, %d tickets, %.2f revenue, want 2, 5, 50", stat.Bookings, stat.Tickets, stat.Revenue)
+	}
+	if math.Abs(stat.AvgLeadHours()-36) > 0.01 {
+		t.Errorf("average lead = %.2fh, want 36h", stat.AvgLeadHours())
+	}
+
+	mark, err := repo.Watermark(ctx)
+	if err != nil {
+		t.Fatalf("Watermark: %v", err)
+	}
+	if !mark.Equal(upTo.Truncate(time.Microsecond)) {
+		t.Errorf("watermark = %s, want %s", mark, upTo)
+	}
+
+	// A re-run for the same time, or an earlier one, adds nothing
+	for _, again := range []time.Time{upTo, upTo.Add(-time.Hour)} {
+		if added, err := repo.Rollup(ctx, again); err != nil || added != 0 {
+			t.Errorf("Rollup(%s) again = %d, %v, want nothing added", again, added, err)
+		}
+	}
+	if stat := f.profile(t); stat.Bookings != 2 {
+		t.Errorf("re-run changed the stats to %d bookings", stat.Bookings)
+	}
+
+	// The next run picks up the booking confirmed after the watermark
+	if added, err := repo.Rollup(ctx, now.Add(time.Minute)); err != nil || added != 1 {
+		t.Fatalf("next Rollup = %d, %v, want 1", added, err)
+	}
+	if stat := f.profile(t); stat.Bookings != 3 || stat.Tickets != 6 {
+		t.Errorf("stat = %d bookings, %d tickets, want 3 and 6", stat.Bookings, stat.Tickets)
+	}
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// featuredSlotRepository implements repository.FeaturedSlotRepository
+type featuredSlotRepository struct {
+	db *Database
+}
+
+// NewFeaturedSlotRepository creates a new featured slot repository
+func NewFeaturedSlotRepository(db *Database) repository.FeaturedSlotRepository {
+	return &featuredSlotRepository{db: db}
+}
+
+func (r *featuredSlotRepository) Create(ctx context.Context, slot *entity.FeaturedSlot) error {
+	if err := r.db.WithContext(ctx).Omit("Movie").Create(slot).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create featured slot")
+	}
+	return nil
+}
+
+func (r *featuredSlotRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.FeaturedSlot, error) {
+	var slot entity.FeaturedSlot
+	if err := r.db.WithContext(ctx).Preload("Movie").First(&slot, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("featured slot")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get featured slot")
+	}
+	return &slot, nil
+}
+
+func (r *featuredSlotRepository) Update(ctx context.Context, slot *entity.FeaturedSlot) error {
+	if err := r.db.WithContext(ctx).Omit("Movie").Save(slot).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update featured slot")
+	}
+	return nil
+}
+
+func (r *featuredSlotRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.FeaturedSlot{}, "id = ?", id)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete featured slot")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound("featured slot")
+	}
+	return nil
+}
+
+func (r *featuredSlotRepository) List(ctx context.Context, filter repository.FeaturedSlotFilter, offset, limit int) ([]*entity.FeaturedSlot, int64, error) {
+	var slots []*entity.FeaturedSlot
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.FeaturedSlot{})
+
+	if filter.Position != "" {
+		db = db.Where("position = ?", filter.Position)
+	}
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.ActiveAt != nil {
+		db = db.Scopes(activeAt(*filter.ActiveAt))
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count featured slots")
+	}
+
+	if err := db.Preload("Movie").Offset(offset).Limit(limit).
+		Order("position ASC, sort_order ASC, created_at ASC").
+		Find(&slots).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list featured slots")
+	}
+
+	return slots, total, nil
+}
+
+func (r *featuredSlotRepository) ListActive(ctx context.Context, at time.Time) ([]*entity.FeaturedSlot, error) {
+	var slots []*entity.FeaturedSlot
+	if err := r.db.WithContext(ctx).
+		Preload("Movie").
+		Scopes(activeAt(at)).
+		Order("position ASC, sort_order ASC, created_at ASC").
+		Find(&slots).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list active featured slots")
+	}
+	return slots, nil
+}
+
+func (r *featuredSlotRepository) HasOverlap(ctx context.Context, slot *entity.FeaturedSlot) (bool, error) {
+	db := r.db.WithContext(ctx).Model(&entity.FeaturedSlot{}).
+		Where("position = ? AND sort_order = ?", slot.Position, slot.SortOrder)
+
+	if slot.ID != uuid.Nil {
+		db = db.Where("id <> ?", slot.ID)
+	}
+	// Windows [from, until) overlap unless one ends before the other starts
+	if slot.ActiveUntil != nil {
+		db = db.Where("active_from IS NULL OR active_from < ?", *slot.ActiveUntil)
+	}
+	if slot.ActiveFrom != nil {
+		db = db.Where("active_until IS NULL OR active_until > ?", *slot.ActiveFrom)
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check featured slot overlap")
+	}
+	return count > 0, nil
+}
+
+// activeAt keeps featured slots whose active window contains t
+func activeAt(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("active_from IS NULL OR active_from <= ?", t).
+			Where("active_until IS NULL OR active_until > ?", t)
+	}
+}
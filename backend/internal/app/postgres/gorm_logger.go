@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger routes GORM's logs to the application logger. Queries slower
+// than the threshold are logged at Warn with their SQL, arguments inlined;
+// at the Info level every query is logged at Debug and failed ones at Warn.
+// Each query's duration is recorded once metrics are attached.
+type gormLogger struct {
+	log           *logger.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration // 0 turns slow query logging off
+	metrics       *atomic.Pointer[metrics.Metrics]
+}
+
+func newGormLogger(log *logger.Logger, level gormlogger.LogLevel, slowThreshold time.Duration) *gormLogger {
+	return &gormLogger{
+		log:           log,
+		level:         level,
+		slowThreshold: slowThreshold,
+		metrics:       new(atomic.Pointer[metrics.Metrics]),
+	}
+}
+
+// LogMode returns a copy logging at level; copies share the metrics
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Info {
+		l.log.WithContext(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Warn {
+		l.log.WithContext(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Error {
+		l.log.WithContext(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace is called after every query. Failed queries are not logged outside
+// the Info level: repositories wrap and return them, and some, like unique
+// violations, are expected.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if m := l.metrics.Load(); m != nil {
+		m.RecordQuery(ctx, elapsed, failed)
+	}
+
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	switch {
+	case slow && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.log.WithContext(ctx).Warn("slow query",
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("elapsed", elapsed),
+			zap.Duration("threshold", l.slowThreshold),
+			zap.Error(err),
+		)
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		fields := []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("elapsed", elapsed),
+		}
+		if failed {
+			l.log.WithContext(ctx).Warn("query failed", append(fields, zap.Error(err))...)
+			return
+		}
+		l.log.WithContext(ctx).Debug("query", fields...)
+	}
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	query := func() (string, int64) { return "SELECT * FROM movies WHERE id = 'x'", 1 }
+	failure := errors.New("relation does not exist")
+
+	tests := []struct {
+		name    string
+		level   gormlogger.LogLevel
+		elapsed time.Duration
+		err     error
+		want    []string // messages logged, each with its level
+	}{
+		{"fast query in production", gormlogger.Warn, time.Millisecond, nil, nil},
+		{"failed query in production", gormlogger.Warn, time.Millisecond, failure, nil},
+		{"slow query in production", gormlogger.Warn, time.Second, nil, []string{"warn: slow query"}},
+		{"query in development", gormlogger.Info, time.Millisecond, nil, []string{"debug: query"}},
+		{"failed query in development", gormlogger.Info, time.Millisecond, failure, []string{"warn: query failed"}},
+		{"missing row in development", gormlogger.Info, time.Millisecond, gorm.ErrRecordNotFound, []string{"debug: query"}},
+		{"slow query in development", gormlogger.Info, time.Second, nil, []string{"warn: slow query"}},
+		{"silent", gormlogger.Silent, time.Second, failure, nil},
+	}
+	for _, tt := range tests {
+		core, logs := observer.New(zapcore.DebugLevel)
+		l := newGormLogger(&logger.Logger{Logger: zap.New(core)}, gormlogger.Warn, 200*time.Millisecond).LogMode(tt.level)
+
+		l.Trace(context.Background(), time.Now().Add(-tt.elapsed), query, tt.err)
+
+		var got []string
+		for _, entry := range logs.All() {
+			got = append(got, entry.Level.String()+": "+entry.Message)
+			if sql := entry.ContextMap()["sql"]; sql != "SELECT * FROM movies WHERE id = 'x'" {
+				t.Errorf("%s: logged sql %v", tt.name, sql)
+			}
+		}
+		if len(got) != len(tt.want) || (len(got) == 1 && got[0] != tt.want[0]) {
+			t.Errorf("%s: logged %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGormLoggerWithoutSlowThreshold(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := newGormLogger(&logger.Logger{Logger: zap.New(core)}, gormlogger.Warn, 0)
+
+	l.Trace(context.Background(), time.Now().Add(-time.Minute), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	if logs.Len() != 0 {
+		t.Errorf("logged %d entries with slow query logging off", logs.Len())
+	}
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupBookingRepository implements repository.GroupBookingRepository
+type groupBookingRepository struct {
+	db *Database
+}
+
+// NewGroupBookingRepository creates a new group booking repository
+func NewGroupBookingRepository(db *Database) repository.GroupBookingRepository {
+	return &groupBookingRepository{db: db}
+}
+
+func (r *groupBookingRepository) Create(ctx context.Context, group *entity.GroupBooking) error {
+	if err := r.db.WithContext(ctx).Create(group).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create group booking")
+	}
+	return nil
+}
+
+func (r *groupBookingRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.GroupBooking, error) {
+	var group entity.GroupBooking
+	err := r.db.WithContext(ctx).
+		Preload("Participants", func(db *gorm.DB) *gorm.DB { return db.Order("joined_at ASC") }).
+		First(&group, "share_token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "group booking not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get group booking")
+	}
+	return &group, nil
+}
+
+func (r *groupBookingRepository) AddParticipant(ctx context.Context, participant *entity.GroupBookingParticipant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.GroupBooking{}).
+			Where("id = ? AND status = ? AND current_participants < max_participants",
+				participant.GroupBookingID, entity.GroupBookingOpen).
+			Update("current_participants", gorm.Expr("current_participants + 1"))
+		if result.Error != nil {
+			return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to count group booking participant")
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.New(apperrors.CodeConflict, "group booking is full or no longer open")
+		}
+
+		if err := tx.Create(participant).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to add group booking participant")
+		}
+		return nil
+	})
+}
+
+func (r *groupBookingRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to entity.GroupBookingStatus) error {
+	if !from.CanTransitionTo(to) {
+		return apperrors.New(apperrors.CodeConflict, "invalid group booking transition from "+string(from)+" to "+string(to))
+	}
+
+	result := r.db.WithContext(ctx).Model(&entity.GroupBooking{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update group booking status")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeConflict, "group booking status has changed")
+	}
+	return nil
+}
+
+func (r *groupBookingRepository) Update(ctx context.Context, group *entity.GroupBooking) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(group).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update group booking")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestGroupBookingParticipantLimit(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewGroupBookingRepository(f.db)
+
+	var users []uuid.UUID
+	for i := range 3 {
+		user := &entity.User{Email: "group-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "Group", LastName: "Test"}
+		if err := f.db.DB.Create(user).Error; err != nil {
+			t.Fatalf("create user %d: %v", i, err)
+		}
+		users = append(users, user.ID)
+	}
+	group := &entity.GroupBooking{
+		OrganizerUserID: users[0],
+		ShowtimeID:      f.showtime.ID,
+		MaxParticipants: 2,
+		ShareTokenHash:  "hash-" + uuid.NewString(),
+		ExpiresAt:       time.Now().Add(10 * time.Minute),
+		Status:          entity.GroupBookingOpen,
+	}
+	if err := repo.Create(ctx, group); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join := func(userID uuid.UUID) error {
+		return repo.AddParticipant(ctx, &entity.GroupBookingParticipant{
+			GroupBookingID: group.ID, UserID: userID, HoldID: uuid.NewString(), SeatIDs: entity.UUIDList{uuid.New()}, JoinedAt: time.Now(),
+		})
+	}
+
+	for _, userID := range users[:2] {
+		if err := join(userID); err != nil {
+			t.Fatalf("AddParticipant: %v", err)
+		}
+	}
+	if err := join(users[2]); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Fatalf("joining a full group: %v, want %s", err, apperrors.CodeConflict)
+	}
+
+	stored, err := repo.GetByTokenHash(ctx, group.ShareTokenHash)
+	if err != nil {
+		t.Fatalf("GetByTokenHash: %v", err)
+	}
+	if stored.CurrentParticipants != 2 || len(stored.Participants) != 2 || stored.Participants[0].UserID != users[0] {
+		t.Errorf("%d counted, %d stored, want the first 2 in join order", stored.CurrentParticipants, len(stored.Participants))
+	}
+
+	if err := repo.TransitionStatus(ctx, group.ID, entity.GroupBookingOpen, entity.GroupBookingFinalized); err != nil {
+		t.Fatalf("TransitionStatus: %v", err)
+	}
+	if err := repo.TransitionStatus(ctx, group.ID, entity.GroupBookingOpen, entity.GroupBookingExpired); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("expiring a finalized group: %v, want %s", err, apperrors.CodeConflict)
+	}
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupCheckoutRepository implements repository.GroupCheckoutRepository
+type groupCheckoutRepository struct {
+	db *Database
+}
+
+// NewGroupCheckoutRepository creates a new group checkout repository
+func NewGroupCheckoutRepository(db *Database) repository.GroupCheckoutRepository {
+	return &groupCheckoutRepository{db: db}
+}
+
+func (r *groupCheckoutRepository) Create(ctx context.Context, group *entity.GroupCheckout) error {
+	if err := conn(ctx, r.db).Create(group).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create group checkout")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.GroupCheckout, error) {
+	var group entity.GroupCheckout
+	err := conn(ctx, r.db).
+		Preload("Shares", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&group, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "group checkout not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get group checkout")
+	}
+	return &group, nil
+}
+
+func (r *groupCheckoutRepository) GetActiveByHoldID(ctx context.Context, holdID string) (*entity.GroupCheckout, error) {
+	var group entity.GroupCheckout
+	err := conn(ctx, r.db).
+		Where("hold_id = ? AND status IN ?", holdID,
+			[]entity.GroupCheckoutStatus{entity.GroupCheckoutOpen, entity.GroupCheckoutAwaitingOrganizer}).
+		First(&group).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get group checkout")
+	}
+	return &group, nil
+}
+
+func (r *groupCheckoutRepository) ExistsForHold(ctx context.Context, holdID string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&entity.GroupCheckout{}).Where("hold_id = ?", holdID).Count(&count).Error
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check group checkout")
+	}
+	return count > 0, nil
+}
+
+func (r *groupCheckoutRepository) Update(ctx context.Context, group *entity.GroupCheckout) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(group).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update group checkout")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to entity.GroupCheckoutStatus) error {
+	if !from.CanTransitionTo(to) {
+		return apperrors.New(apperrors.CodeConflict, "invalid group checkout transition from "+string(from)+" to "+string(to))
+	}
+
+	result := conn(ctx, r.db).Model(&entity.GroupCheckout{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update group checkout status")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeConflict, "group checkout status has changed")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) CreateShare(ctx context.Context, share *entity.GroupCheckoutShare) error {
+	if err := conn(ctx, r.db).Create(share).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create group checkout share")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) GetShareByTokenHash(ctx context.Context, tokenHash string) (*entity.GroupCheckoutShare, error) {
+	var share entity.GroupCheckoutShare
+	err := conn(ctx, r.db).First(&share, "pay_token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTokenInvalid()
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get group checkout share")
+	}
+	return &share, nil
+}
+
+func (r *groupCheckoutRepository) GetShareByPaymentReference(ctx context.Context, reference string) (*entity.GroupCheckoutShare, error) {
+	var share entity.GroupCheckoutShare
+	err := conn(ctx, r.db).First(&share, "payment_reference = ?", reference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "group checkout share not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get group checkout share")
+	}
+	return &share, nil
+}
+
+func (r *groupCheckoutRepository) MarkSharePaid(ctx context.Context, id uuid.UUID, gatewayTransactionID string, paidAt time.Time) error {
+	result := conn(ctx, r.db).Model(&entity.GroupCheckoutShare{}).
+		Where("id = ? AND status = ?", id, entity.ShareStatusPending).
+		Updates(map[string]interface{}{
+			"status":                 entity.ShareStatusPaid,
+			"gateway_transaction_id": gatewayTransactionID,
+			"paid_at":                paidAt,
+		})
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to mark share as paid")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeConflict, "share is no longer pending")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) UpdateShareStatus(ctx context.Context, groupID uuid.UUID, from, to entity.GroupCheckoutShareStatus) error {
+	if err := conn(ctx, r.db).Model(&entity.GroupCheckoutShare{}).
+		Where("group_checkout_id = ? AND status = ?", groupID, from).
+		Update("status", to).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update share status")
+	}
+	return nil
+}
+
+func (r *groupCheckoutRepository) ListLapsed(ctx context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error) {
+	var groups []*entity.GroupCheckout
+	if err := conn(ctx, r.db).
+		Preload("Shares").
+		Where("status = ?", entity.GroupCheckoutOpen).
+		Where("EXISTS (SELECT 1 FROM group_checkout_shares s WHERE s.group_checkout_id = group_checkouts.id AND s.status = ? AND s.expires_at < ?)",
+			entity.ShareStatusPending, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&groups).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list lapsed group checkouts")
+	}
+	return groups, nil
+}
+
+func (r *groupCheckoutRepository) ListAbandoned(ctx context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error) {
+	var groups []*entity.GroupCheckout
+	if err := conn(ctx, r.db).
+		Preload("Shares").
+		Where("status = ? AND hold_expires_at < ?", entity.GroupCheckoutAwaitingOrganizer, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&groups).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list abandoned group checkouts")
+	}
+	return groups, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// holdRecoveryRepository implements repository.HoldRecoveryRepository
+type holdRecoveryRepository struct {
+	db *Database
+}
+
+// NewHoldRecoveryRepository creates a new hold recovery repository
+func NewHoldRecoveryRepository(db *Database) repository.HoldRecoveryRepository {
+	return &holdRecoveryRepository{db: db}
+}
+
+func (r *holdRecoveryRepository) Create(ctx context.Context, recovery *entity.HoldRecovery) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "showtime_id"}},
+			DoNothing: true,
+		}).
+		Create(recovery)
+	if result.Error != nil {
+		return false, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to create hold recovery")
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *holdRecoveryRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.HoldRecovery, error) {
+	var recovery entity.HoldRecovery
+	err := r.db.WithContext(ctx).First(&recovery, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "recovery link is invalid")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get hold recovery")
+	}
+	return &recovery, nil
+}
+
+func (r *holdRecoveryRepository) GetByUserAndShowtime(ctx context.Context, userID, showtimeID uuid.UUID) (*entity.HoldRecovery, error) {
+	var recovery entity.HoldRecovery
+	err := r.db.WithContext(ctx).First(&recovery, "user_id = ? AND showtime_id = ?", userID, showtimeID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get hold recovery")
+	}
+	return &recovery, nil
+}
+
+func (r *holdRecoveryRepository) Update(ctx context.Context, recovery *entity.HoldRecovery) error {
+	if err := r.db.WithContext(ctx).Save(recovery).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update hold recovery")
+	}
+	return nil
+}
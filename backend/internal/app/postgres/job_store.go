@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/scheduler"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jobLockNamespace is mixed into advisory lock keys so job locks do not
+// collide with other advisory lock users
+const jobLockNamespace = "scheduler.job:"
+
+// jobStore implements scheduler.Store with session-level advisory locks and
+// the job_runs table
+type jobStore struct {
+	db *Database
+}
+
+// NewJobStore creates a Postgres-backed scheduler store
+func NewJobStore(db *Database) scheduler.Store {
+	return &jobStore{db: db}
+}
+
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(jobLockNamespace + name))
+	return int64(h.Sum64())
+}
+
+// TryLock takes a session-level advisory lock on a dedicated connection.
+// Postgres releases it on its own if this instance dies and the connection
+// drops, which lets another instance take the job over.
+func (s *jobStore) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := s.db.DB.DB()
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get database handle")
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get connection for job lock")
+	}
+
+	key := jobLockKey(name)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to take job lock")
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			// Closing the connection below releases the lock as well
+			s.db.logger.Warn("failed to release job lock", zap.String("job", name), zap.Error(err))
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func (s *jobStore) Get(ctx context.Context, name string) (*scheduler.RunState, error) {
+	var run entity.JobRun
+	if err := s.db.WithContext(ctx).First(&run, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get job run")
+	}
+
+	state := &scheduler.RunState{
+		Name:           run.Name,
+		Owner:          run.Owner,
+		LastStartedAt:  run.LastStartedAt,
+		LastFinishedAt: run.LastFinishedAt,
+		LastSuccessAt:  run.LastSuccessAt,
+		LastDuration:   time.Duration(run.LastDurationMs) * time.Millisecond,
+		Runs:           run.Runs,
+		Failures:       run.Failures,
+	}
+	if run.LastError != nil {
+		state.LastError = *run.LastError
+	}
+	return state, nil
+}
+
+func (s *jobStore) RecordStart(ctx context.Context, name, owner string, at time.Time) error {
+	run := entity.JobRun{Name: name, Owner: owner, LastStartedAt: &at}
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"owner", "last_started_at", "updated_at"}),
+		}).
+		Create(&run).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record job start")
+	}
+	return nil
+}
+
+func (s *jobStore) RecordFinish(ctx context.Context, name string, at time.Time, duration time.Duration, runErr error) error {
+	updates := map[string]interface{}{
+		"last_finished_at": at,
+		"last_duration_ms": duration.Milliseconds(),
+		"runs":             gorm.Expr("runs + 1"),
+	}
+	if runErr != nil {
+		updates["last_error"] = runErr.Error()
+		updates["failures"] = gorm.Expr("failures + 1")
+	} else {
+		updates["last_error"] = nil
+		updates["last_success_at"] = at
+	}
+
+	err := s.db.WithContext(ctx).Model(&entity.JobRun{}).Where("name = ?", name).Updates(updates).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record job finish")
+	}
+	return nil
+}
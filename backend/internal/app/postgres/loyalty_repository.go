@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loyaltyRepository implements repository.LoyaltyRepository
+type loyaltyRepository struct {
+	db *Database
+}
+
+// NewLoyaltyRepository creates a new loyalty repository
+func NewLoyaltyRepository(db *Database) repository.LoyaltyRepository {
+	return &loyaltyRepository{db: db}
+}
+
+func (r *loyaltyRepository) GetBalance(ctx context.Context, userID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	var account entity.LoyaltyAccount
+	if err := r.db.WithContext(ctx).First(&account, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get loyalty balance")
+	}
+	return &account, nil
+}
+
+func (r *loyaltyRepository) ListTransactions(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.LoyaltyTransaction, int64, error) {
+	db := r.db.WithContext(ctx).Model(&entity.LoyaltyTransaction{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count loyalty transactions")
+	}
+
+	var transactions []*entity.LoyaltyTransaction
+	if err := db.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&transactions).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list loyalty transactions")
+	}
+	return transactions, total, nil
+}
+
+func (r *loyaltyRepository) AddPoints(ctx context.Context, userID, bookingID uuid.UUID, points int, description string) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The booking and type index makes a replayed earn a no-op
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.LoyaltyTransaction{
+			UserID:      userID,
+			BookingID:   &bookingID,
+			Type:        entity.LoyaltyEarn,
+			Points:      points,
+			Description: description,
+		})
+		if result.Error != nil {
+			return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to record loyalty points")
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"points_balance":   gorm.Expr("loyalty_accounts.points_balance + ?", points),
+				"last_activity_at": now,
+				"updated_at":       now,
+			}),
+		}).Create(&entity.LoyaltyAccount{
+			UserID:         userID,
+			PointsBalance:  points,
+			LastActivityAt: now,
+		}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to add loyalty points")
+		}
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return added, nil
+}
+
+func (r *loyaltyRepository) ExpireInactive(ctx context.Context, before time.Time, limit int) (int, error) {
+	var accounts []*entity.LoyaltyAccount
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Accounts another sweeper holds are skipped, so no balance expires twice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("points_balance > 0 AND last_activity_at < ?", before).
+			Order("last_activity_at ASC").
+			Limit(limit).
+			Find(&accounts).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get inactive loyalty accounts")
+		}
+
+		for _, account := range accounts {
+			if err := tx.Create(&entity.LoyaltyTransaction{
+				UserID:      account.UserID,
+				Type:        entity.LoyaltyExpire,
+				Points:      -account.PointsBalance,
+				Description: fmt.Sprintf("Points expired after no activity since %s", account.LastActivityAt.Format("2006-01-02")),
+			}).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record expired loyalty points")
+			}
+			if err := tx.Model(&entity.LoyaltyAccount{}).
+				Where("user_id = ?", account.UserID).
+				Updates(map[string]any{"points_balance": 0, "updated_at": time.Now()}).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to expire loyalty points")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(accounts), nil
+}
+
+// redeemLoyaltyPoints debits the points a new booking spends. The balance
+// is checked and debited in one statement, so concurrent checkouts cannot
+// both spend the same points.
+func redeemLoyaltyPoints(tx *gorm.DB, booking *entity.Booking) error {
+	if booking.UserID == nil {
+		return apperrors.New(apperrors.CodeInsufficientPoints, "guest bookings cannot redeem loyalty points")
+	}
+
+	result := tx.Model(&entity.LoyaltyAccount{}).
+		Where("user_id = ? AND points_balance >= ?", *booking.UserID, booking.PointsRedeemed).
+		Updates(map[string]any{
+			"points_balance":   gorm.Expr("points_balance - ?", booking.PointsRedeemed),
+			"last_activity_at": time.Now(),
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to redeem loyalty points")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeInsufficientPoints, "not enough loyalty points")
+	}
+
+	if err := tx.Create(&entity.LoyaltyTransaction{
+		UserID:      *booking.UserID,
+		BookingID:   &booking.ID,
+		Type:        entity.LoyaltyRedeem,
+		Points:      -booking.PointsRedeemed,
+		Description: "Redeemed on booking " + booking.BookingReference,
+	}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record redeemed loyalty points")
+	}
+	return nil
+}
+
+// reverseLoyaltyPoints undoes the points of a booking that will not go
+// ahead: redeemed points are credited back and earned points debited, as
+// far as the balance allows. Each is undone once.
+func reverseLoyaltyPoints(tx *gorm.DB, booking *entity.Booking) error {
+	var transactions []entity.LoyaltyTransaction
+	if err := tx.Where("booking_id = ?", booking.ID).Find(&transactions).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get booking loyalty points")
+	}
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byType := make(map[entity.LoyaltyTransactionType]entity.LoyaltyTransaction, len(transactions))
+	for _, t := range transactions {
+		byType[t.Type] = t
+	}
+	redeemed, hasRedeemed := byType[entity.LoyaltyRedeem]
+	_, refunded := byType[entity.LoyaltyRefund]
+	earned, hasEarned := byType[entity.LoyaltyEarn]
+	_, reversed := byType[entity.LoyaltyReverse]
+	if (!hasRedeemed || refunded) && (!hasEarned || reversed) {
+		return nil
+	}
+
+	userID := transactions[0].UserID
+	var account entity.LoyaltyAccount
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, "user_id = ?", userID).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get loyalty account")
+	}
+
+	balance := account.PointsBalance
+	var undo []entity.LoyaltyTransaction
+	if hasRedeemed && !refunded {
+		balance -= redeemed.Points
+		undo = append(undo, entity.LoyaltyTransaction{
+			UserID:      userID,
+			BookingID:   &booking.ID,
+			Type:        entity.LoyaltyRefund,
+			Points:      -redeemed.Points,
+			Description: "Returned from booking " + booking.BookingReference,
+		})
+	}
+	if hasEarned && !reversed {
+		if taken := min(earned.Points, balance); taken > 0 {
+			balance -= taken
+			undo = append(undo, entity.LoyaltyTransaction{
+				UserID:      userID,
+				BookingID:   &booking.ID,
+				Type:        entity.LoyaltyReverse,
+				Points:      -taken,
+				Description: "Taken back for cancelled booking " + booking.BookingReference,
+			})
+		}
+	}
+	if len(undo) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&undo).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record returned loyalty points")
+	}
+	if err := tx.Model(&entity.LoyaltyAccount{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{"points_balance": balance, "updated_at": time.Now()}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to return loyalty points")
+	}
+	return nil
+}
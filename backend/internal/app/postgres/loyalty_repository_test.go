@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestLoyaltyPoints(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	bookings := NewBookingRepository(f.db)
+	loyalty := NewLoyaltyRepository(f.db)
+
+	user := &entity.User{Email: "loyalty-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "Film", LastName: "Fan"}
+	if err := f.db.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	book := func(status entity.BookingStatus, points int) (*entity.Booking, error) {
+		booking := &entity.Booking{
+			BookingReference: "BK-LOYALTY-" + uuid.NewString()[:8],
+			UserID:           &user.ID,
+			ShowtimeID:       f.showtime.ID,
+			NumTickets:       1,
+			SubtotalAmount:   10,
+			FinalAmount:      10,
+			BookingStatus:    status,
+			PaymentStatus:    entity.PaymentPending,
+			SalesChannel:     entity.ChannelOnline,
+			BookedAt:         time.Now(),
+			PointsRedeemed:   points,
+		}
+		return booking, bookings.CreateWithSeats(ctx, booking, nil)
+	}
+	balance := func() int {
+		t.Helper()
+		account, err := loyalty.GetBalance(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if account == nil {
+			return 0
+		}
+		return account.PointsBalance
+	}
+
+	earning, err := book(entity.BookingConfirmed, 0)
+	if err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+	for i, want := range []bool{true, false} {
+		added, err := loyalty.AddPoints(ctx, user.ID, earning.ID, 500, "Booking "+earning.BookingReference)
+		if err != nil {
+			t.Fatalf("AddPoints: %v", err)
+		}
+		if added != want {
+			t.Errorf("AddPoints #%d = %v, want %v", i+1, added, want)
+		}
+	}
+	if got := balance(); got != 500 {
+		t.Fatalf("balance = %d, want 500 after a replayed earn", got)
+	}
+
+	// The balance is checked with the booking, which is not written
+	if _, err := book(entity.BookingPending, 800); !apperrors.Is(err, apperrors.CodeInsufficientPoints) {
+		t.Fatalf("redeeming more than the balance: %v, want %s", err, apperrors.CodeInsufficientPoints)
+	}
+	redeeming, err := book(entity.BookingPending, 300)
+	if err != nil {
+		t.Fatalf("redeem: %v", err)
+	}
+	if got := balance(); got != 200 {
+		t.Errorf("balance = %d after redeeming 300, want 200", got)
+	}
+
+	// Cancelling gives the redeemed points back and takes the earned ones
+	if _, err := bookings.Cancel(ctx, redeeming.ID, 0); err != nil {
+		t.Fatalf("cancel the redeeming booking: %v", err)
+	}
+	if got := balance(); got != 500 {
+		t.Errorf("balance = %d after the redeemed points came back, want 500", got)
+	}
+	if _, err := bookings.Cancel(ctx, earning.ID, 0); err != nil {
+		t.Fatalf("cancel the earning booking: %v", err)
+	}
+	if got := balance(); got != 0 {
+		t.Errorf("balance = %d after the earned points were taken back, want 0", got)
+	}
+
+	transactions, total, err := loyalty.ListTransactions(ctx, user.ID, 0, 10)
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	sum := 0
+	for _, tx := range transactions {
+		sum += tx.Points
+	}
+	if total != 4 || sum != 0 {
+		t.Errorf("%d transactions adding up to %d, want 4 adding up to 0", total, sum)
+	}
+}
+
+func TestExpireInactiveLoyaltyPoints(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	loyalty := NewLoyaltyRepository(db)
+
+	longAgo := time.Now().AddDate(-2, 0, 0)
+	var users []uuid.UUID
+	for i, lastActivity := range []time.Time{longAgo, longAgo.Add(time.Hour), time.Now()} {
+		user := &entity.User{Email: "expiry-" + uuid.NewString()[:8] + "@example.com", PasswordHash: "x", FirstName: "Film", LastName: "Fan"}
+		if err := db.DB.Create(user).Error; err != nil {
+			t.Fatalf("create user %d: %v", i, err)
+		}
+		if err := db.DB.Create(&entity.LoyaltyAccount{UserID: user.ID, PointsBalance: 100, LastActivityAt: lastActivity}).Error; err != nil {
+			t.Fatalf("create account %d: %v", i, err)
+		}
+		users = append(users, user.ID)
+	}
+
+	// The oldest account goes first; the active one is never expired
+	before := time.Now().AddDate(-1, 0, 0)
+	for i, want := range []int{1, 1, 0} {
+		n, err := loyalty.ExpireInactive(ctx, before, 1)
+		if err != nil {
+			t.Fatalf("ExpireInactive: %v", err)
+		}
+		if n != want {
+			t.Errorf("sweep %d expired %d accounts, want %d", i+1, n, want)
+		}
+	}
+	for i, want := range []int{0, 0, 100} {
+		account, err := loyalty.GetBalance(ctx, users[i])
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if account.PointsBalance != want {
+			t.Errorf("account %d balance = %d, want %d", i, account.PointsBalance, want)
+		}
+	}
+}
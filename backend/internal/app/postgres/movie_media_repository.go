@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// movieMediaRepository implements repository.MovieMediaRepository
+type movieMediaRepository struct {
+	db *Database
+}
+
+// NewMovieMediaRepository creates a new movie media repository
+func NewMovieMediaRepository(db *Database) repository.MovieMediaRepository {
+	return &movieMediaRepository{db: db}
+}
+
+func (r *movieMediaRepository) Create(ctx context.Context, media *entity.MovieMedia) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the movie so concurrent uploads get distinct positions
+		if err := tx.Exec("SELECT 1 FROM movies WHERE id = ? FOR UPDATE", media.MovieID).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create movie media")
+		}
+
+		var last *int
+		if err := tx.Model(&entity.MovieMedia{}).
+			Where("movie_id = ?", media.MovieID).
+			Select("MAX(sort_order)").
+			Scan(&last).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create movie media")
+		}
+		media.SortOrder = 0
+		if last != nil {
+			media.SortOrder = *last + 1
+		}
+
+		if err := tx.Create(media).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create movie media")
+		}
+		return nil
+	})
+}
+
+func (r *movieMediaRepository) GetByID(ctx context.Context, movieID, id uuid.UUID) (*entity.MovieMedia, error) {
+	var media entity.MovieMedia
+	if err := r.db.WithContext(ctx).First(&media, "id = ? AND movie_id = ?", id, movieID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("movie media")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get movie media")
+	}
+	return &media, nil
+}
+
+func (r *movieMediaRepository) Update(ctx context.Context, media *entity.MovieMedia) error {
+	if err := r.db.WithContext(ctx).Save(media).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update movie media")
+	}
+	return nil
+}
+
+func (r *movieMediaRepository) Delete(ctx context.Context, movieID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.MovieMedia{}, "id = ? AND movie_id = ?", id, movieID)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete movie media")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound("movie media")
+	}
+	return nil
+}
+
+func (r *movieMediaRepository) ListByMovie(ctx context.Context, movieID uuid.UUID, publishedOnly bool) ([]*entity.MovieMedia, error) {
+	db := r.db.WithContext(ctx).Where("movie_id = ?", movieID)
+	if publishedOnly {
+		db = db.Where("published = ?", true)
+	}
+
+	var media []*entity.MovieMedia
+	if err := db.Order("sort_order ASC, created_at ASC").Find(&media).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list movie media")
+	}
+	return media, nil
+}
+
+func (r *movieMediaRepository) Count(ctx context.Context, movieID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.MovieMedia{}).Where("movie_id = ?", movieID).Count(&count).Error; err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count movie media")
+	}
+	return count, nil
+}
+
+func (r *movieMediaRepository) Reorder(ctx context.Context, movieID uuid.UUID, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&entity.MovieMedia{}).
+				Where("id = ? AND movie_id = ?", id, movieID).
+				UpdateColumn("sort_order", i).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to reorder movie media")
+			}
+		}
+		return nil
+	})
+}
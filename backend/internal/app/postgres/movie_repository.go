@@ -3,14 +3,18 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/app/repository"
 	apperrors "cinemaos-backend/internal/pkg/errors"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type movieRepository struct {
@@ -67,16 +71,32 @@ func (r *movieRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *movieRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&entity.Movie{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to restore movie")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeNotFound, "movie not found")
+	}
+	return nil
+}
+
 func (r *movieRepository) List(ctx context.Context, filter repository.MovieFilter, offset, limit int) ([]*entity.Movie, int64, error) {
 	var movies []*entity.Movie
 	var total int64
 
 	db := r.db.WithContext(ctx).Model(&entity.Movie{})
+	if filter.IncludeDeleted {
+		db = db.Unscoped()
+	}
 
 	// Apply filters
+	order := clause.Expr{SQL: "release_date DESC"}
 	if filter.Search != "" {
-		searchTerm := "%" + filter.Search + "%"
-		db = db.Where("title ILIKE ? OR description ILIKE ?", searchTerm, searchTerm)
+		db, order = searchMovies(db, filter.Search)
 	}
 
 	if filter.Genre != "" {
@@ -103,13 +123,35 @@ func (r *movieRepository) List(ctx context.Context, filter repository.MovieFilte
 		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count movies")
 	}
 
-	if err := db.Offset(offset).Limit(limit).Order("release_date DESC").Find(&movies).Error; err != nil {
+	if err := db.Clauses(clause.OrderBy{Expression: order}).Offset(offset).Limit(limit).Find(&movies).Error; err != nil {
 		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list movies")
 	}
 
 	return movies, total, nil
 }
 
+// movieSearchMinLength is the shortest search run as a full-text search
+const movieSearchMinLength = 3
+
+// searchMovies restricts db to the movies matching a search and returns
+// their order. Searches go through search_vector, ranked so title matches
+// come before description ones and those before director and cast ones,
+// then by popularity. A search shorter than movieSearchMinLength, or
+// without a word in it, matches the start of titles instead. Accents are
+// ignored either way.
+func searchMovies(db *gorm.DB, search string) (*gorm.DB, clause.Expr) {
+	search = strings.TrimSpace(search)
+	if utf8.RuneCountInString(search) < movieSearchMinLength || prefixTSQuery(search) == "" {
+		pattern := escapeLike(search) + "%"
+		return db.Where("unaccent(title) ILIKE unaccent(?)", pattern),
+			gorm.Expr("popularity_score DESC, release_date DESC")
+	}
+
+	query := "websearch_to_tsquery('simple', unaccent(?))"
+	return db.Where("search_vector @@ "+query, search),
+		gorm.Expr("ts_rank(search_vector, "+query+") DESC, popularity_score DESC, release_date DESC", search)
+}
+
 func (r *movieRepository) GetNowShowing(ctx context.Context, cinemaID *uuid.UUID, offset, limit int) ([]*entity.Movie, int64, error) {
 	var movies []*entity.Movie
 	var total int64
@@ -137,6 +179,48 @@ func (r *movieRepository) GetNowShowing(ctx context.Context, cinemaID *uuid.UUID
 	return movies, total, nil
 }
 
+func (r *movieRepository) GetNowShowingFor(ctx context.Context, prefs repository.NowShowingPreferences, offset, limit int) ([]*entity.Movie, int64, error) {
+	var movies []*entity.Movie
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.Movie{}).Where("is_now_showing = ? AND is_active = ?", true, true)
+
+	if prefs.OnlyMatching {
+		var match []string
+		var args []any
+		if len(prefs.Genres) > 0 {
+			match = append(match, "genres && ?")
+			args = append(args, pq.Array(prefs.Genres))
+		}
+		if len(prefs.Formats) > 0 {
+			match = append(match, "format IN ?")
+			args = append(args, prefs.Formats)
+		}
+		if len(prefs.CinemaIDs) > 0 {
+			match = append(match, "id IN (SELECT movie_id FROM showtimes WHERE cinema_id IN ? AND show_date >= ? AND deleted_at IS NULL)")
+			args = append(args, prefs.CinemaIDs, time.Now().Format("2006-01-02"))
+		}
+		if len(match) > 0 {
+			db = db.Where(strings.Join(match, " OR "), args...)
+		}
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count now showing movies")
+	}
+
+	// Movies in a favorite genre first, each part by popularity
+	order := gorm.Expr("popularity_score DESC, id ASC")
+	if len(prefs.Genres) > 0 {
+		order = gorm.Expr("COALESCE(genres && ?, false) DESC, popularity_score DESC, id ASC", pq.Array(prefs.Genres))
+	}
+	if err := db.Clauses(clause.OrderBy{Expression: order}).Offset(offset).Limit(limit).Find(&movies).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list now showing movies")
+	}
+
+	return movies, total, nil
+}
+
 func (r *movieRepository) GetComingSoon(ctx context.Context, offset, limit int) ([]*entity.Movie, int64, error) {
 	var movies []*entity.Movie
 	var total int64
@@ -164,3 +248,62 @@ func (r *movieRepository) UpdatePopularityScore(ctx context.Context, id uuid.UUI
 	}
 	return nil
 }
+
+func (r *movieRepository) UpdatePopularityScores(ctx context.Context, scores map[uuid.UUID]float64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, score := range scores {
+			err := tx.Model(&entity.Movie{}).
+				Where("id = ?", id).
+				Update("popularity_score", score).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update popularity scores")
+	}
+	return nil
+}
+
+func (r *movieRepository) ListPopularityStats(ctx context.Context, since time.Time) ([]repository.MoviePopularityStats, error) {
+	var stats []repository.MoviePopularityStats
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT movies.id AS movie_id,
+			COALESCE(sales.bookings, 0) AS bookings,
+			COALESCE(sales.revenue, 0) AS revenue,
+			movies.user_rating AS rating
+		FROM movies
+		LEFT JOIN (
+			SELECT showtimes.movie_id,
+				COUNT(*) FILTER (WHERE bookings.payment_status = ?) AS bookings,
+				SUM(bookings.final_amount - COALESCE(bookings.refund_amount, 0)) AS revenue
+			FROM bookings
+			JOIN showtimes ON showtimes.id = bookings.showtime_id
+			WHERE bookings.payment_status IN ? AND bookings.booked_at >= ?
+				AND bookings.deleted_at IS NULL
+			GROUP BY showtimes.movie_id
+		) sales ON sales.movie_id = movies.id
+		WHERE movies.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM showtimes
+				WHERE showtimes.movie_id = movies.id AND showtimes.show_date >= ?::date
+					AND showtimes.status <> ? AND showtimes.deleted_at IS NULL
+			)`, entity.PaymentPaid, soldPaymentStatuses, since, since, entity.ShowtimeCancelled).Scan(&stats).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get movie popularity stats")
+	}
+	return stats, nil
+}
+
+func (r *movieRepository) UpdateReviewStats(ctx context.Context, id uuid.UUID, rating *float64, count int64) error {
+	result := r.db.WithContext(ctx).Model(&entity.Movie{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{"user_rating": rating, "review_count": count})
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update review stats")
+	}
+	return nil
+}
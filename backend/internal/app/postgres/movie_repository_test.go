@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestListPopularityStats(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	confirmedAt := f.startsAt.Add(-24 * time.Hour)
+	f.book(t, entity.BookingConfirmed, 2, 24, confirmedAt)
+	f.book(t, entity.BookingConfirmed, 1, 48, confirmedAt)
+	movies := NewMovieRepository(f.db)
+
+	stats, err := movies.ListPopularityStats(ctx, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListPopularityStats: %v", err)
+	}
+	var found bool
+	for _, s := range stats {
+		if s.MovieID != f.movie.ID {
+			continue
+		}
+		found = true
+		if s.Bookings != 2 || s.Revenue != 30 || s.Rating != nil {
+			t.Errorf("stats = %+v, want 2 bookings for 30 and no rating", s)
+		}
+	}
+	if !found {
+		t.Fatal("the movie with an upcoming showtime has no stats")
+	}
+
+	// Scores of many movies are saved together
+	if err := movies.UpdatePopularityScores(ctx, map[uuid.UUID]float64{f.movie.ID: 7.25}); err != nil {
+		t.Fatalf("UpdatePopularityScores: %v", err)
+	}
+	movie, err := movies.GetByID(ctx, f.movie.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if movie.PopularityScore != 7.25 {
+		t.Errorf("popularity = %v, want 7.25", movie.PopularityScore)
+	}
+}
+
+func TestSearchMoviesSQL(t *testing.T) {
+	// A dry run builds the statements without a database
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	tests := []struct {
+		search string
+		where  string
+		order  string
+		arg    string
+	}{
+		{"dune part two", "search_vector @@ websearch_to_tsquery('simple', unaccent($1))", "ts_rank(search_vector, websearch_to_tsquery('simple', unaccent($2))) DESC, popularity_score DESC", "dune part two"},
+		{"  amélie ", "search_vector @@", "ts_rank(", "amélie"},
+		{"du", "unaccent(title) ILIKE unaccent($1)", "popularity_score DESC, release_date DESC", "du%"},
+		{"100%", "search_vector @@", "ts_rank(", "100%"},
+		{"!!!", "unaccent(title) ILIKE unaccent($1)", "popularity_score DESC", "!!!%"},
+	}
+	for _, tt := range tests {
+		var movies []*entity.Movie
+		query, order := searchMovies(db.Model(&entity.Movie{}), tt.search)
+		stmt := query.Clauses(clause.OrderBy{Expression: order}).Find(&movies).Statement
+		sql := stmt.SQL.String()
+		if !strings.Contains(sql, "WHERE "+tt.where) || !strings.Contains(sql, "ORDER BY "+tt.order) {
+			t.Errorf("%q: sql = %s", tt.search, sql)
+		}
+		if len(stmt.Vars) == 0 || stmt.Vars[0] != tt.arg {
+			t.Errorf("%q: args = %v, want %q first", tt.search, stmt.Vars, tt.arg)
+		}
+	}
+}
+
+func TestSearchMovies(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	movies := NewMovieRepository(db)
+	suffix := uuid.NewString()[:8]
+	word := "zephyrine" + suffix
+
+	director := "Zoë " + word
+	description := "A story about " + word
+	created := map[string]*entity.Movie{
+		"director":    {Title: "Director " + suffix, Director: &director},
+		"description": {Title: "Description " + suffix, Description: &description},
+		"title":       {Title: "Amélie " + word},
+	}
+	for name, movie := range created {
+		movie.Slug = "search-" + name + "-" + suffix
+		movie.Duration = 100
+		movie.ReleaseDate = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		movie.IsActive = true
+		if err := movies.Create(ctx, movie); err != nil {
+			t.Fatalf("create movie: %v", err)
+		}
+	}
+
+	titles := func(search string) []string {
+		t.Helper()
+		found, _, err := movies.List(ctx, repository.MovieFilter{Search: search}, 0, 1000)
+		if err != nil {
+			t.Fatalf("List(%q): %v", search, err)
+		}
+		var titles []string
+		for _, movie := range found {
+			titles = append(titles, movie.Title)
+		}
+		return titles
+	}
+
+	// Title matches rank above description ones and those above the cast
+	want := []string{created["title"].Title, created["description"].Title, created["director"].Title}
+	if got := titles(word); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("search for %q = %q, want %q", word, got, want)
+	}
+	// Accents are ignored, and "-word" excludes
+	if got := titles("amelie " + word); len(got) != 1 || got[0] != created["title"].Title {
+		t.Errorf("search without the accent = %q", got)
+	}
+	if got := titles(word + " -amelie"); len(got) != 2 {
+		t.Errorf("search excluding the title match = %q", got)
+	}
+	// Short searches match the start of titles, accents aside
+	if got := titles("am"); !slices.Contains(got, created["title"].Title) || slices.Contains(got, created["director"].Title) {
+		t.Errorf("prefix search = %q", got)
+	}
+}
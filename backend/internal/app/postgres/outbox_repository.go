@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// outboxRepository implements repository.OutboxRepository
+type outboxRepository struct {
+	db *Database
+}
+
+// NewOutboxRepository creates a new event outbox repository
+func NewOutboxRepository(db *Database) repository.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, events []*entity.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := conn(ctx, r.db).Create(&events).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to store events")
+	}
+	return nil
+}
+
+func (r *outboxRepository) ListUnpublished(ctx context.Context, maxAttempts, limit int) ([]*entity.OutboxEvent, error) {
+	var events []*entity.OutboxEvent
+	if err := conn(ctx, r.db).
+		Where("published_at IS NULL AND attempts < ?", maxAttempts).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list unpublished events")
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	if err := conn(ctx, r.db).Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Update("published_at", at).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to mark event published")
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	if err := conn(ctx, r.db).Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record event failure")
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&entity.OutboxEvent{})
+	if result.Error != nil {
+		return 0, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete published events")
+	}
+	return result.RowsAffected, nil
+}
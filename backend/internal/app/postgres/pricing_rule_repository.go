@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// pricingRuleRepository implements repository.PricingRuleRepository
+type pricingRuleRepository struct {
+	db *Database
+}
+
+// NewPricingRuleRepository creates a new pricing rule repository
+func NewPricingRuleRepository(db *Database) repository.PricingRuleRepository {
+	return &pricingRuleRepository{db: db}
+}
+
+func (r *pricingRuleRepository) ListByCinema(ctx context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, error) {
+	var rules []*entity.PricingRule
+	if err := r.db.WithContext(ctx).
+		Where("cinema_id = ?", cinemaID).
+		Order("created_at, id").
+		Find(&rules).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list pricing rules")
+	}
+	return rules, nil
+}
+
+func (r *pricingRuleRepository) GetByID(ctx context.Context, cinemaID, id uuid.UUID) (*entity.PricingRule, error) {
+	var rule entity.PricingRule
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND cinema_id = ?", id, cinemaID).
+		First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("pricing rule")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get pricing rule")
+	}
+	return &rule, nil
+}
+
+func (r *pricingRuleRepository) Create(ctx context.Context, rule *entity.PricingRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create pricing rule")
+	}
+	return nil
+}
+
+func (r *pricingRuleRepository) Update(ctx context.Context, rule *entity.PricingRule) error {
+	if err := r.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update pricing rule")
+	}
+	return nil
+}
+
+func (r *pricingRuleRepository) Delete(ctx context.Context, cinemaID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND cinema_id = ?", id, cinemaID).
+		Delete(&entity.PricingRule{})
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete pricing rule")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound("pricing rule")
+	}
+	return nil
+}
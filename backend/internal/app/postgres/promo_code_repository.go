@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// promoCodeRepository implements repository.PromoCodeRepository
+type promoCodeRepository struct {
+	db *Database
+}
+
+// NewPromoCodeRepository creates a new promo code repository
+func NewPromoCodeRepository(db *Database) repository.PromoCodeRepository {
+	return &promoCodeRepository{db: db}
+}
+
+func (r *promoCodeRepository) Create(ctx context.Context, promo *entity.PromoCode) error {
+	if err := r.db.WithContext(ctx).Create(promo).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create promo code")
+	}
+	return nil
+}
+
+func (r *promoCodeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+	if err := r.db.WithContext(ctx).First(&promo, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("promo code")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get promo code")
+	}
+	return &promo, nil
+}
+
+// GetByCode matches codes case-insensitively
+func (r *promoCodeRepository) GetByCode(ctx context.Context, code string) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+	if err := r.db.WithContext(ctx).First(&promo, "UPPER(code) = UPPER(?)", code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("promo code")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get promo code")
+	}
+	return &promo, nil
+}
+
+func (r *promoCodeRepository) Update(ctx context.Context, promo *entity.PromoCode) error {
+	if err := r.db.WithContext(ctx).Save(promo).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update promo code")
+	}
+	return nil
+}
+
+func (r *promoCodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.PromoCode{}, "id = ?", id)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete promo code")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound("promo code")
+	}
+	return nil
+}
+
+func (r *promoCodeRepository) List(ctx context.Context, activeOnly bool, offset, limit int) ([]*entity.PromoCode, int64, error) {
+	db := r.db.WithContext(ctx).Model(&entity.PromoCode{})
+	if activeOnly {
+		db = db.Where("is_active = ?", true)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count promo codes")
+	}
+
+	var promos []*entity.PromoCode
+	if err := db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&promos).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list promo codes")
+	}
+	return promos, total, nil
+}
+
+func (r *promoCodeRepository) IncrementUsageCount(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Model(&entity.PromoCode{}).
+		Where("id = ?", id).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update promo code usage")
+	}
+	return nil
+}
+
+func (r *promoCodeRepository) GetUserUsageCount(ctx context.Context, promoID uuid.UUID, userID *uuid.UUID, email string) (int, error) {
+	return countPromoRedemptions(r.db.WithContext(ctx), promoID, userID, email)
+}
+
+func (r *promoCodeRepository) CodeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&entity.PromoCode{}).
+		Where("UPPER(code) = UPPER(?)", code).
+		Count(&count).Error; err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check promo code")
+	}
+	return count > 0, nil
+}
+
+func (r *promoCodeRepository) GetStats(ctx context.Context, promoID uuid.UUID) (*repository.PromoCodeStats, error) {
+	var row struct {
+		Bookings      int64
+		TotalDiscount float64
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.Booking{}).
+		Select("COUNT(*) AS bookings, COALESCE(SUM(discount_amount), 0) AS total_discount").
+		Where("promo_code_id = ? AND booking_status IN ?", promoID,
+			[]entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted}).
+		Scan(&row).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get promo code stats")
+	}
+	return &repository.PromoCodeStats{
+		Bookings:      row.Bookings,
+		TotalDiscount: row.TotalDiscount,
+	}, nil
+}
+
+// countPromoRedemptions counts the uses of the promo code by an account or
+// a guest email. An account's uses include those made as a guest with its
+// email, and a guest's those of the account with that email, so signing in
+// or out does not reset the per-user limit.
+func countPromoRedemptions(db *gorm.DB, promoID uuid.UUID, userID *uuid.UUID, email string) (int, error) {
+	query := db.Model(&entity.PromoCodeRedemption{}).Where("promo_code_id = ?", promoID)
+	email = strings.ToLower(strings.TrimSpace(email))
+	switch {
+	case userID != nil:
+		query = query.Where("user_id = ? OR (guest_email <> '' AND guest_email = (SELECT LOWER(email) FROM users WHERE id = ?))", *userID, *userID)
+	case email != "":
+		query = query.Where("guest_email = ? OR user_id IN (SELECT id FROM users WHERE LOWER(email) = ?)", email, email)
+	default:
+		return 0, nil
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count promo code usage")
+	}
+	return int(count), nil
+}
+
+// redeemPromoCode counts a use of the booking's promo code within the
+// booking's transaction, after the booking is created. The code's row is
+// locked first, so concurrent bookings cannot both take its last use; a
+// code that can no longer be applied fails the transaction.
+func redeemPromoCode(tx *gorm.DB, booking *entity.Booking) error {
+	var promo entity.PromoCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promo, "id = ?", *booking.PromoCodeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.New(apperrors.CodeInvalidPromoCode, "promo code not found")
+		}
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to redeem promo code")
+	}
+
+	uses, err := countPromoRedemptions(tx, promo.ID, booking.UserID, booking.GuestEmail)
+	if err != nil {
+		return err
+	}
+	if reason := promo.Rejection(booking.SubtotalAmount, uses); reason != "" {
+		return apperrors.New(apperrors.CodeInvalidPromoCode, reason)
+	}
+
+	redemption := &entity.PromoCodeRedemption{
+		PromoCodeID: promo.ID,
+		UserID:      booking.UserID,
+		BookingID:   booking.ID,
+		RedeemedAt:  time.Now(),
+	}
+	if booking.UserID == nil {
+		redemption.GuestEmail = strings.ToLower(strings.TrimSpace(booking.GuestEmail))
+	}
+	if err := tx.Create(redemption).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record promo code redemption")
+	}
+
+	if err := tx.Model(&entity.PromoCode{}).
+		Where("id = ?", promo.ID).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update promo code usage")
+	}
+	return nil
+}
+
+// releasePromoCode gives back the use of an expired or cancelled booking's
+// promo code, so abandoned checkouts do not use up a limited code and the
+// customer can use it again
+func releasePromoCode(tx *gorm.DB, booking *entity.Booking) error {
+	if err := tx.Where("booking_id = ?", booking.ID).Delete(&entity.PromoCodeRedemption{}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to release promo code redemption")
+	}
+	if err := tx.Model(&entity.PromoCode{}).
+		Where("id = ? AND usage_count > 0", *booking.PromoCodeID).
+		UpdateColumn("usage_count", gorm.Expr("usage_count - 1")).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to release promo code usage")
+	}
+	return nil
+}
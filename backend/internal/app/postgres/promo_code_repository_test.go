@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateWithSeatsRedeemsThePromoCode(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	bookings := NewBookingRepository(f.db)
+	promos := NewPromoCodeRepository(f.db)
+
+	limit := 2
+	promo := &entity.PromoCode{
+		Code: "TEST-" + uuid.NewString()[:8], DiscountType: "FIXED", DiscountValue: 5,
+		UsageLimit: &limit, IsActive: true,
+		ValidFrom: time.Now().Add(-time.Hour), ValidUntil: time.Now().Add(time.Hour),
+	}
+	if err := promos.Create(ctx, promo); err != nil {
+		t.Fatalf("create promo code: %v", err)
+	}
+
+	book := func() error {
+		return bookings.CreateWithSeats(ctx, &entity.Booking{
+			BookingReference: "BK-PROMO-" + uuid.NewString()[:8],
+			ShowtimeID:       f.showtime.ID,
+			NumTickets:       1,
+			SubtotalAmount:   10,
+			DiscountAmount:   5,
+			FinalAmount:      5,
+			BookingStatus:    entity.BookingPending,
+			PaymentStatus:    entity.PaymentPending,
+			SalesChannel:     entity.ChannelOnline,
+			BookedAt:         time.Now(),
+			PromoCode:        &promo.Code,
+			PromoCodeID:      &promo.ID,
+		}, nil)
+	}
+	usage := func() int {
+		t.Helper()
+		stored, err := promos.GetByID(ctx, promo.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		return stored.UsageCount
+	}
+
+	for want := 1; want <= limit; want++ {
+		if err := book(); err != nil {
+			t.Fatalf("booking %d: %v", want, err)
+		}
+		if got := usage(); got != want {
+			t.Errorf("usage count = %d, want %d", got, want)
+		}
+	}
+
+	// The code ran out: the booking fails and nothing is written
+	if err := book(); !apperrors.Is(err, apperrors.CodeInvalidPromoCode) {
+		t.Fatalf("booking past the limit: %v, want %s", err, apperrors.CodeInvalidPromoCode)
+	}
+	if got := usage(); got != limit {
+		t.Errorf("usage count = %d after the failed booking, want %d", got, limit)
+	}
+	var count int64
+	if err := f.db.DB.Model(&entity.Booking{}).Where("promo_code_id = ?", promo.ID).Count(&count).Error; err != nil {
+		t.Fatalf("count bookings: %v", err)
+	}
+	if count != int64(limit) {
+		t.Errorf("%d bookings with the code, want %d", count, limit)
+	}
+}
+
+func TestPromoCodeExistsAndStats(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	bookings := NewBookingRepository(f.db)
+	promos := NewPromoCodeRepository(f.db)
+
+	promo := &entity.PromoCode{
+		Code: "STATS" + strings.ToUpper(uuid.NewString()[:8]), DiscountType: "FIXED", DiscountValue: 5, IsActive: true,
+		ValidFrom: time.Now().Add(-time.Hour), ValidUntil: time.Now().Add(time.Hour),
+	}
+	if err := promos.Create(ctx, promo); err != nil {
+		t.Fatalf("create promo code: %v", err)
+	}
+	for _, status := range []entity.BookingStatus{entity.BookingConfirmed, entity.BookingCompleted, entity.BookingCancelled} {
+		if err := bookings.CreateWithSeats(ctx, &entity.Booking{
+			BookingReference: "BK-STATS-" + uuid.NewString()[:8],
+			ShowtimeID:       f.showtime.ID,
+			NumTickets:       1,
+			SubtotalAmount:   10,
+			DiscountAmount:   5,
+			FinalAmount:      5,
+			BookingStatus:    status,
+			PaymentStatus:    entity.PaymentPaid,
+			SalesChannel:     entity.ChannelOnline,
+			BookedAt:         time.Now(),
+			PromoCode:        &promo.Code,
+			PromoCodeID:      &promo.ID,
+		}, nil); err != nil {
+			t.Fatalf("create %s booking: %v", status, err)
+		}
+	}
+
+	stats, err := promos.GetStats(ctx, promo.ID)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.Bookings != 2 || stats.TotalDiscount != 10 {
+		t.Errorf("stats = %+v, want the 2 paid bookings and 10 off", stats)
+	}
+
+	// Deleted codes still count as taken, in any case
+	if err := promos.Delete(ctx, promo.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	exists, err := promos.CodeExists(ctx, strings.ToLower(promo.Code))
+	if err != nil {
+		t.Fatalf("CodeExists: %v", err)
+	}
+	if !exists {
+		t.Errorf("code of a deleted promo is free")
+	}
+}
+
+func TestPromoCodeRedemptions(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	bookings := NewBookingRepository(f.db)
+	promos := NewPromoCodeRepository(f.db)
+
+	once := 1
+	promo := &entity.PromoCode{
+		Code: "ONCE-" + uuid.NewString()[:8], DiscountType: "FIXED", DiscountValue: 5,
+		UsageLimitPerUser: &once, IsActive: true,
+		ValidFrom: time.Now().Add(-time.Hour), ValidUntil: time.Now().Add(time.Hour),
+	}
+	if err := promos.Create(ctx, promo); err != nil {
+		t.Fatalf("create promo code: %v", err)
+	}
+	email := "fan-" + uuid.NewString()[:8] + "@example.com"
+	user := &entity.User{Email: email, PasswordHash: "x", FirstName: "Film", LastName: "Fan", Role: entity.RoleCustomer, IsActive: true}
+	if err := f.db.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// Guests book with the email in any case
+	book := func() (*entity.Booking, error) {
+		booking := &entity.Booking{
+			BookingReference: "BK-REDEEM-" + uuid.NewString()[:8], ShowtimeID: f.showtime.ID, NumTickets: 1,
+			SubtotalAmount: 10, DiscountAmount: 5, FinalAmount: 5,
+			BookingStatus: entity.BookingPending, PaymentStatus: entity.PaymentPending, SalesChannel: entity.ChannelOnline,
+			BookedAt: time.Now(), GuestEmail: strings.ToUpper(email), GuestName: "Film Fan",
+			PromoCode: &promo.Code, PromoCodeID: &promo.ID,
+		}
+		return booking, bookings.CreateWithSeats(ctx, booking, nil)
+	}
+	uses := func(userID *uuid.UUID, email string) int {
+		t.Helper()
+		n, err := promos.GetUserUsageCount(ctx, promo.ID, userID, email)
+		if err != nil {
+			t.Fatalf("GetUserUsageCount: %v", err)
+		}
+		return n
+	}
+
+	first, err := book()
+	if err != nil {
+		t.Fatalf("first booking: %v", err)
+	}
+	// The account counts the uses made as a guest with its email
+	if guest, account := uses(nil, email), uses(&user.ID, ""); guest != 1 || account != 1 {
+		t.Errorf("uses = %d as a guest, %d for the account, want 1 and 1", guest, account)
+	}
+	if _, err := book(); !apperrors.Is(err, apperrors.CodeInvalidPromoCode) {
+		t.Fatalf("second guest booking: %v, want %s", err, apperrors.CodeInvalidPromoCode)
+	}
+
+	// An expired booking gives its use back
+	if _, err := bookings.Expire(ctx, first.ID); err != nil {
+		t.Fatalf("Expire: %v", err)
+	}
+	if n := uses(nil, email); n != 0 {
+		t.Errorf("uses = %d after the expiry, want 0", n)
+	}
+
+	// Claimed guest bookings bring their redemptions to the account
+	second, err := book()
+	if err != nil {
+		t.Fatalf("booking after the expiry: %v", err)
+	}
+	if _, err := bookings.ClaimGuestBookings(ctx, user.ID, email); err != nil {
+		t.Fatalf("ClaimGuestBookings: %v", err)
+	}
+	var redemption entity.PromoCodeRedemption
+	if err := f.db.DB.Where("booking_id = ?", second.ID).First(&redemption).Error; err != nil {
+		t.Fatalf("get redemption: %v", err)
+	}
+	if redemption.UserID == nil || *redemption.UserID != user.ID || redemption.GuestEmail != email {
+		t.Errorf("redemption of %v / %q, want the account with the guest email kept", redemption.UserID, redemption.GuestEmail)
+	}
+	if n := uses(&user.ID, ""); n != 1 {
+		t.Errorf("account uses = %d after the claim, want 1", n)
+	}
+}
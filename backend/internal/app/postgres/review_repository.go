@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// reviewRepository implements repository.ReviewRepository
+type reviewRepository struct {
+	db *Database
+}
+
+// NewReviewRepository creates a new review repository
+func NewReviewRepository(db *Database) repository.ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(review).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create review")
+	}
+	return nil
+}
+
+func (r *reviewRepository) GetByID(ctx context.Context, movieID, id uuid.UUID) (*entity.Review, error) {
+	var review entity.Review
+	if err := r.db.WithContext(ctx).Preload("User").First(&review, "id = ? AND movie_id = ?", id, movieID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("review")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get review")
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetByUserAndMovie(ctx context.Context, userID, movieID uuid.UUID) (*entity.Review, error) {
+	var review entity.Review
+	if err := r.db.WithContext(ctx).First(&review, "user_id = ? AND movie_id = ?", userID, movieID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get review")
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetByMovieID(ctx context.Context, movieID uuid.UUID, offset, limit int) ([]*entity.Review, int64, error) {
+	db := r.db.WithContext(ctx).Model(&entity.Review{}).Where("movie_id = ?", movieID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count reviews")
+	}
+
+	var reviews []*entity.Review
+	if err := db.Preload("User").
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list reviews")
+	}
+	return reviews, total, nil
+}
+
+func (r *reviewRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Review, int64, error) {
+	db := r.db.WithContext(ctx).Model(&entity.Review{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count reviews")
+	}
+
+	var reviews []*entity.Review
+	if err := db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&reviews).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list reviews")
+	}
+	return reviews, total, nil
+}
+
+func (r *reviewRepository) GetAverageRating(ctx context.Context, movieID uuid.UUID) (float64, int64, error) {
+	var row struct {
+		Average float64
+		Count   int64
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("movie_id = ?", movieID).
+		Scan(&row).Error; err != nil {
+		return 0, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get average rating")
+	}
+	return row.Average, row.Count, nil
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, movieID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entity.Review{}, "id = ? AND movie_id = ?", id, movieID)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to delete review")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound("review")
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seatHoldRepository implements repository.SeatHoldRepository in the
+// database, for a server running without Redis. Holds on a showtime are
+// made and extended with the showtime row locked, which stands in for the
+// Redis seat locks. Nothing is cached: probes report the booked seats as a
+// cache miss so they are read from the bookings.
+type seatHoldRepository struct {
+	db *Database
+}
+
+// NewSeatHoldRepository creates a new database-backed seat hold repository
+func NewSeatHoldRepository(db *Database) repository.SeatHoldRepository {
+	return &seatHoldRepository{db: db}
+}
+
+// seatHoldRow is a row of seat_holds
+type seatHoldRow struct {
+	ID              string `gorm:"primaryKey"`
+	ShowtimeID      uuid.UUID
+	Payload         string `gorm:"type:jsonb"`
+	ExpiresAt       time.Time
+	ExpiryAnnounced bool
+}
+
+func (seatHoldRow) TableName() string { return "seat_holds" }
+
+// seatHoldSeatRow is a row of seat_hold_seats, one per held seat
+type seatHoldSeatRow struct {
+	ShowtimeID uuid.UUID `gorm:"primaryKey"`
+	SeatID     uuid.UUID `gorm:"primaryKey"`
+	HoldID     string
+	ExpiresAt  time.Time
+}
+
+func (seatHoldSeatRow) TableName() string { return "seat_hold_seats" }
+
+func (r *seatHoldRepository) Create(ctx context.Context, hold *entity.SeatHold) error {
+	if hold.TTL() <= 0 {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+	payload, err := json.Marshal(hold)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode hold")
+	}
+
+	seatIDs := hold.SeatIDs()
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockShowtime(tx, hold.ShowtimeID); err != nil {
+			return err
+		}
+
+		var taken []uuid.UUID
+		if err := tx.Model(&seatHoldSeatRow{}).
+			Where("showtime_id = ? AND seat_id IN ? AND hold_id <> ? AND expires_at > ?", hold.ShowtimeID, seatIDs, hold.ID, time.Now()).
+			Pluck("seat_id", &taken).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock seats")
+		}
+		if len(taken) > 0 {
+			held := make([]string, 0, len(taken))
+			for _, seatID := range seatIDs {
+				if entity.UUIDList(taken).Contains(seatID) {
+					held = append(held, seatID.String())
+				}
+			}
+			return apperrors.New(apperrors.CodeSeatNotAvailable, "one or more seats are already held").
+				WithDetails(map[string]any{"seat_ids": held})
+		}
+
+		// The seats' rows left by expired holds give way to the new hold
+		if err := tx.Where("showtime_id = ? AND seat_id IN ?", hold.ShowtimeID, seatIDs).
+			Delete(&seatHoldSeatRow{}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock seats")
+		}
+		row := seatHoldRow{ID: hold.ID, ShowtimeID: hold.ShowtimeID, Payload: string(payload), ExpiresAt: hold.ExpiresAt}
+		if err := tx.Create(&row).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to store hold")
+		}
+		seats := make([]seatHoldSeatRow, len(seatIDs))
+		for i, seatID := range seatIDs {
+			seats[i] = seatHoldSeatRow{ShowtimeID: hold.ShowtimeID, SeatID: seatID, HoldID: hold.ID, ExpiresAt: hold.ExpiresAt}
+		}
+		if err := tx.Create(&seats).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock seats")
+		}
+		return nil
+	})
+}
+
+func (r *seatHoldRepository) GetByID(ctx context.Context, id string) (*entity.SeatHold, error) {
+	var payloads []string
+	if err := conn(ctx, r.db).Model(&seatHoldRow{}).
+		Where("id = ? AND expires_at > ?", id, time.Now()).
+		Pluck("payload", &payloads).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get hold")
+	}
+	if len(payloads) == 0 {
+		return nil, apperrors.New(apperrors.CodeNotFound, "hold not found or expired")
+	}
+	return decodeHold(payloads[0])
+}
+
+func (r *seatHoldRepository) Update(ctx context.Context, hold *entity.SeatHold) error {
+	if hold.TTL() <= 0 {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+	return r.save(conn(ctx, r.db), hold)
+}
+
+func (r *seatHoldRepository) Extend(ctx context.Context, hold *entity.SeatHold, expiresAt time.Time) error {
+	if !expiresAt.After(time.Now()) || hold.IsExpired() {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockShowtime(tx, hold.ShowtimeID); err != nil {
+			return err
+		}
+
+		var locked int64
+		if err := tx.Model(&seatHoldSeatRow{}).
+			Where("hold_id = ? AND showtime_id = ? AND seat_id IN ? AND expires_at > ?", hold.ID, hold.ShowtimeID, hold.SeatIDs(), time.Now()).
+			Count(&locked).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to extend seat locks")
+		}
+		if int(locked) != len(hold.Seats) {
+			return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+		}
+		if err := tx.Model(&seatHoldSeatRow{}).Where("hold_id = ?", hold.ID).
+			Update("expires_at", expiresAt).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to extend seat locks")
+		}
+
+		hold.ExpiresAt = expiresAt
+		return r.save(tx, hold)
+	})
+}
+
+func (r *seatHoldRepository) ReleaseSeats(ctx context.Context, hold *entity.SeatHold, seatIDs []uuid.UUID) error {
+	if len(seatIDs) > 0 {
+		if err := conn(ctx, r.db).Where("hold_id = ? AND seat_id IN ?", hold.ID, seatIDs).
+			Delete(&seatHoldSeatRow{}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to release seat locks")
+		}
+	}
+
+	release := entity.UUIDList(seatIDs)
+	remaining := hold.Seats[:0]
+	for _, seat := range hold.Seats {
+		if release.Contains(seat.SeatID) {
+			continue
+		}
+		remaining = append(remaining, seat)
+	}
+	hold.Seats = remaining
+	hold.Reprice()
+
+	if len(hold.Seats) == 0 {
+		return r.remove(ctx, hold.ID)
+	}
+	return r.Update(ctx, hold)
+}
+
+func (r *seatHoldRepository) Delete(ctx context.Context, hold *entity.SeatHold) error {
+	return r.remove(ctx, hold.ID)
+}
+
+func (r *seatHoldRepository) Release(ctx context.Context, hold *entity.SeatHold) (int, error) {
+	// Only the seats still locked by the hold count as released
+	result := conn(ctx, r.db).Where("hold_id = ? AND expires_at > ?", hold.ID, time.Now()).Delete(&seatHoldSeatRow{})
+	if result.Error != nil {
+		return 0, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to release seat locks")
+	}
+	released := int(result.RowsAffected)
+	if err := r.remove(ctx, hold.ID); err != nil {
+		return released, err
+	}
+	return released, nil
+}
+
+// ClaimExpired deletes holds that expired before the given time and
+// returns them. Rows another instance is claiming are skipped, so each hold
+// is returned to exactly one caller.
+func (r *seatHoldRepository) ClaimExpired(ctx context.Context, before time.Time, limit int) ([]*entity.SeatHold, error) {
+	var payloads []string
+	err := conn(ctx, r.db).Raw(`
+		DELETE FROM seat_holds WHERE id IN (
+			SELECT id FROM seat_holds WHERE expires_at <= ?
+			ORDER BY expires_at LIMIT ? FOR UPDATE SKIP LOCKED
+		) RETURNING payload`, before, limit).
+		Scan(&payloads).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim expired holds")
+	}
+	return r.decodeHolds(ctx, payloads), nil
+}
+
+func (r *seatHoldRepository) GetHeldSeatIDs(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(seatIDs) == 0 {
+		return nil, nil
+	}
+	held := make([]uuid.UUID, 0)
+	if err := conn(ctx, r.db).Model(&seatHoldSeatRow{}).
+		Where("showtime_id = ? AND seat_id IN ? AND expires_at > ?", showtimeID, seatIDs, time.Now()).
+		Pluck("seat_id", &held).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get seat locks")
+	}
+	return held, nil
+}
+
+// GetHeldCounts counts the held seats from the seat locks, so there are no
+// counters to keep current
+func (r *seatHoldRepository) GetHeldCounts(ctx context.Context, showtimeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(showtimeIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ShowtimeID uuid.UUID
+		Held       int
+	}
+	if err := conn(ctx, r.db).Model(&seatHoldSeatRow{}).
+		Select("showtime_id, COUNT(*) AS held").
+		Where("showtime_id IN ? AND expires_at > ?", showtimeIDs, time.Now()).
+		Group("showtime_id").
+		Scan(&rows).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get held seat counts")
+	}
+	for _, row := range rows {
+		counts[row.ShowtimeID] = row.Held
+	}
+	return counts, nil
+}
+
+func (r *seatHoldRepository) CountActive(ctx context.Context) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&seatHoldRow{}).Where("expires_at > ?", time.Now()).Count(&count).Error; err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count active holds")
+	}
+	return count, nil
+}
+
+// ExpireHeldCounts marks holds that expired before the given time as
+// announced and returns them. The held counts need no update, as they are
+// counted from the seat locks.
+func (r *seatHoldRepository) ExpireHeldCounts(ctx context.Context, before time.Time, limit int) (int, []*entity.SeatHold, error) {
+	var payloads []string
+	err := conn(ctx, r.db).Raw(`
+		UPDATE seat_holds SET expiry_announced = TRUE WHERE id IN (
+			SELECT id FROM seat_holds WHERE expires_at <= ? AND NOT expiry_announced
+			ORDER BY expires_at LIMIT ? FOR UPDATE SKIP LOCKED
+		) RETURNING payload`, before, limit).
+		Scan(&payloads).Error
+	if err != nil {
+		return 0, nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim expired holds")
+	}
+	return len(payloads), r.decodeHolds(ctx, payloads), nil
+}
+
+func (r *seatHoldRepository) ProbeSeats(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) (*entity.SeatProbe, error) {
+	held, err := r.GetHeldSeatIDs(ctx, showtimeID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+	if held == nil {
+		held = make([]uuid.UUID, 0)
+	}
+	return &entity.SeatProbe{Held: held}, nil
+}
+
+// The booked seat and seat type sale caches are Redis-only; without Redis
+// they always miss and are never written
+
+func (r *seatHoldRepository) CacheBookedSeats(context.Context, uuid.UUID, int64, []uuid.UUID, time.Duration) error {
+	return nil
+}
+
+func (r *seatHoldRepository) InvalidateBookedSeats(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (r *seatHoldRepository) GetOnlineSold(context.Context, uuid.UUID) (map[entity.SeatType]int, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *seatHoldRepository) CacheOnlineSold(context.Context, uuid.UUID, int64, map[entity.SeatType]int, time.Duration) error {
+	return nil
+}
+
+// save overwrites the stored hold payload and expiry
+func (r *seatHoldRepository) save(tx *gorm.DB, hold *entity.SeatHold) error {
+	payload, err := json.Marshal(hold)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode hold")
+	}
+	result := tx.Model(&seatHoldRow{}).Where("id = ?", hold.ID).
+		Updates(map[string]any{"payload": string(payload), "expires_at": hold.ExpiresAt})
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to store hold")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.New(apperrors.CodeNotFound, "hold not found or expired")
+	}
+	return nil
+}
+
+// remove deletes a hold together with its seat locks
+func (r *seatHoldRepository) remove(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Where("id = ?", id).Delete(&seatHoldRow{}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete hold")
+	}
+	return nil
+}
+
+// decodeHolds decodes claimed holds, logging and skipping corrupt ones
+func (r *seatHoldRepository) decodeHolds(ctx context.Context, payloads []string) []*entity.SeatHold {
+	holds := make([]*entity.SeatHold, 0, len(payloads))
+	for _, payload := range payloads {
+		hold, err := decodeHold(payload)
+		if err != nil {
+			r.db.logger.WithContext(ctx).Warn("failed to decode seat hold", zap.Error(err))
+			continue
+		}
+		holds = append(holds, hold)
+	}
+	return holds
+}
+
+func decodeHold(payload string) (*entity.SeatHold, error) {
+	var hold entity.SeatHold
+	if err := json.Unmarshal([]byte(payload), &hold); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to decode hold")
+	}
+	return &hold, nil
+}
+
+// lockShowtime locks the showtime row until the transaction ends, so seat
+// holds on the showtime are made one at a time
+func lockShowtime(tx *gorm.DB, showtimeID uuid.UUID) error {
+	var ids []uuid.UUID
+	if err := tx.Model(&entity.Showtime{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", showtimeID).
+		Pluck("id", &ids).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock showtime")
+	}
+	if len(ids) == 0 {
+		return apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func newTestHold(showtimeID uuid.UUID, ttl time.Duration, seatIDs ...uuid.UUID) *entity.SeatHold {
+	hold := &entity.SeatHold{
+		ID:         uuid.NewString(),
+		ShowtimeID: showtimeID,
+		UserID:     uuid.New(),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+	for _, seatID := range seatIDs {
+		hold.Seats = append(hold.Seats, entity.HeldSeat{SeatID: seatID, Price: 10})
+	}
+	return hold
+}
+
+func TestSeatHolds(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewSeatHoldRepository(f.db)
+	showtimeID := f.showtime.ID
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	hold := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err := repo.Create(ctx, newTestHold(showtimeID, time.Minute, b, c))
+	if !apperrors.Is(err, apperrors.CodeSeatNotAvailable) {
+		t.Fatalf("second hold on seat b = %v, want %s", err, apperrors.CodeSeatNotAvailable)
+	}
+	if held, _ := repo.GetHeldSeatIDs(ctx, showtimeID, []uuid.UUID{a, b, c}); len(held) != 2 {
+		t.Errorf("held %v after a refused hold, want a and b", held)
+	}
+	if err := repo.Create(ctx, newTestHold(uuid.New(), time.Minute, c)); !apperrors.Is(err, apperrors.CodeShowtimeNotFound) {
+		t.Errorf("hold on a missing showtime = %v, want %s", err, apperrors.CodeShowtimeNotFound)
+	}
+
+	stored, err := repo.GetByID(ctx, hold.ID)
+	if err != nil || len(stored.Seats) != 2 || stored.UserID != hold.UserID {
+		t.Fatalf("GetByID = %+v, %v", stored, err)
+	}
+	counts, err := repo.GetHeldCounts(ctx, []uuid.UUID{showtimeID})
+	if err != nil || counts[showtimeID] != 2 {
+		t.Errorf("GetHeldCounts = %v, %v, want 2 held", counts, err)
+	}
+	probe, err := repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{a, c})
+	if err != nil || len(probe.Held) != 1 || probe.Held[0] != a || probe.Booked != nil {
+		t.Errorf("ProbeSeats = %+v, %v, want a held and the booked seats missed", probe, err)
+	}
+
+	later := time.Now().Add(5 * time.Minute)
+	if err := repo.Extend(ctx, hold, later); err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, hold.ID); !stored.ExpiresAt.Equal(hold.ExpiresAt) {
+		t.Errorf("expiry stored as %v, want %v", stored.ExpiresAt, hold.ExpiresAt)
+	}
+
+	if err := repo.ReleaseSeats(ctx, hold, []uuid.UUID{b}); err != nil {
+		t.Fatalf("ReleaseSeats: %v", err)
+	}
+	if held, _ := repo.GetHeldSeatIDs(ctx, showtimeID, []uuid.UUID{a, b}); len(held) != 1 || held[0] != a {
+		t.Errorf("held %v after releasing b, want a", held)
+	}
+	if err := repo.Create(ctx, newTestHold(showtimeID, time.Minute, b)); err != nil {
+		t.Errorf("hold on the released seat: %v", err)
+	}
+
+	released, err := repo.Release(ctx, hold)
+	if err != nil || released != 1 {
+		t.Errorf("Release = %d, %v, want 1 seat released", released, err)
+	}
+	if _, err := repo.GetByID(ctx, hold.ID); !apperrors.Is(err, apperrors.CodeNotFound) {
+		t.Errorf("released hold: %v, want %s", err, apperrors.CodeNotFound)
+	}
+}
+
+func TestSeatHoldsExpire(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewSeatHoldRepository(f.db)
+	showtimeID := f.showtime.ID
+	seat := uuid.New()
+
+	expiring := newTestHold(showtimeID, 50*time.Millisecond, seat)
+	if err := repo.Create(ctx, expiring); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if err := repo.Extend(ctx, expiring, time.Now().Add(time.Minute)); !apperrors.Is(err, apperrors.CodeBookingExpired) {
+		t.Errorf("Extend of an expired hold = %v, want %s", err, apperrors.CodeBookingExpired)
+	}
+	if n, err := repo.CountActive(ctx); err != nil || n != 0 {
+		t.Errorf("CountActive = %d, %v, want 0", n, err)
+	}
+
+	// The seat of an expired hold can be held again before the sweeps ran
+	if err := repo.Create(ctx, newTestHold(showtimeID, time.Minute, seat)); err != nil {
+		t.Fatalf("hold on the seat of an expired hold: %v", err)
+	}
+
+	n, announced, err := repo.ExpireHeldCounts(ctx, time.Now(), 10)
+	if err != nil || n != 1 || len(announced) != 1 || announced[0].ID != expiring.ID {
+		t.Fatalf("ExpireHeldCounts = %d, %v, %v, want the expired hold", n, announced, err)
+	}
+	if n, _, _ := repo.ExpireHeldCounts(ctx, time.Now(), 10); n != 0 {
+		t.Errorf("second ExpireHeldCounts took %d holds, want none", n)
+	}
+
+	claimed, err := repo.ClaimExpired(ctx, time.Now(), 10)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != expiring.ID || len(claimed[0].Seats) != 1 {
+		t.Fatalf("ClaimExpired = %v, %v, want the expired hold", claimed, err)
+	}
+	if claimed, _ := repo.ClaimExpired(ctx, time.Now(), 10); len(claimed) != 0 {
+		t.Errorf("second ClaimExpired = %v, want nothing", claimed)
+	}
+	if held, _ := repo.GetHeldSeatIDs(ctx, showtimeID, []uuid.UUID{seat}); len(held) != 1 {
+		t.Errorf("sweeping the expired hold released the seat held since")
+	}
+}
+
+func TestSeatHoldsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := newCommittedTestDatabase(t)
+	repo := NewSeatHoldRepository(db)
+
+	suffix := uuid.NewString()[:8]
+	cinema := &entity.Cinema{Name: "Hold Test", Slug: "hold-test-" + suffix, Address: "1 Test St", City: "Test", Country: "VN", IsActive: true, Timezone: "UTC"}
+	movie := &entity.Movie{Title: "Hold Test", Slug: "hold-test-" + suffix, Duration: 120, ReleaseDate: time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+	if err := db.DB.Create(cinema).Error; err != nil {
+		t.Fatalf("create cinema: %v", err)
+	}
+	if err := db.DB.Create(movie).Error; err != nil {
+		t.Fatalf("create movie: %v", err)
+	}
+	screen := &entity.Screen{CinemaID: cinema.ID, Name: "1", ScreenNumber: 1, Capacity: 20, ScreenType: entity.ScreenStandard, Rows: 4, SeatsPerRow: 5, IsActive: true}
+	if err := db.DB.Create(screen).Error; err != nil {
+		t.Fatalf("create screen: %v", err)
+	}
+	showtime := &entity.Showtime{
+		CinemaID: cinema.ID, ScreenID: screen.ID, MovieID: movie.ID,
+		ShowDate: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), StartTime: "19:30", EndTime: "21:30",
+		Status: entity.ShowtimeScheduled, TotalSeats: 20, AvailableSeats: 20,
+	}
+	if err := db.DB.Create(showtime).Error; err != nil {
+		t.Fatalf("create showtime: %v", err)
+	}
+	t.Cleanup(func() {
+		db.DB.Exec("DELETE FROM seat_holds WHERE showtime_id = ?", showtime.ID)
+		db.DB.Unscoped().Delete(showtime)
+		db.DB.Unscoped().Delete(screen)
+		db.DB.Unscoped().Delete(movie)
+		db.DB.Unscoped().Delete(cinema)
+	})
+
+	// Every hold wants the shared seat, so only one of them can be made
+	const holders = 8
+	shared := uuid.New()
+	errs := make(chan error, holders)
+	for range holders {
+		go func() {
+			errs <- repo.Create(ctx, newTestHold(showtime.ID, time.Minute, uuid.New(), shared))
+		}()
+	}
+	made := 0
+	for range holders {
+		err := <-errs
+		switch {
+		case err == nil:
+			made++
+		case !apperrors.Is(err, apperrors.CodeSeatNotAvailable):
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if made != 1 {
+		t.Errorf("%d holds made on the shared seat, want 1", made)
+	}
+	if counts, _ := repo.GetHeldCounts(ctx, []uuid.UUID{showtime.ID}); counts[showtime.ID] != 2 {
+		t.Errorf("%d seats held, want the 2 of the one hold made", counts[showtime.ID])
+	}
+}
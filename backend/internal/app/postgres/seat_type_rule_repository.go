@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seatTypeRuleRepository implements repository.SeatTypeRuleRepository
+type seatTypeRuleRepository struct {
+	db *Database
+}
+
+// NewSeatTypeRuleRepository creates a new seat type rule repository
+func NewSeatTypeRuleRepository(db *Database) repository.SeatTypeRuleRepository {
+	return &seatTypeRuleRepository{db: db}
+}
+
+func (r *seatTypeRuleRepository) ListByShowtime(ctx context.Context, showtimeID uuid.UUID) ([]*entity.SeatTypeRule, error) {
+	var rules []*entity.SeatTypeRule
+	if err := r.db.WithContext(ctx).
+		Where("showtime_id = ?", showtimeID).
+		Order("seat_type").
+		Find(&rules).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list seat type rules")
+	}
+	return rules, nil
+}
+
+func (r *seatTypeRuleRepository) Replace(ctx context.Context, showtimeID uuid.UUID, rules []*entity.SeatTypeRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("showtime_id = ?", showtimeID).Delete(&entity.SeatTypeRule{}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update seat type rules")
+		}
+		for _, rule := range rules {
+			rule.ShowtimeID = showtimeID
+		}
+		if len(rules) > 0 {
+			if err := tx.Create(&rules).Error; err != nil {
+				return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update seat type rules")
+			}
+		}
+		return nil
+	})
+}
+
+func (r *seatTypeRuleRepository) CountOnlineSold(ctx context.Context, showtimeID uuid.UUID) (map[entity.SeatType]int, error) {
+	return onlineSoldByType(r.db.WithContext(ctx), showtimeID)
+}
+
+// onlineSoldByType counts the seats of each type held by the showtime's
+// active online bookings
+func onlineSoldByType(db *gorm.DB, showtimeID uuid.UUID) (map[entity.SeatType]int, error) {
+	var rows []struct {
+		SeatType entity.SeatType
+		Sold     int
+	}
+	err := db.Table("booking_seats").
+		Select("seats.seat_type, COUNT(*) AS sold").
+		Joins("JOIN bookings ON bookings.id = booking_seats.booking_id").
+		Joins("JOIN seats ON seats.id = booking_seats.seat_id").
+		Where("booking_seats.showtime_id = ?", showtimeID).
+		Where("bookings.sales_channel = ? AND bookings.deleted_at IS NULL", entity.ChannelOnline).
+		Where("bookings.booking_status IN ?",
+			[]entity.BookingStatus{entity.BookingPending, entity.BookingConfirmed, entity.BookingCompleted}).
+		Group("seats.seat_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count sold seats by type")
+	}
+
+	sold := make(map[entity.SeatType]int, len(rows))
+	for _, row := range rows {
+		sold[row.SeatType] = row.Sold
+	}
+	return sold, nil
+}
+
+// checkOnlineCaps enforces the showtime's online sales caps on the seat
+// types of an online booking whose seats were just written in tx. The
+// capped rules are locked first, so concurrent bookings of the same type
+// are counted one after the other.
+func checkOnlineCaps(tx *gorm.DB, booking *entity.Booking, seatIDs []uuid.UUID) error {
+	if booking.SalesChannel != "" && booking.SalesChannel != entity.ChannelOnline {
+		return nil
+	}
+	if len(seatIDs) == 0 {
+		return nil
+	}
+
+	var rules []*entity.SeatTypeRule
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("showtime_id = ? AND online_sales_cap IS NOT NULL", booking.ShowtimeID).
+		Where("seat_type IN (?)", tx.Session(&gorm.Session{NewDB: true}).
+			Model(&entity.Seat{}).Distinct("seat_type").Where("id IN ?", seatIDs)).
+		Order("seat_type").
+		Find(&rules).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock seat type rules")
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	sold, err := onlineSoldByType(tx, booking.ShowtimeID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if sold[rule.SeatType] > *rule.OnlineSalesCap {
+			return apperrors.New(apperrors.CodeSeatTypeCapReached,
+				fmt.Sprintf("online sales of %s seats are capped at %d", rule.SeatType, *rule.OnlineSalesCap)).
+				WithDetails(map[string]any{"seat_type": rule.SeatType, "online_sales_cap": *rule.OnlineSalesCap})
+		}
+	}
+	return nil
+}
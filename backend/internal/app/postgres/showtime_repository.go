@@ -7,9 +7,11 @@ import (
 
 	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ShowtimeRepository implements repository.ShowtimeRepository
@@ -36,13 +38,14 @@ func (r *ShowtimeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity
 	return &showtime, nil
 }
 
-// GetByIDWithDetails retrieves a showtime with movie, screen, and cinema
+// GetByIDWithDetails retrieves a showtime with movie, screen, and cinema.
+// Relations are loaded even if they were deleted since.
 func (r *ShowtimeRepository) GetByIDWithDetails(ctx context.Context, id uuid.UUID) (*entity.Showtime, error) {
 	var showtime entity.Showtime
 	if err := r.db.WithContext(ctx).
-		Preload("Movie").
-		Preload("Cinema").
-		Preload("Screen").
+		Preload("Movie", unscoped).
+		Preload("Cinema", unscoped).
+		Preload("Screen", unscoped).
 		First(&showtime, id).Error; err != nil {
 		return nil, err
 	}
@@ -54,10 +57,29 @@ func (r *ShowtimeRepository) Update(ctx context.Context, showtime *entity.Showti
 	return r.db.WithContext(ctx).Save(showtime).Error
 }
 
-// UpdateWithVersion updates a showtime with optimistic locking
+// UpdateWithVersion updates a showtime with optimistic locking: the row
+// is only written while its version is the one the showtime was read at
 func (r *ShowtimeRepository) UpdateWithVersion(ctx context.Context, showtime *entity.Showtime) error {
-	// Simple implementation for now, mirroring Update
-	return r.Update(ctx, showtime)
+	readVersion := showtime.Version
+	showtime.Version++
+
+	result := r.db.WithContext(ctx).Model(showtime).
+		Where("version = ?", readVersion).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(showtime)
+	if result.Error != nil {
+		showtime.Version = readVersion
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		showtime.Version = readVersion
+		if _, err := r.GetByID(ctx, showtime.ID); err != nil {
+			return err
+		}
+		return apperrors.New(apperrors.CodeVersionConflict, "showtime was changed by another request, please try again")
+	}
+	return nil
 }
 
 // Delete soft deletes a showtime
@@ -96,6 +118,14 @@ func (r *ShowtimeRepository) List(ctx context.Context, filter repository.Showtim
 		query = query.Where("status = ?", *filter.Status)
 	}
 
+	if filter.ListedOnly {
+		query = query.Where("visibility = ?", entity.VisibilityPublic)
+	}
+
+	if filter.AvailableOnly {
+		query = query.Scopes(availableRelations)
+	}
+
 	// Order by show date and start time
 	if err := query.Order("show_date ASC, start_time ASC").Find(&showtimes).Error; err != nil {
 		return nil, err
@@ -118,26 +148,47 @@ func (r *ShowtimeRepository) GetByDateRange(ctx context.Context, cinemaID uuid.U
 
 // DecrementAvailableSeats decrements available seats using optimistic locking
 func (r *ShowtimeRepository) DecrementAvailableSeats(ctx context.Context, id uuid.UUID, count int, version int) error {
-	// Simple implementation: direct update
-	// In a real optimistic locking scenario, we'd check version
-	result := r.db.WithContext(ctx).Model(&entity.Showtime{}).
-		Where("id = ? AND available_seats >= ?", id, count).
-		UpdateColumn("available_seats", gorm.Expr("available_seats - ?", count))
-	
+	return decrementAvailableSeats(r.db.WithContext(ctx), id, count, version)
+}
+
+// decrementAvailableSeats takes count seats off a showtime read at the
+// given version. Two writers that read the same version cannot both
+// succeed, and the seat guard keeps the count from going negative.
+func decrementAvailableSeats(db *gorm.DB, id uuid.UUID, count, version int) error {
+	result := db.Model(&entity.Showtime{}).
+		Where("id = ? AND version = ? AND available_seats >= ?", id, version, count).
+		UpdateColumns(map[string]any{
+			"available_seats": gorm.Expr("available_seats - ?", count),
+			"version":         gorm.Expr("version + 1"),
+		})
 	if result.Error != nil {
-		return result.Error
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to update available seats")
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("not enough seats available or showtime not found")
+	if result.RowsAffected > 0 {
+		return nil
 	}
-	return nil
+
+	var current entity.Showtime
+	if err := db.Select("id", "version", "available_seats").First(&current, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+		}
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to get showtime")
+	}
+	if current.AvailableSeats < count {
+		return apperrors.New(apperrors.CodeSeatNotAvailable, "not enough seats available")
+	}
+	return apperrors.New(apperrors.CodeVersionConflict, "showtime seats changed concurrently")
 }
 
 // IncrementAvailableSeats increments available seats (for cancellations)
 func (r *ShowtimeRepository) IncrementAvailableSeats(ctx context.Context, id uuid.UUID, count int) error {
 	return r.db.WithContext(ctx).Model(&entity.Showtime{}).
 		Where("id = ?", id).
-		UpdateColumn("available_seats", gorm.Expr("available_seats + ?", count)).
+		UpdateColumns(map[string]any{
+			"available_seats": gorm.Expr("available_seats + ?", count),
+			"version":         gorm.Expr("version + 1"),
+		}).
 		Error
 }
 
@@ -148,17 +199,238 @@ func (r *ShowtimeRepository) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 		Update("status", status).Error
 }
 
-// GetByMovieID returns showtimes for a specific movie
-func (r *ShowtimeRepository) GetByMovieID(ctx context.Context, movieID uuid.UUID) ([]*entity.Showtime, error) {
+// ListForMovie returns the upcoming showtimes of a movie at one page of
+// the cinemas showing it, and how many cinemas there are
+func (r *ShowtimeRepository) ListForMovie(ctx context.Context, filter repository.MovieShowtimeFilter, offset, limit int) ([]*entity.Showtime, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&entity.Showtime{}).
+		Scopes(movieShowtimes(filter)).
+		Distinct("showtimes.cinema_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var cinemaIDs []uuid.UUID
+	if err := db.Model(&entity.Showtime{}).
+		Scopes(movieShowtimes(filter)).
+		Group("cinemas.id, cinemas.name").
+		Order("cinemas.name ASC, cinemas.id ASC").
+		Offset(offset).Limit(limit).
+		Pluck("cinemas.id", &cinemaIDs).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(cinemaIDs) == 0 {
+		return nil, total, nil
+	}
+
 	var showtimes []*entity.Showtime
-	if err := r.db.WithContext(ctx).
-		Preload("Movie").
+	if err := db.
 		Preload("Cinema").
 		Preload("Screen").
-		Where("movie_id = ? AND show_date >= ?", movieID, time.Now().Truncate(24*time.Hour)).
+		Scopes(movieShowtimes(filter)).
+		Where("showtimes.cinema_id IN ?", cinemaIDs).
+		Order("cinemas.name ASC, cinemas.id ASC, showtimes.show_date ASC, showtimes.start_time ASC").
+		Find(&showtimes).Error; err != nil {
+		return nil, 0, err
+	}
+	return showtimes, total, nil
+}
+
+// movieShowtimes joins the cinemas and keeps the showtimes ListForMovie
+// returns
+func movieShowtimes(filter repository.MovieShowtimeFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.
+			Joins("JOIN cinemas ON cinemas.id = showtimes.cinema_id AND cinemas.deleted_at IS NULL AND cinemas.is_active").
+			Where("showtimes.movie_id = ?", filter.MovieID).
+			Where("showtimes.status = ?", entity.ShowtimeScheduled).
+			Where("showtimes.visibility = ?", entity.VisibilityPublic).
+			Where("showtimes.show_date BETWEEN ? AND ?", filter.DateFrom.Format("2006-01-02"), filter.DateTo.Format("2006-01-02")).
+			Where("(showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC') > ?", filter.StartsAfter).
+			Scopes(availableRelations)
+		if filter.CinemaID != uuid.Nil {
+			db = db.Where("showtimes.cinema_id = ?", filter.CinemaID)
+		}
+		if filter.City != "" {
+			db = db.Where("LOWER(cinemas.city) = LOWER(?)", filter.City)
+		}
+		return db
+	}
+}
+
+// ListScheduledForMovie returns the scheduled showtimes of a movie that
+// start after the given instant, judged in each cinema's timezone
+func (r *ShowtimeRepository) ListScheduledForMovie(ctx context.Context, movieID uuid.UUID, after time.Time) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN cinemas ON cinemas.id = showtimes.cinema_id").
+		Where("showtimes.movie_id = ? AND showtimes.status = ?", movieID, entity.ShowtimeScheduled).
+		Where("showtimes.show_date >= ?", after.AddDate(0, 0, -1).Format("2006-01-02")).
+		Where("(showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC') > ?", after).
+		Order("showtimes.show_date ASC, showtimes.start_time ASC").
+		Find(&showtimes).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list scheduled showtimes")
+	}
+	return showtimes, nil
+}
+
+// ListUnavailable returns scheduled showtimes from the given date on whose
+// movie or screen has been deactivated or deleted
+func (r *ShowtimeRepository) ListUnavailable(ctx context.Context, from time.Time) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	db := r.db.WithContext(ctx)
+	if err := db.
+		Preload("Movie", unscoped).
+		Preload("Cinema", unscoped).
+		Preload("Screen", unscoped).
+		Where("status = ? AND show_date >= ?", entity.ShowtimeScheduled, from).
+		Where("movie_id NOT IN (?) OR screen_id NOT IN (?)", activeMovieIDs(db), activeScreenIDs(db)).
 		Order("show_date ASC, start_time ASC").
 		Find(&showtimes).Error; err != nil {
 		return nil, err
 	}
 	return showtimes, nil
 }
+
+// availableRelations keeps showtimes whose movie and screen are active and
+// not deleted
+func availableRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Where("movie_id IN (?)", activeMovieIDs(db)).
+		Where("screen_id IN (?)", activeScreenIDs(db))
+}
+
+// activeMovieIDs is a subquery of the IDs of active, undeleted movies
+func activeMovieIDs(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&entity.Movie{}).Select("id").Where("is_active")
+}
+
+// activeScreenIDs is a subquery of the IDs of active, undeleted screens
+func activeScreenIDs(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&entity.Screen{}).Select("id").Where("is_active")
+}
+
+// unscoped preloads a relation including soft-deleted rows, so historical
+// records still resolve what they referred to
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// GetCapacities returns the given showtimes with only their capacity and
+// availability columns loaded
+func (r *ShowtimeRepository) GetCapacities(ctx context.Context, ids []uuid.UUID) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	if len(ids) == 0 {
+		return showtimes, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Select("id", "status", "visibility", "total_seats", "available_seats", "blocked_seats", "capacity_limit").
+		Where("id IN ?", ids).
+		Find(&showtimes).Error; err != nil {
+		return nil, err
+	}
+	return showtimes, nil
+}
+
+// GetWithCinemas returns the given showtimes with their cinema, deleted
+// showtimes and cinemas included
+func (r *ShowtimeRepository) GetWithCinemas(ctx context.Context, ids []uuid.UUID) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	if len(ids) == 0 {
+		return showtimes, nil
+	}
+	if err := r.db.WithContext(ctx).Unscoped().
+		Preload("Cinema", unscoped).
+		Where("id IN ?", ids).
+		Find(&showtimes).Error; err != nil {
+		return nil, err
+	}
+	return showtimes, nil
+}
+
+// ListStartingBetween returns scheduled showtimes starting within [from, to)
+// in their cinema's timezone, with only their ID and screen loaded
+func (r *ShowtimeRepository) ListStartingBetween(ctx context.Context, from, to time.Time) ([]*entity.Showtime, error) {
+	var showtimes []*entity.Showtime
+	if err := r.db.WithContext(ctx).
+		Select("showtimes.id", "showtimes.screen_id").
+		Joins("JOIN cinemas ON cinemas.id = showtimes.cinema_id").
+		Where("showtimes.status = ?", entity.ShowtimeScheduled).
+		// A day either side covers every timezone and keeps the date index usable
+		Where("showtimes.show_date BETWEEN ? AND ?", from.AddDate(0, 0, -1).Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02")).
+		Where("(showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC') >= ?", from).
+		Where("(showtimes.show_date + showtimes.start_time) AT TIME ZONE COALESCE(NULLIF(cinemas.timezone, ''), 'UTC') < ?", to).
+		Order("showtimes.show_date ASC, showtimes.start_time ASC").
+		Find(&showtimes).Error; err != nil {
+		return nil, err
+	}
+	return showtimes, nil
+}
+
+// CreateBatch creates the showtimes that do not clash with another show on
+// the screen, all in one transaction
+func (r *ShowtimeRepository) CreateBatch(ctx context.Context, screenID uuid.UUID, showtimes []*entity.Showtime, gap time.Duration) ([]repository.ShowtimeClash, error) {
+	if len(showtimes) == 0 {
+		return nil, nil
+	}
+	from, to := showtimes[0].ShowDate, showtimes[0].ShowDate
+	for _, st := range showtimes[1:] {
+		if st.ShowDate.Before(from) {
+			from = st.ShowDate
+		}
+		if st.ShowDate.After(to) {
+			to = st.ShowDate
+		}
+	}
+
+	var clashes []repository.ShowtimeClash
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the screen serialises batches for it; locking its showtimes
+		// alone would not stop another batch inserting into an empty day
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&entity.Screen{}, "id = ?", screenID).Error; err != nil {
+			return err
+		}
+
+		// A day either side covers shows running past midnight
+		var existing []*entity.Showtime
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("screen_id = ? AND status <> ?", screenID, entity.ShowtimeCancelled).
+			Where("show_date BETWEEN ? AND ?", from.AddDate(0, 0, -1).Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02")).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+
+		accepted := make([]*entity.Showtime, 0, len(showtimes))
+		for _, st := range showtimes {
+			if with := firstClash(st, existing, accepted, gap); with != nil {
+				clashes = append(clashes, repository.ShowtimeClash{Showtime: st, With: with})
+				continue
+			}
+			accepted = append(accepted, st)
+		}
+		if len(accepted) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(accepted, 100).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clashes, nil
+}
+
+// firstClash returns the first show in the lists that st runs too close to
+func firstClash(st *entity.Showtime, existing, accepted []*entity.Showtime, gap time.Duration) *entity.Showtime {
+	for _, list := range [][]*entity.Showtime{existing, accepted} {
+		for _, other := range list {
+			if st.ClashesWith(other, gap) {
+				return other
+			}
+		}
+	}
+	return nil
+}
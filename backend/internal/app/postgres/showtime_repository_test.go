@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBatch(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewShowtimeRepository(f.db)
+
+	// The fixture's show runs 19:30-21:30 on 2030-01-07
+	slot := func(day int, start, end string) *entity.Showtime {
+		return &entity.Showtime{
+			CinemaID: f.cinema.ID, ScreenID: f.showtime.ScreenID, MovieID: f.movie.ID,
+			ShowDate: time.Date(2030, 1, day, 0, 0, 0, 0, time.UTC), StartTime: start, EndTime: end,
+			Status: entity.ShowtimeScheduled, TotalSeats: 100, AvailableSeats: 100,
+		}
+	}
+	afternoon := slot(7, "14:00", "16:00")
+	tooSoon := slot(7, "21:40", "23:40")
+	lateShow := slot(6, "23:30", "01:30")
+	nextDay := slot(8, "14:00", "16:00")
+	overlapsBatch := slot(8, "15:00", "17:00")
+
+	clashes, err := repo.CreateBatch(ctx, f.showtime.ScreenID,
+		[]*entity.Showtime{afternoon, tooSoon, lateShow, nextDay, overlapsBatch}, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+
+	want := map[*entity.Showtime]*entity.Showtime{tooSoon: f.showtime, overlapsBatch: nextDay}
+	if len(clashes) != len(want) {
+		t.Fatalf("%d clashes, want %d", len(clashes), len(want))
+	}
+	for _, clash := range clashes {
+		if with, ok := want[clash.Showtime]; !ok || clash.With.ID != with.ID {
+			t.Errorf("clash %s %s with %s", clash.Showtime.ShowDate.Format("2006-01-02"), clash.Showtime.StartTime, clash.With.ID)
+		}
+	}
+
+	var created []*entity.Showtime
+	if err := f.db.DB.Where("screen_id = ?", f.showtime.ScreenID).Order("show_date, start_time").Find(&created).Error; err != nil {
+		t.Fatalf("list showtimes: %v", err)
+	}
+	var starts []string
+	for _, st := range created {
+		starts = append(starts, st.ShowDate.Format("01-02")+" "+st.StartTime)
+	}
+	wantStarts := []string{"01-06 23:30:00", "01-07 14:00:00", "01-07 19:30:00", "01-08 14:00:00"}
+	if len(starts) != len(wantStarts) {
+		t.Fatalf("showtimes %v, want %v", starts, wantStarts)
+	}
+	for i := range starts {
+		if starts[i] != wantStarts[i] {
+			t.Errorf("showtimes %v, want %v", starts, wantStarts)
+			break
+		}
+	}
+
+	// A cancelled show frees its slot
+	if err := f.db.DB.Model(f.showtime).Update("status", entity.ShowtimeCancelled).Error; err != nil {
+		t.Fatalf("cancel showtime: %v", err)
+	}
+	clashes, err = repo.CreateBatch(ctx, f.showtime.ScreenID, []*entity.Showtime{slot(7, "21:40", "23:40")}, 15*time.Minute)
+	if err != nil || len(clashes) != 0 {
+		t.Errorf("CreateBatch after the cancellation: %d clashes, err %v", len(clashes), err)
+	}
+}
+
+func TestListForMovie(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	repo := NewShowtimeRepository(f.db)
+
+	// A second cinema, sorted before the fixture's, with a screen of its own
+	other := &entity.Cinema{Name: "Alpha " + f.cinema.Slug, Slug: "alpha-" + f.cinema.Slug, Address: "2 Test St", City: "Elsewhere",
+		Country: "VN", IsActive: true, Timezone: "Asia/Ho_Chi_Minh"}
+	if err := f.db.DB.Create(other).Error; err != nil {
+		t.Fatalf("create cinema: %v", err)
+	}
+	screen := &entity.Screen{CinemaID: other.ID, Name: "Screen 1", ScreenNumber: 1, Capacity: 50, ScreenType: entity.ScreenStandard,
+		Rows: 5, SeatsPerRow: 10, IsActive: true}
+	if err := f.db.DB.Create(screen).Error; err != nil {
+		t.Fatalf("create screen: %v", err)
+	}
+	add := func(cinema *entity.Cinema, screenID uuid.UUID, day int, start string, edit func(*entity.Showtime)) *entity.Showtime {
+		st := &entity.Showtime{CinemaID: cinema.ID, ScreenID: screenID, MovieID: f.movie.ID,
+			ShowDate: time.Date(2030, 1, day, 0, 0, 0, 0, time.UTC), StartTime: start, EndTime: "23:59",
+			Status: entity.ShowtimeScheduled, Visibility: entity.VisibilityPublic, TotalSeats: 50, AvailableSeats: 50}
+		if edit != nil {
+			edit(st)
+		}
+		if err := f.db.DB.Create(st).Error; err != nil {
+			t.Fatalf("create showtime: %v", err)
+		}
+		return st
+	}
+	early := add(other, screen.ID, 7, "09:00", nil)
+	late := add(other, screen.ID, 8, "21:00", nil)
+	add(other, screen.ID, 8, "22:00", func(st *entity.Showtime) { st.Visibility = entity.VisibilityPrivate })
+	add(other, screen.ID, 9, "20:00", func(st *entity.Showtime) { st.Status = entity.ShowtimeCancelled })
+	add(other, screen.ID, 20, "20:00", nil) // after the range
+
+	filter := repository.MovieShowtimeFilter{
+		MovieID:     f.movie.ID,
+		DateFrom:    time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC),
+		DateTo:      time.Date(2030, 1, 13, 0, 0, 0, 0, time.UTC),
+		StartsAfter: time.Date(2030, 1, 6, 0, 0, 0, 0, time.UTC),
+	}
+	ids := func(showtimes []*entity.Showtime) []uuid.UUID {
+		var ids []uuid.UUID
+		for _, st := range showtimes {
+			ids = append(ids, st.ID)
+		}
+		return ids
+	}
+
+	showtimes, total, err := repo.ListForMovie(ctx, filter, 0, 10)
+	if err != nil {
+		t.Fatalf("ListForMovie: %v", err)
+	}
+	want := []uuid.UUID{early.ID, late.ID, f.showtime.ID}
+	if total != 2 || !slices.Equal(ids(showtimes), want) {
+		t.Errorf("ListForMovie = %v (%d cinemas), want %v (2 cinemas)", ids(showtimes), total, want)
+	}
+	if len(showtimes) > 0 && (showtimes[0].Cinema.ID != other.ID || showtimes[0].Screen.ID != screen.ID) {
+		t.Error("cinema and screen are not loaded")
+	}
+
+	// Pages count cinemas
+	showtimes, total, err = repo.ListForMovie(ctx, filter, 1, 1)
+	if err != nil || total != 2 || !slices.Equal(ids(showtimes), []uuid.UUID{f.showtime.ID}) {
+		t.Errorf("second page = %v (%d cinemas), %v", ids(showtimes), total, err)
+	}
+
+	// 09:00 in Saigon on the 7th is 02:00 UTC; at 03:00 UTC it has started
+	filter.StartsAfter = time.Date(2030, 1, 7, 3, 0, 0, 0, time.UTC)
+	if showtimes, _, _ = repo.ListForMovie(ctx, filter, 0, 10); slices.Contains(ids(showtimes), early.ID) {
+		t.Error("a showtime that has started is listed")
+	}
+
+	// City matches ignoring case
+	filter.City = "ELSEWHERE"
+	showtimes, total, err = repo.ListForMovie(ctx, filter, 0, 10)
+	if err != nil || total != 1 || !slices.Equal(ids(showtimes), []uuid.UUID{late.ID}) {
+		t.Errorf("by city = %v (%d cinemas), %v", ids(showtimes), total, err)
+	}
+
+	// Inactive cinemas are left out
+	if err := f.db.DB.Model(other).Update("is_active", false).Error; err != nil {
+		t.Fatalf("deactivate cinema: %v", err)
+	}
+	if _, total, _ = repo.ListForMovie(ctx, filter, 0, 10); total != 0 {
+		t.Errorf("%d cinemas after the deactivation, want 0", total)
+	}
+}
+
+func TestDecrementAvailableSeatsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := newCommittedTestDatabase(t)
+	repo := NewShowtimeRepository(db)
+
+	suffix := uuid.NewString()[:8]
+	cinema := &entity.Cinema{Name: "Version Test", Slug: "version-test-" + suffix, Address: "1 Test St", City: "Test", Country: "VN", IsActive: true, Timezone: "UTC"}
+	movie := &entity.Movie{Title: "Version Test", Slug: "version-test-" + suffix, Duration: 120, ReleaseDate: time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+	if err := db.DB.Create(cinema).Error; err != nil {
+		t.Fatalf("create cinema: %v", err)
+	}
+	if err := db.DB.Create(movie).Error; err != nil {
+		t.Fatalf("create movie: %v", err)
+	}
+	screen := &entity.Screen{CinemaID: cinema.ID, Name: "1", ScreenNumber: 1, Capacity: 20, ScreenType: entity.ScreenStandard, Rows: 4, SeatsPerRow: 5, IsActive: true}
+	if err := db.DB.Create(screen).Error; err != nil {
+		t.Fatalf("create screen: %v", err)
+	}
+	const seats = 20
+	showtime := &entity.Showtime{
+		CinemaID: cinema.ID, ScreenID: screen.ID, MovieID: movie.ID,
+		ShowDate: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), StartTime: "19:30", EndTime: "21:30",
+		Status: entity.ShowtimeScheduled, TotalSeats: seats, AvailableSeats: seats,
+	}
+	if err := db.DB.Create(showtime).Error; err != nil {
+		t.Fatalf("create showtime: %v", err)
+	}
+	t.Cleanup(func() {
+		db.DB.Unscoped().Delete(showtime)
+		db.DB.Unscoped().Delete(screen)
+		db.DB.Unscoped().Delete(movie)
+		db.DB.Unscoped().Delete(cinema)
+	})
+
+	// Each writer takes one seat at a time, reading the version again after
+	// losing a race, until the showtime is full
+	const writers = 8
+	taken := make([]int, writers)
+	errs := make(chan error, writers)
+	for w := range writers {
+		go func() {
+			for {
+				current, err := repo.GetByID(ctx, showtime.ID)
+				if err != nil {
+					errs <- err
+					return
+				}
+				err = repo.DecrementAvailableSeats(ctx, showtime.ID, 1, current.Version)
+				switch {
+				case err == nil:
+					taken[w]++
+				case apperrors.Is(err, apperrors.CodeVersionConflict):
+				case apperrors.Is(err, apperrors.CodeSeatNotAvailable):
+					errs <- nil
+					return
+				default:
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	for range writers {
+		if err := <-errs; err != nil {
+			t.Fatalf("writer: %v", err)
+		}
+	}
+
+	total := 0
+	for _, n := range taken {
+		total += n
+	}
+	final, err := repo.GetByID(ctx, showtime.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if total != seats || final.AvailableSeats != 0 || final.Version != seats {
+		t.Errorf("%d seats taken, %d left at version %d; want %d taken, none left at version %d",
+			total, final.AvailableSeats, final.Version, seats, seats)
+	}
+
+	// A stale version is refused even with seats to spare
+	if err := repo.IncrementAvailableSeats(ctx, showtime.ID, 1); err != nil {
+		t.Fatalf("IncrementAvailableSeats: %v", err)
+	}
+	if err := repo.DecrementAvailableSeats(ctx, showtime.ID, 1, final.Version); !apperrors.Is(err, apperrors.CodeVersionConflict) {
+		t.Errorf("decrement at a stale version: %v, want %s", err, apperrors.CodeVersionConflict)
+	}
+	if err := repo.DecrementAvailableSeats(ctx, uuid.New(), 1, 0); !apperrors.Is(err, apperrors.CodeShowtimeNotFound) {
+		t.Errorf("missing showtime: %v, want %s", err, apperrors.CodeShowtimeNotFound)
+	}
+}
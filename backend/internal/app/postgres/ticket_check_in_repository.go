@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+// ticketCheckInRepository implements repository.TicketCheckInRepository
+type ticketCheckInRepository struct {
+	db *Database
+}
+
+// NewTicketCheckInRepository creates a new ticket check-in repository
+func NewTicketCheckInRepository(db *Database) repository.TicketCheckInRepository {
+	return &ticketCheckInRepository{db: db}
+}
+
+func (r *ticketCheckInRepository) Create(ctx context.Context, checkIn *entity.TicketCheckIn) error {
+	if err := r.db.WithContext(ctx).Create(checkIn).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to record ticket check-in")
+	}
+	return nil
+}
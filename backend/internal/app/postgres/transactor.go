@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/repository"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs work spanning several repositories in one transaction
+type Transactor struct {
+	db *Database
+}
+
+// NewTransactor creates a new transactor
+func NewTransactor(db *Database) repository.Transactor {
+	return &Transactor{db: db}
+}
+
+// InTransaction runs fn in a transaction that commits when fn returns nil
+// and rolls back otherwise. Repositories called with the context fn is
+// given take part in the transaction; called inside another transaction,
+// fn joins it.
+func (t *Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction ctx carries, or db, bound to ctx. A
+// repository's own transactions opened on it become savepoints of the
+// enclosing one.
+func conn(ctx context.Context, db *Database) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestTransactorRollsBackEveryRepository(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	tx := NewTransactor(f.db)
+	bookings := NewBookingRepository(f.db)
+	payments := NewPaymentRepository(f.db)
+
+	booking := &entity.Booking{
+		BookingReference: "BK-TX-" + uuid.NewString()[:8], ShowtimeID: f.showtime.ID, NumTickets: 1,
+		SubtotalAmount: 10, FinalAmount: 10, BookingStatus: entity.BookingConfirmed,
+		PaymentStatus: entity.PaymentPaid, SalesChannel: entity.ChannelOnline, BookedAt: time.Now(),
+	}
+	failure := errors.New("share payment lost")
+	err := tx.InTransaction(ctx, func(ctx context.Context) error {
+		if err := bookings.CreateWithSeats(ctx, booking, nil); err != nil {
+			return err
+		}
+		payment := &entity.Payment{BookingID: booking.ID, PaymentReference: "PAY-TX-" + uuid.NewString()[:8], PaymentGateway: "test", Amount: 10, PaymentStatus: entity.PaymentPaid}
+		if err := payments.Create(ctx, payment); err != nil {
+			return err
+		}
+		// Nested work joins the outer transaction
+		return tx.InTransaction(ctx, func(context.Context) error { return failure })
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("InTransaction = %v, want the work's error", err)
+	}
+
+	var count int64
+	f.db.DB.Model(&entity.Booking{}).Where("id = ?", booking.ID).Count(&count)
+	if count != 0 {
+		t.Error("the booking was kept after the rollback")
+	}
+	f.db.DB.Model(&entity.Payment{}).Where("booking_id = ?", booking.ID).Count(&count)
+	if count != 0 {
+		t.Error("the payment was kept after the rollback")
+	}
+}
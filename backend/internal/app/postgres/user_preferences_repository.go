@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userPreferencesRepository implements repository.UserPreferencesRepository
+type userPreferencesRepository struct {
+	db *Database
+}
+
+// NewUserPreferencesRepository creates a new user preferences repository
+func NewUserPreferencesRepository(db *Database) repository.UserPreferencesRepository {
+	return &userPreferencesRepository{db: db}
+}
+
+func (r *userPreferencesRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserPreferences, error) {
+	var prefs entity.UserPreferences
+	if err := r.db.WithContext(ctx).First(&prefs, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get user preferences")
+	}
+	return &prefs, nil
+}
+
+func (r *userPreferencesRepository) Upsert(ctx context.Context, prefs *entity.UserPreferences) error {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"favorite_genres", "favorite_cinema_ids", "preferred_formats",
+			"notification_email", "notification_push", "updated_at",
+		}),
+	}).Create(prefs).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to save user preferences")
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // userRepository implements repository.UserRepository
@@ -24,8 +25,16 @@ func NewUserRepository(db *Database) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
-		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create user")
+	// Concurrent registrations for one email all pass the existence check;
+	// the unique index decides which one wins
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, DoNothing: true}).
+		Create(user)
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to create user")
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrEmailExists()
 	}
 	return nil
 }
@@ -54,6 +63,18 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 	return &user, nil
 }
 
+func (r *userRepository) GetByGoogleID(ctx context.Context, googleID string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).First(&user, "google_id = ?", googleID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.CodeUserNotFound, "user not found")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get user")
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
 		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update user")
@@ -85,6 +106,16 @@ func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity
 	return users, total, nil
 }
 
+func (r *userRepository) ListByRoles(ctx context.Context, roles ...entity.Role) ([]*entity.User, error) {
+	var users []*entity.User
+	if err := r.db.WithContext(ctx).
+		Where("role IN ? AND is_active = ?", roles, true).
+		Find(&users).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list users by role")
+	}
+	return users, nil
+}
+
 func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
 	result := r.db.WithContext(ctx).Model(&entity.User{}).
 		Where("id = ?", id).
@@ -185,6 +216,34 @@ func (r *refreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+func (r *refreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND revoked = ?", id, false).
+			Updates(map[string]interface{}{
+				"revoked":     true,
+				"revoked_at":  time.Now(),
+				"replaced_by": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to rotate refresh token")
+	}
+	return rotated, nil
+}
+
 func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
 	now := time.Now()
 	if err := r.db.WithContext(ctx).Model(&entity.RefreshToken{}).
@@ -198,6 +257,19 @@ func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uu
 	return nil
 }
 
+func (r *refreshTokenRepository) RevokeAllForUserExcept(ctx context.Context, userID, keepID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND id <> ? AND revoked = ?", userID, keepID, false).
+		Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to revoke tokens")
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	if err := r.db.WithContext(ctx).
 		Where("expires_at < ?", time.Now()).
@@ -287,3 +359,121 @@ func (r *passwordResetTokenRepository) InvalidateAllForUser(ctx context.Context,
 	}
 	return nil
 }
+
+// emailVerificationTokenRepository implements repository.EmailVerificationTokenRepository
+type emailVerificationTokenRepository struct {
+	db *Database
+}
+
+// NewEmailVerificationTokenRepository creates a new email verification token repository
+func NewEmailVerificationTokenRepository(db *Database) repository.EmailVerificationTokenRepository {
+	return &emailVerificationTokenRepository{db: db}
+}
+
+func (r *emailVerificationTokenRepository) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to create email verification token")
+	}
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	var token entity.EmailVerificationToken
+	err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTokenInvalid()
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get email verification token")
+	}
+	return &token, nil
+}
+
+func (r *emailVerificationTokenRepository) GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*entity.EmailVerificationToken, error) {
+	var token entity.EmailVerificationToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used = ?", userID, false).
+		Order("created_at DESC").
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get email verification token")
+	}
+	return &token, nil
+}
+
+func (r *emailVerificationTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&entity.EmailVerificationToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"used":    true,
+			"used_at": now,
+		})
+
+	if result.Error != nil {
+		return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to mark token as used")
+	}
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) Redeem(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the token so concurrent redemptions see it used
+		var token entity.EmailVerificationToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&token, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrTokenInvalid()
+			}
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to redeem email verification token")
+		}
+		if token.Used {
+			return apperrors.ErrTokenInvalid()
+		}
+		if !token.IsValid() {
+			return apperrors.ErrTokenExpired()
+		}
+
+		if err := tx.Model(&token).Updates(map[string]interface{}{
+			"used":    true,
+			"used_at": time.Now(),
+		}).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to redeem email verification token")
+		}
+
+		result := tx.Model(&entity.User{}).
+			Where("id = ?", token.UserID).
+			Update("email_verified", true)
+		if result.Error != nil {
+			return apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to verify email")
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.New(apperrors.CodeUserNotFound, "user not found")
+		}
+		return nil
+	})
+}
+
+func (r *emailVerificationTokenRepository) DeleteExpired(ctx context.Context) error {
+	if err := r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&entity.EmailVerificationToken{}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete expired tokens")
+	}
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) InvalidateAllForUser(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&entity.EmailVerificationToken{}).
+		Where("user_id = ? AND used = ?", userID, false).
+		Updates(map[string]interface{}{
+			"used":    true,
+			"used_at": now,
+		}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to invalidate tokens")
+	}
+	return nil
+}
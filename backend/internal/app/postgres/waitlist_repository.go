@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// waitlistRepository implements repository.WaitlistRepository
+type waitlistRepository struct {
+	db *Database
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *Database) repository.WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) Enqueue(ctx context.Context, entry *entity.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the showtime so concurrent joins get distinct positions
+		if err := tx.Exec("SELECT 1 FROM showtimes WHERE id = ? FOR UPDATE", entry.ShowtimeID).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to join waitlist")
+		}
+
+		waiting := tx.Model(&entity.WaitlistEntry{}).
+			Where("showtime_id = ? AND status = ?", entry.ShowtimeID, entity.WaitlistWaiting)
+		if entry.UserID != nil {
+			waiting = waiting.Where("user_id = ?", *entry.UserID)
+		} else {
+			waiting = waiting.Where("user_id IS NULL AND LOWER(guest_email) = ?", strings.ToLower(entry.GuestEmail))
+		}
+		var existing int64
+		if err := waiting.Count(&existing).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to join waitlist")
+		}
+		if existing > 0 {
+			return apperrors.ErrConflict("already on the waitlist for this showtime")
+		}
+
+		var last *int64
+		if err := tx.Model(&entity.WaitlistEntry{}).
+			Where("showtime_id = ?", entry.ShowtimeID).
+			Select("MAX(position)").
+			Scan(&last).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to join waitlist")
+		}
+		entry.Position = 1
+		if last != nil {
+			entry.Position = *last + 1
+		}
+		entry.Status = entity.WaitlistWaiting
+
+		if err := tx.Create(entry).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "failed to join waitlist")
+		}
+		return nil
+	})
+}
+
+func (r *waitlistRepository) Dequeue(ctx context.Context, showtimeID uuid.UUID, maxSeats int) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Entries another caller is taking are skipped rather than waited on
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("showtime_id = ? AND status = ? AND seat_count <= ?", showtimeID, entity.WaitlistWaiting, maxSeats).
+			Order("position ASC").
+			First(&entry).Error; err != nil {
+			return err
+		}
+		entry.Status = entity.WaitlistNotified
+		return tx.Model(&entry).Update("status", entity.WaitlistNotified).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to dequeue waitlist entry")
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) GetByUser(ctx context.Context, showtimeID, userID uuid.UUID) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("showtime_id = ? AND user_id = ?", showtimeID, userID).
+		Order("position DESC").
+		First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("waitlist entry")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get waitlist entry")
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) CountAhead(ctx context.Context, entry *entity.WaitlistEntry) (int64, error) {
+	var ahead int64
+	if err := r.db.WithContext(ctx).Model(&entity.WaitlistEntry{}).
+		Where("showtime_id = ? AND status = ? AND position < ?", entry.ShowtimeID, entity.WaitlistWaiting, entry.Position).
+		Count(&ahead).Error; err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count waitlist entries")
+	}
+	return ahead, nil
+}
+
+func (r *waitlistRepository) Notify(ctx context.Context, id uuid.UUID, holdID *string, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&entity.WaitlistEntry{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"hold_id": holdID, "notified_at": at}).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update waitlist entry")
+	}
+	return nil
+}
+
+func (r *waitlistRepository) ExpireWaiting(ctx context.Context, showtimeID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.WaitlistEntry{}).
+		Where("showtime_id = ? AND status = ?", showtimeID, entity.WaitlistWaiting).
+		Update("status", entity.WaitlistExpired)
+	if result.Error != nil {
+		return 0, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to expire waitlist entries")
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+func TestWaitlistQueue(t *testing.T) {
+	ctx := context.Background()
+	f := newDemandFixture(t)
+	waitlist := NewWaitlistRepository(f.db)
+
+	join := func(email string, seats int) *entity.WaitlistEntry {
+		t.Helper()
+		entry := &entity.WaitlistEntry{ShowtimeID: f.showtime.ID, GuestEmail: email, SeatCount: seats}
+		if err := waitlist.Enqueue(ctx, entry); err != nil {
+			t.Fatalf("Enqueue(%s): %v", email, err)
+		}
+		return entry
+	}
+	four := join("four@example.com", 4)
+	two := join("two@example.com", 2)
+	one := join("one@example.com", 1)
+	if four.Position != 1 || two.Position != 2 || one.Position != 3 {
+		t.Errorf("positions %d, %d, %d, want 1, 2, 3", four.Position, two.Position, one.Position)
+	}
+
+	duplicate := &entity.WaitlistEntry{ShowtimeID: f.showtime.ID, GuestEmail: "TWO@example.com", SeatCount: 1}
+	if err := waitlist.Enqueue(ctx, duplicate); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("second join with the same email = %v, want a conflict", err)
+	}
+
+	// Three seats: the four-seat entry does not fit and keeps its place
+	dequeued, err := waitlist.Dequeue(ctx, f.showtime.ID, 3)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if dequeued == nil || dequeued.ID != two.ID || dequeued.Status != entity.WaitlistNotified {
+		t.Fatalf("dequeued %+v, want the two-seat entry", dequeued)
+	}
+	ahead, err := waitlist.CountAhead(ctx, one)
+	if err != nil {
+		t.Fatalf("CountAhead: %v", err)
+	}
+	if ahead != 1 {
+		t.Errorf("%d entries ahead of the last, want 1", ahead)
+	}
+
+	if dequeued, err := waitlist.Dequeue(ctx, f.showtime.ID, 0); err != nil || dequeued != nil {
+		t.Errorf("Dequeue with no seats = %+v, %v, want nothing", dequeued, err)
+	}
+
+	expired, err := waitlist.ExpireWaiting(ctx, f.showtime.ID)
+	if err != nil {
+		t.Fatalf("ExpireWaiting: %v", err)
+	}
+	if expired != 2 {
+		t.Errorf("expired %d entries, want the 2 still waiting", expired)
+	}
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookEventRepository implements repository.WebhookEventRepository
+type webhookEventRepository struct {
+	db *Database
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *Database) repository.WebhookEventRepository {
+	return &webhookEventRepository{db: db}
+}
+
+func (r *webhookEventRepository) Create(ctx context.Context, event *entity.WebhookEvent) (*entity.WebhookEvent, bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(event)
+	if result.Error != nil {
+		return nil, false, apperrors.Wrap(result.Error, apperrors.CodeInternal, "failed to store webhook event")
+	}
+	if result.RowsAffected == 1 {
+		return event, true, nil
+	}
+
+	// Redelivery of an event we already have
+	var stored entity.WebhookEvent
+	if err := r.db.WithContext(ctx).
+		First(&stored, "provider = ? AND event_id = ?", event.Provider, event.EventID).Error; err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get webhook event")
+	}
+	return &stored, false, nil
+}
+
+func (r *webhookEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEvent, error) {
+	var event entity.WebhookEvent
+	err := r.db.WithContext(ctx).First(&event, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound("Webhook event")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get webhook event")
+	}
+	return &event, nil
+}
+
+func (r *webhookEventRepository) List(ctx context.Context, filter repository.WebhookEventFilter, offset, limit int) ([]*entity.WebhookEvent, int64, error) {
+	var events []*entity.WebhookEvent
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&entity.WebhookEvent{})
+
+	if filter.Provider != "" {
+		db = db.Where("provider = ?", filter.Provider)
+	}
+	if filter.EventType != "" {
+		db = db.Where("event_type = ?", filter.EventType)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if filter.PaymentReference != "" {
+		db = db.Where("payment_reference = ?", filter.PaymentReference)
+	}
+	if filter.ReceivedFrom != nil {
+		db = db.Where("received_at >= ?", *filter.ReceivedFrom)
+	}
+	if filter.ReceivedTo != nil {
+		db = db.Where("received_at <= ?", *filter.ReceivedTo)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count webhook events")
+	}
+
+	if err := db.Offset(offset).Limit(limit).Order("received_at DESC").Find(&events).Error; err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list webhook events")
+	}
+
+	return events, total, nil
+}
+
+func (r *webhookEventRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Model(&entity.WebhookEvent{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update webhook event")
+	}
+	return nil
+}
+
+func (r *webhookEventRepository) Update(ctx context.Context, event *entity.WebhookEvent) error {
+	if err := r.db.WithContext(ctx).Model(event).
+		Select("status", "last_error", "payment_reference", "booking_id", "payment_id", "processed_at").
+		Updates(event).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to update webhook event")
+	}
+	return nil
+}
+
+func (r *webhookEventRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]*entity.WebhookEvent, error) {
+	var events []*entity.WebhookEvent
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND received_at < ? AND alerted_at IS NULL",
+			[]entity.WebhookStatus{entity.WebhookReceived, entity.WebhookFailed}, before).
+		Order("received_at ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list stale webhook events")
+	}
+	return events, nil
+}
+
+func (r *webhookEventRepository) MarkAlerted(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Model(&entity.WebhookEvent{}).
+		Where("id IN ?", ids).
+		Update("alerted_at", at).Error; err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to mark webhook events alerted")
+	}
+	return nil
+}
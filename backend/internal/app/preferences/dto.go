@@ -0,0 +1,28 @@
+package preferences
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdatePreferencesRequest replaces the current user's preferences. A list
+// left out keeps its current value and an empty one clears it; likewise a
+// notification setting left out is kept.
+type UpdatePreferencesRequest struct {
+	FavoriteGenres    []string    `json:"favorite_genres" validate:"omitempty,max=20,dive,required,max=50"`
+	FavoriteCinemaIDs []uuid.UUID `json:"favorite_cinema_ids" validate:"omitempty,max=20,dive,required" swaggertype:"array,string" format:"uuid"`
+	PreferredFormats  []string    `json:"preferred_formats" validate:"omitempty,max=5,dive,oneof=STANDARD 3D IMAX 4DX DOLBY"`
+	NotificationEmail *bool       `json:"notification_email"`
+	NotificationPush  *bool       `json:"notification_push"`
+}
+
+// PreferencesResponse is a user's preferences
+type PreferencesResponse struct {
+	FavoriteGenres    []string    `json:"favorite_genres"`
+	FavoriteCinemaIDs []uuid.UUID `json:"favorite_cinema_ids" swaggertype:"array,string" format:"uuid"`
+	PreferredFormats  []string    `json:"preferred_formats"`
+	NotificationEmail bool        `json:"notification_email"`
+	NotificationPush  bool        `json:"notification_push"`
+	UpdatedAt         *time.Time  `json:"updated_at,omitempty"` // unset until first saved
+}
@@ -0,0 +1,112 @@
+package preferences
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Service stores what users like to watch and how they want to be
+// notified. The movie service reads the same preferences to personalise
+// the now-showing listing.
+type Service struct {
+	repo   repository.UserPreferencesRepository
+	logger *logger.Logger
+}
+
+// NewService creates a new user preferences service
+func NewService(repo repository.UserPreferencesRepository, logger *logger.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Get returns the user's preferences; a user who never saved any gets the
+// defaults
+func (s *Service) Get(ctx context.Context, userID uuid.UUID) (*PreferencesResponse, error) {
+	prefs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = entity.DefaultUserPreferences(userID)
+	}
+	return toResponse(prefs), nil
+}
+
+// Update saves the user's preferences. Genres are trimmed and repeated
+// values dropped.
+func (s *Service) Update(ctx context.Context, userID uuid.UUID, req UpdatePreferencesRequest) (*PreferencesResponse, error) {
+	prefs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = entity.DefaultUserPreferences(userID)
+	}
+
+	if req.FavoriteGenres != nil {
+		genres := make([]string, 0, len(req.FavoriteGenres))
+		for _, genre := range req.FavoriteGenres {
+			genres = append(genres, strings.TrimSpace(genre))
+		}
+		prefs.FavoriteGenres = distinct(genres)
+	}
+	if req.FavoriteCinemaIDs != nil {
+		ids := make([]string, 0, len(req.FavoriteCinemaIDs))
+		for _, id := range req.FavoriteCinemaIDs {
+			ids = append(ids, id.String())
+		}
+		prefs.FavoriteCinemaIDs = distinct(ids)
+	}
+	if req.PreferredFormats != nil {
+		prefs.PreferredFormats = distinct(req.PreferredFormats)
+	}
+	if req.NotificationEmail != nil {
+		prefs.NotificationEmail = *req.NotificationEmail
+	}
+	if req.NotificationPush != nil {
+		prefs.NotificationPush = *req.NotificationPush
+	}
+
+	if err := s.repo.Upsert(ctx, prefs); err != nil {
+		s.logger.WithContext(ctx).Error("failed to save user preferences", zap.Error(err))
+		return nil, err
+	}
+	return toResponse(prefs), nil
+}
+
+// distinct drops empty and repeated values, keeping the first of each
+func distinct(values []string) pq.StringArray {
+	out := make(pq.StringArray, 0, len(values))
+	for _, value := range values {
+		if value != "" && !slices.Contains(out, value) {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func toResponse(prefs *entity.UserPreferences) *PreferencesResponse {
+	// Empty arrays may scan as nil, but the lists are never null
+	res := &PreferencesResponse{
+		FavoriteGenres:    append([]string{}, prefs.FavoriteGenres...),
+		FavoriteCinemaIDs: prefs.CinemaIDs(),
+		PreferredFormats:  append([]string{}, prefs.PreferredFormats...),
+		NotificationEmail: prefs.NotificationEmail,
+		NotificationPush:  prefs.NotificationPush,
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		res.UpdatedAt = &prefs.UpdatedAt
+	}
+	return res
+}
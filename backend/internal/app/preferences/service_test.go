@@ -0,0 +1,97 @@
+package preferences
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memPreferences keeps preferences in memory, one set per user
+type memPreferences struct {
+	repository.UserPreferencesRepository
+	prefs map[uuid.UUID]entity.UserPreferences
+}
+
+func (m *memPreferences) GetByUserID(_ context.Context, userID uuid.UUID) (*entity.UserPreferences, error) {
+	prefs, ok := m.prefs[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &prefs, nil
+}
+
+func (m *memPreferences) Upsert(_ context.Context, prefs *entity.UserPreferences) error {
+	m.prefs[prefs.UserID] = *prefs
+	return nil
+}
+
+func newTestService() (*Service, *memPreferences) {
+	repo := &memPreferences{prefs: make(map[uuid.UUID]entity.UserPreferences)}
+	return NewService(repo, &logger.Logger{Logger: zap.NewNop()}), repo
+}
+
+func TestGetDefaults(t *testing.T) {
+	svc, _ := newTestService()
+
+	res, err := svc.Get(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.FavoriteGenres == nil || res.FavoriteCinemaIDs == nil || res.PreferredFormats == nil {
+		t.Errorf("lists of a new user are null: %+v", res)
+	}
+	if len(res.FavoriteGenres)+len(res.FavoriteCinemaIDs)+len(res.PreferredFormats) != 0 {
+		t.Errorf("new user has favorites: %+v", res)
+	}
+	if !res.NotificationEmail || !res.NotificationPush || res.UpdatedAt != nil {
+		t.Errorf("notifications %v/%v, updated at %v, want both on and never saved", res.NotificationEmail, res.NotificationPush, res.UpdatedAt)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+	userID := uuid.New()
+	cinemaID := uuid.New()
+	off := false
+
+	res, err := svc.Update(ctx, userID, UpdatePreferencesRequest{
+		FavoriteGenres:    []string{" Drama ", "Sci-Fi", "Drama", " "},
+		FavoriteCinemaIDs: []uuid.UUID{cinemaID, cinemaID},
+		PreferredFormats:  []string{"IMAX"},
+		NotificationPush:  &off,
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !slices.Equal(res.FavoriteGenres, []string{"Drama", "Sci-Fi"}) || !slices.Equal(res.FavoriteCinemaIDs, []uuid.UUID{cinemaID}) {
+		t.Errorf("genres %q, cinemas %v, want trimmed and distinct", res.FavoriteGenres, res.FavoriteCinemaIDs)
+	}
+	if !res.NotificationEmail || res.NotificationPush {
+		t.Errorf("notifications %v/%v, want email on and push off", res.NotificationEmail, res.NotificationPush)
+	}
+
+	// Lists left out are kept and an empty list clears one
+	res, err = svc.Update(ctx, userID, UpdatePreferencesRequest{PreferredFormats: []string{}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !slices.Equal(res.FavoriteGenres, []string{"Drama", "Sci-Fi"}) || len(res.PreferredFormats) != 0 || res.PreferredFormats == nil {
+		t.Errorf("genres %q, formats %q, want the genres kept and the formats cleared", res.FavoriteGenres, res.PreferredFormats)
+	}
+	if res.NotificationPush {
+		t.Error("push notifications turned back on by an update that left them out")
+	}
+
+	saved, _ := repo.GetByUserID(ctx, userID)
+	if saved == nil || !saved.HasFavorites() || len(saved.PreferredFormats) != 0 {
+		t.Errorf("saved preferences = %+v", saved)
+	}
+}
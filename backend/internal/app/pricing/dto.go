@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"encoding/json"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreatePricingRuleRequest adds a pricing rule to a cinema
+type CreatePricingRuleRequest struct {
+	RuleType     string `json:"rule_type" validate:"required,oneof=SEAT_TYPE TIME_OF_DAY DAY_OF_WEEK OCCUPANCY"`
+	ModifierType string `json:"modifier_type" validate:"required,oneof=FLAT PERCENTAGE"`
+	// ModifierValue is an amount for FLAT and a percent for PERCENTAGE;
+	// negative values are discounts
+	ModifierValue float64 `json:"modifier_value" validate:"min=-1000,max=1000"`
+	// ConditionJSON is when the rule applies, e.g. {"seat_types":["VIP"]},
+	// {"from":"18:00","to":"23:00"}, {"days":["SAT","SUN"]} or
+	// {"min_occupancy":80}
+	ConditionJSON json.RawMessage `json:"condition_json" validate:"required" swaggertype:"object"`
+	// ScreenType limits the rule to screens of one type
+	ScreenType string `json:"screen_type" validate:"omitempty,oneof=STANDARD IMAX 4DX DOLBY VIP"`
+	// Priority picks, among matching rules of the same type, the ones applied
+	Priority int `json:"priority" validate:"min=-1000,max=1000"`
+	// ValidFrom and ValidUntil limit the rule to showtimes starting within them
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// UpdatePricingRuleRequest changes a pricing rule; omitted fields are kept.
+// The rule type cannot change.
+type UpdatePricingRuleRequest struct {
+	ModifierType    string          `json:"modifier_type" validate:"omitempty,oneof=FLAT PERCENTAGE"`
+	ModifierValue   *float64        `json:"modifier_value" validate:"omitempty,min=-1000,max=1000"`
+	ConditionJSON   json.RawMessage `json:"condition_json" swaggertype:"object"`
+	ScreenType      string          `json:"screen_type" validate:"omitempty,oneof=STANDARD IMAX 4DX DOLBY VIP"`
+	Priority        *int            `json:"priority" validate:"omitempty,min=-1000,max=1000"`
+	ValidFrom       *time.Time      `json:"valid_from"`
+	ValidUntil      *time.Time      `json:"valid_until"`
+	ClearScreenType bool            `json:"clear_screen_type"`
+	ClearValidFrom  bool            `json:"clear_valid_from"`
+	ClearValidUntil bool            `json:"clear_valid_until"`
+}
+
+// PricingRuleResponse represents a pricing rule in responses
+type PricingRuleResponse struct {
+	ID            uuid.UUID       `json:"id" swaggertype:"string" format:"uuid"`
+	CinemaID      uuid.UUID       `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	RuleType      string          `json:"rule_type"`
+	ModifierType  string          `json:"modifier_type"`
+	ModifierValue float64         `json:"modifier_value"`
+	ConditionJSON json.RawMessage `json:"condition_json" swaggertype:"object"`
+	ScreenType    *string         `json:"screen_type,omitempty"`
+	Priority      int             `json:"priority"`
+	ValidFrom     *time.Time      `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time      `json:"valid_until,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+func toPricingRuleResponse(rule *entity.PricingRule) PricingRuleResponse {
+	res := PricingRuleResponse{
+		ID:            rule.ID,
+		CinemaID:      rule.CinemaID,
+		RuleType:      string(rule.RuleType),
+		ModifierType:  string(rule.ModifierType),
+		ModifierValue: rule.ModifierValue,
+		ConditionJSON: json.RawMessage(rule.ConditionJSON),
+		Priority:      rule.Priority,
+		ValidFrom:     rule.ValidFrom,
+		ValidUntil:    rule.ValidUntil,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+	if rule.ScreenType != nil {
+		screenType := string(*rule.ScreenType)
+		res.ScreenType = &screenType
+	}
+	return res
+}
@@ -0,0 +1,163 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultSeatTypeMultipliers scale the showtime base price by seat type
+// before the cinema's rules apply, unless configured otherwise
+var defaultSeatTypeMultipliers = map[entity.SeatType]float64{
+	entity.SeatStandard:   1.0,
+	entity.SeatWheelchair: 1.0,
+	entity.SeatPremium:    1.5,
+	entity.SeatRecliner:   1.75,
+	entity.SeatVIP:        2.0,
+	entity.SeatCouple:     2.0,
+	entity.SeatCompanion:  1.0, // only when the cinema's companion policy is off
+}
+
+// Engine prices the seats of showtimes. Every price shown or charged for a
+// seat comes from it, so the seat map and the hold always agree.
+type Engine interface {
+	// Quote prices the seat types of a showtime. The showtime needs its
+	// cinema and screen loaded.
+	Quote(ctx context.Context, showtime *entity.Showtime) (*Quote, error)
+}
+
+// SeatTypeMultipliers returns the default multipliers overridden by the
+// configured ones, keyed by seat type in any case
+func SeatTypeMultipliers(configured map[string]float64) map[entity.SeatType]float64 {
+	multipliers := make(map[entity.SeatType]float64, len(defaultSeatTypeMultipliers))
+	for seatType, multiplier := range defaultSeatTypeMultipliers {
+		multipliers[seatType] = multiplier
+	}
+	for seatType, multiplier := range configured {
+		multipliers[entity.SeatType(strings.ToUpper(seatType))] = multiplier
+	}
+	return multipliers
+}
+
+// Quote is the price of each seat type of one showtime
+type Quote struct {
+	base        float64
+	multipliers map[entity.SeatType]float64
+	rules       []quoteRule // rules that match the showtime
+}
+
+type quoteRule struct {
+	rule *entity.PricingRule
+	cond entity.PricingCondition
+}
+
+// Price returns the price of a seat of the given type
+func (q *Quote) Price(seatType entity.SeatType) float64 {
+	multiplier, ok := q.multipliers[seatType]
+	if !ok {
+		multiplier = 1.0
+	}
+
+	var applied []*entity.PricingRule
+	for _, r := range q.rules {
+		if r.cond.MatchesSeat(r.rule.RuleType, seatType) {
+			applied = append(applied, r.rule)
+		}
+	}
+	return entity.ApplyPricingRules(q.base*multiplier, entity.TopPriorityPricingRules(applied))
+}
+
+// RuleBasedEngine prices seats from the showtime base price, the seat type
+// and the cinema's pricing rules. Rules are cached per cinema in Redis and
+// read from Postgres when the cache misses or is unavailable.
+type RuleBasedEngine struct {
+	ruleRepo    repository.PricingRuleRepository
+	cache       repository.PricingRuleCache
+	cacheTTL    time.Duration
+	multipliers map[entity.SeatType]float64
+	logger      *logger.Logger
+}
+
+// NewRuleBasedEngine creates a new rule-based pricing engine
+func NewRuleBasedEngine(
+	ruleRepo repository.PricingRuleRepository,
+	cache repository.PricingRuleCache,
+	cacheTTL time.Duration,
+	multipliers map[entity.SeatType]float64,
+	logger *logger.Logger,
+) *RuleBasedEngine {
+	return &RuleBasedEngine{
+		ruleRepo:    ruleRepo,
+		cache:       cache,
+		cacheTTL:    cacheTTL,
+		multipliers: multipliers,
+		logger:      logger,
+	}
+}
+
+func (e *RuleBasedEngine) Quote(ctx context.Context, showtime *entity.Showtime) (*Quote, error) {
+	rules, err := e.rules(ctx, showtime.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{base: showtime.BasePrice, multipliers: e.multipliers}
+	for _, rule := range rules {
+		if !rule.AppliesTo(showtime) {
+			continue
+		}
+		cond, err := rule.Condition()
+		if err != nil {
+			// Conditions are validated on create, so this is a bad row
+			e.logger.WithContext(ctx).Warn("skipping pricing rule with invalid condition",
+				zap.String("rule_id", rule.ID.String()), zap.Error(err))
+			continue
+		}
+		if cond.MatchesShowtime(rule.RuleType, showtime) {
+			quote.rules = append(quote.rules, quoteRule{rule: rule, cond: cond})
+		}
+	}
+	return quote, nil
+}
+
+// Warm caches a cinema's rules
+func (e *RuleBasedEngine) Warm(ctx context.Context, cinemaID uuid.UUID) error {
+	rules, err := e.ruleRepo.ListByCinema(ctx, cinemaID)
+	if err != nil {
+		return err
+	}
+	return e.cache.Set(ctx, cinemaID, rules, e.cacheTTL)
+}
+
+// Invalidate drops a cinema's cached rules after they changed
+func (e *RuleBasedEngine) Invalidate(ctx context.Context, cinemaID uuid.UUID) {
+	if err := e.cache.Invalidate(ctx, cinemaID); err != nil {
+		e.logger.WithContext(ctx).Warn("failed to invalidate cached pricing rules",
+			zap.String("cinema_id", cinemaID.String()), zap.Error(err))
+	}
+}
+
+// rules returns a cinema's rules from the cache, loading them on a miss.
+// Cache errors only cost the round trip to Postgres.
+func (e *RuleBasedEngine) rules(ctx context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, error) {
+	rules, ok, err := e.cache.Get(ctx, cinemaID)
+	if err == nil && ok {
+		return rules, nil
+	}
+
+	rules, err = e.ruleRepo.ListByCinema(ctx, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.cache.Set(ctx, cinemaID, rules, e.cacheTTL); err != nil {
+		e.logger.WithContext(ctx).Debug("failed to cache pricing rules", zap.Error(err))
+	}
+	return rules, nil
+}
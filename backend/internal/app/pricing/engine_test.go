@@ -0,0 +1,255 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memRules keeps pricing rules per cinema and counts reads
+type memRules struct {
+	repository.PricingRuleRepository
+	rules map[uuid.UUID][]*entity.PricingRule
+	reads int
+}
+
+func (m *memRules) ListByCinema(_ context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, error) {
+	m.reads++
+	return m.rules[cinemaID], nil
+}
+
+func (m *memRules) GetByID(_ context.Context, cinemaID, id uuid.UUID) (*entity.PricingRule, error) {
+	for _, rule := range m.rules[cinemaID] {
+		if rule.ID == id {
+			copied := *rule
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("pricing rule")
+}
+
+func (m *memRules) Update(_ context.Context, rule *entity.PricingRule) error {
+	for i, r := range m.rules[rule.CinemaID] {
+		if r.ID == rule.ID {
+			m.rules[rule.CinemaID][i] = rule
+		}
+	}
+	return nil
+}
+
+// memCache caches rules per cinema; down makes every call fail the way an
+// unreachable Redis does
+type memCache struct {
+	rules map[uuid.UUID][]*entity.PricingRule
+	down  bool
+}
+
+var errCacheDown = errors.New("cache is down")
+
+func (m *memCache) Get(_ context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, bool, error) {
+	if m.down {
+		return nil, false, errCacheDown
+	}
+	rules, ok := m.rules[cinemaID]
+	return rules, ok, nil
+}
+
+func (m *memCache) Set(_ context.Context, cinemaID uuid.UUID, rules []*entity.PricingRule, _ time.Duration) error {
+	if m.down {
+		return errCacheDown
+	}
+	m.rules[cinemaID] = rules
+	return nil
+}
+
+func (m *memCache) Invalidate(_ context.Context, cinemaID uuid.UUID) error {
+	if m.down {
+		return errCacheDown
+	}
+	delete(m.rules, cinemaID)
+	return nil
+}
+
+func TestQuote(t *testing.T) {
+	cinemaID := uuid.New()
+	repo := &memRules{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {
+		{ID: uuid.New(), RuleType: entity.PricingRuleDayOfWeek, ModifierType: entity.PricingModifierPercentage, ModifierValue: 20, ConditionJSON: `{"days":["SAT"]}`},
+		{ID: uuid.New(), RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat, ModifierValue: 3, ConditionJSON: `{"seat_types":["VIP"]}`},
+		{ID: uuid.New(), RuleType: entity.PricingRuleTimeOfDay, ModifierType: entity.PricingModifierPercentage, ModifierValue: -50, ConditionJSON: `{"from":"10:00","to":"14:00"}`},
+		// A bad row is skipped, not fatal
+		{ID: uuid.New(), RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat, ModifierValue: 100, ConditionJSON: `{"seat_types":`},
+	}}}
+	engine := NewRuleBasedEngine(repo, &memCache{rules: map[uuid.UUID][]*entity.PricingRule{}}, time.Minute, SeatTypeMultipliers(nil), &logger.Logger{Logger: zap.NewNop()})
+
+	// Saturday evening: the weekend rule applies, the matinee one does not
+	showtime := &entity.Showtime{
+		CinemaID:  cinemaID,
+		ShowDate:  time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		StartTime: "20:00",
+		BasePrice: 10,
+	}
+	quote, err := engine.Quote(context.Background(), showtime)
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+
+	tests := []struct {
+		seatType entity.SeatType
+		want     float64
+	}{
+		{entity.SeatStandard, 12},
+		{entity.SeatPremium, 18},
+		{entity.SeatVIP, 27}, // 10 * 2.0 * 1.2 + 3
+		{"UNKNOWN", 12},
+	}
+	for _, tt := range tests {
+		if got := quote.Price(tt.seatType); got != tt.want {
+			t.Errorf("%s: price = %v, want %v", tt.seatType, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteCachesRules(t *testing.T) {
+	ctx := context.Background()
+	cinemaID := uuid.New()
+	repo := &memRules{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {
+		{ID: uuid.New(), RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat, ModifierValue: 1, ConditionJSON: `{"seat_types":["STANDARD"]}`},
+	}}}
+	cache := &memCache{rules: map[uuid.UUID][]*entity.PricingRule{}}
+	engine := NewRuleBasedEngine(repo, cache, time.Minute, SeatTypeMultipliers(nil), &logger.Logger{Logger: zap.NewNop()})
+	showtime := &entity.Showtime{CinemaID: cinemaID, ShowDate: time.Now(), StartTime: "20:00", BasePrice: 10}
+
+	price := func() float64 {
+		t.Helper()
+		quote, err := engine.Quote(ctx, showtime)
+		if err != nil {
+			t.Fatalf("Quote: %v", err)
+		}
+		return quote.Price(entity.SeatStandard)
+	}
+
+	price()
+	price()
+	if repo.reads != 1 {
+		t.Errorf("%d reads from Postgres, want 1 with a warm cache", repo.reads)
+	}
+
+	// A new rule shows once the cinema's entry is invalidated
+	repo.rules[cinemaID] = append(repo.rules[cinemaID], &entity.PricingRule{ID: uuid.New(),
+		RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat, ModifierValue: 1, ConditionJSON: `{"seat_types":["STANDARD"]}`})
+	engine.Invalidate(ctx, cinemaID)
+	if got := price(); got != 12 {
+		t.Errorf("price after the invalidation = %v, want 12", got)
+	}
+
+	// Without the cache, every quote reads the rules from Postgres
+	cache.down = true
+	reads := repo.reads
+	if got := price(); got != 12 {
+		t.Errorf("price with the cache down = %v, want 12", got)
+	}
+	if repo.reads != reads+1 {
+		t.Errorf("the rules were not read from Postgres with the cache down")
+	}
+}
+
+func TestQuoteScopesRules(t *testing.T) {
+	cinemaID := uuid.New()
+	imax := entity.ScreenIMAX
+	opening := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	closing := opening.AddDate(0, 0, 7)
+	repo := &memRules{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {
+		{ID: uuid.New(), RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat, ModifierValue: 5,
+			ConditionJSON: `{"seat_types":["STANDARD"]}`, ScreenType: &imax},
+		{ID: uuid.New(), RuleType: entity.PricingRuleDayOfWeek, ModifierType: entity.PricingModifierPercentage, ModifierValue: -50,
+			ConditionJSON: `{"days":["MON","TUE","WED","THU","FRI","SAT","SUN"]}`, ValidFrom: &opening, ValidUntil: &closing},
+	}}}
+	engine := NewRuleBasedEngine(repo, &memCache{rules: map[uuid.UUID][]*entity.PricingRule{}}, time.Minute,
+		SeatTypeMultipliers(nil), &logger.Logger{Logger: zap.NewNop()})
+
+	tests := []struct {
+		name   string
+		screen entity.ScreenType
+		day    time.Time
+		want   float64
+	}{
+		{"IMAX in the opening week", entity.ScreenIMAX, opening.AddDate(0, 0, 2), 10},
+		{"IMAX after the opening week", entity.ScreenIMAX, closing, 15},
+		{"standard screen in the opening week", entity.ScreenStandard, opening, 5},
+		{"standard screen before the opening week", entity.ScreenStandard, opening.AddDate(0, 0, -1), 10},
+	}
+	for _, tt := range tests {
+		showtime := &entity.Showtime{
+			CinemaID:  cinemaID,
+			ShowDate:  tt.day,
+			StartTime: "20:00",
+			BasePrice: 10,
+			Cinema:    entity.Cinema{Timezone: "UTC"},
+			Screen:    entity.Screen{ScreenType: tt.screen},
+		}
+		quote, err := engine.Quote(context.Background(), showtime)
+		if err != nil {
+			t.Fatalf("%s: Quote: %v", tt.name, err)
+		}
+		if got := quote.Price(entity.SeatStandard); got != tt.want {
+			t.Errorf("%s: price = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuotePriority(t *testing.T) {
+	cinemaID := uuid.New()
+	rule := func(priority int, value float64, seatTypes string) *entity.PricingRule {
+		return &entity.PricingRule{ID: uuid.New(), RuleType: entity.PricingRuleSeatType, ModifierType: entity.PricingModifierFlat,
+			ModifierValue: value, ConditionJSON: `{"seat_types":` + seatTypes + `}`, Priority: priority}
+	}
+	repo := &memRules{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {
+		rule(0, 2, `["STANDARD","VIP"]`),
+		rule(0, 1, `["STANDARD","VIP"]`),
+		// Overrides both for VIP seats only
+		rule(10, 4, `["VIP"]`),
+		// Other types are not affected by the priority of seat type rules
+		{ID: uuid.New(), RuleType: entity.PricingRuleTimeOfDay, ModifierType: entity.PricingModifierFlat, ModifierValue: 1,
+			ConditionJSON: `{"from":"18:00","to":"23:00"}`, Priority: -5},
+	}}}
+	engine := NewRuleBasedEngine(repo, &memCache{rules: map[uuid.UUID][]*entity.PricingRule{}}, time.Minute,
+		SeatTypeMultipliers(map[string]float64{"vip": 1}), &logger.Logger{Logger: zap.NewNop()})
+
+	quote, err := engine.Quote(context.Background(), &entity.Showtime{CinemaID: cinemaID, ShowDate: time.Now(), StartTime: "20:00", BasePrice: 10})
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if got := quote.Price(entity.SeatStandard); got != 14 {
+		t.Errorf("standard price = %v, want 14 with both priority 0 rules", got)
+	}
+	if got := quote.Price(entity.SeatVIP); got != 15 {
+		t.Errorf("VIP price = %v, want 15 with the priority 10 rule only", got)
+	}
+}
+
+func TestSeatTypeMultipliers(t *testing.T) {
+	got := SeatTypeMultipliers(map[string]float64{"premium": 1.25, "Balcony": 1.1})
+	want := map[entity.SeatType]float64{
+		entity.SeatStandard: 1.0,
+		entity.SeatPremium:  1.25,
+		entity.SeatVIP:      defaultSeatTypeMultipliers[entity.SeatVIP],
+		"BALCONY":           1.1,
+	}
+	for seatType, multiplier := range want {
+		if got[seatType] != multiplier {
+			t.Errorf("%s = %v, want %v", seatType, got[seatType], multiplier)
+		}
+	}
+	if defaultSeatTypeMultipliers[entity.SeatPremium] != 1.5 {
+		t.Error("the defaults were changed")
+	}
+}
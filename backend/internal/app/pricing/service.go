@@ -0,0 +1,172 @@
+package pricing
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service manages cinemas' pricing rules
+type Service struct {
+	ruleRepo   repository.PricingRuleRepository
+	cinemaRepo repository.CinemaRepository
+	engine     *RuleBasedEngine
+	logger     *logger.Logger
+}
+
+// NewService creates a new pricing rule service
+func NewService(
+	ruleRepo repository.PricingRuleRepository,
+	cinemaRepo repository.CinemaRepository,
+	engine *RuleBasedEngine,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		ruleRepo:   ruleRepo,
+		cinemaRepo: cinemaRepo,
+		engine:     engine,
+		logger:     logger,
+	}
+}
+
+// ListRules returns a cinema's pricing rules
+func (s *Service) ListRules(ctx context.Context, cinemaID uuid.UUID) ([]PricingRuleResponse, error) {
+	if _, err := s.cinemaRepo.GetByID(ctx, cinemaID); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.ListByCinema(ctx, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]PricingRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		responses = append(responses, toPricingRuleResponse(rule))
+	}
+	return responses, nil
+}
+
+// CreateRule adds a pricing rule to a cinema. It applies to seats priced
+// from then on; seats already held keep their price.
+func (s *Service) CreateRule(ctx context.Context, cinemaID uuid.UUID, req CreatePricingRuleRequest) (*PricingRuleResponse, error) {
+	if _, err := s.cinemaRepo.GetByID(ctx, cinemaID); err != nil {
+		return nil, err
+	}
+
+	rule := &entity.PricingRule{
+		CinemaID:      cinemaID,
+		RuleType:      entity.PricingRuleType(req.RuleType),
+		ModifierType:  entity.PricingModifierType(req.ModifierType),
+		ModifierValue: req.ModifierValue,
+		ConditionJSON: string(req.ConditionJSON),
+		Priority:      req.Priority,
+		ValidFrom:     req.ValidFrom,
+		ValidUntil:    req.ValidUntil,
+	}
+	if req.ScreenType != "" {
+		screenType := entity.ScreenType(req.ScreenType)
+		rule.ScreenType = &screenType
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, apperrors.ErrBadRequest(err.Error())
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.engine.Invalidate(ctx, cinemaID)
+
+	s.logger.WithContext(ctx).Info("pricing rule created",
+		zap.String("cinema_id", cinemaID.String()),
+		zap.String("rule_id", rule.ID.String()),
+		zap.String("rule_type", string(rule.RuleType)),
+	)
+	res := toPricingRuleResponse(rule)
+	return &res, nil
+}
+
+// GetRule returns one of a cinema's pricing rules
+func (s *Service) GetRule(ctx context.Context, cinemaID, ruleID uuid.UUID) (*PricingRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, cinemaID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	res := toPricingRuleResponse(rule)
+	return &res, nil
+}
+
+// UpdateRule changes one of a cinema's pricing rules. Like a new rule, the
+// change applies to seats priced from then on.
+func (s *Service) UpdateRule(ctx context.Context, cinemaID, ruleID uuid.UUID, req UpdatePricingRuleRequest) (*PricingRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, cinemaID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ModifierType != "" {
+		rule.ModifierType = entity.PricingModifierType(req.ModifierType)
+	}
+	if req.ModifierValue != nil {
+		rule.ModifierValue = *req.ModifierValue
+	}
+	if len(req.ConditionJSON) > 0 {
+		rule.ConditionJSON = string(req.ConditionJSON)
+	}
+	if req.ClearScreenType {
+		rule.ScreenType = nil
+	}
+	if req.ScreenType != "" {
+		screenType := entity.ScreenType(req.ScreenType)
+		rule.ScreenType = &screenType
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.ClearValidFrom {
+		rule.ValidFrom = nil
+	}
+	if req.ValidFrom != nil {
+		rule.ValidFrom = req.ValidFrom
+	}
+	if req.ClearValidUntil {
+		rule.ValidUntil = nil
+	}
+	if req.ValidUntil != nil {
+		rule.ValidUntil = req.ValidUntil
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, apperrors.ErrBadRequest(err.Error())
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.engine.Invalidate(ctx, cinemaID)
+
+	s.logger.WithContext(ctx).Info("pricing rule updated",
+		zap.String("cinema_id", cinemaID.String()),
+		zap.String("rule_id", rule.ID.String()),
+	)
+	res := toPricingRuleResponse(rule)
+	return &res, nil
+}
+
+// DeleteRule removes one of a cinema's pricing rules
+func (s *Service) DeleteRule(ctx context.Context, cinemaID, ruleID uuid.UUID) error {
+	if err := s.ruleRepo.Delete(ctx, cinemaID, ruleID); err != nil {
+		return err
+	}
+	s.engine.Invalidate(ctx, cinemaID)
+
+	s.logger.WithContext(ctx).Info("pricing rule deleted",
+		zap.String("cinema_id", cinemaID.String()),
+		zap.String("rule_id", ruleID.String()),
+	)
+	return nil
+}
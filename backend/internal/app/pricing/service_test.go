@@ -0,0 +1,66 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestUpdateRule(t *testing.T) {
+	ctx := context.Background()
+	cinemaID := uuid.New()
+	imax := entity.ScreenIMAX
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	rule := &entity.PricingRule{ID: uuid.New(), CinemaID: cinemaID, RuleType: entity.PricingRuleSeatType,
+		ModifierType: entity.PricingModifierFlat, ModifierValue: 2, ConditionJSON: `{"seat_types":["VIP"]}`,
+		ScreenType: &imax, Priority: 1, ValidFrom: &from}
+	repo := &memRules{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {rule}}}
+	cache := &memCache{rules: map[uuid.UUID][]*entity.PricingRule{cinemaID: {rule}}}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	svc := NewService(repo, nil, NewRuleBasedEngine(repo, cache, time.Minute, SeatTypeMultipliers(nil), log), log)
+
+	// Omitted fields are kept
+	value, priority := 3.5, 5
+	res, err := svc.UpdateRule(ctx, cinemaID, rule.ID, UpdatePricingRuleRequest{ModifierValue: &value, Priority: &priority})
+	if err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	if res.ModifierValue != 3.5 || res.Priority != 5 || res.ScreenType == nil || *res.ScreenType != "IMAX" ||
+		res.ValidFrom == nil || res.ModifierType != string(entity.PricingModifierFlat) {
+		t.Errorf("updated rule = %+v", res)
+	}
+	if _, ok := cache.rules[cinemaID]; ok {
+		t.Error("the cinema's cached rules were not invalidated")
+	}
+
+	// Optional fields are cleared with their flags
+	res, err = svc.UpdateRule(ctx, cinemaID, rule.ID, UpdatePricingRuleRequest{ClearScreenType: true, ClearValidFrom: true})
+	if err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	if res.ScreenType != nil || res.ValidFrom != nil {
+		t.Errorf("cleared rule = %+v", res)
+	}
+
+	// An invalid change is refused and not saved
+	until := from.AddDate(0, 0, -1)
+	_, err = svc.UpdateRule(ctx, cinemaID, rule.ID, UpdatePricingRuleRequest{ValidFrom: &from, ValidUntil: &until})
+	if !apperrors.Is(err, apperrors.CodeBadRequest) {
+		t.Errorf("inverted window: err = %v, want %s", err, apperrors.CodeBadRequest)
+	}
+	if saved, _ := repo.GetByID(ctx, cinemaID, rule.ID); saved.ValidFrom != nil || saved.ValidUntil != nil {
+		t.Errorf("the invalid change was saved: %+v", saved)
+	}
+
+	// Another cinema's rule is not found
+	if _, err := svc.UpdateRule(ctx, uuid.New(), rule.ID, UpdatePricingRuleRequest{}); !apperrors.Is(err, apperrors.CodeNotFound) {
+		t.Errorf("other cinema: err = %v, want %s", err, apperrors.CodeNotFound)
+	}
+}
@@ -0,0 +1,112 @@
+package promo
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreatePromoCodeRequest represents a request to create a promo code
+type CreatePromoCodeRequest struct {
+	Code         string  `json:"code" validate:"required,alphanum,min=3,max=32"`
+	Description  *string `json:"description" validate:"omitempty,max=500"`
+	DiscountType string  `json:"discount_type" validate:"required,oneof=PERCENTAGE FIXED"`
+	// DiscountValue is a percent for PERCENTAGE and an amount for FIXED
+	DiscountValue     float64   `json:"discount_value" validate:"gt=0"`
+	MaxDiscount       *float64  `json:"max_discount" validate:"omitempty,gt=0"`
+	MinPurchase       *float64  `json:"min_purchase" validate:"omitempty,min=0"`
+	UsageLimit        *int      `json:"usage_limit" validate:"omitempty,min=1"`
+	UsageLimitPerUser *int      `json:"usage_limit_per_user" validate:"omitempty,min=1"`
+	ValidFrom         time.Time `json:"valid_from" validate:"required"`
+	ValidUntil        time.Time `json:"valid_until" validate:"required"`
+	IsActive          *bool     `json:"is_active"` // defaults to true
+}
+
+// UpdatePromoCodeRequest represents a request to update a promo code.
+// Omitted fields keep their current value; the code itself cannot change.
+type UpdatePromoCodeRequest struct {
+	Description            *string    `json:"description" validate:"omitempty,max=500"`
+	DiscountType           string     `json:"discount_type" validate:"omitempty,oneof=PERCENTAGE FIXED"`
+	DiscountValue          *float64   `json:"discount_value" validate:"omitempty,gt=0"`
+	MaxDiscount            *float64   `json:"max_discount" validate:"omitempty,gt=0"`
+	MinPurchase            *float64   `json:"min_purchase" validate:"omitempty,min=0"`
+	UsageLimit             *int       `json:"usage_limit" validate:"omitempty,min=1"`
+	UsageLimitPerUser      *int       `json:"usage_limit_per_user" validate:"omitempty,min=1"`
+	ValidFrom              *time.Time `json:"valid_from"`
+	ValidUntil             *time.Time `json:"valid_until"`
+	IsActive               *bool      `json:"is_active"`
+	ClearMaxDiscount       bool       `json:"clear_max_discount"`
+	ClearMinPurchase       bool       `json:"clear_min_purchase"`
+	ClearUsageLimit        bool       `json:"clear_usage_limit"`
+	ClearUsageLimitPerUser bool       `json:"clear_usage_limit_per_user"`
+}
+
+// PromoCodeListParams represents query parameters for listing promo codes
+type PromoCodeListParams struct {
+	Page       int    `form:"page,default=1"`
+	Limit      int    `form:"limit,default=20"`
+	ActiveOnly bool   `form:"active_only"`
+	Code       string `form:"code" validate:"omitempty,max=32"` // looks up a single code
+}
+
+// PromoCodeStatsParams represents query parameters for a promo code's stats
+type PromoCodeStatsParams struct {
+	UserID string `form:"user_id" validate:"omitempty,uuid"` // also count this user's uses
+}
+
+// PromoCodeResponse represents a promo code in admin responses
+type PromoCodeResponse struct {
+	ID                uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	Code              string    `json:"code"`
+	Description       *string   `json:"description,omitempty"`
+	DiscountType      string    `json:"discount_type"`
+	DiscountValue     float64   `json:"discount_value"`
+	MaxDiscount       *float64  `json:"max_discount,omitempty"`
+	MinPurchase       *float64  `json:"min_purchase,omitempty"`
+	UsageLimit        *int      `json:"usage_limit,omitempty"`
+	UsageCount        int       `json:"usage_count"`
+	UsageLimitPerUser *int      `json:"usage_limit_per_user,omitempty"`
+	ValidFrom         time.Time `json:"valid_from"`
+	ValidUntil        time.Time `json:"valid_until"`
+	IsActive          bool      `json:"is_active"`
+	Valid             bool      `json:"valid"` // can be applied right now
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PromoCodeStatsResponse represents a promo code's usage
+type PromoCodeStatsResponse struct {
+	PromoCodeID uuid.UUID `json:"promo_code_id" swaggertype:"string" format:"uuid"`
+	Code        string    `json:"code"`
+	// UsageCount counts the code's redemptions, pending bookings included
+	UsageCount int  `json:"usage_count"`
+	UsageLimit *int `json:"usage_limit,omitempty"`
+	// Bookings and TotalDiscount cover confirmed and completed bookings
+	Bookings      int64      `json:"bookings"`
+	TotalDiscount float64    `json:"total_discount"`
+	UserID        *uuid.UUID `json:"user_id,omitempty" swaggertype:"string" format:"uuid"`
+	UserUses      *int       `json:"user_uses,omitempty"`
+}
+
+func toPromoCodeResponse(promo *entity.PromoCode) *PromoCodeResponse {
+	return &PromoCodeResponse{
+		ID:                promo.ID,
+		Code:              promo.Code,
+		Description:       promo.Description,
+		DiscountType:      promo.DiscountType,
+		DiscountValue:     promo.DiscountValue,
+		MaxDiscount:       promo.MaxDiscount,
+		MinPurchase:       promo.MinPurchase,
+		UsageLimit:        promo.UsageLimit,
+		UsageCount:        promo.UsageCount,
+		UsageLimitPerUser: promo.UsageLimitPerUser,
+		ValidFrom:         promo.ValidFrom,
+		ValidUntil:        promo.ValidUntil,
+		IsActive:          promo.IsActive,
+		Valid:             promo.IsValid(),
+		CreatedAt:         promo.CreatedAt,
+		UpdatedAt:         promo.UpdatedAt,
+	}
+}
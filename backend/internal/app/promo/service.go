@@ -0,0 +1,238 @@
+package promo
+
+import (
+	"context"
+	"strings"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service manages promo codes for admins. Customers apply codes through
+// the booking service.
+type Service struct {
+	promoRepo repository.PromoCodeRepository
+	logger    *logger.Logger
+}
+
+// NewService creates a new promo code service
+func NewService(promoRepo repository.PromoCodeRepository, logger *logger.Logger) *Service {
+	return &Service{
+		promoRepo: promoRepo,
+		logger:    logger,
+	}
+}
+
+// CreatePromoCode creates a promo code. Codes are stored uppercase and are
+// unique case-insensitively, deleted codes included, so a code printed on
+// old vouchers never starts meaning something else.
+func (s *Service) CreatePromoCode(ctx context.Context, req CreatePromoCodeRequest) (*PromoCodeResponse, error) {
+	promo := &entity.PromoCode{
+		Code:              strings.ToUpper(req.Code),
+		Description:       req.Description,
+		DiscountType:      req.DiscountType,
+		DiscountValue:     req.DiscountValue,
+		MaxDiscount:       req.MaxDiscount,
+		MinPurchase:       req.MinPurchase,
+		UsageLimit:        req.UsageLimit,
+		UsageLimitPerUser: req.UsageLimitPerUser,
+		ValidFrom:         req.ValidFrom,
+		ValidUntil:        req.ValidUntil,
+		IsActive:          req.IsActive == nil || *req.IsActive,
+	}
+	if err := promo.Validate(); err != nil {
+		return nil, apperrors.ErrValidation(err.Error())
+	}
+
+	exists, err := s.promoRepo.CodeExists(ctx, promo.Code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperrors.ErrConflict("promo code already exists")
+	}
+
+	active := promo.IsActive
+	if err := s.promoRepo.Create(ctx, promo); err != nil {
+		return nil, err
+	}
+	// GORM writes the column default over a false is_active on create, so
+	// an inactive code is saved again
+	if !active {
+		promo.IsActive = false
+		if err := s.promoRepo.Update(ctx, promo); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("promo code created",
+		zap.String("promo_code_id", promo.ID.String()),
+		zap.String("code", promo.Code),
+	)
+
+	return toPromoCodeResponse(promo), nil
+}
+
+// GetPromoCode retrieves a promo code
+func (s *Service) GetPromoCode(ctx context.Context, id uuid.UUID) (*PromoCodeResponse, error) {
+	promo, err := s.promoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toPromoCodeResponse(promo), nil
+}
+
+// GetPromoCodeByCode retrieves a promo code by its code
+func (s *Service) GetPromoCodeByCode(ctx context.Context, code string) (*PromoCodeResponse, error) {
+	promo, err := s.promoRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return toPromoCodeResponse(promo), nil
+}
+
+// UpdatePromoCode updates a promo code. Bookings already made keep the
+// discount they were given.
+func (s *Service) UpdatePromoCode(ctx context.Context, id uuid.UUID, req UpdatePromoCodeRequest) (*PromoCodeResponse, error) {
+	promo, err := s.promoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		promo.Description = req.Description
+	}
+	if req.DiscountType != "" {
+		promo.DiscountType = req.DiscountType
+	}
+	if req.DiscountValue != nil {
+		promo.DiscountValue = *req.DiscountValue
+	}
+	if req.ClearMaxDiscount {
+		promo.MaxDiscount = nil
+	}
+	if req.MaxDiscount != nil {
+		promo.MaxDiscount = req.MaxDiscount
+	}
+	if req.ClearMinPurchase {
+		promo.MinPurchase = nil
+	}
+	if req.MinPurchase != nil {
+		promo.MinPurchase = req.MinPurchase
+	}
+	if req.ClearUsageLimit {
+		promo.UsageLimit = nil
+	}
+	if req.UsageLimit != nil {
+		promo.UsageLimit = req.UsageLimit
+	}
+	if req.ClearUsageLimitPerUser {
+		promo.UsageLimitPerUser = nil
+	}
+	if req.UsageLimitPerUser != nil {
+		promo.UsageLimitPerUser = req.UsageLimitPerUser
+	}
+	if req.ValidFrom != nil {
+		promo.ValidFrom = *req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		promo.ValidUntil = *req.ValidUntil
+	}
+	if req.IsActive != nil {
+		promo.IsActive = *req.IsActive
+	}
+
+	if err := promo.Validate(); err != nil {
+		return nil, apperrors.ErrValidation(err.Error())
+	}
+
+	if err := s.promoRepo.Update(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("promo code updated",
+		zap.String("promo_code_id", promo.ID.String()),
+		zap.String("code", promo.Code),
+	)
+
+	return toPromoCodeResponse(promo), nil
+}
+
+// DeletePromoCode deletes a promo code. Bookings made with it keep their
+// discount.
+func (s *Service) DeletePromoCode(ctx context.Context, id uuid.UUID) error {
+	if err := s.promoRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).Info("promo code deleted", zap.String("promo_code_id", id.String()))
+	return nil
+}
+
+// ListPromoCodes lists promo codes, newest first. A code in the params
+// looks up that code alone.
+func (s *Service) ListPromoCodes(ctx context.Context, params PromoCodeListParams) ([]*PromoCodeResponse, int64, error) {
+	if params.Code != "" {
+		promo, err := s.promoRepo.GetByCode(ctx, params.Code)
+		if apperrors.Is(err, apperrors.CodeNotFound) {
+			return []*PromoCodeResponse{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if params.ActiveOnly && !promo.IsActive {
+			return []*PromoCodeResponse{}, 0, nil
+		}
+		return []*PromoCodeResponse{toPromoCodeResponse(promo)}, 1, nil
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	promos, total, err := s.promoRepo.List(ctx, params.ActiveOnly, offset, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*PromoCodeResponse, len(promos))
+	for i, promo := range promos {
+		responses[i] = toPromoCodeResponse(promo)
+	}
+	return responses, total, nil
+}
+
+// GetStats returns how often a promo code was used and the discount it
+// gave. With a user ID it also counts that user's uses, as checked against
+// the code's per-user limit.
+func (s *Service) GetStats(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*PromoCodeStatsResponse, error) {
+	promo, err := s.promoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.promoRepo.GetStats(ctx, promo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PromoCodeStatsResponse{
+		PromoCodeID:   promo.ID,
+		Code:          promo.Code,
+		UsageCount:    promo.UsageCount,
+		UsageLimit:    promo.UsageLimit,
+		Bookings:      stats.Bookings,
+		TotalDiscount: stats.TotalDiscount,
+	}
+	if userID != nil {
+		uses, err := s.promoRepo.GetUserUsageCount(ctx, promo.ID, userID, "")
+		if err != nil {
+			return nil, err
+		}
+		res.UserID = userID
+		res.UserUses = &uses
+	}
+	return res, nil
+}
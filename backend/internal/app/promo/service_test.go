@@ -0,0 +1,243 @@
+package promo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memPromos keeps promo codes in memory. Deleted codes are kept, as the
+// soft delete of the Postgres repository does.
+type memPromos struct {
+	repository.PromoCodeRepository
+	promos  []*entity.PromoCode
+	deleted map[uuid.UUID]bool
+	stats   repository.PromoCodeStats
+	uses    map[uuid.UUID]int // by user
+}
+
+func (m *memPromos) Create(_ context.Context, promo *entity.PromoCode) error {
+	promo.ID = uuid.New()
+	// The column defaults to true, so GORM never writes a false is_active
+	// on create
+	promo.IsActive = true
+	copied := *promo
+	m.promos = append(m.promos, &copied)
+	return nil
+}
+
+func (m *memPromos) GetByID(_ context.Context, id uuid.UUID) (*entity.PromoCode, error) {
+	for _, promo := range m.promos {
+		if promo.ID == id && !m.deleted[id] {
+			copied := *promo
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("promo code")
+}
+
+func (m *memPromos) GetByCode(_ context.Context, code string) (*entity.PromoCode, error) {
+	for _, promo := range m.promos {
+		if strings.EqualFold(promo.Code, code) && !m.deleted[promo.ID] {
+			copied := *promo
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("promo code")
+}
+
+func (m *memPromos) Update(_ context.Context, promo *entity.PromoCode) error {
+	for i, stored := range m.promos {
+		if stored.ID == promo.ID {
+			copied := *promo
+			m.promos[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (m *memPromos) Delete(_ context.Context, id uuid.UUID) error {
+	if m.deleted == nil {
+		m.deleted = make(map[uuid.UUID]bool)
+	}
+	m.deleted[id] = true
+	return nil
+}
+
+func (m *memPromos) CodeExists(_ context.Context, code string) (bool, error) {
+	for _, promo := range m.promos {
+		if strings.EqualFold(promo.Code, code) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memPromos) GetStats(context.Context, uuid.UUID) (*repository.PromoCodeStats, error) {
+	stats := m.stats
+	return &stats, nil
+}
+
+func (m *memPromos) GetUserUsageCount(_ context.Context, _ uuid.UUID, userID *uuid.UUID, _ string) (int, error) {
+	return m.uses[*userID], nil
+}
+
+func newPromoService() (*Service, *memPromos) {
+	promos := &memPromos{}
+	return NewService(promos, &logger.Logger{Logger: zap.NewNop()}), promos
+}
+
+func createRequest(code string) CreatePromoCodeRequest {
+	return CreatePromoCodeRequest{
+		Code:          code,
+		DiscountType:  "PERCENTAGE",
+		DiscountValue: 15,
+		ValidFrom:     time.Now().Add(-time.Hour),
+		ValidUntil:    time.Now().Add(24 * time.Hour),
+	}
+}
+
+func TestCreatePromoCode(t *testing.T) {
+	ctx := context.Background()
+	svc, promos := newPromoService()
+
+	created, err := svc.CreatePromoCode(ctx, createRequest("summer25"))
+	if err != nil {
+		t.Fatalf("CreatePromoCode: %v", err)
+	}
+	if created.Code != "SUMMER25" || !created.IsActive || !created.Valid {
+		t.Errorf("created %s (active %t, valid %t), want SUMMER25 active and valid", created.Code, created.IsActive, created.Valid)
+	}
+
+	if _, err := svc.CreatePromoCode(ctx, createRequest("Summer25")); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("same code in another case = %v, want a conflict", err)
+	}
+	if err := svc.DeletePromoCode(ctx, created.ID); err != nil {
+		t.Fatalf("DeletePromoCode: %v", err)
+	}
+	if _, err := svc.CreatePromoCode(ctx, createRequest("SUMMER25")); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("code of a deleted promo = %v, want a conflict", err)
+	}
+
+	inactive := false
+	req := createRequest("WINTER")
+	req.IsActive = &inactive
+	created, err = svc.CreatePromoCode(ctx, req)
+	if err != nil {
+		t.Fatalf("CreatePromoCode: %v", err)
+	}
+	stored, _ := promos.GetByID(ctx, created.ID)
+	if created.IsActive || stored.IsActive {
+		t.Errorf("inactive code saved active")
+	}
+
+	req = createRequest("TOOMUCH")
+	req.DiscountValue = 120
+	if _, err := svc.CreatePromoCode(ctx, req); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("120%% discount = %v, want a validation error", err)
+	}
+	if len(promos.promos) != 2 {
+		t.Errorf("%d codes stored, want 2", len(promos.promos))
+	}
+}
+
+func TestUpdatePromoCode(t *testing.T) {
+	ctx := context.Background()
+	svc, promos := newPromoService()
+	limit := 100
+	req := createRequest("SPRING")
+	req.UsageLimit = &limit
+	created, err := svc.CreatePromoCode(ctx, req)
+	if err != nil {
+		t.Fatalf("CreatePromoCode: %v", err)
+	}
+
+	value := 10.0
+	updated, err := svc.UpdatePromoCode(ctx, created.ID, UpdatePromoCodeRequest{
+		DiscountType:    "FIXED",
+		DiscountValue:   &value,
+		ClearUsageLimit: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePromoCode: %v", err)
+	}
+	if updated.Code != "SPRING" || updated.DiscountType != "FIXED" || updated.DiscountValue != 10 || updated.UsageLimit != nil {
+		t.Errorf("updated to %+v", updated)
+	}
+
+	// A window that ends before it starts is refused and nothing is saved
+	until := created.ValidFrom.Add(-time.Minute)
+	if _, err := svc.UpdatePromoCode(ctx, created.ID, UpdatePromoCodeRequest{ValidUntil: &until}); !apperrors.Is(err, apperrors.CodeValidation) {
+		t.Errorf("reversed window = %v, want a validation error", err)
+	}
+	if stored, _ := promos.GetByID(ctx, created.ID); !stored.ValidUntil.Equal(created.ValidUntil) {
+		t.Errorf("valid_until saved as %v", stored.ValidUntil)
+	}
+}
+
+func TestListPromoCodesByCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newPromoService()
+	inactive := false
+	req := createRequest("AUTUMN")
+	req.IsActive = &inactive
+	if _, err := svc.CreatePromoCode(ctx, req); err != nil {
+		t.Fatalf("CreatePromoCode: %v", err)
+	}
+
+	tests := []struct {
+		params PromoCodeListParams
+		want   int64
+	}{
+		{PromoCodeListParams{Code: "autumn"}, 1},
+		{PromoCodeListParams{Code: "AUTUMN", ActiveOnly: true}, 0},
+		{PromoCodeListParams{Code: "NOSUCH"}, 0},
+	}
+	for _, tt := range tests {
+		codes, total, err := svc.ListPromoCodes(ctx, tt.params)
+		if err != nil {
+			t.Fatalf("ListPromoCodes(%+v): %v", tt.params, err)
+		}
+		if total != tt.want || int64(len(codes)) != tt.want {
+			t.Errorf("ListPromoCodes(%+v) = %d codes, total %d, want %d", tt.params, len(codes), total, tt.want)
+		}
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	ctx := context.Background()
+	svc, promos := newPromoService()
+	created, err := svc.CreatePromoCode(ctx, createRequest("FAMILY"))
+	if err != nil {
+		t.Fatalf("CreatePromoCode: %v", err)
+	}
+	userID := uuid.New()
+	promos.promos[0].UsageCount = 4
+	promos.stats = repository.PromoCodeStats{Bookings: 3, TotalDiscount: 27.5}
+	promos.uses = map[uuid.UUID]int{userID: 2}
+
+	stats, err := svc.GetStats(ctx, created.ID, nil)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.UsageCount != 4 || stats.Bookings != 3 || stats.TotalDiscount != 27.5 || stats.UserUses != nil {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	stats, err = svc.GetStats(ctx, created.ID, &userID)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.UserUses == nil || *stats.UserUses != 2 {
+		t.Errorf("user uses = %v, want 2", stats.UserUses)
+	}
+}
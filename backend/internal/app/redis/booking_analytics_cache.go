@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// bookingAnalyticsKeyPrefix caches booking analytics reports per query
+	bookingAnalyticsKeyPrefix = "booking_analytics:"
+	// bookingStatsKeyPrefix caches booking stats per query
+	bookingStatsKeyPrefix = "booking_stats:"
+)
+
+// bookingAnalyticsCache implements repository.BookingAnalyticsCache
+type bookingAnalyticsCache struct {
+	client *Client
+}
+
+// NewBookingAnalyticsCache creates a new Redis-backed booking analytics cache
+func NewBookingAnalyticsCache(client *Client) repository.BookingAnalyticsCache {
+	return &bookingAnalyticsCache{client: client}
+}
+
+func (r *bookingAnalyticsCache) available() error {
+	if r.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "booking analytics cache is unavailable")
+	}
+	return nil
+}
+
+func (r *bookingAnalyticsCache) Get(ctx context.Context, key string) (*repository.BookingAnalytics, bool, error) {
+	return getCached[repository.BookingAnalytics](ctx, r, bookingAnalyticsKeyPrefix+key)
+}
+
+func (r *bookingAnalyticsCache) Set(ctx context.Context, key string, report *repository.BookingAnalytics, ttl time.Duration) error {
+	return r.set(ctx, bookingAnalyticsKeyPrefix+key, report, ttl)
+}
+
+func (r *bookingAnalyticsCache) GetStats(ctx context.Context, key string) (*repository.BookingStats, bool, error) {
+	return getCached[repository.BookingStats](ctx, r, bookingStatsKeyPrefix+key)
+}
+
+func (r *bookingAnalyticsCache) SetStats(ctx context.Context, key string, stats *repository.BookingStats, ttl time.Duration) error {
+	return r.set(ctx, bookingStatsKeyPrefix+key, stats, ttl)
+}
+
+func getCached[T any](ctx context.Context, r *bookingAnalyticsCache, key string) (*T, bool, error) {
+	if err := r.available(); err != nil {
+		return nil, false, err
+	}
+
+	data, err := r.client.GetClient().Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to read cached booking analytics")
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		// A corrupt entry is treated as a miss and overwritten
+		return nil, false, nil
+	}
+	return &value, true, nil
+}
+
+func (r *bookingAnalyticsCache) set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode booking analytics")
+	}
+	if err := r.client.GetClient().Set(ctx, key, data, ttl).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to cache booking analytics")
+	}
+	return nil
+}
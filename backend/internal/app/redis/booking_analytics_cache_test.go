@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+)
+
+func TestBookingAnalyticsCache(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	cache := NewBookingAnalyticsCache(client)
+
+	if _, ok, err := cache.Get(ctx, "q1"); ok || err != nil {
+		t.Fatalf("Get before Set = %t, %v, want a miss", ok, err)
+	}
+
+	report := &repository.BookingAnalytics{
+		TimeSeries: []repository.TimeBucket{{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), TotalBookings: 3, Revenue: 45}},
+		TopMovies:  []repository.MovieBookingStats{{Title: "Dune: Part Two", Tickets: 4}},
+	}
+	if err := cache.Set(ctx, "q1", report, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	cached, ok, err := cache.Get(ctx, "q1")
+	if err != nil || !ok {
+		t.Fatalf("Get = %t, %v, want a hit", ok, err)
+	}
+	if len(cached.TimeSeries) != 1 || !cached.TimeSeries[0].Date.Equal(report.TimeSeries[0].Date) ||
+		cached.TimeSeries[0].Revenue != 45 || cached.TopMovies[0].Title != "Dune: Part Two" {
+		t.Errorf("cached report = %+v", cached)
+	}
+
+	// A corrupt entry reads as a miss, so the report is rebuilt
+	srv.Set(bookingAnalyticsKeyPrefix+"q2", "{not json")
+	if _, ok, err := cache.Get(ctx, "q2"); ok || err != nil {
+		t.Errorf("corrupt entry = %t, %v, want a miss", ok, err)
+	}
+
+	srv.FastForward(time.Minute)
+	if _, ok, _ := cache.Get(ctx, "q1"); ok {
+		t.Errorf("report still cached after its TTL")
+	}
+
+	if _, _, err := NewBookingAnalyticsCache(nil).Get(ctx, "q1"); err == nil {
+		t.Errorf("Get without Redis succeeded")
+	}
+}
+
+func TestBookingStatsCache(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	cache := NewBookingAnalyticsCache(client)
+
+	stats := &repository.BookingStats{TotalBookings: 3, TotalRevenue: 25,
+		Daily: []repository.DailyBookingStats{{Date: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), TotalBookings: 3, Revenue: 25}}}
+	if err := cache.SetStats(ctx, "q1", stats, time.Minute); err != nil {
+		t.Fatalf("SetStats: %v", err)
+	}
+	got, ok, err := cache.GetStats(ctx, "q1")
+	if err != nil || !ok {
+		t.Fatalf("GetStats = %v, %v", ok, err)
+	}
+	if got.TotalBookings != 3 || got.TotalRevenue != 25 || len(got.Daily) != 1 || !got.Daily[0].Date.Equal(stats.Daily[0].Date) {
+		t.Errorf("cached stats = %+v", got)
+	}
+
+	// Stats and reports of the same query do not share an entry
+	if _, ok, _ := cache.Get(ctx, "q1"); ok {
+		t.Error("the stats were read back as a report")
+	}
+
+	// A corrupt entry is a miss, and entries expire with their TTL
+	srv.Set(bookingStatsKeyPrefix+"q2", "{not json")
+	if _, ok, err := cache.GetStats(ctx, "q2"); ok || err != nil {
+		t.Errorf("corrupt entry: GetStats = %v, %v, want a miss", ok, err)
+	}
+	srv.FastForward(time.Minute)
+	if _, ok, _ := cache.GetStats(ctx, "q1"); ok {
+		t.Error("stats still cached after their TTL")
+	}
+}
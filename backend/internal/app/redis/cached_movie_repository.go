@@ -0,0 +1,231 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// movieListKeyPrefix caches a page of a movie listing, keyed by the
+	// namespace version and a hash of the query
+	movieListKeyPrefix = "movies:list:"
+	// movieListVersionKey is bumped on every movie write, which moves every
+	// listing to new keys; the old pages expire on their own
+	movieListVersionKey = "movies:list:version"
+)
+
+// movieListQuery identifies a cached page; it is hashed into the key
+type movieListQuery struct {
+	Kind   string                 `json:"kind"`
+	Filter repository.MovieFilter `json:"filter"`
+	Offset int                    `json:"offset"`
+	Limit  int                    `json:"limit"`
+}
+
+// movieListPage is what is cached for a page
+type movieListPage struct {
+	Movies []*entity.Movie `json:"movies"`
+	Total  int64           `json:"total"`
+}
+
+// CachedMovieRepository wraps a movie repository and caches the pages of
+// movie listings in Redis. Every write through it drops all cached pages.
+// Without Redis, or when the cache fails, reads go to the wrapped
+// repository.
+type CachedMovieRepository struct {
+	repository.MovieRepository
+	client *Client
+	ttl    time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachedMovieRepository creates a movie repository caching listings for
+// ttl; a ttl of zero turns the cache off
+func NewCachedMovieRepository(next repository.MovieRepository, client *Client, ttl time.Duration) *CachedMovieRepository {
+	return &CachedMovieRepository{
+		MovieRepository: next,
+		client:          client,
+		ttl:             ttl,
+	}
+}
+
+// Stats returns how many listing reads were served from the cache and how
+// many went to the database since the server started
+func (r *CachedMovieRepository) Stats() (hits, misses int64) {
+	return r.hits.Load(), r.misses.Load()
+}
+
+func (r *CachedMovieRepository) enabled() bool {
+	return r.client != nil && r.ttl > 0
+}
+
+func (r *CachedMovieRepository) List(ctx context.Context, filter repository.MovieFilter, offset, limit int) ([]*entity.Movie, int64, error) {
+	// Admin listings with deleted movies are rare and must be current
+	if filter.IncludeDeleted {
+		return r.MovieRepository.List(ctx, filter, offset, limit)
+	}
+	query := movieListQuery{Kind: "all", Filter: filter, Offset: offset, Limit: limit}
+	return r.cached(ctx, query, func() ([]*entity.Movie, int64, error) {
+		return r.MovieRepository.List(ctx, filter, offset, limit)
+	})
+}
+
+func (r *CachedMovieRepository) GetNowShowing(ctx context.Context, cinemaID *uuid.UUID, offset, limit int) ([]*entity.Movie, int64, error) {
+	// Per cinema the listing follows its showtimes, which do not invalidate
+	// the cache, so only the full listing is cached
+	if cinemaID != nil {
+		return r.MovieRepository.GetNowShowing(ctx, cinemaID, offset, limit)
+	}
+	query := movieListQuery{Kind: "now_showing", Offset: offset, Limit: limit}
+	return r.cached(ctx, query, func() ([]*entity.Movie, int64, error) {
+		return r.MovieRepository.GetNowShowing(ctx, nil, offset, limit)
+	})
+}
+
+func (r *CachedMovieRepository) GetComingSoon(ctx context.Context, offset, limit int) ([]*entity.Movie, int64, error) {
+	query := movieListQuery{Kind: "coming_soon", Offset: offset, Limit: limit}
+	return r.cached(ctx, query, func() ([]*entity.Movie, int64, error) {
+		return r.MovieRepository.GetComingSoon(ctx, offset, limit)
+	})
+}
+
+func (r *CachedMovieRepository) Create(ctx context.Context, movie *entity.Movie) error {
+	if err := r.MovieRepository.Create(ctx, movie); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) Update(ctx context.Context, movie *entity.Movie) error {
+	if err := r.MovieRepository.Update(ctx, movie); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.MovieRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	if err := r.MovieRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) UpdatePopularityScore(ctx context.Context, id uuid.UUID, score float64) error {
+	if err := r.MovieRepository.UpdatePopularityScore(ctx, id, score); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) UpdatePopularityScores(ctx context.Context, scores map[uuid.UUID]float64) error {
+	if err := r.MovieRepository.UpdatePopularityScores(ctx, scores); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedMovieRepository) UpdateReviewStats(ctx context.Context, id uuid.UUID, rating *float64, count int64) error {
+	if err := r.MovieRepository.UpdateReviewStats(ctx, id, rating, count); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// cached serves a page from the cache, or loads and caches it
+func (r *CachedMovieRepository) cached(ctx context.Context, query movieListQuery, load func() ([]*entity.Movie, int64, error)) ([]*entity.Movie, int64, error) {
+	if !r.enabled() {
+		return load()
+	}
+
+	key, err := r.pageKey(ctx, query)
+	if err != nil {
+		r.client.logger.Warn("failed to read movie list cache version", zap.Error(err))
+		return load()
+	}
+
+	data, err := r.client.GetClient().Get(ctx, key).Bytes()
+	if err == nil {
+		var page movieListPage
+		if err := json.Unmarshal(data, &page); err == nil {
+			r.hits.Add(1)
+			return page.Movies, page.Total, nil
+		}
+		// A corrupt entry is treated as a miss and overwritten
+	} else if !errors.Is(err, redis.Nil) {
+		r.client.logger.Warn("failed to read cached movie list", zap.String("key", key), zap.Error(err))
+	}
+	r.misses.Add(1)
+
+	movies, total, err := load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err = json.Marshal(movieListPage{Movies: movies, Total: total})
+	if err == nil {
+		err = r.client.GetClient().Set(ctx, key, data, r.ttl).Err()
+	}
+	if err != nil {
+		r.client.logger.Warn("failed to cache movie list", zap.String("key", key), zap.Error(err))
+	}
+	return movies, total, nil
+}
+
+// pageKey returns the key of a page under the current namespace version
+func (r *CachedMovieRepository) pageKey(ctx context.Context, query movieListQuery) (string, error) {
+	version, err := r.client.GetClient().Get(ctx, movieListVersionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		version = "0"
+	} else if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return movieListKeyPrefix + version + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// invalidate drops every cached page. A failure is logged; the pages then
+// expire within the TTL.
+func (r *CachedMovieRepository) invalidate(ctx context.Context) {
+	if !r.enabled() {
+		return
+	}
+	version, err := r.client.GetClient().Incr(ctx, movieListVersionKey).Result()
+	if err != nil {
+		r.client.logger.Warn("failed to invalidate movie list cache", zap.Error(err))
+		return
+	}
+	r.client.logger.Debug("movie list cache invalidated", zap.Int64("version", version))
+}
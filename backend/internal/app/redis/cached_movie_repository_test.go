@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// memMovies serves a fixed listing and counts the reads that reach it
+type memMovies struct {
+	repository.MovieRepository
+	movies []*entity.Movie
+	reads  int
+}
+
+func (m *memMovies) List(_ context.Context, _ repository.MovieFilter, offset, limit int) ([]*entity.Movie, int64, error) {
+	m.reads++
+	end := min(offset+limit, len(m.movies))
+	return m.movies[offset:end], int64(len(m.movies)), nil
+}
+
+func (m *memMovies) GetNowShowing(_ context.Context, _ *uuid.UUID, _, _ int) ([]*entity.Movie, int64, error) {
+	m.reads++
+	return m.movies, int64(len(m.movies)), nil
+}
+
+func (m *memMovies) Update(_ context.Context, movie *entity.Movie) error {
+	for i, existing := range m.movies {
+		if existing.ID == movie.ID {
+			m.movies[i] = movie
+		}
+	}
+	return nil
+}
+
+func (m *memMovies) Restore(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+
+func (m *memMovies) UpdatePopularityScores(_ context.Context, scores map[uuid.UUID]float64) error {
+	for _, movie := range m.movies {
+		if score, ok := scores[movie.ID]; ok {
+			movie.PopularityScore = score
+		}
+	}
+	return nil
+}
+
+func TestCachedMovieList(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	movies := &memMovies{movies: []*entity.Movie{
+		{ID: uuid.New(), Title: "Dune"},
+		{ID: uuid.New(), Title: "Arrival"},
+		{ID: uuid.New(), Title: "Sicario"},
+	}}
+	repo := NewCachedMovieRepository(movies, client, time.Minute)
+	page := func(filter repository.MovieFilter, offset int) []*entity.Movie {
+		t.Helper()
+		got, total, err := repo.List(ctx, filter, offset, 2)
+		if err != nil || total != 3 {
+			t.Fatalf("List = %d movies of %d, %v", len(got), total, err)
+		}
+		return got
+	}
+
+	first := page(repository.MovieFilter{}, 0)
+	page(repository.MovieFilter{}, 0)
+	if movies.reads != 1 || first[0].Title != "Dune" {
+		t.Errorf("%d reads, want the second one from the cache", movies.reads)
+	}
+
+	// Another page or filter is another key
+	page(repository.MovieFilter{}, 2)
+	page(repository.MovieFilter{Genre: "Drama"}, 0)
+	if movies.reads != 3 {
+		t.Errorf("%d reads, want one per page and filter", movies.reads)
+	}
+
+	// A write moves every listing to new keys
+	if err := repo.Update(ctx, &entity.Movie{ID: first[0].ID, Title: "Dune: Part Two"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := page(repository.MovieFilter{}, 0); got[0].Title != "Dune: Part Two" {
+		t.Errorf("title after the update = %q", got[0].Title)
+	}
+	if hits, misses := repo.Stats(); hits != 1 || misses != 4 {
+		t.Errorf("stats = %d hits, %d misses, want 1 and 4", hits, misses)
+	}
+
+	// Admin listings with deleted movies always read the database, and a
+	// restore moves the public listings to new keys
+	reads := movies.reads
+	page(repository.MovieFilter{IncludeDeleted: true}, 0)
+	page(repository.MovieFilter{IncludeDeleted: true}, 0)
+	if err := repo.Restore(ctx, first[0].ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	page(repository.MovieFilter{}, 0)
+	if movies.reads != reads+3 {
+		t.Errorf("%d reads, want 3 after the admin listings and the restore", movies.reads-reads)
+	}
+
+	// New popularity scores reorder the listings, so they move them too
+	if err := repo.UpdatePopularityScores(ctx, map[uuid.UUID]float64{first[0].ID: 9.5}); err != nil {
+		t.Fatalf("UpdatePopularityScores: %v", err)
+	}
+	if got := page(repository.MovieFilter{}, 0); got[0].PopularityScore != 9.5 {
+		t.Errorf("popularity after the update = %v, want 9.5", got[0].PopularityScore)
+	}
+}
+
+func TestCachedMovieListBypass(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	movies := &memMovies{movies: []*entity.Movie{{ID: uuid.New(), Title: "Dune"}}}
+	cinemaID := uuid.New()
+
+	// The listing of one cinema follows its showtimes and is never cached
+	repo := NewCachedMovieRepository(movies, client, time.Minute)
+	for range 2 {
+		repo.GetNowShowing(ctx, &cinemaID, 0, 10)
+	}
+	if movies.reads != 2 {
+		t.Errorf("%d reads for one cinema's listing, want 2", movies.reads)
+	}
+
+	tests := []struct {
+		name string
+		repo *CachedMovieRepository
+	}{
+		{"without Redis", NewCachedMovieRepository(movies, nil, time.Minute)},
+		{"with a zero TTL", NewCachedMovieRepository(movies, client, 0)},
+	}
+	for _, tt := range tests {
+		movies.reads = 0
+		for range 2 {
+			if _, _, err := tt.repo.GetNowShowing(ctx, nil, 0, 10); err != nil {
+				t.Fatalf("%s: GetNowShowing: %v", tt.name, err)
+			}
+		}
+		if movies.reads != 2 {
+			t.Errorf("%s: %d reads, want every one from the database", tt.name, movies.reads)
+		}
+	}
+
+	// A failing Redis falls back to the database
+	srv.Close()
+	movies.reads = 0
+	if got, _, err := repo.GetNowShowing(ctx, nil, 0, 10); err != nil || len(got) != 1 || movies.reads != 1 {
+		t.Errorf("with Redis down: %d movies, %v after %d reads, want the database listing", len(got), err, movies.reads)
+	}
+}
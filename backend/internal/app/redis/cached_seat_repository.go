@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// seatLayoutKeyPrefix caches the seats of a screen
+	seatLayoutKeyPrefix = "seat_layout:"
+	// seatLayoutVersionKeyPrefix versions a screen's cached seats; it is
+	// bumped on every seat write so a slow reader cannot cache stale seats
+	seatLayoutVersionKeyPrefix = "seat_layout_version:"
+)
+
+// seatLayout is what is cached for a screen, with the version it was
+// loaded at
+type seatLayout struct {
+	Version int64          `json:"version"`
+	Seats   []*entity.Seat `json:"seats"`
+}
+
+// CachedSeatRepository wraps a seat repository and caches the seats of each
+// screen in Redis, read with one round trip. Every write through it drops
+// the cached seats of the screens it touches. Without Redis, or when the
+// cache fails, reads go to the wrapped repository.
+type CachedSeatRepository struct {
+	repository.SeatRepository
+	client *Client
+	ttl    time.Duration
+}
+
+// NewCachedSeatRepository creates a seat repository caching screen layouts
+// for ttl; a ttl of zero turns the cache off
+func NewCachedSeatRepository(next repository.SeatRepository, client *Client, ttl time.Duration) *CachedSeatRepository {
+	return &CachedSeatRepository{
+		SeatRepository: next,
+		client:         client,
+		ttl:            ttl,
+	}
+}
+
+func seatLayoutKey(screenID uuid.UUID) string {
+	return seatLayoutKeyPrefix + screenID.String()
+}
+
+func seatLayoutVersionKey(screenID uuid.UUID) string {
+	return seatLayoutVersionKeyPrefix + screenID.String()
+}
+
+func (r *CachedSeatRepository) enabled() bool {
+	return r.client != nil && r.ttl > 0
+}
+
+func (r *CachedSeatRepository) GetByScreenID(ctx context.Context, screenID uuid.UUID) ([]*entity.Seat, error) {
+	if !r.enabled() {
+		return r.SeatRepository.GetByScreenID(ctx, screenID)
+	}
+	log := r.client.logger.WithField("screen_id", screenID.String())
+
+	// The layout and the current version are read together; a layout
+	// cached at an older version is a miss
+	var version int64
+	values, err := r.client.GetClient().MGet(ctx, seatLayoutKey(screenID), seatLayoutVersionKey(screenID)).Result()
+	if err != nil {
+		log.Warn("failed to read cached seat layout", zap.Error(err))
+		return r.SeatRepository.GetByScreenID(ctx, screenID)
+	}
+	if raw, ok := values[1].(string); ok {
+		version, _ = strconv.ParseInt(raw, 10, 64)
+	}
+	if raw, ok := values[0].(string); ok {
+		var layout seatLayout
+		// A corrupt entry is treated as a miss and overwritten
+		if err := json.Unmarshal([]byte(raw), &layout); err == nil && layout.Version == version {
+			return layout.Seats, nil
+		}
+	}
+
+	seats, err := r.SeatRepository.GetByScreenID(ctx, screenID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached at the version read before the query, so a seat changed
+	// meanwhile cannot leave the old layout in the cache. Same check-and-set
+	// as the booked seats.
+	data, err := json.Marshal(seatLayout{Version: version, Seats: seats})
+	if err == nil {
+		keys := []string{seatLayoutKey(screenID), seatLayoutVersionKey(screenID)}
+		err = cacheBookedScript.Run(ctx, r.client.GetClient(), keys, strconv.FormatInt(version, 10), data, r.ttl.Milliseconds()).Err()
+	}
+	if err != nil {
+		log.Warn("failed to cache seat layout", zap.Error(err))
+	}
+	return seats, nil
+}
+
+func (r *CachedSeatRepository) CreateBatch(ctx context.Context, seats []*entity.Seat) error {
+	if err := r.SeatRepository.CreateBatch(ctx, seats); err != nil {
+		return err
+	}
+	screenIDs := make([]uuid.UUID, 0, 1)
+	seen := make(map[uuid.UUID]bool)
+	for _, seat := range seats {
+		if !seen[seat.ScreenID] {
+			seen[seat.ScreenID] = true
+			screenIDs = append(screenIDs, seat.ScreenID)
+		}
+	}
+	r.invalidate(ctx, screenIDs...)
+	return nil
+}
+
+func (r *CachedSeatRepository) Update(ctx context.Context, seat *entity.Seat) error {
+	if err := r.SeatRepository.Update(ctx, seat); err != nil {
+		return err
+	}
+	r.invalidate(ctx, seat.ScreenID)
+	return nil
+}
+
+func (r *CachedSeatRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	// The screen is looked up first; the deleted seat cannot be read after
+	seats, err := r.SeatRepository.GetByIDs(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+	if err := r.SeatRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	for _, seat := range seats {
+		r.invalidate(ctx, seat.ScreenID)
+	}
+	return nil
+}
+
+// invalidate drops the cached seats of the screens. A failure is logged;
+// the layout then stays cached until the TTL runs out.
+func (r *CachedSeatRepository) invalidate(ctx context.Context, screenIDs ...uuid.UUID) {
+	if !r.enabled() || len(screenIDs) == 0 {
+		return
+	}
+	_, err := r.client.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, screenID := range screenIDs {
+			pipe.Incr(ctx, seatLayoutVersionKey(screenID))
+			pipe.Del(ctx, seatLayoutKey(screenID))
+		}
+		return nil
+	})
+	if err != nil {
+		r.client.logger.Warn("failed to invalidate seat layouts", zap.Int("screens", len(screenIDs)), zap.Error(err))
+	}
+}
@@ -0,0 +1,233 @@
+package redis
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// memLayouts serves the seats of screens and counts the queries. onRead
+// runs after the seats are read, to land a write while the caller works.
+type memLayouts struct {
+	repository.SeatRepository
+	seats   []*entity.Seat
+	queries atomic.Int64
+	onRead  func()
+}
+
+func (m *memLayouts) GetByScreenID(_ context.Context, screenID uuid.UUID) ([]*entity.Seat, error) {
+	m.queries.Add(1)
+	var seats []*entity.Seat
+	for _, seat := range m.seats {
+		if seat.ScreenID == screenID {
+			copied := *seat
+			seats = append(seats, &copied)
+		}
+	}
+	if m.onRead != nil {
+		m.onRead()
+		m.onRead = nil
+	}
+	return seats, nil
+}
+
+func (m *memLayouts) GetByIDs(_ context.Context, ids []uuid.UUID) ([]*entity.Seat, error) {
+	var seats []*entity.Seat
+	for _, seat := range m.seats {
+		if entity.UUIDList(ids).Contains(seat.ID) {
+			seats = append(seats, seat)
+		}
+	}
+	return seats, nil
+}
+
+func (m *memLayouts) Update(_ context.Context, seat *entity.Seat) error {
+	for i, existing := range m.seats {
+		if existing.ID == seat.ID {
+			m.seats[i] = seat
+		}
+	}
+	return nil
+}
+
+func (m *memLayouts) Delete(_ context.Context, id uuid.UUID) error {
+	for i, seat := range m.seats {
+		if seat.ID == id {
+			m.seats = append(m.seats[:i], m.seats[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func newLayout(screenID uuid.UUID, n int) []*entity.Seat {
+	seats := make([]*entity.Seat, 0, n)
+	for i := 1; i <= n; i++ {
+		seats = append(seats, &entity.Seat{ID: uuid.New(), ScreenID: screenID, RowLabel: "A", SeatNumber: i, SeatType: entity.SeatStandard})
+	}
+	return seats
+}
+
+func TestCachedSeatLayout(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	screenID, otherScreenID := uuid.New(), uuid.New()
+	seats := &memLayouts{seats: append(newLayout(screenID, 3), newLayout(otherScreenID, 2)...)}
+	repo := NewCachedSeatRepository(seats, client, time.Hour)
+	layout := func(screenID uuid.UUID) []*entity.Seat {
+		t.Helper()
+		got, err := repo.GetByScreenID(ctx, screenID)
+		if err != nil {
+			t.Fatalf("GetByScreenID: %v", err)
+		}
+		return got
+	}
+
+	layout(screenID)
+	if got := layout(screenID); len(got) != 3 || seats.queries.Load() != 1 {
+		t.Fatalf("%d seats after %d queries, want 3 seats from one query", len(got), seats.queries.Load())
+	}
+	layout(otherScreenID)
+
+	// A write drops the layout of its screen only
+	changed := *seats.seats[0]
+	changed.SeatType = entity.SeatVIP
+	if err := repo.Update(ctx, &changed); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := layout(screenID); got[0].SeatType != entity.SeatVIP || seats.queries.Load() != 3 {
+		t.Errorf("after the update: %s after %d queries, want VIP from a fresh query", got[0].SeatType, seats.queries.Load())
+	}
+	layout(otherScreenID)
+	if seats.queries.Load() != 3 {
+		t.Errorf("the other screen's layout was dropped")
+	}
+
+	if err := repo.Delete(ctx, seats.seats[1].ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := layout(screenID); len(got) != 2 {
+		t.Errorf("%d seats after the delete, want 2", len(got))
+	}
+}
+
+func TestCachedSeatLayoutNeverCachesAStaleRead(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	screenID := uuid.New()
+	seats := &memLayouts{seats: newLayout(screenID, 2)}
+	repo := NewCachedSeatRepository(seats, client, time.Hour)
+
+	// A seat is changed after the read, before the layout is cached
+	seats.onRead = func() {
+		changed := *seats.seats[0]
+		changed.IsActive = false
+		repo.Update(ctx, &changed)
+	}
+	repo.GetByScreenID(ctx, screenID)
+
+	got, _ := repo.GetByScreenID(ctx, screenID)
+	if seats.queries.Load() != 2 || got[0].IsActive {
+		t.Errorf("%d queries, active %v: the stale layout was cached", seats.queries.Load(), got[0].IsActive)
+	}
+}
+
+// callCounter counts the commands and pipelines sent to Redis
+type callCounter struct{ calls atomic.Int64 }
+
+func (c *callCounter) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (c *callCounter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c.calls.Add(1)
+		return next(ctx, cmd)
+	}
+}
+
+func (c *callCounter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		c.calls.Add(1)
+		return next(ctx, cmds)
+	}
+}
+
+// BenchmarkSeatMapReads compares the reads behind one seat map of a 200
+// seat screen with 40 seats booked and 10 held. "uncached" is the path
+// before the layout cache: the layout and the booked seats are queried
+// and the locks are read with one MGET. "cached" reads the cached layout,
+// then the locks and the cached booked seats in one probe. Besides the
+// time against an in-process Redis it reports database queries and Redis
+// round trips per seat map, which are what dominate in production.
+func BenchmarkSeatMapReads(b *testing.B) {
+	ctx := context.Background()
+	client, _ := newTestClient(b)
+	counter := &callCounter{}
+	client.GetClient().AddHook(counter)
+
+	screenID, showtimeID := uuid.New(), uuid.New()
+	layout := &memLayouts{seats: newLayout(screenID, 200)}
+	holds := &seatHoldRepository{client: client}
+	hold := newTestHold(showtimeID, time.Hour)
+	for _, seat := range layout.seats[40:50] {
+		hold.Seats = append(hold.Seats, entity.HeldSeat{SeatID: seat.ID})
+	}
+	if err := holds.Create(ctx, hold); err != nil {
+		b.Fatalf("Create: %v", err)
+	}
+	booked := make([]uuid.UUID, 0, 40)
+	for _, seat := range layout.seats[:40] {
+		booked = append(booked, seat.ID)
+	}
+	var bookedQueries atomic.Int64
+	queryBooked := func() []uuid.UUID {
+		bookedQueries.Add(1)
+		return append([]uuid.UUID{}, booked...)
+	}
+
+	run := func(b *testing.B, layoutTTL time.Duration, seatMap func(seats *CachedSeatRepository)) {
+		seats := NewCachedSeatRepository(layout, client, layoutTTL)
+		seatMap(seats) // warms the caches
+		layout.queries.Store(0)
+		bookedQueries.Store(0)
+		counter.calls.Store(0)
+
+		b.ResetTimer()
+		for range b.N {
+			seatMap(seats)
+		}
+		b.ReportMetric(float64(layout.queries.Load()+bookedQueries.Load())/float64(b.N), "queries/op")
+		b.ReportMetric(float64(counter.calls.Load())/float64(b.N), "redis-round-trips/op")
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		run(b, 0, func(seats *CachedSeatRepository) {
+			layoutSeats, _ := seats.GetByScreenID(ctx, screenID)
+			queryBooked()
+			holds.GetHeldSeatIDs(ctx, showtimeID, seatIDsOf(layoutSeats))
+		})
+	})
+	b.Run("cached", func(b *testing.B) {
+		run(b, time.Hour, func(seats *CachedSeatRepository) {
+			layoutSeats, _ := seats.GetByScreenID(ctx, screenID)
+			probe, _ := holds.ProbeSeats(ctx, showtimeID, seatIDsOf(layoutSeats))
+			if probe.Booked == nil {
+				holds.CacheBookedSeats(ctx, showtimeID, probe.Version, queryBooked(), time.Hour)
+			}
+		})
+	})
+}
+
+func seatIDsOf(seats []*entity.Seat) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(seats))
+	for _, seat := range seats {
+		ids = append(ids, seat.ID)
+	}
+	return ids
+}
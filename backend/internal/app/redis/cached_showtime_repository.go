@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// movieShowtimesKeyPrefix caches a page of a movie's showtimes, keyed by a
+// hash of the query
+const movieShowtimesKeyPrefix = "showtimes:movie:"
+
+// movieShowtimesQuery identifies a cached page; it is hashed into the key
+type movieShowtimesQuery struct {
+	Filter repository.MovieShowtimeFilter `json:"filter"`
+	Offset int                            `json:"offset"`
+	Limit  int                            `json:"limit"`
+}
+
+// movieShowtimesPage is what is cached for a page
+type movieShowtimesPage struct {
+	Showtimes []*entity.Showtime `json:"showtimes"`
+	Total     int64              `json:"total"`
+}
+
+// CachedShowtimeRepository wraps a showtime repository and caches the pages
+// of a movie's showtime listing in Redis for a short TTL. Writes do not
+// drop cached pages, so a listing may lag showtime and seat changes by the
+// TTL. Without Redis, or when the cache fails, reads go to the wrapped
+// repository.
+type CachedShowtimeRepository struct {
+	repository.ShowtimeRepository
+	client *Client
+	ttl    time.Duration
+}
+
+// NewCachedShowtimeRepository creates a showtime repository caching movie
+// showtime listings for ttl; a ttl of zero turns the cache off
+func NewCachedShowtimeRepository(next repository.ShowtimeRepository, client *Client, ttl time.Duration) *CachedShowtimeRepository {
+	return &CachedShowtimeRepository{
+		ShowtimeRepository: next,
+		client:             client,
+		ttl:                ttl,
+	}
+}
+
+func (r *CachedShowtimeRepository) enabled() bool {
+	return r.client != nil && r.ttl > 0
+}
+
+func (r *CachedShowtimeRepository) ListForMovie(ctx context.Context, filter repository.MovieShowtimeFilter, offset, limit int) ([]*entity.Showtime, int64, error) {
+	if !r.enabled() {
+		return r.ShowtimeRepository.ListForMovie(ctx, filter, offset, limit)
+	}
+
+	// The cut-off moves with every request, so it is left out of the key
+	// and applied to cached pages instead
+	startsAfter := filter.StartsAfter
+	keyed := filter
+	keyed.StartsAfter = time.Time{}
+	key, err := movieShowtimesKey(movieShowtimesQuery{Filter: keyed, Offset: offset, Limit: limit})
+	if err != nil {
+		return r.ShowtimeRepository.ListForMovie(ctx, filter, offset, limit)
+	}
+
+	data, err := r.client.GetClient().Get(ctx, key).Bytes()
+	if err == nil {
+		var page movieShowtimesPage
+		if err := json.Unmarshal(data, &page); err == nil {
+			return startingAfter(page.Showtimes, startsAfter), page.Total, nil
+		}
+		// A corrupt entry is treated as a miss and overwritten
+	} else if !errors.Is(err, redis.Nil) {
+		r.client.logger.Warn("failed to read cached movie showtimes", zap.String("key", key), zap.Error(err))
+	}
+
+	showtimes, total, err := r.ShowtimeRepository.ListForMovie(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err = json.Marshal(movieShowtimesPage{Showtimes: showtimes, Total: total})
+	if err == nil {
+		err = r.client.GetClient().Set(ctx, key, data, r.ttl).Err()
+	}
+	if err != nil {
+		r.client.logger.Warn("failed to cache movie showtimes", zap.String("key", key), zap.Error(err))
+	}
+	return showtimes, total, nil
+}
+
+// movieShowtimesKey returns the key of a page
+func movieShowtimesKey(query movieShowtimesQuery) (string, error) {
+	data, err := json.Marshal(query)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return movieShowtimesKeyPrefix + query.Filter.MovieID.String() + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// startingAfter keeps the showtimes starting after the cut-off, judged in
+// each cinema's timezone like the query does
+func startingAfter(showtimes []*entity.Showtime, after time.Time) []*entity.Showtime {
+	kept := showtimes[:0]
+	for _, st := range showtimes {
+		if st.StartsAt(st.Cinema.Location()).After(after) {
+			kept = append(kept, st)
+		}
+	}
+	return kept
+}
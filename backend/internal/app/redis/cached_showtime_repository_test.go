@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// memListings serves a fixed showtime listing and counts the reads that
+// reach it
+type memListings struct {
+	repository.ShowtimeRepository
+	showtimes []*entity.Showtime
+	reads     int
+}
+
+func (m *memListings) ListForMovie(_ context.Context, filter repository.MovieShowtimeFilter, _, _ int) ([]*entity.Showtime, int64, error) {
+	m.reads++
+	var listed []*entity.Showtime
+	for _, st := range m.showtimes {
+		if filter.CinemaID == uuid.Nil || st.CinemaID == filter.CinemaID {
+			copied := *st
+			listed = append(listed, &copied)
+		}
+	}
+	return listed, int64(len(listed)), nil
+}
+
+func TestCachedMovieShowtimes(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	movieID, downtown := uuid.New(), uuid.New()
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	listings := &memListings{showtimes: []*entity.Showtime{
+		{ID: uuid.New(), MovieID: movieID, CinemaID: downtown, ShowDate: day, StartTime: "18:00", Cinema: entity.Cinema{Timezone: "UTC"}},
+		{ID: uuid.New(), MovieID: movieID, CinemaID: uuid.New(), ShowDate: day, StartTime: "21:00", Cinema: entity.Cinema{Timezone: "UTC"}},
+	}}
+	repo := NewCachedShowtimeRepository(listings, client, time.Minute)
+	list := func(filter repository.MovieShowtimeFilter) []*entity.Showtime {
+		t.Helper()
+		showtimes, _, err := repo.ListForMovie(ctx, filter, 0, 10)
+		if err != nil {
+			t.Fatalf("ListForMovie: %v", err)
+		}
+		return showtimes
+	}
+	filter := repository.MovieShowtimeFilter{MovieID: movieID, StartsAfter: day.Add(12 * time.Hour)}
+
+	list(filter)
+	if got := list(filter); len(got) != 2 || listings.reads != 1 {
+		t.Errorf("%d showtimes after %d reads, want 2 with the second listing cached", len(got), listings.reads)
+	}
+
+	// The cut-off is applied to the cached page, so a show that started
+	// meanwhile drops out without another read
+	filter.StartsAfter = day.Add(19 * time.Hour)
+	if got := list(filter); len(got) != 1 || got[0].StartTime != "21:00" || listings.reads != 1 {
+		t.Errorf("after 19:00: %d showtimes after %d reads, want the 21:00 show from the cache", len(got), listings.reads)
+	}
+
+	// Another cinema is another key
+	filter.CinemaID = downtown
+	filter.StartsAfter = day
+	if got := list(filter); len(got) != 1 || got[0].CinemaID != downtown || listings.reads != 2 {
+		t.Errorf("one cinema: %d showtimes after %d reads", len(got), listings.reads)
+	}
+
+	// Pages expire with the TTL
+	srv.FastForward(time.Minute)
+	list(filter)
+	if listings.reads != 3 {
+		t.Errorf("%d reads, want the expired page read again", listings.reads)
+	}
+}
+
+func TestCachedMovieShowtimesBypass(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	listings := &memListings{showtimes: []*entity.Showtime{{ID: uuid.New(), StartTime: "18:00"}}}
+	filter := repository.MovieShowtimeFilter{MovieID: uuid.New()}
+
+	tests := []struct {
+		name string
+		repo *CachedShowtimeRepository
+	}{
+		{"without Redis", NewCachedShowtimeRepository(listings, nil, time.Minute)},
+		{"with a zero TTL", NewCachedShowtimeRepository(listings, client, 0)},
+	}
+	for _, tt := range tests {
+		listings.reads = 0
+		for range 2 {
+			if _, _, err := tt.repo.ListForMovie(ctx, filter, 0, 10); err != nil {
+				t.Fatalf("%s: ListForMovie: %v", tt.name, err)
+			}
+		}
+		if listings.reads != 2 {
+			t.Errorf("%s: %d reads, want every one from the database", tt.name, listings.reads)
+		}
+	}
+
+	// A failing Redis falls back to the database
+	srv.Close()
+	listings.reads = 0
+	repo := NewCachedShowtimeRepository(listings, client, time.Minute)
+	if got, _, err := repo.ListForMovie(ctx, filter, 0, 10); err != nil || len(got) != 1 || listings.reads != 1 {
+		t.Errorf("with Redis down: %d showtimes, %v after %d reads, want the database listing", len(got), err, listings.reads)
+	}
+}
@@ -33,6 +33,7 @@ func New(cfg config.RedisConfig, log *logger.Logger) (*Client, error) {
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
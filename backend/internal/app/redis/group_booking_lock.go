@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// groupLockTTL bounds how long a crashed holder can keep a group locked
+	groupLockTTL = 10 * time.Second
+	// groupLockWait is how long a caller waits for a taken lock
+	groupLockWait = 3 * time.Second
+	// groupLockRetry is the pause between attempts on a taken lock
+	groupLockRetry = 50 * time.Millisecond
+)
+
+// unlockGroupScript deletes the lock only while it is still owned by the
+// caller, so a holder whose lock expired cannot release the next holder's
+var unlockGroupScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// groupBookingLock implements repository.GroupBookingLock
+type groupBookingLock struct {
+	client *Client
+}
+
+// NewGroupBookingLock creates a Redis-backed lock for group bookings
+func NewGroupBookingLock(client *Client) repository.GroupBookingLock {
+	return &groupBookingLock{client: client}
+}
+
+func groupLockKey(token string) string {
+	return "group:" + token + ":lock"
+}
+
+func (l *groupBookingLock) Acquire(ctx context.Context, token string) (func(), error) {
+	if l.client == nil {
+		return nil, apperrors.New(apperrors.CodeInternal, "group bookings are unavailable")
+	}
+
+	rdb := l.client.GetClient()
+	key := groupLockKey(token)
+	owner := uuid.NewString()
+	deadline := time.Now().Add(groupLockWait)
+
+	for {
+		ok, err := rdb.SetNX(ctx, key, owner, groupLockTTL).Result()
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock group booking")
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, apperrors.New(apperrors.CodeConflict, "group booking is busy, please try again")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, apperrors.Wrap(ctx.Err(), apperrors.CodeInternal, "failed to lock group booking")
+		case <-time.After(groupLockRetry):
+		}
+	}
+
+	release := func() {
+		// Released even when the request was cancelled; otherwise the group
+		// stays locked until the TTL runs out
+		err := unlockGroupScript.Run(context.WithoutCancel(ctx), rdb, []string{key}, owner).Err()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			l.client.logger.Warn("failed to release group booking lock", zap.Error(err))
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+func TestGroupBookingLock(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	locks := NewGroupBookingLock(client)
+
+	release, err := locks.Acquire(ctx, "token-1")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	// Other groups are not blocked
+	releaseOther, err := locks.Acquire(ctx, "token-2")
+	if err != nil {
+		t.Fatalf("Acquire another group: %v", err)
+	}
+	releaseOther()
+
+	// A second caller waits for the lock and gets it once it is released
+	acquired := make(chan error, 1)
+	go func() {
+		second, err := locks.Acquire(ctx, "token-1")
+		if err == nil {
+			second()
+		}
+		acquired <- err
+	}()
+	time.Sleep(2 * groupLockRetry)
+	select {
+	case err := <-acquired:
+		t.Fatalf("second Acquire returned %v while the lock was held", err)
+	default:
+	}
+	release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("second Acquire after the release: %v", err)
+	}
+	if srv.Exists(groupLockKey("token-1")) {
+		t.Error("the lock was left behind")
+	}
+}
+
+func TestGroupBookingLockExpired(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	locks := NewGroupBookingLock(client)
+
+	stale, err := locks.Acquire(ctx, "token-1")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	// The holder crashed: its lock runs out and the next caller takes over
+	srv.FastForward(groupLockTTL)
+	release, err := locks.Acquire(ctx, "token-1")
+	if err != nil {
+		t.Fatalf("Acquire after the TTL: %v", err)
+	}
+	defer release()
+
+	// The late holder cannot release the new holder's lock
+	stale()
+	if !srv.Exists(groupLockKey("token-1")) {
+		t.Fatal("a stale holder released the current lock")
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 3*groupLockRetry)
+	defer cancel()
+	if _, err := locks.Acquire(waitCtx, "token-1"); err == nil {
+		t.Error("acquired a held lock")
+	}
+}
+
+func TestGroupBookingLockUnavailable(t *testing.T) {
+	_, err := NewGroupBookingLock(nil).Acquire(context.Background(), "token-1")
+	if !apperrors.Is(err, apperrors.CodeInternal) {
+		t.Errorf("Acquire without Redis = %v, want %s", err, apperrors.CodeInternal)
+	}
+}
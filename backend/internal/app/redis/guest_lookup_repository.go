@@ -0,0 +1,156 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lookupFailKeyPrefix  = "lookup_fail:"
+	lookupBlockKeyPrefix = "lookup_block:"
+	lookupCodeKeyPrefix  = "lookup_code:"
+)
+
+// countFailureScript increments a failure counter, starting its window on
+// the first failure
+var countFailureScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// useCodeScript counts an attempt against a verification code and returns
+// the code hash with the attempt count
+var useCodeScript = redis.NewScript(`
+local hash = redis.call('HGET', KEYS[1], 'hash')
+if not hash then
+	return {'', 0}
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+return {hash, attempts}
+`)
+
+// guestLookupRepository implements repository.GuestLookupRepository
+type guestLookupRepository struct {
+	client *Client
+}
+
+// NewGuestLookupRepository creates a new Redis-backed guest lookup repository
+func NewGuestLookupRepository(client *Client) repository.GuestLookupRepository {
+	return &guestLookupRepository{client: client}
+}
+
+func (r *guestLookupRepository) available() error {
+	if r.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "booking lookups are unavailable")
+	}
+	return nil
+}
+
+func (r *guestLookupRepository) BlockedFor(ctx context.Context, keys ...string) (time.Duration, error) {
+	if err := r.available(); err != nil {
+		return 0, err
+	}
+
+	pipe := r.client.GetClient().Pipeline()
+	cmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.PTTL(ctx, lookupBlockKeyPrefix+key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check lookup throttle")
+	}
+
+	var longest time.Duration
+	for _, cmd := range cmds {
+		// Missing keys report a negative TTL
+		if ttl := cmd.Val(); ttl > longest {
+			longest = ttl
+		}
+	}
+	return longest, nil
+}
+
+func (r *guestLookupRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	if err := r.available(); err != nil {
+		return 0, err
+	}
+
+	n, err := countFailureScript.Run(ctx, r.client.GetClient(),
+		[]string{lookupFailKeyPrefix + key}, window.Milliseconds()).Int()
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to record lookup failure")
+	}
+	return n, nil
+}
+
+func (r *guestLookupRepository) Block(ctx context.Context, key string, d time.Duration) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	if err := r.client.GetClient().Set(ctx, lookupBlockKeyPrefix+key, 1, d).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to block lookups")
+	}
+	return nil
+}
+
+func (r *guestLookupRepository) ResetFailures(ctx context.Context, key string) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	if err := r.client.GetClient().Del(ctx, lookupFailKeyPrefix+key).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to reset lookup failures")
+	}
+	return nil
+}
+
+func (r *guestLookupRepository) SaveCode(ctx context.Context, reference, codeHash string, ttl time.Duration) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	key := lookupCodeKeyPrefix + reference
+	pipe := r.client.GetClient().TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "hash", codeHash, "attempts", 0)
+	pipe.PExpire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to save verification code")
+	}
+	return nil
+}
+
+func (r *guestLookupRepository) UseCode(ctx context.Context, reference string) (string, int, error) {
+	if err := r.available(); err != nil {
+		return "", 0, err
+	}
+
+	res, err := useCodeScript.Run(ctx, r.client.GetClient(), []string{lookupCodeKeyPrefix + reference}).Slice()
+	if err != nil {
+		return "", 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check verification code")
+	}
+	hash, _ := res[0].(string)
+	attempts, _ := res[1].(int64)
+	return hash, int(attempts), nil
+}
+
+func (r *guestLookupRepository) DeleteCode(ctx context.Context, reference string) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	if err := r.client.GetClient().Del(ctx, lookupCodeKeyPrefix+reference).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete verification code")
+	}
+	return nil
+}
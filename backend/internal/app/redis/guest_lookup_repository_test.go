@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestClient returns a client of an in-process Redis. Expiry only moves
+// when the test fast-forwards the server.
+func newTestClient(t testing.TB) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return &Client{client: rdb, logger: &logger.Logger{Logger: zap.NewNop()}}, srv
+}
+
+func TestLookupFailureWindow(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	repo := NewGuestLookupRepository(client)
+
+	for want := 1; want <= 3; want++ {
+		n, err := repo.RecordFailure(ctx, "ref:BK1", time.Minute)
+		if err != nil || n != want {
+			t.Fatalf("RecordFailure = %d, %v, want %d", n, err, want)
+		}
+		srv.FastForward(10 * time.Second)
+	}
+
+	// The window starts at the first failure and is not extended by later
+	// ones: 30s have passed, so it ends in another 30s
+	srv.FastForward(29 * time.Second)
+	if n, _ := repo.RecordFailure(ctx, "ref:BK1", time.Minute); n != 4 {
+		t.Fatalf("failure inside the window counted as %d, want 4", n)
+	}
+	srv.FastForward(2 * time.Second)
+	if n, _ := repo.RecordFailure(ctx, "ref:BK1", time.Minute); n != 1 {
+		t.Errorf("failure after the window counted as %d, want a new window", n)
+	}
+
+	// Keys are counted separately, and a reset forgets the count
+	if n, _ := repo.RecordFailure(ctx, "ip:10.0.0.1", time.Minute); n != 1 {
+		t.Errorf("other key counted as %d, want 1", n)
+	}
+	if err := repo.ResetFailures(ctx, "ref:BK1"); err != nil {
+		t.Fatalf("ResetFailures: %v", err)
+	}
+	if n, _ := repo.RecordFailure(ctx, "ref:BK1", time.Minute); n != 1 {
+		t.Errorf("failure after reset counted as %d, want 1", n)
+	}
+}
+
+func TestLookupBlock(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	repo := NewGuestLookupRepository(client)
+
+	if d, err := repo.BlockedFor(ctx, "ref:BK1", "ip:10.0.0.1"); err != nil || d != 0 {
+		t.Fatalf("BlockedFor = %s, %v, want not blocked", d, err)
+	}
+
+	_ = repo.Block(ctx, "ref:BK1", 5*time.Second)
+	_ = repo.Block(ctx, "ip:10.0.0.1", 20*time.Second)
+	if d, _ := repo.BlockedFor(ctx, "ref:BK1", "ip:10.0.0.1"); d != 20*time.Second {
+		t.Errorf("BlockedFor = %s, want the longest block", d)
+	}
+
+	srv.FastForward(6 * time.Second)
+	if d, _ := repo.BlockedFor(ctx, "ref:BK1"); d != 0 {
+		t.Errorf("reference still blocked for %s after its block ran out", d)
+	}
+	if d, _ := repo.BlockedFor(ctx, "ref:BK1", "ip:10.0.0.1"); d != 14*time.Second {
+		t.Errorf("BlockedFor = %s, want the IP's remaining 14s", d)
+	}
+
+	// A reset keeps an active block
+	_ = repo.ResetFailures(ctx, "ip:10.0.0.1")
+	if d, _ := repo.BlockedFor(ctx, "ip:10.0.0.1"); d == 0 {
+		t.Error("reset lifted the block")
+	}
+}
+
+func TestLookupCode(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	repo := NewGuestLookupRepository(client)
+
+	if hash, attempts, err := repo.UseCode(ctx, "BK1"); err != nil || hash != "" || attempts != 0 {
+		t.Fatalf("UseCode without a code = %q, %d, %v", hash, attempts, err)
+	}
+
+	_ = repo.SaveCode(ctx, "BK1", "hash-1", time.Minute)
+	for want := 1; want <= 2; want++ {
+		hash, attempts, err := repo.UseCode(ctx, "BK1")
+		if err != nil || hash != "hash-1" || attempts != want {
+			t.Fatalf("UseCode = %q, %d, %v, want hash-1 after %d attempts", hash, attempts, err, want)
+		}
+	}
+
+	// A new code starts its attempts over
+	_ = repo.SaveCode(ctx, "BK1", "hash-2", time.Minute)
+	if hash, attempts, _ := repo.UseCode(ctx, "BK1"); hash != "hash-2" || attempts != 1 {
+		t.Errorf("UseCode after a new code = %q, %d", hash, attempts)
+	}
+
+	srv.FastForward(time.Minute)
+	if hash, _, _ := repo.UseCode(ctx, "BK1"); hash != "" {
+		t.Error("expired code still returned")
+	}
+
+	_ = repo.SaveCode(ctx, "BK1", "hash-3", time.Minute)
+	_ = repo.DeleteCode(ctx, "BK1")
+	if hash, _, _ := repo.UseCode(ctx, "BK1"); hash != "" {
+		t.Error("deleted code still returned")
+	}
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// IdempotencyStore keeps the state of requests made with an idempotency
+// key, shared by every instance using the Redis server
+type IdempotencyStore struct {
+	client *Client
+}
+
+// NewIdempotencyStore creates a Redis-backed idempotency key store
+func NewIdempotencyStore(client *Client) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Claim stores value under key unless the key is taken, in which case it
+// returns the value stored by whoever took it
+func (s *IdempotencyStore) Claim(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	if s.client == nil {
+		return nil, false, apperrors.New(apperrors.CodeInternal, "idempotency keys are unavailable")
+	}
+
+	rdb := s.client.GetClient()
+	ok, err := rdb.SetNX(ctx, idempotencyKeyPrefix+key, value, ttl).Result()
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim idempotency key")
+	}
+	if ok {
+		return nil, true, nil
+	}
+
+	stored, err := rdb.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Expired between the two calls; the caller may retry
+		return nil, false, apperrors.New(apperrors.CodeConflict, "idempotency key was released, please try again")
+	}
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to read idempotency key")
+	}
+	return stored, false, nil
+}
+
+// Set overwrites the value stored under key
+func (s *IdempotencyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if s.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "idempotency keys are unavailable")
+	}
+	if err := s.client.GetClient().Set(ctx, idempotencyKeyPrefix+key, value, ttl).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to store idempotency key")
+	}
+	return nil
+}
+
+// Delete releases key
+func (s *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	if s.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "idempotency keys are unavailable")
+	}
+	if err := s.client.GetClient().Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to release idempotency key")
+	}
+	return nil
+}
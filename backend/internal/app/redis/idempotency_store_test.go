@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	if _, claimed, err := store.Claim(ctx, "key-1", []byte("pending"), time.Minute); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v; want claimed", claimed, err)
+	}
+	stored, claimed, err := store.Claim(ctx, "key-1", []byte("other"), time.Minute)
+	if err != nil || claimed || string(stored) != "pending" {
+		t.Fatalf("second Claim = %q, %v, %v; want the first value", stored, claimed, err)
+	}
+
+	// The response replaces the claim with its own TTL
+	if err := store.Set(ctx, "key-1", []byte("done"), time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if ttl := srv.TTL(idempotencyKeyPrefix + "key-1"); ttl != time.Hour {
+		t.Errorf("TTL = %s, want 1h", ttl)
+	}
+
+	// A released key can be claimed again, and so can an expired claim
+	if err := store.Delete(ctx, "key-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, claimed, _ := store.Claim(ctx, "key-1", []byte("again"), time.Minute); !claimed {
+		t.Error("a released key was not claimed")
+	}
+	srv.FastForward(time.Minute)
+	if _, claimed, _ := store.Claim(ctx, "key-1", []byte("after expiry"), time.Minute); !claimed {
+		t.Error("an expired claim was not claimed")
+	}
+}
+
+func TestIdempotencyStoreUnavailable(t *testing.T) {
+	_, _, err := NewIdempotencyStore(nil).Claim(context.Background(), "key-1", []byte("pending"), time.Minute)
+	if !apperrors.Is(err, apperrors.CodeInternal) {
+		t.Errorf("Claim without Redis = %v, want %s", err, apperrors.CodeInternal)
+	}
+}
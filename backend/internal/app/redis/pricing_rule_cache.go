@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// pricingRulesKeyPrefix caches the pricing rules per cinema
+const pricingRulesKeyPrefix = "pricing_rules:"
+
+// pricingRuleCache implements repository.PricingRuleCache
+type pricingRuleCache struct {
+	client *Client
+}
+
+// NewPricingRuleCache creates a new Redis-backed pricing rule cache
+func NewPricingRuleCache(client *Client) repository.PricingRuleCache {
+	return &pricingRuleCache{client: client}
+}
+
+func pricingRulesKey(cinemaID uuid.UUID) string {
+	return pricingRulesKeyPrefix + cinemaID.String()
+}
+
+func (r *pricingRuleCache) available() error {
+	if r.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "pricing rule cache is unavailable")
+	}
+	return nil
+}
+
+func (r *pricingRuleCache) Get(ctx context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, bool, error) {
+	if err := r.available(); err != nil {
+		return nil, false, err
+	}
+
+	data, err := r.client.GetClient().Get(ctx, pricingRulesKey(cinemaID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, apperrors.Wrap(err, apperrors.CodeInternal, "failed to read cached pricing rules")
+	}
+
+	var rules []*entity.PricingRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		// A corrupt entry is treated as a miss and overwritten
+		return nil, false, nil
+	}
+	return rules, true, nil
+}
+
+func (r *pricingRuleCache) Set(ctx context.Context, cinemaID uuid.UUID, rules []*entity.PricingRule, ttl time.Duration) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+	if rules == nil {
+		rules = []*entity.PricingRule{}
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode pricing rules")
+	}
+	if err := r.client.GetClient().Set(ctx, pricingRulesKey(cinemaID), data, ttl).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to cache pricing rules")
+	}
+	return nil
+}
+
+func (r *pricingRuleCache) Invalidate(ctx context.Context, cinemaID uuid.UUID) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+	if err := r.client.GetClient().Del(ctx, pricingRulesKey(cinemaID)).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to invalidate pricing rules")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitKeyPrefix = "rate_limit:"
+
+// slidingWindowScript drops requests older than the window, then admits the
+// request if fewer than the limit remain. It returns whether the request
+// was admitted, the requests now in the window and the score of the
+// oldest, which tells a rejected client when a slot frees up.
+var slidingWindowScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {allowed, count, tonumber(oldest[2] or ARGV[1])}
+`)
+
+// RateLimiter counts requests per key in a sliding window kept as a sorted
+// set of request timestamps. It is safe for concurrent use; every instance
+// sharing the Redis server shares the counts.
+type RateLimiter struct {
+	client *Client
+}
+
+// NewRateLimiter creates a Redis-backed sliding-window rate limiter
+func NewRateLimiter(client *Client) *RateLimiter {
+	return &RateLimiter{client: client}
+}
+
+// Allow records a request under key and reports whether it fits in the
+// limit, how many requests are left and, when rejected, how long until
+// the oldest request leaves the window
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Duration, error) {
+	if r.client == nil {
+		return false, 0, 0, apperrors.New(apperrors.CodeInternal, "rate limiting is unavailable")
+	}
+
+	now := time.Now().UnixMilli()
+	// Requests in the same millisecond need distinct members
+	member := fmt.Sprintf("%d-%d", now, rand.Uint64())
+	res, err := slidingWindowScript.Run(ctx, r.client.GetClient(), []string{rateLimitKeyPrefix + key},
+		now, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return false, 0, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to check rate limit")
+	}
+
+	allowed, count, oldest := res[0] == 1, int(res[1]), res[2]
+	var retryAfter time.Duration
+	if !allowed {
+		retryAfter = time.Duration(oldest+window.Milliseconds()-now) * time.Millisecond
+	}
+	return allowed, max(limit-count, 0), retryAfter, nil
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	limiter := NewRateLimiter(client)
+	window := 200 * time.Millisecond
+
+	for want := 2; want >= 0; want-- {
+		allowed, remaining, _, err := limiter.Allow(ctx, "default:10.0.0.1", 3, window)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !allowed || remaining != want {
+			t.Fatalf("allowed %v, remaining %d, want allowed with %d left", allowed, remaining, want)
+		}
+	}
+
+	allowed, remaining, retryAfter, err := limiter.Allow(ctx, "default:10.0.0.1", 3, window)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if allowed || remaining != 0 {
+		t.Errorf("fourth request allowed %v, remaining %d", allowed, remaining)
+	}
+	if retryAfter <= 0 || retryAfter > window {
+		t.Errorf("retry after %s, want within the %s window", retryAfter, window)
+	}
+
+	// Another client has its own budget
+	if allowed, _, _, _ := limiter.Allow(ctx, "default:10.0.0.2", 3, window); !allowed {
+		t.Error("another client was limited")
+	}
+
+	// The rejected request was not counted: once the window slides past
+	// the first three, the budget is whole again
+	time.Sleep(window)
+	if allowed, remaining, _, _ := limiter.Allow(ctx, "default:10.0.0.1", 3, window); !allowed || remaining != 2 {
+		t.Errorf("after the window allowed %v, remaining %d, want allowed with 2 left", allowed, remaining)
+	}
+}
+
+func TestRateLimiterConcurrentRequests(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	limiter := NewRateLimiter(client)
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _, _, err := limiter.Allow(ctx, "default:10.0.0.1", 10, time.Minute)
+			if err != nil {
+				t.Errorf("Allow: %v", err)
+			}
+			if ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 10 {
+		t.Errorf("%d of 50 concurrent requests allowed, want 10", got)
+	}
+}
+
+func TestRateLimiterRedisDown(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.Close()
+
+	if _, _, _, err := NewRateLimiter(client).Allow(context.Background(), "default:10.0.0.1", 3, time.Minute); err == nil {
+		t.Error("Allow succeeded without Redis")
+	}
+	if _, _, _, err := NewRateLimiter(nil).Allow(context.Background(), "default:10.0.0.1", 3, time.Minute); err == nil {
+		t.Error("Allow succeeded without a client")
+	}
+}
@@ -0,0 +1,617 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	holdKeyPrefix     = "hold:"
+	seatLockKeyPrefix = "seat_lock:"
+	// holdSnapshotKeyPrefix keeps a copy of each hold past its expiry so the
+	// expiry sweep can still see what was held
+	holdSnapshotKeyPrefix = "hold_snapshot:"
+	// holdExpiryKey is a sorted set of live hold IDs scored by expiry time
+	holdExpiryKey = "hold_expiry"
+	// snapshotRetention is how long a snapshot outlives its hold
+	snapshotRetention = 24 * time.Hour
+	// heldCountKeyPrefix keys the number of seats held per showtime
+	heldCountKeyPrefix = "held_count:"
+	// heldExpiryKey is a sorted set of live hold IDs scored by expiry time,
+	// drained as soon as holds expire to keep the held counters current
+	heldExpiryKey = "held_expiry"
+	// heldCountGrace is how long a held counter outlives the last hold it
+	// counts, so a decrement that never happened cannot stick
+	heldCountGrace = 5 * time.Minute
+	// bookedSeatsKeyPrefix caches the booked seat IDs per showtime
+	bookedSeatsKeyPrefix = "booked_seats:"
+	// bookedVersionKeyPrefix versions a showtime's booked seat cache; it is
+	// bumped on every invalidation so a slow reader cannot cache stale seats
+	bookedVersionKeyPrefix = "booked_seats_version:"
+	// bookedVersionRetention keeps the version well past any cache entry
+	bookedVersionRetention = 24 * time.Hour
+	// onlineSoldKeyPrefix caches the online seats sold per seat type per
+	// showtime; it shares the booked seat cache's version
+	onlineSoldKeyPrefix = "online_sold:"
+)
+
+// lockSeatsScript locks all of a hold's seats or none of them. A seat
+// locked by another hold fails the whole lock and its index (1-based) is
+// returned; on success nothing is returned.
+var lockSeatsScript = redis.NewScript(`
+local taken = {}
+for i, key in ipairs(KEYS) do
+	local owner = redis.call('GET', key)
+	if owner and owner ~= ARGV[1] then
+		taken[#taken + 1] = i
+	end
+end
+if #taken > 0 then
+	return taken
+end
+for _, key in ipairs(KEYS) do
+	redis.call('SET', key, ARGV[1], 'PX', ARGV[2])
+end
+return taken
+`)
+
+// unlockSeatsScript deletes the seat locks still owned by the hold and
+// returns how many it deleted, so one hold cannot release another's seats
+var unlockSeatsScript = redis.NewScript(`
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call('GET', key) == ARGV[1] then
+		released = released + redis.call('DEL', key)
+	end
+end
+return released
+`)
+
+// extendLocksScript moves the expiry of a hold's seat locks. It fails
+// without changing anything, returning 0, if any lock is no longer owned
+// by the hold.
+var extendLocksScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+	if redis.call('GET', key) ~= ARGV[1] then
+		return 0
+	end
+end
+for _, key in ipairs(KEYS) do
+	redis.call('PEXPIRE', key, ARGV[2])
+end
+return 1
+`)
+
+// cacheBookedScript caches booked seats only if the cache version has not
+// moved since they were read
+var cacheBookedScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// invalidateBookedScript bumps the cache version and drops the cached seats
+// and per-type sales
+var invalidateBookedScript = redis.NewScript(`
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[1])
+redis.call('DEL', KEYS[1], KEYS[3])
+return 1
+`)
+
+// incrHeldScript adds to a held counter and extends its expiry to cover the
+// new hold
+var incrHeldScript = redis.NewScript(`
+local n = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[2]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return n
+`)
+
+// decrHeldScript subtracts from a held counter without going below zero
+var decrHeldScript = redis.NewScript(`
+local n = tonumber(redis.call('GET', KEYS[1]) or '0') - tonumber(ARGV[1])
+if n <= 0 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+redis.call('SET', KEYS[1], n, 'KEEPTTL')
+return n
+`)
+
+// seatHoldRepository implements repository.SeatHoldRepository
+type seatHoldRepository struct {
+	client *Client
+}
+
+// NewSeatHoldRepository creates a new Redis-backed seat hold repository
+func NewSeatHoldRepository(client *Client) repository.SeatHoldRepository {
+	return &seatHoldRepository{client: client}
+}
+
+func holdKey(id string) string {
+	return holdKeyPrefix + id
+}
+
+func holdSnapshotKey(id string) string {
+	return holdSnapshotKeyPrefix + id
+}
+
+func seatLockKey(showtimeID, seatID uuid.UUID) string {
+	return fmt.Sprintf("%s%s:%s", seatLockKeyPrefix, showtimeID, seatID)
+}
+
+func heldCountKey(showtimeID uuid.UUID) string {
+	return heldCountKeyPrefix + showtimeID.String()
+}
+
+func bookedSeatsKey(showtimeID uuid.UUID) string {
+	return bookedSeatsKeyPrefix + showtimeID.String()
+}
+
+func bookedVersionKey(showtimeID uuid.UUID) string {
+	return bookedVersionKeyPrefix + showtimeID.String()
+}
+
+func onlineSoldKey(showtimeID uuid.UUID) string {
+	return onlineSoldKeyPrefix + showtimeID.String()
+}
+
+func (r *seatHoldRepository) Create(ctx context.Context, hold *entity.SeatHold) error {
+	ttl := hold.TTL()
+	if ttl <= 0 {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+
+	seatIDs := hold.SeatIDs()
+	taken, err := lockSeatsScript.Run(ctx, r.client.GetClient(), r.seatLockKeys(hold, seatIDs), hold.ID, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to lock seats")
+	}
+	if len(taken) > 0 {
+		held := make([]string, 0, len(taken))
+		for _, i := range taken {
+			held = append(held, seatIDs[i-1].String())
+		}
+		return apperrors.New(apperrors.CodeSeatNotAvailable, "one or more seats are already held").
+			WithDetails(map[string]any{"seat_ids": held})
+	}
+
+	if err := r.save(ctx, hold, ttl); err != nil {
+		r.unlock(ctx, hold, seatIDs)
+		return err
+	}
+	r.countHeld(ctx, hold.ShowtimeID, len(seatIDs), ttl)
+	return nil
+}
+
+func (r *seatHoldRepository) GetByID(ctx context.Context, id string) (*entity.SeatHold, error) {
+	data, err := r.client.GetClient().Get(ctx, holdKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, apperrors.New(apperrors.CodeNotFound, "hold not found or expired")
+		}
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get hold")
+	}
+
+	var hold entity.SeatHold
+	if err := json.Unmarshal(data, &hold); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to decode hold")
+	}
+	return &hold, nil
+}
+
+func (r *seatHoldRepository) Update(ctx context.Context, hold *entity.SeatHold) error {
+	ttl := hold.TTL()
+	if ttl <= 0 {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+	return r.save(ctx, hold, ttl)
+}
+
+func (r *seatHoldRepository) Extend(ctx context.Context, hold *entity.SeatHold, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 || hold.IsExpired() {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+
+	ok, err := extendLocksScript.Run(ctx, r.client.GetClient(), r.seatLockKeys(hold, hold.SeatIDs()), hold.ID, ttl.Milliseconds()).Bool()
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to extend seat locks")
+	}
+	if !ok {
+		return apperrors.New(apperrors.CodeBookingExpired, "hold has already expired")
+	}
+
+	hold.ExpiresAt = expiresAt
+	if err := r.save(ctx, hold, ttl); err != nil {
+		return err
+	}
+	// Raises the counter's expiry to cover the extended hold
+	r.countHeld(ctx, hold.ShowtimeID, 0, ttl)
+	return nil
+}
+
+func (r *seatHoldRepository) ReleaseSeats(ctx context.Context, hold *entity.SeatHold, seatIDs []uuid.UUID) error {
+	r.unlock(ctx, hold, seatIDs)
+
+	release := entity.UUIDList(seatIDs)
+	remaining := hold.Seats[:0]
+	for _, seat := range hold.Seats {
+		if release.Contains(seat.SeatID) {
+			continue
+		}
+		remaining = append(remaining, seat)
+	}
+	hold.Seats = remaining
+	hold.Reprice()
+
+	if len(hold.Seats) == 0 {
+		r.forget(ctx, hold.ID)
+		return r.client.GetClient().Del(ctx, holdKey(hold.ID)).Err()
+	}
+	return r.Update(ctx, hold)
+}
+
+func (r *seatHoldRepository) Delete(ctx context.Context, hold *entity.SeatHold) error {
+	// A hold is deleted once its seats are booked, so the cached booked
+	// seats must go before the locks; otherwise a probe could see the seats
+	// neither locked nor booked
+	if err := r.InvalidateBookedSeats(ctx, hold.ShowtimeID); err != nil {
+		r.client.logger.Warn("failed to invalidate booked seats", zap.String("hold_id", hold.ID), zap.Error(err))
+	}
+	r.unlock(ctx, hold, hold.SeatIDs())
+	r.forget(ctx, hold.ID)
+	if err := r.client.GetClient().Del(ctx, holdKey(hold.ID)).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete hold")
+	}
+	return nil
+}
+
+func (r *seatHoldRepository) Release(ctx context.Context, hold *entity.SeatHold) (int, error) {
+	released := r.unlock(ctx, hold, hold.SeatIDs())
+	r.forget(ctx, hold.ID)
+	if err := r.client.GetClient().Del(ctx, holdKey(hold.ID)).Err(); err != nil {
+		return released, apperrors.Wrap(err, apperrors.CodeInternal, "failed to delete hold")
+	}
+	return released, nil
+}
+
+// ClaimExpired removes holds that expired before the given time from the
+// expiry index and returns their last stored state. Each hold is returned
+// to exactly one caller even when several instances sweep concurrently.
+func (r *seatHoldRepository) ClaimExpired(ctx context.Context, before time.Time, limit int) ([]*entity.SeatHold, error) {
+	rdb := r.client.GetClient()
+	ids, err := rdb.ZRangeByScore(ctx, holdExpiryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list expired holds")
+	}
+
+	holds := make([]*entity.SeatHold, 0, len(ids))
+	for _, id := range ids {
+		removed, err := rdb.ZRem(ctx, holdExpiryKey, id).Result()
+		if err != nil {
+			return holds, apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim expired hold")
+		}
+		if removed == 0 {
+			// Claimed by another instance
+			continue
+		}
+
+		data, err := rdb.GetDel(ctx, holdSnapshotKey(id)).Bytes()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				r.client.logger.Warn("failed to get hold snapshot", zap.String("hold_id", id), zap.Error(err))
+			}
+			continue
+		}
+
+		var hold entity.SeatHold
+		if err := json.Unmarshal(data, &hold); err != nil {
+			r.client.logger.Warn("failed to decode hold snapshot", zap.String("hold_id", id), zap.Error(err))
+			continue
+		}
+		holds = append(holds, &hold)
+	}
+	return holds, nil
+}
+
+func (r *seatHoldRepository) GetHeldSeatIDs(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(seatIDs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(seatIDs))
+	for i, seatID := range seatIDs {
+		keys[i] = seatLockKey(showtimeID, seatID)
+	}
+
+	owners, err := r.client.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get seat locks")
+	}
+
+	held := make([]uuid.UUID, 0)
+	for i, owner := range owners {
+		if owner != nil {
+			held = append(held, seatIDs[i])
+		}
+	}
+	return held, nil
+}
+
+func (r *seatHoldRepository) ProbeSeats(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) (*entity.SeatProbe, error) {
+	keys := make([]string, 0, len(seatIDs)+2)
+	keys = append(keys, bookedSeatsKey(showtimeID), bookedVersionKey(showtimeID))
+	for _, seatID := range seatIDs {
+		keys = append(keys, seatLockKey(showtimeID, seatID))
+	}
+
+	values, err := r.client.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to probe seats")
+	}
+
+	probe := &entity.SeatProbe{Held: make([]uuid.UUID, 0)}
+	if booked, ok := values[0].(string); ok {
+		if err := json.Unmarshal([]byte(booked), &probe.Booked); err != nil {
+			probe.Booked = nil // treat a corrupt entry as a cache miss
+		} else if probe.Booked == nil {
+			probe.Booked = []uuid.UUID{}
+		}
+	}
+	if version, ok := values[1].(string); ok {
+		probe.Version, _ = strconv.ParseInt(version, 10, 64)
+	}
+	for i, owner := range values[2:] {
+		if owner != nil {
+			probe.Held = append(probe.Held, seatIDs[i])
+		}
+	}
+	return probe, nil
+}
+
+func (r *seatHoldRepository) CacheBookedSeats(ctx context.Context, showtimeID uuid.UUID, version int64, seatIDs []uuid.UUID, ttl time.Duration) error {
+	if seatIDs == nil {
+		seatIDs = []uuid.UUID{}
+	}
+
+	data, err := json.Marshal(seatIDs)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode booked seats")
+	}
+	keys := []string{bookedSeatsKey(showtimeID), bookedVersionKey(showtimeID)}
+	if err := cacheBookedScript.Run(ctx, r.client.GetClient(), keys, strconv.FormatInt(version, 10), data, ttl.Milliseconds()).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to cache booked seats")
+	}
+	return nil
+}
+
+func (r *seatHoldRepository) InvalidateBookedSeats(ctx context.Context, showtimeID uuid.UUID) error {
+	keys := []string{bookedSeatsKey(showtimeID), bookedVersionKey(showtimeID), onlineSoldKey(showtimeID)}
+	if err := invalidateBookedScript.Run(ctx, r.client.GetClient(), keys, bookedVersionRetention.Milliseconds()).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to invalidate booked seats")
+	}
+	return nil
+}
+
+func (r *seatHoldRepository) GetOnlineSold(ctx context.Context, showtimeID uuid.UUID) (map[entity.SeatType]int, int64, error) {
+	values, err := r.client.GetClient().MGet(ctx, onlineSoldKey(showtimeID), bookedVersionKey(showtimeID)).Result()
+	if err != nil {
+		return nil, 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to read cached seat type sales")
+	}
+
+	var version int64
+	if raw, ok := values[1].(string); ok {
+		version, _ = strconv.ParseInt(raw, 10, 64)
+	}
+	raw, ok := values[0].(string)
+	if !ok {
+		return nil, version, nil
+	}
+	var sold map[entity.SeatType]int
+	if err := json.Unmarshal([]byte(raw), &sold); err != nil {
+		// A corrupt entry is treated as a miss and overwritten
+		return nil, version, nil
+	}
+	return sold, version, nil
+}
+
+func (r *seatHoldRepository) CacheOnlineSold(ctx context.Context, showtimeID uuid.UUID, version int64, sold map[entity.SeatType]int, ttl time.Duration) error {
+	if sold == nil {
+		sold = map[entity.SeatType]int{}
+	}
+
+	data, err := json.Marshal(sold)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode seat type sales")
+	}
+	keys := []string{onlineSoldKey(showtimeID), bookedVersionKey(showtimeID)}
+	if err := cacheBookedScript.Run(ctx, r.client.GetClient(), keys, strconv.FormatInt(version, 10), data, ttl.Milliseconds()).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to cache seat type sales")
+	}
+	return nil
+}
+
+func (r *seatHoldRepository) GetHeldCounts(ctx context.Context, showtimeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(showtimeIDs) == 0 {
+		return counts, nil
+	}
+
+	keys := make([]string, len(showtimeIDs))
+	for i, id := range showtimeIDs {
+		keys[i] = heldCountKey(id)
+	}
+
+	values, err := r.client.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to get held seat counts")
+	}
+
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(str); err == nil && n > 0 {
+			counts[showtimeIDs[i]] = n
+		}
+	}
+	return counts, nil
+}
+
+func (r *seatHoldRepository) CountActive(ctx context.Context) (int64, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := r.client.GetClient().ZCount(ctx, heldExpiryKey, now, "+inf").Result()
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "failed to count active holds")
+	}
+	return count, nil
+}
+
+// ExpireHeldCounts drains expired holds from the held expiry index and
+// subtracts their seats from the held counters. Each hold is processed by
+// exactly one caller even when several instances sweep concurrently.
+func (r *seatHoldRepository) ExpireHeldCounts(ctx context.Context, before time.Time, limit int) (int, []*entity.SeatHold, error) {
+	rdb := r.client.GetClient()
+	ids, err := rdb.ZRangeByScore(ctx, heldExpiryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return 0, nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to list expired holds")
+	}
+
+	processed := 0
+	var expired []*entity.SeatHold
+	for _, id := range ids {
+		removed, err := rdb.ZRem(ctx, heldExpiryKey, id).Result()
+		if err != nil {
+			return processed, expired, apperrors.Wrap(err, apperrors.CodeInternal, "failed to claim expired hold")
+		}
+		if removed == 0 {
+			continue
+		}
+		processed++
+
+		// The snapshot outlives the hold and has the seats it still held
+		data, err := rdb.Get(ctx, holdSnapshotKey(id)).Bytes()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				r.client.logger.Warn("failed to get hold snapshot", zap.String("hold_id", id), zap.Error(err))
+			}
+			continue
+		}
+
+		var hold entity.SeatHold
+		if err := json.Unmarshal(data, &hold); err != nil {
+			r.client.logger.Warn("failed to decode hold snapshot", zap.String("hold_id", id), zap.Error(err))
+			continue
+		}
+		r.uncountHeld(ctx, hold.ShowtimeID, len(hold.Seats))
+		expired = append(expired, &hold)
+	}
+	return processed, expired, nil
+}
+
+func (r *seatHoldRepository) save(ctx context.Context, hold *entity.SeatHold, ttl time.Duration) error {
+	data, err := json.Marshal(hold)
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to encode hold")
+	}
+
+	_, err = r.client.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, holdKey(hold.ID), data, ttl)
+		pipe.Set(ctx, holdSnapshotKey(hold.ID), data, ttl+snapshotRetention)
+		pipe.ZAdd(ctx, holdExpiryKey, redis.Z{Score: float64(hold.ExpiresAt.UnixMilli()), Member: hold.ID})
+		pipe.ZAdd(ctx, heldExpiryKey, redis.Z{Score: float64(hold.ExpiresAt.UnixMilli()), Member: hold.ID})
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to store hold")
+	}
+	return nil
+}
+
+// forget drops a hold that ended before expiring from the expiry indexes
+func (r *seatHoldRepository) forget(ctx context.Context, id string) {
+	rdb := r.client.GetClient()
+	for _, key := range []string{holdExpiryKey, heldExpiryKey} {
+		if err := rdb.ZRem(ctx, key, id).Err(); err != nil {
+			r.client.logger.Warn("failed to remove hold from expiry index", zap.String("key", key), zap.String("hold_id", id), zap.Error(err))
+		}
+	}
+	if err := rdb.Del(ctx, holdSnapshotKey(id)).Err(); err != nil {
+		r.client.logger.Warn("failed to delete hold snapshot", zap.String("hold_id", id), zap.Error(err))
+	}
+}
+
+// unlock removes seat locks that are still owned by the hold and returns
+// how many it removed
+func (r *seatHoldRepository) unlock(ctx context.Context, hold *entity.SeatHold, seatIDs []uuid.UUID) int {
+	if len(seatIDs) == 0 {
+		return 0
+	}
+	released, err := unlockSeatsScript.Run(ctx, r.client.GetClient(), r.seatLockKeys(hold, seatIDs), hold.ID).Int()
+	if err != nil {
+		r.client.logger.Warn("failed to release seat locks", zap.String("hold_id", hold.ID), zap.Error(err))
+		return 0
+	}
+	r.uncountHeld(ctx, hold.ShowtimeID, released)
+	return released
+}
+
+// seatLockKeys returns the lock keys of some of a hold's seats
+func (r *seatHoldRepository) seatLockKeys(hold *entity.SeatHold, seatIDs []uuid.UUID) []string {
+	keys := make([]string, len(seatIDs))
+	for i, seatID := range seatIDs {
+		keys[i] = seatLockKey(hold.ShowtimeID, seatID)
+	}
+	return keys
+}
+
+// countHeld adds seats to the showtime's held counter; adding none only
+// extends the counter to cover ttl. The counter is only a summary, so
+// failures are logged rather than failing the hold.
+func (r *seatHoldRepository) countHeld(ctx context.Context, showtimeID uuid.UUID, seats int, ttl time.Duration) {
+	if seats < 0 {
+		return
+	}
+	key := heldCountKey(showtimeID)
+	expiry := (ttl + heldCountGrace).Milliseconds()
+	if err := incrHeldScript.Run(ctx, r.client.GetClient(), []string{key}, seats, expiry).Err(); err != nil {
+		r.client.logger.Warn("failed to count held seats", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// uncountHeld subtracts seats from the showtime's held counter
+func (r *seatHoldRepository) uncountHeld(ctx context.Context, showtimeID uuid.UUID, seats int) {
+	if seats <= 0 {
+		return
+	}
+	key := heldCountKey(showtimeID)
+	if err := decrHeldScript.Run(ctx, r.client.GetClient(), []string{key}, seats).Err(); err != nil {
+		r.client.logger.Warn("failed to uncount held seats", zap.String("key", key), zap.Error(err))
+	}
+}
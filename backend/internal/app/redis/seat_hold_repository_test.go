@@ -0,0 +1,276 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRepository runs the repository against an in-process Redis and
+// returns a showtime to hold seats of
+func newTestRepository(t *testing.T) (*seatHoldRepository, uuid.UUID) {
+	t.Helper()
+	client, _ := newTestClient(t)
+	return &seatHoldRepository{client: client}, uuid.New()
+}
+
+func newTestHold(showtimeID uuid.UUID, ttl time.Duration, seatIDs ...uuid.UUID) *entity.SeatHold {
+	hold := &entity.SeatHold{
+		ID:         uuid.NewString(),
+		ShowtimeID: showtimeID,
+		UserID:     uuid.New(),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+	for _, seatID := range seatIDs {
+		hold.Seats = append(hold.Seats, entity.HeldSeat{SeatID: seatID, Price: 10})
+	}
+	return hold
+}
+
+func lockOwner(t *testing.T, repo *seatHoldRepository, showtimeID, seatID uuid.UUID) string {
+	t.Helper()
+	owner, err := repo.client.GetClient().Get(context.Background(), seatLockKey(showtimeID, seatID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return ""
+	}
+	if err != nil {
+		t.Fatalf("get seat lock: %v", err)
+	}
+	return owner
+}
+
+func TestCreateLocksAllSeatsOrNone(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	first := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("first hold: %v", err)
+	}
+
+	second := newTestHold(showtimeID, time.Minute, b, c)
+	err := repo.Create(ctx, second)
+	if !apperrors.Is(err, apperrors.CodeSeatNotAvailable) {
+		t.Fatalf("overlapping hold = %v, want SEAT_NOT_AVAILABLE", err)
+	}
+	details, _ := err.(*apperrors.AppError).Details.(map[string]any)
+	if taken, _ := details["seat_ids"].([]string); !slices.Equal(taken, []string{b.String()}) {
+		t.Errorf("taken seats = %v, want only %s", details["seat_ids"], b)
+	}
+
+	if owner := lockOwner(t, repo, showtimeID, c); owner != "" {
+		t.Errorf("failed hold left seat c locked by %s", owner)
+	}
+	if owner := lockOwner(t, repo, showtimeID, b); owner != first.ID {
+		t.Errorf("seat b locked by %q, want the first hold", owner)
+	}
+
+	// Locking again with the same hold is not a conflict
+	if err := repo.Create(ctx, first); err != nil {
+		t.Errorf("relock by the owning hold: %v", err)
+	}
+}
+
+func TestUnlockReleasesOnlyOwnSeats(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+	a, b := uuid.New(), uuid.New()
+
+	owner := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.Create(ctx, owner); err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+
+	// A hold claiming the same seats under another ID releases nothing
+	impostor := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.ReleaseSeats(ctx, impostor, []uuid.UUID{a, b}); err != nil {
+		t.Fatalf("impostor release: %v", err)
+	}
+	if lockOwner(t, repo, showtimeID, a) != owner.ID || lockOwner(t, repo, showtimeID, b) != owner.ID {
+		t.Fatal("impostor released the owner's seats")
+	}
+
+	if err := repo.ReleaseSeats(ctx, owner, []uuid.UUID{a}); err != nil {
+		t.Fatalf("owner release: %v", err)
+	}
+	if lockOwner(t, repo, showtimeID, a) != "" || lockOwner(t, repo, showtimeID, b) != owner.ID {
+		t.Error("releasing seat a did not release exactly seat a")
+	}
+	if err := repo.Delete(ctx, owner); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if lockOwner(t, repo, showtimeID, b) != "" {
+		t.Error("seat b still locked after the hold was deleted")
+	}
+}
+
+func TestReleaseUnlocksOnlyTheSeatsStillHeld(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+	a, b := uuid.New(), uuid.New()
+
+	hold := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	// Seat b was given up and someone else took it
+	if err := repo.ReleaseSeats(ctx, hold, []uuid.UUID{b}); err != nil {
+		t.Fatalf("release seat b: %v", err)
+	}
+	other := newTestHold(showtimeID, time.Minute, b)
+	if err := repo.Create(ctx, other); err != nil {
+		t.Fatalf("other hold: %v", err)
+	}
+
+	released, err := repo.Release(ctx, hold)
+	if err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("released %d seats, want 1", released)
+	}
+	if lockOwner(t, repo, showtimeID, a) != "" || lockOwner(t, repo, showtimeID, b) != other.ID {
+		t.Error("release did not free exactly seat a")
+	}
+	if _, err := repo.GetByID(ctx, hold.ID); !apperrors.Is(err, apperrors.CodeNotFound) {
+		t.Errorf("GetByID after release = %v, want not found", err)
+	}
+
+	if released, err := repo.Release(ctx, hold); err != nil || released != 0 {
+		t.Errorf("second Release = %d, %v, want 0", released, err)
+	}
+}
+
+func TestExtendMovesEveryLockOrNone(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+	rdb := repo.client.GetClient()
+	a, b := uuid.New(), uuid.New()
+
+	hold := newTestHold(showtimeID, time.Minute, a, b)
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+
+	if err := repo.Extend(ctx, hold, time.Now().Add(10*time.Minute)); err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	for _, seatID := range []uuid.UUID{a, b} {
+		if ttl := rdb.PTTL(ctx, seatLockKey(showtimeID, seatID)).Val(); ttl < 9*time.Minute {
+			t.Errorf("seat lock expires in %v after extending to 10m", ttl)
+		}
+	}
+
+	// Once a lock has passed to another hold, nothing is extended
+	rdb.Set(ctx, seatLockKey(showtimeID, b), "other-hold", time.Minute)
+	err := repo.Extend(ctx, hold, time.Now().Add(20*time.Minute))
+	if !apperrors.Is(err, apperrors.CodeBookingExpired) {
+		t.Fatalf("extend after losing a seat = %v, want BOOKING_EXPIRED", err)
+	}
+	if ttl := rdb.PTTL(ctx, seatLockKey(showtimeID, a)).Val(); ttl > 10*time.Minute {
+		t.Errorf("failed extend still moved seat a's lock to %v", ttl)
+	}
+	if ttl := rdb.PTTL(ctx, seatLockKey(showtimeID, b)).Val(); ttl > time.Minute {
+		t.Errorf("failed extend moved the other hold's lock to %v", ttl)
+	}
+}
+
+// TestConcurrentOverlappingHolds races holds over overlapping seats: every
+// seat ends up with at most one owner, and every hold that succeeded owns
+// all of its seats
+func TestConcurrentOverlappingHolds(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+
+	seats := make([]uuid.UUID, 8)
+	for i := range seats {
+		seats[i] = uuid.New()
+	}
+
+	// Hold i wants seats i, i+1 and i+2 of a ring, so neighbours overlap
+	const holders = 24
+	holds := make([]*entity.SeatHold, holders)
+	for i := range holds {
+		holds[i] = newTestHold(showtimeID, time.Minute,
+			seats[i%len(seats)], seats[(i+1)%len(seats)], seats[(i+2)%len(seats)])
+	}
+
+	results := make([]error, holders)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range holds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			results[i] = repo.Create(ctx, holds[i])
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	owners := make(map[uuid.UUID]string)
+	succeeded := 0
+	for i, err := range results {
+		switch {
+		case err == nil:
+			succeeded++
+			for _, seatID := range holds[i].SeatIDs() {
+				if previous, ok := owners[seatID]; ok {
+					t.Errorf("seat %s held by %s and %s", seatID, previous, holds[i].ID)
+				}
+				owners[seatID] = holds[i].ID
+				if owner := lockOwner(t, repo, showtimeID, seatID); owner != holds[i].ID {
+					t.Errorf("hold %s succeeded but seat %s is locked by %q", holds[i].ID, seatID, owner)
+				}
+			}
+		case !apperrors.Is(err, apperrors.CodeSeatNotAvailable):
+			t.Errorf("hold %d: %v", i, err)
+		}
+	}
+	if succeeded == 0 {
+		t.Fatal("no hold succeeded")
+	}
+
+	// Seats not owned by a successful hold are not locked at all
+	for _, seatID := range seats {
+		if _, ok := owners[seatID]; !ok {
+			if owner := lockOwner(t, repo, showtimeID, seatID); owner != "" {
+				t.Errorf("seat %s locked by %q, which failed", seatID, owner)
+			}
+		}
+	}
+	t.Logf("%d of %d overlapping holds succeeded", succeeded, holders)
+}
+
+func TestCountActive(t *testing.T) {
+	repo, showtimeID := newTestRepository(t)
+	ctx := context.Background()
+
+	for _, ttl := range []time.Duration{time.Minute, time.Minute, 20 * time.Millisecond} {
+		if err := repo.Create(ctx, newTestHold(showtimeID, ttl, uuid.New())); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	// Another showtime's holds count too
+	if err := repo.Create(ctx, newTestHold(uuid.New(), time.Minute, uuid.New())); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// The short hold has expired but not been swept yet
+	time.Sleep(30 * time.Millisecond)
+	if n, err := repo.CountActive(ctx); err != nil || n != 3 {
+		t.Errorf("CountActive = %d, %v, want 3", n, err)
+	}
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestProbeSeats(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	repo := NewSeatHoldRepository(client)
+
+	showtimeID := uuid.New()
+	free, held, booked := uuid.New(), uuid.New(), uuid.New()
+	hold := &entity.SeatHold{
+		ID:         uuid.NewString(),
+		ShowtimeID: showtimeID,
+		UserID:     uuid.New(),
+		Seats:      []entity.HeldSeat{{SeatID: held, Price: 10}},
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("create hold: %v", err)
+	}
+
+	probe, err := repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{free, held, booked})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if !slices.Equal(probe.Held, []uuid.UUID{held}) {
+		t.Errorf("held = %v, want %v", probe.Held, held)
+	}
+	if probe.Booked != nil {
+		t.Errorf("booked = %v on a cold cache, want a miss", probe.Booked)
+	}
+
+	if err := repo.CacheBookedSeats(ctx, showtimeID, probe.Version, []uuid.UUID{booked}, time.Minute); err != nil {
+		t.Fatalf("cache booked seats: %v", err)
+	}
+	probe, err = repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{free})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if !slices.Equal(probe.Booked, []uuid.UUID{booked}) {
+		t.Errorf("booked = %v, want %v", probe.Booked, booked)
+	}
+
+	// An empty list is cached as such, not as a miss
+	other := uuid.New()
+	if err := repo.CacheBookedSeats(ctx, other, 0, nil, time.Minute); err != nil {
+		t.Fatalf("cache booked seats: %v", err)
+	}
+	if probe, _ := repo.ProbeSeats(ctx, other, []uuid.UUID{free}); probe.Booked == nil || len(probe.Booked) != 0 {
+		t.Errorf("booked = %v, want an empty hit", probe.Booked)
+	}
+}
+
+func TestCacheBookedSeatsRejectsStaleReads(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	repo := NewSeatHoldRepository(client)
+
+	showtimeID, seatID := uuid.New(), uuid.New()
+
+	// A reader probes, then a booking lands and invalidates the cache
+	// before the reader stores what it read from the database
+	probe, err := repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{seatID})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if err := repo.InvalidateBookedSeats(ctx, showtimeID); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	if err := repo.CacheBookedSeats(ctx, showtimeID, probe.Version, nil, time.Minute); err != nil {
+		t.Fatalf("cache booked seats: %v", err)
+	}
+
+	probe, err = repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{seatID})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if probe.Booked != nil {
+		t.Fatalf("booked = %v, the stale read was cached", probe.Booked)
+	}
+
+	// A read at the current version is cached
+	if err := repo.CacheBookedSeats(ctx, showtimeID, probe.Version, []uuid.UUID{seatID}, time.Minute); err != nil {
+		t.Fatalf("cache booked seats: %v", err)
+	}
+	if probe, _ := repo.ProbeSeats(ctx, showtimeID, nil); !slices.Equal(probe.Booked, []uuid.UUID{seatID}) {
+		t.Errorf("booked = %v, want %v", probe.Booked, seatID)
+	}
+}
+
+func TestDeleteHoldInvalidatesBookedSeats(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	repo := NewSeatHoldRepository(client)
+
+	showtimeID, seatID := uuid.New(), uuid.New()
+	hold := &entity.SeatHold{
+		ID:         uuid.NewString(),
+		ShowtimeID: showtimeID,
+		UserID:     uuid.New(),
+		Seats:      []entity.HeldSeat{{SeatID: seatID, Price: 10}},
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("create hold: %v", err)
+	}
+	if err := repo.CacheBookedSeats(ctx, showtimeID, 0, nil, time.Minute); err != nil {
+		t.Fatalf("cache booked seats: %v", err)
+	}
+
+	// The hold is deleted once its seats are booked: the cached list, which
+	// does not have them, must not be served afterwards
+	if err := repo.Delete(ctx, hold); err != nil {
+		t.Fatalf("delete hold: %v", err)
+	}
+	probe, err := repo.ProbeSeats(ctx, showtimeID, []uuid.UUID{seatID})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if probe.Booked != nil {
+		t.Errorf("booked = %v after the hold was deleted, want a miss", probe.Booked)
+	}
+	if len(probe.Held) != 0 {
+		t.Errorf("held = %v after the hold was deleted", probe.Held)
+	}
+	if probe.Version == 0 {
+		t.Error("the cache version did not move")
+	}
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Seat updates are published per showtime on showtime:{id}:seats
+const (
+	seatUpdatesChannelPrefix = "showtime:"
+	seatUpdatesChannelSuffix = ":seats"
+)
+
+// seatUpdateFeed implements repository.SeatUpdateFeed over Redis pub/sub
+type seatUpdateFeed struct {
+	client *Client
+}
+
+// NewSeatUpdateFeed creates a new Redis-backed seat update feed
+func NewSeatUpdateFeed(client *Client) repository.SeatUpdateFeed {
+	return &seatUpdateFeed{client: client}
+}
+
+func seatUpdatesChannel(showtimeID uuid.UUID) string {
+	return seatUpdatesChannelPrefix + showtimeID.String() + seatUpdatesChannelSuffix
+}
+
+func (r *seatUpdateFeed) available() error {
+	if r.client == nil {
+		return apperrors.New(apperrors.CodeInternal, "seat update feed is unavailable")
+	}
+	return nil
+}
+
+func (r *seatUpdateFeed) Publish(ctx context.Context, showtimeID uuid.UUID, updates []repository.SeatUpdate) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	channel := seatUpdatesChannel(showtimeID)
+	_, err := r.client.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, update := range updates {
+			data, err := json.Marshal(update)
+			if err != nil {
+				return err
+			}
+			pipe.Publish(ctx, channel, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to publish seat updates")
+	}
+	return nil
+}
+
+func (r *seatUpdateFeed) Listen(ctx context.Context, handle func(showtimeID uuid.UUID, update []byte)) error {
+	if err := r.available(); err != nil {
+		return err
+	}
+
+	pubsub := r.client.GetClient().PSubscribe(ctx, seatUpdatesChannelPrefix+"*"+seatUpdatesChannelSuffix)
+	defer pubsub.Close()
+	// Fail fast when Redis is down instead of on the first message
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return apperrors.Wrap(err, apperrors.CodeInternal, "failed to subscribe to seat updates")
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return apperrors.New(apperrors.CodeInternal, "seat update subscription closed")
+			}
+			id := strings.TrimSuffix(strings.TrimPrefix(msg.Channel, seatUpdatesChannelPrefix), seatUpdatesChannelSuffix)
+			showtimeID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			handle(showtimeID, []byte(msg.Payload))
+		}
+	}
+}
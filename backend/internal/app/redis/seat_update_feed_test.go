@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestSeatUpdateFeed(t *testing.T) {
+	client, srv := newTestClient(t)
+	feed := NewSeatUpdateFeed(client)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	type received struct {
+		showtimeID uuid.UUID
+		update     repository.SeatUpdate
+	}
+	got := make(chan received, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- feed.Listen(ctx, func(showtimeID uuid.UUID, data []byte) {
+			var update repository.SeatUpdate
+			if err := json.Unmarshal(data, &update); err != nil {
+				t.Errorf("decode %s: %v", data, err)
+			}
+			got <- received{showtimeID, update}
+		})
+	}()
+	for deadline := time.Now().Add(time.Second); srv.PubSubNumPat() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Listen did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	showtimeID := uuid.New()
+	updates := []repository.SeatUpdate{
+		{SeatID: uuid.New(), Status: "LOCKED", UpdatedAt: time.Now().UTC().Truncate(time.Millisecond)},
+		{SeatID: uuid.New(), Status: "AVAILABLE", UpdatedAt: time.Now().UTC().Truncate(time.Millisecond)},
+	}
+	if err := feed.Publish(ctx, showtimeID, updates); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// Other channels are not seat updates
+	srv.Publish("showtime:not-a-uuid:seats", "{}")
+
+	for i, want := range updates {
+		select {
+		case r := <-got:
+			if r.showtimeID != showtimeID || r.update.SeatID != want.SeatID || r.update.Status != want.Status ||
+				!r.update.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("update %d = %+v on %s, want %+v on %s", i, r.update, r.showtimeID, want, showtimeID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("update %d not received", i)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Listen = %v after cancel, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Listen did not return after cancel")
+	}
+	select {
+	case r := <-got:
+		t.Errorf("unexpected update %+v", r)
+	default:
+	}
+}
+
+func TestSeatUpdateFeedRedisDown(t *testing.T) {
+	client, srv := newTestClient(t)
+	feed := NewSeatUpdateFeed(client)
+	srv.Close()
+
+	if err := feed.Publish(context.Background(), uuid.New(), []repository.SeatUpdate{{SeatID: uuid.New(), Status: "BOOKED"}}); err == nil {
+		t.Error("Publish succeeded with Redis down")
+	}
+	if err := feed.Listen(context.Background(), func(uuid.UUID, []byte) {}); err == nil {
+		t.Error("Listen = nil with Redis down, want an error so the caller resubscribes")
+	}
+}
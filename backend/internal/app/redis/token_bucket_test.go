@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	bucket := NewTokenBucket(client)
+	// One token back every 100ms
+	window := 200 * time.Millisecond
+
+	// A new bucket starts full, so a user may burst up to the limit
+	for want := 1; want >= 0; want-- {
+		allowed, remaining, _, err := bucket.Allow(ctx, "user:1", 2, window)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !allowed || remaining != want {
+			t.Fatalf("allowed %v, remaining %d, want allowed with %d left", allowed, remaining, want)
+		}
+	}
+
+	allowed, _, retryAfter, err := bucket.Allow(ctx, "user:1", 2, window)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if allowed {
+		t.Error("third request allowed with the bucket empty")
+	}
+	if retryAfter <= 0 || retryAfter > window/2 {
+		t.Errorf("retry after %s, want within the %s a token takes", retryAfter, window/2)
+	}
+
+	// Another user has a bucket of their own
+	if allowed, _, _, _ := bucket.Allow(ctx, "user:2", 2, window); !allowed {
+		t.Error("another user was limited")
+	}
+
+	// Tokens come back at the refill rate, one at a time
+	time.Sleep(window / 2)
+	if allowed, remaining, _, _ := bucket.Allow(ctx, "user:1", 2, window); !allowed || remaining != 0 {
+		t.Errorf("after one refill allowed %v, remaining %d, want allowed with none left", allowed, remaining)
+	}
+	if allowed, _, _, _ := bucket.Allow(ctx, "user:1", 2, window); allowed {
+		t.Error("allowed a second request after one refill")
+	}
+}
+
+func TestTokenBucketWithoutRedis(t *testing.T) {
+	if _, _, _, err := NewTokenBucket(nil).Allow(context.Background(), "user:1", 2, time.Minute); err == nil {
+		t.Error("Allow without Redis did not fail")
+	}
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// AssistiveDeviceRepository defines the interface for assistive device
+// inventory and reservations
+type AssistiveDeviceRepository interface {
+	// ListByScreen returns a screen's device stock
+	ListByScreen(ctx context.Context, screenID uuid.UUID) ([]*entity.ScreenDevice, error)
+
+	// SetInventory replaces a screen's device stock
+	SetInventory(ctx context.Context, screenID uuid.UUID, devices []*entity.ScreenDevice) error
+
+	// Reserved returns how many devices of each type confirmed bookings for
+	// the showtime have reserved
+	Reserved(ctx context.Context, showtimeID uuid.UUID) (map[entity.DeviceType]int, error)
+
+	// Reserve reserves devices for a booking from its showtime's screen. The
+	// stock is locked while checking, so concurrent reservations cannot
+	// exceed it.
+	Reserve(ctx context.Context, booking *entity.Booking, requests []entity.DeviceRequest) error
+}
@@ -15,6 +15,26 @@ type ShowtimeFilter struct {
 	ScreenID *uuid.UUID
 	Date     time.Time
 	Status   *entity.ShowtimeStatus
+	// ListedOnly leaves out unlisted and private showtimes
+	ListedOnly bool
+	// AvailableOnly leaves out showtimes whose movie or screen has been
+	// deactivated or deleted
+	AvailableOnly bool
+}
+
+// MovieShowtimeFilter selects the showtimes of a movie still to come
+type MovieShowtimeFilter struct {
+	MovieID uuid.UUID
+	// CinemaID keeps one cinema's showtimes; the zero value matches all
+	CinemaID uuid.UUID
+	// City matches the cinema's city, ignoring case; empty matches all
+	City string
+	// DateFrom and DateTo bound the show date, both inclusive
+	DateFrom time.Time
+	DateTo   time.Time
+	// StartsAfter leaves out showtimes that start at or before it, judged
+	// in each cinema's timezone
+	StartsAfter time.Time
 }
 
 // ShowtimeRepository defines the interface for showtime data access
@@ -31,7 +51,9 @@ type ShowtimeRepository interface {
 	// Update updates a showtime
 	Update(ctx context.Context, showtime *entity.Showtime) error
 	
-	// UpdateWithVersion updates a showtime with optimistic locking
+	// UpdateWithVersion updates a showtime only if its version is still the
+	// one it was read at, and bumps the version. It fails with
+	// CodeVersionConflict when the showtime changed in the meantime.
 	UpdateWithVersion(ctx context.Context, showtime *entity.Showtime) error
 	
 	// Delete soft deletes a showtime
@@ -43,33 +65,114 @@ type ShowtimeRepository interface {
 	// GetByDateRange returns showtimes within a date range
 	GetByDateRange(ctx context.Context, cinemaID uuid.UUID, startDate, endDate time.Time) ([]*entity.Showtime, error)
 	
-	// DecrementAvailableSeats decrements available seats using optimistic locking
+	// DecrementAvailableSeats takes count seats off the showtime's
+	// availability and bumps its version, provided the version is still the
+	// given one. It fails with CodeVersionConflict when the version moved and
+	// with CodeSeatNotAvailable when fewer than count seats are left.
 	DecrementAvailableSeats(ctx context.Context, id uuid.UUID, count int, version int) error
 	
 	// IncrementAvailableSeats increments available seats (for cancellations)
+	// and bumps the version
 	IncrementAvailableSeats(ctx context.Context, id uuid.UUID, count int) error
 	
 	// UpdateStatus updates the showtime status
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.ShowtimeStatus) error
 
-	// GetByMovieID returns showtimes for a specific movie
-	GetByMovieID(ctx context.Context, movieID uuid.UUID) ([]*entity.Showtime, error)
+	// ListForMovie returns the scheduled, listed showtimes of a movie at a
+	// page of the cinemas showing it, ordered by cinema name, with their
+	// cinema and screen loaded. Showtimes whose movie or screen is no longer
+	// available are left out. It also returns how many cinemas match.
+	ListForMovie(ctx context.Context, filter MovieShowtimeFilter, offset, limit int) ([]*entity.Showtime, int64, error)
+
+	// ListScheduledForMovie returns the scheduled showtimes of a movie that
+	// start after the given instant, judged in each cinema's timezone
+	ListScheduledForMovie(ctx context.Context, movieID uuid.UUID, after time.Time) ([]*entity.Showtime, error)
+
+	// ListUnavailable returns scheduled showtimes from the given date on whose
+	// movie or screen has been deactivated or deleted
+	ListUnavailable(ctx context.Context, from time.Time) ([]*entity.Showtime, error)
+
+	// GetCapacities returns the given showtimes with only their capacity and
+	// availability columns loaded; unknown IDs are left out
+	GetCapacities(ctx context.Context, ids []uuid.UUID) ([]*entity.Showtime, error)
+
+	// GetWithCinemas returns the given showtimes with their cinema loaded,
+	// deleted ones included; unknown IDs are left out
+	GetWithCinemas(ctx context.Context, ids []uuid.UUID) ([]*entity.Showtime, error)
+
+	// ListStartingBetween returns scheduled showtimes starting within
+	// [from, to) with only their ID and screen loaded
+	ListStartingBetween(ctx context.Context, from, to time.Time) ([]*entity.Showtime, error)
+
+	// CreateBatch creates showtimes on one screen in a single transaction,
+	// skipping those that run within gap of another show on the screen,
+	// whether already scheduled or earlier in the batch. The screen and its
+	// showtimes around the batch are locked first, so concurrent batches
+	// cannot both take a slot. It returns the skipped showtimes.
+	CreateBatch(ctx context.Context, screenID uuid.UUID, showtimes []*entity.Showtime, gap time.Duration) ([]ShowtimeClash, error)
+}
+
+// ShowtimeClash is a showtime left out of a batch and the show it runs too
+// close to
+type ShowtimeClash struct {
+	Showtime *entity.Showtime
+	With     *entity.Showtime
 }
 
 // BookingFilter defines filters for booking queries
 type BookingFilter struct {
 	UserID        *uuid.UUID
 	ShowtimeID    *uuid.UUID
+	CinemaID      *uuid.UUID
+	Reference     string // exact booking reference
+	Email         string // guest email, or the email of the booking's account; matched case-insensitively
 	BookingStatus *entity.BookingStatus
 	PaymentStatus *entity.PaymentStatus
 	DateFrom      *time.Time
 	DateTo        *time.Time
+	Period        BookingPeriod // judged against each showtime's start in its cinema's timezone
+	ShowDateFrom  *time.Time    // first show date, inclusive
+	ShowDateTo    *time.Time    // last show date, inclusive
+	OldestFirst   bool          // order by booked_at ascending instead of newest first
+	CheckedIn     bool          // only bookings checked in at the door, the latest scan first
+}
+
+// BookingPeriod splits a booking history into bookings still to be used,
+// used or lapsed, and cancelled
+type BookingPeriod string
+
+const (
+	// BookingsUpcoming are confirmed bookings for shows yet to start
+	BookingsUpcoming BookingPeriod = "upcoming"
+	// BookingsPast are completed bookings and confirmed bookings for shows
+	// that have started
+	BookingsPast BookingPeriod = "past"
+	// BookingsCancelled are cancelled bookings, refunded or not
+	BookingsCancelled BookingPeriod = "cancelled"
+)
+
+// BookingCursor is the position of a booking in a user's booking history,
+// which runs newest first with the ID breaking ties
+type BookingCursor struct {
+	BookedAt time.Time
+	ID       uuid.UUID
 }
 
 // BookingRepository defines the interface for booking data access
 type BookingRepository interface {
 	// Create creates a new booking
 	Create(ctx context.Context, booking *entity.Booking) error
+
+	// CreateWithSeats creates a booking and its seats and takes the seats off the
+	// showtime's availability in a single transaction. A booking with a promo
+	// code counts a use of the code in the same transaction and fails with
+	// CodeInvalidPromoCode when the code can no longer be applied. Loyalty
+	// points in PointsRedeemed are debited in the same transaction too, failing
+	// with CodeInsufficientPoints when the balance is short. The seats are
+	// taken off under the showtime's version, so it fails with
+	// CodeVersionConflict when another booking changed the showtime first;
+	// nothing is written then and the call can be retried.
+	CreateWithSeats(ctx context.Context, booking *entity.Booking, seats []*entity.BookingSeat) error
 	
 	// GetByID retrieves a booking by ID
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Booking, error)
@@ -85,30 +188,167 @@ type BookingRepository interface {
 	
 	// List returns filtered and paginated bookings
 	List(ctx context.Context, filter BookingFilter, offset, limit int) ([]*entity.Booking, int64, error)
+
+	// Search is List with each booking's account, showtime with its movie,
+	// cinema and screen, seats and payments loaded
+	Search(ctx context.Context, filter BookingFilter, offset, limit int) ([]*entity.Booking, int64, error)
 	
 	// GetByUserID returns all bookings for a user
 	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Booking, int64, error)
+
+	// ListAfter returns up to limit of the bookings matching the filter that
+	// come after the cursor, newest first; from the newest when after is nil.
+	// The filter's ordering options are ignored.
+	ListAfter(ctx context.Context, filter BookingFilter, after *BookingCursor, limit int) ([]*entity.Booking, error)
 	
 	// UpdateStatus updates booking status
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.BookingStatus) error
 	
 	// UpdatePaymentStatus updates payment status
 	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status entity.PaymentStatus) error
+
+	// SetTicketURL records where the booking's e-ticket is downloaded
+	SetTicketURL(ctx context.Context, id uuid.UUID, url string) error
+
+	// CheckIn marks a confirmed, paid booking that has not been checked in
+	// yet COMPLETED, recording when and by which staff user. It fails with
+	// CodeInvalidStatus when the booking is no longer in that state.
+	CheckIn(ctx context.Context, id, staffID uuid.UUID, at time.Time) error
 	
 	// GetExpiredPendingBookings returns pending bookings that have expired
 	GetExpiredPendingBookings(ctx context.Context) ([]*entity.Booking, error)
-	
-	// GetBookingStats returns booking statistics
-	GetBookingStats(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time) (*BookingStats, error)
+
+	// ExpirePending expires up to limit pending bookings whose deadline
+	// passed before the given time: each is marked EXPIRED, its seats are
+	// deleted and returned to the showtime's available seats, and the use of
+	// its promo code and its loyalty points are given back. Each booking
+	// is expired by exactly one caller even when several instances sweep
+	// concurrently.
+	ExpirePending(ctx context.Context, before time.Time, limit int) ([]*entity.Booking, error)
+
+	// Expire expires one pending booking before its deadline, releasing its
+	// seats and promo code use like ExpirePending. It fails with
+	// CodeInvalidStatus when the booking is no longer pending.
+	Expire(ctx context.Context, id uuid.UUID) (*entity.Booking, error)
+
+	// Cancel marks the booking CANCELLED and, in the same transaction,
+	// releases its seats and the use of its promo code like ExpirePending.
+	// A refund above zero also records the refund on the booking and moves
+	// the booking and its paid payments to REFUNDED. The returned booking
+	// lists the released seats. It fails with CodeInvalidStatus when the
+	// booking cannot be cancelled or refunded.
+	Cancel(ctx context.Context, id uuid.UUID, refund float64) (*entity.Booking, error)
+	
+	// GetBookingStats returns the totals of the bookings made in
+	// [dateFrom, dateTo), the same by day, and the top movies by revenue.
+	// Revenue is of paid and refunded bookings, less their refunds.
+	GetBookingStats(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time, top int) (*BookingStats, error)
+
+	// GetBookingTimeSeries buckets the bookings made in [dateFrom, dateTo) by
+	// day, week or month of booking. Occupancy is of the showtimes playing
+	// in each bucket. Buckets without bookings or showtimes are left out.
+	GetBookingTimeSeries(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time, groupBy string) ([]TimeBucket, error)
+
+	// GetTopMovies returns the movies with the most revenue from paid
+	// bookings made in [dateFrom, dateTo)
+	GetTopMovies(ctx context.Context, cinemaID *uuid.UUID, limit int, dateFrom, dateTo time.Time) ([]MovieBookingStats, error)
+
+	// GetRevenueByScreenType returns the paid bookings made in
+	// [dateFrom, dateTo) by the type of screen they are for
+	GetRevenueByScreenType(ctx context.Context, cinemaID *uuid.UUID, dateFrom, dateTo time.Time) ([]ScreenTypeRevenue, error)
+
+	// GetShowtimeSales returns the admissions and revenue of confirmed
+	// bookings for each of a cinema's showtimes on a date
+	GetShowtimeSales(ctx context.Context, cinemaID uuid.UUID, showDate string) ([]entity.ShowtimeSales, error)
+
+	// GetPaymentTotals returns the payments a cinema took within [from, to),
+	// grouped by payment method
+	GetPaymentTotals(ctx context.Context, cinemaID uuid.UUID, from, to time.Time) ([]entity.PaymentMethodTotal, error)
+
+	// GetRefundTotals returns the refunds a cinema issued within [from, to)
+	GetRefundTotals(ctx context.Context, cinemaID uuid.UUID, from, to time.Time) (*entity.RefundTotals, error)
+
+	// ClaimGuestBookings attaches every guest booking made with the email,
+	// compared case-insensitively, to the user and returns how many it
+	// attached. The guest fields are kept.
+	ClaimGuestBookings(ctx context.Context, userID uuid.UUID, email string) (int64, error)
+
+	// ClaimGuestBooking attaches a single guest booking to the user. It
+	// fails with a conflict if the booking already belongs to an account.
+	ClaimGuestBooking(ctx context.Context, id, userID uuid.UUID) error
+
+	// HasBookingForMovie reports whether the user holds a booking in one of
+	// the given statuses for any showtime of the movie
+	HasBookingForMovie(ctx context.Context, userID, movieID uuid.UUID, statuses ...entity.BookingStatus) (bool, error)
 }
 
 // BookingStats holds booking statistics
 type BookingStats struct {
-	TotalBookings     int64   `json:"total_bookings"`
-	ConfirmedBookings int64   `json:"confirmed_bookings"`
-	CancelledBookings int64   `json:"cancelled_bookings"`
-	TotalRevenue      float64 `json:"total_revenue"`
-	AverageTicketPrice float64 `json:"average_ticket_price"`
+	TotalBookings      int64               `json:"total_bookings"`
+	ConfirmedBookings  int64               `json:"confirmed_bookings"`
+	CancelledBookings  int64               `json:"cancelled_bookings"`
+	TicketsSold        int64               `json:"tickets_sold"`  // of paid and refunded bookings
+	GrossRevenue       float64             `json:"gross_revenue"` // paid for tickets, before refunds
+	RefundedAmount     float64             `json:"refunded_amount"`
+	TotalRevenue       float64             `json:"total_revenue"`        // gross revenue less refunds
+	AverageTicketPrice float64             `json:"average_ticket_price"` // gross revenue per ticket sold
+	Daily              []DailyBookingStats `json:"daily"`
+	TopMovies          []MovieBookingStats `json:"top_movies"` // by revenue less refunds
+}
+
+// DailyBookingStats holds the bookings made on one day
+type DailyBookingStats struct {
+	Date              time.Time `json:"date"`
+	TotalBookings     int64     `json:"total_bookings"`
+	ConfirmedBookings int64     `json:"confirmed_bookings"`
+	CancelledBookings int64     `json:"cancelled_bookings"`
+	Revenue           float64   `json:"revenue"` // less refunds
+}
+
+// TimeBucket holds the bookings of one day, week or month
+type TimeBucket struct {
+	Date              time.Time `json:"date"` // start of the bucket
+	TotalBookings     int64     `json:"total_bookings"`
+	Revenue           float64   `json:"revenue"` // of paid bookings
+	CancelledBookings int64     `json:"cancelled_bookings"`
+	OccupancyRate     float64   `json:"occupancy_rate"` // percent of seats sold for the bucket's showtimes
+}
+
+// MovieBookingStats holds a movie's paid bookings
+type MovieBookingStats struct {
+	MovieID  uuid.UUID `json:"movie_id"`
+	Title    string    `json:"title"`
+	Bookings int64     `json:"bookings"`
+	Tickets  int64     `json:"tickets"`
+	Revenue  float64   `json:"revenue"`
+}
+
+// ScreenTypeRevenue holds the paid bookings for one type of screen
+type ScreenTypeRevenue struct {
+	ScreenType entity.ScreenType `json:"screen_type"`
+	Bookings   int64             `json:"bookings"`
+	Tickets    int64             `json:"tickets"`
+	Revenue    float64           `json:"revenue"`
+}
+
+// BookingAnalytics is a booking analytics report
+type BookingAnalytics struct {
+	TimeSeries  []TimeBucket        `json:"time_series"`
+	TopMovies   []MovieBookingStats `json:"top_movies"`
+	ScreenTypes []ScreenTypeRevenue `json:"screen_types"`
+}
+
+// BookingAnalyticsCache caches booking analytics reports by query
+type BookingAnalyticsCache interface {
+	// Get returns a cached report, and false on a miss
+	Get(ctx context.Context, key string) (*BookingAnalytics, bool, error)
+
+	Set(ctx context.Context, key string, report *BookingAnalytics, ttl time.Duration) error
+
+	// GetStats returns cached booking stats, and false on a miss
+	GetStats(ctx context.Context, key string) (*BookingStats, bool, error)
+
+	SetStats(ctx context.Context, key string, stats *BookingStats, ttl time.Duration) error
 }
 
 // BookingSeatRepository defines the interface for booking seat data access
@@ -129,6 +369,74 @@ type BookingSeatRepository interface {
 	Delete(ctx context.Context, bookingID uuid.UUID) error
 }
 
+// SeatHoldRepository defines the interface for temporary seat hold storage
+type SeatHoldRepository interface {
+	// Create locks the hold's seats and stores the hold until it expires
+	Create(ctx context.Context, hold *entity.SeatHold) error
+
+	// GetByID retrieves a hold by ID
+	GetByID(ctx context.Context, id string) (*entity.SeatHold, error)
+
+	// Update overwrites the stored hold payload
+	Update(ctx context.Context, hold *entity.SeatHold) error
+
+	// Extend moves the hold's expiry, and the expiry of its seat locks, to
+	// expiresAt. It fails with CodeBookingExpired when the hold lost any of
+	// its locks.
+	Extend(ctx context.Context, hold *entity.SeatHold, expiresAt time.Time) error
+
+	// ReleaseSeats unlocks some of the hold's seats and drops them from the hold
+	ReleaseSeats(ctx context.Context, hold *entity.SeatHold, seatIDs []uuid.UUID) error
+
+	// Delete unlocks all seats and removes the hold
+	Delete(ctx context.Context, hold *entity.SeatHold) error
+
+	// Release removes a hold its user abandoned, unlocking the seats the
+	// hold still owns, and returns how many seats were unlocked
+	Release(ctx context.Context, hold *entity.SeatHold) (int, error)
+
+	// GetHeldSeatIDs returns which of the given seats are currently locked by any hold
+	GetHeldSeatIDs(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) ([]uuid.UUID, error)
+
+	// ClaimExpired returns up to limit holds that expired before the given
+	// time without being deleted, each to a single caller
+	ClaimExpired(ctx context.Context, before time.Time, limit int) ([]*entity.SeatHold, error)
+
+	// GetHeldCounts returns how many seats of each showtime are held, read
+	// from per-showtime counters; showtimes with nothing held are left out
+	GetHeldCounts(ctx context.Context, showtimeIDs []uuid.UUID) (map[uuid.UUID]int, error)
+
+	// CountActive returns how many holds have not expired yet, across all
+	// showtimes
+	CountActive(ctx context.Context) (int64, error)
+
+	// ExpireHeldCounts takes up to limit holds that expired before the given
+	// time out of the held counters. It returns how many it processed and
+	// those of them whose seats it could still read.
+	ExpireHeldCounts(ctx context.Context, before time.Time, limit int) (int, []*entity.SeatHold, error)
+
+	// ProbeSeats reads which of the given seats are locked, together with the
+	// showtime's cached booked seats, in a single round trip
+	ProbeSeats(ctx context.Context, showtimeID uuid.UUID, seatIDs []uuid.UUID) (*entity.SeatProbe, error)
+
+	// CacheBookedSeats caches a showtime's booked seats read at the probe's
+	// version. The cache is left alone if it was invalidated since.
+	CacheBookedSeats(ctx context.Context, showtimeID uuid.UUID, version int64, seatIDs []uuid.UUID, ttl time.Duration) error
+
+	// InvalidateBookedSeats drops a showtime's cached booked seats and
+	// per-type online sales
+	InvalidateBookedSeats(ctx context.Context, showtimeID uuid.UUID) error
+
+	// GetOnlineSold returns a showtime's cached online sales per seat type,
+	// nil on a miss, with the cache version to cache a fresh count at
+	GetOnlineSold(ctx context.Context, showtimeID uuid.UUID) (map[entity.SeatType]int, int64, error)
+
+	// CacheOnlineSold caches a showtime's online sales per seat type counted
+	// at the given version. The cache is left alone if it was invalidated
+	// since.
+	CacheOnlineSold(ctx context.Context, showtimeID uuid.UUID, version int64, sold map[entity.SeatType]int, ttl time.Duration) error
+}
+
 // PaymentRepository defines the interface for payment data access
 type PaymentRepository interface {
 	// Create creates a new payment
@@ -140,6 +448,10 @@ type PaymentRepository interface {
 	// GetByReference retrieves a payment by reference
 	GetByReference(ctx context.Context, reference string) (*entity.Payment, error)
 	
+	// GetByGatewayTransactionID retrieves a payment by the gateway's ID for
+	// it, such as a Stripe checkout session
+	GetByGatewayTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error)
+	
 	// GetByBookingID retrieves all payments for a booking
 	GetByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*entity.Payment, error)
 	
@@ -148,6 +460,15 @@ type PaymentRepository interface {
 	
 	// UpdateStatus updates payment status
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.PaymentStatus) error
+
+	// ListReconcilable returns pending payments initiated before the given
+	// time that are due a gateway status poll: their booking is still
+	// pending and unexpired, and they have fewer than maxAttempts polls
+	ListReconcilable(ctx context.Context, initiatedBefore time.Time, maxAttempts, limit int) ([]*entity.Payment, error)
+
+	// ScheduleReconcile records how many gateway status polls were made and
+	// when the next one is due
+	ScheduleReconcile(ctx context.Context, id uuid.UUID, attempts int, next time.Time) error
 }
 
 // PromoCodeRepository defines the interface for promo code data access
@@ -173,6 +494,21 @@ type PromoCodeRepository interface {
 	// IncrementUsageCount increments the usage count
 	IncrementUsageCount(ctx context.Context, id uuid.UUID) error
 	
-	// GetUserUsageCount returns how many times a user has used a promo code
-	GetUserUsageCount(ctx context.Context, promoID, userID uuid.UUID) (int, error)
+	// GetUserUsageCount returns how many times an account, or a guest by
+	// email when userID is nil, has used the promo code on bookings that
+	// still hold the use; expired and cancelled ones gave it back
+	GetUserUsageCount(ctx context.Context, promoID uuid.UUID, userID *uuid.UUID, email string) (int, error)
+
+	// CodeExists returns true if a promo code, deleted ones included, already
+	// has the code, compared case-insensitively
+	CodeExists(ctx context.Context, code string) (bool, error)
+
+	// GetStats returns the paid bookings made with a promo code
+	GetStats(ctx context.Context, promoID uuid.UUID) (*PromoCodeStats, error)
+}
+
+// PromoCodeStats holds a promo code's usage statistics
+type PromoCodeStats struct {
+	Bookings      int64   // confirmed and completed bookings made with the code
+	TotalDiscount float64 // discount given on those bookings
 }
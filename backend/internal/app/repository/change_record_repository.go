@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// ChangeRecordFilter holds filter options for listing change records
+type ChangeRecordFilter struct {
+	EntityType    entity.ChangeEntityType
+	EntityID      uuid.UUID
+	From          *time.Time
+	To            *time.Time
+	ExcludeSystem bool // leave out changes made by background jobs
+}
+
+// ChangeRecordRepository defines the interface for entity change history
+type ChangeRecordRepository interface {
+	// Create stores a change record
+	Create(ctx context.Context, record *entity.ChangeRecord) error
+
+	// List returns an entity's change records, newest first
+	List(ctx context.Context, filter ChangeRecordFilter, offset, limit int) ([]*entity.ChangeRecord, int64, error)
+}
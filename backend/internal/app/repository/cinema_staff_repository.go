@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// CinemaStaffRepository defines the interface for cinema staff assignments
+type CinemaStaffRepository interface {
+	// ListUsers returns the active users assigned to a cinema with one of
+	// the roles at that cinema
+	ListUsers(ctx context.Context, cinemaID uuid.UUID, roles ...entity.Role) ([]*entity.User, error)
+
+	// IsAssigned reports whether a user is assigned to a cinema
+	IsAssigned(ctx context.Context, cinemaID, userID uuid.UUID) (bool, error)
+
+	// GetRole returns the user's role at a cinema, or an empty role if the
+	// user is not assigned to it
+	GetRole(ctx context.Context, cinemaID, userID uuid.UUID) (entity.Role, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// DailyReportRepository defines the interface for end-of-day report data access
+type DailyReportRepository interface {
+	// Upsert saves a report, replacing any report for the same cinema and date
+	Upsert(ctx context.Context, report *entity.DailyReport) error
+
+	// Exists reports whether a cinema has a report for the business date
+	Exists(ctx context.Context, cinemaID uuid.UUID, businessDate string) (bool, error)
+
+	// ListByCinema returns a cinema's reports, newest business date first
+	ListByCinema(ctx context.Context, cinemaID uuid.UUID, offset, limit int) ([]*entity.DailyReport, int64, error)
+}
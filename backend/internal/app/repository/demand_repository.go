@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// DemandFilter defines filters for demand stat queries
+type DemandFilter struct {
+	CinemaID *uuid.UUID
+	MovieID  *uuid.UUID
+}
+
+// DemandRepository defines the interface for showtime demand stats
+type DemandRepository interface {
+	// Rollup adds the bookings confirmed after the watermark and up to the
+	// given time to the stats and moves the watermark there, in a single
+	// transaction. It returns how many bookings it added; running it again
+	// for the same time adds nothing.
+	Rollup(ctx context.Context, upTo time.Time) (int64, error)
+
+	// Watermark returns the time bookings have been rolled up to
+	Watermark(ctx context.Context) (time.Time, error)
+
+	// GetDemandProfile returns a movie's stats at a cinema, for the schedule
+	// generator
+	GetDemandProfile(ctx context.Context, movieID, cinemaID uuid.UUID) ([]*entity.ShowtimeDemandStat, error)
+
+	// List returns filtered stats ordered by cinema, movie, day and bucket
+	List(ctx context.Context, filter DemandFilter) ([]*entity.ShowtimeDemandStat, error)
+}
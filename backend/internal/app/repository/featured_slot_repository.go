@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// FeaturedSlotFilter holds filter options for listing featured slots
+type FeaturedSlotFilter struct {
+	Position entity.SlotPosition
+	Type     entity.SlotType
+	ActiveAt *time.Time
+}
+
+// FeaturedSlotRepository defines the interface for homepage curation data access
+type FeaturedSlotRepository interface {
+	// Create creates a new featured slot
+	Create(ctx context.Context, slot *entity.FeaturedSlot) error
+
+	// GetByID retrieves a featured slot with its movie
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.FeaturedSlot, error)
+
+	// Update updates a featured slot
+	Update(ctx context.Context, slot *entity.FeaturedSlot) error
+
+	// Delete soft deletes a featured slot
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List returns featured slots with their movies, by position and sort order
+	List(ctx context.Context, filter FeaturedSlotFilter, offset, limit int) ([]*entity.FeaturedSlot, int64, error)
+
+	// ListActive returns every slot active at the given time with its movie,
+	// by position and sort order
+	ListActive(ctx context.Context, at time.Time) ([]*entity.FeaturedSlot, error)
+
+	// HasOverlap returns true if another slot has the same position and
+	// sort order and an active window overlapping the slot's
+	HasOverlap(ctx context.Context, slot *entity.FeaturedSlot) (bool, error)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"github.com/google/uuid"
+)
+
+// GroupBookingRepository defines the interface for group booking data access
+type GroupBookingRepository interface {
+	// Create creates a group booking session
+	Create(ctx context.Context, group *entity.GroupBooking) error
+
+	// GetByTokenHash retrieves a group booking with its participants by the
+	// hash of its share token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.GroupBooking, error)
+
+	// AddParticipant records a participant and counts them against the
+	// group's limit, failing if the group is no longer open or already full
+	AddParticipant(ctx context.Context, participant *entity.GroupBookingParticipant) error
+
+	// TransitionStatus moves the group from one status to another, failing if
+	// the stored status no longer matches from
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to entity.GroupBookingStatus) error
+
+	// Update saves the group booking fields (not its participants)
+	Update(ctx context.Context, group *entity.GroupBooking) error
+}
+
+// GroupBookingLock serializes changes to one group booking across instances
+type GroupBookingLock interface {
+	// Acquire takes the lock of the group behind the share token, waiting
+	// briefly if it is taken, and returns the function that releases it
+	Acquire(ctx context.Context, token string) (release func(), err error)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"github.com/google/uuid"
+)
+
+// GroupCheckoutRepository defines the interface for split-payment group checkout data access
+type GroupCheckoutRepository interface {
+	// Create creates a group checkout together with its shares
+	Create(ctx context.Context, group *entity.GroupCheckout) error
+
+	// GetByID retrieves a group checkout with its shares
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.GroupCheckout, error)
+
+	// GetActiveByHoldID retrieves the non-terminal group checkout for a hold, or nil
+	GetActiveByHoldID(ctx context.Context, holdID string) (*entity.GroupCheckout, error)
+
+	// ExistsForHold returns true if any group checkout, terminal or not, was
+	// created from the hold
+	ExistsForHold(ctx context.Context, holdID string) (bool, error)
+
+	// Update saves the group checkout fields (not its shares)
+	Update(ctx context.Context, group *entity.GroupCheckout) error
+
+	// TransitionStatus moves the group from one status to another, failing if
+	// the stored status no longer matches from
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to entity.GroupCheckoutStatus) error
+
+	// CreateShare adds a share to an existing group checkout
+	CreateShare(ctx context.Context, share *entity.GroupCheckoutShare) error
+
+	// GetShareByTokenHash retrieves a share by the hash of its pay link token
+	GetShareByTokenHash(ctx context.Context, tokenHash string) (*entity.GroupCheckoutShare, error)
+
+	// GetShareByPaymentReference retrieves a share by its payment reference
+	GetShareByPaymentReference(ctx context.Context, reference string) (*entity.GroupCheckoutShare, error)
+
+	// MarkSharePaid marks a pending share as paid
+	MarkSharePaid(ctx context.Context, id uuid.UUID, gatewayTransactionID string, paidAt time.Time) error
+
+	// UpdateShareStatus moves all shares of a group in status from to status to
+	UpdateShareStatus(ctx context.Context, groupID uuid.UUID, from, to entity.GroupCheckoutShareStatus) error
+
+	// ListLapsed returns open group checkouts with pending shares past their expiry
+	ListLapsed(ctx context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error)
+
+	// ListAbandoned returns group checkouts still waiting on the organizer after their hold expired
+	ListAbandoned(ctx context.Context, now time.Time, limit int) ([]*entity.GroupCheckout, error)
+}
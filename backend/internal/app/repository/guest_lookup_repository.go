@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// GuestLookupRepository stores the throttling state and verification codes
+// of guest booking lookups. Throttle keys identify what is throttled, such
+// as a booking reference or a client IP.
+type GuestLookupRepository interface {
+	// BlockedFor returns the longest remaining block among the keys, or zero
+	// if none of them is blocked
+	BlockedFor(ctx context.Context, keys ...string) (time.Duration, error)
+
+	// RecordFailure counts a failed lookup against the key and returns the
+	// failures counted within the window, which starts at the first failure
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// Block rejects lookups for the key for the given duration
+	Block(ctx context.Context, key string, d time.Duration) error
+
+	// ResetFailures forgets the key's failures; an active block is kept
+	ResetFailures(ctx context.Context, key string) error
+
+	// SaveCode stores the hash of a verification code for a reference,
+	// replacing any earlier code
+	SaveCode(ctx context.Context, reference, codeHash string, ttl time.Duration) error
+
+	// UseCode counts an attempt against the reference's code and returns
+	// its hash with the attempts made so far, or an empty hash if no
+	// unexpired code exists
+	UseCode(ctx context.Context, reference string) (string, int, error)
+
+	// DeleteCode removes the reference's verification code
+	DeleteCode(ctx context.Context, reference string) error
+}
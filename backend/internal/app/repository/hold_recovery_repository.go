@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+	"github.com/google/uuid"
+)
+
+// HoldRecoveryRepository defines the interface for hold recovery email records
+type HoldRecoveryRepository interface {
+	// Create stores a recovery unless the user already has one for the
+	// showtime; created is false in that case
+	Create(ctx context.Context, recovery *entity.HoldRecovery) (created bool, err error)
+
+	// GetByTokenHash retrieves a recovery by the hash of its re-hold token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.HoldRecovery, error)
+
+	// GetByUserAndShowtime retrieves the user's recovery for a showtime, or nil
+	GetByUserAndShowtime(ctx context.Context, userID, showtimeID uuid.UUID) (*entity.HoldRecovery, error)
+
+	// Update saves a recovery
+	Update(ctx context.Context, recovery *entity.HoldRecovery) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyRepository defines the interface for loyalty points data access.
+// Points are redeemed and given back with the booking that uses them, in
+// BookingRepository's transactions.
+type LoyaltyRepository interface {
+	// GetBalance retrieves a user's loyalty account, or nil before the
+	// user has earned any points
+	GetBalance(ctx context.Context, userID uuid.UUID) (*entity.LoyaltyAccount, error)
+
+	// ListTransactions returns a user's loyalty transactions, newest first
+	ListTransactions(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.LoyaltyTransaction, int64, error)
+
+	// AddPoints credits the points a booking earned, opening the user's
+	// account if needed. A booking earns once; it returns false when the
+	// points were already credited.
+	AddPoints(ctx context.Context, userID, bookingID uuid.UUID, points int, description string) (bool, error)
+
+	// ExpireInactive empties up to limit balances of accounts with no
+	// activity since before, recording the points expired, and returns
+	// how many accounts it emptied
+	ExpireInactive(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// MovieMediaRepository defines the interface for movie media gallery data
+// access
+type MovieMediaRepository interface {
+	// Create adds an asset at the end of the movie's gallery
+	Create(ctx context.Context, media *entity.MovieMedia) error
+
+	// GetByID retrieves an asset of a movie
+	GetByID(ctx context.Context, movieID, id uuid.UUID) (*entity.MovieMedia, error)
+
+	// Update updates an asset
+	Update(ctx context.Context, media *entity.MovieMedia) error
+
+	// Delete deletes an asset of a movie
+	Delete(ctx context.Context, movieID, id uuid.UUID) error
+
+	// ListByMovie returns a movie's assets in sort order, optionally only the
+	// published ones
+	ListByMovie(ctx context.Context, movieID uuid.UUID, publishedOnly bool) ([]*entity.MovieMedia, error)
+
+	// Count returns how many assets a movie has
+	Count(ctx context.Context, movieID uuid.UUID) (int64, error)
+
+	// Reorder sets the sort order of a movie's assets to the order of ids
+	Reorder(ctx context.Context, movieID uuid.UUID, ids []uuid.UUID) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"cinemaos-backend/internal/app/entity"
 	"github.com/google/uuid"
@@ -15,6 +16,28 @@ type MovieFilter struct {
 	IsActive    *bool
 	IsNowShowing *bool
 	IsComingSoon *bool
+	// IncludeDeleted lists soft-deleted movies too
+	IncludeDeleted bool
+}
+
+// NowShowingPreferences personalises the now-showing listing. Movies in
+// one of the genres are listed first. With OnlyMatching, only movies in one
+// of the genres or formats, or showing at one of the cinemas, are listed.
+type NowShowingPreferences struct {
+	Genres       []string
+	Formats      []string
+	CinemaIDs    []uuid.UUID
+	OnlyMatching bool
+}
+
+// MoviePopularityStats is what a movie's popularity score is computed from:
+// its paid bookings and their revenue, net of refunds, over a recent window,
+// and its average review rating
+type MoviePopularityStats struct {
+	MovieID  uuid.UUID
+	Bookings int64
+	Revenue  float64
+	Rating   *float64
 }
 
 // MovieRepository defines the interface for movie data access
@@ -34,17 +57,35 @@ type MovieRepository interface {
 	// Delete soft deletes a movie
 	Delete(ctx context.Context, id uuid.UUID) error
 	
+	// Restore undoes the soft delete of a movie
+	Restore(ctx context.Context, id uuid.UUID) error
+	
 	// List returns a filtered and paginated list of movies
 	List(ctx context.Context, filter MovieFilter, offset, limit int) ([]*entity.Movie, int64, error)
 	
 	// GetNowShowing returns movies currently showing
 	GetNowShowing(ctx context.Context, cinemaID *uuid.UUID, offset, limit int) ([]*entity.Movie, int64, error)
+
+	// GetNowShowingFor returns movies currently showing, personalised by a
+	// user's preferences
+	GetNowShowingFor(ctx context.Context, prefs NowShowingPreferences, offset, limit int) ([]*entity.Movie, int64, error)
 	
 	// GetComingSoon returns upcoming movies
 	GetComingSoon(ctx context.Context, offset, limit int) ([]*entity.Movie, int64, error)
 	
 	// UpdatePopularityScore updates a movie's popularity score
 	UpdatePopularityScore(ctx context.Context, id uuid.UUID, score float64) error
+
+	// UpdatePopularityScores updates the popularity scores of many movies in
+	// one transaction
+	UpdatePopularityScores(ctx context.Context, scores map[uuid.UUID]float64) error
+
+	// ListPopularityStats returns the popularity stats of the movies with a
+	// showtime on or after since, counting bookings made since then
+	ListPopularityStats(ctx context.Context, since time.Time) ([]MoviePopularityStats, error)
+	
+	// UpdateReviewStats sets a movie's average user rating and review count
+	UpdateReviewStats(ctx context.Context, id uuid.UUID, rating *float64, count int64) error
 }
 
 // CinemaRepository defines the interface for cinema data access
@@ -67,10 +108,15 @@ type CinemaRepository interface {
 	// List returns a paginated list of cinemas
 	List(ctx context.Context, city string, offset, limit int) ([]*entity.Cinema, int64, error)
 	
+	// Search returns a page of the active cinemas whose name, city or
+	// address match the query, best match first, optionally in one city
+	Search(ctx context.Context, query, city string, offset, limit int) ([]*entity.Cinema, int64, error)
+	
 	// GetWithScreens retrieves a cinema with its screens
 	GetWithScreens(ctx context.Context, id uuid.UUID) (*entity.Cinema, error)
 	
-	// GetNearby returns cinemas near a location
+	// GetNearby returns active cinemas within radiusKm of a location,
+	// nearest first
 	GetNearby(ctx context.Context, latitude, longitude float64, radiusKm float64, limit int) ([]*entity.Cinema, error)
 }
 
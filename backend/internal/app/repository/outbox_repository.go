@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+)
+
+// OutboxRepository defines the interface for event outbox data access
+type OutboxRepository interface {
+	// Add stores events to publish. Called inside Transactor.InTransaction,
+	// they are committed or rolled back with the rest of the work.
+	Add(ctx context.Context, events []*entity.OutboxEvent) error
+
+	// ListUnpublished returns up to limit unpublished events that failed
+	// fewer than maxAttempts times, in publication order
+	ListUnpublished(ctx context.Context, maxAttempts, limit int) ([]*entity.OutboxEvent, error)
+
+	// MarkPublished records that the event was delivered
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+
+	// MarkFailed counts a failed publication attempt and its reason
+	MarkFailed(ctx context.Context, id int64, reason string) error
+
+	// DeletePublishedBefore removes events published before the cutoff and
+	// returns how many were removed
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// PricingRuleRepository defines the interface for cinemas' pricing rules
+type PricingRuleRepository interface {
+	// ListByCinema returns a cinema's pricing rules, oldest first
+	ListByCinema(ctx context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, error)
+
+	// GetByID returns one of a cinema's pricing rules and fails with
+	// CodeNotFound when the cinema has no such rule
+	GetByID(ctx context.Context, cinemaID, id uuid.UUID) (*entity.PricingRule, error)
+
+	Create(ctx context.Context, rule *entity.PricingRule) error
+
+	Update(ctx context.Context, rule *entity.PricingRule) error
+
+	// Delete deletes one of a cinema's pricing rules and fails with
+	// CodeNotFound when the cinema has no such rule
+	Delete(ctx context.Context, cinemaID, id uuid.UUID) error
+}
+
+// PricingRuleCache caches each cinema's pricing rules
+type PricingRuleCache interface {
+	// Get returns a cinema's cached rules, and false on a miss
+	Get(ctx context.Context, cinemaID uuid.UUID) ([]*entity.PricingRule, bool, error)
+
+	Set(ctx context.Context, cinemaID uuid.UUID, rules []*entity.PricingRule, ttl time.Duration) error
+
+	// Invalidate drops a cinema's cached rules
+	Invalidate(ctx context.Context, cinemaID uuid.UUID) error
+}
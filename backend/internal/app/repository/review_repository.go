@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// ReviewRepository defines the interface for movie review data access
+type ReviewRepository interface {
+	// Create creates a new review
+	Create(ctx context.Context, review *entity.Review) error
+
+	// GetByID retrieves a review of a movie with its author
+	GetByID(ctx context.Context, movieID, id uuid.UUID) (*entity.Review, error)
+
+	// GetByUserAndMovie retrieves a user's review of a movie, or nil when
+	// there is none
+	GetByUserAndMovie(ctx context.Context, userID, movieID uuid.UUID) (*entity.Review, error)
+
+	// GetByMovieID returns a movie's reviews with their authors, newest first
+	GetByMovieID(ctx context.Context, movieID uuid.UUID, offset, limit int) ([]*entity.Review, int64, error)
+
+	// GetByUserID returns a user's reviews, newest first
+	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Review, int64, error)
+
+	// GetAverageRating returns the average rating and the number of a
+	// movie's reviews; the average is zero without reviews
+	GetAverageRating(ctx context.Context, movieID uuid.UUID) (float64, int64, error)
+
+	// Delete soft-deletes a review of a movie
+	Delete(ctx context.Context, movieID, id uuid.UUID) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// SeatTypeRuleRepository defines the interface for per-seat-type online
+// sales rules of showtimes
+type SeatTypeRuleRepository interface {
+	// ListByShowtime returns a showtime's seat type rules
+	ListByShowtime(ctx context.Context, showtimeID uuid.UUID) ([]*entity.SeatTypeRule, error)
+
+	// Replace replaces a showtime's seat type rules
+	Replace(ctx context.Context, showtimeID uuid.UUID, rules []*entity.SeatTypeRule) error
+
+	// CountOnlineSold returns how many seats of each type the showtime's
+	// active online bookings hold
+	CountOnlineSold(ctx context.Context, showtimeID uuid.UUID) (map[entity.SeatType]int, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeatUpdate is the new status of one seat of a showtime. Status takes the
+// seat map's values: AVAILABLE, LOCKED or BOOKED.
+type SeatUpdate struct {
+	SeatID    uuid.UUID `json:"seat_id"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SeatUpdateFeed carries seat status changes to every API instance, so
+// clients watching a showtime hear of holds and bookings made anywhere
+type SeatUpdateFeed interface {
+	// Publish sends updates to the seats of a showtime
+	Publish(ctx context.Context, showtimeID uuid.UUID, updates []SeatUpdate) error
+
+	// Listen calls handle with each published update, encoded as JSON,
+	// until ctx is done or the subscription fails
+	Listen(ctx context.Context, handle func(showtimeID uuid.UUID, update []byte)) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+)
+
+// TicketCheckInRepository defines the interface for the audit log of
+// ticket scans
+type TicketCheckInRepository interface {
+	// Create records a ticket scan
+	Create(ctx context.Context, checkIn *entity.TicketCheckIn) error
+}
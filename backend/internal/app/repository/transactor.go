@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// Transactor runs work spanning several repositories in one database
+// transaction. Repository calls made with the context passed to fn are
+// part of it; it commits when fn returns nil and rolls back otherwise.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
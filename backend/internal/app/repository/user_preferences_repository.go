@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// UserPreferencesRepository defines the interface for user preferences data
+// access
+type UserPreferencesRepository interface {
+	// GetByUserID retrieves a user's preferences, or nil before the user
+	// saved any
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserPreferences, error)
+
+	// Upsert creates or replaces a user's preferences
+	Upsert(ctx context.Context, prefs *entity.UserPreferences) error
+}
@@ -17,6 +17,9 @@ type UserRepository interface {
 	
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+
+	// GetByGoogleID retrieves the user signing in with a Google account
+	GetByGoogleID(ctx context.Context, googleID string) (*entity.User, error)
 	
 	// Update updates a user
 	Update(ctx context.Context, user *entity.User) error
@@ -27,6 +30,9 @@ type UserRepository interface {
 	// List returns a paginated list of users
 	List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error)
 	
+	// ListByRoles returns active users holding any of the roles
+	ListByRoles(ctx context.Context, roles ...entity.Role) ([]*entity.User, error)
+	
 	// UpdatePassword updates the user's password
 	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
 	
@@ -53,9 +59,18 @@ type RefreshTokenRepository interface {
 	
 	// Revoke revokes a token
 	Revoke(ctx context.Context, id uuid.UUID) error
+
+	// Rotate revokes a token in favour of its successor and stores the
+	// successor. It returns false, storing nothing, when the token was
+	// already revoked, so only one of concurrent rotations wins.
+	Rotate(ctx context.Context, id uuid.UUID, next *entity.RefreshToken) (bool, error)
 	
 	// RevokeAllForUser revokes all tokens for a user
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+
+	// RevokeAllForUserExcept revokes all of a user's tokens but one and
+	// returns how many it revoked
+	RevokeAllForUserExcept(ctx context.Context, userID, keepID uuid.UUID) (int64, error)
 	
 	// DeleteExpired deletes all expired tokens
 	DeleteExpired(ctx context.Context) error
@@ -90,9 +105,19 @@ type EmailVerificationTokenRepository interface {
 	// GetByTokenHash retrieves a token by its hash
 	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error)
 	
+	// GetLatestByUserID retrieves the latest unused token for a user
+	GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*entity.EmailVerificationToken, error)
+	
 	// MarkUsed marks a token as used
 	MarkUsed(ctx context.Context, id uuid.UUID) error
 	
+	// Redeem marks a token used and its user's email verified in one
+	// transaction. It fails if the token was used or expired meanwhile.
+	Redeem(ctx context.Context, id uuid.UUID) error
+	
 	// DeleteExpired deletes all expired tokens
 	DeleteExpired(ctx context.Context) error
+	
+	// InvalidateAllForUser invalidates all tokens for a user
+	InvalidateAllForUser(ctx context.Context, userID uuid.UUID) error
 }
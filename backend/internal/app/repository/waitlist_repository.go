@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistRepository defines the interface for showtime waitlist data access
+type WaitlistRepository interface {
+	// Enqueue adds the entry at the back of its showtime's queue and sets
+	// its position. It fails with a conflict if the user or guest email is
+	// already waiting for the showtime.
+	Enqueue(ctx context.Context, entry *entity.WaitlistEntry) error
+
+	// Dequeue takes the first waiting entry of the showtime that asks for
+	// at most maxSeats seats and marks it NOTIFIED, so no two callers are
+	// given the same entry. It returns nil when no entry fits.
+	Dequeue(ctx context.Context, showtimeID uuid.UUID, maxSeats int) (*entity.WaitlistEntry, error)
+
+	// GetByUser retrieves the user's latest entry for the showtime
+	GetByUser(ctx context.Context, showtimeID, userID uuid.UUID) (*entity.WaitlistEntry, error)
+
+	// CountAhead returns how many entries are still waiting in front of the
+	// entry
+	CountAhead(ctx context.Context, entry *entity.WaitlistEntry) (int64, error)
+
+	// Notify records when a dequeued entry was told about the open seats
+	// and the hold made for it, if any
+	Notify(ctx context.Context, id uuid.UUID, holdID *string, at time.Time) error
+
+	// ExpireWaiting marks every entry still waiting for the showtime
+	// EXPIRED and returns how many it expired
+	ExpireWaiting(ctx context.Context, showtimeID uuid.UUID) (int64, error)
+}
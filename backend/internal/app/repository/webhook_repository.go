@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"github.com/google/uuid"
+)
+
+// WebhookEventFilter holds filter options for listing webhook events
+type WebhookEventFilter struct {
+	Provider         string
+	EventType        string
+	Statuses         []entity.WebhookStatus
+	PaymentReference string
+	ReceivedFrom     *time.Time
+	ReceivedTo       *time.Time
+}
+
+// WebhookEventRepository defines the interface for webhook inbox data access
+type WebhookEventRepository interface {
+	// Create stores a received event. If the provider already delivered an
+	// event with the same ID, the stored event is returned instead and
+	// created is false.
+	Create(ctx context.Context, event *entity.WebhookEvent) (stored *entity.WebhookEvent, created bool, err error)
+
+	// GetByID retrieves a webhook event by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEvent, error)
+
+	// List returns webhook events matching the filter, newest first
+	List(ctx context.Context, filter WebhookEventFilter, offset, limit int) ([]*entity.WebhookEvent, int64, error)
+
+	// IncrementAttempts records that processing of the event started
+	IncrementAttempts(ctx context.Context, id uuid.UUID) error
+
+	// Update saves the event's processing outcome
+	Update(ctx context.Context, event *entity.WebhookEvent) error
+
+	// ListStale returns unprocessed events received before the cutoff that
+	// have not been alerted on yet
+	ListStale(ctx context.Context, before time.Time, limit int) ([]*entity.WebhookEvent, error)
+
+	// MarkAlerted records that an alert was sent for the events
+	MarkAlerted(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
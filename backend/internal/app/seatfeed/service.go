@@ -0,0 +1,60 @@
+package seatfeed
+
+import (
+	"context"
+	"time"
+
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/ws"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// retryDelay is how long Run waits before resubscribing after the feed failed
+const retryDelay = 5 * time.Second
+
+// Service relays the seat updates published by every instance to the
+// clients of this instance watching the showtimes
+type Service struct {
+	feed   repository.SeatUpdateFeed
+	hub    *ws.Hub
+	logger *logger.Logger
+}
+
+// NewService creates a new seat feed service
+func NewService(feed repository.SeatUpdateFeed, hub *ws.Hub, logger *logger.Logger) *Service {
+	return &Service{
+		feed:   feed,
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// Run relays seat updates until ctx is done, resubscribing when the feed
+// fails. Updates published while it is down are lost; clients reload the
+// seat map when they reconnect.
+func (s *Service) Run(ctx context.Context) {
+	for {
+		err := s.feed.Listen(ctx, func(showtimeID uuid.UUID, update []byte) {
+			s.hub.Broadcast(showtimeID.String(), update)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn("seat update feed stopped, resubscribing", zap.Duration("retry_in", retryDelay), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// Watch subscribes to the seat updates of a showtime. The caller closes the
+// subscription when done.
+func (s *Service) Watch(showtimeID uuid.UUID) *ws.Subscription {
+	return s.hub.Subscribe(showtimeID.String())
+}
@@ -0,0 +1,130 @@
+// Package shadowread wraps repositories so rewritten read paths can be
+// verified against the implementations serving traffic. Every call returns
+// the primary result; sampled calls also run the candidate and log any
+// difference.
+package shadowread
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/shadow"
+
+	"github.com/google/uuid"
+)
+
+// Comparison names, used in config, logs and stats
+const (
+	ComparisonBookingList = "booking_list"
+	ComparisonBookedSeats = "seat_map_booked_seats"
+)
+
+// bookingRepository shadows the booking list read path
+type bookingRepository struct {
+	repository.BookingRepository
+	candidate repository.BookingRepository
+	reader    *shadow.Reader
+}
+
+// NewBookingRepository wraps primary so list reads are compared with candidate
+func NewBookingRepository(primary, candidate repository.BookingRepository, reader *shadow.Reader) repository.BookingRepository {
+	return &bookingRepository{
+		BookingRepository: primary,
+		candidate:         candidate,
+		reader:            reader,
+	}
+}
+
+type bookingPage struct {
+	bookings []*entity.Booking
+	total    int64
+}
+
+// bookingRecord holds the booking fields a list read must agree on
+type bookingRecord struct {
+	ID            uuid.UUID
+	Reference     string
+	BookingStatus entity.BookingStatus
+	PaymentStatus entity.PaymentStatus
+	FinalAmount   float64
+	BookedAt      time.Time
+}
+
+func normalizeBookingPage(page bookingPage) any {
+	records := make([]bookingRecord, len(page.bookings))
+	for i, b := range page.bookings {
+		records[i] = bookingRecord{
+			ID:            b.ID,
+			Reference:     b.BookingReference,
+			BookingStatus: b.BookingStatus,
+			PaymentStatus: b.PaymentStatus,
+			FinalAmount:   b.FinalAmount,
+			BookedAt:      b.BookedAt.UTC(),
+		}
+	}
+	return struct {
+		Total    int64
+		Bookings []bookingRecord
+	}{page.total, records}
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter repository.BookingFilter, offset, limit int) ([]*entity.Booking, int64, error) {
+	page, err := shadow.Read(ctx, r.reader, ComparisonBookingList,
+		func(ctx context.Context) (bookingPage, error) {
+			bookings, total, err := r.BookingRepository.List(ctx, filter, offset, limit)
+			return bookingPage{bookings, total}, err
+		},
+		func(ctx context.Context) (bookingPage, error) {
+			bookings, total, err := r.candidate.List(ctx, filter, offset, limit)
+			return bookingPage{bookings, total}, err
+		},
+		normalizeBookingPage,
+	)
+	return page.bookings, page.total, err
+}
+
+func (r *bookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*entity.Booking, int64, error) {
+	return r.List(ctx, repository.BookingFilter{UserID: &userID}, offset, limit)
+}
+
+// bookingSeatRepository shadows the booked seat read behind the seat map
+type bookingSeatRepository struct {
+	repository.BookingSeatRepository
+	candidate repository.BookingSeatRepository
+	reader    *shadow.Reader
+}
+
+// NewBookingSeatRepository wraps primary so booked seat reads are compared
+// with candidate
+func NewBookingSeatRepository(primary, candidate repository.BookingSeatRepository, reader *shadow.Reader) repository.BookingSeatRepository {
+	return &bookingSeatRepository{
+		BookingSeatRepository: primary,
+		candidate:             candidate,
+		reader:                reader,
+	}
+}
+
+// normalizeSeatIDs ignores order, which neither query guarantees
+func normalizeSeatIDs(ids []uuid.UUID) any {
+	sorted := make([]string, len(ids))
+	for i, id := range ids {
+		sorted[i] = id.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func (r *bookingSeatRepository) GetBookedSeatIDs(ctx context.Context, showtimeID uuid.UUID) ([]uuid.UUID, error) {
+	return shadow.Read(ctx, r.reader, ComparisonBookedSeats,
+		func(ctx context.Context) ([]uuid.UUID, error) {
+			return r.BookingSeatRepository.GetBookedSeatIDs(ctx, showtimeID)
+		},
+		func(ctx context.Context) ([]uuid.UUID, error) {
+			return r.candidate.GetBookedSeatIDs(ctx, showtimeID)
+		},
+		normalizeSeatIDs,
+	)
+}
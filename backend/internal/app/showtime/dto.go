@@ -1,17 +1,20 @@
 package showtime
 
 import (
+	"time"
 
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/pkg/apiversion"
 
 	"github.com/google/uuid"
 )
 
 // ShowtimeResponse represents a showtime in responses
 type ShowtimeResponse struct {
-	ID              uuid.UUID `json:"id"`
-	CinemaID        uuid.UUID `json:"cinema_id"`
-	ScreenID        uuid.UUID `json:"screen_id"`
-	MovieID         uuid.UUID `json:"movie_id"`
+	ID              uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	CinemaID        uuid.UUID `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	ScreenID        uuid.UUID `json:"screen_id" swaggertype:"string" format:"uuid"`
+	MovieID         uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
 	ShowDate        string    `json:"show_date"` // YYYY-MM-DD
 	StartTime       string    `json:"start_time"` // HH:MM
 	EndTime         string    `json:"end_time"`   // HH:MM
@@ -20,20 +23,131 @@ type ShowtimeResponse struct {
 	TotalSeats      int       `json:"total_seats"`
 	AvailableSeats  int       `json:"available_seats"`
 	Status          string    `json:"status"`
+	SalesState      string     `json:"sales_state"`
+	SalesOpenAt     *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt    time.Time  `json:"sales_close_at"`
+	HasPresale      bool       `json:"has_presale"`
+	Capacity        int        `json:"capacity"` // seats that can be sold after capacity overrides
+	CapacityLimit   *int       `json:"capacity_limit,omitempty"`
+	Distancing      bool       `json:"distancing"`
+	SoldOut         bool       `json:"sold_out"`
+	Visibility      string     `json:"visibility"`
 	CinemaName      string    `json:"cinema_name,omitempty"`
 	ScreenName      string    `json:"screen_name,omitempty"`
 	MovieTitle      string    `json:"movie_title,omitempty"`
+
+	// Set when the movie or screen was deactivated or deleted after the
+	// showtime was scheduled; their names are still filled in
+	MovieUnavailable  bool `json:"movie_unavailable"`
+	ScreenUnavailable bool `json:"screen_unavailable"`
+
+	Availability *AvailabilityResponse `json:"availability,omitempty"` // set in listings
+}
+
+// ShowtimeResponseV2 is the v2 shape of a showtime, with money in cents
+type ShowtimeResponseV2 struct {
+	ID             uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	CinemaID       uuid.UUID `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	ScreenID       uuid.UUID `json:"screen_id" swaggertype:"string" format:"uuid"`
+	MovieID        uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	ShowDate       string    `json:"show_date"`
+	StartTime      string    `json:"start_time"`
+	EndTime        string    `json:"end_time"`
+	PriceTier      string    `json:"price_tier"`
+	BasePriceCents int64     `json:"base_price_cents"`
+	TotalSeats     int       `json:"total_seats"`
+	AvailableSeats int       `json:"available_seats"`
+	Status         string     `json:"status"`
+	SalesState     string     `json:"sales_state"`
+	SalesOpenAt    *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt   time.Time  `json:"sales_close_at"`
+	HasPresale     bool       `json:"has_presale"`
+	Capacity       int        `json:"capacity"`
+	CapacityLimit  *int       `json:"capacity_limit,omitempty"`
+	Distancing     bool       `json:"distancing"`
+	SoldOut        bool       `json:"sold_out"`
+	Visibility     string     `json:"visibility"`
+	CinemaName     string    `json:"cinema_name,omitempty"`
+	ScreenName     string    `json:"screen_name,omitempty"`
+	MovieTitle     string    `json:"movie_title,omitempty"`
+
+	MovieUnavailable  bool `json:"movie_unavailable"`
+	ScreenUnavailable bool `json:"screen_unavailable"`
+
+	Availability *AvailabilityResponse `json:"availability,omitempty"`
+}
+
+// ForVersion returns the showtime shape for the given API version
+func (r *ShowtimeResponse) ForVersion(v apiversion.Version) any {
+	if v == apiversion.V1 {
+		return r
+	}
+	return &ShowtimeResponseV2{
+		ID:             r.ID,
+		CinemaID:       r.CinemaID,
+		ScreenID:       r.ScreenID,
+		MovieID:        r.MovieID,
+		ShowDate:       r.ShowDate,
+		StartTime:      r.StartTime,
+		EndTime:        r.EndTime,
+		PriceTier:      r.PriceTier,
+		BasePriceCents: apiversion.Cents(r.BasePrice),
+		TotalSeats:     r.TotalSeats,
+		AvailableSeats: r.AvailableSeats,
+		Status:         r.Status,
+		SalesState:     r.SalesState,
+		SalesOpenAt:    r.SalesOpenAt,
+		SalesCloseAt:   r.SalesCloseAt,
+		HasPresale:     r.HasPresale,
+		Capacity:       r.Capacity,
+		CapacityLimit:  r.CapacityLimit,
+		Distancing:     r.Distancing,
+		SoldOut:        r.SoldOut,
+		Visibility:     r.Visibility,
+		CinemaName:     r.CinemaName,
+		ScreenName:     r.ScreenName,
+		MovieTitle:     r.MovieTitle,
+		MovieUnavailable:  r.MovieUnavailable,
+		ScreenUnavailable: r.ScreenUnavailable,
+		Availability:   r.Availability,
+	}
+}
+
+// Availability badges shown in the showtime picker
+const (
+	AvailabilityPlenty  = "PLENTY"
+	AvailabilityLimited = "LIMITED"
+	AvailabilitySoldOut = "SOLD_OUT"
+)
+
+// MaxAvailabilityIDs caps the showtimes in one availability request
+const MaxAvailabilityIDs = 100
+
+// AvailabilityResponse summarizes how many seats of a showtime can still be
+// bought, without the seat map
+type AvailabilityResponse struct {
+	ShowtimeID uuid.UUID `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Available  int       `json:"available"` // sellable seats neither booked nor held
+	Capacity   int       `json:"capacity"`  // seats that can be sold after capacity overrides
+	Held       int       `json:"held"`
+	Badge      string    `json:"badge"` // PLENTY, LIMITED or SOLD_OUT
 }
 
 // CreateShowtimeRequest represents request to create a showtime
 type CreateShowtimeRequest struct {
-	CinemaID  uuid.UUID `json:"cinema_id" validate:"required"`
-	ScreenID  uuid.UUID `json:"screen_id" validate:"required"`
-	MovieID   uuid.UUID `json:"movie_id" validate:"required"`
+	CinemaID  uuid.UUID `json:"cinema_id" validate:"required" swaggertype:"string" format:"uuid"`
+	ScreenID  uuid.UUID `json:"screen_id" validate:"required" swaggertype:"string" format:"uuid"`
+	MovieID   uuid.UUID `json:"movie_id" validate:"required" swaggertype:"string" format:"uuid"`
 	ShowDate  string    `json:"show_date" validate:"required,datetime=2006-01-02"`
 	StartTime string    `json:"start_time" validate:"required,datetime=15:04"`
 	PriceTier string    `json:"price_tier" validate:"omitempty,oneof=STANDARD PREMIUM DISCOUNT HOLIDAY"`
 	BasePrice float64   `json:"base_price" validate:"required,min=0"`
+
+	// Optional sales window, in absolute time; defaults to on sale now until start
+	SalesOpenAt  *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt *time.Time `json:"sales_close_at,omitempty"`
+	// Optional pre-sale access code that bypasses SalesOpenAt; stored hashed
+	PresaleCode string `json:"presale_code,omitempty" validate:"omitempty,min=4,max=64"`
 }
 
 // UpdateShowtimeRequest represents request to update a showtime
@@ -43,12 +157,211 @@ type UpdateShowtimeRequest struct {
 	PriceTier string  `json:"price_tier" validate:"omitempty,oneof=STANDARD PREMIUM DISCOUNT HOLIDAY"`
 	BasePrice float64 `json:"base_price" validate:"omitempty,min=0"`
 	Status    string  `json:"status" validate:"omitempty,oneof=SCHEDULED ONGOING COMPLETED CANCELLED"`
+
+	SalesOpenAt  *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt *time.Time `json:"sales_close_at,omitempty"`
+	PresaleCode      string `json:"presale_code,omitempty" validate:"omitempty,min=4,max=64"` // replaces the current code
+	ClearPresaleCode bool   `json:"clear_presale_code,omitempty"`
+	// Reset the window to the defaults (on sale now, closes at start)
+	ClearSalesWindow bool `json:"clear_sales_window,omitempty"`
+}
+
+// UpdateCapacityRequest represents request to change a showtime's capacity
+// overrides and visibility. Omitted fields are left unchanged.
+type UpdateCapacityRequest struct {
+	CapacityLimit      *int   `json:"capacity_limit,omitempty" validate:"omitempty,min=0"`
+	ClearCapacityLimit bool   `json:"clear_capacity_limit,omitempty"`
+	Distancing         *bool  `json:"distancing,omitempty"` // block a checkerboard of seats
+	Visibility         string `json:"visibility,omitempty" validate:"omitempty,oneof=PUBLIC UNLISTED PRIVATE"`
+	// Access code for PRIVATE showtimes; stored hashed and required when
+	// switching to PRIVATE without one
+	AccessCode string `json:"access_code,omitempty" validate:"omitempty,min=4,max=64"`
+}
+
+// UpdateSeatTypeRulesRequest replaces a showtime's per-seat-type online
+// sales rules. Seat types left out are sold online without restriction.
+type UpdateSeatTypeRulesRequest struct {
+	Rules []SeatTypeRuleRequest `json:"rules" validate:"max=7,dive"`
+}
+
+// SeatTypeRuleRequest restricts online sales of one seat type
+type SeatTypeRuleRequest struct {
+	SeatType string `json:"seat_type" validate:"required,oneof=STANDARD PREMIUM VIP WHEELCHAIR COUPLE RECLINER COMPANION"`
+	// OnlineSalesCap is how many seats of the type may be sold online; the
+	// rest are kept for the box office
+	OnlineSalesCap *int `json:"online_sales_cap,omitempty" validate:"omitempty,min=0"`
+	// SalesOpenAt delays online sales of the type past the showtime's opening
+	SalesOpenAt *time.Time `json:"sales_open_at,omitempty"`
+}
+
+// SeatTypeRuleResponse represents a seat type rule in responses
+type SeatTypeRuleResponse struct {
+	SeatType       string     `json:"seat_type"`
+	OnlineSalesCap *int       `json:"online_sales_cap,omitempty"`
+	SalesOpenAt    *time.Time `json:"sales_open_at,omitempty"`
+	OnlineSold     int        `json:"online_sold"` // seats of the type booked online
+}
+
+func toSeatTypeRuleResponses(rules []*entity.SeatTypeRule, sold map[entity.SeatType]int) []SeatTypeRuleResponse {
+	responses := make([]SeatTypeRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		responses = append(responses, SeatTypeRuleResponse{
+			SeatType:       string(rule.SeatType),
+			OnlineSalesCap: rule.OnlineSalesCap,
+			SalesOpenAt:    rule.SalesOpenAt,
+			OnlineSold:     sold[rule.SeatType],
+		})
+	}
+	return responses
+}
+
+// BulkCreateShowtimesRequest schedules a movie on a screen at the same
+// times every day of a date range
+type BulkCreateShowtimesRequest struct {
+	ScreenID  uuid.UUID `json:"screen_id" validate:"required" swaggertype:"string" format:"uuid"`
+	MovieID   uuid.UUID `json:"movie_id" validate:"required" swaggertype:"string" format:"uuid"`
+	StartDate string    `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string    `json:"end_date" validate:"required,datetime=2006-01-02"` // inclusive
+	ShowTimes []string  `json:"show_times" validate:"required,min=1,max=24,dive,datetime=15:04"`
+	PriceTier string    `json:"price_tier" validate:"omitempty,oneof=STANDARD PREMIUM DISCOUNT HOLIDAY"`
+	BasePrice float64   `json:"base_price" validate:"required,min=0"`
+}
+
+// SkippedShowtime is a slot of a bulk create that was not scheduled
+type SkippedShowtime struct {
+	ShowDate  string `json:"show_date"`
+	StartTime string `json:"start_time"`
+	Reason    string `json:"reason"`
+}
+
+// BulkCreateResponse reports the outcome of a bulk create
+type BulkCreateResponse struct {
+	CreatedIDs []uuid.UUID       `json:"created_ids" swaggertype:"array,string" format:"uuid"`
+	Skipped    []SkippedShowtime `json:"skipped"`
+	Total      int               `json:"total"` // showtimes created
+}
+
+// GenerateScheduleRequest asks for a cinema's showtimes over a date range
+// to be planned from the movies and how often each should show a day
+type GenerateScheduleRequest struct {
+	CinemaID  uuid.UUID              `json:"cinema_id" validate:"required" swaggertype:"string" format:"uuid"`
+	StartDate string                 `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string                 `json:"end_date" validate:"required,datetime=2006-01-02"` // inclusive
+	Movies    []ScheduleMovieRequest `json:"movies" validate:"required,min=1,max=20,dive"`
+	PriceTier string                 `json:"price_tier" validate:"omitempty,oneof=STANDARD PREMIUM DISCOUNT HOLIDAY"`
+	BasePrice float64                `json:"base_price" validate:"required,min=0"`
+	// Minutes left between shows on a screen for cleaning; defaults to 15,
+	// the least allowed
+	CleaningBuffer int `json:"cleaning_buffer" validate:"omitempty,min=15,max=120"`
+	// Plan the schedule without creating the showtimes
+	DryRun bool `json:"dry_run"`
+}
+
+// ScheduleMovieRequest is a movie to schedule and its daily show count
+type ScheduleMovieRequest struct {
+	MovieID     uuid.UUID `json:"movie_id" validate:"required" swaggertype:"string" format:"uuid"`
+	ShowsPerDay int       `json:"shows_per_day" validate:"required,min=1,max=12"`
+}
+
+// GenerateScheduleResponse is the planned, or with dry_run false created,
+// schedule and how well it uses the screens
+type GenerateScheduleResponse struct {
+	DryRun      bool                        `json:"dry_run"`
+	Showtimes   []ScheduledShowtimeResponse `json:"showtimes"`
+	Unscheduled []UnscheduledShow           `json:"unscheduled"`
+	ClosedDates []string                    `json:"closed_dates"` // dates the cinema is closed
+	Metrics     ScheduleMetrics             `json:"metrics"`
+}
+
+// ScheduledShowtimeResponse is a showtime of a generated schedule
+type ScheduledShowtimeResponse struct {
+	ID         *uuid.UUID `json:"id,omitempty" swaggertype:"string" format:"uuid"` // unset on a dry run
+	ScreenID   uuid.UUID  `json:"screen_id" swaggertype:"string" format:"uuid"`
+	ScreenName string     `json:"screen_name"`
+	MovieID    uuid.UUID  `json:"movie_id" swaggertype:"string" format:"uuid"`
+	MovieTitle string     `json:"movie_title"`
+	ShowDate   string     `json:"show_date"`  // YYYY-MM-DD
+	StartTime  string     `json:"start_time"` // HH:MM
+	EndTime    string     `json:"end_time"`   // HH:MM
+}
+
+// UnscheduledShow counts the requested shows of a movie on one day that
+// did not fit the schedule
+type UnscheduledShow struct {
+	MovieID  uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	ShowDate string    `json:"show_date"` // business date, YYYY-MM-DD
+	Shows    int       `json:"shows"`
+	Reason   string    `json:"reason"`
+}
+
+// ScheduleMetrics summarizes a generated schedule
+type ScheduleMetrics struct {
+	// Share of the screens' operating time taken by shows, existing ones
+	// included, as a percentage
+	UtilizationPercent float64              `json:"utilization_percent"`
+	Screens            []ScreenUtilization  `json:"screens"`
+	Movies             []MovieScheduleStats `json:"movies"`
+}
+
+// ScreenUtilization is how much of one screen's operating time is used
+type ScreenUtilization struct {
+	ScreenID           uuid.UUID `json:"screen_id" swaggertype:"string" format:"uuid"`
+	ScreenName         string    `json:"screen_name"`
+	Shows              int       `json:"shows"` // scheduled by this request
+	UtilizationPercent float64   `json:"utilization_percent"`
+}
+
+// MovieScheduleStats compares the shows scheduled for a movie with those
+// requested
+type MovieScheduleStats struct {
+	MovieID    uuid.UUID `json:"movie_id" swaggertype:"string" format:"uuid"`
+	MovieTitle string    `json:"movie_title"`
+	Requested  int       `json:"requested"`
+	Scheduled  int       `json:"scheduled"`
 }
 
 // ShowtimeListParams represents query parameters for listing showtimes
 type ShowtimeListParams struct {
-	CinemaID uuid.UUID `form:"cinema_id"`
-	MovieID  uuid.UUID `form:"movie_id"`
-	ScreenID uuid.UUID `form:"screen_id"`
+	CinemaID uuid.UUID `form:"cinema_id" swaggertype:"string" format:"uuid"`
+	MovieID  uuid.UUID `form:"movie_id" swaggertype:"string" format:"uuid"`
+	ScreenID uuid.UUID `form:"screen_id" swaggertype:"string" format:"uuid"`
 	Date     string    `form:"date"` // YYYY-MM-DD
 }
+
+// MovieShowtimesParams represents query parameters for a movie's showtimes
+type MovieShowtimesParams struct {
+	CinemaID string `form:"cinema_id"` // UUID; all cinemas when empty
+	City     string `form:"city"`
+	DateFrom string `form:"date_from"` // YYYY-MM-DD, defaults to today
+	DateTo   string `form:"date_to"`   // YYYY-MM-DD, defaults to a week after date_from
+}
+
+// CinemaShowtimesResponse lists a movie's showtimes at one cinema, by date
+type CinemaShowtimesResponse struct {
+	CinemaID   uuid.UUID              `json:"cinema_id" swaggertype:"string" format:"uuid"`
+	CinemaName string                 `json:"cinema_name"`
+	Address    string                 `json:"address"`
+	City       string                 `json:"city"`
+	Dates      []ShowtimeDateResponse `json:"dates"`
+}
+
+// ShowtimeDateResponse lists the showtimes on one show date
+type ShowtimeDateResponse struct {
+	Date      string                  `json:"date"` // YYYY-MM-DD
+	Showtimes []MovieShowtimeResponse `json:"showtimes"`
+}
+
+// MovieShowtimeResponse is a showtime as listed on a movie's page
+type MovieShowtimeResponse struct {
+	ID             uuid.UUID `json:"id" swaggertype:"string" format:"uuid"`
+	ScreenID       uuid.UUID `json:"screen_id" swaggertype:"string" format:"uuid"`
+	ScreenName     string    `json:"screen_name"`
+	Format         string    `json:"format"`     // screen type, e.g. IMAX
+	StartTime      string    `json:"start_time"` // HH:MM
+	EndTime        string    `json:"end_time"`   // HH:MM
+	PriceTier      string    `json:"price_tier"`
+	AvailableSeats int       `json:"available_seats"` // net of seats held for checkout
+	// AvailablePercent is the share of the capacity still available, 0-100
+	AvailablePercent int    `json:"available_percent"`
+	SalesState       string `json:"sales_state"`
+}
@@ -0,0 +1,557 @@
+package showtime
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// scheduleSlotStep is what generated start times are rounded up to
+	scheduleSlotStep = 5 * time.Minute
+	// defaultOpeningTime is when a cinema without hours for a day is taken
+	// to open; it closes at midnight then, as in Cinema.ClosingTime
+	defaultOpeningTime = 10 * time.Hour
+	// peakStart and peakEnd bound the evening slots the most popular
+	// movies are given first; a peak show starts between them
+	peakStart = 18 * time.Hour
+	peakEnd   = 21 * time.Hour
+)
+
+// Reasons a requested show is left out of a generated schedule
+const (
+	unscheduledNoSlot   = "no screen is free long enough before closing"
+	unscheduledClash    = "another show was scheduled on the screen meanwhile"
+	unscheduledNoFormat = "no screen supports the movie's format"
+)
+
+// interval is the time a show runs, without the cleaning after it
+type interval struct {
+	start, end time.Time
+}
+
+// scheduleScreen tracks a screen while a schedule is planned
+type scheduleScreen struct {
+	screen  *entity.Screen
+	busy    []interval // sorted by start
+	premium bool       // supports a format beyond standard
+}
+
+// plannedShow is a showtime of a generated schedule
+type plannedShow struct {
+	showtime *entity.Showtime
+	screen   *scheduleScreen
+	movie    *entity.Movie
+	runs     interval
+	day      time.Time // business date
+	dropped  bool      // clashed when it was created
+}
+
+// GenerateSchedule plans a cinema's showtimes over a date range: each
+// requested movie is shown the requested number of times a day, within the
+// cinema's operating hours, with the cleaning buffer between shows on a
+// screen and around the shows already scheduled, and only on screens
+// supporting its format. Each day the movies, most popular first, are
+// first given a show starting in the evening peak; the rest of their shows
+// are spread over the day in rounds, premium-format movies first. Unless it
+// is a dry run the showtimes are then created, marked auto-generated,
+// leaving out any that clash with shows created meanwhile.
+func (s *Service) GenerateSchedule(ctx context.Context, req GenerateScheduleRequest) (*GenerateScheduleResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, apperrors.ErrValidation("invalid start_date")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, apperrors.ErrValidation("invalid end_date")
+	}
+	if endDate.Before(startDate) {
+		return nil, apperrors.ErrValidation("end_date must not be before start_date")
+	}
+	if days := int(endDate.Sub(startDate).Hours()/24) + 1; days > maxBulkCreateDays {
+		return nil, apperrors.ErrValidation(fmt.Sprintf("date range must not exceed %d days", maxBulkCreateDays))
+	}
+	buffer := minShowtimeGap
+	if req.CleaningBuffer > 0 {
+		buffer = time.Duration(req.CleaningBuffer) * time.Minute
+	}
+
+	cinema, err := s.cinemaRepo.GetByID(ctx, req.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+	loc := cinema.Location()
+
+	movies := make([]*entity.Movie, 0, len(req.Movies))
+	showsPerDay := make(map[uuid.UUID]int, len(req.Movies))
+	rounds := 0
+	for _, m := range req.Movies {
+		if _, ok := showsPerDay[m.MovieID]; ok {
+			return nil, apperrors.ErrValidation(fmt.Sprintf("movie %s is listed more than once", m.MovieID))
+		}
+		movie, err := s.movieRepo.GetByID(ctx, m.MovieID)
+		if err != nil {
+			return nil, err
+		}
+		if !movie.IsAvailable() {
+			return nil, apperrors.ErrValidation(fmt.Sprintf("movie %q is not active", movie.Title))
+		}
+		if movie.Duration <= 0 {
+			return nil, apperrors.ErrValidation(fmt.Sprintf("movie %q has no duration", movie.Title))
+		}
+		movies = append(movies, movie)
+		showsPerDay[movie.ID] = m.ShowsPerDay
+		rounds = max(rounds, m.ShowsPerDay)
+	}
+	// Premium formats pick their screens first, and longer movies are
+	// placed before shorter ones that fit the gaps they leave
+	slices.SortStableFunc(movies, func(a, b *entity.Movie) int {
+		return cmp.Or(
+			cmp.Compare(formatRank(a.Format), formatRank(b.Format)),
+			cmp.Compare(b.Duration, a.Duration),
+		)
+	})
+
+	// The most popular movies pick their peak slots first
+	byPopularity := slices.Clone(movies)
+	slices.SortStableFunc(byPopularity, func(a, b *entity.Movie) int {
+		return cmp.Compare(b.PopularityScore, a.PopularityScore)
+	})
+
+	screens, err := s.scheduleScreens(ctx, cinema.ID, startDate, endDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(screens) == 0 {
+		return nil, apperrors.ErrValidation("cinema has no active screens")
+	}
+	unsupported := make(map[uuid.UUID]bool)
+	for _, movie := range movies {
+		if !slices.ContainsFunc(screens, func(screen *scheduleScreen) bool { return screen.supports(movie.Format) }) {
+			unsupported[movie.ID] = true
+		}
+	}
+
+	resp := &GenerateScheduleResponse{
+		DryRun:      req.DryRun,
+		Showtimes:   []ScheduledShowtimeResponse{},
+		Unscheduled: []UnscheduledShow{},
+		ClosedDates: []string{},
+	}
+	now := time.Now()
+	priceTier := entity.PriceTierStandard
+	if req.PriceTier != "" {
+		priceTier = entity.PriceTier(req.PriceTier)
+	}
+
+	var planned []*plannedShow
+	plan := func(screen *scheduleScreen, movie *entity.Movie, start, day time.Time) {
+		end := start.Add(time.Duration(movie.Duration) * time.Minute)
+		runs := interval{start: start, end: end}
+		screen.reserve(runs)
+		planned = append(planned, &plannedShow{
+			showtime: &entity.Showtime{
+				CinemaID:        cinema.ID,
+				ScreenID:        screen.screen.ID,
+				MovieID:         movie.ID,
+				ShowDate:        time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+				StartTime:       start.Format("15:04"),
+				EndTime:         end.Format("15:04"),
+				PriceTier:       priceTier,
+				BasePrice:       req.BasePrice,
+				TotalSeats:      screen.screen.Capacity,
+				AvailableSeats:  screen.screen.Capacity,
+				Status:          entity.ShowtimeScheduled,
+				IsAutoGenerated: true,
+			},
+			screen: screen,
+			movie:  movie,
+			runs:   runs,
+			day:    day,
+		})
+	}
+
+	var windows []interval
+	openDays := 0
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		open, closing, ok := operatingWindow(cinema, day)
+		if !ok {
+			resp.ClosedDates = append(resp.ClosedDates, day.Format("2006-01-02"))
+			continue
+		}
+		openDays++
+		windows = append(windows, interval{start: open, end: closing})
+		earliest := open
+		if now.After(earliest) {
+			earliest = now.In(loc)
+		}
+
+		remaining := make(map[uuid.UUID]int, len(movies))
+		for _, movie := range movies {
+			if !unsupported[movie.ID] {
+				remaining[movie.ID] = showsPerDay[movie.ID]
+			}
+		}
+
+		// Evening peak first, by popularity; a movie not placed there gets
+		// all its shows in the rounds
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		peakFrom, peakTo := later(midnight.Add(peakStart), earliest), midnight.Add(peakEnd)
+		for _, movie := range byPopularity {
+			if remaining[movie.ID] == 0 || peakFrom.After(peakTo) {
+				continue
+			}
+			screen, start, ok := placeShow(screens, movie, peakFrom, closing, buffer)
+			if ok && !start.After(peakTo) {
+				plan(screen, movie, start, day)
+				remaining[movie.ID]--
+			}
+		}
+
+		missing := make(map[uuid.UUID]int)
+		for round := 1; round <= rounds; round++ {
+			for _, movie := range movies {
+				if remaining[movie.ID] < round {
+					continue
+				}
+				screen, start, ok := placeShow(screens, movie, earliest, closing, buffer)
+				if !ok {
+					missing[movie.ID]++
+					continue
+				}
+				plan(screen, movie, start, day)
+			}
+		}
+		for _, movie := range movies {
+			n, reason := missing[movie.ID], unscheduledNoSlot
+			if unsupported[movie.ID] {
+				n, reason = showsPerDay[movie.ID], unscheduledNoFormat
+			}
+			if n > 0 {
+				resp.Unscheduled = append(resp.Unscheduled, UnscheduledShow{
+					MovieID:  movie.ID,
+					ShowDate: day.Format("2006-01-02"),
+					Shows:    n,
+					Reason:   reason,
+				})
+			}
+		}
+	}
+
+	if !req.DryRun {
+		if err := s.createPlanned(ctx, planned, buffer); err != nil {
+			s.logger.Error("failed to create generated schedule", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	scheduled := make(map[uuid.UUID]int, len(movies))
+	screenShows := make(map[uuid.UUID]int, len(screens))
+	for _, p := range planned {
+		if p.dropped {
+			resp.Unscheduled = append(resp.Unscheduled, UnscheduledShow{
+				MovieID:  p.movie.ID,
+				ShowDate: p.day.Format("2006-01-02"),
+				Shows:    1,
+				Reason:   unscheduledClash,
+			})
+			continue
+		}
+		scheduled[p.movie.ID]++
+		screenShows[p.screen.screen.ID]++
+
+		res := ScheduledShowtimeResponse{
+			ScreenID:   p.screen.screen.ID,
+			ScreenName: p.screen.screen.Name,
+			MovieID:    p.movie.ID,
+			MovieTitle: p.movie.Title,
+			ShowDate:   p.showtime.ShowDate.Format("2006-01-02"),
+			StartTime:  p.showtime.StartTime,
+			EndTime:    p.showtime.EndTime,
+		}
+		if !req.DryRun {
+			id := p.showtime.ID
+			res.ID = &id
+		}
+		resp.Showtimes = append(resp.Showtimes, res)
+	}
+	slices.SortStableFunc(resp.Showtimes, func(a, b ScheduledShowtimeResponse) int {
+		return cmp.Or(
+			strings.Compare(a.ShowDate, b.ShowDate),
+			strings.Compare(a.StartTime, b.StartTime),
+			strings.Compare(a.ScreenName, b.ScreenName),
+		)
+	})
+
+	// Dropped shows were planned into the screens' busy times; they are
+	// taken out again before the utilization is measured
+	for _, p := range planned {
+		if p.dropped {
+			p.screen.release(p.runs)
+		}
+	}
+	var used, operating time.Duration
+	for _, screen := range screens {
+		screenUsed := screen.usedWithin(windows)
+		screenOperating := totalDuration(windows)
+		used += screenUsed
+		operating += screenOperating
+		resp.Metrics.Screens = append(resp.Metrics.Screens, ScreenUtilization{
+			ScreenID:           screen.screen.ID,
+			ScreenName:         screen.screen.Name,
+			Shows:              screenShows[screen.screen.ID],
+			UtilizationPercent: percent(screenUsed, screenOperating),
+		})
+	}
+	resp.Metrics.UtilizationPercent = percent(used, operating)
+	for _, m := range req.Movies {
+		idx := slices.IndexFunc(movies, func(movie *entity.Movie) bool { return movie.ID == m.MovieID })
+		resp.Metrics.Movies = append(resp.Metrics.Movies, MovieScheduleStats{
+			MovieID:    m.MovieID,
+			MovieTitle: movies[idx].Title,
+			Requested:  m.ShowsPerDay * openDays,
+			Scheduled:  scheduled[m.MovieID],
+		})
+	}
+
+	s.logger.Info("generated schedule",
+		zap.String("cinema_id", cinema.ID.String()),
+		zap.Bool("dry_run", req.DryRun),
+		zap.Int("showtimes", len(resp.Showtimes)),
+		zap.Int("unscheduled", len(resp.Unscheduled)),
+		zap.Float64("utilization_percent", resp.Metrics.UtilizationPercent))
+	return resp, nil
+}
+
+// scheduleScreens returns the cinema's active screens with the shows
+// already scheduled on them around the date range
+func (s *Service) scheduleScreens(ctx context.Context, cinemaID uuid.UUID, startDate, endDate time.Time, loc *time.Location) ([]*scheduleScreen, error) {
+	all, err := s.screenRepo.GetByCinemaID(ctx, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*scheduleScreen, len(all))
+	screens := make([]*scheduleScreen, 0, len(all))
+	for _, screen := range all {
+		if !screen.IsAvailable() {
+			continue
+		}
+		sc := &scheduleScreen{
+			screen: screen,
+			premium: slices.ContainsFunc(screen.SupportedFormats, func(f entity.MovieFormat) bool {
+				return f != entity.FormatStandard
+			}),
+		}
+		byID[screen.ID] = sc
+		screens = append(screens, sc)
+	}
+	slices.SortFunc(screens, func(a, b *scheduleScreen) int {
+		return cmp.Compare(a.screen.ScreenNumber, b.screen.ScreenNumber)
+	})
+
+	// A day either side covers shows running over midnight into the range
+	existing, err := s.showtimeRepo.GetByDateRange(ctx, cinemaID, startDate.AddDate(0, 0, -1), endDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range existing {
+		sc, ok := byID[st.ScreenID]
+		if !ok || st.Status == entity.ShowtimeCancelled {
+			continue
+		}
+		sc.reserve(interval{start: st.StartsAt(loc), end: st.EndsAt(loc)})
+	}
+	return screens, nil
+}
+
+// createPlanned creates the planned showtimes screen by screen, marking
+// those that clash with shows created since they were planned
+func (s *Service) createPlanned(ctx context.Context, planned []*plannedShow, buffer time.Duration) error {
+	byScreen := make(map[uuid.UUID][]*entity.Showtime)
+	var screenIDs []uuid.UUID
+	plans := make(map[*entity.Showtime]*plannedShow, len(planned))
+	for _, p := range planned {
+		id := p.screen.screen.ID
+		if _, ok := byScreen[id]; !ok {
+			screenIDs = append(screenIDs, id)
+		}
+		byScreen[id] = append(byScreen[id], p.showtime)
+		plans[p.showtime] = p
+	}
+
+	for _, screenID := range screenIDs {
+		clashes, err := s.showtimeRepo.CreateBatch(ctx, screenID, byScreen[screenID], buffer)
+		if err != nil {
+			return err
+		}
+		for _, clash := range clashes {
+			plans[clash.Showtime].dropped = true
+		}
+	}
+	return nil
+}
+
+// operatingWindow returns when the cinema opens and closes on a business
+// date, or false when it is closed that day
+func operatingWindow(cinema *entity.Cinema, day time.Time) (time.Time, time.Time, bool) {
+	loc := cinema.Location()
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	open := midnight.Add(defaultOpeningTime)
+	hours, ok := cinema.OperatingHours[strings.ToLower(midnight.Weekday().String())]
+	if ok && hours.Closed {
+		return time.Time{}, time.Time{}, false
+	}
+	if ok && hours.Open != "" {
+		if at, err := time.Parse("15:04", hours.Open); err == nil {
+			open = time.Date(midnight.Year(), midnight.Month(), midnight.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+		}
+	}
+	closing := cinema.ClosingTime(day)
+	if !closing.After(open) {
+		return time.Time{}, time.Time{}, false
+	}
+	return open, closing, true
+}
+
+// placeShow finds the screen and start time for a show of the movie,
+// starting no earlier than from and ending by closing, on a screen
+// supporting the movie's format. The earliest slot wins; on a tie standard
+// movies prefer screens without premium formats, leaving those free.
+func placeShow(screens []*scheduleScreen, movie *entity.Movie, from, closing time.Time, buffer time.Duration) (*scheduleScreen, time.Time, bool) {
+	length := time.Duration(movie.Duration) * time.Minute
+	standardMovie := formatRank(movie.Format) == 1
+
+	var best *scheduleScreen
+	var bestStart time.Time
+	for _, screen := range screens {
+		if !screen.supports(movie.Format) {
+			continue
+		}
+		start := screen.earliestSlot(from, length, buffer)
+		if start.Add(length).After(closing) {
+			continue
+		}
+		switch {
+		case best == nil:
+		case !start.Equal(bestStart):
+			if start.After(bestStart) {
+				continue
+			}
+		case !standardMovie || screen.premium || !best.premium:
+			continue
+		}
+		best, bestStart = screen, start
+	}
+	return best, bestStart, best != nil
+}
+
+// supports reports whether the screen can show a movie in the format.
+// Every screen shows standard prints; other formats must be listed.
+func (s *scheduleScreen) supports(format entity.MovieFormat) bool {
+	return formatRank(format) == 1 || slices.Contains(s.screen.SupportedFormats, format)
+}
+
+// earliestSlot returns the first start from from on at which a show of the
+// given length leaves buffer to the shows before and after it
+func (s *scheduleScreen) earliestSlot(from time.Time, length, buffer time.Duration) time.Time {
+	start := roundUp(from, scheduleSlotStep)
+	for _, busy := range s.busy {
+		if !busy.start.Before(start.Add(length + buffer)) {
+			break
+		}
+		if start.Before(busy.end.Add(buffer)) {
+			start = roundUp(busy.end.Add(buffer), scheduleSlotStep)
+		}
+	}
+	return start
+}
+
+// reserve adds a show to the screen's busy times
+func (s *scheduleScreen) reserve(show interval) {
+	i, _ := slices.BinarySearchFunc(s.busy, show, func(a, b interval) int {
+		return a.start.Compare(b.start)
+	})
+	s.busy = slices.Insert(s.busy, i, show)
+}
+
+// release takes a show out of the screen's busy times
+func (s *scheduleScreen) release(show interval) {
+	i := slices.IndexFunc(s.busy, func(busy interval) bool {
+		return busy.start.Equal(show.start) && busy.end.Equal(show.end)
+	})
+	if i >= 0 {
+		s.busy = slices.Delete(s.busy, i, i+1)
+	}
+}
+
+// usedWithin returns how much of the windows the screen's shows take up
+func (s *scheduleScreen) usedWithin(windows []interval) time.Duration {
+	var used time.Duration
+	for _, window := range windows {
+		for _, busy := range s.busy {
+			start := later(busy.start, window.start)
+			end := earlier(busy.end, window.end)
+			if end.After(start) {
+				used += end.Sub(start)
+			}
+		}
+	}
+	return used
+}
+
+// formatRank orders premium formats before standard ones
+func formatRank(format entity.MovieFormat) int {
+	if format == "" || format == entity.FormatStandard {
+		return 1
+	}
+	return 0
+}
+
+func totalDuration(windows []interval) time.Duration {
+	var total time.Duration
+	for _, window := range windows {
+		total += window.end.Sub(window.start)
+	}
+	return total
+}
+
+// percent returns part of whole as a percentage with one decimal
+func percent(part, whole time.Duration) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*1000) / 10
+}
+
+func roundUp(t time.Time, step time.Duration) time.Time {
+	rounded := t.Truncate(step)
+	if rounded.Before(t) {
+		rounded = rounded.Add(step)
+	}
+	return rounded
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
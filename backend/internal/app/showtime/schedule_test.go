@@ -0,0 +1,292 @@
+package showtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// scheduleShowtimes serves the shows known when a schedule is planned and
+// creates showtimes per screen, refusing those that clash with any show
+// on the screen by then
+type scheduleShowtimes struct {
+	repository.ShowtimeRepository
+	known    []*entity.Showtime
+	existing []*entity.Showtime
+}
+
+func (m *scheduleShowtimes) GetByDateRange(context.Context, uuid.UUID, time.Time, time.Time) ([]*entity.Showtime, error) {
+	return m.known, nil
+}
+
+func (m *scheduleShowtimes) CreateBatch(_ context.Context, screenID uuid.UUID, showtimes []*entity.Showtime, gap time.Duration) ([]repository.ShowtimeClash, error) {
+	var clashes []repository.ShowtimeClash
+next:
+	for _, st := range showtimes {
+		for _, other := range m.existing {
+			if other.ScreenID == screenID && st.ClashesWith(other, gap) {
+				clashes = append(clashes, repository.ShowtimeClash{Showtime: st, With: other})
+				continue next
+			}
+		}
+		st.ID = uuid.New()
+		m.existing = append(m.existing, st)
+	}
+	return clashes, nil
+}
+
+type memCatalogue struct {
+	repository.MovieRepository
+	movies map[uuid.UUID]*entity.Movie
+}
+
+func (m *memCatalogue) GetByID(_ context.Context, id uuid.UUID) (*entity.Movie, error) {
+	if movie, ok := m.movies[id]; ok {
+		return movie, nil
+	}
+	return nil, apperrors.New(apperrors.CodeMovieNotFound, "movie not found")
+}
+
+type memCinemaScreens struct {
+	repository.ScreenRepository
+	screens []*entity.Screen
+}
+
+func (m *memCinemaScreens) GetByCinemaID(context.Context, uuid.UUID) ([]*entity.Screen, error) {
+	return m.screens, nil
+}
+
+// scheduleFixture is a cinema open 10:00-23:00 on Mondays and closed on
+// Sundays, with a standard screen and an IMAX one
+type scheduleFixture struct {
+	svc       *Service
+	showtimes *scheduleShowtimes
+	cinema    *entity.Cinema
+	standard  *entity.Screen
+	imax      *entity.Screen
+	epic      *entity.Movie // IMAX, 150 minutes
+	comedy    *entity.Movie // standard, 90 minutes
+}
+
+func newScheduleFixture() *scheduleFixture {
+	f := &scheduleFixture{
+		showtimes: &scheduleShowtimes{},
+		cinema: &entity.Cinema{ID: uuid.New(), Timezone: "UTC", OperatingHours: entity.OperatingHours{
+			"sunday": {Closed: true},
+			"monday": {Open: "10:00", Close: "23:00"},
+		}},
+		epic:   &entity.Movie{ID: uuid.New(), Title: "Epic", Duration: 150, Format: entity.FormatIMAX, IsActive: true},
+		comedy: &entity.Movie{ID: uuid.New(), Title: "Comedy", Duration: 90, Format: entity.FormatStandard, IsActive: true},
+	}
+	f.standard = &entity.Screen{ID: uuid.New(), CinemaID: f.cinema.ID, Name: "Screen 1", ScreenNumber: 1, Capacity: 80, IsActive: true,
+		SupportedFormats: entity.SupportedFormats{entity.FormatStandard}}
+	f.imax = &entity.Screen{ID: uuid.New(), CinemaID: f.cinema.ID, Name: "IMAX", ScreenNumber: 2, Capacity: 200, IsActive: true,
+		SupportedFormats: entity.SupportedFormats{entity.FormatStandard, entity.FormatIMAX}}
+	f.svc = NewService(f.showtimes,
+		&memCatalogue{movies: map[uuid.UUID]*entity.Movie{f.epic.ID: f.epic, f.comedy.ID: f.comedy}},
+		&memCinemas{cinema: f.cinema}, &memCinemaScreens{screens: []*entity.Screen{f.imax, f.standard}},
+		nil, nil, nil, nil, config.AvailabilityConfig{}, nil, &logger.Logger{Logger: zap.NewNop()})
+	return f
+}
+
+// request schedules two Epic and three Comedy shows a day over Sunday
+// 2030-01-06 and Monday 2030-01-07
+func (f *scheduleFixture) request(dryRun bool) GenerateScheduleRequest {
+	return GenerateScheduleRequest{
+		CinemaID:  f.cinema.ID,
+		StartDate: "2030-01-06",
+		EndDate:   "2030-01-07",
+		Movies: []ScheduleMovieRequest{
+			{MovieID: f.comedy.ID, ShowsPerDay: 3},
+			{MovieID: f.epic.ID, ShowsPerDay: 2},
+		},
+		BasePrice: 9,
+		DryRun:    dryRun,
+	}
+}
+
+func TestGenerateSchedule(t *testing.T) {
+	f := newScheduleFixture()
+	resp, err := f.svc.GenerateSchedule(context.Background(), f.request(true))
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+
+	// Each movie gets an evening peak show; the rest go from opening,
+	// premium first on the IMAX screen while the comedy takes the plain one
+	want := []struct{ screen, movie, start, end string }{
+		{"IMAX", "Epic", "10:00", "12:30"},
+		{"Screen 1", "Comedy", "10:00", "11:30"},
+		{"Screen 1", "Comedy", "11:45", "13:15"},
+		{"IMAX", "Epic", "18:00", "20:30"},
+		{"Screen 1", "Comedy", "18:00", "19:30"},
+	}
+	if len(resp.Showtimes) != len(want) {
+		t.Fatalf("%d showtimes, want %d: %+v", len(resp.Showtimes), len(want), resp.Showtimes)
+	}
+	for i, w := range want {
+		got := resp.Showtimes[i]
+		if got.ScreenName != w.screen || got.MovieTitle != w.movie || got.StartTime != w.start || got.EndTime != w.end ||
+			got.ShowDate != "2030-01-07" || got.ID != nil {
+			t.Errorf("showtime %d = %+v, want %s %s %s-%s on 2030-01-07", i, got, w.screen, w.movie, w.start, w.end)
+		}
+	}
+	if len(resp.ClosedDates) != 1 || resp.ClosedDates[0] != "2030-01-06" || len(resp.Unscheduled) != 0 {
+		t.Errorf("closed %v, unscheduled %+v; want Sunday closed and everything placed", resp.ClosedDates, resp.Unscheduled)
+	}
+	if len(f.showtimes.existing) != 0 {
+		t.Errorf("a dry run created %d showtimes", len(f.showtimes.existing))
+	}
+
+	// 300 of the IMAX screen's 780 minutes and 270 of Screen 1's
+	if m := resp.Metrics; m.UtilizationPercent != 36.5 || m.Screens[0].UtilizationPercent != 34.6 || m.Screens[1].UtilizationPercent != 38.5 {
+		t.Errorf("utilization = %v overall, %+v per screen", m.UtilizationPercent, m.Screens)
+	}
+	for _, stats := range resp.Metrics.Movies {
+		if stats.Requested != stats.Scheduled {
+			t.Errorf("%s: %d of %d scheduled", stats.MovieTitle, stats.Scheduled, stats.Requested)
+		}
+	}
+}
+
+func TestGenerateScheduleAroundExistingShows(t *testing.T) {
+	f := newScheduleFixture()
+	// Screen 1 already shows something 10:00-12:00; another show lands on
+	// the IMAX screen after planning
+	f.showtimes.known = []*entity.Showtime{{ID: uuid.New(), ScreenID: f.standard.ID,
+		ShowDate: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), StartTime: "10:00", EndTime: "12:00", Status: entity.ShowtimeScheduled}}
+	late := &entity.Showtime{ID: uuid.New(), ScreenID: f.imax.ID,
+		ShowDate: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), StartTime: "13:00", EndTime: "14:00"}
+	f.showtimes.existing = append(f.showtimes.existing, f.showtimes.known[0], late)
+
+	resp, err := f.svc.GenerateSchedule(context.Background(), f.request(false))
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	for _, st := range resp.Showtimes {
+		if st.ScreenName == "Screen 1" && st.StartTime < "12:15" {
+			t.Errorf("%s at %s overlaps the existing show or its cleaning", st.MovieTitle, st.StartTime)
+		}
+		if st.ID == nil {
+			t.Errorf("%s at %s has no ID after creation", st.MovieTitle, st.StartTime)
+		}
+	}
+	// The comedy's third show was planned on the IMAX screen at 12:45,
+	// before the late show landed there
+	if len(resp.Unscheduled) != 1 || resp.Unscheduled[0].Reason != unscheduledClash || resp.Unscheduled[0].MovieID != f.comedy.ID {
+		t.Errorf("unscheduled = %+v, want a comedy show clashing", resp.Unscheduled)
+	}
+	if created := len(f.showtimes.existing) - 2; created != len(resp.Showtimes) {
+		t.Errorf("%d showtimes created, %d reported", created, len(resp.Showtimes))
+	}
+	for _, st := range f.showtimes.existing[2:] {
+		if !st.IsAutoGenerated {
+			t.Errorf("showtime at %s not marked auto-generated", st.StartTime)
+		}
+	}
+}
+
+func TestGenerateSchedulePeakHours(t *testing.T) {
+	f := newScheduleFixture()
+	drama := &entity.Movie{ID: uuid.New(), Title: "Drama", Duration: 120, Format: entity.FormatStandard, IsActive: true, PopularityScore: 10}
+	f.comedy.PopularityScore, f.epic.PopularityScore = 90, 50
+	f.svc.movieRepo.(*memCatalogue).movies[drama.ID] = drama
+	f.svc.screenRepo = &memCinemaScreens{screens: []*entity.Screen{f.imax}}
+
+	req := f.request(true)
+	req.Movies = []ScheduleMovieRequest{
+		{MovieID: drama.ID, ShowsPerDay: 1},
+		{MovieID: f.epic.ID, ShowsPerDay: 1},
+		{MovieID: f.comedy.ID, ShowsPerDay: 1},
+	}
+	resp, err := f.svc.GenerateSchedule(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+
+	// The most popular take the evening; the least popular no longer fits
+	// there and fills the morning
+	want := map[string]string{"Comedy": "18:00", "Epic": "19:45", "Drama": "10:00"}
+	if len(resp.Showtimes) != len(want) {
+		t.Fatalf("%d showtimes, want %d: %+v", len(resp.Showtimes), len(want), resp.Showtimes)
+	}
+	for _, st := range resp.Showtimes {
+		if st.StartTime != want[st.MovieTitle] {
+			t.Errorf("%s at %s, want %s", st.MovieTitle, st.StartTime, want[st.MovieTitle])
+		}
+	}
+}
+
+func TestGenerateScheduleFormats(t *testing.T) {
+	f := newScheduleFixture()
+	rides := &entity.Movie{ID: uuid.New(), Title: "Rides", Duration: 100, Format: entity.Format4DX, IsActive: true}
+	f.svc.movieRepo.(*memCatalogue).movies[rides.ID] = rides
+
+	req := f.request(true)
+	req.Movies = append(req.Movies, ScheduleMovieRequest{MovieID: rides.ID, ShowsPerDay: 2})
+	resp, err := f.svc.GenerateSchedule(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+
+	for _, st := range resp.Showtimes {
+		if st.MovieTitle == "Epic" && st.ScreenName != "IMAX" {
+			t.Errorf("Epic at %s on %s, which has no IMAX", st.StartTime, st.ScreenName)
+		}
+		if st.MovieTitle == "Rides" {
+			t.Errorf("Rides scheduled at %s without a 4DX screen", st.StartTime)
+		}
+	}
+	if len(resp.Unscheduled) != 1 || resp.Unscheduled[0].MovieID != rides.ID ||
+		resp.Unscheduled[0].Shows != 2 || resp.Unscheduled[0].Reason != unscheduledNoFormat {
+		t.Errorf("unscheduled = %+v, want both Rides shows for want of a 4DX screen", resp.Unscheduled)
+	}
+}
+
+func TestGenerateScheduleOutOfTime(t *testing.T) {
+	f := newScheduleFixture()
+	f.cinema.OperatingHours["monday"] = entity.DayHours{Open: "10:00", Close: "14:00"}
+	req := f.request(true)
+	req.Movies = []ScheduleMovieRequest{{MovieID: f.epic.ID, ShowsPerDay: 3}}
+	f.svc.screenRepo = &memCinemaScreens{screens: []*entity.Screen{f.imax}}
+
+	resp, err := f.svc.GenerateSchedule(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	if len(resp.Showtimes) != 1 || len(resp.Unscheduled) != 1 || resp.Unscheduled[0].Shows != 2 || resp.Unscheduled[0].Reason != unscheduledNoSlot {
+		t.Errorf("%d showtimes, unscheduled %+v; want one show and two that do not fit", len(resp.Showtimes), resp.Unscheduled)
+	}
+}
+
+func TestGenerateScheduleRejects(t *testing.T) {
+	f := newScheduleFixture()
+	inactive := &entity.Movie{ID: uuid.New(), Title: "Pulled", Duration: 100}
+	f.svc.movieRepo.(*memCatalogue).movies[inactive.ID] = inactive
+
+	tests := []struct {
+		name string
+		edit func(*GenerateScheduleRequest)
+	}{
+		{"bad date", func(r *GenerateScheduleRequest) { r.StartDate = "07/01/2030" }},
+		{"end before start", func(r *GenerateScheduleRequest) { r.EndDate = "2030-01-05" }},
+		{"movie listed twice", func(r *GenerateScheduleRequest) { r.Movies = append(r.Movies, r.Movies[0]) }},
+		{"inactive movie", func(r *GenerateScheduleRequest) { r.Movies[0].MovieID = inactive.ID }},
+	}
+	for _, tt := range tests {
+		req := f.request(true)
+		tt.edit(&req)
+		if _, err := f.svc.GenerateSchedule(context.Background(), req); !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%s: GenerateSchedule = %v, want %s", tt.name, err, apperrors.CodeValidation)
+		}
+	}
+}
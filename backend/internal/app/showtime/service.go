@@ -2,22 +2,50 @@ package showtime
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/changelog"
 	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
 	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	// minShowtimeGap is the least time between shows on a screen, for
+	// cleaning and seating the next audience
+	minShowtimeGap = 15 * time.Minute
+	// maxBulkCreateDays caps the date range of a bulk create
+	maxBulkCreateDays = 62
+	// movieShowtimeDays is the date range a movie's showtimes are listed
+	// for when no end date is given; maxMovieShowtimeDays caps it
+	movieShowtimeDays    = 7
+	maxMovieShowtimeDays = 31
+)
+
 // Service handles showtime business logic
 type Service struct {
 	showtimeRepo repository.ShowtimeRepository
 	movieRepo    repository.MovieRepository
 	cinemaRepo   repository.CinemaRepository // Assuming CinemaRepo has GetScreen methods we might need, or separate ScreenRepo
 	screenRepo   repository.ScreenRepository
+	seatRepo     repository.SeatRepository
+	holdRepo     repository.SeatHoldRepository
+	ruleRepo     repository.SeatTypeRuleRepository
+	changeLog    *changelog.Service
+	availability config.AvailabilityConfig
+	bus          *eventbus.Bus
 	logger       *logger.Logger
 }
 
@@ -27,6 +55,12 @@ func NewService(
 	movieRepo repository.MovieRepository,
 	cinemaRepo repository.CinemaRepository,
 	screenRepo repository.ScreenRepository,
+	seatRepo repository.SeatRepository,
+	holdRepo repository.SeatHoldRepository,
+	ruleRepo repository.SeatTypeRuleRepository,
+	changeLog *changelog.Service,
+	availability config.AvailabilityConfig,
+	bus *eventbus.Bus,
 	logger *logger.Logger,
 ) *Service {
 	return &Service{
@@ -34,10 +68,21 @@ func NewService(
 		movieRepo:    movieRepo,
 		cinemaRepo:   cinemaRepo,
 		screenRepo:   screenRepo,
+		seatRepo:     seatRepo,
+		holdRepo:     holdRepo,
+		ruleRepo:     ruleRepo,
+		changeLog:    changeLog,
+		availability: availability,
+		bus:          bus,
 		logger:       logger,
 	}
 }
 
+// ListingCacheTTL returns how long a movie's showtime listing may be cached
+func (s *Service) ListingCacheTTL() time.Duration {
+	return s.availability.CacheTTL
+}
+
 // Create creates a new showtime
 func (s *Service) Create(ctx context.Context, req CreateShowtimeRequest) (*ShowtimeResponse, error) {
 	// Verify dependencies
@@ -51,6 +96,11 @@ func (s *Service) Create(ctx context.Context, req CreateShowtimeRequest) (*Showt
 		return nil, err
 	}
 
+	cinema, err := s.cinemaRepo.GetByID(ctx, req.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Verify screen belongs to cinema
 	if screen.CinemaID != req.CinemaID {
 		// Just a basic check
@@ -93,6 +143,16 @@ func (s *Service) Create(ctx context.Context, req CreateShowtimeRequest) (*Showt
 		TotalSeats:     screen.Capacity,
 		AvailableSeats: screen.Capacity,
 		Status:         entity.ShowtimeScheduled,
+		SalesOpenAt:    req.SalesOpenAt,
+		SalesCloseAt:   req.SalesCloseAt,
+	}
+	if req.PresaleCode != "" {
+		hash := authinfra.HashToken(req.PresaleCode)
+		showtime.PresaleCodeHash = &hash
+	}
+
+	if err := validateSalesWindow(showtime, cinema.Location()); err != nil {
+		return nil, err
 	}
 
 	if err := s.showtimeRepo.Create(ctx, showtime); err != nil {
@@ -103,24 +163,148 @@ func (s *Service) Create(ctx context.Context, req CreateShowtimeRequest) (*Showt
 	// Load relationships for response
 	showtime.Movie = *movie
 	showtime.Screen = *screen
+	showtime.Cinema = *cinema
 	// Cinema might not be loaded, can fetch if needed, but for now response might miss CinemaName unless fetched
 
 	return s.toShowtimeResponse(showtime), nil
 }
 
-// GetByID gets a showtime by ID
-func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*ShowtimeResponse, error) {
+// BulkCreate schedules a movie on a screen at the given times every day of a
+// date range. Slots that have passed or run within minShowtimeGap of another
+// show on the screen are skipped; the rest are created together.
+func (s *Service) BulkCreate(ctx context.Context, req BulkCreateShowtimesRequest) (*BulkCreateResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, apperrors.ErrValidation("invalid start_date")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, apperrors.ErrValidation("invalid end_date")
+	}
+	if endDate.Before(startDate) {
+		return nil, apperrors.ErrValidation("end_date must not be before start_date")
+	}
+	if days := int(endDate.Sub(startDate).Hours()/24) + 1; days > maxBulkCreateDays {
+		return nil, apperrors.ErrValidation(fmt.Sprintf("date range must not exceed %d days", maxBulkCreateDays))
+	}
+
+	movie, err := s.movieRepo.GetByID(ctx, req.MovieID)
+	if err != nil {
+		return nil, err
+	}
+	if !movie.IsAvailable() {
+		return nil, apperrors.ErrValidation("movie is not active")
+	}
+	screen, err := s.screenRepo.GetByID(ctx, req.ScreenID)
+	if err != nil {
+		return nil, err
+	}
+	if !screen.IsAvailable() {
+		return nil, apperrors.ErrValidation("screen is not active")
+	}
+	cinema, err := s.cinemaRepo.GetByID(ctx, screen.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	priceTier := entity.PriceTierStandard
+	if req.PriceTier != "" {
+		priceTier = entity.PriceTier(req.PriceTier)
+	}
+	showTimes := slices.Clone(req.ShowTimes)
+	slices.Sort(showTimes)
+	showTimes = slices.Compact(showTimes)
+
+	resp := &BulkCreateResponse{CreatedIDs: []uuid.UUID{}, Skipped: []SkippedShowtime{}}
+	now := time.Now()
+	var showtimes []*entity.Showtime
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		for _, startTime := range showTimes {
+			start, _ := time.Parse("15:04", startTime)
+			showtime := &entity.Showtime{
+				CinemaID:       screen.CinemaID,
+				ScreenID:       screen.ID,
+				MovieID:        movie.ID,
+				ShowDate:       day,
+				StartTime:      startTime,
+				EndTime:        start.Add(time.Duration(movie.Duration) * time.Minute).Format("15:04"),
+				PriceTier:      priceTier,
+				BasePrice:      req.BasePrice,
+				TotalSeats:     screen.Capacity,
+				AvailableSeats: screen.Capacity,
+				Status:         entity.ShowtimeScheduled,
+			}
+			if !showtime.StartsAt(cinema.Location()).After(now) {
+				resp.Skipped = append(resp.Skipped, skippedShowtime(showtime, "start time has passed"))
+				continue
+			}
+			showtimes = append(showtimes, showtime)
+		}
+	}
+
+	clashes, err := s.showtimeRepo.CreateBatch(ctx, screen.ID, showtimes, minShowtimeGap)
+	if err != nil {
+		s.logger.Error("failed to bulk create showtimes", zap.Error(err))
+		return nil, err
+	}
+	skipped := make(map[*entity.Showtime]bool, len(clashes))
+	for _, clash := range clashes {
+		skipped[clash.Showtime] = true
+		resp.Skipped = append(resp.Skipped, skippedShowtime(clash.Showtime, fmt.Sprintf(
+			"less than %d minutes from the %s-%s show on %s",
+			int(minShowtimeGap.Minutes()), hhmm(clash.With.StartTime), hhmm(clash.With.EndTime),
+			clash.With.ShowDate.Format("2006-01-02"))))
+	}
+	for _, showtime := range showtimes {
+		if !skipped[showtime] {
+			resp.CreatedIDs = append(resp.CreatedIDs, showtime.ID)
+		}
+	}
+	resp.Total = len(resp.CreatedIDs)
+
+	s.logger.Info("bulk created showtimes",
+		zap.String("screen_id", screen.ID.String()),
+		zap.String("movie_id", movie.ID.String()),
+		zap.Int("created", resp.Total),
+		zap.Int("skipped", len(resp.Skipped)))
+	return resp, nil
+}
+
+func skippedShowtime(showtime *entity.Showtime, reason string) SkippedShowtime {
+	return SkippedShowtime{
+		ShowDate:  showtime.ShowDate.Format("2006-01-02"),
+		StartTime: showtime.StartTime,
+		Reason:    reason,
+	}
+}
+
+// hhmm trims the seconds Postgres TIME columns come back with
+func hhmm(clock string) string {
+	if len(clock) > 5 {
+		return clock[:5]
+	}
+	return clock
+}
+
+// GetByID gets a showtime by ID. Unlisted showtimes are returned to anyone
+// with the ID; private ones only with their access code.
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID, accessCode string) (*ShowtimeResponse, error) {
 	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !showtime.CanAccess(hashCode(accessCode)) {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
 	return s.toShowtimeResponse(showtime), nil
 }
 
 // List lists showtimes
 func (s *Service) List(ctx context.Context, params ShowtimeListParams) ([]*ShowtimeResponse, error) {
 	filter := repository.ShowtimeFilter{
-		CinemaID: params.CinemaID,
+		CinemaID:      params.CinemaID,
+		ListedOnly:    true,
+		AvailableOnly: true,
 	}
 
 	if params.MovieID != uuid.Nil {
@@ -141,20 +325,91 @@ func (s *Service) List(ctx context.Context, params ShowtimeListParams) ([]*Showt
 		return nil, err
 	}
 
+	held := s.heldCounts(ctx, showtimeIDs(showtimes))
+
 	var responses []*ShowtimeResponse
 	for _, st := range showtimes {
-		responses = append(responses, s.toShowtimeResponse(st))
+		res := s.toShowtimeResponse(st)
+		res.Availability = s.toAvailabilityResponse(st, held[st.ID])
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+// ListUnavailable returns upcoming scheduled showtimes whose movie or screen
+// has been deactivated or deleted. They are hidden from listings and cannot
+// be booked, so an admin has to reschedule or cancel them.
+func (s *Service) ListUnavailable(ctx context.Context) ([]*ShowtimeResponse, error) {
+	showtimes, err := s.showtimeRepo.ListUnavailable(ctx, time.Now().Truncate(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ShowtimeResponse, len(showtimes))
+	for i, st := range showtimes {
+		responses[i] = s.toShowtimeResponse(st)
+	}
+	return responses, nil
+}
+
+// GetAvailability returns the availability summary of each requested
+// showtime, in request order. Unknown and private showtimes are left out.
+func (s *Service) GetAvailability(ctx context.Context, ids []uuid.UUID) ([]*AvailabilityResponse, error) {
+	if len(ids) > MaxAvailabilityIDs {
+		return nil, apperrors.ErrBadRequest(fmt.Sprintf("cannot request more than %d showtimes", MaxAvailabilityIDs))
+	}
+
+	showtimes, err := s.showtimeRepo.GetCapacities(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entity.Showtime, len(showtimes))
+	for _, st := range showtimes {
+		byID[st.ID] = st
 	}
 
+	held := s.heldCounts(ctx, showtimeIDs(showtimes))
+
+	responses := make([]*AvailabilityResponse, 0, len(showtimes))
+	for _, id := range ids {
+		st, ok := byID[id]
+		if !ok || st.Visibility == entity.VisibilityPrivate {
+			continue
+		}
+		responses = append(responses, s.toAvailabilityResponse(st, held[id]))
+		// Report duplicates once
+		delete(byID, id)
+	}
 	return responses, nil
 }
 
+// heldCounts returns the held seat counts of the showtimes. The counts only
+// refine the summary, so without Redis every showtime counts as unheld.
+func (s *Service) heldCounts(ctx context.Context, ids []uuid.UUID) map[uuid.UUID]int {
+	held, err := s.holdRepo.GetHeldCounts(ctx, ids)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("failed to get held seat counts", zap.Error(err))
+		return nil
+	}
+	return held
+}
+
+func showtimeIDs(showtimes []*entity.Showtime) []uuid.UUID {
+	ids := make([]uuid.UUID, len(showtimes))
+	for i, st := range showtimes {
+		ids[i] = st.ID
+	}
+	return ids
+}
+
 // Update updates a showtime
 func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateShowtimeRequest) (*ShowtimeResponse, error) {
 	showtime, err := s.showtimeRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	before := *showtime
 
 	if req.ShowDate != "" {
 		date, err := time.Parse("2006-01-02", req.ShowDate)
@@ -177,30 +432,280 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req UpdateShowtimeRe
 		showtime.BasePrice = req.BasePrice
 	}
 
+	wasCancelled := showtime.Status == entity.ShowtimeCancelled
 	if req.Status != "" {
 		showtime.Status = entity.ShowtimeStatus(req.Status)
 	}
 
-	if err := s.showtimeRepo.Update(ctx, showtime); err != nil {
+	if req.ClearSalesWindow {
+		showtime.SalesOpenAt = nil
+		showtime.SalesCloseAt = nil
+	}
+	if req.SalesOpenAt != nil {
+		showtime.SalesOpenAt = req.SalesOpenAt
+	}
+	if req.SalesCloseAt != nil {
+		showtime.SalesCloseAt = req.SalesCloseAt
+	}
+
+	if req.ClearPresaleCode {
+		showtime.PresaleCodeHash = nil
+	}
+	if req.PresaleCode != "" {
+		hash := authinfra.HashToken(req.PresaleCode)
+		showtime.PresaleCodeHash = &hash
+	}
+
+	cinema, err := s.cinemaRepo.GetByID(ctx, showtime.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSalesWindow(showtime, cinema.Location()); err != nil {
+		return nil, err
+	}
+
+	if err := s.showtimeRepo.UpdateWithVersion(ctx, showtime); err != nil {
 		return nil, err
 	}
+	s.changeLog.Record(ctx, entity.ChangeEntityShowtime, showtime.ID, before, *showtime)
+
+	if !wasCancelled && showtime.Status == entity.ShowtimeCancelled {
+		s.bus.Publish(ctx, events.ShowtimeCancelled{
+			ShowtimeID:  showtime.ID,
+			MovieID:     showtime.MovieID,
+			CinemaID:    showtime.CinemaID,
+			CancelledAt: time.Now(),
+		})
+	}
 
+	showtime.Cinema = *cinema
 	return s.toShowtimeResponse(showtime), nil
 }
 
-// GetShowtimesByMovieID returns all showtimes for a movie
-func (s *Service) GetShowtimesByMovieID(ctx context.Context, movieID uuid.UUID) ([]*ShowtimeResponse, error) {
-	showtimes, err := s.showtimeRepo.GetByMovieID(ctx, movieID)
+// ListForMovie returns the upcoming showtimes of a movie grouped by cinema,
+// then by show date, with one page of cinemas per call. Showtimes that have
+// already started today are left out. The listing is cached for
+// ListingCacheTTL, so seat counts may lag by as much.
+func (s *Service) ListForMovie(ctx context.Context, movieID uuid.UUID, params MovieShowtimesParams, page, limit int) ([]CinemaShowtimesResponse, int64, error) {
+	if _, err := s.movieRepo.GetByID(ctx, movieID); err != nil {
+		return nil, 0, err
+	}
+	var cinemaID uuid.UUID
+	if params.CinemaID != "" {
+		id, err := uuid.Parse(params.CinemaID)
+		if err != nil {
+			return nil, 0, apperrors.ErrValidation("invalid cinema_id")
+		}
+		cinemaID = id
+	}
+
+	now := time.Now()
+	dateFrom := now.UTC().Truncate(24 * time.Hour)
+	// A day back covers cinemas behind UTC, where it is still yesterday;
+	// shows that already started are dropped by their start time
+	showDateFrom := dateFrom.AddDate(0, 0, -1)
+	if params.DateFrom != "" {
+		date, err := time.Parse("2006-01-02", params.DateFrom)
+		if err != nil {
+			return nil, 0, apperrors.ErrValidation("invalid date_from")
+		}
+		dateFrom, showDateFrom = date, date
+	}
+	dateTo := dateFrom.AddDate(0, 0, movieShowtimeDays-1)
+	if params.DateTo != "" {
+		date, err := time.Parse("2006-01-02", params.DateTo)
+		if err != nil {
+			return nil, 0, apperrors.ErrValidation("invalid date_to")
+		}
+		dateTo = date
+	}
+	if dateTo.Before(dateFrom) {
+		return nil, 0, apperrors.ErrValidation("date_to must not be before date_from")
+	}
+	if days := int(dateTo.Sub(dateFrom).Hours()/24) + 1; days > maxMovieShowtimeDays {
+		return nil, 0, apperrors.ErrValidation(fmt.Sprintf("date range cannot exceed %d days", maxMovieShowtimeDays))
+	}
+
+	showtimes, total, err := s.showtimeRepo.ListForMovie(ctx, repository.MovieShowtimeFilter{
+		MovieID:     movieID,
+		CinemaID:    cinemaID,
+		City:        strings.TrimSpace(params.City),
+		DateFrom:    showDateFrom,
+		DateTo:      dateTo,
+		StartsAfter: now,
+	}, (page-1)*limit, limit)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	var responses []*ShowtimeResponse
+	held := s.heldCounts(ctx, showtimeIDs(showtimes))
+
+	// Showtimes come ordered by cinema, then date and start time
+	responses := make([]CinemaShowtimesResponse, 0)
 	for _, st := range showtimes {
-		responses = append(responses, s.toShowtimeResponse(st))
+		date := st.ShowDate.Format("2006-01-02")
+		if n := len(responses); n == 0 || responses[n-1].CinemaID != st.CinemaID {
+			responses = append(responses, CinemaShowtimesResponse{
+				CinemaID:   st.CinemaID,
+				CinemaName: st.Cinema.Name,
+				Address:    st.Cinema.Address,
+				City:       st.Cinema.City,
+			})
+		}
+		cinema := &responses[len(responses)-1]
+		if n := len(cinema.Dates); n == 0 || cinema.Dates[n-1].Date != date {
+			cinema.Dates = append(cinema.Dates, ShowtimeDateResponse{Date: date})
+		}
+		day := &cinema.Dates[len(cinema.Dates)-1]
+		availability := s.toAvailabilityResponse(st, held[st.ID])
+		percent := 0
+		if availability.Capacity > 0 {
+			percent = int(math.Round(float64(availability.Available) / float64(availability.Capacity) * 100))
+		}
+		day.Showtimes = append(day.Showtimes, MovieShowtimeResponse{
+			ID:               st.ID,
+			ScreenID:         st.ScreenID,
+			ScreenName:       st.Screen.Name,
+			Format:           string(st.Screen.ScreenType),
+			StartTime:        st.StartTime,
+			EndTime:          st.EndTime,
+			PriceTier:        string(st.PriceTier),
+			AvailableSeats:   availability.Available,
+			AvailablePercent: percent,
+			SalesState:       string(st.SalesStateAt(now, st.Cinema.Location())),
+		})
 	}
 
-	return responses, nil
+	return responses, total, nil
+}
+
+// UpdateCapacity changes a showtime's capacity limit, distancing pattern and
+// visibility. Seats already sold are kept; available seats are recomputed
+// against the new effective capacity.
+func (s *Service) UpdateCapacity(ctx context.Context, id uuid.UUID, req UpdateCapacityRequest) (*ShowtimeResponse, error) {
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousCapacity := showtime.EffectiveCapacity()
+	before := *showtime
+
+	if req.ClearCapacityLimit {
+		showtime.CapacityLimit = nil
+	}
+	if req.CapacityLimit != nil {
+		if *req.CapacityLimit > showtime.TotalSeats {
+			return nil, apperrors.ErrValidation("capacity_limit cannot exceed the screen's seat count")
+		}
+		limit := *req.CapacityLimit
+		showtime.CapacityLimit = &limit
+	}
+
+	if req.Distancing != nil {
+		showtime.Distancing = *req.Distancing
+		showtime.BlockedSeats = 0
+		if showtime.Distancing {
+			seats, err := s.seatRepo.GetByScreenID(ctx, showtime.ScreenID)
+			if err != nil {
+				return nil, err
+			}
+			showtime.BlockedSeats = len(entity.CheckerboardBlocked(seats))
+		}
+	}
+
+	if req.Visibility != "" {
+		showtime.Visibility = entity.ShowtimeVisibility(req.Visibility)
+	}
+	if req.AccessCode != "" {
+		hash := authinfra.HashToken(req.AccessCode)
+		showtime.AccessCodeHash = &hash
+	}
+	if showtime.Visibility == entity.VisibilityPrivate && showtime.AccessCodeHash == nil {
+		return nil, apperrors.ErrValidation("access_code is required for private showtimes")
+	}
+	if showtime.Visibility != entity.VisibilityPrivate {
+		showtime.AccessCodeHash = nil
+	}
+
+	showtime.ApplyCapacity(previousCapacity)
+
+	if err := s.showtimeRepo.UpdateWithVersion(ctx, showtime); err != nil {
+		s.logger.Error("failed to update showtime capacity", zap.String("showtime_id", id.String()), zap.Error(err))
+		return nil, err
+	}
+	s.changeLog.Record(ctx, entity.ChangeEntityShowtime, showtime.ID, before, *showtime)
+
+	s.logger.Info("showtime capacity updated",
+		zap.String("showtime_id", id.String()),
+		zap.Int("capacity", showtime.EffectiveCapacity()),
+		zap.Int("available_seats", showtime.AvailableSeats),
+		zap.String("visibility", string(showtime.Visibility)),
+	)
+
+	return s.toShowtimeResponse(showtime), nil
+}
+
+// GetSeatTypeRules returns a showtime's per-seat-type online sales rules
+// with the seats of each type sold online so far
+func (s *Service) GetSeatTypeRules(ctx context.Context, id uuid.UUID) ([]SeatTypeRuleResponse, error) {
+	if _, err := s.showtimeRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.ListByShowtime(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sold, err := s.ruleRepo.CountOnlineSold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSeatTypeRuleResponses(rules, sold), nil
+}
+
+// UpdateSeatTypeRules replaces a showtime's per-seat-type online sales
+// rules. A cap below the seats already sold online only stops further
+// online sales; bookings already made are kept.
+func (s *Service) UpdateSeatTypeRules(ctx context.Context, id uuid.UUID, req UpdateSeatTypeRulesRequest) ([]SeatTypeRuleResponse, error) {
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	start := showtime.StartsAt(showtime.Cinema.Location())
+
+	rules := make([]*entity.SeatTypeRule, 0, len(req.Rules))
+	seen := make(map[entity.SeatType]bool, len(req.Rules))
+	for _, r := range req.Rules {
+		seatType := entity.SeatType(r.SeatType)
+		if seen[seatType] {
+			return nil, apperrors.ErrValidation("seat type " + r.SeatType + " has more than one rule")
+		}
+		seen[seatType] = true
+
+		if r.OnlineSalesCap == nil && r.SalesOpenAt == nil {
+			continue
+		}
+		if r.SalesOpenAt != nil && !r.SalesOpenAt.Before(start) {
+			return nil, apperrors.ErrValidation("sales_open_at for " + r.SeatType + " seats must be before the showtime start")
+		}
+		rules = append(rules, &entity.SeatTypeRule{
+			SeatType:       seatType,
+			OnlineSalesCap: r.OnlineSalesCap,
+			SalesOpenAt:    r.SalesOpenAt,
+		})
+	}
+
+	if err := s.ruleRepo.Replace(ctx, id, rules); err != nil {
+		s.logger.Error("failed to update seat type rules", zap.String("showtime_id", id.String()), zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("seat type rules updated",
+		zap.String("showtime_id", id.String()),
+		zap.Int("rules", len(rules)),
+	)
+
+	return s.GetSeatTypeRules(ctx, id)
 }
 
 // Delete deletes a showtime
@@ -208,7 +713,55 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.showtimeRepo.Delete(ctx, id)
 }
 
+// validateSalesWindow checks that sales open before they close and close no
+// later than the showtime starts in the cinema's time zone
+func validateSalesWindow(st *entity.Showtime, loc *time.Location) error {
+	start := st.StartsAt(loc)
+
+	if st.SalesOpenAt != nil && st.SalesCloseAt != nil && !st.SalesOpenAt.Before(*st.SalesCloseAt) {
+		return apperrors.ErrValidation("sales_open_at must be before sales_close_at")
+	}
+	if st.SalesCloseAt != nil && st.SalesCloseAt.After(start) {
+		return apperrors.ErrValidation("sales_close_at cannot be after the showtime start")
+	}
+	if st.SalesOpenAt != nil && !st.SalesOpenAt.Before(start) {
+		return apperrors.ErrValidation("sales_open_at must be before the showtime start")
+	}
+	return nil
+}
+
+func hashCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	return authinfra.HashToken(code)
+}
+
+func (s *Service) toAvailabilityResponse(st *entity.Showtime, held int) *AvailabilityResponse {
+	capacity := st.EffectiveCapacity()
+	available := max(min(st.AvailableSeats, capacity)-held, 0)
+
+	badge := AvailabilityPlenty
+	switch {
+	case available == 0:
+		badge = AvailabilitySoldOut
+	case available <= s.availability.LimitedSeats,
+		float64(available) <= float64(capacity)*s.availability.LimitedRatio:
+		badge = AvailabilityLimited
+	}
+
+	return &AvailabilityResponse{
+		ShowtimeID: st.ID,
+		Available:  available,
+		Capacity:   capacity,
+		Held:       held,
+		Badge:      badge,
+	}
+}
+
 func (s *Service) toShowtimeResponse(st *entity.Showtime) *ShowtimeResponse {
+	loc := st.Cinema.Location()
+
 	return &ShowtimeResponse{
 		ID:             st.ID,
 		CinemaID:       st.CinemaID,
@@ -222,8 +775,19 @@ func (s *Service) toShowtimeResponse(st *entity.Showtime) *ShowtimeResponse {
 		TotalSeats:     st.TotalSeats,
 		AvailableSeats: st.AvailableSeats,
 		Status:         string(st.Status),
+		SalesState:     string(st.SalesStateAt(time.Now(), loc)),
+		SalesOpenAt:    st.SalesOpenAt,
+		SalesCloseAt:   st.SalesCloseTime(loc),
+		HasPresale:     st.HasPresale(),
+		Capacity:       st.EffectiveCapacity(),
+		CapacityLimit:  st.CapacityLimit,
+		Distancing:     st.Distancing,
+		SoldOut:        st.AvailableSeats == 0,
+		Visibility:     string(st.Visibility),
 		CinemaName:     st.Cinema.Name,
 		ScreenName:     st.Screen.Name,
 		MovieTitle:     st.Movie.Title,
+		MovieUnavailable:  st.MovieUnavailable(),
+		ScreenUnavailable: st.ScreenUnavailable(),
 	}
 }
@@ -0,0 +1,354 @@
+package showtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/apiversion"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestValidateSalesWindow(t *testing.T) {
+	saigon, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20:00 in Saigon
+	start := time.Date(2026, 10, 16, 20, 0, 0, 0, saigon)
+	at := func(d time.Duration) *time.Time {
+		v := start.Add(d)
+		return &v
+	}
+
+	tests := []struct {
+		name    string
+		open    *time.Time
+		close   *time.Time
+		wantErr bool
+	}{
+		{"no window", nil, nil, false},
+		{"open a week early", at(-7 * 24 * time.Hour), nil, false},
+		{"close at the start", nil, at(0), false},
+		{"close after the start", nil, at(time.Second), true},
+		{"open at the start", at(0), nil, true},
+		{"open equals close", at(-time.Hour), at(-time.Hour), true},
+		{"open after close", at(-time.Hour), at(-2 * time.Hour), true},
+		{"open before close", at(-2 * time.Hour), at(-time.Hour), false},
+	}
+	for _, tt := range tests {
+		st := &entity.Showtime{
+			ShowDate:     time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			StartTime:    "20:00",
+			SalesOpenAt:  tt.open,
+			SalesCloseAt: tt.close,
+		}
+		if err := validateSalesWindow(st, saigon); (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDeactivatedMovieKeepsItsTitle(t *testing.T) {
+	st := &entity.Showtime{
+		ID:        uuid.New(),
+		ShowDate:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		StartTime: "20:00",
+		Movie:     entity.Movie{ID: uuid.New(), Title: "Dune: Part Two", IsActive: true},
+		Screen:    entity.Screen{ID: uuid.New(), Name: "Screen 1", IsActive: true},
+		Cinema:    entity.Cinema{ID: uuid.New(), Name: "Downtown"},
+	}
+	svc := &Service{}
+
+	before := svc.toShowtimeResponse(st)
+	if before.MovieTitle != "Dune: Part Two" || before.MovieUnavailable {
+		t.Fatalf("active movie: title %q, unavailable %v", before.MovieTitle, before.MovieUnavailable)
+	}
+
+	// The movie is pulled from the catalogue after tickets were sold
+	st.Movie.IsActive = false
+	st.Movie.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	after := svc.toShowtimeResponse(st)
+	if after.MovieTitle != "Dune: Part Two" || !after.MovieUnavailable || after.ScreenUnavailable {
+		t.Errorf("deactivated movie: title %q, movie unavailable %v, screen unavailable %v",
+			after.MovieTitle, after.MovieUnavailable, after.ScreenUnavailable)
+	}
+	v2 := after.ForVersion(apiversion.V2).(*ShowtimeResponseV2)
+	if v2.MovieTitle != "Dune: Part Two" || !v2.MovieUnavailable {
+		t.Errorf("v2: title %q, unavailable %v", v2.MovieTitle, v2.MovieUnavailable)
+	}
+}
+
+// memShowtimes holds a screen's schedule for CreateBatch and the showtimes
+// ListForMovie returns
+type memShowtimes struct {
+	repository.ShowtimeRepository
+	existing []*entity.Showtime
+	batches  int
+
+	forMovie  []*entity.Showtime
+	cinemas   int64
+	filter    repository.MovieShowtimeFilter
+	offset    int
+	listLimit int
+}
+
+func (m *memShowtimes) ListForMovie(_ context.Context, filter repository.MovieShowtimeFilter, offset, limit int) ([]*entity.Showtime, int64, error) {
+	m.filter, m.offset, m.listLimit = filter, offset, limit
+	return m.forMovie, m.cinemas, nil
+}
+
+// memHeld counts the seats held per showtime
+type memHeld struct {
+	repository.SeatHoldRepository
+	held map[uuid.UUID]int
+}
+
+func (m *memHeld) GetHeldCounts(context.Context, []uuid.UUID) (map[uuid.UUID]int, error) {
+	return m.held, nil
+}
+
+func (m *memShowtimes) CreateBatch(_ context.Context, _ uuid.UUID, showtimes []*entity.Showtime, gap time.Duration) ([]repository.ShowtimeClash, error) {
+	m.batches++
+	var clashes []repository.ShowtimeClash
+next:
+	for _, st := range showtimes {
+		for _, other := range m.existing {
+			if st.ClashesWith(other, gap) {
+				clashes = append(clashes, repository.ShowtimeClash{Showtime: st, With: other})
+				continue next
+			}
+		}
+		st.ID = uuid.New()
+		m.existing = append(m.existing, st)
+	}
+	return clashes, nil
+}
+
+type memMovies struct {
+	repository.MovieRepository
+	movie *entity.Movie
+}
+
+func (m *memMovies) GetByID(context.Context, uuid.UUID) (*entity.Movie, error) {
+	return m.movie, nil
+}
+
+type memScreens struct {
+	repository.ScreenRepository
+	screen *entity.Screen
+}
+
+func (m *memScreens) GetByID(context.Context, uuid.UUID) (*entity.Screen, error) {
+	return m.screen, nil
+}
+
+type memCinemas struct {
+	repository.CinemaRepository
+	cinema *entity.Cinema
+}
+
+func (m *memCinemas) GetByID(context.Context, uuid.UUID) (*entity.Cinema, error) {
+	return m.cinema, nil
+}
+
+func TestBulkCreate(t *testing.T) {
+	cinema := &entity.Cinema{ID: uuid.New(), Timezone: "UTC"}
+	screen := &entity.Screen{ID: uuid.New(), CinemaID: cinema.ID, Capacity: 80, IsActive: true}
+	movie := &entity.Movie{ID: uuid.New(), Duration: 120, IsActive: true}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	date := func(days int) string { return today.AddDate(0, 0, days).Format("2006-01-02") }
+
+	// Another show already runs 21:00-23:00 on the second day
+	showtimes := &memShowtimes{existing: []*entity.Showtime{
+		{ID: uuid.New(), ScreenID: screen.ID, ShowDate: today.AddDate(0, 0, 2), StartTime: "21:00:00", EndTime: "23:00:00"},
+	}}
+	svc := NewService(showtimes, &memMovies{movie: movie}, &memCinemas{cinema: cinema}, &memScreens{screen: screen},
+		nil, nil, nil, nil, config.AvailabilityConfig{}, nil, &logger.Logger{Logger: zap.NewNop()})
+
+	resp, err := svc.BulkCreate(context.Background(), BulkCreateShowtimesRequest{
+		ScreenID:  screen.ID,
+		MovieID:   movie.ID,
+		StartDate: date(1),
+		EndDate:   date(3),
+		ShowTimes: []string{"20:00", "14:00", "20:00"},
+		BasePrice: 9,
+	})
+	if err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+
+	// 20:00 on the second day clashes and the duplicate time is scheduled
+	// once
+	if resp.Total != 5 || len(resp.CreatedIDs) != 5 {
+		t.Errorf("created %d (%d IDs), want 5", resp.Total, len(resp.CreatedIDs))
+	}
+	if len(resp.Skipped) != 1 || resp.Skipped[0].ShowDate != date(2) || resp.Skipped[0].StartTime != "20:00" ||
+		!strings.Contains(resp.Skipped[0].Reason, "21:00-23:00 show on "+date(2)) {
+		t.Errorf("skipped %+v, want 20:00 on %s", resp.Skipped, date(2))
+	}
+	for _, st := range showtimes.existing[1:] {
+		if st.EndTime != "22:00" && st.EndTime != "16:00" || st.TotalSeats != 80 || st.AvailableSeats != 80 ||
+			st.PriceTier != entity.PriceTierStandard || st.BasePrice != 9 {
+			t.Errorf("created showtime %+v", st)
+		}
+	}
+
+	// Running the same template again creates nothing
+	again, err := svc.BulkCreate(context.Background(), BulkCreateShowtimesRequest{
+		ScreenID: screen.ID, MovieID: movie.ID, StartDate: date(1), EndDate: date(3), ShowTimes: []string{"14:00", "20:00"}, BasePrice: 9,
+	})
+	if err != nil {
+		t.Fatalf("BulkCreate again: %v", err)
+	}
+	if again.Total != 0 || len(again.Skipped) != 6 {
+		t.Errorf("second run created %d and skipped %d showtimes, want 0 and 6", again.Total, len(again.Skipped))
+	}
+
+	// Slots that have already started are never sent to the repository
+	batches := showtimes.batches
+	past, err := svc.BulkCreate(context.Background(), BulkCreateShowtimesRequest{
+		ScreenID: screen.ID, MovieID: movie.ID, StartDate: date(-2), EndDate: date(-1), ShowTimes: []string{"20:00"}, BasePrice: 9,
+	})
+	if err != nil {
+		t.Fatalf("BulkCreate in the past: %v", err)
+	}
+	if past.Total != 0 || len(past.Skipped) != 2 || past.Skipped[0].Reason != "start time has passed" {
+		t.Errorf("past slots: created %d, skipped %+v", past.Total, past.Skipped)
+	}
+	if len(showtimes.existing) != 6 || showtimes.batches != batches+1 {
+		t.Errorf("past slots reached the repository")
+	}
+}
+
+func TestBulkCreateRejects(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	date := func(days int) string { return today.AddDate(0, 0, days).Format("2006-01-02") }
+
+	tests := []struct {
+		name   string
+		start  string
+		end    string
+		movie  entity.Movie
+		screen entity.Screen
+	}{
+		{"end before start", date(2), date(1), entity.Movie{IsActive: true}, entity.Screen{IsActive: true}},
+		{"range too long", date(1), date(1 + maxBulkCreateDays), entity.Movie{IsActive: true}, entity.Screen{IsActive: true}},
+		{"bad date", "tomorrow", date(1), entity.Movie{IsActive: true}, entity.Screen{IsActive: true}},
+		{"inactive movie", date(1), date(2), entity.Movie{}, entity.Screen{IsActive: true}},
+		{"inactive screen", date(1), date(2), entity.Movie{IsActive: true}, entity.Screen{}},
+	}
+	for _, tt := range tests {
+		showtimes := &memShowtimes{}
+		svc := NewService(showtimes, &memMovies{movie: &tt.movie}, &memCinemas{cinema: &entity.Cinema{}}, &memScreens{screen: &tt.screen},
+			nil, nil, nil, nil, config.AvailabilityConfig{}, nil, &logger.Logger{Logger: zap.NewNop()})
+		_, err := svc.BulkCreate(context.Background(), BulkCreateShowtimesRequest{
+			StartDate: tt.start, EndDate: tt.end, ShowTimes: []string{"20:00"},
+		})
+		if !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%s: err = %v, want a validation error", tt.name, err)
+		}
+		if showtimes.batches != 0 {
+			t.Errorf("%s: a batch was created", tt.name)
+		}
+	}
+}
+
+func TestListForMovie(t *testing.T) {
+	ctx := context.Background()
+	movie := &entity.Movie{ID: uuid.New(), IsActive: true}
+	downtown := entity.Cinema{ID: uuid.New(), Name: "Downtown", City: "Hanoi", Timezone: "Asia/Ho_Chi_Minh"}
+	riverside := entity.Cinema{ID: uuid.New(), Name: "Riverside", City: "Hanoi"}
+	imax := entity.Screen{ID: uuid.New(), Name: "IMAX 1", ScreenType: entity.ScreenIMAX}
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	show := func(cinema entity.Cinema, day time.Time, start string) *entity.Showtime {
+		return &entity.Showtime{ID: uuid.New(), CinemaID: cinema.ID, Cinema: cinema, ScreenID: imax.ID, Screen: imax,
+			MovieID: movie.ID, ShowDate: day, StartTime: start, EndTime: "23:00", PriceTier: entity.PriceTierStandard,
+			Status: entity.ShowtimeScheduled, TotalSeats: 100, AvailableSeats: 40}
+	}
+	first := show(downtown, tomorrow, "18:00")
+	showtimes := &memShowtimes{cinemas: 5, forMovie: []*entity.Showtime{
+		first,
+		show(downtown, tomorrow, "21:00"),
+		show(downtown, tomorrow.AddDate(0, 0, 1), "18:00"),
+		show(riverside, tomorrow, "20:00"),
+	}}
+	svc := NewService(showtimes, &memMovies{movie: movie}, nil, nil, nil, &memHeld{held: map[uuid.UUID]int{first.ID: 15}},
+		nil, nil, config.AvailabilityConfig{}, nil, &logger.Logger{Logger: zap.NewNop()})
+
+	cinemas, total, err := svc.ListForMovie(ctx, movie.ID, MovieShowtimesParams{City: " hanoi "}, 2, 2)
+	if err != nil {
+		t.Fatalf("ListForMovie: %v", err)
+	}
+	if total != 5 || showtimes.offset != 2 || showtimes.listLimit != 2 {
+		t.Errorf("total %d, offset %d, limit %d", total, showtimes.offset, showtimes.listLimit)
+	}
+	if len(cinemas) != 2 || cinemas[0].CinemaName != "Downtown" || cinemas[1].CinemaName != "Riverside" {
+		t.Fatalf("cinemas = %+v", cinemas)
+	}
+	dates := cinemas[0].Dates
+	if len(dates) != 2 || dates[0].Date != tomorrow.Format("2006-01-02") || len(dates[0].Showtimes) != 2 || len(dates[1].Showtimes) != 1 {
+		t.Fatalf("Downtown dates = %+v", dates)
+	}
+	got := dates[0].Showtimes[0]
+	if got.ID != first.ID || got.ScreenName != "IMAX 1" || got.Format != "IMAX" || got.StartTime != "18:00" ||
+		got.AvailableSeats != 25 || got.AvailablePercent != 25 || got.PriceTier != "STANDARD" {
+		t.Errorf("first showtime = %+v, want 25 seats, 25%%, left after the held ones", got)
+	}
+
+	// The filter starts a day back for cinemas behind UTC and spans a week
+	f := showtimes.filter
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if f.MovieID != movie.ID || f.City != "hanoi" || !f.DateFrom.Equal(today.AddDate(0, 0, -1)) ||
+		!f.DateTo.Equal(today.AddDate(0, 0, movieShowtimeDays-1)) || time.Since(f.StartsAfter) > time.Minute {
+		t.Errorf("filter = %+v", f)
+	}
+
+	// Explicit dates are used as given
+	if _, _, err := svc.ListForMovie(ctx, movie.ID, MovieShowtimesParams{DateFrom: "2030-01-01", DateTo: "2030-01-31"}, 1, 10); err != nil {
+		t.Fatalf("ListForMovie with dates: %v", err)
+	}
+	if f := showtimes.filter; f.DateFrom.Format("2006-01-02") != "2030-01-01" || f.DateTo.Format("2006-01-02") != "2030-01-31" {
+		t.Errorf("filter dates %s to %s", f.DateFrom, f.DateTo)
+	}
+	if f.CinemaID != uuid.Nil {
+		t.Errorf("filter cinema %s without cinema_id", f.CinemaID)
+	}
+
+	// cinema_id narrows the listing to one cinema
+	if _, _, err := svc.ListForMovie(ctx, movie.ID, MovieShowtimesParams{CinemaID: riverside.ID.String()}, 1, 10); err != nil {
+		t.Fatalf("ListForMovie with a cinema: %v", err)
+	}
+	if showtimes.filter.CinemaID != riverside.ID {
+		t.Errorf("filter cinema %s, want Riverside", showtimes.filter.CinemaID)
+	}
+}
+
+func TestListForMovieRejectsDates(t *testing.T) {
+	movie := &entity.Movie{ID: uuid.New(), IsActive: true}
+	svc := NewService(&memShowtimes{}, &memMovies{movie: movie}, nil, nil, nil, &memHeld{},
+		nil, nil, config.AvailabilityConfig{}, nil, &logger.Logger{Logger: zap.NewNop()})
+
+	for _, params := range []MovieShowtimesParams{
+		{DateFrom: "01/07/2030"},
+		{DateTo: "soon"},
+		{DateFrom: "2030-01-07", DateTo: "2030-01-06"},
+		{DateFrom: "2030-01-01", DateTo: "2030-02-01"},
+		{CinemaID: "downtown"},
+	} {
+		if _, _, err := svc.ListForMovie(context.Background(), movie.ID, params, 1, 10); !apperrors.Is(err, apperrors.CodeValidation) {
+			t.Errorf("%+v: err = %v, want a validation error", params, err)
+		}
+	}
+}
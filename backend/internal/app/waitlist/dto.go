@@ -0,0 +1,28 @@
+package waitlist
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JoinRequest represents a request to join a showtime's waitlist
+type JoinRequest struct {
+	SeatCount int `json:"seat_count" validate:"required,min=1,max=10"`
+	// Email is where guests without an account are notified
+	Email      string `json:"email,omitempty" validate:"omitempty,email"`
+	AccessCode string `json:"access_code,omitempty"` // required for private showtimes
+}
+
+// PositionResponse is a customer's place on a showtime's waitlist
+type PositionResponse struct {
+	EntryID    uuid.UUID `json:"entry_id" swaggertype:"string" format:"uuid"`
+	ShowtimeID uuid.UUID `json:"showtime_id" swaggertype:"string" format:"uuid"`
+	Status     string    `json:"status"`
+	// Position counts from 1 at the front of the queue; it is left out
+	// once the entry is no longer waiting
+	Position   int64      `json:"position,omitempty"`
+	SeatCount  int        `json:"seat_count"`
+	JoinedAt   time.Time  `json:"joined_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
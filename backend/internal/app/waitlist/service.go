@@ -0,0 +1,240 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/eventbus"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service queues customers for sold-out showtimes and offers them the
+// seats that cancellations release, first come first served
+type Service struct {
+	waitlistRepo repository.WaitlistRepository
+	showtimeRepo repository.ShowtimeRepository
+	userRepo     repository.UserRepository
+	bookings     *booking.Service
+	dispatcher   *async.Dispatcher
+	logger       *logger.Logger
+	frontendURL  string
+}
+
+// NewService creates a new waitlist service
+func NewService(
+	waitlistRepo repository.WaitlistRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	userRepo repository.UserRepository,
+	bookings *booking.Service,
+	dispatcher *async.Dispatcher,
+	logger *logger.Logger,
+	frontendURL string,
+) *Service {
+	return &Service{
+		waitlistRepo: waitlistRepo,
+		showtimeRepo: showtimeRepo,
+		userRepo:     userRepo,
+		bookings:     bookings,
+		dispatcher:   dispatcher,
+		logger:       logger,
+		frontendURL:  frontendURL,
+	}
+}
+
+// Join puts the customer on the waitlist of a sold-out showtime. Signed-in
+// customers are identified by userID; guests pass the email to notify.
+func (s *Service) Join(ctx context.Context, userID *uuid.UUID, showtimeID uuid.UUID, req JoinRequest) (*PositionResponse, error) {
+	if userID == nil && req.Email == "" {
+		return nil, apperrors.ErrBadRequest("email is required to join without an account")
+	}
+
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !showtime.CanAccess(hashCode(req.AccessCode)) {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	if showtime.Status != entity.ShowtimeScheduled ||
+		showtime.SalesStateAt(time.Now(), showtime.Cinema.Location()) != entity.SalesOnSale {
+		return nil, apperrors.ErrBadRequest("showtime is not open for booking")
+	}
+	if !showtime.IsFull() {
+		return nil, apperrors.ErrConflict("showtime still has seats available")
+	}
+
+	entry := &entity.WaitlistEntry{
+		ShowtimeID: showtimeID,
+		UserID:     userID,
+		SeatCount:  req.SeatCount,
+	}
+	if userID == nil {
+		entry.GuestEmail = strings.TrimSpace(req.Email)
+	}
+	if err := s.waitlistRepo.Enqueue(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("joined waitlist",
+		zap.String("showtime_id", showtimeID.String()),
+		zap.Int64("position", entry.Position),
+		zap.Int("seats", entry.SeatCount),
+	)
+	return s.toPositionResponse(ctx, entry)
+}
+
+// Position returns where the user's latest entry for the showtime stands
+func (s *Service) Position(ctx context.Context, userID, showtimeID uuid.UUID) (*PositionResponse, error) {
+	entry, err := s.waitlistRepo.GetByUser(ctx, showtimeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toPositionResponse(ctx, entry)
+}
+
+func (s *Service) toPositionResponse(ctx context.Context, entry *entity.WaitlistEntry) (*PositionResponse, error) {
+	res := &PositionResponse{
+		EntryID:    entry.ID,
+		ShowtimeID: entry.ShowtimeID,
+		Status:     string(entry.Status),
+		SeatCount:  entry.SeatCount,
+		JoinedAt:   entry.CreatedAt,
+		NotifiedAt: entry.NotifiedAt,
+	}
+	if entry.Status == entity.WaitlistWaiting {
+		ahead, err := s.waitlistRepo.CountAhead(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		res.Position = ahead + 1
+	}
+	return res, nil
+}
+
+// RegisterSubscribers subscribes the service's side effects to domain events
+func (s *Service) RegisterSubscribers(bus *eventbus.Bus) {
+	bus.Subscribe(events.BookingCancelledEvent, "waitlist.offer_released_seats", s.onBookingCancelled)
+	bus.Subscribe(events.ShowtimeCancelledEvent, "waitlist.expire", s.onShowtimeCancelled)
+}
+
+// onBookingCancelled offers the released seats to the waitlist in order.
+// Each entry that fits is taken off the queue, a hold on its share of the
+// seats is made for signed-in customers, and the customer is emailed.
+func (s *Service) onBookingCancelled(ctx context.Context, event eventbus.Event) error {
+	cancelled := event.(events.BookingCancelled)
+	seats := cancelled.SeatIDs
+
+	for len(seats) > 0 {
+		entry, err := s.waitlistRepo.Dequeue(ctx, cancelled.ShowtimeID, len(seats))
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return nil
+		}
+		offered := seats[:entry.SeatCount]
+		seats = seats[entry.SeatCount:]
+
+		s.offer(ctx, entry, offered)
+	}
+	return nil
+}
+
+// offer holds the seats for a dequeued entry where it can and tells the
+// customer. Guests cannot hold seats without an account, and someone else
+// may have taken the seats first; either way the customer is still told
+// that seats opened up.
+func (s *Service) offer(ctx context.Context, entry *entity.WaitlistEntry, seatIDs []uuid.UUID) {
+	log := s.logger.WithContext(ctx).WithField("waitlist_entry_id", entry.ID.String())
+
+	showtime, err := s.showtimeRepo.GetByIDWithDetails(ctx, entry.ShowtimeID)
+	if err != nil {
+		log.Error("failed to load showtime for waitlist offer", zap.Error(err))
+		return
+	}
+
+	to := entry.GuestEmail
+	var hold *booking.HoldResponse
+	if entry.UserID != nil {
+		user, err := s.userRepo.GetByID(ctx, *entry.UserID)
+		if err != nil {
+			log.Error("failed to load waitlisted user", zap.Error(err))
+			return
+		}
+		to = user.Email
+
+		hold, err = s.bookings.HoldSeats(ctx, *entry.UserID, booking.HoldSeatsRequest{
+			ShowtimeID: entry.ShowtimeID,
+			SeatIDs:    seatIDs,
+		})
+		if err != nil {
+			log.Warn("failed to hold released seats for waitlist", zap.Error(err))
+		}
+	}
+
+	var holdID *string
+	if hold != nil {
+		holdID = &hold.HoldID
+	}
+	if err := s.waitlistRepo.Notify(ctx, entry.ID, holdID, time.Now()); err != nil {
+		log.Warn("failed to record waitlist notification", zap.Error(err))
+	}
+
+	if to == "" {
+		return
+	}
+	if !s.dispatcher.SubmitEmail(async.EmailPayload{
+		To:      []string{to},
+		Subject: "Seats opened up for " + showtime.Movie.Title,
+		Body:    s.offerBody(showtime, entry.SeatCount, hold),
+	}) {
+		log.Warn("email queue full, waitlist offer not sent")
+	}
+}
+
+func (s *Service) offerBody(showtime *entity.Showtime, seats int, hold *booking.HoldResponse) string {
+	loc := showtime.Cinema.Location()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Good news: seats opened up for %s at %s, %s.\n\n",
+		showtime.Movie.Title, showtime.Cinema.Name, showtime.StartsAt(loc).Format("Mon, 02 Jan 2006 15:04"))
+	if hold != nil {
+		fmt.Fprintf(&b, "We are holding %d seats for you until %s. Complete your booking here:\n%s/holds/%s\n",
+			seats, hold.ExpiresAt.In(loc).Format("15:04"), s.frontendURL, hold.HoldID)
+	} else {
+		fmt.Fprintf(&b, "They go to whoever books first, so book now:\n%s/showtimes/%s\n", s.frontendURL, showtime.ID)
+	}
+	return b.String()
+}
+
+// onShowtimeCancelled expires the waitlist of a cancelled showtime
+func (s *Service) onShowtimeCancelled(ctx context.Context, event eventbus.Event) error {
+	cancelled := event.(events.ShowtimeCancelled)
+	expired, err := s.waitlistRepo.ExpireWaiting(ctx, cancelled.ShowtimeID)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		s.logger.WithContext(ctx).Info("expired waitlist of cancelled showtime",
+			zap.String("showtime_id", cancelled.ShowtimeID.String()), zap.Int64("entries", expired))
+	}
+	return nil
+}
+
+func hashCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	return authinfra.HashToken(code)
+}
@@ -0,0 +1,324 @@
+package waitlist
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/events"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/pkg/async"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memWaitlist keeps waitlist entries in memory, in the order the Postgres
+// repository serves them
+type memWaitlist struct {
+	repository.WaitlistRepository
+	entries []*entity.WaitlistEntry
+}
+
+func (m *memWaitlist) Enqueue(_ context.Context, entry *entity.WaitlistEntry) error {
+	var last int64
+	for _, e := range m.entries {
+		if e.ShowtimeID != entry.ShowtimeID {
+			continue
+		}
+		last = max(last, e.Position)
+		if e.Status != entity.WaitlistWaiting {
+			continue
+		}
+		if entry.UserID != nil && e.UserID != nil && *e.UserID == *entry.UserID ||
+			entry.UserID == nil && e.UserID == nil && strings.EqualFold(e.GuestEmail, entry.GuestEmail) {
+			return apperrors.ErrConflict("already on the waitlist for this showtime")
+		}
+	}
+	entry.ID = uuid.New()
+	entry.Position = last + 1
+	entry.Status = entity.WaitlistWaiting
+	entry.CreatedAt = time.Now()
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memWaitlist) Dequeue(_ context.Context, showtimeID uuid.UUID, maxSeats int) (*entity.WaitlistEntry, error) {
+	sort.Slice(m.entries, func(i, j int) bool { return m.entries[i].Position < m.entries[j].Position })
+	for _, e := range m.entries {
+		if e.ShowtimeID == showtimeID && e.Status == entity.WaitlistWaiting && e.SeatCount <= maxSeats {
+			e.Status = entity.WaitlistNotified
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memWaitlist) GetByUser(_ context.Context, showtimeID, userID uuid.UUID) (*entity.WaitlistEntry, error) {
+	var latest *entity.WaitlistEntry
+	for _, e := range m.entries {
+		if e.ShowtimeID == showtimeID && e.UserID != nil && *e.UserID == userID &&
+			(latest == nil || e.Position > latest.Position) {
+			latest = e
+		}
+	}
+	if latest == nil {
+		return nil, apperrors.ErrNotFound("waitlist entry")
+	}
+	return latest, nil
+}
+
+func (m *memWaitlist) CountAhead(_ context.Context, entry *entity.WaitlistEntry) (int64, error) {
+	var ahead int64
+	for _, e := range m.entries {
+		if e.ShowtimeID == entry.ShowtimeID && e.Status == entity.WaitlistWaiting && e.Position < entry.Position {
+			ahead++
+		}
+	}
+	return ahead, nil
+}
+
+func (m *memWaitlist) Notify(_ context.Context, id uuid.UUID, holdID *string, at time.Time) error {
+	for _, e := range m.entries {
+		if e.ID == id {
+			e.HoldID = holdID
+			e.NotifiedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *memWaitlist) ExpireWaiting(_ context.Context, showtimeID uuid.UUID) (int64, error) {
+	var expired int64
+	for _, e := range m.entries {
+		if e.ShowtimeID == showtimeID && e.Status == entity.WaitlistWaiting {
+			e.Status = entity.WaitlistExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// stubShowtimes serves a single showtime
+type stubShowtimes struct {
+	repository.ShowtimeRepository
+	showtime *entity.Showtime
+}
+
+func (s *stubShowtimes) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	if s.showtime.ID != id {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	copied := *s.showtime
+	return &copied, nil
+}
+
+// fakeSender hands the emails it is given to the test
+type fakeSender struct {
+	sent chan async.EmailPayload
+}
+
+func (f *fakeSender) Send(_ context.Context, email async.EmailPayload) error {
+	f.sent <- email
+	return nil
+}
+
+type waitlistFixture struct {
+	svc      *Service
+	entries  *memWaitlist
+	showtime *entity.Showtime
+	sender   *fakeSender
+}
+
+// newWaitlistFixture returns a service for a sold-out showtime a week
+// away. Only guests are waitlisted in these tests, so no booking service is
+// needed to hold seats.
+func newWaitlistFixture(t *testing.T) *waitlistFixture {
+	t.Helper()
+	log := &logger.Logger{Logger: zap.NewNop()}
+	f := &waitlistFixture{
+		entries: &memWaitlist{},
+		showtime: &entity.Showtime{
+			ID:             uuid.New(),
+			ShowDate:       time.Now().AddDate(0, 0, 7),
+			StartTime:      "20:00",
+			Status:         entity.ShowtimeScheduled,
+			TotalSeats:     100,
+			AvailableSeats: 0,
+			Movie:          entity.Movie{Title: "Dune: Part Two"},
+			Cinema:         entity.Cinema{Name: "Saigon Central"},
+		},
+		sender: &fakeSender{sent: make(chan async.EmailPayload, 10)},
+	}
+	dispatcher := async.NewDispatcher(1, 10, f.sender, log)
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(time.Second) })
+
+	f.svc = NewService(f.entries, &stubShowtimes{showtime: f.showtime}, nil, nil, dispatcher, log,
+		"https://cinema.example.com")
+	return f
+}
+
+func (f *waitlistFixture) joinAsGuest(t *testing.T, email string, seats int) *PositionResponse {
+	t.Helper()
+	res, err := f.svc.Join(context.Background(), nil, f.showtime.ID, JoinRequest{SeatCount: seats, Email: email})
+	if err != nil {
+		t.Fatalf("Join(%s): %v", email, err)
+	}
+	return res
+}
+
+func TestJoin(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name   string
+		change func(*entity.Showtime)
+		userID *uuid.UUID
+		req    JoinRequest
+		want   apperrors.ErrorCode
+	}{
+		{name: "guest without an email", req: JoinRequest{SeatCount: 2}, want: apperrors.CodeBadRequest},
+		{
+			name:   "seats still available",
+			change: func(s *entity.Showtime) { s.AvailableSeats = 3 },
+			userID: &userID, req: JoinRequest{SeatCount: 2}, want: apperrors.CodeConflict,
+		},
+		{
+			name:   "showtime cancelled",
+			change: func(s *entity.Showtime) { s.Status = entity.ShowtimeCancelled },
+			userID: &userID, req: JoinRequest{SeatCount: 2}, want: apperrors.CodeBadRequest,
+		},
+		{
+			name: "sales closed",
+			change: func(s *entity.Showtime) {
+				s.ShowDate = time.Now().AddDate(0, 0, -1)
+			},
+			userID: &userID, req: JoinRequest{SeatCount: 2}, want: apperrors.CodeBadRequest,
+		},
+		{
+			name:   "private showtime without the access code",
+			change: func(s *entity.Showtime) { s.Visibility = entity.VisibilityPrivate },
+			userID: &userID, req: JoinRequest{SeatCount: 2}, want: apperrors.CodeShowtimeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWaitlistFixture(t)
+			if tt.change != nil {
+				tt.change(f.showtime)
+			}
+			_, err := f.svc.Join(context.Background(), tt.userID, f.showtime.ID, tt.req)
+			if !apperrors.Is(err, tt.want) {
+				t.Errorf("Join = %v, want %s", err, tt.want)
+			}
+			if len(f.entries.entries) != 0 {
+				t.Errorf("%d entries queued, want none", len(f.entries.entries))
+			}
+		})
+	}
+}
+
+func TestJoinAndPosition(t *testing.T) {
+	ctx := context.Background()
+	f := newWaitlistFixture(t)
+	f.joinAsGuest(t, "first@example.com", 2)
+
+	userID := uuid.New()
+	joined, err := f.svc.Join(ctx, &userID, f.showtime.ID, JoinRequest{SeatCount: 1})
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if joined.Position != 2 || joined.Status != string(entity.WaitlistWaiting) {
+		t.Errorf("joined at %d (%s), want 2 (WAITING)", joined.Position, joined.Status)
+	}
+	if _, err := f.svc.Join(ctx, &userID, f.showtime.ID, JoinRequest{SeatCount: 1}); !apperrors.Is(err, apperrors.CodeConflict) {
+		t.Errorf("joining twice = %v, want a conflict", err)
+	}
+
+	// The guest ahead is served, so the user moves to the front
+	f.entries.entries[0].Status = entity.WaitlistNotified
+	position, err := f.svc.Position(ctx, userID, f.showtime.ID)
+	if err != nil {
+		t.Fatalf("Position: %v", err)
+	}
+	if position.Position != 1 {
+		t.Errorf("position = %d, want 1", position.Position)
+	}
+}
+
+func TestCancellationOffersTheSeatsInOrder(t *testing.T) {
+	f := newWaitlistFixture(t)
+	f.joinAsGuest(t, "four@example.com", 4)
+	f.joinAsGuest(t, "two@example.com", 2)
+	f.joinAsGuest(t, "also-two@example.com", 2)
+	f.joinAsGuest(t, "one@example.com", 1)
+
+	// Three seats free up: four seats do not fit, the first two-seat entry
+	// takes two, the second no longer fits and the one-seat entry gets
+	// the last seat
+	err := f.svc.onBookingCancelled(context.Background(), events.BookingCancelled{
+		ShowtimeID: f.showtime.ID,
+		SeatIDs:    []uuid.UUID{uuid.New(), uuid.New(), uuid.New()},
+	})
+	if err != nil {
+		t.Fatalf("onBookingCancelled: %v", err)
+	}
+
+	want := map[string]entity.WaitlistStatus{
+		"four@example.com":     entity.WaitlistWaiting,
+		"two@example.com":      entity.WaitlistNotified,
+		"also-two@example.com": entity.WaitlistWaiting,
+		"one@example.com":      entity.WaitlistNotified,
+	}
+	for _, e := range f.entries.entries {
+		if e.Status != want[e.GuestEmail] {
+			t.Errorf("%s is %s, want %s", e.GuestEmail, e.Status, want[e.GuestEmail])
+		}
+		if notified := e.NotifiedAt != nil; notified != (e.Status == entity.WaitlistNotified) {
+			t.Errorf("%s: notified at %v", e.GuestEmail, e.NotifiedAt)
+		}
+		if e.HoldID != nil {
+			t.Errorf("%s: guest given hold %s", e.GuestEmail, *e.HoldID)
+		}
+	}
+
+	emailed := map[string]bool{}
+	for range 2 {
+		select {
+		case email := <-f.sender.sent:
+			emailed[email.To[0]] = true
+			if !strings.Contains(email.Subject, "Dune: Part Two") ||
+				!strings.Contains(email.Body, "https://cinema.example.com/showtimes/"+f.showtime.ID.String()) {
+				t.Errorf("offer email %q:\n%s", email.Subject, email.Body)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("emailed %v, want two offers", emailed)
+		}
+	}
+	if !emailed["two@example.com"] || !emailed["one@example.com"] {
+		t.Errorf("emailed %v", emailed)
+	}
+}
+
+func TestShowtimeCancellationExpiresTheWaitlist(t *testing.T) {
+	f := newWaitlistFixture(t)
+	f.joinAsGuest(t, "first@example.com", 2)
+	f.joinAsGuest(t, "second@example.com", 1)
+	f.entries.entries[0].Status = entity.WaitlistNotified
+
+	if err := f.svc.onShowtimeCancelled(context.Background(), events.ShowtimeCancelled{ShowtimeID: f.showtime.ID}); err != nil {
+		t.Fatalf("onShowtimeCancelled: %v", err)
+	}
+	if got := f.entries.entries[0].Status; got != entity.WaitlistNotified {
+		t.Errorf("notified entry is %s, want it left alone", got)
+	}
+	if got := f.entries.entries[1].Status; got != entity.WaitlistExpired {
+		t.Errorf("waiting entry is %s, want EXPIRED", got)
+	}
+}
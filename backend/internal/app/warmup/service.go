@@ -0,0 +1,295 @@
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	bookingapp "cinemaos-backend/internal/app/booking"
+	confirmationapp "cinemaos-backend/internal/app/confirmation"
+	curationapp "cinemaos-backend/internal/app/curation"
+	"cinemaos-backend/internal/app/entity"
+	pricingapp "cinemaos-backend/internal/app/pricing"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// State is the progress of the warmup phase
+type State string
+
+const (
+	StatePending  State = "PENDING"
+	StateRunning  State = "RUNNING"
+	StateDone     State = "DONE"
+	StateTimedOut State = "BUDGET_EXCEEDED" // items still running are no longer waited for
+	StateSkipped  State = "SKIPPED"
+)
+
+// TaskResult reports how warming one item went
+type TaskResult struct {
+	Name     string `json:"name"`
+	Done     bool   `json:"done"`
+	Warmed   int    `json:"warmed"` // entries loaded
+	Duration string `json:"duration,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Status reports the warmup phase
+type Status struct {
+	State    State        `json:"state"`
+	Duration string       `json:"duration,omitempty"`
+	Tasks    []TaskResult `json:"tasks,omitempty"`
+}
+
+// Ready reports whether the instance may take traffic: warmup finished,
+// ran out of budget or was skipped
+func (s Status) Ready() bool {
+	return s.State == StateDone || s.State == StateTimedOut || s.State == StateSkipped
+}
+
+type task struct {
+	name string
+	run  func(ctx context.Context) (int, error)
+}
+
+// Service primes caches at startup so the first users after a deploy do not
+// all miss at once. Items are warmed concurrently within a time budget; a
+// failed item is logged and does not hold up readiness.
+type Service struct {
+	showtimeRepo        repository.ShowtimeRepository
+	bookingService      *bookingapp.Service
+	confirmationService *confirmationapp.Service
+	curationService     *curationapp.Service
+	pricingEngine       *pricingapp.RuleBasedEngine
+	cfg                 config.WarmupConfig
+	logger              *logger.Logger
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewService creates a new warmup service
+func NewService(
+	showtimeRepo repository.ShowtimeRepository,
+	bookingService *bookingapp.Service,
+	confirmationService *confirmationapp.Service,
+	curationService *curationapp.Service,
+	pricingEngine *pricingapp.RuleBasedEngine,
+	cfg config.WarmupConfig,
+	logger *logger.Logger,
+) *Service {
+	return &Service{
+		showtimeRepo:        showtimeRepo,
+		bookingService:      bookingService,
+		confirmationService: confirmationService,
+		curationService:     curationService,
+		pricingEngine:       pricingEngine,
+		cfg:                 cfg,
+		logger:              logger,
+		status:              Status{State: StatePending},
+	}
+}
+
+// Status returns the progress of the warmup phase
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Tasks = append([]TaskResult(nil), s.status.Tasks...)
+	return status
+}
+
+// Run warms every item and returns once all are done or the budget runs
+// out. Items still running then are cancelled.
+func (s *Service) Run(ctx context.Context) {
+	if !s.cfg.Enabled {
+		s.setState(StateSkipped, 0)
+		s.logger.Info("cache warmup skipped")
+		return
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Budget)
+	defer cancel()
+
+	// Seat caches and layouts both need the upcoming showtimes; list them once
+	upcoming := sync.OnceValues(func() ([]*entity.Showtime, error) {
+		now := time.Now()
+		return s.showtimeRepo.ListStartingBetween(ctx, now, now.Add(s.cfg.Horizon))
+	})
+	tasks := []task{
+		{name: "now_showing", run: s.warmHome},
+		{name: "seat_caches", run: func(ctx context.Context) (int, error) { return s.warmSeatCaches(ctx, upcoming) }},
+		{name: "seat_layouts", run: func(ctx context.Context) (int, error) { return s.warmLayouts(ctx, upcoming) }},
+		{name: "pricing_rules", run: func(ctx context.Context) (int, error) { return s.warmPricingRules(ctx, upcoming) }},
+	}
+
+	s.mu.Lock()
+	s.status.State = StateRunning
+	s.status.Tasks = make([]TaskResult, len(tasks))
+	for i, t := range tasks {
+		s.status.Tasks[i] = TaskResult{Name: t.name}
+	}
+	s.mu.Unlock()
+
+	var g errgroup.Group
+	for i, t := range tasks {
+		g.Go(func() error {
+			taskStart := time.Now()
+			warmed, err := t.run(ctx)
+			s.finishTask(i, warmed, time.Since(taskStart), err)
+			return nil
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = g.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.setState(StateDone, time.Since(start))
+	case <-ctx.Done():
+		s.setState(StateTimedOut, time.Since(start))
+	}
+
+	status := s.Status()
+	fields := []zap.Field{zap.String("state", string(status.State)), zap.Duration("duration", time.Since(start))}
+	for _, t := range status.Tasks {
+		fields = append(fields, zap.Int(t.Name, t.Warmed))
+	}
+	s.logger.Info("cache warmup finished", fields...)
+}
+
+func (s *Service) warmHome(ctx context.Context) (int, error) {
+	home, err := s.curationService.GetHome(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(home.NowShowing), nil
+}
+
+// warmSeatCaches caches the booked seats and online sales of upcoming
+// showtimes
+func (s *Service) warmSeatCaches(ctx context.Context, upcoming func() ([]*entity.Showtime, error)) (int, error) {
+	showtimes, err := upcoming()
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]uuid.UUID, len(showtimes))
+	for i, showtime := range showtimes {
+		ids[i] = showtime.ID
+	}
+	return s.each(ctx, ids, s.bookingService.WarmSeatCaches)
+}
+
+// warmLayouts caches the seat layouts of the screens of upcoming showtimes
+func (s *Service) warmLayouts(ctx context.Context, upcoming func() ([]*entity.Showtime, error)) (int, error) {
+	showtimes, err := upcoming()
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var screenIDs []uuid.UUID
+	for _, showtime := range showtimes {
+		if !seen[showtime.ScreenID] {
+			seen[showtime.ScreenID] = true
+			screenIDs = append(screenIDs, showtime.ScreenID)
+		}
+	}
+	return s.each(ctx, screenIDs, s.confirmationService.WarmLayout)
+}
+
+// warmPricingRules caches the pricing rules of the cinemas of upcoming
+// showtimes
+func (s *Service) warmPricingRules(ctx context.Context, upcoming func() ([]*entity.Showtime, error)) (int, error) {
+	showtimes, err := upcoming()
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var cinemaIDs []uuid.UUID
+	for _, showtime := range showtimes {
+		if !seen[showtime.CinemaID] {
+			seen[showtime.CinemaID] = true
+			cinemaIDs = append(cinemaIDs, showtime.CinemaID)
+		}
+	}
+	return s.each(ctx, cinemaIDs, s.pricingEngine.Warm)
+}
+
+// each runs warm for every ID, Concurrency at a time, and returns how many
+// succeeded with the first error
+func (s *Service) each(ctx context.Context, ids []uuid.UUID, warm func(context.Context, uuid.UUID) error) (int, error) {
+	var (
+		mu       sync.Mutex
+		warmed   int
+		firstErr error
+	)
+
+	var g errgroup.Group
+	g.SetLimit(max(s.cfg.Concurrency, 1))
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			err := warm(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				warmed++
+			} else if firstErr == nil {
+				firstErr = err
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return warmed, firstErr
+}
+
+func (s *Service) finishTask(i, warmed int, d time.Duration, err error) {
+	s.mu.Lock()
+	result := &s.status.Tasks[i]
+	result.Done = true
+	result.Warmed = warmed
+	result.Duration = d.Round(time.Millisecond).String()
+	if err != nil {
+		result.Error = err.Error()
+	}
+	name := result.Name
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.Warn("cache warmup item failed",
+			zap.String("item", name),
+			zap.Int("warmed", warmed),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Service) setState(state State, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.State = state
+	if d > 0 {
+		s.status.Duration = d.Round(time.Millisecond).String()
+	}
+}
@@ -10,15 +10,36 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	CORS     CORSConfig     `mapstructure:"cors"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
-	Tracer   TracerConfig   `mapstructure:"tracer"`
-	Email    EmailConfig    `mapstructure:"email"`
+	App          AppConfig          `mapstructure:"app"`
+	Server       ServerConfig       `mapstructure:"server"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	Redis        RedisConfig        `mapstructure:"redis"`
+	JWT          JWTConfig          `mapstructure:"jwt"`
+	CORS         CORSConfig         `mapstructure:"cors"`
+	RateLimit    RateLimitConfig    `mapstructure:"rate_limit"`
+	Logger       LoggerConfig       `mapstructure:"logger"`
+	Tracer       TracerConfig       `mapstructure:"tracer"`
+	Metrics      MetricsConfig      `mapstructure:"metrics"`
+	Email        EmailConfig        `mapstructure:"email"`
+	Storage      StorageConfig      `mapstructure:"storage"`
+	Movie        MovieConfig        `mapstructure:"movie"`
+	Booking      BookingConfig      `mapstructure:"booking"`
+	Pricing      PricingConfig      `mapstructure:"pricing"`
+	Loyalty      LoyaltyConfig      `mapstructure:"loyalty"`
+	Ticket       TicketConfig       `mapstructure:"ticket"`
+	GuestLookup  GuestLookupConfig  `mapstructure:"guest_lookup"`
+	Availability AvailabilityConfig `mapstructure:"availability"`
+	Home         HomeConfig         `mapstructure:"home"`
+	Reports      ReportsConfig      `mapstructure:"reports"`
+	Analytics    AnalyticsConfig    `mapstructure:"analytics"`
+	API          APIConfig          `mapstructure:"api"`
+	Idempotency  IdempotencyConfig  `mapstructure:"idempotency"`
+	Events       EventsConfig       `mapstructure:"events"`
+	Shadow       ShadowConfig       `mapstructure:"shadow"`
+	Payment      PaymentConfig      `mapstructure:"payment"`
+	Jobs         JobsConfig         `mapstructure:"jobs"`
+	Warmup       WarmupConfig       `mapstructure:"warmup"`
+	TMDB         TMDBConfig         `mapstructure:"tmdb"`
 }
 
 // AppConfig holds application-level configuration
@@ -36,6 +57,13 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// RequestTimeout is how long a request may run before its context is
+	// cancelled and the client gets 504; zero leaves requests unbounded
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// RouteTimeouts overrides RequestTimeout for single routes or groups of
+	// routes, keyed like rate_limit.routes, e.g. /bookings/confirm or
+	// /admin/*. Zero turns the timeout off for the route.
+	RouteTimeouts map[string]time.Duration `mapstructure:"route_timeouts"`
 }
 
 // DatabaseConfig holds database configuration
@@ -50,6 +78,11 @@ type DatabaseConfig struct {
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
 	DebugLevel      string        `mapstrucutre:"debug_level"`
+	ConnectAttempts int           `mapstructure:"connect_attempts"` // tries at startup before giving up
+	ConnectMaxWait  time.Duration `mapstructure:"connect_max_wait"` // cap on the doubling wait between tries
+	// SlowQueryThreshold is how long a query may run before it is logged as
+	// slow; 0 turns slow query logging off
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
 }
 
 // DSN returns the database connection string
@@ -69,6 +102,10 @@ type RedisConfig struct {
 	PoolSize     int           `mapstructure:"pool_size"`
 	MinIdleConns int           `mapstructure:"min_idle_conns"`
 	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	// Tries at startup before running without Redis, and the cap on the
+	// doubling wait between them
+	ConnectAttempts int           `mapstructure:"connect_attempts"`
+	ConnectMaxWait  time.Duration `mapstructure:"connect_max_wait"`
 }
 
 // Address returns the Redis address
@@ -85,6 +122,15 @@ type JWTConfig struct {
 	ResetTokenExpiry   time.Duration `mapstructure:"reset_token_expiry"`
 	VerifyTokenExpiry  time.Duration `mapstructure:"verify_token_expiry"`
 	Issuer             string        `mapstructure:"issuer"`
+	// KeepSessionOnPasswordChange keeps the session that changed the password
+	// signed in and ends all others, instead of ending every session
+	KeepSessionOnPasswordChange bool `mapstructure:"keep_session_on_password_change"`
+	// GoogleClientID is the OAuth client the frontend's Google Sign-In uses;
+	// Google ID tokens must name it as their audience. Empty turns Google
+	// sign-in off.
+	GoogleClientID string        `mapstructure:"google_client_id"`
+	GoogleCertsURL string        `mapstructure:"google_certs_url"`
+	GoogleTimeout  time.Duration `mapstructure:"google_timeout"` // for fetching Google's signing keys
 }
 
 // CORSConfig holds CORS configuration
@@ -97,6 +143,38 @@ type CORSConfig struct {
 	MaxAge           int      `mapstructure:"max_age"`
 }
 
+// LimitConfig is a request budget of Limit requests per Window
+type LimitConfig struct {
+	Limit  int           `mapstructure:"limit"` // requests allowed per window
+	Window time.Duration `mapstructure:"window"`
+}
+
+// RateLimitConfig holds request rate limits, counted in Redis so every
+// instance shares them
+type RateLimitConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	LimitConfig `mapstructure:",squash"`
+	// Routes overrides the budget of single routes, keyed on the pattern
+	// below the API version, e.g. /analytics/events. A key ending in /*,
+	// e.g. /auth/*, gives every route below it one shared budget; an exact
+	// route wins over such a group, and a longer group over a shorter one.
+	Routes map[string]LimitConfig `mapstructure:"routes"`
+	// Users limits each signed-in user on top of the per-IP budget; it is
+	// the only budget counted per account
+	Users UserRateLimitConfig `mapstructure:"users"`
+}
+
+// UserRateLimitConfig limits the requests of each signed-in user, whatever
+// IP they come from, with a token bucket holding Limit requests that refills
+// over Window. The budget depends on the user's role.
+type UserRateLimitConfig struct {
+	Enabled bool        `mapstructure:"enabled"`
+	Default LimitConfig `mapstructure:"default"` // roles not listed in Roles
+	// Roles is keyed on the lower-cased role, e.g. customer; a limit of
+	// zero leaves the role unlimited
+	Roles map[string]LimitConfig `mapstructure:"roles"`
+}
+
 // LoggerConfig holds logging configuration
 type LoggerConfig struct {
 	Level      string `mapstructure:"level"`  // debug, info, warn, error
@@ -114,15 +192,254 @@ type TracerConfig struct {
 	SampleRate  float64 `mapstructure:"sample_rate"`
 }
 
+// MetricsConfig holds OpenTelemetry metrics configuration. Metrics are
+// served for Prometheus to scrape at Path, without authentication.
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
 // EmailConfig holds email configuration for password reset etc.
 type EmailConfig struct {
-	SMTPHost     string `mapstructure:"smtp_host"`
-	SMTPPort     int    `mapstructure:"smtp_port"`
-	SMTPUser     string `mapstructure:"smtp_user"`
-	SMTPPassword string `mapstructure:"smtp_password"`
-	FromAddress  string `mapstructure:"from_address"`
-	FromName     string `mapstructure:"from_name"`
-	FrontendURL  string `mapstructure:"frontend_url"`
+	SMTPHost     string        `mapstructure:"smtp_host"`
+	SMTPPort     int           `mapstructure:"smtp_port"`
+	SMTPUser     string        `mapstructure:"smtp_user"`
+	SMTPPassword string        `mapstructure:"smtp_password"`
+	FromAddress  string        `mapstructure:"from_address"`
+	FromName     string        `mapstructure:"from_name"`
+	FrontendURL  string        `mapstructure:"frontend_url"`
+	Timeout      time.Duration `mapstructure:"timeout"` // for delivering one email
+}
+
+// StorageConfig holds the S3-compatible bucket uploaded files, such as
+// avatars, are stored in. Uploads are off without a bucket.
+type StorageConfig struct {
+	Endpoint        string        `mapstructure:"endpoint"` // empty for AWS S3 in Region
+	Bucket          string        `mapstructure:"bucket"`
+	Region          string        `mapstructure:"region"`
+	AccessKeyID     string        `mapstructure:"access_key_id"`
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	ForcePathStyle  bool          `mapstructure:"force_path_style"` // for MinIO and other S3-compatible stores
+	PublicBaseURL   string        `mapstructure:"public_base_url"`  // e.g. a CDN; the bucket URL when empty
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// MovieConfig holds movie catalogue settings
+type MovieConfig struct {
+	// ListCacheTTL is how long a page of a movie listing is cached in Redis;
+	// every write to a movie drops all cached pages
+	ListCacheTTL time.Duration `mapstructure:"list_cache_ttl"`
+	// PopularityInterval is how often popularity scores are recomputed, from
+	// the bookings made over the last PopularityWindow
+	PopularityInterval time.Duration `mapstructure:"popularity_interval"`
+	PopularityWindow   time.Duration `mapstructure:"popularity_window"`
+}
+
+// BookingConfig holds seat hold and checkout configuration
+type BookingConfig struct {
+	HoldTTL            time.Duration `mapstructure:"hold_ttl"`
+	HoldMaxLifetime    time.Duration `mapstructure:"hold_max_lifetime"` // extended holds never last longer than this
+	MaxSeatsPerHold    int           `mapstructure:"max_seats_per_hold"`
+	SplitShareMargin   time.Duration `mapstructure:"split_share_margin"` // shares expire this long before the hold
+	SplitSweepInterval time.Duration `mapstructure:"split_sweep_interval"`
+	// RecoveryDelay is how long after a hold expires the recovery email goes out
+	RecoveryDelay         time.Duration `mapstructure:"recovery_delay"`
+	RecoverySweepInterval time.Duration `mapstructure:"recovery_sweep_interval"`
+	// HeldCountSweepInterval is how often expired holds leave the per-showtime held counts
+	HeldCountSweepInterval time.Duration `mapstructure:"held_count_sweep_interval"`
+	// BookedCacheTTL is how long a showtime's booked seats are cached for the
+	// seat availability probe
+	BookedCacheTTL time.Duration `mapstructure:"booked_cache_ttl"`
+	// SeatLayoutCacheTTL is how long a screen's seats are cached for seat
+	// maps; seat changes made through the API drop the cached layout
+	SeatLayoutCacheTTL time.Duration `mapstructure:"seat_layout_cache_ttl"`
+	// PendingSweepInterval is how often unpaid bookings past their payment
+	// deadline are expired and their seats released
+	PendingSweepInterval time.Duration `mapstructure:"pending_sweep_interval"`
+	// CancellationWindows is the refund policy for cancelling paid bookings.
+	// The window with the longest Before that the cancellation is still
+	// ahead of applies; closer to the start than every window, paid
+	// bookings can no longer be cancelled.
+	CancellationWindows []CancellationWindow `mapstructure:"cancellation_windows"`
+}
+
+// CancellationWindow refunds RefundPercent of a paid booking cancelled at
+// least Before the showtime starts
+type CancellationWindow struct {
+	Name          string        `mapstructure:"name"`
+	Before        time.Duration `mapstructure:"before"`
+	RefundPercent float64       `mapstructure:"refund_percent"` // 0..100
+}
+
+// PricingConfig holds seat pricing settings
+type PricingConfig struct {
+	RuleCacheTTL time.Duration `mapstructure:"rule_cache_ttl"` // how long a cinema's pricing rules are cached in Redis
+	// SeatTypeMultipliers scale the showtime base price by seat type, per
+	// seat type; unlisted types keep their built-in multiplier
+	SeatTypeMultipliers map[string]float64 `mapstructure:"seat_type_multipliers"`
+}
+
+// LoyaltyConfig holds loyalty points configuration
+type LoyaltyConfig struct {
+	InactivityExpiry    time.Duration `mapstructure:"inactivity_expiry"`     // a balance expires this long after the account was last used
+	ExpirySweepInterval time.Duration `mapstructure:"expiry_sweep_interval"` // how often inactive balances are expired
+}
+
+// TicketConfig holds e-ticket and check-in configuration. The QR code on
+// a ticket carries the booking reference signed with SigningSecret; staff
+// can check a ticket in from CheckInOpensBefore the show starts until
+// CheckInClosesAfter it started.
+type TicketConfig struct {
+	SigningSecret      string        `mapstructure:"signing_secret"`
+	CheckInOpensBefore time.Duration `mapstructure:"check_in_opens_before"`
+	CheckInClosesAfter time.Duration `mapstructure:"check_in_closes_after"`
+}
+
+// GuestLookupConfig holds guest booking lookup configuration. Failed
+// lookups are counted per booking reference and per client IP; past
+// FreeAttempts within FailureWindow each failure blocks further lookups
+// for twice as long as the previous one, from BaseDelay up to MaxDelay.
+type GuestLookupConfig struct {
+	VerifyEmail     bool          `mapstructure:"verify_email"` // require a code emailed to the guest before showing the booking
+	CodeTTL         time.Duration `mapstructure:"code_ttl"`
+	MaxCodeAttempts int           `mapstructure:"max_code_attempts"`
+	FailureWindow   time.Duration `mapstructure:"failure_window"`
+	FreeAttempts    int           `mapstructure:"free_attempts"`
+	BaseDelay       time.Duration `mapstructure:"base_delay"`
+	MaxDelay        time.Duration `mapstructure:"max_delay"`
+}
+
+// AvailabilityConfig holds the thresholds of the showtime availability badge.
+// A showtime is LIMITED once either threshold is reached.
+type AvailabilityConfig struct {
+	LimitedRatio float64 `mapstructure:"limited_ratio"` // fraction of capacity still available
+	LimitedSeats int     `mapstructure:"limited_seats"` // seats still available
+	// CacheTTL is how long a movie's showtime listing is cached in Redis and
+	// by clients; seats booked meanwhile show up once it expires
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// HomeConfig holds homepage configuration
+type HomeConfig struct {
+	CacheTTL time.Duration `mapstructure:"cache_ttl"` // how long the homepage is cached, in process and by clients
+	RowSize  int           `mapstructure:"row_size"`  // movies in the now-showing and coming-soon rows
+}
+
+// ReportsConfig holds end-of-day report settings
+type ReportsConfig struct {
+	DailyInterval time.Duration `mapstructure:"daily_interval"` // how often cinemas are checked for a due report
+	CloseGrace    time.Duration `mapstructure:"close_grace"`    // wait after closing time before reporting the day
+	// Showtime demand rollup for scheduling
+	DemandInterval time.Duration `mapstructure:"demand_interval"` // how often confirmed bookings are rolled up
+	DemandLag      time.Duration `mapstructure:"demand_lag"`      // bookings confirmed this recently wait for the next run
+	// Booking analytics for admins and managers
+	AnalyticsCacheTTL time.Duration `mapstructure:"analytics_cache_ttl"` // how long a report is cached per query
+	StatsCacheTTL     time.Duration `mapstructure:"stats_cache_ttl"`     // how long booking stats are cached per query
+}
+
+// AnalyticsConfig holds booking funnel analytics settings
+type AnalyticsConfig struct {
+	Sink              string             `mapstructure:"sink"`        // "file" or "noop"
+	FilePath          string             `mapstructure:"file_path"`   // JSON-lines output of the file sink
+	BufferSize        int                `mapstructure:"buffer_size"` // bytes buffered before a write to the file
+	FlushInterval     time.Duration      `mapstructure:"flush_interval"`
+	DefaultSampleRate float64            `mapstructure:"default_sample_rate"` // fraction of events kept, 0..1
+	SampleRates       map[string]float64 `mapstructure:"sample_rates"`        // per event name
+	IngestRateLimit   int                `mapstructure:"ingest_rate_limit"`   // client event requests per minute per IP
+}
+
+// APIConfig holds public API versioning and documentation configuration
+type APIConfig struct {
+	Deprecations []RouteDeprecation `mapstructure:"deprecations"`
+	Docs         bool               `mapstructure:"docs"` // serve the generated spec and Swagger UI under /api/v1/docs
+}
+
+// IdempotencyConfig holds the replay of requests retried with an
+// X-Idempotency-Key header. A key is held for InFlightTTL while its first
+// request runs, so a crashed instance cannot block the key for long, and
+// the successful response is replayed for TTL.
+type IdempotencyConfig struct {
+	TTL         time.Duration `mapstructure:"ttl"`
+	InFlightTTL time.Duration `mapstructure:"in_flight_ttl"`
+}
+
+// RouteDeprecation marks a versioned route as slated for removal
+type RouteDeprecation struct {
+	Method       string `mapstructure:"method"`
+	Path         string `mapstructure:"path"`          // gin route pattern, e.g. /api/v1/movies/:id
+	DeprecatedAt string `mapstructure:"deprecated_at"` // YYYY-MM-DD
+	Sunset       string `mapstructure:"sunset"`        // YYYY-MM-DD
+	Link         string `mapstructure:"link"`          // migration guide
+}
+
+// EventsConfig holds in-process event bus configuration
+type EventsConfig struct {
+	Lanes          int           `mapstructure:"lanes"` // events for one aggregate always use the same lane
+	QueueSize      int           `mapstructure:"queue_size"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	RetryBackoff   time.Duration `mapstructure:"retry_backoff"`
+	PublishTimeout time.Duration `mapstructure:"publish_timeout"`
+
+	// Events written to the outbox are published by a background job
+	OutboxInterval    time.Duration `mapstructure:"outbox_interval"`
+	OutboxBatchSize   int           `mapstructure:"outbox_batch_size"`
+	OutboxMaxAttempts int           `mapstructure:"outbox_max_attempts"` // after which an event is left for inspection
+	OutboxRetention   time.Duration `mapstructure:"outbox_retention"`    // how long published events are kept
+}
+
+// ShadowConfig holds shadow-read verification settings. Sampling can be
+// changed at runtime through the admin API.
+type ShadowConfig struct {
+	Enabled     bool            `mapstructure:"enabled"`
+	SampleRate  float64         `mapstructure:"sample_rate"` // fraction of reads also run on the candidate
+	Comparisons map[string]bool `mapstructure:"comparisons"` // per read path; unlisted paths are on
+	Timeout     time.Duration   `mapstructure:"timeout"`
+	MaxInFlight int             `mapstructure:"max_in_flight"`
+}
+
+// PaymentConfig holds payment gateway webhook and reconciliation
+// configuration
+type PaymentConfig struct {
+	Provider             string        `mapstructure:"provider"`
+	WebhookSecret        string        `mapstructure:"webhook_secret"`      // HMAC-SHA256 key for webhook signatures
+	WebhookAlertAfter    time.Duration `mapstructure:"webhook_alert_after"` // unprocessed events older than this alert admins
+	WebhookSweepInterval time.Duration `mapstructure:"webhook_sweep_interval"`
+	GatewayURL           string        `mapstructure:"gateway_url"` // payment status API; reconciliation is off when empty
+	GatewayAPIKey        string        `mapstructure:"gateway_api_key"`
+	GatewayTimeout       time.Duration `mapstructure:"gateway_timeout"`
+	ReconcileAfter       time.Duration `mapstructure:"reconcile_after"`   // pending payments older than this are polled
+	ReconcileBackoff     time.Duration `mapstructure:"reconcile_backoff"` // wait before the second poll, doubled after each
+	ReconcileMaxAttempts int           `mapstructure:"reconcile_max_attempts"`
+	ReconcileInterval    time.Duration `mapstructure:"reconcile_interval"`
+	StripeSecretKey      string        `mapstructure:"stripe_secret_key"` // booking payments are not started when empty
+	StripeURL            string        `mapstructure:"stripe_url"`
+	Currency             string        `mapstructure:"currency"`
+	CheckoutSuccessURL   string        `mapstructure:"checkout_success_url"` // {BOOKING_REFERENCE} is filled in
+	CheckoutCancelURL    string        `mapstructure:"checkout_cancel_url"`
+}
+
+// JobsConfig holds background job scheduling settings
+type JobsConfig struct {
+	Instance string  `mapstructure:"instance"` // names this instance in job history; defaults to the hostname
+	Jitter   float64 `mapstructure:"jitter"`   // fraction of a job's interval added at random to each wait
+}
+
+// WarmupConfig holds cache priming settings for startup
+type WarmupConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`     // off skips warmup, e.g. for local development
+	Budget      time.Duration `mapstructure:"budget"`      // readiness waits no longer than this for warmup
+	Horizon     time.Duration `mapstructure:"horizon"`     // showtimes starting within this are preloaded
+	Concurrency int           `mapstructure:"concurrency"` // showtimes or screens loaded at once per item
+}
+
+// TMDBConfig holds TMDB API settings for movie metadata lookups
+type TMDBConfig struct {
+	APIKey       string        `mapstructure:"api_key"` // lookups are off when empty
+	BaseURL      string        `mapstructure:"base_url"`
+	ImageBaseURL string        `mapstructure:"image_base_url"`
+	Timeout      time.Duration `mapstructure:"timeout"` // per attempt; requests are tried up to three times
+	Backoff      time.Duration `mapstructure:"backoff"` // wait before the second attempt, doubled after each
+	CastLimit    int           `mapstructure:"cast_limit"`
 }
 
 // Load reads configuration from file and environment variables
@@ -180,6 +497,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", "15s")
 	v.SetDefault("server.write_timeout", "15s")
 	v.SetDefault("server.shutdown_timeout", "30s")
+	v.SetDefault("server.request_timeout", "10s")
+	v.SetDefault("server.route_timeouts", map[string]string{
+		"/bookings/confirm":     "14s",
+		"/bookings/:id/payment": "14s",
+	})
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -191,6 +513,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_open_conns", 25)
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.connect_attempts", 6)
+	v.SetDefault("database.connect_max_wait", "10s")
+	v.SetDefault("database.slow_query_threshold", "200ms")
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -200,6 +525,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("redis.pool_size", 10)
 	v.SetDefault("redis.min_idle_conns", 5)
 	v.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.connect_attempts", 4)
+	v.SetDefault("redis.connect_max_wait", "5s")
 
 	// JWT defaults
 	v.SetDefault("jwt.access_secret", "your-super-secret-access-key-change-in-production")
@@ -209,15 +536,32 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jwt.reset_token_expiry", "1h")
 	v.SetDefault("jwt.verify_token_expiry", "24h")
 	v.SetDefault("jwt.issuer", "cinemaos")
+	v.SetDefault("jwt.keep_session_on_password_change", true)
+	v.SetDefault("jwt.google_client_id", "")
+	v.SetDefault("jwt.google_certs_url", "https://www.googleapis.com/oauth2/v3/certs")
+	v.SetDefault("jwt.google_timeout", "5s")
 
 	// CORS defaults
 	v.SetDefault("cors.allow_origins", []string{"*"})
 	v.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
-	v.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"})
-	v.SetDefault("cors.expose_headers", []string{"X-Request-ID"})
+	v.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-API-Version", "X-Anonymous-ID", "X-Idempotency-Key"})
+	v.SetDefault("cors.expose_headers", []string{"X-Request-ID", "X-API-Version", "Deprecation", "Sunset", "Link", "Idempotent-Replayed"})
 	v.SetDefault("cors.allow_credentials", true)
 	v.SetDefault("cors.max_age", 86400)
 
+	// Rate limit defaults
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.limit", 100)
+	v.SetDefault("rate_limit.window", "1m")
+	v.SetDefault("rate_limit.users.enabled", true)
+	v.SetDefault("rate_limit.users.default.limit", 10)
+	v.SetDefault("rate_limit.users.default.window", "1m")
+	for _, role := range []string{"customer", "staff", "manager"} {
+		v.SetDefault("rate_limit.users.roles."+role+".limit", 100)
+		v.SetDefault("rate_limit.users.roles."+role+".window", "1m")
+	}
+	v.SetDefault("rate_limit.users.roles.admin.limit", 0)
+
 	// Logger defaults
 	v.SetDefault("logger.level", "debug")
 	v.SetDefault("logger.format", "console")
@@ -231,11 +575,152 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("tracer.insecure", true)
 	v.SetDefault("tracer.sample_rate", 1.0)
 
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+
 	// Email defaults
 	v.SetDefault("email.smtp_host", "smtp.gmail.com")
 	v.SetDefault("email.smtp_port", 587)
 	v.SetDefault("email.from_name", "CinemaOS")
 	v.SetDefault("email.frontend_url", "http://localhost:3000")
+	v.SetDefault("email.timeout", "30s")
+
+	// Booking defaults
+	v.SetDefault("booking.hold_ttl", "15m")
+	v.SetDefault("booking.hold_max_lifetime", "30m")
+	v.SetDefault("booking.max_seats_per_hold", 10)
+	v.SetDefault("booking.split_share_margin", "3m")
+	v.SetDefault("booking.split_sweep_interval", "30s")
+	v.SetDefault("booking.recovery_delay", "10m")
+	v.SetDefault("booking.recovery_sweep_interval", "1m")
+	v.SetDefault("booking.held_count_sweep_interval", "10s")
+	v.SetDefault("booking.booked_cache_ttl", "30s")
+	v.SetDefault("booking.seat_layout_cache_ttl", "24h")
+	v.SetDefault("booking.pending_sweep_interval", "1m")
+	v.SetDefault("booking.cancellation_windows", []map[string]any{
+		{"name": "full_refund", "before": "24h", "refund_percent": 100},
+		{"name": "partial_refund", "before": "2h", "refund_percent": 50},
+	})
+
+	// Storage defaults
+	v.SetDefault("storage.endpoint", "")
+	v.SetDefault("storage.bucket", "")
+	v.SetDefault("storage.region", "us-east-1")
+	v.SetDefault("storage.force_path_style", false)
+	v.SetDefault("storage.public_base_url", "")
+	v.SetDefault("storage.timeout", "30s")
+
+	// Movie defaults
+	v.SetDefault("movie.list_cache_ttl", "2m")
+	v.SetDefault("movie.popularity_interval", "1h")
+	v.SetDefault("movie.popularity_window", "168h")
+
+	// Pricing defaults
+	v.SetDefault("pricing.rule_cache_ttl", "5m")
+
+	// Loyalty defaults
+	v.SetDefault("loyalty.inactivity_expiry", "8760h")
+	v.SetDefault("loyalty.expiry_sweep_interval", "24h")
+
+	// Ticket defaults
+	v.SetDefault("ticket.signing_secret", "your-super-secret-ticket-key-change-in-production")
+	v.SetDefault("ticket.check_in_opens_before", "1h")
+	v.SetDefault("ticket.check_in_closes_after", "30m")
+
+	// Guest booking lookup defaults
+	v.SetDefault("guest_lookup.verify_email", false)
+	v.SetDefault("guest_lookup.code_ttl", "10m")
+	v.SetDefault("guest_lookup.max_code_attempts", 5)
+	v.SetDefault("guest_lookup.failure_window", "1h")
+	v.SetDefault("guest_lookup.free_attempts", 5)
+	v.SetDefault("guest_lookup.base_delay", "2s")
+	v.SetDefault("guest_lookup.max_delay", "15m")
+
+	// Availability badge defaults
+	v.SetDefault("availability.limited_ratio", 0.2)
+	v.SetDefault("availability.limited_seats", 10)
+	v.SetDefault("availability.cache_ttl", "30s")
+
+	// Homepage defaults
+	v.SetDefault("home.cache_ttl", "1m")
+	v.SetDefault("home.row_size", 10)
+
+	// Reports defaults
+	v.SetDefault("reports.daily_interval", "15m")
+	v.SetDefault("reports.close_grace", "30m")
+	v.SetDefault("reports.demand_interval", "24h")
+	v.SetDefault("reports.demand_lag", "5m")
+	v.SetDefault("reports.analytics_cache_ttl", "10m")
+	v.SetDefault("reports.stats_cache_ttl", "5m")
+
+	// Analytics defaults
+	v.SetDefault("analytics.sink", "noop")
+	v.SetDefault("analytics.file_path", "./data/analytics/events.jsonl")
+	v.SetDefault("analytics.buffer_size", 65536)
+	v.SetDefault("analytics.flush_interval", "5s")
+	v.SetDefault("analytics.default_sample_rate", 1.0)
+	v.SetDefault("analytics.ingest_rate_limit", 30)
+
+	// API defaults
+	v.SetDefault("api.docs", true)
+
+	// Event bus defaults
+	v.SetDefault("events.lanes", 4)
+	v.SetDefault("events.queue_size", 256)
+	v.SetDefault("events.max_attempts", 3)
+	v.SetDefault("events.retry_backoff", "200ms")
+	v.SetDefault("events.publish_timeout", "1s")
+	v.SetDefault("events.outbox_interval", "2s")
+	v.SetDefault("events.outbox_batch_size", 100)
+	v.SetDefault("events.outbox_max_attempts", 10)
+	v.SetDefault("events.outbox_retention", "168h")
+
+	// Shadow read defaults
+	v.SetDefault("shadow.enabled", false)
+	v.SetDefault("shadow.sample_rate", 0.01)
+	v.SetDefault("shadow.timeout", "2s")
+	v.SetDefault("shadow.max_in_flight", 16)
+
+	// Idempotency defaults
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.in_flight_ttl", "1m")
+
+	// Payment defaults
+	v.SetDefault("payment.provider", "gateway")
+	v.SetDefault("payment.webhook_secret", "")
+	v.SetDefault("payment.webhook_alert_after", "10m")
+	v.SetDefault("payment.webhook_sweep_interval", "1m")
+	v.SetDefault("payment.gateway_url", "")
+	v.SetDefault("payment.gateway_api_key", "")
+	v.SetDefault("payment.gateway_timeout", "10s")
+	v.SetDefault("payment.reconcile_after", "5m")
+	v.SetDefault("payment.reconcile_backoff", "1m")
+	v.SetDefault("payment.reconcile_max_attempts", 6)
+	v.SetDefault("payment.reconcile_interval", "1m")
+	v.SetDefault("payment.stripe_secret_key", "")
+	v.SetDefault("payment.stripe_url", "https://api.stripe.com")
+	v.SetDefault("payment.currency", "usd")
+	v.SetDefault("payment.checkout_success_url", "http://localhost:3000/bookings/{BOOKING_REFERENCE}?payment=success")
+	v.SetDefault("payment.checkout_cancel_url", "http://localhost:3000/bookings/{BOOKING_REFERENCE}?payment=cancelled")
+
+	// Job runner defaults
+	v.SetDefault("jobs.instance", "")
+	v.SetDefault("jobs.jitter", 0.1)
+
+	// Warmup defaults
+	v.SetDefault("warmup.enabled", true)
+	v.SetDefault("warmup.budget", "20s")
+	v.SetDefault("warmup.horizon", "6h")
+	v.SetDefault("warmup.concurrency", 8)
+
+	// TMDB defaults
+	v.SetDefault("tmdb.api_key", "")
+	v.SetDefault("tmdb.base_url", "https://api.themoviedb.org/3")
+	v.SetDefault("tmdb.image_base_url", "https://image.tmdb.org/t/p/original")
+	v.SetDefault("tmdb.timeout", "5s")
+	v.SetDefault("tmdb.backoff", "200ms")
+	v.SetDefault("tmdb.cast_limit", 10)
 }
 
 // IsDevelopment returns true if running in development mode
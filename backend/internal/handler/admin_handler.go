@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	adminanalyticsapp "cinemaos-backend/internal/app/adminanalytics"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/scheduler"
+	"cinemaos-backend/internal/pkg/shadow"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles operational admin endpoints
+type AdminHandler struct {
+	shadowReads *shadow.Reader
+	jobs        *scheduler.Runner
+	analytics   *adminanalyticsapp.Service
+	validator   *validator.Validator
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(
+	shadowReads *shadow.Reader,
+	jobs *scheduler.Runner,
+	analytics *adminanalyticsapp.Service,
+	validator *validator.Validator,
+) *AdminHandler {
+	return &AdminHandler{
+		shadowReads: shadowReads,
+		jobs:        jobs,
+		analytics:   analytics,
+		validator:   validator,
+	}
+}
+
+// ShadowReadsResponse represents the shadow-read configuration and counters
+type ShadowReadsResponse struct {
+	Enabled     bool                    `json:"enabled"`
+	SampleRate  float64                 `json:"sample_rate"`
+	TimeoutMs   int64                   `json:"timeout_ms"`
+	MaxInFlight int                     `json:"max_in_flight"`
+	Comparisons map[string]bool         `json:"comparisons"`
+	Stats       map[string]shadow.Stats `json:"stats"`
+}
+
+// UpdateShadowReadsRequest changes shadow-read sampling. Omitted fields keep
+// their current value; listed comparisons are switched on or off.
+type UpdateShadowReadsRequest struct {
+	Enabled     *bool           `json:"enabled"`
+	SampleRate  *float64        `json:"sample_rate" validate:"omitempty,min=0,max=1"`
+	TimeoutMs   *int64          `json:"timeout_ms" validate:"omitempty,min=1,max=60000"`
+	Comparisons map[string]bool `json:"comparisons"`
+}
+
+func (h *AdminHandler) shadowReadsResponse() ShadowReadsResponse {
+	cfg := h.shadowReads.Config()
+	return ShadowReadsResponse{
+		Enabled:     cfg.Enabled,
+		SampleRate:  cfg.SampleRate,
+		TimeoutMs:   cfg.Timeout.Milliseconds(),
+		MaxInFlight: cfg.MaxInFlight,
+		Comparisons: cfg.Comparisons,
+		Stats:       h.shadowReads.Stats(),
+	}
+}
+
+// GetShadowReads godoc
+// @Summary Get shadow-read settings
+// @Description Get shadow-read sampling settings and per-comparison mismatch counters
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=ShadowReadsResponse}
+// @Failure 403 {object} response.Response
+// @Router /admin/shadow-reads [get]
+func (h *AdminHandler) GetShadowReads(c *gin.Context) {
+	response.Success(c, h.shadowReadsResponse())
+}
+
+// UpdateShadowReads godoc
+// @Summary Update shadow-read settings
+// @Description Change shadow-read sampling at runtime; the change is not persisted across restarts
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateShadowReadsRequest true "Shadow-read settings"
+// @Success 200 {object} response.Response{data=ShadowReadsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/shadow-reads [put]
+func (h *AdminHandler) UpdateShadowReads(c *gin.Context) {
+	var req UpdateShadowReadsRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	cfg := h.shadowReads.Config()
+	if req.Enabled != nil {
+		cfg.Enabled = *req.Enabled
+	}
+	if req.SampleRate != nil {
+		cfg.SampleRate = *req.SampleRate
+	}
+	if req.TimeoutMs != nil {
+		cfg.Timeout = time.Duration(*req.TimeoutMs) * time.Millisecond
+	}
+	for name, on := range req.Comparisons {
+		cfg.Comparisons[name] = on
+	}
+	h.shadowReads.Configure(cfg)
+
+	response.SuccessWithMessage(c, "Shadow-read settings updated", h.shadowReadsResponse())
+}
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description Get the health and last runs of every scheduled background job across all instances
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]scheduler.JobStatus}
+// @Failure 403 {object} response.Response
+// @Router /admin/jobs [get]
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	jobs, err := h.jobs.Status(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs)
+}
+
+// BookingStats godoc
+// @Summary Get booking stats
+// @Description Get total, confirmed and cancelled bookings, tickets sold, gross revenue, refunds, revenue less refunds and the average ticket price, with the same by day and the 10 movies with the most revenue. Revenue counts paid and refunded bookings less their refunds. Managers must pass one of their cinemas. Stats are cached for a few minutes.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param params query adminanalyticsapp.BookingStatsParams true "Filters"
+// @Success 200 {object} response.Response{data=adminanalyticsapp.BookingStatsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/stats/bookings [get]
+func (h *AdminHandler) BookingStats(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var params adminanalyticsapp.BookingStatsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.analytics.BookingStats(c.Request.Context(), userID, middleware.GetUserRole(c), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// BookingAnalytics godoc
+// @Summary Get booking analytics
+// @Description Get bookings, revenue, cancellations and occupancy by day, week or month, with the top movies and revenue by screen type. Managers must pass one of their cinemas. Send Accept: text/csv to export one section as CSV. Reports are cached for a few minutes.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param params query adminanalyticsapp.BookingAnalyticsParams true "Filters"
+// @Success 200 {object} response.Response{data=adminanalyticsapp.BookingAnalyticsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/analytics/bookings [get]
+func (h *AdminHandler) BookingAnalytics(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var params adminanalyticsapp.BookingAnalyticsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.analytics.BookingAnalytics(c.Request.Context(), userID, middleware.GetUserRole(c), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, "text/csv") != "text/csv" {
+		response.Success(c, result)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="booking-`+params.Section+`.csv"`)
+	c.Status(http.StatusOK)
+	if err := adminanalyticsapp.WriteCSV(c.Writer, result, params.Section); err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxAnalyticsBody bounds an event batch request
+	maxAnalyticsBody = 64 * 1024
+	// maxEventAge is how far back a client timestamp is trusted; older or
+	// future timestamps are replaced with the time of receipt
+	maxEventAge = 24 * time.Hour
+	// maxPropertyKey bounds property names
+	maxPropertyKey = 40
+)
+
+// AnalyticsHandler ingests booking funnel events sent by clients
+type AnalyticsHandler struct {
+	tracker   *analytics.Tracker
+	validator *validator.Validator
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(tracker *analytics.Tracker, validator *validator.Validator) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		tracker:   tracker,
+		validator: validator,
+	}
+}
+
+// TrackEventsRequest is a batch of client funnel events
+type TrackEventsRequest struct {
+	Events []ClientEventRequest `json:"events" validate:"required,min=1,max=20,dive"`
+}
+
+// ClientEventRequest is a funnel event observed by the client. The visitor
+// is identified by the X-Anonymous-ID header and, when signed in, the
+// access token; events carry no other identity.
+type ClientEventRequest struct {
+	Name       string         `json:"name" validate:"required,oneof=showtime_viewed seat_selected seat_deselected checkout_started checkout_abandoned"`
+	ShowtimeID *uuid.UUID     `json:"showtime_id"`
+	MovieID    *uuid.UUID     `json:"movie_id"`
+	Properties map[string]any `json:"properties" validate:"omitempty,max=20"`
+	Timestamp  *time.Time     `json:"timestamp"`
+}
+
+// TrackEventsResponse reports how many events were accepted
+type TrackEventsResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// Track godoc
+// @Summary Record client funnel events
+// @Description Record a batch of booking funnel events observed by the client. Properties must be scalar values; string values that look like email addresses are dropped. Events are sampled and written asynchronously.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param X-Anonymous-ID header string false "Client-generated visitor UUID"
+// @Param request body TrackEventsRequest true "Events"
+// @Success 200 {object} response.Response{data=TrackEventsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /analytics/events [post]
+func (h *AnalyticsHandler) Track(c *gin.Context) {
+	var req TrackEventsRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, maxAnalyticsBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+	for i, event := range req.Events {
+		if err := checkProperties(event.Properties); err != nil {
+			response.BadRequest(c, fmt.Sprintf("events[%d]: %s", i, err))
+			return
+		}
+	}
+
+	var userID *uuid.UUID
+	if id, ok := middleware.GetUserID(c); ok {
+		userID = &id
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now().UTC()
+	for _, event := range req.Events {
+		timestamp := now
+		if event.Timestamp != nil && event.Timestamp.Before(now) && now.Sub(*event.Timestamp) <= maxEventAge {
+			timestamp = event.Timestamp.UTC()
+		}
+		h.tracker.Track(ctx, analytics.Event{
+			Name:       event.Name,
+			UserID:     userID,
+			ShowtimeID: event.ShowtimeID,
+			MovieID:    event.MovieID,
+			Properties: event.Properties,
+			Timestamp:  timestamp,
+		})
+	}
+
+	response.Success(c, TrackEventsResponse{Accepted: len(req.Events)})
+}
+
+// checkProperties accepts short keys with scalar values only
+func checkProperties(props map[string]any) error {
+	for key, value := range props {
+		if key == "" || len(key) > maxPropertyKey {
+			return fmt.Errorf("property names must be 1-%d characters", maxPropertyKey)
+		}
+		switch value.(type) {
+		case string, bool, float64:
+		default:
+			return fmt.Errorf("property %q must be a string, number or boolean", key)
+		}
+	}
+	return nil
+}
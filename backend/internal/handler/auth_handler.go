@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"cinemaos-backend/internal/app/auth"
@@ -95,9 +97,42 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response.SuccessWithMessage(c, "Login successful", result)
 }
 
+// LoginWithGoogle godoc
+// @Summary Login with Google
+// @Description Sign in with the ID token from Google Sign-In. A new Google account is registered as a customer; an existing user with the same verified email is linked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.GoogleLoginRequest true "Google ID token"
+// @Success 200 {object} response.Response{data=auth.AuthResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /auth/google [post]
+func (h *AuthHandler) LoginWithGoogle(c *gin.Context) {
+	var req auth.GoogleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.authService.LoginWithGoogle(c.Request.Context(), req.IDToken)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login successful", result)
+}
+
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get a new access token using refresh token
+// @Description Exchange a refresh token for a new access token and a new refresh token. The presented token is revoked; presenting it again revokes every session of the user.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -153,6 +188,68 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	response.SuccessWithMessage(c, "Logged out successfully", nil)
 }
 
+// LogoutOthers godoc
+// @Summary Logout other sessions
+// @Description Revoke every refresh token of the user except the current session's, identified by the refresh token in the body or else by the access token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body auth.LogoutOthersRequest false "Refresh token of the current session"
+// @Success 200 {object} response.Response{data=auth.LogoutOthersResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/logout-others [post]
+func (h *AuthHandler) LogoutOthers(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	// The body is optional
+	var req auth.LogoutOthersRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+	res, err := h.authService.LogoutOthers(c.Request.Context(), userID, sessionID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ListSessions godoc
+// @Summary List sessions
+// @Description List the user's unexpired sessions, newest first, marking the current one and those that were logged out
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]auth.SessionResponse}
+// @Failure 401 {object} response.Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+	res, err := h.authService.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
 // ForgotPassword godoc
 // @Summary Request password reset
 // @Description Send password reset email
@@ -212,6 +309,62 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	response.SuccessWithMessage(c, "Password has been reset successfully", nil)
 }
 
+// SendVerificationEmail godoc
+// @Summary Send verification email
+// @Description Email the current user a new link to verify their email address
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /auth/verify-email/send [post]
+func (h *AuthHandler) SendVerificationEmail(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	if err := h.authService.SendVerificationEmail(c.Request.Context(), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Verification email sent", nil)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Verify an email address using the token from the verification email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req auth.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Email has been verified", nil)
+}
+
 // ChangePassword godoc
 // @Summary Change password
 // @Description Change password for authenticated user
@@ -242,7 +395,8 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.ChangePassword(c.Request.Context(), userID, req); err != nil {
+	sessionID, _ := middleware.GetSessionID(c)
+	if err := h.authService.ChangePassword(c.Request.Context(), userID, sessionID, req); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -313,3 +467,59 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "Profile updated successfully", user)
 }
+
+// avatarFormOverhead is allowed on top of the avatar for the rest of the
+// multipart form
+const avatarFormOverhead = 1 << 20
+
+// UpdateAvatar godoc
+// @Summary Upload avatar
+// @Description Replace the authenticated user's avatar with a JPEG or PNG image of at most 5MB, sent as the "avatar" field of a multipart form
+// @Tags auth
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "JPEG or PNG image, at most 5MB"
+// @Success 200 {object} response.Response{data=auth.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /auth/me/avatar [patch]
+func (h *AuthHandler) UpdateAvatar(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, auth.MaxAvatarSize+avatarFormOverhead)
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.BadRequest(c, "Avatar must be at most 5MB")
+			return
+		}
+		response.BadRequest(c, "An avatar image is required in the avatar field")
+		return
+	}
+	if header.Size > auth.MaxAvatarSize {
+		response.BadRequest(c, "Avatar must be at most 5MB")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		response.BadRequest(c, "Invalid avatar file")
+		return
+	}
+	defer file.Close()
+
+	user, err := h.authService.UpdateAvatar(c.Request.Context(), userID, file)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Avatar updated successfully", user)
+}
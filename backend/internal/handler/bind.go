@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindStrictJSON decodes an admin request body, rejecting unknown fields so
+// misspelled fields fail loudly instead of being silently ignored. It writes
+// the error response and returns false when the body is rejected. Customer
+// endpoints keep c.ShouldBindJSON so older and newer clients stay
+// compatible.
+func bindStrictJSON(c *gin.Context, req any) bool {
+	unknown, err := validator.DecodeStrict(c.Request.Body, req)
+	if unknown != nil {
+		response.ValidationError(c, unknown)
+		return false
+	}
+	if err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return false
+	}
+	return true
+}
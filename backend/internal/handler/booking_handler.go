@@ -0,0 +1,702 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/app/confirmation"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BookingHandler handles seat hold and booking HTTP requests
+type BookingHandler struct {
+	service       *booking.Service
+	confirmations *confirmation.Service
+	validator     *validator.Validator
+}
+
+// NewBookingHandler creates a new booking handler
+func NewBookingHandler(service *booking.Service, confirmations *confirmation.Service, validator *validator.Validator) *BookingHandler {
+	return &BookingHandler{
+		service:       service,
+		confirmations: confirmations,
+		validator:     validator,
+	}
+}
+
+// HoldSeats godoc
+// @Summary Hold seats
+// @Description Temporarily hold seats for a showtime
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body booking.HoldSeatsRequest true "Seats to hold"
+// @Success 201 {object} response.Response{data=booking.HoldResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /bookings/hold [post]
+func (h *BookingHandler) HoldSeats(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req booking.HoldSeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.HoldSeats(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// ReleaseHold godoc
+// @Summary Release seat hold
+// @Description Give up a seat hold owned by the current user so its seats are available again. Releasing a hold that has already expired or been released succeeds without freeing any seats.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body booking.ReleaseHoldRequest true "Hold to release"
+// @Success 200 {object} response.Response{data=booking.ReleaseHoldResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /bookings/release [post]
+func (h *BookingHandler) ReleaseHold(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req booking.ReleaseHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.ReleaseHold(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ConfirmBooking godoc
+// @Summary Confirm booking
+// @Description Turn a seat hold into a booking pending payment, at the prices quoted by the hold. Loyalty points in redeem_points are taken off the seats at $0.01 each. A retry sent with the same X-Idempotency-Key gets the first successful response back instead of a second booking. When the payment cannot be started the booking is still returned, with payment_error instead of checkout_url, and the payment is retried with POST /bookings/{id}/payment.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Idempotency-Key header string false "Key to safely retry the request with"
+// @Param request body booking.ConfirmBookingRequest true "Hold to confirm"
+// @Success 201 {object} response.Response{data=booking.ConfirmBookingResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /bookings/confirm [post]
+func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req booking.ConfirmBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.ConfirmBooking(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// RetryPayment godoc
+// @Summary Retry booking payment
+// @Description Start the payment of a pending booking again when confirming it returned a payment_error instead of a checkout URL. Only possible while the booking has not expired and no checkout is open for it.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} response.Response{data=booking.ConfirmBookingResponse}
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /bookings/{id}/payment [post]
+func (h *BookingHandler) RetryPayment(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid booking ID")
+		return
+	}
+
+	res, err := h.service.RetryPayment(c.Request.Context(), userID, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ValidatePromoCode godoc
+// @Summary Validate a promo code
+// @Description Price a seat hold with a promo code before confirming it. An INVALID_PROMO_CODE error says why the code does not apply.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body booking.ValidatePromoCodeRequest true "Hold and promo code"
+// @Success 200 {object} response.Response{data=booking.PromoQuoteResponse}
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /bookings/promo-code [post]
+func (h *BookingHandler) ValidatePromoCode(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req booking.ValidatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.ValidatePromoCode(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ListUserBookings godoc
+// @Summary List my bookings
+// @Description List the current user's bookings, newest first, with whether each can be cancelled now and the refund percentage that would apply. status narrows them to upcoming (confirmed, show not yet started), past (completed, or confirmed and the show has started) or cancelled bookings, judged in each cinema's timezone; date_from and date_to bound the show date. Pages are linked by meta.next_cursor; passing page instead returns numbered pages with totals.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "upcoming, past or cancelled"
+// @Param date_from query string false "First show date (YYYY-MM-DD)"
+// @Param date_to query string false "Last show date (YYYY-MM-DD)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param page query int false "Page number, for numbered pages"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]booking.BookingSummaryResponse}
+// @Failure 400 {object} response.Response
+// @Router /bookings [get]
+func (h *BookingHandler) ListUserBookings(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var params booking.BookingHistoryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	if c.Query("page") != "" {
+		res, total, err := h.service.ListUserBookingsPage(c.Request.Context(), userID, params, pagination.Page, pagination.Limit)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.Paginated(c, res, pagination, total)
+		return
+	}
+
+	res, next, err := h.service.ListUserBookings(c.Request.Context(), userID, params, c.Query("cursor"), pagination.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.CursorPaginated(c, res, pagination.Limit, next)
+}
+
+// SearchBookings godoc
+// @Summary Search bookings
+// @Description Find bookings by reference, customer email (guest or account), showtime, status, payment status and booked_at range, with the movie, showtime, seats and payment of each. Managers and staff must pass one of their cinemas.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param params query booking.SearchBookingsParams false "Filters"
+// @Success 200 {object} response.Response{data=[]booking.StaffBookingResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/bookings [get]
+func (h *BookingHandler) SearchBookings(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var params booking.SearchBookingsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	res, total, err := h.service.SearchBookings(c.Request.Context(), userID, middleware.GetUserRole(c), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, res, pagination, total)
+}
+
+// CheckIn godoc
+// @Summary Check in a ticket
+// @Description Admit the holder of a scanned e-ticket, or of a booking reference typed in with the showtime being admitted. The booking must be for that showtime, when given, and confirmed and paid for a show today, within the check-in window around its start; it is then marked COMPLETED. A ticket scanned a second time fails with ALREADY_CHECKED_IN and the time of the first scan. Managers and staff can only check in tickets for their cinemas.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body booking.CheckInRequest true "Scanned QR code or booking reference"
+// @Success 200 {object} response.Response{data=booking.StaffBookingResponse}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /staff/check-in [post]
+// @Router /admin/bookings/check-in [post]
+func (h *BookingHandler) CheckIn(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req booking.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.CheckIn(c.Request.Context(), userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ListCheckIns godoc
+// @Summary List check-ins of a showtime
+// @Description List the bookings of a showtime checked in at the door, the latest first. Managers and staff can only list showtimes at their cinemas.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Showtime ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Response{data=[]booking.StaffBookingResponse}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /staff/showtimes/{id}/check-ins [get]
+func (h *BookingHandler) ListCheckIns(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	showtimeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	res, total, err := h.service.ListCheckIns(c.Request.Context(), userID, middleware.GetUserRole(c), showtimeID, pagination.Page, pagination.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, res, pagination, total)
+}
+
+// GetBooking godoc
+// @Summary Get booking
+// @Description Get a booking for its owner or an admin. Guests without an account pass the booking reference and email instead of signing in.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param booking_reference query string false "Booking reference, for guest bookings"
+// @Param email query string false "Guest email, for guest bookings"
+// @Success 200 {object} response.Response{data=booking.BookingDetailResponse}
+// @Failure 404 {object} response.Response
+// @Router /bookings/{id} [get]
+func (h *BookingHandler) GetBooking(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid booking ID")
+		return
+	}
+
+	viewer := bookingViewer(c, c.Query("booking_reference"), c.Query("email"))
+	res, err := h.service.GetBooking(c.Request.Context(), viewer, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// CancelBooking godoc
+// @Summary Cancel booking
+// @Description Cancel a booking and release its seats. Paid bookings are refunded under the cancellation policy and cannot be cancelled close to the showtime. Guests without an account pass the booking reference and email in the body.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body booking.CancelBookingRequest false "Guest proof and reason"
+// @Success 200 {object} response.Response{data=booking.CancelBookingResponse}
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /bookings/{id}/cancel [post]
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid booking ID")
+		return
+	}
+
+	// The body is optional
+	var req booking.CancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	viewer := bookingViewer(c, req.BookingReference, req.Email)
+	res, err := h.service.CancelBooking(c.Request.Context(), viewer, id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// bookingViewer identifies the caller of a booking endpoint, signed in or
+// a guest with the reference and email
+func bookingViewer(c *gin.Context, reference, email string) booking.Viewer {
+	viewer := booking.Viewer{
+		Admin:     middleware.IsAdmin(c),
+		Reference: reference,
+		Email:     email,
+	}
+	if userID, ok := middleware.GetUserID(c); ok {
+		viewer.UserID = &userID
+	}
+	return viewer
+}
+
+// GetHold godoc
+// @Summary Get seat hold
+// @Description Get a seat hold owned by the current user
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} response.Response{data=booking.HoldResponse}
+// @Failure 404 {object} response.Response
+// @Router /holds/{id} [get]
+func (h *BookingHandler) GetHold(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	res, err := h.service.GetHold(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ExtendHold godoc
+// @Summary Extend seat hold
+// @Description Extend a seat hold owned by the current user by the hold time, up to the maximum hold lifetime. A hold already at that lifetime fails with HOLD_EXTENSION_LIMIT_REACHED.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Hold ID"
+// @Param request body booking.ExtendHoldRequest false "Showtime the hold is for"
+// @Success 200 {object} response.Response{data=booking.HoldResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /holds/{id}/extend [post]
+func (h *BookingHandler) ExtendHold(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	// The body is optional
+	var req booking.ExtendHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	res, err := h.service.ExtendHold(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// GetSeatMap godoc
+// @Summary Get showtime seat map
+// @Description Get seats and their availability for a showtime. Before sales open only the sales state is returned unless a valid pre-sale code is supplied.
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Showtime ID"
+// @Param presale_code query string false "Pre-sale access code"
+// @Param access_code query string false "Access code of a private showtime"
+// @Success 200 {object} response.Response{data=booking.SeatMapResponse}
+// @Failure 404 {object} response.Response
+// @Router /showtimes/{id}/seats [get]
+func (h *BookingHandler) GetSeatMap(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	res, err := h.service.GetSeatMap(c.Request.Context(), id, c.Query("presale_code"), c.Query("access_code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// CheckSeats godoc
+// @Summary Probe seat availability
+// @Description Advisory check of whether seats are available, held or booked, to re-validate a selection just before holding it. Nothing is reserved. When some seats are gone a replacement selection of the same seat types may be suggested.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Showtime ID"
+// @Param request body booking.CheckSeatsRequest true "Seats to check"
+// @Success 200 {object} response.Response{data=booking.CheckSeatsResponse}
+// @Failure 400 {object} response.Response
+// @Router /showtimes/{id}/check-seats [post]
+func (h *BookingHandler) CheckSeats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	var req booking.CheckSeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.CheckSeats(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// GetSeatPlanSVG godoc
+// @Summary Get booking seat plan as SVG
+// @Description Render the auditorium with the booking's seats highlighted, for printed tickets
+// @Tags bookings
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {file} binary
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /bookings/{id}/seatmap.svg [get]
+func (h *BookingHandler) GetSeatPlanSVG(c *gin.Context) {
+	h.seatPlan(c, confirmation.FormatSVG, "image/svg+xml")
+}
+
+// GetSeatPlanPNG godoc
+// @Summary Get booking seat plan as PNG
+// @Description Render the auditorium with the booking's seats highlighted, for email clients without SVG support
+// @Tags bookings
+// @Produce image/png
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {file} binary
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /bookings/{id}/seatmap.png [get]
+func (h *BookingHandler) GetSeatPlanPNG(c *gin.Context) {
+	h.seatPlan(c, confirmation.FormatPNG, "image/png")
+}
+
+// GetTicket godoc
+// @Summary Download booking e-ticket
+// @Description Render the confirmed booking's e-ticket as a PDF with a QR code of the booking reference
+// @Tags bookings
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {file} binary
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /bookings/{id}/ticket [get]
+func (h *BookingHandler) GetTicket(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid booking ID")
+		return
+	}
+
+	pdf, reference, err := h.confirmations.Ticket(c.Request.Context(), userID, middleware.IsAdmin(c), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="ticket-%s.pdf"`, reference))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (h *BookingHandler) seatPlan(c *gin.Context, format, contentType string) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid booking ID")
+		return
+	}
+
+	img, err := h.confirmations.SeatPlan(c.Request.Context(), userID, middleware.IsAdmin(c), id, format)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, contentType, img)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	changelogapp "cinemaos-backend/internal/app/changelog"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ChangeLogHandler handles the admin change history of movies, showtimes and
+// bookings
+type ChangeLogHandler struct {
+	service   *changelogapp.Service
+	validator *validator.Validator
+}
+
+// NewChangeLogHandler creates a new change log handler
+func NewChangeLogHandler(service *changelogapp.Service, validator *validator.Validator) *ChangeLogHandler {
+	return &ChangeLogHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// ListMovieChanges godoc
+// @Summary List movie changes
+// @Description List the field-level changes made to a movie, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param params query changelogapp.ChangeListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]changelogapp.ChangeRecordResponse}
+// @Router /admin/movies/{id}/changes [get]
+func (h *ChangeLogHandler) ListMovieChanges(c *gin.Context) {
+	h.list(c, entity.ChangeEntityMovie, "Invalid movie ID")
+}
+
+// ListShowtimeChanges godoc
+// @Summary List showtime changes
+// @Description List the field-level changes made to a showtime, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Showtime ID"
+// @Param params query changelogapp.ChangeListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]changelogapp.ChangeRecordResponse}
+// @Router /admin/showtimes/{id}/changes [get]
+func (h *ChangeLogHandler) ListShowtimeChanges(c *gin.Context) {
+	h.list(c, entity.ChangeEntityShowtime, "Invalid showtime ID")
+}
+
+// ListBookingChanges godoc
+// @Summary List booking changes
+// @Description List the changes made to a booking by background jobs such as payment reconciliation, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param params query changelogapp.ChangeListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]changelogapp.ChangeRecordResponse}
+// @Router /admin/bookings/{id}/changes [get]
+func (h *ChangeLogHandler) ListBookingChanges(c *gin.Context) {
+	h.list(c, entity.ChangeEntityBooking, "Invalid booking ID")
+}
+
+func (h *ChangeLogHandler) list(c *gin.Context, entityType entity.ChangeEntityType, invalidID string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, invalidID)
+		return
+	}
+
+	var params changelogapp.ChangeListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.service.List(c.Request.Context(), entityType, id, params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
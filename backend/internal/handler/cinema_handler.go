@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	cinemaapp "cinemaos-backend/internal/app/cinema"
+	"cinemaos-backend/internal/middleware"
 	"cinemaos-backend/internal/pkg/response"
 	"cinemaos-backend/internal/pkg/validator"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// cinemaFields are the fields ?fields= can select from a cinema
+var cinemaFields = response.NewFieldSet(cinemaapp.CinemaResponse{}, cinemaapp.PublicCinemaResponse{})
+
 // CinemaHandler handles cinema HTTP requests
 type CinemaHandler struct {
 	cinemaService *cinemaapp.Service
@@ -40,8 +44,7 @@ func NewCinemaHandler(cinemaService *cinemaapp.Service, validator *validator.Val
 // @Router /cinemas [post]
 func (h *CinemaHandler) Create(c *gin.Context) {
 	var req cinemaapp.CreateCinemaRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -69,10 +72,16 @@ func (h *CinemaHandler) Create(c *gin.Context) {
 // @Tags cinemas
 // @Produce json
 // @Param id path string true "Cinema ID"
+// @Param fields query string false "Comma-separated fields to include"
 // @Success 200 {object} response.Response{data=cinemaapp.CinemaResponse}
+// @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /cinemas/{id} [get]
 func (h *CinemaHandler) GetByID(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), cinemaFields) {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		response.BadRequest(c, "Invalid cinema ID")
@@ -94,9 +103,15 @@ func (h *CinemaHandler) GetByID(c *gin.Context) {
 // @Tags cinemas
 // @Produce json
 // @Param params query cinemaapp.CinemaListParams false "Filter params"
+// @Param fields query string false "Comma-separated fields to include"
 // @Success 200 {object} response.Response{data=[]cinemaapp.CinemaResponse}
+// @Failure 400 {object} response.Response
 // @Router /cinemas [get]
 func (h *CinemaHandler) List(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), cinemaFields) {
+		return
+	}
+
 	var params cinemaapp.CinemaListParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		response.BadRequest(c, "Invalid query parameters")
@@ -116,6 +131,80 @@ func (h *CinemaHandler) List(c *gin.Context) {
 	response.Paginated(c, result, pagination, total)
 }
 
+// Search godoc
+// @Summary Search cinemas
+// @Description Search active cinemas by name, city and address, best match first. A single word matches anywhere in the text; several words match whole words, the last as a prefix.
+// @Tags cinemas
+// @Produce json
+// @Param params query cinemaapp.CinemaListParams true "Query, city and pagination"
+// @Param fields query string false "Comma-separated fields to include"
+// @Success 200 {object} response.Response{data=[]cinemaapp.CinemaResponse}
+// @Failure 400 {object} response.Response
+// @Router /cinemas/search [get]
+func (h *CinemaHandler) Search(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), cinemaFields) {
+		return
+	}
+
+	var params cinemaapp.CinemaListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, total, err := h.cinemaService.Search(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// Nearby godoc
+// @Summary Find nearby cinemas
+// @Description List active cinemas within a radius of a point, nearest first, with their distance
+// @Tags cinemas
+// @Produce json
+// @Param params query cinemaapp.NearbyCinemaParams true "Location and radius"
+// @Param fields query string false "Comma-separated fields to include"
+// @Success 200 {object} response.Response{data=[]cinemaapp.CinemaResponse}
+// @Failure 400 {object} response.Response
+// @Router /cinemas/nearby [get]
+func (h *CinemaHandler) Nearby(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), cinemaFields) {
+		return
+	}
+
+	var params cinemaapp.NearbyCinemaParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.cinemaService.Nearby(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // AddScreen godoc
 // @Summary Add screen to cinema
 // @Description Add a new screen to a cinema
@@ -136,8 +225,7 @@ func (h *CinemaHandler) AddScreen(c *gin.Context) {
 	}
 
 	var req cinemaapp.CreateScreenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -158,3 +246,161 @@ func (h *CinemaHandler) AddScreen(c *gin.Context) {
 		Data:    result,
 	})
 }
+
+// Update godoc
+// @Summary Update cinema
+// @Description Update an existing cinema
+// @Tags cinemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param request body cinemaapp.UpdateCinemaRequest true "Cinema updates"
+// @Success 200 {object} response.Response{data=cinemaapp.CinemaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id} [put]
+func (h *CinemaHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	var req cinemaapp.UpdateCinemaRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.cinemaService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// LinkCompanionSeat godoc
+// @Summary Link wheelchair companion seat
+// @Description Designate the free companion seat for a wheelchair seat
+// @Tags cinemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param seatId path string true "Wheelchair seat ID"
+// @Param request body cinemaapp.LinkCompanionSeatRequest true "Companion seat"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/seats/{seatId}/companion [put]
+func (h *CinemaHandler) LinkCompanionSeat(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	seatID, err := uuid.Parse(c.Param("seatId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid seat ID")
+		return
+	}
+
+	var req cinemaapp.LinkCompanionSeatRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if err := h.cinemaService.LinkCompanionSeat(c.Request.Context(), cinemaID, seatID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Companion seat updated successfully", nil)
+}
+
+// GetScreenDevices godoc
+// @Summary Get screen assistive devices
+// @Description Get a screen's stock of assistive listening and audio description devices
+// @Tags cinemas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param screenId path string true "Screen ID"
+// @Success 200 {object} response.Response{data=cinemaapp.ScreenDevicesResponse}
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/screens/{screenId}/devices [get]
+func (h *CinemaHandler) GetScreenDevices(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	screenID, err := uuid.Parse(c.Param("screenId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid screen ID")
+		return
+	}
+
+	result, err := h.cinemaService.GetScreenDevices(c.Request.Context(), cinemaID, screenID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// UpdateScreenDevices godoc
+// @Summary Update screen assistive devices
+// @Description Replace a screen's stock of assistive devices; omitted device types have no stock
+// @Tags cinemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param screenId path string true "Screen ID"
+// @Param request body cinemaapp.UpdateScreenDevicesRequest true "Device stock"
+// @Success 200 {object} response.Response{data=cinemaapp.ScreenDevicesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/screens/{screenId}/devices [put]
+func (h *CinemaHandler) UpdateScreenDevices(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	screenID, err := uuid.Parse(c.Param("screenId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid screen ID")
+		return
+	}
+
+	var req cinemaapp.UpdateScreenDevicesRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.cinemaService.UpdateScreenDevices(c.Request.Context(), cinemaID, screenID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	curationapp "cinemaos-backend/internal/app/curation"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CurationHandler handles the homepage and its curated slots
+type CurationHandler struct {
+	service   *curationapp.Service
+	validator *validator.Validator
+}
+
+// NewCurationHandler creates a new curation handler
+func NewCurationHandler(service *curationapp.Service, validator *validator.Validator) *CurationHandler {
+	return &CurationHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetHome godoc
+// @Summary Get homepage
+// @Description Get the active hero and featured slots with the now-showing and coming-soon rows. Responses carry an ETag and may be cached briefly.
+// @Tags home
+// @Produce json
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {object} response.Response{data=curationapp.HomeResponse}
+// @Success 304 "Not modified"
+// @Router /home [get]
+func (h *CurationHandler) GetHome(c *gin.Context) {
+	res, err := h.service.GetHome(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Cached(c, res, h.service.CacheTTL())
+}
+
+// ListSlots godoc
+// @Summary List featured slots
+// @Description List homepage slots, including scheduled and expired ones
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param params query curationapp.SlotListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]curationapp.SlotResponse}
+// @Router /admin/featured-slots [get]
+func (h *CurationHandler) ListSlots(c *gin.Context) {
+	var params curationapp.SlotListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.service.ListSlots(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// GetSlot godoc
+// @Summary Get featured slot
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Slot ID"
+// @Success 200 {object} response.Response{data=curationapp.SlotResponse}
+// @Failure 404 {object} response.Response
+// @Router /admin/featured-slots/{id} [get]
+func (h *CurationHandler) GetSlot(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid slot ID")
+		return
+	}
+
+	res, err := h.service.GetSlot(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// CreateSlot godoc
+// @Summary Create featured slot
+// @Description Feature a movie or a promotional banner on the homepage
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body curationapp.CreateSlotRequest true "Slot"
+// @Success 201 {object} response.Response{data=curationapp.SlotResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/featured-slots [post]
+func (h *CurationHandler) CreateSlot(c *gin.Context) {
+	var req curationapp.CreateSlotRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.CreateSlot(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// UpdateSlot godoc
+// @Summary Update featured slot
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Slot ID"
+// @Param request body curationapp.UpdateSlotRequest true "Changes"
+// @Success 200 {object} response.Response{data=curationapp.SlotResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/featured-slots/{id} [put]
+func (h *CurationHandler) UpdateSlot(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid slot ID")
+		return
+	}
+
+	var req curationapp.UpdateSlotRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.UpdateSlot(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// DeleteSlot godoc
+// @Summary Delete featured slot
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Slot ID"
+// @Success 204
+// @Failure 404 {object} response.Response
+// @Router /admin/featured-slots/{id} [delete]
+func (h *CurationHandler) DeleteSlot(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid slot ID")
+		return
+	}
+
+	if err := h.service.DeleteSlot(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	dailyreportapp "cinemaos-backend/internal/app/dailyreport"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DailyReportHandler handles cinemas' end-of-day operations reports
+type DailyReportHandler struct {
+	service   *dailyreportapp.Service
+	validator *validator.Validator
+}
+
+// NewDailyReportHandler creates a new daily report handler
+func NewDailyReportHandler(service *dailyreportapp.Service, validator *validator.Validator) *DailyReportHandler {
+	return &DailyReportHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// List godoc
+// @Summary List daily reports
+// @Description List a cinema's end-of-day reports, newest business date first. Managers see only their assigned cinemas.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param params query dailyreportapp.ReportListParams false "Pagination params"
+// @Success 200 {object} response.Response{data=[]dailyreportapp.DailyReportResponse}
+// @Failure 403 {object} response.Response
+// @Router /admin/cinemas/{id}/daily-reports [get]
+func (h *DailyReportHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	var params dailyreportapp.ReportListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.service.List(c.Request.Context(), userID, middleware.GetUserRole(c), cinemaID, params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// Regenerate godoc
+// @Summary Regenerate a daily report
+// @Description Build a cinema's report for a date again after late corrections, replacing the saved report and emailing the cinema's managers
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param request body dailyreportapp.RegenerateRequest true "Business date"
+// @Success 200 {object} response.Response{data=dailyreportapp.DailyReportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/cinemas/{id}/daily-reports [post]
+func (h *DailyReportHandler) Regenerate(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	var req dailyreportapp.RegenerateRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.service.Regenerate(c.Request.Context(), userID, middleware.GetUserRole(c), cinemaID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	demandapp "cinemaos-backend/internal/app/demand"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DemandHandler handles showtime demand stats exports
+type DemandHandler struct {
+	service   *demandapp.Service
+	validator *validator.Validator
+}
+
+// NewDemandHandler creates a new demand handler
+func NewDemandHandler(service *demandapp.Service, validator *validator.Validator) *DemandHandler {
+	return &DemandHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Export godoc
+// @Summary Export showtime demand stats
+// @Description Export confirmed bookings rolled up by movie, cinema, day of week, time bucket and screen type, as JSON or CSV
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param params query demandapp.ExportParams false "Filters and format"
+// @Success 200 {object} response.Response{data=demandapp.ExportResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/demand-stats [get]
+func (h *DemandHandler) Export(c *gin.Context) {
+	var params demandapp.ExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.service.Export(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if params.Format != "csv" {
+		response.Success(c, result)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="showtime-demand.csv"`)
+	c.Status(http.StatusOK)
+	if err := demandapp.WriteCSV(c.Writer, result.Stats); err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"cinemaos-backend/internal/app/groupbooking"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupBookingHandler handles group booking HTTP requests
+type GroupBookingHandler struct {
+	service   *groupbooking.Service
+	validator *validator.Validator
+}
+
+// NewGroupBookingHandler creates a new group booking handler
+func NewGroupBookingHandler(service *groupbooking.Service, validator *validator.Validator) *GroupBookingHandler {
+	return &GroupBookingHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Create godoc
+// @Summary Open a group booking
+// @Description Open a group booking for a showtime and get the link participants join with
+// @Tags group-bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body groupbooking.CreateGroupBookingRequest true "Group booking"
+// @Success 201 {object} response.Response{data=groupbooking.GroupBookingResponse}
+// @Failure 400 {object} response.Response
+// @Router /group-bookings [post]
+func (h *GroupBookingHandler) Create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req groupbooking.CreateGroupBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// Get godoc
+// @Summary Get group booking
+// @Description Get the group booking behind a share link with the seats picked so far
+// @Tags group-bookings
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} response.Response{data=groupbooking.GroupBookingResponse}
+// @Failure 404 {object} response.Response
+// @Router /group-bookings/{token} [get]
+func (h *GroupBookingHandler) Get(c *gin.Context) {
+	res, err := h.service.Get(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Join godoc
+// @Summary Join a group booking
+// @Description Join a group booking with your own seats, which are held for the group until it is finalized
+// @Tags group-bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Share token"
+// @Param request body groupbooking.JoinGroupBookingRequest true "Seats to join with"
+// @Success 201 {object} response.Response{data=groupbooking.JoinGroupBookingResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /group-bookings/{token}/join [post]
+func (h *GroupBookingHandler) Join(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req groupbooking.JoinGroupBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Join(c.Request.Context(), userID, c.Param("token"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// Finalize godoc
+// @Summary Finalize a group booking
+// @Description Book the seats of everyone who has joined as one booking, paid for by the organizer
+// @Tags group-bookings
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Share token"
+// @Success 201 {object} response.Response{data=groupbooking.FinalizeGroupBookingResponse}
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /group-bookings/{token}/finalize [post]
+func (h *GroupBookingHandler) Finalize(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	res, err := h.service.Finalize(c.Request.Context(), userID, c.Param("token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"cinemaos-backend/internal/app/groupcheckout"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GroupCheckoutHandler handles split-payment group checkout HTTP requests
+type GroupCheckoutHandler struct {
+	service   *groupcheckout.Service
+	validator *validator.Validator
+}
+
+// NewGroupCheckoutHandler creates a new group checkout handler
+func NewGroupCheckoutHandler(service *groupcheckout.Service, validator *validator.Validator) *GroupCheckoutHandler {
+	return &GroupCheckoutHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Split godoc
+// @Summary Split a hold between several payers
+// @Description Assign the held seats to invitees who each pay their own share
+// @Tags group-checkouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Hold ID"
+// @Param request body groupcheckout.SplitHoldRequest true "Share assignments"
+// @Success 201 {object} response.Response{data=groupcheckout.GroupCheckoutResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /holds/{id}/split [post]
+func (h *GroupCheckoutHandler) Split(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req groupcheckout.SplitHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Split(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// GetByID godoc
+// @Summary Get group checkout
+// @Description Get a group checkout organized by the current user
+// @Tags group-checkouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group checkout ID"
+// @Success 200 {object} response.Response{data=groupcheckout.GroupCheckoutResponse}
+// @Failure 404 {object} response.Response
+// @Router /group-checkouts/{id} [get]
+func (h *GroupCheckoutHandler) GetByID(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid group checkout ID")
+		return
+	}
+
+	res, err := h.service.GetByID(c.Request.Context(), userID, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Resolve godoc
+// @Summary Resolve unpaid shares
+// @Description Pay the remainder or release the unpaid seats after the share window lapsed
+// @Tags group-checkouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group checkout ID"
+// @Param request body groupcheckout.ResolveRequest true "Organizer decision"
+// @Success 200 {object} response.Response{data=groupcheckout.GroupCheckoutResponse}
+// @Failure 409 {object} response.Response
+// @Router /group-checkouts/{id}/resolve [post]
+func (h *GroupCheckoutHandler) Resolve(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid group checkout ID")
+		return
+	}
+
+	var req groupcheckout.ResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Resolve(c.Request.Context(), userID, id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// GetShare godoc
+// @Summary Get share by pay link
+// @Description Get the share an invitee was asked to pay
+// @Tags group-checkouts
+// @Produce json
+// @Param token path string true "Pay link token"
+// @Success 200 {object} response.Response{data=groupcheckout.SharePaymentResponse}
+// @Failure 401 {object} response.Response
+// @Router /group-checkouts/shares/{token} [get]
+func (h *GroupCheckoutHandler) GetShare(c *gin.Context) {
+	res, err := h.service.GetShareByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	guestlookupapp "cinemaos-backend/internal/app/guestlookup"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestLookupHandler handles booking lookups by guests without an account
+type GuestLookupHandler struct {
+	service   *guestlookupapp.Service
+	validator *validator.Validator
+}
+
+// NewGuestLookupHandler creates a new guest lookup handler
+func NewGuestLookupHandler(service *guestlookupapp.Service, validator *validator.Validator) *GuestLookupHandler {
+	return &GuestLookupHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Lookup godoc
+// @Summary Look up a guest booking
+// @Description Find a guest booking by reference and email. When email verification is enabled a code is emailed to the guest and the booking is returned by the verify endpoint.
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param request body guestlookupapp.LookupRequest true "Reference and email"
+// @Success 200 {object} response.Response{data=guestlookupapp.LookupResponse}
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /guest-bookings/lookup [post]
+func (h *GuestLookupHandler) Lookup(c *gin.Context) {
+	var req guestlookupapp.LookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Lookup(c.Request.Context(), req, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Verify godoc
+// @Summary Verify a guest booking lookup
+// @Description Return the guest booking once the code emailed by the lookup is verified
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param request body guestlookupapp.VerifyRequest true "Reference, email and code"
+// @Success 200 {object} response.Response{data=guestlookupapp.LookupResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /guest-bookings/lookup/verify [post]
+func (h *GuestLookupHandler) Verify(c *gin.Context) {
+	var req guestlookupapp.VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Verify(c.Request.Context(), req, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Claim godoc
+// @Summary Claim a guest booking
+// @Description Start adding a guest booking to the signed-in account. A code is emailed to the booking's guest email and the booking is moved by the verify endpoint.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body guestlookupapp.ClaimRequest true "Booking reference"
+// @Success 200 {object} response.Response{data=guestlookupapp.ClaimResponse}
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /bookings/claim [post]
+func (h *GuestLookupHandler) Claim(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req guestlookupapp.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Claim(c.Request.Context(), userID, req, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// VerifyClaim godoc
+// @Summary Verify a guest booking claim
+// @Description Add the guest booking to the signed-in account once the code emailed by the claim is verified
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body guestlookupapp.ClaimVerifyRequest true "Booking reference and code"
+// @Success 200 {object} response.Response{data=guestlookupapp.GuestBookingResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /bookings/claim/verify [post]
+func (h *GuestLookupHandler) VerifyClaim(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req guestlookupapp.ClaimVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.VerifyClaim(c.Request.Context(), userID, req, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
@@ -6,7 +6,9 @@ import (
 	"runtime"
 	"time"
 
+	warmupapp "cinemaos-backend/internal/app/warmup"
 	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/shadow"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,16 +23,20 @@ type HealthHandler struct {
 	cfg      *config.Config
 	db       HealthChecker
 	redis    HealthChecker
+	shadowReads *shadow.Reader
+	warmup      *warmupapp.Service
 	startTime time.Time
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(cfg *config.Config, db, redis HealthChecker) *HealthHandler {
+func NewHealthHandler(cfg *config.Config, db, redis HealthChecker, shadowReads *shadow.Reader, warmup *warmupapp.Service) *HealthHandler {
 	return &HealthHandler{
-		cfg:       cfg,
-		db:        db,
-		redis:     redis,
-		startTime: time.Now(),
+		cfg:         cfg,
+		db:          db,
+		redis:       redis,
+		shadowReads: shadowReads,
+		warmup:      warmup,
+		startTime:   time.Now(),
 	}
 }
 
@@ -41,6 +47,8 @@ type HealthResponse struct {
 	Environment string                 `json:"environment"`
 	Uptime      string                 `json:"uptime"`
 	Checks      map[string]CheckStatus `json:"checks,omitempty"`
+	ShadowReads map[string]shadow.Stats `json:"shadow_reads,omitempty"`
+	Warmup      *warmupapp.Status       `json:"warmup,omitempty"`
 }
 
 // CheckStatus represents individual health check status
@@ -67,7 +75,7 @@ func (h *HealthHandler) Health(c *gin.Context) {
 
 // HealthDetailed godoc
 // @Summary Detailed health check
-// @Description Health check with dependency status
+// @Description Health check with dependency status. The server only listens once the database and Redis connections were tried, so this is unreachable rather than ready while they are retried at startup; a server that gave up on Redis reports "degraded", keeps seat holds in the database and stays ready.
 // @Tags health
 // @Produce json
 // @Success 200 {object} HealthResponse
@@ -90,13 +98,40 @@ func (h *HealthHandler) HealthDetailed(c *gin.Context) {
 		}
 	}
 
-	// Check Redis
-	if h.redis != nil {
-		if err := h.redis.Health(ctx); err != nil {
-			checks["redis"] = CheckStatus{Status: "unhealthy", Message: err.Error()}
+	// Check Redis; without it the server still serves traffic, degraded
+	if h.redis == nil {
+		checks["redis"] = CheckStatus{
+			Status:  "degraded",
+			Message: "running without Redis: nothing is cached and seat updates are not pushed",
+		}
+		checks["seat_holds"] = CheckStatus{
+			Status:  "degraded",
+			Message: "seat holds are kept in the database",
+		}
+		if overallStatus == "healthy" {
+			overallStatus = "degraded"
+		}
+	} else if err := h.redis.Health(ctx); err != nil {
+		checks["redis"] = CheckStatus{Status: "unhealthy", Message: err.Error()}
+		if overallStatus == "healthy" {
 			overallStatus = "degraded" // Redis might be optional
+		}
+	} else {
+		checks["redis"] = CheckStatus{Status: "healthy"}
+	}
+
+	// Not ready until caches are warmed or the warmup budget runs out
+	var warmup *warmupapp.Status
+	if h.warmup != nil {
+		status := h.warmup.Status()
+		warmup = &status
+		if status.Ready() {
+			checks["warmup"] = CheckStatus{Status: "healthy"}
 		} else {
-			checks["redis"] = CheckStatus{Status: "healthy"}
+			checks["warmup"] = CheckStatus{Status: "warming", Message: string(status.State)}
+			if overallStatus != "unhealthy" {
+				overallStatus = "starting"
+			}
 		}
 	}
 
@@ -106,10 +141,16 @@ func (h *HealthHandler) HealthDetailed(c *gin.Context) {
 		Environment: h.cfg.App.Environment,
 		Uptime:      time.Since(h.startTime).String(),
 		Checks:      checks,
+		Warmup:      warmup,
+	}
+
+	// Shadow-read mismatches are reported but never fail readiness
+	if h.shadowReads != nil {
+		resp.ShadowReads = h.shadowReads.Stats()
 	}
 
 	status := http.StatusOK
-	if overallStatus == "unhealthy" {
+	if overallStatus == "unhealthy" || overallStatus == "starting" {
 		status = http.StatusServiceUnavailable
 	}
 
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinemaos-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checker reports err as its health
+type checker struct{ err error }
+
+func (c checker) Health(context.Context) error { return c.err }
+
+func TestHealthDetailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	down := checker{errors.New("connection refused")}
+
+	tests := []struct {
+		name       string
+		db, redis  HealthChecker
+		wantCode   int
+		wantStatus string
+		wantRedis  string
+		wantHolds  string // seat holds check, reported only without Redis
+	}{
+		{"all healthy", checker{}, checker{}, http.StatusOK, "healthy", "healthy", ""},
+		{"running without Redis", checker{}, nil, http.StatusOK, "degraded", "degraded", "degraded"},
+		{"Redis failing", checker{}, down, http.StatusOK, "degraded", "unhealthy", ""},
+		{"database down without Redis", down, nil, http.StatusServiceUnavailable, "unhealthy", "degraded", "degraded"},
+		{"database and Redis down", down, down, http.StatusServiceUnavailable, "unhealthy", "unhealthy", ""},
+	}
+	for _, tt := range tests {
+		h := NewHealthHandler(&config.Config{}, tt.db, tt.redis, nil, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+		h.HealthDetailed(c)
+
+		var resp HealthResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode: %v", tt.name, err)
+		}
+		if w.Code != tt.wantCode || resp.Status != tt.wantStatus {
+			t.Errorf("%s: %d %q, want %d %q", tt.name, w.Code, resp.Status, tt.wantCode, tt.wantStatus)
+		}
+		if got := resp.Checks["redis"].Status; got != tt.wantRedis {
+			t.Errorf("%s: redis check %q, want %q", tt.name, got, tt.wantRedis)
+		}
+		if got := resp.Checks["seat_holds"].Status; got != tt.wantHolds {
+			t.Errorf("%s: seat holds check %q, want %q", tt.name, got, tt.wantHolds)
+		}
+	}
+}
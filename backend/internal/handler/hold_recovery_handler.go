@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"cinemaos-backend/internal/app/holdrecovery"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HoldRecoveryHandler handles re-holds from hold recovery emails
+type HoldRecoveryHandler struct {
+	service   *holdrecovery.Service
+	validator *validator.Validator
+}
+
+// NewHoldRecoveryHandler creates a new hold recovery handler
+func NewHoldRecoveryHandler(service *holdrecovery.Service, validator *validator.Validator) *HoldRecoveryHandler {
+	return &HoldRecoveryHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Rehold godoc
+// @Summary Hold seats again from a recovery email
+// @Description Hold seats comparable to an expired hold using the token from the "your seats were released" email
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body holdrecovery.ReholdRequest true "Recovery token"
+// @Success 201 {object} response.Response{data=holdrecovery.ReholdResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /holds/recover [post]
+func (h *HoldRecoveryHandler) Rehold(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req holdrecovery.ReholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Rehold(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	loyaltyapp "cinemaos-backend/internal/app/loyalty"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoyaltyHandler handles the loyalty points of the current user
+type LoyaltyHandler struct {
+	service *loyaltyapp.Service
+}
+
+// NewLoyaltyHandler creates a new loyalty handler
+func NewLoyaltyHandler(service *loyaltyapp.Service) *LoyaltyHandler {
+	return &LoyaltyHandler{service: service}
+}
+
+// GetBalance godoc
+// @Summary Get loyalty balance
+// @Description Get the current user's loyalty points balance, what it is worth and when it expires without further activity
+// @Tags loyalty
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=loyaltyapp.BalanceResponse}
+// @Failure 401 {object} response.Response
+// @Router /loyalty/balance [get]
+func (h *LoyaltyHandler) GetBalance(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	res, err := h.service.GetBalance(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ListTransactions godoc
+// @Summary List loyalty transactions
+// @Description List the points the current user earned, redeemed, got back or lost to expiry, newest first
+// @Tags loyalty
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]loyaltyapp.TransactionResponse}
+// @Failure 401 {object} response.Response
+// @Router /loyalty/transactions [get]
+func (h *LoyaltyHandler) ListTransactions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	result, total, err := h.service.ListTransactions(c.Request.Context(), userID, pagination.Page, pagination.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
@@ -1,10 +1,11 @@
 package handler
 
 import (
-
+	"fmt"
 
 	movieapp "cinemaos-backend/internal/app/movie"
 	showtimeapp "cinemaos-backend/internal/app/showtime"
+	"cinemaos-backend/internal/middleware"
 	"cinemaos-backend/internal/pkg/response"
 	"cinemaos-backend/internal/pkg/validator"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// movieFields are the fields ?fields= can select from a movie
+var movieFields = response.NewFieldSet(movieapp.MovieResponse{}, movieapp.PublicMovieResponse{})
+
 // MovieHandler handles movie HTTP requests
 type MovieHandler struct {
 	movieService    *movieapp.Service
@@ -43,8 +47,7 @@ func NewMovieHandler(movieService *movieapp.Service, showtimeService *showtimeap
 // @Router /movies [post]
 func (h *MovieHandler) Create(c *gin.Context) {
 	var req movieapp.CreateMovieRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -68,10 +71,16 @@ func (h *MovieHandler) Create(c *gin.Context) {
 // @Tags movies
 // @Produce json
 // @Param id path string true "Movie ID"
+// @Param fields query string false "Comma-separated fields to include"
 // @Success 200 {object} response.Response{data=movieapp.MovieResponse}
+// @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /movies/{id} [get]
 func (h *MovieHandler) GetByID(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), movieFields) {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		response.BadRequest(c, "Invalid movie ID")
@@ -108,8 +117,7 @@ func (h *MovieHandler) Update(c *gin.Context) {
 	}
 
 	var req movieapp.UpdateMovieRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -129,12 +137,14 @@ func (h *MovieHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete movie
-// @Description Soft delete a movie
+// @Description Soft delete a movie. A movie with upcoming scheduled showtimes is only deleted with force, which cancels them.
 // @Tags movies
 // @Security BearerAuth
 // @Param id path string true "Movie ID"
-// @Success 200 {object} response.Response
+// @Param force query bool false "Cancel the movie's upcoming showtimes"
+// @Success 200 {object} response.Response{data=movieapp.DeleteMovieResponse}
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /movies/{id} [delete]
 func (h *MovieHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -143,12 +153,99 @@ func (h *MovieHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.movieService.Delete(c.Request.Context(), id); err != nil {
+	var params movieapp.DeleteMovieParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.movieService.Delete(c.Request.Context(), id, params.Force)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Movie deleted successfully", result)
+}
+
+// Restore godoc
+// @Summary Restore movie
+// @Description Bring back a deleted movie
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Success 200 {object} response.Response{data=movieapp.MovieResponse}
+// @Failure 404 {object} response.Response
+// @Router /admin/movies/{id}/restore [post]
+func (h *MovieHandler) Restore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	result, err := h.movieService.Restore(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RecalculatePopularity godoc
+// @Summary Recalculate movie popularity
+// @Description Recompute a movie's popularity score now rather than on the next hourly run
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Success 200 {object} response.Response{data=movieapp.MovieResponse}
+// @Failure 404 {object} response.Response
+// @Router /admin/movies/{id}/recalculate-popularity [post]
+func (h *MovieHandler) RecalculatePopularity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	result, err := h.movieService.RecalculatePopularity(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// AdminList godoc
+// @Summary List movies for admins
+// @Description List movies including inactive ones, and deleted ones with include_deleted
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param params query movieapp.AdminMovieListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]movieapp.MovieResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/movies [get]
+func (h *MovieHandler) AdminList(c *gin.Context) {
+	var params movieapp.AdminMovieListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.movieService.ListForAdmin(c.Request.Context(), params)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	response.SuccessWithMessage(c, "Movie deleted successfully", nil)
+	response.Paginated(c, result, pagination, total)
 }
 
 // List godoc
@@ -157,9 +254,15 @@ func (h *MovieHandler) Delete(c *gin.Context) {
 // @Tags movies
 // @Produce json
 // @Param params query movieapp.MovieListParams false "Filter params"
+// @Param fields query string false "Comma-separated fields to include, e.g. id,title,poster_url,genres"
 // @Success 200 {object} response.Response{data=[]movieapp.MovieResponse}
+// @Failure 400 {object} response.Response
 // @Router /movies [get]
 func (h *MovieHandler) List(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), movieFields) {
+		return
+	}
+
 	var params movieapp.MovieListParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		response.BadRequest(c, "Invalid query parameters")
@@ -182,17 +285,35 @@ func (h *MovieHandler) List(c *gin.Context) {
 
 // GetNowShowing godoc
 // @Summary Get now showing movies
-// @Description Get movies currently showing
+// @Description Get movies currently showing. For a signed-in user with saved preferences, movies in their favorite genres come first.
 // @Tags movies
 // @Produce json
+// @Param personalized query bool false "Only movies matching the user's favorite genres, formats or cinemas"
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
+// @Param fields query string false "Comma-separated fields to include"
 // @Success 200 {object} response.Response{data=[]movieapp.MovieResponse}
+// @Failure 400 {object} response.Response
 // @Router /movies/now-showing [get]
 func (h *MovieHandler) GetNowShowing(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), movieFields) {
+		return
+	}
+
+	var params movieapp.NowShowingParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, ok := middleware.GetUserID(c); ok {
+		userID = &id
+	}
+
 	pagination := response.GetPagination(c)
 
-	result, total, err := h.movieService.GetNowShowing(c.Request.Context(), pagination.Page, pagination.Limit)
+	result, total, err := h.movieService.GetNowShowing(c.Request.Context(), userID, params.Personalized, pagination.Page, pagination.Limit)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -208,9 +329,15 @@ func (h *MovieHandler) GetNowShowing(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
+// @Param fields query string false "Comma-separated fields to include"
 // @Success 200 {object} response.Response{data=[]movieapp.MovieResponse}
+// @Failure 400 {object} response.Response
 // @Router /movies/coming-soon [get]
 func (h *MovieHandler) GetComingSoon(c *gin.Context) {
+	if !response.UseView(c, middleware.IsAdmin(c), movieFields) {
+		return
+	}
+
 	pagination := response.GetPagination(c)
 
 	result, total, err := h.movieService.GetComingSoon(c.Request.Context(), pagination.Page, pagination.Limit)
@@ -224,11 +351,17 @@ func (h *MovieHandler) GetComingSoon(c *gin.Context) {
 
 // GetShowtimes godoc
 // @Summary Get movie showtimes
-// @Description Get all upcoming showtimes for a specific movie
+// @Description Get the upcoming showtimes of a movie grouped by cinema, then by date. Pages count cinemas; showtimes that have already started are left out. Listings are cached briefly, so seat counts may lag.
 // @Tags movies
 // @Produce json
 // @Param id path string true "Movie ID"
-// @Success 200 {object} response.Response{data=[]showtimeapp.ShowtimeResponse}
+// @Param cinema_id query string false "Cinema ID"
+// @Param city query string false "Cinema city"
+// @Param date_from query string false "First show date (YYYY-MM-DD), defaults to today"
+// @Param date_to query string false "Last show date (YYYY-MM-DD), defaults to a week after date_from"
+// @Param page query int false "Page number"
+// @Param limit query int false "Cinemas per page"
+// @Success 200 {object} response.Response{data=[]showtimeapp.CinemaShowtimesResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /movies/{id}/showtimes [get]
@@ -239,11 +372,21 @@ func (h *MovieHandler) GetShowtimes(c *gin.Context) {
 		return
 	}
 
-	result, err := h.showtimeService.GetShowtimesByMovieID(c.Request.Context(), id)
+	var params showtimeapp.MovieShowtimesParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	result, total, err := h.showtimeService.ListForMovie(c.Request.Context(), id, params, pagination.Page, pagination.Limit)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, result)
+	if ttl := h.showtimeService.ListingCacheTTL(); ttl > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+	}
+	response.Paginated(c, result, pagination, total)
 }
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	movieapp "cinemaos-backend/internal/app/movie"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListMedia godoc
+// @Summary List movie media
+// @Description List every asset of a movie's media gallery in order, published or not
+// @Tags movies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Success 200 {object} response.Response{data=[]movieapp.MediaResponse}
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/media [get]
+func (h *MovieHandler) ListMedia(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	result, err := h.movieService.ListMedia(c.Request.Context(), movieID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CreateMedia godoc
+// @Summary Add movie media
+// @Description Add a trailer, teaser, still or alternative poster at the end of a movie's media gallery. Video links are normalized to their canonical YouTube or Vimeo URL.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param request body movieapp.CreateMediaRequest true "Media asset"
+// @Success 201 {object} response.Response{data=movieapp.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/media [post]
+func (h *MovieHandler) CreateMedia(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	var req movieapp.CreateMediaRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.movieService.CreateMedia(c.Request.Context(), movieID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// UpdateMedia godoc
+// @Summary Update movie media
+// @Description Change a media asset's URL, title or published flag
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param mediaId path string true "Media ID"
+// @Param request body movieapp.UpdateMediaRequest true "Media updates"
+// @Success 200 {object} response.Response{data=movieapp.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/media/{mediaId} [put]
+func (h *MovieHandler) UpdateMedia(c *gin.Context) {
+	movieID, mediaID, ok := parseMediaIDs(c)
+	if !ok {
+		return
+	}
+
+	var req movieapp.UpdateMediaRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.movieService.UpdateMedia(c.Request.Context(), movieID, mediaID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// DeleteMedia godoc
+// @Summary Delete movie media
+// @Description Remove an asset from a movie's media gallery
+// @Tags movies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param mediaId path string true "Media ID"
+// @Success 204
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/media/{mediaId} [delete]
+func (h *MovieHandler) DeleteMedia(c *gin.Context) {
+	movieID, mediaID, ok := parseMediaIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.movieService.DeleteMedia(c.Request.Context(), movieID, mediaID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ReorderMedia godoc
+// @Summary Reorder movie media
+// @Description Put a movie's media gallery in order. The list must name every asset of the movie exactly once.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param request body movieapp.ReorderMediaRequest true "Media IDs in order"
+// @Success 200 {object} response.Response{data=[]movieapp.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Router /movies/{id}/media/order [put]
+func (h *MovieHandler) ReorderMedia(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	var req movieapp.ReorderMediaRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.movieService.ReorderMedia(c.Request.Context(), movieID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+func parseMediaIDs(c *gin.Context) (movieID, mediaID uuid.UUID, ok bool) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return movieID, mediaID, false
+	}
+	mediaID, err = uuid.Parse(c.Param("mediaId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid media ID")
+		return movieID, mediaID, false
+	}
+	return movieID, mediaID, true
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	movieapp "cinemaos-backend/internal/app/movie"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListReviews godoc
+// @Summary List movie reviews
+// @Description List a movie's reviews, newest first
+// @Tags movies
+// @Produce json
+// @Param id path string true "Movie ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]movieapp.ReviewResponse}
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/reviews [get]
+func (h *MovieHandler) ListReviews(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	result, total, err := h.movieService.ListReviews(c.Request.Context(), movieID, pagination.Page, pagination.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// CreateReview godoc
+// @Summary Review a movie
+// @Description Rate a movie from 1 to 5 stars. Requires a confirmed or completed booking for the movie; each customer can review a movie once.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param request body movieapp.CreateReviewRequest true "Review"
+// @Success 201 {object} response.Response{data=movieapp.ReviewResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /movies/{id}/reviews [post]
+func (h *MovieHandler) CreateReview(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req movieapp.CreateReviewRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	result, err := h.movieService.AddReview(c.Request.Context(), userID, movieID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// DeleteReview godoc
+// @Summary Delete a movie review
+// @Description Remove a review. Customers can remove their own reviews; admins can remove any.
+// @Tags movies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Movie ID"
+// @Param reviewId path string true "Review ID"
+// @Success 204
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /movies/{id}/reviews/{reviewId} [delete]
+func (h *MovieHandler) DeleteReview(c *gin.Context) {
+	movieID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid movie ID")
+		return
+	}
+	reviewID, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid review ID")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	if err := h.movieService.DeleteReview(c.Request.Context(), userID, middleware.IsAdmin(c), movieID, reviewID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	paymentapp "cinemaos-backend/internal/app/payment"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookSignatureHeader carries the gateway's HMAC-SHA256 of the body
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// StripeSignatureHeader carries Stripe's timestamped signature of the body
+const StripeSignatureHeader = "Stripe-Signature"
+
+// maxWebhookBodySize caps the webhook body we are willing to store
+const maxWebhookBodySize = 64 << 10
+
+// PaymentHandler handles payment gateway webhooks and the webhook inbox
+type PaymentHandler struct {
+	service   *paymentapp.Service
+	validator *validator.Validator
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(service *paymentapp.Service, validator *validator.Validator) *PaymentHandler {
+	return &PaymentHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Webhook godoc
+// @Summary Payment gateway webhook
+// @Description Receive a payment gateway event, including the Stripe checkout.session.* and payment_intent.* events. A paid checkout session confirms its booking; an expired or declined one releases the seats. The event is stored before it is processed.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string false "Hex HMAC-SHA256 of the body"
+// @Param Stripe-Signature header string false "Stripe's signature, when Stripe sends the event"
+// @Success 200 {object} response.Response{data=paymentapp.WebhookAckResponse}
+// @Failure 401 {object} response.Response
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	signature := c.GetHeader(WebhookSignatureHeader)
+	if signature == "" {
+		signature = c.GetHeader(StripeSignatureHeader)
+	}
+
+	res, err := h.service.ReceiveWebhook(c.Request.Context(), signature, body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ListWebhookEvents godoc
+// @Summary List webhook events
+// @Description List received payment webhook events, e.g. the failed ones awaiting replay
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param params query paymentapp.WebhookEventListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]paymentapp.WebhookEventResponse}
+// @Router /admin/webhooks [get]
+func (h *PaymentHandler) ListWebhookEvents(c *gin.Context) {
+	var params paymentapp.WebhookEventListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.service.ListWebhookEvents(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// ReplayWebhookEvent godoc
+// @Summary Replay webhook event
+// @Description Process a stored webhook event again. Already processed events are left unchanged.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook event ID"
+// @Success 200 {object} response.Response{data=paymentapp.WebhookEventResponse}
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/webhooks/{id}/replay [post]
+func (h *PaymentHandler) ReplayWebhookEvent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid webhook event ID")
+		return
+	}
+
+	res, err := h.service.ReplayWebhookEvent(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// ReplayWebhookEvents godoc
+// @Summary Replay webhook events
+// @Description Process several stored webhook events again
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body paymentapp.ReplayBatchRequest true "Webhook event IDs"
+// @Success 200 {object} response.Response{data=paymentapp.ReplayBatchResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/webhooks/replay [post]
+func (h *PaymentHandler) ReplayWebhookEvents(c *gin.Context) {
+	var req paymentapp.ReplayBatchRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.ReplayWebhookEvents(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
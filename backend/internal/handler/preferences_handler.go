@@ -0,0 +1,88 @@
+package handler
+
+import (
+	preferencesapp "cinemaos-backend/internal/app/preferences"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreferencesHandler handles the preferences of the current user
+type PreferencesHandler struct {
+	service   *preferencesapp.Service
+	validator *validator.Validator
+}
+
+// NewPreferencesHandler creates a new user preferences handler
+func NewPreferencesHandler(service *preferencesapp.Service, validator *validator.Validator) *PreferencesHandler {
+	return &PreferencesHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Get godoc
+// @Summary Get preferences
+// @Description Get the current user's favorite genres, cinemas and formats and notification settings
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=preferencesapp.PreferencesResponse}
+// @Failure 401 {object} response.Response
+// @Router /auth/me/preferences [get]
+func (h *PreferencesHandler) Get(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	res, err := h.service.Get(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Update godoc
+// @Summary Update preferences
+// @Description Save the current user's preferences. Lists and settings left out keep their current value; an empty list clears it.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body preferencesapp.UpdatePreferencesRequest true "Preferences"
+// @Success 200 {object} response.Response{data=preferencesapp.PreferencesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/me/preferences [put]
+func (h *PreferencesHandler) Update(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req preferencesapp.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.Update(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Preferences updated successfully", res)
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	pricingapp "cinemaos-backend/internal/app/pricing"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PricingHandler handles cinema pricing rule HTTP requests
+type PricingHandler struct {
+	service   *pricingapp.Service
+	validator *validator.Validator
+}
+
+// NewPricingHandler creates a new pricing rule handler
+func NewPricingHandler(service *pricingapp.Service, validator *validator.Validator) *PricingHandler {
+	return &PricingHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// ListRules godoc
+// @Summary List pricing rules
+// @Description List a cinema's pricing rules
+// @Tags cinemas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Success 200 {object} response.Response{data=[]pricingapp.PricingRuleResponse}
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/pricing-rules [get]
+func (h *PricingHandler) ListRules(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	res, err := h.service.ListRules(c.Request.Context(), cinemaID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// CreateRule godoc
+// @Summary Create pricing rule
+// @Description Add a rule that adjusts a cinema's seat prices by seat type, time of day, day of week or occupancy, optionally only on one screen type or within a validity window. Of the matching rules of each type only those with the highest priority apply. Percentage modifiers compound on the base price by seat type, then flat amounts are added.
+// @Tags cinemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param request body pricingapp.CreatePricingRuleRequest true "Pricing rule"
+// @Success 201 {object} response.Response{data=pricingapp.PricingRuleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/pricing-rules [post]
+func (h *PricingHandler) CreateRule(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+
+	var req pricingapp.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.CreateRule(c.Request.Context(), cinemaID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// GetRule godoc
+// @Summary Get pricing rule
+// @Description Get one of a cinema's pricing rules
+// @Tags cinemas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param ruleId path string true "Pricing rule ID"
+// @Success 200 {object} response.Response{data=pricingapp.PricingRuleResponse}
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/pricing-rules/{ruleId} [get]
+func (h *PricingHandler) GetRule(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pricing rule ID")
+		return
+	}
+
+	res, err := h.service.GetRule(c.Request.Context(), cinemaID, ruleID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// UpdateRule godoc
+// @Summary Update pricing rule
+// @Description Change a pricing rule's modifier, condition, screen type, priority or validity window. Omitted fields are kept; the clear_* flags remove optional ones.
+// @Tags cinemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param ruleId path string true "Pricing rule ID"
+// @Param request body pricingapp.UpdatePricingRuleRequest true "Changes"
+// @Success 200 {object} response.Response{data=pricingapp.PricingRuleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/pricing-rules/{ruleId} [put]
+func (h *PricingHandler) UpdateRule(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pricing rule ID")
+		return
+	}
+
+	var req pricingapp.UpdatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.UpdateRule(c.Request.Context(), cinemaID, ruleID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// DeleteRule godoc
+// @Summary Delete pricing rule
+// @Description Remove one of a cinema's pricing rules
+// @Tags cinemas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cinema ID"
+// @Param ruleId path string true "Pricing rule ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /cinemas/{id}/pricing-rules/{ruleId} [delete]
+func (h *PricingHandler) DeleteRule(c *gin.Context) {
+	cinemaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid cinema ID")
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pricing rule ID")
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), cinemaID, ruleID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Pricing rule deleted successfully", nil)
+}
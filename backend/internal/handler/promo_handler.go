@@ -0,0 +1,225 @@
+package handler
+
+import (
+	promoapp "cinemaos-backend/internal/app/promo"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PromoHandler handles admin promo code HTTP requests
+type PromoHandler struct {
+	service   *promoapp.Service
+	validator *validator.Validator
+}
+
+// NewPromoHandler creates a new promo code handler
+func NewPromoHandler(service *promoapp.Service, validator *validator.Validator) *PromoHandler {
+	return &PromoHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// List godoc
+// @Summary List promo codes
+// @Description List promo codes, newest first, or look up a single code
+// @Tags promo-codes
+// @Produce json
+// @Security BearerAuth
+// @Param params query promoapp.PromoCodeListParams false "Filter params"
+// @Success 200 {object} response.Response{data=[]promoapp.PromoCodeResponse}
+// @Router /promo-codes [get]
+func (h *PromoHandler) List(c *gin.Context) {
+	var params promoapp.PromoCodeListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	pagination := response.GetPagination(c)
+	params.Page = pagination.Page
+	params.Limit = pagination.Limit
+
+	result, total, err := h.service.ListPromoCodes(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, result, pagination, total)
+}
+
+// Get godoc
+// @Summary Get promo code
+// @Tags promo-codes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promo code ID"
+// @Success 200 {object} response.Response{data=promoapp.PromoCodeResponse}
+// @Failure 404 {object} response.Response
+// @Router /promo-codes/{id} [get]
+func (h *PromoHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID")
+		return
+	}
+
+	res, err := h.service.GetPromoCode(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Create godoc
+// @Summary Create promo code
+// @Description Create a percentage or fixed-amount promo code. Codes are stored uppercase and must be unique, deleted codes included.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body promoapp.CreatePromoCodeRequest true "Promo code"
+// @Success 201 {object} response.Response{data=promoapp.PromoCodeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /promo-codes [post]
+func (h *PromoHandler) Create(c *gin.Context) {
+	var req promoapp.CreatePromoCodeRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.CreatePromoCode(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// Update godoc
+// @Summary Update promo code
+// @Description Change a promo code's discount, limits or validity. Bookings already made keep their discount.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promo code ID"
+// @Param request body promoapp.UpdatePromoCodeRequest true "Changes"
+// @Success 200 {object} response.Response{data=promoapp.PromoCodeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /promo-codes/{id} [put]
+func (h *PromoHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID")
+		return
+	}
+
+	var req promoapp.UpdatePromoCodeRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.UpdatePromoCode(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// Delete godoc
+// @Summary Delete promo code
+// @Tags promo-codes
+// @Security BearerAuth
+// @Param id path string true "Promo code ID"
+// @Success 204
+// @Failure 404 {object} response.Response
+// @Router /promo-codes/{id} [delete]
+func (h *PromoHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID")
+		return
+	}
+
+	if err := h.service.DeletePromoCode(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// GetStats godoc
+// @Summary Get promo code stats
+// @Description Get a promo code's redemptions, its paid bookings and the discount given, and optionally one user's uses
+// @Tags promo-codes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promo code ID"
+// @Param params query promoapp.PromoCodeStatsParams false "Stats params"
+// @Success 200 {object} response.Response{data=promoapp.PromoCodeStatsResponse}
+// @Failure 404 {object} response.Response
+// @Router /promo-codes/{id}/stats [get]
+func (h *PromoHandler) GetStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID")
+		return
+	}
+
+	var params promoapp.PromoCodeStatsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if errors := h.validator.Validate(params); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	var userID *uuid.UUID
+	if params.UserID != "" {
+		parsed, err := uuid.Parse(params.UserID)
+		if err != nil {
+			response.BadRequest(c, "Invalid user ID")
+			return
+		}
+		userID = &parsed
+	}
+
+	res, err := h.service.GetStats(c.Request.Context(), id, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
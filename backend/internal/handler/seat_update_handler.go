@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"cinemaos-backend/internal/app/seatfeed"
+	"cinemaos-backend/internal/app/showtime"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// seatUpdateWriteTimeout drops a client that stops reading
+	seatUpdateWriteTimeout = 10 * time.Second
+	// seatUpdatePingInterval is how often clients are pinged, so a
+	// connection that went away without closing is noticed
+	seatUpdatePingInterval = 30 * time.Second
+	// seatUpdateIdleTimeout drops a client that has answered no ping, nor
+	// sent anything else, for this long
+	seatUpdateIdleTimeout = 2*seatUpdatePingInterval + seatUpdateWriteTimeout
+	// seatUpdateReadLimit bounds what a client may send; pongs and close
+	// frames are all that is expected
+	seatUpdateReadLimit = 512
+)
+
+// SeatUpdateHandler streams seat status changes of a showtime over WebSocket
+type SeatUpdateHandler struct {
+	feed      *seatfeed.Service
+	showtimes *showtime.Service
+	cors      config.CORSConfig
+}
+
+// NewSeatUpdateHandler creates a new seat update handler
+func NewSeatUpdateHandler(feed *seatfeed.Service, showtimes *showtime.Service, cors config.CORSConfig) *SeatUpdateHandler {
+	return &SeatUpdateHandler{
+		feed:      feed,
+		showtimes: showtimes,
+		cors:      cors,
+	}
+}
+
+// Watch upgrades to a WebSocket that receives a JSON message
+// {seat_id, status, updated_at} whenever a seat of the showtime is locked,
+// booked or released. The connection is closed when the client falls
+// behind; clients reload the seat map when they reconnect.
+func (h *SeatUpdateHandler) Watch(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+	// Private showtimes are watched with their access code, like they are read
+	if _, err := h.showtimes.GetByID(c.Request.Context(), id, c.Query("access_code")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkOrigin}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered the client
+		return
+	}
+	h.stream(conn, id)
+}
+
+// checkOrigin accepts browsers on the origins CORS allows, and clients that
+// send no origin
+func (h *SeatUpdateHandler) checkOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	return origin == "" || slices.Contains(h.cors.AllowOrigins, "*") || slices.Contains(h.cors.AllowOrigins, origin)
+}
+
+func (h *SeatUpdateHandler) stream(conn *websocket.Conn, showtimeID uuid.UUID) {
+	defer conn.Close()
+	sub := h.feed.Watch(showtimeID)
+	defer sub.Close()
+
+	// Clients send nothing but pongs; reading notices when they hang up or
+	// stop answering pings. Each pong, or anything else the client sends,
+	// pushes the read deadline back.
+	conn.SetReadLimit(seatUpdateReadLimit)
+	conn.SetReadDeadline(time.Now().Add(seatUpdateIdleTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(seatUpdateIdleTimeout))
+	})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(seatUpdateIdleTimeout))
+		}
+	}()
+
+	ping := time.NewTicker(seatUpdatePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(seatUpdateWriteTimeout)); err != nil {
+				return
+			}
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			if err := conn.SetWriteDeadline(time.Now().Add(seatUpdateWriteTimeout)); err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		}
+	}
+}
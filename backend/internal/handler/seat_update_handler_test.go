@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/app/seatfeed"
+	"cinemaos-backend/internal/app/showtime"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/ws"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const allowedOrigin = "https://cinema.example"
+
+type memShowtimes struct {
+	repository.ShowtimeRepository
+	showtimes map[uuid.UUID]*entity.Showtime
+}
+
+func (m *memShowtimes) GetByIDWithDetails(_ context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	if st, ok := m.showtimes[id]; ok {
+		return st, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// seatUpdateServer serves the seat update endpoint for one public and one
+// private showtime
+func seatUpdateServer(t *testing.T) (*httptest.Server, *ws.Hub, *entity.Showtime, *entity.Showtime) {
+	t.Helper()
+	log := &logger.Logger{Logger: zap.NewNop()}
+	day := time.Now().AddDate(0, 0, 1)
+	public := &entity.Showtime{ID: uuid.New(), ShowDate: day, StartTime: "20:00", Visibility: entity.VisibilityPublic}
+	codeHash := authinfra.HashToken("letmein")
+	private := &entity.Showtime{ID: uuid.New(), ShowDate: day, StartTime: "20:00", Visibility: entity.VisibilityPrivate,
+		AccessCodeHash: &codeHash}
+	showtimes := showtime.NewService(&memShowtimes{showtimes: map[uuid.UUID]*entity.Showtime{public.ID: public, private.ID: private}},
+		nil, nil, nil, nil, nil, nil, nil, config.AvailabilityConfig{}, nil, log)
+
+	hub := ws.NewHub(8)
+	h := NewSeatUpdateHandler(seatfeed.NewService(nil, hub, log), showtimes, config.CORSConfig{AllowOrigins: []string{allowedOrigin}})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/showtimes/:id/seat-updates", h.Watch)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, hub, public, private
+}
+
+func dialSeatUpdates(server *httptest.Server, path, origin string) (*websocket.Conn, error) {
+	conn, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path,
+		http.Header{"Origin": {origin}})
+	if res != nil {
+		res.Body.Close()
+	}
+	return conn, err
+}
+
+// waitForWatchers waits until the hub has n watchers of a showtime
+func waitForWatchers(t *testing.T, hub *ws.Hub, showtimeID uuid.UUID, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); hub.Subscribers(showtimeID.String()) != n; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d watchers, want %d", hub.Subscribers(showtimeID.String()), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchSeatUpdates(t *testing.T) {
+	server, hub, public, _ := seatUpdateServer(t)
+
+	conn, err := dialSeatUpdates(server, "/api/v1/showtimes/"+public.ID.String()+"/seat-updates", allowedOrigin)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	waitForWatchers(t, hub, public.ID, 1)
+
+	want := repository.SeatUpdate{SeatID: uuid.New(), Status: "LOCKED", UpdatedAt: time.Now().UTC().Truncate(time.Second)}
+	data, _ := json.Marshal(want)
+	hub.Broadcast(public.ID.String(), data)
+	// Updates to other showtimes are not sent
+	hub.Broadcast(uuid.NewString(), data)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var got repository.SeatUpdate
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if got.SeatID != want.SeatID || got.Status != want.Status || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("update = %+v, want %+v", got, want)
+	}
+
+	// Hanging up ends the subscription
+	conn.Close()
+	waitForWatchers(t, hub, public.ID, 0)
+}
+
+func TestWatchSeatUpdatesRejects(t *testing.T) {
+	server, _, public, private := seatUpdateServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		origin string
+	}{
+		{"origin not allowed", "/api/v1/showtimes/" + public.ID.String() + "/seat-updates", "https://evil.example"},
+		{"unknown showtime", "/api/v1/showtimes/" + uuid.NewString() + "/seat-updates", allowedOrigin},
+		{"bad ID", "/api/v1/showtimes/42/seat-updates", allowedOrigin},
+		{"private without its code", "/api/v1/showtimes/" + private.ID.String() + "/seat-updates", allowedOrigin},
+		{"private with a wrong code", "/api/v1/showtimes/" + private.ID.String() + "/seat-updates?access_code=guess", allowedOrigin},
+	}
+	for _, tt := range tests {
+		if conn, err := dialSeatUpdates(server, tt.path, tt.origin); err == nil {
+			conn.Close()
+			t.Errorf("%s: the upgrade succeeded", tt.name)
+		}
+	}
+
+	conn, err := dialSeatUpdates(server, "/api/v1/showtimes/"+private.ID.String()+"/seat-updates?access_code=letmein", allowedOrigin)
+	if err != nil {
+		t.Fatalf("private with its code: %v", err)
+	}
+	conn.Close()
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"strings"
+
+	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/app/showtime"
+	"cinemaos-backend/internal/middleware"
 	"cinemaos-backend/internal/pkg/response"
 	"cinemaos-backend/internal/pkg/validator"
 
@@ -11,23 +15,24 @@ import (
 
 // ShowtimeHandler handles showtime HTTP requests
 type ShowtimeHandler struct {
-	service   *showtime.Service
-	validator *validator.Validator
+	service      *showtime.Service
+	cinemaAccess *middleware.CinemaAccessMiddleware
+	validator    *validator.Validator
 }
 
 // NewShowtimeHandler creates a new showtime handler
-func NewShowtimeHandler(service *showtime.Service, validator *validator.Validator) *ShowtimeHandler {
+func NewShowtimeHandler(service *showtime.Service, cinemaAccess *middleware.CinemaAccessMiddleware, validator *validator.Validator) *ShowtimeHandler {
 	return &ShowtimeHandler{
-		service:   service,
-		validator: validator,
+		service:      service,
+		cinemaAccess: cinemaAccess,
+		validator:    validator,
 	}
 }
 
 // Create creates a new showtime
 func (h *ShowtimeHandler) Create(c *gin.Context) {
 	var req showtime.CreateShowtimeRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body: "+err.Error())
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -36,6 +41,11 @@ func (h *ShowtimeHandler) Create(c *gin.Context) {
 		return
 	}
 
+	// Only admins and the cinema's managers schedule its showtimes
+	if !h.cinemaAccess.Authorize(c, req.CinemaID, entity.RoleManager) {
+		return
+	}
+
 	res, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
 		// assuming service returns standard error, let response.Error handle it
@@ -46,6 +56,59 @@ func (h *ShowtimeHandler) Create(c *gin.Context) {
 	response.Created(c, res)
 }
 
+// BulkCreate schedules a movie on a screen at the same times every day of a
+// date range, skipping slots that clash with other shows
+func (h *ShowtimeHandler) BulkCreate(c *gin.Context) {
+	var req showtime.BulkCreateShowtimesRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if validationErrors := h.validator.Validate(req); validationErrors != nil {
+		response.ValidationError(c, validationErrors)
+		return
+	}
+
+	res, err := h.service.BulkCreate(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// GenerateSchedule plans a cinema's showtimes over a date range from the
+// movies and their daily show counts, and creates them unless dry_run is
+// set. Served at POST /showtimes/schedule and PUT /admin/schedule/generate.
+func (h *ShowtimeHandler) GenerateSchedule(c *gin.Context) {
+	var req showtime.GenerateScheduleRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if validationErrors := h.validator.Validate(req); validationErrors != nil {
+		response.ValidationError(c, validationErrors)
+		return
+	}
+
+	if !h.cinemaAccess.Authorize(c, req.CinemaID, entity.RoleManager) {
+		return
+	}
+
+	res, err := h.service.GenerateSchedule(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if req.DryRun {
+		response.Success(c, res)
+		return
+	}
+	response.Created(c, res)
+}
+
 // GetByID gets a showtime by ID
 func (h *ShowtimeHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -55,7 +118,7 @@ func (h *ShowtimeHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.GetByID(c.Request.Context(), id)
+	res, err := h.service.GetByID(c.Request.Context(), id, c.Query("access_code"))
 	if err != nil {
 		// response.Error handles mapping to 404 if it's a known error, 
 		// otherwise might return 500. For now relying on it.
@@ -84,6 +147,50 @@ func (h *ShowtimeHandler) List(c *gin.Context) {
 	response.Success(c, res)
 }
 
+// ListUnavailable lists upcoming showtimes whose movie or screen is no
+// longer available
+func (h *ShowtimeHandler) ListUnavailable(c *gin.Context) {
+	res, err := h.service.ListUnavailable(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// GetAvailability returns availability summaries for a batch of showtimes
+func (h *ShowtimeHandler) GetAvailability(c *gin.Context) {
+	// ids may be repeated or comma-separated
+	var ids []uuid.UUID
+	for _, param := range c.QueryArray("ids") {
+		for _, idStr := range strings.Split(param, ",") {
+			idStr = strings.TrimSpace(idStr)
+			if idStr == "" {
+				continue
+			}
+			id, err := uuid.Parse(idStr)
+			if err != nil {
+				response.BadRequest(c, "Invalid showtime ID")
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		response.BadRequest(c, "ids is required")
+		return
+	}
+
+	res, err := h.service.GetAvailability(c.Request.Context(), ids)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
 // Update updates a showtime
 func (h *ShowtimeHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -94,8 +201,7 @@ func (h *ShowtimeHandler) Update(c *gin.Context) {
 	}
 
 	var req showtime.UpdateShowtimeRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body: "+err.Error())
+	if !bindStrictJSON(c, &req) {
 		return
 	}
 
@@ -113,6 +219,79 @@ func (h *ShowtimeHandler) Update(c *gin.Context) {
 	response.Success(c, res)
 }
 
+// UpdateCapacity sets a showtime's capacity limit, distancing and visibility
+func (h *ShowtimeHandler) UpdateCapacity(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	var req showtime.UpdateCapacityRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if validationErrors := h.validator.Validate(req); validationErrors != nil {
+		response.ValidationError(c, validationErrors)
+		return
+	}
+
+	res, err := h.service.UpdateCapacity(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// GetSeatTypeRules returns a showtime's per-seat-type online sales rules
+func (h *ShowtimeHandler) GetSeatTypeRules(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	res, err := h.service.GetSeatTypeRules(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
+// UpdateSeatTypeRules replaces a showtime's per-seat-type online sales caps
+// and openings
+func (h *ShowtimeHandler) UpdateSeatTypeRules(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	var req showtime.UpdateSeatTypeRulesRequest
+	if !bindStrictJSON(c, &req) {
+		return
+	}
+
+	if validationErrors := h.validator.Validate(req); validationErrors != nil {
+		response.ValidationError(c, validationErrors)
+		return
+	}
+
+	res, err := h.service.UpdateSeatTypeRules(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
+
 // Delete deletes a showtime
 func (h *ShowtimeHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
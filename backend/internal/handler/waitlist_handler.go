@@ -0,0 +1,102 @@
+package handler
+
+import (
+	waitlistapp "cinemaos-backend/internal/app/waitlist"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/response"
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WaitlistHandler handles showtime waitlist HTTP requests
+type WaitlistHandler struct {
+	service   *waitlistapp.Service
+	validator *validator.Validator
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(service *waitlistapp.Service, validator *validator.Validator) *WaitlistHandler {
+	return &WaitlistHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Join godoc
+// @Summary Join showtime waitlist
+// @Description Wait for seats at a sold-out showtime. When a cancellation releases enough seats they are held for signed-in customers and the customer is emailed. Guests without an account give an email.
+// @Tags showtimes
+// @Accept json
+// @Produce json
+// @Param id path string true "Showtime ID"
+// @Param request body waitlistapp.JoinRequest true "Seats wanted"
+// @Success 201 {object} response.Response{data=waitlistapp.PositionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /showtimes/{id}/waitlist [post]
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	showtimeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	var req waitlistapp.JoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if errors := h.validator.Validate(req); errors != nil {
+		response.ValidationError(c, errors)
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, ok := middleware.GetUserID(c); ok {
+		userID = &id
+	}
+
+	res, err := h.service.Join(c.Request.Context(), userID, showtimeID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, res)
+}
+
+// Position godoc
+// @Summary Get waitlist position
+// @Description Get the current user's place on a showtime's waitlist
+// @Tags showtimes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Showtime ID"
+// @Success 200 {object} response.Response{data=waitlistapp.PositionResponse}
+// @Failure 404 {object} response.Response
+// @Router /showtimes/{id}/waitlist/position [get]
+func (h *WaitlistHandler) Position(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	showtimeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid showtime ID")
+		return
+	}
+
+	res, err := h.service.Position(c.Request.Context(), userID, showtimeID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, res)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"cinemaos-backend/internal/pkg/analytics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AnonymousIDMiddleware stores the client's anonymous ID in the request
+// context so server-side funnel events can be joined to the client's own.
+// Only UUIDs are accepted; anything else is ignored.
+func AnonymousIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if header := c.GetHeader(analytics.AnonymousIDHeader); header != "" {
+			if id, err := uuid.Parse(header); err == nil {
+				c.Request = c.Request.WithContext(analytics.WithAnonymousID(c.Request.Context(), id.String()))
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/apiversion"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIVersionMiddleware stores the API version in the request context.
+// The version comes from the route group unless the client overrides it
+// with the X-API-Version header.
+func APIVersionMiddleware(version apiversion.Version) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resolved := version
+		if header := c.GetHeader(apiversion.Header); header != "" {
+			v, ok := apiversion.Parse(header)
+			if !ok {
+				response.BadRequest(c, "Unsupported API version")
+				c.Abort()
+				return
+			}
+			resolved = v
+		}
+
+		c.Set(apiversion.ContextKey, resolved)
+		c.Request = c.Request.WithContext(apiversion.WithContext(c.Request.Context(), resolved))
+		c.Header(apiversion.Header, string(resolved))
+
+		c.Next()
+	}
+}
+
+// GetAPIVersion extracts the API version from context
+func GetAPIVersion(c *gin.Context) apiversion.Version {
+	return apiversion.FromGin(c)
+}
+
+type deprecationNotice struct {
+	deprecation string
+	sunset      string
+	link        string
+}
+
+// DeprecationMiddleware emits Deprecation (RFC 9745), Sunset (RFC 8594) and
+// Link headers on routes listed in the API deprecation config
+func DeprecationMiddleware(cfg config.APIConfig, log *logger.Logger) gin.HandlerFunc {
+	notices := make(map[string]deprecationNotice, len(cfg.Deprecations))
+
+	for _, d := range cfg.Deprecations {
+		var notice deprecationNotice
+
+		if d.DeprecatedAt != "" {
+			at, err := time.Parse("2006-01-02", d.DeprecatedAt)
+			if err != nil {
+				log.Warn("ignoring invalid deprecation date", zap.String("path", d.Path), zap.String("deprecated_at", d.DeprecatedAt))
+				continue
+			}
+			notice.deprecation = "@" + itoa(int(at.Unix()))
+		} else {
+			notice.deprecation = "true"
+		}
+
+		if d.Sunset != "" {
+			sunset, err := time.Parse("2006-01-02", d.Sunset)
+			if err != nil {
+				log.Warn("ignoring invalid sunset date", zap.String("path", d.Path), zap.String("sunset", d.Sunset))
+				continue
+			}
+			notice.sunset = sunset.UTC().Format(http.TimeFormat)
+		}
+
+		if d.Link != "" {
+			notice.link = "<" + d.Link + `>; rel="deprecation"`
+		}
+
+		notices[strings.ToUpper(d.Method)+" "+d.Path] = notice
+	}
+
+	return func(c *gin.Context) {
+		if notice, ok := notices[c.Request.Method+" "+c.FullPath()]; ok {
+			c.Header("Deprecation", notice.deprecation)
+			if notice.sunset != "" {
+				c.Header("Sunset", notice.sunset)
+			}
+			if notice.link != "" {
+				c.Header("Link", notice.link)
+			}
+		}
+
+		c.Next()
+	}
+}
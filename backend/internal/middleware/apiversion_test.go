@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/booking"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/apiversion"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func testHold() *booking.HoldResponse {
+	return &booking.HoldResponse{
+		HoldID:     "hold-1",
+		ShowtimeID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Seats: []booking.HeldSeatResponse{{
+			SeatID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			SeatLabel: "A1",
+			SeatType:  "STANDARD",
+			Price:     10.15,
+			Fare:      "STANDARD",
+		}},
+		Subtotal:  10.15,
+		Fee:       0.5,
+		Total:     10.65,
+		ExpiresAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// versionedRouter serves the same handlers under /api/v1 and /api/v2, the
+// way the router mounts the public API
+func versionedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	for _, v := range []apiversion.Version{apiversion.V1, apiversion.V2} {
+		group := r.Group("/api/"+string(v), APIVersionMiddleware(v))
+		group.GET("/hold", func(c *gin.Context) {
+			response.Success(c, testHold())
+		})
+		group.GET("/holds", func(c *gin.Context) {
+			response.Success(c, []*booking.HoldResponse{testHold()})
+		})
+		group.GET("/page", func(c *gin.Context) {
+			response.Paginated(c, []string{}, response.GetPagination(c), 45)
+		})
+	}
+	return r
+}
+
+func serve(t *testing.T, r http.Handler, path, header string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if header != "" {
+		req.Header.Set(apiversion.Header, header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// v1Hold is testHold as v1 serializes it. v1 clients parse it as it is, so
+// it must not change byte for byte.
+const v1Hold = `{"hold_id":"hold-1",` +
+	`"showtime_id":"11111111-1111-1111-1111-111111111111",` +
+	`"seats":[{"seat_id":"22222222-2222-2222-2222-222222222222","seat_label":"A1","seat_type":"STANDARD","price":10.15,"fare":"STANDARD"}],` +
+	`"subtotal":10.15,"fee":0.5,"total":10.65,"expires_at":"2026-10-16T12:00:00Z"}`
+
+const v2Hold = `{"hold_id":"hold-1",` +
+	`"showtime_id":"11111111-1111-1111-1111-111111111111",` +
+	`"seats":[{"seat_id":"22222222-2222-2222-2222-222222222222","seat_label":"A1","seat_type":"STANDARD","price_cents":1015,"fare":"STANDARD"}],` +
+	`"subtotal_cents":1015,"fee_cents":50,"total_cents":1065,"expires_at":"2026-10-16T12:00:00Z"}`
+
+func TestAPIVersionCompatibility(t *testing.T) {
+	r := versionedRouter()
+	body := func(data string) string { return `{"success":true,"data":` + data + `}` }
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantHeader string
+		wantBody   string
+	}{
+		{"v1 path", "/api/v1/hold", "", http.StatusOK, "v1", body(v1Hold)},
+		{"v2 path", "/api/v2/hold", "", http.StatusOK, "v2", body(v2Hold)},
+		{"v1 path, v2 header", "/api/v1/hold", "v2", http.StatusOK, "v2", body(v2Hold)},
+		{"v2 path, v1 header", "/api/v2/hold", "1", http.StatusOK, "v1", body(v1Hold)},
+		{"v2 path, upper case header", "/api/v2/hold", "V2", http.StatusOK, "v2", body(v2Hold)},
+		{"v1 list", "/api/v1/holds", "", http.StatusOK, "v1", body("[" + v1Hold + "]")},
+		{"v2 list", "/api/v2/holds", "", http.StatusOK, "v2", body("[" + v2Hold + "]")},
+		{"unknown header", "/api/v1/hold", "v3", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, r, tt.path, tt.header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if got := w.Header().Get(apiversion.Header); got != tt.wantHeader {
+				t.Errorf("%s = %q, want %q", apiversion.Header, got, tt.wantHeader)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body =\n%s\nwant\n%s", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAPIVersionPagination(t *testing.T) {
+	r := versionedRouter()
+
+	var page struct {
+		Meta response.Meta `json:"meta"`
+	}
+	decode := func(w *httptest.ResponseRecorder) response.Meta {
+		t.Helper()
+		page.Meta = response.Meta{}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode %s: %v", w.Body, err)
+		}
+		return page.Meta
+	}
+
+	v1 := decode(serve(t, r, "/api/v1/page?page=2&limit=20", ""))
+	if v1 != (response.Meta{Page: 2, Limit: 20, Total: 45, TotalPages: 3}) {
+		t.Errorf("v1 meta = %+v", v1)
+	}
+
+	first := decode(serve(t, r, "/api/v2/page?limit=20", ""))
+	if first.Page != 0 || first.Total != 0 || first.NextCursor == "" {
+		t.Fatalf("v2 first page meta = %+v", first)
+	}
+	second := decode(serve(t, r, "/api/v2/page?cursor="+first.NextCursor, ""))
+	if second.NextCursor == "" {
+		t.Fatalf("v2 second page has no cursor: %+v", second)
+	}
+	last := decode(serve(t, r, "/api/v2/page?cursor="+second.NextCursor, ""))
+	if last.NextCursor != "" {
+		t.Errorf("v2 last page has a cursor: %+v", last)
+	}
+
+	// v1 ignores a cursor and keeps paging by number
+	ignored := decode(serve(t, r, "/api/v1/page?cursor="+first.NextCursor, ""))
+	if ignored.Page != 1 {
+		t.Errorf("v1 with a cursor is on page %d, want 1", ignored.Page)
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(DeprecationMiddleware(config.APIConfig{Deprecations: []config.RouteDeprecation{
+		{Method: "get", Path: "/api/v1/movies/:id", DeprecatedAt: "2026-01-01", Sunset: "2026-12-31", Link: "https://docs.example.com/v2"},
+		{Method: "GET", Path: "/api/v1/cinemas", Sunset: "31/12/2026"},
+	}}, &logger.Logger{Logger: zap.NewNop()}))
+	for _, path := range []string{"/api/v1/movies/:id", "/api/v2/movies/:id", "/api/v1/cinemas"} {
+		r.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	w := serve(t, r, "/api/v1/movies/42", "")
+	if got := w.Header().Get("Deprecation"); got != "@1767225600" {
+		t.Errorf("Deprecation = %q, want @1767225600", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Thu, 31 Dec 2026 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://docs.example.com/v2>; rel="deprecation"` {
+		t.Errorf("Link = %q", got)
+	}
+
+	// Only the listed route and method carry the notice, and an entry
+	// with an invalid date is left out
+	for _, path := range []string{"/api/v2/movies/42", "/api/v1/cinemas"} {
+		if got := serve(t, r, path, "").Header().Get("Deprecation"); got != "" {
+			t.Errorf("%s: Deprecation = %q, want none", path, got)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want apiversion.Version
+		ok   bool
+	}{
+		{"v1", apiversion.V1, true},
+		{"V2", apiversion.V2, true},
+		{"2", apiversion.V2, true},
+		{" v1 ", apiversion.V1, true},
+		{"v3", "", false},
+		{"", "", false},
+		{"version2", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := apiversion.Parse(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCents(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int64
+	}{
+		{10.15, 1015},
+		{0.1 + 0.2, 30},
+		{19.999, 2000},
+		{-4.35, -435},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := apiversion.Cents(tt.in); got != tt.want {
+			t.Errorf("Cents(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
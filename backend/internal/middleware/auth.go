@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 	"time"
 
 	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/pkg/changes"
 	"cinemaos-backend/internal/pkg/logger"
 	"cinemaos-backend/internal/pkg/response"
 
@@ -23,18 +25,23 @@ const (
 	UserEmailKey = "user_email"
 	// UserRoleKey is the context key for user role
 	UserRoleKey = "user_role"
+	// SessionIDKey is the context key for the session of the access token
+	SessionIDKey = "session_id"
 )
 
-// AuthMiddleware handles JWT authentication
+// AuthMiddleware handles JWT authentication. Signed-in requests are also
+// counted against the user's rate limit once the token is verified.
 type AuthMiddleware struct {
 	jwtManager *authinfra.JWTManager
+	users      *UserRateLimiter // nil leaves users unlimited
 	logger     *logger.Logger
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(jwtManager *authinfra.JWTManager, logger *logger.Logger) *AuthMiddleware {
+func NewAuthMiddleware(jwtManager *authinfra.JWTManager, users *UserRateLimiter, logger *logger.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtManager: jwtManager,
+		users:      users,
 		logger:     logger,
 	}
 }
@@ -67,6 +74,16 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserEmailKey, claims.Email)
 		c.Set(UserRoleKey, claims.Role)
+		c.Set(SessionIDKey, claims.SessionID)
+
+		// Attribute changes made during the request to the user
+		if userID, err := uuid.Parse(claims.UserID); err == nil {
+			c.Request = c.Request.WithContext(changes.WithActor(c.Request.Context(), changes.User(userID)))
+		}
+
+		if !m.users.limit(c) {
+			return
+		}
 
 		c.Next()
 	}
@@ -97,6 +114,11 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserEmailKey, claims.Email)
 		c.Set(UserRoleKey, claims.Role)
+		c.Set(SessionIDKey, claims.SessionID)
+
+		if !m.users.limit(c) {
+			return
+		}
 
 		c.Next()
 	}
@@ -130,6 +152,11 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return m.RequireRole("ADMIN", "MANAGER")
 }
 
+// RequireStaff requires a cinema staff, manager or admin role
+func (m *AuthMiddleware) RequireStaff() gin.HandlerFunc {
+	return m.RequireRole("ADMIN", "MANAGER", "STAFF")
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(c *gin.Context) (uuid.UUID, bool) {
 	userID, exists := c.Get(UserIDKey)
@@ -145,6 +172,22 @@ func GetUserID(c *gin.Context) (uuid.UUID, bool) {
 	return id, true
 }
 
+// GetSessionID extracts the session ID of the access token from context.
+// Tokens issued before sessions were tracked have none.
+func GetSessionID(c *gin.Context) (uuid.UUID, bool) {
+	sessionID, exists := c.Get(SessionIDKey)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(sessionID.(string))
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
 // GetUserEmail extracts user email from context
 func GetUserEmail(c *gin.Context) string {
 	if email, exists := c.Get(UserEmailKey); exists {
@@ -161,6 +204,12 @@ func GetUserRole(c *gin.Context) string {
 	return ""
 }
 
+// IsAdmin returns true if the caller is authenticated as an admin or manager
+func IsAdmin(c *gin.Context) bool {
+	role := GetUserRole(c)
+	return role == "ADMIN" || role == "MANAGER"
+}
+
 // RequestIDMiddleware adds a unique request ID to each request
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -171,6 +220,8 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		
 		c.Set("request_id", requestID)
 		c.Writer.Header().Set("X-Request-ID", requestID)
+		// Expose the ID to code that only sees the request context
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID))
 		
 		c.Next()
 	}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinemaos-backend/internal/app/entity"
+	"cinemaos-backend/internal/app/repository"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memStaffRoles keeps users' roles per cinema
+type memStaffRoles struct {
+	repository.CinemaStaffRepository
+	roles map[[2]uuid.UUID]entity.Role // cinema and user
+}
+
+func (m *memStaffRoles) GetRole(_ context.Context, cinemaID, userID uuid.UUID) (entity.Role, error) {
+	return m.roles[[2]uuid.UUID{cinemaID, userID}], nil
+}
+
+// memShowtimeCinemas serves showtimes with only their cinema set
+type memShowtimeCinemas struct {
+	repository.ShowtimeRepository
+	cinemas map[uuid.UUID]uuid.UUID // showtime to cinema
+	reads   int
+}
+
+func (m *memShowtimeCinemas) GetByID(_ context.Context, id uuid.UUID) (*entity.Showtime, error) {
+	m.reads++
+	cinemaID, ok := m.cinemas[id]
+	if !ok {
+		return nil, apperrors.New(apperrors.CodeShowtimeNotFound, "showtime not found")
+	}
+	return &entity.Showtime{ID: id, CinemaID: cinemaID}, nil
+}
+
+func TestCinemaAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cinema, otherCinema := uuid.New(), uuid.New()
+	showtime, otherShowtime := uuid.New(), uuid.New()
+	manager, staff := uuid.New(), uuid.New()
+	staffRoles := &memStaffRoles{roles: map[[2]uuid.UUID]entity.Role{
+		{cinema, manager}:    entity.RoleManager,
+		{cinema, staff}:      entity.RoleStaff,
+		{otherCinema, staff}: entity.RoleManager,
+	}}
+	showtimes := &memShowtimeCinemas{cinemas: map[uuid.UUID]uuid.UUID{showtime: cinema, otherShowtime: otherCinema}}
+	access := NewCinemaAccessMiddleware(staffRoles, showtimes, &logger.Logger{Logger: zap.NewNop()})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// Stands in for AuthMiddleware: the caller and account role come from headers
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set(UserIDKey, id)
+			c.Set(UserRoleKey, c.GetHeader("X-Test-Role"))
+		}
+	})
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.PUT("/cinemas/:id", access.RequireCinemaRole("id", entity.RoleManager), ok)
+	router.PUT("/showtimes/:id", access.RequireShowtimeCinemaRole("id", entity.RoleManager), ok)
+	router.GET("/cinemas/:id/bookings", access.RequireCinemaRole("id", entity.RoleManager, entity.RoleStaff), ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		user   uuid.UUID
+		role   entity.Role // account role
+		want   int
+	}{
+		{"manager of the cinema", http.MethodPut, "/cinemas/" + cinema.String(), manager, entity.RoleManager, http.StatusOK},
+		{"manager of another cinema", http.MethodPut, "/cinemas/" + otherCinema.String(), manager, entity.RoleManager, http.StatusForbidden},
+		{"staff at the cinema", http.MethodPut, "/cinemas/" + cinema.String(), staff, entity.RoleStaff, http.StatusForbidden},
+		{"staff on a staff route", http.MethodGet, "/cinemas/" + cinema.String() + "/bookings", staff, entity.RoleStaff, http.StatusOK},
+		{"role at the cinema, not the account's", http.MethodPut, "/cinemas/" + otherCinema.String(), staff, entity.RoleStaff, http.StatusOK},
+		{"admin anywhere", http.MethodPut, "/cinemas/" + otherCinema.String(), uuid.New(), entity.RoleAdmin, http.StatusOK},
+		{"malformed cinema", http.MethodPut, "/cinemas/nope", manager, entity.RoleManager, http.StatusBadRequest},
+		{"showtime of the cinema", http.MethodPut, "/showtimes/" + showtime.String(), manager, entity.RoleManager, http.StatusOK},
+		{"showtime of another cinema", http.MethodPut, "/showtimes/" + otherShowtime.String(), manager, entity.RoleManager, http.StatusForbidden},
+		{"missing showtime", http.MethodPut, "/showtimes/" + uuid.NewString(), manager, entity.RoleManager, http.StatusNotFound},
+		{"signed out", http.MethodPut, "/cinemas/" + cinema.String(), uuid.Nil, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.user != uuid.Nil {
+			req.Header.Set("X-Test-User", tt.user.String())
+			req.Header.Set("X-Test-Role", string(tt.role))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+
+	// Admins are let through without looking the showtime up
+	reads := showtimes.reads
+	req := httptest.NewRequest(http.MethodPut, "/showtimes/"+uuid.NewString(), nil)
+	req.Header.Set("X-Test-User", uuid.NewString())
+	req.Header.Set("X-Test-Role", string(entity.RoleAdmin))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || showtimes.reads != reads {
+		t.Errorf("admin: status %d after %d showtime reads, want 200 after none", rec.Code, showtimes.reads-reads)
+	}
+}
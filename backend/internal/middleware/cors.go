@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"net/http"
-	"time"
 
 	"cinemaos-backend/internal/config"
 
@@ -50,74 +49,6 @@ func CORSMiddleware(cfg config.CORSConfig) gin.HandlerFunc {
 	}
 }
 
-// RateLimitMiddleware provides basic rate limiting
-type RateLimiter struct {
-	requests map[string][]time.Time
-	limit    int
-	window   time.Duration
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	return &RateLimiter{
-		requests: make(map[string][]time.Time),
-		limit:    limit,
-		window:   window,
-	}
-}
-
-// RateLimit returns a rate limiting middleware
-func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		now := time.Now()
-
-		// Clean old requests
-		if timestamps, exists := rl.requests[clientIP]; exists {
-			var valid []time.Time
-			for _, t := range timestamps {
-				if now.Sub(t) < rl.window {
-					valid = append(valid, t)
-				}
-			}
-			rl.requests[clientIP] = valid
-		}
-
-		// Check limit
-		if len(rl.requests[clientIP]) >= rl.limit {
-			c.Header("Retry-After", itoa(int(rl.window.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "TOO_MANY_REQUESTS",
-					"message": "Rate limit exceeded. Please try again later.",
-				},
-			})
-			return
-		}
-
-		// Record request
-		rl.requests[clientIP] = append(rl.requests[clientIP], now)
-
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", itoa(rl.limit))
-		c.Header("X-RateLimit-Remaining", itoa(rl.limit-len(rl.requests[clientIP])))
-
-		c.Next()
-	}
-}
-
-// TimeoutMiddleware adds request timeout
-func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Note: For production, consider using context with timeout
-		// ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
-		// defer cancel()
-		// c.Request = c.Request.WithContext(ctx)
-		c.Next()
-	}
-}
-
 // SecureHeadersMiddleware adds security headers
 func SecureHeadersMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
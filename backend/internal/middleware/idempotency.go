@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a mutating request
+	IdempotencyKeyHeader = "X-Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed for a retry
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// KeyValueStore stores values under keys that expire, shared between
+// instances
+type KeyValueStore interface {
+	// Claim stores value under key unless the key is taken, in which case
+	// it returns the value stored by whoever took it
+	Claim(ctx context.Context, key string, value []byte, ttl time.Duration) (stored []byte, claimed bool, err error)
+
+	// Set overwrites the value stored under key
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete releases key
+	Delete(ctx context.Context, key string) error
+}
+
+// idempotentRequest is what is stored under an idempotency key: what the
+// first request with it was and, once that succeeded, its response
+type idempotentRequest struct {
+	Fingerprint string `json:"fingerprint"` // hash of the method, route and body
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency makes mutating routes safe to retry. A request with an
+// X-Idempotency-Key header claims the key while it runs; a successful
+// response is stored under the key and replayed, without running the
+// handler again, to later requests with the same key. A request sent while
+// the first is still running gets 409, and a key reused for another
+// request gets 422. Keys belong to the user who sent them, or to the client
+// IP for anonymous requests, so two users picking the same key never see
+// each other's requests. Failed responses release the key so the request
+// can be retried. Requests without the header are not affected,
+// nor is anything while the store is unavailable.
+type Idempotency struct {
+	store  KeyValueStore // nil without Redis
+	cfg    config.IdempotencyConfig
+	logger *logger.Logger
+}
+
+// NewIdempotency creates the idempotency key middleware
+func NewIdempotency(store KeyValueStore, cfg config.IdempotencyConfig, log *logger.Logger) *Idempotency {
+	return &Idempotency{
+		store:  store,
+		cfg:    cfg,
+		logger: log,
+	}
+}
+
+// Idempotent returns the middleware for one route. It runs after
+// authentication, so keys are scoped to the signed-in user.
+func (m *Idempotency) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" || m.store == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.BadRequest(c, "Idempotency key is too long")
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		log := m.logger.WithContext(ctx)
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, "Invalid request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key = requestOwner(c) + ":" + key
+		req := idempotentRequest{Fingerprint: fingerprint(c, body)}
+		pending, err := json.Marshal(req)
+		if err != nil {
+			log.Error("failed to encode idempotency key", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		stored, claimed, err := m.store.Claim(ctx, key, pending, m.cfg.InFlightTTL)
+		if err != nil {
+			if apperrors.Is(err, apperrors.CodeConflict) {
+				response.Error(c, err)
+				c.Abort()
+				return
+			}
+			log.Warn("idempotency key store unavailable, running request without it", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !claimed {
+			m.replay(c, req, stored)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The outcome is stored even when the client has gone away; that
+		// is the retry the key is for
+		ctx = context.WithoutCancel(ctx)
+		status := writer.Status()
+		if status < 200 || status >= 300 {
+			if err := m.store.Delete(ctx, key); err != nil {
+				log.Warn("failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		req.Done = true
+		req.Status = status
+		req.ContentType = writer.Header().Get("Content-Type")
+		req.Body = writer.body.Bytes()
+		done, err := json.Marshal(req)
+		if err == nil {
+			err = m.store.Set(ctx, key, done, m.cfg.TTL)
+		}
+		if err != nil {
+			log.Error("failed to store idempotent response", zap.Int("status", status), zap.Error(err))
+		}
+	}
+}
+
+// replay answers a request whose key was claimed before
+func (m *Idempotency) replay(c *gin.Context, req idempotentRequest, stored []byte) {
+	var first idempotentRequest
+	if err := json.Unmarshal(stored, &first); err != nil {
+		m.logger.WithContext(c.Request.Context()).Error("failed to decode idempotency key", zap.Error(err))
+		response.Error(c, apperrors.ErrInternal("failed to read idempotency key"))
+		return
+	}
+
+	// The stored response is never shown for a different request that
+	// happens to reuse the key
+	if first.Fingerprint != req.Fingerprint {
+		response.Error(c, apperrors.New(apperrors.CodeFailedPrecondition,
+			"idempotency key was already used for a different request"))
+		return
+	}
+	if !first.Done {
+		response.Error(c, apperrors.ErrConflict("a request with this idempotency key is still in progress"))
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(first.Status, first.ContentType, first.Body)
+}
+
+// requestOwner identifies who sent a request
+func requestOwner(c *gin.Context) string {
+	if userID, ok := GetUserID(c); ok {
+		return "user:" + userID.String()
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// fingerprint hashes what a retry must repeat to be replayed
+func fingerprint(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body as it is written
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
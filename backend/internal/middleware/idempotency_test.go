@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// memKeyValues is an in-memory KeyValueStore; down makes every call fail
+// the way an unreachable Redis does
+type memKeyValues struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	down   bool
+}
+
+var errStoreDown = errors.New("store is down")
+
+func (m *memKeyValues) Claim(_ context.Context, key string, value []byte, _ time.Duration) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return nil, false, errStoreDown
+	}
+	if stored, ok := m.values[key]; ok {
+		return stored, false, nil
+	}
+	m.values[key] = value
+	return nil, true, nil
+}
+
+func (m *memKeyValues) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memKeyValues) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+// idempotentRouter serves POST /bookings/confirm behind the middleware.
+// The handler answers with the status in the body's "status" field, and
+// waits for release when it is given.
+type idempotentRouter struct {
+	*gin.Engine
+	store   *memKeyValues
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func newIdempotentRouter() *idempotentRouter {
+	gin.SetMode(gin.TestMode)
+	r := &idempotentRouter{Engine: gin.New(), store: &memKeyValues{values: map[string][]byte{}}}
+	idempotency := NewIdempotency(r.store, config.IdempotencyConfig{TTL: time.Hour, InFlightTTL: time.Minute},
+		&logger.Logger{Logger: zap.NewNop()})
+
+	r.Use(func(c *gin.Context) {
+		// Stands in for AuthMiddleware
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set(UserIDKey, id)
+		}
+	})
+	r.POST("/bookings/confirm", idempotency.Idempotent(), func(c *gin.Context) {
+		n := r.calls.Add(1)
+		if r.release != nil {
+			<-r.release
+		}
+		var req struct {
+			Status int `json:"status"`
+		}
+		c.ShouldBindJSON(&req)
+		if req.Status == 0 {
+			req.Status = http.StatusCreated
+		}
+		c.JSON(req.Status, gin.H{"call": n})
+	})
+	return r
+}
+
+// confirm sends a confirm request with the idempotency key
+func (r *idempotentRouter) confirm(key, user, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings/confirm", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+const (
+	alice = "11111111-1111-1111-1111-111111111111"
+	bob   = "22222222-2222-2222-2222-222222222222"
+)
+
+func TestIdempotentReplay(t *testing.T) {
+	r := newIdempotentRouter()
+
+	first := r.confirm("key-1", alice, `{"hold_id":"h1"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("first request: %d, replayed %q", first.Code, first.Header().Get(IdempotentReplayedHeader))
+	}
+
+	retry := r.confirm("key-1", alice, `{"hold_id":"h1"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want the first response %s", retry.Code, retry.Body, first.Body)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("retry headers = %v", retry.Header())
+	}
+	if n := r.calls.Load(); n != 1 {
+		t.Errorf("handler ran %d times, want once", n)
+	}
+
+	// Requests without a key run every time
+	r.confirm("", alice, `{"hold_id":"h1"}`)
+	r.confirm("", alice, `{"hold_id":"h1"}`)
+	if n := r.calls.Load(); n != 3 {
+		t.Errorf("handler ran %d times, want 3", n)
+	}
+}
+
+func TestIdempotentInFlight(t *testing.T) {
+	r := newIdempotentRouter()
+	r.release = make(chan struct{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- r.confirm("key-1", alice, `{}`) }()
+	for r.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if rec := r.confirm("key-1", alice, `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("request while the first runs = %d, want 409", rec.Code)
+	}
+	close(r.release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Fatalf("first request = %d", rec.Code)
+	}
+	if rec := r.confirm("key-1", alice, `{}`); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("retry after the first finished = %d, replayed %q", rec.Code, rec.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func TestIdempotentMismatch(t *testing.T) {
+	r := newIdempotentRouter()
+	r.confirm("key-1", alice, `{"hold_id":"h1"}`)
+
+	rec := r.confirm("key-1", alice, `{"hold_id":"h2"}`)
+	if rec.Code != http.StatusUnprocessableEntity || strings.Contains(rec.Body.String(), `"call"`) {
+		t.Errorf("another body: %d %s, want 422 without the stored response", rec.Code, rec.Body)
+	}
+	if n := r.calls.Load(); n != 1 {
+		t.Errorf("handler ran %d times, want once", n)
+	}
+}
+
+func TestIdempotentKeysArePerUser(t *testing.T) {
+	r := newIdempotentRouter()
+	first := r.confirm("key-1", alice, `{"hold_id":"h1"}`)
+
+	// Others who pick the same key run their own request, and never get
+	// the first user's response
+	for i, user := range []string{bob, ""} {
+		rec := r.confirm("key-1", user, `{"hold_id":"h1"}`)
+		if rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" || rec.Body.String() == first.Body.String() {
+			t.Errorf("user %q: %d %s, replayed %q, want a request of their own", user, rec.Code, rec.Body, rec.Header().Get(IdempotentReplayedHeader))
+		}
+		if n := r.calls.Load(); n != int32(i+2) {
+			t.Errorf("handler ran %d times, want %d", n, i+2)
+		}
+	}
+
+	// Each still gets their own response on a retry
+	if rec := r.confirm("key-1", bob, `{"hold_id":"h1"}`); rec.Header().Get(IdempotentReplayedHeader) != "true" || !strings.Contains(rec.Body.String(), `"call":2`) {
+		t.Errorf("retry by the second user = %s, replayed %q", rec.Body, rec.Header().Get(IdempotentReplayedHeader))
+	}
+	if rec := r.confirm("key-1", alice, `{"hold_id":"h1"}`); rec.Body.String() != first.Body.String() {
+		t.Errorf("retry by the first user = %s, want %s", rec.Body, first.Body)
+	}
+}
+
+func TestIdempotentFailureReleasesKey(t *testing.T) {
+	r := newIdempotentRouter()
+
+	if rec := r.confirm("key-1", alice, `{"status":409}`); rec.Code != http.StatusConflict {
+		t.Fatalf("failing request = %d", rec.Code)
+	}
+	// The same request runs again rather than replaying the failure
+	if rec := r.confirm("key-1", alice, `{"status":409}`); rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("a failed response was replayed")
+	}
+	if n := r.calls.Load(); n != 2 {
+		t.Errorf("handler ran %d times, want twice", n)
+	}
+}
+
+func TestIdempotentStoreDown(t *testing.T) {
+	r := newIdempotentRouter()
+	r.store.down = true
+	for range 2 {
+		if rec := r.confirm("key-1", alice, `{}`); rec.Code != http.StatusCreated {
+			t.Fatalf("request with the store down = %d", rec.Code)
+		}
+	}
+	if n := r.calls.Load(); n != 2 {
+		t.Errorf("handler ran %d times, want twice without the store", n)
+	}
+
+	if rec := r.confirm(strings.Repeat("k", maxIdempotencyKeyLength+1), alice, `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("overlong key = %d, want 400", rec.Code)
+	}
+}
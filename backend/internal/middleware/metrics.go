@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"time"
+
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so probes for
+// random paths do not each get their own series
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records the count and latency of each request by
+// method, route pattern and status
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.RecordRequest(c.Request.Context(), c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cinemaos-backend/internal/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	m, err := metrics.New(metrics.Config{Enabled: true, ServiceName: "cinemaos-test"}, metrics.Gauges{})
+	if err != nil {
+		t.Fatalf("metrics.New: %v", err)
+	}
+	t.Cleanup(func() { m.Shutdown(context.Background()) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(MetricsMiddleware(m))
+	r.GET("/api/v1/movies/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/movies/1", "/api/v1/movies/2", "/wp-login.php", "/.env"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	// Requests are labelled by route pattern, and probes share one series
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/v1/movies/:id",status="200"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 2`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("scrape lacks %s", want)
+		}
+	}
+	if strings.Contains(string(body), "wp-login") {
+		t.Error("a raw path became a label")
+	}
+}
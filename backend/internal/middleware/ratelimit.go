@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// failureLogInterval spaces out warnings while the shared limiter is down
+const failureLogInterval = time.Minute
+
+// errNoSharedLimiter is logged when the server runs without Redis
+var errNoSharedLimiter = errors.New("no shared limiter configured")
+
+// WindowLimiter counts requests per key against a limit per window, in a
+// sliding window or a token bucket refilled over the window
+type WindowLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, retryAfter time.Duration, err error)
+}
+
+// RateLimiter limits requests per client IP in a sliding window; signed-in
+// users are limited per account by UserRateLimiter. Counts are kept by a
+// shared limiter so every instance enforces the same budget; while it is
+// missing or failing, requests are let through with a warning rather than
+// limited by counts no other instance sees. Routes and groups of routes
+// may have their own budget, counted apart from the default one.
+type RateLimiter struct {
+	shared   WindowLimiter // nil without Redis
+	enabled  bool
+	defaults config.LimitConfig
+	routes   map[string]config.LimitConfig
+	groups   map[string]config.LimitConfig // keyed on the prefix, e.g. /auth/
+	logger   *logger.Logger
+
+	lastFailureLog atomic.Int64
+}
+
+// NewRateLimiter creates a rate limiter. Route overrides are keyed on the
+// route pattern below the API version, e.g. /movies/:id, or on a group of
+// routes, e.g. /movies/*.
+func NewRateLimiter(shared WindowLimiter, cfg config.RateLimitConfig, log *logger.Logger) *RateLimiter {
+	routes := make(map[string]config.LimitConfig, len(cfg.Routes))
+	groups := make(map[string]config.LimitConfig)
+	for pattern, limit := range cfg.Routes {
+		// Config keys arrive lower-cased
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			groups[prefix] = limit
+			continue
+		}
+		routes[pattern] = limit
+	}
+	return &RateLimiter{
+		shared:   shared,
+		enabled:  cfg.Enabled,
+		defaults: cfg.LimitConfig,
+		routes:   routes,
+		groups:   groups,
+		logger:   log,
+	}
+}
+
+// RateLimit returns a rate limiting middleware
+func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.enabled {
+			c.Next()
+			return
+		}
+
+		limit, bucket := rl.limitFor(routePattern(c.FullPath()))
+		if limit.Limit <= 0 || limit.Window <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, retryAfter := rl.allow(c.Request.Context(), bucket+":"+c.ClientIP(), limit)
+
+		c.Header("X-RateLimit-Limit", itoa(limit.Limit))
+		c.Header("X-RateLimit-Remaining", itoa(remaining))
+		if !allowed {
+			c.Header("Retry-After", itoa(max(int(retryAfter.Round(time.Second).Seconds()), 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "TOO_MANY_REQUESTS",
+					"message": "Rate limit exceeded. Please try again later.",
+				},
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// limitFor returns the budget of a route and the bucket it is counted in:
+// the route's own, else the longest group containing it, else the default
+func (rl *RateLimiter) limitFor(route string) (config.LimitConfig, string) {
+	if route == "" {
+		return rl.defaults, "default"
+	}
+	if limit, ok := rl.routes[route]; ok {
+		return limit, route
+	}
+
+	var group string
+	for prefix := range rl.groups {
+		if strings.HasPrefix(route, prefix) && len(prefix) > len(group) {
+			group = prefix
+		}
+	}
+	if group != "" {
+		return rl.groups[group], group + "*"
+	}
+	return rl.defaults, "default"
+}
+
+// allow checks the shared limiter, letting the request through when it is
+// unavailable
+func (rl *RateLimiter) allow(ctx context.Context, key string, limit config.LimitConfig) (bool, int, time.Duration) {
+	if rl.shared == nil {
+		rl.warnUnlimited(errNoSharedLimiter)
+		return true, limit.Limit, 0
+	}
+	allowed, remaining, retryAfter, err := rl.shared.Allow(ctx, key, limit.Limit, limit.Window)
+	if err != nil {
+		rl.warnUnlimited(err)
+		return true, limit.Limit, 0
+	}
+	return allowed, remaining, retryAfter
+}
+
+// warnUnlimited logs, at most once per failureLogInterval, that requests
+// go through unlimited
+func (rl *RateLimiter) warnUnlimited(err error) {
+	now := time.Now().UnixNano()
+	last := rl.lastFailureLog.Load()
+	if now-last >= int64(failureLogInterval) && rl.lastFailureLog.CompareAndSwap(last, now) {
+		rl.logger.Warn("shared rate limiter unavailable, requests are not rate limited", zap.Error(err))
+	}
+}
+
+// routePattern returns a gin route pattern without its /api/vN prefix,
+// lower-cased to match config keys
+func routePattern(fullPath string) string {
+	if rest, ok := strings.CutPrefix(fullPath, "/api/"); ok {
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			return strings.ToLower(rest[i:])
+		}
+	}
+	return strings.ToLower(fullPath)
+}
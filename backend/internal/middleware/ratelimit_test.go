@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// countingLimiter is a shared limiter that admits a fixed number of
+// requests per key, or fails when down
+type countingLimiter struct {
+	seen map[string]int
+	down bool
+}
+
+func (l *countingLimiter) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, int, time.Duration, error) {
+	if l.down {
+		return false, 0, 0, errors.New("connection refused")
+	}
+	if l.seen[key] >= limit {
+		return false, 0, window, nil
+	}
+	l.seen[key]++
+	return true, limit - l.seen[key], 0, nil
+}
+
+func rateLimitedRouter(shared WindowLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	limiter := NewRateLimiter(shared, config.RateLimitConfig{
+		Enabled:     true,
+		LimitConfig: config.LimitConfig{Limit: 2, Window: time.Minute},
+		Routes: map[string]config.LimitConfig{
+			"/Analytics/Events":     {Limit: 4, Window: time.Minute},
+			"/auth/*":               {Limit: 3, Window: time.Minute},
+			"/auth/admin/*":         {Limit: 1, Window: time.Minute},
+			"/auth/forgot-password": {Limit: 1, Window: time.Minute},
+			"/bookings/hold":        {Limit: 1, Window: time.Minute},
+		},
+	}, &logger.Logger{Logger: zap.NewNop()})
+
+	api := r.Group("/api/v1", limiter.RateLimit())
+	for _, path := range []string{"/movies", "/cinemas", "/analytics/events", "/auth/login", "/auth/refresh",
+		"/auth/forgot-password", "/auth/admin/users", "/bookings/hold"} {
+		api.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return r
+}
+
+func TestRateLimitBudgets(t *testing.T) {
+	shared := &countingLimiter{seen: make(map[string]int)}
+	r := rateLimitedRouter(shared)
+
+	// The default budget is shared by every route without an override
+	for i, path := range []string{"/api/v1/movies", "/api/v1/cinemas"} {
+		w := serve(t, r, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != itoa(1-i) {
+			t.Errorf("request %d: remaining %s, want %d", i, got, 1-i)
+		}
+	}
+	w := serve(t, r, "/api/v1/movies", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request: status %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want 60", got)
+	}
+
+	// An overridden route is counted apart, whatever the key's case
+	for i := range 4 {
+		if w := serve(t, r, "/api/v1/analytics/events", ""); w.Code != http.StatusOK {
+			t.Fatalf("analytics request %d: status %d", i, w.Code)
+		}
+	}
+	if w := serve(t, r, "/api/v1/analytics/events", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("fifth analytics request: status %d, want 429", w.Code)
+	}
+	if _, ok := shared.seen["/analytics/events:192.0.2.1"]; !ok {
+		t.Errorf("keys = %v, want the route's own bucket", shared.seen)
+	}
+}
+
+func TestRateLimitRouteGroups(t *testing.T) {
+	shared := &countingLimiter{seen: make(map[string]int)}
+	r := rateLimitedRouter(shared)
+
+	// Every route below /auth/ shares the group's budget
+	for i, path := range []string{"/api/v1/auth/login", "/api/v1/auth/refresh", "/api/v1/auth/login"} {
+		if w := serve(t, r, path, ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, w.Code)
+		}
+	}
+	if w := serve(t, r, "/api/v1/auth/refresh", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("fourth request in the group: status %d, want 429", w.Code)
+	}
+
+	// An exact route and a longer group are counted apart from it
+	for _, path := range []string{"/api/v1/auth/forgot-password", "/api/v1/auth/admin/users"} {
+		if w := serve(t, r, path, ""); w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", path, w.Code)
+		}
+		if w := serve(t, r, path, ""); w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s again: status %d, want 429", path, w.Code)
+		}
+	}
+	for _, key := range []string{"/auth/*:192.0.2.1", "/auth/admin/*:192.0.2.1", "/auth/forgot-password:192.0.2.1"} {
+		if _, ok := shared.seen[key]; !ok {
+			t.Errorf("no bucket %s in %v", key, shared.seen)
+		}
+	}
+
+	// Other routes keep the default budget
+	if w := serve(t, r, "/api/v1/movies", ""); w.Code != http.StatusOK {
+		t.Errorf("default budget: status %d", w.Code)
+	}
+}
+
+func TestRateLimitCountsSignedInUsersPerIP(t *testing.T) {
+	jwtManager := authinfra.NewJWTManager(config.JWTConfig{AccessSecret: "secret", AccessTokenExpiry: time.Hour})
+	shared := &countingLimiter{seen: make(map[string]int)}
+	r := rateLimitedRouter(shared)
+
+	hold := func(userID uuid.UUID) int {
+		t.Helper()
+		signed, err := jwtManager.GenerateAccessToken(userID, uuid.New(), "fan@example.com", "CUSTOMER")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/hold", nil)
+		req.Header.Set(AuthorizationHeader, "Bearer "+signed)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Per-account budgets are the user limiter's; route windows only see
+	// the IP, so users behind one IP share the route's budget
+	if code := hold(uuid.New()); code != http.StatusOK {
+		t.Fatalf("first hold: status %d", code)
+	}
+	if code := hold(uuid.New()); code != http.StatusTooManyRequests {
+		t.Errorf("another user's hold from the same IP: status %d, want 429", code)
+	}
+	if len(shared.seen) != 1 || shared.seen["/bookings/hold:192.0.2.1"] != 1 {
+		t.Errorf("buckets = %v, want one for the IP", shared.seen)
+	}
+}
+
+func TestRateLimitWithoutTheSharedLimiter(t *testing.T) {
+	for name, shared := range map[string]WindowLimiter{
+		"redis down": &countingLimiter{down: true},
+		"no redis":   nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			core, logs := observer.New(zap.WarnLevel)
+			limiter := NewRateLimiter(shared, config.RateLimitConfig{
+				Enabled:     true,
+				LimitConfig: config.LimitConfig{Limit: 2, Window: time.Minute},
+			}, &logger.Logger{Logger: zap.New(core)})
+			r := gin.New()
+			r.GET("/api/v1/movies", limiter.RateLimit(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			// No instance counts on its own, so requests go through
+			for i := range 5 {
+				if w := serve(t, r, "/api/v1/movies", ""); w.Code != http.StatusOK {
+					t.Fatalf("request %d: status %d", i+1, w.Code)
+				}
+			}
+			// and the outage is logged once, not for every request
+			if warnings := logs.FilterMessage("shared rate limiter unavailable, requests are not rate limited").Len(); warnings != 1 {
+				t.Errorf("logged %d warnings, want 1", warnings)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"cinemaos-backend/internal/pkg/logger"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
 // ResponseTimeMiddleware logs the response time for each request
@@ -16,22 +15,56 @@ func ResponseTimeMiddleware(log *logger.Logger) gin.HandlerFunc {
 		// Record start time
 		start := time.Now()
 
+		// Headers go out with the first write, so the header is set then
+		// rather than after the handlers have answered
+		c.Writer = &responseTimeWriter{ResponseWriter: c.Writer, start: start}
+
 		// Process request - this calls other handlers and middleware
 		c.Next()
 
 		// Calculate duration after request is complete
 		duration := time.Since(start)
 
+		// Responses without a body are written after the middleware returns
+		if !c.Writer.Written() {
+			c.Header("X-Response-Time", duration.String())
+		}
+
 		// Log the response time with request details
-		log.Info("request completed",
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
-			zap.Int("status", c.Writer.Status()),
-			zap.Duration("duration", duration),
-			zap.String("client_ip", c.ClientIP()),
+		log.WithContext(c.Request.Context()).Info("request completed",
+			logger.String("method", c.Request.Method),
+			logger.String("path", c.Request.URL.Path),
+			logger.Int("status", c.Writer.Status()),
+			logger.Duration("duration", duration),
+			logger.String("client_ip", c.ClientIP()),
 		)
+	}
+}
+
+// responseTimeWriter sets X-Response-Time to the time taken so far just
+// before the response headers are written
+type responseTimeWriter struct {
+	gin.ResponseWriter
+	start time.Time
+}
 
-		// Also set as header so clients can see it
-		c.Header("X-Response-Time", duration.String())
+func (w *responseTimeWriter) stamp() {
+	if !w.Written() {
+		w.Header().Set("X-Response-Time", time.Since(w.start).String())
 	}
 }
+
+func (w *responseTimeWriter) WriteHeaderNow() {
+	w.stamp()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *responseTimeWriter) Write(b []byte) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseTimeWriter) WriteString(s string) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.WriteString(s)
+}
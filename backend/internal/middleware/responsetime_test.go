@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestResponseTimeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ResponseTimeMiddleware(&logger.Logger{Logger: zap.NewNop()}))
+	r.GET("/json", func(c *gin.Context) {
+		time.Sleep(10 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	r.GET("/string", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	r.DELETE("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	// The recorder keeps the headers as they were when the response was
+	// written, as a client sees them
+	for _, tt := range []struct{ method, path string }{
+		{http.MethodGet, "/json"},
+		{http.MethodGet, "/string"},
+		{http.MethodDelete, "/empty"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		header := w.Result().Header.Get("X-Response-Time")
+		took, err := time.ParseDuration(header)
+		if err != nil {
+			t.Errorf("%s %s: X-Response-Time = %q", tt.method, tt.path, header)
+			continue
+		}
+		if tt.path == "/json" && took < 10*time.Millisecond {
+			t.Errorf("%s %s: X-Response-Time = %s, want the handler's time", tt.method, tt.path, took)
+		}
+	}
+}
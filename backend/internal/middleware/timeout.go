@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TimeoutMiddleware bounds each request with a deadline on its context, so
+// database queries and other calls made with it are cancelled once the
+// request has run too long. Routes and groups of routes may have their own
+// timeout, matched like rate limit overrides.
+//
+// The handlers run on their own goroutine and write to a buffer. If they
+// have not finished by the deadline, the client gets a 504 at once, even
+// from a handler stuck in a call that ignores its context, and whatever the
+// handler writes later is dropped. The middleware still waits for the
+// handlers to return before it does, as the gin context is reused after
+// that. Errors from calls cut short are turned into 504 by response.Error.
+// WebSocket upgrades are left alone, as their connections outlive any
+// request deadline.
+func TimeoutMiddleware(cfg config.ServerConfig, log *logger.Logger) gin.HandlerFunc {
+	timeouts := newRouteTimeouts(cfg)
+
+	return func(c *gin.Context) {
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		route := routePattern(c.FullPath())
+		timeout := timeouts.timeoutFor(route)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := newTimeoutWriter(c.Writer)
+		c.Writer = writer
+
+		done := make(chan struct{})
+		var panicked any
+		go func() {
+			defer close(done)
+			defer func() { panicked = recover() }()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && writer.timeOut() {
+				log.WithContext(ctx).Warn("request timed out",
+					zap.String("route", route),
+					zap.Duration("timeout", timeout),
+				)
+			}
+			<-done
+		}
+
+		c.Writer = writer.ResponseWriter
+		if panicked != nil {
+			// Handled by the recovery middleware, as it would be without
+			// the deadline
+			panic(panicked)
+		}
+		if writer.flush() || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		// The deadline passed as the handlers returned without answering
+		log.WithContext(ctx).Warn("request timed out",
+			zap.String("route", route),
+			zap.Duration("timeout", timeout),
+		)
+		response.Error(c, apperrors.New(apperrors.CodeTimeout, "The request took too long"))
+		c.Abort()
+	}
+}
+
+// timeoutWriter holds a response in memory until the handlers finish or
+// the deadline passes, whichever comes first
+type timeoutWriter struct {
+	gin.ResponseWriter // the client's, written once the outcome is known
+
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: w,
+		// Headers set by the middleware before this one are kept
+		header: w.Header().Clone(),
+		status: http.StatusOK,
+	}
+}
+
+// timeOut answers 504 unless the response was already sent, and drops what
+// the handlers write from then on. It reports whether it answered.
+func (w *timeoutWriter) timeOut() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return false
+	}
+	w.timedOut = true
+
+	body, err := json.Marshal(response.Response{
+		Error: &response.ErrorResponse{
+			Code:    string(apperrors.CodeTimeout),
+			Message: "The request took too long",
+		},
+	})
+	if err != nil {
+		return false
+	}
+	header := w.ResponseWriter.Header()
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	// The connection stays busy until the handler gives up
+	header.Set("Connection", "close")
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+	return true
+}
+
+// flush sends the buffered response, unless the deadline answered first.
+// It reports whether the client has been answered.
+func (w *timeoutWriter) flush() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return true
+	}
+	w.timedOut = true
+
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+	// A status set without a body is written by gin once the request ends
+	w.ResponseWriter.WriteHeader(w.status)
+	if !w.wroteHeader {
+		return false
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+	return true
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader && code > 0 {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.body.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wroteHeader
+}
+
+// Flush does nothing: the response is sent whole once the handlers finish
+func (w *timeoutWriter) Flush() {}
+
+// routeTimeouts resolves the timeout of a route: its own, else the longest
+// group containing it, else the default
+type routeTimeouts struct {
+	defaults time.Duration
+	routes   map[string]time.Duration
+	groups   map[string]time.Duration // keyed on the prefix, e.g. /admin/
+}
+
+func newRouteTimeouts(cfg config.ServerConfig) *routeTimeouts {
+	t := &routeTimeouts{
+		defaults: cfg.RequestTimeout,
+		routes:   make(map[string]time.Duration, len(cfg.RouteTimeouts)),
+		groups:   make(map[string]time.Duration),
+	}
+	for pattern, timeout := range cfg.RouteTimeouts {
+		// Config keys arrive lower-cased
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			t.groups[prefix] = timeout
+			continue
+		}
+		t.routes[pattern] = timeout
+	}
+	return t
+}
+
+func (t *routeTimeouts) timeoutFor(route string) time.Duration {
+	if route == "" {
+		return t.defaults
+	}
+	if timeout, ok := t.routes[route]; ok {
+		return timeout
+	}
+
+	var group string
+	for prefix := range t.groups {
+		if strings.HasPrefix(route, prefix) && len(prefix) > len(group) {
+			group = prefix
+		}
+	}
+	if group != "" {
+		return t.groups[group]
+	}
+	return t.defaults
+}
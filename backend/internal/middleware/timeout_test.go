@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	apperrors "cinemaos-backend/internal/pkg/errors"
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// timeoutRouter serves routes that wait on their context behind the
+// middleware: /wait writes nothing, /fail returns the error a cancelled
+// query would, and /fast answers at once
+func timeoutRouter(cfg config.ServerConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(TimeoutMiddleware(cfg, &logger.Logger{Logger: zap.NewNop()}))
+	wait := func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	}
+	api := r.Group("/api/v1")
+	api.GET("/wait", wait)
+	api.POST("/bookings/confirm", wait)
+	api.GET("/admin/wait", wait)
+	api.GET("/fail", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		response.Error(c, apperrors.Wrap(c.Request.Context().Err(), apperrors.CodeInternal, "failed to list showtimes"))
+	})
+	api.GET("/fast", func(c *gin.Context) {
+		response.Success(c, "done")
+	})
+	return r
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	r := timeoutRouter(config.ServerConfig{
+		RequestTimeout: 50 * time.Millisecond,
+		RouteTimeouts:  map[string]time.Duration{"/bookings/confirm": 200 * time.Millisecond},
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		atLeast  time.Duration
+		atMost   time.Duration
+		timedOut bool
+	}{
+		{"default budget", http.MethodGet, "/api/v1/wait", http.StatusGatewayTimeout, 50 * time.Millisecond, time.Second, true},
+		{"a cancelled query", http.MethodGet, "/api/v1/fail", http.StatusGatewayTimeout, 50 * time.Millisecond, time.Second, true},
+		{"the confirm budget", http.MethodPost, "/api/v1/bookings/confirm", http.StatusGatewayTimeout, 200 * time.Millisecond, time.Second, true},
+		{"in time", http.MethodGet, "/api/v1/fast", http.StatusOK, 0, 50 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		start := time.Now()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		took := time.Since(start)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if took < tt.atLeast || took > tt.atMost {
+			t.Errorf("%s: answered after %s, want between %s and %s", tt.name, took, tt.atLeast, tt.atMost)
+		}
+		var body response.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: body %q: %v", tt.name, w.Body, err)
+		}
+		if timedOut := body.Error != nil && body.Error.Code == string(apperrors.CodeTimeout); timedOut != tt.timedOut {
+			t.Errorf("%s: error = %+v, want a timeout %v", tt.name, body.Error, tt.timedOut)
+		}
+	}
+}
+
+// slowRepository stands in for a query that ignores the request's context,
+// such as one waiting on a lock with a driver that cannot cancel it
+type slowRepository struct {
+	delay    time.Duration
+	finished chan struct{}
+}
+
+func (r *slowRepository) List(context.Context) ([]string, error) {
+	time.Sleep(r.delay)
+	close(r.finished)
+	return []string{"late"}, nil
+}
+
+func TestTimeoutAnswersWhileTheHandlerIsStuck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &slowRepository{delay: 500 * time.Millisecond, finished: make(chan struct{})}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Header("X-Request-ID", "req-1")
+	})
+	r.Use(TimeoutMiddleware(config.ServerConfig{RequestTimeout: 50 * time.Millisecond}, &logger.Logger{Logger: zap.NewNop()}))
+	r.GET("/api/v1/showtimes", func(c *gin.Context) {
+		showtimes, err := repo.List(c.Request.Context())
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.Success(c, showtimes)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	start := time.Now()
+	res, err := http.Get(srv.URL + "/api/v1/showtimes")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	took := time.Since(start)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	if res.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504", res.StatusCode)
+	}
+	if took > 250*time.Millisecond {
+		t.Errorf("answered after %s, want about the 50ms budget rather than the %s query", took, repo.delay)
+	}
+	var decoded response.Response
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Error == nil || decoded.Error.Code != string(apperrors.CodeTimeout) {
+		t.Errorf("body = %s (%v), want a timeout error", body, err)
+	}
+	if got := res.Header.Get("X-Request-ID"); got != "req-1" {
+		t.Errorf("X-Request-ID = %q, want the header set before the deadline", got)
+	}
+
+	select {
+	case <-repo.finished:
+		t.Error("the query finished before the client was answered")
+	default:
+	}
+	<-repo.finished
+}
+
+func TestTimeoutMiddlewareKeepsTheResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(TimeoutMiddleware(config.ServerConfig{RequestTimeout: time.Second}, &logger.Logger{Logger: zap.NewNop()}))
+	r.POST("/created", func(c *gin.Context) {
+		c.Header("Location", "/bookings/1")
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+	r.DELETE("/gone", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("handler failed")
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		header string
+		body   string
+	}{
+		{http.MethodPost, "/created", http.StatusCreated, "/bookings/1", `{"id":1}`},
+		{http.MethodDelete, "/gone", http.StatusNoContent, "", ""},
+		{http.MethodGet, "/panic", http.StatusInternalServerError, "", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status || w.Header().Get("Location") != tt.header || w.Body.String() != tt.body {
+			t.Errorf("%s %s = %d %q, Location %q, want %d %q, Location %q",
+				tt.method, tt.path, w.Code, w.Body, w.Header().Get("Location"), tt.status, tt.body, tt.header)
+		}
+	}
+}
+
+func TestPaymentRoutesGetTheLongerTimeout(t *testing.T) {
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(TimeoutMiddleware(cfg.Server, &logger.Logger{Logger: zap.NewNop()}))
+	budget := func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if !ok {
+			c.String(http.StatusOK, "none")
+			return
+		}
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Second).String())
+	}
+	bookings := r.Group("/api/v1/bookings")
+	bookings.POST("/confirm", budget)
+	bookings.POST("/:id/payment", budget)
+	bookings.GET("/:id", budget)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodPost, "/api/v1/bookings/confirm", "14s"},
+		{http.MethodPost, "/api/v1/bookings/4f8c/payment", "14s"},
+		{http.MethodGet, "/api/v1/bookings/4f8c", "10s"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.want {
+			t.Errorf("%s %s ran with %s left, want %s", tt.method, tt.path, w.Body, tt.want)
+		}
+	}
+	if cfg.Server.WriteTimeout <= 14*time.Second {
+		t.Errorf("write_timeout %s cuts the confirm budget short", cfg.Server.WriteTimeout)
+	}
+}
+
+func TestTimeoutMiddlewareOff(t *testing.T) {
+	// A zero budget turns the timeout off: the handler's context has no
+	// deadline, so it only returns once the client goes away
+	r := timeoutRouter(config.ServerConfig{
+		RequestTimeout: 50 * time.Millisecond,
+		RouteTimeouts:  map[string]time.Duration{"/admin/*": 0},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/wait", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), 150*time.Millisecond)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	r.ServeHTTP(w, req.WithContext(ctx))
+	if took := time.Since(start); took < 150*time.Millisecond {
+		t.Errorf("admin route cut short after %s", took)
+	}
+	if w.Code == http.StatusGatewayTimeout {
+		t.Error("admin route answered 504 with the timeout off")
+	}
+}
+
+func TestRouteTimeouts(t *testing.T) {
+	timeouts := newRouteTimeouts(config.ServerConfig{
+		RequestTimeout: 10 * time.Second,
+		RouteTimeouts: map[string]time.Duration{
+			"/Bookings/Confirm": 14 * time.Second,
+			"/admin/*":          30 * time.Second,
+			"/admin/reports/*":  time.Minute,
+			"/health":           0,
+		},
+	})
+
+	tests := []struct {
+		route string
+		want  time.Duration
+	}{
+		{"/bookings/confirm", 14 * time.Second},
+		{"/bookings/:id", 10 * time.Second},
+		{"/admin/movies", 30 * time.Second},
+		{"/admin/reports/sales", time.Minute},
+		{"/health", 0},
+		{"", 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := timeouts.timeoutFor(tt.route); got != tt.want {
+			t.Errorf("timeoutFor(%q) = %s, want %s", tt.route, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UserRateLimiter limits the requests of each signed-in user in a token
+// bucket, on top of the per-IP budget, so users sharing an IP behind a
+// proxy keep their own budget. It is the only per-account limit. The
+// budget depends on the user's role, and unlisted roles get the default
+// one. Buckets are kept by a shared limiter so every instance enforces the
+// same budget; while it is missing or failing, requests are let through
+// with a warning.
+type UserRateLimiter struct {
+	shared   WindowLimiter // nil without Redis
+	enabled  bool
+	defaults config.LimitConfig
+	roles    map[string]config.LimitConfig // keyed on the lower-cased role
+	logger   *logger.Logger
+
+	lastFailureLog atomic.Int64
+}
+
+// NewUserRateLimiter creates a per-user rate limiter
+func NewUserRateLimiter(shared WindowLimiter, cfg config.UserRateLimitConfig, log *logger.Logger) *UserRateLimiter {
+	roles := make(map[string]config.LimitConfig, len(cfg.Roles))
+	for role, limit := range cfg.Roles {
+		roles[strings.ToLower(role)] = limit
+	}
+	return &UserRateLimiter{
+		shared:   shared,
+		enabled:  cfg.Enabled,
+		defaults: cfg.Default,
+		roles:    roles,
+		logger:   log,
+	}
+}
+
+// AllowUser counts a request of the user against the budget of their role
+// and reports whether it fits, how many requests are left and, when it
+// does not, how long until the next one will. The remaining count is -1
+// for an unlimited role.
+func (l *UserRateLimiter) AllowUser(ctx context.Context, userID, role string) (bool, int, time.Duration) {
+	limit, ok := l.roles[strings.ToLower(role)]
+	if !ok {
+		limit = l.defaults
+	}
+	if !l.enabled || limit.Limit <= 0 || limit.Window <= 0 {
+		return true, -1, 0
+	}
+
+	if l.shared == nil {
+		l.warnUnlimited(errNoSharedLimiter)
+		return true, limit.Limit, 0
+	}
+	allowed, remaining, retryAfter, err := l.shared.Allow(ctx, "user:"+userID, limit.Limit, limit.Window)
+	if err != nil {
+		l.warnUnlimited(err)
+		return true, limit.Limit, 0
+	}
+	return allowed, remaining, retryAfter
+}
+
+// warnUnlimited logs, at most once per failureLogInterval, that users go
+// unlimited
+func (l *UserRateLimiter) warnUnlimited(err error) {
+	now := time.Now().UnixNano()
+	last := l.lastFailureLog.Load()
+	if now-last >= int64(failureLogInterval) && l.lastFailureLog.CompareAndSwap(last, now) {
+		l.logger.Warn("shared user rate limiter unavailable, users are not rate limited", zap.Error(err))
+	}
+}
+
+// limit checks the signed-in user of the request and answers 429 when
+// they are over budget. It reports whether the request may go on.
+func (l *UserRateLimiter) limit(c *gin.Context) bool {
+	if l == nil {
+		return true
+	}
+	userID, role := c.GetString(UserIDKey), c.GetString(UserRoleKey)
+	if userID == "" {
+		return true
+	}
+
+	allowed, remaining, retryAfter := l.AllowUser(c.Request.Context(), userID, role)
+	if remaining >= 0 {
+		c.Header("X-RateLimit-User-Remaining", itoa(remaining))
+	}
+	if !allowed {
+		c.Header("Retry-After", itoa(max(int(retryAfter.Round(time.Second).Seconds()), 1)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "TOO_MANY_REQUESTS",
+				"message": "Too many requests from this account. Please try again later.",
+			},
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// userLimitedRouter serves GET /api/v1/me behind Authenticate with a
+// per-user limit of one request a minute for customers, none for admins
+// and two for any other role
+func userLimitedRouter(t *testing.T, shared WindowLimiter) (*gin.Engine, func(userID uuid.UUID, role string) string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{Logger: zap.NewNop()}
+	users := NewUserRateLimiter(shared, config.UserRateLimitConfig{
+		Enabled: true,
+		Default: config.LimitConfig{Limit: 2, Window: time.Minute},
+		Roles: map[string]config.LimitConfig{
+			"Customer": {Limit: 1, Window: time.Minute},
+			"admin":    {Limit: 0, Window: time.Minute},
+		},
+	}, log)
+	jwtManager := authinfra.NewJWTManager(config.JWTConfig{AccessSecret: "secret", AccessTokenExpiry: time.Hour})
+
+	r := gin.New()
+	auth := NewAuthMiddleware(jwtManager, users, log)
+	r.GET("/api/v1/me", auth.Authenticate(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token := func(userID uuid.UUID, role string) string {
+		t.Helper()
+		signed, err := jwtManager.GenerateAccessToken(userID, uuid.New(), "fan@example.com", role)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken: %v", err)
+		}
+		return "Bearer " + signed
+	}
+	return r, token
+}
+
+func getMe(r http.Handler, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.Header.Set(AuthorizationHeader, authorization)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserRateLimit(t *testing.T) {
+	r, token := userLimitedRouter(t, &countingLimiter{seen: make(map[string]int)})
+
+	tests := []struct {
+		name    string
+		role    string
+		allowed int
+	}{
+		{"customer", "CUSTOMER", 1},
+		{"admins are unlimited", "ADMIN", 10},
+		{"unknown roles get the default", "AUDITOR", 2},
+	}
+	for _, tt := range tests {
+		authorization := token(uuid.New(), tt.role)
+		for i := range tt.allowed {
+			if w := getMe(r, authorization); w.Code != http.StatusOK {
+				t.Fatalf("%s: request %d: status %d", tt.name, i+1, w.Code)
+			}
+		}
+		if tt.role == "ADMIN" {
+			if w := getMe(r, authorization); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-User-Remaining") != "" {
+				t.Errorf("%s: status %d, remaining %q", tt.name, w.Code, w.Header().Get("X-RateLimit-User-Remaining"))
+			}
+			continue
+		}
+
+		w := getMe(r, authorization)
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s: request %d: status %d, want 429", tt.name, tt.allowed+1, w.Code)
+		}
+		if w.Header().Get("Retry-After") != "60" || w.Header().Get("X-RateLimit-User-Remaining") != "0" {
+			t.Errorf("%s: Retry-After %q, remaining %q", tt.name, w.Header().Get("Retry-After"), w.Header().Get("X-RateLimit-User-Remaining"))
+		}
+	}
+
+	// Another customer behind the same IP has a budget of their own
+	if w := getMe(r, token(uuid.New(), "CUSTOMER")); w.Code != http.StatusOK {
+		t.Errorf("another customer: status %d", w.Code)
+	}
+}
+
+func TestUserRateLimitWithoutTheSharedLimiter(t *testing.T) {
+	for name, shared := range map[string]WindowLimiter{
+		"redis down": &countingLimiter{down: true},
+		"no redis":   nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, token := userLimitedRouter(t, shared)
+			authorization := token(uuid.New(), "CUSTOMER")
+
+			// No instance counts on its own, so the user is not limited
+			for i := range 3 {
+				if w := getMe(r, authorization); w.Code != http.StatusOK {
+					t.Fatalf("request %d: status %d", i+1, w.Code)
+				}
+			}
+		})
+	}
+}
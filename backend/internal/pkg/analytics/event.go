@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Funnel event names emitted by the server
+const (
+	EventSeatMapViewed    = "seat_map_viewed"
+	EventSeatsHeld        = "seats_held"
+	EventPaymentSucceeded = "payment_succeeded"
+	EventPaymentFailed    = "payment_failed"
+	EventBookingConfirmed = "booking_confirmed"
+)
+
+// Funnel event names accepted from clients
+const (
+	EventShowtimeViewed    = "showtime_viewed"
+	EventSeatSelected      = "seat_selected"
+	EventSeatDeselected    = "seat_deselected"
+	EventCheckoutStarted   = "checkout_started"
+	EventCheckoutAbandoned = "checkout_abandoned"
+)
+
+// Event is a single booking funnel event. It carries identifiers only:
+// there are no fields for names, emails or other contact details, and
+// string properties that look like an email address are dropped.
+type Event struct {
+	Name        string         `json:"name"`
+	AnonymousID string         `json:"anonymous_id,omitempty"`
+	UserID      *uuid.UUID     `json:"user_id,omitempty"`
+	ShowtimeID  *uuid.UUID     `json:"showtime_id,omitempty"`
+	MovieID     *uuid.UUID     `json:"movie_id,omitempty"`
+	Properties  map[string]any `json:"properties,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// AnonymousIDHeader carries the visitor's anonymous ID, a client-generated
+// UUID, on every request
+const AnonymousIDHeader = "X-Anonymous-ID"
+
+type anonymousIDKey struct{}
+
+// WithAnonymousID returns a context carrying the client's anonymous ID, so
+// server-side events can be joined to the same visitor's client events
+func WithAnonymousID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, anonymousIDKey{}, id)
+}
+
+// AnonymousIDFrom returns the anonymous ID carried by the context, if any
+func AnonymousIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(anonymousIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,35 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize bounds a single JSON line read back from a file sink
+const maxLineSize = 1024 * 1024
+
+// ReadEvents parses JSON-lines output of a FileSink back into events.
+// Blank lines are skipped; a malformed line fails with its line number.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var events []Event
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,77 @@
+package analytics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFileSinkRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics", "events.jsonl")
+	sink, err := NewFileSink(path, 0, time.Hour)
+	if err != nil {
+		t.Fatalf("open sink: %v", err)
+	}
+
+	userID, showtimeID, movieID := uuid.New(), uuid.New(), uuid.New()
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	want := []Event{
+		{
+			Name:        EventSeatsHeld,
+			AnonymousID: "3b7c1d2e-0000-4000-8000-000000000001",
+			UserID:      &userID,
+			ShowtimeID:  &showtimeID,
+			MovieID:     &movieID,
+			// JSON numbers come back as float64
+			Properties: map[string]any{"seats": float64(2), "fare": "STANDARD", "presale": true},
+			Timestamp:  ts,
+		},
+		{Name: EventSeatMapViewed, ShowtimeID: &showtimeID, Timestamp: ts.Add(time.Second)},
+	}
+	for _, event := range want {
+		if err := sink.Write(event); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := sink.Write(want[0]); err == nil {
+		t.Error("write after close succeeded")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer file.Close()
+	got, err := ReadEvents(file)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	events, err := ReadEvents(strings.NewReader(
+		`{"name":"seat_selected","timestamp":"2026-10-16T12:00:00Z"}` + "\n\n" +
+			`{"name":"checkout_started","timestamp":"2026-10-16T12:00:01Z"}` + "\n"))
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 2 || events[0].Name != EventSeatSelected || events[1].Name != EventCheckoutStarted {
+		t.Errorf("events = %+v", events)
+	}
+
+	_, err = ReadEvents(strings.NewReader(`{"name":"seat_selected"}` + "\n" + `{"name":` + "\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("malformed line: err = %v, want it reported on line 2", err)
+	}
+}
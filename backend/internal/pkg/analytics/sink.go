@@ -0,0 +1,130 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sink receives analytics events
+type Sink interface {
+	// Write records an event. It may buffer; Close flushes.
+	Write(event Event) error
+	// Close flushes buffered events and releases the sink
+	Close() error
+}
+
+// NoopSink discards every event
+type NoopSink struct{}
+
+// Write discards the event
+func (NoopSink) Write(Event) error { return nil }
+
+// Close does nothing
+func (NoopSink) Close() error { return nil }
+
+// FileSink appends events to a file as JSON lines, one event per line.
+// Writes are buffered and flushed when the buffer fills, every
+// flushInterval, and on Close.
+type FileSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	stop   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// NewFileSink opens path for appending, creating it and its directory if
+// needed
+func NewFileSink(path string, bufferSize int, flushInterval time.Duration) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open analytics file: %w", err)
+	}
+	if bufferSize <= 0 {
+		bufferSize = 64 * 1024
+	}
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+
+	s := &FileSink{
+		file:   file,
+		writer: bufio.NewWriterSize(file, bufferSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.flushLoop(flushInterval)
+	return s, nil
+}
+
+// Write appends the event as a JSON line
+func (s *FileSink) Write(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("analytics file sink is closed")
+	}
+	if _, err := s.writer.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Flush writes buffered events to the file
+func (s *FileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	return s.writer.Flush()
+}
+
+// Close flushes buffered events and closes the file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+
+	flushErr := s.writer.Flush()
+	if err := s.file.Close(); err != nil && flushErr == nil {
+		return err
+	}
+	return flushErr
+}
+
+func (s *FileSink) flushLoop(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
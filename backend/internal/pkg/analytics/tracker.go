@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// maxPropertyLength bounds string property values; longer values are dropped
+const maxPropertyLength = 200
+
+// Config holds event sampling settings
+type Config struct {
+	DefaultSampleRate float64            // fraction of events of unlisted types kept, 0..1
+	SampleRates       map[string]float64 // per event name, overriding the default
+}
+
+// Tracker samples funnel events and writes them to a sink on the async
+// dispatcher, so tracking never blocks or fails the request that emits it
+type Tracker struct {
+	cfg        Config
+	sink       Sink
+	dispatcher *async.Dispatcher
+	logger     *logger.Logger
+}
+
+// NewTracker creates a tracker writing to sink
+func NewTracker(cfg Config, sink Sink, dispatcher *async.Dispatcher, log *logger.Logger) *Tracker {
+	cfg.DefaultSampleRate = min(max(cfg.DefaultSampleRate, 0), 1)
+	return &Tracker{
+		cfg:        cfg,
+		sink:       sink,
+		dispatcher: dispatcher,
+		logger:     log,
+	}
+}
+
+// Track records the event if it is sampled. The timestamp defaults to now
+// and the anonymous ID to the one carried by ctx.
+func (t *Tracker) Track(ctx context.Context, event Event) {
+	if t == nil || !t.sampled(event.Name) {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.AnonymousID == "" {
+		event.AnonymousID = AnonymousIDFrom(ctx)
+	}
+	event.Properties = scrub(event.Properties)
+
+	name := event.Name
+	if !t.dispatcher.SubmitAnalytics(func(context.Context) error {
+		return t.sink.Write(event)
+	}) {
+		t.logger.Debug("analytics event dropped, queue full", zap.String("event", name))
+	}
+}
+
+// Close flushes and closes the sink
+func (t *Tracker) Close() error {
+	return t.sink.Close()
+}
+
+// sampled reports whether an event of the named type is kept
+func (t *Tracker) sampled(name string) bool {
+	rate, ok := t.cfg.SampleRates[name]
+	if !ok {
+		rate = t.cfg.DefaultSampleRate
+	}
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// scrub keeps scalar properties only, dropping long strings and anything
+// that looks like an email address
+func scrub(props map[string]any) map[string]any {
+	if len(props) == 0 {
+		return nil
+	}
+	clean := make(map[string]any, len(props))
+	for key, value := range props {
+		switch v := value.(type) {
+		case string:
+			if len(v) > maxPropertyLength || strings.Contains(v, "@") {
+				continue
+			}
+		case bool, int, int32, int64, float32, float64:
+		default:
+			continue
+		}
+		clean[key] = value
+	}
+	return clean
+}
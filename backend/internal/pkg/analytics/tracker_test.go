@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// chanSink hands written events to the test
+type chanSink chan Event
+
+func (s chanSink) Write(event Event) error {
+	s <- event
+	return nil
+}
+
+func (s chanSink) Close() error { return nil }
+
+func TestTrackerWritesThroughTheDispatcher(t *testing.T) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	dispatcher := async.NewDispatcher(1, 10, nil, log)
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(time.Second) })
+
+	sink := make(chanSink, 1)
+	tracker := NewTracker(Config{DefaultSampleRate: 1}, sink, dispatcher, log)
+
+	ctx := WithAnonymousID(context.Background(), "visitor-1")
+	tracker.Track(ctx, Event{Name: EventSeatMapViewed, Properties: map[string]any{
+		"seats": 2,
+		"email": "jane@example.com",
+	}})
+
+	select {
+	case event := <-sink:
+		if event.AnonymousID != "visitor-1" {
+			t.Errorf("anonymous ID = %q, want the one from the context", event.AnonymousID)
+		}
+		if event.Timestamp.IsZero() {
+			t.Error("the timestamp was not set")
+		}
+		if !reflect.DeepEqual(event.Properties, map[string]any{"seats": 2}) {
+			t.Errorf("properties = %v, want the email dropped", event.Properties)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the event was not written")
+	}
+}
+
+func TestTrackerSampling(t *testing.T) {
+	tracker := NewTracker(Config{
+		DefaultSampleRate: 1,
+		SampleRates:       map[string]float64{EventSeatSelected: 0, EventSeatDeselected: 0.5},
+	}, NoopSink{}, nil, nil)
+
+	for i := 0; i < 100; i++ {
+		if !tracker.sampled(EventSeatsHeld) {
+			t.Fatal("an event at the default rate of 1 was dropped")
+		}
+		if tracker.sampled(EventSeatSelected) {
+			t.Fatal("an event at a rate of 0 was kept")
+		}
+	}
+
+	kept := 0
+	for i := 0; i < 1000; i++ {
+		if tracker.sampled(EventSeatDeselected) {
+			kept++
+		}
+	}
+	if kept < 350 || kept > 650 {
+		t.Errorf("kept %d of 1000 events at a rate of 0.5", kept)
+	}
+
+	// Out of range rates are clamped
+	if NewTracker(Config{DefaultSampleRate: 7}, NoopSink{}, nil, nil).cfg.DefaultSampleRate != 1 {
+		t.Error("a default rate above 1 was not clamped")
+	}
+}
+
+func TestScrub(t *testing.T) {
+	long := make([]byte, maxPropertyLength+1)
+	for i := range long {
+		long[i] = 'x'
+	}
+
+	got := scrub(map[string]any{
+		"seats":    2,
+		"amount":   10.5,
+		"presale":  true,
+		"fare":     "STANDARD",
+		"email":    "jane@example.com",
+		"note":     string(long),
+		"seat_ids": []string{"A1"},
+		"user":     map[string]any{"id": 1},
+	})
+	want := map[string]any{"seats": 2, "amount": 10.5, "presale": true, "fare": "STANDARD"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scrub = %v, want %v", got, want)
+	}
+	if scrub(map[string]any{}) != nil {
+		t.Error("an empty map was not dropped")
+	}
+}
@@ -0,0 +1,73 @@
+package apiversion
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version identifies a public API version
+type Version string
+
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
+
+	// Latest is the newest version served by the API
+	Latest = V2
+
+	// Header lets clients that cannot pick a path prefix select a version
+	Header = "X-API-Version"
+
+	// ContextKey is the gin context key holding the request's API version
+	ContextKey = "api_version"
+)
+
+type ctxKey struct{}
+
+// Parse converts "v2", "V2" or "2" into a known version
+func Parse(s string) (Version, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s != "" && !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+
+	switch Version(s) {
+	case V1, V2:
+		return Version(s), true
+	default:
+		return "", false
+	}
+}
+
+// WithContext returns a copy of ctx carrying the version
+func WithContext(ctx context.Context, v Version) context.Context {
+	return context.WithValue(ctx, ctxKey{}, v)
+}
+
+// FromContext returns the version stored in ctx, defaulting to V1
+func FromContext(ctx context.Context) Version {
+	if v, ok := ctx.Value(ctxKey{}).(Version); ok {
+		return v
+	}
+	return V1
+}
+
+// FromGin returns the version resolved for the request, defaulting to V1
+func FromGin(c *gin.Context) Version {
+	if v, exists := c.Get(ContextKey); exists {
+		if version, ok := v.(Version); ok {
+			return version
+		}
+	}
+	return V1
+}
+
+// Cents converts a decimal money amount to integer minor units
+func Cents(amount float64) int64 {
+	if amount < 0 {
+		return int64(amount*100 - 0.5)
+	}
+	return int64(amount*100 + 0.5)
+}
@@ -19,6 +19,8 @@ const (
 	JobTypeNotification JobType = "notification"
 	JobTypeCleanup     JobType = "cleanup"
 	JobTypeReport      JobType = "report"
+	JobTypeAnalytics   JobType = "analytics"
+	JobTypeTicket      JobType = "ticket"
 )
 
 // EmailPayload represents data for sending an email
@@ -27,6 +29,17 @@ type EmailPayload struct {
 	Subject string
 	Body    string
 	IsHTML  bool
+	// Attachments are sent with the email; those with a ContentID are
+	// inline and can be referenced from an HTML body as cid:<ContentID>
+	Attachments []EmailAttachment
+}
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	ContentID   string
+	Data        []byte
 }
 
 // NotificationPayload represents data for a notification
@@ -38,17 +51,25 @@ type NotificationPayload struct {
 	Data    map[string]interface{}
 }
 
+// EmailSender delivers emails
+type EmailSender interface {
+	Send(ctx context.Context, email EmailPayload) error
+}
+
 // Dispatcher manages async job dispatching
 type Dispatcher struct {
 	pool   *worker.Pool
+	sender EmailSender
 	logger *logger.Logger
 }
 
-// NewDispatcher creates a new async dispatcher
-func NewDispatcher(workers, queueSize int, log *logger.Logger) *Dispatcher {
+// NewDispatcher creates a new async dispatcher that delivers email jobs
+// with sender
+func NewDispatcher(workers, queueSize int, sender EmailSender, log *logger.Logger) *Dispatcher {
 	pool := worker.NewPool("async-jobs", workers, queueSize, log)
 	return &Dispatcher{
 		pool:   pool,
+		sender: sender,
 		logger: log,
 	}
 }
@@ -98,6 +119,31 @@ func (d *Dispatcher) SubmitCleanup(cleanupFn func(ctx context.Context) error) bo
 	return d.pool.Submit(job)
 }
 
+// SubmitAnalytics submits an analytics write. Analytics are best effort:
+// callers drop the event when the queue is full.
+func (d *Dispatcher) SubmitAnalytics(writeFn func(ctx context.Context) error) bool {
+	job := worker.Job{
+		ID:   uuid.New().String(),
+		Type: string(JobTypeAnalytics),
+		Handler: func(ctx context.Context, _ interface{}) error {
+			return writeFn(ctx)
+		},
+	}
+	return d.pool.Submit(job)
+}
+
+// SubmitTicket submits e-ticket generation for a confirmed booking
+func (d *Dispatcher) SubmitTicket(generateFn func(ctx context.Context) error) bool {
+	job := worker.Job{
+		ID:   uuid.New().String(),
+		Type: string(JobTypeTicket),
+		Handler: func(ctx context.Context, _ interface{}) error {
+			return generateFn(ctx)
+		},
+	}
+	return d.pool.Submit(job)
+}
+
 // handleEmail processes email jobs
 func (d *Dispatcher) handleEmail(ctx context.Context, payload interface{}) error {
 	email, ok := payload.(EmailPayload)
@@ -109,11 +155,17 @@ func (d *Dispatcher) handleEmail(ctx context.Context, payload interface{}) error
 	d.logger.Info("sending email",
 		zap.Strings("to", email.To),
 		zap.String("subject", email.Subject),
+		zap.Int("attachments", len(email.Attachments)),
 	)
 
-	// TODO: Integrate with email service (SendGrid, AWS SES, etc.)
-	// For now, just simulate sending
-	time.Sleep(100 * time.Millisecond)
+	if err := d.sender.Send(ctx, email); err != nil {
+		d.logger.Error("failed to send email",
+			zap.Strings("to", email.To),
+			zap.String("subject", email.Subject),
+			zap.Error(err),
+		)
+		return err
+	}
 
 	d.logger.Info("email sent successfully")
 	return nil
@@ -0,0 +1,44 @@
+package changes
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ActorType says who made a change
+type ActorType string
+
+const (
+	ActorUser   ActorType = "USER"
+	ActorSystem ActorType = "SYSTEM" // background jobs and other unattended callers
+)
+
+// Actor is whoever made a change
+type Actor struct {
+	Type ActorType  `json:"type"`
+	ID   *uuid.UUID `json:"id,omitempty"`
+}
+
+// System is the actor of changes made outside a user request
+var System = Actor{Type: ActorSystem}
+
+// User returns the actor for a signed-in user
+func User(id uuid.UUID) Actor {
+	return Actor{Type: ActorUser, ID: &id}
+}
+
+type ctxKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// ActorFrom returns the actor stored in ctx, defaulting to System
+func ActorFrom(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(ctxKey{}).(Actor); ok {
+		return actor
+	}
+	return System
+}
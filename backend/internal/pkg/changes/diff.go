@@ -0,0 +1,166 @@
+// Package changes computes field-level diffs between two versions of an
+// entity and tracks who made a change.
+package changes
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Change is the difference in one field. Scalar fields carry Old and New;
+// slice fields carry the elements Added and Removed instead.
+type Change struct {
+	Field   string `json:"field"`
+	Old     any    `json:"old,omitempty"`
+	New     any    `json:"new,omitempty"`
+	Added   []any  `json:"added,omitempty"`
+	Removed []any  `json:"removed,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Diff compares two values of the same struct type field by field and
+// returns the fields that differ, named by their json tags. Fields tagged
+// json:"-", gorm relations and the fields listed in ignore are skipped.
+// It returns nil when before and after are not the same struct type.
+func Diff(before, after any, ignore ...string) []Change {
+	b, a := indirect(reflect.ValueOf(before)), indirect(reflect.ValueOf(after))
+	if !b.IsValid() || !a.IsValid() || b.Type() != a.Type() || b.Kind() != reflect.Struct {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		skip[name] = true
+	}
+
+	var out []Change
+	diffStruct(b, a, skip, &out)
+	return out
+}
+
+func diffStruct(b, a reflect.Value, skip map[string]bool, out *[]Change) {
+	t := b.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct && field.Type != timeType {
+			bv, av := indirect(b.Field(i)), indirect(a.Field(i))
+			if bv.IsValid() && av.IsValid() {
+				diffStruct(bv, av, skip, out)
+			}
+			continue
+		}
+
+		name, ok := fieldName(field)
+		if !ok || skip[name] {
+			continue
+		}
+
+		if change, changed := diffValue(name, b.Field(i), a.Field(i)); changed {
+			*out = append(*out, change)
+		}
+	}
+}
+
+// fieldName returns the json name of a field, or false if the field is not
+// part of the entity's own data
+func fieldName(field reflect.StructField) (string, bool) {
+	gormTag := field.Tag.Get("gorm")
+	if strings.Contains(gormTag, "foreignKey") || strings.Contains(gormTag, "many2many") {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+func diffValue(name string, b, a reflect.Value) (Change, bool) {
+	b, a = indirect(b), indirect(a)
+	if !b.IsValid() || !a.IsValid() {
+		if b.IsValid() == a.IsValid() {
+			return Change{}, false
+		}
+		return Change{Field: name, Old: valueOf(b), New: valueOf(a)}, true
+	}
+
+	switch {
+	case b.Type() == timeType:
+		if b.Interface().(time.Time).Equal(a.Interface().(time.Time)) {
+			return Change{}, false
+		}
+	case isList(b.Type()):
+		added, removed := diffElements(b, a)
+		if len(added) == 0 && len(removed) == 0 {
+			return Change{}, false
+		}
+		return Change{Field: name, Added: added, Removed: removed}, true
+	default:
+		if reflect.DeepEqual(b.Interface(), a.Interface()) {
+			return Change{}, false
+		}
+	}
+	return Change{Field: name, Old: b.Interface(), New: a.Interface()}, true
+}
+
+// diffElements returns the elements of a missing from b and the elements of
+// b missing from a. Duplicates are counted; order is ignored.
+func diffElements(b, a reflect.Value) (added, removed []any) {
+	remaining := make([]any, 0, b.Len())
+	for i := 0; i < b.Len(); i++ {
+		remaining = append(remaining, b.Index(i).Interface())
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		elem := a.Index(i).Interface()
+		found := false
+		for j, old := range remaining {
+			if reflect.DeepEqual(old, elem) {
+				remaining = append(remaining[:j], remaining[j+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, elem)
+		}
+	}
+	return added, remaining
+}
+
+func isList(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -7,7 +7,8 @@ import (
 	"runtime"
 )
 
-// ErrorCode represents application error codes
+// ErrorCode represents application error codes. New codes also go in the
+// enums of response.ErrorResponse, which documents them in the API spec.
 type ErrorCode string
 
 const (
@@ -20,6 +21,8 @@ const (
 	CodeUnauthorized   ErrorCode = "UNAUTHORIZED"
 	CodeForbidden      ErrorCode = "FORBIDDEN"
 	CodeTooManyRequests ErrorCode = "TOO_MANY_REQUESTS"
+	CodeFailedPrecondition ErrorCode = "FAILED_PRECONDITION" // the request is valid but not allowed in the current state
+	CodeTimeout        ErrorCode = "TIMEOUT" // the request ran past its deadline
 
 	// Auth specific errors
 	CodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
@@ -39,9 +42,21 @@ const (
 
 	// Business logic errors
 	CodeBookingExpired    ErrorCode = "BOOKING_EXPIRED"
+	CodeHoldExtensionLimit ErrorCode = "HOLD_EXTENSION_LIMIT_REACHED" // a seat hold already at its longest lifetime
 	CodePaymentFailed     ErrorCode = "PAYMENT_FAILED"
 	CodeInvalidPromoCode  ErrorCode = "INVALID_PROMO_CODE"
 	CodeSeatsAlreadyBooked ErrorCode = "SEATS_ALREADY_BOOKED"
+	CodeSalesNotOpen      ErrorCode = "SALES_NOT_OPEN"
+	CodeSalesClosed       ErrorCode = "SALES_CLOSED"
+	CodeShowtimeFull      ErrorCode = "SHOWTIME_FULL"
+	CodeInvalidStatus     ErrorCode = "INVALID_STATUS_TRANSITION"
+	CodeDeviceUnavailable ErrorCode = "DEVICE_UNAVAILABLE"
+	CodeSeatTypeNotOnSale ErrorCode = "SEAT_TYPE_NOT_ON_SALE"
+	CodeSeatTypeCapReached ErrorCode = "SEAT_TYPE_ONLINE_CAP_REACHED"
+	CodeInsufficientPoints ErrorCode = "INSUFFICIENT_LOYALTY_POINTS"
+	CodeInvalidTicket      ErrorCode = "INVALID_TICKET"     // a scanned ticket whose signature does not match
+	CodeAlreadyCheckedIn   ErrorCode = "ALREADY_CHECKED_IN" // a ticket scanned a second time
+	CodeVersionConflict    ErrorCode = "VERSION_CONFLICT"   // the row changed since it was read
 )
 
 // AppError represents an application error with context
@@ -130,11 +145,17 @@ func codeToHTTPStatus(code ErrorCode) int {
 	case CodeNotFound, CodeUserNotFound, CodeMovieNotFound, CodeBookingNotFound,
 		CodeShowtimeNotFound, CodeCinemaNotFound:
 		return http.StatusNotFound
-	case CodeConflict, CodeEmailAlreadyExists, CodeSeatsAlreadyBooked:
+	case CodeConflict, CodeEmailAlreadyExists, CodeSeatsAlreadyBooked, CodeShowtimeFull,
+		CodeInvalidStatus, CodeDeviceUnavailable, CodeSeatTypeCapReached, CodeAlreadyCheckedIn,
+		CodeVersionConflict:
 		return http.StatusConflict
 	case CodeTooManyRequests:
 		return http.StatusTooManyRequests
-	case CodeSeatNotAvailable, CodeBookingExpired, CodePaymentFailed, CodeInvalidPromoCode:
+	case CodeTimeout:
+		return http.StatusGatewayTimeout
+	case CodeSeatNotAvailable, CodeBookingExpired, CodeHoldExtensionLimit, CodePaymentFailed, CodeInvalidPromoCode,
+		CodeSalesNotOpen, CodeSalesClosed, CodeSeatTypeNotOnSale, CodeFailedPrecondition,
+		CodeInsufficientPoints, CodeInvalidTicket:
 		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
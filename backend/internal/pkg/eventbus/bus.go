@@ -0,0 +1,222 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/worker"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Event is a domain event published on the bus
+type Event interface {
+	// EventName identifies the event type subscribers register for
+	EventName() string
+	// AggregateID keys delivery ordering; events with the same key are
+	// delivered to each subscriber in publish order
+	AggregateID() string
+}
+
+// Handler processes a single event
+type Handler func(ctx context.Context, event Event) error
+
+// Config holds event bus tuning
+type Config struct {
+	Lanes          int           // ordered delivery lanes (one worker each)
+	QueueSize      int           // buffered events per lane
+	MaxAttempts    int           // attempts per subscriber before giving up
+	RetryBackoff   time.Duration // delay before the first retry, doubled per attempt
+	PublishTimeout time.Duration // how long Publish waits for a full lane
+}
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus is an in-process publish/subscribe bus. Subscribers run on worker
+// pools, isolated from each other and from the publisher.
+type Bus struct {
+	cfg         Config
+	lanes       []*worker.Pool
+	subscribers map[string][]subscriber
+	mu          sync.RWMutex
+	logger      *logger.Logger
+}
+
+// New creates a new event bus
+func New(cfg Config, log *logger.Logger) *Bus {
+	if cfg.Lanes <= 0 {
+		cfg.Lanes = 4
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 256
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.PublishTimeout <= 0 {
+		cfg.PublishTimeout = time.Second
+	}
+
+	lanes := make([]*worker.Pool, cfg.Lanes)
+	for i := range lanes {
+		lanes[i] = worker.NewPool(fmt.Sprintf("event-bus-%d", i), 1, cfg.QueueSize, log)
+	}
+
+	return &Bus{
+		cfg:         cfg,
+		lanes:       lanes,
+		subscribers: make(map[string][]subscriber),
+		logger:      log,
+	}
+}
+
+// Subscribe registers a named handler for an event type. Subscribers are
+// registered at wiring time, before Start.
+func (b *Bus) Subscribe(eventName, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[eventName] = append(b.subscribers[eventName], subscriber{name: name, handler: handler})
+}
+
+// Start starts the delivery lanes
+func (b *Bus) Start() {
+	for _, lane := range b.lanes {
+		lane.Start()
+	}
+	b.logger.Info("event bus started", zap.Int("lanes", len(b.lanes)))
+}
+
+// Stop stops the delivery lanes. Events still queued are dropped.
+func (b *Bus) Stop(timeout time.Duration) error {
+	var firstErr error
+	for _, lane := range b.lanes {
+		if err := lane.Stop(timeout); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Publish queues events for delivery. Events that must not be lost with
+// the process, or sent for work that was rolled back, go through the
+// outbox instead, which delivers them with Deliver once committed.
+func (b *Bus) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		subs := b.subscribersOf(event)
+		if len(subs) == 0 {
+			continue
+		}
+		if err := b.submit(ctx, event, subs, nil); err != nil {
+			b.logger.Error("failed to publish event",
+				zap.String("event", event.EventName()),
+				zap.String("aggregate_id", event.AggregateID()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// Deliver queues an event like Publish and waits until every subscriber
+// has handled it or given up. An error means the event may not have been
+// delivered and should be tried again.
+func (b *Bus) Deliver(ctx context.Context, event Event) error {
+	subs := b.subscribersOf(event)
+	if len(subs) == 0 {
+		return nil
+	}
+
+	done := make(chan struct{})
+	if err := b.submit(ctx, event, subs, done); err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) subscribersOf(event Event) []subscriber {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.subscribers[event.EventName()]
+}
+
+// submit queues delivery of the event on its lane, closing done, if given,
+// once the subscribers ran
+func (b *Bus) submit(ctx context.Context, event Event, subs []subscriber, done chan struct{}) error {
+	job := worker.Job{
+		ID:      uuid.New().String(),
+		Type:    event.EventName(),
+		Payload: event,
+		Handler: func(ctx context.Context, payload interface{}) error {
+			b.deliver(ctx, payload.(Event), subs)
+			if done != nil {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	// Detach from the request so cancellation does not drop the event
+	submitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b.cfg.PublishTimeout)
+	defer cancel()
+	return b.laneFor(event).SubmitWait(submitCtx, job)
+}
+
+// laneFor pins every aggregate to one lane so its events stay ordered
+func (b *Bus) laneFor(event Event) *worker.Pool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(event.AggregateID()))
+	return b.lanes[h.Sum32()%uint32(len(b.lanes))]
+}
+
+// deliver runs every subscriber in turn; a failing or panicking subscriber
+// is retried and then logged without affecting the others
+func (b *Bus) deliver(ctx context.Context, event Event, subs []subscriber) {
+	for _, sub := range subs {
+		backoff := b.cfg.RetryBackoff
+		for attempt := 1; ; attempt++ {
+			err := b.invoke(ctx, sub, event)
+			if err == nil {
+				break
+			}
+
+			if attempt >= b.cfg.MaxAttempts {
+				b.logger.Error("event subscriber failed",
+					zap.String("event", event.EventName()),
+					zap.String("subscriber", sub.name),
+					zap.String("aggregate_id", event.AggregateID()),
+					zap.Int("attempts", attempt),
+					zap.Error(err),
+				)
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+func (b *Bus) invoke(ctx context.Context, sub subscriber, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber panicked: %v", r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
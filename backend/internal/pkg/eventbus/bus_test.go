@@ -0,0 +1,184 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type testEvent struct {
+	aggregate string
+	seq       int
+}
+
+func (e testEvent) EventName() string   { return "test.event" }
+func (e testEvent) AggregateID() string { return e.aggregate }
+
+func newTestBus(t *testing.T, cfg Config) *Bus {
+	t.Helper()
+	bus := New(cfg, &logger.Logger{Logger: zap.NewNop()})
+	t.Cleanup(func() { _ = bus.Stop(time.Second) })
+	return bus
+}
+
+// waitFor polls until cond holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFailingSubscriberIsIsolated(t *testing.T) {
+	bus := newTestBus(t, Config{Lanes: 1, MaxAttempts: 3, RetryBackoff: time.Millisecond})
+
+	var failing, panicking, healthy atomic.Int32
+	bus.Subscribe("test.event", "failing", func(context.Context, Event) error {
+		failing.Add(1)
+		return errors.New("always fails")
+	})
+	bus.Subscribe("test.event", "panicking", func(context.Context, Event) error {
+		panicking.Add(1)
+		panic("boom")
+	})
+	bus.Subscribe("test.event", "healthy", func(context.Context, Event) error {
+		healthy.Add(1)
+		return nil
+	})
+	bus.Start()
+
+	bus.Publish(context.Background(), testEvent{aggregate: "booking-1"}, testEvent{aggregate: "booking-1"})
+
+	waitFor(t, "healthy subscriber", func() bool { return healthy.Load() == 2 })
+	if got := failing.Load(); got != 6 {
+		t.Errorf("failing subscriber ran %d times, want 3 attempts per event", got)
+	}
+	if got := panicking.Load(); got != 6 {
+		t.Errorf("panicking subscriber ran %d times, want 3 attempts per event", got)
+	}
+}
+
+func TestRetriedSubscriberSucceeds(t *testing.T) {
+	bus := newTestBus(t, Config{Lanes: 1, MaxAttempts: 3})
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	bus.Subscribe("test.event", "flaky", func(context.Context, Event) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		close(done)
+		return nil
+	})
+	bus.Start()
+
+	bus.Publish(context.Background(), testEvent{aggregate: "booking-1"})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("subscriber did not succeed, %d attempts", attempts.Load())
+	}
+}
+
+func TestOrderingPerAggregate(t *testing.T) {
+	bus := newTestBus(t, Config{Lanes: 4, QueueSize: 1024})
+
+	var mu sync.Mutex
+	seen := make(map[string][]int)
+	bus.Subscribe("test.event", "recorder", func(_ context.Context, event Event) error {
+		e := event.(testEvent)
+		// A slow first event must not let later ones overtake it
+		if e.seq == 0 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		mu.Lock()
+		seen[e.aggregate] = append(seen[e.aggregate], e.seq)
+		mu.Unlock()
+		return nil
+	})
+	bus.Start()
+
+	const bookings, perBooking = 8, 50
+	var wg sync.WaitGroup
+	for b := 0; b < bookings; b++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for seq := 0; seq < perBooking; seq++ {
+				bus.Publish(context.Background(), testEvent{aggregate: id, seq: seq})
+			}
+		}(fmt.Sprintf("booking-%d", b))
+	}
+	wg.Wait()
+
+	waitFor(t, "all events", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		total := 0
+		for _, seqs := range seen {
+			total += len(seqs)
+		}
+		return total == bookings*perBooking
+	})
+
+	for id, seqs := range seen {
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("%s: event %d delivered at position %d: %v", id, seq, i, seqs)
+			}
+		}
+	}
+}
+
+func TestDeliverWaitsForSubscribers(t *testing.T) {
+	bus := newTestBus(t, Config{Lanes: 1, MaxAttempts: 2})
+
+	var handled []string
+	bus.Subscribe("test.event", "slow", func(_ context.Context, event Event) error {
+		time.Sleep(20 * time.Millisecond)
+		handled = append(handled, "slow:"+event.AggregateID())
+		return nil
+	})
+	bus.Subscribe("test.event", "failing", func(context.Context, Event) error {
+		return errors.New("subscriber down")
+	})
+	bus.Start()
+
+	// Every subscriber has run, or given up, by the time Deliver returns
+	if err := bus.Deliver(context.Background(), testEvent{aggregate: "booking-1"}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if len(handled) != 1 || handled[0] != "slow:booking-1" {
+		t.Errorf("handled %v when Deliver returned", handled)
+	}
+
+	// Events nobody subscribes to are delivered at once
+	if err := bus.Deliver(context.Background(), otherEvent{}); err != nil {
+		t.Errorf("Deliver without subscribers: %v", err)
+	}
+
+	// A caller that stops waiting is told the event may not have arrived
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := bus.Deliver(ctx, testEvent{aggregate: "booking-2"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Deliver past the deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+type otherEvent struct{}
+
+func (otherEvent) EventName() string   { return "other.event" }
+func (otherEvent) AggregateID() string { return "other" }
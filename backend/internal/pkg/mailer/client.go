@@ -0,0 +1,214 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+
+	"github.com/google/uuid"
+)
+
+// ErrQueueFull is returned when an email cannot be queued for delivery
+var ErrQueueFull = errors.New("email queue full")
+
+// seatPlanContentID references the inline seat plan from the confirmation
+// template
+const seatPlanContentID = "seatplan"
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"join":   func(items []string) string { return strings.Join(items, ", ") },
+	"money":  func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	"expiry": formatExpiry,
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// EmailClient sends the account and booking emails
+type EmailClient interface {
+	SendPasswordReset(ctx context.Context, email PasswordReset) error
+	SendEmailVerification(ctx context.Context, email EmailVerification) error
+	SendBookingConfirmation(ctx context.Context, email BookingConfirmation) error
+	SendBookingCancellation(ctx context.Context, email BookingCancellation) error
+}
+
+// PasswordReset is an email with a password reset link
+type PasswordReset struct {
+	To     string
+	Name   string
+	Token  string // raw reset token, put in the link
+	Expiry time.Duration
+}
+
+// EmailVerification is an email with a link confirming the address
+type EmailVerification struct {
+	To     string
+	Name   string
+	Token  string // raw verification token, put in the link
+	Expiry time.Duration
+}
+
+// BookingConfirmation is the email confirming a booking
+type BookingConfirmation struct {
+	To             string
+	Name           string // greeting is left out when empty
+	BookingID      uuid.UUID
+	Reference      string
+	Movie          string
+	Cinema         string
+	Screen         string
+	StartsAt       time.Time // in the cinema's time zone
+	Seats          []string
+	Devices        []string // reserved assistive devices, such as "1 x Booster seat"
+	Subtotal       float64
+	Discount       float64
+	PointsRedeemed int
+	PointsDiscount float64
+	Fee            float64
+	Tax            float64
+	Total          float64
+	SeatPlan       []byte // PNG shown inline; optional
+}
+
+// BookingCancellation is the email telling a customer their booking was
+// cancelled
+type BookingCancellation struct {
+	To        string
+	Name      string // greeting is left out when empty
+	MovieID   uuid.UUID
+	Reference string
+	Movie     string
+	Cinema    string
+	Screen    string
+	StartsAt  time.Time // in the cinema's time zone
+	Seats     []string
+	Reason    string
+	Refund    float64
+	Paid      bool // says that no refund applies when there is none
+}
+
+// Client renders emails from the templates in templates/ and queues them on
+// the dispatcher, whose sender delivers them over SMTP
+type Client struct {
+	dispatcher  *async.Dispatcher
+	frontendURL string
+}
+
+// NewClient creates an email client. Links in the emails point at
+// frontendURL.
+func NewClient(dispatcher *async.Dispatcher, frontendURL string) *Client {
+	return &Client{dispatcher: dispatcher, frontendURL: frontendURL}
+}
+
+// SendPasswordReset queues the email with the reset link
+func (c *Client) SendPasswordReset(_ context.Context, email PasswordReset) error {
+	body, err := render("password_reset.html", struct {
+		PasswordReset
+		Link string
+	}{email, fmt.Sprintf("%s/reset-password?token=%s", c.frontendURL, email.Token)})
+	if err != nil {
+		return err
+	}
+	return c.submit(async.EmailPayload{
+		To:      []string{email.To},
+		Subject: "Reset your password",
+		Body:    body,
+		IsHTML:  true,
+	})
+}
+
+// SendEmailVerification queues the email with the verification link
+func (c *Client) SendEmailVerification(_ context.Context, email EmailVerification) error {
+	body, err := render("email_verification.html", struct {
+		EmailVerification
+		Link string
+	}{email, fmt.Sprintf("%s/verify-email?token=%s", c.frontendURL, email.Token)})
+	if err != nil {
+		return err
+	}
+	return c.submit(async.EmailPayload{
+		To:      []string{email.To},
+		Subject: "Confirm your email address",
+		Body:    body,
+		IsHTML:  true,
+	})
+}
+
+// SendBookingConfirmation queues the confirmation, with the seat plan
+// inline when there is one
+func (c *Client) SendBookingConfirmation(_ context.Context, email BookingConfirmation) error {
+	body, err := render("booking_confirmation.html", struct {
+		BookingConfirmation
+		Link string
+	}{email, fmt.Sprintf("%s/bookings/%s", c.frontendURL, email.BookingID)})
+	if err != nil {
+		return err
+	}
+	payload := async.EmailPayload{
+		To:      []string{email.To},
+		Subject: "Booking " + email.Reference + " is confirmed",
+		Body:    body,
+		IsHTML:  true,
+	}
+	if len(email.SeatPlan) > 0 {
+		payload.Attachments = []async.EmailAttachment{{
+			Filename:    "seats-" + email.Reference + ".png",
+			ContentType: "image/png",
+			ContentID:   seatPlanContentID,
+			Data:        email.SeatPlan,
+		}}
+	}
+	return c.submit(payload)
+}
+
+// SendBookingCancellation queues the cancellation, with a link to the
+// movie's other showtimes
+func (c *Client) SendBookingCancellation(_ context.Context, email BookingCancellation) error {
+	body, err := render("booking_cancellation.html", struct {
+		BookingCancellation
+		Link string
+	}{email, fmt.Sprintf("%s/movies/%s", c.frontendURL, email.MovieID)})
+	if err != nil {
+		return err
+	}
+	return c.submit(async.EmailPayload{
+		To:      []string{email.To},
+		Subject: "Booking " + email.Reference + " is cancelled",
+		Body:    body,
+		IsHTML:  true,
+	})
+}
+
+func (c *Client) submit(email async.EmailPayload) error {
+	if !c.dispatcher.SubmitEmail(email) {
+		return ErrQueueFull
+	}
+	return nil
+}
+
+func render(name string, data any) (string, error) {
+	var body bytes.Buffer
+	if err := templates.ExecuteTemplate(&body, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return body.String(), nil
+}
+
+// formatExpiry describes a link lifetime in whole hours or minutes
+func formatExpiry(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		hours := int(d.Hours())
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
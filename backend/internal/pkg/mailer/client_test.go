@@ -0,0 +1,219 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeSender struct {
+	sent chan async.EmailPayload
+}
+
+func (f *fakeSender) Send(_ context.Context, email async.EmailPayload) error {
+	f.sent <- email
+	return nil
+}
+
+func newTestClient(t *testing.T, sender async.EmailSender) *Client {
+	t.Helper()
+	dispatcher := async.NewDispatcher(1, 10, sender, &logger.Logger{Logger: zap.NewNop()})
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(time.Second) })
+	return NewClient(dispatcher, "https://cinema.example.com")
+}
+
+func receive(t *testing.T, sender *fakeSender) async.EmailPayload {
+	t.Helper()
+	select {
+	case email := <-sender.sent:
+		return email
+	case <-time.After(time.Second):
+		t.Fatal("no email sent")
+		return async.EmailPayload{}
+	}
+}
+
+func TestSendPasswordReset(t *testing.T) {
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 1)}
+	client := newTestClient(t, sender)
+
+	err := client.SendPasswordReset(context.Background(), PasswordReset{
+		To: "fan@example.com", Name: "<b>Fan</b>", Token: "abc123", Expiry: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("SendPasswordReset: %v", err)
+	}
+	email := receive(t, sender)
+	if email.To[0] != "fan@example.com" || email.Subject != "Reset your password" || !email.IsHTML {
+		t.Errorf("email to %v, subject %q, HTML %v", email.To, email.Subject, email.IsHTML)
+	}
+	for _, want := range []string{
+		`href="https://cinema.example.com/reset-password?token=abc123"`,
+		"1 hour",
+		"&lt;b&gt;Fan&lt;/b&gt;",
+	} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("body is missing %q:\n%s", want, email.Body)
+		}
+	}
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 1)}
+	client := newTestClient(t, sender)
+
+	err := client.SendEmailVerification(context.Background(), EmailVerification{
+		To: "fan@example.com", Name: "Fan", Token: "xyz789", Expiry: 48 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("SendEmailVerification: %v", err)
+	}
+	email := receive(t, sender)
+	if email.Subject != "Confirm your email address" {
+		t.Errorf("subject %q", email.Subject)
+	}
+	for _, want := range []string{`href="https://cinema.example.com/verify-email?token=xyz789"`, "48 hours"} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("body is missing %q:\n%s", want, email.Body)
+		}
+	}
+}
+
+func TestSendBookingConfirmation(t *testing.T) {
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 2)}
+	client := newTestClient(t, sender)
+	booking := BookingConfirmation{
+		To:             "fan@example.com",
+		Name:           "Fan",
+		BookingID:      uuid.MustParse("8a0f8f5e-6f4b-4a55-9d6a-1f2d3c4b5a69"),
+		Reference:      "BK-7Q2M",
+		Movie:          "Dune: Part Two",
+		Cinema:         "Downtown",
+		Screen:         "Screen 3",
+		StartsAt:       time.Date(2026, 3, 6, 19, 30, 0, 0, time.UTC),
+		Seats:          []string{"F7", "F8"},
+		Subtotal:       24,
+		PointsRedeemed: 500,
+		PointsDiscount: 5,
+		Total:          19,
+		SeatPlan:       []byte("png"),
+	}
+
+	if err := client.SendBookingConfirmation(context.Background(), booking); err != nil {
+		t.Fatalf("SendBookingConfirmation: %v", err)
+	}
+	email := receive(t, sender)
+	if email.Subject != "Booking BK-7Q2M is confirmed" {
+		t.Errorf("subject %q", email.Subject)
+	}
+	for _, want := range []string{
+		"Dune: Part Two",
+		"Fri, 06 Mar 2026 19:30",
+		"Seats: F7, F8",
+		"Loyalty points (500): -5.00",
+		"Total: 19.00",
+		`src="cid:seatplan"`,
+		`href="https://cinema.example.com/bookings/8a0f8f5e-6f4b-4a55-9d6a-1f2d3c4b5a69"`,
+	} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("body is missing %q:\n%s", want, email.Body)
+		}
+	}
+	if strings.Contains(email.Body, "Discount:") || strings.Contains(email.Body, "Booking fee") {
+		t.Errorf("body lists charges the booking does not have:\n%s", email.Body)
+	}
+	if len(email.Attachments) != 1 || email.Attachments[0].ContentID != seatPlanContentID ||
+		email.Attachments[0].Filename != "seats-BK-7Q2M.png" || string(email.Attachments[0].Data) != "png" {
+		t.Errorf("attachments %+v, want the seat plan inline", email.Attachments)
+	}
+
+	// Without a seat plan there is nothing to attach or reference
+	booking.SeatPlan = nil
+	if err := client.SendBookingConfirmation(context.Background(), booking); err != nil {
+		t.Fatalf("SendBookingConfirmation: %v", err)
+	}
+	email = receive(t, sender)
+	if len(email.Attachments) != 0 || strings.Contains(email.Body, "cid:") {
+		t.Errorf("email without a seat plan has attachments %+v", email.Attachments)
+	}
+}
+
+func TestSendBookingCancellation(t *testing.T) {
+	sender := &fakeSender{sent: make(chan async.EmailPayload, 2)}
+	client := newTestClient(t, sender)
+	movieID := uuid.MustParse("0c3d2f1e-5b6a-4c7d-8e9f-a0b1c2d3e4f5")
+	cancellation := BookingCancellation{
+		To:        "fan@example.com",
+		MovieID:   movieID,
+		Reference: "BK-7Q2M",
+		Movie:     "Dune: Part Two",
+		Cinema:    "Downtown",
+		Screen:    "Screen 3",
+		StartsAt:  time.Date(2026, 3, 6, 19, 30, 0, 0, time.UTC),
+		Seats:     []string{"F7"},
+		Reason:    "The showtime was cancelled",
+		Refund:    12.5,
+		Paid:      true,
+	}
+
+	if err := client.SendBookingCancellation(context.Background(), cancellation); err != nil {
+		t.Fatalf("SendBookingCancellation: %v", err)
+	}
+	email := receive(t, sender)
+	if email.Subject != "Booking BK-7Q2M is cancelled" {
+		t.Errorf("subject %q", email.Subject)
+	}
+	for _, want := range []string{"The showtime was cancelled", "12.50", `href="https://cinema.example.com/movies/` + movieID.String() + `"`} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("body is missing %q:\n%s", want, email.Body)
+		}
+	}
+	if strings.Contains(email.Body, "Hi ") {
+		t.Errorf("body greets a customer without a name:\n%s", email.Body)
+	}
+
+	cancellation.Refund = 0
+	if err := client.SendBookingCancellation(context.Background(), cancellation); err != nil {
+		t.Fatalf("SendBookingCancellation: %v", err)
+	}
+	if email := receive(t, sender); !strings.Contains(email.Body, "No refund applies") {
+		t.Errorf("paid booking without a refund does not say so:\n%s", email.Body)
+	}
+}
+
+func TestSendWithoutDispatcher(t *testing.T) {
+	// A stopped dispatcher takes no jobs
+	dispatcher := async.NewDispatcher(1, 10, &fakeSender{}, &logger.Logger{Logger: zap.NewNop()})
+	client := NewClient(dispatcher, "https://cinema.example.com")
+
+	err := client.SendPasswordReset(context.Background(), PasswordReset{To: "fan@example.com", Token: "abc123", Expiry: time.Hour})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("err = %v, want %v", err, ErrQueueFull)
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	tests := []struct {
+		expiry time.Duration
+		want   string
+	}{
+		{time.Hour, "1 hour"},
+		{24 * time.Hour, "24 hours"},
+		{30 * time.Minute, "30 minutes"},
+		{90 * time.Minute, "90 minutes"},
+	}
+	for _, tt := range tests {
+		if got := formatExpiry(tt.expiry); got != tt.want {
+			t.Errorf("formatExpiry(%v) = %q, want %q", tt.expiry, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+// mailpit is a Mailpit server catching the emails sent to its SMTP port
+type mailpit struct {
+	host    string
+	smtp    int
+	baseURL string // of the HTTP API
+}
+
+// newMailpit starts Mailpit in a container. The test is skipped with -short
+// or when there is no Docker to run it in.
+func newMailpit(t *testing.T) *mailpit {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping the Mailpit container in short mode")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	container, err := testcontainers.Run(ctx, "axllent/mailpit:v1.21",
+		testcontainers.WithExposedPorts("1025/tcp", "8025/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/livez").WithPort("8025/tcp")),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start mailpit: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("mailpit host: %v", err)
+	}
+	smtpPort, err := container.MappedPort(ctx, "1025/tcp")
+	if err != nil {
+		t.Fatalf("mailpit SMTP port: %v", err)
+	}
+	apiPort, err := container.MappedPort(ctx, "8025/tcp")
+	if err != nil {
+		t.Fatalf("mailpit API port: %v", err)
+	}
+	return &mailpit{
+		host:    host,
+		smtp:    smtpPort.Int(),
+		baseURL: "http://" + host + ":" + strconv.Itoa(apiPort.Int()),
+	}
+}
+
+type mailpitMessage struct {
+	ID      string
+	Subject string
+	To      []struct{ Address string }
+	HTML    string
+	Inline  []struct {
+		FileName    string
+		ContentType string
+		ContentID   string
+	}
+}
+
+// message waits for the email with subject and fetches it
+func (m *mailpit) message(t *testing.T, subject string) mailpitMessage {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var list struct{ Messages []mailpitMessage }
+		m.get(t, "/api/v1/messages", &list)
+		for _, summary := range list.Messages {
+			if summary.Subject == subject {
+				var msg mailpitMessage
+				m.get(t, "/api/v1/message/"+summary.ID, &msg)
+				return msg
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("no email %q reached mailpit", subject)
+	return mailpitMessage{}
+}
+
+func (m *mailpit) get(t *testing.T, path string, v any) {
+	t.Helper()
+	resp, err := http.Get(m.baseURL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+}
+
+func TestMailpitDelivery(t *testing.T) {
+	server := newMailpit(t)
+	ctx := context.Background()
+	sender := NewSMTPSender(Config{
+		Host:        server.host,
+		Port:        server.smtp,
+		FromAddress: "tickets@cinema.example.com",
+		FromName:    "CinemaOS",
+		Timeout:     5 * time.Second,
+	})
+	dispatcher := async.NewDispatcher(1, 10, sender, &logger.Logger{Logger: zap.NewNop()})
+	dispatcher.Start()
+	t.Cleanup(func() { dispatcher.Stop(5 * time.Second) })
+	client := NewClient(dispatcher, "https://cinema.example.com")
+
+	if err := client.SendPasswordReset(ctx, PasswordReset{
+		To: "fan@example.com", Name: "Fan", Token: "abc123", Expiry: time.Hour,
+	}); err != nil {
+		t.Fatalf("SendPasswordReset: %v", err)
+	}
+	reset := server.message(t, "Reset your password")
+	if len(reset.To) != 1 || reset.To[0].Address != "fan@example.com" {
+		t.Errorf("reset sent to %v", reset.To)
+	}
+	if !strings.Contains(reset.HTML, "https://cinema.example.com/reset-password?token=abc123") {
+		t.Errorf("reset email is missing the link:\n%s", reset.HTML)
+	}
+
+	bookingID := uuid.New()
+	if err := client.SendBookingConfirmation(ctx, BookingConfirmation{
+		To:        "fan@example.com",
+		BookingID: bookingID,
+		Reference: "BK-7Q2M",
+		Movie:     "Dune: Part Two",
+		Cinema:    "Downtown",
+		Screen:    "Screen 3",
+		StartsAt:  time.Date(2026, 3, 6, 19, 30, 0, 0, time.UTC),
+		Seats:     []string{"F7", "F8"},
+		Subtotal:  24,
+		Total:     24,
+		SeatPlan:  []byte("\x89PNG\r\n\x1a\n"),
+	}); err != nil {
+		t.Fatalf("SendBookingConfirmation: %v", err)
+	}
+	confirmation := server.message(t, "Booking BK-7Q2M is confirmed")
+	if !strings.Contains(confirmation.HTML, fmt.Sprintf("https://cinema.example.com/bookings/%s", bookingID)) ||
+		!strings.Contains(confirmation.HTML, "Seats: F7, F8") {
+		t.Errorf("confirmation body:\n%s", confirmation.HTML)
+	}
+	if len(confirmation.Inline) != 1 || confirmation.Inline[0].ContentID != seatPlanContentID ||
+		confirmation.Inline[0].ContentType != "image/png" {
+		t.Errorf("inline parts %+v, want the seat plan", confirmation.Inline)
+	}
+}
@@ -0,0 +1,311 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// implicitTLSPort is the SMTPS port, where TLS starts before SMTP does.
+// Other ports upgrade with STARTTLS when the server offers it.
+const implicitTLSPort = 465
+
+// Config holds SMTP settings
+type Config struct {
+	Host        string
+	Port        int
+	Username    string // no AUTH when empty
+	Password    string
+	FromAddress string
+	FromName    string
+	Timeout     time.Duration // for a whole delivery
+}
+
+// SMTPSender delivers emails through an SMTP server
+type SMTPSender struct {
+	cfg  Config
+	from mail.Address
+}
+
+// NewSMTPSender creates an SMTP email sender
+func NewSMTPSender(cfg Config) *SMTPSender {
+	return &SMTPSender{
+		cfg:  cfg,
+		from: mail.Address{Name: cfg.FromName, Address: cfg.FromAddress},
+	}
+}
+
+// Send delivers an email to all its recipients
+func (s *SMTPSender) Send(ctx context.Context, email async.EmailPayload) error {
+	if len(email.To) == 0 {
+		return errors.New("email has no recipients")
+	}
+	msg, err := s.buildMessage(email)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp dial failed: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.Port == implicitTLSPort {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && s.cfg.Port != implicitTLSPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.cfg.FromAddress); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, to := range email.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected email: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage renders the email as MIME. Attachments with a ContentID go
+// into a multipart/related part with the body so cid: references resolve;
+// the rest are attached to a multipart/mixed envelope.
+func (s *SMTPSender) buildMessage(email async.EmailPayload) ([]byte, error) {
+	var inline, attached []async.EmailAttachment
+	for _, a := range email.Attachments {
+		if a.ContentID != "" {
+			inline = append(inline, a)
+		} else {
+			attached = append(attached, a)
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", s.from.String())
+	header.Set("To", strings.Join(email.To, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", "<"+uuid.NewString()+"@"+domainOf(s.cfg.FromAddress)+">")
+	header.Set("MIME-Version", "1.0")
+
+	var msg bytes.Buffer
+	if len(email.Attachments) == 0 {
+		header.Set("Content-Type", contentType(email))
+		header.Set("Content-Transfer-Encoding", "base64")
+		writeHeader(&msg, header)
+		writeBase64(&msg, []byte(email.Body))
+		return msg.Bytes(), nil
+	}
+
+	// The body with its inline images
+	related := func(w *multipart.Writer) error {
+		if err := writeBody(w, email); err != nil {
+			return err
+		}
+		for _, a := range inline {
+			if err := writeAttachment(w, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var parts bytes.Buffer
+	outer := multipart.NewWriter(&parts)
+	var err error
+	switch {
+	case len(attached) == 0:
+		header.Set("Content-Type", "multipart/related; boundary="+outer.Boundary())
+		err = related(outer)
+	case len(inline) == 0:
+		header.Set("Content-Type", "multipart/mixed; boundary="+outer.Boundary())
+		err = writeBody(outer, email)
+	default:
+		header.Set("Content-Type", "multipart/mixed; boundary="+outer.Boundary())
+		err = writeNested(outer, "related", related)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attached {
+		if err := writeAttachment(outer, a); err != nil {
+			return nil, err
+		}
+	}
+	if err := outer.Close(); err != nil {
+		return nil, err
+	}
+
+	writeHeader(&msg, header)
+	msg.Write(parts.Bytes())
+	return msg.Bytes(), nil
+}
+
+func writeNested(w *multipart.Writer, subtype string, fill func(*multipart.Writer) error) error {
+	var buf bytes.Buffer
+	nested := multipart.NewWriter(&buf)
+	if err := fill(nested); err != nil {
+		return err
+	}
+	if err := nested.Close(); err != nil {
+		return err
+	}
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/" + subtype + "; boundary=" + nested.Boundary()},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(buf.Bytes())
+	return err
+}
+
+func writeBody(w *multipart.Writer, email async.EmailPayload) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType(email)},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeBase64(&buf, []byte(email.Body))
+	_, err = part.Write(buf.Bytes())
+	return err
+}
+
+func writeAttachment(w *multipart.Writer, a async.EmailAttachment) error {
+	header := textproto.MIMEHeader{}
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "base64")
+	disposition := "attachment"
+	if a.ContentID != "" {
+		disposition = "inline"
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeBase64(&buf, a.Data)
+	_, err = part.Write(buf.Bytes())
+	return err
+}
+
+func contentType(email async.EmailPayload) string {
+	if email.IsHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			buf.WriteString(key + ": " + value + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeBase64 encodes data in lines of 76 characters as RFC 2045 requires
+func writeBase64(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
+
+// LogSender only logs emails. It stands in for SMTP in development, when
+// no mail server is configured.
+type LogSender struct {
+	logger *logger.Logger
+}
+
+// NewLogSender creates an email sender that logs instead of sending
+func NewLogSender(log *logger.Logger) *LogSender {
+	return &LogSender{logger: log}
+}
+
+// Send logs the email's recipients and subject
+func (s *LogSender) Send(ctx context.Context, email async.EmailPayload) error {
+	s.logger.Info("email not sent, no mail server configured",
+		zap.Strings("to", email.To),
+		zap.String("subject", email.Subject),
+		zap.Int("attachments", len(email.Attachments)),
+	)
+	return nil
+}
@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// gaugeTimeout bounds how long a scrape waits for a gauge's value
+const gaugeTimeout = 2 * time.Second
+
+// requestDurationBuckets are the latency buckets of http_request_duration_seconds
+var requestDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// queryDurationBuckets are the latency buckets of db_query_duration_seconds
+var queryDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Config holds metrics configuration
+type Config struct {
+	Enabled     bool
+	ServiceName string
+	Environment string
+	Version     string
+}
+
+// Gauges reads the values reported as gauges on each scrape. A nil
+// function leaves its gauge out.
+type Gauges struct {
+	ActiveSeatHolds func(ctx context.Context) (int64, error)
+	RedisPoolSize   func() int64
+	// MovieListCache returns the movie listing reads served from the cache
+	// and from the database so far, reported as counters
+	MovieListCache func() (hits, misses int64)
+}
+
+// Metrics records application metrics with OpenTelemetry and serves them in
+// the Prometheus exposition format
+type Metrics struct {
+	provider *sdkmetric.MeterProvider
+	handler  http.Handler
+	enabled  bool
+
+	httpRequests      metric.Int64Counter
+	httpDuration      metric.Float64Histogram
+	queryDuration     metric.Float64Histogram
+	bookingsCreated   metric.Int64Counter
+	bookingsCancelled metric.Int64Counter
+	seatsHeld         metric.Int64Counter
+}
+
+// New creates a new metrics instance. When disabled, every instrument is a
+// noop and no handler is served.
+func New(cfg Config, gauges Gauges) (*Metrics, error) {
+	if !cfg.Enabled {
+		m := &Metrics{}
+		if err := m.createInstruments(noop.NewMeterProvider().Meter(cfg.ServiceName)); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	// Metrics get their own registry so only what is recorded here is served
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(
+		otelprometheus.WithRegisterer(registry),
+		otelprometheus.WithoutScopeInfo(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.Version),
+			attribute.String("environment", cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+	)
+	m := &Metrics{
+		provider: provider,
+		handler:  promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+		enabled:  true,
+	}
+
+	meter := provider.Meter(cfg.ServiceName)
+	if err := m.createInstruments(meter); err != nil {
+		return nil, err
+	}
+	if err := registerGauges(meter, gauges); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Metrics) createInstruments(meter metric.Meter) error {
+	var err error
+	if m.httpRequests, err = meter.Int64Counter("http_requests_total",
+		metric.WithDescription("HTTP requests served, by method, route and status")); err != nil {
+		return err
+	}
+	if m.httpDuration, err = meter.Float64Histogram("http_request_duration_seconds",
+		metric.WithDescription("HTTP request latency, by method, route and status"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(requestDurationBuckets...)); err != nil {
+		return err
+	}
+	if m.queryDuration, err = meter.Float64Histogram("db_query_duration_seconds",
+		metric.WithDescription("Database query latency, by whether the query failed"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(queryDurationBuckets...)); err != nil {
+		return err
+	}
+	if m.bookingsCreated, err = meter.Int64Counter("booking_created_total",
+		metric.WithDescription("Bookings confirmed")); err != nil {
+		return err
+	}
+	if m.bookingsCancelled, err = meter.Int64Counter("booking_cancelled_total",
+		metric.WithDescription("Bookings cancelled")); err != nil {
+		return err
+	}
+	if m.seatsHeld, err = meter.Int64Counter("seats_held_total",
+		metric.WithDescription("Seats held for checkout")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// registerGauges observes the gauges on each scrape. A value that cannot be
+// read is left out of that scrape rather than reported as zero.
+func registerGauges(meter metric.Meter, gauges Gauges) error {
+	if gauges.ActiveSeatHolds != nil {
+		_, err := meter.Int64ObservableGauge("active_seat_holds",
+			metric.WithDescription("Seat holds that have not expired"),
+			metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+				ctx, cancel := context.WithTimeout(ctx, gaugeTimeout)
+				defer cancel()
+				if count, err := gauges.ActiveSeatHolds(ctx); err == nil {
+					o.Observe(count)
+				}
+				return nil
+			}))
+		if err != nil {
+			return err
+		}
+	}
+	if gauges.RedisPoolSize != nil {
+		_, err := meter.Int64ObservableGauge("redis_pool_size",
+			metric.WithDescription("Connections open in the Redis pool"),
+			metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+				o.Observe(gauges.RedisPoolSize())
+				return nil
+			}))
+		if err != nil {
+			return err
+		}
+	}
+	if gauges.MovieListCache != nil {
+		_, err := meter.Int64ObservableCounter("movie_list_cache_requests_total",
+			metric.WithDescription("Movie listing reads, by whether the cache had them"),
+			metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+				hits, misses := gauges.MovieListCache()
+				o.Observe(hits, metric.WithAttributes(attribute.String("result", "hit")))
+				o.Observe(misses, metric.WithAttributes(attribute.String("result", "miss")))
+				return nil
+			}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordRequest records a served HTTP request. route is the matched route
+// pattern, not the raw path, to keep the number of series bounded.
+func (m *Metrics) RecordRequest(ctx context.Context, method, route string, status int, duration time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("route", route),
+		attribute.String("status", strconv.Itoa(status)),
+	)
+	m.httpRequests.Add(ctx, 1, attrs)
+	m.httpDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+// RecordQuery records a database query. A query finding no rows has not
+// failed.
+func (m *Metrics) RecordQuery(ctx context.Context, duration time.Duration, failed bool) {
+	m.queryDuration.Record(ctx, duration.Seconds(),
+		metric.WithAttributes(attribute.Bool("failed", failed)))
+}
+
+// BookingCreated records a confirmed booking
+func (m *Metrics) BookingCreated(ctx context.Context) {
+	m.bookingsCreated.Add(ctx, 1)
+}
+
+// BookingCancelled records a cancelled booking
+func (m *Metrics) BookingCancelled(ctx context.Context) {
+	m.bookingsCancelled.Add(ctx, 1)
+}
+
+// SeatsHeld records seats held for checkout
+func (m *Metrics) SeatsHeld(ctx context.Context, count int) {
+	m.seatsHeld.Add(ctx, int64(count))
+}
+
+// Handler serves the metrics in the Prometheus exposition format, nil when
+// metrics are disabled
+func (m *Metrics) Handler() http.Handler {
+	return m.handler
+}
+
+// Shutdown shuts down the meter provider
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	if m.provider == nil {
+		return nil
+	}
+	return m.provider.Shutdown(ctx)
+}
+
+// IsEnabled returns whether metrics are enabled
+func (m *Metrics) IsEnabled() bool {
+	return m.enabled
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape returns the metrics as Prometheus serves them
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape: status %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	m, err := New(Config{Enabled: true, ServiceName: "cinemaos-test"}, Gauges{
+		ActiveSeatHolds: func(context.Context) (int64, error) { return 7, nil },
+		RedisPoolSize:   func() int64 { return 3 },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { m.Shutdown(ctx) })
+
+	m.RecordRequest(ctx, http.MethodGet, "/api/v1/movies/:id", http.StatusOK, 30*time.Millisecond)
+	m.RecordRequest(ctx, http.MethodGet, "/api/v1/movies/:id", http.StatusOK, 30*time.Millisecond)
+	m.RecordRequest(ctx, http.MethodPost, "/api/v1/bookings/hold", http.StatusConflict, time.Second)
+	m.SeatsHeld(ctx, 3)
+	m.BookingCreated(ctx)
+	m.BookingCancelled(ctx)
+	m.RecordQuery(ctx, 2*time.Millisecond, false)
+	m.RecordQuery(ctx, 2*time.Second, true)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/v1/movies/:id",status="200"} 2`,
+		`http_requests_total{method="POST",route="/api/v1/bookings/hold",status="409"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="/api/v1/movies/:id",status="200",le="0.05"} 2`,
+		`http_request_duration_seconds_bucket{method="POST",route="/api/v1/bookings/hold",status="409",le="0.5"} 0`,
+		`seats_held_total 3`,
+		`booking_created_total 1`,
+		`booking_cancelled_total 1`,
+		`active_seat_holds 7`,
+		`redis_pool_size 3`,
+		`db_query_duration_seconds_bucket{failed="false",le="0.0025"} 1`,
+		`db_query_duration_seconds_count{failed="true"} 1`,
+		`service_name="cinemaos-test"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape lacks %s", want)
+		}
+	}
+}
+
+func TestGaugeFailureLeavesItOut(t *testing.T) {
+	m, err := New(Config{Enabled: true, ServiceName: "cinemaos-test"}, Gauges{
+		ActiveSeatHolds: func(context.Context) (int64, error) { return 0, errors.New("redis is down") },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { m.Shutdown(context.Background()) })
+
+	// An unreadable value is not reported as zero holds
+	if body := scrape(t, m); strings.Contains(body, "active_seat_holds ") || strings.Contains(body, "redis_pool_size") {
+		t.Errorf("scrape reports gauges it could not read:\n%s", body)
+	}
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	m, err := New(Config{ServiceName: "cinemaos-test"}, Gauges{RedisPoolSize: func() int64 { return 3 }})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if m.IsEnabled() || m.Handler() != nil {
+		t.Error("disabled metrics are served")
+	}
+
+	// Recording still works, into noop instruments
+	ctx := context.Background()
+	m.RecordRequest(ctx, http.MethodGet, "/health", http.StatusOK, time.Millisecond)
+	m.SeatsHeld(ctx, 1)
+	m.BookingCreated(ctx)
+	m.BookingCancelled(ctx)
+	if err := m.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
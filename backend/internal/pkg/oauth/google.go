@@ -0,0 +1,235 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/pkg/circuitbreaker"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultKeyTTL is how long signing keys are kept when Google's
+	// response has no max-age
+	defaultKeyTTL = time.Hour
+	// minRefetchInterval limits refetching the keys for an unknown key ID,
+	// so forged tokens cannot make us hammer Google
+	minRefetchInterval = time.Minute
+)
+
+// googleIssuers are the issuers Google signs ID tokens as
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// ErrInvalidToken is returned for an ID token that is malformed, expired,
+// not signed by Google or issued to another client
+var ErrInvalidToken = errors.New("oauth: invalid Google ID token")
+
+// GoogleConfig holds Google Sign-In settings
+type GoogleConfig struct {
+	ClientID string        // OAuth client ID the frontend signs in with; ID tokens must be issued to it
+	CertsURL string        // Google's JWKS, e.g. https://www.googleapis.com/oauth2/v3/certs
+	Timeout  time.Duration // for fetching the signing keys
+}
+
+// GoogleProfile is the verified identity in a Google ID token
+type GoogleProfile struct {
+	Subject       string // Google's stable account ID
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Picture       string
+}
+
+// GoogleVerifier verifies the ID tokens the frontend receives from Google
+// Sign-In. Tokens are checked offline against Google's signing keys, which
+// are cached for as long as Google allows.
+type GoogleVerifier struct {
+	cfg     GoogleConfig
+	http    *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	fetchedAt time.Time
+}
+
+// NewGoogleVerifier creates a Google ID token verifier
+func NewGoogleVerifier(cfg GoogleConfig, log *logger.Logger) *GoogleVerifier {
+	return &GoogleVerifier{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: circuitbreaker.New(circuitbreaker.DefaultConfig("google"), log),
+	}
+}
+
+// idTokenClaims are the claims of a Google ID token we read
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Picture       string `json:"picture"`
+}
+
+// Verify checks an ID token's signature, issuer, audience and expiry and
+// returns the profile it carries. A token failing any check returns
+// ErrInvalidToken; other errors mean the signing keys could not be loaded.
+func (v *GoogleVerifier) Verify(ctx context.Context, idToken string) (*GoogleProfile, error) {
+	var keyErr error
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.key(ctx, kid)
+		if err != nil {
+			keyErr = err
+		}
+		return key, err
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithAudience(v.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) || claims.Subject == "" || claims.Email == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &GoogleProfile{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: claims.EmailVerified,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+// key returns the signing key with the given ID. The keys are refetched
+// when they expire, and early for an unknown ID since Google rotates them.
+func (v *GoogleVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	key, ok := v.keys[kid]
+	stale := now.After(v.expiresAt)
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && now.Sub(v.fetchedAt) < minRefetchInterval {
+		return nil, ErrInvalidToken
+	}
+
+	if err := v.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrInvalidToken
+}
+
+// jwks is Google's JSON Web Key Set
+type jwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// fetchKeys loads Google's signing keys; the caller holds v.mu
+func (v *GoogleVerifier) fetchKeys(ctx context.Context) error {
+	var (
+		set    jwks
+		maxAge time.Duration
+	)
+	err := v.breaker.Execute(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.CertsURL, nil)
+		if err != nil {
+			return err
+		}
+		res, err := v.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("google certs request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("google certs returned %d", res.StatusCode)
+		}
+		set = jwks{}
+		if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+			return fmt.Errorf("invalid google certs response: %w", err)
+		}
+		maxAge = cacheMaxAge(res.Header.Get("Cache-Control"))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New("google certs contain no RSA keys")
+	}
+
+	if maxAge <= 0 {
+		maxAge = defaultKeyTTL
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.expiresAt = v.fetchedAt.Add(maxAge)
+	return nil
+}
+
+// cacheMaxAge reads max-age from a Cache-Control header
+func cacheMaxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const testClientID = "client-123.apps.googleusercontent.com"
+
+// googleStub serves a JWKS with one signing key and counts the fetches
+type googleStub struct {
+	key     *rsa.PrivateKey
+	kid     string
+	fetches atomic.Int32
+	server  *httptest.Server
+}
+
+func newGoogleStub(t *testing.T) *googleStub {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	g := &googleStub{key: key, kid: "key-1"}
+	g.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.fetches.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=3600, must-revalidate")
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": g.kid,
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(g.server.Close)
+	return g
+}
+
+func (g *googleStub) verifier() *GoogleVerifier {
+	return NewGoogleVerifier(GoogleConfig{ClientID: testClientID, CertsURL: g.server.URL, Timeout: time.Second},
+		&logger.Logger{Logger: zap.NewNop()})
+}
+
+// sign issues an ID token; edit changes the claims before signing
+func (g *googleStub) sign(t *testing.T, edit func(*idTokenClaims)) string {
+	t.Helper()
+	claims := &idTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "110169484474386276334",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Email:         "Fan@Example.com",
+		EmailVerified: true,
+		GivenName:     "Film",
+		FamilyName:    "Fan",
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = g.kid
+	signed, err := token.SignedString(g.key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	g := newGoogleStub(t)
+	v := g.verifier()
+
+	profile, err := v.Verify(context.Background(), g.sign(t, nil))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	want := GoogleProfile{Subject: "110169484474386276334", Email: "fan@example.com", EmailVerified: true, GivenName: "Film", FamilyName: "Fan"}
+	if *profile != want {
+		t.Errorf("profile = %+v, want %+v", *profile, want)
+	}
+
+	// The keys are cached for the max-age Google sends
+	if _, err := v.Verify(context.Background(), g.sign(t, nil)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if n := g.fetches.Load(); n != 1 {
+		t.Errorf("keys fetched %d times, want 1", n)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	g := newGoogleStub(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"another client", func() string {
+			return g.sign(t, func(c *idTokenClaims) { c.Audience = jwt.ClaimStrings{"someone-else"} })
+		}},
+		{"another issuer", func() string {
+			return g.sign(t, func(c *idTokenClaims) { c.Issuer = "https://evil.example.com" })
+		}},
+		{"expired", func() string {
+			return g.sign(t, func(c *idTokenClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) })
+		}},
+		{"no expiry", func() string {
+			return g.sign(t, func(c *idTokenClaims) { c.ExpiresAt = nil })
+		}},
+		{"no email", func() string {
+			return g.sign(t, func(c *idTokenClaims) { c.Email = "" })
+		}},
+		{"signed with another key", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, &idTokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "accounts.google.com", Subject: "1", Audience: jwt.ClaimStrings{testClientID},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}, Email: "fan@example.com"})
+			token.Header["kid"] = g.kid
+			signed, _ := token.SignedString(other)
+			return signed
+		}},
+		{"HMAC", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Audience: jwt.ClaimStrings{testClientID}})
+			signed, _ := token.SignedString([]byte("secret"))
+			return signed
+		}},
+		{"garbage", func() string { return "not-a-jwt" }},
+	}
+	v := g.verifier()
+	for _, tt := range tests {
+		if _, err := v.Verify(context.Background(), tt.token()); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidToken", tt.name, err)
+		}
+	}
+}
+
+func TestVerifyUnknownKeyID(t *testing.T) {
+	g := newGoogleStub(t)
+	v := g.verifier()
+	if _, err := v.Verify(context.Background(), g.sign(t, nil)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	// Google rotated its keys: the new ID is fetched at once, but tokens
+	// with made-up IDs cannot make every request refetch
+	g.kid = "key-2"
+	if _, err := v.Verify(context.Background(), g.sign(t, nil)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify = %v within the refetch interval, want ErrInvalidToken", err)
+	}
+	if n := g.fetches.Load(); n != 1 {
+		t.Errorf("keys fetched %d times, want 1", n)
+	}
+	v.fetchedAt = v.fetchedAt.Add(-minRefetchInterval)
+	if _, err := v.Verify(context.Background(), g.sign(t, nil)); err != nil {
+		t.Errorf("Verify after the refetch interval: %v", err)
+	}
+}
+
+func TestVerifyCertsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	g := newGoogleStub(t)
+	v := NewGoogleVerifier(GoogleConfig{ClientID: testClientID, CertsURL: server.URL, Timeout: time.Second},
+		&logger.Logger{Logger: zap.NewNop()})
+
+	// Not the token's fault, so not reported as an invalid token
+	_, err := v.Verify(context.Background(), g.sign(t, nil))
+	if err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify = %v, want a key loading error", err)
+	}
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"public, max-age=19794, must-revalidate, no-transform", 19794 * time.Second},
+		{"max-age=60", time.Minute},
+		{"no-cache", 0},
+		{"max-age=soon", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := cacheMaxAge(tt.header); got != tt.want {
+			t.Errorf("cacheMaxAge(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "cinemaos-backend/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cached sends a success response that clients and shared caches may keep
+// for maxAge. The body is tagged with a weak ETag of its hash, and a
+// request whose If-None-Match already holds it gets 304 Not Modified.
+func Cached(c *gin.Context, data any, maxAge time.Duration) {
+	body, err := json.Marshal(Response{
+		Success: true,
+		Data:    shape(c, data),
+	})
+	if err != nil {
+		Error(c, apperrors.ErrInternal("failed to encode response"))
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag. The
+// comparison is weak, as If-None-Match requires.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
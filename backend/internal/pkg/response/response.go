@@ -1,9 +1,15 @@
 package response
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
+	"cinemaos-backend/internal/pkg/apiversion"
 	apperrors "cinemaos-backend/internal/pkg/errors"
 	"cinemaos-backend/internal/pkg/validator"
 )
@@ -17,11 +23,12 @@ type Response struct {
 	Meta    *Meta  `json:"meta,omitempty"`
 }
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response. The code is one of the
+// apperrors codes; keep the enums in step with them.
 type ErrorResponse struct {
-	Code    string `json:"code"`
+	Code    string `json:"code" enums:"INTERNAL_ERROR,VALIDATION_ERROR,NOT_FOUND,CONFLICT,BAD_REQUEST,UNAUTHORIZED,FORBIDDEN,TOO_MANY_REQUESTS,FAILED_PRECONDITION,TIMEOUT,INVALID_CREDENTIALS,TOKEN_EXPIRED,TOKEN_INVALID,EMAIL_NOT_VERIFIED,ACCOUNT_DISABLED,USER_NOT_FOUND,MOVIE_NOT_FOUND,BOOKING_NOT_FOUND,SHOWTIME_NOT_FOUND,CINEMA_NOT_FOUND,SEAT_NOT_AVAILABLE,EMAIL_ALREADY_EXISTS,BOOKING_EXPIRED,PAYMENT_FAILED,INVALID_PROMO_CODE,SEATS_ALREADY_BOOKED,SALES_NOT_OPEN,SALES_CLOSED,SHOWTIME_FULL,INVALID_STATUS_TRANSITION,DEVICE_UNAVAILABLE,SEAT_TYPE_NOT_ON_SALE,SEAT_TYPE_ONLINE_CAP_REACHED,INSUFFICIENT_LOYALTY_POINTS,INVALID_TICKET,ALREADY_CHECKED_IN,VERSION_CONFLICT"`
 	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+	Details any    `json:"details,omitempty" swaggertype:"object"`
 }
 
 // Meta holds pagination or other metadata
@@ -30,6 +37,65 @@ type Meta struct {
 	Limit      int   `json:"limit,omitempty"`
 	Total      int64 `json:"total,omitempty"`
 	TotalPages int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"` // v2 cursor pagination
+}
+
+// Versioned is implemented by DTOs whose shape differs between API versions.
+// ForVersion is only consulted for versions after v1, so v1 payloads are
+// always serialized from the original struct.
+type Versioned interface {
+	ForVersion(v apiversion.Version) any
+}
+
+// shape renders data for the request: the public variant for callers
+// outside the admin audience, the shape of the request's API version, and
+// the fields selected with ?fields= (see UseView)
+func shape(c *gin.Context, data any) any {
+	if data == nil {
+		return data
+	}
+
+	view := viewFrom(c)
+	if view != nil && !view.admin {
+		data = transform(data, func(p Public) any { return p.Public() })
+	}
+
+	if version := apiversion.FromGin(c); version != apiversion.V1 {
+		data = transform(data, func(v Versioned) any { return v.ForVersion(version) })
+	}
+
+	if view != nil && len(view.fields) > 0 {
+		data = selectFields(data, view.fields)
+	}
+	return data
+}
+
+// transform applies fn to data, or to each element of a slice, when it
+// implements T. Other values are returned unchanged.
+func transform[T any](data any, fn func(T) any) any {
+	if v, ok := data.(T); ok {
+		return fn(v)
+	}
+
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice || rv.IsNil() {
+		return data
+	}
+	elem := rv.Type().Elem()
+	if elem.Kind() != reflect.Interface && !elem.Implements(reflect.TypeOf((*T)(nil)).Elem()) {
+		return data
+	}
+
+	shaped := make([]any, rv.Len())
+	for i := range shaped {
+		item := rv.Index(i).Interface()
+		if v, ok := item.(T); ok {
+			shaped[i] = fn(v)
+		} else {
+			shaped[i] = item
+		}
+	}
+	return shaped
 }
 
 // Pagination holds pagination parameters
@@ -38,11 +104,18 @@ type Pagination struct {
 	Limit int
 }
 
-// GetPagination extracts pagination from query params with defaults
+// GetPagination extracts pagination from query params with defaults.
+// v2 clients page with the opaque cursor returned in meta.next_cursor.
 func GetPagination(c *gin.Context) Pagination {
 	page := 1
 	limit := 20
 
+	if cursor := c.Query("cursor"); cursor != "" && apiversion.FromGin(c) != apiversion.V1 {
+		if p, ok := decodeCursor(cursor); ok {
+			return p
+		}
+	}
+
 	if p := c.Query("page"); p != "" {
 		if parsed := parseInt(p); parsed > 0 {
 			page = parsed
@@ -63,6 +136,26 @@ func (p Pagination) Offset() int {
 	return (p.Page - 1) * p.Limit
 }
 
+func encodeCursor(p Pagination) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", p.Page, p.Limit)))
+}
+
+func decodeCursor(cursor string) (Pagination, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Pagination{}, false
+	}
+
+	var p Pagination
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &p.Page, &p.Limit); err != nil {
+		return Pagination{}, false
+	}
+	if p.Page < 1 || p.Limit < 1 || p.Limit > 100 {
+		return Pagination{}, false
+	}
+	return p, true
+}
+
 func parseInt(s string) int {
 	var result int
 	for _, c := range s {
@@ -78,7 +171,7 @@ func parseInt(s string) int {
 func Success(c *gin.Context, data any) {
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Data:    data,
+		Data:    shape(c, data),
 	})
 }
 
@@ -87,7 +180,7 @@ func SuccessWithMessage(c *gin.Context, message string, data any) {
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: message,
-		Data:    data,
+		Data:    shape(c, data),
 	})
 }
 
@@ -96,7 +189,7 @@ func Created(c *gin.Context, data any) {
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
 		Message: "Created successfully",
-		Data:    data,
+		Data:    shape(c, data),
 	})
 }
 
@@ -105,8 +198,23 @@ func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// Paginated sends a paginated response
+// Paginated sends a paginated response. v2 replaces page numbers with a
+// cursor to the next page.
 func Paginated(c *gin.Context, data any, pagination Pagination, total int64) {
+	if apiversion.FromGin(c) != apiversion.V1 {
+		meta := &Meta{Limit: pagination.Limit}
+		if int64(pagination.Offset()+pagination.Limit) < total {
+			meta.NextCursor = encodeCursor(Pagination{Page: pagination.Page + 1, Limit: pagination.Limit})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Success: true,
+			Data:    shape(c, data),
+			Meta:    meta,
+		})
+		return
+	}
+
 	totalPages := int(total) / pagination.Limit
 	if int(total)%pagination.Limit > 0 {
 		totalPages++
@@ -114,7 +222,7 @@ func Paginated(c *gin.Context, data any, pagination Pagination, total int64) {
 
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Data:    data,
+		Data:    shape(c, data),
 		Meta: &Meta{
 			Page:       pagination.Page,
 			Limit:      pagination.Limit,
@@ -124,6 +232,19 @@ func Paginated(c *gin.Context, data any, pagination Pagination, total int64) {
 	})
 }
 
+// CursorPaginated sends a page of a list that is paged by a cursor in
+// every API version. nextCursor is empty on the last page.
+func CursorPaginated(c *gin.Context, data any, limit int, nextCursor string) {
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    shape(c, data),
+		Meta: &Meta{
+			Limit:      limit,
+			NextCursor: nextCursor,
+		},
+	})
+}
+
 // Error sends an error response
 func Error(c *gin.Context, err error) {
 	var appErr *apperrors.AppError
@@ -134,6 +255,12 @@ func Error(c *gin.Context, err error) {
 	default:
 		appErr = apperrors.ErrInternal(err.Error())
 	}
+	// A query cut short by the request's deadline fails however its driver
+	// reports it; the client is told the request timed out
+	if appErr.HTTPStatus >= http.StatusInternalServerError && appErr.Code != apperrors.CodeTimeout &&
+		errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		appErr = apperrors.Wrap(err, apperrors.CodeTimeout, "The request took too long")
+	}
 
 	c.JSON(appErr.HTTPStatus, Response{
 		Success: false,
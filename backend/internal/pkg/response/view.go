@@ -0,0 +1,136 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cinemaos-backend/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Public is implemented by DTOs with a public variant that leaves out
+// internal and admin-only fields. Callers outside the admin audience of a
+// handler that opted in with UseView get the public variant.
+type Public interface {
+	Public() any
+}
+
+// FieldSet lists the top-level JSON fields of a DTO and of its public
+// variant; ?fields= may only name fields the caller can see
+type FieldSet struct {
+	Full   []string
+	Public []string
+}
+
+// NewFieldSet builds a field set from a DTO and its public variant
+func NewFieldSet(full, public any) FieldSet {
+	return FieldSet{Full: JSONFields(full), Public: JSONFields(public)}
+}
+
+// JSONFields returns the top-level JSON field names of a struct
+func JSONFields(v any) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+const viewKey = "response_view"
+
+type view struct {
+	admin  bool
+	fields []string
+}
+
+func viewFrom(c *gin.Context) *view {
+	if v, ok := c.Get(viewKey); ok {
+		return v.(*view)
+	}
+	return nil
+}
+
+// UseView opts a handler into response shaping. Non-admin callers get the
+// public variant of the data, and a ?fields= list narrows objects to the
+// named top-level fields. Unknown fields are rejected with a validation
+// error, in which case UseView has written the response and returns false.
+func UseView(c *gin.Context, admin bool, fields FieldSet) bool {
+	allowed := fields.Public
+	if admin {
+		allowed = fields.Full
+	}
+
+	v := &view{admin: admin}
+	for _, name := range strings.Split(c.Query("fields"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(v.fields, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			ValidationError(c, []validator.ValidationError{{
+				Field:   "fields",
+				Tag:     "oneof",
+				Value:   name,
+				Message: fmt.Sprintf("unknown field %q: must be one of %s", name, strings.Join(allowed, ", ")),
+			}})
+			return false
+		}
+		v.fields = append(v.fields, name)
+	}
+
+	c.Set(viewKey, v)
+	return true
+}
+
+// selectFields narrows an object, or each object in a slice, to fields
+func selectFields(data any, fields []string) any {
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice {
+		return pickFields(data, fields)
+	}
+
+	selected := make([]any, rv.Len())
+	for i := range selected {
+		selected[i] = pickFields(rv.Index(i).Interface(), fields)
+	}
+	return selected
+}
+
+func pickFields(item any, fields []string) any {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return item
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return item // not an object
+	}
+
+	picked := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if value, ok := all[name]; ok {
+			picked[name] = value
+		}
+	}
+	return picked
+}
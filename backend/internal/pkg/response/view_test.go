@@ -0,0 +1,153 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testItem struct {
+	ID       int     `json:"id"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"popularity_score"`
+	IsActive bool    `json:"is_active"`
+	internal string
+}
+
+type publicTestItem struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func (i *testItem) Public() any { return &publicTestItem{ID: i.ID, Title: i.Title} }
+
+var testFields = NewFieldSet(testItem{}, publicTestItem{})
+
+// viewRouter serves one item and a page of items; the X-Admin header
+// stands in for an admin token
+func viewRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	optIn := func(c *gin.Context) bool {
+		return UseView(c, c.GetHeader("X-Admin") == "true", testFields)
+	}
+	r.GET("/item", func(c *gin.Context) {
+		if optIn(c) {
+			Success(c, &testItem{ID: 1, Title: "Dune", Score: 97.5, IsActive: true})
+		}
+	})
+	r.GET("/items", func(c *gin.Context) {
+		if optIn(c) {
+			items := []*testItem{{ID: 1, Title: "Dune", Score: 97.5}, {ID: 2, Title: "Arrival", Score: 80}}
+			Paginated(c, items, GetPagination(c), 2)
+		}
+	})
+	return r
+}
+
+// fetch returns the response status and the keys of the first data object
+func fetch(t *testing.T, path string, admin bool) (int, []string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if admin {
+		req.Header.Set("X-Admin", "true")
+	}
+	w := httptest.NewRecorder()
+	viewRouter().ServeHTTP(w, req)
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+
+	var object map[string]any
+	var list []map[string]any
+	switch {
+	case len(body.Data) == 0:
+		return w.Code, nil
+	case json.Unmarshal(body.Data, &list) == nil && len(list) > 0:
+		object = list[0]
+	case json.Unmarshal(body.Data, &object) != nil:
+		t.Fatalf("data is neither an object nor a list: %s", body.Data)
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return w.Code, keys
+}
+
+func TestFieldVisibility(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		admin bool
+		want  []string
+	}{
+		{"admin item", "/item", true, []string{"id", "is_active", "popularity_score", "title"}},
+		{"customer item", "/item", false, []string{"id", "title"}},
+		{"admin list", "/items", true, []string{"id", "is_active", "popularity_score", "title"}},
+		{"customer list", "/items", false, []string{"id", "title"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, keys := fetch(t, tt.path, tt.admin)
+			if code != http.StatusOK || !slices.Equal(keys, tt.want) {
+				t.Errorf("got %d %v, want 200 %v", code, keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		admin    bool
+		wantCode int
+		want     []string
+	}{
+		{"one field", "/items?fields=title", false, http.StatusOK, []string{"title"}},
+		{"spaces and repeats", "/items?fields=id,%20title,id", false, http.StatusOK, []string{"id", "title"}},
+		{"empty list", "/items?fields=", false, http.StatusOK, []string{"id", "title"}},
+		{"admin-only field for an admin", "/item?fields=id,popularity_score", true, http.StatusOK, []string{"id", "popularity_score"}},
+		{"admin-only field for a customer", "/item?fields=id,popularity_score", false, http.StatusBadRequest, nil},
+		{"unknown field", "/items?fields=id,budget", true, http.StatusBadRequest, nil},
+		{"unexported field", "/items?fields=internal", true, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, keys := fetch(t, tt.path, tt.admin)
+			if code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && !slices.Equal(keys, tt.want) {
+				t.Errorf("fields = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	type tagged struct {
+		Plain     string
+		Renamed   string `json:"renamed"`
+		OmitEmpty string `json:"omit,omitempty"`
+		Skipped   string `json:"-"`
+		hidden    string
+	}
+	got := JSONFields(&tagged{})
+	if want := []string{"Plain", "renamed", "omit"}; !slices.Equal(got, want) {
+		t.Errorf("JSONFields = %v, want %v", got, want)
+	}
+}
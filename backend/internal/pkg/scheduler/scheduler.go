@@ -0,0 +1,257 @@
+// Package scheduler runs periodic background jobs so that, across all
+// instances of the service, each job runs on one instance at a time and
+// about once per interval. Instances coordinate through a Store; there is no
+// standing leader, so when an instance dies the next one due simply takes
+// over.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Job is a periodic background job
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// RunState is the run history of a job across all instances
+type RunState struct {
+	Name           string
+	Owner          string // instance that last started the job
+	LastStartedAt  *time.Time
+	LastFinishedAt *time.Time
+	LastSuccessAt  *time.Time
+	LastError      string
+	LastDuration   time.Duration
+	Runs           int64
+	Failures       int64
+}
+
+// Store coordinates jobs between instances and keeps their run history
+type Store interface {
+	// TryLock takes the named job's lock unless another instance holds it.
+	// The lock is held until unlock is called or the holder's connection
+	// to the store is lost.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+
+	// Get returns the job's run history, or nil if it never ran
+	Get(ctx context.Context, name string) (*RunState, error)
+
+	// RecordStart records that owner started the job at the given time
+	RecordStart(ctx context.Context, name, owner string, at time.Time) error
+
+	// RecordFinish records the outcome of the run started last
+	RecordFinish(ctx context.Context, name string, at time.Time, duration time.Duration, runErr error) error
+}
+
+// Config holds scheduler settings
+type Config struct {
+	Instance string  // names this instance in run history
+	Jitter   float64 // up to this fraction of the interval is added to each wait, 0..1
+}
+
+// Job health values reported by Status
+const (
+	HealthOK      = "OK"
+	HealthFailing = "FAILING" // the last run returned an error
+	HealthStale   = "STALE"   // no successful run for staleAfter intervals
+	HealthPending = "PENDING" // never finished a run
+)
+
+// staleAfter is how many intervals may pass without a successful run before
+// a job is reported stale
+const staleAfter = 3
+
+// dueSlack lets a job run slightly before a full interval has passed since
+// the last start, so jitter does not make it skip a whole round
+const dueSlack = 0.9
+
+// JobStatus describes a registered job and its last runs
+type JobStatus struct {
+	Name           string     `json:"name"`
+	IntervalMs     int64      `json:"interval_ms"`
+	Health         string     `json:"health"`
+	Owner          string     `json:"owner,omitempty"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastSuccessAt  *time.Time `json:"last_success_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	Runs           int64      `json:"runs"`
+	Failures       int64      `json:"failures"`
+}
+
+// Runner schedules registered jobs on this instance
+type Runner struct {
+	cfg    Config
+	store  Store
+	jobs   []Job
+	mu     sync.Mutex
+	logger *logger.Logger
+}
+
+// New creates a new job runner
+func New(cfg Config, store Store, log *logger.Logger) *Runner {
+	cfg.Jitter = min(max(cfg.Jitter, 0), 1)
+	return &Runner{
+		cfg:    cfg,
+		store:  store,
+		logger: log,
+	}
+}
+
+// Register adds a job. Jobs are registered at wiring time, before Run.
+func (r *Runner) Register(job Job) {
+	if job.Interval <= 0 {
+		panic(fmt.Sprintf("scheduler: job %q has no interval", job.Name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, registered := range r.jobs {
+		if registered.Name == job.Name {
+			panic(fmt.Sprintf("scheduler: job %q registered twice", job.Name))
+		}
+	}
+	r.jobs = append(r.jobs, job)
+}
+
+// Run schedules every registered job until the context is cancelled
+func (r *Runner) Run(ctx context.Context) {
+	r.mu.Lock()
+	jobs := append([]Job(nil), r.jobs...)
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.schedule(ctx, job)
+		}()
+	}
+
+	r.logger.Info("job runner started", zap.String("instance", r.cfg.Instance), zap.Int("jobs", len(jobs)))
+	wg.Wait()
+}
+
+func (r *Runner) schedule(ctx context.Context, job Job) {
+	// A random first wait keeps instances started together from contending
+	// on every tick
+	wait := time.Duration(rand.Float64() * float64(job.Interval))
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := r.RunOnce(ctx, job); err != nil {
+			r.logger.Error("scheduled job failed", zap.String("job", job.Name), zap.Error(err))
+		}
+		wait = job.Interval + time.Duration(rand.Float64()*r.cfg.Jitter*float64(job.Interval))
+	}
+}
+
+// RunOnce runs the job if no other instance is running it and it has not
+// started anywhere within the last interval. It reports whether the job ran;
+// err is the job's error or a store failure.
+func (r *Runner) RunOnce(ctx context.Context, job Job) (ran bool, err error) {
+	unlock, ok, err := r.store.TryLock(ctx, job.Name)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer unlock()
+
+	state, err := r.store.Get(ctx, job.Name)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if state != nil && state.LastStartedAt != nil &&
+		now.Sub(*state.LastStartedAt) < time.Duration(dueSlack*float64(job.Interval)) {
+		return false, nil
+	}
+
+	if err := r.store.RecordStart(ctx, job.Name, r.cfg.Instance, now); err != nil {
+		return false, err
+	}
+
+	runErr := r.invoke(ctx, job)
+	finished := time.Now()
+	if err := r.store.RecordFinish(context.WithoutCancel(ctx), job.Name, finished, finished.Sub(now), runErr); err != nil {
+		r.logger.Error("failed to record job run", zap.String("job", job.Name), zap.Error(err))
+	}
+	return true, runErr
+}
+
+func (r *Runner) invoke(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Status returns the health of every registered job, sorted by name
+func (r *Runner) Status(ctx context.Context) ([]JobStatus, error) {
+	r.mu.Lock()
+	jobs := append([]Job(nil), r.jobs...)
+	r.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		state, err := r.store.Get(ctx, job.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		status := JobStatus{
+			Name:       job.Name,
+			IntervalMs: job.Interval.Milliseconds(),
+			Health:     HealthPending,
+		}
+		if state != nil {
+			status.Owner = state.Owner
+			status.LastStartedAt = state.LastStartedAt
+			status.LastFinishedAt = state.LastFinishedAt
+			status.LastSuccessAt = state.LastSuccessAt
+			status.LastError = state.LastError
+			status.LastDurationMs = state.LastDuration.Milliseconds()
+			status.Runs = state.Runs
+			status.Failures = state.Failures
+			status.Health = health(state, job.Interval, now)
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+func health(state *RunState, interval time.Duration, now time.Time) string {
+	switch {
+	case state.LastFinishedAt == nil:
+		return HealthPending
+	case state.LastError != "":
+		return HealthFailing
+	case state.LastSuccessAt == nil || now.Sub(*state.LastSuccessAt) > staleAfter*interval:
+		return HealthStale
+	}
+	return HealthOK
+}
@@ -0,0 +1,319 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// memStore is a Store shared by several instances in memory. Each instance
+// talks to it through its own connection, whose locks go when it is lost.
+type memStore struct {
+	mu     sync.Mutex
+	locks  map[string]string // job name to the connection holding it
+	states map[string]*RunState
+}
+
+func newMemStore() *memStore {
+	return &memStore{locks: make(map[string]string), states: make(map[string]*RunState)}
+}
+
+// connect opens a connection to the store
+func (s *memStore) connect(id string) *memConn {
+	return &memConn{store: s, id: id}
+}
+
+// drop loses a connection, releasing its locks like a database does for a
+// session that went away
+func (s *memStore) drop(conn *memConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, holder := range s.locks {
+		if holder == conn.id {
+			delete(s.locks, name)
+		}
+	}
+}
+
+func (s *memStore) state(name string) RunState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.states[name]; ok {
+		return *state
+	}
+	return RunState{}
+}
+
+type memConn struct {
+	store *memStore
+	id    string
+}
+
+func (c *memConn) TryLock(_ context.Context, name string) (func(), bool, error) {
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, held := s.locks[name]; held {
+		return nil, false, nil
+	}
+	s.locks[name] = c.id
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.locks[name] == c.id {
+			delete(s.locks, name)
+		}
+	}, true, nil
+}
+
+func (c *memConn) Get(_ context.Context, name string) (*RunState, error) {
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	if !ok {
+		return nil, nil
+	}
+	copied := *state
+	return &copied, nil
+}
+
+func (c *memConn) RecordStart(_ context.Context, name, owner string, at time.Time) error {
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	if !ok {
+		state = &RunState{Name: name}
+		s.states[name] = state
+	}
+	state.Owner = owner
+	state.LastStartedAt = &at
+	state.Runs++
+	return nil
+}
+
+func (c *memConn) RecordFinish(_ context.Context, name string, at time.Time, duration time.Duration, runErr error) error {
+	s := c.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[name]
+	state.LastFinishedAt = &at
+	state.LastDuration = duration
+	state.LastError = ""
+	if runErr != nil {
+		state.LastError = runErr.Error()
+		state.Failures++
+	} else {
+		state.LastSuccessAt = &at
+	}
+	return nil
+}
+
+func newTestRunner(instance string, store Store) *Runner {
+	return New(Config{Instance: instance}, store, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestRunOnceRunsOnceAcrossContendingInstances(t *testing.T) {
+	store := newMemStore()
+	a := newTestRunner("a", store.connect("a"))
+	b := newTestRunner("b", store.connect("b"))
+
+	var executions atomic.Int32
+	job := Job{Name: "sweep", Interval: time.Hour, Run: func(context.Context) error {
+		executions.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 20 {
+		runner := a
+		if i%2 == 1 {
+			runner = b
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := runner.RunOnce(context.Background(), job); err != nil {
+				t.Errorf("RunOnce: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := executions.Load(); n != 1 {
+		t.Errorf("job ran %d times, want exactly once", n)
+	}
+	if state := store.state("sweep"); state.Runs != 1 {
+		t.Errorf("run history counts %d runs, want 1", state.Runs)
+	}
+}
+
+func TestRunOnceSkipsWhileAnotherInstanceRuns(t *testing.T) {
+	store := newMemStore()
+	a := newTestRunner("a", store.connect("a"))
+	b := newTestRunner("b", store.connect("b"))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	job := Job{Name: "sweep", Interval: time.Millisecond, Run: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if ran, err := a.RunOnce(context.Background(), job); !ran || err != nil {
+			t.Errorf("instance a: ran %v, %v", ran, err)
+		}
+	}()
+	<-started
+
+	if ran, err := b.RunOnce(context.Background(), job); ran || err != nil {
+		t.Errorf("instance b ran the job while a held it: %v, %v", ran, err)
+	}
+	close(release)
+	<-done
+}
+
+func TestRunOnceWaitsForTheInterval(t *testing.T) {
+	store := newMemStore()
+	a := newTestRunner("a", store.connect("a"))
+	b := newTestRunner("b", store.connect("b"))
+
+	const interval = 50 * time.Millisecond
+	job := Job{Name: "sweep", Interval: interval, Run: func(context.Context) error { return nil }}
+
+	if ran, _ := a.RunOnce(context.Background(), job); !ran {
+		t.Fatal("first run skipped")
+	}
+	if ran, _ := b.RunOnce(context.Background(), job); ran {
+		t.Error("job ran again on another instance within its interval")
+	}
+
+	time.Sleep(interval)
+	if ran, _ := b.RunOnce(context.Background(), job); !ran {
+		t.Error("job not run once its interval passed")
+	}
+	if owner := store.state("sweep").Owner; owner != "b" {
+		t.Errorf("owner = %q, want b", owner)
+	}
+}
+
+// TestTakeoverAfterInstanceDies loses the connection of an instance stuck
+// in a run; once the interval has passed another instance takes over
+func TestTakeoverAfterInstanceDies(t *testing.T) {
+	store := newMemStore()
+	connA := store.connect("a")
+	a := newTestRunner("a", connA)
+	b := newTestRunner("b", store.connect("b"))
+
+	const interval = 30 * time.Millisecond
+	stuck, started := make(chan struct{}), make(chan struct{})
+	defer close(stuck)
+	var first atomic.Bool
+	job := Job{Name: "sweep", Interval: interval, Run: func(context.Context) error {
+		// Only the first run hangs
+		if first.CompareAndSwap(false, true) {
+			close(started)
+			<-stuck
+		}
+		return nil
+	}}
+
+	go a.RunOnce(context.Background(), job)
+	<-started
+
+	time.Sleep(interval)
+	if ran, _ := b.RunOnce(context.Background(), job); ran {
+		t.Fatal("instance b ran the job while a still held the lock")
+	}
+
+	store.drop(connA)
+	if ran, err := b.RunOnce(context.Background(), job); !ran || err != nil {
+		t.Fatalf("instance b did not take over: %v, %v", ran, err)
+	}
+	if state := store.state("sweep"); state.Owner != "b" || state.Runs != 2 {
+		t.Errorf("after takeover owner = %q, runs = %d; want b, 2", state.Owner, state.Runs)
+	}
+}
+
+func TestRunOnceRecordsFailures(t *testing.T) {
+	store := newMemStore()
+	runner := newTestRunner("a", store.connect("a"))
+
+	failing := Job{Name: "failing", Interval: time.Hour, Run: func(context.Context) error {
+		return errors.New("database unavailable")
+	}}
+	panicking := Job{Name: "panicking", Interval: time.Hour, Run: func(context.Context) error {
+		panic("nil map")
+	}}
+	runner.Register(failing)
+	runner.Register(panicking)
+
+	for _, job := range []Job{failing, panicking} {
+		if ran, err := runner.RunOnce(context.Background(), job); !ran || err == nil {
+			t.Errorf("%s: ran %v, err %v; want ran with an error", job.Name, ran, err)
+		}
+	}
+
+	statuses, err := runner.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Health != HealthFailing || status.Failures != 1 {
+			t.Errorf("%s: health %s with %d failures, want FAILING with 1", status.Name, status.Health, status.Failures)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+	const interval = time.Minute
+
+	tests := []struct {
+		name  string
+		state RunState
+		want  string
+	}{
+		{"never finished", RunState{LastStartedAt: at(0)}, HealthPending},
+		{"last run failed", RunState{LastFinishedAt: at(0), LastSuccessAt: at(time.Minute), LastError: "boom"}, HealthFailing},
+		{"recent success", RunState{LastFinishedAt: at(0), LastSuccessAt: at(0)}, HealthOK},
+		{"success too long ago", RunState{LastFinishedAt: at(0), LastSuccessAt: at(staleAfter*interval + time.Second)}, HealthStale},
+		{"never succeeded", RunState{LastFinishedAt: at(0)}, HealthStale},
+	}
+	for _, tt := range tests {
+		if got := health(&tt.state, interval, now); got != tt.want {
+			t.Errorf("%s: health = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterRejectsDuplicates(t *testing.T) {
+	runner := newTestRunner("a", newMemStore().connect("a"))
+	runner.Register(Job{Name: "sweep", Interval: time.Minute})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a job twice did not panic")
+		}
+	}()
+	runner.Register(Job{Name: "sweep", Interval: time.Minute})
+}
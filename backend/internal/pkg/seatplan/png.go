@@ -0,0 +1,116 @@
+package seatplan
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// glyphScale is the pixel size of one dot of the bitmap font
+const glyphScale = 2
+
+// glyphs is a 3x5 bitmap font covering row labels and the screen caption.
+// Each glyph is five rows of three dots, top to bottom.
+var glyphs = map[rune][5]string{
+	'A': {"###", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", "###"},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'Q': {"###", "#.#", "#.#", "###", "..#"},
+	'R': {"###", "#.#", "##.", "#.#", "#.#"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+}
+
+// PNG renders the layout as a PNG image, for email clients that do not
+// display SVG
+func PNG(layout Layout) ([]byte, error) {
+	p := place(layout)
+
+	img := image.NewRGBA(image.Rect(0, 0, int(p.width), int(p.height)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground.rgba()}, image.Point{}, draw.Src)
+
+	fillRect(img, margin+labelWidth, margin, p.width-2*margin-labelWidth, 4, colorScreen)
+	drawText(img, "SCREEN", (p.width+labelWidth)/2, margin+14, colorLabel)
+
+	for _, row := range p.rows {
+		drawText(img, row.text, margin+labelWidth/2, row.cy, colorLabel)
+	}
+
+	for _, seat := range p.seats {
+		fill := colorSeat
+		if seat.Highlighted {
+			fill = colorHighlight
+		}
+		fillRect(img, seat.px, seat.py, seatSize, seatSize, fill)
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func fillRect(img *image.RGBA, x, y, w, h float64, c rgb) {
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(img, r, &image.Uniform{c.rgba()}, image.Point{}, draw.Src)
+}
+
+// drawText draws text centered on (cx, cy). Characters without a glyph
+// are left blank.
+func drawText(img *image.RGBA, text string, cx, cy float64, c rgb) {
+	const advance = 4 * glyphScale // three dots plus one of spacing
+	runes := []rune(strings.ToUpper(text))
+	x := int(math.Round(cx)) - (len(runes)*advance-glyphScale)/2
+	y := int(math.Round(cy)) - 5*glyphScale/2
+
+	for _, ch := range runes {
+		if glyph, ok := glyphs[ch]; ok {
+			for row, line := range glyph {
+				for col, dot := range line {
+					if dot == '#' {
+						fillRect(img, float64(x+col*glyphScale), float64(y+row*glyphScale), glyphScale, glyphScale, c)
+					}
+				}
+			}
+		}
+		x += advance
+	}
+}
+
+func (c rgb) rgba() color.RGBA {
+	return color.RGBA{R: c.r, G: c.g, B: c.b, A: 0xff}
+}
@@ -0,0 +1,206 @@
+// Package seatplan renders a static picture of an auditorium with some
+// seats highlighted, for emails and printed tickets where the interactive
+// seat map cannot run. Output is deterministic: the same layout always
+// renders to the same bytes.
+package seatplan
+
+import (
+	"math"
+	"sort"
+)
+
+// Seat is one seat of the layout
+type Seat struct {
+	Row         string
+	Number      int
+	X, Y        float64 // screen-relative position; ignored when not set on every seat
+	Highlighted bool
+}
+
+// Layout is an auditorium's seats with the screen at the top
+type Layout struct {
+	Seats []Seat
+}
+
+// Geometry in output pixels
+const (
+	seatSize    = 18.0
+	seatPitch   = 24.0 // distance between neighbouring seat origins
+	margin      = 16.0
+	labelWidth  = 24.0 // row labels left of the seats
+	screenDepth = 36.0 // screen bar plus gap above the first row
+	maxWidth    = 1200.0
+)
+
+// Colors shared by the SVG and PNG renderers
+var (
+	colorBackground = rgb{0xff, 0xff, 0xff}
+	colorSeat       = rgb{0xd1, 0xd5, 0xdb}
+	colorHighlight  = rgb{0xe1, 0x1d, 0x48}
+	colorScreen     = rgb{0x37, 0x41, 0x51}
+	colorLabel      = rgb{0x4b, 0x55, 0x63}
+)
+
+type rgb struct{ r, g, b uint8 }
+
+// placedSeat is a seat with its top-left corner in output pixels
+type placedSeat struct {
+	Seat
+	px, py float64
+}
+
+// rowLabel is a row label with the vertical center of its row
+type rowLabel struct {
+	text string
+	cy   float64
+}
+
+// plan is a layout resolved to output pixels
+type plan struct {
+	width, height float64
+	seats         []placedSeat
+	rows          []rowLabel
+}
+
+// place resolves seat positions. Seat X/Y are used when every seat has a
+// distinct position; otherwise seats fall back to a grid of row by number.
+func place(layout Layout) plan {
+	seats := append([]Seat(nil), layout.Seats...)
+	sort.SliceStable(seats, func(i, j int) bool {
+		if seats[i].Row != seats[j].Row {
+			return rowLess(seats[i].Row, seats[j].Row)
+		}
+		return seats[i].Number < seats[j].Number
+	})
+
+	var xs, ys []float64
+	if hasPositions(seats) {
+		xs, ys = scalePositions(seats)
+	} else {
+		xs, ys = gridPositions(seats)
+	}
+
+	p := plan{seats: make([]placedSeat, len(seats))}
+	left, top := margin+labelWidth, margin+screenDepth
+	right, bottom := left, top
+	rowY := make(map[string]float64)
+	rowCount := make(map[string]int)
+	var rowOrder []string
+
+	for i, seat := range seats {
+		px, py := round1(left+xs[i]), round1(top+ys[i])
+		p.seats[i] = placedSeat{Seat: seat, px: px, py: py}
+		right = math.Max(right, px+seatSize)
+		bottom = math.Max(bottom, py+seatSize)
+
+		if _, ok := rowY[seat.Row]; !ok {
+			rowOrder = append(rowOrder, seat.Row)
+		}
+		rowY[seat.Row] += py
+		rowCount[seat.Row]++
+	}
+
+	for _, row := range rowOrder {
+		p.rows = append(p.rows, rowLabel{text: row, cy: round1(rowY[row]/float64(rowCount[row]) + seatSize/2)})
+	}
+
+	p.width = math.Ceil(right + margin)
+	p.height = math.Ceil(bottom + margin)
+	return p
+}
+
+// rowLess orders row labels A..Z before AA..
+func rowLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func hasPositions(seats []Seat) bool {
+	if len(seats) == 0 {
+		return false
+	}
+	seen := make(map[[2]float64]bool, len(seats))
+	for _, seat := range seats {
+		key := [2]float64{seat.X, seat.Y}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+// scalePositions maps seat positions so the closest neighbours on each axis
+// are one seat pitch apart, shrinking uniformly to stay within maxWidth
+func scalePositions(seats []Seat) ([]float64, []float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	for _, seat := range seats {
+		minX = math.Min(minX, seat.X)
+		minY = math.Min(minY, seat.Y)
+	}
+
+	sx := seatPitch / minSpacing(seats, func(s Seat) float64 { return s.X })
+	sy := seatPitch / minSpacing(seats, func(s Seat) float64 { return s.Y })
+
+	maxX := 0.0
+	for _, seat := range seats {
+		maxX = math.Max(maxX, (seat.X-minX)*sx)
+	}
+	if limit := maxWidth - 2*margin - labelWidth - seatSize; maxX > limit {
+		shrink := limit / maxX
+		sx *= shrink
+		sy *= shrink
+	}
+
+	xs, ys := make([]float64, len(seats)), make([]float64, len(seats))
+	for i, seat := range seats {
+		xs[i] = (seat.X - minX) * sx
+		ys[i] = (seat.Y - minY) * sy
+	}
+	return xs, ys
+}
+
+// minSpacing returns the smallest gap between distinct values on one axis,
+// or 1 if all seats share the value
+func minSpacing(seats []Seat, axis func(Seat) float64) float64 {
+	values := make([]float64, 0, len(seats))
+	for _, seat := range seats {
+		values = append(values, axis(seat))
+	}
+	sort.Float64s(values)
+
+	spacing := math.Inf(1)
+	for i := 1; i < len(values); i++ {
+		if d := values[i] - values[i-1]; d > 1e-6 {
+			spacing = math.Min(spacing, d)
+		}
+	}
+	if math.IsInf(spacing, 1) {
+		return 1
+	}
+	return spacing
+}
+
+func gridPositions(seats []Seat) ([]float64, []float64) {
+	rowIndex := make(map[string]int)
+	minNumber := math.MaxInt
+	for _, seat := range seats {
+		if _, ok := rowIndex[seat.Row]; !ok {
+			rowIndex[seat.Row] = len(rowIndex)
+		}
+		minNumber = min(minNumber, seat.Number)
+	}
+
+	xs, ys := make([]float64, len(seats)), make([]float64, len(seats))
+	for i, seat := range seats {
+		xs[i] = float64(seat.Number-minNumber) * seatPitch
+		ys[i] = float64(rowIndex[seat.Row]) * seatPitch
+	}
+	return xs, ys
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,49 @@
+package seatplan
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+)
+
+// SVG renders the layout as an SVG document
+func SVG(layout Layout) []byte {
+	p := place(layout)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`,
+		num(p.width), num(p.height), num(p.width), num(p.height))
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, colorBackground.hex())
+
+	// Screen edge across the top
+	fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="4" fill="%s"/>`,
+		num(margin+labelWidth), num(margin), num(p.width-2*margin-labelWidth), colorScreen.hex())
+	fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="sans-serif" font-size="10" text-anchor="middle" fill="%s">SCREEN</text>`,
+		num((p.width+labelWidth)/2), num(margin+18), colorLabel.hex())
+
+	for _, row := range p.rows {
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="sans-serif" font-size="11" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`,
+			num(margin+labelWidth/2), num(row.cy), colorLabel.hex(), html.EscapeString(row.text))
+	}
+
+	for _, seat := range p.seats {
+		fill := colorSeat
+		if seat.Highlighted {
+			fill = colorHighlight
+		}
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" rx="3" fill="%s"><title>%s%d</title></rect>`,
+			num(seat.px), num(seat.py), num(seatSize), num(seatSize), fill.hex(), html.EscapeString(seat.Row), seat.Number)
+	}
+
+	b.WriteString(`</svg>`)
+	return b.Bytes()
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
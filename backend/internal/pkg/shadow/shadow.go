@@ -0,0 +1,287 @@
+// Package shadow verifies a candidate read implementation against the one
+// currently serving traffic. Sampled calls run the candidate in the
+// background and log any difference; callers always get the primary result.
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Config controls which reads are shadowed and how often
+type Config struct {
+	Enabled     bool
+	SampleRate  float64         // fraction of calls shadowed, 0..1
+	Comparisons map[string]bool // per-comparison switch; missing names are on
+	Timeout     time.Duration   // budget for a single shadow read
+	MaxInFlight int             // shadow reads beyond this are skipped
+}
+
+// Stats are the counters kept for one comparison
+type Stats struct {
+	Compared   int64 `json:"compared"`
+	Mismatched int64 `json:"mismatched"`
+	Errors     int64 `json:"errors"`
+	Skipped    int64 `json:"skipped"`
+}
+
+type counters struct {
+	compared   atomic.Int64
+	mismatched atomic.Int64
+	errors     atomic.Int64
+	skipped    atomic.Int64
+}
+
+// Reader runs shadow reads and records their outcome. Configuration can be
+// swapped at runtime; the in-flight limit is fixed at construction.
+type Reader struct {
+	cfg      atomic.Pointer[Config]
+	sem      chan struct{}
+	counters sync.Map // comparison name -> *counters
+	logger   *logger.Logger
+}
+
+// New creates a new shadow reader
+func New(cfg Config, log *logger.Logger) *Reader {
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 16
+	}
+
+	r := &Reader{
+		sem:    make(chan struct{}, cfg.MaxInFlight),
+		logger: log,
+	}
+	r.Configure(cfg)
+	return r
+}
+
+// Configure replaces the sampling configuration
+func (r *Reader) Configure(cfg Config) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	cfg.SampleRate = min(max(cfg.SampleRate, 0), 1)
+	cfg.MaxInFlight = cap(r.sem)
+
+	cfg.Comparisons = cloneComparisons(cfg.Comparisons)
+
+	r.cfg.Store(&cfg)
+}
+
+// Config returns a copy of the active configuration
+func (r *Reader) Config() Config {
+	cfg := *r.cfg.Load()
+	cfg.Comparisons = cloneComparisons(cfg.Comparisons)
+	return cfg
+}
+
+func cloneComparisons(src map[string]bool) map[string]bool {
+	dst := make(map[string]bool, len(src))
+	for name, on := range src {
+		dst[name] = on
+	}
+	return dst
+}
+
+// Stats returns the counters of every comparison seen so far
+func (r *Reader) Stats() map[string]Stats {
+	stats := make(map[string]Stats)
+	r.counters.Range(func(key, value any) bool {
+		c := value.(*counters)
+		stats[key.(string)] = Stats{
+			Compared:   c.compared.Load(),
+			Mismatched: c.mismatched.Load(),
+			Errors:     c.errors.Load(),
+			Skipped:    c.skipped.Load(),
+		}
+		return true
+	})
+	return stats
+}
+
+// Mismatches returns the total mismatch count across comparisons
+func (r *Reader) Mismatches() int64 {
+	var total int64
+	for _, s := range r.Stats() {
+		total += s.Mismatched
+	}
+	return total
+}
+
+func (r *Reader) countersFor(name string) *counters {
+	c, _ := r.counters.LoadOrStore(name, &counters{})
+	return c.(*counters)
+}
+
+// sampled reports whether this call of the named comparison is shadowed
+func (r *Reader) sampled(name string) bool {
+	cfg := r.cfg.Load()
+	if !cfg.Enabled || cfg.SampleRate <= 0 {
+		return false
+	}
+	if on, ok := cfg.Comparisons[name]; ok && !on {
+		return false
+	}
+	return cfg.SampleRate >= 1 || rand.Float64() < cfg.SampleRate
+}
+
+// Read serves primary and, for sampled calls, runs candidate asynchronously
+// and compares both results after passing them through normalize. A nil
+// normalize compares the raw results.
+func Read[T any](
+	ctx context.Context,
+	r *Reader,
+	name string,
+	primary func(ctx context.Context) (T, error),
+	candidate func(ctx context.Context) (T, error),
+	normalize func(T) any,
+) (T, error) {
+	result, err := primary(ctx)
+	if err != nil || r == nil || !r.sampled(name) {
+		return result, err
+	}
+
+	c := r.countersFor(name)
+	select {
+	case r.sem <- struct{}{}:
+	default:
+		c.skipped.Add(1)
+		return result, err
+	}
+
+	// Normalize the primary result now; the caller owns it once we return
+	var want any = result
+	if normalize != nil {
+		want = normalize(result)
+	}
+
+	log := r.logger.WithContext(ctx)
+	shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.cfg.Load().Timeout)
+
+	go func() {
+		defer func() { <-r.sem }()
+		defer cancel()
+		defer func() {
+			if p := recover(); p != nil {
+				c.errors.Add(1)
+				log.Error("shadow read panicked", zap.String("comparison", name), zap.Any("panic", p))
+			}
+		}()
+
+		shadowResult, shadowErr := candidate(shadowCtx)
+		if shadowErr != nil {
+			c.errors.Add(1)
+			log.Warn("shadow read failed", zap.String("comparison", name), zap.Error(shadowErr))
+			return
+		}
+
+		var got any = shadowResult
+		if normalize != nil {
+			got = normalize(shadowResult)
+		}
+
+		c.compared.Add(1)
+		if diffs := Diff(want, got); len(diffs) > 0 {
+			c.mismatched.Add(1)
+			log.Warn("shadow read mismatch",
+				zap.String("comparison", name),
+				zap.Strings("diffs", diffs),
+			)
+		}
+	}()
+
+	return result, err
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// maxDiffs caps the differences reported for one comparison
+const maxDiffs = 10
+
+// Diff describes where got differs from want, one entry per difference
+func Diff(want, got any) []string {
+	var diffs []string
+	diffValue(&diffs, "$", reflect.ValueOf(want), reflect.ValueOf(got))
+	return diffs
+}
+
+func diffValue(diffs *[]string, path string, want, got reflect.Value) {
+	if len(*diffs) >= maxDiffs {
+		return
+	}
+	if !want.IsValid() || !got.IsValid() {
+		if want.IsValid() != got.IsValid() {
+			*diffs = append(*diffs, path+": present on one side only")
+		}
+		return
+	}
+	if want.Type() != got.Type() {
+		*diffs = append(*diffs, path+": type "+want.Type().String()+" != "+got.Type().String())
+		return
+	}
+
+	switch want.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				*diffs = append(*diffs, path+": nil on one side only")
+			}
+			return
+		}
+		diffValue(diffs, path, want.Elem(), got.Elem())
+	case reflect.Struct:
+		if want.Type() == timeType {
+			if !want.Interface().(time.Time).Equal(got.Interface().(time.Time)) {
+				*diffs = append(*diffs, path+": "+fmtValue(want)+" != "+fmtValue(got))
+			}
+			return
+		}
+		for i := 0; i < want.NumField(); i++ {
+			if !want.Type().Field(i).IsExported() {
+				continue
+			}
+			diffValue(diffs, path+"."+want.Type().Field(i).Name, want.Field(i), got.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if want.Len() != got.Len() {
+			*diffs = append(*diffs, path+": length "+strconv.Itoa(want.Len())+" != "+strconv.Itoa(got.Len()))
+			return
+		}
+		for i := 0; i < want.Len(); i++ {
+			diffValue(diffs, path+"["+strconv.Itoa(i)+"]", want.Index(i), got.Index(i))
+		}
+	case reflect.Map:
+		keys := want.MapKeys()
+		for _, k := range got.MapKeys() {
+			if !want.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return fmtValue(keys[i]) < fmtValue(keys[j]) })
+		for _, k := range keys {
+			diffValue(diffs, path+"["+fmtValue(k)+"]", want.MapIndex(k), got.MapIndex(k))
+		}
+	default:
+		if !reflect.DeepEqual(want.Interface(), got.Interface()) {
+			*diffs = append(*diffs, path+": "+fmtValue(want)+" != "+fmtValue(got))
+		}
+	}
+}
+
+func fmtValue(v reflect.Value) string {
+	if !v.CanInterface() {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
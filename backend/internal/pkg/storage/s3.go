@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config holds the settings of an S3-compatible bucket
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.eu-west-1.amazonaws.com or a MinIO URL; empty for AWS in Region
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// ForcePathStyle addresses the bucket in the path rather than the host
+	// name, as MinIO and most other S3-compatible stores need
+	ForcePathStyle bool
+	// PublicBaseURL replaces the bucket URL in the URLs handed out, e.g. a
+	// CDN in front of the bucket
+	PublicBaseURL string
+	Timeout       time.Duration // per request
+}
+
+// S3Client stores objects in an S3-compatible bucket through its REST API,
+// signing requests with AWS Signature Version 4
+type S3Client struct {
+	cfg  S3Config
+	http *http.Client
+	base *url.URL // the bucket URL objects are stored under
+}
+
+// NewS3Client creates an S3 client for one bucket
+func NewS3Client(cfg S3Config) (*S3Client, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("s3 bucket and region are required")
+	}
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.ForcePathStyle {
+		base.Path = strings.TrimRight(base.Path, "/") + "/" + cfg.Bucket
+	} else {
+		base.Host = cfg.Bucket + "." + base.Host
+	}
+	cfg.PublicBaseURL = strings.TrimRight(cfg.PublicBaseURL, "/")
+
+	return &S3Client{
+		cfg:  cfg,
+		http: &http.Client{Timeout: cfg.Timeout},
+		base: base,
+	}, nil
+}
+
+// Upload stores the object under key and returns the URL it is served at.
+// The content is read into memory to be signed, so callers bound its size.
+func (c *S3Client) Upload(ctx context.Context, key, contentType string, reader io.Reader) (string, error) {
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read object: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, body, time.Now())
+
+	if err := c.do(req); err != nil {
+		return "", err
+	}
+	return c.URL(key), nil
+}
+
+// Delete removes the object stored under key. A missing object is not an
+// error.
+func (c *S3Client) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	c.sign(req, nil, time.Now())
+	return c.do(req)
+}
+
+// URL returns the URL the object stored under key is served at
+func (c *S3Client) URL(key string) string {
+	if c.cfg.PublicBaseURL != "" {
+		return c.cfg.PublicBaseURL + "/" + escapePath(key)
+	}
+	return c.objectURL(key)
+}
+
+func (c *S3Client) objectURL(key string) string {
+	u := *c.base
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + key
+	u.RawPath = escapePath(u.Path)
+	return u.String()
+}
+
+func (c *S3Client) do(req *http.Request) error {
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound && req.Method == http.MethodDelete {
+		return nil
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return fmt.Errorf("s3 returned %d for %s: %s", res.StatusCode, req.Method, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 authorization to a request with no
+// query string
+func (c *S3Client) sign(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"", // query string
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// escapePath percent-encodes a path the way Signature Version 4 expects:
+// everything but unreserved characters and the slashes between segments
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~', ch == '/':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
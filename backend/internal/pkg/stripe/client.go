@@ -0,0 +1,140 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/pkg/circuitbreaker"
+	"cinemaos-backend/internal/pkg/logger"
+)
+
+// Config holds Stripe API settings
+type Config struct {
+	SecretKey string        // sk_live_… or sk_test_…
+	BaseURL   string        // e.g. https://api.stripe.com
+	Timeout   time.Duration // per request
+}
+
+// CheckoutSessionParams describes a hosted checkout page for one payment
+type CheckoutSessionParams struct {
+	Amount            int64  // in the currency's smallest unit
+	Currency          string // ISO code, lower case
+	ProductName       string // the line item shown on the page
+	SuccessURL        string
+	CancelURL         string
+	ExpiresAt         time.Time // zero keeps Stripe's default of 24 hours
+	ClientReferenceID string
+	Metadata          map[string]string
+	IdempotencyKey    string // a retried create returns the first session
+}
+
+// CheckoutSession is a hosted checkout page the customer is redirected to
+type CheckoutSession struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Status        string `json:"status"`
+	PaymentStatus string `json:"payment_status"`
+}
+
+// Client creates checkout sessions through the Stripe REST API. Requests
+// run behind a circuit breaker.
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+// NewClient creates a Stripe API client
+func NewClient(cfg Config, log *logger.Logger) *Client {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: circuitbreaker.New(circuitbreaker.DefaultConfig("stripe"), log),
+	}
+}
+
+// errorResponse is the body Stripe sends with a non-2xx status
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateCheckoutSession creates a one-off payment checkout session for a
+// single line item. Stripe replays the first response for a repeated
+// idempotency key, so a retry never opens a second session.
+func (c *Client) CreateCheckoutSession(ctx context.Context, params CheckoutSessionParams) (*CheckoutSession, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", params.Currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(params.Amount, 10))
+	form.Set("line_items[0][price_data][product_data][name]", params.ProductName)
+	form.Set("success_url", params.SuccessURL)
+	form.Set("cancel_url", params.CancelURL)
+	if !params.ExpiresAt.IsZero() {
+		form.Set("expires_at", strconv.FormatInt(params.ExpiresAt.Unix(), 10))
+	}
+	if params.ClientReferenceID != "" {
+		form.Set("client_reference_id", params.ClientReferenceID)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var session CheckoutSession
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		session = CheckoutSession{}
+		return c.post(ctx, "/v1/checkout/sessions", form, params.IdempotencyKey, &session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if session.ID == "" || session.URL == "" {
+		return nil, errors.New("stripe returned a checkout session without an id or url")
+	}
+	return &session, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.cfg.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		var apiErr errorResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe returned %d (%s): %s", res.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe returned %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid stripe response: %w", err)
+	}
+	return nil
+}
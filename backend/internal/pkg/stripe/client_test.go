@@ -0,0 +1,117 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// fakeStripe answers checkout session creates, replaying the first session
+// for a repeated idempotency key as Stripe does
+type fakeStripe struct {
+	sessions map[string]string // by idempotency key
+	requests []*http.Request
+	forms    []map[string]string
+}
+
+func (f *fakeStripe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	form := make(map[string]string, len(r.PostForm))
+	for key := range r.PostForm {
+		form[key] = r.PostForm.Get(key)
+	}
+	f.requests = append(f.requests, r)
+	f.forms = append(f.forms, form)
+
+	if key, _, _ := r.BasicAuth(); key != "sk_test_key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"Invalid API Key provided"}}`))
+		return
+	}
+	key := r.Header.Get("Idempotency-Key")
+	id, ok := f.sessions[key]
+	if !ok {
+		id = "cs_test_" + string(rune('a'+len(f.sessions)))
+		f.sessions[key] = id
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"id":"` + id + `","url":"https://checkout.stripe.com/c/pay/` + id + `","status":"open","payment_status":"unpaid"}`))
+}
+
+func newTestClient(t *testing.T, secretKey string) (*Client, *fakeStripe) {
+	t.Helper()
+	fake := &fakeStripe{sessions: make(map[string]string)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	client := NewClient(Config{SecretKey: secretKey, BaseURL: srv.URL + "/", Timeout: time.Second},
+		&logger.Logger{Logger: zap.NewNop()})
+	return client, fake
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newTestClient(t, "sk_test_key")
+	params := CheckoutSessionParams{
+		Amount:            2450,
+		Currency:          "usd",
+		ProductName:       "Booking BK-7Q2M (2 tickets)",
+		SuccessURL:        "https://cinema.example.com/bookings/BK-7Q2M?payment=success",
+		CancelURL:         "https://cinema.example.com/bookings/BK-7Q2M?payment=cancelled",
+		ExpiresAt:         time.Unix(1900000000, 0),
+		ClientReferenceID: "booking-1",
+		Metadata:          map[string]string{"booking_reference": "BK-7Q2M"},
+		IdempotencyKey:    "BK-7Q2M",
+	}
+
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	retried, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if retried.ID != session.ID || len(fake.sessions) != 1 {
+		t.Errorf("retry opened session %s, want the first session %s", retried.ID, session.ID)
+	}
+
+	req := fake.requests[0]
+	if req.Method != http.MethodPost || req.URL.Path != "/v1/checkout/sessions" || req.Header.Get("Idempotency-Key") != "BK-7Q2M" {
+		t.Errorf("request %s %s, Idempotency-Key %q", req.Method, req.URL.Path, req.Header.Get("Idempotency-Key"))
+	}
+	want := map[string]string{
+		"mode":                                          "payment",
+		"line_items[0][quantity]":                       "1",
+		"line_items[0][price_data][currency]":           "usd",
+		"line_items[0][price_data][unit_amount]":        "2450",
+		"line_items[0][price_data][product_data][name]": "Booking BK-7Q2M (2 tickets)",
+		"success_url":                                   params.SuccessURL,
+		"cancel_url":                                    params.CancelURL,
+		"expires_at":                                    "1900000000",
+		"client_reference_id":                           "booking-1",
+		"metadata[booking_reference]":                   "BK-7Q2M",
+	}
+	for key, value := range want {
+		if got := fake.forms[0][key]; got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if len(fake.forms[0]) != len(want) {
+		t.Errorf("sent %d fields, want %d: %v", len(fake.forms[0]), len(want), fake.forms[0])
+	}
+}
+
+func TestCreateCheckoutSessionError(t *testing.T) {
+	client, _ := newTestClient(t, "sk_test_wrong")
+	_, err := client.CreateCheckoutSession(context.Background(), CheckoutSessionParams{Amount: 100, Currency: "usd"})
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "Invalid API Key provided") {
+		t.Errorf("err = %v, want Stripe's 401 message", err)
+	}
+}
@@ -0,0 +1,166 @@
+// Package ticket renders printable e-tickets as PDF. The booking reference
+// is signed and encoded in a QR code that box office staff scan at the door.
+package ticket
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Ticket is what gets printed on an e-ticket
+type Ticket struct {
+	Reference string
+	Code      string // QR payload from Sign; the bare reference when empty
+	Movie     string
+	Cinema    string
+	Screen    string
+	StartsAt  time.Time // in the cinema's time zone
+	Seats     []string  // seat labels such as "F12"
+	Tickets   int
+	Total     float64
+}
+
+// Page geometry in points; A6 portrait
+const (
+	pageWidth  = 298
+	pageHeight = 420
+	margin     = 24
+
+	// qrModule is the printed size of one QR module. The code comes with
+	// four modules of quiet zone around it.
+	qrModule = 3
+
+	// titleLineChars approximates how much of a bold 16pt title fits on
+	// one line
+	titleLineChars = 26
+)
+
+// Gray levels, from 0 for black to 255 for white
+const (
+	grayText  = 0
+	grayMuted = 102
+	grayRule  = 204
+)
+
+// Generate renders the ticket as a one-page PDF
+func Generate(t Ticket) ([]byte, error) {
+	doc, err := render(t)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := doc.Output(&b); err != nil {
+		return nil, fmt.Errorf("render ticket: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// render lays out the ticket. The standard Helvetica fonts are used, so
+// nothing needs to be embedded; text outside Windows-1252 prints as '?'.
+func render(t Ticket) (*gofpdf.Fpdf, error) {
+	payload := t.Code
+	if payload == "" {
+		payload = t.Reference
+	}
+	code, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("encode ticket code: %w", err)
+	}
+
+	doc := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "pt",
+		Size:    gofpdf.SizeType{Wd: pageWidth, Ht: pageHeight},
+	})
+	doc.SetMargins(0, 0, 0)
+	doc.SetAutoPageBreak(false, 0)
+	doc.AddPage()
+	tr := doc.UnicodeTranslatorFromDescriptor("")
+
+	text := func(x, y, size float64, style string, gray int, s string) {
+		doc.SetFont("Helvetica", style, size)
+		doc.SetTextColor(gray, gray, gray)
+		doc.Text(x, y, tr(s))
+	}
+
+	y := float64(margin) + 10
+	text(margin, y, 9, "B", grayMuted, "E-TICKET")
+	y += 24
+	for _, line := range wrap(t.Movie, titleLineChars, 2) {
+		text(margin, y, 16, "B", grayText, line)
+		y += 20
+	}
+	y += 4
+
+	details := [][2]string{
+		{"Cinema", t.Cinema},
+		{"Screen", t.Screen},
+		{"Date", t.StartsAt.Format("Mon, 02 Jan 2006")},
+		{"Time", t.StartsAt.Format("15:04")},
+		{"Seats", strings.Join(t.Seats, ", ")},
+		{"Tickets", fmt.Sprintf("%d", t.Tickets)},
+		{"Total", fmt.Sprintf("%.2f", t.Total)},
+	}
+	for _, d := range details {
+		text(margin, y, 9, "", grayMuted, d[0])
+		text(margin+64, y, 10, "", grayText, d[1])
+		y += 16
+	}
+
+	y += 4
+	doc.SetFillColor(grayRule, grayRule, grayRule)
+	doc.Rect(margin, y, pageWidth-2*margin, 0.75, "F")
+	y += 8
+
+	modules := code.Bitmap()
+	side := float64(len(modules) * qrModule)
+	left := (pageWidth - side) / 2
+	doc.SetFillColor(grayText, grayText, grayText)
+	for row, line := range modules {
+		for col, dark := range line {
+			if dark {
+				doc.Rect(left+float64(col*qrModule), y+float64(row*qrModule), qrModule, qrModule, "F")
+			}
+		}
+	}
+	y += side + 14
+
+	doc.SetFont("Helvetica", "B", 12)
+	ref := doc.GetStringWidth(t.Reference)
+	text((pageWidth-ref)/2, y, 12, "B", grayText, t.Reference)
+
+	if err := doc.Error(); err != nil {
+		return nil, fmt.Errorf("render ticket: %w", err)
+	}
+	return doc, nil
+}
+
+// wrap breaks s into at most maxLines lines of about width characters,
+// ending with an ellipsis when it does not fit
+func wrap(s string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] += "..."
+	}
+	return lines
+}
@@ -0,0 +1,93 @@
+package ticket
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func TestGenerate(t *testing.T) {
+	pdf, err := Generate(Ticket{
+		Reference: "BK20261016ABCD",
+		Movie:     "Dune: Part Two (IMAX)",
+		Cinema:    "Downtown",
+		Screen:    "Screen 1",
+		StartsAt:  time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC),
+		Seats:     []string{"F12", "F13"},
+		Tickets:   2,
+		Total:     25.5,
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(bytes.TrimSpace(pdf), []byte("%%EOF")) {
+		t.Fatalf("not a PDF: %q...", pdf[:min(len(pdf), 20)])
+	}
+}
+
+func TestRender(t *testing.T) {
+	ticket := Ticket{
+		Reference: "BK20261016ABCD",
+		Code:      "BK20261016ABCD.c2lnbmF0dXJl",
+		Movie:     "Dune: Part Two (IMAX)",
+		Cinema:    "Cinéma Lumière",
+		Screen:    "Screen 1",
+		StartsAt:  time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC),
+		Seats:     []string{"F12", "F13"},
+		Tickets:   2,
+		Total:     25.5,
+	}
+	doc, err := render(ticket)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	doc.SetCompression(false)
+	var b bytes.Buffer
+	if err := doc.Output(&b); err != nil {
+		t.Fatalf("Output: %v", err)
+	}
+	pdf := b.Bytes()
+
+	for _, want := range []string{"(BK20261016ABCD) Tj", `(Dune: Part Two \(IMAX\)) Tj`, "(Cin\xe9ma Lumi\xe8re) Tj", "(F12, F13) Tj", "(Fri, 16 Oct 2026) Tj", "(25.50) Tj"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("ticket is missing %q", want)
+		}
+	}
+
+	// The code printed is the signed payload: one filled square per dark
+	// module, plus the rule above the code
+	code, err := qrcode.New(ticket.Code, qrcode.Medium)
+	if err != nil {
+		t.Fatalf("qrcode.New: %v", err)
+	}
+	dark := 0
+	for _, row := range code.Bitmap() {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	if got := bytes.Count(pdf, []byte(" re f")); got != dark+1 {
+		t.Errorf("%d filled rectangles, want %d dark modules and the rule", got, dark)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"Dune", []string{"Dune"}},
+		{"The Lord of the Rings: The Return of the King", []string{"The Lord of the Rings: The", "Return of the King"}},
+		{"Night of the Day of the Dawn of the Son of the Bride of the Return", []string{"Night of the Day of the", "Dawn of the Son of the..."}},
+	}
+	for _, tt := range tests {
+		if got := wrap(tt.s, titleLineChars, 2); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrap(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
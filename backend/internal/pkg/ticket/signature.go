@@ -0,0 +1,41 @@
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// signatureBytes is how much of the HMAC-SHA256 goes on the ticket. 128
+// bits cannot be guessed and keep the QR code small enough to scan from a
+// phone screen.
+const signatureBytes = 16
+
+// Sign returns the QR payload of a ticket: the booking reference and its
+// truncated HMAC-SHA256 under secret, joined by a dot
+func Sign(reference, secret string) string {
+	return reference + "." + hex.EncodeToString(signature(reference, secret))
+}
+
+// Verify checks a scanned payload against secret and returns the booking
+// reference it carries. The reference is returned even when the signature
+// does not match, so the failed scan can be logged against it.
+func Verify(payload, secret string) (string, bool) {
+	payload = strings.TrimSpace(payload)
+	reference, sig, found := strings.Cut(payload, ".")
+	if !found {
+		return reference, false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || reference == "" {
+		return reference, false
+	}
+	return reference, hmac.Equal(got, signature(reference, secret))
+}
+
+func signature(reference, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(reference))
+	return mac.Sum(nil)[:signatureBytes]
+}
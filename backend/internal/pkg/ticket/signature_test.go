@@ -0,0 +1,36 @@
+package ticket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "ticket-secret"
+	payload := Sign("BK-ABC123", secret)
+	if !strings.HasPrefix(payload, "BK-ABC123.") || len(payload) != len("BK-ABC123.")+2*signatureBytes {
+		t.Fatalf("payload = %q, want the reference and a %d byte hex signature", payload, signatureBytes)
+	}
+
+	tests := []struct {
+		name          string
+		payload       string
+		wantReference string
+		wantOK        bool
+	}{
+		{"signed", payload, "BK-ABC123", true},
+		{"scanner whitespace", " " + payload + "\n", "BK-ABC123", true},
+		{"another secret", Sign("BK-ABC123", "other-secret"), "BK-ABC123", false},
+		{"reference swapped", "BK-XYZ789" + payload[len("BK-ABC123"):], "BK-XYZ789", false},
+		{"bare reference", "BK-ABC123", "BK-ABC123", false},
+		{"signature not hex", "BK-ABC123.not-hex", "BK-ABC123", false},
+		{"signature cut short", payload[:len(payload)-2], "BK-ABC123", false},
+		{"no reference", "." + payload[len("BK-ABC123."):], "", false},
+	}
+	for _, tt := range tests {
+		reference, ok := Verify(tt.payload, secret)
+		if reference != tt.wantReference || ok != tt.wantOK {
+			t.Errorf("%s: Verify = %q, %v; want %q, %v", tt.name, reference, ok, tt.wantReference, tt.wantOK)
+		}
+	}
+}
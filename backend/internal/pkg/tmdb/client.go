@@ -0,0 +1,231 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinemaos-backend/internal/pkg/circuitbreaker"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// maxAttempts caps how often a request is tried, the first try included
+const maxAttempts = 3
+
+// ErrNotFound is returned for a TMDB ID that TMDB does not know
+var ErrNotFound = errors.New("tmdb: movie not found")
+
+// Config holds TMDB API settings
+type Config struct {
+	APIKey       string        // v3 API key
+	BaseURL      string        // e.g. https://api.themoviedb.org/3
+	ImageBaseURL string        // poster and backdrop paths are appended to this
+	Timeout      time.Duration // per attempt
+	Backoff      time.Duration // wait before the second attempt, doubled after each
+	CastLimit    int           // top-billed cast members kept
+}
+
+// Movie is the metadata TMDB has for a movie
+type Movie struct {
+	ID               int
+	Title            string
+	OriginalTitle    string
+	Overview         string
+	Genres           []string
+	PosterURL        string
+	BackdropURL      string
+	Runtime          int    // minutes
+	ReleaseDate      string // YYYY-MM-DD
+	OriginalLanguage string
+	Cast             []string
+	Director         string
+}
+
+// Client reads movie metadata from the TMDB v3 REST API. Requests run
+// behind a circuit breaker and are retried with exponential back-off.
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+	logger  *logger.Logger
+}
+
+// NewClient creates a TMDB API client
+func NewClient(cfg Config, log *logger.Logger) *Client {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	cfg.ImageBaseURL = strings.TrimRight(cfg.ImageBaseURL, "/")
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: circuitbreaker.New(circuitbreaker.DefaultConfig("tmdb"), log),
+		logger:  log,
+	}
+}
+
+// movieResponse is TMDB's movie details with credits appended
+type movieResponse struct {
+	ID               int    `json:"id"`
+	Title            string `json:"title"`
+	OriginalTitle    string `json:"original_title"`
+	Overview         string `json:"overview"`
+	PosterPath       string `json:"poster_path"`
+	BackdropPath     string `json:"backdrop_path"`
+	Runtime          int    `json:"runtime"`
+	ReleaseDate      string `json:"release_date"`
+	OriginalLanguage string `json:"original_language"`
+	Genres           []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Credits struct {
+		Cast []struct {
+			Name string `json:"name"`
+		} `json:"cast"`
+		Crew []struct {
+			Name string `json:"name"`
+			Job  string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+}
+
+// statusError is a non-200 response; only server errors and rate limiting
+// are worth retrying
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("tmdb returned %d: %s", e.code, e.body)
+}
+
+func (e *statusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// GetMovie returns TMDB's metadata for a movie, with its credits
+func (c *Client) GetMovie(ctx context.Context, id int) (*Movie, error) {
+	query := url.Values{}
+	query.Set("api_key", c.cfg.APIKey)
+	query.Set("append_to_response", "credits")
+	endpoint := c.cfg.BaseURL + "/movie/" + strconv.Itoa(id) + "?" + query.Encode()
+
+	var (
+		res      movieResponse
+		notFound bool
+	)
+	backoff := c.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+			res = movieResponse{}
+			err := c.get(ctx, endpoint, &res)
+			// An unknown ID says nothing about TMDB's health
+			var statusErr *statusError
+			if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+				notFound = true
+				return nil
+			}
+			return err
+		})
+		if notFound {
+			return nil, ErrNotFound
+		}
+		if err == nil {
+			return c.toMovie(&res), nil
+		}
+
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && !statusErr.retryable() {
+			return nil, err
+		}
+		if errors.Is(err, circuitbreaker.ErrCircuitOpen) || attempt == maxAttempts {
+			return nil, err
+		}
+
+		c.logger.Warn("tmdb request failed, retrying",
+			zap.Int("tmdb_id", id),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		// The URL carries the API key; keep it out of errors and logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("tmdb request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return &statusError{code: res.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid tmdb response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) toMovie(res *movieResponse) *Movie {
+	movie := &Movie{
+		ID:               res.ID,
+		Title:            res.Title,
+		OriginalTitle:    res.OriginalTitle,
+		Overview:         res.Overview,
+		PosterURL:        c.imageURL(res.PosterPath),
+		BackdropURL:      c.imageURL(res.BackdropPath),
+		Runtime:          res.Runtime,
+		ReleaseDate:      res.ReleaseDate,
+		OriginalLanguage: res.OriginalLanguage,
+	}
+	for _, genre := range res.Genres {
+		movie.Genres = append(movie.Genres, genre.Name)
+	}
+	// Cast comes in billing order
+	for _, member := range res.Credits.Cast {
+		if c.cfg.CastLimit > 0 && len(movie.Cast) >= c.cfg.CastLimit {
+			break
+		}
+		movie.Cast = append(movie.Cast, member.Name)
+	}
+	for _, member := range res.Credits.Crew {
+		if member.Job == "Director" {
+			movie.Director = member.Name
+			break
+		}
+	}
+	return movie
+}
+
+func (c *Client) imageURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	return c.cfg.ImageBaseURL + path
+}
@@ -0,0 +1,158 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/circuitbreaker"
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const duneJSON = `{
+	"id": 693134,
+	"title": "Dune: Part Two",
+	"original_title": "Dune: Part Two",
+	"overview": "Paul Atreides unites with Chani and the Fremen.",
+	"poster_path": "/poster.jpg",
+	"backdrop_path": "",
+	"runtime": 167,
+	"release_date": "2024-02-27",
+	"original_language": "en",
+	"genres": [{"name": "Science Fiction"}, {"name": "Adventure"}],
+	"credits": {
+		"cast": [{"name": "Timothée Chalamet"}, {"name": "Zendaya"}, {"name": "Rebecca Ferguson"}],
+		"crew": [{"name": "Hans Zimmer", "job": "Original Music Composer"}, {"name": "Denis Villeneuve", "job": "Director"}]
+	}
+}`
+
+// tmdbServer answers each request with the next status in statuses, then
+// with 200 and duneJSON, and counts the requests
+func tmdbServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			w.Write([]byte(`{"status_message":"nope"}`))
+			return
+		}
+		if r.URL.Path != "/3/movie/693134" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api_key"); got != "secret-key" {
+			t.Errorf("api_key = %q", got)
+		}
+		if got := r.URL.Query().Get("append_to_response"); got != "credits" {
+			t.Errorf("append_to_response = %q", got)
+		}
+		w.Write([]byte(duneJSON))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newTestClient(baseURL string) *Client {
+	return NewClient(Config{
+		APIKey:       "secret-key",
+		BaseURL:      baseURL + "/3/",
+		ImageBaseURL: "https://image.example.com/t/p/original/",
+		Timeout:      time.Second,
+		Backoff:      time.Millisecond,
+		CastLimit:    2,
+	}, &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestGetMovie(t *testing.T) {
+	srv, _ := tmdbServer(t)
+
+	movie, err := newTestClient(srv.URL).GetMovie(context.Background(), 693134)
+	if err != nil {
+		t.Fatalf("GetMovie: %v", err)
+	}
+	if movie.Title != "Dune: Part Two" || movie.Runtime != 167 || movie.ReleaseDate != "2024-02-27" {
+		t.Errorf("movie = %+v", movie)
+	}
+	if movie.PosterURL != "https://image.example.com/t/p/original/poster.jpg" {
+		t.Errorf("poster = %q", movie.PosterURL)
+	}
+	if movie.BackdropURL != "" {
+		t.Errorf("backdrop = %q, want none without a path", movie.BackdropURL)
+	}
+	if !slices.Equal(movie.Genres, []string{"Science Fiction", "Adventure"}) {
+		t.Errorf("genres = %v", movie.Genres)
+	}
+	if !slices.Equal(movie.Cast, []string{"Timothée Chalamet", "Zendaya"}) {
+		t.Errorf("cast = %v, want the two top-billed", movie.Cast)
+	}
+	if movie.Director != "Denis Villeneuve" {
+		t.Errorf("director = %q", movie.Director)
+	}
+}
+
+func TestGetMovieRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int32
+		wantErr      bool
+	}{
+		{"server error then success", []int{500, 502}, 3, false},
+		{"rate limited", []int{429}, 2, false},
+		{"three server errors", []int{503, 503, 503}, 3, true},
+		{"unauthorized is not retried", []int{401}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := tmdbServer(t, tt.statuses...)
+
+			_, err := newTestClient(srv.URL).GetMovie(context.Background(), 693134)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("%d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestGetMovieNotFound(t *testing.T) {
+	srv, requests := tmdbServer(t, 404, 404, 404, 404, 404, 404)
+	client := newTestClient(srv.URL)
+
+	// More unknown IDs than the breaker tolerates failures
+	for range 6 {
+		if _, err := client.GetMovie(context.Background(), 693134); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+	}
+	if got := requests.Load(); got != 6 {
+		t.Errorf("%d requests, want one per lookup", got)
+	}
+	if state := client.breaker.State(); state != circuitbreaker.StateClosed {
+		t.Errorf("breaker is %s after unknown IDs", state)
+	}
+}
+
+func TestGetMovieKeepsTheAPIKeyOutOfErrors(t *testing.T) {
+	srv, _ := tmdbServer(t)
+	srv.Close()
+
+	_, err := newTestClient(srv.URL).GetMovie(context.Background(), 693134)
+	if err == nil {
+		t.Fatal("GetMovie succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
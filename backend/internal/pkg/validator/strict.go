@@ -0,0 +1,233 @@
+package validator
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeStrict decodes a JSON body into v, rejecting fields v does not
+// declare at any depth. Unknown fields are returned as validation errors
+// naming the field's path (e.g. "devices[0].device_typ") and, when one is
+// close enough, the field that was probably meant. A body that is not
+// valid JSON for v returns an error instead.
+func DecodeStrict(r io.Reader, v any) ([]ValidationError, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	if unknown := unknownFields(raw, reflect.TypeOf(v), ""); len(unknown) > 0 {
+		return unknown, nil
+	}
+
+	// Anything the walk cannot see (e.g. custom unmarshalers) is still
+	// caught by the decoder
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	return nil, decoder.Decode(v)
+}
+
+// unknownFields walks a decoded JSON value alongside the Go type it will be
+// decoded into and reports object keys the type has no field for
+func unknownFields(raw any, t reflect.Type, path string) []ValidationError {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) ||
+		t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	var errs []ValidationError
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := lookupField(fields, key)
+			if !ok {
+				errs = append(errs, unknownFieldError(joinPath(path, key), key, fields))
+				continue
+			}
+			errs = append(errs, unknownFields(object[key], fieldType, joinPath(path, key))...)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			errs = append(errs, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			errs = append(errs, unknownFields(object[key], t.Elem(), joinPath(path, key))...)
+		}
+	}
+	return errs
+}
+
+// jsonFields returns a struct's JSON field names and types the way
+// encoding/json sees them, including fields promoted from embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.SplitN(tag, ",", 2)[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for promoted, promotedType := range jsonFields(embedded) {
+					if _, ok := fields[promoted]; !ok {
+						fields[promoted] = promotedType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupField matches a key exactly, then case-insensitively, as
+// encoding/json does
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func unknownFieldError(path, key string, fields map[string]reflect.Type) ValidationError {
+	message := fmt.Sprintf("unknown field %q", path)
+	if suggestion := suggestField(key, fields); suggestion != "" {
+		message += fmt.Sprintf("; did you mean %q?", suggestion)
+	}
+	return ValidationError{
+		Field:   path,
+		Tag:     "unknown",
+		Message: message,
+	}
+}
+
+// suggestField returns the field name closest to key, ignoring case and
+// separators, or "" when none is close enough to be a likely typo. A key
+// that is a shortened form of exactly one field (e.g. "duration" for
+// "duration_minutes") suggests that field.
+func suggestField(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	target := normalizeField(key)
+	best, bestDistance := "", -1
+	for _, name := range names {
+		distance := levenshtein(target, normalizeField(name))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	if bestDistance >= 0 && bestDistance <= max(2, len(target)/3) {
+		return best
+	}
+
+	prefixed := ""
+	for _, name := range names {
+		normalized := normalizeField(name)
+		if min(len(target), len(normalized)) >= 4 && (strings.HasPrefix(normalized, target) || strings.HasPrefix(target, normalized)) {
+			if prefixed != "" {
+				return ""
+			}
+			prefixed = name
+		}
+	}
+	return prefixed
+}
+
+// normalizeField folds case and drops separators, so camelCase, snake_case
+// and kebab-case spellings of a name compare equal
+func normalizeField(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
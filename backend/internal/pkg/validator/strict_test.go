@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testDevice struct {
+	DeviceType string `json:"device_type"`
+	Quantity   int    `json:"quantity"`
+}
+
+type testAudit struct {
+	Reason string `json:"reason"`
+}
+
+type testScreen struct {
+	testAudit
+	Name        string                `json:"name"`
+	Devices     []testDevice          `json:"devices"`
+	Layout      *testDevice           `json:"layout,omitempty"`
+	Labels      map[string]testDevice `json:"labels"`
+	OpensAt     time.Time             `json:"opens_at"`
+	Ignored     string                `json:"-"`
+	NoTag       string
+	hiddenField string
+}
+
+type testMovie struct {
+	Title           string  `json:"title"`
+	DurationMinutes int     `json:"duration_minutes"`
+	PopularityScore float64 `json:"popularity_score"`
+	ReleaseDate     string  `json:"release_date"`
+	AgeRating       string  `json:"age_rating"`
+}
+
+func TestDecodeStrictUnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string // field paths
+	}{
+		{"known fields", `{"name":"S1","devices":[{"device_type":"HEADSET","quantity":2}],"reason":"r","NoTag":"x","opens_at":"2026-10-16T12:00:00Z"}`, nil},
+		{"case-insensitive match", `{"Name":"S1","DEVICES":[]}`, nil},
+		{"top level", `{"name":"S1","nmae":"S2"}`, []string{"nmae"}},
+		{"nested object", `{"layout":{"device_type":"HEADSET","qty":1}}`, []string{"layout.qty"}},
+		{"array of objects", `{"devices":[{"quantity":1},{"quantity":2,"devicetype":"HEADSET"}]}`, []string{"devices[1].devicetype"}},
+		{"map values", `{"labels":{"front":{"quantty":1}}}`, []string{"labels.front.quantty"}},
+		{"json:\"-\" field", `{"Ignored":"x"}`, []string{"Ignored"}},
+		{"unexported field", `{"hiddenField":"x"}`, []string{"hiddenField"}},
+		{"several, in order", `{"zeta":1,"alpha":2}`, []string{"alpha", "zeta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var screen testScreen
+			unknown, err := DecodeStrict(strings.NewReader(tt.body), &screen)
+			if err != nil {
+				t.Fatalf("DecodeStrict: %v", err)
+			}
+			if len(unknown) != len(tt.want) {
+				t.Fatalf("unknown = %+v, want %v", unknown, tt.want)
+			}
+			for i, field := range tt.want {
+				if unknown[i].Field != field || unknown[i].Tag != "unknown" {
+					t.Errorf("unknown[%d] = %+v, want %s", i, unknown[i], field)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeStrictDecodes(t *testing.T) {
+	var screen testScreen
+	unknown, err := DecodeStrict(strings.NewReader(`{"name":"S1","devices":[{"device_type":"HEADSET","quantity":2}],"reason":"refit"}`), &screen)
+	if err != nil || unknown != nil {
+		t.Fatalf("DecodeStrict = %v, %v", unknown, err)
+	}
+	if screen.Name != "S1" || len(screen.Devices) != 1 || screen.Devices[0].Quantity != 2 || screen.Reason != "refit" {
+		t.Errorf("decoded %+v", screen)
+	}
+
+	if _, err := DecodeStrict(strings.NewReader(`{"name":`), &screen); err == nil {
+		t.Error("malformed JSON was accepted")
+	}
+	if _, err := DecodeStrict(strings.NewReader(`{"name":42}`), &screen); err == nil {
+		t.Error("a wrongly typed field was accepted")
+	}
+}
+
+func TestUnknownFieldSuggestions(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string // "" for no suggestion
+	}{
+		{"popularityScore", "popularity_score"},
+		{"popularity-score", "popularity_score"},
+		{"popularty_score", "popularity_score"},
+		{"titel", "title"},
+		{"duration", "duration_minutes"},
+		{"durationMins", "duration_minutes"},
+		{"releaseDate", "release_date"},
+		{"age", ""}, // too short to guess from a prefix
+		{"rating", ""},
+		{"director", ""},
+		{"x", ""},
+	}
+	for _, tt := range tests {
+		var movie testMovie
+		unknown, err := DecodeStrict(strings.NewReader(`{"`+tt.key+`":1}`), &movie)
+		if err != nil || len(unknown) != 1 {
+			t.Fatalf("%s: DecodeStrict = %v, %v", tt.key, unknown, err)
+		}
+		message := unknown[0].Message
+		if tt.want == "" {
+			if strings.Contains(message, "did you mean") {
+				t.Errorf("%s: %q, want no suggestion", tt.key, message)
+			}
+			continue
+		}
+		if !strings.HasSuffix(message, `did you mean "`+tt.want+`"?`) {
+			t.Errorf("%s: %q, want %s suggested", tt.key, message, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"title", "titel", 2},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ws
+
+import (
+	"sync"
+)
+
+// Hub fans messages out to the subscribers of a topic. Topics exist only
+// while they have subscribers. A subscriber that falls behind is dropped
+// rather than slowing the others down.
+type Hub struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+// Subscription receives the messages broadcast to one topic
+type Subscription struct {
+	topic string
+	send  chan []byte
+	hub   *Hub
+	once  sync.Once
+}
+
+// NewHub creates a hub whose subscribers buffer up to bufferSize messages
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &Hub{
+		bufferSize: bufferSize,
+		topics:     make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe starts receiving the messages of a topic
+func (h *Hub) Subscribe(topic string) *Subscription {
+	sub := &Subscription{
+		topic: topic,
+		send:  make(chan []byte, h.bufferSize),
+		hub:   h,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs, ok := h.topics[topic]
+	if !ok {
+		subs = make(map[*Subscription]struct{})
+		h.topics[topic] = subs
+	}
+	subs[sub] = struct{}{}
+	return sub
+}
+
+// Broadcast sends a message to every subscriber of a topic without
+// blocking. Subscribers whose buffer is full are closed.
+func (h *Hub) Broadcast(topic string, msg []byte) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for sub := range h.topics[topic] {
+		select {
+		case sub.send <- msg:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.Close()
+	}
+}
+
+// Subscribers returns how many subscribers a topic has
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+// Messages returns the channel messages arrive on. It is closed when the
+// subscription is closed, by the subscriber or by the hub.
+func (s *Subscription) Messages() <-chan []byte {
+	return s.send
+}
+
+// Close stops the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		h := s.hub
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if subs, ok := h.topics[s.topic]; ok {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(h.topics, s.topic)
+			}
+		}
+		close(s.send)
+	})
+}
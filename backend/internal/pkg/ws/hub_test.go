@@ -0,0 +1,71 @@
+package ws
+
+import (
+	"testing"
+)
+
+func TestHubBroadcast(t *testing.T) {
+	h := NewHub(4)
+	a, b := h.Subscribe("showtime-1"), h.Subscribe("showtime-1")
+	other := h.Subscribe("showtime-2")
+
+	h.Broadcast("showtime-1", []byte("held"))
+	for _, sub := range []*Subscription{a, b} {
+		if msg := <-sub.Messages(); string(msg) != "held" {
+			t.Errorf("message = %q, want held", msg)
+		}
+	}
+	select {
+	case msg := <-other.Messages():
+		t.Errorf("another topic got %q", msg)
+	default:
+	}
+
+	// Broadcasting to a topic nobody watches is a no-op
+	h.Broadcast("showtime-3", []byte("held"))
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	h := NewHub(2)
+	slow, fast := h.Subscribe("t"), h.Subscribe("t")
+
+	for _, msg := range []string{"1", "2"} {
+		h.Broadcast("t", []byte(msg))
+		<-fast.Messages()
+	}
+	// slow's buffer is full: it is closed instead of blocking the others
+	h.Broadcast("t", []byte("3"))
+	if msg := <-fast.Messages(); string(msg) != "3" {
+		t.Errorf("fast subscriber got %q, want 3", msg)
+	}
+
+	var got []string
+	for msg := range slow.Messages() {
+		got = append(got, string(msg))
+	}
+	if len(got) != 2 {
+		t.Errorf("slow subscriber got %v before being closed, want the two buffered messages", got)
+	}
+	if n := h.Subscribers("t"); n != 1 {
+		t.Errorf("%d subscribers, want 1", n)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	h := NewHub(1)
+	sub := h.Subscribe("t")
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.Messages(); ok {
+		t.Error("the channel of a closed subscription is open")
+	}
+	if n := h.Subscribers("t"); n != 0 {
+		t.Errorf("%d subscribers after Close, want 0", n)
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if _, ok := h.topics["t"]; ok {
+		t.Error("the topic outlived its last subscriber")
+	}
+}
@@ -1,14 +1,37 @@
 package provider
 
 import (
+	adminanalyticsapp "cinemaos-backend/internal/app/adminanalytics"
 	authapp "cinemaos-backend/internal/app/auth"
+	bookingapp "cinemaos-backend/internal/app/booking"
+	changelogapp "cinemaos-backend/internal/app/changelog"
 	cinemaapp "cinemaos-backend/internal/app/cinema"
+	confirmationapp "cinemaos-backend/internal/app/confirmation"
+	curationapp "cinemaos-backend/internal/app/curation"
+	dailyreportapp "cinemaos-backend/internal/app/dailyreport"
+	demandapp "cinemaos-backend/internal/app/demand"
+	groupbookingapp "cinemaos-backend/internal/app/groupbooking"
+	groupcheckoutapp "cinemaos-backend/internal/app/groupcheckout"
+	guestlookupapp "cinemaos-backend/internal/app/guestlookup"
+	holdrecoveryapp "cinemaos-backend/internal/app/holdrecovery"
+	loyaltyapp "cinemaos-backend/internal/app/loyalty"
 	movieapp "cinemaos-backend/internal/app/movie"
+	paymentapp "cinemaos-backend/internal/app/payment"
 	"cinemaos-backend/internal/app/postgres"
+	preferencesapp "cinemaos-backend/internal/app/preferences"
+	pricingapp "cinemaos-backend/internal/app/pricing"
+	promoapp "cinemaos-backend/internal/app/promo"
 	"cinemaos-backend/internal/app/redis"
+	seatfeedapp "cinemaos-backend/internal/app/seatfeed"
 	showtimeapp "cinemaos-backend/internal/app/showtime"
+	waitlistapp "cinemaos-backend/internal/app/waitlist"
+	warmupapp "cinemaos-backend/internal/app/warmup"
 	"cinemaos-backend/internal/config"
 	"cinemaos-backend/internal/handler"
+	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/scheduler"
+	"cinemaos-backend/internal/pkg/shadow"
 	"cinemaos-backend/internal/pkg/validator"
 )
 
@@ -25,8 +48,16 @@ func ProvideHealthHandler(
 	cfg *config.Config,
 	db *postgres.Database,
 	redisClient *redis.Client,
+	shadowReads *shadow.Reader,
+	warmup *warmupapp.Service,
 ) *handler.HealthHandler {
-	return handler.NewHealthHandler(cfg, db, redisClient)
+	// A nil client must reach the handler as a nil checker, which it reports
+	// as running without Redis
+	var redisChecker handler.HealthChecker
+	if redisClient != nil {
+		redisChecker = redisClient
+	}
+	return handler.NewHealthHandler(cfg, db, redisChecker, shadowReads, warmup)
 }
 
 // ProvideMovieHandler creates and returns a movie handler
@@ -49,7 +80,150 @@ func ProvideCinemaHandler(
 // ProvideShowtimeHandler creates and returns a showtime handler
 func ProvideShowtimeHandler(
 	showtimeService *showtimeapp.Service,
+	cinemaAccess *middleware.CinemaAccessMiddleware,
 	validator *validator.Validator,
 ) *handler.ShowtimeHandler {
-	return handler.NewShowtimeHandler(showtimeService, validator)
+	return handler.NewShowtimeHandler(showtimeService, cinemaAccess, validator)
+}
+
+// ProvideBookingHandler creates and returns a booking handler
+func ProvideBookingHandler(
+	bookingService *bookingapp.Service,
+	confirmationService *confirmationapp.Service,
+	validator *validator.Validator,
+) *handler.BookingHandler {
+	return handler.NewBookingHandler(bookingService, confirmationService, validator)
+}
+
+// ProvideGroupCheckoutHandler creates and returns a group checkout handler
+func ProvideGroupCheckoutHandler(
+	groupCheckoutService *groupcheckoutapp.Service,
+	validator *validator.Validator,
+) *handler.GroupCheckoutHandler {
+	return handler.NewGroupCheckoutHandler(groupCheckoutService, validator)
+}
+
+// ProvideGroupBookingHandler creates and returns a group booking handler
+func ProvideGroupBookingHandler(
+	groupBookingService *groupbookingapp.Service,
+	validator *validator.Validator,
+) *handler.GroupBookingHandler {
+	return handler.NewGroupBookingHandler(groupBookingService, validator)
+}
+
+// ProvideHoldRecoveryHandler creates and returns a hold recovery handler
+func ProvideHoldRecoveryHandler(
+	holdRecoveryService *holdrecoveryapp.Service,
+	validator *validator.Validator,
+) *handler.HoldRecoveryHandler {
+	return handler.NewHoldRecoveryHandler(holdRecoveryService, validator)
+}
+
+// ProvidePaymentHandler creates and returns a payment handler
+func ProvidePaymentHandler(
+	paymentService *paymentapp.Service,
+	validator *validator.Validator,
+) *handler.PaymentHandler {
+	return handler.NewPaymentHandler(paymentService, validator)
+}
+
+// ProvideChangeLogHandler creates and returns a change history handler
+func ProvideChangeLogHandler(
+	changeLogService *changelogapp.Service,
+	validator *validator.Validator,
+) *handler.ChangeLogHandler {
+	return handler.NewChangeLogHandler(changeLogService, validator)
+}
+
+// ProvideCurationHandler creates and returns a homepage curation handler
+func ProvideCurationHandler(
+	curationService *curationapp.Service,
+	validator *validator.Validator,
+) *handler.CurationHandler {
+	return handler.NewCurationHandler(curationService, validator)
+}
+
+// ProvideGuestLookupHandler creates and returns a guest booking lookup handler
+func ProvideGuestLookupHandler(
+	guestLookupService *guestlookupapp.Service,
+	validator *validator.Validator,
+) *handler.GuestLookupHandler {
+	return handler.NewGuestLookupHandler(guestLookupService, validator)
+}
+
+// ProvideDailyReportHandler creates and returns a daily report handler
+func ProvideDailyReportHandler(
+	dailyReportService *dailyreportapp.Service,
+	validator *validator.Validator,
+) *handler.DailyReportHandler {
+	return handler.NewDailyReportHandler(dailyReportService, validator)
+}
+
+// ProvideDemandHandler creates and returns a showtime demand stats handler
+func ProvideDemandHandler(
+	demandService *demandapp.Service,
+	validator *validator.Validator,
+) *handler.DemandHandler {
+	return handler.NewDemandHandler(demandService, validator)
+}
+
+// ProvideLoyaltyHandler creates and returns a loyalty points handler
+func ProvideLoyaltyHandler(loyaltyService *loyaltyapp.Service) *handler.LoyaltyHandler {
+	return handler.NewLoyaltyHandler(loyaltyService)
+}
+
+// ProvidePreferencesHandler creates and returns a user preferences handler
+func ProvidePreferencesHandler(preferencesService *preferencesapp.Service, validator *validator.Validator) *handler.PreferencesHandler {
+	return handler.NewPreferencesHandler(preferencesService, validator)
+}
+
+// ProvideWaitlistHandler creates and returns a showtime waitlist handler
+func ProvideWaitlistHandler(
+	waitlistService *waitlistapp.Service,
+	validator *validator.Validator,
+) *handler.WaitlistHandler {
+	return handler.NewWaitlistHandler(waitlistService, validator)
+}
+
+// ProvidePricingHandler creates and returns a cinema pricing rule handler
+func ProvidePricingHandler(
+	pricingService *pricingapp.Service,
+	validator *validator.Validator,
+) *handler.PricingHandler {
+	return handler.NewPricingHandler(pricingService, validator)
+}
+
+// ProvidePromoHandler creates and returns an admin promo code handler
+func ProvidePromoHandler(
+	promoService *promoapp.Service,
+	validator *validator.Validator,
+) *handler.PromoHandler {
+	return handler.NewPromoHandler(promoService, validator)
+}
+
+// ProvideSeatUpdateHandler creates and returns a seat update WebSocket handler
+func ProvideSeatUpdateHandler(
+	feedService *seatfeedapp.Service,
+	showtimeService *showtimeapp.Service,
+	cfg *config.Config,
+) *handler.SeatUpdateHandler {
+	return handler.NewSeatUpdateHandler(feedService, showtimeService, cfg.CORS)
+}
+
+// ProvideAnalyticsHandler creates and returns a client analytics handler
+func ProvideAnalyticsHandler(
+	tracker *analytics.Tracker,
+	validator *validator.Validator,
+) *handler.AnalyticsHandler {
+	return handler.NewAnalyticsHandler(tracker, validator)
+}
+
+// ProvideAdminHandler creates and returns an admin handler
+func ProvideAdminHandler(
+	shadowReads *shadow.Reader,
+	jobs *scheduler.Runner,
+	analyticsService *adminanalyticsapp.Service,
+	validator *validator.Validator,
+) *handler.AdminHandler {
+	return handler.NewAdminHandler(shadowReads, jobs, analyticsService, validator)
 }
@@ -1,12 +1,24 @@
 package provider
 
 import (
+	"fmt"
+	"time"
+
 	"cinemaos-backend/internal/app/postgres"
 	"cinemaos-backend/internal/app/redis"
+	"cinemaos-backend/internal/app/repository"
 	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+	"cinemaos-backend/internal/pkg/metrics"
+	"cinemaos-backend/internal/pkg/shadow"
 	"cinemaos-backend/internal/pkg/tracer"
 	"cinemaos-backend/internal/pkg/validator"
+
+	"go.uber.org/zap"
 )
 
 // ProvideLogger creates and returns a logger instance
@@ -32,22 +44,164 @@ func ProvideTracer(cfg *config.Config) (*tracer.Tracer, error) {
 	})
 }
 
-// ProvideDatabase creates and returns a database connection
+// ProvideMetrics creates and returns the metrics recorder, which reports the
+// active seat holds, the Redis pool size and the movie list cache hits, and
+// records the duration of every database query
+func ProvideMetrics(cfg *config.Config, database *postgres.Database, redisClient *redis.Client, holdRepo repository.SeatHoldRepository, movieRepo *redis.CachedMovieRepository) (*metrics.Metrics, error) {
+	gauges := metrics.Gauges{
+		ActiveSeatHolds: holdRepo.CountActive,
+		MovieListCache:  movieRepo.Stats,
+	}
+	if redisClient != nil {
+		gauges.RedisPoolSize = func() int64 {
+			return int64(redisClient.GetClient().PoolStats().TotalConns)
+		}
+	}
+
+	m, err := metrics.New(metrics.Config{
+		Enabled:     cfg.Metrics.Enabled,
+		ServiceName: cfg.Tracer.ServiceName,
+		Environment: cfg.App.Environment,
+		Version:     cfg.App.Version,
+	}, gauges)
+	if err != nil {
+		return nil, err
+	}
+	database.RecordQueries(m)
+	return m, nil
+}
+
+// ProvideDatabase creates and returns a database connection, retrying while
+// the database comes up alongside the server
 func ProvideDatabase(cfg *config.Config, log *logger.Logger) (*postgres.Database, error) {
-	return postgres.New(cfg.Database, log)
+	return connectWithRetry(log, "database", cfg.Database.ConnectAttempts, cfg.Database.ConnectMaxWait, func() (*postgres.Database, error) {
+		return postgres.New(cfg.Database, log)
+	})
 }
 
 // ProvideRedis creates and returns a Redis client
-// Note: Returns nil error if Redis is optional and connection fails
+// Note: Returns nil error if Redis is still down after the connect attempts;
+// the server then runs in degraded mode, reported by /health/ready, with
+// seat holds kept in the database
 func ProvideRedis(cfg *config.Config, log *logger.Logger) (*redis.Client, error) {
-	client, err := redis.New(cfg.Redis, log)
+	client, err := connectWithRetry(log, "redis", cfg.Redis.ConnectAttempts, cfg.Redis.ConnectMaxWait, func() (*redis.Client, error) {
+		return redis.New(cfg.Redis, log)
+	})
 	if err != nil {
-		log.Error("Failed to connect to Redis, continuing without it")
+		log.Error("Failed to connect to Redis, continuing without it in degraded mode", zap.Error(err))
 		return nil, nil // Return nil client but no error (optional dependency)
 	}
 	return client, nil
 }
 
+// connectInitialWait is the wait after the first failed connect attempt,
+// doubled after each one up to the configured maximum
+const connectInitialWait = 500 * time.Millisecond
+
+// connectWithRetry calls connect until it succeeds or the attempts run out,
+// returning the last error
+func connectWithRetry[T any](log *logger.Logger, name string, attempts int, maxWait time.Duration, connect func() (T, error)) (T, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := connectInitialWait
+	for attempt := 1; ; attempt++ {
+		conn, err := connect()
+		if err == nil || attempt >= attempts {
+			return conn, err
+		}
+
+		if maxWait > 0 && wait > maxWait {
+			wait = maxWait
+		}
+		log.Warn("connection failed, retrying",
+			zap.String("dependency", name),
+			zap.Int("attempt", attempt),
+			zap.Int("attempts", attempts),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// ProvideAsyncDispatcher creates and returns the background job dispatcher
+// Note: The dispatcher is started and stopped by the application in main
+func ProvideAsyncDispatcher(sender async.EmailSender, log *logger.Logger) *async.Dispatcher {
+	return async.NewDispatcher(5, 500, sender, log)
+}
+
+// ProvideEmailSender creates the SMTP email sender, or one that only logs
+// emails when no SMTP host or sender address is configured
+func ProvideEmailSender(cfg *config.Config, log *logger.Logger) async.EmailSender {
+	if cfg.Email.SMTPHost == "" || cfg.Email.FromAddress == "" {
+		log.Warn("email delivery disabled, no SMTP host or from address configured")
+		return mailer.NewLogSender(log)
+	}
+	return mailer.NewSMTPSender(mailer.Config{
+		Host:        cfg.Email.SMTPHost,
+		Port:        cfg.Email.SMTPPort,
+		Username:    cfg.Email.SMTPUser,
+		Password:    cfg.Email.SMTPPassword,
+		FromAddress: cfg.Email.FromAddress,
+		FromName:    cfg.Email.FromName,
+		Timeout:     cfg.Email.Timeout,
+	})
+}
+
+// ProvideEmailClient creates the client that renders the account and
+// booking emails and queues them on the dispatcher
+func ProvideEmailClient(dispatcher *async.Dispatcher, cfg *config.Config) mailer.EmailClient {
+	return mailer.NewClient(dispatcher, cfg.Email.FrontendURL)
+}
+
+// ProvideEventBus creates and returns the in-process domain event bus
+// Note: Subscribers register in the service providers; the bus is started and stopped in main
+func ProvideEventBus(cfg *config.Config, log *logger.Logger) *eventbus.Bus {
+	return eventbus.New(eventbus.Config{
+		Lanes:          cfg.Events.Lanes,
+		QueueSize:      cfg.Events.QueueSize,
+		MaxAttempts:    cfg.Events.MaxAttempts,
+		RetryBackoff:   cfg.Events.RetryBackoff,
+		PublishTimeout: cfg.Events.PublishTimeout,
+	}, log)
+}
+
+// ProvideAnalyticsTracker creates and returns the funnel analytics tracker
+// with the configured sink
+// Note: The sink is flushed and closed by the application in main
+func ProvideAnalyticsTracker(cfg *config.Config, dispatcher *async.Dispatcher, log *logger.Logger) (*analytics.Tracker, error) {
+	var sink analytics.Sink = analytics.NoopSink{}
+	switch cfg.Analytics.Sink {
+	case "file":
+		fileSink, err := analytics.NewFileSink(cfg.Analytics.FilePath, cfg.Analytics.BufferSize, cfg.Analytics.FlushInterval)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	case "", "noop":
+	default:
+		return nil, fmt.Errorf("unknown analytics sink %q", cfg.Analytics.Sink)
+	}
+
+	return analytics.NewTracker(analytics.Config{
+		DefaultSampleRate: cfg.Analytics.DefaultSampleRate,
+		SampleRates:       cfg.Analytics.SampleRates,
+	}, sink, dispatcher, log), nil
+}
+
+// ProvideShadowReader creates and returns the shadow-read verifier
+func ProvideShadowReader(cfg *config.Config, log *logger.Logger) *shadow.Reader {
+	return shadow.New(shadow.Config{
+		Enabled:     cfg.Shadow.Enabled,
+		SampleRate:  cfg.Shadow.SampleRate,
+		Comparisons: cfg.Shadow.Comparisons,
+		Timeout:     cfg.Shadow.Timeout,
+		MaxInFlight: cfg.Shadow.MaxInFlight,
+	}, log)
+}
+
 // ProvideValidator creates and returns a request validator
 func ProvideValidator() *validator.Validator {
 	return validator.New()
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cinemaos-backend/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	errDown := errors.New("connection refused")
+
+	// flaky fails its first failures calls
+	flaky := func(failures int) (func() (string, error), *int) {
+		calls := 0
+		return func() (string, error) {
+			calls++
+			if calls <= failures {
+				return "", errDown
+			}
+			return "conn", nil
+		}, &calls
+	}
+
+	tests := []struct {
+		name      string
+		attempts  int
+		failures  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{"first try", 3, 0, false, 1},
+		{"comes up in time", 3, 2, false, 3},
+		{"still down", 3, 5, true, 3},
+		{"at least one attempt", 0, 5, true, 1},
+	}
+	for _, tt := range tests {
+		connect, calls := flaky(tt.failures)
+		conn, err := connectWithRetry(log, "test", tt.attempts, time.Millisecond, connect)
+		if (err != nil) != tt.wantErr || *calls != tt.wantCalls {
+			t.Errorf("%s: %q, %v after %d calls, want error %v after %d", tt.name, conn, err, *calls, tt.wantErr, tt.wantCalls)
+		}
+		if err != nil && !errors.Is(err, errDown) {
+			t.Errorf("%s: %v, want the last connection error", tt.name, err)
+		}
+	}
+}
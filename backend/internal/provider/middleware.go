@@ -1,7 +1,12 @@
 package provider
 
 import (
+	"time"
+
 	"cinemaos-backend/internal/app/authinfra"
+	"cinemaos-backend/internal/app/redis"
+	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/config"
 	"cinemaos-backend/internal/middleware"
 	"cinemaos-backend/internal/pkg/logger"
 )
@@ -9,7 +14,73 @@ import (
 // ProvideAuthMiddleware creates and returns an auth middleware
 func ProvideAuthMiddleware(
 	jwtManager *authinfra.JWTManager,
+	userRateLimiter *middleware.UserRateLimiter,
 	logger *logger.Logger,
 ) *middleware.AuthMiddleware {
-	return middleware.NewAuthMiddleware(jwtManager, logger)
+	return middleware.NewAuthMiddleware(jwtManager, userRateLimiter, logger)
+}
+
+// ProvideUserRateLimiter creates the per-user rate limiter, with token
+// buckets shared through Redis when it is available
+func ProvideUserRateLimiter(
+	cfg *config.Config,
+	redisClient *redis.Client,
+	logger *logger.Logger,
+) *middleware.UserRateLimiter {
+	var shared middleware.WindowLimiter
+	if redisClient != nil {
+		shared = redis.NewTokenBucket(redisClient)
+	}
+	return middleware.NewUserRateLimiter(shared, cfg.RateLimit.Users, logger)
+}
+
+// ProvideCinemaAccessMiddleware creates and returns the cinema-scoped access middleware
+func ProvideCinemaAccessMiddleware(
+	staffRepo repository.CinemaStaffRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	logger *logger.Logger,
+) *middleware.CinemaAccessMiddleware {
+	return middleware.NewCinemaAccessMiddleware(staffRepo, showtimeRepo, logger)
+}
+
+// ProvideIdempotency creates the idempotency key middleware, which is a
+// no-op without Redis
+func ProvideIdempotency(
+	cfg *config.Config,
+	redisClient *redis.Client,
+	logger *logger.Logger,
+) *middleware.Idempotency {
+	var store middleware.KeyValueStore
+	if redisClient != nil {
+		store = redis.NewIdempotencyStore(redisClient)
+	}
+	return middleware.NewIdempotency(store, cfg.Idempotency, logger)
+}
+
+// ProvideRateLimiter creates the request rate limiter, shared through Redis
+// when it is available
+func ProvideRateLimiter(
+	cfg *config.Config,
+	redisClient *redis.Client,
+	logger *logger.Logger,
+) *middleware.RateLimiter {
+	limits := cfg.RateLimit
+	limits.Routes = make(map[string]config.LimitConfig, len(cfg.RateLimit.Routes)+1)
+	for pattern, limit := range cfg.RateLimit.Routes {
+		limits.Routes[pattern] = limit
+	}
+	// Client analytics keep their own, tighter budget unless configured
+	if _, ok := limits.Routes["/analytics/events"]; !ok {
+		ingestLimit := cfg.Analytics.IngestRateLimit
+		if ingestLimit <= 0 {
+			ingestLimit = 30
+		}
+		limits.Routes["/analytics/events"] = config.LimitConfig{Limit: ingestLimit, Window: time.Minute}
+	}
+
+	var shared middleware.WindowLimiter
+	if redisClient != nil {
+		shared = redis.NewRateLimiter(redisClient)
+	}
+	return middleware.NewRateLimiter(shared, limits, logger)
 }
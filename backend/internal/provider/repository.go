@@ -2,7 +2,12 @@ package provider
 
 import (
 	"cinemaos-backend/internal/app/postgres"
+	"cinemaos-backend/internal/app/redis"
 	"cinemaos-backend/internal/app/repository"
+	"cinemaos-backend/internal/app/shadowread"
+	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/scheduler"
+	"cinemaos-backend/internal/pkg/shadow"
 )
 
 // ProvideUserRepository creates and returns a user repository
@@ -20,9 +25,30 @@ func ProvidePasswordResetTokenRepository(db *postgres.Database) repository.Passw
 	return postgres.NewPasswordResetTokenRepository(db)
 }
 
+// ProvideEmailVerificationTokenRepository creates and returns an email verification token repository
+func ProvideEmailVerificationTokenRepository(db *postgres.Database) repository.EmailVerificationTokenRepository {
+	return postgres.NewEmailVerificationTokenRepository(db)
+}
+
+// ProvideCachedMovieRepository creates the movie repository with its
+// listings cached in Redis
+func ProvideCachedMovieRepository(db *postgres.Database, redisClient *redis.Client, cfg *config.Config) *redis.CachedMovieRepository {
+	return redis.NewCachedMovieRepository(postgres.NewMovieRepository(db), redisClient, cfg.Movie.ListCacheTTL)
+}
+
 // ProvideMovieRepository creates and returns a movie repository
-func ProvideMovieRepository(db *postgres.Database) repository.MovieRepository {
-	return postgres.NewMovieRepository(db)
+func ProvideMovieRepository(cached *redis.CachedMovieRepository) repository.MovieRepository {
+	return cached
+}
+
+// ProvideMovieMediaRepository creates and returns a movie media repository
+func ProvideMovieMediaRepository(db *postgres.Database) repository.MovieMediaRepository {
+	return postgres.NewMovieMediaRepository(db)
+}
+
+// ProvideReviewRepository creates and returns a movie review repository
+func ProvideReviewRepository(db *postgres.Database) repository.ReviewRepository {
+	return postgres.NewReviewRepository(db)
 }
 
 // ProvideCinemaRepository creates and returns a cinema repository
@@ -36,11 +62,173 @@ func ProvideScreenRepository(db *postgres.Database) repository.ScreenRepository
 }
 
 // ProvideSeatRepository creates and returns a seat repository
-func ProvideSeatRepository(db *postgres.Database) repository.SeatRepository {
-	return postgres.NewSeatRepository(db)
+func ProvideSeatRepository(db *postgres.Database, redisClient *redis.Client, cfg *config.Config) repository.SeatRepository {
+	// Seat layouts are cached per screen for seat maps
+	return redis.NewCachedSeatRepository(postgres.NewSeatRepository(db), redisClient, cfg.Booking.SeatLayoutCacheTTL)
 }
 
 // ProvideShowtimeRepository creates and returns a showtime repository
-func ProvideShowtimeRepository(db *postgres.Database) repository.ShowtimeRepository {
-	return postgres.NewShowtimeRepository(db)
+func ProvideShowtimeRepository(db *postgres.Database, redisClient *redis.Client, cfg *config.Config) repository.ShowtimeRepository {
+	// A movie's showtime listing is cached briefly for the movie page
+	return redis.NewCachedShowtimeRepository(postgres.NewShowtimeRepository(db), redisClient, cfg.Availability.CacheTTL)
+}
+
+// ProvideBookingRepository creates and returns a booking repository
+// Note: List reads are shadowed against the single-query implementation
+func ProvideBookingRepository(db *postgres.Database, reader *shadow.Reader) repository.BookingRepository {
+	return shadowread.NewBookingRepository(
+		postgres.NewBookingRepository(db),
+		postgres.NewSingleQueryBookingRepository(db),
+		reader,
+	)
+}
+
+// ProvideBookingSeatRepository creates and returns a booking seat repository
+// Note: Booked seat reads are shadowed against the semi-join implementation
+func ProvideBookingSeatRepository(db *postgres.Database, reader *shadow.Reader) repository.BookingSeatRepository {
+	return shadowread.NewBookingSeatRepository(
+		postgres.NewBookingSeatRepository(db),
+		postgres.NewSemiJoinBookingSeatRepository(db),
+		reader,
+	)
+}
+
+// ProvidePromoCodeRepository creates and returns a promo code repository
+func ProvidePromoCodeRepository(db *postgres.Database) repository.PromoCodeRepository {
+	return postgres.NewPromoCodeRepository(db)
+}
+
+// ProvidePaymentRepository creates and returns a payment repository
+func ProvidePaymentRepository(db *postgres.Database) repository.PaymentRepository {
+	return postgres.NewPaymentRepository(db)
+}
+
+// ProvideTransactor creates and returns a transactor for work spanning
+// repositories
+func ProvideTransactor(db *postgres.Database) repository.Transactor {
+	return postgres.NewTransactor(db)
+}
+
+// ProvideOutboxRepository creates and returns an event outbox repository
+func ProvideOutboxRepository(db *postgres.Database) repository.OutboxRepository {
+	return postgres.NewOutboxRepository(db)
+}
+
+// ProvideGroupCheckoutRepository creates and returns a group checkout repository
+func ProvideGroupCheckoutRepository(db *postgres.Database) repository.GroupCheckoutRepository {
+	return postgres.NewGroupCheckoutRepository(db)
+}
+
+// ProvideHoldRecoveryRepository creates and returns a hold recovery repository
+func ProvideHoldRecoveryRepository(db *postgres.Database) repository.HoldRecoveryRepository {
+	return postgres.NewHoldRecoveryRepository(db)
+}
+
+// ProvideAssistiveDeviceRepository creates and returns an assistive device repository
+func ProvideAssistiveDeviceRepository(db *postgres.Database) repository.AssistiveDeviceRepository {
+	return postgres.NewAssistiveDeviceRepository(db)
+}
+
+// ProvideSeatTypeRuleRepository creates and returns a seat type rule repository
+func ProvideSeatTypeRuleRepository(db *postgres.Database) repository.SeatTypeRuleRepository {
+	return postgres.NewSeatTypeRuleRepository(db)
+}
+
+// ProvideDailyReportRepository creates and returns a daily report repository
+func ProvideDailyReportRepository(db *postgres.Database) repository.DailyReportRepository {
+	return postgres.NewDailyReportRepository(db)
+}
+
+// ProvideDemandRepository creates and returns a showtime demand stats repository
+func ProvideDemandRepository(db *postgres.Database) repository.DemandRepository {
+	return postgres.NewDemandRepository(db)
+}
+
+// ProvideGroupBookingRepository creates and returns a group booking repository
+func ProvideGroupBookingRepository(db *postgres.Database) repository.GroupBookingRepository {
+	return postgres.NewGroupBookingRepository(db)
+}
+
+// ProvideLoyaltyRepository creates and returns a loyalty points repository
+func ProvideLoyaltyRepository(db *postgres.Database) repository.LoyaltyRepository {
+	return postgres.NewLoyaltyRepository(db)
+}
+
+// ProvideUserPreferencesRepository creates and returns a user preferences repository
+func ProvideUserPreferencesRepository(db *postgres.Database) repository.UserPreferencesRepository {
+	return postgres.NewUserPreferencesRepository(db)
+}
+
+// ProvideWaitlistRepository creates and returns a showtime waitlist repository
+func ProvideWaitlistRepository(db *postgres.Database) repository.WaitlistRepository {
+	return postgres.NewWaitlistRepository(db)
+}
+
+// ProvideCinemaStaffRepository creates and returns a cinema staff repository
+func ProvideCinemaStaffRepository(db *postgres.Database) repository.CinemaStaffRepository {
+	return postgres.NewCinemaStaffRepository(db)
+}
+
+// ProvidePricingRuleRepository creates and returns a cinema pricing rule repository
+func ProvidePricingRuleRepository(db *postgres.Database) repository.PricingRuleRepository {
+	return postgres.NewPricingRuleRepository(db)
+}
+
+// ProvidePricingRuleCache creates and returns a Redis-backed pricing rule cache
+func ProvidePricingRuleCache(redisClient *redis.Client) repository.PricingRuleCache {
+	return redis.NewPricingRuleCache(redisClient)
+}
+
+// ProvideBookingAnalyticsCache creates and returns a Redis-backed booking analytics cache
+func ProvideBookingAnalyticsCache(redisClient *redis.Client) repository.BookingAnalyticsCache {
+	return redis.NewBookingAnalyticsCache(redisClient)
+}
+
+// ProvideGroupBookingLock creates and returns a Redis-backed group booking lock
+func ProvideGroupBookingLock(redisClient *redis.Client) repository.GroupBookingLock {
+	return redis.NewGroupBookingLock(redisClient)
+}
+
+// ProvideSeatHoldRepository creates and returns a Redis-backed seat hold
+// repository, or a database-backed one while running without Redis
+func ProvideSeatHoldRepository(redisClient *redis.Client, db *postgres.Database) repository.SeatHoldRepository {
+	if redisClient == nil {
+		return postgres.NewSeatHoldRepository(db)
+	}
+	return redis.NewSeatHoldRepository(redisClient)
+}
+
+// ProvideSeatUpdateFeed creates and returns a Redis pub/sub seat update feed
+func ProvideSeatUpdateFeed(redisClient *redis.Client) repository.SeatUpdateFeed {
+	return redis.NewSeatUpdateFeed(redisClient)
+}
+
+// ProvideGuestLookupRepository creates and returns a Redis-backed guest lookup repository
+func ProvideGuestLookupRepository(redisClient *redis.Client) repository.GuestLookupRepository {
+	return redis.NewGuestLookupRepository(redisClient)
+}
+
+// ProvideTicketCheckInRepository creates and returns the ticket check-in audit repository
+func ProvideTicketCheckInRepository(db *postgres.Database) repository.TicketCheckInRepository {
+	return postgres.NewTicketCheckInRepository(db)
+}
+
+// ProvideChangeRecordRepository creates and returns a change history repository
+func ProvideChangeRecordRepository(db *postgres.Database) repository.ChangeRecordRepository {
+	return postgres.NewChangeRecordRepository(db)
+}
+
+// ProvideFeaturedSlotRepository creates and returns a homepage curation repository
+func ProvideFeaturedSlotRepository(db *postgres.Database) repository.FeaturedSlotRepository {
+	return postgres.NewFeaturedSlotRepository(db)
+}
+
+// ProvideWebhookEventRepository creates and returns a webhook inbox repository
+func ProvideWebhookEventRepository(db *postgres.Database) repository.WebhookEventRepository {
+	return postgres.NewWebhookEventRepository(db)
+}
+
+// ProvideJobStore creates and returns the Postgres-backed background job store
+func ProvideJobStore(db *postgres.Database) scheduler.Store {
+	return postgres.NewJobStore(db)
 }
@@ -5,6 +5,7 @@ import (
 	"cinemaos-backend/internal/handler"
 	"cinemaos-backend/internal/middleware"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
 	"cinemaos-backend/internal/router"
 	httpserver "cinemaos-backend/internal/server"
 
@@ -15,22 +16,66 @@ import (
 func ProvideRouter(
 	cfg *config.Config,
 	log *logger.Logger,
+	appMetrics *metrics.Metrics,
 	authMiddleware *middleware.AuthMiddleware,
+	cinemaAccess *middleware.CinemaAccessMiddleware,
+	rateLimiter *middleware.RateLimiter,
+	idempotency *middleware.Idempotency,
 	authHandler *handler.AuthHandler,
 	healthHandler *handler.HealthHandler,
 	movieHandler *handler.MovieHandler,
 	cinemaHandler *handler.CinemaHandler,
 	showtimeHandler *handler.ShowtimeHandler,
+	bookingHandler *handler.BookingHandler,
+	groupCheckoutHandler *handler.GroupCheckoutHandler,
+	groupBookingHandler *handler.GroupBookingHandler,
+	holdRecoveryHandler *handler.HoldRecoveryHandler,
+	paymentHandler *handler.PaymentHandler,
+	adminHandler *handler.AdminHandler,
+	changeLogHandler *handler.ChangeLogHandler,
+	curationHandler *handler.CurationHandler,
+	guestLookupHandler *handler.GuestLookupHandler,
+	dailyReportHandler *handler.DailyReportHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	demandHandler *handler.DemandHandler,
+	waitlistHandler *handler.WaitlistHandler,
+	pricingHandler *handler.PricingHandler,
+	promoHandler *handler.PromoHandler,
+	seatUpdateHandler *handler.SeatUpdateHandler,
+	loyaltyHandler *handler.LoyaltyHandler,
+	preferencesHandler *handler.PreferencesHandler,
 ) *gin.Engine {
 	appRouter := router.NewRouter(
 		cfg,
 		log,
+		appMetrics,
 		authMiddleware,
+		cinemaAccess,
+		rateLimiter,
+		idempotency,
 		authHandler,
 		healthHandler,
 		movieHandler,
 		cinemaHandler,
 		showtimeHandler,
+		bookingHandler,
+		groupCheckoutHandler,
+		groupBookingHandler,
+		holdRecoveryHandler,
+		paymentHandler,
+		adminHandler,
+		changeLogHandler,
+		curationHandler,
+		guestLookupHandler,
+		dailyReportHandler,
+		analyticsHandler,
+		demandHandler,
+		waitlistHandler,
+		pricingHandler,
+		promoHandler,
+		seatUpdateHandler,
+		loyaltyHandler,
+		preferencesHandler,
 	)
 	return appRouter.Setup()
 }
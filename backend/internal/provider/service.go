@@ -1,14 +1,48 @@
 package provider
 
 import (
+	"os"
+	"time"
+
+	adminanalyticsapp "cinemaos-backend/internal/app/adminanalytics"
 	authapp "cinemaos-backend/internal/app/auth"
 	"cinemaos-backend/internal/app/authinfra"
+	bookingapp "cinemaos-backend/internal/app/booking"
+	changelogapp "cinemaos-backend/internal/app/changelog"
 	cinemaapp "cinemaos-backend/internal/app/cinema"
+	confirmationapp "cinemaos-backend/internal/app/confirmation"
+	curationapp "cinemaos-backend/internal/app/curation"
+	dailyreportapp "cinemaos-backend/internal/app/dailyreport"
+	demandapp "cinemaos-backend/internal/app/demand"
+	groupbookingapp "cinemaos-backend/internal/app/groupbooking"
+	groupcheckoutapp "cinemaos-backend/internal/app/groupcheckout"
+	guestlookupapp "cinemaos-backend/internal/app/guestlookup"
+	holdrecoveryapp "cinemaos-backend/internal/app/holdrecovery"
+	loyaltyapp "cinemaos-backend/internal/app/loyalty"
 	movieapp "cinemaos-backend/internal/app/movie"
+	outboxapp "cinemaos-backend/internal/app/outbox"
+	paymentapp "cinemaos-backend/internal/app/payment"
+	preferencesapp "cinemaos-backend/internal/app/preferences"
+	pricingapp "cinemaos-backend/internal/app/pricing"
+	promoapp "cinemaos-backend/internal/app/promo"
 	"cinemaos-backend/internal/app/repository"
+	seatfeedapp "cinemaos-backend/internal/app/seatfeed"
 	showtimeapp "cinemaos-backend/internal/app/showtime"
+	waitlistapp "cinemaos-backend/internal/app/waitlist"
+	warmupapp "cinemaos-backend/internal/app/warmup"
 	"cinemaos-backend/internal/config"
+	"cinemaos-backend/internal/pkg/analytics"
+	"cinemaos-backend/internal/pkg/async"
+	"cinemaos-backend/internal/pkg/eventbus"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/mailer"
+	"cinemaos-backend/internal/pkg/metrics"
+	"cinemaos-backend/internal/pkg/oauth"
+	"cinemaos-backend/internal/pkg/scheduler"
+	"cinemaos-backend/internal/pkg/storage"
+	"cinemaos-backend/internal/pkg/stripe"
+	"cinemaos-backend/internal/pkg/tmdb"
+	"cinemaos-backend/internal/pkg/ws"
 )
 
 // ProvideJWTManager creates and returns a JWT manager
@@ -26,8 +60,13 @@ func ProvideAuthService(
 	userRepo repository.UserRepository,
 	refreshRepo repository.RefreshTokenRepository,
 	resetTokenRepo repository.PasswordResetTokenRepository,
+	verifyRepo repository.EmailVerificationTokenRepository,
+	bookingRepo repository.BookingRepository,
 	jwtManager *authinfra.JWTManager,
 	passwordMgr *authinfra.PasswordManager,
+	google authapp.GoogleVerifier,
+	avatars authapp.StorageClient,
+	emails mailer.EmailClient,
 	logger *logger.Logger,
 	cfg *config.Config,
 ) *authapp.Service {
@@ -35,19 +74,105 @@ func ProvideAuthService(
 		userRepo,
 		refreshRepo,
 		resetTokenRepo,
+		verifyRepo,
+		bookingRepo,
 		jwtManager,
 		passwordMgr,
+		google,
+		avatars,
+		emails,
 		logger,
-		cfg.Email.FrontendURL,
+		cfg.JWT.KeepSessionOnPasswordChange,
 	)
 }
 
+// ProvideGoogleVerifier creates the Google ID token verifier, or nil when
+// no Google client ID is configured
+func ProvideGoogleVerifier(cfg *config.Config, logger *logger.Logger) authapp.GoogleVerifier {
+	if cfg.JWT.GoogleClientID == "" {
+		return nil
+	}
+	return oauth.NewGoogleVerifier(oauth.GoogleConfig{
+		ClientID: cfg.JWT.GoogleClientID,
+		CertsURL: cfg.JWT.GoogleCertsURL,
+		Timeout:  intervalOr(cfg.JWT.GoogleTimeout, 5*time.Second),
+	}, logger)
+}
+
+// ProvideStorageClient creates the S3 client uploaded avatars are stored
+// with, or returns nil when no bucket is configured
+func ProvideStorageClient(cfg *config.Config, logger *logger.Logger) (authapp.StorageClient, error) {
+	if cfg.Storage.Bucket == "" {
+		logger.Warn("avatar uploads disabled, no storage bucket configured")
+		return nil, nil
+	}
+	return storage.NewS3Client(storage.S3Config{
+		Endpoint:        cfg.Storage.Endpoint,
+		Bucket:          cfg.Storage.Bucket,
+		Region:          cfg.Storage.Region,
+		AccessKeyID:     cfg.Storage.AccessKeyID,
+		SecretAccessKey: cfg.Storage.SecretAccessKey,
+		ForcePathStyle:  cfg.Storage.ForcePathStyle,
+		PublicBaseURL:   cfg.Storage.PublicBaseURL,
+		Timeout:         intervalOr(cfg.Storage.Timeout, 30*time.Second),
+	})
+}
+
+// ProvideChangeLogService creates and returns a change history service
+func ProvideChangeLogService(
+	changeRepo repository.ChangeRecordRepository,
+	logger *logger.Logger,
+) *changelogapp.Service {
+	return changelogapp.NewService(changeRepo, logger)
+}
+
 // ProvideMovieService creates and returns a movie service
 func ProvideMovieService(
 	movieRepo repository.MovieRepository,
+	mediaRepo repository.MovieMediaRepository,
+	reviewRepo repository.ReviewRepository,
+	bookingRepo repository.BookingRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	prefsRepo repository.UserPreferencesRepository,
+	changeLog *changelogapp.Service,
+	tmdbService *movieapp.TMDBService,
+	popularity *movieapp.PopularityCalculator,
+	bus *eventbus.Bus,
 	logger *logger.Logger,
 ) *movieapp.Service {
-	return movieapp.NewService(movieRepo, logger)
+	return movieapp.NewService(movieRepo, mediaRepo, reviewRepo, bookingRepo, showtimeRepo, prefsRepo, changeLog, tmdbService, popularity, bus, logger)
+}
+
+// ProvidePopularityCalculator creates the movie popularity calculator
+func ProvidePopularityCalculator(movieRepo repository.MovieRepository, cfg *config.Config, logger *logger.Logger) *movieapp.PopularityCalculator {
+	return movieapp.NewPopularityCalculator(movieRepo, intervalOr(cfg.Movie.PopularityWindow, 7*24*time.Hour), logger)
+}
+
+// ProvideTMDBService creates the TMDB movie metadata service, or nil when no
+// TMDB API key is configured
+func ProvideTMDBService(cfg *config.Config, logger *logger.Logger) *movieapp.TMDBService {
+	if cfg.TMDB.APIKey == "" {
+		return nil
+	}
+	client := tmdb.NewClient(tmdb.Config{
+		APIKey:       cfg.TMDB.APIKey,
+		BaseURL:      cfg.TMDB.BaseURL,
+		ImageBaseURL: cfg.TMDB.ImageBaseURL,
+		Timeout:      intervalOr(cfg.TMDB.Timeout, 5*time.Second),
+		Backoff:      intervalOr(cfg.TMDB.Backoff, 200*time.Millisecond),
+		CastLimit:    cfg.TMDB.CastLimit,
+	}, logger)
+	return movieapp.NewTMDBService(client, logger)
+}
+
+// ProvideCurationService creates and returns a homepage curation service
+func ProvideCurationService(
+	slotRepo repository.FeaturedSlotRepository,
+	movieRepo repository.MovieRepository,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *curationapp.Service {
+	return curationapp.NewService(slotRepo, movieRepo, cfg.Home, logger)
 }
 
 // ProvideCinemaService creates and returns a cinema service
@@ -55,9 +180,10 @@ func ProvideCinemaService(
 	cinemaRepo repository.CinemaRepository,
 	screenRepo repository.ScreenRepository,
 	seatRepo repository.SeatRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
 	logger *logger.Logger,
 ) *cinemaapp.Service {
-	return cinemaapp.NewService(cinemaRepo, screenRepo, seatRepo, logger)
+	return cinemaapp.NewService(cinemaRepo, screenRepo, seatRepo, deviceRepo, logger)
 }
 
 // ProvideShowtimeService creates and returns a showtime service
@@ -66,7 +192,438 @@ func ProvideShowtimeService(
 	movieRepo repository.MovieRepository,
 	cinemaRepo repository.CinemaRepository,
 	screenRepo repository.ScreenRepository,
+	seatRepo repository.SeatRepository,
+	holdRepo repository.SeatHoldRepository,
+	ruleRepo repository.SeatTypeRuleRepository,
+	changeLog *changelogapp.Service,
+	bus *eventbus.Bus,
 	logger *logger.Logger,
+	cfg *config.Config,
 ) *showtimeapp.Service {
-	return showtimeapp.NewService(showtimeRepo, movieRepo, cinemaRepo, screenRepo, logger)
+	return showtimeapp.NewService(showtimeRepo, movieRepo, cinemaRepo, screenRepo, seatRepo, holdRepo, ruleRepo, changeLog, cfg.Availability, bus, logger)
+}
+
+// ProvideBookingService creates and returns a booking service
+func ProvideBookingService(
+	holdRepo repository.SeatHoldRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	seatRepo repository.SeatRepository,
+	bookingRepo repository.BookingRepository,
+	bookingSeatRepo repository.BookingSeatRepository,
+	groupRepo repository.GroupCheckoutRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
+	ruleRepo repository.SeatTypeRuleRepository,
+	promoRepo repository.PromoCodeRepository,
+	staffRepo repository.CinemaStaffRepository,
+	checkInRepo repository.TicketCheckInRepository,
+	seatUpdates repository.SeatUpdateFeed,
+	pricingEngine *pricingapp.RuleBasedEngine,
+	payments bookingapp.PaymentStarter,
+	tracker *analytics.Tracker,
+	appMetrics *metrics.Metrics,
+	tx repository.Transactor,
+	events *outboxapp.Outbox,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *bookingapp.Service {
+	return bookingapp.NewService(holdRepo, showtimeRepo, seatRepo, bookingRepo, bookingSeatRepo, groupRepo, deviceRepo, ruleRepo, promoRepo, staffRepo, checkInRepo, seatUpdates, pricingEngine, payments, tracker, appMetrics, tx, events, cfg.Booking, cfg.Ticket, logger)
+}
+
+// ProvidePricingEngine creates and returns the rule-based seat pricing engine
+func ProvidePricingEngine(
+	ruleRepo repository.PricingRuleRepository,
+	cache repository.PricingRuleCache,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *pricingapp.RuleBasedEngine {
+	return pricingapp.NewRuleBasedEngine(ruleRepo, cache, cfg.Pricing.RuleCacheTTL,
+		pricingapp.SeatTypeMultipliers(cfg.Pricing.SeatTypeMultipliers), logger)
+}
+
+// ProvidePricingService creates and returns the cinema pricing rule service
+func ProvidePricingService(
+	ruleRepo repository.PricingRuleRepository,
+	cinemaRepo repository.CinemaRepository,
+	engine *pricingapp.RuleBasedEngine,
+	logger *logger.Logger,
+) *pricingapp.Service {
+	return pricingapp.NewService(ruleRepo, cinemaRepo, engine, logger)
+}
+
+// ProvidePromoService creates and returns the admin promo code service
+func ProvidePromoService(
+	promoRepo repository.PromoCodeRepository,
+	logger *logger.Logger,
+) *promoapp.Service {
+	return promoapp.NewService(promoRepo, logger)
+}
+
+// ProvideConfirmationService creates and returns the booking confirmation service
+func ProvideConfirmationService(
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	seatRepo repository.SeatRepository,
+	dispatcher *async.Dispatcher,
+	emails mailer.EmailClient,
+	tracker *analytics.Tracker,
+	bus *eventbus.Bus,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *confirmationapp.Service {
+	svc := confirmationapp.NewService(bookingRepo, userRepo, seatRepo, dispatcher, emails, tracker, logger, cfg.Ticket.SigningSecret)
+	svc.RegisterSubscribers(bus)
+	return svc
+}
+
+// ProvideWaitlistService creates and returns the showtime waitlist service
+func ProvideWaitlistService(
+	waitlistRepo repository.WaitlistRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	userRepo repository.UserRepository,
+	bookingService *bookingapp.Service,
+	dispatcher *async.Dispatcher,
+	bus *eventbus.Bus,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *waitlistapp.Service {
+	svc := waitlistapp.NewService(waitlistRepo, showtimeRepo, userRepo, bookingService, dispatcher, logger, cfg.Email.FrontendURL)
+	svc.RegisterSubscribers(bus)
+	return svc
+}
+
+// ProvideOutbox creates and returns the event outbox services write domain
+// events to
+func ProvideOutbox(repo repository.OutboxRepository) *outboxapp.Outbox {
+	return outboxapp.New(repo)
+}
+
+// ProvideOutboxRelay creates and returns the relay publishing outbox events
+// to the event bus
+func ProvideOutboxRelay(repo repository.OutboxRepository, bus *eventbus.Bus, logger *logger.Logger, cfg *config.Config) *outboxapp.Relay {
+	return outboxapp.NewRelay(repo, bus, outboxapp.RelayConfig{
+		BatchSize:   cfg.Events.OutboxBatchSize,
+		MaxAttempts: cfg.Events.OutboxMaxAttempts,
+		Retention:   cfg.Events.OutboxRetention,
+	}, logger)
+}
+
+// ProvideGroupCheckoutService creates and returns a group checkout service
+func ProvideGroupCheckoutService(
+	groupRepo repository.GroupCheckoutRepository,
+	holdRepo repository.SeatHoldRepository,
+	bookingRepo repository.BookingRepository,
+	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
+	deviceRepo repository.AssistiveDeviceRepository,
+	tx repository.Transactor,
+	events *outboxapp.Outbox,
+	dispatcher *async.Dispatcher,
+	bus *eventbus.Bus,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *groupcheckoutapp.Service {
+	svc := groupcheckoutapp.NewService(
+		groupRepo,
+		holdRepo,
+		bookingRepo,
+		paymentRepo,
+		userRepo,
+		deviceRepo,
+		tx,
+		events,
+		dispatcher,
+		cfg.Booking,
+		logger,
+		cfg.Email.FrontendURL,
+	)
+	svc.RegisterSubscribers(bus)
+	return svc
+}
+
+// ProvideGroupBookingService creates and returns a group booking service
+func ProvideGroupBookingService(
+	groupRepo repository.GroupBookingRepository,
+	locks repository.GroupBookingLock,
+	showtimeRepo repository.ShowtimeRepository,
+	holdRepo repository.SeatHoldRepository,
+	userRepo repository.UserRepository,
+	bookingService *bookingapp.Service,
+	dispatcher *async.Dispatcher,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *groupbookingapp.Service {
+	return groupbookingapp.NewService(
+		groupRepo,
+		locks,
+		showtimeRepo,
+		holdRepo,
+		userRepo,
+		bookingService,
+		dispatcher,
+		cfg.Booking,
+		logger,
+		cfg.Email.FrontendURL,
+	)
+}
+
+// ProvideGuestLookupService creates and returns a guest booking lookup service
+func ProvideGuestLookupService(
+	bookingRepo repository.BookingRepository,
+	lookupRepo repository.GuestLookupRepository,
+	dispatcher *async.Dispatcher,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *guestlookupapp.Service {
+	return guestlookupapp.NewService(bookingRepo, lookupRepo, dispatcher, cfg.GuestLookup, logger)
+}
+
+// ProvideHoldRecoveryService creates and returns the hold recovery email service
+func ProvideHoldRecoveryService(
+	holdRepo repository.SeatHoldRepository,
+	recoveryRepo repository.HoldRecoveryRepository,
+	showtimeRepo repository.ShowtimeRepository,
+	bookingRepo repository.BookingRepository,
+	groupRepo repository.GroupCheckoutRepository,
+	userRepo repository.UserRepository,
+	bookingService *bookingapp.Service,
+	dispatcher *async.Dispatcher,
+	bus *eventbus.Bus,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *holdrecoveryapp.Service {
+	svc := holdrecoveryapp.NewService(
+		holdRepo,
+		recoveryRepo,
+		showtimeRepo,
+		bookingRepo,
+		groupRepo,
+		userRepo,
+		bookingService,
+		dispatcher,
+		bus,
+		cfg.Booking,
+		logger,
+		cfg.Email.FrontendURL,
+	)
+	svc.RegisterSubscribers(bus)
+	return svc
+}
+
+// ProvidePaymentService creates and returns the payment webhook service
+func ProvidePaymentService(
+	webhookRepo repository.WebhookEventRepository,
+	paymentRepo repository.PaymentRepository,
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	groupCheckoutService *groupcheckoutapp.Service,
+	bookingService *bookingapp.Service,
+	gateway paymentapp.Gateway,
+	changeLog *changelogapp.Service,
+	dispatcher *async.Dispatcher,
+	tx repository.Transactor,
+	events *outboxapp.Outbox,
+	tracker *analytics.Tracker,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *paymentapp.Service {
+	return paymentapp.NewService(
+		webhookRepo,
+		paymentRepo,
+		bookingRepo,
+		userRepo,
+		groupCheckoutService,
+		bookingService,
+		gateway,
+		changeLog,
+		dispatcher,
+		tx,
+		events,
+		tracker,
+		cfg.Payment,
+		logger,
+	)
+}
+
+// ProvidePaymentGateway creates the payment gateway status client, or nil
+// when no gateway URL is configured and reconciliation is off
+func ProvidePaymentGateway(cfg *config.Config) paymentapp.Gateway {
+	if cfg.Payment.GatewayURL == "" {
+		return nil
+	}
+	return paymentapp.NewHTTPGateway(cfg.Payment.GatewayURL, cfg.Payment.GatewayAPIKey, intervalOr(cfg.Payment.GatewayTimeout, 10*time.Second))
+}
+
+// ProvidePaymentCheckout creates the Stripe checkout for booking payments,
+// or nil when no Stripe key is configured
+func ProvidePaymentCheckout(paymentRepo repository.PaymentRepository, logger *logger.Logger, cfg *config.Config) bookingapp.PaymentStarter {
+	if cfg.Payment.StripeSecretKey == "" {
+		return nil
+	}
+	client := stripe.NewClient(stripe.Config{
+		SecretKey: cfg.Payment.StripeSecretKey,
+		BaseURL:   cfg.Payment.StripeURL,
+		Timeout:   intervalOr(cfg.Payment.GatewayTimeout, 10*time.Second),
+	}, logger)
+	return paymentapp.NewCheckout(paymentRepo, client, cfg.Payment, logger)
+}
+
+// ProvideDailyReportService creates and returns the end-of-day report service
+func ProvideDailyReportService(
+	reportRepo repository.DailyReportRepository,
+	bookingRepo repository.BookingRepository,
+	cinemaRepo repository.CinemaRepository,
+	staffRepo repository.CinemaStaffRepository,
+	dispatcher *async.Dispatcher,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *dailyreportapp.Service {
+	return dailyreportapp.NewService(reportRepo, bookingRepo, cinemaRepo, staffRepo, dispatcher, cfg.Reports, logger)
+}
+
+// ProvideDemandService creates and returns the showtime demand rollup service
+func ProvideDemandService(
+	demandRepo repository.DemandRepository,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *demandapp.Service {
+	return demandapp.NewService(demandRepo, cfg.Reports, logger)
+}
+
+// ProvidePreferencesService creates and returns the user preferences service
+func ProvidePreferencesService(prefsRepo repository.UserPreferencesRepository, logger *logger.Logger) *preferencesapp.Service {
+	return preferencesapp.NewService(prefsRepo, logger)
+}
+
+// ProvideLoyaltyService creates and returns the loyalty points service
+func ProvideLoyaltyService(
+	loyaltyRepo repository.LoyaltyRepository,
+	bus *eventbus.Bus,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *loyaltyapp.Service {
+	svc := loyaltyapp.NewService(loyaltyRepo, cfg.Loyalty, logger)
+	svc.RegisterSubscribers(bus)
+	return svc
+}
+
+// ProvideAdminAnalyticsService creates and returns the booking analytics service
+func ProvideAdminAnalyticsService(
+	bookingRepo repository.BookingRepository,
+	staffRepo repository.CinemaStaffRepository,
+	cache repository.BookingAnalyticsCache,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *adminanalyticsapp.Service {
+	return adminanalyticsapp.NewService(bookingRepo, staffRepo, cache, cfg.Reports, logger)
+}
+
+// ProvideSeatFeedService creates and returns the service relaying seat
+// updates to the WebSocket clients of this instance
+func ProvideSeatFeedService(feed repository.SeatUpdateFeed, logger *logger.Logger) *seatfeedapp.Service {
+	// Updates a watcher may fall behind by before it is dropped
+	return seatfeedapp.NewService(feed, ws.NewHub(64), logger)
+}
+
+// ProvideWarmupService creates and returns the startup cache warmup service
+func ProvideWarmupService(
+	showtimeRepo repository.ShowtimeRepository,
+	bookingService *bookingapp.Service,
+	confirmationService *confirmationapp.Service,
+	curationService *curationapp.Service,
+	pricingEngine *pricingapp.RuleBasedEngine,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *warmupapp.Service {
+	return warmupapp.NewService(showtimeRepo, bookingService, confirmationService, curationService, pricingEngine, cfg.Warmup, logger)
+}
+
+// ProvideJobRunner creates the background job runner and registers the
+// periodic jobs of every service
+func ProvideJobRunner(
+	store scheduler.Store,
+	groupCheckoutService *groupcheckoutapp.Service,
+	holdRecoveryService *holdrecoveryapp.Service,
+	paymentService *paymentapp.Service,
+	bookingService *bookingapp.Service,
+	dailyReportService *dailyreportapp.Service,
+	demandService *demandapp.Service,
+	loyaltyService *loyaltyapp.Service,
+	popularity *movieapp.PopularityCalculator,
+	relay *outboxapp.Relay,
+	logger *logger.Logger,
+	cfg *config.Config,
+) *scheduler.Runner {
+	instance := cfg.Jobs.Instance
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+
+	runner := scheduler.New(scheduler.Config{
+		Instance: instance,
+		Jitter:   cfg.Jobs.Jitter,
+	}, store, logger)
+
+	runner.Register(scheduler.Job{
+		Name:     "events.relay_outbox",
+		Interval: intervalOr(cfg.Events.OutboxInterval, 2*time.Second),
+		Run:      relay.PublishPending,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "group_checkout.expire_lapsed",
+		Interval: intervalOr(cfg.Booking.SplitSweepInterval, 30*time.Second),
+		Run:      groupCheckoutService.ExpireLapsed,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "hold.recovery_sweep",
+		Interval: intervalOr(cfg.Booking.RecoverySweepInterval, time.Minute),
+		Run:      holdRecoveryService.SweepExpired,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "hold.expire_held_counts",
+		Interval: intervalOr(cfg.Booking.HeldCountSweepInterval, 10*time.Second),
+		Run:      bookingService.ExpireHeldCounts,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "booking.expire_pending",
+		Interval: intervalOr(cfg.Booking.PendingSweepInterval, time.Minute),
+		Run:      bookingService.ExpirePendingBookings,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "payment.stale_webhook_alerts",
+		Interval: intervalOr(cfg.Payment.WebhookSweepInterval, time.Minute),
+		Run:      paymentService.AlertStaleWebhooks,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "payment.reconcile_pending",
+		Interval: intervalOr(cfg.Payment.ReconcileInterval, time.Minute),
+		Run:      paymentService.ReconcilePending,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "reports.daily",
+		Interval: intervalOr(cfg.Reports.DailyInterval, 15*time.Minute),
+		Run:      dailyReportService.GenerateDue,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "reports.showtime_demand",
+		Interval: intervalOr(cfg.Reports.DemandInterval, 24*time.Hour),
+		Run:      demandService.Rollup,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "loyalty.expire_inactive",
+		Interval: intervalOr(cfg.Loyalty.ExpirySweepInterval, 24*time.Hour),
+		Run:      loyaltyService.ExpireInactive,
+	})
+	runner.Register(scheduler.Job{
+		Name:     "movie.recalculate_popularity",
+		Interval: intervalOr(cfg.Movie.PopularityInterval, time.Hour),
+		Run:      popularity.RecalculateAll,
+	})
+	return runner
+}
+
+func intervalOr(interval, fallback time.Duration) time.Duration {
+	if interval <= 0 {
+		return fallback
+	}
+	return interval
 }
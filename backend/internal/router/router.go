@@ -1,48 +1,117 @@
 package router
 
 import (
-	"time"
-
+	"cinemaos-backend/internal/app/entity"
 	"cinemaos-backend/internal/config"
 	"cinemaos-backend/internal/handler"
 	"cinemaos-backend/internal/middleware"
+	"cinemaos-backend/internal/pkg/apiversion"
 	"cinemaos-backend/internal/pkg/logger"
+	"cinemaos-backend/internal/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // Router holds all route dependencies
 type Router struct {
 	cfg            *config.Config
 	logger         *logger.Logger
+	metrics        *metrics.Metrics
 	authMiddleware *middleware.AuthMiddleware
+	cinemaAccess   *middleware.CinemaAccessMiddleware
 	authHandler    *handler.AuthHandler
 	healthHandler  *handler.HealthHandler
 	movieHandler   *handler.MovieHandler
 	cinemaHandler  *handler.CinemaHandler
 	showtimeHandler *handler.ShowtimeHandler
+	bookingHandler  *handler.BookingHandler
+	groupCheckoutHandler *handler.GroupCheckoutHandler
+	groupBookingHandler  *handler.GroupBookingHandler
+	holdRecoveryHandler  *handler.HoldRecoveryHandler
+	paymentHandler  *handler.PaymentHandler
+	adminHandler    *handler.AdminHandler
+	changeLogHandler *handler.ChangeLogHandler
+	curationHandler  *handler.CurationHandler
+	guestLookupHandler *handler.GuestLookupHandler
+	dailyReportHandler *handler.DailyReportHandler
+	analyticsHandler   *handler.AnalyticsHandler
+	demandHandler      *handler.DemandHandler
+	waitlistHandler    *handler.WaitlistHandler
+	pricingHandler     *handler.PricingHandler
+	promoHandler       *handler.PromoHandler
+	seatUpdateHandler  *handler.SeatUpdateHandler
+	loyaltyHandler     *handler.LoyaltyHandler
+	preferencesHandler *handler.PreferencesHandler
+	rateLimiter        *middleware.RateLimiter
+	idempotency        *middleware.Idempotency
 }
 
 // NewRouter creates a new router
 func NewRouter(
 	cfg *config.Config,
 	logger *logger.Logger,
+	metrics *metrics.Metrics,
 	authMiddleware *middleware.AuthMiddleware,
+	cinemaAccess *middleware.CinemaAccessMiddleware,
+	rateLimiter *middleware.RateLimiter,
+	idempotency *middleware.Idempotency,
 	authHandler *handler.AuthHandler,
 	healthHandler *handler.HealthHandler,
 	movieHandler *handler.MovieHandler,
 	cinemaHandler *handler.CinemaHandler,
 	showtimeHandler *handler.ShowtimeHandler,
+	bookingHandler *handler.BookingHandler,
+	groupCheckoutHandler *handler.GroupCheckoutHandler,
+	groupBookingHandler *handler.GroupBookingHandler,
+	holdRecoveryHandler *handler.HoldRecoveryHandler,
+	paymentHandler *handler.PaymentHandler,
+	adminHandler *handler.AdminHandler,
+	changeLogHandler *handler.ChangeLogHandler,
+	curationHandler *handler.CurationHandler,
+	guestLookupHandler *handler.GuestLookupHandler,
+	dailyReportHandler *handler.DailyReportHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	demandHandler *handler.DemandHandler,
+	waitlistHandler *handler.WaitlistHandler,
+	pricingHandler *handler.PricingHandler,
+	promoHandler *handler.PromoHandler,
+	seatUpdateHandler *handler.SeatUpdateHandler,
+	loyaltyHandler *handler.LoyaltyHandler,
+	preferencesHandler *handler.PreferencesHandler,
 ) *Router {
 	return &Router{
 		cfg:            cfg,
 		logger:         logger,
+		metrics:        metrics,
 		authMiddleware: authMiddleware,
+		cinemaAccess:   cinemaAccess,
 		authHandler:    authHandler,
 		healthHandler:  healthHandler,
 		movieHandler:   movieHandler,
 		cinemaHandler:  cinemaHandler,
 		showtimeHandler: showtimeHandler,
+		bookingHandler:  bookingHandler,
+		groupCheckoutHandler: groupCheckoutHandler,
+		groupBookingHandler:  groupBookingHandler,
+		holdRecoveryHandler:  holdRecoveryHandler,
+		paymentHandler:  paymentHandler,
+		adminHandler:    adminHandler,
+		changeLogHandler: changeLogHandler,
+		curationHandler:  curationHandler,
+		guestLookupHandler: guestLookupHandler,
+		dailyReportHandler: dailyReportHandler,
+		analyticsHandler:   analyticsHandler,
+		demandHandler:      demandHandler,
+		waitlistHandler:    waitlistHandler,
+		pricingHandler:     pricingHandler,
+		promoHandler:       promoHandler,
+		seatUpdateHandler:  seatUpdateHandler,
+		loyaltyHandler:     loyaltyHandler,
+		preferencesHandler: preferencesHandler,
+		rateLimiter:        rateLimiter,
+		idempotency:        idempotency,
 	}
 }
 
@@ -59,14 +128,20 @@ func (r *Router) Setup() *gin.Engine {
 	// Global middleware
 	router.Use(middleware.RecoveryMiddleware(r.logger))
 	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.AnonymousIDMiddleware())
 	router.Use(middleware.LoggingMiddleware(r.logger))
 	router.Use(middleware.ResponseTimeMiddleware(r.logger))
+	if r.metrics.IsEnabled() {
+		router.Use(middleware.MetricsMiddleware(r.metrics))
+	}
 	router.Use(middleware.CORSMiddleware(r.cfg.CORS))
 	router.Use(middleware.SecureHeadersMiddleware())
 
-	// Rate limiting (100 requests per minute per IP)
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
-	router.Use(rateLimiter.RateLimit())
+	// Rate limiting per client IP, with per-route budgets
+	router.Use(r.rateLimiter.RateLimit())
+
+	// Deadline on each request's context, with per-route overrides
+	router.Use(middleware.TimeoutMiddleware(r.cfg.Server, r.logger))
 
 	// Health check routes (no auth required)
 	router.GET("/health", r.healthHandler.Health)
@@ -74,77 +149,26 @@ func (r *Router) Setup() *gin.Engine {
 	router.GET("/health/live", r.healthHandler.Live)
 	router.GET("/info", r.healthHandler.Info)
 
-	// API v1 routes
-	v1 := router.Group("/api/v1")
-	{
-		// Auth routes
-		auth := v1.Group("/auth")
-		{
-			// Public routes
-			auth.POST("/register", r.authHandler.Register)
-			auth.POST("/login", r.authHandler.Login)
-			auth.POST("/refresh", r.authHandler.RefreshToken)
-			auth.POST("/forgot-password", r.authHandler.ForgotPassword)
-			auth.POST("/reset-password", r.authHandler.ResetPassword)
-
-			// Protected routes
-			auth.POST("/logout", r.authMiddleware.Authenticate(), r.authHandler.Logout)
-			auth.POST("/change-password", r.authMiddleware.Authenticate(), r.authHandler.ChangePassword)
-			auth.GET("/me", r.authMiddleware.Authenticate(), r.authHandler.GetCurrentUser)
-			auth.PATCH("/me", r.authMiddleware.Authenticate(), r.authHandler.UpdateProfile)
-		}
-
-		// Movies routes
-		movies := v1.Group("/movies")
-		{
-			movies.GET("", r.movieHandler.List)
-			movies.GET("/:id", r.movieHandler.GetByID)
-			movies.GET("/now-showing", r.movieHandler.GetNowShowing)
-			movies.GET("/coming-soon", r.movieHandler.GetComingSoon)
-			movies.GET("/:id/showtimes", r.movieHandler.GetShowtimes)
-			
-			// Admin only
-			movies.POST("", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Create)
-			movies.PUT("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Update)
-			movies.DELETE("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Delete)
-		}
-
-		// Cinemas routes
-		cinemas := v1.Group("/cinemas")
-		{
-			cinemas.GET("", r.cinemaHandler.List)
-			cinemas.GET("/:id", r.cinemaHandler.GetByID)
-			// cinemas.GET("/:id/showtimes", r.cinemaHandler.GetShowtimes) // To be implemented with Showtime module
-
-			// Admin only
-			cinemas.POST("", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.cinemaHandler.Create)
-			cinemas.POST("/:id/screens", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.cinemaHandler.AddScreen)
-		}
-
-		// Showtime routes
-		showtimes := v1.Group("/showtimes")
-		{
-			showtimes.GET("", r.showtimeHandler.List)
-			showtimes.GET("/:id", r.showtimeHandler.GetByID)
-			
-			// Admin only
-			showtimes.POST("", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.showtimeHandler.Create)
-			showtimes.PUT("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.showtimeHandler.Update)
-			showtimes.DELETE("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.showtimeHandler.Delete)
-		}
-
-		// Bookings routes (to be implemented)
-		// bookings := v1.Group("/bookings")
-		// {
-		// 	bookings.Use(authMiddleware.Authenticate())
-		// 	bookings.POST("/hold", bookingHandler.HoldSeats)
-		// 	bookings.POST("/confirm", bookingHandler.ConfirmBooking)
-		// 	bookings.GET("", bookingHandler.GetUserBookings)
-		// 	bookings.GET("/:id", bookingHandler.GetByID)
-		// 	bookings.POST("/:id/cancel", bookingHandler.Cancel)
-		// }
+	// Prometheus scrape endpoint (no auth required)
+	if r.metrics.IsEnabled() {
+		router.GET(r.cfg.Metrics.Path, gin.WrapH(r.metrics.Handler()))
+	}
+
+	// Versioned API routes. v2 shares the v1 handlers; version-specific
+	// response shapes are selected from the APIVersion in context.
+	deprecations := middleware.DeprecationMiddleware(r.cfg.API, r.logger)
+
+	v1 := router.Group("/api/v1", middleware.APIVersionMiddleware(apiversion.V1), deprecations)
+	r.registerAPIRoutes(v1)
+
+	// Generated API spec (make docs) and the Swagger UI
+	if r.cfg.API.Docs {
+		v1.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
+	v2 := router.Group("/api/v2", middleware.APIVersionMiddleware(apiversion.V2), deprecations)
+	r.registerAPIRoutes(v2)
+
 	// Not found handler
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(404, gin.H{
@@ -158,3 +182,237 @@ func (r *Router) Setup() *gin.Engine {
 
 	return router
 }
+
+// registerAPIRoutes registers the routes shared by every API version
+func (r *Router) registerAPIRoutes(api *gin.RouterGroup) {
+	// Auth routes
+	auth := api.Group("/auth")
+	{
+		// Public routes
+		auth.POST("/register", r.authHandler.Register)
+		auth.POST("/login", r.authHandler.Login)
+		auth.POST("/google", r.authHandler.LoginWithGoogle)
+		auth.POST("/refresh", r.authHandler.RefreshToken)
+		auth.POST("/forgot-password", r.authHandler.ForgotPassword)
+		auth.POST("/reset-password", r.authHandler.ResetPassword)
+		auth.POST("/verify-email", r.authHandler.VerifyEmail)
+
+		// Protected routes
+		auth.POST("/logout", r.authMiddleware.Authenticate(), r.authHandler.Logout)
+		auth.POST("/logout-others", r.authMiddleware.Authenticate(), r.authHandler.LogoutOthers)
+		auth.GET("/sessions", r.authMiddleware.Authenticate(), r.authHandler.ListSessions)
+		auth.POST("/change-password", r.authMiddleware.Authenticate(), r.authHandler.ChangePassword)
+		auth.POST("/verify-email/send", r.authMiddleware.Authenticate(), r.authHandler.SendVerificationEmail)
+		auth.GET("/me", r.authMiddleware.Authenticate(), r.authHandler.GetCurrentUser)
+		auth.PATCH("/me", r.authMiddleware.Authenticate(), r.authHandler.UpdateProfile)
+		auth.PATCH("/me/avatar", r.authMiddleware.Authenticate(), r.authHandler.UpdateAvatar)
+		auth.GET("/me/preferences", r.authMiddleware.Authenticate(), r.preferencesHandler.Get)
+		auth.PUT("/me/preferences", r.authMiddleware.Authenticate(), r.preferencesHandler.Update)
+	}
+
+	// Homepage (curated slots and movie rows)
+	api.GET("/home", r.curationHandler.GetHome)
+
+	// Movies routes
+	movies := api.Group("/movies")
+	{
+		movies.GET("", r.authMiddleware.OptionalAuth(), r.movieHandler.List)
+		movies.GET("/:id", r.authMiddleware.OptionalAuth(), r.movieHandler.GetByID)
+		movies.GET("/now-showing", r.authMiddleware.OptionalAuth(), r.movieHandler.GetNowShowing)
+		movies.GET("/coming-soon", r.authMiddleware.OptionalAuth(), r.movieHandler.GetComingSoon)
+		movies.GET("/:id/showtimes", r.movieHandler.GetShowtimes)
+		movies.GET("/:id/reviews", r.movieHandler.ListReviews)
+		movies.POST("/:id/reviews", r.authMiddleware.Authenticate(), r.movieHandler.CreateReview)
+		movies.DELETE("/:id/reviews/:reviewId", r.authMiddleware.Authenticate(), r.movieHandler.DeleteReview)
+		
+		// Admin only
+		movies.POST("", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Create)
+		movies.PUT("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Update)
+		movies.DELETE("/:id", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.Delete)
+		movies.GET("/:id/media", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.ListMedia)
+		movies.POST("/:id/media", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.CreateMedia)
+		movies.PUT("/:id/media/order", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.ReorderMedia)
+		movies.PUT("/:id/media/:mediaId", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.UpdateMedia)
+		movies.DELETE("/:id/media/:mediaId", r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin(), r.movieHandler.DeleteMedia)
+	}
+
+	// Cinemas routes
+	cinemas := api.Group("/cinemas")
+	{
+		cinemas.GET("", r.authMiddleware.OptionalAuth(), r.cinemaHandler.List)
+		cinemas.GET("/nearby", r.authMiddleware.OptionalAuth(), r.cinemaHandler.Nearby)
+		cinemas.GET("/search", r.authMiddleware.OptionalAuth(), r.cinemaHandler.Search)
+		cinemas.GET("/:id", r.authMiddleware.OptionalAuth(), r.cinemaHandler.GetByID)
+		// cinemas.GET("/:id/showtimes", r.cinemaHandler.GetShowtimes) // To be implemented with Showtime module
+
+		// Cinemas are opened by admins and managed by admins and their own managers
+		manageCinema := r.cinemaAccess.RequireCinemaRole("id", entity.RoleManager)
+		cinemas.POST("", r.authMiddleware.Authenticate(), r.authMiddleware.RequireRole("ADMIN"), r.cinemaHandler.Create)
+		cinemas.POST("/:id/screens", r.authMiddleware.Authenticate(), manageCinema, r.cinemaHandler.AddScreen)
+		cinemas.PUT("/:id", r.authMiddleware.Authenticate(), manageCinema, r.cinemaHandler.Update)
+		cinemas.PUT("/:id/seats/:seatId/companion", r.authMiddleware.Authenticate(), manageCinema, r.cinemaHandler.LinkCompanionSeat)
+		cinemas.GET("/:id/screens/:screenId/devices", r.authMiddleware.Authenticate(), manageCinema, r.cinemaHandler.GetScreenDevices)
+		cinemas.PUT("/:id/screens/:screenId/devices", r.authMiddleware.Authenticate(), manageCinema, r.cinemaHandler.UpdateScreenDevices)
+		cinemas.GET("/:id/pricing-rules", r.authMiddleware.Authenticate(), manageCinema, r.pricingHandler.ListRules)
+		cinemas.POST("/:id/pricing-rules", r.authMiddleware.Authenticate(), manageCinema, r.pricingHandler.CreateRule)
+		cinemas.GET("/:id/pricing-rules/:ruleId", r.authMiddleware.Authenticate(), manageCinema, r.pricingHandler.GetRule)
+		cinemas.PUT("/:id/pricing-rules/:ruleId", r.authMiddleware.Authenticate(), manageCinema, r.pricingHandler.UpdateRule)
+		cinemas.DELETE("/:id/pricing-rules/:ruleId", r.authMiddleware.Authenticate(), manageCinema, r.pricingHandler.DeleteRule)
+	}
+
+	// Showtime routes
+	showtimes := api.Group("/showtimes")
+	{
+		showtimes.GET("", r.showtimeHandler.List)
+		showtimes.GET("/availability", r.showtimeHandler.GetAvailability)
+		showtimes.GET("/:id", r.showtimeHandler.GetByID)
+		showtimes.GET("/:id/seats", r.bookingHandler.GetSeatMap)
+		showtimes.GET("/:id/seat-updates", r.seatUpdateHandler.Watch)
+		showtimes.POST("/:id/check-seats", r.bookingHandler.CheckSeats)
+		showtimes.POST("/:id/waitlist", r.authMiddleware.OptionalAuth(), r.waitlistHandler.Join)
+		showtimes.GET("/:id/waitlist/position", r.authMiddleware.Authenticate(), r.waitlistHandler.Position)
+		
+		// Showtimes are managed by admins and the managers of their cinema;
+		// Create and GenerateSchedule check the cinema in the request body
+		manageShowtime := r.cinemaAccess.RequireShowtimeCinemaRole("id", entity.RoleManager)
+		showtimes.POST("", r.authMiddleware.Authenticate(), r.showtimeHandler.Create)
+		showtimes.POST("/schedule", r.authMiddleware.Authenticate(), r.showtimeHandler.GenerateSchedule)
+		showtimes.PUT("/:id", r.authMiddleware.Authenticate(), manageShowtime, r.showtimeHandler.Update)
+		showtimes.PUT("/:id/capacity", r.authMiddleware.Authenticate(), manageShowtime, r.showtimeHandler.UpdateCapacity)
+		showtimes.GET("/:id/seat-type-rules", r.authMiddleware.Authenticate(), manageShowtime, r.showtimeHandler.GetSeatTypeRules)
+		showtimes.PUT("/:id/seat-type-rules", r.authMiddleware.Authenticate(), manageShowtime, r.showtimeHandler.UpdateSeatTypeRules)
+		showtimes.DELETE("/:id", r.authMiddleware.Authenticate(), manageShowtime, r.showtimeHandler.Delete)
+	}
+
+	// Bookings routes
+	bookings := api.Group("/bookings")
+	{
+		bookings.Use(r.authMiddleware.Authenticate())
+		bookings.POST("/hold", r.bookingHandler.HoldSeats)
+		bookings.POST("/release", r.bookingHandler.ReleaseHold)
+		bookings.POST("/claim", r.guestLookupHandler.Claim)
+		bookings.POST("/claim/verify", r.guestLookupHandler.VerifyClaim)
+		bookings.GET("/:id/seatmap.svg", r.bookingHandler.GetSeatPlanSVG)
+		bookings.GET("/:id/seatmap.png", r.bookingHandler.GetSeatPlanPNG)
+		bookings.GET("/:id/ticket", r.bookingHandler.GetTicket)
+		bookings.POST("/confirm", r.idempotency.Idempotent(), r.bookingHandler.ConfirmBooking)
+		bookings.POST("/:id/payment", r.bookingHandler.RetryPayment)
+		bookings.POST("/promo-code", r.bookingHandler.ValidatePromoCode)
+		bookings.GET("", r.bookingHandler.ListUserBookings)
+	}
+
+	// Booking details and cancellation, for the owner, admins, or a guest
+	// with the booking reference and email
+	api.GET("/bookings/:id", r.authMiddleware.OptionalAuth(), r.bookingHandler.GetBooking)
+	api.POST("/bookings/:id/cancel", r.authMiddleware.OptionalAuth(), r.bookingHandler.CancelBooking)
+
+	// Loyalty points routes
+	loyalty := api.Group("/loyalty")
+	{
+		loyalty.Use(r.authMiddleware.Authenticate())
+		loyalty.GET("/balance", r.loyaltyHandler.GetBalance)
+		loyalty.GET("/transactions", r.loyaltyHandler.ListTransactions)
+	}
+
+	// Guest booking lookup (reference + email, throttled)
+	guestBookings := api.Group("/guest-bookings")
+	{
+		guestBookings.POST("/lookup", r.guestLookupHandler.Lookup)
+		guestBookings.POST("/lookup/verify", r.guestLookupHandler.Verify)
+	}
+
+	// Seat hold routes
+	holds := api.Group("/holds")
+	{
+		holds.Use(r.authMiddleware.Authenticate())
+		holds.GET("/:id", r.bookingHandler.GetHold)
+		holds.POST("/:id/extend", r.bookingHandler.ExtendHold)
+		holds.POST("/:id/split", r.groupCheckoutHandler.Split)
+		holds.POST("/recover", r.holdRecoveryHandler.Rehold)
+	}
+
+	// Group checkout (split payment) routes
+	groupCheckouts := api.Group("/group-checkouts")
+	{
+		// Public pay link lookup for invitees
+		groupCheckouts.GET("/shares/:token", r.groupCheckoutHandler.GetShare)
+
+		groupCheckouts.GET("/:id", r.authMiddleware.Authenticate(), r.groupCheckoutHandler.GetByID)
+		groupCheckouts.POST("/:id/resolve", r.authMiddleware.Authenticate(), r.groupCheckoutHandler.Resolve)
+	}
+
+	// Group booking routes; the share token identifies the group
+	groupBookings := api.Group("/group-bookings")
+	{
+		// Public lookup for whoever has the link
+		groupBookings.GET("/:token", r.groupBookingHandler.Get)
+
+		groupBookings.POST("", r.authMiddleware.Authenticate(), r.groupBookingHandler.Create)
+		groupBookings.POST("/:token/join", r.authMiddleware.Authenticate(), r.groupBookingHandler.Join)
+		groupBookings.POST("/:token/finalize", r.authMiddleware.Authenticate(), r.groupBookingHandler.Finalize)
+	}
+
+	// Payment gateway callbacks (authenticated by signature)
+	payments := api.Group("/payments")
+	{
+		payments.POST("/webhook", r.paymentHandler.Webhook)
+	}
+
+	// Promo code management (admin only)
+	promoCodes := api.Group("/promo-codes")
+	{
+		promoCodes.Use(r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin())
+		promoCodes.GET("", r.promoHandler.List)
+		promoCodes.POST("", r.promoHandler.Create)
+		promoCodes.GET("/:id", r.promoHandler.Get)
+		promoCodes.PUT("/:id", r.promoHandler.Update)
+		promoCodes.DELETE("/:id", r.promoHandler.Delete)
+		promoCodes.GET("/:id/stats", r.promoHandler.GetStats)
+	}
+
+	// Client funnel analytics (rate limited per IP)
+	api.POST("/analytics/events", r.authMiddleware.OptionalAuth(), r.analyticsHandler.Track)
+
+	// Box office booking search and ticket check-in, open to cinema staff as well
+	api.GET("/admin/bookings", r.authMiddleware.Authenticate(), r.authMiddleware.RequireStaff(), r.bookingHandler.SearchBookings)
+	api.POST("/admin/bookings/check-in", r.authMiddleware.Authenticate(), r.authMiddleware.RequireStaff(), r.bookingHandler.CheckIn)
+
+	// Door staff scanning tickets
+	staff := api.Group("/staff")
+	{
+		staff.Use(r.authMiddleware.Authenticate(), r.authMiddleware.RequireStaff())
+		staff.POST("/check-in", r.bookingHandler.CheckIn)
+		staff.GET("/showtimes/:id/check-ins", r.bookingHandler.ListCheckIns)
+	}
+
+	// Operational admin routes
+	admin := api.Group("/admin")
+	{
+		admin.Use(r.authMiddleware.Authenticate(), r.authMiddleware.RequireAdmin())
+		admin.GET("/shadow-reads", r.adminHandler.GetShadowReads)
+		admin.PUT("/shadow-reads", r.adminHandler.UpdateShadowReads)
+		admin.GET("/jobs", r.adminHandler.ListJobs)
+		admin.GET("/webhooks", r.paymentHandler.ListWebhookEvents)
+		admin.POST("/webhooks/replay", r.paymentHandler.ReplayWebhookEvents)
+		admin.POST("/webhooks/:id/replay", r.paymentHandler.ReplayWebhookEvent)
+		admin.GET("/movies", r.movieHandler.AdminList)
+		admin.POST("/movies/:id/restore", r.movieHandler.Restore)
+		admin.POST("/movies/:id/recalculate-popularity", r.movieHandler.RecalculatePopularity)
+		admin.GET("/movies/:id/changes", r.changeLogHandler.ListMovieChanges)
+		admin.GET("/featured-slots", r.curationHandler.ListSlots)
+		admin.POST("/featured-slots", r.curationHandler.CreateSlot)
+		admin.GET("/featured-slots/:id", r.curationHandler.GetSlot)
+		admin.PUT("/featured-slots/:id", r.curationHandler.UpdateSlot)
+		admin.DELETE("/featured-slots/:id", r.curationHandler.DeleteSlot)
+		admin.GET("/showtimes/unavailable", r.showtimeHandler.ListUnavailable)
+		admin.POST("/showtimes/bulk", r.showtimeHandler.BulkCreate)
+		admin.PUT("/schedule/generate", r.showtimeHandler.GenerateSchedule)
+		admin.GET("/showtimes/:id/changes", r.changeLogHandler.ListShowtimeChanges)
+		admin.GET("/bookings/:id/changes", r.changeLogHandler.ListBookingChanges)
+		admin.GET("/cinemas/:id/daily-reports", r.dailyReportHandler.List)
+		admin.POST("/cinemas/:id/daily-reports", r.dailyReportHandler.Regenerate)
+		admin.GET("/demand-stats", r.demandHandler.Export)
+		admin.GET("/analytics/bookings", r.adminHandler.BookingAnalytics)
+		admin.GET("/stats/bookings", r.adminHandler.BookingStats)
+	}
+}
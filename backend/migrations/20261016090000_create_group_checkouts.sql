@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS group_checkouts (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_reference  VARCHAR(32)    NOT NULL UNIQUE,
+    hold_id          VARCHAR(64)    NOT NULL,
+    showtime_id      UUID           NOT NULL REFERENCES showtimes(id),
+    organizer_id     UUID           NOT NULL REFERENCES users(id),
+    organizer_email  VARCHAR(255)   NOT NULL,
+    status           VARCHAR(20)    NOT NULL DEFAULT 'OPEN'
+        CHECK (status IN ('OPEN', 'AWAITING_ORGANIZER', 'COMPLETED', 'CANCELLED')),
+    total_amount     DECIMAL(10, 2) NOT NULL,
+    hold_expires_at  TIMESTAMPTZ    NOT NULL,
+    booking_id       UUID REFERENCES bookings(id),
+    completed_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+-- Only one active group checkout per hold
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_checkouts_active_hold
+    ON group_checkouts (hold_id)
+    WHERE status IN ('OPEN', 'AWAITING_ORGANIZER');
+
+CREATE INDEX IF NOT EXISTS idx_group_checkouts_status_hold_expiry
+    ON group_checkouts (status, hold_expires_at);
+
+CREATE TABLE IF NOT EXISTS group_checkout_shares (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_checkout_id       UUID           NOT NULL REFERENCES group_checkouts(id) ON DELETE CASCADE,
+    invitee_email           VARCHAR(255)   NOT NULL,
+    seat_ids                JSONB          NOT NULL DEFAULT '[]',
+    amount                  DECIMAL(10, 2) NOT NULL,
+    status                  VARCHAR(20)    NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'PAID', 'EXPIRED', 'RELEASED')),
+    payment_reference       VARCHAR(64)    NOT NULL UNIQUE,
+    pay_token_hash          VARCHAR(64)    NOT NULL UNIQUE,
+    gateway_transaction_id  VARCHAR(255),
+    expires_at              TIMESTAMPTZ    NOT NULL,
+    paid_at                 TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_checkout_shares_group
+    ON group_checkout_shares (group_checkout_id);
+
+CREATE INDEX IF NOT EXISTS idx_group_checkout_shares_pending_expiry
+    ON group_checkout_shares (expires_at)
+    WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS group_checkout_shares;
+DROP TABLE IF EXISTS group_checkouts;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cinemas
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- NULL sales_open_at means on sale immediately; NULL sales_close_at means
+-- sales close at the showtime start
+ALTER TABLE showtimes
+    ADD COLUMN IF NOT EXISTS sales_open_at     TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS sales_close_at    TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS presale_code_hash VARCHAR(64);
+
+ALTER TABLE showtimes
+    ADD CONSTRAINT chk_showtimes_sales_window
+    CHECK (sales_open_at IS NULL OR sales_close_at IS NULL OR sales_open_at < sales_close_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE showtimes DROP CONSTRAINT IF EXISTS chk_showtimes_sales_window;
+ALTER TABLE showtimes
+    DROP COLUMN IF EXISTS presale_code_hash,
+    DROP COLUMN IF EXISTS sales_close_at,
+    DROP COLUMN IF EXISTS sales_open_at;
+ALTER TABLE cinemas DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
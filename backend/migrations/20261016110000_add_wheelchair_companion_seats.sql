@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cinemas
+    ADD COLUMN IF NOT EXISTS companion_policy_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Set on a wheelchair seat; each companion seat serves one wheelchair seat
+ALTER TABLE seats
+    ADD COLUMN IF NOT EXISTS companion_seat_id UUID REFERENCES seats(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seats_companion_seat
+    ON seats (companion_seat_id)
+    WHERE companion_seat_id IS NOT NULL;
+
+ALTER TABLE booking_seats
+    ADD COLUMN IF NOT EXISTS fare VARCHAR(20) NOT NULL DEFAULT 'STANDARD'
+        CHECK (fare IN ('STANDARD', 'COMPANION'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE booking_seats DROP COLUMN IF EXISTS fare;
+DROP INDEX IF EXISTS idx_seats_companion_seat;
+ALTER TABLE seats DROP COLUMN IF EXISTS companion_seat_id;
+ALTER TABLE cinemas DROP COLUMN IF EXISTS companion_policy_enabled;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_inbox (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider           VARCHAR(50)  NOT NULL,
+    event_id           VARCHAR(255) NOT NULL,
+    event_type         VARCHAR(100) NOT NULL,
+    payload            JSONB        NOT NULL,
+    signature_valid    BOOLEAN      NOT NULL,
+    status             VARCHAR(20)  NOT NULL DEFAULT 'RECEIVED'
+        CHECK (status IN ('RECEIVED', 'PROCESSED', 'FAILED', 'IGNORED', 'REJECTED')),
+    attempts           INTEGER      NOT NULL DEFAULT 0,
+    last_error         TEXT,
+    payment_reference  VARCHAR(64),
+    booking_id         UUID REFERENCES bookings(id),
+    payment_id         UUID REFERENCES payments(id),
+    received_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    processed_at       TIMESTAMPTZ,
+    alerted_at         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Gateways redeliver; one row per gateway event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_inbox_provider_event
+    ON webhook_inbox (provider, event_id);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_inbox_status_received
+    ON webhook_inbox (status, received_at);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_inbox_payment_reference
+    ON webhook_inbox (payment_reference)
+    WHERE payment_reference IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_inbox;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL capacity_limit means the whole screen can be sold; blocked_seats
+-- counts the seats a distancing pattern leaves empty
+ALTER TABLE showtimes
+    ADD COLUMN IF NOT EXISTS capacity_limit   INT,
+    ADD COLUMN IF NOT EXISTS distancing       BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS blocked_seats    INT         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS visibility       VARCHAR(20) NOT NULL DEFAULT 'PUBLIC',
+    ADD COLUMN IF NOT EXISTS access_code_hash VARCHAR(64);
+
+ALTER TABLE showtimes
+    ADD CONSTRAINT chk_showtimes_capacity_limit CHECK (capacity_limit IS NULL OR capacity_limit >= 0),
+    ADD CONSTRAINT chk_showtimes_blocked_seats CHECK (blocked_seats >= 0),
+    ADD CONSTRAINT chk_showtimes_visibility CHECK (visibility IN ('PUBLIC', 'UNLISTED', 'PRIVATE'));
+
+CREATE INDEX IF NOT EXISTS idx_showtimes_visibility ON showtimes(visibility) WHERE visibility <> 'PUBLIC';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_showtimes_visibility;
+ALTER TABLE showtimes
+    DROP CONSTRAINT IF EXISTS chk_showtimes_visibility,
+    DROP CONSTRAINT IF EXISTS chk_showtimes_blocked_seats,
+    DROP CONSTRAINT IF EXISTS chk_showtimes_capacity_limit;
+ALTER TABLE showtimes
+    DROP COLUMN IF EXISTS access_code_hash,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS blocked_seats,
+    DROP COLUMN IF EXISTS distancing,
+    DROP COLUMN IF EXISTS capacity_limit;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS hold_recovery_emails BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- One recovery email per user and showtime, enforced by the unique key
+CREATE TABLE IF NOT EXISTS hold_recoveries (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id              UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    showtime_id          UUID         NOT NULL REFERENCES showtimes(id) ON DELETE CASCADE,
+    hold_id              VARCHAR(64)  NOT NULL,
+    token_hash           VARCHAR(64)  NOT NULL UNIQUE,
+    seat_types           TEXT[],
+    rehold_offered       BOOLEAN      NOT NULL DEFAULT FALSE,
+    sent_at              TIMESTAMPTZ  NOT NULL,
+    reheld_at            TIMESTAMPTZ,
+    rehold_hold_id       VARCHAR(64),
+    converted_at         TIMESTAMPTZ,
+    converted_booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    created_at           TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_hold_recoveries_user_showtime UNIQUE (user_id, showtime_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_hold_recoveries_showtime ON hold_recoveries(showtime_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS hold_recoveries;
+ALTER TABLE users DROP COLUMN IF EXISTS hold_recovery_emails;
+-- +goose StatementEnd
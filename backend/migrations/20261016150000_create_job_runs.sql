@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Run history of scheduled background jobs, shared by all instances. Runs
+-- themselves are serialized with advisory locks, not rows in this table.
+CREATE TABLE IF NOT EXISTS job_runs (
+    name             VARCHAR(100) PRIMARY KEY,
+    owner            VARCHAR(255) NOT NULL,
+    last_started_at  TIMESTAMPTZ,
+    last_finished_at TIMESTAMPTZ,
+    last_success_at  TIMESTAMPTZ,
+    last_error       TEXT,
+    last_duration_ms BIGINT       NOT NULL DEFAULT 0,
+    runs             BIGINT       NOT NULL DEFAULT 0,
+    failures         BIGINT       NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS job_runs;
+-- +goose StatementEnd
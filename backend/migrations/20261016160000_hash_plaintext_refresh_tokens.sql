@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Refresh tokens are stored as the hex SHA-256 of the token. Rows written
+-- with the raw token instead can never be matched on refresh or logout, so
+-- hash them in place; the digest matches authinfra.HashToken.
+UPDATE refresh_tokens
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex')
+WHERE token_hash !~ '^[0-9a-f]{64}$';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Hashing is one-way; the raw tokens cannot be restored
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-cinema booking fee policy. fee_amount is a percent for PERCENTAGE
+-- fees and a flat amount otherwise.
+ALTER TABLE cinemas
+    ADD COLUMN IF NOT EXISTS fee_type        VARCHAR(20)   NOT NULL DEFAULT 'NONE',
+    ADD COLUMN IF NOT EXISTS fee_amount      DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fee_cap         DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS fee_online_only BOOLEAN       NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS fee_refundable  BOOLEAN       NOT NULL DEFAULT FALSE;
+
+ALTER TABLE cinemas
+    ADD CONSTRAINT cinemas_fee_type_check
+    CHECK (fee_type IN ('NONE', 'PER_TICKET', 'PER_ORDER', 'PERCENTAGE'));
+
+-- Booking fee charged on each booking and carried by each group share
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS fee_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE group_checkout_shares
+    ADD COLUMN IF NOT EXISTS fee_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE group_checkout_shares DROP COLUMN IF EXISTS fee_amount;
+ALTER TABLE bookings DROP COLUMN IF EXISTS fee_amount;
+ALTER TABLE cinemas DROP CONSTRAINT IF EXISTS cinemas_fee_type_check;
+ALTER TABLE cinemas
+    DROP COLUMN IF EXISTS fee_refundable,
+    DROP COLUMN IF EXISTS fee_online_only,
+    DROP COLUMN IF EXISTS fee_cap,
+    DROP COLUMN IF EXISTS fee_amount,
+    DROP COLUMN IF EXISTS fee_type;
+-- +goose StatementEnd
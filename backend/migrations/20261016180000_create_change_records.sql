@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Field-level change history of movies and showtimes. changes holds a JSON
+-- array of {field, old, new} or {field, added, removed} entries.
+CREATE TABLE IF NOT EXISTS change_records (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id   UUID        NOT NULL,
+    actor_type  VARCHAR(20) NOT NULL,
+    actor_id    UUID,
+    changes     JSONB       NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_records_entity
+    ON change_records (entity_type, entity_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS change_records;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Curated homepage entries. MOVIE slots reference a movie; BANNER slots
+-- carry their own image, headline and link.
+CREATE TABLE IF NOT EXISTS featured_slots (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    position     VARCHAR(20)  NOT NULL,
+    type         VARCHAR(20)  NOT NULL,
+    movie_id     UUID REFERENCES movies(id),
+    image_url    TEXT,
+    headline     VARCHAR(255),
+    link_url     TEXT,
+    active_from  TIMESTAMPTZ,
+    active_until TIMESTAMPTZ,
+    sort_order   INTEGER      NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deleted_at   TIMESTAMPTZ,
+
+    CONSTRAINT featured_slots_position_check CHECK (position IN ('HERO', 'FEATURED')),
+    CONSTRAINT featured_slots_type_check CHECK (
+        (type = 'MOVIE' AND movie_id IS NOT NULL) OR
+        (type = 'BANNER' AND image_url IS NOT NULL AND headline IS NOT NULL)
+    ),
+    CONSTRAINT featured_slots_window_check CHECK (
+        active_from IS NULL OR active_until IS NULL OR active_from < active_until
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_featured_slots_position
+    ON featured_slots (position, sort_order)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS featured_slots;
+-- +goose StatementEnd
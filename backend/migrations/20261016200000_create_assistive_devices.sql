@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Assistive devices each screen can lend out
+CREATE TABLE IF NOT EXISTS screen_devices (
+    screen_id UUID NOT NULL REFERENCES screens(id) ON DELETE CASCADE,
+    device_type VARCHAR(30) NOT NULL CHECK (device_type IN ('ASSISTED_LISTENING', 'AUDIO_DESCRIPTION')),
+    total_count INTEGER NOT NULL CHECK (total_count >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (screen_id, device_type)
+);
+
+-- Devices reserved with a booking; they count against the stock while the
+-- booking is confirmed
+CREATE TABLE IF NOT EXISTS booking_devices (
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    device_type VARCHAR(30) NOT NULL CHECK (device_type IN ('ASSISTED_LISTENING', 'AUDIO_DESCRIPTION')),
+    showtime_id UUID NOT NULL REFERENCES showtimes(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (booking_id, device_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_devices_showtime ON booking_devices (showtime_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS booking_devices;
+DROP TABLE IF EXISTS screen_devices;
+-- +goose StatementEnd
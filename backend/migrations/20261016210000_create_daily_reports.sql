@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Staff and managers assigned to a cinema
+CREATE TABLE IF NOT EXISTS cinema_staff (
+    cinema_id UUID NOT NULL REFERENCES cinemas(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cinema_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cinema_staff_user ON cinema_staff (user_id);
+
+-- One end-of-day report per cinema and business date
+CREATE TABLE IF NOT EXISTS daily_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cinema_id UUID NOT NULL REFERENCES cinemas(id) ON DELETE CASCADE,
+    business_date DATE NOT NULL,
+    data JSONB NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cinema_id, business_date)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS daily_reports;
+DROP TABLE IF EXISTS cinema_staff;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Online sales rules per seat type of a showtime: a cap on the seats sold
+-- online, the rest kept for the box office, and a later opening time
+CREATE TABLE IF NOT EXISTS showtime_seat_type_rules (
+    showtime_id UUID NOT NULL REFERENCES showtimes(id) ON DELETE CASCADE,
+    seat_type VARCHAR(20) NOT NULL,
+    online_sales_cap INTEGER CHECK (online_sales_cap >= 0),
+    sales_open_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (showtime_id, seat_type)
+);
+
+-- Walk-in sales do not count against online caps
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS sales_channel VARCHAR(20) NOT NULL DEFAULT 'ONLINE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP COLUMN IF EXISTS sales_channel;
+DROP TABLE IF EXISTS showtime_seat_type_rules;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Gateway status polls of payments left pending without a webhook
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reconcile_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS next_reconcile_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_payments_pending_created
+    ON payments (created_at) WHERE payment_status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_payments_pending_created;
+ALTER TABLE payments DROP COLUMN IF EXISTS next_reconcile_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS reconcile_attempts;
+-- +goose StatementEnd
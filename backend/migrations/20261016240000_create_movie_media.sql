@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ordered trailers, teasers and images of a movie's media gallery
+CREATE TABLE IF NOT EXISTS movie_media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    movie_id UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('TRAILER', 'TEASER', 'STILL', 'POSTER_ALT')),
+    url TEXT NOT NULL,
+    title VARCHAR(200),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    published BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_media_movie ON movie_media (movie_id, sort_order);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_media;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- When a guest booking was attached to an account; the guest fields are kept
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_unclaimed_guest_email ON bookings (LOWER(guest_email))
+    WHERE user_id IS NULL AND guest_email <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_unclaimed_guest_email;
+ALTER TABLE bookings DROP COLUMN IF EXISTS claimed_at;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Confirmed bookings rolled up by movie, cinema, day of week, time bucket
+-- and screen type, as demand signals for scheduling
+CREATE TABLE IF NOT EXISTS showtime_demand_stats (
+    movie_id UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    cinema_id UUID NOT NULL REFERENCES cinemas(id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    time_bucket VARCHAR(20) NOT NULL CHECK (time_bucket IN ('MORNING', 'AFTERNOON', 'EVENING', 'LATE')),
+    screen_type VARCHAR(20) NOT NULL,
+    bookings BIGINT NOT NULL DEFAULT 0,
+    tickets BIGINT NOT NULL DEFAULT 0,
+    revenue DECIMAL(14,2) NOT NULL DEFAULT 0,
+    lead_hours DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (movie_id, cinema_id, day_of_week, time_bucket, screen_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_showtime_demand_stats_cinema ON showtime_demand_stats(cinema_id);
+
+-- How far each rollup has processed bookings, by confirmation time
+CREATE TABLE IF NOT EXISTS demand_rollup_watermarks (
+    name VARCHAR(50) PRIMARY KEY,
+    watermark TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO demand_rollup_watermarks (name, watermark)
+VALUES ('showtime_demand', 'epoch')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_confirmed_at ON bookings(confirmed_at) WHERE confirmed_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_confirmed_at;
+DROP TABLE IF EXISTS demand_rollup_watermarks;
+DROP TABLE IF EXISTS showtime_demand_stats;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A refresh token is revoked when it is rotated; replaced_by links it to its
+-- successor, so presenting it again is told apart from a logged-out token
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS replaced_by UUID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS replaced_by;
+-- +goose StatementEnd
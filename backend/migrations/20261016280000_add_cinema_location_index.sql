@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Nearby searches filter active cinemas with coordinates on a bounding box
+-- before computing distances
+CREATE INDEX IF NOT EXISTS idx_cinemas_location ON cinemas (latitude, longitude)
+    WHERE is_active AND latitude IS NOT NULL AND longitude IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cinemas_location;
+-- +goose StatementEnd
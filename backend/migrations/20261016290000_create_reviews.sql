@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customer ratings of movies; one live review per customer and movie
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    movie_id UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(200),
+    body TEXT,
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_movie_user ON reviews (movie_id, user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_reviews_movie_created ON reviews (movie_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_reviews_user ON reviews (user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews (deleted_at);
+
+-- Aggregate of the live reviews, recomputed whenever one is added or removed
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS user_rating DECIMAL(2,1);
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN IF EXISTS review_count;
+ALTER TABLE movies DROP COLUMN IF EXISTS user_rating;
+DROP TABLE IF EXISTS reviews;
+-- +goose StatementEnd
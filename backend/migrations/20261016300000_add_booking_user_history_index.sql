@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Serves a user's booking history newest first and the keyset seek past a
+-- (booked_at, id) cursor
+CREATE INDEX IF NOT EXISTS idx_bookings_user_booked_at ON bookings (user_id, booked_at DESC, id DESC)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_user_booked_at;
+-- +goose StatementEnd
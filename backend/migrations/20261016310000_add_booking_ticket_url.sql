@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Where the PDF e-ticket is downloaded, set once it is issued after confirmation
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS ticket_url VARCHAR(500);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP COLUMN IF EXISTS ticket_url;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers waiting for seats at sold-out showtimes, served in position
+-- order as cancellations release seats
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    showtime_id UUID NOT NULL REFERENCES showtimes(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    guest_email VARCHAR(255),
+    seat_count INTEGER NOT NULL CHECK (seat_count > 0),
+    position BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING',
+    hold_id VARCHAR(64),
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT waitlist_entries_owner CHECK (user_id IS NOT NULL OR guest_email IS NOT NULL),
+    CONSTRAINT waitlist_entries_position UNIQUE (showtime_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_queue ON waitlist_entries (showtime_id, position)
+    WHERE status = 'WAITING';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_entries_user ON waitlist_entries (showtime_id, user_id)
+    WHERE status = 'WAITING' AND user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_entries_guest ON waitlist_entries (showtime_id, LOWER(guest_email))
+    WHERE status = 'WAITING' AND user_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS waitlist_entries;
+-- +goose StatementEnd
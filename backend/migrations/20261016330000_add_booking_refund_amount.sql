@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What a cancelled booking refunded under the cancellation policy
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS refund_amount DECIMAL(10,2);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP COLUMN IF EXISTS refund_amount;
+-- +goose StatementEnd
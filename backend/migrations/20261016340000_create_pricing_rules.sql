@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-cinema adjustments of seat prices, applied on top of the showtime
+-- base price scaled by seat type
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cinema_id UUID NOT NULL REFERENCES cinemas(id) ON DELETE CASCADE,
+    rule_type VARCHAR(20) NOT NULL
+        CHECK (rule_type IN ('SEAT_TYPE', 'TIME_OF_DAY', 'DAY_OF_WEEK', 'OCCUPANCY')),
+    modifier_type VARCHAR(20) NOT NULL CHECK (modifier_type IN ('FLAT', 'PERCENTAGE')),
+    modifier_value DECIMAL(10,2) NOT NULL,
+    condition_json JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_cinema ON pricing_rules(cinema_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pricing_rules;
+-- +goose StatementEnd
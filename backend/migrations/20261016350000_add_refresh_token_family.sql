@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every token rotated from one login shares that login's family_id, and
+-- keeps the hash of the token it replaced in parent_token_hash
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS parent_token_hash VARCHAR(255);
+
+-- Existing chains take the ID of their first token as the family
+WITH RECURSIVE chain AS (
+    SELECT t.id, t.id AS family_id, t.replaced_by
+    FROM refresh_tokens t
+    WHERE NOT EXISTS (SELECT 1 FROM refresh_tokens p WHERE p.replaced_by = t.id)
+    UNION ALL
+    SELECT t.id, chain.family_id, t.replaced_by
+    FROM refresh_tokens t
+    JOIN chain ON chain.replaced_by = t.id
+)
+UPDATE refresh_tokens t
+SET family_id = chain.family_id
+FROM chain
+WHERE chain.id = t.id AND t.family_id IS NULL;
+
+UPDATE refresh_tokens t
+SET parent_token_hash = p.token_hash
+FROM refresh_tokens p
+WHERE p.replaced_by = t.id AND t.parent_token_hash IS NULL;
+
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS parent_token_hash;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- google_id is the Google account a user signs in with, if any
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_id VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users(google_id) WHERE google_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_google_id;
+ALTER TABLE users DROP COLUMN IF EXISTS google_id;
+-- +goose StatementEnd
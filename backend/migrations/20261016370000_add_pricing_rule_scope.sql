@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pricing rules can be limited to a screen type and to showtimes starting
+-- within a validity window; among matching rules of one type only the
+-- highest priority applies
+ALTER TABLE pricing_rules
+    ADD COLUMN IF NOT EXISTS screen_type VARCHAR(20)
+        CHECK (screen_type IN ('STANDARD', 'IMAX', '4DX', 'DOLBY', 'VIP')),
+    ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS valid_from TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS valid_until TIMESTAMPTZ,
+    ADD CONSTRAINT chk_pricing_rules_validity
+        CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pricing_rules
+    DROP CONSTRAINT IF EXISTS chk_pricing_rules_validity,
+    DROP COLUMN IF EXISTS valid_until,
+    DROP COLUMN IF EXISTS valid_from,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS screen_type;
+-- +goose StatementEnd
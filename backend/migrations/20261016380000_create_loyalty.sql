@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Loyalty points are earned on confirmed bookings and redeemed against the
+-- seats of new ones; balances expire after a period without activity
+CREATE TABLE IF NOT EXISTS loyalty_accounts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    points_balance INTEGER NOT NULL DEFAULT 0 CHECK (points_balance >= 0),
+    last_activity_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_accounts_activity
+    ON loyalty_accounts(last_activity_at) WHERE points_balance > 0;
+
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('EARN', 'REDEEM', 'EXPIRE', 'REFUND', 'REVERSE')),
+    points INTEGER NOT NULL, -- credits positive, debits negative
+    description TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_user
+    ON loyalty_transactions(user_id, created_at DESC);
+-- A booking earns, redeems and undoes each at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_booking_type
+    ON loyalty_transactions(booking_id, type) WHERE booking_id IS NOT NULL;
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS points_redeemed INTEGER NOT NULL DEFAULT 0 CHECK (points_redeemed >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP COLUMN IF EXISTS points_redeemed;
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Serve the staff booking search: by email (guest or account), by showtime,
+-- and the unfiltered list by booked_at. Lookups by reference use the
+-- unique index on booking_reference.
+CREATE INDEX IF NOT EXISTS idx_bookings_guest_email ON bookings (LOWER(guest_email))
+    WHERE guest_email <> '';
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_bookings_showtime_booked_at ON bookings (showtime_id, booked_at DESC)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_booked_at ON bookings (booked_at DESC, id DESC)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_booked_at;
+DROP INDEX IF EXISTS idx_bookings_showtime_booked_at;
+DROP INDEX IF EXISTS idx_users_email_lower;
+DROP INDEX IF EXISTS idx_bookings_guest_email;
+-- +goose StatementEnd
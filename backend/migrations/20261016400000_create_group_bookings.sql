@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS group_bookings (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organizer_user_id     UUID         NOT NULL REFERENCES users(id),
+    showtime_id           UUID         NOT NULL REFERENCES showtimes(id),
+    max_participants      INTEGER      NOT NULL CHECK (max_participants >= 2),
+    current_participants  INTEGER      NOT NULL DEFAULT 0
+        CHECK (current_participants BETWEEN 0 AND max_participants),
+    share_token_hash      VARCHAR(64)  NOT NULL UNIQUE,
+    expires_at            TIMESTAMPTZ  NOT NULL,
+    status                VARCHAR(20)  NOT NULL DEFAULT 'OPEN'
+        CHECK (status IN ('OPEN', 'FINALIZED', 'EXPIRED')),
+    booking_id            UUID REFERENCES bookings(id),
+    finalized_at          TIMESTAMPTZ,
+    created_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_bookings_organizer
+    ON group_bookings (organizer_user_id);
+
+CREATE TABLE IF NOT EXISTS group_booking_participants (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_booking_id  UUID         NOT NULL REFERENCES group_bookings(id) ON DELETE CASCADE,
+    user_id           UUID         NOT NULL REFERENCES users(id),
+    hold_id           VARCHAR(64)  NOT NULL,
+    seat_ids          JSONB        NOT NULL DEFAULT '[]',
+    joined_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- A user joins a group once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_booking_participants_user
+    ON group_booking_participants (group_booking_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS group_booking_participants;
+DROP TABLE IF EXISTS group_bookings;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A user's role at one cinema; managers only manage the cinemas they are
+-- assigned to as MANAGER
+ALTER TABLE cinema_staff
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'STAFF'
+        CHECK (role IN ('MANAGER', 'STAFF'));
+
+-- Existing assignments take the role of the user's account
+UPDATE cinema_staff
+SET role = 'MANAGER'
+FROM users
+WHERE users.id = cinema_staff.user_id
+  AND users.role = 'MANAGER';
+
+CREATE INDEX IF NOT EXISTS idx_cinema_staff_cinema_role ON cinema_staff (cinema_id, role);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cinema_staff_cinema_role;
+ALTER TABLE cinema_staff DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS checked_in_by UUID REFERENCES users(id);
+
+-- Every scan of a ticket at the door, accepted or not
+CREATE TABLE IF NOT EXISTS ticket_check_ins (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_reference  VARCHAR(128) NOT NULL,
+    booking_id         UUID REFERENCES bookings(id),
+    staff_user_id      UUID         NOT NULL REFERENCES users(id),
+    result             VARCHAR(30)  NOT NULL,
+    message            TEXT,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_check_ins_booking
+    ON ticket_check_ins (booking_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ticket_check_ins;
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS checked_in_by,
+    DROP COLUMN IF EXISTS checked_in_at;
+-- +goose StatementEnd
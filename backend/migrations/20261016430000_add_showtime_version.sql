@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bumped by every write to available_seats; writers that read the same
+-- version cannot both succeed
+ALTER TABLE showtimes
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
+
+-- Last line of defence against overselling. NOT VALID leaves rows that
+-- already went negative for an operator to fix.
+ALTER TABLE showtimes
+    ADD CONSTRAINT chk_showtimes_available_seats_non_negative
+        CHECK (available_seats >= 0) NOT VALID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE showtimes DROP CONSTRAINT IF EXISTS chk_showtimes_available_seats_non_negative;
+-- The version column predates this migration in older schemas and is kept
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The storage key of an uploaded avatar, so it can be deleted when replaced;
+-- NULL for avatars hosted elsewhere, e.g. a Google profile picture
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
+-- +goose StatementEnd
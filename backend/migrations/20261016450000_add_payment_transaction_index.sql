@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Payment intent webhooks without a payment reference are matched to their
+-- payment by the gateway's transaction ID
+CREATE INDEX IF NOT EXISTS idx_payments_gateway_transaction_id
+    ON payments (gateway_transaction_id) WHERE gateway_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_payments_gateway_transaction_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text search over a cinema's name, city and address. The 'simple'
+-- configuration does no stemming, which suits names and places in any
+-- language; queries must use the same expression to use the index.
+CREATE INDEX IF NOT EXISTS idx_cinemas_search
+    ON cinemas USING GIN (to_tsvector('simple', name || ' ' || city || ' ' || address));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cinemas_search;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per booking that used a promo code, counted against the code's
+-- per-user limit: by account, and by email for guests. Releasing the
+-- booking deletes its row.
+CREATE TABLE IF NOT EXISTS promo_code_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_code_id UUID NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    guest_email VARCHAR(255) NOT NULL DEFAULT '', -- lower-cased
+    booking_id UUID NOT NULL UNIQUE REFERENCES bookings(id) ON DELETE CASCADE,
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_code_redemptions_user
+    ON promo_code_redemptions(promo_code_id, user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_promo_code_redemptions_guest_email
+    ON promo_code_redemptions(promo_code_id, guest_email) WHERE guest_email <> '';
+
+-- Uses so far, from the bookings that still hold them
+INSERT INTO promo_code_redemptions (promo_code_id, user_id, guest_email, booking_id, redeemed_at)
+SELECT promo_code_id, user_id,
+       CASE WHEN user_id IS NULL THEN LOWER(COALESCE(guest_email, '')) ELSE '' END,
+       id, booked_at
+FROM bookings
+WHERE promo_code_id IS NOT NULL
+    AND booking_status NOT IN ('EXPIRED', 'CANCELLED')
+    AND deleted_at IS NULL
+ON CONFLICT (booking_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS promo_code_redemptions;
+-- +goose StatementEnd
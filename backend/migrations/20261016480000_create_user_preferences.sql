@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What a user likes to watch, used to personalise movie listings, and how
+-- they want to be notified. Users without a row have the defaults.
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    favorite_genres TEXT[] NOT NULL DEFAULT '{}',
+    favorite_cinema_ids UUID[] NOT NULL DEFAULT '{}',
+    preferred_formats TEXT[] NOT NULL DEFAULT '{}',
+    notification_email BOOLEAN NOT NULL DEFAULT TRUE,
+    notification_push BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- The document a movie is searched by: the title weighted A, the
+-- description B, the director and cast C. Accents are stripped so "amelie"
+-- finds "Amélie", and the 'simple' configuration does no stemming, which
+-- suits titles and names in any language. unaccent and array_to_string are
+-- only stable, so the dictionary is named to let the function be immutable
+-- as a generated column requires.
+CREATE OR REPLACE FUNCTION movie_search_vector(title TEXT, description TEXT, director TEXT, cast_members TEXT[])
+RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT setweight(to_tsvector('simple', public.unaccent('public.unaccent'::regdictionary, COALESCE(title, ''))), 'A')
+        || setweight(to_tsvector('simple', public.unaccent('public.unaccent'::regdictionary, COALESCE(description, ''))), 'B')
+        || setweight(to_tsvector('simple', public.unaccent('public.unaccent'::regdictionary,
+            COALESCE(director, '') || ' ' || COALESCE(array_to_string(cast_members, ' '), ''))), 'C')
+$$;
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (movie_search_vector(title, description, director, "cast")) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_movies_search ON movies USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_movies_search;
+ALTER TABLE movies DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS movie_search_vector(TEXT, TEXT, TEXT, TEXT[]);
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events written in the transaction that produced them and
+-- published to the in-process bus by the outbox relay once committed. The
+-- sequence orders publication.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_name VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_unpublished ON event_outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_published_at ON event_outbox (published_at) WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_outbox;
+-- +goose StatementEnd
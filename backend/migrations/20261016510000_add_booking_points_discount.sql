@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Redeemed loyalty points were added to discount_amount, which promo code
+-- stats sum; their value now has its own column. Points are worth a cent
+-- each.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS points_discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (points_discount_amount >= 0);
+
+UPDATE bookings
+SET points_discount_amount = points_redeemed * 0.01,
+    discount_amount = GREATEST(discount_amount - points_redeemed * 0.01, 0)
+WHERE points_redeemed > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE bookings
+SET discount_amount = discount_amount + points_discount_amount
+WHERE points_discount_amount > 0;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS points_discount_amount;
+-- +goose StatementEnd
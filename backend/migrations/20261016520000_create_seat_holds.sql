@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Seat holds of a server running without Redis. Holds are made with the
+-- showtime row locked, so two holds on one showtime are made one at a time.
+CREATE TABLE IF NOT EXISTS seat_holds (
+    id VARCHAR(64) PRIMARY KEY,
+    showtime_id UUID NOT NULL REFERENCES showtimes(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL, -- the entity.SeatHold
+    expires_at TIMESTAMPTZ NOT NULL,
+    -- set once the seats were announced released after the hold expired
+    expiry_announced BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_seat_holds_expires_at ON seat_holds(expires_at);
+
+-- One row per held seat. A row outlives its hold's expiry until the seat is
+-- held again or the hold is swept.
+CREATE TABLE IF NOT EXISTS seat_hold_seats (
+    showtime_id UUID NOT NULL,
+    seat_id UUID NOT NULL,
+    hold_id VARCHAR(64) NOT NULL REFERENCES seat_holds(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (showtime_id, seat_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_seat_hold_seats_hold ON seat_hold_seats(hold_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS seat_hold_seats;
+DROP TABLE IF EXISTS seat_holds;
+-- +goose StatementEnd